@@ -2,7 +2,6 @@ package analysis
 
 import (
 	"context"
-	"sync"
 	"time"
 
 	"github.com/aws/smithy-go/ptr"
@@ -17,7 +16,6 @@ import (
 	log "github.com/sirupsen/logrus"
 	batchv1 "k8s.io/api/batch/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/util/wait"
 	batchinformers "k8s.io/client-go/informers/batch/v1"
 	coreinformer "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
@@ -34,6 +32,7 @@ import (
 	controllerutil "github.com/argoproj/argo-rollouts/utils/controller"
 	logutil "github.com/argoproj/argo-rollouts/utils/log"
 	"github.com/argoproj/argo-rollouts/utils/record"
+	"github.com/argoproj/argo-rollouts/utils/sharding"
 	timeutil "github.com/argoproj/argo-rollouts/utils/time"
 )
 
@@ -74,19 +73,29 @@ type Controller struct {
 	// Kubernetes API.
 	recorder     record.EventRecorder
 	resyncPeriod time.Duration
+	// sharder selects the analysis runs reconciled by this controller replica when running in sharding mode
+	sharder *sharding.Sharder
+	// workerScaling configures the scaling of the analysis workers
+	workerScaling controllerutil.WorkerScaling
 }
 
 // ControllerConfig describes the data required to instantiate a new analysis controller
 type ControllerConfig struct {
-	KubeClientSet        kubernetes.Interface
-	ArgoProjClientset    clientset.Interface
-	AnalysisRunInformer  informers.AnalysisRunInformer
-	JobInformer          batchinformers.JobInformer
-	JobPodsInformer      coreinformer.PodInformer
-	ResyncPeriod         time.Duration
-	AnalysisRunWorkQueue workqueue.RateLimitingInterface
-	MetricsServer        *metrics.MetricsServer
-	Recorder             record.EventRecorder
+	KubeClientSet kubernetes.Interface
+	// MetricsKubeClientSet is the clientset used by the metric providers, e.g. to create the Jobs of job metrics.
+	// Defaults to KubeClientSet.
+	MetricsKubeClientSet    kubernetes.Interface
+	ArgoProjClientset       clientset.Interface
+	AnalysisRunInformer     informers.AnalysisRunInformer
+	JobInformer             batchinformers.JobInformer
+	JobPodsInformer         coreinformer.PodInformer
+	ResyncPeriod            time.Duration
+	AnalysisRunWorkQueue    workqueue.RateLimitingInterface
+	MetricsServer           *metrics.MetricsServer
+	Recorder                record.EventRecorder
+	AnalysisRunResyncPeriod time.Duration
+	Sharder                 *sharding.Sharder
+	WorkerScaling           controllerutil.WorkerScaling
 }
 
 // NewController returns a new analysis controller
@@ -103,6 +112,8 @@ func NewController(cfg ControllerConfig) *Controller {
 		analysisRunSynced:    cfg.AnalysisRunInformer.Informer().HasSynced,
 		recorder:             cfg.Recorder,
 		resyncPeriod:         cfg.ResyncPeriod,
+		sharder:              cfg.Sharder,
+		workerScaling:        cfg.WorkerScaling,
 	}
 
 	controller.enqueueAnalysis = func(obj any) {
@@ -112,8 +123,12 @@ func NewController(cfg ControllerConfig) *Controller {
 		controllerutil.EnqueueAfter(obj, duration, cfg.AnalysisRunWorkQueue)
 	}
 
+	metricsKubeClientSet := cfg.MetricsKubeClientSet
+	if metricsKubeClientSet == nil {
+		metricsKubeClientSet = controller.kubeclientset
+	}
 	providerFactory := metricproviders.ProviderFactory{
-		KubeClient:    controller.kubeclientset,
+		KubeClient:    metricsKubeClientSet,
 		JobLister:     cfg.JobInformer.Lister(),
 		JobPodsLister: cfg.JobPodsInformer.Lister(),
 	}
@@ -133,7 +148,7 @@ func NewController(cfg ControllerConfig) *Controller {
 
 	log.Info("Setting up analysis event handlers")
 	// Set up an event handler for when analysis resources change
-	cfg.AnalysisRunInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+	controllerutil.AddEventHandlerWithResyncPeriod(cfg.AnalysisRunInformer.Informer(), cache.ResourceEventHandlerFuncs{
 		AddFunc: controller.enqueueAnalysis,
 		UpdateFunc: func(old, new any) {
 			controller.enqueueAnalysis(new)
@@ -146,24 +161,16 @@ func NewController(cfg ControllerConfig) *Controller {
 				controller.metricsServer.Remove(ar.Namespace, ar.Name, logutil.AnalysisRunKey)
 			}
 		},
-	})
+	}, cfg.AnalysisRunResyncPeriod)
 	return controller
 }
 
 func (c *Controller) Run(ctx context.Context, threadiness int) error {
 	log.Info("Starting analysis workers")
-	wg := sync.WaitGroup{}
-	for i := 0; i < threadiness; i++ {
-		wg.Add(1)
-		go wait.Until(func() {
-			controllerutil.RunWorker(ctx, c.analysisRunWorkQueue, logutil.AnalysisRunKey, c.syncHandler, c.metricsServer)
-			log.Debug("Analysis worker has stopped")
-			wg.Done()
-		}, time.Second, ctx.Done())
-	}
+	workers := controllerutil.StartWorkers(ctx, c.analysisRunWorkQueue, logutil.AnalysisRunKey, c.syncHandler, c.metricsServer, threadiness, c.workerScaling)
 	log.Infof("Started %d analysis workers", threadiness)
 	<-ctx.Done()
-	wg.Wait()
+	workers.Wait(controllerutil.DefaultWorkerDrainTimeout)
 	log.Info("All analysis workers have stopped")
 
 	return nil
@@ -175,7 +182,6 @@ func (c *Controller) syncHandler(ctx context.Context, key string) error {
 	if err != nil {
 		return err
 	}
-	log.WithField(logutil.AnalysisRunKey, name).WithField(logutil.NamespaceKey, namespace).Infof("Started syncing Analysis at (%v)", startTime)
 	run, err := c.analysisRunLister.AnalysisRuns(namespace).Get(name)
 	if k8serrors.IsNotFound(err) {
 		log.WithField(logutil.AnalysisRunKey, name).WithField(logutil.NamespaceKey, namespace).Info("Analysis has been deleted")
@@ -184,6 +190,10 @@ func (c *Controller) syncHandler(ctx context.Context, key string) error {
 	if err != nil {
 		return err
 	}
+	if !c.sharder.Owns(run) {
+		return nil
+	}
+	log.WithField(logutil.AnalysisRunKey, name).WithField(logutil.NamespaceKey, namespace).Infof("Started syncing Analysis at (%v)", startTime)
 
 	defer func() {
 		duration := time.Since(startTime)
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"strings"
@@ -11,6 +14,7 @@ import (
 	smiclientset "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/split/clientset/versioned"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
@@ -20,25 +24,41 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth/azure"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
 
 	"github.com/argoproj/argo-rollouts/metricproviders"
 	"github.com/argoproj/argo-rollouts/rollout"
+	"github.com/argoproj/argo-rollouts/utils/certs"
 	"github.com/argoproj/argo-rollouts/utils/errors"
 	"github.com/argoproj/argo-rollouts/utils/record"
+	"github.com/argoproj/argo-rollouts/utils/sharding"
 
 	"github.com/argoproj/argo-rollouts/controller"
 	"github.com/argoproj/argo-rollouts/controller/metrics"
 	jobprovider "github.com/argoproj/argo-rollouts/metricproviders/job"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	clientset "github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned"
+	rolloutinformers "github.com/argoproj/argo-rollouts/pkg/client/informers/externalversions/rollouts/v1alpha1"
 	"github.com/argoproj/argo-rollouts/pkg/signals"
+	"github.com/argoproj/argo-rollouts/utils/admission"
 	controllerutil "github.com/argoproj/argo-rollouts/utils/controller"
 	"github.com/argoproj/argo-rollouts/utils/defaults"
+	"github.com/argoproj/argo-rollouts/utils/eventbus"
+	"github.com/argoproj/argo-rollouts/utils/featureflag"
 	ingressutil "github.com/argoproj/argo-rollouts/utils/ingress"
 	istioutil "github.com/argoproj/argo-rollouts/utils/istio"
 	logutil "github.com/argoproj/argo-rollouts/utils/log"
+	"github.com/argoproj/argo-rollouts/utils/plugin"
+	"github.com/argoproj/argo-rollouts/utils/policy"
+	"github.com/argoproj/argo-rollouts/utils/queue"
+	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
+	tlsutil "github.com/argoproj/argo-rollouts/utils/tls"
 	"github.com/argoproj/argo-rollouts/utils/tolerantinformer"
+	"github.com/argoproj/argo-rollouts/utils/tracing"
 	"github.com/argoproj/argo-rollouts/utils/version"
+	"github.com/argoproj/argo-rollouts/utils/writeback"
 )
 
 const (
@@ -58,12 +78,20 @@ func newCommand() *cobra.Command {
 		rolloutResyncPeriod            int64
 		logLevel                       string
 		logFormat                      string
+		logSamplingInitial             int
+		logSamplingThereafter          int
 		klogLevel                      int
 		metricsPort                    int
 		healthzPort                    int
+		ofrepPort                      int
+		admissionWebhookPort           int
 		instanceID                     string
 		qps                            float32
 		burst                          int
+		dynamicQPS                     float32
+		dynamicBurst                   int
+		metricsQPS                     float32
+		metricsBurst                   int
 		rolloutThreads                 int
 		experimentThreads              int
 		analysisThreads                int
@@ -73,6 +101,8 @@ func newCommand() *cobra.Command {
 		ephemeralMetadataPodRetries    int
 		targetGroupBindingVersion      string
 		albTagKeyResourceID            string
+		awsRoleARN                     string
+		awsWebIdentitySource           string
 		istioVersion                   string
 		trafficSplitVersion            string
 		traefikAPIGroup                string
@@ -83,13 +113,41 @@ func newCommand() *cobra.Command {
 		albIngressClasses              []string
 		nginxIngressClasses            []string
 		awsVerifyTargetGroup           bool
+		istioVerifyWeight              bool
 		namespaced                     bool
 		printVersion                   bool
 		selfServiceNotificationEnabled bool
 		controllersEnabled             []string
 		pprofAddress                   string
+		pprofTokenFile                 string
+		statusUpdateWindow             time.Duration
+		eventThrottleWindow            time.Duration
+		eventThrottleReasonWindows     map[string]string
+		resyncPeriods                  controller.ResyncPeriods
+		workerScaling                  controller.WorkerScaling
+		warmStart                      controller.WarmStart
+		garbageCollection              controller.GarbageCollection
+		progressionLimits              controller.ProgressionLimits
+		reconcileCache                 controller.ReconcileCache
+		shards                         int
+		shard                          int
+		replicaSetLabelSelector        string
+		compactReplicaSets             bool
+		syncActionsEnabled             bool
+		serviceLabelSelector           string
+		otlpAddress                    string
+		eventBusSinks                  []string
+		writeBackConfig                writeback.Config
+		writeBackTokenFile             string
+		selfSignedTLS                  bool
+		selfSignedTLSConfig            certs.Config
+		selfSignedTLSService           string
+		outboundTLSConfig              tlsutil.Config
+		otlpInsecure                   bool
+		otlpSampleRatio                float64
 	)
 	electOpts := controller.NewLeaderElectionOptions()
+	rateLimiterConfig := queue.DefaultRateLimiterConfig()
 	var command = cobra.Command{
 		Use:   cliName,
 		Short: "argo-rollouts is a controller to operate on rollout CRD",
@@ -104,21 +162,67 @@ func newCommand() *cobra.Command {
 				log.SetFormatter(createFormatter(logFormat))
 				logger.SetFormatter(createFormatter(logFormat))
 			}
+			if logSamplingInitial > 0 {
+				log.SetFormatter(logutil.NewSamplingFormatter(log.StandardLogger().Formatter, logSamplingInitial, logSamplingThereafter, time.Second))
+			}
 			logutil.SetKLogLogger(logger)
 			logutil.SetKLogLevel(klogLevel)
 
 			// set up signals so we handle the first shutdown signal gracefully
 			ctx := signals.SetupSignalHandlerContext()
 
+			if !outboundTLSConfig.IsZero() {
+				tlsConfig, err := outboundTLSConfig.TLSConfig()
+				errors.CheckError(err)
+				tlsutil.SetClientConfig(tlsConfig)
+				log.Info("Applying the outbound TLS policy")
+			}
+
+			if otlpAddress != "" {
+				shutdown, err := tracing.InitTracer(ctx, otlpAddress, otlpInsecure, otlpSampleRatio)
+				errors.CheckError(err)
+				defer func() {
+					if err := shutdown(context.Background()); err != nil {
+						log.Warnf("Failed to shut down tracer: %v", err)
+					}
+				}()
+				log.Infof("Exporting traces to %s", otlpAddress)
+			}
+
+			if len(eventBusSinks) > 0 {
+				shutdown, err := eventbus.Init(eventBusSinks)
+				errors.CheckError(err)
+				defer shutdown()
+				log.Infof("Publishing rollout lifecycle events to %d event sinks", len(eventBusSinks))
+			}
+
+			if writeBackConfig.RepoURL != "" {
+				token, err := readToken(writeBackTokenFile)
+				errors.CheckError(err)
+				writeBackConfig.Token = token
+				shutdown, err := writeback.Init(writeBackConfig)
+				errors.CheckError(err)
+				defer shutdown()
+				log.Infof("Writing promotions and aborts back to %s", writeBackConfig.RepoURL)
+			}
+
 			defaults.SetVerifyTargetGroup(awsVerifyTargetGroup)
 			defaults.SetTargetGroupBindingAPIVersion(targetGroupBindingVersion)
 			defaults.SetalbTagKeyResourceID(albTagKeyResourceID)
+			defaults.SetAWSRoleARN(awsRoleARN)
+			defaults.SetAWSWebIdentitySource(awsWebIdentitySource)
 			defaults.SetIstioAPIVersion(istioVersion)
+			defaults.SetIstioVerifyWeight(istioVerifyWeight)
 			defaults.SetAmbassadorAPIVersion(ambassadorVersion)
 			defaults.SetSMIAPIVersion(trafficSplitVersion)
 			defaults.SetAppMeshCRDVersion(appmeshCRDVersion)
 			defaults.SetTraefikAPIGroup(traefikAPIGroup)
 			defaults.SetTraefikVersion(traefikVersion)
+			defaults.SetRolloutStatusUpdateWindow(statusUpdateWindow)
+			defaults.SetSyncActionsEnabled(syncActionsEnabled)
+			reasonWindows, err := parseEventThrottleWindows(eventThrottleReasonWindows)
+			errors.CheckError(err)
+			defaults.SetEventThrottleWindows(eventThrottleWindow, reasonWindows)
 
 			config, err := clientConfig.ClientConfig()
 			errors.CheckError(err)
@@ -130,6 +234,8 @@ func newCommand() *cobra.Command {
 			if namespaced {
 				namespace = configNS
 			}
+			sharder, err := newSharder(shards, shard)
+			errors.CheckError(err)
 			log.WithFields(log.Fields{
 				"version":     version.GetVersion(),
 				"namespace":   namespace,
@@ -141,26 +247,54 @@ func newCommand() *cobra.Command {
 			k8sRequestProvider := &metrics.K8sRequestsCountProvider{}
 			kubeclientmetrics.AddMetricsTransportWrapper(config, k8sRequestProvider.IncKubernetesRequest)
 
+			// the dynamic and metrics clients are rate limited separately, so that neither the traffic routers nor the
+			// analysis Jobs starve the reconciliation of the rollouts
+			dynamicConfig := newClientConfig(config, "dynamic", dynamicQPS, dynamicBurst)
+			metricsConfig := newClientConfig(config, "metrics", metricsQPS, metricsBurst)
+			config = newClientConfig(config, "main", qps, burst)
+
 			kubeClient, err := kubernetes.NewForConfig(config)
 			errors.CheckError(err)
 			argoprojClient, err := clientset.NewForConfig(config)
 			errors.CheckError(err)
-			dynamicClient, err := dynamic.NewForConfig(config)
+			dynamicClient, err := dynamic.NewForConfig(dynamicConfig)
+			errors.CheckError(err)
+			metricsKubeClient, err := kubernetes.NewForConfig(metricsConfig)
 			errors.CheckError(err)
 			discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
 			errors.CheckError(err)
 			smiClient, err := smiclientset.NewForConfig(config)
 			errors.CheckError(err)
+			var certRotator *certs.Rotator
+			if selfSignedTLS {
+				selfSignedTLSConfig.Namespace = defaults.Namespace()
+				if len(selfSignedTLSConfig.DNSNames) == 0 {
+					selfSignedTLSConfig.DNSNames = certs.DefaultDNSNames(selfSignedTLSService, selfSignedTLSConfig.Namespace)
+				}
+				// webhook configurations are cluster-scoped, so that the CA bundle can only be injected with cluster RBAC
+				selfSignedTLSConfig.InjectWebhooks = !namespaced
+				certRotator = certs.NewRotator(kubeClient, selfSignedTLSConfig)
+				errors.CheckError(certRotator.Rotate(ctx))
+				go certRotator.Run(ctx)
+				log.Infof("Serving self-signed certificates for %s", strings.Join(selfSignedTLSConfig.DNSNames, ", "))
+			}
 			resyncDuration := time.Duration(rolloutResyncPeriod) * time.Second
 			kubeInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(
 				kubeClient,
 				resyncDuration,
 				kubeinformers.WithNamespace(namespace))
+			err = controllerutil.RegisterLabelFilteredInformers(kubeInformerFactory, namespace, replicaSetLabelSelector, serviceLabelSelector)
+			errors.CheckError(err)
+			if compactReplicaSets {
+				defaults.SetCompactReplicaSetHistory(true)
+				err = kubeInformerFactory.Apps().V1().ReplicaSets().Informer().SetTransform(replicasetutil.CompactReplicaSet)
+				errors.CheckError(err)
+			}
 			instanceIDSelector := controllerutil.InstanceIDRequirement(instanceID)
 			instanceIDTweakListFunc := func(options *metav1.ListOptions) {
 				options.LabelSelector = instanceIDSelector.String()
 			}
-			jobKubeClient, _, err := metricproviders.GetAnalysisJobClientset(kubeClient)
+			jobKubeClient, _, err := metricproviders.GetAnalysisJobClientset(metricsKubeClient)
 			errors.CheckError(err)
 			jobNs := metricproviders.GetAnalysisJobNamespace()
 			if jobNs == "" {
@@ -174,6 +308,15 @@ func newCommand() *cobra.Command {
 				kubeinformers.WithTweakListOptions(func(options *metav1.ListOptions) {
 					options.LabelSelector = jobprovider.AnalysisRunUIDLabelKey
 				}))
+			// The pods of the rollouts are watched by a separate factory, which only caches the pods
+			// labeled with the pod template hash of a rollout
+			rolloutPodsInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(
+				kubeClient,
+				resyncDuration,
+				kubeinformers.WithNamespace(namespace),
+				kubeinformers.WithTweakListOptions(func(options *metav1.ListOptions) {
+					options.LabelSelector = v1alpha1.DefaultRolloutUniqueLabelKey
+				}))
 			// We need three dynamic informer factories:
 			// 1. The first is the dynamic informer for rollouts, analysisruns, analysistemplates, experiments
 			dynamicInformerFactory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynamicClient, resyncDuration, namespace, instanceIDTweakListFunc)
@@ -215,16 +358,27 @@ func newCommand() *cobra.Command {
 				}),
 			)
 
+			watchLogLevel(ctx, kubeClient, log.GetLevel())
+			watchProgressionPolicies(ctx, kubeClient)
+			if ofrepPort > 0 {
+				var tlsConfig *tls.Config
+				if certRotator != nil {
+					tlsConfig = certRotator.TLSConfig()
+				}
+				serveOFREP(ctx, kubeClient, tolerantinformer.NewTolerantRolloutInformer(dynamicInformerFactory), ofrepPort, tlsConfig)
+			}
+			if admissionWebhookPort > 0 {
+				if certRotator == nil {
+					log.Fatal("--admission-webhook-port requires --self-signed-tls, since the API server only calls webhooks over HTTPS")
+				}
+				serveAdmissionWebhooks(ctx, kubeClient, admissionWebhookPort, certRotator.TLSConfig())
+			}
+
 			mode, err := ingressutil.DetermineIngressMode(ingressVersion, kubeClient.DiscoveryClient)
 			errors.CheckError(err)
 			ingressWrapper, err := ingressutil.NewIngressWrapper(mode, kubeClient, kubeInformerFactory)
 			errors.CheckError(err)
 
-			if pprofAddress != "" {
-				mux := controller.NewPProfServer()
-				go func() { log.Println(http.ListenAndServe(pprofAddress, mux)) }()
-			}
-
 			var cm *controller.Manager
 
 			enabledControllers, err := getEnabledControllers(controllersEnabled)
@@ -250,7 +404,12 @@ func newCommand() *cobra.Command {
 					clusterDynamicInformerFactory,
 					namespaced,
 					kubeInformerFactory,
-					jobInformerFactory)
+					jobInformerFactory,
+					sharder,
+					rateLimiterConfig,
+					resyncPeriods,
+					workerScaling,
+					metricsKubeClient)
 			} else {
 				cm = controller.NewManager(
 					namespace,
@@ -264,11 +423,14 @@ func newCommand() *cobra.Command {
 					ingressWrapper,
 					jobInformerFactory.Batch().V1().Jobs(),
 					jobInformerFactory.Core().V1().Pods(),
+					rolloutPodsInformerFactory.Core().V1().Pods(),
 					tolerantinformer.NewTolerantRolloutInformer(dynamicInformerFactory),
 					tolerantinformer.NewTolerantExperimentInformer(dynamicInformerFactory),
 					tolerantinformer.NewTolerantAnalysisRunInformer(dynamicInformerFactory),
 					tolerantinformer.NewTolerantAnalysisTemplateInformer(dynamicInformerFactory),
 					tolerantinformer.NewTolerantClusterAnalysisTemplateInformer(clusterDynamicInformerFactory),
+					tolerantinformer.NewTolerantRolloutNotificationPolicyInformer(dynamicInformerFactory),
+					tolerantinformer.NewTolerantRolloutControllerConfigInformer(dynamicInformerFactory),
 					istioPrimaryDynamicClient,
 					istioDynamicInformerFactory.ForResource(istioutil.GetIstioVirtualServiceGVR()).Informer(),
 					istioDynamicInformerFactory.ForResource(istioutil.GetIstioDestinationRuleGVR()).Informer(),
@@ -287,9 +449,34 @@ func newCommand() *cobra.Command {
 					namespaced,
 					kubeInformerFactory,
 					jobInformerFactory,
+					rolloutPodsInformerFactory,
 					ephemeralMetadataThreads,
-					ephemeralMetadataPodRetries)
+					ephemeralMetadataPodRetries,
+					sharder,
+					rateLimiterConfig,
+					resyncPeriods,
+					workerScaling,
+					warmStart,
+					garbageCollection,
+					progressionLimits,
+					reconcileCache,
+					metricsKubeClient)
+			}
+			// the config is initialized by the manager before the plugins are reloaded
+			watchPlugins(ctx, kubeClient)
+			if certRotator != nil {
+				cm.SetTLSConfig(certRotator.TLSConfig())
+			}
+			if pprofAddress != "" {
+				token, err := readToken(pprofTokenFile)
+				errors.CheckError(err)
+				if token == "" {
+					log.Warnf("%s is not served without --pprof-token-file", controller.DumpPath)
+				}
+				handler := cm.PProfHandler(token)
+				go func() { log.Println(http.ListenAndServe(pprofAddress, handler)) }()
 			}
+
 			if err = cm.Run(ctx, rolloutThreads, serviceThreads, ingressThreads, experimentThreads, analysisThreads, electOpts); err != nil {
 				log.Fatalf("Error running controller: %s", err.Error())
 			}
@@ -302,29 +489,49 @@ func newCommand() *cobra.Command {
 
 	clientConfig = addKubectlFlagsToCmd(&command)
 	command.Flags().Int64Var(&rolloutResyncPeriod, "rollout-resync", controller.DefaultRolloutResyncPeriod, "Time period in seconds for rollouts resync.")
+	command.Flags().DurationVar(&resyncPeriods.Rollouts, "rollout-informer-resync", 0, "Resync period of the Rollouts (e.g. 10m). Defaults to --rollout-resync")
+	command.Flags().DurationVar(&resyncPeriods.ReplicaSets, "replicaset-informer-resync", 0, "Resync period in which the Rollouts of all ReplicaSets are reconciled (e.g. 30m). ReplicaSets are not resynced when zero")
+	command.Flags().DurationVar(&resyncPeriods.AnalysisRuns, "analysisrun-informer-resync", 0, "Resync period of the AnalysisRuns (e.g. 5m). Defaults to --rollout-resync")
+	command.Flags().DurationVar(&resyncPeriods.Experiments, "experiment-informer-resync", 0, "Resync period of the Experiments (e.g. 5m). Defaults to --rollout-resync")
 	command.Flags().BoolVar(&namespaced, "namespaced", false, "runs controller in namespaced mode (does not require cluster RBAC)")
 	command.Flags().StringVar(&logLevel, "loglevel", "info", "Set the logging level. One of: debug|info|warn|error")
 	command.Flags().StringVar(&logFormat, "logformat", "", "Set the logging format. One of: text|json")
 	command.Flags().IntVar(&klogLevel, "kloglevel", 0, "Set the klog logging level")
+	command.Flags().IntVar(&logSamplingInitial, "log-sampling-initial", 0, "Number of info and debug messages with the same level and message logged per second before the messages are sampled. Sampling is disabled when zero")
+	command.Flags().IntVar(&logSamplingThereafter, "log-sampling-thereafter", 100, "Log every n-th info or debug message with the same level and message per second once --log-sampling-initial messages were logged")
 	command.Flags().IntVar(&metricsPort, "metricsPort", controller.DefaultMetricsPort, "Set the port the metrics endpoint should be exposed over")
 	command.Flags().IntVar(&metricsPort, "metricsport", controller.DefaultMetricsPort, "Set the port the metrics endpoint should be exposed over (deprecated, use --metricsPort)")
 	command.Flags().MarkDeprecated("metricsport", "use --metricsPort instead")
 	command.Flags().IntVar(&healthzPort, "healthzPort", controller.DefaultHealthzPort, "Set the port the healthz endpoint should be exposed over")
+	command.Flags().IntVar(&admissionWebhookPort, "admission-webhook-port", 0, "Serve the validating and mutating admission webhooks of the Rollouts over HTTPS on this port (requires --self-signed-tls). Disabled when zero")
+	command.Flags().IntVar(&ofrepPort, "ofrep-port", 0, "Serve the OpenFeature flags of the setFeatureFlag steps with the OpenFeature Remote Evaluation Protocol over this port, to the holders of Kubernetes tokens which may get the rollouts of the namespace. Disabled when zero")
+	command.Flags().DurationVar(&plugin.DrainTimeout, "plugin-drain-timeout", plugin.DrainTimeout, "Maximum duration to wait for the in-flight calls of a plugin to complete before its process is stopped, when the plugin is reloaded after a change of its definition in the argo-rollouts-config ConfigMap")
 	command.Flags().StringVar(&instanceID, "instance-id", "", "Indicates which argo rollout objects the controller should operate on")
 	command.Flags().Float32Var(&qps, "qps", defaults.DefaultQPS, "Maximum QPS (queries per second) to the K8s API server")
 	command.Flags().IntVar(&burst, "burst", defaults.DefaultBurst, "Maximum burst for throttle.")
+	command.Flags().Float32Var(&dynamicQPS, "dynamic-qps", 0, "Maximum QPS (queries per second) of the dynamic client to the K8s API server, used for the traffic routers and the dynamic informers. Defaults to --qps")
+	command.Flags().IntVar(&dynamicBurst, "dynamic-burst", 0, "Maximum burst of the dynamic client. Defaults to --burst")
+	command.Flags().Float32Var(&metricsQPS, "metrics-qps", 0, "Maximum QPS (queries per second) of the client of the metric providers to the K8s API server, e.g. for the Jobs of job metrics. Defaults to --qps")
+	command.Flags().IntVar(&metricsBurst, "metrics-burst", 0, "Maximum burst of the client of the metric providers. Defaults to --burst")
 	command.Flags().IntVar(&rolloutThreads, "rollout-threads", controller.DefaultRolloutThreads, "Set the number of worker threads for the Rollout controller")
 	command.Flags().IntVar(&experimentThreads, "experiment-threads", controller.DefaultExperimentThreads, "Set the number of worker threads for the Experiment controller")
 	command.Flags().IntVar(&analysisThreads, "analysis-threads", controller.DefaultAnalysisThreads, "Set the number of worker threads for the Experiment controller")
+	command.Flags().IntVar(&workerScaling.MaxRolloutWorkers, "rollout-max-threads", 0, "Maximum number of worker threads of the Rollout controller. When greater than --rollout-threads, the number of workers is scaled between both based on the depth of the workqueue")
+	command.Flags().IntVar(&workerScaling.MaxExperimentWorkers, "experiment-max-threads", 0, "Maximum number of worker threads of the Experiment controller. When greater than --experiment-threads, the number of workers is scaled between both based on the depth of the workqueue")
+	command.Flags().IntVar(&workerScaling.MaxAnalysisWorkers, "analysis-max-threads", 0, "Maximum number of worker threads of the Analysis controller. When greater than --analysis-threads, the number of workers is scaled between both based on the depth of the workqueue")
+	command.Flags().DurationVar(&workerScaling.TargetLatency, "worker-target-latency", controllerutil.DefaultWorkerTargetLatency, "Duration in which the items of a workqueue should be processed. Workers are added when a workqueue is estimated to take longer to drain")
+	command.Flags().DurationVar(&workerScaling.Interval, "worker-scale-interval", controllerutil.DefaultWorkerScaleInterval, "Interval in which the number of workers is adjusted to the depth of the workqueues")
 	command.Flags().IntVar(&serviceThreads, "service-threads", controller.DefaultServiceThreads, "Set the number of worker threads for the Service controller")
 	command.Flags().IntVar(&ingressThreads, "ingress-threads", controller.DefaultIngressThreads, "Set the number of worker threads for the Ingress controller")
 	command.Flags().IntVar(&ephemeralMetadataThreads, "ephemeral-metadata-threads", rollout.DefaultEphemeralMetadataThreads, "Set the number of worker threads for the Ephemeral Metadata reconciler")
 	command.Flags().IntVar(&ephemeralMetadataPodRetries, "ephemeral-metadata-update-pod-retries", rollout.DefaultEphemeralMetadataPodRetries, "Set the number of retries to update pod Ephemeral Metadata")
 	command.Flags().StringVar(&targetGroupBindingVersion, "aws-target-group-binding-api-version", defaults.DefaultTargetGroupBindingAPIVersion, "Set the default AWS TargetGroupBinding apiVersion that controller uses when verifying target group weights.")
+	command.Flags().StringVar(&awsRoleARN, "aws-role-arn", "", "IAM role the controller assumes when calling the AWS APIs, on top of the credentials of its environment such as IAM roles for service accounts or EKS Pod Identity, or with the token of --aws-web-identity-source")
+	command.Flags().StringVar(&awsWebIdentitySource, "aws-web-identity-source", "", "Source of the web identity token exchanged for the credentials of --aws-role-arn when the controller runs outside of AWS: gcp (GKE Workload Identity) or azure (Azure Workload Identity)")
 	command.Flags().StringVar(&albTagKeyResourceID, "alb-tag-key-resource-id", defaults.DefaultAlbTagKeyResourceID, "Set the default AWS LoadBalancer tag key for resource ID that controller uses when verifying target group weights.")
 	command.Flags().StringVar(&istioVersion, "istio-api-version", defaults.DefaultIstioVersion, "Set the default Istio apiVersion that controller should look when manipulating VirtualServices.")
 	command.Flags().StringVar(&ambassadorVersion, "ambassador-api-version", defaults.DefaultAmbassadorVersion, "Set the Ambassador apiVersion that controller should look when manipulating Ambassador Mappings.")
-	command.Flags().StringVar(&trafficSplitVersion, "traffic-split-api-version", defaults.DefaultSMITrafficSplitVersion, "Set the default TrafficSplit apiVersion that controller uses when creating TrafficSplits.")
+	command.Flags().StringVar(&trafficSplitVersion, "traffic-split-api-version", defaults.DefaultSMITrafficSplitVersion, "Set the default TrafficSplit apiVersion that controller uses when creating TrafficSplits (v1alpha1, v1alpha2, v1alpha3 or v1alpha4). Header routes require v1alpha3 or later.")
 	command.Flags().StringVar(&traefikAPIGroup, "traefik-api-group", defaults.DefaultTraefikAPIGroup, "Set the default Traefik apiGroup that controller uses.")
 	command.Flags().StringVar(&traefikVersion, "traefik-api-version", defaults.DefaultTraefikVersion, "Set the default Traefik apiVersion that controller uses.")
 	command.Flags().StringVar(&ingressVersion, "ingress-api-version", "", "Set the Ingress apiVersion that the controller should use.")
@@ -334,14 +541,99 @@ func newCommand() *cobra.Command {
 	command.Flags().BoolVar(&awsVerifyTargetGroup, "alb-verify-weight", false, "Verify ALB target group weights before progressing through steps (requires AWS privileges)")
 	command.Flags().MarkDeprecated("alb-verify-weight", "Use --aws-verify-target-group instead")
 	command.Flags().BoolVar(&awsVerifyTargetGroup, "aws-verify-target-group", false, "Verify ALB target group before progressing through steps (requires AWS privileges)")
+	command.Flags().BoolVar(&istioVerifyWeight, "istio-verify-weight", false, "Verify Istio weights from the Reconciled condition istiod reports on the VirtualServices before progressing through steps (requires PILOT_ENABLE_CONFIG_DISTRIBUTION_TRACKING on istiod)")
 	command.Flags().BoolVar(&printVersion, "version", false, "Print version")
 	command.Flags().BoolVar(&electOpts.LeaderElect, "leader-elect", controller.DefaultLeaderElect, "If true, controller will perform leader election between instances to ensure no more than one instance of controller operates at a time")
 	command.Flags().DurationVar(&electOpts.LeaderElectionLeaseDuration, "leader-election-lease-duration", controller.DefaultLeaderElectionLeaseDuration, "The duration that non-leader candidates will wait after observing a leadership renewal until attempting to acquire leadership of a led but unrenewed leader slot. This is effectively the maximum duration that a leader can be stopped before it is replaced by another candidate. This is only applicable if leader election is enabled.")
 	command.Flags().DurationVar(&electOpts.LeaderElectionRenewDeadline, "leader-election-renew-deadline", controller.DefaultLeaderElectionRenewDeadline, "The interval between attempts by the acting master to renew a leadership slot before it stops leading. This must be less than or equal to the lease duration. This is only applicable if leader election is enabled.")
 	command.Flags().DurationVar(&electOpts.LeaderElectionRetryPeriod, "leader-election-retry-period", controller.DefaultLeaderElectionRetryPeriod, "The duration the clients should wait between attempting acquisition and renewal of a leadership. This is only applicable if leader election is enabled.")
+	command.Flags().BoolVar(&electOpts.LeaderElectionGracefulHandOff, "leader-election-graceful-handoff", controller.DefaultLeaderElectionGracefulHandOff, "If true, the leader finishes its in-flight reconciliations and releases its lease on shutdown, so that another instance takes over right away instead of after the lease duration. With --warm-start-persist-interval, the new leader additionally skips the reconciliations of the unchanged rollouts until their next resync. This is only applicable if leader election is enabled.")
 	command.Flags().BoolVar(&selfServiceNotificationEnabled, "self-service-notification-enabled", false, "Allows rollouts controller to pull notification config from the namespace that the rollout resource is in. This is useful for self-service notification.")
 	command.Flags().StringSliceVar(&controllersEnabled, "controllers", nil, "Explicitly specify the list of controllers to run, currently only supports 'analysis', eg. --controller=analysis. Default: all controllers are enabled")
 	command.Flags().StringVar(&pprofAddress, "enable-pprof-address", "", "Enable pprof profiling on controller by providing a server address.")
+	command.Flags().StringVar(&pprofTokenFile, "pprof-token-file", "", "Path to a file containing a bearer token which requests to the pprof server must present in their Authorization header. The in-memory state dump (/debug/dump) is only served when set")
+	command.Flags().DurationVar(&rateLimiterConfig.BaseDelay, "workqueue-base-delay", queue.DefaultBaseDelay, "Delay of the first retry of a failing item of the workqueues, which doubles with every further failure")
+	command.Flags().DurationVar(&rateLimiterConfig.MaxDelay, "workqueue-max-delay", queue.DefaultMaxDelay, "Maximum delay between the retries of a failing item of the workqueues")
+	command.Flags().Float64Var(&rateLimiterConfig.BucketQPS, "workqueue-qps", 0, "Overall rate at which items are added to each workqueue. The overall rate is not limited when zero")
+	command.Flags().IntVar(&rateLimiterConfig.BucketSize, "workqueue-burst", 0, "Burst of the overall rate limit of each workqueue. Defaults to the workqueue QPS")
+	command.Flags().IntVar(&rateLimiterConfig.BackoffThreshold, "workqueue-backoff-threshold", queue.DefaultBackoffThreshold, "Number of consecutive failures of an item after which all additions of the item to the workqueue are delayed by its backoff, so that a persistently failing rollout can't starve the workqueue. Disabled when zero")
+	command.Flags().DurationVar(&statusUpdateWindow, "rollout-status-update-window", defaults.DefaultRolloutStatusUpdateWindow, "Window after a status update of a rollout in which further status updates that only change its replica counts are coalesced into a single update (e.g. 1s). Disabled when zero")
+	command.Flags().DurationVar(&eventThrottleWindow, "event-throttle-window", defaults.DefaultEventThrottleWindow, "Window in which repeated events with the same reason for the same object are suppressed, along with their notifications (e.g. 10m). Disabled when zero")
+	command.Flags().StringToStringVar(&eventThrottleReasonWindows, "event-throttle-reason-window", nil, "Window in which repeated events with a specific reason are suppressed, which takes precedence over --event-throttle-window (e.g. RolloutAborted=10m,RolloutUpdated=0s)")
+	command.Flags().DurationVar(&warmStart.PersistInterval, "warm-start-persist-interval", 0, "Interval in which the state of the rollouts at their last reconciliation is persisted to a ConfigMap, so that the reconciliations of unchanged rollouts are spread over --warm-start-spread after a restart (e.g. 30s). Disabled when zero")
+	command.Flags().DurationVar(&warmStart.Spread, "warm-start-spread", 5*time.Minute, "Period over which the first reconciliations of the unchanged rollouts are spread after a restart. Only applicable if --warm-start-persist-interval is set")
+	command.Flags().DurationVar(&garbageCollection.Interval, "garbage-collection-interval", 0, "Interval in which the AnalysisRuns and Experiments of rollouts which are no longer needed are garbage collected in the background, i.e. the ones whose rollout or revision no longer exists and the ones exceeding the history limits (e.g. 10m). Disabled when zero")
+	command.Flags().Float32Var(&garbageCollection.QPS, "garbage-collection-qps", rollout.DefaultGarbageCollectionQPS, "Maximum number of AnalysisRuns and Experiments deleted per second by the garbage collector")
+	command.Flags().IntVar(&progressionLimits.MaxConcurrent, "max-concurrent-progressions", 0, "Maximum number of rollouts progressing through an update at once. The updates of other rollouts are pending until a progressing rollout completes or is aborted. Unlimited when zero")
+	command.Flags().IntVar(&progressionLimits.MaxConcurrentPerNamespace, "max-concurrent-progressions-per-namespace", 0, "Maximum number of rollouts of a namespace progressing through an update at once. Unlimited when zero")
+	command.Flags().DurationVar(&reconcileCache.MaxAge, "reconcile-cache-max-age", 0, "Maximum age of the last reconciliation of an idle, fully promoted rollout for which reconciliations are skipped while neither the rollout nor its ReplicaSets, AnalysisRuns and Experiments change (e.g. 1h). Disabled when zero")
+	command.Flags().IntVar(&shards, "shards", 1, "Number of controller shards. When greater than 1, each shard reconciles a deterministic subset of the rollouts, experiments and analysis runs, and elects its own leader")
+	command.Flags().IntVar(&shard, "shard", -1, "Index of the shard this controller is responsible for. Defaults to the ordinal at the end of the hostname (e.g. of a StatefulSet pod). Only applicable if --shards is greater than 1")
+	command.Flags().StringVar(&replicaSetLabelSelector, "replicaset-label-selector", "", "Only watch and cache the ReplicaSets matching this label selector (e.g. rollouts-pod-template-hash) to reduce the memory usage of the controller")
+	command.Flags().BoolVar(&compactReplicaSets, "compact-replicaset-history", false, "Drop the pod template spec of the scaled down ReplicaSets of Rollouts from the informer cache, to reduce the memory usage of the controller on clusters with deep revision histories. The full ReplicaSets are fetched from the API server when needed")
+	command.Flags().BoolVar(&syncActionsEnabled, "sync-actions", false, "Retry or fully promote the rollouts whose rollout.argoproj.io/sync-action annotation requests it. The actions bypass the RBAC of the rollouts/status subresource, since any user who can update a rollout can set the annotation")
+	command.Flags().StringVar(&serviceLabelSelector, "service-label-selector", "", "Only watch and cache the Services matching this label selector to reduce the memory usage of the controller. All Services referenced by Rollouts and Experiments must match it")
+	command.Flags().StringVar(&otlpAddress, "otlp-address", "", "Address of an OpenTelemetry collector (e.g. otel-collector:4317) to export traces of the rollout reconciliations to via OTLP/gRPC. Tracing is disabled when empty")
+	command.Flags().StringArrayVar(&eventBusSinks, "event-bus-sink", nil, "Publish CloudEvents of the lifecycle of the rollouts, such as step completions, promotions, aborts and analysis verdicts, to a sink (e.g. https://collector.example.com/events, nats://nats:4222/rollouts or kafka+http://kafka-rest-proxy:8082/rollouts). Can be repeated")
+	command.Flags().StringVar(&writeBackConfig.RepoURL, "git-write-back-repo", "", "Record the promotions and aborts of the rollouts, with the promoted images and revision, in a GitHub or GitHub Enterprise repository (e.g. https://github.com/example/deployments). Disabled when empty")
+	command.Flags().StringVar(&writeBackConfig.Branch, "git-write-back-branch", writeback.DefaultBranch, "Branch of the write-back repository the promotions and aborts are committed to, or the base branch of their pull requests")
+	command.Flags().StringVar(&writeBackConfig.Path, "git-write-back-path", "", "Directory of the write-back repository under which the file <namespace>/<name>.yaml of each rollout is written")
+	command.Flags().BoolVar(&writeBackConfig.PullRequest, "git-write-back-pull-request", false, "Open a pull request per promotion or abort instead of committing to the write-back branch")
+	command.Flags().StringVar(&writeBackTokenFile, "git-write-back-token-file", "", "Path to a file containing the token used to authenticate to the write-back repository (e.g. mounted from a Secret)")
+	command.Flags().BoolVar(&selfSignedTLS, "self-signed-tls", false, "Serve the metrics and healthz endpoints over HTTPS with a serving certificate generated and rotated by the controller, signed by a self-signed CA whose bundle is published to a ConfigMap")
+	command.Flags().StringVar(&selfSignedTLSConfig.SecretName, "self-signed-tls-secret", certs.DefaultSecretName, "Secret the self-signed CA and serving certificate are persisted to, so that all replicas of the controller serve the same certificate")
+	command.Flags().StringVar(&selfSignedTLSConfig.CABundleConfigMapName, "self-signed-tls-ca-bundle-configmap", certs.DefaultCABundleConfigMapName, "ConfigMap the bundle of the self-signed CA is published to, under the key "+certs.CABundleKey)
+	command.Flags().StringVar(&selfSignedTLSService, "self-signed-tls-service", "argo-rollouts-metrics", "Service whose DNS names the self-signed serving certificate is valid for. Ignored if --self-signed-tls-dns-names is set")
+	command.Flags().StringSliceVar(&selfSignedTLSConfig.DNSNames, "self-signed-tls-dns-names", nil, "DNS names the self-signed serving certificate is valid for. Defaults to the DNS names of --self-signed-tls-service")
+	command.Flags().DurationVar(&selfSignedTLSConfig.CertValidity, "self-signed-tls-cert-validity", certs.DefaultCertValidity, "Validity of the self-signed serving certificates, which are renewed once a third of their validity remains")
+	command.Flags().DurationVar(&selfSignedTLSConfig.CAOverlap, "self-signed-tls-ca-overlap", certs.DefaultCAOverlap, "Period in which a renewed self-signed CA is published in the CA bundle before it signs the serving certificate, so that the clients trust it by then")
+	command.Flags().StringVar(&outboundTLSConfig.MinVersion, "outbound-tls-min-version", "", "Minimum TLS version of the outbound connections to metric providers, notification services, webhooks and plugin downloads: 1.0, 1.1, 1.2 or 1.3. Defaults to the minimum of Go")
+	command.Flags().StringSliceVar(&outboundTLSConfig.CipherSuites, "outbound-tls-cipher-suites", nil, "Cipher suites of the outbound TLS 1.2 connections, e.g. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256. Defaults to the cipher suites of Go")
+	command.Flags().StringVar(&outboundTLSConfig.CAFile, "outbound-tls-ca-file", "", "PEM bundle of the CAs trusted by the outbound TLS connections, in addition to the CAs of the system")
+	command.Flags().StringVar(&outboundTLSConfig.CertFile, "outbound-tls-cert-file", "", "PEM client certificate presented by the outbound TLS connections to the servers which request one. Requires --outbound-tls-key-file")
+	command.Flags().StringVar(&outboundTLSConfig.KeyFile, "outbound-tls-key-file", "", "PEM key of the client certificate of --outbound-tls-cert-file")
+	command.Flags().BoolVar(&otlpInsecure, "otlp-insecure", false, "Export traces to the OpenTelemetry collector without TLS")
+	command.Flags().Float64Var(&otlpSampleRatio, "otlp-sample-ratio", 1, "Fraction of the rollout reconciliations which are traced, between 0 and 1")
+	command.AddCommand(newDumpCommand())
+	return &command
+}
+
+func newDumpCommand() *cobra.Command {
+	var (
+		address   string
+		tokenFile string
+	)
+	var command = cobra.Command{
+		Use:   "dump",
+		Short: "Print the in-memory state of a running controller",
+		Long:  "Print the in-memory state of a running controller (workqueue depths, informer cache sizes and the last reconciliation errors) to debug stalls. The controller must be started with --enable-pprof-address and --pprof-token-file.",
+		Args:  cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			token, err := readToken(tokenFile)
+			if err != nil {
+				return err
+			}
+			req, err := http.NewRequestWithContext(c.Context(), http.MethodGet, "http://"+address+controller.DumpPath, nil)
+			if err != nil {
+				return err
+			}
+			if token != "" {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return fmt.Errorf("failed to dump the controller state: %s", resp.Status)
+			}
+			_, err = io.Copy(c.OutOrStdout(), resp.Body)
+			return err
+		},
+	}
+	command.Flags().StringVar(&address, "address", "localhost:6060", "Address of the pprof server of the controller (see --enable-pprof-address)")
+	command.Flags().StringVar(&tokenFile, "token-file", "", "Path to a file containing the bearer token of the pprof server (see --pprof-token-file)")
 	return &command
 }
 
@@ -362,6 +654,147 @@ func addKubectlFlagsToCmd(cmd *cobra.Command) clientcmd.ClientConfig {
 	return clientcmd.NewInteractiveDeferredLoadingClientConfig(loadingRules, &overrides, os.Stdin)
 }
 
+// watchLogLevel watches the controller ConfigMap, so that the log level can be changed without restarting the
+// controller. The level given by the flags applies whenever the ConfigMap does not override it.
+func watchLogLevel(ctx context.Context, kubeClient kubernetes.Interface, defaultLevel log.Level) {
+	informerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(
+		kubeClient,
+		0,
+		kubeinformers.WithNamespace(defaults.Namespace()),
+		kubeinformers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fmt.Sprintf("metadata.name=%s", defaults.DefaultRolloutsConfigMapName)
+		}),
+	)
+	_, err := informerFactory.Core().V1().ConfigMaps().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			cm, _ := obj.(*corev1.ConfigMap)
+			logutil.SetLevelFromConfigMap(cm, defaultLevel)
+		},
+		UpdateFunc: func(_, obj any) {
+			cm, _ := obj.(*corev1.ConfigMap)
+			logutil.SetLevelFromConfigMap(cm, defaultLevel)
+		},
+		DeleteFunc: func(obj any) {
+			logutil.SetLevelFromConfigMap(nil, defaultLevel)
+		},
+	})
+	errors.CheckError(err)
+	informerFactory.Start(ctx.Done())
+}
+
+// watchProgressionPolicies watches the policy ConfigMap, so that the progression policies can be changed without
+// restarting the controller
+func watchProgressionPolicies(ctx context.Context, kubeClient kubernetes.Interface) {
+	informerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(
+		kubeClient,
+		0,
+		kubeinformers.WithNamespace(defaults.Namespace()),
+		kubeinformers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fmt.Sprintf("metadata.name=%s", policy.ConfigMapName)
+		}),
+	)
+	_, err := informerFactory.Core().V1().ConfigMaps().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			cm, _ := obj.(*corev1.ConfigMap)
+			policy.SetPoliciesFromConfigMap(cm)
+		},
+		UpdateFunc: func(_, obj any) {
+			cm, _ := obj.(*corev1.ConfigMap)
+			policy.SetPoliciesFromConfigMap(cm)
+		},
+		DeleteFunc: func(obj any) {
+			policy.SetPoliciesFromConfigMap(nil)
+		},
+	})
+	errors.CheckError(err)
+	informerFactory.Start(ctx.Done())
+}
+
+// watchPlugins watches the controller ConfigMap, so that the plugins are reloaded without restarting the controller
+// when their definitions change
+func watchPlugins(ctx context.Context, kubeClient kubernetes.Interface) {
+	informerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(
+		kubeClient,
+		0,
+		kubeinformers.WithNamespace(defaults.Namespace()),
+		kubeinformers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fmt.Sprintf("metadata.name=%s", defaults.DefaultRolloutsConfigMapName)
+		}),
+	)
+	reload := func(cm *corev1.ConfigMap) {
+		if err := plugin.ReloadPlugins(plugin.FileDownloaderImpl{}, kubeClient, cm); err != nil {
+			log.Errorf("Failed to reload plugins: %v", err)
+		}
+	}
+	_, err := informerFactory.Core().V1().ConfigMaps().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			cm, _ := obj.(*corev1.ConfigMap)
+			reload(cm)
+		},
+		UpdateFunc: func(_, obj any) {
+			cm, _ := obj.(*corev1.ConfigMap)
+			reload(cm)
+		},
+		DeleteFunc: func(obj any) {
+			reload(nil)
+		},
+	})
+	errors.CheckError(err)
+	informerFactory.Start(ctx.Done())
+}
+
+// serveOFREP serves the OpenFeature flags of the setFeatureFlag steps of the rollouts. It shares the rollout informer
+// of the controller, which it runs right away, so that every replica of the controller serves the flags, and not only
+// the leader. The informer factory of the controller skips the informer once it already runs.
+func serveOFREP(ctx context.Context, kubeClient kubernetes.Interface, rolloutsInformer rolloutinformers.RolloutInformer, port int, tlsConfig *tls.Config) {
+	go rolloutsInformer.Informer().Run(ctx.Done())
+	server := &http.Server{
+		Addr:      fmt.Sprintf(":%d", port),
+		Handler:   featureflag.NewOFREPHandler(rolloutsInformer.Lister(), kubeClient),
+		TLSConfig: tlsConfig,
+	}
+	go func() {
+		var err error
+		if tlsConfig != nil {
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			log.Errorf("OFREP server failed: %v", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+	log.Infof("Serving OpenFeature flags over OFREP on port %d", port)
+}
+
+// serveAdmissionWebhooks serves the validating and mutating admission webhooks of the rollouts over HTTPS
+func serveAdmissionWebhooks(ctx context.Context, kubeClient kubernetes.Interface, port int, tlsConfig *tls.Config) {
+	// the updates of the controller are not recorded as actions of users
+	username, err := admission.Username(ctx, kubeClient)
+	if err != nil {
+		log.Warnf("Failed to determine the user of the controller, the actions of users on the Rollouts are not recorded: %v", err)
+	}
+	server := &http.Server{
+		Addr:      fmt.Sprintf(":%d", port),
+		Handler:   admission.NewHandler(kubeClient, username),
+		TLSConfig: tlsConfig,
+	}
+	go func() {
+		if err := server.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+			log.Errorf("Admission webhook server failed: %v", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+	log.Infof("Serving the admission webhooks of the Rollouts on port %d", port)
+}
+
 // setLogLevel parses and sets a logrus log level
 func setLogLevel(logLevel string) {
 	level, err := log.ParseLevel(logLevel)
@@ -401,3 +834,60 @@ func getEnabledControllers(controllersEnabled []string) (map[string]bool, error)
 	}
 	return enabledControllers, nil
 }
+
+// readToken reads a bearer token from a file. No token is returned when the path is empty.
+func readToken(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// parseEventThrottleWindows parses the windows in which repeated events are suppressed, keyed by the event reason
+func parseEventThrottleWindows(reasonWindows map[string]string) (map[string]time.Duration, error) {
+	windows := make(map[string]time.Duration, len(reasonWindows))
+	for reason, value := range reasonWindows {
+		window, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid event throttle window for reason %s: %w", reason, err)
+		}
+		windows[reason] = window
+	}
+	return windows, nil
+}
+
+// newClientConfig returns a copy of the client config with the given client side rate limits, falling back to the
+// rate limits of the config when they are zero. The name of the client is added to the user agent, so that the
+// requests of the clients can be told apart by the API server, e.g. in audit logs and when debugging API Priority and
+// Fairness.
+func newClientConfig(config *rest.Config, client string, qps float32, burst int) *rest.Config {
+	clientConfig := rest.CopyConfig(config)
+	if qps > 0 {
+		clientConfig.QPS = qps
+	}
+	if burst > 0 {
+		clientConfig.Burst = burst
+	}
+	clientConfig.UserAgent = fmt.Sprintf("argo-rollouts/%s (%s)", version.GetVersion().Version, client)
+	return clientConfig
+}
+
+// newSharder returns the sharder of the controller, or nil when sharding is disabled. When no shard index is
+// given, it is derived from the hostname of the controller pod.
+func newSharder(shards, shard int) (*sharding.Sharder, error) {
+	if shards > 1 && shard < 0 {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, err
+		}
+		shard, err = sharding.IndexFromHostname(hostname)
+		if err != nil {
+			return nil, fmt.Errorf("unable to determine the shard index, use --shard to set it explicitly: %w", err)
+		}
+	}
+	return sharding.NewSharder(shards, shard)
+}
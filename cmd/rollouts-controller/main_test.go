@@ -2,8 +2,10 @@ package main
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/rest"
 )
 
 func TestMetricsPortFlagCompatibility(t *testing.T) {
@@ -19,3 +21,29 @@ func TestMetricsPortFlagCompatibility(t *testing.T) {
 	// Test that deprecated flag is marked as deprecated
 	assert.True(t, metricsportFlag.Deprecated != "", "metricsport flag should be marked as deprecated")
 }
+
+func TestParseEventThrottleWindows(t *testing.T) {
+	windows, err := parseEventThrottleWindows(map[string]string{"RolloutAborted": "10m", "RolloutUpdated": "0s"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{"RolloutAborted": 10 * time.Minute, "RolloutUpdated": 0}, windows)
+
+	_, err = parseEventThrottleWindows(map[string]string{"RolloutAborted": "ten minutes"})
+	assert.EqualError(t, err, `invalid event throttle window for reason RolloutAborted: time: invalid duration "ten minutes"`)
+}
+
+func TestNewClientConfig(t *testing.T) {
+	config := &rest.Config{Host: "https://kubernetes", QPS: 40, Burst: 80}
+
+	clientConfig := newClientConfig(config, "dynamic", 100, 0)
+	assert.Equal(t, float32(100), clientConfig.QPS)
+	assert.Equal(t, 80, clientConfig.Burst)
+	assert.Contains(t, clientConfig.UserAgent, "(dynamic)")
+	assert.Equal(t, "https://kubernetes", clientConfig.Host)
+	// the config is not modified
+	assert.Equal(t, float32(40), config.QPS)
+	assert.Empty(t, config.UserAgent)
+
+	clientConfig = newClientConfig(config, "metrics", 0, 0)
+	assert.Equal(t, float32(40), clientConfig.QPS)
+	assert.Equal(t, 80, clientConfig.Burst)
+}
@@ -2,16 +2,19 @@ package controller
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/argoproj/argo-rollouts/utils/plugin"
 
 	istioutil "github.com/argoproj/argo-rollouts/utils/istio"
+	kedautil "github.com/argoproj/argo-rollouts/utils/keda"
 
 	goPlugin "github.com/hashicorp/go-plugin"
 
@@ -27,8 +30,10 @@ import (
 
 	smiclientset "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/split/clientset/versioned"
 	log "github.com/sirupsen/logrus"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/client-go/discovery"
@@ -53,10 +58,12 @@ import (
 	informers "github.com/argoproj/argo-rollouts/pkg/client/informers/externalversions/rollouts/v1alpha1"
 	"github.com/argoproj/argo-rollouts/rollout"
 	"github.com/argoproj/argo-rollouts/service"
+	controllerutil "github.com/argoproj/argo-rollouts/utils/controller"
 	"github.com/argoproj/argo-rollouts/utils/defaults"
 	ingressutil "github.com/argoproj/argo-rollouts/utils/ingress"
 	"github.com/argoproj/argo-rollouts/utils/queue"
 	"github.com/argoproj/argo-rollouts/utils/record"
+	"github.com/argoproj/argo-rollouts/utils/sharding"
 )
 
 const (
@@ -96,7 +103,11 @@ const (
 	// DefaultLeaderElectionRetryPeriod is the default time in seconds that the leader election clients should wait between tries of actions
 	DefaultLeaderElectionRetryPeriod = 2 * time.Second
 
+	// DefaultLeaderElectionGracefulHandOff is the default whether the leader releases its lease once it stopped after a shutdown signal
+	DefaultLeaderElectionGracefulHandOff = true
+
 	defaultLeaderElectionLeaseLockName = "argo-rollouts-controller-lock"
+	defaultWarmStartConfigMapName      = "argo-rollouts-warm-start"
 	listenAddr                         = "0.0.0.0:%d"
 )
 
@@ -106,6 +117,9 @@ type LeaderElectionOptions struct {
 	LeaderElectionLeaseDuration time.Duration
 	LeaderElectionRenewDeadline time.Duration
 	LeaderElectionRetryPeriod   time.Duration
+	// LeaderElectionGracefulHandOff makes the leader finish its in-flight reconciliations and release its lease on
+	// shutdown, instead of leaving the lease to expire
+	LeaderElectionGracefulHandOff bool
 }
 
 // leaseLockName returns the name of the lease lock used for leader election. When an
@@ -121,13 +135,92 @@ func leaseLockName(instanceID string) string {
 	return fmt.Sprintf("%s-%s", defaultLeaderElectionLeaseLockName, instanceID)
 }
 
+// shardLeaseLockName returns the name of the lease lock used for leader election of a controller shard. When
+// sharding is enabled, the index of the shard is appended to the lock name so that there is one active
+// controller replica per shard.
+func shardLeaseLockName(lockName string, sharder *sharding.Sharder) string {
+	if sharder == nil {
+		return lockName
+	}
+	return fmt.Sprintf("%s-shard-%d", lockName, sharder.Index())
+}
+
+// warmStartConfigMapName returns the name of the ConfigMap the warm start state of the controller is persisted to.
+// Like the lease lock, it is unique per instance ID and shard, since each controller persists its own rollouts.
+func warmStartConfigMapName(instanceID string, sharder *sharding.Sharder) string {
+	name := defaultWarmStartConfigMapName
+	if instanceID != "" {
+		name = fmt.Sprintf("%s-%s", name, instanceID)
+	}
+	return shardLeaseLockName(name, sharder)
+}
+
 func NewLeaderElectionOptions() *LeaderElectionOptions {
 	return &LeaderElectionOptions{
-		LeaderElect:                 DefaultLeaderElect,
-		LeaderElectionNamespace:     defaults.Namespace(),
-		LeaderElectionLeaseDuration: DefaultLeaderElectionLeaseDuration,
-		LeaderElectionRenewDeadline: DefaultLeaderElectionRenewDeadline,
-		LeaderElectionRetryPeriod:   DefaultLeaderElectionRetryPeriod,
+		LeaderElect:                   DefaultLeaderElect,
+		LeaderElectionNamespace:       defaults.Namespace(),
+		LeaderElectionLeaseDuration:   DefaultLeaderElectionLeaseDuration,
+		LeaderElectionRenewDeadline:   DefaultLeaderElectionRenewDeadline,
+		LeaderElectionRetryPeriod:     DefaultLeaderElectionRetryPeriod,
+		LeaderElectionGracefulHandOff: DefaultLeaderElectionGracefulHandOff,
+	}
+}
+
+// ResyncPeriods are the resync periods of the informers of the controller by the type of their objects. A zero
+// period defaults to the resync period of the informer factories, except for ReplicaSets which are not resynced
+// unless a period is set.
+type ResyncPeriods struct {
+	Rollouts     time.Duration
+	ReplicaSets  time.Duration
+	AnalysisRuns time.Duration
+	Experiments  time.Duration
+}
+
+// WorkerScaling configures the scaling of the workers of the rollout, experiment and analysis controllers based on
+// the depth of their workqueues. The workers of a controller are scaled between its threadiness and its maximum
+// number of workers, and are not scaled when the maximum is not greater than the threadiness.
+type WorkerScaling struct {
+	MaxRolloutWorkers    int
+	MaxExperimentWorkers int
+	MaxAnalysisWorkers   int
+	TargetLatency        time.Duration
+	Interval             time.Duration
+}
+
+// WarmStart configures the persistence of the state of the rollouts at their last successful reconciliation to a
+// ConfigMap. After a restart, the first reconciliations of the fully promoted rollouts which did not change since
+// are spread over the Spread period. It is disabled when the persist interval is zero.
+type WarmStart struct {
+	PersistInterval time.Duration
+	Spread          time.Duration
+}
+
+// GarbageCollection configures the periodic garbage collection of the AnalysisRuns and Experiments of rollouts which
+// are no longer needed. It is disabled when the interval is zero.
+type GarbageCollection struct {
+	Interval time.Duration
+	QPS      float32
+}
+
+// ProgressionLimits limits the number of rollouts which progress through an update at once, globally and per
+// namespace. A limit is disabled when it is zero.
+type ProgressionLimits struct {
+	MaxConcurrent             int
+	MaxConcurrentPerNamespace int
+}
+
+// ReconcileCache configures the skipping of the reconciliations of idle rollouts which did not change since their
+// last successful reconciliation. An unchanged rollout is reconciled again once its last reconciliation is older than
+// the max age. It is disabled when the max age is zero.
+type ReconcileCache struct {
+	MaxAge time.Duration
+}
+
+func (s WorkerScaling) forWorkers(maxWorkers int) controllerutil.WorkerScaling {
+	return controllerutil.WorkerScaling{
+		MaxWorkers:    maxWorkers,
+		TargetLatency: s.TargetLatency,
+		Interval:      s.Interval,
 	}
 }
 
@@ -148,13 +241,19 @@ type Manager struct {
 	analysisRunSynced             cache.InformerSynced
 	analysisTemplateSynced        cache.InformerSynced
 	clusterAnalysisTemplateSynced cache.InformerSynced
+	notificationPolicySynced      cache.InformerSynced
+	controllerConfigSynced        cache.InformerSynced
 	serviceSynced                 cache.InformerSynced
 	ingressSynced                 cache.InformerSynced
 	jobSynced                     cache.InformerSynced
 	jobPodsSynced                 cache.InformerSynced
+	rolloutPodsSynced             cache.InformerSynced
 	replicasSetSynced             cache.InformerSynced
 	configMapSynced               cache.InformerSynced
 	secretSynced                  cache.InformerSynced
+	podDisruptionBudgetSynced     cache.InformerSynced
+	rolloutJobSynced              cache.InformerSynced
+	scaledObjectSynced            cache.InformerSynced
 
 	rolloutWorkqueue     workqueue.RateLimitingInterface
 	serviceWorkqueue     workqueue.RateLimitingInterface
@@ -162,6 +261,9 @@ type Manager struct {
 	experimentWorkqueue  workqueue.RateLimitingInterface
 	analysisRunWorkqueue workqueue.RateLimitingInterface
 
+	// informerStores are the informer caches by the kind of their objects, which are reported in diagnostic dumps
+	informerStores map[string]cache.Store
+
 	refResolver rollout.TemplateRefResolver
 
 	kubeClientSet kubernetes.Interface
@@ -173,14 +275,21 @@ type Manager struct {
 	// against a shared cluster elect leaders independently (see leaseLockName).
 	instanceID string
 
+	// sharder is set when the controller runs in sharding mode. Each shard elects its own leader, so
+	// that one replica per shard is active at a time (see shardLeaseLockName).
+	sharder *sharding.Sharder
+
 	dynamicInformerFactory               dynamicinformer.DynamicSharedInformerFactory
 	clusterDynamicInformerFactory        dynamicinformer.DynamicSharedInformerFactory
 	istioDynamicInformerFactory          dynamicinformer.DynamicSharedInformerFactory
+	kedaDynamicInformerFactory           dynamicinformer.DynamicSharedInformerFactory
+	kedaDynamicClient                    dynamic.Interface
 	namespaced                           bool
 	kubeInformerFactory                  kubeinformers.SharedInformerFactory
 	notificationConfigMapInformerFactory kubeinformers.SharedInformerFactory
 	notificationSecretInformerFactory    kubeinformers.SharedInformerFactory
 	jobInformerFactory                   kubeinformers.SharedInformerFactory
+	rolloutPodsInformerFactory           kubeinformers.SharedInformerFactory
 	istioPrimaryDynamicClient            dynamic.Interface
 
 	onlyAnalysisMode bool
@@ -204,6 +313,11 @@ func NewAnalysisManager(
 	namespaced bool,
 	kubeInformerFactory kubeinformers.SharedInformerFactory,
 	jobInformerFactory kubeinformers.SharedInformerFactory,
+	sharder *sharding.Sharder,
+	rateLimiterConfig queue.RateLimiterConfig,
+	resyncPeriods ResyncPeriods,
+	workerScaling WorkerScaling,
+	metricsKubeclientset kubernetes.Interface,
 ) *Manager {
 	runtime.Must(rolloutscheme.AddToScheme(scheme.Scheme))
 	log.Info("Creating event broadcaster")
@@ -220,20 +334,32 @@ func NewAnalysisManager(
 	})
 
 	healthzServer := NewHealthzServer(fmt.Sprintf(listenAddr, healthzPort))
-	analysisRunWorkqueue := workqueue.NewNamedRateLimitingQueue(queue.DefaultArgoRolloutsRateLimiter(), "AnalysisRuns")
-	recorder := record.NewEventRecorder(kubeclientset, metrics.MetricRolloutEventsTotal, metrics.MetricNotificationFailedTotal, metrics.MetricNotificationSuccessTotal, metrics.MetricNotificationSend, nil)
+	analysisRunWorkqueue := queue.NewNamedRateLimitingQueue(rateLimiterConfig, "AnalysisRuns")
+	recorder := record.NewEventRecorder(kubeclientset, metrics.MetricRolloutEventsTotal, metrics.MetricNotificationFailedTotal, metrics.MetricNotificationSuccessTotal, metrics.MetricNotificationSend, nil, nil)
 	analysisController := analysis.NewController(analysis.ControllerConfig{
-		KubeClientSet:        kubeclientset,
-		ArgoProjClientset:    argoprojclientset,
-		AnalysisRunInformer:  analysisRunInformer,
-		JobInformer:          jobInformer,
-		JobPodsInformer:      jobPodsInformer,
-		ResyncPeriod:         resyncPeriod,
-		AnalysisRunWorkQueue: analysisRunWorkqueue,
-		MetricsServer:        metricsServer,
-		Recorder:             recorder,
+		KubeClientSet:           kubeclientset,
+		MetricsKubeClientSet:    metricsKubeclientset,
+		ArgoProjClientset:       argoprojclientset,
+		AnalysisRunInformer:     analysisRunInformer,
+		JobInformer:             jobInformer,
+		JobPodsInformer:         jobPodsInformer,
+		ResyncPeriod:            resyncPeriod,
+		AnalysisRunWorkQueue:    analysisRunWorkqueue,
+		MetricsServer:           metricsServer,
+		Recorder:                recorder,
+		AnalysisRunResyncPeriod: resyncPeriods.AnalysisRuns,
+		Sharder:                 sharder,
+		WorkerScaling:           workerScaling.forWorkers(workerScaling.MaxAnalysisWorkers),
 	})
 
+	informerStores := map[string]cache.Store{
+		"Jobs":                     jobInformer.Informer().GetStore(),
+		"Pods":                     jobPodsInformer.Informer().GetStore(),
+		"AnalysisRuns":             analysisRunInformer.Informer().GetStore(),
+		"AnalysisTemplates":        analysisTemplateInformer.Informer().GetStore(),
+		"ClusterAnalysisTemplates": clusterAnalysisTemplateInformer.Informer().GetStore(),
+	}
+
 	cm := &Manager{
 		wg:                            &sync.WaitGroup{},
 		metricsServer:                 metricsServer,
@@ -244,6 +370,7 @@ func NewAnalysisManager(
 		analysisTemplateSynced:        analysisTemplateInformer.Informer().HasSynced,
 		clusterAnalysisTemplateSynced: clusterAnalysisTemplateInformer.Informer().HasSynced,
 		analysisRunWorkqueue:          analysisRunWorkqueue,
+		informerStores:                informerStores,
 		analysisController:            analysisController,
 		namespace:                     namespace,
 		kubeClientSet:                 kubeclientset,
@@ -253,6 +380,7 @@ func NewAnalysisManager(
 		kubeInformerFactory:           kubeInformerFactory,
 		jobInformerFactory:            jobInformerFactory,
 		onlyAnalysisMode:              true,
+		sharder:                       sharder,
 	}
 
 	_, err := rolloutsConfig.InitializeConfig(kubeclientset, defaults.DefaultRolloutsConfigMapName)
@@ -281,11 +409,14 @@ func NewManager(
 	ingressWrap *ingressutil.IngressWrap,
 	jobInformer batchinformers.JobInformer,
 	jobPodsInformer coreinformers.PodInformer,
+	rolloutPodsInformer coreinformers.PodInformer,
 	rolloutsInformer informers.RolloutInformer,
 	experimentsInformer informers.ExperimentInformer,
 	analysisRunInformer informers.AnalysisRunInformer,
 	analysisTemplateInformer informers.AnalysisTemplateInformer,
 	clusterAnalysisTemplateInformer informers.ClusterAnalysisTemplateInformer,
+	notificationPolicyInformer informers.RolloutNotificationPolicyInformer,
+	controllerConfigInformer informers.RolloutControllerConfigInformer,
 	istioPrimaryDynamicClient dynamic.Interface,
 	istioVirtualServiceInformer cache.SharedIndexInformer,
 	istioDestinationRuleInformer cache.SharedIndexInformer,
@@ -304,8 +435,18 @@ func NewManager(
 	namespaced bool,
 	kubeInformerFactory kubeinformers.SharedInformerFactory,
 	jobInformerFactory kubeinformers.SharedInformerFactory,
+	rolloutPodsInformerFactory kubeinformers.SharedInformerFactory,
 	ephemeralMetadataThreads int,
 	ephemeralMetadataPodRetries int,
+	sharder *sharding.Sharder,
+	rateLimiterConfig queue.RateLimiterConfig,
+	resyncPeriods ResyncPeriods,
+	workerScaling WorkerScaling,
+	warmStart WarmStart,
+	garbageCollection GarbageCollection,
+	progressionLimits ProgressionLimits,
+	reconcileCache ReconcileCache,
+	metricsKubeclientset kubernetes.Interface,
 ) *Manager {
 	runtime.Must(rolloutscheme.AddToScheme(scheme.Scheme))
 	log.Info("Creating event broadcaster")
@@ -322,15 +463,23 @@ func NewManager(
 	})
 
 	healthzServer := NewHealthzServer(fmt.Sprintf(listenAddr, healthzPort))
-	rolloutWorkqueue := workqueue.NewNamedRateLimitingQueue(queue.DefaultArgoRolloutsRateLimiter(), "Rollouts")
-	experimentWorkqueue := workqueue.NewNamedRateLimitingQueue(queue.DefaultArgoRolloutsRateLimiter(), "Experiments")
-	analysisRunWorkqueue := workqueue.NewNamedRateLimitingQueue(queue.DefaultArgoRolloutsRateLimiter(), "AnalysisRuns")
-	serviceWorkqueue := workqueue.NewNamedRateLimitingQueue(queue.DefaultArgoRolloutsRateLimiter(), "Services")
-	ingressWorkqueue := workqueue.NewNamedRateLimitingQueue(queue.DefaultArgoRolloutsRateLimiter(), "Ingresses")
+	rolloutWorkqueue := queue.NewNamedPriorityRateLimitingQueue(rateLimiterConfig, "Rollouts")
+	experimentWorkqueue := queue.NewNamedRateLimitingQueue(rateLimiterConfig, "Experiments")
+	analysisRunWorkqueue := queue.NewNamedRateLimitingQueue(rateLimiterConfig, "AnalysisRuns")
+	serviceWorkqueue := queue.NewNamedRateLimitingQueue(rateLimiterConfig, "Services")
+	ingressWorkqueue := queue.NewNamedRateLimitingQueue(rateLimiterConfig, "Ingresses")
+
+	// the ScaledObjects are not labeled with the instance ID of the controller, so they are not filtered by it
+	kedaDynamicInformerFactory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynamicclientset, resyncPeriod, namespace, nil)
+	scaledObjectInformer := kedaDynamicInformerFactory.ForResource(kedautil.GetScaledObjectGVR()).Informer()
+	// the PodDisruptionBudgets of the ReplicaSets, and the Jobs of the load test and migration steps, are labeled with
+	// the pod template hash of their ReplicaSet, which the rollout pods informer factory selects
+	podDisruptionBudgetInformer := rolloutPodsInformerFactory.Policy().V1().PodDisruptionBudgets()
+	rolloutJobInformer := rolloutPodsInformerFactory.Batch().V1().Jobs()
 
 	refResolver := rollout.NewInformerBasedWorkloadRefResolver(namespace, dynamicclientset, discoveryClient, argoprojclientset, rolloutsInformer.Informer())
 	apiFactory := notificationapi.NewFactory(record.NewAPIFactorySettings(analysisRunInformer), defaults.Namespace(), notificationSecretInformerFactory.Core().V1().Secrets().Informer(), notificationConfigMapInformerFactory.Core().V1().ConfigMaps().Informer())
-	recorder := record.NewEventRecorder(kubeclientset, metrics.MetricRolloutEventsTotal, metrics.MetricNotificationFailedTotal, metrics.MetricNotificationSuccessTotal, metrics.MetricNotificationSend, apiFactory)
+	recorder := record.NewEventRecorder(kubeclientset, metrics.MetricRolloutEventsTotal, metrics.MetricNotificationFailedTotal, metrics.MetricNotificationSuccessTotal, metrics.MetricNotificationSend, apiFactory, notificationPolicyInformer.Lister())
 	notificationsController := notificationcontroller.NewControllerWithNamespaceSupport(dynamicclientset.Resource(v1alpha1.RolloutGVR), rolloutsInformer.Informer(), apiFactory,
 		notificationcontroller.WithToUnstructured(func(obj metav1.Object) (*unstructured.Unstructured, error) {
 			data, err := json.Marshal(obj)
@@ -344,8 +493,25 @@ func NewManager(
 			}
 			return res, nil
 		}),
+		notificationcontroller.WithSkipProcessing(func(obj metav1.Object) (bool, string) {
+			if !sharder.Owns(obj) {
+				return true, "rollout is reconciled by another controller shard"
+			}
+			return false, ""
+		}),
 	)
 
+	rolloutResyncPeriod := resyncPeriods.Rollouts
+	if rolloutResyncPeriod <= 0 {
+		rolloutResyncPeriod = resyncPeriod
+	}
+	warmStartConfig := rollout.WarmStartConfig{
+		Namespace:       defaults.Namespace(),
+		Name:            warmStartConfigMapName(instanceID, sharder),
+		PersistInterval: warmStart.PersistInterval,
+		Spread:          warmStart.Spread,
+		HandOffDelay:    rolloutResyncPeriod,
+	}
 	rolloutController := rollout.NewController(rollout.ControllerConfig{
 		Namespace:                       namespace,
 		KubeClientSet:                   kubeclientset,
@@ -357,11 +523,16 @@ func NewManager(
 		AnalysisRunInformer:             analysisRunInformer,
 		AnalysisTemplateInformer:        analysisTemplateInformer,
 		ClusterAnalysisTemplateInformer: clusterAnalysisTemplateInformer,
+		ControllerConfigInformer:        controllerConfigInformer,
 		IstioPrimaryDynamicClient:       istioPrimaryDynamicClient,
 		IstioVirtualServiceInformer:     istioVirtualServiceInformer,
 		IstioDestinationRuleInformer:    istioDestinationRuleInformer,
+		ScaledObjectInformer:            scaledObjectInformer,
 		ReplicaSetInformer:              replicaSetInformer,
 		ServicesInformer:                servicesInformer,
+		PodInformer:                     rolloutPodsInformer,
+		PodDisruptionBudgetInformer:     podDisruptionBudgetInformer,
+		JobInformer:                     rolloutJobInformer,
 		IngressWrapper:                  ingressWrap,
 		RolloutsInformer:                rolloutsInformer,
 		ResyncPeriod:                    resyncPeriod,
@@ -372,6 +543,22 @@ func NewManager(
 		Recorder:                        recorder,
 		EphemeralMetadataThreads:        ephemeralMetadataThreads,
 		EphemeralMetadataPodRetries:     ephemeralMetadataPodRetries,
+		RolloutResyncPeriod:             resyncPeriods.Rollouts,
+		ReplicaSetResyncPeriod:          resyncPeriods.ReplicaSets,
+		Sharder:                         sharder,
+		WorkerScaling:                   workerScaling.forWorkers(workerScaling.MaxRolloutWorkers),
+		WarmStart:                       warmStartConfig,
+		GarbageCollection: rollout.GarbageCollectionConfig{
+			Interval: garbageCollection.Interval,
+			QPS:      garbageCollection.QPS,
+		},
+		ProgressionLimits: rollout.ProgressionLimitsConfig{
+			MaxConcurrent:             progressionLimits.MaxConcurrent,
+			MaxConcurrentPerNamespace: progressionLimits.MaxConcurrentPerNamespace,
+		},
+		ReconcileCache: rollout.ReconcileCacheConfig{
+			MaxAge: reconcileCache.MaxAge,
+		},
 	})
 
 	experimentController := experiments.NewController(experiments.ControllerConfig{
@@ -388,18 +575,25 @@ func NewManager(
 		ExperimentWorkQueue:             experimentWorkqueue,
 		MetricsServer:                   metricsServer,
 		Recorder:                        recorder,
+		ExperimentResyncPeriod:          resyncPeriods.Experiments,
+		Sharder:                         sharder,
+		WorkerScaling:                   workerScaling.forWorkers(workerScaling.MaxExperimentWorkers),
 	})
 
 	analysisController := analysis.NewController(analysis.ControllerConfig{
-		KubeClientSet:        kubeclientset,
-		ArgoProjClientset:    argoprojclientset,
-		AnalysisRunInformer:  analysisRunInformer,
-		JobInformer:          jobInformer,
-		JobPodsInformer:      jobPodsInformer,
-		ResyncPeriod:         resyncPeriod,
-		AnalysisRunWorkQueue: analysisRunWorkqueue,
-		MetricsServer:        metricsServer,
-		Recorder:             recorder,
+		KubeClientSet:           kubeclientset,
+		MetricsKubeClientSet:    metricsKubeclientset,
+		ArgoProjClientset:       argoprojclientset,
+		AnalysisRunInformer:     analysisRunInformer,
+		JobInformer:             jobInformer,
+		JobPodsInformer:         jobPodsInformer,
+		ResyncPeriod:            resyncPeriod,
+		AnalysisRunWorkQueue:    analysisRunWorkqueue,
+		MetricsServer:           metricsServer,
+		Recorder:                recorder,
+		AnalysisRunResyncPeriod: resyncPeriods.AnalysisRuns,
+		Sharder:                 sharder,
+		WorkerScaling:           workerScaling.forWorkers(workerScaling.MaxAnalysisWorkers),
 	})
 
 	serviceController := service.NewController(service.ControllerConfig{
@@ -411,6 +605,7 @@ func NewManager(
 		ServiceWorkqueue:  serviceWorkqueue,
 		ResyncPeriod:      resyncPeriod,
 		MetricsServer:     metricsServer,
+		Sharder:           sharder,
 	})
 
 	ingressController := ingress.NewController(ingress.ControllerConfig{
@@ -425,8 +620,26 @@ func NewManager(
 
 		ALBClasses:   albIngressClasses,
 		NGINXClasses: nginxIngressClasses,
+		Sharder:      sharder,
 	})
 
+	informerStores := map[string]cache.Store{
+		"Rollouts":                    rolloutsInformer.Informer().GetStore(),
+		"ReplicaSets":                 replicaSetInformer.Informer().GetStore(),
+		"Services":                    servicesInformer.Informer().GetStore(),
+		"Experiments":                 experimentsInformer.Informer().GetStore(),
+		"AnalysisRuns":                analysisRunInformer.Informer().GetStore(),
+		"AnalysisTemplates":           analysisTemplateInformer.Informer().GetStore(),
+		"ClusterAnalysisTemplates":    clusterAnalysisTemplateInformer.Informer().GetStore(),
+		"RolloutNotificationPolicies": notificationPolicyInformer.Informer().GetStore(),
+		"RolloutControllerConfigs":    controllerConfigInformer.Informer().GetStore(),
+		"Jobs":                        jobInformer.Informer().GetStore(),
+		"Pods":                        jobPodsInformer.Informer().GetStore(),
+		"RolloutPods":                 rolloutPodsInformer.Informer().GetStore(),
+		"PodDisruptionBudgets":        podDisruptionBudgetInformer.Informer().GetStore(),
+		"RolloutJobs":                 rolloutJobInformer.Informer().GetStore(),
+	}
+
 	cm := &Manager{
 		wg:                                   &sync.WaitGroup{},
 		metricsServer:                        metricsServer,
@@ -436,18 +649,25 @@ func NewManager(
 		ingressSynced:                        ingressWrap.HasSynced,
 		jobSynced:                            jobInformer.Informer().HasSynced,
 		jobPodsSynced:                        jobPodsInformer.Informer().HasSynced,
+		rolloutPodsSynced:                    rolloutPodsInformer.Informer().HasSynced,
+		podDisruptionBudgetSynced:            podDisruptionBudgetInformer.Informer().HasSynced,
+		rolloutJobSynced:                     rolloutJobInformer.Informer().HasSynced,
 		experimentSynced:                     experimentsInformer.Informer().HasSynced,
 		analysisRunSynced:                    analysisRunInformer.Informer().HasSynced,
 		analysisTemplateSynced:               analysisTemplateInformer.Informer().HasSynced,
 		clusterAnalysisTemplateSynced:        clusterAnalysisTemplateInformer.Informer().HasSynced,
+		notificationPolicySynced:             optionalInformerSynced(discoveryClient, v1alpha1.RolloutNotificationPolicyGVR, notificationPolicyInformer.Informer()),
+		controllerConfigSynced:               optionalInformerSynced(discoveryClient, v1alpha1.RolloutControllerConfigGVR, controllerConfigInformer.Informer()),
 		replicasSetSynced:                    replicaSetInformer.Informer().HasSynced,
 		configMapSynced:                      notificationConfigMapInformerFactory.Core().V1().ConfigMaps().Informer().HasSynced,
 		secretSynced:                         notificationSecretInformerFactory.Core().V1().Secrets().Informer().HasSynced,
+		scaledObjectSynced:                   scaledObjectInformer.HasSynced,
 		rolloutWorkqueue:                     rolloutWorkqueue,
 		experimentWorkqueue:                  experimentWorkqueue,
 		analysisRunWorkqueue:                 analysisRunWorkqueue,
 		serviceWorkqueue:                     serviceWorkqueue,
 		ingressWorkqueue:                     ingressWorkqueue,
+		informerStores:                       informerStores,
 		rolloutController:                    rolloutController,
 		serviceController:                    serviceController,
 		ingressController:                    ingressController,
@@ -461,12 +681,16 @@ func NewManager(
 		dynamicInformerFactory:               dynamicInformerFactory,
 		clusterDynamicInformerFactory:        clusterDynamicInformerFactory,
 		istioDynamicInformerFactory:          istioDynamicInformerFactory,
+		kedaDynamicInformerFactory:           kedaDynamicInformerFactory,
+		kedaDynamicClient:                    dynamicclientset,
 		namespaced:                           namespaced,
 		kubeInformerFactory:                  kubeInformerFactory,
 		jobInformerFactory:                   jobInformerFactory,
+		rolloutPodsInformerFactory:           rolloutPodsInformerFactory,
 		istioPrimaryDynamicClient:            istioPrimaryDynamicClient,
 		notificationConfigMapInformerFactory: notificationConfigMapInformerFactory,
 		notificationSecretInformerFactory:    notificationSecretInformerFactory,
+		sharder:                              sharder,
 	}
 
 	_, err := rolloutsConfig.InitializeConfig(kubeclientset, defaults.DefaultRolloutsConfigMapName)
@@ -493,7 +717,7 @@ func (c *Manager) Run(ctx context.Context, rolloutThreadiness, serviceThreadines
 
 	go func() {
 		log.Infof("Starting Healthz Server at %s", c.healthzServer.Addr)
-		err := c.healthzServer.ListenAndServe()
+		err := listenAndServe(c.healthzServer)
 		if err != nil {
 			log.Error(fmt.Errorf("Healthz Server Error: %w", err))
 		}
@@ -501,14 +725,17 @@ func (c *Manager) Run(ctx context.Context, rolloutThreadiness, serviceThreadines
 
 	go func() {
 		log.Infof("Starting Metric Server at %s", c.metricsServer.Addr)
-		if err := c.metricsServer.ListenAndServe(); err != nil {
+		if err := listenAndServe(c.metricsServer.Server); err != nil {
 			log.Error(fmt.Errorf("Metric Server Error: %w", err))
 		}
 	}()
 
 	if !electOpts.LeaderElect {
 		log.Info("Leader election is turned off. Running in single-instance mode")
-		go c.startLeading(ctx, rolloutThreadiness, serviceThreadiness, ingressThreadiness, experimentThreadiness, analysisThreadiness)
+		// the controllers finish their in-flight reconciliations on shutdown
+		leaderCtx, cancel := context.WithCancelCause(context.Background())
+		context.AfterFunc(ctx, func() { cancel(controllerutil.ErrShutdown) })
+		go c.startLeading(leaderCtx, rolloutThreadiness, serviceThreadiness, ingressThreadiness, experimentThreadiness, analysisThreadiness)
 		<-ctx.Done()
 	} else {
 		// id used to distinguish between multiple controller manager instances
@@ -525,22 +752,52 @@ func (c *Manager) Run(ctx context.Context, rolloutThreadiness, serviceThreadines
 		id = id + "_" + string(uuid.NewUUID())
 		log.Infof("Leaderelection get id %s", id)
 
-		lockName := leaseLockName(c.instanceID)
+		lockName := shardLeaseLockName(leaseLockName(c.instanceID), c.sharder)
 		log.Infof("Using leader election lease lock name %s", lockName)
-		leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
+
+		// The leader election runs with its own context, which is cancelled once the shutdown signal was received.
+		// With a graceful hand-off, it is only cancelled once the controllers stopped and finished their in-flight
+		// reconciliations, so that the lease can be released right away without two leaders reconciling at once.
+		electionCtx, cancelElection := context.WithCancel(context.Background())
+		defer cancelElection()
+		var leading atomic.Bool
+		go func() {
+			<-ctx.Done()
+			if electOpts.LeaderElectionGracefulHandOff && leading.Load() {
+				log.Info("Waiting for the controllers to stop before releasing the lease")
+				c.shutDownWorkqueues()
+				c.wg.Wait()
+				if !c.onlyAnalysisMode {
+					handOffCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+					if err := c.rolloutController.HandOff(handOffCtx); err != nil {
+						log.Warnf("Failed to hand off the warm start state: %v", err)
+					}
+					cancel()
+				}
+			}
+			cancelElection()
+		}()
+		leaderelection.RunOrDie(electionCtx, leaderelection.LeaderElectionConfig{
 			Lock: &resourcelock.LeaseLock{
 				LeaseMeta: metav1.ObjectMeta{Name: lockName, Namespace: electOpts.LeaderElectionNamespace}, Client: c.kubeClientSet.CoordinationV1(),
 				LockConfig: resourcelock.ResourceLockConfig{Identity: id},
 			},
-			ReleaseOnCancel: false, // We can not set this to true because our context is sent on sig which means our code
-			// is still running prior to calling cancel. We would need to shut down and then call cancel in order to set this to true.
-			LeaseDuration: electOpts.LeaderElectionLeaseDuration,
-			RenewDeadline: electOpts.LeaderElectionRenewDeadline,
-			RetryPeriod:   electOpts.LeaderElectionRetryPeriod,
+			ReleaseOnCancel: electOpts.LeaderElectionGracefulHandOff,
+			LeaseDuration:   electOpts.LeaderElectionLeaseDuration,
+			RenewDeadline:   electOpts.LeaderElectionRenewDeadline,
+			RetryPeriod:     electOpts.LeaderElectionRetryPeriod,
 			Callbacks: leaderelection.LeaderCallbacks{
-				OnStartedLeading: func(ctx context.Context) {
+				OnStartedLeading: func(leaderCtx context.Context) {
+					if ctx.Err() != nil {
+						return
+					}
 					log.Infof("I am the new leader: %s", id)
-					c.startLeading(ctx, rolloutThreadiness, serviceThreadiness, ingressThreadiness, experimentThreadiness, analysisThreadiness)
+					leading.Store(true)
+					// the controllers stop once the shutdown signal was received or the leadership was lost. Only on
+					// shutdown, the controllers finish their in-flight reconciliations.
+					leaderCtx, cancel := context.WithCancelCause(leaderCtx)
+					context.AfterFunc(ctx, func() { cancel(controllerutil.ErrShutdown) })
+					c.startLeading(leaderCtx, rolloutThreadiness, serviceThreadiness, ingressThreadiness, experimentThreadiness, analysisThreadiness)
 				},
 				OnStoppedLeading: func() {
 					log.Infof("OnStoppedLeading called, shutting down: %s, context err: %s", id, ctx.Err())
@@ -553,15 +810,7 @@ func (c *Manager) Run(ctx context.Context, rolloutThreadiness, serviceThreadines
 	}
 	log.Info("Shutting down workers")
 	goPlugin.CleanupClients()
-
-	if !c.onlyAnalysisMode {
-		c.serviceWorkqueue.ShutDownWithDrain()
-		c.ingressWorkqueue.ShutDownWithDrain()
-		c.rolloutWorkqueue.ShutDownWithDrain()
-		c.experimentWorkqueue.ShutDownWithDrain()
-	}
-
-	c.analysisRunWorkqueue.ShutDownWithDrain()
+	c.shutDownWorkqueues()
 
 	ctxWithTimeout, cancel := context.WithTimeout(ctx, 5*time.Second) // give max of 10 seconds for http servers to shut down
 	defer cancel()
@@ -573,11 +822,45 @@ func (c *Manager) Run(ctx context.Context, rolloutThreadiness, serviceThreadines
 	return nil
 }
 
+// SetTLSConfig serves the healthz and metrics servers over HTTPS with the TLS configuration, e.g. to serve the
+// certificates of a certs.Rotator. It must be called before Run.
+func (c *Manager) SetTLSConfig(tlsConfig *tls.Config) {
+	c.healthzServer.TLSConfig = tlsConfig
+	c.metricsServer.TLSConfig = tlsConfig
+}
+
+// listenAndServe serves the server over HTTPS when it has a TLS configuration, and over HTTP otherwise
+func listenAndServe(server *http.Server) error {
+	if server.TLSConfig != nil {
+		return server.ListenAndServeTLS("", "")
+	}
+	return server.ListenAndServe()
+}
+
+// shutDownWorkqueues shuts down the workqueues, waiting for the workers to finish processing their current items
+func (c *Manager) shutDownWorkqueues() {
+	if !c.onlyAnalysisMode {
+		c.serviceWorkqueue.ShutDownWithDrain()
+		c.ingressWorkqueue.ShutDownWithDrain()
+		c.rolloutWorkqueue.ShutDownWithDrain()
+		c.experimentWorkqueue.ShutDownWithDrain()
+	}
+
+	c.analysisRunWorkqueue.ShutDownWithDrain()
+}
+
 func (c *Manager) startLeading(ctx context.Context, rolloutThreadiness, serviceThreadiness, ingressThreadiness, experimentThreadiness, analysisThreadiness int) {
 	defer runtime.HandleCrash()
 	// Start the informer factories to begin populating the informer caches
 	log.Info("Starting Controllers")
 
+	if !c.onlyAnalysisMode {
+		// the warm start state must be restored before the rollouts are added to the informer cache
+		if err := c.rolloutController.RestoreWarmStart(ctx); err != nil {
+			log.Warnf("Failed to restore warm start state: %v", err)
+		}
+	}
+
 	// notice that there is no need to run Start methods in a separate goroutine. (i.e. go kubeInformerFactory.Start(stopCh)
 	// Start method is non-blocking and runs all registered informers in a dedicated goroutine.
 	c.dynamicInformerFactory.Start(ctx.Done())
@@ -606,6 +889,7 @@ func (c *Manager) startLeading(ctx context.Context, rolloutThreadiness, serviceT
 		}()
 	} else {
 
+		c.rolloutPodsInformerFactory.Start(ctx.Done())
 		c.notificationConfigMapInformerFactory.Start(ctx.Done())
 		c.notificationSecretInformerFactory.Start(ctx.Done())
 		if ok := cache.WaitForCacheSync(ctx.Done(), c.configMapSynced, c.secretSynced); !ok {
@@ -616,10 +900,16 @@ func (c *Manager) startLeading(ctx context.Context, rolloutThreadiness, serviceT
 		if istioutil.DoesIstioExist(c.istioPrimaryDynamicClient, c.namespace) {
 			c.istioDynamicInformerFactory.Start(ctx.Done())
 		}
+		// Check if KEDA installed on cluster before starting the informer of the ScaledObjects
+		scaledObjectSynced := cache.InformerSynced(func() bool { return true })
+		if kedautil.DoesKEDAExist(c.kedaDynamicClient, c.namespace) {
+			c.kedaDynamicInformerFactory.Start(ctx.Done())
+			scaledObjectSynced = c.scaledObjectSynced
+		}
 
 		// Wait for the caches to be synced before starting workers
 		log.Info("Waiting for controller's informer caches to sync")
-		if ok := cache.WaitForCacheSync(ctx.Done(), c.serviceSynced, c.ingressSynced, c.jobSynced, c.jobPodsSynced, c.rolloutSynced, c.experimentSynced, c.analysisRunSynced, c.analysisTemplateSynced, c.replicasSetSynced, c.configMapSynced, c.secretSynced); !ok {
+		if ok := cache.WaitForCacheSync(ctx.Done(), c.serviceSynced, c.ingressSynced, c.jobSynced, c.jobPodsSynced, c.rolloutPodsSynced, c.rolloutSynced, c.experimentSynced, c.analysisRunSynced, c.analysisTemplateSynced, c.replicasSetSynced, c.configMapSynced, c.secretSynced, c.notificationPolicySynced, c.controllerConfigSynced, c.podDisruptionBudgetSynced, c.rolloutJobSynced, scaledObjectSynced); !ok {
 			log.Fatalf("failed to wait for caches to sync, exiting")
 		}
 		// only wait for cluster scoped informers to sync if we are running in cluster-wide mode
@@ -663,3 +953,23 @@ func (c *Manager) startLeading(ctx context.Context, rolloutThreadiness, serviceT
 	}
 	log.Info("Started controller")
 }
+
+// optionalInformerSynced returns the sync of the informer of a resource whose CRD may not be installed, e.g. after an
+// upgrade of the controller alone. The caches do not wait for the informer when the API server does not serve the
+// resource, and the informer keeps retrying to list it, so that the resource is watched once its CRD is installed.
+func optionalInformerSynced(discoveryClient discovery.DiscoveryInterface, gvr schema.GroupVersionResource, informer cache.SharedIndexInformer) cache.InformerSynced {
+	resources, err := discoveryClient.ServerResourcesForGroupVersion(gvr.GroupVersion().String())
+	if err != nil && !k8serrors.IsNotFound(err) {
+		log.Warnf("Failed to discover %s, waiting for its cache to sync: %v", gvr.Resource, err)
+		return informer.HasSynced
+	}
+	if resources != nil {
+		for _, resource := range resources.APIResources {
+			if resource.Name == gvr.Resource {
+				return informer.HasSynced
+			}
+		}
+	}
+	log.Warnf("%s are not served by the API server, install the CRDs of this version of the controller to use them", gvr.Resource)
+	return func() bool { return true }
+}
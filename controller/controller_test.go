@@ -2,7 +2,11 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"sync"
 	"testing"
 	"time"
@@ -11,6 +15,7 @@ import (
 	notificationcontroller "github.com/argoproj/notifications-engine/pkg/controller"
 	smifake "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/split/clientset/versioned/fake"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -20,6 +25,7 @@ import (
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	kubeinformers "k8s.io/client-go/informers"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 
 	"k8s.io/client-go/util/workqueue"
 
@@ -34,8 +40,11 @@ import (
 	"github.com/argoproj/argo-rollouts/service"
 	ingressutil "github.com/argoproj/argo-rollouts/utils/ingress"
 	istioutil "github.com/argoproj/argo-rollouts/utils/istio"
+	kedautil "github.com/argoproj/argo-rollouts/utils/keda"
+	logutil "github.com/argoproj/argo-rollouts/utils/log"
 	"github.com/argoproj/argo-rollouts/utils/queue"
 	"github.com/argoproj/argo-rollouts/utils/record"
+	"github.com/argoproj/argo-rollouts/utils/sharding"
 )
 
 var (
@@ -74,13 +83,18 @@ func (f *fixture) newManager(t *testing.T) *Manager {
 		analysisRunSynced:                    alwaysReady,
 		analysisTemplateSynced:               alwaysReady,
 		clusterAnalysisTemplateSynced:        alwaysReady,
+		notificationPolicySynced:             alwaysReady,
+		controllerConfigSynced:               alwaysReady,
 		serviceSynced:                        alwaysReady,
 		ingressSynced:                        alwaysReady,
 		jobSynced:                            alwaysReady,
 		jobPodsSynced:                        alwaysReady,
+		rolloutPodsSynced:                    alwaysReady,
 		replicasSetSynced:                    alwaysReady,
 		configMapSynced:                      alwaysReady,
 		secretSynced:                         alwaysReady,
+		podDisruptionBudgetSynced:            alwaysReady,
+		rolloutJobSynced:                     alwaysReady,
 		rolloutWorkqueue:                     rolloutWorkqueue,
 		serviceWorkqueue:                     serviceWorkqueue,
 		ingressWorkqueue:                     ingressWorkqueue,
@@ -110,22 +124,28 @@ func (f *fixture) newManager(t *testing.T) *Manager {
 	destGVR := istioutil.GetIstioDestinationRuleGVR()
 	scheme := runtime.NewScheme()
 	listMapping := map[schema.GroupVersionResource]string{
-		tgbGVR:  "TargetGroupBindingList",
-		vsvcGVR: vsvcGVR.Resource + "List",
-		destGVR: destGVR.Resource + "List",
+		tgbGVR:                        "TargetGroupBindingList",
+		vsvcGVR:                       vsvcGVR.Resource + "List",
+		destGVR:                       destGVR.Resource + "List",
+		kedautil.GetScaledObjectGVR(): "ScaledObjectList",
 	}
 
 	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping)
 	dynamicInformerFactory := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 0)
 	istioVirtualServiceInformer := dynamicInformerFactory.ForResource(istioutil.GetIstioVirtualServiceGVR()).Informer()
 	istioDestinationRuleInformer := dynamicInformerFactory.ForResource(istioutil.GetIstioDestinationRuleGVR()).Informer()
+	scaledObjectInformer := dynamicInformerFactory.ForResource(kedautil.GetScaledObjectGVR()).Informer()
 
 	cm.dynamicInformerFactory = dynamicInformerFactory
 	cm.clusterDynamicInformerFactory = dynamicInformerFactory
 	cm.kubeInformerFactory = k8sI
 	cm.jobInformerFactory = k8sI
+	cm.rolloutPodsInformerFactory = k8sI
 	cm.istioPrimaryDynamicClient = dynamicClient
 	cm.istioDynamicInformerFactory = dynamicInformerFactory
+	cm.kedaDynamicInformerFactory = dynamicInformerFactory
+	cm.kedaDynamicClient = dynamicClient
+	cm.scaledObjectSynced = scaledObjectInformer.HasSynced
 
 	mode, err := ingressutil.DetermineIngressMode("extensions/v1beta1", &discoveryfake.FakeDiscovery{})
 	assert.NoError(t, err)
@@ -141,13 +161,18 @@ func (f *fixture) newManager(t *testing.T) *Manager {
 		AnalysisRunInformer:             i.Argoproj().V1alpha1().AnalysisRuns(),
 		AnalysisTemplateInformer:        i.Argoproj().V1alpha1().AnalysisTemplates(),
 		ClusterAnalysisTemplateInformer: i.Argoproj().V1alpha1().ClusterAnalysisTemplates(),
+		ControllerConfigInformer:        i.Argoproj().V1alpha1().RolloutControllerConfigs(),
 		ReplicaSetInformer:              k8sI.Apps().V1().ReplicaSets(),
 		ServicesInformer:                k8sI.Core().V1().Services(),
+		PodInformer:                     k8sI.Core().V1().Pods(),
+		PodDisruptionBudgetInformer:     k8sI.Policy().V1().PodDisruptionBudgets(),
+		JobInformer:                     k8sI.Batch().V1().Jobs(),
 		IngressWrapper:                  ingressWrapper,
 		RolloutsInformer:                i.Argoproj().V1alpha1().Rollouts(),
 		IstioPrimaryDynamicClient:       dynamicClient,
 		IstioVirtualServiceInformer:     istioVirtualServiceInformer,
 		IstioDestinationRuleInformer:    istioDestinationRuleInformer,
+		ScaledObjectInformer:            scaledObjectInformer,
 		ResyncPeriod:                    noResyncPeriodFunc(),
 		RolloutWorkQueue:                rolloutWorkqueue,
 		ServiceWorkQueue:                serviceWorkqueue,
@@ -243,17 +268,20 @@ func TestNewManager(t *testing.T) {
 		f.client,
 		dynamicClient,
 		smifake.NewSimpleClientset(),
-		&discoveryfake.FakeDiscovery{},
+		&discoveryfake.FakeDiscovery{Fake: &k8stesting.Fake{}},
 		k8sI.Apps().V1().ReplicaSets(),
 		k8sI.Core().V1().Services(),
 		ingressWrapper,
 		k8sI.Batch().V1().Jobs(),
 		k8sI.Core().V1().Pods(),
+		k8sI.Core().V1().Pods(),
 		i.Argoproj().V1alpha1().Rollouts(),
 		i.Argoproj().V1alpha1().Experiments(),
 		i.Argoproj().V1alpha1().AnalysisRuns(),
 		i.Argoproj().V1alpha1().AnalysisTemplates(),
 		i.Argoproj().V1alpha1().ClusterAnalysisTemplates(),
+		i.Argoproj().V1alpha1().RolloutNotificationPolicies(),
+		i.Argoproj().V1alpha1().RolloutControllerConfigs(),
 		dynamicClient,
 		istioVirtualServiceInformer,
 		istioDestinationRuleInformer,
@@ -272,14 +300,43 @@ func TestNewManager(t *testing.T) {
 		false,
 		nil,
 		nil,
+		k8sI,
 		rolloutController.DefaultEphemeralMetadataThreads,
 		rolloutController.DefaultEphemeralMetadataPodRetries,
+		nil,
+		queue.DefaultRateLimiterConfig(),
+		ResyncPeriods{},
+		WorkerScaling{},
+		WarmStart{},
+		GarbageCollection{},
+		ProgressionLimits{},
+		ReconcileCache{},
+		nil,
 	)
 
 	assert.NotNil(t, cm)
 	assert.Equal(t, "test", cm.instanceID)
 }
 
+func TestOptionalInformerSynced(t *testing.T) {
+	f := newFixture(t)
+	i := informers.NewSharedInformerFactory(f.client, noResyncPeriodFunc())
+	informer := i.Argoproj().V1alpha1().RolloutNotificationPolicies().Informer()
+
+	// the informer is not synced since it is not started, so the caches only wait for it once its CRD is installed
+	discoveryClient := &discoveryfake.FakeDiscovery{Fake: &k8stesting.Fake{}}
+	assert.True(t, optionalInformerSynced(discoveryClient, v1alpha1.RolloutNotificationPolicyGVR, informer)())
+
+	discoveryClient.Resources = []*metav1.APIResourceList{{
+		GroupVersion: v1alpha1.SchemeGroupVersion.String(),
+		APIResources: []metav1.APIResource{{Name: "rollouts"}},
+	}}
+	assert.True(t, optionalInformerSynced(discoveryClient, v1alpha1.RolloutNotificationPolicyGVR, informer)())
+
+	discoveryClient.Resources[0].APIResources = append(discoveryClient.Resources[0].APIResources, metav1.APIResource{Name: "rolloutnotificationpolicies"})
+	assert.False(t, optionalInformerSynced(discoveryClient, v1alpha1.RolloutNotificationPolicyGVR, informer)())
+}
+
 func TestNewAnalysisManager(t *testing.T) {
 	f := newFixture(t)
 
@@ -310,6 +367,11 @@ func TestNewAnalysisManager(t *testing.T) {
 		false,
 		nil,
 		nil,
+		nil,
+		queue.DefaultRateLimiterConfig(),
+		ResyncPeriods{},
+		WorkerScaling{},
+		nil,
 	)
 
 	assert.NotNil(t, cm)
@@ -328,6 +390,23 @@ func TestPrimaryController(t *testing.T) {
 	cm.Run(ctx, 1, 1, 1, 1, 1, electOpts)
 }
 
+func TestPrimaryControllerReleasesLeaseOnShutdown(t *testing.T) {
+	f := newFixture(t)
+
+	cm := f.newManager(t)
+	electOpts := NewLeaderElectionOptions()
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(5 * time.Second)
+		cancel()
+	}()
+	cm.Run(ctx, 1, 1, 1, 1, 1, electOpts)
+
+	lease, err := f.kubeclient.CoordinationV1().Leases(electOpts.LeaderElectionNamespace).Get(context.Background(), defaultLeaderElectionLeaseLockName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, *lease.Spec.HolderIdentity)
+}
+
 func TestPrimaryControllerSingleInstanceWithShutdown(t *testing.T) {
 	f := newFixture(t)
 
@@ -365,3 +444,72 @@ func TestLeaseLockName(t *testing.T) {
 		})
 	}
 }
+
+func TestShardLeaseLockName(t *testing.T) {
+	assert.Equal(t, defaultLeaderElectionLeaseLockName, shardLeaseLockName(defaultLeaderElectionLeaseLockName, nil))
+
+	sharder, err := sharding.NewSharder(3, 2)
+	require.NoError(t, err)
+	assert.Equal(t, defaultLeaderElectionLeaseLockName+"-my-instance-shard-2", shardLeaseLockName(leaseLockName("my-instance"), sharder))
+}
+
+func TestDumpHandler(t *testing.T) {
+	f := newFixture(t)
+	cm := f.newManager(t)
+	cm.rolloutWorkqueue.Add("default/foo")
+	cm.metricsServer.SetLastError("default", "foo", logutil.RolloutKey, errors.New("intentional error"))
+
+	rr := httptest.NewRecorder()
+	cm.DumpHandler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, DumpPath, nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var dump DiagnosticsDump
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &dump))
+	assert.Equal(t, 1, dump.QueueDepths["Rollouts"])
+	assert.Equal(t, 0, dump.QueueDepths["Experiments"])
+	if assert.Len(t, dump.LastErrors, 1) {
+		assert.Equal(t, "foo", dump.LastErrors[0].Name)
+		assert.Equal(t, "intentional error", dump.LastErrors[0].Error)
+	}
+}
+
+func TestPProfHandler(t *testing.T) {
+	f := newFixture(t)
+	cm := f.newManager(t)
+	serve := func(handler http.Handler, path, authorization string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	handler := cm.PProfHandler("")
+	assert.Equal(t, http.StatusOK, serve(handler, "/debug/vars", ""))
+	assert.Equal(t, http.StatusNotFound, serve(handler, DumpPath, ""))
+
+	handler = cm.PProfHandler("secret")
+	assert.Equal(t, http.StatusUnauthorized, serve(handler, "/debug/vars", ""))
+	assert.Equal(t, http.StatusUnauthorized, serve(handler, DumpPath, ""))
+	assert.Equal(t, http.StatusOK, serve(handler, DumpPath, "Bearer secret"))
+}
+
+func TestWithBearerToken(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	serve := func(handler http.Handler, authorization string) int {
+		req := httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve(WithBearerToken("", handler), ""))
+	assert.Equal(t, http.StatusUnauthorized, serve(WithBearerToken("secret", handler), ""))
+	assert.Equal(t, http.StatusUnauthorized, serve(WithBearerToken("secret", handler), "Bearer wrong"))
+	assert.Equal(t, http.StatusOK, serve(WithBearerToken("secret", handler), "Bearer secret"))
+}
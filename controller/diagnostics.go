@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/argoproj/argo-rollouts/controller/metrics"
+)
+
+const (
+	// DumpPath is the endpoint of the pprof server which dumps the in-memory state of the controller
+	DumpPath = "/debug/dump"
+)
+
+// DiagnosticsDump is the in-memory state of the controller, which is dumped to debug stalled controllers
+type DiagnosticsDump struct {
+	// Time is the time the dump was taken
+	Time time.Time `json:"time"`
+	// QueueDepths are the number of items waiting in the workqueues
+	QueueDepths map[string]int `json:"queueDepths"`
+	// InformerCacheSizes are the number of objects in the informer caches
+	InformerCacheSizes map[string]int `json:"informerCacheSizes"`
+	// LastErrors are the last reconciliation errors of the objects
+	LastErrors []metrics.LastError `json:"lastErrors"`
+}
+
+// Dump returns the in-memory state of the controller
+func (c *Manager) Dump() DiagnosticsDump {
+	dump := DiagnosticsDump{
+		Time:               time.Now(),
+		QueueDepths:        map[string]int{},
+		InformerCacheSizes: map[string]int{},
+		LastErrors:         c.metricsServer.LastErrors(),
+	}
+	for name, queue := range map[string]workqueue.RateLimitingInterface{
+		"Rollouts":     c.rolloutWorkqueue,
+		"Services":     c.serviceWorkqueue,
+		"Ingresses":    c.ingressWorkqueue,
+		"Experiments":  c.experimentWorkqueue,
+		"AnalysisRuns": c.analysisRunWorkqueue,
+	} {
+		if queue != nil {
+			dump.QueueDepths[name] = queue.Len()
+		}
+	}
+	for name, store := range c.informerStores {
+		dump.InformerCacheSizes[name] = len(store.ListKeys())
+	}
+	return dump
+}
+
+// PProfHandler returns the handler of the pprof server. The dump of the in-memory state is only served when a
+// token is given, in which case every request to the server must carry the token. Without a token, only the
+// pprof profiles and expvar variables are served.
+func (c *Manager) PProfHandler(token string) http.Handler {
+	mux := NewPProfServer()
+	if token == "" {
+		return mux
+	}
+	mux.Handle(DumpPath, c.DumpHandler())
+	return WithBearerToken(token, mux)
+}
+
+// DumpHandler returns a handler which responds with the in-memory state of the controller
+func (c *Manager) DumpHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(c.Dump()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -3,6 +3,8 @@ package metrics
 import (
 	"net/http"
 	"runtime"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/argoproj/argo-rollouts/utils/defaults"
@@ -22,8 +24,10 @@ import (
 
 type MetricsServer struct {
 	*http.Server
-	reconcileRolloutHistogram *prometheus.HistogramVec
-	errorRolloutCounter       *prometheus.CounterVec
+	reconcileRolloutHistogram      *prometheus.HistogramVec
+	reconcileRolloutPhaseHistogram *prometheus.HistogramVec
+	rolloutStepDurationHistogram   *prometheus.HistogramVec
+	errorRolloutCounter            *prometheus.CounterVec
 
 	reconcileExperimentHistogram *prometheus.HistogramVec
 	errorExperimentCounter       *prometheus.CounterVec
@@ -34,6 +38,31 @@ type MetricsServer struct {
 	errorNotificationCounter      *prometheus.CounterVec
 	sendNotificationRunHistogram  *prometheus.HistogramVec
 	k8sRequestsCounter            *K8sRequestsCountProvider
+	workersGauge                  *prometheus.GaugeVec
+
+	// rolloutStepStarts remembers when the current canary step of each rollout started
+	rolloutStepStarts     map[string]rolloutStepStart
+	rolloutStepStartsLock sync.Mutex
+
+	// lastErrors remembers the last reconciliation error of each object, for diagnostics
+	lastErrors     map[string]LastError
+	lastErrorsLock sync.Mutex
+}
+
+// LastError is the last error which occurred during the reconciliation of an object
+type LastError struct {
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Error     string    `json:"error"`
+	Time      time.Time `json:"time"`
+}
+
+// rolloutStepStart is the start of a canary step of a revision of a rollout
+type rolloutStepStart struct {
+	podHash   string
+	stepIndex int32
+	startTime time.Time
 }
 
 const (
@@ -41,6 +70,16 @@ const (
 	MetricsPath = "/metrics"
 )
 
+// Phases of the rollout reconciliation
+const (
+	RolloutPhaseReplicaSets    = "ReplicaSets"
+	RolloutPhaseServices       = "Services"
+	RolloutPhaseTrafficRouting = "TrafficRouting"
+	RolloutPhaseExperiments    = "Experiments"
+	RolloutPhaseAnalysisRuns   = "AnalysisRuns"
+	RolloutPhaseStatusPatch    = "StatusPatch"
+)
+
 var (
 	buildInfo = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -76,6 +115,8 @@ func NewMetricsServer(cfg ServerConfig) *MetricsServer {
 	reg.MustRegister(NewAnalysisRunCollector(cfg.AnalysisRunLister, cfg.AnalysisTemplateLister, cfg.ClusterAnalysisTemplateLister))
 	cfg.K8SRequestProvider.MustRegister(reg)
 	reg.MustRegister(MetricRolloutReconcile)
+	reg.MustRegister(MetricRolloutReconcilePhase)
+	reg.MustRegister(MetricRolloutStepDuration)
 	reg.MustRegister(MetricRolloutReconcileError)
 	reg.MustRegister(MetricRolloutEventsTotal)
 	reg.MustRegister(MetricExperimentReconcile)
@@ -85,6 +126,7 @@ func NewMetricsServer(cfg ServerConfig) *MetricsServer {
 	reg.MustRegister(MetricNotificationSuccessTotal)
 	reg.MustRegister(MetricNotificationFailedTotal)
 	reg.MustRegister(MetricNotificationSend)
+	reg.MustRegister(MetricControllerWorkers)
 	reg.MustRegister(MetricVersionGauge)
 	reg.MustRegister(buildInfo)
 
@@ -101,8 +143,10 @@ func NewMetricsServer(cfg ServerConfig) *MetricsServer {
 			Addr:    cfg.Addr,
 			Handler: mux,
 		},
-		reconcileRolloutHistogram: MetricRolloutReconcile,
-		errorRolloutCounter:       MetricRolloutReconcileError,
+		reconcileRolloutHistogram:      MetricRolloutReconcile,
+		reconcileRolloutPhaseHistogram: MetricRolloutReconcilePhase,
+		rolloutStepDurationHistogram:   MetricRolloutStepDuration,
+		errorRolloutCounter:            MetricRolloutReconcileError,
 
 		reconcileExperimentHistogram: MetricExperimentReconcile,
 		errorExperimentCounter:       MetricExperimentReconcileError,
@@ -114,6 +158,9 @@ func NewMetricsServer(cfg ServerConfig) *MetricsServer {
 		sendNotificationRunHistogram:  MetricNotificationSend,
 
 		k8sRequestsCounter: cfg.K8SRequestProvider,
+		workersGauge:       MetricControllerWorkers,
+		rolloutStepStarts:  map[string]rolloutStepStart{},
+		lastErrors:         map[string]LastError{},
 	}
 }
 
@@ -122,6 +169,41 @@ func (m *MetricsServer) IncRolloutReconcile(rollout *v1alpha1.Rollout, duration
 	m.reconcileRolloutHistogram.WithLabelValues(rollout.Namespace, rollout.Name).Observe(duration.Seconds())
 }
 
+// ObserveRolloutReconcilePhase records the duration of a phase of the reconciliation of a Rollout
+func (m *MetricsServer) ObserveRolloutReconcilePhase(rollout *v1alpha1.Rollout, phase string, duration time.Duration) {
+	m.reconcileRolloutPhaseHistogram.WithLabelValues(rollout.Namespace, rollout.Name, phase).Observe(duration.Seconds())
+}
+
+// ObserveRolloutStep records the duration of the canary step the Rollout completed, if the status of the Rollout
+// moves on to a later step of the same revision. The start of a step is only known to the controller if it
+// observed the Rollout entering the step, so the first step completed after a restart of the controller is not
+// recorded.
+func (m *MetricsServer) ObserveRolloutStep(rollout *v1alpha1.Rollout, newStatus *v1alpha1.RolloutStatus, now time.Time) {
+	if newStatus.CurrentStepIndex == nil {
+		return
+	}
+	key := rollout.Namespace + "/" + rollout.Name
+	m.rolloutStepStartsLock.Lock()
+	defer m.rolloutStepStartsLock.Unlock()
+	prev, ok := m.rolloutStepStarts[key]
+	if ok && prev.podHash == newStatus.CurrentPodHash && prev.stepIndex == *newStatus.CurrentStepIndex {
+		return
+	}
+	if ok && prev.podHash == newStatus.CurrentPodHash && prev.stepIndex < *newStatus.CurrentStepIndex {
+		m.rolloutStepDurationHistogram.WithLabelValues(rollout.Namespace, rollout.Name).Observe(now.Sub(prev.startTime).Seconds())
+	}
+	prevStatus := rollout.Status
+	if !ok && (prevStatus.CurrentPodHash == newStatus.CurrentPodHash && prevStatus.CurrentStepIndex != nil && *prevStatus.CurrentStepIndex == *newStatus.CurrentStepIndex) {
+		// the step started before the controller observed the rollout
+		return
+	}
+	m.rolloutStepStarts[key] = rolloutStepStart{
+		podHash:   newStatus.CurrentPodHash,
+		stepIndex: *newStatus.CurrentStepIndex,
+		startTime: now,
+	}
+}
+
 // IncExperimentReconcile increments the reconcile counter for an Experiment
 func (m *MetricsServer) IncExperimentReconcile(ex *v1alpha1.Experiment, duration time.Duration) {
 	m.reconcileExperimentHistogram.WithLabelValues(ex.Namespace, ex.Name).Observe(duration.Seconds())
@@ -132,6 +214,11 @@ func (m *MetricsServer) IncAnalysisRunReconcile(ar *v1alpha1.AnalysisRun, durati
 	m.reconcileAnalysisRunHistogram.WithLabelValues(ar.Namespace, ar.Name).Observe(duration.Seconds())
 }
 
+// SetWorkers sets the number of workers processing the workqueue of the controller of the given kind
+func (m *MetricsServer) SetWorkers(kind string, workers int) {
+	m.workersGauge.WithLabelValues(kind).Set(float64(workers))
+}
+
 // IncError increments the reconcile counter for an rollout
 func (m *MetricsServer) IncError(namespace, name string, kind string) {
 	switch kind {
@@ -144,14 +231,60 @@ func (m *MetricsServer) IncError(namespace, name string, kind string) {
 	}
 }
 
+// SetLastError remembers the last reconciliation error of an object
+func (m *MetricsServer) SetLastError(namespace, name string, kind string, err error) {
+	m.lastErrorsLock.Lock()
+	defer m.lastErrorsLock.Unlock()
+	m.lastErrors[kind+"/"+namespace+"/"+name] = LastError{
+		Kind:      kind,
+		Namespace: namespace,
+		Name:      name,
+		Error:     err.Error(),
+		Time:      time.Now(),
+	}
+}
+
+// ClearLastError forgets the last reconciliation error of an object, after it was reconciled successfully or
+// deleted. It is a no-op on a nil MetricsServer, which some workers run with.
+func (m *MetricsServer) ClearLastError(namespace, name string, kind string) {
+	if m == nil {
+		return
+	}
+	m.lastErrorsLock.Lock()
+	defer m.lastErrorsLock.Unlock()
+	delete(m.lastErrors, kind+"/"+namespace+"/"+name)
+}
+
+// LastErrors returns the last reconciliation errors of the objects, ordered by kind, namespace and name
+func (m *MetricsServer) LastErrors() []LastError {
+	m.lastErrorsLock.Lock()
+	defer m.lastErrorsLock.Unlock()
+	keys := make([]string, 0, len(m.lastErrors))
+	for key := range m.lastErrors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	lastErrors := make([]LastError, 0, len(keys))
+	for _, key := range keys {
+		lastErrors = append(lastErrors, m.lastErrors[key])
+	}
+	return lastErrors
+}
+
 // Remove removes the metrics server from the registry
 func (m *MetricsServer) Remove(namespace string, name string, kind string) {
+	m.ClearLastError(namespace, name, kind)
 	go func(namespace string, name string, kind string) {
 		// wait for the metrics to be collected, prometheus scrape interval is 60 seconds by default
 		time.Sleep(defaults.GetMetricCleanupDelaySeconds())
 		switch kind {
 		case log.RolloutKey:
 			m.reconcileRolloutHistogram.Delete(map[string]string{"namespace": namespace, "name": name})
+			m.reconcileRolloutPhaseHistogram.DeletePartialMatch(map[string]string{"namespace": namespace, "name": name})
+			m.rolloutStepDurationHistogram.Delete(map[string]string{"namespace": namespace, "name": name})
+			m.rolloutStepStartsLock.Lock()
+			delete(m.rolloutStepStarts, namespace+"/"+name)
+			m.rolloutStepStartsLock.Unlock()
 			m.errorRolloutCounter.Delete(map[string]string{"namespace": namespace, "name": name})
 
 			m.successNotificationCounter.DeletePartialMatch(map[string]string{"namespace": namespace, "name": name})
@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net/http"
 	"net/http/httptest"
@@ -10,11 +11,14 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
 
 	"github.com/argoproj/argo-rollouts/utils/defaults"
 
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	"github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned/fake"
 	informerfactory "github.com/argoproj/argo-rollouts/pkg/client/informers/externalversions"
 	logutil "github.com/argoproj/argo-rollouts/utils/log"
@@ -112,13 +116,59 @@ rollout_reconcile_error{name="name1",namespace="ns"} 1`
 	metricsServ.IncError("ns", "name1", logutil.RolloutKey)
 	metricsServ.IncError("ns", "name1", logutil.AnalysisRunKey)
 	metricsServ.IncError("ns", "name1", logutil.ExperimentKey)
+	metricsServ.SetLastError("ns", "name1", logutil.RolloutKey, errors.New("intentional error"))
 	testHttpResponse(t, metricsServ.Handler, expectedResponse, assert.Contains)
 
 	metricsServ.Remove("ns", "name1", logutil.AnalysisRunKey)
 	metricsServ.Remove("ns", "name1", logutil.ExperimentKey)
 	metricsServ.Remove("ns", "name1", logutil.RolloutKey)
+	assert.Empty(t, metricsServ.LastErrors())
 
 	//Sleep for 2x the cleanup delay to allow metrics to be removed
 	time.Sleep(defaults.GetMetricCleanupDelaySeconds() * 2)
 	testHttpResponse(t, metricsServ.Handler, expectedResponse, assert.NotContains)
 }
+
+func TestObserveRolloutReconcilePhase(t *testing.T) {
+	expectedResponse := `# HELP rollout_reconcile_phase Duration of the phases of the rollout reconciliation.
+# TYPE rollout_reconcile_phase histogram
+rollout_reconcile_phase_count{name="name",namespace="ns",phase="TrafficRouting"} 1`
+
+	metricsServ := NewMetricsServer(newFakeServerConfig())
+	ro := &v1alpha1.Rollout{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "name"}}
+
+	metricsServ.ObserveRolloutReconcilePhase(ro, RolloutPhaseTrafficRouting, 100*time.Millisecond)
+	testHttpResponse(t, metricsServ.Handler, expectedResponse, assert.Contains)
+}
+
+func TestObserveRolloutStep(t *testing.T) {
+	metricsServ := NewMetricsServer(newFakeServerConfig())
+	ro := &v1alpha1.Rollout{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "step"}}
+	ro.Status.CurrentPodHash = "abc"
+	ro.Status.CurrentStepIndex = ptr.To[int32](1)
+	now := time.Now()
+
+	// the start of the current step is unknown
+	newStatus := ro.Status.DeepCopy()
+	metricsServ.ObserveRolloutStep(ro, newStatus, now)
+	newStatus.CurrentStepIndex = ptr.To[int32](2)
+	metricsServ.ObserveRolloutStep(ro, newStatus, now.Add(time.Minute))
+	testHttpResponse(t, metricsServ.Handler, `rollout_step_duration_count{name="step",namespace="ns"}`, assert.NotContains)
+
+	// step 2 was entered after the controller observed the rollout
+	ro.Status = *newStatus.DeepCopy()
+	newStatus.CurrentStepIndex = ptr.To[int32](3)
+	metricsServ.ObserveRolloutStep(ro, newStatus, now.Add(3*time.Minute))
+	testHttpResponse(t, metricsServ.Handler, `rollout_step_duration_sum{name="step",namespace="ns"} 120`, assert.Contains)
+
+	// a new revision starts over with a new step
+	ro.Status = *newStatus.DeepCopy()
+	newStatus.CurrentPodHash = "def"
+	newStatus.CurrentStepIndex = ptr.To[int32](0)
+	metricsServ.ObserveRolloutStep(ro, newStatus, now.Add(4*time.Minute))
+	testHttpResponse(t, metricsServ.Handler, `rollout_step_duration_count{name="step",namespace="ns"} 1`, assert.Contains)
+	ro.Status = *newStatus.DeepCopy()
+	newStatus.CurrentStepIndex = ptr.To[int32](1)
+	metricsServ.ObserveRolloutStep(ro, newStatus, now.Add(5*time.Minute))
+	testHttpResponse(t, metricsServ.Handler, `rollout_step_duration_sum{name="step",namespace="ns"} 180`, assert.Contains)
+}
@@ -23,6 +23,24 @@ var (
 		namespaceNameLabels,
 	)
 
+	MetricRolloutReconcilePhase = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "rollout_reconcile_phase",
+			Help:    "Duration of the phases of the rollout reconciliation.",
+			Buckets: []float64{0.01, 0.15, .25, .5, 1},
+		},
+		append(namespaceNameLabels, "phase"),
+	)
+
+	MetricRolloutStepDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "rollout_step_duration",
+			Help:    "Duration in seconds from the start until the completion of the canary steps of a rollout.",
+			Buckets: []float64{10, 30, 60, 300, 600, 1800, 3600, 7200, 21600},
+		},
+		namespaceNameLabels,
+	)
+
 	MetricRolloutReconcileError = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "rollout_reconcile_error",
@@ -224,6 +242,17 @@ var (
 	)
 )
 
+// Worker metrics
+var (
+	MetricControllerWorkers = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "controller_workers",
+			Help: "Number of workers processing the workqueue of a controller.",
+		},
+		[]string{"kind"},
+	)
+)
+
 // MetricVersionGauge version info
 var (
 	MetricVersionGauge = prometheus.NewGaugeFunc(
@@ -1,6 +1,8 @@
 package controller
 
 import (
+	"crypto/subtle"
+	"expvar"
 	"net/http"
 	"net/http/pprof"
 )
@@ -16,6 +18,23 @@ func NewPProfServer() *http.ServeMux {
 	mux.HandleFunc("/debug/pprof/profile/", pprof.Profile)
 	mux.HandleFunc("/debug/pprof/symbol/", pprof.Symbol)
 	mux.HandleFunc("/debug/pprof/trace/", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
 
 	return mux
 }
+
+// WithBearerToken only passes requests to the handler which carry the token in their Authorization header.
+// Requests are not authenticated when the token is empty.
+func WithBearerToken(token string, handler http.Handler) http.Handler {
+	if token == "" {
+		return handler
+	}
+	expected := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -783,9 +783,10 @@ func TestCreateAnalysisRunWithMetadataAndDryRun(t *testing.T) {
 	assert.Len(t, analysisRun.ObjectMeta.Labels, 2)
 	assert.Equal(t, analysisRun.ObjectMeta.Labels["foo"], "bar")
 	assert.Equal(t, analysisRun.ObjectMeta.Labels["foo2"], "bar2")
-	assert.Len(t, analysisRun.ObjectMeta.Annotations, 2)
+	assert.Len(t, analysisRun.ObjectMeta.Annotations, 3)
 	assert.Equal(t, analysisRun.ObjectMeta.Annotations["bar"], "foo")
 	assert.Equal(t, analysisRun.ObjectMeta.Annotations["bar2"], "foo2")
+	assert.Equal(t, analysisRun.ObjectMeta.Annotations[v1alpha1.AnnotationKeyControllerShardKey], "default/foo")
 
 	assert.Len(t, analysisRun.Spec.DryRun, 2)
 	assert.Equal(t, analysisRun.Spec.DryRun[0].MetricName, "someMetric")
@@ -841,9 +842,10 @@ func TestCreateAnalysisRunWithMetadataAndDryRunWithClusterScope(t *testing.T) {
 	assert.Len(t, analysisRun.ObjectMeta.Labels, 2)
 	assert.Equal(t, analysisRun.ObjectMeta.Labels["foo"], "bar")
 	assert.Equal(t, analysisRun.ObjectMeta.Labels["foo2"], "bar2")
-	assert.Len(t, analysisRun.ObjectMeta.Annotations, 2)
+	assert.Len(t, analysisRun.ObjectMeta.Annotations, 3)
 	assert.Equal(t, analysisRun.ObjectMeta.Annotations["bar"], "foo")
 	assert.Equal(t, analysisRun.ObjectMeta.Annotations["bar2"], "foo2")
+	assert.Equal(t, analysisRun.ObjectMeta.Annotations[v1alpha1.AnnotationKeyControllerShardKey], "default/foo")
 
 	assert.Len(t, analysisRun.Spec.DryRun, 2)
 	assert.Equal(t, analysisRun.Spec.DryRun[0].MetricName, "someMetric")
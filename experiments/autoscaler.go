@@ -0,0 +1,134 @@
+package experiments
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+	"github.com/argoproj/argo-rollouts/utils/record"
+)
+
+// defaultTargetCPUUtilizationPercentage is the target CPU utilization of a template autoscaler without any target
+const defaultTargetCPUUtilizationPercentage = int32(80)
+
+// createTemplateAutoscaler creates the HorizontalPodAutoscaler which scales the ReplicaSet of the template
+func (ec *experimentContext) createTemplateAutoscaler(template v1alpha1.TemplateSpec, templateStatus *v1alpha1.TemplateStatus, rs *appsv1.ReplicaSet) {
+	hpa, err := ec.CreateHorizontalPodAutoscaler(template, rs)
+	if err != nil {
+		templateStatus.Status = v1alpha1.TemplateStatusError
+		templateStatus.Message = fmt.Sprintf("Failed to create HorizontalPodAutoscaler for template '%s': %v", template.Name, err)
+		return
+	}
+	templateStatus.HorizontalPodAutoscalerName = hpa.Name
+	ec.recorder.Eventf(ec.ex, record.EventOptions{EventReason: conditions.HorizontalPodAutoscalerCreatedReason}, conditions.HorizontalPodAutoscalerCreatedMessage, hpa.Name, template.Name)
+}
+
+// deleteTemplateAutoscaler deletes the HorizontalPodAutoscaler of the template, so that the ReplicaSet can be scaled
+// down
+func (ec *experimentContext) deleteTemplateAutoscaler(templateStatus *v1alpha1.TemplateStatus, templateName string) {
+	if templateStatus.HorizontalPodAutoscalerName == "" {
+		return
+	}
+	ctx := context.TODO()
+	name := templateStatus.HorizontalPodAutoscalerName
+	ec.log.Infof("Trying to cleanup HorizontalPodAutoscaler '%s'", name)
+	err := ec.kubeclientset.AutoscalingV2().HorizontalPodAutoscalers(ec.ex.Namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		templateStatus.Status = v1alpha1.TemplateStatusError
+		templateStatus.Message = fmt.Sprintf("Failed to delete HorizontalPodAutoscaler for template '%s': %v", templateName, err)
+		return
+	}
+	templateStatus.HorizontalPodAutoscalerName = ""
+	ec.recorder.Eventf(ec.ex, record.EventOptions{EventReason: conditions.HorizontalPodAutoscalerDeletedReason}, conditions.HorizontalPodAutoscalerDeletedMessage, name, templateName)
+}
+
+// CreateHorizontalPodAutoscaler creates a HorizontalPodAutoscaler with the name of the ReplicaSet of the template,
+// which targets the ReplicaSet
+func (ec *experimentContext) CreateHorizontalPodAutoscaler(template v1alpha1.TemplateSpec, rs *appsv1.ReplicaSet) (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	ctx := context.TODO()
+	newHPA := newHorizontalPodAutoscaler(ec.ex, template, rs)
+	hpa, err := ec.kubeclientset.AutoscalingV2().HorizontalPodAutoscalers(ec.ex.Namespace).Create(ctx, newHPA, metav1.CreateOptions{FieldManager: defaults.DefaultFieldManager})
+	if err != nil {
+		// If the autoscaler already exists, check that it is owned by the experiment template. Otherwise return error.
+		if errors.IsAlreadyExists(err) {
+			hpa, err := ec.kubeclientset.AutoscalingV2().HorizontalPodAutoscalers(ec.ex.Namespace).Get(ctx, newHPA.Name, metav1.GetOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("did not get existing HorizontalPodAutoscaler with name %s: %v", newHPA.Name, err)
+			}
+			controllerRef := metav1.GetControllerOf(hpa)
+			if controllerRef == nil || controllerRef.UID != ec.ex.UID || hpa.Annotations[v1alpha1.ExperimentTemplateNameAnnotationKey] != template.Name {
+				return nil, fmt.Errorf("HorizontalPodAutoscaler %s already exists and is not owned by experiment template %s", newHPA.Name, template.Name)
+			}
+			return hpa, nil
+		}
+		return nil, fmt.Errorf("cannot create HorizontalPodAutoscaler: %v", err)
+	}
+	return hpa, nil
+}
+
+// templateMinReplicas returns the replicas the autoscaler of the template keeps at least, which default to the replicas
+// of the template
+func templateMinReplicas(template v1alpha1.TemplateSpec) int32 {
+	if template.Autoscaling != nil && template.Autoscaling.MinReplicas != nil {
+		return *template.Autoscaling.MinReplicas
+	}
+	return defaults.GetReplicasOrDefault(template.Replicas)
+}
+
+func newHorizontalPodAutoscaler(ex *v1alpha1.Experiment, template v1alpha1.TemplateSpec, rs *appsv1.ReplicaSet) *autoscalingv2.HorizontalPodAutoscaler {
+	autoscaling := template.Autoscaling
+	minReplicas := ptr.To(templateMinReplicas(template))
+	var metrics []autoscalingv2.MetricSpec
+	targetCPU := autoscaling.TargetCPUUtilizationPercentage
+	if targetCPU == nil && autoscaling.TargetMemoryUtilizationPercentage == nil {
+		targetCPU = ptr.To(defaultTargetCPUUtilizationPercentage)
+	}
+	if targetCPU != nil {
+		metrics = append(metrics, newResourceMetricSpec(corev1.ResourceCPU, *targetCPU))
+	}
+	if autoscaling.TargetMemoryUtilizationPercentage != nil {
+		metrics = append(metrics, newResourceMetricSpec(corev1.ResourceMemory, *autoscaling.TargetMemoryUtilizationPercentage))
+	}
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rs.Name,
+			Namespace: ex.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(ex, experimentKind),
+			},
+			Annotations: newServiceAnnotations(ex.Name, template.Name),
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: appsv1.SchemeGroupVersion.String(),
+				Kind:       "ReplicaSet",
+				Name:       rs.Name,
+			},
+			MinReplicas: minReplicas,
+			MaxReplicas: autoscaling.MaxReplicas,
+			Metrics:     metrics,
+		},
+	}
+}
+
+func newResourceMetricSpec(resource corev1.ResourceName, utilization int32) autoscalingv2.MetricSpec {
+	return autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricSource{
+			Name: resource,
+			Target: autoscalingv2.MetricTarget{
+				Type:               autoscalingv2.UtilizationMetricType,
+				AverageUtilization: ptr.To(utilization),
+			},
+		},
+	}
+}
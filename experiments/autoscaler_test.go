@@ -0,0 +1,184 @@
+package experiments
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	"github.com/argoproj/argo-rollouts/utils/record"
+)
+
+func newAutoscaledExperiment() *v1alpha1.Experiment {
+	templates := generateTemplates("bar")
+	templates[0].Autoscaling = &v1alpha1.TemplateAutoscaling{MaxReplicas: 5}
+	return newExperiment("foo", templates, "")
+}
+
+// TestCreateTemplateAutoscaler verifies the autoscaler of a template is created once its ReplicaSet is scaled up
+func TestCreateTemplateAutoscaler(t *testing.T) {
+	ex := newAutoscaledExperiment()
+	ex.Status.TemplateStatuses = []v1alpha1.TemplateStatus{
+		generateTemplatesStatus("bar", 1, 1, v1alpha1.TemplateStatusRunning, now()),
+	}
+	rs := templateToRS(ex, ex.Spec.Templates[0], 1)
+	exCtx := newTestContext(ex, rs)
+	exCtx.templateRSs = map[string]*appsv1.ReplicaSet{
+		"bar": rs,
+	}
+	newStatus := exCtx.reconcile()
+	assert.Equal(t, v1alpha1.AnalysisPhaseRunning, newStatus.Phase)
+	assert.Equal(t, rs.Name, newStatus.TemplateStatuses[0].HorizontalPodAutoscalerName)
+	assert.Equal(t, []string{conditions.HorizontalPodAutoscalerCreatedReason}, exCtx.recorder.(*record.FakeEventRecorder).Events())
+
+	hpa, err := exCtx.kubeclientset.AutoscalingV2().HorizontalPodAutoscalers(ex.Namespace).Get(context.TODO(), rs.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "ReplicaSet", hpa.Spec.ScaleTargetRef.Kind)
+	assert.Equal(t, rs.Name, hpa.Spec.ScaleTargetRef.Name)
+	assert.Equal(t, int32(1), *hpa.Spec.MinReplicas)
+	assert.Equal(t, int32(5), hpa.Spec.MaxReplicas)
+	assert.Len(t, hpa.Spec.Metrics, 1)
+	assert.Equal(t, corev1.ResourceCPU, hpa.Spec.Metrics[0].Resource.Name)
+	assert.Equal(t, defaultTargetCPUUtilizationPercentage, *hpa.Spec.Metrics[0].Resource.Target.AverageUtilization)
+	assert.True(t, metav1.IsControlledBy(hpa, ex))
+}
+
+// TestTemplateAutoscalerNotOwnedByExperiment verifies an existing autoscaler of someone else is not adopted
+func TestTemplateAutoscalerNotOwnedByExperiment(t *testing.T) {
+	ex := newAutoscaledExperiment()
+	ex.Status.TemplateStatuses = []v1alpha1.TemplateStatus{
+		generateTemplatesStatus("bar", 1, 1, v1alpha1.TemplateStatusRunning, now()),
+	}
+	rs := templateToRS(ex, ex.Spec.Templates[0], 1)
+	exCtx := newTestContext(ex, rs)
+	exCtx.templateRSs = map[string]*appsv1.ReplicaSet{
+		"bar": rs,
+	}
+	existing := newHorizontalPodAutoscaler(ex, ex.Spec.Templates[0], rs)
+	existing.OwnerReferences = nil
+	_, err := exCtx.kubeclientset.AutoscalingV2().HorizontalPodAutoscalers(ex.Namespace).Create(context.TODO(), existing, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	newStatus := exCtx.reconcile()
+	assert.Equal(t, v1alpha1.TemplateStatusError, newStatus.TemplateStatuses[0].Status)
+	assert.Contains(t, newStatus.TemplateStatuses[0].Message, "Failed to create HorizontalPodAutoscaler for template 'bar'")
+	assert.Empty(t, newStatus.TemplateStatuses[0].HorizontalPodAutoscalerName)
+}
+
+// TestAutoscaledTemplateKeepsReplicas verifies the replicas of an autoscaled ReplicaSet are left to the autoscaler
+func TestAutoscaledTemplateKeepsReplicas(t *testing.T) {
+	ex := newAutoscaledExperiment()
+	templateStatus := generateTemplatesStatus("bar", 3, 3, v1alpha1.TemplateStatusRunning, now())
+	templateStatus.HorizontalPodAutoscalerName = "foo-bar"
+	ex.Status.TemplateStatuses = []v1alpha1.TemplateStatus{templateStatus}
+	rs := templateToRS(ex, ex.Spec.Templates[0], 3)
+	rs.Spec.Replicas = ptr.To[int32](3)
+	exCtx := newTestContext(ex, rs)
+	exCtx.templateRSs = map[string]*appsv1.ReplicaSet{
+		"bar": rs,
+	}
+	newStatus := exCtx.reconcile()
+	assert.Equal(t, v1alpha1.AnalysisPhaseRunning, newStatus.Phase)
+	assert.Equal(t, v1alpha1.TemplateStatusRunning, newStatus.TemplateStatuses[0].Status)
+	assert.Equal(t, int32(3), newStatus.TemplateStatuses[0].Replicas)
+
+	updatedRS, err := exCtx.kubeclientset.AppsV1().ReplicaSets(ex.Namespace).Get(context.TODO(), rs.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), *updatedRS.Spec.Replicas)
+}
+
+// TestAutoscaledTemplateScaledUp verifies a template keeps running while the replicas the autoscaler added are not
+// available yet, even past the progress deadline
+func TestAutoscaledTemplateScaledUp(t *testing.T) {
+	ex := newAutoscaledExperiment()
+	ex.Spec.Templates[0].Replicas = ptr.To[int32](2)
+	// the autoscaler scaled the ReplicaSet up to 5 replicas an hour ago, of which 3 are available
+	templateStatus := generateTemplatesStatus("bar", 5, 3, v1alpha1.TemplateStatusRunning, secondsAgo(3600))
+	templateStatus.UpdatedReplicas = 5
+	templateStatus.HorizontalPodAutoscalerName = "foo-bar"
+	ex.Status.TemplateStatuses = []v1alpha1.TemplateStatus{templateStatus}
+	ex.Status.AvailableAt = secondsAgo(3600)
+	rs := templateToRS(ex, ex.Spec.Templates[0], 3)
+	rs.Spec.Replicas = ptr.To[int32](5)
+	rs.Status.Replicas = 5
+	exCtx := newTestContext(ex, rs)
+	exCtx.templateRSs = map[string]*appsv1.ReplicaSet{
+		"bar": rs,
+	}
+	enqueued := false
+	exCtx.enqueueExperimentAfter = func(obj any, duration time.Duration) {
+		enqueued = true
+	}
+	newStatus := exCtx.reconcile()
+	assert.Equal(t, v1alpha1.AnalysisPhaseRunning, newStatus.Phase)
+	assert.Equal(t, v1alpha1.TemplateStatusRunning, newStatus.TemplateStatuses[0].Status)
+	assert.Equal(t, int32(3), newStatus.TemplateStatuses[0].AvailableReplicas)
+	assert.False(t, enqueued)
+
+	// the template fails once fewer replicas than the minimum of the autoscaler are available
+	rs.Status.AvailableReplicas = 1
+	rs.Status.ReadyReplicas = 1
+	exCtx = newTestContext(ex, rs)
+	exCtx.templateRSs = map[string]*appsv1.ReplicaSet{
+		"bar": rs,
+	}
+	ex.Status.TemplateStatuses[0].AvailableReplicas = 1
+	ex.Status.TemplateStatuses[0].ReadyReplicas = 1
+	newStatus = exCtx.reconcile()
+	assert.Equal(t, v1alpha1.TemplateStatusFailed, newStatus.TemplateStatuses[0].Status)
+}
+
+// TestDeleteTemplateAutoscaler verifies the autoscaler of a template is deleted when the experiment is terminated
+func TestDeleteTemplateAutoscaler(t *testing.T) {
+	ex := newAutoscaledExperiment()
+	ex.Spec.Terminate = true
+	templateStatus := generateTemplatesStatus("bar", 3, 3, v1alpha1.TemplateStatusRunning, now())
+	templateStatus.HorizontalPodAutoscalerName = "foo-bar"
+	ex.Status.TemplateStatuses = []v1alpha1.TemplateStatus{templateStatus}
+	rs := templateToRS(ex, ex.Spec.Templates[0], 3)
+	rs.Spec.Replicas = ptr.To[int32](3)
+	exCtx := newTestContext(ex, rs)
+	exCtx.templateRSs = map[string]*appsv1.ReplicaSet{
+		"bar": rs,
+	}
+	hpa := newHorizontalPodAutoscaler(ex, ex.Spec.Templates[0], rs)
+	_, err := exCtx.kubeclientset.AutoscalingV2().HorizontalPodAutoscalers(ex.Namespace).Create(context.TODO(), hpa, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	newStatus := exCtx.reconcile()
+	assert.Empty(t, newStatus.TemplateStatuses[0].HorizontalPodAutoscalerName)
+	assert.Contains(t, exCtx.recorder.(*record.FakeEventRecorder).Events(), conditions.HorizontalPodAutoscalerDeletedReason)
+	_, err = exCtx.kubeclientset.AutoscalingV2().HorizontalPodAutoscalers(ex.Namespace).Get(context.TODO(), hpa.Name, metav1.GetOptions{})
+	assert.True(t, k8serrors.IsNotFound(err))
+}
+
+func TestNewHorizontalPodAutoscaler(t *testing.T) {
+	ex := newAutoscaledExperiment()
+	template := ex.Spec.Templates[0]
+	template.Autoscaling = &v1alpha1.TemplateAutoscaling{
+		MinReplicas:                       ptr.To[int32](2),
+		MaxReplicas:                       10,
+		TargetMemoryUtilizationPercentage: ptr.To[int32](70),
+	}
+	rs := templateToRS(ex, template, 1)
+	hpa := newHorizontalPodAutoscaler(ex, template, rs)
+	assert.Equal(t, int32(2), *hpa.Spec.MinReplicas)
+	assert.Equal(t, int32(10), hpa.Spec.MaxReplicas)
+	assert.Len(t, hpa.Spec.Metrics, 1)
+	assert.Equal(t, corev1.ResourceMemory, hpa.Spec.Metrics[0].Resource.Name)
+	assert.Equal(t, int32(70), *hpa.Spec.Metrics[0].Resource.Target.AverageUtilization)
+
+	template.Autoscaling.TargetCPUUtilizationPercentage = ptr.To[int32](50)
+	hpa = newHorizontalPodAutoscaler(ex, template, rs)
+	assert.Len(t, hpa.Spec.Metrics, 2)
+	assert.Equal(t, corev1.ResourceCPU, hpa.Spec.Metrics[0].Resource.Name)
+	assert.Equal(t, int32(50), *hpa.Spec.Metrics[0].Resource.Target.AverageUtilization)
+}
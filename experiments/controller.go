@@ -13,7 +13,6 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	patchtypes "k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/wait"
 	appsinformers "k8s.io/client-go/informers/apps/v1"
 	informersv1 "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
@@ -24,17 +23,20 @@ import (
 	"k8s.io/kubernetes/pkg/controller"
 
 	"github.com/argoproj/argo-rollouts/controller/metrics"
+	"github.com/argoproj/argo-rollouts/experiments/export"
 	register "github.com/argoproj/argo-rollouts/pkg/apis/rollouts"
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	clientset "github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned"
 	informers "github.com/argoproj/argo-rollouts/pkg/client/informers/externalversions/rollouts/v1alpha1"
 	listers "github.com/argoproj/argo-rollouts/pkg/client/listers/rollouts/v1alpha1"
 	"github.com/argoproj/argo-rollouts/utils/conditions"
+	"github.com/argoproj/argo-rollouts/utils/config"
 	controllerutil "github.com/argoproj/argo-rollouts/utils/controller"
 	"github.com/argoproj/argo-rollouts/utils/defaults"
 	"github.com/argoproj/argo-rollouts/utils/diff"
 	logutil "github.com/argoproj/argo-rollouts/utils/log"
 	"github.com/argoproj/argo-rollouts/utils/record"
+	"github.com/argoproj/argo-rollouts/utils/sharding"
 	timeutil "github.com/argoproj/argo-rollouts/utils/time"
 	unstructuredutil "github.com/argoproj/argo-rollouts/utils/unstructured"
 )
@@ -50,6 +52,7 @@ type Controller struct {
 	replicaSetControl controller.RSControlInterface
 
 	replicaSetLister              appslisters.ReplicaSetLister
+	replicaSetIndexer             cache.Indexer
 	experimentsLister             listers.ExperimentLister
 	analysisTemplateLister        listers.AnalysisTemplateLister
 	clusterAnalysisTemplateLister listers.ClusterAnalysisTemplateLister
@@ -67,6 +70,14 @@ type Controller struct {
 	// used for unit testing
 	enqueueExperiment      func(obj any)
 	enqueueExperimentAfter func(obj any, duration time.Duration)
+	newResultExporters     func(cfg config.ExperimentResultExport) ([]export.Exporter, error)
+
+	// resultExports exports the results of completed experiments in the background
+	resultExports *resultExports
+	// resultExporters are the exporters of resultExportConfig, which are reused until the configuration changes
+	resultExportersLock sync.Mutex
+	resultExportConfig  *config.ExperimentResultExport
+	resultExporters     []export.Exporter
 
 	// workqueue is a rate limited work queue. This is used to queue work to be
 	// processed instead of performing it as soon as a change happens. This
@@ -79,6 +90,10 @@ type Controller struct {
 	// Kubernetes API.
 	recorder     record.EventRecorder
 	resyncPeriod time.Duration
+	// sharder selects the experiments reconciled by this controller replica when running in sharding mode
+	sharder *sharding.Sharder
+	// workerScaling configures the scaling of the experiment workers
+	workerScaling controllerutil.WorkerScaling
 }
 
 // ControllerConfig describes the data required to instantiate a new experiments controller
@@ -96,6 +111,9 @@ type ControllerConfig struct {
 	ExperimentWorkQueue             workqueue.RateLimitingInterface
 	MetricsServer                   *metrics.MetricsServer
 	Recorder                        record.EventRecorder
+	ExperimentResyncPeriod          time.Duration
+	Sharder                         *sharding.Sharder
+	WorkerScaling                   controllerutil.WorkerScaling
 }
 
 // NewController returns a new experiment controller
@@ -105,11 +123,16 @@ func NewController(cfg ControllerConfig) *Controller {
 		Recorder:   cfg.Recorder.K8sRecorder(),
 	}
 
+	if err := controllerutil.AddOwnerIndexer(cfg.ReplicaSetInformer.Informer()); err != nil {
+		panic(err)
+	}
+
 	controller := &Controller{
 		kubeclientset:                 cfg.KubeClientSet,
 		argoProjClientset:             cfg.ArgoProjClientset,
 		replicaSetControl:             replicaSetControl,
 		replicaSetLister:              cfg.ReplicaSetInformer.Lister(),
+		replicaSetIndexer:             cfg.ReplicaSetInformer.Informer().GetIndexer(),
 		experimentsLister:             cfg.ExperimentsInformer.Lister(),
 		analysisTemplateLister:        cfg.AnalysisTemplateInformer.Lister(),
 		clusterAnalysisTemplateLister: cfg.ClusterAnalysisTemplateInformer.Lister(),
@@ -126,6 +149,9 @@ func NewController(cfg ControllerConfig) *Controller {
 		clusterAnalysisTemplateSynced: cfg.ClusterAnalysisTemplateInformer.Informer().HasSynced,
 		recorder:                      cfg.Recorder,
 		resyncPeriod:                  cfg.ResyncPeriod,
+		sharder:                       cfg.Sharder,
+		workerScaling:                 cfg.WorkerScaling,
+		resultExports:                 newResultExports(),
 	}
 
 	controller.enqueueExperiment = func(obj any) {
@@ -134,16 +160,19 @@ func NewController(cfg ControllerConfig) *Controller {
 	controller.enqueueExperimentAfter = func(obj any, duration time.Duration) {
 		controllerutil.EnqueueAfter(obj, duration, cfg.ExperimentWorkQueue)
 	}
+	controller.newResultExporters = func(exportCfg config.ExperimentResultExport) ([]export.Exporter, error) {
+		return export.NewExporters(exportCfg, cfg.KubeClientSet)
+	}
 
 	log.Info("Setting up experiments event handlers")
 	// Set up an event handler for when experiment resources change
-	cfg.ExperimentsInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+	controllerutil.AddEventHandlerWithResyncPeriod(cfg.ExperimentsInformer.Informer(), cache.ResourceEventHandlerFuncs{
 		AddFunc: controller.enqueueExperiment,
 		UpdateFunc: func(old, new any) {
 			controller.enqueueExperiment(new)
 		},
 		DeleteFunc: controller.enqueueExperiment,
-	})
+	}, cfg.ExperimentResyncPeriod)
 
 	cfg.ExperimentsInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj any) {
@@ -227,18 +256,10 @@ func NewController(cfg ControllerConfig) *Controller {
 // Run starts the controller threads
 func (ec *Controller) Run(ctx context.Context, threadiness int) error {
 	log.Info("Starting Experiment workers")
-	wg := sync.WaitGroup{}
-	for i := 0; i < threadiness; i++ {
-		wg.Add(1)
-		go wait.Until(func() {
-			controllerutil.RunWorker(ctx, ec.experimentWorkqueue, logutil.ExperimentKey, ec.syncHandler, ec.metricsServer)
-			log.Debug("Experiment worker has stopped")
-			wg.Done()
-		}, time.Second, ctx.Done())
-	}
+	workers := controllerutil.StartWorkers(ctx, ec.experimentWorkqueue, logutil.ExperimentKey, ec.syncHandler, ec.metricsServer, threadiness, ec.workerScaling)
 	log.Info("Started Experiment workers")
 	<-ctx.Done()
-	wg.Wait()
+	workers.Wait(controllerutil.DefaultWorkerDrainTimeout)
 	log.Info("All experiment workers have stopped")
 
 	return nil
@@ -251,15 +272,19 @@ func (ec *Controller) syncHandler(ctx context.Context, key string) error {
 		return err
 	}
 	logCtx := log.WithField(logutil.ExperimentKey, name).WithField(logutil.NamespaceKey, namespace)
-	logCtx.Infof("Started syncing Experiment at (%v)", startTime)
 	experiment, err := ec.experimentsLister.Experiments(namespace).Get(name)
 	if k8serrors.IsNotFound(err) {
 		logCtx.Info("Experiment has been deleted")
+		ec.resultExports.Forget(key)
 		return nil
 	}
 	if err != nil {
 		return err
 	}
+	if !ec.sharder.Owns(experiment) {
+		return nil
+	}
+	logCtx.Infof("Started syncing Experiment at (%v)", startTime)
 
 	defer func() {
 		duration := time.Since(startTime)
@@ -272,6 +297,10 @@ func (ec *Controller) syncHandler(ctx context.Context, key string) error {
 		return nil
 	}
 
+	if deleted, err := ec.maybeGarbageCollectExperiment(experiment, logCtx); err != nil || deleted {
+		return err
+	}
+
 	prevCond := conditions.GetExperimentCondition(experiment.Status, v1alpha1.InvalidExperimentSpec)
 	invalidSpecCond := conditions.VerifyExperimentSpec(experiment, prevCond)
 	if invalidSpecCond != nil {
@@ -315,6 +344,7 @@ func (ec *Controller) syncHandler(ctx context.Context, key string) error {
 	)
 
 	newStatus := exCtx.reconcile()
+	ec.reconcileResultExport(experiment, newStatus)
 	return ec.persistExperimentStatus(experiment, newStatus)
 }
 
@@ -583,6 +583,12 @@ func (f *fixture) expectUpdateExperimentAction(experiment *v1alpha1.Experiment)
 	return len
 }
 
+func (f *fixture) expectDeleteExperimentAction(experiment *v1alpha1.Experiment) int {
+	len := len(f.actions)
+	f.actions = append(f.actions, core.NewDeleteAction(schema.GroupVersionResource{Resource: "experiments"}, experiment.Namespace, experiment.Name))
+	return len
+}
+
 func (f *fixture) expectGetReplicaSetAction(r *appsv1.ReplicaSet) int {
 	len := len(f.kubeactions)
 	f.kubeactions = append(f.kubeactions, core.NewGetAction(schema.GroupVersionResource{Resource: "replicasets"}, r.Namespace, r.Name))
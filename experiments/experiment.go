@@ -2,6 +2,7 @@ package experiments
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,11 +10,13 @@ import (
 	clientset "github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned"
 	rolloutslisters "github.com/argoproj/argo-rollouts/pkg/client/listers/rollouts/v1alpha1"
 	analysisutil "github.com/argoproj/argo-rollouts/utils/analysis"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
 	"github.com/argoproj/argo-rollouts/utils/defaults"
 	experimentutil "github.com/argoproj/argo-rollouts/utils/experiment"
 	logutil "github.com/argoproj/argo-rollouts/utils/log"
 	"github.com/argoproj/argo-rollouts/utils/record"
 	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
+	"github.com/argoproj/argo-rollouts/utils/sharding"
 	templateutil "github.com/argoproj/argo-rollouts/utils/template"
 	timeutil "github.com/argoproj/argo-rollouts/utils/time"
 
@@ -95,6 +98,11 @@ func newExperimentContext(
 }
 
 func (ec *experimentContext) reconcile() *v1alpha1.ExperimentStatus {
+	if ec.reconcileSchedule() {
+		ec.newStatus = calculateExperimentConditions(ec.ex, *ec.newStatus)
+		return ec.newStatus
+	}
+
 	for _, template := range ec.ex.Spec.Templates {
 		ec.reconcileTemplate(template)
 	}
@@ -127,6 +135,11 @@ func (ec *experimentContext) reconcileTemplate(template v1alpha1.TemplateSpec) {
 	now := timeutil.MetaNow()
 
 	rs := ec.templateRSs[template.Name]
+	autoscaled := rs != nil && templateStatus.HorizontalPodAutoscalerName != "" && desiredReplicaCount != 0
+	if autoscaled {
+		// the autoscaler owns the replica count of the ReplicaSet while the template is running
+		desiredReplicaCount = *rs.Spec.Replicas
+	}
 
 	// Create ReplicaSet if does not exist
 	if rs == nil {
@@ -154,6 +167,8 @@ func (ec *experimentContext) reconcileTemplate(template v1alpha1.TemplateSpec) {
 
 		// add scaleDownDelaySeconds if replicas will be scaled down
 		if desiredReplicaCount == 0 {
+			// the autoscaler would scale the ReplicaSet back up
+			ec.deleteTemplateAutoscaler(templateStatus, template.Name)
 			ec.addScaleDownDelayToTemplateRS(rs, template.Name)
 			rs = ec.templateRSs[template.Name]
 		}
@@ -163,6 +178,9 @@ func (ec *experimentContext) reconcileTemplate(template v1alpha1.TemplateSpec) {
 			experimentReplicas := defaults.GetReplicasOrDefault(template.Replicas)
 			ec.scaleTemplateRS(rs, template, templateStatus, desiredReplicaCount, experimentReplicas)
 			templateStatus.LastTransitionTime = &now
+		} else if template.Autoscaling != nil && desiredReplicaCount != 0 && templateStatus.HorizontalPodAutoscalerName == "" {
+			// the autoscaler is attached once the ReplicaSet is scaled up to the replicas of the template
+			ec.createTemplateAutoscaler(template, templateStatus, rs)
 		}
 
 	}
@@ -194,7 +212,12 @@ func (ec *experimentContext) reconcileTemplate(template v1alpha1.TemplateSpec) {
 
 	// Don't allow template statuses to transition out of completed statuses
 	if !templateStatus.Status.Completed() {
-		if desiredReplicaCount == templateStatus.AvailableReplicas {
+		available := desiredReplicaCount == templateStatus.AvailableReplicas
+		if autoscaled {
+			// the replicas the autoscaler adds with the load do not need to be available for the template to run
+			available = templateStatus.AvailableReplicas >= templateMinReplicas(template)
+		}
+		if available {
 			passedDuration, _ := experimentutil.PassedDurations(ec.ex)
 			if passedDuration {
 				templateStatus.Status = v1alpha1.TemplateStatusSuccessful
@@ -321,6 +344,30 @@ func (ec *experimentContext) createTemplateService(template *v1alpha1.TemplateSp
 
 // createReplicaSetForTemplate initializes ReplicaSet with zero replicas for given experiment template
 func (ec *experimentContext) createReplicaSetForTemplate(template v1alpha1.TemplateSpec, templateStatus *v1alpha1.TemplateStatus, logCtx *log.Entry, now metav1.Time) {
+	if template.WorkloadRef != nil {
+		// the workload is only resolved when the ReplicaSet is created, so that later changes to the
+		// workload do not affect a running experiment
+		resolved, err := ec.resolveWorkloadRef(template)
+		if errors.As(err, &workloadUnavailableError{}) {
+			// the workload may not exist yet, e.g. when it is synced together with the experiment. The template
+			// keeps progressing until it exceeds its progress deadline.
+			logCtx.Infof("Waiting for workloadRef: %v", err)
+			templateStatus.Message = fmt.Sprintf("Waiting for the workloadRef of template '%s': %v", template.Name, err)
+			if templateStatus.LastTransitionTime == nil {
+				templateStatus.LastTransitionTime = &now
+			}
+			ec.enqueueExperimentAfter(ec.ex, workloadRefRetryPeriod)
+			return
+		}
+		if err != nil {
+			logCtx.Warnf("Failed to resolve workloadRef: %v", err)
+			templateStatus.Status = v1alpha1.TemplateStatusError
+			templateStatus.Message = fmt.Sprintf("Failed to resolve workloadRef of template '%s': %v", template.Name, err)
+			return
+		}
+		templateStatus.Message = ""
+		template = resolved
+	}
 	template.Replicas = ptr.To[int32](0)
 	rs, err := ec.createReplicaSet(template, templateStatus.CollisionCount)
 	if err != nil {
@@ -352,7 +399,7 @@ func (ec *experimentContext) deleteTemplateService(svc *corev1.Service, template
 
 // calculateEnqueueDuration returns an appropriate duration to requeue the experiment. This will be
 // the shortest of:
-// * status.availableAt + spec.duration
+// * status.availableAt + spec.duration + spec.extension + status.pausedSeconds, unless the experiment is paused
 // * status.templateStatuses[].lastTransitionTime + spec.progressDeadlineSeconds
 // Returns nil if there is no need to requeue
 func calculateEnqueueDuration(ex *v1alpha1.Experiment, newStatus *v1alpha1.ExperimentStatus) *time.Duration {
@@ -362,7 +409,7 @@ func calculateEnqueueDuration(ex *v1alpha1.Experiment, newStatus *v1alpha1.Exper
 		return nil
 	}
 	var candidateDuration *time.Duration
-	if ex.Status.AvailableAt != nil && ex.Spec.Duration != "" {
+	if ex.Status.AvailableAt != nil && ex.Spec.Duration != "" && !ex.Spec.Paused {
 		// Set candidate duration to status.availableAt + duration
 		passedDuration, timeRemaining := experimentutil.PassedDurations(ex)
 		if !passedDuration {
@@ -378,9 +425,13 @@ func calculateEnqueueDuration(ex *v1alpha1.Experiment, newStatus *v1alpha1.Exper
 			continue
 		}
 		desiredReplicaCount := experimentutil.CalculateTemplateReplicasCount(ex, template)
+		available := ts.AvailableReplicas == desiredReplicaCount
+		if ts.HorizontalPodAutoscalerName != "" && desiredReplicaCount != 0 {
+			available = ts.AvailableReplicas >= templateMinReplicas(template)
+		}
 		// only requeue if we are not meeting our desired replicas, since if we are at our desired
 		// replicas, then theres nothing to check on
-		if ts.AvailableReplicas != desiredReplicaCount && ts.LastTransitionTime != nil {
+		if !available && ts.LastTransitionTime != nil {
 			progressDeadlineDuration := ts.LastTransitionTime.Add(time.Second * time.Duration(deadlineSeconds)).Sub(now)
 			if candidateDuration == nil || progressDeadlineDuration < *candidateDuration {
 				candidateDuration = &progressDeadlineDuration
@@ -518,6 +569,7 @@ func (ec *experimentContext) createAnalysisRun(analysis v1alpha1.ExperimentAnaly
 	if err != nil {
 		return nil, err
 	}
+	sharding.PropagateShard(ec.ex, run)
 	return analysisutil.CreateWithCollisionCounter(ec.log, analysisRunIf, *run)
 }
 
@@ -568,9 +620,9 @@ func (ec *experimentContext) calculateStatus() *v1alpha1.ExperimentStatus {
 				// We will now fail the experiment.
 				ec.newStatus.Phase = analysesStatus
 				ec.newStatus.Message = analysesMessage
-			} else if experimentutil.RequiredAnalysisRunsSuccessful(ec.ex, ec.newStatus) {
+			} else if !ec.ex.Spec.Paused && experimentutil.RequiredAnalysisRunsSuccessful(ec.ex, ec.newStatus) {
 				// All the required analysis runs have completed successfully so we can conclude the experiment
-				// successfully.
+				// successfully, unless it is paused.
 				ec.newStatus.Phase = analysesStatus
 				ec.newStatus.Message = analysesMessage
 			} else {
@@ -581,10 +633,44 @@ func (ec *experimentContext) calculateStatus() *v1alpha1.ExperimentStatus {
 			}
 		}
 	}
+	ec.reconcileWinner()
+	ec.reconcilePause()
+	if ec.newStatus.Phase.Completed() && ec.newStatus.CompletedAt == nil {
+		completedAt := timeutil.MetaNow()
+		if ec.ex.Status.Phase.Completed() {
+			// the experiment completed before status.completedAt was recorded
+			if previous := experimentutil.GetCompletedAt(ec.ex); previous != nil {
+				completedAt = *previous
+			}
+		}
+		ec.newStatus.CompletedAt = &completedAt
+		ec.log.Infof("Marked CompletedAt: %v", completedAt)
+	}
 	ec.newStatus = calculateExperimentConditions(ec.ex, *ec.newStatus)
 	return ec.newStatus
 }
 
+// reconcilePause records when the experiment is paused, and accumulates the time it was paused in
+// status.pausedSeconds when it is resumed or completes
+func (ec *experimentContext) reconcilePause() {
+	now := timeutil.MetaNow()
+	if ec.ex.Spec.Paused && !ec.newStatus.Phase.Completed() {
+		if ec.newStatus.PausedAt == nil {
+			ec.newStatus.PausedAt = &now
+			ec.log.Infof("Marked PausedAt: %v", now)
+			ec.recorder.Eventf(ec.ex, record.EventOptions{EventReason: conditions.ExperimentPausedReason}, "Experiment paused")
+		}
+		return
+	}
+	if ec.newStatus.PausedAt != nil {
+		pausedDuration := experimentutil.GetPausedDuration(*ec.newStatus, now.Time).Round(time.Second)
+		ec.newStatus.PausedSeconds = int64(pausedDuration.Seconds())
+		ec.newStatus.PausedAt = nil
+		ec.log.Infof("Experiment resumed after being paused for %s in total", pausedDuration)
+		ec.recorder.Eventf(ec.ex, record.EventOptions{EventReason: conditions.ExperimentResumedReason}, "Experiment resumed")
+	}
+}
+
 // assessTemplates examines at all the template statuses, and returns the worst of them to be
 // considered as the experiment status, along with the message
 func (ec *experimentContext) assessTemplates() (v1alpha1.AnalysisPhase, string) {
@@ -624,8 +710,13 @@ func (ec *experimentContext) assessAnalysisRuns() (v1alpha1.AnalysisPhase, strin
 
 	for _, a := range ec.ex.Spec.Analyses {
 		as := experimentutil.GetAnalysisRunStatus(*ec.newStatus, a.Name)
-		if analysisutil.IsWorse(worstStatus, as.Phase) {
-			worstStatus = as.Phase
+		phase := as.Phase
+		if phase.Completed() && experimentutil.IsWinnerCandidateAnalysis(ec.ex, a.Name) {
+			// a candidate whose analysis did not succeed loses the experiment instead of failing it
+			phase = v1alpha1.AnalysisPhaseSuccessful
+		}
+		if analysisutil.IsWorse(worstStatus, phase) {
+			worstStatus = phase
 			message = as.Message
 		}
 	}
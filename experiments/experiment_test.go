@@ -32,9 +32,9 @@ func newTestContext(ex *v1alpha1.Experiment, objects ...runtime.Object) *experim
 	kubeobjects := []runtime.Object{}
 	for _, obj := range objects {
 		switch obj.(type) {
-		case *v1alpha1.Experiment:
+		case *v1alpha1.Experiment, *v1alpha1.Rollout, *v1alpha1.AnalysisRun:
 			exobjects = append(exobjects, obj)
-		case *appsv1.ReplicaSet:
+		case *appsv1.ReplicaSet, *appsv1.Deployment:
 			kubeobjects = append(kubeobjects, obj)
 		}
 	}
@@ -48,6 +48,11 @@ func newTestContext(ex *v1alpha1.Experiment, objects ...runtime.Object) *experim
 	analysisTemplateLister := rolloutsI.Argoproj().V1alpha1().AnalysisTemplates().Lister()
 	clusterAnalysisTemplateLister := rolloutsI.Argoproj().V1alpha1().ClusterAnalysisTemplates().Lister()
 	serviceLister := k8sI.Core().V1().Services().Lister()
+	for _, obj := range objects {
+		if run, ok := obj.(*v1alpha1.AnalysisRun); ok {
+			rolloutsI.Argoproj().V1alpha1().AnalysisRuns().Informer().GetIndexer().Add(run)
+		}
+	}
 
 	return newExperimentContext(
 		ex,
@@ -329,11 +334,12 @@ func TestSuccessAfterDurationPasses(t *testing.T) {
 		generateTemplatesStatus("baz", 1, 1, v1alpha1.TemplateStatusSuccessful, now()),
 	}
 	cond := newCondition(conditions.ExperimentCompleteReason, e)
-	expectedPatch := calculatePatch(e, `{
+	expectedPatch := calculatePatch(e, fmt.Sprintf(`{
 		"status":{
-			"phase": "Successful"
+			"phase": "Successful",
+			"completedAt": "%s"
 		}
-	}`, templateStatuses, cond, nil, "")
+	}`, now().UTC().Format(time.RFC3339)), templateStatuses, cond, nil, "")
 	assert.JSONEq(t, expectedPatch, patch)
 }
 
@@ -415,6 +421,62 @@ func TestRequeueAfterProgressDeadlineSeconds(t *testing.T) {
 	assert.True(t, enqueueCalled)
 }
 
+// TestPausedExperimentDoesNotComplete verifies a paused experiment keeps running after its duration passes,
+// and is not requeued for its duration
+func TestPausedExperimentDoesNotComplete(t *testing.T) {
+	templates := generateTemplates("bar")
+	ex := newExperiment("foo", templates, "5s")
+	ex.Spec.Paused = true
+	ex.Status.AvailableAt = &metav1.Time{Time: timeutil.MetaNow().Add(-10 * time.Second)}
+	ex.Status.TemplateStatuses = []v1alpha1.TemplateStatus{
+		generateTemplatesStatus("bar", 1, 1, v1alpha1.TemplateStatusRunning, now()),
+	}
+	exCtx := newTestContext(ex)
+	rs1 := templateToRS(ex, ex.Spec.Templates[0], 1)
+	exCtx.templateRSs = map[string]*appsv1.ReplicaSet{
+		"bar": rs1,
+	}
+	enqueueCalled := false
+	exCtx.enqueueExperimentAfter = func(obj any, duration time.Duration) {
+		enqueueCalled = true
+	}
+	newStatus := exCtx.reconcile()
+	assert.False(t, enqueueCalled)
+	assert.Equal(t, v1alpha1.AnalysisPhaseRunning, newStatus.Phase)
+	assert.Equal(t, v1alpha1.TemplateStatusRunning, newStatus.TemplateStatuses[0].Status)
+	assert.NotNil(t, newStatus.PausedAt)
+}
+
+// TestResumeExperiment verifies the time a resumed experiment was paused is added to its duration
+func TestResumeExperiment(t *testing.T) {
+	templates := generateTemplates("bar")
+	ex := newExperiment("foo", templates, "30s")
+	ex.Spec.Extension = "10s"
+	ex.Status.AvailableAt = &metav1.Time{Time: timeutil.MetaNow().Add(-50 * time.Second)}
+	ex.Status.PausedAt = &metav1.Time{Time: timeutil.MetaNow().Add(-20 * time.Second)}
+	ex.Status.TemplateStatuses = []v1alpha1.TemplateStatus{
+		generateTemplatesStatus("bar", 1, 1, v1alpha1.TemplateStatusRunning, now()),
+	}
+	exCtx := newTestContext(ex)
+	rs1 := templateToRS(ex, ex.Spec.Templates[0], 1)
+	exCtx.templateRSs = map[string]*appsv1.ReplicaSet{
+		"bar": rs1,
+	}
+	enqueueCalled := false
+	exCtx.enqueueExperimentAfter = func(obj any, duration time.Duration) {
+		enqueueCalled = true
+		// ensures we are enqueued around 30s + 10s + 20s - 50s = 10 seconds
+		tenSeconds := time.Second * time.Duration(10)
+		delta := math.Abs(float64(tenSeconds - duration))
+		assert.True(t, delta < float64(150*time.Millisecond))
+	}
+	newStatus := exCtx.reconcile()
+	assert.True(t, enqueueCalled)
+	assert.Equal(t, v1alpha1.AnalysisPhaseRunning, newStatus.Phase)
+	assert.Nil(t, newStatus.PausedAt)
+	assert.Equal(t, int64(20), newStatus.PausedSeconds)
+}
+
 func TestFailReplicaSetCreation(t *testing.T) {
 	templates := generateTemplates("good", "bad")
 	e := newExperiment("foo", templates, "")
@@ -903,11 +965,12 @@ func expectFailureWithMessage(e *v1alpha1.Experiment, templates []v1alpha1.Templ
 			Phase:       "Error",
 		},
 	}
-	expectedPatch := calculatePatch(e, `{
+	expectedPatch := calculatePatch(e, fmt.Sprintf(`{
 		"status":{
-			"phase": "Error"
+			"phase": "Error",
+			"completedAt": "%s"
 		}
-	}`, templateStatus, cond, analysisRun, message)
+	}`, now().UTC().Format(time.RFC3339)), templateStatus, cond, analysisRun, message)
 	assert.Equal(t, expectedPatch, patch)
 }
 
@@ -0,0 +1,57 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/argoproj/argo-rollouts/utils/config"
+)
+
+// Exporter exports the results of completed experiments to an external system
+type Exporter interface {
+	// Type returns the type of the exporter, e.g. webhook
+	Type() string
+	// Export exports the result of an experiment
+	Export(ctx context.Context, result *Result) error
+}
+
+// NewExporters returns the exporters of the configuration
+func NewExporters(cfg config.ExperimentResultExport, kubeclientset kubernetes.Interface) ([]Exporter, error) {
+	var exporters []Exporter
+	if cfg.Webhook != nil {
+		exporters = append(exporters, NewWebhookExporter(*cfg.Webhook, kubeclientset))
+	}
+	if cfg.S3 != nil {
+		exporter, err := NewS3Exporter(*cfg.S3)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create s3 exporter: %w", err)
+		}
+		exporters = append(exporters, exporter)
+	}
+	if cfg.GCS != nil {
+		exporter, err := NewGCSExporter(*cfg.GCS)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gcs exporter: %w", err)
+		}
+		exporters = append(exporters, exporter)
+	}
+	return exporters, nil
+}
+
+// ObjectName returns the name of the document of the result in a bucket, i.e. <prefix><namespace>/<name>/<uid>.json.
+// The uid distinguishes experiments which are recreated with the same name.
+func ObjectName(prefix string, result *Result) string {
+	return prefix + path.Join(result.Metadata.Namespace, result.Metadata.Name, result.Metadata.UID+".json")
+}
+
+func marshalResult(result *Result) ([]byte, error) {
+	body, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return body, nil
+}
@@ -0,0 +1,155 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/argoproj/argo-rollouts/utils/config"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+)
+
+func TestObjectName(t *testing.T) {
+	result := NewResult(newCompletedExperiment(), nil)
+	assert.Equal(t, "default/foo/1234.json", ObjectName("", result))
+	assert.Equal(t, "experiments/default/foo/1234.json", ObjectName("experiments/", result))
+}
+
+func TestNewExporters(t *testing.T) {
+	exporters, err := NewExporters(config.ExperimentResultExport{}, fake.NewSimpleClientset())
+	assert.NoError(t, err)
+	assert.Empty(t, exporters)
+
+	exporters, err = NewExporters(config.ExperimentResultExport{
+		Webhook: &config.WebhookResultExport{URL: "http://example.com"},
+	}, fake.NewSimpleClientset())
+	assert.NoError(t, err)
+	assert.Len(t, exporters, 1)
+	assert.Equal(t, WebhookExporterType, exporters[0].Type())
+}
+
+func TestWebhookExporter(t *testing.T) {
+	var received Result
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &received)
+	}))
+	defer server.Close()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhook", Namespace: defaults.Namespace()},
+		Data:       map[string][]byte{"token": []byte("Bearer secret")},
+	}
+	exporter := NewWebhookExporter(config.WebhookResultExport{
+		URL: server.URL,
+		Headers: []config.WebhookResultExportHeader{
+			{Key: "X-Source", Value: "argo-rollouts"},
+			{Key: "Authorization", SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "webhook"},
+				Key:                  "token",
+			}},
+		},
+	}, fake.NewSimpleClientset(secret))
+
+	err := exporter.Export(context.Background(), NewResult(newCompletedExperiment(), nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "application/json", headers.Get("Content-Type"))
+	assert.Equal(t, "argo-rollouts", headers.Get("X-Source"))
+	assert.Equal(t, "Bearer secret", headers.Get("Authorization"))
+	assert.Equal(t, ResultKind, received.Kind)
+	assert.Equal(t, "foo", received.Metadata.Name)
+}
+
+func TestWebhookExporterErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("boom"))
+	}))
+	defer server.Close()
+	result := NewResult(newCompletedExperiment(), nil)
+
+	exporter := NewWebhookExporter(config.WebhookResultExport{URL: server.URL}, fake.NewSimpleClientset())
+	err := exporter.Export(context.Background(), result)
+	assert.EqualError(t, err, "webhook responded with status 500: boom")
+
+	exporter = NewWebhookExporter(config.WebhookResultExport{
+		URL: server.URL,
+		Headers: []config.WebhookResultExportHeader{
+			{Key: "Authorization", SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "missing"},
+				Key:                  "token",
+			}},
+		},
+	}, fake.NewSimpleClientset())
+	err = exporter.Export(context.Background(), result)
+	assert.ErrorContains(t, err, "failed to get secret of header Authorization")
+}
+
+type fakeS3Client struct {
+	input *s3.PutObjectInput
+	body  []byte
+	err   error
+}
+
+func (c *fakeS3Client) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	c.input = params
+	c.body, _ = io.ReadAll(params.Body)
+	return &s3.PutObjectOutput{}, c.err
+}
+
+func TestS3Exporter(t *testing.T) {
+	client := &fakeS3Client{}
+	exporter := NewS3ExporterWithClient(config.S3ResultExport{Bucket: "results", Prefix: "experiments/"}, client)
+	assert.Equal(t, S3ExporterType, exporter.Type())
+
+	err := exporter.Export(context.Background(), NewResult(newCompletedExperiment(), nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "results", aws.ToString(client.input.Bucket))
+	assert.Equal(t, "experiments/default/foo/1234.json", aws.ToString(client.input.Key))
+	assert.Equal(t, "application/json", aws.ToString(client.input.ContentType))
+	var received Result
+	assert.NoError(t, json.Unmarshal(client.body, &received))
+	assert.Equal(t, "foo", received.Metadata.Name)
+
+	client.err = errors.New("access denied")
+	err = exporter.Export(context.Background(), NewResult(newCompletedExperiment(), nil))
+	assert.EqualError(t, err, "failed to upload experiments/default/foo/1234.json to bucket results: access denied")
+}
+
+func TestGCSExporter(t *testing.T) {
+	var request *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request = r
+		if r.URL.Query().Get("name") == "forbidden/default/foo/1234.json" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+	result := NewResult(newCompletedExperiment(), nil)
+
+	exporter := NewGCSExporterWithClient(config.GCSResultExport{Bucket: "results", Prefix: "experiments/"}, server.Client(), server.URL)
+	assert.Equal(t, GCSExporterType, exporter.Type())
+	err := exporter.Export(context.Background(), result)
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodPost, request.Method)
+	assert.Equal(t, "/b/results/o", request.URL.Path)
+	assert.Equal(t, "media", request.URL.Query().Get("uploadType"))
+	assert.Equal(t, "experiments/default/foo/1234.json", request.URL.Query().Get("name"))
+
+	exporter = NewGCSExporterWithClient(config.GCSResultExport{Bucket: "results", Prefix: "forbidden/"}, server.Client(), server.URL)
+	err = exporter.Export(context.Background(), result)
+	assert.ErrorContains(t, err, "status 403")
+}
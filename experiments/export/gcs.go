@@ -0,0 +1,77 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/oauth2/google"
+
+	"github.com/argoproj/argo-rollouts/utils/config"
+)
+
+const (
+	// GCSExporterType is the type of the Google Cloud Storage exporter
+	GCSExporterType = "gcs"
+	// gcsUploadURL is the endpoint of the JSON API of Google Cloud Storage which uploads objects
+	gcsUploadURL = "https://storage.googleapis.com/upload/storage/v1"
+	// gcsScope is the OAuth2 scope required to upload objects
+	gcsScope = "https://www.googleapis.com/auth/devstorage.read_write"
+)
+
+type gcsExporter struct {
+	cfg       config.GCSResultExport
+	client    *http.Client
+	uploadURL string
+}
+
+// NewGCSExporter returns an exporter which uploads the results to the Google Cloud Storage bucket, authenticated with
+// the application default credentials
+func NewGCSExporter(cfg config.GCSResultExport) (Exporter, error) {
+	client, err := google.DefaultClient(context.TODO(), gcsScope)
+	if err != nil {
+		return nil, err
+	}
+	return NewGCSExporterWithClient(cfg, client, gcsUploadURL), nil
+}
+
+// NewGCSExporterWithClient returns an exporter which uploads the results to the Google Cloud Storage bucket with the
+// authenticated client, through the upload endpoint of the JSON API
+func NewGCSExporterWithClient(cfg config.GCSResultExport, client *http.Client, uploadURL string) Exporter {
+	return &gcsExporter{
+		cfg:       cfg,
+		client:    client,
+		uploadURL: uploadURL,
+	}
+}
+
+func (e *gcsExporter) Type() string {
+	return GCSExporterType
+}
+
+func (e *gcsExporter) Export(ctx context.Context, result *Result) error {
+	body, err := marshalResult(result)
+	if err != nil {
+		return err
+	}
+	name := ObjectName(e.cfg.Prefix, result)
+	uploadURL := fmt.Sprintf("%s/b/%s/o?uploadType=media&name=%s", e.uploadURL, url.PathEscape(e.cfg.Bucket), url.QueryEscape(name))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uploadURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s to bucket %s: %w", name, e.cfg.Bucket, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("failed to upload %s to bucket %s: status %d: %s", name, e.cfg.Bucket, resp.StatusCode, string(respBody))
+	}
+	return nil
+}
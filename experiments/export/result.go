@@ -0,0 +1,132 @@
+package export
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	experimentutil "github.com/argoproj/argo-rollouts/utils/experiment"
+)
+
+const (
+	// ResultAPIVersion is the version of the result document
+	ResultAPIVersion = "argoproj.io/v1alpha1"
+	// ResultKind is the kind of the result document
+	ResultKind = "ExperimentResult"
+)
+
+// Result is the structured result document of a completed experiment
+type Result struct {
+	APIVersion string         `json:"apiVersion"`
+	Kind       string         `json:"kind"`
+	Metadata   ResultMetadata `json:"metadata"`
+	// Phase is the verdict of the experiment
+	Phase   v1alpha1.AnalysisPhase `json:"phase"`
+	Message string                 `json:"message,omitempty"`
+	// Winner is the template which won the experiment, if it selects a winner
+	Winner string `json:"winner,omitempty"`
+	// CreatedAt is the time the experiment was created
+	CreatedAt metav1.Time `json:"createdAt"`
+	// AvailableAt is the time all the templates of the experiment became available
+	AvailableAt *metav1.Time `json:"availableAt,omitempty"`
+	// CompletedAt is the time the experiment completed
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+	// Duration is the duration the experiment was requested to run for, including its extension
+	Duration v1alpha1.DurationString `json:"duration,omitempty"`
+	// RunningSeconds is the time between availableAt and completedAt, without the time the experiment was paused
+	RunningSeconds int64 `json:"runningSeconds,omitempty"`
+	// PausedSeconds is the time the experiment was paused
+	PausedSeconds int64            `json:"pausedSeconds,omitempty"`
+	Templates     []TemplateResult `json:"templates,omitempty"`
+	Analyses      []AnalysisResult `json:"analyses,omitempty"`
+}
+
+// ResultMetadata identifies the experiment of a result
+type ResultMetadata struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	UID       string            `json:"uid"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	// Rollout is the name of the rollout which created the experiment
+	Rollout string `json:"rollout,omitempty"`
+}
+
+// TemplateResult is the result of a template of an experiment
+type TemplateResult struct {
+	Name              string                      `json:"name"`
+	Status            v1alpha1.TemplateStatusCode `json:"status"`
+	Message           string                      `json:"message,omitempty"`
+	PodTemplateHash   string                      `json:"podTemplateHash,omitempty"`
+	Replicas          int32                       `json:"replicas"`
+	AvailableReplicas int32                       `json:"availableReplicas"`
+}
+
+// AnalysisResult is the result of an analysis of an experiment, with the measurements of its metrics
+type AnalysisResult struct {
+	Name        string                  `json:"name"`
+	AnalysisRun string                  `json:"analysisRun,omitempty"`
+	Phase       v1alpha1.AnalysisPhase  `json:"phase"`
+	Message     string                  `json:"message,omitempty"`
+	StartedAt   *metav1.Time            `json:"startedAt,omitempty"`
+	CompletedAt *metav1.Time            `json:"completedAt,omitempty"`
+	Metrics     []v1alpha1.MetricResult `json:"metrics,omitempty"`
+}
+
+// NewResult returns the result document of the completed experiment. The analyses are completed with the metric
+// results of the analysis runs, given by their name.
+func NewResult(experiment *v1alpha1.Experiment, analysisRuns map[string]*v1alpha1.AnalysisRun) *Result {
+	result := &Result{
+		APIVersion: ResultAPIVersion,
+		Kind:       ResultKind,
+		Metadata: ResultMetadata{
+			Name:      experiment.Name,
+			Namespace: experiment.Namespace,
+			UID:       string(experiment.UID),
+			Labels:    experiment.Labels,
+		},
+		Phase:         experiment.Status.Phase,
+		Message:       experiment.Status.Message,
+		Winner:        experiment.Status.Winner,
+		CreatedAt:     experiment.CreationTimestamp,
+		AvailableAt:   experiment.Status.AvailableAt,
+		CompletedAt:   experiment.Status.CompletedAt,
+		Duration:      experiment.Spec.Duration,
+		PausedSeconds: experiment.Status.PausedSeconds,
+	}
+	if ownerRef := experimentutil.GetRolloutOwnerRef(experiment); ownerRef != nil {
+		result.Metadata.Rollout = ownerRef.Name
+	}
+	if experiment.Spec.Duration != "" && experiment.Spec.Extension != "" {
+		if duration, err := experimentutil.GetDuration(experiment); err == nil {
+			result.Duration = v1alpha1.DurationString(duration.String())
+		}
+	}
+	if result.AvailableAt != nil && result.CompletedAt != nil {
+		running := result.CompletedAt.Sub(result.AvailableAt.Time).Seconds() - float64(result.PausedSeconds)
+		result.RunningSeconds = max(int64(running), 0)
+	}
+	for _, ts := range experiment.Status.TemplateStatuses {
+		result.Templates = append(result.Templates, TemplateResult{
+			Name:              ts.Name,
+			Status:            ts.Status,
+			Message:           ts.Message,
+			PodTemplateHash:   ts.PodTemplateHash,
+			Replicas:          ts.Replicas,
+			AvailableReplicas: ts.AvailableReplicas,
+		})
+	}
+	for _, as := range experiment.Status.AnalysisRuns {
+		analysis := AnalysisResult{
+			Name:        as.Name,
+			AnalysisRun: as.AnalysisRun,
+			Phase:       as.Phase,
+			Message:     as.Message,
+		}
+		if run := analysisRuns[as.AnalysisRun]; run != nil {
+			analysis.StartedAt = run.Status.StartedAt
+			analysis.CompletedAt = run.Status.CompletedAt
+			analysis.Metrics = run.Status.MetricResults
+		}
+		result.Analyses = append(result.Analyses, analysis)
+	}
+	return result
+}
@@ -0,0 +1,83 @@
+package export
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+func newCompletedExperiment() *v1alpha1.Experiment {
+	availableAt := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	completedAt := metav1.NewTime(availableAt.Add(10 * time.Minute))
+	return &v1alpha1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: "default",
+			UID:       "1234",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "argoproj.io/v1alpha1",
+				Kind:       "Rollout",
+				Name:       "guestbook",
+				Controller: ptr.To(true),
+			}},
+		},
+		Spec: v1alpha1.ExperimentSpec{
+			Duration:  "5m",
+			Extension: "3m",
+		},
+		Status: v1alpha1.ExperimentStatus{
+			Phase:         v1alpha1.AnalysisPhaseSuccessful,
+			Winner:        "bar",
+			AvailableAt:   &availableAt,
+			CompletedAt:   &completedAt,
+			PausedSeconds: 120,
+			TemplateStatuses: []v1alpha1.TemplateStatus{
+				{Name: "bar", Status: v1alpha1.TemplateStatusSuccessful, Replicas: 1, AvailableReplicas: 1},
+			},
+			AnalysisRuns: []v1alpha1.ExperimentAnalysisRunStatus{
+				{Name: "bar-score", AnalysisRun: "foo-bar-score", Phase: v1alpha1.AnalysisPhaseSuccessful},
+				{Name: "baz-score", AnalysisRun: "foo-baz-score", Phase: v1alpha1.AnalysisPhaseError},
+			},
+		},
+	}
+}
+
+func TestNewResult(t *testing.T) {
+	ex := newCompletedExperiment()
+	run := &v1alpha1.AnalysisRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo-bar-score"},
+		Status: v1alpha1.AnalysisRunStatus{
+			MetricResults: []v1alpha1.MetricResult{{Name: "score", Measurements: []v1alpha1.Measurement{{Value: "0.99"}}}},
+		},
+	}
+	result := NewResult(ex, map[string]*v1alpha1.AnalysisRun{run.Name: run})
+
+	assert.Equal(t, ResultAPIVersion, result.APIVersion)
+	assert.Equal(t, ResultKind, result.Kind)
+	assert.Equal(t, ResultMetadata{Name: "foo", Namespace: "default", UID: "1234", Rollout: "guestbook"}, result.Metadata)
+	assert.Equal(t, v1alpha1.AnalysisPhaseSuccessful, result.Phase)
+	assert.Equal(t, "bar", result.Winner)
+	assert.Equal(t, v1alpha1.DurationString("8m0s"), result.Duration)
+	assert.Equal(t, int64(480), result.RunningSeconds)
+	assert.Equal(t, int64(120), result.PausedSeconds)
+	assert.Equal(t, []TemplateResult{{Name: "bar", Status: v1alpha1.TemplateStatusSuccessful, Replicas: 1, AvailableReplicas: 1}}, result.Templates)
+	assert.Len(t, result.Analyses, 2)
+	assert.Equal(t, run.Status.MetricResults, result.Analyses[0].Metrics)
+	// the analysis run which no longer exists is exported without its metrics
+	assert.Equal(t, v1alpha1.AnalysisPhaseError, result.Analyses[1].Phase)
+	assert.Nil(t, result.Analyses[1].Metrics)
+}
+
+func TestNewResultWithoutDurationExtension(t *testing.T) {
+	ex := newCompletedExperiment()
+	ex.Spec.Extension = ""
+	ex.Status.AvailableAt = nil
+	result := NewResult(ex, nil)
+	assert.Equal(t, v1alpha1.DurationString("5m"), result.Duration)
+	assert.Equal(t, int64(0), result.RunningSeconds)
+}
@@ -0,0 +1,71 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	awsutil "github.com/argoproj/argo-rollouts/utils/aws"
+	"github.com/argoproj/argo-rollouts/utils/config"
+)
+
+// S3ExporterType is the type of the S3 exporter
+const S3ExporterType = "s3"
+
+// S3PutObjectAPI is the part of the S3 client used to upload the results
+type S3PutObjectAPI interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+}
+
+type s3Exporter struct {
+	cfg    config.S3ResultExport
+	client S3PutObjectAPI
+}
+
+// NewS3Exporter returns an exporter which uploads the results to the S3 bucket
+func NewS3Exporter(cfg config.S3ResultExport) (Exporter, error) {
+	awsCfg, err := awsutil.LoadConfig(context.TODO(), cfg.Region, cfg.RoleARN, "")
+	if err != nil {
+		return nil, err
+	}
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if cfg.Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.Endpoint)
+			o.UsePathStyle = true
+		}
+	})
+	return NewS3ExporterWithClient(cfg, client), nil
+}
+
+// NewS3ExporterWithClient returns an exporter which uploads the results to the S3 bucket with the client
+func NewS3ExporterWithClient(cfg config.S3ResultExport, client S3PutObjectAPI) Exporter {
+	return &s3Exporter{
+		cfg:    cfg,
+		client: client,
+	}
+}
+
+func (e *s3Exporter) Type() string {
+	return S3ExporterType
+}
+
+func (e *s3Exporter) Export(ctx context.Context, result *Result) error {
+	body, err := marshalResult(result)
+	if err != nil {
+		return err
+	}
+	key := ObjectName(e.cfg.Prefix, result)
+	_, err = e.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(e.cfg.Bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(body),
+		ContentType: aws.String("application/json"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload %s to bucket %s: %w", key, e.cfg.Bucket, err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/argoproj/argo-rollouts/utils/config"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+)
+
+const (
+	// WebhookExporterType is the type of the webhook exporter
+	WebhookExporterType = "webhook"
+	// defaultWebhookTimeout is the timeout of the requests of a webhook without timeoutSeconds
+	defaultWebhookTimeout = 10 * time.Second
+)
+
+type webhookExporter struct {
+	cfg           config.WebhookResultExport
+	kubeclientset kubernetes.Interface
+	client        *http.Client
+}
+
+// NewWebhookExporter returns an exporter which posts the results to the webhook. The values of the headers which
+// reference a secret are read from the namespace of the controller on every export.
+func NewWebhookExporter(cfg config.WebhookResultExport, kubeclientset kubernetes.Interface) Exporter {
+	timeout := defaultWebhookTimeout
+	if cfg.TimeoutSeconds > 0 {
+		timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+	return &webhookExporter{
+		cfg:           cfg,
+		kubeclientset: kubeclientset,
+		client:        &http.Client{Timeout: timeout},
+	}
+}
+
+func (e *webhookExporter) Type() string {
+	return WebhookExporterType
+}
+
+func (e *webhookExporter) Export(ctx context.Context, result *Result) error {
+	body, err := marshalResult(result)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for _, header := range e.cfg.Headers {
+		value := header.Value
+		if header.SecretKeyRef != nil {
+			secret, err := e.kubeclientset.CoreV1().Secrets(defaults.Namespace()).Get(ctx, header.SecretKeyRef.Name, metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("failed to get secret of header %s: %w", header.Key, err)
+			}
+			secretValue, ok := secret.Data[header.SecretKeyRef.Key]
+			if !ok {
+				return fmt.Errorf("secret %s has no key %s for header %s", header.SecretKeyRef.Name, header.SecretKeyRef.Key, header.Key)
+			}
+			value = string(secretValue)
+		}
+		req.Header.Set(header.Key, value)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook responded with status %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
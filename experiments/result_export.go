@@ -0,0 +1,211 @@
+package experiments
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/argoproj/argo-rollouts/experiments/export"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	"github.com/argoproj/argo-rollouts/utils/config"
+	logutil "github.com/argoproj/argo-rollouts/utils/log"
+	"github.com/argoproj/argo-rollouts/utils/record"
+	timeutil "github.com/argoproj/argo-rollouts/utils/time"
+)
+
+const (
+	// resultExportTimeout bounds the time the result of an experiment is exported for
+	resultExportTimeout = 30 * time.Second
+	// resultExportRetryPeriod is the delay before the first retry of a failed export, which doubles with every failure
+	resultExportRetryPeriod = 30 * time.Second
+	// resultExportMaxRetryPeriod bounds the delay before the retry of a failed export
+	resultExportMaxRetryPeriod = 30 * time.Minute
+	// maxConcurrentResultExports bounds the exports running at the same time, e.g. once the export is configured
+	// for a cluster with many completed experiments
+	maxConcurrentResultExports = 10
+)
+
+// resultExports tracks the exports of experiment results, which run in the background so that slow exporters do not
+// block the workers of the controller. An export is keyed by the namespace/name of its experiment.
+type resultExports struct {
+	lock    sync.Mutex
+	entries map[string]*resultExport
+	// slots limits the exports running at the same time
+	slots chan struct{}
+}
+
+// resultExport is the state of the export of the result of an experiment
+type resultExport struct {
+	running bool
+	// exported holds the types of the exporters the result was exported with, which are not retried
+	exported map[string]bool
+	// failures is the number of failed attempts
+	failures int
+	// retryAt is the time of the next attempt after a failure
+	retryAt time.Time
+}
+
+func newResultExports() *resultExports {
+	return &resultExports{
+		entries: map[string]*resultExport{},
+		slots:   make(chan struct{}, maxConcurrentResultExports),
+	}
+}
+
+// Forget removes the state of the export of a deleted experiment, or of an experiment whose export was recorded
+func (r *resultExports) Forget(key string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.entries, key)
+}
+
+// next returns whether the result was exported with all the exporters, or else starts an attempt with the exporters
+// the result was not yet exported with unless an attempt is running or a retry is pending. onDone is called with the
+// delay of the retry once the attempt completed, which is zero if it succeeded.
+func (r *resultExports) next(key string, exporters []export.Exporter, attempt func([]export.Exporter) map[string]error, onDone func(time.Duration)) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	entry, ok := r.entries[key]
+	if !ok {
+		entry = &resultExport{exported: map[string]bool{}}
+		r.entries[key] = entry
+	}
+	if entry.running || timeutil.Now().Before(entry.retryAt) {
+		return false
+	}
+	var pending []export.Exporter
+	for _, exporter := range exporters {
+		if !entry.exported[exporter.Type()] {
+			pending = append(pending, exporter)
+		}
+	}
+	if len(pending) == 0 {
+		return true
+	}
+	entry.running = true
+	go func() {
+		r.slots <- struct{}{}
+		errs := attempt(pending)
+		<-r.slots
+
+		r.lock.Lock()
+		entry.running = false
+		var retryAfter time.Duration
+		for _, exporter := range pending {
+			if errs[exporter.Type()] == nil {
+				entry.exported[exporter.Type()] = true
+			}
+		}
+		if len(errs) > 0 {
+			retryAfter = resultExportRetryPeriod << entry.failures
+			if retryAfter <= 0 || retryAfter > resultExportMaxRetryPeriod {
+				retryAfter = resultExportMaxRetryPeriod
+			}
+			entry.failures++
+			entry.retryAt = timeutil.Now().Add(retryAfter)
+		}
+		r.lock.Unlock()
+		onDone(retryAfter)
+	}()
+	return false
+}
+
+// reconcileResultExport exports the result of the completed experiment with the exporters configured in the configmap
+// of the controller, and records in the status once it was exported with all of them. The result is exported in the
+// background, and a failed export is retried with a backoff. An exporter may receive the same result more than once,
+// e.g. when the controller restarts before the export was recorded.
+func (ec *Controller) reconcileResultExport(ex *v1alpha1.Experiment, newStatus *v1alpha1.ExperimentStatus) {
+	key := ex.Namespace + "/" + ex.Name
+	if ex.Status.ResultExportedAt != nil {
+		ec.resultExports.Forget(key)
+		return
+	}
+	if !newStatus.Phase.Completed() {
+		return
+	}
+	logCtx := logutil.WithExperiment(ex)
+	exporters, err := ec.getResultExporters()
+	if err != nil {
+		logCtx.Warnf("Failed to create the experiment result exporters: %v", err)
+		ec.recorder.Eventf(ex, record.EventOptions{EventType: corev1.EventTypeWarning, EventReason: conditions.ExperimentResultExportFailedReason}, "Failed to export experiment result: %v", err)
+		return
+	}
+	if len(exporters) == 0 {
+		return
+	}
+
+	analysisRuns := make(map[string]*v1alpha1.AnalysisRun)
+	for _, runStatus := range ex.Status.AnalysisRuns {
+		if runStatus.AnalysisRun == "" {
+			continue
+		}
+		run, err := ec.analysisRunLister.AnalysisRuns(ex.Namespace).Get(runStatus.AnalysisRun)
+		if err != nil {
+			logCtx.Warnf("Failed to get AnalysisRun '%s' for the experiment result: %v", runStatus.AnalysisRun, err)
+			continue
+		}
+		analysisRuns[run.Name] = run
+	}
+	exCopy := ex.DeepCopy()
+	exCopy.Status = *newStatus.DeepCopy()
+	result := export.NewResult(exCopy, analysisRuns)
+
+	recorder, enqueueExperiment, enqueueExperimentAfter := ec.recorder, ec.enqueueExperiment, ec.enqueueExperimentAfter
+	exported := ec.resultExports.next(key, exporters, func(pending []export.Exporter) map[string]error {
+		ctx, cancel := context.WithTimeout(context.Background(), resultExportTimeout)
+		defer cancel()
+		errs := map[string]error{}
+		for _, exporter := range pending {
+			if err := exporter.Export(ctx, result); err != nil {
+				logCtx.Warnf("Failed to export experiment result with the %s exporter: %v", exporter.Type(), err)
+				recorder.Eventf(exCopy, record.EventOptions{EventType: corev1.EventTypeWarning, EventReason: conditions.ExperimentResultExportFailedReason}, conditions.ExperimentResultExportFailedMessage, exporter.Type(), err)
+				errs[exporter.Type()] = err
+				continue
+			}
+			recorder.Eventf(exCopy, record.EventOptions{EventReason: conditions.ExperimentResultExportedReason}, conditions.ExperimentResultExportedMessage, exporter.Type())
+		}
+		return errs
+	}, func(retryAfter time.Duration) {
+		if retryAfter == 0 {
+			enqueueExperiment(exCopy)
+			return
+		}
+		logCtx.Infof("Retrying the export of the experiment result in %s", retryAfter)
+		enqueueExperimentAfter(exCopy, retryAfter)
+	})
+	if exported {
+		exportedAt := timeutil.MetaNow()
+		newStatus.ResultExportedAt = &exportedAt
+	}
+}
+
+// getResultExporters returns the exporters of the export configured in the configmap of the controller, or none if
+// the export is not configured. The exporters are reused until the configuration changes.
+func (ec *Controller) getResultExporters() ([]export.Exporter, error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, nil
+	}
+	exportCfg, err := cfg.GetExperimentResultExport()
+	if err != nil {
+		return nil, err
+	}
+	if exportCfg == nil {
+		return nil, nil
+	}
+	ec.resultExportersLock.Lock()
+	defer ec.resultExportersLock.Unlock()
+	if ec.resultExportConfig != nil && reflect.DeepEqual(*ec.resultExportConfig, *exportCfg) {
+		return ec.resultExporters, nil
+	}
+	exporters, err := ec.newResultExporters(*exportCfg)
+	if err != nil {
+		return nil, err
+	}
+	ec.resultExportConfig, ec.resultExporters = exportCfg, exporters
+	return exporters, nil
+}
@@ -0,0 +1,231 @@
+package experiments
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/argoproj/argo-rollouts/experiments/export"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	"github.com/argoproj/argo-rollouts/utils/config"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+	"github.com/argoproj/argo-rollouts/utils/record"
+)
+
+type fakeExporter struct {
+	typ     string
+	results []*export.Result
+	err     error
+}
+
+func (e *fakeExporter) Type() string {
+	if e.typ != "" {
+		return e.typ
+	}
+	return "fake"
+}
+
+func (e *fakeExporter) Export(ctx context.Context, result *export.Result) error {
+	e.results = append(e.results, result)
+	return e.err
+}
+
+// initializeResultExportConfig initializes the configuration of the controller with the export of experiment results
+func initializeResultExportConfig(t *testing.T, resultExport string) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      defaults.DefaultRolloutsConfigMapName,
+			Namespace: defaults.Namespace(),
+		},
+		Data: map[string]string{},
+	}
+	if resultExport != "" {
+		cm.Data["experimentResultExport"] = resultExport
+	}
+	config.UnInitializeConfig()
+	_, err := config.InitializeConfig(k8sfake.NewSimpleClientset(cm), defaults.DefaultRolloutsConfigMapName)
+	assert.NoError(t, err)
+	t.Cleanup(config.UnInitializeConfig)
+}
+
+func newCompletedExperimentWithAnalysis() (*v1alpha1.Experiment, *v1alpha1.AnalysisRun) {
+	templates := generateTemplates("bar")
+	ex := newExperiment("foo", templates, "")
+	ex.Spec.Analyses = []v1alpha1.ExperimentAnalysisTemplateRef{{Name: "success-rate", TemplateName: "success-rate"}}
+	run := analysisTemplateToRun("success-rate", ex, &v1alpha1.AnalysisTemplateSpec{})
+	run.Status.Phase = v1alpha1.AnalysisPhaseSuccessful
+	run.Status.MetricResults = []v1alpha1.MetricResult{{Name: "success-rate", Measurements: []v1alpha1.Measurement{{Value: "0.99"}}}}
+	ex.Status.Phase = v1alpha1.AnalysisPhaseSuccessful
+	ex.Status.AvailableAt = secondsAgo(60)
+	ex.Status.CompletedAt = now()
+	ex.Status.TemplateStatuses = []v1alpha1.TemplateStatus{
+		generateTemplatesStatus("bar", 0, 0, v1alpha1.TemplateStatusSuccessful, now()),
+	}
+	ex.Status.AnalysisRuns = []v1alpha1.ExperimentAnalysisRunStatus{
+		{Name: "success-rate", AnalysisRun: run.Name, Phase: v1alpha1.AnalysisPhaseSuccessful},
+	}
+	return ex, run
+}
+
+// newResultExportController returns a controller which exports the results with the exporters, and a channel
+// receiving the delay of the retry once an export completed
+func newResultExportController(f *fixture, exporters ...export.Exporter) (*Controller, chan time.Duration) {
+	c, _, _ := f.newController(noResyncPeriodFunc)
+	c.newResultExporters = func(cfg config.ExperimentResultExport) ([]export.Exporter, error) {
+		return exporters, nil
+	}
+	done := make(chan time.Duration, 1)
+	c.enqueueExperiment = func(obj any) {
+		done <- 0
+	}
+	c.enqueueExperimentAfter = func(obj any, duration time.Duration) {
+		done <- duration
+	}
+	return c, done
+}
+
+func TestExportResult(t *testing.T) {
+	initializeResultExportConfig(t, "webhook:\n  url: http://example.com\n")
+	ex, run := newCompletedExperimentWithAnalysis()
+	f := newFixture(t, ex, run)
+	defer f.Close()
+	exporter := &fakeExporter{}
+	c, done := newResultExportController(f, exporter)
+	var exportCfg config.ExperimentResultExport
+	c.newResultExporters = func(cfg config.ExperimentResultExport) ([]export.Exporter, error) {
+		exportCfg = cfg
+		return []export.Exporter{exporter}, nil
+	}
+
+	// the result is exported in the background
+	newStatus := ex.Status.DeepCopy()
+	c.reconcileResultExport(ex, newStatus)
+	assert.Nil(t, newStatus.ResultExportedAt)
+	assert.Equal(t, time.Duration(0), <-done)
+
+	assert.Equal(t, "http://example.com", exportCfg.Webhook.URL)
+	assert.Len(t, exporter.results, 1)
+	result := exporter.results[0]
+	assert.Equal(t, v1alpha1.AnalysisPhaseSuccessful, result.Phase)
+	assert.Len(t, result.Analyses, 1)
+	assert.Equal(t, run.Status.MetricResults, result.Analyses[0].Metrics)
+	events := c.recorder.(*record.FakeEventRecorder).Events()
+	assert.Equal(t, []string{conditions.ExperimentResultExportedReason}, events)
+
+	// the export is recorded once it completed
+	newStatus = ex.Status.DeepCopy()
+	c.reconcileResultExport(ex, newStatus)
+	assert.NotNil(t, newStatus.ResultExportedAt)
+	assert.Len(t, exporter.results, 1)
+
+	// the result is not exported again once the export was recorded
+	ex.Status.ResultExportedAt = newStatus.ResultExportedAt
+	newStatus = ex.Status.DeepCopy()
+	c.reconcileResultExport(ex, newStatus)
+	assert.Len(t, exporter.results, 1)
+	assert.Empty(t, c.resultExports.entries)
+}
+
+func TestExportResultFailed(t *testing.T) {
+	initializeResultExportConfig(t, "webhook:\n  url: http://example.com\n")
+	ex, run := newCompletedExperimentWithAnalysis()
+	f := newFixture(t, ex, run)
+	defer f.Close()
+	failing := &fakeExporter{typ: "webhook", err: errors.New("connection refused")}
+	exporter := &fakeExporter{typ: "s3"}
+	c, done := newResultExportController(f, failing, exporter)
+
+	newStatus := ex.Status.DeepCopy()
+	c.reconcileResultExport(ex, newStatus)
+	assert.Equal(t, resultExportRetryPeriod, <-done)
+	assert.Len(t, failing.results, 1)
+	assert.Len(t, exporter.results, 1)
+	events := c.recorder.(*record.FakeEventRecorder).Events()
+	assert.Equal(t, []string{conditions.ExperimentResultExportFailedReason, conditions.ExperimentResultExportedReason}, events)
+
+	// the export is not retried before the retry is due
+	c.reconcileResultExport(ex, newStatus)
+	assert.Nil(t, newStatus.ResultExportedAt)
+	assert.Len(t, failing.results, 1)
+
+	// the retry backs off, and only exports with the failed exporter
+	c.resultExports.entries[ex.Namespace+"/"+ex.Name].retryAt = time.Time{}
+	c.reconcileResultExport(ex, newStatus)
+	assert.Equal(t, 2*resultExportRetryPeriod, <-done)
+	assert.Len(t, failing.results, 2)
+	assert.Len(t, exporter.results, 1)
+
+	failing.err = nil
+	c.resultExports.entries[ex.Namespace+"/"+ex.Name].retryAt = time.Time{}
+	c.reconcileResultExport(ex, newStatus)
+	assert.Equal(t, time.Duration(0), <-done)
+	c.reconcileResultExport(ex, newStatus)
+	assert.NotNil(t, newStatus.ResultExportedAt)
+	assert.Len(t, failing.results, 3)
+	assert.Len(t, exporter.results, 1)
+}
+
+func TestExportResultNotCompleted(t *testing.T) {
+	initializeResultExportConfig(t, "webhook:\n  url: http://example.com\n")
+	ex, run := newCompletedExperimentWithAnalysis()
+	f := newFixture(t, ex, run)
+	defer f.Close()
+	exporter := &fakeExporter{}
+	c, _ := newResultExportController(f, exporter)
+
+	newStatus := ex.Status.DeepCopy()
+	newStatus.Phase = v1alpha1.AnalysisPhaseRunning
+	c.reconcileResultExport(ex, newStatus)
+	assert.Nil(t, newStatus.ResultExportedAt)
+	assert.Empty(t, c.resultExports.entries)
+}
+
+func TestResultExportersReused(t *testing.T) {
+	initializeResultExportConfig(t, "webhook:\n  url: http://example.com\n")
+	f := newFixture(t)
+	defer f.Close()
+	c, _, _ := f.newController(noResyncPeriodFunc)
+	created := 0
+	c.newResultExporters = func(cfg config.ExperimentResultExport) ([]export.Exporter, error) {
+		created++
+		return []export.Exporter{&fakeExporter{}}, nil
+	}
+
+	first, err := c.getResultExporters()
+	assert.NoError(t, err)
+	second, err := c.getResultExporters()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, created)
+	assert.Same(t, first[0], second[0])
+
+	// the exporters are created again once the configuration changes
+	initializeResultExportConfig(t, "webhook:\n  url: http://example.org\n")
+	_, err = c.getResultExporters()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, created)
+}
+
+func TestExportResultNotConfigured(t *testing.T) {
+	initializeResultExportConfig(t, "")
+	ex, run := newCompletedExperimentWithAnalysis()
+	f := newFixture(t, ex, run)
+	defer f.Close()
+	c, _, _ := f.newController(noResyncPeriodFunc)
+
+	c.newResultExporters = func(cfg config.ExperimentResultExport) ([]export.Exporter, error) {
+		t.Fatal("exporters should not be created without a configuration")
+		return nil, nil
+	}
+	newStatus := ex.Status.DeepCopy()
+	c.reconcileResultExport(ex, newStatus)
+
+	assert.Nil(t, newStatus.ResultExportedAt)
+	assert.Empty(t, c.recorder.(*record.FakeEventRecorder).Events())
+}
@@ -0,0 +1,66 @@
+package experiments
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	experimentutil "github.com/argoproj/argo-rollouts/utils/experiment"
+	"github.com/argoproj/argo-rollouts/utils/record"
+	timeutil "github.com/argoproj/argo-rollouts/utils/time"
+)
+
+// reconcileSchedule holds an experiment which did not start yet in the Pending phase until the start
+// scheduled by its startAt and window. Returns true if the experiment waits for its scheduled start.
+func (ec *experimentContext) reconcileSchedule() bool {
+	if experimentutil.HasStarted(ec.ex) || ec.ex.Spec.Terminate || ec.newStatus.Phase.Completed() {
+		return false
+	}
+	if ec.ex.Spec.StartAt == nil && ec.ex.Spec.Window == nil {
+		return false
+	}
+	now := timeutil.Now()
+	startTime, err := experimentutil.GetScheduledStartTime(ec.ex, now)
+	if err != nil {
+		// the window is verified with the spec, so this is not expected to happen
+		ec.log.Warnf("Failed to determine the scheduled start: %v", err)
+		return false
+	}
+	if startTime == nil {
+		return false
+	}
+	message := fmt.Sprintf(conditions.ExperimentScheduledMessage, startTime.UTC().Format(time.RFC3339))
+	if ec.newStatus.Message != message {
+		ec.log.Info(message)
+		ec.recorder.Eventf(ec.ex, record.EventOptions{EventReason: conditions.ExperimentScheduledReason}, message)
+	}
+	ec.newStatus.Phase = v1alpha1.AnalysisPhasePending
+	ec.newStatus.Message = message
+	ec.enqueueExperimentAfter(ec.ex, startTime.Sub(now))
+	return true
+}
+
+// maybeGarbageCollectExperiment deletes a completed experiment once its TTL expired, or enqueues the
+// experiment to be deleted when it expires. Returns true if the experiment was deleted.
+func (ec *Controller) maybeGarbageCollectExperiment(experiment *v1alpha1.Experiment, logCtx *log.Entry) (bool, error) {
+	expiry := experimentutil.GetTTLExpiry(experiment)
+	if expiry == nil {
+		return false, nil
+	}
+	if remaining := expiry.Sub(timeutil.Now()); remaining > 0 {
+		ec.enqueueExperimentAfter(experiment, remaining)
+		return false, nil
+	}
+	logCtx.Info("Deleting experiment with expired TTL")
+	err := ec.argoProjClientset.ArgoprojV1alpha1().Experiments(experiment.Namespace).Delete(context.TODO(), experiment.Name, metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,115 @@
+package experiments
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	logutil "github.com/argoproj/argo-rollouts/utils/log"
+	"github.com/argoproj/argo-rollouts/utils/record"
+	timeutil "github.com/argoproj/argo-rollouts/utils/time"
+)
+
+// TestScheduledExperimentWaits verifies an experiment with a future startAt stays pending without creating its
+// templates, and is requeued for its start
+func TestScheduledExperimentWaits(t *testing.T) {
+	templates := generateTemplates("bar")
+	ex := newExperiment("foo", templates, "")
+	ex.Spec.StartAt = &metav1.Time{Time: timeutil.Now().Add(time.Hour)}
+	exCtx := newTestContext(ex)
+	enqueueCalled := false
+	exCtx.enqueueExperimentAfter = func(obj any, duration time.Duration) {
+		enqueueCalled = true
+		delta := math.Abs(float64(time.Hour - duration))
+		assert.True(t, delta < float64(150*time.Millisecond))
+	}
+	newStatus := exCtx.reconcile()
+	assert.True(t, enqueueCalled)
+	assert.Equal(t, v1alpha1.AnalysisPhasePending, newStatus.Phase)
+	assert.Contains(t, newStatus.Message, "Experiment is scheduled to start at")
+	assert.Empty(t, newStatus.TemplateStatuses)
+	assert.Equal(t, []string{conditions.ExperimentScheduledReason}, exCtx.recorder.(*record.FakeEventRecorder).Events())
+}
+
+// TestScheduledExperimentStarts verifies an experiment starts its templates once its startAt passed
+func TestScheduledExperimentStarts(t *testing.T) {
+	templates := generateTemplates("bar")
+	ex := newExperiment("foo", templates, "")
+	ex.Spec.StartAt = &metav1.Time{Time: timeutil.Now().Add(-time.Minute)}
+	exCtx := newTestContext(ex)
+	newStatus := exCtx.reconcile()
+	assert.Len(t, newStatus.TemplateStatuses, 1)
+	assert.Equal(t, v1alpha1.TemplateStatusProgressing, newStatus.TemplateStatuses[0].Status)
+}
+
+// TestStartedExperimentIgnoresWindow verifies the window only gates the start of an experiment
+func TestStartedExperimentIgnoresWindow(t *testing.T) {
+	templates := generateTemplates("bar")
+	ex := newExperiment("foo", templates, "")
+	ex.Spec.StartAt = &metav1.Time{Time: timeutil.Now().Add(time.Hour)}
+	ex.Status.TemplateStatuses = []v1alpha1.TemplateStatus{
+		generateTemplatesStatus("bar", 1, 1, v1alpha1.TemplateStatusRunning, now()),
+	}
+	exCtx := newTestContext(ex)
+	exCtx.templateRSs = map[string]*appsv1.ReplicaSet{
+		"bar": templateToRS(ex, ex.Spec.Templates[0], 1),
+	}
+	newStatus := exCtx.reconcile()
+	assert.Equal(t, v1alpha1.AnalysisPhaseRunning, newStatus.Phase)
+}
+
+func newCompletedExperiment(completedSecondsAgo int) *v1alpha1.Experiment {
+	ex := newExperiment("foo", generateTemplates("bar"), "")
+	ex.Spec.TTLStrategy = &v1alpha1.TTLStrategy{SecondsAfterCompletion: ptr.To[int32](60)}
+	ex.Status.Phase = v1alpha1.AnalysisPhaseSuccessful
+	ex.Status.CompletedAt = secondsAgo(completedSecondsAgo)
+	return ex
+}
+
+// TestDeleteExperimentWithExpiredTTL verifies a completed experiment is deleted once its TTL expired
+func TestDeleteExperimentWithExpiredTTL(t *testing.T) {
+	ex := newCompletedExperiment(120)
+	f := newFixture(t, ex)
+	defer f.Close()
+
+	f.expectDeleteExperimentAction(ex)
+	f.run(getKey(ex, t))
+}
+
+// TestCompletedAtOfExperimentCompletedBeforeUpgrade verifies the completion of an experiment which completed before
+// status.completedAt was recorded is recorded from its conditions rather than the time it is reconciled
+func TestCompletedAtOfExperimentCompletedBeforeUpgrade(t *testing.T) {
+	completedAt := secondsAgo(3600)
+	ex := newCompletedExperiment(0)
+	ex.Status.CompletedAt = nil
+	ex.Status.Conditions = []v1alpha1.ExperimentCondition{{
+		Type:               v1alpha1.ExperimentProgressing,
+		Status:             corev1.ConditionFalse,
+		Reason:             conditions.ExperimentCompleteReason,
+		LastTransitionTime: *completedAt,
+	}}
+	exCtx := newTestContext(ex)
+	newStatus := exCtx.reconcile()
+	assert.Equal(t, completedAt.Unix(), newStatus.CompletedAt.Unix())
+}
+
+// TestEnqueueExperimentWithTTL verifies a completed experiment is requeued for the expiry of its TTL
+func TestEnqueueExperimentWithTTL(t *testing.T) {
+	ex := newCompletedExperiment(30)
+	f := newFixture(t, ex)
+	defer f.Close()
+
+	c, _, _ := f.newController(noResyncPeriodFunc)
+	deleted, err := c.maybeGarbageCollectExperiment(ex, logutil.WithExperiment(ex))
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+	assert.Equal(t, 1, f.enqueuedObjects[getKey(ex, t)])
+}
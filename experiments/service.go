@@ -12,6 +12,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
 	logutil "github.com/argoproj/argo-rollouts/utils/log"
 )
 
@@ -78,7 +79,7 @@ func (ec *experimentContext) CreateService(serviceName string, template v1alpha1
 		},
 	}
 
-	service, err := ec.kubeclientset.CoreV1().Services(ec.ex.Namespace).Create(ctx, newService, metav1.CreateOptions{})
+	service, err := ec.kubeclientset.CoreV1().Services(ec.ex.Namespace).Create(ctx, newService, metav1.CreateOptions{FieldManager: defaults.DefaultFieldManager})
 	if err != nil {
 		// If service already exists, get service and check that it is owned by Experiment Template. Otherwise return error.
 		if errors.IsAlreadyExists(err) {
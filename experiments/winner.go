@@ -0,0 +1,88 @@
+package experiments
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	analysisutil "github.com/argoproj/argo-rollouts/utils/analysis"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	experimentutil "github.com/argoproj/argo-rollouts/utils/experiment"
+	"github.com/argoproj/argo-rollouts/utils/record"
+)
+
+// reconcileWinner selects the template which won the experiment once it completed successfully. An experiment
+// without a winner is inconclusive.
+func (ec *experimentContext) reconcileWinner() {
+	if ec.ex.Spec.WinnerSelection == nil || ec.newStatus.Phase != v1alpha1.AnalysisPhaseSuccessful || ec.newStatus.Winner != "" {
+		return
+	}
+	winner, err := ec.selectWinner(*ec.ex.Spec.WinnerSelection)
+	if err != nil {
+		msg := fmt.Sprintf(conditions.ExperimentNoWinnerMessage, err)
+		ec.log.Warn(msg)
+		ec.newStatus.Phase = v1alpha1.AnalysisPhaseInconclusive
+		ec.newStatus.Message = msg
+		ec.recorder.Eventf(ec.ex, record.EventOptions{EventType: corev1.EventTypeWarning, EventReason: conditions.ExperimentNoWinnerReason}, msg)
+		return
+	}
+	ec.newStatus.Winner = winner
+	ec.log.Infof("Template '%s' won the experiment", winner)
+	ec.recorder.Eventf(ec.ex, record.EventOptions{EventReason: conditions.ExperimentWinnerSelectedReason}, conditions.ExperimentWinnerSelectedMessage, winner)
+}
+
+// selectWinner returns the candidate whose analysis succeeded and, if a metric ranks the candidates, which has the
+// best last measurement of the metric
+func (ec *experimentContext) selectWinner(selection v1alpha1.ExperimentWinnerSelection) (string, error) {
+	winner := ""
+	var winnerValue float64
+	for _, candidate := range selection.Candidates {
+		runStatus := experimentutil.GetAnalysisRunStatus(*ec.newStatus, candidate.AnalysisName)
+		if runStatus == nil || runStatus.Phase != v1alpha1.AnalysisPhaseSuccessful {
+			continue
+		}
+		if selection.MetricName == "" {
+			return candidate.TemplateName, nil
+		}
+		run, err := ec.analysisRunLister.AnalysisRuns(ec.ex.Namespace).Get(runStatus.AnalysisRun)
+		if err != nil {
+			return "", err
+		}
+		value, err := lastMeasurementValue(run, selection.MetricName)
+		if err != nil {
+			return "", fmt.Errorf("analysis '%s' of template '%s': %w", candidate.AnalysisName, candidate.TemplateName, err)
+		}
+		if winner == "" || isBetterMeasurement(selection.Goal, value, winnerValue) {
+			winner = candidate.TemplateName
+			winnerValue = value
+		}
+	}
+	if winner == "" {
+		return "", fmt.Errorf("the analyses of all candidates were unsuccessful")
+	}
+	return winner, nil
+}
+
+// lastMeasurementValue returns the value of the last measurement of the metric as a number. A value with a single
+// element (e.g. "[0.99]" returned by a query of a vector) is a number as well.
+func lastMeasurementValue(run *v1alpha1.AnalysisRun, metricName string) (float64, error) {
+	measurement := analysisutil.LastMeasurement(run, metricName)
+	if measurement == nil {
+		return 0, fmt.Errorf("metric '%s' has no measurement", metricName)
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(strings.Trim(measurement.Value, "[]")), 64)
+	if err != nil {
+		return 0, fmt.Errorf("measurement '%s' of metric '%s' is not a number", measurement.Value, metricName)
+	}
+	return value, nil
+}
+
+func isBetterMeasurement(goal v1alpha1.WinnerSelectionGoal, value, best float64) bool {
+	if goal == v1alpha1.WinnerSelectionGoalMinimize {
+		return value < best
+	}
+	return value > best
+}
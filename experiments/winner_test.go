@@ -0,0 +1,114 @@
+package experiments
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+)
+
+// newWinnerTestContext returns the context of an experiment whose candidates 'bar' and 'baz' completed their analyses
+// with the given phases and last measurements of the 'score' metric
+func newWinnerTestContext(selection v1alpha1.ExperimentWinnerSelection, barPhase, bazPhase v1alpha1.AnalysisPhase, barScore, bazScore string) *experimentContext {
+	templates := generateTemplates("bar", "baz")
+	ex := newExperiment("foo", templates, "")
+	ex.Spec.Analyses = []v1alpha1.ExperimentAnalysisTemplateRef{
+		{Name: "bar-score", TemplateName: "score", RequiredForCompletion: true},
+		{Name: "baz-score", TemplateName: "score", RequiredForCompletion: true},
+	}
+	selection.Candidates = []v1alpha1.ExperimentWinnerCandidate{
+		{TemplateName: "bar", AnalysisName: "bar-score"},
+		{TemplateName: "baz", AnalysisName: "baz-score"},
+	}
+	ex.Spec.WinnerSelection = &selection
+	ex.Status.Phase = v1alpha1.AnalysisPhaseRunning
+	ex.Status.AvailableAt = secondsAgo(60)
+	ex.Status.TemplateStatuses = []v1alpha1.TemplateStatus{
+		generateTemplatesStatus("bar", 1, 1, v1alpha1.TemplateStatusRunning, now()),
+		generateTemplatesStatus("baz", 1, 1, v1alpha1.TemplateStatusRunning, now()),
+	}
+	barRun := analysisTemplateToRun("bar-score", ex, &v1alpha1.AnalysisTemplateSpec{})
+	barRun.Status.Phase = barPhase
+	barRun.Status.MetricResults = []v1alpha1.MetricResult{{Name: "score", Measurements: []v1alpha1.Measurement{{Value: barScore}}}}
+	bazRun := analysisTemplateToRun("baz-score", ex, &v1alpha1.AnalysisTemplateSpec{})
+	bazRun.Status.Phase = bazPhase
+	bazRun.Status.MetricResults = []v1alpha1.MetricResult{{Name: "score", Measurements: []v1alpha1.Measurement{{Value: bazScore}}}}
+	ex.Status.AnalysisRuns = []v1alpha1.ExperimentAnalysisRunStatus{
+		{Name: "bar-score", AnalysisRun: barRun.Name, Phase: barPhase},
+		{Name: "baz-score", AnalysisRun: bazRun.Name, Phase: bazPhase},
+	}
+
+	rs1 := templateToRS(ex, ex.Spec.Templates[0], 1)
+	rs2 := templateToRS(ex, ex.Spec.Templates[1], 1)
+	exCtx := newTestContext(ex, rs1, rs2, barRun, bazRun)
+	exCtx.templateRSs = map[string]*appsv1.ReplicaSet{
+		"bar": rs1,
+		"baz": rs2,
+	}
+	return exCtx
+}
+
+func TestSelectFirstSuccessfulCandidate(t *testing.T) {
+	exCtx := newWinnerTestContext(v1alpha1.ExperimentWinnerSelection{}, v1alpha1.AnalysisPhaseFailed, v1alpha1.AnalysisPhaseSuccessful, "", "")
+	newStatus := exCtx.reconcile()
+	// the failed analysis of a candidate does not fail the experiment
+	assert.Equal(t, v1alpha1.AnalysisPhaseSuccessful, newStatus.Phase)
+	assert.Equal(t, "baz", newStatus.Winner)
+}
+
+func TestSelectWinnerByMetric(t *testing.T) {
+	tests := []struct {
+		goal     v1alpha1.WinnerSelectionGoal
+		expected string
+	}{
+		{goal: "", expected: "baz"},
+		{goal: v1alpha1.WinnerSelectionGoalMaximize, expected: "baz"},
+		{goal: v1alpha1.WinnerSelectionGoalMinimize, expected: "bar"},
+	}
+	for _, test := range tests {
+		t.Run(string(test.goal), func(t *testing.T) {
+			selection := v1alpha1.ExperimentWinnerSelection{MetricName: "score", Goal: test.goal}
+			exCtx := newWinnerTestContext(selection, v1alpha1.AnalysisPhaseSuccessful, v1alpha1.AnalysisPhaseSuccessful, "0.95", "[0.99]")
+			newStatus := exCtx.reconcile()
+			assert.Equal(t, v1alpha1.AnalysisPhaseSuccessful, newStatus.Phase)
+			assert.Equal(t, test.expected, newStatus.Winner)
+		})
+	}
+}
+
+func TestUnsuccessfulCandidateDoesNotWin(t *testing.T) {
+	selection := v1alpha1.ExperimentWinnerSelection{MetricName: "score"}
+	exCtx := newWinnerTestContext(selection, v1alpha1.AnalysisPhaseSuccessful, v1alpha1.AnalysisPhaseInconclusive, "0.95", "0.99")
+	newStatus := exCtx.reconcile()
+	assert.Equal(t, v1alpha1.AnalysisPhaseSuccessful, newStatus.Phase)
+	assert.Equal(t, "bar", newStatus.Winner)
+}
+
+func TestNoWinner(t *testing.T) {
+	exCtx := newWinnerTestContext(v1alpha1.ExperimentWinnerSelection{}, v1alpha1.AnalysisPhaseFailed, v1alpha1.AnalysisPhaseError, "", "")
+	newStatus := exCtx.reconcile()
+	assert.Equal(t, v1alpha1.AnalysisPhaseInconclusive, newStatus.Phase)
+	assert.Equal(t, "No template won the experiment: the analyses of all candidates were unsuccessful", newStatus.Message)
+	assert.Empty(t, newStatus.Winner)
+}
+
+func TestNoWinnerWithInvalidMeasurement(t *testing.T) {
+	selection := v1alpha1.ExperimentWinnerSelection{MetricName: "score"}
+	exCtx := newWinnerTestContext(selection, v1alpha1.AnalysisPhaseSuccessful, v1alpha1.AnalysisPhaseSuccessful, "0.95", "[0.99,0.98]")
+	newStatus := exCtx.reconcile()
+	assert.Equal(t, v1alpha1.AnalysisPhaseInconclusive, newStatus.Phase)
+	assert.Equal(t, "No template won the experiment: analysis 'baz-score' of template 'baz': measurement '[0.99,0.98]' of metric 'score' is not a number", newStatus.Message)
+}
+
+func TestWinnerIsNotSelectedAgain(t *testing.T) {
+	exCtx := newWinnerTestContext(v1alpha1.ExperimentWinnerSelection{}, v1alpha1.AnalysisPhaseSuccessful, v1alpha1.AnalysisPhaseSuccessful, "", "")
+	exCtx.ex.Status.Phase = v1alpha1.AnalysisPhaseSuccessful
+	exCtx.ex.Status.Winner = "baz"
+	exCtx.newStatus = exCtx.ex.Status.DeepCopy()
+	newStatus := exCtx.reconcile()
+	assert.Equal(t, "baz", newStatus.Winner)
+	assert.NotEqual(t, conditions.ExperimentNoWinnerReason, newStatus.Message)
+}
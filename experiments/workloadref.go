@@ -0,0 +1,122 @@
+package experiments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/yaml"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+var (
+	deploymentGroupKind = schema.GroupKind{Group: "apps", Kind: "Deployment"}
+	rolloutGroupKind    = schema.GroupKind{Group: rollouts.Group, Kind: rollouts.RolloutKind}
+)
+
+// workloadRefRetryPeriod is the period the workload of a template is fetched again with, when it cannot be fetched
+const workloadRefRetryPeriod = 10 * time.Second
+
+// workloadUnavailableError is returned when the workload of a template cannot be fetched, e.g. because it is not
+// created yet. Fetching the workload is retried until the template exceeds its progress deadline.
+type workloadUnavailableError struct {
+	err error
+}
+
+func (e workloadUnavailableError) Error() string {
+	return e.err.Error()
+}
+
+func (e workloadUnavailableError) Unwrap() error {
+	return e.err
+}
+
+// resolveWorkloadRef returns the template with the pod template of the workload it references, patched with the
+// patch of the reference. The pods of the template must not be selected by the selector of the workload, since the
+// Services of the workload would then send its traffic to the pods of the experiment.
+func (ec *experimentContext) resolveWorkloadRef(template v1alpha1.TemplateSpec) (v1alpha1.TemplateSpec, error) {
+	ref := template.WorkloadRef
+	podTemplate, workloadSelector, err := ec.getWorkloadPodTemplate(ref.APIVersion, ref.Kind, ref.Name)
+	if err != nil {
+		return template, err
+	}
+	if ref.Patch != "" {
+		podTemplate, err = patchPodTemplate(podTemplate, ref.Patch)
+		if err != nil {
+			return template, err
+		}
+	}
+	if workloadSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(workloadSelector)
+		if err != nil {
+			return template, err
+		}
+		if selector.Matches(labels.Set(podTemplate.Labels)) {
+			return template, fmt.Errorf("the pods would be selected by %s '%s' and its Services, give them distinct labels through the patch", ref.Kind, ref.Name)
+		}
+	}
+	template.Template = podTemplate
+	return template, nil
+}
+
+// getWorkloadPodTemplate returns the pod template and the selector of a Deployment or Rollout. The pod template of a
+// Rollout which references a Deployment is the pod template of the Deployment.
+func (ec *experimentContext) getWorkloadPodTemplate(apiVersion, kind, name string) (corev1.PodTemplateSpec, *metav1.LabelSelector, error) {
+	ctx := context.TODO()
+	gvk := schema.FromAPIVersionAndKind(apiVersion, kind)
+	switch gvk.GroupKind() {
+	case deploymentGroupKind:
+		deployment, err := ec.kubeclientset.AppsV1().Deployments(ec.ex.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return corev1.PodTemplateSpec{}, nil, workloadUnavailableError{err}
+		}
+		return deployment.Spec.Template, deployment.Spec.Selector, nil
+	case rolloutGroupKind:
+		ro, err := ec.argoProjClientset.ArgoprojV1alpha1().Rollouts(ec.ex.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return corev1.PodTemplateSpec{}, nil, workloadUnavailableError{err}
+		}
+		if ro.Spec.WorkloadRef == nil {
+			return ro.Spec.Template, ro.Spec.Selector, nil
+		}
+		refGVK := schema.FromAPIVersionAndKind(ro.Spec.WorkloadRef.APIVersion, ro.Spec.WorkloadRef.Kind)
+		if refGVK.GroupKind() != deploymentGroupKind {
+			return corev1.PodTemplateSpec{}, nil, fmt.Errorf("rollout '%s' references a workload of type %s/%s, which is not supported", name, refGVK.Group, refGVK.Kind)
+		}
+		podTemplate, selector, err := ec.getWorkloadPodTemplate(ro.Spec.WorkloadRef.APIVersion, ro.Spec.WorkloadRef.Kind, ro.Spec.WorkloadRef.Name)
+		if ro.Spec.Selector != nil {
+			selector = ro.Spec.Selector
+		}
+		return podTemplate, selector, err
+	}
+	return corev1.PodTemplateSpec{}, nil, fmt.Errorf("workload of type %s/%s is not supported", gvk.Group, gvk.Kind)
+}
+
+// patchPodTemplate applies a strategic merge patch (in YAML or JSON) to a pod template
+func patchPodTemplate(template corev1.PodTemplateSpec, patch string) (corev1.PodTemplateSpec, error) {
+	patchJSON, err := yaml.YAMLToJSON([]byte(patch))
+	if err != nil {
+		return template, fmt.Errorf("failed to parse workloadRef patch: %w", err)
+	}
+	original, err := json.Marshal(template)
+	if err != nil {
+		return template, err
+	}
+	patched, err := strategicpatch.StrategicMergePatch(original, patchJSON, corev1.PodTemplateSpec{})
+	if err != nil {
+		return template, fmt.Errorf("failed to apply workloadRef patch: %w", err)
+	}
+	var patchedTemplate corev1.PodTemplateSpec
+	if err := json.Unmarshal(patched, &patchedTemplate); err != nil {
+		return template, fmt.Errorf("failed to apply workloadRef patch: %w", err)
+	}
+	return patchedTemplate, nil
+}
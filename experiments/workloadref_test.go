@@ -0,0 +1,226 @@
+package experiments
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	timeutil "github.com/argoproj/argo-rollouts/utils/time"
+)
+
+func newWorkloadPodTemplate(image string) corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"app": "guestbook"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "guestbook", Image: image}},
+		},
+	}
+}
+
+func newDeployment(name string, image string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "guestbook"}},
+			Template: newWorkloadPodTemplate(image),
+		},
+	}
+}
+
+// experimentLabelsPatch gives the pods of the experiment labels which the workloads do not select
+const experimentLabelsPatch = `
+metadata:
+  labels:
+    app: guestbook-experiment`
+
+func newWorkloadRefTemplate(kind, name, patch string) v1alpha1.TemplateSpec {
+	apiVersion := "apps/v1"
+	if kind == "Rollout" {
+		apiVersion = "argoproj.io/v1alpha1"
+	}
+	return v1alpha1.TemplateSpec{
+		Name:     "canary",
+		Replicas: ptr.To[int32](1),
+		Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "guestbook-experiment"}},
+		WorkloadRef: &v1alpha1.ExperimentWorkloadRef{
+			APIVersion: apiVersion,
+			Kind:       kind,
+			Name:       name,
+			Patch:      patch,
+		},
+	}
+}
+
+func TestResolveWorkloadRef(t *testing.T) {
+	ro := &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{Name: "guestbook-rollout", Namespace: metav1.NamespaceDefault},
+		Spec: v1alpha1.RolloutSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "guestbook", "tier": "rollout"}},
+			Template: newWorkloadPodTemplate("guestbook:rollout"),
+		},
+	}
+	refRollout := &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{Name: "guestbook-ref", Namespace: metav1.NamespaceDefault},
+		Spec: v1alpha1.RolloutSpec{
+			WorkloadRef: &v1alpha1.ObjectRef{APIVersion: "apps/v1", Kind: "Deployment", Name: "guestbook"},
+		},
+	}
+	unsupportedRefRollout := &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{Name: "guestbook-podtemplate", Namespace: metav1.NamespaceDefault},
+		Spec: v1alpha1.RolloutSpec{
+			WorkloadRef: &v1alpha1.ObjectRef{APIVersion: "v1", Kind: "PodTemplate", Name: "guestbook"},
+		},
+	}
+	ex := newExperiment("foo", nil, "")
+	exCtx := newTestContext(ex, newDeployment("guestbook", "guestbook:v1"), ro, refRollout, unsupportedRefRollout)
+
+	experimentPodTemplate := func(image string) corev1.PodTemplateSpec {
+		template := newWorkloadPodTemplate(image)
+		template.Labels = map[string]string{"app": "guestbook-experiment"}
+		return template
+	}
+
+	t.Run("Deployment", func(t *testing.T) {
+		template, err := exCtx.resolveWorkloadRef(newWorkloadRefTemplate("Deployment", "guestbook", experimentLabelsPatch))
+		assert.NoError(t, err)
+		assert.Equal(t, experimentPodTemplate("guestbook:v1"), template.Template)
+		// the selector of the template is kept
+		assert.Equal(t, map[string]string{"app": "guestbook-experiment"}, template.Selector.MatchLabels)
+	})
+
+	t.Run("Deployment with patch", func(t *testing.T) {
+		workloadRefTemplate := newWorkloadRefTemplate("Deployment", "guestbook", `
+metadata:
+  labels:
+    app: guestbook-experiment
+    track: experiment
+spec:
+  containers:
+  - name: guestbook
+    image: guestbook:v2`)
+		template, err := exCtx.resolveWorkloadRef(workloadRefTemplate)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"app": "guestbook-experiment", "track": "experiment"}, template.Template.Labels)
+		assert.Equal(t, "guestbook:v2", template.Template.Spec.Containers[0].Image)
+	})
+
+	t.Run("Deployment selecting the pods", func(t *testing.T) {
+		_, err := exCtx.resolveWorkloadRef(newWorkloadRefTemplate("Deployment", "guestbook", `
+metadata:
+  labels:
+    track: experiment`))
+		assert.EqualError(t, err, "the pods would be selected by Deployment 'guestbook' and its Services, give them distinct labels through the patch")
+	})
+
+	t.Run("Rollout", func(t *testing.T) {
+		template, err := exCtx.resolveWorkloadRef(newWorkloadRefTemplate("Rollout", "guestbook-rollout", experimentLabelsPatch))
+		assert.NoError(t, err)
+		assert.Equal(t, experimentPodTemplate("guestbook:rollout"), template.Template)
+	})
+
+	t.Run("Rollout selecting the pods", func(t *testing.T) {
+		_, err := exCtx.resolveWorkloadRef(newWorkloadRefTemplate("Rollout", "guestbook-rollout", `
+metadata:
+  labels:
+    tier: rollout`))
+		assert.EqualError(t, err, "the pods would be selected by Rollout 'guestbook-rollout' and its Services, give them distinct labels through the patch")
+	})
+
+	t.Run("Rollout with a Deployment workloadRef", func(t *testing.T) {
+		template, err := exCtx.resolveWorkloadRef(newWorkloadRefTemplate("Rollout", "guestbook-ref", experimentLabelsPatch))
+		assert.NoError(t, err)
+		assert.Equal(t, experimentPodTemplate("guestbook:v1"), template.Template)
+	})
+
+	t.Run("Rollout with an unsupported workloadRef", func(t *testing.T) {
+		_, err := exCtx.resolveWorkloadRef(newWorkloadRefTemplate("Rollout", "guestbook-podtemplate", ""))
+		assert.EqualError(t, err, "rollout 'guestbook-podtemplate' references a workload of type /PodTemplate, which is not supported")
+	})
+
+	t.Run("unsupported kind", func(t *testing.T) {
+		_, err := exCtx.resolveWorkloadRef(newWorkloadRefTemplate("StatefulSet", "guestbook", ""))
+		assert.EqualError(t, err, "workload of type apps/StatefulSet is not supported")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := exCtx.resolveWorkloadRef(newWorkloadRefTemplate("Deployment", "doesnotexist", ""))
+		assert.EqualError(t, err, `deployments.apps "doesnotexist" not found`)
+		assert.ErrorAs(t, err, &workloadUnavailableError{})
+	})
+
+	t.Run("invalid patch", func(t *testing.T) {
+		_, err := exCtx.resolveWorkloadRef(newWorkloadRefTemplate("Deployment", "guestbook", "spec: [invalid"))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse workloadRef patch")
+	})
+}
+
+func TestCreateReplicaSetFromWorkloadRef(t *testing.T) {
+	ex := newExperiment("foo", []v1alpha1.TemplateSpec{newWorkloadRefTemplate("Deployment", "guestbook", experimentLabelsPatch)}, "")
+	exCtx := newTestContext(ex, newDeployment("guestbook", "guestbook:v1"))
+	exCtx.reconcile()
+
+	rs, err := exCtx.kubeclientset.AppsV1().ReplicaSets(metav1.NamespaceDefault).Get(context.TODO(), "foo-canary", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "guestbook:v1", rs.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, "guestbook-experiment", rs.Spec.Selector.MatchLabels["app"])
+	assert.Equal(t, rs.Labels[v1alpha1.DefaultRolloutUniqueLabelKey], rs.Spec.Selector.MatchLabels[v1alpha1.DefaultRolloutUniqueLabelKey])
+}
+
+func TestCreateReplicaSetFromMissingWorkloadRef(t *testing.T) {
+	ex := newExperiment("foo", []v1alpha1.TemplateSpec{newWorkloadRefTemplate("Deployment", "doesnotexist", experimentLabelsPatch)}, "")
+	exCtx := newTestContext(ex)
+	var enqueued []time.Duration
+	exCtx.enqueueExperimentAfter = func(obj any, duration time.Duration) {
+		enqueued = append(enqueued, duration)
+	}
+	newStatus := exCtx.reconcile()
+
+	// the workload is fetched again until the template exceeds its progress deadline
+	assert.Equal(t, v1alpha1.TemplateStatusProgressing, newStatus.TemplateStatuses[0].Status)
+	assert.Equal(t, `Waiting for the workloadRef of template 'canary': deployments.apps "doesnotexist" not found`, newStatus.TemplateStatuses[0].Message)
+	assert.Contains(t, enqueued, workloadRefRetryPeriod)
+
+	ex.Status = *newStatus
+	exCtx = newTestContext(ex, newDeployment("doesnotexist", "guestbook:v1"))
+	newStatus = exCtx.reconcile()
+	assert.Equal(t, v1alpha1.TemplateStatusProgressing, newStatus.TemplateStatuses[0].Status)
+	assert.Empty(t, newStatus.TemplateStatuses[0].Message)
+	_, err := exCtx.kubeclientset.AppsV1().ReplicaSets(metav1.NamespaceDefault).Get(context.TODO(), "foo-canary", metav1.GetOptions{})
+	assert.NoError(t, err)
+}
+
+func TestCreateReplicaSetFromWorkloadRefPastDeadline(t *testing.T) {
+	ex := newExperiment("foo", []v1alpha1.TemplateSpec{newWorkloadRefTemplate("Deployment", "doesnotexist", experimentLabelsPatch)}, "")
+	past := metav1.NewTime(timeutil.Now().Add(-time.Hour))
+	ex.Status.TemplateStatuses = []v1alpha1.TemplateStatus{{
+		Name:               "canary",
+		Status:             v1alpha1.TemplateStatusProgressing,
+		LastTransitionTime: &past,
+	}}
+	exCtx := newTestContext(ex)
+	newStatus := exCtx.reconcile()
+
+	assert.Equal(t, v1alpha1.TemplateStatusFailed, newStatus.TemplateStatuses[0].Status)
+}
+
+func TestCreateReplicaSetFromWorkloadRefSelectingThePods(t *testing.T) {
+	ex := newExperiment("foo", []v1alpha1.TemplateSpec{newWorkloadRefTemplate("Deployment", "guestbook", "")}, "")
+	exCtx := newTestContext(ex, newDeployment("guestbook", "guestbook:v1"))
+	newStatus := exCtx.reconcile()
+
+	assert.Equal(t, v1alpha1.TemplateStatusError, newStatus.TemplateStatuses[0].Status)
+	assert.Equal(t, "Failed to resolve workloadRef of template 'canary': the pods would be selected by Deployment 'guestbook' and its Services, give them distinct labels through the patch", newStatus.TemplateStatuses[0].Message)
+}
@@ -36,11 +36,13 @@ var preserveUnknownFields = map[string]any{
 }
 
 var crdPaths = map[string]string{
-	"Rollout":                 "manifests/crds/rollout-crd.yaml",
-	"Experiment":              "manifests/crds/experiment-crd.yaml",
-	"AnalysisTemplate":        "manifests/crds/analysis-template-crd.yaml",
-	"ClusterAnalysisTemplate": "manifests/crds/cluster-analysis-template-crd.yaml",
-	"AnalysisRun":             "manifests/crds/analysis-run-crd.yaml",
+	"Rollout":                   "manifests/crds/rollout-crd.yaml",
+	"Experiment":                "manifests/crds/experiment-crd.yaml",
+	"AnalysisTemplate":          "manifests/crds/analysis-template-crd.yaml",
+	"ClusterAnalysisTemplate":   "manifests/crds/cluster-analysis-template-crd.yaml",
+	"AnalysisRun":               "manifests/crds/analysis-run-crd.yaml",
+	"RolloutNotificationPolicy": "manifests/crds/rollout-notification-policy-crd.yaml",
+	"RolloutControllerConfig":   "manifests/crds/rollout-controller-config-crd.yaml",
 }
 
 func setValidationOverride(un *unstructured.Unstructured, fieldOverride map[string]any, path string) {
@@ -104,6 +106,8 @@ func NewCustomResourceDefinition() []*extensionsobj.CustomResourceDefinition {
 	deleteFile("config/crd/argoproj.io_clusteranalysistemplates.yaml")
 	deleteFile("config/crd/argoproj.io_experiments.yaml")
 	deleteFile("config/crd/argoproj.io_rollouts.yaml")
+	deleteFile("config/crd/argoproj.io_rolloutnotificationpolicies.yaml")
+	deleteFile("config/crd/argoproj.io_rolloutcontrollerconfigs.yaml")
 	deleteFile("config/crd")
 	deleteFile("config")
 
@@ -219,6 +223,8 @@ func createMetadataValidation(un *unstructured.Unstructured) {
 			analysisJobValidated = append(analysisJobValidated, v)
 		}
 		unstructured.SetNestedSlice(un.Object, analysisJobValidated, prePath...)
+	case "RolloutNotificationPolicy", "RolloutControllerConfig":
+		// a policy and a config have no pod templates
 	default:
 		panic(fmt.Sprintf("unknown kind: %s", kind))
 	}
@@ -237,6 +243,13 @@ func removeK8S118Fields(un *unstructured.Unstructured) {
 		// Replace this with "spec.template.spec.volumes[].ephemeral.volumeClaimTemplate.spec.resources.{limits/requests}"
 		// when it's ok to only support k8s 1.17+
 		setValidationOverride(un, preserveUnknownFields, "spec.template.spec.volumes")
+		// the pod template of a load test Job is validated by the API server when the Job is created
+		setValidationOverride(un, preserveUnknownFields, "spec.strategy.canary.steps[].loadTest.jobSpec.template")
+		// the pod templates of migration and rollback Jobs are validated by the API server when the Jobs are created
+		setValidationOverride(un, preserveUnknownFields, "spec.strategy.canary.steps[].migration.jobSpec.template")
+		setValidationOverride(un, preserveUnknownFields, "spec.strategy.canary.steps[].migration.rollbackJobSpec.template")
+		// the pod template of a smoke test Job is validated by the API server when the Job is created
+		setValidationOverride(un, preserveUnknownFields, "spec.strategy.blueGreen.postPromotionSmokeTest.job.spec.template")
 	case "Experiment":
 		setValidationOverride(un, preserveUnknownFields, "spec.templates[].template.spec.containers[].resources.limits")
 		setValidationOverride(un, preserveUnknownFields, "spec.templates[].template.spec.containers[].resources.requests")
@@ -257,6 +270,7 @@ func removeK8S118Fields(un *unstructured.Unstructured) {
 		// Replace this with "spec.metrics[].provider.job.spec.template.spec.volumes[].ephemeral.volumeClaimTemplate.spec.resources.{limits/requests}"
 		// when it's ok to only support k8s 1.17+
 		setValidationOverride(un, preserveUnknownFields, "spec.metrics[].provider.job.spec.template.spec.volumes")
+	case "RolloutNotificationPolicy", "RolloutControllerConfig":
 	default:
 		panic(fmt.Sprintf("unknown kind: %s", kind))
 	}
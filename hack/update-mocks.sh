@@ -29,5 +29,5 @@ mockery \
 
 mockery \
     --dir "${PROJECT_ROOT}"/rollout/steps/plugin/rpc \
-    --name "StepPlugin" \
+    --name "StepPlugin|StepPluginClient" \
     --output "${PROJECT_ROOT}"/rollout/steps/plugin/rpc/mocks
@@ -33,7 +33,8 @@ func (c *Controller) syncALBIngress(ingress *ingressutil.Ingress, rollouts []*v1
 	newIngress := ingress.DeepCopy()
 	modified := false
 	for roName := range managedActions {
-		if _, ok := actionHasExistingRollout[roName]; !ok {
+		// the actions of a rollout are only reset by the shard of that rollout
+		if _, ok := actionHasExistingRollout[roName]; !ok && c.sharder.OwnsRollout(ingress.GetNamespace(), roName) {
 			modified = true
 			actionKeys := managedActions[roName]
 			delete(managedActions, roName)
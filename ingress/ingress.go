@@ -21,6 +21,7 @@ import (
 	controllerutil "github.com/argoproj/argo-rollouts/utils/controller"
 	ingressutil "github.com/argoproj/argo-rollouts/utils/ingress"
 	logutil "github.com/argoproj/argo-rollouts/utils/log"
+	"github.com/argoproj/argo-rollouts/utils/sharding"
 	unstructuredutil "github.com/argoproj/argo-rollouts/utils/unstructured"
 )
 
@@ -41,6 +42,9 @@ type ControllerConfig struct {
 	MetricsServer *metrics.MetricsServer
 	ALBClasses    []string
 	NGINXClasses  []string
+	// Sharder selects the rollouts whose ALB actions are cleaned up by this controller replica when running
+	// in sharding mode
+	Sharder *sharding.Sharder
 }
 
 // Controller describes an ingress controller
@@ -54,6 +58,7 @@ type Controller struct {
 	enqueueRollout func(obj any)
 	albClasses     []string
 	nginxClasses   []string
+	sharder        *sharding.Sharder
 }
 
 type IngressWrapper interface {
@@ -73,6 +78,7 @@ func NewController(cfg ControllerConfig) *Controller {
 		metricServer:     cfg.MetricsServer,
 		albClasses:       cfg.ALBClasses,
 		nginxClasses:     cfg.NGINXClasses,
+		sharder:          cfg.Sharder,
 	}
 
 	kubectlutil.CheckErr(cfg.RolloutsInformer.Informer().AddIndexers(cache.Indexers{
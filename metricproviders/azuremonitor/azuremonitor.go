@@ -0,0 +1,246 @@
+package azuremonitor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/evaluate"
+	metricutil "github.com/argoproj/argo-rollouts/utils/metric"
+	timeutil "github.com/argoproj/argo-rollouts/utils/time"
+)
+
+const (
+	//ProviderType indicates the provider is Azure Monitor
+	ProviderType = "AzureMonitor"
+
+	// resourceManagerEndpoint is the endpoint of the Azure Resource Manager API which serves the metrics
+	resourceManagerEndpoint = "https://management.azure.com"
+	// resourceManagerScope is the scope of the tokens of the Azure Resource Manager API
+	resourceManagerScope = "https://management.azure.com/.default"
+	// metricsAPIVersion is the version of the metrics API of Azure Monitor
+	metricsAPIVersion = "2023-10-01"
+	// queryTimeout limits the time a query takes
+	queryTimeout = 30 * time.Second
+
+	defaultAggregation = "Average"
+	defaultInterval    = 5 * time.Minute
+)
+
+var (
+	// credential is the credential of the controller, shared by the clients so that its tokens are cached
+	credential     azcore.TokenCredential
+	credentialLock sync.Mutex
+)
+
+// AzureMonitorClientAPI queries the metrics of Azure Monitor
+type AzureMonitorClientAPI interface {
+	// Query returns the latest value of each time series of the metric aggregated over the interval
+	Query(metric *v1alpha1.AzureMonitorMetric, interval time.Duration) ([]float64, error)
+}
+
+type AzureMonitorClient struct {
+	credential azcore.TokenCredential
+	endpoint   string
+	client     *http.Client
+}
+
+// metricsResponse is the response of the metrics API, see
+// https://learn.microsoft.com/en-us/rest/api/monitor/metrics/list
+type metricsResponse struct {
+	Value []struct {
+		Timeseries []struct {
+			Data []map[string]any `json:"data"`
+		} `json:"timeseries"`
+	} `json:"value"`
+}
+
+// errorResponse is the response of the Azure Resource Manager API to a failed request
+type errorResponse struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (c *AzureMonitorClient) Query(metric *v1alpha1.AzureMonitorMetric, interval time.Duration) ([]float64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+	token, err := c.credential.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{resourceManagerScope}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get a token of the Azure Resource Manager API: %w", err)
+	}
+
+	aggregation := metric.Aggregation
+	if aggregation == "" {
+		aggregation = defaultAggregation
+	}
+	endTime := timeutil.Now().UTC()
+	startTime := endTime.Add(-interval)
+	params := url.Values{}
+	params.Set("api-version", metricsAPIVersion)
+	params.Set("metricnames", metric.MetricName)
+	params.Set("aggregation", aggregation)
+	params.Set("timespan", startTime.Format(time.RFC3339)+"/"+endTime.Format(time.RFC3339))
+	// a single data point aggregates the whole time span
+	params.Set("interval", "FULL")
+	if metric.MetricNamespace != "" {
+		params.Set("metricnamespace", metric.MetricNamespace)
+	}
+	if metric.Filter != "" {
+		params.Set("$filter", metric.Filter)
+	}
+	u := c.endpoint + "/" + strings.TrimPrefix(metric.ResourceID, "/") + "/providers/Microsoft.Insights/metrics?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token.Token)
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var errResp errorResponse
+		if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error.Message != "" {
+			return nil, fmt.Errorf("failed to query the metric %s: %s: %s", metric.MetricName, errResp.Error.Code, errResp.Error.Message)
+		}
+		return nil, fmt.Errorf("failed to query the metric %s: received status code %d", metric.MetricName, resp.StatusCode)
+	}
+
+	var metricsResp metricsResponse
+	if err := json.Unmarshal(body, &metricsResp); err != nil {
+		return nil, fmt.Errorf("failed to parse the metrics of Azure Monitor: %w", err)
+	}
+	// the data points name their value after the aggregation in camel case, e.g. average
+	key := strings.ToLower(aggregation[:1]) + aggregation[1:]
+	values := []float64{}
+	for _, m := range metricsResp.Value {
+		for _, series := range m.Timeseries {
+			for i := len(series.Data) - 1; i >= 0; i-- {
+				if value, ok := series.Data[i][key].(float64); ok {
+					values = append(values, value)
+					break
+				}
+			}
+		}
+	}
+	return values, nil
+}
+
+// Provider contains all the required components to run an Azure Monitor query
+// Implements the Provider Interface
+type Provider struct {
+	api    AzureMonitorClientAPI
+	logCtx log.Entry
+}
+
+func (p *Provider) Type() string {
+	return ProviderType
+}
+
+// GetMetadata returns any additional metadata which needs to be stored & displayed as part of the metrics result.
+func (p *Provider) GetMetadata(metric v1alpha1.Metric) map[string]string {
+	return nil
+}
+
+// Run queries Azure Monitor for the metric
+func (p *Provider) Run(run *v1alpha1.AnalysisRun, metric v1alpha1.Metric) v1alpha1.Measurement {
+	startTime := timeutil.MetaNow()
+	measurement := v1alpha1.Measurement{
+		StartedAt: &startTime,
+	}
+
+	interval := defaultInterval
+	if metric.Provider.AzureMonitor.Interval != "" {
+		d, err := metric.Provider.AzureMonitor.Interval.Duration()
+		if err != nil {
+			return metricutil.MarkMeasurementError(measurement, err)
+		}
+		interval = d
+	}
+
+	values, err := p.api.Query(metric.Provider.AzureMonitor, interval)
+	if err != nil {
+		return metricutil.MarkMeasurementError(measurement, err)
+	}
+	if len(values) == 0 {
+		return metricutil.MarkMeasurementError(measurement, fmt.Errorf("no data points for the metric %s", metric.Provider.AzureMonitor.MetricName))
+	}
+
+	// a metric with a single time series is evaluated as a scalar, others as the list of their values
+	var result any = values
+	if len(values) == 1 {
+		result = values[0]
+	}
+	measurement.Value = fmt.Sprintf("%v", result)
+
+	status, err := evaluate.EvaluateResult(result, metric, p.logCtx)
+	if err != nil {
+		return metricutil.MarkMeasurementError(measurement, err)
+	}
+
+	measurement.Phase = status
+	finishedTime := timeutil.MetaNow()
+	measurement.FinishedAt = &finishedTime
+
+	return measurement
+}
+
+func (p *Provider) Resume(run *v1alpha1.AnalysisRun, metric v1alpha1.Metric, measurement v1alpha1.Measurement) v1alpha1.Measurement {
+	return measurement
+}
+
+func (p *Provider) Terminate(run *v1alpha1.AnalysisRun, metric v1alpha1.Metric, measurement v1alpha1.Measurement) v1alpha1.Measurement {
+	return measurement
+}
+
+func (p *Provider) GarbageCollect(run *v1alpha1.AnalysisRun, metric v1alpha1.Metric, i int) error {
+	return nil
+}
+
+// NewAzureMonitorProvider creates a new Azure Monitor provider
+func NewAzureMonitorProvider(api AzureMonitorClientAPI, logCtx log.Entry) *Provider {
+	return &Provider{
+		api:    api,
+		logCtx: logCtx,
+	}
+}
+
+// NewAzureMonitorAPIClient returns the client of Azure Monitor with the credentials of the controller, such as Azure
+// Workload Identity, managed identities or the AZURE_CLIENT_ID, AZURE_TENANT_ID and AZURE_CLIENT_SECRET environment
+// variables
+func NewAzureMonitorAPIClient() (AzureMonitorClientAPI, error) {
+	credentialLock.Lock()
+	defer credentialLock.Unlock()
+	if credential == nil {
+		c, err := azidentity.NewDefaultAzureCredential(nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the Azure credentials of the controller: %w", err)
+		}
+		credential = c
+	}
+	return &AzureMonitorClient{
+		credential: credential,
+		endpoint:   resourceManagerEndpoint,
+		client:     &http.Client{},
+	}, nil
+}
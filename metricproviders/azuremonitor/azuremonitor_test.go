@@ -0,0 +1,131 @@
+package azuremonitor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+type mockAPI struct {
+	values   []float64
+	err      error
+	interval time.Duration
+}
+
+func (m *mockAPI) Query(metric *v1alpha1.AzureMonitorMetric, interval time.Duration) ([]float64, error) {
+	m.interval = interval
+	return m.values, m.err
+}
+
+type fakeCredential struct{}
+
+func (fakeCredential) GetToken(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	if len(options.Scopes) != 1 || options.Scopes[0] != resourceManagerScope {
+		return azcore.AccessToken{}, fmt.Errorf("unexpected scopes %v", options.Scopes)
+	}
+	return azcore.AccessToken{Token: "token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+func newMetric(successCondition string) v1alpha1.Metric {
+	return v1alpha1.Metric{
+		Name:             "foo",
+		SuccessCondition: successCondition,
+		Provider: v1alpha1.MetricProvider{
+			AzureMonitor: &v1alpha1.AzureMonitorMetric{
+				ResourceID: "/subscriptions/sub/resourceGroups/group/providers/Microsoft.Network/applicationGateways/gateway",
+				MetricName: "FailedRequests",
+			},
+		},
+	}
+}
+
+func TestType(t *testing.T) {
+	p := NewAzureMonitorProvider(&mockAPI{}, log.Entry{})
+	assert.Equal(t, ProviderType, p.Type())
+}
+
+func TestRun(t *testing.T) {
+	t.Run("single time series", func(t *testing.T) {
+		api := &mockAPI{values: []float64{0.5}}
+		p := NewAzureMonitorProvider(api, log.Entry{})
+		measurement := p.Run(&v1alpha1.AnalysisRun{}, newMetric("result < 1"))
+		assert.Equal(t, v1alpha1.AnalysisPhaseSuccessful, measurement.Phase)
+		assert.Equal(t, "0.5", measurement.Value)
+		assert.NotNil(t, measurement.FinishedAt)
+		assert.Equal(t, defaultInterval, api.interval)
+	})
+	t.Run("multiple time series", func(t *testing.T) {
+		p := NewAzureMonitorProvider(&mockAPI{values: []float64{0.5, 2}}, log.Entry{})
+		measurement := p.Run(&v1alpha1.AnalysisRun{}, newMetric("all(result, {# < 1})"))
+		assert.Equal(t, v1alpha1.AnalysisPhaseFailed, measurement.Phase)
+		assert.Equal(t, "[0.5 2]", measurement.Value)
+	})
+	t.Run("interval", func(t *testing.T) {
+		api := &mockAPI{values: []float64{0.5}}
+		p := NewAzureMonitorProvider(api, log.Entry{})
+		metric := newMetric("result < 1")
+		metric.Provider.AzureMonitor.Interval = "10m"
+		p.Run(&v1alpha1.AnalysisRun{}, metric)
+		assert.Equal(t, 10*time.Minute, api.interval)
+	})
+	t.Run("no data points", func(t *testing.T) {
+		p := NewAzureMonitorProvider(&mockAPI{}, log.Entry{})
+		measurement := p.Run(&v1alpha1.AnalysisRun{}, newMetric("result < 1"))
+		assert.Equal(t, v1alpha1.AnalysisPhaseError, measurement.Phase)
+		assert.Equal(t, "no data points for the metric FailedRequests", measurement.Message)
+	})
+	t.Run("query error", func(t *testing.T) {
+		p := NewAzureMonitorProvider(&mockAPI{err: fmt.Errorf("bad request")}, log.Entry{})
+		measurement := p.Run(&v1alpha1.AnalysisRun{}, newMetric("result < 1"))
+		assert.Equal(t, v1alpha1.AnalysisPhaseError, measurement.Phase)
+		assert.Equal(t, "bad request", measurement.Message)
+	})
+}
+
+func TestQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "/subscriptions/sub/resourceGroups/group/providers/Microsoft.Network/applicationGateways/gateway/providers/Microsoft.Insights/metrics", r.URL.Path)
+		query := r.URL.Query()
+		assert.Equal(t, metricsAPIVersion, query.Get("api-version"))
+		assert.Equal(t, "FULL", query.Get("interval"))
+		assert.Equal(t, "StatusCode eq '500'", query.Get("$filter"))
+		if query.Get("metricnames") != "FailedRequests" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"code":"BadRequest","message":"Failed to find metric configuration"}}`))
+			return
+		}
+		assert.Equal(t, "Total", query.Get("aggregation"))
+		w.Write([]byte(`{"value":[{"name":{"value":"FailedRequests"},"timeseries":[
+			{"data":[{"timeStamp":"2026-01-01T00:00:00Z","total":3}]},
+			{"data":[{"timeStamp":"2026-01-01T00:00:00Z"}]}
+		]}]}`))
+	}))
+	defer server.Close()
+	client := &AzureMonitorClient{credential: fakeCredential{}, endpoint: server.URL, client: server.Client()}
+
+	metric := newMetric("")
+	metric.Provider.AzureMonitor.Aggregation = "Total"
+	metric.Provider.AzureMonitor.Filter = "StatusCode eq '500'"
+	values, err := client.Query(metric.Provider.AzureMonitor, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{3}, values)
+
+	metric.Provider.AzureMonitor.MetricName = "Unknown"
+	_, err = client.Query(metric.Provider.AzureMonitor, time.Minute)
+	assert.EqualError(t, err, "failed to query the metric Unknown: BadRequest: Failed to find metric configuration")
+}
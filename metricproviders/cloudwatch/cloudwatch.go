@@ -5,12 +5,13 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	awsutil "github.com/argoproj/argo-rollouts/utils/aws"
+	"github.com/argoproj/argo-rollouts/utils/config"
 	"github.com/argoproj/argo-rollouts/utils/evaluate"
 	metricutil "github.com/argoproj/argo-rollouts/utils/metric"
 	timeutil "github.com/argoproj/argo-rollouts/utils/time"
@@ -123,8 +124,25 @@ func NewCloudWatchProvider(api CloudWatchClientAPI, logCtx log.Entry) *Provider
 	}
 }
 
-func NewCloudWatchAPIClient(metric v1alpha1.Metric, opts ...func(*cloudwatch.Options)) (CloudWatchClientAPI, error) {
-	cfg, err := config.LoadDefaultConfig(context.TODO())
+// NewCloudWatchAPIClient returns the client of the metric of an analysis run of the namespace. The IAM role of the
+// metric must be one of the roles which the metrics may assume, and is assumed with the namespace as external ID, so
+// that a role which requires the external ID of a namespace can't be assumed by the metrics of other namespaces.
+func NewCloudWatchAPIClient(metric v1alpha1.Metric, namespace string, opts ...func(*cloudwatch.Options)) (CloudWatchClientAPI, error) {
+	roleARN := metric.Provider.CloudWatch.RoleARN
+	if roleARN != "" {
+		cfg, err := config.GetConfig()
+		if err != nil {
+			return nil, err
+		}
+		assumable, err := cfg.AWSRoleAssumable(roleARN)
+		if err != nil {
+			return nil, err
+		}
+		if !assumable {
+			return nil, fmt.Errorf("role %s is not one of the awsAssumableRoles of the controller configmap", roleARN)
+		}
+	}
+	cfg, err := awsutil.LoadConfig(context.TODO(), metric.Provider.CloudWatch.Region, roleARN, namespace)
 	if err != nil {
 		return nil, err
 	}
@@ -6,15 +6,22 @@ import (
 	"os"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/utils/ptr"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/config"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
 )
 
 func newAnalysisRun() *v1alpha1.AnalysisRun {
@@ -227,9 +234,41 @@ func TestNewCloudWatchAPIClient(t *testing.T) {
 			assert.Equal(t, "fuga", cred.SecretAccessKey)
 		}
 
-		_, err := NewCloudWatchAPIClient(metric, checkEnvs)
+		_, err := NewCloudWatchAPIClient(metric, "default", checkEnvs)
 		assert.Nil(t, err)
 	})
+
+	t.Run("with region and role", func(t *testing.T) {
+		config.UnInitializeConfig()
+		t.Cleanup(config.UnInitializeConfig)
+		_, err := config.InitializeConfig(k8sfake.NewSimpleClientset(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: defaults.DefaultRolloutsConfigMapName, Namespace: defaults.Namespace()},
+			Data:       map[string]string{"awsAssumableRoles": "- arn:aws:iam::123456789012:role/metrics-*"},
+		}), defaults.DefaultRolloutsConfigMapName)
+		require.NoError(t, err)
+		metric := v1alpha1.Metric{
+			Provider: v1alpha1.MetricProvider{
+				CloudWatch: &v1alpha1.CloudWatchMetric{
+					Region:  "eu-west-1",
+					RoleARN: "arn:aws:iam::123456789012:role/metrics-team-a",
+				},
+			},
+		}
+		checkRole := func(opt *cloudwatch.Options) {
+			assert.Equal(t, "eu-west-1", opt.Region)
+			cache, ok := opt.Credentials.(*aws.CredentialsCache)
+			assert.True(t, ok)
+			assert.True(t, cache.IsCredentialsProvider(&stscreds.AssumeRoleProvider{}))
+		}
+
+		_, err = NewCloudWatchAPIClient(metric, "team-a", checkRole)
+		assert.Nil(t, err)
+
+		// the roles which are not assumable are rejected
+		metric.Provider.CloudWatch.RoleARN = "arn:aws:iam::123456789012:role/admin"
+		_, err = NewCloudWatchAPIClient(metric, "team-a")
+		assert.EqualError(t, err, "role arn:aws:iam::123456789012:role/admin is not one of the awsAssumableRoles of the controller configmap")
+	})
 }
 
 func TestConvertType(t *testing.T) {
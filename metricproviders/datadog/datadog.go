@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"reflect"
 	"strconv"
 	"time"
@@ -448,7 +449,11 @@ func findCredentials(logCtx log.Entry, kubeclientset kubernetes.Interface, names
 	if secretName != "" {
 		finders = append(finders, NewSecretFinder(kubeclientset, secretName, credentialsNs))
 	} else {
-		finders = append(finders, NewEnvVariablesFinder(), NewSecretFinder(kubeclientset, DatadogTokensSecretName, defaults.Namespace()))
+		finders = append(finders, NewEnvVariablesFinder())
+		if secretID := os.Getenv(DatadogAWSSecretIDEnv); secretID != "" {
+			finders = append(finders, NewAWSSecretsManagerFinder(secretID))
+		}
+		finders = append(finders, NewSecretFinder(kubeclientset, DatadogTokensSecretName, defaults.Namespace()))
 	}
 	for _, finder := range finders {
 		address, apiKey, appKey := finder.FindCredentials(logCtx)
@@ -2,13 +2,21 @@ package datadog
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+
+	awsutil "github.com/argoproj/argo-rollouts/utils/aws"
 )
 
 type CredentialsFinder interface {
@@ -77,3 +85,95 @@ func lookupKeysInEnv(keys []string) map[string]string {
 	}
 	return valuesByKey
 }
+
+const (
+	// DatadogAWSSecretIDEnv is the environment variable of the controller holding the ID or the ARN of the secret of
+	// AWS Secrets Manager which holds the credentials
+	DatadogAWSSecretIDEnv = "DD_AWS_SECRET_ID"
+	// awsSecretCacheTTL is the time the credentials read from AWS Secrets Manager are cached
+	awsSecretCacheTTL = 5 * time.Minute
+	// awsSecretTimeout is the maximum time a read of the credentials from AWS Secrets Manager may take
+	awsSecretTimeout = 10 * time.Second
+)
+
+// getAWSSecretValue returns the value of a secret of AWS Secrets Manager. It is declared as a variable to allow mocking
+var getAWSSecretValue = func(ctx context.Context, secretID string) (string, error) {
+	var region string
+	if secretARN, err := arn.Parse(secretID); err == nil {
+		region = secretARN.Region
+	}
+	cfg, err := awsutil.LoadConfig(ctx, region, "", "")
+	if err != nil {
+		return "", err
+	}
+	output, err := secretsmanager.NewFromConfig(cfg).GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: &secretID})
+	if err != nil {
+		return "", err
+	}
+	if output.SecretString == nil {
+		return "", fmt.Errorf("secret %s has no string value", secretID)
+	}
+	return *output.SecretString, nil
+}
+
+// awsSecrets caches the credentials read from AWS Secrets Manager, so that every measurement does not read them. The
+// lock only guards the entries, and the concurrent reads of a secret are merged into one, so that a slow read of a
+// secret does not block the measurements using the credentials of other secrets.
+var awsSecrets = struct {
+	sync.Mutex
+	entries map[string]awsSecretEntry
+	reads   singleflight.Group
+}{entries: map[string]awsSecretEntry{}}
+
+type awsSecretEntry struct {
+	credentials map[string]string
+	expiresAt   time.Time
+}
+
+// NewAWSSecretsManagerFinder returns the finder of the credentials stored in a secret of AWS Secrets Manager, e.g. to
+// query Datadog through AWS PrivateLink without storing the keys in the cluster. The secret is read with the AWS
+// credentials of the controller, such as IAM roles for service accounts or EKS Pod Identity, and holds a JSON object
+// with the api-key, app-key and, optionally, address keys.
+func NewAWSSecretsManagerFinder(secretID string) *awsSecretsManagerFinder {
+	return &awsSecretsManagerFinder{secretID: secretID}
+}
+
+type awsSecretsManagerFinder struct {
+	secretID string
+}
+
+func (f *awsSecretsManagerFinder) FindCredentials(logCtx log.Entry) (string, string, string) {
+	awsSecrets.Lock()
+	entry, ok := awsSecrets.entries[f.secretID]
+	awsSecrets.Unlock()
+	if !ok || time.Now().After(entry.expiresAt) {
+		read, err, _ := awsSecrets.reads.Do(f.secretID, func() (any, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), awsSecretTimeout)
+			defer cancel()
+			value, err := getAWSSecretValue(ctx, f.secretID)
+			if err != nil {
+				return nil, fmt.Errorf("error reading the secret %s of AWS Secrets Manager: %w", f.secretID, err)
+			}
+			var credentials map[string]string
+			if err := json.Unmarshal([]byte(value), &credentials); err != nil {
+				return nil, fmt.Errorf("secret %s of AWS Secrets Manager is not a JSON object: %w", f.secretID, err)
+			}
+			entry := awsSecretEntry{credentials: credentials, expiresAt: time.Now().Add(awsSecretCacheTTL)}
+			awsSecrets.Lock()
+			awsSecrets.entries[f.secretID] = entry
+			awsSecrets.Unlock()
+			return entry, nil
+		})
+		if err != nil {
+			logCtx.Warn(err.Error())
+			return "", "", ""
+		}
+		entry = read.(awsSecretEntry)
+	}
+	apiKey, appKey := entry.credentials[DatadogApiKey], entry.credentials[DatadogAppKey]
+	if apiKey == "" || appKey == "" {
+		logCtx.Debugf("credentials missing in secret %s of AWS Secrets Manager", f.secretID)
+		return "", "", ""
+	}
+	return entry.credentials[DatadogAddress], apiKey, appKey
+}
@@ -1,6 +1,7 @@
 package datadog
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -120,3 +121,59 @@ func NewSecretBuilderDefaultData() *SecretBuilder {
 		},
 	}
 }
+
+func TestAWSSecretsManagerFinder(t *testing.T) {
+	reads := 0
+	value := `{"api-key": "apiKey", "app-key": "appKey", "address": "https://api.privatelink.datadoghq.com"}`
+	getAWSSecretValue = func(ctx context.Context, secretID string) (string, error) {
+		reads++
+		assert.Equal(t, "arn:aws:secretsmanager:us-east-1:123456789012:secret:datadog", secretID)
+		_, ok := ctx.Deadline()
+		assert.True(t, ok)
+		return value, nil
+	}
+	t.Cleanup(func() {
+		awsSecrets.entries = map[string]awsSecretEntry{}
+	})
+
+	finder := NewAWSSecretsManagerFinder("arn:aws:secretsmanager:us-east-1:123456789012:secret:datadog")
+	address, apiKey, appKey := finder.FindCredentials(*log.NewEntry(log.New()))
+	assert.Equal(t, "https://api.privatelink.datadoghq.com", address)
+	assert.Equal(t, ApiKey, apiKey)
+	assert.Equal(t, AppKey, appKey)
+
+	// the credentials are cached
+	_, apiKey, _ = finder.FindCredentials(*log.NewEntry(log.New()))
+	assert.Equal(t, ApiKey, apiKey)
+	assert.Equal(t, 1, reads)
+
+	awsSecrets.entries = map[string]awsSecretEntry{}
+	value = `{"api-key": "apiKey"}`
+	_, apiKey, appKey = finder.FindCredentials(*log.NewEntry(log.New()))
+	assert.Empty(t, apiKey)
+	assert.Empty(t, appKey)
+}
+
+func TestAWSSecretsManagerFinderSlowSecret(t *testing.T) {
+	release := make(chan struct{})
+	getAWSSecretValue = func(ctx context.Context, secretID string) (string, error) {
+		if secretID == "slow" {
+			<-release
+		}
+		return `{"api-key": "apiKey", "app-key": "appKey"}`, nil
+	}
+	t.Cleanup(func() {
+		awsSecrets.entries = map[string]awsSecretEntry{}
+	})
+
+	slow := make(chan string)
+	go func() {
+		_, apiKey, _ := NewAWSSecretsManagerFinder("slow").FindCredentials(*log.NewEntry(log.New()))
+		slow <- apiKey
+	}()
+	// the read of a slow secret does not block the reads of the other secrets
+	_, apiKey, _ := NewAWSSecretsManagerFinder("fast").FindCredentials(*log.NewEntry(log.New()))
+	assert.Equal(t, ApiKey, apiKey)
+	close(release)
+	assert.Equal(t, ApiKey, <-slow)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/argoproj/argo-rollouts/metricproviders/influxdb"
 	"github.com/argoproj/argo-rollouts/metricproviders/skywalking"
 
+	"github.com/argoproj/argo-rollouts/metricproviders/azuremonitor"
 	"github.com/argoproj/argo-rollouts/metricproviders/cloudwatch"
 	"github.com/argoproj/argo-rollouts/metricproviders/datadog"
 	"github.com/argoproj/argo-rollouts/metricproviders/graphite"
@@ -95,7 +96,7 @@ func (f *ProviderFactory) NewProvider(logCtx log.Entry, namespace string, metric
 		}
 		return graphite.NewGraphiteProvider(client, logCtx), nil
 	case cloudwatch.ProviderType:
-		client, err := cloudwatch.NewCloudWatchAPIClient(metric)
+		client, err := cloudwatch.NewCloudWatchAPIClient(metric, namespace)
 		if err != nil {
 			return nil, err
 		}
@@ -112,6 +113,12 @@ func (f *ProviderFactory) NewProvider(logCtx log.Entry, namespace string, metric
 			return nil, err
 		}
 		return skywalking.NewSkyWalkingProvider(client, logCtx), nil
+	case azuremonitor.ProviderType:
+		client, err := azuremonitor.NewAzureMonitorAPIClient()
+		if err != nil {
+			return nil, err
+		}
+		return azuremonitor.NewAzureMonitorProvider(client, logCtx), nil
 	case plugin.ProviderType:
 		plugin, err := plugin.NewRpcPlugin(metric)
 		if err != nil {
@@ -146,6 +153,8 @@ func Type(metric v1alpha1.Metric) string {
 		return influxdb.ProviderType
 	} else if metric.Provider.SkyWalking != nil {
 		return skywalking.ProviderType
+	} else if metric.Provider.AzureMonitor != nil {
+		return azuremonitor.ProviderType
 	} else if metric.Provider.Plugin != nil {
 		return plugin.ProviderType
 	}
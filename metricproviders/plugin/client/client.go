@@ -2,7 +2,6 @@ package client
 
 import (
 	"fmt"
-	"os/exec"
 	"sync"
 
 	goPlugin "github.com/hashicorp/go-plugin"
@@ -33,6 +32,26 @@ var pluginMap = map[string]goPlugin.Plugin{
 	"RpcMetricProviderPlugin": &rpc.RpcMetricProviderPlugin{},
 }
 
+func init() {
+	plugin.RegisterReloader(types.PluginTypeMetricProvider, reloadPlugin)
+}
+
+// reloadPlugin stops the running process of the plugin once its in-flight calls have completed, so that the plugin is
+// started again with its new definition the next time it is used
+func reloadPlugin(pluginName string) {
+	mutex.Lock()
+	if pluginClients == nil || pluginClients.pluginClient[pluginName] == nil {
+		mutex.Unlock()
+		return
+	}
+	pluginClient, rpcPlugin := pluginClients.pluginClient[pluginName], pluginClients.plugin[pluginName]
+	delete(pluginClients.pluginClient, pluginName)
+	delete(pluginClients.plugin, pluginName)
+	mutex.Unlock()
+
+	plugin.StopClient(pluginName, pluginClient, rpcPlugin)
+}
+
 // GetMetricPlugin returns a singleton plugin client for the given metric plugin. Calling this multiple times
 // returns the same plugin client instance for the plugin name defined in the metric.
 func GetMetricPlugin(metric v1alpha1.Metric) (rpc.MetricProviderPlugin, error) {
@@ -63,14 +82,10 @@ func (m *metricPlugin) startPluginSystem(metric v1alpha1.Metric) (rpc.MetricProv
 
 		if m.pluginClient[pluginName] == nil || m.pluginClient[pluginName].Exited() {
 
-			m.pluginClient[pluginName] = goPlugin.NewClient(&goPlugin.ClientConfig{
-				HandshakeConfig: handshakeConfig,
-				Plugins:         pluginMap,
-				Cmd:             exec.Command(pluginPath, args...),
-				Managed:         true,
-			})
+			pluginConfig := plugin.GetPlugin(pluginName, types.PluginTypeMetricProvider)
+			m.pluginClient[pluginName] = plugin.NewClient(pluginPath, args, pluginConfig, handshakeConfig, pluginMap)
 
-			rpcClient, err := m.pluginClient[pluginName].Client()
+			rpcClient, err := plugin.StartClient(m.pluginClient[pluginName], pluginConfig)
 			if err != nil {
 				return nil, fmt.Errorf("unable to get plugin client (%s): %w", pluginName, err)
 			}
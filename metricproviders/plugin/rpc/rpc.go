@@ -4,6 +4,7 @@ import (
 	"encoding/gob"
 	"fmt"
 	"net/rpc"
+	"time"
 
 	"github.com/argoproj/argo-rollouts/utils/plugin/types"
 
@@ -51,7 +52,7 @@ type MetricProviderPlugin interface {
 }
 
 // MetricsPluginRPC Here is an implementation that talks over RPC
-type MetricsPluginRPC struct{ client *rpc.Client }
+type MetricsPluginRPC struct{ client *types.RpcClient }
 
 // InitPlugin is the client side function that is wrapped by a local provider this makes a rpc call to the
 // server side function.
@@ -132,6 +133,12 @@ func (g *MetricsPluginRPC) GarbageCollect(analysisRun *v1alpha1.AnalysisRun, met
 	return resp
 }
 
+// Drain waits for the in-flight calls to the plugin to complete, and returns false if they did not complete within the
+// timeout
+func (g *MetricsPluginRPC) Drain(timeout time.Duration) bool {
+	return g.client.Drain(timeout)
+}
+
 // Type is the client side function that is wrapped by a local provider this makes an rpc call to the server side function.
 func (g *MetricsPluginRPC) Type() string {
 	var resp string
@@ -254,5 +261,5 @@ func (p *RpcMetricProviderPlugin) Server(*plugin.MuxBroker) (any, error) {
 }
 
 func (RpcMetricProviderPlugin) Client(b *plugin.MuxBroker, c *rpc.Client) (any, error) {
-	return &MetricsPluginRPC{client: c}, nil
+	return &MetricsPluginRPC{client: types.NewRpcClient(c)}, nil
 }
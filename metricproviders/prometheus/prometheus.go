@@ -2,7 +2,6 @@ package prometheus
 
 import (
 	"context"
-	"crypto/tls"
 	"errors"
 	"fmt"
 	"net"
@@ -10,6 +9,7 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/api"
@@ -24,6 +24,7 @@ import (
 	"github.com/argoproj/argo-rollouts/utils/evaluate"
 	metricutil "github.com/argoproj/argo-rollouts/utils/metric"
 	timeutil "github.com/argoproj/argo-rollouts/utils/time"
+	tlsutil "github.com/argoproj/argo-rollouts/utils/tls"
 )
 
 const (
@@ -217,12 +218,13 @@ func newHTTPTransport(insecureSkipVerify bool) *http.Transport {
 			KeepAlive: 30 * time.Second,
 		}).DialContext,
 		TLSHandshakeTimeout: 10 * time.Second,
-		TLSClientConfig:     &tls.Config{InsecureSkipVerify: insecureSkipVerify},
+		TLSClientConfig:     tlsutil.ClientConfig(insecureSkipVerify),
 	}
 }
 
-var secureTransport *http.Transport = newHTTPTransport(false)
-var insecureTransport *http.Transport = newHTTPTransport(true)
+// the transports are created on first use, once the TLS policy of the controller is set
+var secureTransport = sync.OnceValue(func() *http.Transport { return newHTTPTransport(false) })
+var insecureTransport = sync.OnceValue(func() *http.Transport { return newHTTPTransport(true) })
 
 // NewPrometheusAPI generates a prometheus API from the metric configuration
 func NewPrometheusAPI(metric v1alpha1.Metric) (v1.API, error) {
@@ -248,9 +250,9 @@ func NewPrometheusAPI(metric v1alpha1.Metric) (v1.API, error) {
 
 	var roundTripper http.RoundTripper
 	if metric.Provider.Prometheus.Insecure {
-		roundTripper = insecureTransport
+		roundTripper = insecureTransport()
 	} else {
-		roundTripper = secureTransport
+		roundTripper = secureTransport()
 	}
 
 	// attach custom headers to api requests, if specified
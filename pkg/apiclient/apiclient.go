@@ -0,0 +1,219 @@
+// Package apiclient is the Go client of the API of the Argo Rollouts dashboard. The API manages the rollouts over gRPC,
+// and over REST under /api/v1, from the same port as the dashboard.
+//
+// The client is versioned like the API: the methods of RolloutsV1 only gain fields within v1, and a change which is
+// not backwards compatible is made in a new version of the client.
+package apiclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/argoproj/argo-rollouts/pkg/apiclient/rollout"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+const (
+	// MaxGRPCMessageSize is the largest message received by the client, which matches the largest message of the server
+	MaxGRPCMessageSize = 100 * 1024 * 1024
+)
+
+// Config configures the connection of a client to the API
+type Config struct {
+	// ServerAddr is the host and the port of the dashboard, e.g. localhost:3100
+	ServerAddr string
+	// TLS connects with TLS, to a dashboard served with --self-signed-tls, or exposed behind a proxy or an ingress
+	// terminating TLS. A dashboard served without TLS is reached without TLS, e.g. through kubectl port-forward.
+	TLS bool
+	// CAData is the PEM encoded bundle of the CAs which verify the TLS certificate of the server, e.g. the ca.crt key
+	// of the CA bundle ConfigMap of a dashboard served with --self-signed-tls. The CAs of the system verify it when
+	// neither CAData nor CAFile is set.
+	CAData []byte
+	// CAFile is the path of a file holding the PEM encoded bundle of the CAs, like CAData
+	CAFile string
+	// Insecure skips the verification of the TLS certificate of the server
+	Insecure bool
+	// AuthToken is the ID token of the user, sent as a bearer token, when the dashboard requires the users to log in.
+	// It is only sent over TLS, unless AllowInsecureToken is set.
+	AuthToken string
+	// AllowInsecureToken sends the AuthToken without TLS. The token is then sent in plain text, which is only safe over
+	// a loopback connection, e.g. kubectl port-forward to localhost.
+	AllowInsecureToken bool
+}
+
+// Interface is the client of the versions of the API
+type Interface interface {
+	RolloutsV1() RolloutsV1Interface
+}
+
+// RolloutsV1Interface manages the rollouts through the v1 API
+type RolloutsV1Interface interface {
+	// Get returns the status of a rollout
+	Get(ctx context.Context, namespace, name string) (*rollout.RolloutInfo, error)
+	// List returns the status of the rollouts of a namespace, or of all the namespaces when empty
+	List(ctx context.Context, namespace string, opts ListOptions) (*rollout.RolloutInfoList, error)
+	// Watch returns a stream of the changes of the rollouts of a namespace, or of all the namespaces when empty
+	Watch(ctx context.Context, namespace string, opts ListOptions) (rollout.RolloutService_WatchRolloutInfosClient, error)
+	// Promote promotes a rollout to its next step, or fully when full is true
+	Promote(ctx context.Context, namespace, name string, full bool) (*v1alpha1.Rollout, error)
+	// Abort aborts the update of a rollout
+	Abort(ctx context.Context, namespace, name string) (*v1alpha1.Rollout, error)
+	// Retry retries the aborted update of a rollout
+	Retry(ctx context.Context, namespace, name string) (*v1alpha1.Rollout, error)
+	// SetImage sets the image of a container of a rollout to image:tag
+	SetImage(ctx context.Context, namespace, name, container, image, tag string) (*v1alpha1.Rollout, error)
+}
+
+// ListOptions selects the rollouts to list or to watch
+type ListOptions struct {
+	// LabelSelector selects the rollouts by label
+	LabelSelector string
+	// Phase selects the rollouts of a phase: Healthy, Progressing, Paused or Degraded
+	Phase string
+	// Strategy selects the rollouts of a strategy: Canary or BlueGreen
+	Strategy string
+	// Limit is the maximum number of rollouts of a list, which lists all the rollouts when 0. It is ignored by Watch.
+	Limit int64
+	// Continue is the token of the previous list to list the next rollouts from. It is ignored by Watch.
+	Continue string
+}
+
+// Clientset is a client of the API of the dashboard. It must be closed once no longer used.
+type Clientset struct {
+	rolloutsV1 *RolloutsV1Client
+	conn       *grpc.ClientConn
+}
+
+var _ Interface = &Clientset{}
+
+// NewForConfig returns a client of the API of the dashboard
+func NewForConfig(c *Config) (*Clientset, error) {
+	if c.ServerAddr == "" {
+		return nil, fmt.Errorf("server address is required")
+	}
+	dialOpts := []grpc.DialOption{
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(MaxGRPCMessageSize)),
+	}
+	if c.TLS {
+		tlsConfig, err := c.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+	if c.AuthToken != "" {
+		if !c.TLS && !c.AllowInsecureToken {
+			return nil, fmt.Errorf("auth token requires TLS, or AllowInsecureToken over a loopback connection")
+		}
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(tokenCredentials{token: c.AuthToken, allowInsecure: !c.TLS}))
+	}
+	conn, err := grpc.NewClient(c.ServerAddr, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", c.ServerAddr, err)
+	}
+	return &Clientset{rolloutsV1: &RolloutsV1Client{client: rollout.NewRolloutServiceClient(conn)}, conn: conn}, nil
+}
+
+// tlsConfig returns the TLS configuration of the connection, which verifies the certificate of the server with the CAs
+// of the config, if any
+func (c *Config) tlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: c.Insecure}
+	caData := c.CAData
+	if c.CAFile != "" {
+		data, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		caData = append(append([]byte{}, caData...), data...)
+	}
+	if len(caData) > 0 {
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caData) {
+			return nil, fmt.Errorf("no PEM encoded CA certificates found")
+		}
+	}
+	return tlsConfig, nil
+}
+
+// RolloutsV1 returns the client of the v1 API
+func (c *Clientset) RolloutsV1() RolloutsV1Interface {
+	return c.rolloutsV1
+}
+
+// Close closes the connection of the client
+func (c *Clientset) Close() error {
+	return c.conn.Close()
+}
+
+// RolloutsV1Client manages the rollouts through the v1 API
+type RolloutsV1Client struct {
+	client rollout.RolloutServiceClient
+}
+
+var _ RolloutsV1Interface = &RolloutsV1Client{}
+
+func (c *RolloutsV1Client) Get(ctx context.Context, namespace, name string) (*rollout.RolloutInfo, error) {
+	return c.client.GetRolloutInfo(ctx, &rollout.RolloutInfoQuery{Namespace: namespace, Name: name})
+}
+
+func (c *RolloutsV1Client) List(ctx context.Context, namespace string, opts ListOptions) (*rollout.RolloutInfoList, error) {
+	return c.client.ListRolloutInfos(ctx, opts.query(namespace))
+}
+
+func (c *RolloutsV1Client) Watch(ctx context.Context, namespace string, opts ListOptions) (rollout.RolloutService_WatchRolloutInfosClient, error) {
+	q := opts.query(namespace)
+	q.Limit = 0
+	q.Continue = ""
+	return c.client.WatchRolloutInfos(ctx, q)
+}
+
+func (c *RolloutsV1Client) Promote(ctx context.Context, namespace, name string, full bool) (*v1alpha1.Rollout, error) {
+	return c.client.PromoteRollout(ctx, &rollout.PromoteRolloutRequest{Namespace: namespace, Name: name, Full: full})
+}
+
+func (c *RolloutsV1Client) Abort(ctx context.Context, namespace, name string) (*v1alpha1.Rollout, error) {
+	return c.client.AbortRollout(ctx, &rollout.AbortRolloutRequest{Namespace: namespace, Name: name})
+}
+
+func (c *RolloutsV1Client) Retry(ctx context.Context, namespace, name string) (*v1alpha1.Rollout, error) {
+	return c.client.RetryRollout(ctx, &rollout.RetryRolloutRequest{Namespace: namespace, Name: name})
+}
+
+func (c *RolloutsV1Client) SetImage(ctx context.Context, namespace, name, container, image, tag string) (*v1alpha1.Rollout, error) {
+	return c.client.SetRolloutImage(ctx, &rollout.SetImageRequest{Namespace: namespace, Rollout: name, Container: container, Image: image, Tag: tag})
+}
+
+func (o ListOptions) query(namespace string) *rollout.RolloutInfoListQuery {
+	return &rollout.RolloutInfoListQuery{
+		Namespace:     namespace,
+		LabelSelector: o.LabelSelector,
+		Phase:         o.Phase,
+		Strategy:      o.Strategy,
+		Limit:         o.Limit,
+		Continue:      o.Continue,
+	}
+}
+
+// tokenCredentials sends the ID token of the user as a bearer token with every call, which requires TLS unless the
+// token is explicitly allowed without it
+type tokenCredentials struct {
+	token         string
+	allowInsecure bool
+}
+
+func (c tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + c.token}, nil
+}
+
+func (c tokenCredentials) RequireTransportSecurity() bool {
+	return !c.allowInsecure
+}
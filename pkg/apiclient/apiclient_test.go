@@ -0,0 +1,154 @@
+package apiclient
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj/argo-rollouts/pkg/apiclient/rollout"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/certs"
+)
+
+type fakeRolloutServiceServer struct {
+	rollout.UnimplementedRolloutServiceServer
+	authorization []string
+	full          bool
+	listQuery     *rollout.RolloutInfoListQuery
+}
+
+func (s *fakeRolloutServiceServer) PromoteRollout(ctx context.Context, q *rollout.PromoteRolloutRequest) (*v1alpha1.Rollout, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.authorization = md.Get("authorization")
+	s.full = q.GetFull()
+	return &v1alpha1.Rollout{ObjectMeta: v1.ObjectMeta{Namespace: q.GetNamespace(), Name: q.GetName()}}, nil
+}
+
+func (s *fakeRolloutServiceServer) ListRolloutInfos(ctx context.Context, q *rollout.RolloutInfoListQuery) (*rollout.RolloutInfoList, error) {
+	s.listQuery = q
+	return &rollout.RolloutInfoList{Continue: "next"}, nil
+}
+
+func newTestServer(t *testing.T, opts ...grpc.ServerOption) (*fakeRolloutServiceServer, string) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	fake := &fakeRolloutServiceServer{}
+	grpcS := grpc.NewServer(opts...)
+	rollout.RegisterRolloutServiceServer(grpcS, fake)
+	go func() { _ = grpcS.Serve(lis) }()
+	t.Cleanup(grpcS.Stop)
+	return fake, lis.Addr().String()
+}
+
+// newTLSTestServer returns a server with a certificate for localhost signed by a self-signed CA, and the PEM encoded
+// certificate of the CA
+func newTLSTestServer(t *testing.T) (*fakeRolloutServiceServer, string, []byte) {
+	ca, err := certs.GenerateCA("test-ca", time.Now(), time.Hour)
+	require.NoError(t, err)
+	serving, err := certs.GenerateServingCert(ca, []string{"localhost"}, time.Now(), time.Hour)
+	require.NoError(t, err)
+	cert, err := tls.X509KeyPair(serving.Cert, serving.Key)
+	require.NoError(t, err)
+	fake, addr := newTestServer(t, grpc.Creds(credentials.NewTLS(&tls.Config{Certificates: []tls.Certificate{cert}})))
+	_, port, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+	return fake, net.JoinHostPort("localhost", port), ca.Cert
+}
+
+func TestNewForConfig(t *testing.T) {
+	t.Run("server address required", func(t *testing.T) {
+		_, err := NewForConfig(&Config{})
+		assert.EqualError(t, err, "server address is required")
+	})
+
+	t.Run("token requires TLS", func(t *testing.T) {
+		_, err := NewForConfig(&Config{ServerAddr: "localhost:3100", AuthToken: "valid-token"})
+		assert.EqualError(t, err, "auth token requires TLS, or AllowInsecureToken over a loopback connection")
+	})
+
+	t.Run("call with token without TLS", func(t *testing.T) {
+		fake, addr := newTestServer(t)
+		client, err := NewForConfig(&Config{ServerAddr: addr, AuthToken: "valid-token", AllowInsecureToken: true})
+		require.NoError(t, err)
+		defer client.Close()
+
+		ro, err := client.RolloutsV1().Promote(context.Background(), "default", "guestbook", true)
+		require.NoError(t, err)
+		assert.Equal(t, "guestbook", ro.Name)
+		assert.Equal(t, []string{"Bearer valid-token"}, fake.authorization)
+		assert.True(t, fake.full)
+	})
+
+	t.Run("call without token", func(t *testing.T) {
+		fake, addr := newTestServer(t)
+		client, err := NewForConfig(&Config{ServerAddr: addr})
+		require.NoError(t, err)
+		defer client.Close()
+
+		_, err = client.RolloutsV1().Promote(context.Background(), "default", "guestbook", false)
+		require.NoError(t, err)
+		assert.Empty(t, fake.authorization)
+	})
+}
+
+func TestNewForConfigTLS(t *testing.T) {
+	fake, addr, caData := newTLSTestServer(t)
+
+	t.Run("call with token and CA data", func(t *testing.T) {
+		client, err := NewForConfig(&Config{ServerAddr: addr, TLS: true, CAData: caData, AuthToken: "valid-token"})
+		require.NoError(t, err)
+		defer client.Close()
+
+		_, err = client.RolloutsV1().Promote(context.Background(), "default", "guestbook", false)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Bearer valid-token"}, fake.authorization)
+	})
+
+	t.Run("call with CA file", func(t *testing.T) {
+		caFile := filepath.Join(t.TempDir(), "ca.crt")
+		require.NoError(t, os.WriteFile(caFile, caData, 0o600))
+		client, err := NewForConfig(&Config{ServerAddr: addr, TLS: true, CAFile: caFile})
+		require.NoError(t, err)
+		defer client.Close()
+
+		_, err = client.RolloutsV1().Promote(context.Background(), "default", "guestbook", false)
+		require.NoError(t, err)
+	})
+
+	t.Run("certificate of an unknown CA", func(t *testing.T) {
+		client, err := NewForConfig(&Config{ServerAddr: addr, TLS: true})
+		require.NoError(t, err)
+		defer client.Close()
+
+		_, err = client.RolloutsV1().Promote(context.Background(), "default", "guestbook", false)
+		assert.ErrorContains(t, err, "certificate signed by unknown authority")
+	})
+
+	t.Run("invalid CA data", func(t *testing.T) {
+		_, err := NewForConfig(&Config{ServerAddr: addr, TLS: true, CAData: []byte("not a certificate")})
+		assert.EqualError(t, err, "no PEM encoded CA certificates found")
+	})
+}
+
+func TestRolloutsV1List(t *testing.T) {
+	fake, addr := newTestServer(t)
+	client, err := NewForConfig(&Config{ServerAddr: addr})
+	require.NoError(t, err)
+	defer client.Close()
+
+	list, err := client.RolloutsV1().List(context.Background(), "default", ListOptions{Phase: "Healthy", Limit: 10, Continue: "token"})
+	require.NoError(t, err)
+	assert.Equal(t, "next", list.Continue)
+	assert.Equal(t, &rollout.RolloutInfoListQuery{Namespace: "default", Phase: "Healthy", Limit: 10, Continue: "token"}, fake.listQuery)
+}
@@ -6,5 +6,6 @@ import (
 
 func init() {
 	forward_RolloutService_WatchRolloutInfos_0 = http.StreamForwarder
+	forward_RolloutService_WatchRolloutInfos_1 = http.StreamForwarder
 	forward_RolloutService_WatchRolloutInfo_0 = http.StreamForwarder
 }
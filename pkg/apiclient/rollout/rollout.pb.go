@@ -86,7 +86,18 @@ func (m *RolloutInfoQuery) GetNamespace() string {
 }
 
 type RolloutInfoListQuery struct {
-	Namespace            string   `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// namespace of the rollouts. The rollouts of all the namespaces are listed when empty.
+	Namespace string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// labelSelector only selects the rollouts whose labels match the selector
+	LabelSelector string `protobuf:"bytes,2,opt,name=labelSelector,proto3" json:"labelSelector,omitempty"`
+	// phase only selects the rollouts in the phase, e.g. Healthy, Progressing, Paused or Degraded
+	Phase string `protobuf:"bytes,3,opt,name=phase,proto3" json:"phase,omitempty"`
+	// strategy only selects the rollouts with the strategy, i.e. Canary or BlueGreen
+	Strategy string `protobuf:"bytes,4,opt,name=strategy,proto3" json:"strategy,omitempty"`
+	// limit is the maximum number of rollouts of a list. All the rollouts are listed when 0.
+	Limit int64 `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	// continue is the token of the previous list to list the next rollouts from
+	Continue             string   `protobuf:"bytes,6,opt,name=continue,proto3" json:"continue,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -132,6 +143,41 @@ func (m *RolloutInfoListQuery) GetNamespace() string {
 	return ""
 }
 
+func (m *RolloutInfoListQuery) GetLabelSelector() string {
+	if m != nil {
+		return m.LabelSelector
+	}
+	return ""
+}
+
+func (m *RolloutInfoListQuery) GetPhase() string {
+	if m != nil {
+		return m.Phase
+	}
+	return ""
+}
+
+func (m *RolloutInfoListQuery) GetStrategy() string {
+	if m != nil {
+		return m.Strategy
+	}
+	return ""
+}
+
+func (m *RolloutInfoListQuery) GetLimit() int64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *RolloutInfoListQuery) GetContinue() string {
+	if m != nil {
+		return m.Continue
+	}
+	return ""
+}
+
 type SetImageRequest struct {
 	Rollout              string   `protobuf:"bytes,1,opt,name=rollout,proto3" json:"rollout,omitempty"`
 	Container            string   `protobuf:"bytes,2,opt,name=container,proto3" json:"container,omitempty"`
@@ -557,9 +603,146 @@ func (m *RolloutWatchEvent) GetRolloutInfo() *RolloutInfo {
 	return nil
 }
 
+// AuditEvent is an action taken on a rollout through the dashboard
+type AuditEvent struct {
+	// action is the action taken, e.g. promote, abort, retry or set-image
+	Action string `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
+	// user is the user who took the action. It is empty when the users of the dashboard do not log in.
+	User      string   `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Timestamp *v1.Time `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// priorState is the state of the rollout before the action
+	PriorState           string   `protobuf:"bytes,4,opt,name=priorState,proto3" json:"priorState,omitempty"`
+	Message              string   `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuditEvent) Reset()         { *m = AuditEvent{} }
+func (m *AuditEvent) String() string { return proto.CompactTextString(m) }
+func (*AuditEvent) ProtoMessage()    {}
+func (*AuditEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_99101d942e8912a7, []int{9}
+}
+func (m *AuditEvent) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AuditEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AuditEvent.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *AuditEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditEvent.Merge(m, src)
+}
+func (m *AuditEvent) XXX_Size() int {
+	return m.Size()
+}
+func (m *AuditEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditEvent proto.InternalMessageInfo
+
+func (m *AuditEvent) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetTimestamp() *v1.Time {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+func (m *AuditEvent) GetPriorState() string {
+	if m != nil {
+		return m.PriorState
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+type AuditEventList struct {
+	Events               []*AuditEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *AuditEventList) Reset()         { *m = AuditEventList{} }
+func (m *AuditEventList) String() string { return proto.CompactTextString(m) }
+func (*AuditEventList) ProtoMessage()    {}
+func (*AuditEventList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_99101d942e8912a7, []int{10}
+}
+func (m *AuditEventList) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AuditEventList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AuditEventList.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *AuditEventList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditEventList.Merge(m, src)
+}
+func (m *AuditEventList) XXX_Size() int {
+	return m.Size()
+}
+func (m *AuditEventList) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditEventList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditEventList proto.InternalMessageInfo
+
+func (m *AuditEventList) GetEvents() []*AuditEvent {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
 type NamespaceInfo struct {
-	Namespace            string   `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
-	AvailableNamespaces  []string `protobuf:"bytes,2,rep,name=availableNamespaces,proto3" json:"availableNamespaces,omitempty"`
+	Namespace           string   `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	AvailableNamespaces []string `protobuf:"bytes,2,rep,name=availableNamespaces,proto3" json:"availableNamespaces,omitempty"`
+	// readOnly is true when the dashboard does not allow any action on the rollouts
+	ReadOnly bool `protobuf:"varint,3,opt,name=readOnly,proto3" json:"readOnly,omitempty"`
+	// namespaceRestricted is true when the dashboard only allows the available namespaces
+	NamespaceRestricted bool `protobuf:"varint,4,opt,name=namespaceRestricted,proto3" json:"namespaceRestricted,omitempty"`
+	// podLogURLTemplate is the URL of the logs of a pod in the log system of the cluster, with the ${metadata.namespace}
+	// and ${metadata.name} placeholders of the pod
+	PodLogURLTemplate    string   `protobuf:"bytes,5,opt,name=podLogURLTemplate,proto3" json:"podLogURLTemplate,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -569,7 +752,7 @@ func (m *NamespaceInfo) Reset()         { *m = NamespaceInfo{} }
 func (m *NamespaceInfo) String() string { return proto.CompactTextString(m) }
 func (*NamespaceInfo) ProtoMessage()    {}
 func (*NamespaceInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_99101d942e8912a7, []int{9}
+	return fileDescriptor_99101d942e8912a7, []int{11}
 }
 func (m *NamespaceInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -612,18 +795,41 @@ func (m *NamespaceInfo) GetAvailableNamespaces() []string {
 	return nil
 }
 
+func (m *NamespaceInfo) GetReadOnly() bool {
+	if m != nil {
+		return m.ReadOnly
+	}
+	return false
+}
+
+func (m *NamespaceInfo) GetNamespaceRestricted() bool {
+	if m != nil {
+		return m.NamespaceRestricted
+	}
+	return false
+}
+
+func (m *NamespaceInfo) GetPodLogURLTemplate() string {
+	if m != nil {
+		return m.PodLogURLTemplate
+	}
+	return ""
+}
+
 type RolloutInfoList struct {
-	Rollouts             []*RolloutInfo `protobuf:"bytes,1,rep,name=rollouts,proto3" json:"rollouts,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
-	XXX_unrecognized     []byte         `json:"-"`
-	XXX_sizecache        int32          `json:"-"`
+	Rollouts []*RolloutInfo `protobuf:"bytes,1,rep,name=rollouts,proto3" json:"rollouts,omitempty"`
+	// continue is the token to list the next rollouts with. It is empty once all the rollouts are listed.
+	Continue             string   `protobuf:"bytes,2,opt,name=continue,proto3" json:"continue,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *RolloutInfoList) Reset()         { *m = RolloutInfoList{} }
 func (m *RolloutInfoList) String() string { return proto.CompactTextString(m) }
 func (*RolloutInfoList) ProtoMessage()    {}
 func (*RolloutInfoList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_99101d942e8912a7, []int{10}
+	return fileDescriptor_99101d942e8912a7, []int{12}
 }
 func (m *RolloutInfoList) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -659,6 +865,13 @@ func (m *RolloutInfoList) GetRollouts() []*RolloutInfo {
 	return nil
 }
 
+func (m *RolloutInfoList) GetContinue() string {
+	if m != nil {
+		return m.Continue
+	}
+	return ""
+}
+
 type VersionInfo struct {
 	RolloutsVersion      string   `protobuf:"bytes,1,opt,name=rolloutsVersion,proto3" json:"rolloutsVersion,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -670,7 +883,7 @@ func (m *VersionInfo) Reset()         { *m = VersionInfo{} }
 func (m *VersionInfo) String() string { return proto.CompactTextString(m) }
 func (*VersionInfo) ProtoMessage()    {}
 func (*VersionInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_99101d942e8912a7, []int{11}
+	return fileDescriptor_99101d942e8912a7, []int{13}
 }
 func (m *VersionInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -707,37 +920,41 @@ func (m *VersionInfo) GetRolloutsVersion() string {
 }
 
 type RolloutInfo struct {
-	ObjectMeta           *v1.ObjectMeta         `protobuf:"bytes,1,opt,name=objectMeta,proto3" json:"objectMeta,omitempty"`
-	Status               string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
-	Message              string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
-	Icon                 string                 `protobuf:"bytes,4,opt,name=icon,proto3" json:"icon,omitempty"`
-	Strategy             string                 `protobuf:"bytes,5,opt,name=strategy,proto3" json:"strategy,omitempty"`
-	Step                 string                 `protobuf:"bytes,6,opt,name=step,proto3" json:"step,omitempty"`
-	SetWeight            string                 `protobuf:"bytes,7,opt,name=setWeight,proto3" json:"setWeight,omitempty"`
-	ActualWeight         string                 `protobuf:"bytes,8,opt,name=actualWeight,proto3" json:"actualWeight,omitempty"`
-	Ready                int32                  `protobuf:"varint,9,opt,name=ready,proto3" json:"ready,omitempty"`
-	Current              int32                  `protobuf:"varint,10,opt,name=current,proto3" json:"current,omitempty"`
-	Desired              int32                  `protobuf:"varint,11,opt,name=desired,proto3" json:"desired,omitempty"`
-	Updated              int32                  `protobuf:"varint,12,opt,name=updated,proto3" json:"updated,omitempty"`
-	Available            int32                  `protobuf:"varint,13,opt,name=available,proto3" json:"available,omitempty"`
-	RestartedAt          string                 `protobuf:"bytes,14,opt,name=restartedAt,proto3" json:"restartedAt,omitempty"`
-	Generation           string                 `protobuf:"bytes,15,opt,name=generation,proto3" json:"generation,omitempty"`
-	ReplicaSets          []*ReplicaSetInfo      `protobuf:"bytes,16,rep,name=replicaSets,proto3" json:"replicaSets,omitempty"`
-	Experiments          []*ExperimentInfo      `protobuf:"bytes,17,rep,name=experiments,proto3" json:"experiments,omitempty"`
-	AnalysisRuns         []*AnalysisRunInfo     `protobuf:"bytes,18,rep,name=analysisRuns,proto3" json:"analysisRuns,omitempty"`
-	Containers           []*ContainerInfo       `protobuf:"bytes,19,rep,name=containers,proto3" json:"containers,omitempty"`
-	Steps                []*v1alpha1.CanaryStep `protobuf:"bytes,20,rep,name=steps,proto3" json:"steps,omitempty"`
-	InitContainers       []*ContainerInfo       `protobuf:"bytes,21,rep,name=initContainers,proto3" json:"initContainers,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
-	XXX_unrecognized     []byte                 `json:"-"`
-	XXX_sizecache        int32                  `json:"-"`
+	ObjectMeta     *v1.ObjectMeta         `protobuf:"bytes,1,opt,name=objectMeta,proto3" json:"objectMeta,omitempty"`
+	Status         string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Message        string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Icon           string                 `protobuf:"bytes,4,opt,name=icon,proto3" json:"icon,omitempty"`
+	Strategy       string                 `protobuf:"bytes,5,opt,name=strategy,proto3" json:"strategy,omitempty"`
+	Step           string                 `protobuf:"bytes,6,opt,name=step,proto3" json:"step,omitempty"`
+	SetWeight      string                 `protobuf:"bytes,7,opt,name=setWeight,proto3" json:"setWeight,omitempty"`
+	ActualWeight   string                 `protobuf:"bytes,8,opt,name=actualWeight,proto3" json:"actualWeight,omitempty"`
+	Ready          int32                  `protobuf:"varint,9,opt,name=ready,proto3" json:"ready,omitempty"`
+	Current        int32                  `protobuf:"varint,10,opt,name=current,proto3" json:"current,omitempty"`
+	Desired        int32                  `protobuf:"varint,11,opt,name=desired,proto3" json:"desired,omitempty"`
+	Updated        int32                  `protobuf:"varint,12,opt,name=updated,proto3" json:"updated,omitempty"`
+	Available      int32                  `protobuf:"varint,13,opt,name=available,proto3" json:"available,omitempty"`
+	RestartedAt    string                 `protobuf:"bytes,14,opt,name=restartedAt,proto3" json:"restartedAt,omitempty"`
+	Generation     string                 `protobuf:"bytes,15,opt,name=generation,proto3" json:"generation,omitempty"`
+	ReplicaSets    []*ReplicaSetInfo      `protobuf:"bytes,16,rep,name=replicaSets,proto3" json:"replicaSets,omitempty"`
+	Experiments    []*ExperimentInfo      `protobuf:"bytes,17,rep,name=experiments,proto3" json:"experiments,omitempty"`
+	AnalysisRuns   []*AnalysisRunInfo     `protobuf:"bytes,18,rep,name=analysisRuns,proto3" json:"analysisRuns,omitempty"`
+	Containers     []*ContainerInfo       `protobuf:"bytes,19,rep,name=containers,proto3" json:"containers,omitempty"`
+	Steps          []*v1alpha1.CanaryStep `protobuf:"bytes,20,rep,name=steps,proto3" json:"steps,omitempty"`
+	InitContainers []*ContainerInfo       `protobuf:"bytes,21,rep,name=initContainers,proto3" json:"initContainers,omitempty"`
+	// weightHistory holds the last changes of the canary traffic weight set on the traffic routers, the oldest first
+	WeightHistory []*v1alpha1.TrafficWeightRecord `protobuf:"bytes,22,rep,name=weightHistory,proto3" json:"weightHistory,omitempty"`
+	// stepProgress is the progress reported by the step plugin of the current step
+	StepProgress         string   `protobuf:"bytes,23,opt,name=stepProgress,proto3" json:"stepProgress,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *RolloutInfo) Reset()         { *m = RolloutInfo{} }
 func (m *RolloutInfo) String() string { return proto.CompactTextString(m) }
 func (*RolloutInfo) ProtoMessage()    {}
 func (*RolloutInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_99101d942e8912a7, []int{12}
+	return fileDescriptor_99101d942e8912a7, []int{14}
 }
 func (m *RolloutInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -913,6 +1130,20 @@ func (m *RolloutInfo) GetInitContainers() []*ContainerInfo {
 	return nil
 }
 
+func (m *RolloutInfo) GetWeightHistory() []*v1alpha1.TrafficWeightRecord {
+	if m != nil {
+		return m.WeightHistory
+	}
+	return nil
+}
+
+func (m *RolloutInfo) GetStepProgress() string {
+	if m != nil {
+		return m.StepProgress
+	}
+	return ""
+}
+
 type ExperimentInfo struct {
 	ObjectMeta           *v1.ObjectMeta     `protobuf:"bytes,1,opt,name=objectMeta,proto3" json:"objectMeta,omitempty"`
 	Icon                 string             `protobuf:"bytes,2,opt,name=icon,proto3" json:"icon,omitempty"`
@@ -930,7 +1161,7 @@ func (m *ExperimentInfo) Reset()         { *m = ExperimentInfo{} }
 func (m *ExperimentInfo) String() string { return proto.CompactTextString(m) }
 func (*ExperimentInfo) ProtoMessage()    {}
 func (*ExperimentInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_99101d942e8912a7, []int{13}
+	return fileDescriptor_99101d942e8912a7, []int{15}
 }
 func (m *ExperimentInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1035,7 +1266,7 @@ func (m *ReplicaSetInfo) Reset()         { *m = ReplicaSetInfo{} }
 func (m *ReplicaSetInfo) String() string { return proto.CompactTextString(m) }
 func (*ReplicaSetInfo) ProtoMessage()    {}
 func (*ReplicaSetInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_99101d942e8912a7, []int{14}
+	return fileDescriptor_99101d942e8912a7, []int{16}
 }
 func (m *ReplicaSetInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1198,7 +1429,7 @@ func (m *PodInfo) Reset()         { *m = PodInfo{} }
 func (m *PodInfo) String() string { return proto.CompactTextString(m) }
 func (*PodInfo) ProtoMessage()    {}
 func (*PodInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_99101d942e8912a7, []int{15}
+	return fileDescriptor_99101d942e8912a7, []int{17}
 }
 func (m *PodInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1274,7 +1505,7 @@ func (m *ContainerInfo) Reset()         { *m = ContainerInfo{} }
 func (m *ContainerInfo) String() string { return proto.CompactTextString(m) }
 func (*ContainerInfo) ProtoMessage()    {}
 func (*ContainerInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_99101d942e8912a7, []int{16}
+	return fileDescriptor_99101d942e8912a7, []int{18}
 }
 func (m *ContainerInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1332,7 +1563,7 @@ func (m *JobInfo) Reset()         { *m = JobInfo{} }
 func (m *JobInfo) String() string { return proto.CompactTextString(m) }
 func (*JobInfo) ProtoMessage()    {}
 func (*JobInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_99101d942e8912a7, []int{17}
+	return fileDescriptor_99101d942e8912a7, []int{19}
 }
 func (m *JobInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1408,7 +1639,7 @@ func (m *AnalysisRunSpecAndStatus) Reset()         { *m = AnalysisRunSpecAndStat
 func (m *AnalysisRunSpecAndStatus) String() string { return proto.CompactTextString(m) }
 func (*AnalysisRunSpecAndStatus) ProtoMessage()    {}
 func (*AnalysisRunSpecAndStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_99101d942e8912a7, []int{18}
+	return fileDescriptor_99101d942e8912a7, []int{20}
 }
 func (m *AnalysisRunSpecAndStatus) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1476,7 +1707,7 @@ func (m *AnalysisRunInfo) Reset()         { *m = AnalysisRunInfo{} }
 func (m *AnalysisRunInfo) String() string { return proto.CompactTextString(m) }
 func (*AnalysisRunInfo) ProtoMessage()    {}
 func (*AnalysisRunInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_99101d942e8912a7, []int{19}
+	return fileDescriptor_99101d942e8912a7, []int{21}
 }
 func (m *AnalysisRunInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1603,7 +1834,7 @@ func (m *NonJobInfo) Reset()         { *m = NonJobInfo{} }
 func (m *NonJobInfo) String() string { return proto.CompactTextString(m) }
 func (*NonJobInfo) ProtoMessage()    {}
 func (*NonJobInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_99101d942e8912a7, []int{20}
+	return fileDescriptor_99101d942e8912a7, []int{22}
 }
 func (m *NonJobInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1675,7 +1906,7 @@ func (m *Metrics) Reset()         { *m = Metrics{} }
 func (m *Metrics) String() string { return proto.CompactTextString(m) }
 func (*Metrics) ProtoMessage()    {}
 func (*Metrics) Descriptor() ([]byte, []int) {
-	return fileDescriptor_99101d942e8912a7, []int{21}
+	return fileDescriptor_99101d942e8912a7, []int{23}
 }
 func (m *Metrics) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1749,6 +1980,8 @@ func init() {
 	proto.RegisterType((*AbortRolloutRequest)(nil), "rollout.AbortRolloutRequest")
 	proto.RegisterType((*RetryRolloutRequest)(nil), "rollout.RetryRolloutRequest")
 	proto.RegisterType((*RolloutWatchEvent)(nil), "rollout.RolloutWatchEvent")
+	proto.RegisterType((*AuditEvent)(nil), "rollout.AuditEvent")
+	proto.RegisterType((*AuditEventList)(nil), "rollout.AuditEventList")
 	proto.RegisterType((*NamespaceInfo)(nil), "rollout.NamespaceInfo")
 	proto.RegisterType((*RolloutInfoList)(nil), "rollout.RolloutInfoList")
 	proto.RegisterType((*VersionInfo)(nil), "rollout.VersionInfo")
@@ -1769,123 +2002,141 @@ func init() {
 }
 
 var fileDescriptor_99101d942e8912a7 = []byte{
-	// 1848 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x59, 0x5f, 0x6f, 0x1c, 0x49,
-	0x11, 0xd7, 0x78, 0xbd, 0xf6, 0xba, 0xd6, 0x7f, 0xdb, 0x49, 0x6e, 0x6e, 0x2f, 0x58, 0xbe, 0x39,
-	0x24, 0x1c, 0x03, 0x33, 0x8e, 0x2f, 0xca, 0x71, 0xfc, 0x93, 0x8c, 0x63, 0xf9, 0x82, 0x92, 0xbb,
-	0x30, 0x06, 0x4e, 0x20, 0x41, 0xd4, 0x9e, 0x6d, 0xaf, 0x27, 0x99, 0x9d, 0x1e, 0xa6, 0x7b, 0x36,
-	0xac, 0x2c, 0x3f, 0xc0, 0x17, 0xe0, 0x81, 0xaf, 0xc0, 0x03, 0x3c, 0x21, 0x24, 0x5e, 0x78, 0xe0,
-	0x15, 0xf1, 0x88, 0xc4, 0x17, 0x40, 0x11, 0x02, 0xf1, 0xc0, 0x03, 0xdf, 0x00, 0x75, 0x4d, 0x4f,
-	0xcf, 0x1f, 0xaf, 0x1d, 0x47, 0x36, 0xe4, 0x9e, 0x76, 0xaa, 0xaa, 0xab, 0xea, 0xd7, 0xdd, 0x55,
-	0xd5, 0xdd, 0xb5, 0xf0, 0x5e, 0xf2, 0x7c, 0xe0, 0xd1, 0x24, 0x0c, 0xa2, 0x90, 0xc5, 0xd2, 0x4b,
-	0x79, 0x14, 0xf1, 0xcc, 0xfc, 0xba, 0x49, 0xca, 0x25, 0x27, 0xb3, 0x9a, 0xec, 0xdd, 0x1e, 0x70,
-	0x3e, 0x88, 0x98, 0x52, 0xf0, 0x68, 0x1c, 0x73, 0x49, 0x65, 0xc8, 0x63, 0x91, 0x0f, 0xeb, 0x3d,
-	0x1a, 0x84, 0xf2, 0x38, 0x3b, 0x74, 0x03, 0x3e, 0xf4, 0x68, 0x3a, 0xe0, 0x49, 0xca, 0x9f, 0xe1,
-	0xc7, 0x97, 0xb5, 0xbe, 0xf0, 0xb4, 0x37, 0xe1, 0x19, 0xce, 0xe8, 0x2e, 0x8d, 0x92, 0x63, 0x7a,
-	0xd7, 0x1b, 0xb0, 0x98, 0xa5, 0x54, 0xb2, 0xbe, 0xb6, 0x76, 0xef, 0xf9, 0x57, 0x84, 0x1b, 0x72,
-	0x35, 0x7c, 0x48, 0x83, 0xe3, 0x30, 0x66, 0xe9, 0xb8, 0xd4, 0x1f, 0x32, 0x49, 0xbd, 0xd1, 0x59,
-	0xad, 0x77, 0x34, 0x42, 0xa4, 0x0e, 0xb3, 0x23, 0x8f, 0x0d, 0x13, 0x39, 0xce, 0x85, 0xce, 0x03,
-	0x58, 0xf6, 0x73, 0xbf, 0x0f, 0xe3, 0x23, 0xfe, 0x9d, 0x8c, 0xa5, 0x63, 0x42, 0x60, 0x3a, 0xa6,
-	0x43, 0x66, 0x5b, 0xeb, 0xd6, 0xc6, 0x9c, 0x8f, 0xdf, 0xe4, 0x36, 0xcc, 0xa9, 0x5f, 0x91, 0xd0,
-	0x80, 0xd9, 0x53, 0x28, 0x28, 0x19, 0xce, 0x3d, 0xb8, 0x51, 0xb1, 0xf2, 0x28, 0x14, 0x32, 0xb7,
-	0x54, 0xd3, 0xb2, 0x9a, 0x5a, 0xbf, 0xb0, 0x60, 0xe9, 0x80, 0xc9, 0x87, 0x43, 0x3a, 0x60, 0x3e,
-	0xfb, 0x49, 0xc6, 0x84, 0x24, 0x36, 0x14, 0x2b, 0xab, 0xc7, 0x17, 0xa4, 0xb2, 0x15, 0xf0, 0x58,
-	0x52, 0x35, 0xeb, 0x02, 0x81, 0x61, 0x90, 0x1b, 0xd0, 0x0e, 0x95, 0x1d, 0xbb, 0x85, 0x92, 0x9c,
-	0x20, 0xcb, 0xd0, 0x92, 0x74, 0x60, 0x4f, 0x23, 0x4f, 0x7d, 0xd6, 0x11, 0xb5, 0x9b, 0x88, 0x8e,
-	0x81, 0x7c, 0x2f, 0xee, 0x73, 0x3d, 0x97, 0x57, 0x63, 0xea, 0x41, 0x27, 0x65, 0xa3, 0x50, 0x84,
-	0x3c, 0x46, 0x48, 0x2d, 0xdf, 0xd0, 0x75, 0x4f, 0xad, 0xa6, 0xa7, 0x87, 0x70, 0xd3, 0x67, 0x42,
-	0xd2, 0x54, 0x36, 0x9c, 0xbd, 0xfe, 0xe2, 0xff, 0x08, 0x6e, 0x3e, 0x49, 0xf9, 0x90, 0x4b, 0x76,
-	0x55, 0x53, 0x4a, 0xe3, 0x28, 0x8b, 0x22, 0x84, 0xdb, 0xf1, 0xf1, 0xdb, 0xd9, 0x87, 0xd5, 0x9d,
-	0x43, 0x7e, 0x0d, 0x38, 0xf7, 0x61, 0xd5, 0x67, 0x32, 0x1d, 0x5f, 0xd9, 0xd0, 0x53, 0x58, 0xd1,
-	0x36, 0x3e, 0xa5, 0x32, 0x38, 0xde, 0x1b, 0xb1, 0x18, 0xcd, 0xc8, 0x71, 0x62, 0xcc, 0xa8, 0x6f,
-	0x72, 0x1f, 0xba, 0x69, 0x19, 0x96, 0x68, 0xa8, 0xbb, 0x7d, 0xc3, 0x2d, 0x32, 0xb9, 0x12, 0xb2,
-	0x7e, 0x75, 0xa0, 0xf3, 0x14, 0x16, 0x3e, 0x2e, 0xbc, 0x29, 0xc6, 0xc5, 0x71, 0x4c, 0xb6, 0x60,
-	0x95, 0x8e, 0x68, 0x18, 0xd1, 0xc3, 0x88, 0x19, 0x3d, 0x61, 0x4f, 0xad, 0xb7, 0x36, 0xe6, 0xfc,
-	0x49, 0x22, 0x67, 0x17, 0x96, 0x1a, 0xf9, 0x42, 0xb6, 0xa0, 0x53, 0x14, 0x00, 0xdb, 0x5a, 0x6f,
-	0x9d, 0x0b, 0xd4, 0x8c, 0x72, 0x3e, 0x80, 0xee, 0xf7, 0x59, 0xaa, 0x62, 0x0d, 0x31, 0x6e, 0xc0,
-	0x52, 0x21, 0xd2, 0x6c, 0x8d, 0xb4, 0xc9, 0x76, 0xfe, 0x39, 0x03, 0xdd, 0x8a, 0x49, 0xf2, 0x04,
-	0x80, 0x1f, 0x3e, 0x63, 0x81, 0x7c, 0xcc, 0x24, 0x45, 0xa5, 0xee, 0xf6, 0x96, 0x9b, 0xd7, 0x1a,
-	0xb7, 0x5a, 0x6b, 0xdc, 0xe4, 0xf9, 0x40, 0x31, 0x84, 0xab, 0x6a, 0x8d, 0x3b, 0xba, 0xeb, 0x7e,
-	0x62, 0xf4, 0xfc, 0x8a, 0x0d, 0x72, 0x0b, 0x66, 0x84, 0xa4, 0x32, 0x13, 0x7a, 0xf3, 0x34, 0xa5,
-	0x32, 0x69, 0xc8, 0x84, 0x28, 0xf3, 0xb4, 0x20, 0xd5, 0xf6, 0x85, 0x01, 0x8f, 0x75, 0xaa, 0xe2,
-	0xb7, 0xca, 0x2e, 0x21, 0x55, 0x25, 0x1b, 0x8c, 0x75, 0xaa, 0x1a, 0x5a, 0x8d, 0x17, 0x92, 0x25,
-	0xf6, 0x4c, 0x3e, 0x5e, 0x7d, 0xab, 0x5d, 0x12, 0x4c, 0x7e, 0xca, 0xc2, 0xc1, 0xb1, 0xb4, 0x67,
-	0xf3, 0x5d, 0x32, 0x0c, 0xe2, 0xc0, 0x3c, 0x0d, 0x64, 0x46, 0x23, 0x3d, 0xa0, 0x83, 0x03, 0x6a,
-	0x3c, 0x55, 0x45, 0x52, 0x46, 0xfb, 0x63, 0x7b, 0x6e, 0xdd, 0xda, 0x68, 0xfb, 0x39, 0xa1, 0x50,
-	0x07, 0x59, 0x9a, 0xb2, 0x58, 0xda, 0x80, 0xfc, 0x82, 0x54, 0x92, 0x3e, 0x13, 0x61, 0xca, 0xfa,
-	0x76, 0x37, 0x97, 0x68, 0x52, 0x49, 0xb2, 0xa4, 0xaf, 0xaa, 0xb0, 0x3d, 0x9f, 0x4b, 0x34, 0xa9,
-	0x50, 0x9a, 0x90, 0xb0, 0x17, 0x50, 0x56, 0x32, 0xc8, 0x3a, 0x74, 0xd3, 0xbc, 0x2e, 0xb0, 0xfe,
-	0x8e, 0xb4, 0x17, 0x11, 0x64, 0x95, 0x45, 0xd6, 0x00, 0x74, 0x85, 0x57, 0x5b, 0xbc, 0x84, 0x03,
-	0x2a, 0x1c, 0xf2, 0xa1, 0xb2, 0x90, 0x44, 0x61, 0x40, 0x0f, 0x98, 0x14, 0xf6, 0x32, 0xc6, 0xd2,
-	0x5b, 0x65, 0x2c, 0x19, 0x99, 0x8e, 0xfb, 0x72, 0xac, 0x52, 0x65, 0x3f, 0x4d, 0x58, 0x1a, 0x0e,
-	0x59, 0x2c, 0x85, 0xbd, 0xd2, 0x50, 0xdd, 0x33, 0xb2, 0x5c, 0xb5, 0x32, 0x96, 0x7c, 0x1d, 0xe6,
-	0x69, 0x4c, 0xa3, 0xb1, 0x08, 0x85, 0x9f, 0xc5, 0xc2, 0x26, 0xa8, 0x6b, 0x1b, 0xdd, 0x9d, 0x52,
-	0x88, 0xca, 0xb5, 0xd1, 0xe4, 0x3e, 0x80, 0x29, 0xe5, 0xc2, 0x5e, 0x45, 0xdd, 0x5b, 0x46, 0x77,
-	0xb7, 0x10, 0xa1, 0x66, 0x65, 0x24, 0xf9, 0x31, 0xb4, 0xd5, 0xce, 0x0b, 0xfb, 0x06, 0xaa, 0x7c,
-	0xe4, 0x96, 0xc7, 0xad, 0x5b, 0x1c, 0xb7, 0xf8, 0xf1, 0xb4, 0xc8, 0x81, 0x32, 0x84, 0x0d, 0xa7,
-	0x38, 0x6e, 0xdd, 0x5d, 0x1a, 0xd3, 0x74, 0x7c, 0x20, 0x59, 0xe2, 0xe7, 0x66, 0xc9, 0x37, 0x61,
-	0x31, 0x8c, 0x43, 0xb9, 0x5b, 0x62, 0xbb, 0x79, 0x21, 0xb6, 0xc6, 0x68, 0xe7, 0x8f, 0x53, 0xb0,
-	0x58, 0x5f, 0xb5, 0xff, 0x41, 0xb2, 0x15, 0xa9, 0x33, 0x55, 0x4f, 0x1d, 0x73, 0x30, 0xb5, 0x1a,
-	0x07, 0x53, 0x99, 0x9c, 0xd3, 0xe7, 0x25, 0x67, 0xbb, 0x9e, 0x9c, 0x8d, 0x90, 0x9a, 0x79, 0x8d,
-	0x90, 0x6a, 0xc6, 0xc5, 0xec, 0xeb, 0xc4, 0x85, 0xf3, 0xeb, 0x69, 0x58, 0xac, 0x5b, 0xff, 0x3f,
-	0x16, 0xab, 0x62, 0x5d, 0x5b, 0xe7, 0xac, 0xeb, 0xf4, 0xc4, 0x75, 0x55, 0x59, 0xdd, 0xc6, 0xe3,
-	0x53, 0x53, 0x8a, 0x1f, 0x60, 0x64, 0x61, 0xb1, 0xea, 0xf8, 0x9a, 0x52, 0x7c, 0x1a, 0xc8, 0x70,
-	0xc4, 0xb0, 0x56, 0x75, 0x7c, 0x4d, 0xa9, 0x7d, 0x48, 0x94, 0x51, 0xf6, 0x02, 0x6b, 0x54, 0xc7,
-	0x2f, 0xc8, 0xdc, 0x3b, 0xae, 0x86, 0xd0, 0x15, 0xca, 0xd0, 0xf5, 0xb2, 0x02, 0xcd, 0xb2, 0xd2,
-	0x83, 0x8e, 0x64, 0xc3, 0x24, 0xa2, 0x92, 0x61, 0xa5, 0x9a, 0xf3, 0x0d, 0x4d, 0xbe, 0x04, 0x2b,
-	0x22, 0xa0, 0x11, 0x7b, 0xc0, 0x5f, 0xc4, 0x0f, 0x18, 0xed, 0x47, 0x61, 0xcc, 0xb0, 0x68, 0xcd,
-	0xf9, 0x67, 0x05, 0x0a, 0x35, 0xde, 0xad, 0x84, 0xbd, 0x80, 0xe7, 0x9b, 0xa6, 0xc8, 0xe7, 0x61,
-	0x3a, 0xe1, 0x7d, 0x61, 0x2f, 0xe2, 0x06, 0x2f, 0x9b, 0x0d, 0x7e, 0xc2, 0xfb, 0xb8, 0xb1, 0x28,
-	0x55, 0x6b, 0x9a, 0x84, 0xf1, 0x00, 0xcb, 0x56, 0xc7, 0xc7, 0x6f, 0xe4, 0xf1, 0x78, 0x60, 0x2f,
-	0x6b, 0x1e, 0x8f, 0x07, 0xea, 0x48, 0xad, 0xa5, 0xd2, 0xc3, 0xdc, 0xe5, 0x4a, 0x7e, 0xa4, 0x4e,
-	0x10, 0x39, 0x7f, 0xb0, 0x60, 0x56, 0xfb, 0x7a, 0xc3, 0x31, 0x62, 0x0e, 0x91, 0x3c, 0xbd, 0xf4,
-	0x21, 0x82, 0x7b, 0x87, 0x55, 0x5c, 0x60, 0x7c, 0xe0, 0xde, 0xe5, 0xb4, 0xf3, 0x21, 0x2c, 0xd4,
-	0xea, 0xc8, 0xc4, 0x3b, 0x91, 0xb9, 0xe1, 0x4e, 0x55, 0x6e, 0xb8, 0xce, 0x7f, 0x2c, 0x98, 0xfd,
-	0x36, 0x3f, 0xfc, 0x0c, 0x4c, 0x7b, 0x0d, 0x60, 0xc8, 0x64, 0x1a, 0x06, 0xea, 0x9e, 0xa3, 0xe7,
-	0x5e, 0xe1, 0x90, 0x8f, 0x60, 0xae, 0x3c, 0xd7, 0xda, 0x08, 0x6e, 0xf3, 0x72, 0xe0, 0xbe, 0x1b,
-	0x0e, 0x99, 0x5f, 0x2a, 0x3b, 0xff, 0xb0, 0xc0, 0xae, 0xd4, 0x8d, 0x83, 0x84, 0x05, 0x3b, 0x71,
-	0xff, 0x20, 0x87, 0x46, 0x61, 0x5a, 0x24, 0x2c, 0xd0, 0xd3, 0x7f, 0x7c, 0xb5, 0x13, 0xa1, 0xe1,
-	0xc5, 0x47, 0xd3, 0x64, 0x50, 0x5b, 0x95, 0xee, 0xf6, 0x27, 0xd7, 0xe7, 0x04, 0xcd, 0x16, 0xcb,
-	0xec, 0xfc, 0xbb, 0x05, 0x4b, 0x8d, 0x02, 0xf9, 0x19, 0x3e, 0x3f, 0xd6, 0x00, 0x44, 0x16, 0x04,
-	0x4c, 0x88, 0xa3, 0x2c, 0xd2, 0x31, 0x5e, 0xe1, 0x28, 0xbd, 0x23, 0x1a, 0x46, 0xac, 0x8f, 0x75,
-	0xb0, 0xed, 0x6b, 0x4a, 0x5d, 0xcc, 0xc2, 0x38, 0xe0, 0x71, 0x10, 0x65, 0xa2, 0xa8, 0x86, 0x6d,
-	0xbf, 0xc6, 0x53, 0xc1, 0xcf, 0xd2, 0x94, 0xa7, 0x58, 0x11, 0xdb, 0x7e, 0x4e, 0xa8, 0x9a, 0xf3,
-	0x8c, 0x1f, 0xaa, 0x5a, 0x58, 0xaf, 0x39, 0x3a, 0x21, 0x7c, 0x94, 0x92, 0xf7, 0x01, 0x62, 0x1e,
-	0x6b, 0x9e, 0x0d, 0x38, 0x76, 0xd5, 0x8c, 0xfd, 0xd8, 0x88, 0xfc, 0xca, 0x30, 0xb2, 0xa9, 0x0e,
-	0x43, 0x15, 0xbb, 0xc2, 0xee, 0x36, 0xac, 0x3f, 0xce, 0xf9, 0x7e, 0x31, 0x80, 0xec, 0xc3, 0x82,
-	0xa8, 0xc6, 0x20, 0x16, 0xcf, 0xee, 0xf6, 0xbb, 0x93, 0x0e, 0xb9, 0x5a, 0xb0, 0xfa, 0x75, 0x3d,
-	0xe7, 0x57, 0x16, 0x40, 0x89, 0x47, 0x4d, 0x7a, 0x44, 0xa3, 0xac, 0x28, 0x03, 0x39, 0x71, 0x6e,
-	0x4e, 0xd6, 0xf3, 0xaf, 0x75, 0x71, 0xfe, 0x4d, 0x5f, 0x25, 0xff, 0x7e, 0x67, 0xc1, 0xac, 0x5e,
-	0x84, 0x89, 0x95, 0x6a, 0x13, 0x96, 0xf5, 0xb6, 0xef, 0xf2, 0xb8, 0x1f, 0xca, 0xd0, 0x04, 0xd7,
-	0x19, 0xbe, 0x9a, 0x63, 0xc0, 0xb3, 0x58, 0x22, 0xe0, 0xb6, 0x9f, 0x13, 0xea, 0x48, 0xaa, 0x6e,
-	0xff, 0xa3, 0x70, 0x18, 0xe6, 0x98, 0xdb, 0xfe, 0x59, 0x81, 0x0a, 0x20, 0x15, 0x4a, 0x59, 0xaa,
-	0x07, 0xe6, 0xa1, 0x57, 0xe3, 0x6d, 0xff, 0x6b, 0x01, 0x16, 0xf5, 0x9b, 0xe7, 0x80, 0xa5, 0xa3,
-	0x30, 0x60, 0x44, 0xc0, 0xe2, 0x3e, 0x93, 0xd5, 0x87, 0xd0, 0xdb, 0x93, 0x5e, 0x5c, 0xd8, 0xc9,
-	0xe8, 0x4d, 0x7c, 0x8c, 0x39, 0x5b, 0x3f, 0xff, 0xeb, 0xdf, 0x7f, 0x39, 0xb5, 0x49, 0x36, 0xb0,
-	0xfd, 0x33, 0xba, 0x5b, 0xf6, 0x70, 0x4e, 0xcc, 0xf3, 0xf0, 0x34, 0xff, 0x3e, 0xf5, 0x42, 0xe5,
-	0xe2, 0x14, 0x96, 0xf1, 0xd1, 0x7a, 0x25, 0xb7, 0xf7, 0xd1, 0xed, 0x16, 0x71, 0x2f, 0xeb, 0xd6,
-	0x7b, 0xa1, 0x7c, 0x6e, 0x59, 0x64, 0x04, 0xcb, 0xea, 0xb5, 0x59, 0x31, 0x26, 0xc8, 0xe7, 0x26,
-	0xf9, 0x30, 0x3d, 0x9c, 0x9e, 0x7d, 0x9e, 0xd8, 0xb9, 0x83, 0x30, 0xde, 0x23, 0xef, 0x5e, 0x08,
-	0x03, 0xa7, 0xfd, 0x33, 0x0b, 0x56, 0x9a, 0xf3, 0x7e, 0xa5, 0xe7, 0x5e, 0x53, 0x5c, 0x3e, 0xf7,
-	0x1d, 0x0f, 0x7d, 0xdf, 0x21, 0x5f, 0x78, 0xa5, 0x6f, 0x33, 0xf7, 0x1f, 0xc0, 0xfc, 0x3e, 0x93,
-	0xe6, 0x15, 0x4e, 0x6e, 0xb9, 0x79, 0x63, 0xcc, 0x2d, 0x1a, 0x63, 0xee, 0xde, 0x30, 0x91, 0xe3,
-	0x5e, 0x79, 0xb9, 0xaf, 0x35, 0x01, 0x9c, 0xb7, 0xd1, 0xe5, 0x2a, 0x59, 0x29, 0x5c, 0x96, 0x1d,
-	0x80, 0xdf, 0x5a, 0xea, 0x9e, 0x5a, 0x6d, 0xe7, 0x90, 0xb5, 0xca, 0xf5, 0x78, 0x42, 0x9f, 0xa7,
-	0xb7, 0x77, 0xb5, 0x43, 0x43, 0x5b, 0x2b, 0x42, 0xa1, 0xf7, 0xc5, 0xcb, 0x84, 0x82, 0xbe, 0x70,
-	0x7c, 0xd5, 0xda, 0x44, 0xc4, 0xf5, 0xae, 0x51, 0x05, 0xf1, 0xc4, 0x76, 0xd2, 0x1b, 0x41, 0x9c,
-	0xe4, 0x48, 0x14, 0xe2, 0xdf, 0x58, 0x30, 0x5f, 0x6d, 0x44, 0x91, 0xdb, 0x65, 0x7d, 0x3d, 0xdb,
-	0x9f, 0xba, 0x2e, 0xb4, 0xf7, 0x10, 0xad, 0xdb, 0xbb, 0x73, 0x19, 0xb4, 0x54, 0xe1, 0x50, 0x58,
-	0xff, 0x94, 0x77, 0x36, 0x8b, 0xa8, 0xc6, 0x5e, 0x64, 0x99, 0x47, 0x8d, 0x9e, 0xe7, 0x75, 0x41,
-	0xf5, 0x11, 0xea, 0xa3, 0xde, 0xfe, 0xc5, 0x50, 0x35, 0xf7, 0xd4, 0x13, 0x4c, 0x7a, 0x27, 0xe6,
-	0x31, 0x7d, 0xea, 0x9d, 0xe0, 0x8d, 0xf2, 0x1b, 0x9b, 0x9b, 0xa7, 0xde, 0x89, 0xa4, 0x83, 0x53,
-	0x35, 0x91, 0xdf, 0x5b, 0xd0, 0xad, 0x74, 0x44, 0xc9, 0x3b, 0x66, 0x12, 0x67, 0xfb, 0xa4, 0xd7,
-	0x35, 0x8f, 0x1d, 0x9c, 0xc7, 0xd7, 0x7a, 0xf7, 0x2f, 0x39, 0x8f, 0x2c, 0xee, 0x73, 0xef, 0xa4,
-	0xb8, 0x9e, 0x9c, 0x16, 0xb1, 0x52, 0xed, 0x35, 0x56, 0x62, 0x65, 0x42, 0x0b, 0xf2, 0x8d, 0xc4,
-	0x4a, 0xaa, 0x70, 0x28, 0xac, 0x4f, 0x60, 0x56, 0x37, 0xe6, 0xce, 0xad, 0x48, 0xe5, 0x29, 0x50,
-	0x69, 0xf8, 0x39, 0x6f, 0xa1, 0xbb, 0x15, 0xb2, 0x54, 0xb8, 0x1b, 0xe5, 0xc2, 0x6f, 0xed, 0xfd,
-	0xf9, 0xe5, 0x9a, 0xf5, 0x97, 0x97, 0x6b, 0xd6, 0xdf, 0x5e, 0xae, 0x59, 0x3f, 0xfc, 0xe0, 0xd2,
-	0x7f, 0x41, 0xd4, 0xff, 0xf0, 0x38, 0x9c, 0x41, 0x14, 0xef, 0xff, 0x37, 0x00, 0x00, 0xff, 0xff,
-	0x6c, 0x69, 0x4c, 0x07, 0x10, 0x19, 0x00, 0x00,
+	// 2140 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x59, 0x4b, 0x8f, 0x1c, 0x49,
+	0xf1, 0x57, 0x4d, 0x77, 0x7b, 0x7a, 0xa2, 0x3d, 0xaf, 0x1c, 0x3f, 0x6a, 0xdb, 0xde, 0xd1, 0x6c,
+	0xfd, 0x57, 0xfa, 0x0f, 0x63, 0xe8, 0x1e, 0xcf, 0xae, 0xbc, 0x2c, 0xec, 0x22, 0x79, 0x6d, 0x6b,
+	0x6c, 0x34, 0x7e, 0x6c, 0x8d, 0x97, 0x15, 0x96, 0xc0, 0xca, 0xa9, 0xce, 0xe9, 0x29, 0xbb, 0xba,
+	0xb2, 0xa8, 0xcc, 0x6a, 0xd3, 0xb2, 0x46, 0x48, 0x88, 0xfb, 0x1e, 0xf8, 0x02, 0x48, 0x70, 0x80,
+	0x13, 0x42, 0xe2, 0x82, 0x04, 0x1c, 0x11, 0x47, 0x24, 0x8e, 0x5c, 0x90, 0x85, 0xb8, 0x71, 0xe0,
+	0x1b, 0xa0, 0x88, 0xca, 0x7a, 0x4e, 0xdb, 0x1e, 0x6b, 0x0c, 0xde, 0x53, 0x57, 0x44, 0x64, 0x3c,
+	0x2a, 0xf2, 0x97, 0x11, 0x59, 0xd1, 0xf0, 0x7f, 0xd1, 0xe3, 0x61, 0x9f, 0x47, 0xbe, 0x17, 0xf8,
+	0x22, 0xd4, 0xfd, 0x58, 0x06, 0x81, 0x4c, 0xf2, 0xdf, 0x5e, 0x14, 0x4b, 0x2d, 0xd9, 0xac, 0x21,
+	0xbb, 0x17, 0x87, 0x52, 0x0e, 0x03, 0x81, 0x0a, 0x7d, 0x1e, 0x86, 0x52, 0x73, 0xed, 0xcb, 0x50,
+	0xa5, 0xcb, 0xba, 0x3b, 0x43, 0x5f, 0x1f, 0x24, 0x7b, 0x3d, 0x4f, 0x8e, 0xfa, 0x3c, 0x1e, 0xca,
+	0x28, 0x96, 0x8f, 0xe8, 0xe1, 0x6b, 0x46, 0x5f, 0xf5, 0x8d, 0x37, 0xd5, 0xcf, 0x39, 0xe3, 0xcb,
+	0x3c, 0x88, 0x0e, 0xf8, 0xe5, 0xfe, 0x50, 0x84, 0x22, 0xe6, 0x5a, 0x0c, 0x8c, 0xb5, 0xf7, 0x1f,
+	0x7f, 0x5d, 0xf5, 0x7c, 0x89, 0xcb, 0x47, 0xdc, 0x3b, 0xf0, 0x43, 0x11, 0x4f, 0x0a, 0xfd, 0x91,
+	0xd0, 0xbc, 0x3f, 0x3e, 0xaa, 0x75, 0xc1, 0x44, 0x48, 0xd4, 0x5e, 0xb2, 0xdf, 0x17, 0xa3, 0x48,
+	0x4f, 0x52, 0xa1, 0x73, 0x1d, 0x96, 0xdc, 0xd4, 0xef, 0xad, 0x70, 0x5f, 0x7e, 0x9a, 0x88, 0x78,
+	0xc2, 0x18, 0x34, 0x43, 0x3e, 0x12, 0xb6, 0xb5, 0x66, 0xad, 0xcf, 0xb9, 0xf4, 0xcc, 0x2e, 0xc2,
+	0x1c, 0xfe, 0xaa, 0x88, 0x7b, 0xc2, 0x9e, 0x21, 0x41, 0xc1, 0x70, 0xfe, 0x68, 0xc1, 0x99, 0x92,
+	0x99, 0x1d, 0x5f, 0xe9, 0xd4, 0x54, 0x45, 0xcd, 0xaa, 0xa9, 0xb1, 0x77, 0x61, 0x3e, 0xe0, 0x7b,
+	0x22, 0xd8, 0x15, 0x81, 0xf0, 0xb4, 0x8c, 0x8d, 0xe1, 0x2a, 0x93, 0x9d, 0x81, 0x56, 0x74, 0xc0,
+	0x95, 0xb0, 0x1b, 0x24, 0x4d, 0x09, 0xd6, 0x85, 0xb6, 0xd2, 0xf8, 0x9a, 0xc3, 0x89, 0xdd, 0x24,
+	0x41, 0x4e, 0xa3, 0x46, 0xe0, 0x8f, 0x7c, 0x6d, 0xb7, 0xd6, 0xac, 0xf5, 0x86, 0x9b, 0x12, 0xa8,
+	0xe1, 0xc9, 0x50, 0xfb, 0x61, 0x22, 0xec, 0x53, 0xa9, 0x46, 0x46, 0x3b, 0x5f, 0x58, 0xb0, 0xb8,
+	0x2b, 0xf4, 0xad, 0x11, 0x1f, 0x0a, 0x57, 0xfc, 0x20, 0x11, 0x4a, 0x33, 0x1b, 0xb2, 0x4d, 0x36,
+	0x91, 0x67, 0x24, 0xbe, 0x15, 0x6a, 0x72, 0xdc, 0x80, 0x2c, 0x19, 0x39, 0x03, 0xbd, 0xfb, 0x68,
+	0x27, 0x8b, 0x97, 0x08, 0xb6, 0x04, 0x0d, 0xcd, 0x87, 0x26, 0x54, 0x7c, 0xac, 0xe6, 0xa6, 0x55,
+	0x4f, 0xe9, 0x01, 0xb0, 0xcf, 0xc2, 0x81, 0x34, 0x59, 0x7d, 0x79, 0x4c, 0x5d, 0x68, 0xc7, 0x62,
+	0xec, 0x2b, 0x5f, 0x86, 0x14, 0x52, 0xc3, 0xcd, 0xe9, 0xaa, 0xa7, 0x46, 0xdd, 0xd3, 0x2d, 0x38,
+	0xeb, 0x0a, 0xa5, 0x79, 0xac, 0x6b, 0xce, 0x5e, 0x1d, 0x07, 0xdf, 0x83, 0xb3, 0xf7, 0x62, 0x39,
+	0x92, 0x5a, 0x9c, 0xd4, 0x14, 0x6a, 0xec, 0x27, 0x41, 0x40, 0xe1, 0xb6, 0x5d, 0x7a, 0x76, 0xb6,
+	0x61, 0xe5, 0xea, 0x9e, 0x7c, 0x0d, 0x71, 0x6e, 0xc3, 0x8a, 0x2b, 0x74, 0x3c, 0x39, 0xb1, 0xa1,
+	0x87, 0xb0, 0x6c, 0x6c, 0x7c, 0xce, 0xb5, 0x77, 0x70, 0x63, 0x2c, 0x42, 0x32, 0xa3, 0x27, 0x51,
+	0x6e, 0x06, 0x9f, 0xd9, 0x15, 0xe8, 0xc4, 0xc5, 0x01, 0x21, 0x43, 0x9d, 0xad, 0x33, 0xbd, 0xac,
+	0xa8, 0x94, 0x0e, 0x8f, 0x5b, 0x5e, 0xe8, 0xfc, 0xde, 0x02, 0xb8, 0x9a, 0x0c, 0x7c, 0x9d, 0x9a,
+	0x3e, 0x07, 0xa7, 0xb8, 0x87, 0x05, 0xc6, 0x18, 0x37, 0x14, 0xba, 0x4c, 0x54, 0x0e, 0x46, 0x7a,
+	0x66, 0x37, 0x61, 0x4e, 0xfb, 0x23, 0xdc, 0xd9, 0x51, 0x44, 0x69, 0xec, 0x6c, 0x6d, 0xf4, 0xd2,
+	0x0a, 0xd2, 0x2b, 0x57, 0x90, 0x5e, 0xf4, 0x78, 0x88, 0x0c, 0xd5, 0xc3, 0x0a, 0xd2, 0x1b, 0x5f,
+	0xee, 0xdd, 0xf7, 0x47, 0xc2, 0x2d, 0x94, 0xd9, 0x2a, 0x40, 0x14, 0xfb, 0x32, 0xde, 0xd5, 0x5c,
+	0x0b, 0x03, 0xe1, 0x12, 0x07, 0x51, 0x39, 0x12, 0x4a, 0x21, 0xe6, 0x53, 0x1c, 0x67, 0xa4, 0xf3,
+	0x31, 0x2c, 0x14, 0xd1, 0x63, 0x59, 0x60, 0x97, 0xe0, 0x94, 0x40, 0x42, 0xd9, 0xd6, 0x5a, 0x63,
+	0xbd, 0xb3, 0xb5, 0x92, 0xe7, 0xa0, 0x58, 0xe8, 0x9a, 0x25, 0xce, 0xdf, 0x2c, 0x98, 0xbf, 0x93,
+	0x25, 0x1b, 0xf3, 0xf1, 0x92, 0x82, 0xb2, 0x09, 0x2b, 0x7c, 0xcc, 0xfd, 0x80, 0xef, 0x05, 0x22,
+	0xd7, 0x53, 0xf6, 0xcc, 0x5a, 0x63, 0x7d, 0xce, 0x9d, 0x26, 0x4a, 0x8f, 0x0d, 0x1f, 0xdc, 0x0d,
+	0x83, 0x89, 0x81, 0x5a, 0x4e, 0xa3, 0xb5, 0xdc, 0x34, 0x9e, 0x90, 0xd8, 0xf7, 0xb4, 0x18, 0xd0,
+	0xfb, 0xb7, 0xdd, 0x69, 0x22, 0xf6, 0x55, 0x58, 0x8e, 0xe4, 0x60, 0x47, 0x0e, 0x3f, 0x73, 0x77,
+	0xee, 0x8b, 0x51, 0x14, 0x60, 0xbe, 0xd2, 0x94, 0x1c, 0x15, 0x38, 0x0f, 0x61, 0xb1, 0x56, 0x34,
+	0xd9, 0x26, 0xb4, 0xb3, 0x36, 0x60, 0xf2, 0x33, 0x1d, 0x23, 0xf9, 0xaa, 0x4a, 0x55, 0x9b, 0xa9,
+	0x55, 0xb5, 0x0f, 0xa0, 0xf3, 0x1d, 0x11, 0x63, 0x09, 0xa0, 0xdc, 0xad, 0xc3, 0x62, 0xa6, 0x66,
+	0xd8, 0x26, 0x83, 0x75, 0xb6, 0xf3, 0x93, 0x36, 0x74, 0x4a, 0xee, 0xd8, 0x3d, 0x00, 0xb9, 0xf7,
+	0x48, 0x78, 0xfa, 0xb6, 0xd0, 0x9c, 0x94, 0x3a, 0x5b, 0x9b, 0xc7, 0xc3, 0xd2, 0xdd, 0x5c, 0xcf,
+	0x2d, 0xd9, 0x40, 0x20, 0x2b, 0xcd, 0x75, 0xa2, 0x4c, 0xd0, 0x86, 0x2a, 0x43, 0xa9, 0x51, 0x81,
+	0x12, 0x42, 0xdc, 0xf7, 0x64, 0x68, 0xe0, 0x47, 0xcf, 0x95, 0x26, 0xd0, 0xaa, 0x35, 0x01, 0x06,
+	0x4d, 0xa5, 0x45, 0x64, 0x4a, 0x3d, 0x3d, 0x23, 0x7a, 0x94, 0xd0, 0x9f, 0x0b, 0x7f, 0x78, 0xa0,
+	0xed, 0xd9, 0x14, 0x3d, 0x39, 0x83, 0x39, 0x70, 0x9a, 0x7b, 0x3a, 0xe1, 0x81, 0x59, 0xd0, 0xa6,
+	0x05, 0x15, 0x1e, 0x16, 0x77, 0xc4, 0xc7, 0xc4, 0x9e, 0x5b, 0xb3, 0xd6, 0x5b, 0x6e, 0x4a, 0x60,
+	0xd4, 0x5e, 0x12, 0xc7, 0x22, 0xd4, 0x36, 0x10, 0x3f, 0x23, 0x51, 0x32, 0x10, 0xca, 0x8f, 0xc5,
+	0xc0, 0xee, 0xa4, 0x12, 0x43, 0xa2, 0x24, 0x89, 0x06, 0xd8, 0xa7, 0xed, 0xd3, 0xa9, 0xc4, 0x90,
+	0x18, 0x65, 0x0e, 0x55, 0x7b, 0x9e, 0x64, 0x05, 0x83, 0xad, 0x41, 0x27, 0x4e, 0xcb, 0xb5, 0x18,
+	0x5c, 0xd5, 0xf6, 0x02, 0x05, 0x59, 0x66, 0xe1, 0x71, 0x35, 0x77, 0x00, 0xdc, 0xe2, 0xc5, 0xf4,
+	0xb8, 0x16, 0x1c, 0xf6, 0x21, 0x5a, 0x88, 0x02, 0xdf, 0xe3, 0xbb, 0x42, 0x2b, 0x7b, 0x89, 0x70,
+	0x76, 0xbe, 0xc0, 0x59, 0x2e, 0x33, 0xe5, 0xa8, 0x58, 0x8b, 0xaa, 0xe2, 0x87, 0x91, 0x88, 0xfd,
+	0x11, 0x1d, 0xe1, 0xe5, 0x9a, 0xea, 0x8d, 0x5c, 0x96, 0xaa, 0x96, 0xd6, 0xb2, 0x8f, 0xe0, 0x34,
+	0x0f, 0x79, 0x30, 0x51, 0xbe, 0x72, 0x93, 0x50, 0xd9, 0x8c, 0x74, 0xed, 0xe2, 0xf8, 0x17, 0x42,
+	0x52, 0xae, 0xac, 0x66, 0x57, 0x00, 0xf2, 0x0e, 0xab, 0xec, 0x15, 0xd2, 0x3d, 0x97, 0xeb, 0x5e,
+	0xcb, 0x44, 0xa4, 0x59, 0x5a, 0xc9, 0xbe, 0x0f, 0x2d, 0xdc, 0x79, 0x65, 0x9f, 0x21, 0x95, 0x9b,
+	0xbd, 0xe2, 0x42, 0xd6, 0xcb, 0x2e, 0x64, 0xf4, 0xf0, 0x30, 0x3b, 0x03, 0x05, 0x84, 0x73, 0x4e,
+	0x76, 0x21, 0xeb, 0x5d, 0xe3, 0x21, 0x8f, 0x27, 0xbb, 0x5a, 0x44, 0x6e, 0x6a, 0x96, 0x7d, 0x0b,
+	0x16, 0xfc, 0xd0, 0xd7, 0xd7, 0x8a, 0xd8, 0xce, 0xbe, 0x30, 0xb6, 0xda, 0x6a, 0xf6, 0x04, 0xe6,
+	0x9f, 0x10, 0xb2, 0x6e, 0xfa, 0x4a, 0xcb, 0x78, 0x62, 0x9f, 0x23, 0xf5, 0x4f, 0x4f, 0x16, 0xe7,
+	0xfd, 0x98, 0xef, 0xef, 0xfb, 0x5e, 0x8a, 0x59, 0x57, 0x78, 0x32, 0x1e, 0xb8, 0x55, 0x3f, 0x08,
+	0x76, 0x7c, 0x83, 0x7b, 0xb1, 0x1c, 0xc6, 0x42, 0x29, 0xfb, 0x7c, 0x0a, 0xf6, 0x32, 0xcf, 0xf9,
+	0xc3, 0x0c, 0x2c, 0x54, 0xb7, 0xf4, 0xbf, 0x50, 0x09, 0xb2, 0x73, 0x3d, 0x53, 0x3d, 0xd7, 0xf9,
+	0x65, 0xa6, 0x51, 0xbb, 0xcc, 0x14, 0x95, 0xa3, 0xf9, 0xbc, 0xca, 0x51, 0x6d, 0x42, 0x75, 0xbc,
+	0x9f, 0x7a, 0x05, 0xbc, 0xd7, 0x41, 0x3b, 0xfb, 0x2a, 0xa0, 0x75, 0x7e, 0xd9, 0x84, 0x85, 0xaa,
+	0xf5, 0xff, 0x61, 0x25, 0xcd, 0xf2, 0xda, 0x78, 0x4e, 0x5e, 0x9b, 0x53, 0xf3, 0x8a, 0x25, 0xa7,
+	0x45, 0x0d, 0xce, 0x50, 0xc8, 0xf7, 0x08, 0xf6, 0x54, 0x49, 0xdb, 0xae, 0xa1, 0xb2, 0xab, 0xc8,
+	0x58, 0x50, 0x21, 0x6d, 0xbb, 0x86, 0xc2, 0x7d, 0x88, 0xd0, 0xa8, 0x78, 0x42, 0x05, 0xb4, 0xed,
+	0x66, 0x64, 0xea, 0x9d, 0xb2, 0xa1, 0x4c, 0xf9, 0xcc, 0xe9, 0x6a, 0xcd, 0x83, 0x7a, 0xcd, 0xeb,
+	0x42, 0x5b, 0x67, 0xed, 0xb4, 0x93, 0xd6, 0xf9, 0x8c, 0xc6, 0x9e, 0xab, 0x3c, 0x1e, 0x88, 0xeb,
+	0xf2, 0x49, 0x78, 0x5d, 0xf0, 0x41, 0xe0, 0x87, 0x82, 0x2a, 0xea, 0x9c, 0x7b, 0x54, 0x80, 0x51,
+	0xd3, 0x7d, 0x5c, 0xd9, 0xf3, 0x74, 0x29, 0x30, 0x14, 0x7b, 0x17, 0x9a, 0x91, 0x1c, 0x28, 0x7b,
+	0x81, 0x36, 0x78, 0x29, 0xdf, 0xe0, 0x7b, 0x72, 0x40, 0x1b, 0x4b, 0x52, 0xcc, 0x69, 0xe4, 0x87,
+	0x43, 0xaa, 0xa9, 0x6d, 0x97, 0x9e, 0x89, 0x27, 0xc3, 0xa1, 0xbd, 0x64, 0x78, 0x32, 0x1c, 0xe2,
+	0xcd, 0xa1, 0x72, 0xce, 0x6f, 0xa5, 0x2e, 0x97, 0xd3, 0x7b, 0xc8, 0x14, 0x91, 0xf3, 0x3b, 0x0b,
+	0x66, 0x8d, 0xaf, 0x37, 0x8c, 0x91, 0xbc, 0xc3, 0xa5, 0xc7, 0xcb, 0x74, 0x38, 0xda, 0x3b, 0x6a,
+	0x31, 0x8a, 0xf0, 0x41, 0x7b, 0x97, 0xd2, 0xce, 0x87, 0x30, 0x5f, 0x29, 0x72, 0x53, 0xef, 0xd1,
+	0xf9, 0x57, 0xd1, 0x4c, 0xe9, 0xab, 0xc8, 0xf9, 0xb7, 0x05, 0xb3, 0xdf, 0x96, 0x7b, 0x5f, 0x82,
+	0xd7, 0x5e, 0x05, 0x18, 0x09, 0xbc, 0xc7, 0xe1, 0xe5, 0x30, 0xbb, 0xe3, 0x16, 0x1c, 0xbc, 0x4d,
+	0x17, 0x4d, 0xb7, 0xf5, 0xea, 0xb7, 0xe9, 0x5c, 0xd9, 0xf9, 0xa7, 0x05, 0x76, 0xa9, 0x6e, 0xec,
+	0x46, 0xc2, 0xbb, 0x1a, 0x0e, 0x76, 0xd3, 0xd0, 0x38, 0x34, 0x55, 0x24, 0x3c, 0xf3, 0xfa, 0xb7,
+	0x4f, 0xd6, 0x06, 0x6a, 0x5e, 0x5c, 0x32, 0xcd, 0x86, 0x95, 0xac, 0x74, 0xb6, 0xee, 0xbe, 0x3e,
+	0x27, 0x64, 0x36, 0x4b, 0xb3, 0xf3, 0xaf, 0x06, 0x2c, 0xd6, 0x0a, 0xe4, 0x97, 0xb8, 0x7f, 0xac,
+	0x02, 0xa8, 0xc4, 0xf3, 0x84, 0x52, 0xfb, 0x49, 0x60, 0x30, 0x5e, 0xe2, 0xa0, 0xde, 0x3e, 0xf7,
+	0x03, 0x31, 0xa0, 0x3a, 0xd8, 0x72, 0x0d, 0x85, 0x8d, 0xd4, 0x0f, 0x3d, 0x19, 0x7a, 0x41, 0xa2,
+	0xb2, 0x6a, 0xd8, 0x72, 0x2b, 0x3c, 0x04, 0xbf, 0x88, 0x63, 0x19, 0x53, 0x45, 0x6c, 0xb9, 0x29,
+	0x81, 0x35, 0xe7, 0x91, 0xdc, 0xc3, 0x5a, 0x58, 0xad, 0x39, 0xe6, 0x40, 0xb8, 0x24, 0x65, 0xef,
+	0x01, 0x84, 0x32, 0x34, 0x3c, 0x1b, 0x6a, 0x1f, 0x4d, 0x77, 0x72, 0x91, 0x5b, 0x5a, 0xc6, 0x36,
+	0xb0, 0x19, 0x22, 0x76, 0x95, 0xdd, 0xa9, 0x59, 0xbf, 0x9d, 0xf2, 0xdd, 0x6c, 0x01, 0xdb, 0x86,
+	0x79, 0x55, 0xc6, 0x20, 0x15, 0xcf, 0xce, 0xd6, 0x3b, 0xd3, 0x9a, 0x5c, 0x05, 0xac, 0x6e, 0x55,
+	0xcf, 0xf9, 0x85, 0x05, 0x50, 0xc4, 0x83, 0x2f, 0x3d, 0xe6, 0x41, 0x92, 0x95, 0x81, 0x94, 0x78,
+	0xee, 0x99, 0xac, 0x9e, 0xbf, 0xc6, 0x8b, 0xcf, 0x5f, 0xf3, 0x24, 0xe7, 0xef, 0x37, 0x16, 0xcc,
+	0x9a, 0x24, 0x4c, 0xad, 0x54, 0x1b, 0xb0, 0x64, 0xb6, 0xfd, 0x9a, 0x0c, 0x07, 0xbe, 0xf6, 0x73,
+	0x70, 0x1d, 0xe1, 0xe3, 0x3b, 0x7a, 0x32, 0x09, 0x35, 0x05, 0xdc, 0x72, 0x53, 0x02, 0x5b, 0x52,
+	0x79, 0xfb, 0x77, 0x68, 0x16, 0xd5, 0xa4, 0x15, 0x47, 0x05, 0x08, 0x20, 0x84, 0x52, 0x12, 0x9b,
+	0x85, 0x29, 0xf4, 0x2a, 0xbc, 0xad, 0x9f, 0x2f, 0xc2, 0x82, 0xf9, 0x20, 0xdb, 0x15, 0xf1, 0xd8,
+	0xf7, 0x04, 0x53, 0xb0, 0xb0, 0x2d, 0x74, 0xf9, 0x2b, 0xed, 0xad, 0x69, 0x9f, 0x8a, 0x34, 0x87,
+	0xeb, 0x4e, 0xfd, 0x8a, 0x74, 0x36, 0x7f, 0xfc, 0xd7, 0x7f, 0xfc, 0x74, 0x66, 0x83, 0xad, 0xd3,
+	0xf4, 0x72, 0x7c, 0xb9, 0x18, 0x41, 0x3e, 0xcd, 0xbf, 0x6e, 0x0f, 0xd3, 0xe7, 0xc3, 0xbe, 0x8f,
+	0x2e, 0x0e, 0x61, 0x89, 0x06, 0x1d, 0x27, 0x72, 0x7b, 0x85, 0xdc, 0x6e, 0xb2, 0xde, 0x71, 0xdd,
+	0xf6, 0x9f, 0xa0, 0xcf, 0x4d, 0x8b, 0x7d, 0x61, 0xc1, 0x12, 0x7e, 0x27, 0x97, 0xac, 0x29, 0xf6,
+	0xf6, 0x34, 0x27, 0xf9, 0x08, 0xb2, 0x6b, 0x3f, 0x4f, 0xec, 0x7c, 0x42, 0x71, 0x7c, 0xc4, 0xde,
+	0x79, 0x61, 0x1c, 0x18, 0xc0, 0x83, 0xf3, 0xec, 0xec, 0x91, 0x45, 0x94, 0x90, 0x9f, 0x59, 0xb0,
+	0x5c, 0xcf, 0xc8, 0x4b, 0x43, 0xea, 0xd6, 0xc5, 0xc5, 0xf0, 0xc8, 0xb9, 0x43, 0x41, 0xdd, 0x64,
+	0xff, 0xff, 0xd2, 0xa0, 0xd2, 0xac, 0x3c, 0x78, 0x9b, 0x5d, 0x98, 0x1a, 0x5a, 0x9e, 0xb4, 0xef,
+	0xc2, 0xe9, 0x6d, 0xa1, 0xf3, 0x99, 0x07, 0x3b, 0xd7, 0x4b, 0x07, 0xc2, 0xbd, 0x6c, 0x20, 0xdc,
+	0xbb, 0x31, 0x8a, 0xf4, 0xa4, 0x5b, 0x7c, 0xb2, 0x54, 0x46, 0x2e, 0xce, 0x5b, 0x14, 0xd1, 0x0a,
+	0x5b, 0xce, 0xdc, 0x14, 0xf3, 0x96, 0x5f, 0x5b, 0x78, 0xc1, 0x2d, 0xcf, 0x0e, 0xd9, 0x6a, 0xe9,
+	0x5e, 0x3d, 0x65, 0xa8, 0xd8, 0xbd, 0x71, 0xb2, 0x6e, 0x63, 0xac, 0x65, 0x18, 0xea, 0x5e, 0x3a,
+	0x0e, 0x86, 0xcc, 0x4d, 0xe5, 0x1b, 0xd6, 0x06, 0x45, 0x5c, 0x1d, 0x51, 0x96, 0x22, 0x9e, 0x3a,
+	0xbb, 0x7c, 0x23, 0x11, 0x47, 0x69, 0x24, 0x18, 0xf1, 0xaf, 0x2c, 0x38, 0x5d, 0x9e, 0x7a, 0xb2,
+	0x8b, 0x45, 0x61, 0x3e, 0x3a, 0x0c, 0x7d, 0x5d, 0xd1, 0xbe, 0x4f, 0xd1, 0xf6, 0xba, 0x5f, 0x39,
+	0x4e, 0xb4, 0x1c, 0xe3, 0xc0, 0x58, 0xff, 0x94, 0x8e, 0xd1, 0x33, 0xd0, 0xd3, 0xe0, 0xbb, 0x38,
+	0x7f, 0xb5, 0x01, 0xfb, 0xeb, 0x0a, 0xd5, 0xa5, 0x50, 0x77, 0xba, 0xdb, 0x2f, 0x0e, 0xd5, 0x70,
+	0x0f, 0xfb, 0x4a, 0xe8, 0xfe, 0xd3, 0x7c, 0x44, 0x70, 0xd8, 0x7f, 0x4a, 0x57, 0xd1, 0x8f, 0x37,
+	0x36, 0x0e, 0xfb, 0x4f, 0x35, 0x1f, 0x1e, 0xe2, 0x8b, 0xfc, 0xd6, 0x82, 0x4e, 0x69, 0xfc, 0xce,
+	0x2e, 0xe4, 0x2f, 0x71, 0x74, 0x28, 0xff, 0xba, 0xde, 0xe3, 0x2a, 0xbd, 0xc7, 0x37, 0xbb, 0x57,
+	0x8e, 0xf9, 0x1e, 0x49, 0x38, 0x90, 0xfd, 0xa7, 0xd9, 0xbd, 0xe6, 0x30, 0xc3, 0x4a, 0x79, 0xb0,
+	0x5d, 0xc2, 0xca, 0x94, 0x79, 0xf7, 0x1b, 0xc1, 0x4a, 0x8c, 0x71, 0x60, 0xac, 0xf7, 0x60, 0xd6,
+	0x8c, 0x1b, 0x9f, 0x5b, 0x91, 0x8a, 0xf6, 0x51, 0x1a, 0x63, 0x3a, 0xe7, 0xc9, 0xdd, 0x32, 0x5b,
+	0xcc, 0xdc, 0x8d, 0x8d, 0x99, 0x1f, 0xc1, 0xb9, 0x52, 0x73, 0x28, 0xc6, 0xc9, 0xea, 0x45, 0x2d,
+	0xea, 0xfc, 0x94, 0xf9, 0x33, 0x75, 0x87, 0xcb, 0xe4, 0xe6, 0x12, 0x3b, 0xde, 0x09, 0x40, 0xdd,
+	0x4f, 0x6e, 0xfc, 0xf9, 0xd9, 0xaa, 0xf5, 0x97, 0x67, 0xab, 0xd6, 0xdf, 0x9f, 0xad, 0x5a, 0x0f,
+	0x3e, 0x38, 0xf6, 0x7f, 0x7f, 0xd5, 0x7f, 0x1a, 0xf7, 0x4e, 0x51, 0x1a, 0xde, 0xfb, 0x4f, 0x00,
+	0x00, 0x00, 0xff, 0xff, 0x23, 0x38, 0xcf, 0xd3, 0x89, 0x1c, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1912,6 +2163,7 @@ type RolloutServiceClient interface {
 	UndoRollout(ctx context.Context, in *UndoRolloutRequest, opts ...grpc.CallOption) (*v1alpha1.Rollout, error)
 	RetryRollout(ctx context.Context, in *RetryRolloutRequest, opts ...grpc.CallOption) (*v1alpha1.Rollout, error)
 	Version(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*VersionInfo, error)
+	ListRolloutAuditEvents(ctx context.Context, in *RolloutInfoQuery, opts ...grpc.CallOption) (*AuditEventList, error)
 }
 
 type rolloutServiceClient struct {
@@ -2076,6 +2328,15 @@ func (c *rolloutServiceClient) Version(ctx context.Context, in *emptypb.Empty, o
 	return out, nil
 }
 
+func (c *rolloutServiceClient) ListRolloutAuditEvents(ctx context.Context, in *RolloutInfoQuery, opts ...grpc.CallOption) (*AuditEventList, error) {
+	out := new(AuditEventList)
+	err := c.cc.Invoke(ctx, "/rollout.RolloutService/ListRolloutAuditEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RolloutServiceServer is the server API for RolloutService service.
 type RolloutServiceServer interface {
 	GetRolloutInfo(context.Context, *RolloutInfoQuery) (*RolloutInfo, error)
@@ -2090,6 +2351,7 @@ type RolloutServiceServer interface {
 	UndoRollout(context.Context, *UndoRolloutRequest) (*v1alpha1.Rollout, error)
 	RetryRollout(context.Context, *RetryRolloutRequest) (*v1alpha1.Rollout, error)
 	Version(context.Context, *emptypb.Empty) (*VersionInfo, error)
+	ListRolloutAuditEvents(context.Context, *RolloutInfoQuery) (*AuditEventList, error)
 }
 
 // UnimplementedRolloutServiceServer can be embedded to have forward compatible implementations.
@@ -2132,6 +2394,9 @@ func (*UnimplementedRolloutServiceServer) RetryRollout(ctx context.Context, req
 func (*UnimplementedRolloutServiceServer) Version(ctx context.Context, req *emptypb.Empty) (*VersionInfo, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Version not implemented")
 }
+func (*UnimplementedRolloutServiceServer) ListRolloutAuditEvents(ctx context.Context, req *RolloutInfoQuery) (*AuditEventList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRolloutAuditEvents not implemented")
+}
 
 func RegisterRolloutServiceServer(s *grpc.Server, srv RolloutServiceServer) {
 	s.RegisterService(&_RolloutService_serviceDesc, srv)
@@ -2359,6 +2624,24 @@ func _RolloutService_Version_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RolloutService_ListRolloutAuditEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RolloutInfoQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RolloutServiceServer).ListRolloutAuditEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rollout.RolloutService/ListRolloutAuditEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RolloutServiceServer).ListRolloutAuditEvents(ctx, req.(*RolloutInfoQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _RolloutService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "rollout.RolloutService",
 	HandlerType: (*RolloutServiceServer)(nil),
@@ -2403,6 +2686,10 @@ var _RolloutService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Version",
 			Handler:    _RolloutService_Version_Handler,
 		},
+		{
+			MethodName: "ListRolloutAuditEvents",
+			Handler:    _RolloutService_ListRolloutAuditEvents_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -2484,6 +2771,39 @@ func (m *RolloutInfoListQuery) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.Continue) > 0 {
+		i -= len(m.Continue)
+		copy(dAtA[i:], m.Continue)
+		i = encodeVarintRollout(dAtA, i, uint64(len(m.Continue)))
+		i--
+		dAtA[i] = 0x32
+	}
+	if m.Limit != 0 {
+		i = encodeVarintRollout(dAtA, i, uint64(m.Limit))
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.Strategy) > 0 {
+		i -= len(m.Strategy)
+		copy(dAtA[i:], m.Strategy)
+		i = encodeVarintRollout(dAtA, i, uint64(len(m.Strategy)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Phase) > 0 {
+		i -= len(m.Phase)
+		copy(dAtA[i:], m.Phase)
+		i = encodeVarintRollout(dAtA, i, uint64(len(m.Phase)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.LabelSelector) > 0 {
+		i -= len(m.LabelSelector)
+		copy(dAtA[i:], m.LabelSelector)
+		i = encodeVarintRollout(dAtA, i, uint64(len(m.LabelSelector)))
+		i--
+		dAtA[i] = 0x12
+	}
 	if len(m.Namespace) > 0 {
 		i -= len(m.Namespace)
 		copy(dAtA[i:], m.Namespace)
@@ -2822,7 +3142,7 @@ func (m *RolloutWatchEvent) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *NamespaceInfo) Marshal() (dAtA []byte, err error) {
+func (m *AuditEvent) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -2832,12 +3152,12 @@ func (m *NamespaceInfo) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *NamespaceInfo) MarshalTo(dAtA []byte) (int, error) {
+func (m *AuditEvent) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *NamespaceInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *AuditEvent) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -2846,26 +3166,50 @@ func (m *NamespaceInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	if len(m.AvailableNamespaces) > 0 {
-		for iNdEx := len(m.AvailableNamespaces) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.AvailableNamespaces[iNdEx])
-			copy(dAtA[i:], m.AvailableNamespaces[iNdEx])
-			i = encodeVarintRollout(dAtA, i, uint64(len(m.AvailableNamespaces[iNdEx])))
-			i--
-			dAtA[i] = 0x12
+	if len(m.Message) > 0 {
+		i -= len(m.Message)
+		copy(dAtA[i:], m.Message)
+		i = encodeVarintRollout(dAtA, i, uint64(len(m.Message)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.PriorState) > 0 {
+		i -= len(m.PriorState)
+		copy(dAtA[i:], m.PriorState)
+		i = encodeVarintRollout(dAtA, i, uint64(len(m.PriorState)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.Timestamp != nil {
+		{
+			size, err := m.Timestamp.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRollout(dAtA, i, uint64(size))
 		}
+		i--
+		dAtA[i] = 0x1a
 	}
-	if len(m.Namespace) > 0 {
-		i -= len(m.Namespace)
-		copy(dAtA[i:], m.Namespace)
-		i = encodeVarintRollout(dAtA, i, uint64(len(m.Namespace)))
+	if len(m.User) > 0 {
+		i -= len(m.User)
+		copy(dAtA[i:], m.User)
+		i = encodeVarintRollout(dAtA, i, uint64(len(m.User)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Action) > 0 {
+		i -= len(m.Action)
+		copy(dAtA[i:], m.Action)
+		i = encodeVarintRollout(dAtA, i, uint64(len(m.Action)))
 		i--
 		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *RolloutInfoList) Marshal() (dAtA []byte, err error) {
+func (m *AuditEventList) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -2875,12 +3219,12 @@ func (m *RolloutInfoList) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *RolloutInfoList) MarshalTo(dAtA []byte) (int, error) {
+func (m *AuditEventList) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *RolloutInfoList) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *AuditEventList) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -2889,10 +3233,10 @@ func (m *RolloutInfoList) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	if len(m.Rollouts) > 0 {
-		for iNdEx := len(m.Rollouts) - 1; iNdEx >= 0; iNdEx-- {
+	if len(m.Events) > 0 {
+		for iNdEx := len(m.Events) - 1; iNdEx >= 0; iNdEx-- {
 			{
-				size, err := m.Rollouts[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				size, err := m.Events[iNdEx].MarshalToSizedBuffer(dAtA[:i])
 				if err != nil {
 					return 0, err
 				}
@@ -2906,7 +3250,7 @@ func (m *RolloutInfoList) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *VersionInfo) Marshal() (dAtA []byte, err error) {
+func (m *NamespaceInfo) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -2916,12 +3260,130 @@ func (m *VersionInfo) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *VersionInfo) MarshalTo(dAtA []byte) (int, error) {
+func (m *NamespaceInfo) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *VersionInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *NamespaceInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.PodLogURLTemplate) > 0 {
+		i -= len(m.PodLogURLTemplate)
+		copy(dAtA[i:], m.PodLogURLTemplate)
+		i = encodeVarintRollout(dAtA, i, uint64(len(m.PodLogURLTemplate)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.NamespaceRestricted {
+		i--
+		if m.NamespaceRestricted {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.ReadOnly {
+		i--
+		if m.ReadOnly {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.AvailableNamespaces) > 0 {
+		for iNdEx := len(m.AvailableNamespaces) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.AvailableNamespaces[iNdEx])
+			copy(dAtA[i:], m.AvailableNamespaces[iNdEx])
+			i = encodeVarintRollout(dAtA, i, uint64(len(m.AvailableNamespaces[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Namespace) > 0 {
+		i -= len(m.Namespace)
+		copy(dAtA[i:], m.Namespace)
+		i = encodeVarintRollout(dAtA, i, uint64(len(m.Namespace)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *RolloutInfoList) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RolloutInfoList) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RolloutInfoList) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Continue) > 0 {
+		i -= len(m.Continue)
+		copy(dAtA[i:], m.Continue)
+		i = encodeVarintRollout(dAtA, i, uint64(len(m.Continue)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Rollouts) > 0 {
+		for iNdEx := len(m.Rollouts) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Rollouts[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRollout(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *VersionInfo) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *VersionInfo) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *VersionInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -2964,6 +3426,31 @@ func (m *RolloutInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.StepProgress) > 0 {
+		i -= len(m.StepProgress)
+		copy(dAtA[i:], m.StepProgress)
+		i = encodeVarintRollout(dAtA, i, uint64(len(m.StepProgress)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xba
+	}
+	if len(m.WeightHistory) > 0 {
+		for iNdEx := len(m.WeightHistory) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.WeightHistory[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRollout(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1
+			i--
+			dAtA[i] = 0xb2
+		}
+	}
 	if len(m.InitContainers) > 0 {
 		for iNdEx := len(m.InitContainers) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -3952,6 +4439,25 @@ func (m *RolloutInfoListQuery) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovRollout(uint64(l))
 	}
+	l = len(m.LabelSelector)
+	if l > 0 {
+		n += 1 + l + sovRollout(uint64(l))
+	}
+	l = len(m.Phase)
+	if l > 0 {
+		n += 1 + l + sovRollout(uint64(l))
+	}
+	l = len(m.Strategy)
+	if l > 0 {
+		n += 1 + l + sovRollout(uint64(l))
+	}
+	if m.Limit != 0 {
+		n += 1 + sovRollout(uint64(m.Limit))
+	}
+	l = len(m.Continue)
+	if l > 0 {
+		n += 1 + l + sovRollout(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -4116,6 +4622,56 @@ func (m *RolloutWatchEvent) Size() (n int) {
 	return n
 }
 
+func (m *AuditEvent) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Action)
+	if l > 0 {
+		n += 1 + l + sovRollout(uint64(l))
+	}
+	l = len(m.User)
+	if l > 0 {
+		n += 1 + l + sovRollout(uint64(l))
+	}
+	if m.Timestamp != nil {
+		l = m.Timestamp.Size()
+		n += 1 + l + sovRollout(uint64(l))
+	}
+	l = len(m.PriorState)
+	if l > 0 {
+		n += 1 + l + sovRollout(uint64(l))
+	}
+	l = len(m.Message)
+	if l > 0 {
+		n += 1 + l + sovRollout(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *AuditEventList) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Events) > 0 {
+		for _, e := range m.Events {
+			l = e.Size()
+			n += 1 + l + sovRollout(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func (m *NamespaceInfo) Size() (n int) {
 	if m == nil {
 		return 0
@@ -4132,6 +4688,16 @@ func (m *NamespaceInfo) Size() (n int) {
 			n += 1 + l + sovRollout(uint64(l))
 		}
 	}
+	if m.ReadOnly {
+		n += 2
+	}
+	if m.NamespaceRestricted {
+		n += 2
+	}
+	l = len(m.PodLogURLTemplate)
+	if l > 0 {
+		n += 1 + l + sovRollout(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -4150,6 +4716,10 @@ func (m *RolloutInfoList) Size() (n int) {
 			n += 1 + l + sovRollout(uint64(l))
 		}
 	}
+	l = len(m.Continue)
+	if l > 0 {
+		n += 1 + l + sovRollout(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -4269,6 +4839,16 @@ func (m *RolloutInfo) Size() (n int) {
 			n += 2 + l + sovRollout(uint64(l))
 		}
 	}
+	if len(m.WeightHistory) > 0 {
+		for _, e := range m.WeightHistory {
+			l = e.Size()
+			n += 2 + l + sovRollout(uint64(l))
+		}
+	}
+	l = len(m.StepProgress)
+	if l > 0 {
+		n += 2 + l + sovRollout(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -4798,60 +5378,9 @@ func (m *RolloutInfoListQuery) Unmarshal(dAtA []byte) error {
 			}
 			m.Namespace = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipRollout(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthRollout
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *SetImageRequest) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowRollout
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: SetImageRequest: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SetImageRequest: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Rollout", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field LabelSelector", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -4879,11 +5408,11 @@ func (m *SetImageRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Rollout = string(dAtA[iNdEx:postIndex])
+			m.LabelSelector = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 2:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Container", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Phase", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -4911,11 +5440,209 @@ func (m *SetImageRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Container = string(dAtA[iNdEx:postIndex])
+			m.Phase = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 3:
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Image", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Strategy", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollout
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRollout
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollout
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Strategy = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+			}
+			m.Limit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollout
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Limit |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Continue", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollout
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRollout
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollout
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Continue = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRollout(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthRollout
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SetImageRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRollout
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SetImageRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SetImageRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rollout", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollout
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRollout
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollout
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Rollout = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Container", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollout
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRollout
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollout
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Container = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Image", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -5514,54 +6241,388 @@ func (m *AbortRolloutRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthRollout
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RetryRolloutRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRollout
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RetryRolloutRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RetryRolloutRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollout
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRollout
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollout
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Namespace", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollout
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRollout
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollout
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Namespace = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRollout(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthRollout
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RolloutWatchEvent) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRollout
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RolloutWatchEvent: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RolloutWatchEvent: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollout
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRollout
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollout
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Type = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RolloutInfo", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollout
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRollout
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollout
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.RolloutInfo == nil {
+				m.RolloutInfo = &RolloutInfo{}
+			}
+			if err := m.RolloutInfo.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRollout(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthRollout
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AuditEvent) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRollout
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AuditEvent: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AuditEvent: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Action", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollout
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRollout
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollout
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Action = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field User", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollout
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRollout
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollout
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.User = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollout
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
 				return ErrInvalidLengthRollout
 			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *RetryRolloutRequest) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowRollout
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollout
 			}
-			if iNdEx >= l {
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
+			if m.Timestamp == nil {
+				m.Timestamp = &v1.Time{}
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: RetryRolloutRequest: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: RetryRolloutRequest: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			if err := m.Timestamp.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field PriorState", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -5589,11 +6650,11 @@ func (m *RetryRolloutRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Name = string(dAtA[iNdEx:postIndex])
+			m.PriorState = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 2:
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Namespace", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -5621,7 +6682,7 @@ func (m *RetryRolloutRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Namespace = string(dAtA[iNdEx:postIndex])
+			m.Message = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -5645,7 +6706,7 @@ func (m *RetryRolloutRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *RolloutWatchEvent) Unmarshal(dAtA []byte) error {
+func (m *AuditEventList) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5668,47 +6729,15 @@ func (m *RolloutWatchEvent) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: RolloutWatchEvent: wiretype end group for non-group")
+			return fmt.Errorf("proto: AuditEventList: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: RolloutWatchEvent: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: AuditEventList: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowRollout
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthRollout
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthRollout
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Type = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field RolloutInfo", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Events", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -5735,10 +6764,8 @@ func (m *RolloutWatchEvent) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.RolloutInfo == nil {
-				m.RolloutInfo = &RolloutInfo{}
-			}
-			if err := m.RolloutInfo.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Events = append(m.Events, &AuditEvent{})
+			if err := m.Events[len(m.Events)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -5857,6 +6884,78 @@ func (m *NamespaceInfo) Unmarshal(dAtA []byte) error {
 			}
 			m.AvailableNamespaces = append(m.AvailableNamespaces, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReadOnly", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollout
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ReadOnly = bool(v != 0)
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NamespaceRestricted", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollout
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.NamespaceRestricted = bool(v != 0)
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PodLogURLTemplate", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollout
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRollout
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollout
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PodLogURLTemplate = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRollout(dAtA[iNdEx:])
@@ -5942,6 +7041,38 @@ func (m *RolloutInfoList) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Continue", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollout
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRollout
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollout
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Continue = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRollout(dAtA[iNdEx:])
@@ -6699,6 +7830,72 @@ func (m *RolloutInfo) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 22:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WeightHistory", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollout
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRollout
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollout
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.WeightHistory = append(m.WeightHistory, &v1alpha1.TrafficWeightRecord{})
+			if err := m.WeightHistory[len(m.WeightHistory)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 23:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StepProgress", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollout
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRollout
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollout
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StepProgress = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRollout(dAtA[iNdEx:])
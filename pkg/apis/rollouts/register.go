@@ -43,4 +43,14 @@ const (
 	AnalysisRunSingular string = "analysisrun"
 	AnalysisRunPlural   string = "analysisruns"
 	AnalysisRunFullName string = AnalysisRunPlural + "." + Group
+
+	RolloutNotificationPolicyKind     string = "RolloutNotificationPolicy"
+	RolloutNotificationPolicySingular string = "rolloutnotificationpolicy"
+	RolloutNotificationPolicyPlural   string = "rolloutnotificationpolicies"
+	RolloutNotificationPolicyFullName string = RolloutNotificationPolicyPlural + "." + Group
+
+	RolloutControllerConfigKind     string = "RolloutControllerConfig"
+	RolloutControllerConfigSingular string = "rolloutcontrollerconfig"
+	RolloutControllerConfigPlural   string = "rolloutcontrollerconfigs"
+	RolloutControllerConfigFullName string = RolloutControllerConfigPlural + "." + Group
 )
@@ -198,6 +198,8 @@ type MetricProvider struct {
 	// +kubebuilder:validation:Type=object
 	// Plugin specifies the hashicorp go-plugin metric to query
 	Plugin map[string]json.RawMessage `json:"plugin,omitempty" protobuf:"bytes,12,opt,name=plugin"`
+	// AzureMonitor specifies the Azure Monitor metric to query
+	AzureMonitor *AzureMonitorMetric `json:"azureMonitor,omitempty" protobuf:"bytes,13,opt,name=azureMonitor"`
 }
 
 // AnalysisPhase is the overall phase of an AnalysisRun, MetricResult, or Measurement
@@ -332,6 +334,36 @@ type InfluxdbMetric struct {
 type CloudWatchMetric struct {
 	Interval          DurationString              `json:"interval,omitempty" protobuf:"bytes,1,opt,name=interval,casttype=DurationString"`
 	MetricDataQueries []CloudWatchMetricDataQuery `json:"metricDataQueries" protobuf:"bytes,2,rep,name=metricDataQueries"`
+	// Region is the AWS region of the metrics. Defaults to the region of the controller
+	// +optional
+	Region string `json:"region,omitempty" protobuf:"bytes,3,opt,name=region"`
+	// RoleARN is the IAM role assumed to query the metrics, on top of the credentials of the controller such as
+	// IAM roles for service accounts or EKS Pod Identity
+	// +optional
+	RoleARN string `json:"roleArn,omitempty" protobuf:"bytes,4,opt,name=roleArn"`
+}
+
+// AzureMonitorMetric defines the Azure Monitor platform metric to query. The metric is queried with the credentials of
+// the controller, such as Azure Workload Identity.
+type AzureMonitorMetric struct {
+	// ResourceID is the ID of the Azure resource of the metric, e.g.
+	// /subscriptions/<id>/resourceGroups/<group>/providers/Microsoft.Network/applicationGateways/<name>
+	ResourceID string `json:"resourceId" protobuf:"bytes,1,opt,name=resourceId"`
+	// MetricName is the name of the metric
+	MetricName string `json:"metricName" protobuf:"bytes,2,opt,name=metricName"`
+	// MetricNamespace is the namespace of the metric. Defaults to the namespace of the type of the resource
+	// +optional
+	MetricNamespace string `json:"metricNamespace,omitempty" protobuf:"bytes,3,opt,name=metricNamespace"`
+	// Aggregation of the metric over the interval. Defaults to Average
+	// +kubebuilder:validation:Enum=Average;Total;Minimum;Maximum;Count
+	// +optional
+	Aggregation string `json:"aggregation,omitempty" protobuf:"bytes,4,opt,name=aggregation"`
+	// Filter selects the time series of the metric by their dimensions, e.g. "StatusCode eq '500'"
+	// +optional
+	Filter string `json:"filter,omitempty" protobuf:"bytes,5,opt,name=filter"`
+	// Interval is the time range the metric is aggregated over, ending at the time of the measurement. Defaults to 5m
+	// +optional
+	Interval DurationString `json:"interval,omitempty" protobuf:"bytes,6,opt,name=interval,casttype=DurationString"`
 }
 
 // CloudWatchMetricDataQuery defines the cloudwatch query
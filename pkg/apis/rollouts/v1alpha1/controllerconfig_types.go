@@ -0,0 +1,69 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RolloutControllerConfig sets the defaults and restrictions of the rollouts of its namespace, so that the
+// administrators of a multi-tenant cluster can configure the controller per tenant
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:path=rolloutcontrollerconfigs,shortName=rcc
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time since resource was created"
+type RolloutControllerConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Spec RolloutControllerConfigSpec `json:"spec" protobuf:"bytes,2,opt,name=spec"`
+}
+
+// RolloutControllerConfigList is a list of RolloutControllerConfig resources
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type RolloutControllerConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata" protobuf:"bytes,1,opt,name=metadata"`
+	Items           []RolloutControllerConfig `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// RolloutControllerConfigSpec is the specification for a RolloutControllerConfig resource
+type RolloutControllerConfigSpec struct {
+	// Defaults are the defaults of the unset fields of the rollouts of the namespace, which apply instead of the
+	// defaults of the controller
+	// +optional
+	Defaults *RolloutDefaults `json:"defaults,omitempty" protobuf:"bytes,1,opt,name=defaults"`
+	// Restrictions are enforced by the controller on the rollouts of the namespace, which are reported as invalid
+	// when they violate a restriction
+	// +optional
+	Restrictions *RolloutRestrictions `json:"restrictions,omitempty" protobuf:"bytes,2,opt,name=restrictions"`
+}
+
+// RolloutDefaults are the defaults of the unset fields of the rollouts of a namespace
+type RolloutDefaults struct {
+	// ProgressDeadlineSeconds is the default of spec.progressDeadlineSeconds
+	// +optional
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty" protobuf:"varint,1,opt,name=progressDeadlineSeconds"`
+	// RevisionHistoryLimit is the default of spec.revisionHistoryLimit
+	// +optional
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty" protobuf:"varint,2,opt,name=revisionHistoryLimit"`
+	// ScaleDownDelaySeconds is the default of the scaleDownDelaySeconds of the blue-green strategy, and of the
+	// canary strategy with traffic routing
+	// +optional
+	ScaleDownDelaySeconds *int32 `json:"scaleDownDelaySeconds,omitempty" protobuf:"varint,3,opt,name=scaleDownDelaySeconds"`
+}
+
+// RolloutRestrictions are the restrictions of the rollouts of a namespace
+type RolloutRestrictions struct {
+	// AllowedTrafficRouters are the traffic routers the rollouts may use: istio, nginx, alb, smi, ambassador,
+	// appMesh, traefik, apisix, knative, or the name of a traffic router plugin. All the traffic routers are allowed
+	// if omitted
+	// +optional
+	AllowedTrafficRouters []string `json:"allowedTrafficRouters,omitempty" protobuf:"bytes,1,rep,name=allowedTrafficRouters"`
+	// MaxCanaryWeightJump is the maximum increase of the canary weight from one setWeight step to the next,
+	// starting from a weight of 0 and ending with a weight of 100
+	// +optional
+	MaxCanaryWeightJump *int32 `json:"maxCanaryWeightJump,omitempty" protobuf:"varint,2,opt,name=maxCanaryWeightJump"`
+	// RequireAnalysis requires the canary rollouts to run a background analysis or an analysis step, and the
+	// blue-green rollouts to run a pre-promotion or post-promotion analysis
+	// +optional
+	RequireAnalysis bool `json:"requireAnalysis,omitempty" protobuf:"varint,3,opt,name=requireAnalysis"`
+}
@@ -68,6 +68,72 @@ type ExperimentSpec struct {
 	// AnalysisRunMetadata labels and annotations that will be added to the AnalysisRuns
 	// +optional
 	AnalysisRunMetadata AnalysisRunMetadata `json:"analysisRunMetadata,omitempty" protobuf:"bytes,9,opt,name=analysisRunMetadata"`
+	// Paused holds a running experiment open. The time spent paused does not count towards the duration,
+	// and the experiment does not complete until it is resumed by resetting paused to false.
+	// +optional
+	Paused bool `json:"paused,omitempty" protobuf:"varint,10,opt,name=paused"`
+	// Extension extends the duration of the experiment by a duration string (e.g. 30s, 5m, 1h).
+	// +optional
+	Extension DurationString `json:"extension,omitempty" protobuf:"bytes,11,opt,name=extension,casttype=DurationString"`
+	// WinnerSelection selects the template which won the experiment from the analyses of the templates, once the
+	// experiment completed successfully
+	// +optional
+	WinnerSelection *ExperimentWinnerSelection `json:"winnerSelection,omitempty" protobuf:"bytes,12,opt,name=winnerSelection"`
+	// StartAt delays the start of the experiment until the given time. The ReplicaSets of the templates are not
+	// created before then.
+	// +optional
+	StartAt *metav1.Time `json:"startAt,omitempty" protobuf:"bytes,13,opt,name=startAt"`
+	// Window restricts the start of the experiment to the windows of a cron schedule, e.g. to the hours of
+	// representative traffic
+	// +optional
+	Window *ExperimentWindow `json:"window,omitempty" protobuf:"bytes,14,opt,name=window"`
+	// TTLStrategy object contains the strategy for the time to live of the experiment after it completed
+	// +optional
+	TTLStrategy *TTLStrategy `json:"ttlStrategy,omitempty" protobuf:"bytes,15,opt,name=ttlStrategy"`
+}
+
+// ExperimentWindow defines the recurring windows in which an experiment can start
+type ExperimentWindow struct {
+	// Schedule is a cron expression of the start of the windows (e.g. "0 9 * * 1-5" for 9am on weekdays)
+	Schedule string `json:"schedule" protobuf:"bytes,1,opt,name=schedule"`
+	// Duration the length of a window as a duration string (e.g. 30m, 8h)
+	Duration DurationString `json:"duration" protobuf:"bytes,2,opt,name=duration,casttype=DurationString"`
+	// TimeZone is the IANA time zone of the schedule (e.g. America/New_York). Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty" protobuf:"bytes,3,opt,name=timeZone"`
+}
+
+// WinnerSelectionGoal defines whether the highest or the lowest measurement wins an experiment
+type WinnerSelectionGoal string
+
+const (
+	// WinnerSelectionGoalMaximize selects the candidate with the highest measurement
+	WinnerSelectionGoalMaximize WinnerSelectionGoal = "Maximize"
+	// WinnerSelectionGoalMinimize selects the candidate with the lowest measurement
+	WinnerSelectionGoalMinimize WinnerSelectionGoal = "Minimize"
+)
+
+// ExperimentWinnerSelection defines how the template which won an experiment is selected. A candidate whose analysis
+// does not succeed loses the experiment, instead of failing it.
+type ExperimentWinnerSelection struct {
+	// Candidates are the templates which can win the experiment, each with the analysis which scores it
+	Candidates []ExperimentWinnerCandidate `json:"candidates" protobuf:"bytes,1,rep,name=candidates"`
+	// MetricName is the name of the metric of the candidate analyses whose last measurement ranks the candidates.
+	// If omitted, the first candidate whose analysis succeeded wins.
+	// +optional
+	MetricName string `json:"metricName,omitempty" protobuf:"bytes,2,opt,name=metricName"`
+	// Goal is whether the candidate with the highest (Maximize) or the lowest (Minimize) measurement wins.
+	// Defaults to Maximize.
+	// +optional
+	Goal WinnerSelectionGoal `json:"goal,omitempty" protobuf:"bytes,3,opt,name=goal,casttype=WinnerSelectionGoal"`
+}
+
+// ExperimentWinnerCandidate pairs a template of an experiment with the analysis which scores it
+type ExperimentWinnerCandidate struct {
+	// TemplateName is the name of the template
+	TemplateName string `json:"templateName" protobuf:"bytes,1,opt,name=templateName"`
+	// AnalysisName is the name of the analysis of the experiment which scores the template
+	AnalysisName string `json:"analysisName" protobuf:"bytes,2,opt,name=analysisName"`
 }
 
 type TemplateSpec struct {
@@ -84,12 +150,53 @@ type TemplateSpec struct {
 	MinReadySeconds int32 `json:"minReadySeconds,omitempty" protobuf:"varint,3,opt,name=minReadySeconds"`
 	// Label selector for pods. Existing ReplicaSets whose pods are
 	// selected by this will be the ones affected by this experiment.
-	// It must match the pod template's labels. Each selector must be unique to the other selectors in the other templates
+	// It must match the pod template's labels. Each selector must be unique to the other selectors in the other templates.
+	// When the template references a workload, the workload must not select the pods of the template.
 	Selector *metav1.LabelSelector `json:"selector" protobuf:"bytes,4,opt,name=selector"`
-	// Template describes the pods that will be created.
-	Template corev1.PodTemplateSpec `json:"template" protobuf:"bytes,5,opt,name=template"`
+	// Template describes the pods that will be created. Must be empty when the template references a workload.
+	// +optional
+	Template corev1.PodTemplateSpec `json:"template,omitempty" protobuf:"bytes,5,opt,name=template"`
 	// TemplateService describes how a service should be generated for template
 	Service *TemplateService `json:"service,omitempty" protobuf:"bytes,6,opt,name=service"`
+	// WorkloadRef references a Deployment or Rollout whose pod template is used instead of the template
+	// +optional
+	WorkloadRef *ExperimentWorkloadRef `json:"workloadRef,omitempty" protobuf:"bytes,7,opt,name=workloadRef"`
+	// Autoscaling attaches a HorizontalPodAutoscaler to the ReplicaSet of the template, which scales it with the load
+	// while the experiment is running
+	// +optional
+	Autoscaling *TemplateAutoscaling `json:"autoscaling,omitempty" protobuf:"bytes,8,opt,name=autoscaling"`
+}
+
+// TemplateAutoscaling defines the HorizontalPodAutoscaler of the ReplicaSet of an experiment template
+type TemplateAutoscaling struct {
+	// MinReplicas is the lower limit of the replicas the autoscaler can scale down to. Defaults to the replicas of
+	// the template.
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty" protobuf:"varint,1,opt,name=minReplicas"`
+	// MaxReplicas is the upper limit of the replicas the autoscaler can scale up to
+	MaxReplicas int32 `json:"maxReplicas" protobuf:"varint,2,opt,name=maxReplicas"`
+	// TargetCPUUtilizationPercentage is the target average CPU utilization of the pods, as a percentage of their
+	// requested CPU. Defaults to 80 if no target is set.
+	// +optional
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty" protobuf:"varint,3,opt,name=targetCPUUtilizationPercentage"`
+	// TargetMemoryUtilizationPercentage is the target average memory utilization of the pods, as a percentage of
+	// their requested memory
+	// +optional
+	TargetMemoryUtilizationPercentage *int32 `json:"targetMemoryUtilizationPercentage,omitempty" protobuf:"varint,4,opt,name=targetMemoryUtilizationPercentage"`
+}
+
+// ExperimentWorkloadRef references the workload whose pod template is used by an experiment template
+type ExperimentWorkloadRef struct {
+	// APIVersion of the workload (e.g. apps/v1 or argoproj.io/v1alpha1)
+	APIVersion string `json:"apiVersion" protobuf:"bytes,1,opt,name=apiVersion"`
+	// Kind of the workload, either Deployment or Rollout
+	Kind string `json:"kind" protobuf:"bytes,2,opt,name=kind"`
+	// Name of the workload, in the namespace of the experiment
+	Name string `json:"name" protobuf:"bytes,3,opt,name=name"`
+	// Patch is a strategic merge patch (in YAML or JSON) of the pod template of the workload,
+	// e.g. to change the image or the labels of the pods of the experiment
+	// +optional
+	Patch string `json:"patch,omitempty" protobuf:"bytes,4,opt,name=patch"`
 }
 
 type TemplateService struct {
@@ -143,6 +250,9 @@ type TemplateStatus struct {
 	ServiceName string `json:"serviceName,omitempty" protobuf:"bytes,10,opt,name=serviceName"`
 	// PodTemplateHash is the value of the Replicas' PodTemplateHash
 	PodTemplateHash string `json:"podTemplateHash,omitempty" protobuf:"bytes,11,opt,name=podTemplateHash"`
+	// HorizontalPodAutoscalerName is the name of the HorizontalPodAutoscaler which scales the ReplicaSet of the template
+	// +optional
+	HorizontalPodAutoscalerName string `json:"horizontalPodAutoscalerName,omitempty" protobuf:"bytes,12,opt,name=horizontalPodAutoscalerName"`
 }
 
 // ExperimentStatus is the status for a Experiment resource
@@ -166,6 +276,22 @@ type ExperimentStatus struct {
 	// AnalysisRuns tracks the status of AnalysisRuns associated with this Experiment
 	// +optional
 	AnalysisRuns []ExperimentAnalysisRunStatus `json:"analysisRuns,omitempty" protobuf:"bytes,6,rep,name=analysisRuns"`
+	// PausedAt the time when the experiment was paused
+	// +optional
+	PausedAt *metav1.Time `json:"pausedAt,omitempty" protobuf:"bytes,7,opt,name=pausedAt"`
+	// PausedSeconds the number of seconds the running experiment was paused, which extend its duration
+	// +optional
+	PausedSeconds int64 `json:"pausedSeconds,omitempty" protobuf:"varint,8,opt,name=pausedSeconds"`
+	// Winner is the name of the template which won the experiment (see spec.winnerSelection)
+	// +optional
+	Winner string `json:"winner,omitempty" protobuf:"bytes,9,opt,name=winner"`
+	// CompletedAt indicates when the experiment completed
+	// +optional
+	CompletedAt *metav1.Time `json:"completedAt,omitempty" protobuf:"bytes,10,opt,name=completedAt"`
+	// ResultExportedAt indicates when the result of the completed experiment was exported with the exporters
+	// configured in the controller. The export is retried until it is set.
+	// +optional
+	ResultExportedAt *metav1.Time `json:"resultExportedAt,omitempty" protobuf:"bytes,11,opt,name=resultExportedAt"`
 }
 
 // ExperimentConditionType defines the conditions of Experiment
@@ -0,0 +1,48 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RolloutNotificationPolicy subscribes the rollouts of its namespace to notifications, without editing the notification
+// ConfigMap shared by all the rollouts
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:path=rolloutnotificationpolicies,shortName=rnp
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time since resource was created"
+type RolloutNotificationPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Spec RolloutNotificationPolicySpec `json:"spec" protobuf:"bytes,2,opt,name=spec"`
+}
+
+// RolloutNotificationPolicyList is a list of RolloutNotificationPolicy resources
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type RolloutNotificationPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata" protobuf:"bytes,1,opt,name=metadata"`
+	Items           []RolloutNotificationPolicy `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// RolloutNotificationPolicySpec is the specification for a RolloutNotificationPolicy resource
+type RolloutNotificationPolicySpec struct {
+	// Selector selects the rollouts of the namespace the policy applies to. The policy applies to all the rollouts
+	// of its namespace if omitted
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty" protobuf:"bytes,1,opt,name=selector"`
+	// Subscriptions subscribe recipients to the triggers of the notification configuration
+	Subscriptions []NotificationSubscription `json:"subscriptions" protobuf:"bytes,2,rep,name=subscriptions"`
+}
+
+// NotificationSubscription subscribes the recipients of a notification service to triggers
+type NotificationSubscription struct {
+	// Triggers are the names of the triggers, such as on-rollout-completed. The default triggers of the
+	// notification configuration are used if omitted
+	// +optional
+	Triggers []string `json:"triggers,omitempty" protobuf:"bytes,1,rep,name=triggers"`
+	// Service is the name of the notification service, such as slack
+	Service string `json:"service" protobuf:"bytes,2,opt,name=service"`
+	// Recipients are the recipients of the notification service, such as the names of Slack channels
+	Recipients []string `json:"recipients" protobuf:"bytes,3,rep,name=recipients"`
+}
@@ -13,11 +13,13 @@ var SchemeGroupVersion = schema.GroupVersion{Group: rollouts.Group, Version: "v1
 
 var (
 	// GroupVersionResource for all rollout types
-	RolloutGVR                 = SchemeGroupVersion.WithResource("rollouts")
-	AnalysisRunGVR             = SchemeGroupVersion.WithResource("analysisruns")
-	AnalysisTemplateGVR        = SchemeGroupVersion.WithResource("analysistemplates")
-	ClusterAnalysisTemplateGVR = SchemeGroupVersion.WithResource("clusteranalysistemplates")
-	ExperimentGVR              = SchemeGroupVersion.WithResource("experiments")
+	RolloutGVR                   = SchemeGroupVersion.WithResource("rollouts")
+	AnalysisRunGVR               = SchemeGroupVersion.WithResource("analysisruns")
+	AnalysisTemplateGVR          = SchemeGroupVersion.WithResource("analysistemplates")
+	ClusterAnalysisTemplateGVR   = SchemeGroupVersion.WithResource("clusteranalysistemplates")
+	ExperimentGVR                = SchemeGroupVersion.WithResource("experiments")
+	RolloutNotificationPolicyGVR = SchemeGroupVersion.WithResource("rolloutnotificationpolicies")
+	RolloutControllerConfigGVR   = SchemeGroupVersion.WithResource("rolloutcontrollerconfigs")
 )
 
 // Kind takes an unqualified kind and returns back a Group qualified GroupKind
@@ -48,6 +50,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&ClusterAnalysisTemplateList{},
 		&AnalysisRun{},
 		&AnalysisRunList{},
+		&RolloutNotificationPolicy{},
+		&RolloutNotificationPolicyList{},
+		&RolloutControllerConfig{},
+		&RolloutControllerConfigList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"time"
 
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -81,8 +82,16 @@ type RolloutSpec struct {
 	ProgressDeadlineAbort bool `json:"progressDeadlineAbort,omitempty" protobuf:"varint,12,opt,name=progressDeadlineAbort"`
 	// RestartAt indicates when all the pods of a Rollout should be restarted
 	RestartAt *metav1.Time `json:"restartAt,omitempty" protobuf:"bytes,9,opt,name=restartAt"`
+	// RestartStrategy configures how the pods are restarted when restartAt is set. Without a
+	// restart strategy, the pods are restarted continuously, up to maxUnavailable at a time.
+	// +optional
+	RestartStrategy *RolloutRestartStrategy `json:"restartStrategy,omitempty" protobuf:"bytes,14,opt,name=restartStrategy"`
 	// Analysis configuration for the analysis runs to retain
 	Analysis *AnalysisRunStrategy `json:"analysis,omitempty" protobuf:"bytes,11,opt,name=analysis"`
+	// ImageVerification verifies the cosign signatures of the images of an update before it receives
+	// traffic. The update is aborted if an image is not signed by one of the configured keys or identities.
+	// +optional
+	ImageVerification *ImageVerification `json:"imageVerification,omitempty" protobuf:"bytes,15,opt,name=imageVerification"`
 }
 
 func (s *RolloutSpec) SetResolvedSelector(selector *metav1.LabelSelector) {
@@ -131,6 +140,57 @@ func (s *RolloutSpec) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// RolloutRestartStrategy defines how the pods of a rollout are restarted in batches
+type RolloutRestartStrategy struct {
+	// BatchSize is the maximum number of pods restarted in a batch. The next batch is only started
+	// once all the pods of the rollout are available. Value can be an absolute number (ex: 5) or a
+	// percentage of the desired pods (ex: 10%). Defaults to maxUnavailable, or 1 if maxUnavailable is 0.
+	// +optional
+	BatchSize *intstr.IntOrString `json:"batchSize,omitempty" protobuf:"bytes,1,opt,name=batchSize"`
+	// PauseBetweenBatches is the duration to wait after a batch became available before the next
+	// batch is restarted (ex: 30s, 5m)
+	// +optional
+	PauseBetweenBatches DurationString `json:"pauseBetweenBatches,omitempty" protobuf:"bytes,2,opt,name=pauseBetweenBatches,casttype=DurationString"`
+	// Analysis is run after every batch. The next batch is only restarted once the analysis run
+	// succeeded. The restart is halted if the analysis run fails.
+	// +optional
+	Analysis *RolloutAnalysis `json:"analysis,omitempty" protobuf:"bytes,3,opt,name=analysis"`
+}
+
+// ImageVerification defines the keys and identities of which one must have signed each image of a rollout with
+// cosign. Keyless signatures are verified with the certificate authorities and the transparency log key, e.g. the
+// ones of the public Sigstore instance.
+type ImageVerification struct {
+	// PublicKeys reference the PEM encoded cosign public keys which sign the images
+	// +optional
+	PublicKeys []SecretKeyRef `json:"publicKeys,omitempty" protobuf:"bytes,1,rep,name=publicKeys"`
+	// Identities are the identities of keyless signatures, whose certificates are issued by the certificate
+	// authorities
+	// +optional
+	Identities []ImageVerificationIdentity `json:"identities,omitempty" protobuf:"bytes,2,rep,name=identities"`
+	// CertificateAuthorities references the PEM encoded root and intermediate certificates of the certificate
+	// authority (e.g. Fulcio) which issues the certificates of keyless signatures. Required with identities.
+	// +optional
+	CertificateAuthorities *SecretKeyRef `json:"certificateAuthorities,omitempty" protobuf:"bytes,3,opt,name=certificateAuthorities"`
+	// TransparencyLogPublicKey references the PEM encoded public key of the transparency log (e.g. Rekor) which
+	// records keyless signatures, whose certificates are verified at the time of the log entry. Required with
+	// identities.
+	// +optional
+	TransparencyLogPublicKey *SecretKeyRef `json:"transparencyLogPublicKey,omitempty" protobuf:"bytes,4,opt,name=transparencyLogPublicKey"`
+}
+
+// ImageVerificationIdentity is the identity of a keyless signature. Exactly one of subject and subjectRegex must be set.
+type ImageVerificationIdentity struct {
+	// Issuer is the OIDC issuer of the identity (e.g. https://token.actions.githubusercontent.com)
+	Issuer string `json:"issuer" protobuf:"bytes,1,opt,name=issuer"`
+	// Subject is the email address or URI of the identity
+	// +optional
+	Subject string `json:"subject,omitempty" protobuf:"bytes,2,opt,name=subject"`
+	// SubjectRegex is a regular expression which matches the email address or URI of the identity
+	// +optional
+	SubjectRegex string `json:"subjectRegex,omitempty" protobuf:"bytes,3,opt,name=subjectRegex"`
+}
+
 // ObjectRef holds a references to the Kubernetes object
 type ObjectRef struct {
 	// API Version of the referent
@@ -159,6 +219,13 @@ const (
 	// LabelKeyControllerInstanceID is the label the controller uses for the rollout, experiment, analysis segregation
 	// between controllers. Controllers will only operate on objects with the same instanceID as the controller.
 	LabelKeyControllerInstanceID = "argo-rollouts.argoproj.io/controller-instance-id"
+	// LabelKeyControllerShard is the label used to explicitly assign a rollout, experiment or analysis run to a
+	// controller shard when the controller runs in sharding mode. It overrides the hash based shard assignment.
+	LabelKeyControllerShard = "argo-rollouts.argoproj.io/controller-shard"
+	// AnnotationKeyControllerShardKey is set by the controller on objects it creates for a rollout or experiment
+	// which are not controlled by a Rollout, such as the analysis runs of an experiment. The hash based shard
+	// assignment of the object uses the value of the annotation, so that it stays on the shard of its owner.
+	AnnotationKeyControllerShardKey = "argo-rollouts.argoproj.io/controller-shard-key"
 )
 
 // RolloutStrategy defines strategy to apply during next rollout
@@ -203,6 +270,10 @@ type BlueGreenStrategy struct {
 	// ScaleDownDelayRevisionLimit limits the number of old RS that can run at one time before getting scaled down
 	// +optional
 	ScaleDownDelayRevisionLimit *int32 `json:"scaleDownDelayRevisionLimit,omitempty" protobuf:"varint,8,opt,name=scaleDownDelayRevisionLimit"`
+	// ScaleDownDelayOverrides overrides scaleDownDelaySeconds for specific old revisions, e.g. to keep the
+	// previous stable ReplicaSet running longer than older ones for instant rollback
+	// +optional
+	ScaleDownDelayOverrides []ScaleDownDelayOverride `json:"scaleDownDelayOverrides,omitempty" protobuf:"bytes,17,rep,name=scaleDownDelayOverrides"`
 	// PrePromotionAnalysis configuration to run analysis before a selector switch
 	PrePromotionAnalysis *RolloutAnalysis `json:"prePromotionAnalysis,omitempty" protobuf:"bytes,9,opt,name=prePromotionAnalysis"`
 	// AntiAffinity enables anti-affinity rules for Blue Green deployment
@@ -210,6 +281,10 @@ type BlueGreenStrategy struct {
 	AntiAffinity *AntiAffinity `json:"antiAffinity,omitempty" protobuf:"bytes,10,opt,name=antiAffinity"`
 	// PostPromotionAnalysis configuration to run analysis after a selector switch
 	PostPromotionAnalysis *RolloutAnalysis `json:"postPromotionAnalysis,omitempty" protobuf:"bytes,11,opt,name=postPromotionAnalysis"`
+	// PostPromotionSmokeTest runs a smoke test Job right after the active service switch and switches the
+	// active service back to the previous stable ReplicaSet if it does not succeed within a window
+	// +optional
+	PostPromotionSmokeTest *PostPromotionSmokeTest `json:"postPromotionSmokeTest,omitempty" protobuf:"bytes,18,opt,name=postPromotionSmokeTest"`
 	// PreviewMetadata specify labels and annotations which will be attached to the preview pods for
 	// the duration which they act as a preview pod, and will be removed after
 	PreviewMetadata *PodTemplateMetadata `json:"previewMetadata,omitempty" protobuf:"bytes,12,opt,name=previewMetadata"`
@@ -221,6 +296,85 @@ type BlueGreenStrategy struct {
 	// Default is 30 second
 	// +optional
 	AbortScaleDownDelaySeconds *int32 `json:"abortScaleDownDelaySeconds,omitempty" protobuf:"varint,14,opt,name=abortScaleDownDelaySeconds"`
+	// PreviewReplicaProfile sizes the preview stack relative to the desired replicas of the rollout
+	// instead of a fixed previewReplicaCount. Mutually exclusive with previewReplicaCount.
+	// +optional
+	PreviewReplicaProfile *PreviewReplicaProfile `json:"previewReplicaProfile,omitempty" protobuf:"bytes,15,opt,name=previewReplicaProfile"`
+	// PreviewRouting exposes the preview ReplicaSet through a header-matched route on the same hostname
+	// as the active service while the rollout is waiting to be promoted
+	// +optional
+	PreviewRouting *BlueGreenPreviewRouting `json:"previewRouting,omitempty" protobuf:"bytes,16,opt,name=previewRouting"`
+}
+
+// BlueGreenPreviewRouting defines a header-matched route which sends requests to the preview service
+// before the switchover. The route is added by the controller when a preview ReplicaSet exists and removed
+// after promotion or abort.
+type BlueGreenPreviewRouting struct {
+	// Name of the route managed by the controller. It must not collide with a route defined in the
+	// VirtualService or HTTPRoute.
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+	// Match lists the request headers which must be present for a request to be routed to the preview service
+	Match []HeaderRoutingMatch `json:"match" protobuf:"bytes,2,rep,name=match"`
+	// Istio holds the Istio VirtualService to add the preview route to
+	// +optional
+	Istio *BlueGreenIstioPreviewRouting `json:"istio,omitempty" protobuf:"bytes,3,opt,name=istio"`
+	// GatewayAPI holds the Gateway API HTTPRoute to add the preview rule to
+	// +optional
+	GatewayAPI *BlueGreenGatewayAPIPreviewRouting `json:"gatewayAPI,omitempty" protobuf:"bytes,4,opt,name=gatewayAPI"`
+}
+
+// BlueGreenIstioPreviewRouting references the Istio VirtualService used for preview routing
+type BlueGreenIstioPreviewRouting struct {
+	// VirtualService is the name of the VirtualService, optionally prefixed with its namespace (namespace/name)
+	VirtualService string `json:"virtualService" protobuf:"bytes,1,opt,name=virtualService"`
+}
+
+// BlueGreenGatewayAPIPreviewRouting references the Gateway API HTTPRoute used for preview routing
+type BlueGreenGatewayAPIPreviewRouting struct {
+	// HTTPRoute is the name of the HTTPRoute in the namespace of the rollout
+	HTTPRoute string `json:"httpRoute" protobuf:"bytes,1,opt,name=httpRoute"`
+}
+
+// PreviewReplicaProfile defines how many replicas to run for the preview stack before the switchover,
+// relative to the desired replicas of the rollout
+type PreviewReplicaProfile struct {
+	// Percent is the percentage of spec.replicas to run for the preview stack, rounded up. When a
+	// HorizontalPodAutoscaler targets the Rollout, the preview stack follows the autoscaled size.
+	Percent int32 `json:"percent" protobuf:"varint,1,opt,name=percent"`
+	// MinReplicas is the lower bound of the computed preview replica count
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty" protobuf:"varint,2,opt,name=minReplicas"`
+	// MaxReplicas is the upper bound of the computed preview replica count
+	// +optional
+	MaxReplicas *int32 `json:"maxReplicas,omitempty" protobuf:"varint,3,opt,name=maxReplicas"`
+}
+
+// PostPromotionSmokeTest defines a smoke test run as part of the post-promotion AnalysisRun
+type PostPromotionSmokeTest struct {
+	// Job is run as the smoke test. The smoke test fails if the Job fails.
+	// +optional
+	Job *JobMetric `json:"job,omitempty" protobuf:"bytes,1,opt,name=job"`
+	// Templates reference AnalysisTemplates (e.g. with a job metric) to run as the smoke test
+	// +optional
+	Templates []AnalysisTemplateRef `json:"templates,omitempty" protobuf:"bytes,2,rep,name=templates"`
+	// Args are the arguments passed to the smoke test templates
+	// +optional
+	Args []AnalysisRunArgument `json:"args,omitempty" protobuf:"bytes,3,rep,name=args"`
+	// WindowSeconds is the number of seconds the post-promotion AnalysisRun has to succeed after it is
+	// started. If it fails or is still running at the end of the window, the rollout is aborted and the
+	// active service is switched back to the previous stable ReplicaSet. Defaults to 300.
+	// +optional
+	WindowSeconds *int32 `json:"windowSeconds,omitempty" protobuf:"varint,4,opt,name=windowSeconds"`
+}
+
+// ScaleDownDelayOverride defines the scale down delay of an old ReplicaSet at a given position in the
+// revision history
+type ScaleDownDelayOverride struct {
+	// Revision is the number of revisions the old ReplicaSet is behind the current revision, according to their
+	// rollout.argoproj.io/revision annotations. 1 is the revision immediately preceding the current one.
+	Revision int32 `json:"revision" protobuf:"varint,1,opt,name=revision"`
+	// DelaySeconds is the number of seconds to wait before scaling down the ReplicaSet
+	DelaySeconds int32 `json:"delaySeconds" protobuf:"varint,2,opt,name=delaySeconds"`
 }
 
 // AntiAffinity defines which inter-pod scheduling rule to use for anti-affinity injection
@@ -229,6 +383,27 @@ type AntiAffinity struct {
 	PreferredDuringSchedulingIgnoredDuringExecution *PreferredDuringSchedulingIgnoredDuringExecution `json:"preferredDuringSchedulingIgnoredDuringExecution,omitempty" protobuf:"bytes,1,opt,name=preferredDuringSchedulingIgnoredDuringExecution"`
 	// +optional
 	RequiredDuringSchedulingIgnoredDuringExecution *RequiredDuringSchedulingIgnoredDuringExecution `json:"requiredDuringSchedulingIgnoredDuringExecution,omitempty" protobuf:"bytes,2,opt,name=requiredDuringSchedulingIgnoredDuringExecution"`
+	// TopologySpread additionally injects topologySpreadConstraints which spread the pods of the new
+	// ReplicaSet evenly with the pods of the stable ReplicaSet across the given topology domains
+	// +optional
+	TopologySpread *AntiAffinityTopologySpread `json:"topologySpread,omitempty" protobuf:"bytes,3,opt,name=topologySpread"`
+}
+
+// AntiAffinityTopologySpread defines the topologySpreadConstraints injected between the stable and new pods
+type AntiAffinityTopologySpread struct {
+	// TopologyKeys are the node label keys of the topology domains to spread the pods across,
+	// e.g. kubernetes.io/hostname or topology.kubernetes.io/zone. Defaults to kubernetes.io/hostname
+	// +optional
+	TopologyKeys []string `json:"topologyKeys,omitempty" protobuf:"bytes,1,rep,name=topologyKeys"`
+	// MaxSkew is the maximum permitted difference in the number of stable and new pods between any
+	// two topology domains. Defaults to 1
+	// +optional
+	MaxSkew *int32 `json:"maxSkew,omitempty" protobuf:"varint,2,opt,name=maxSkew"`
+	// WhenUnsatisfiable indicates how to deal with a pod if it doesn't satisfy the spread constraint.
+	// Defaults to ScheduleAnyway
+	// +kubebuilder:validation:Enum=DoNotSchedule;ScheduleAnyway
+	// +optional
+	WhenUnsatisfiable corev1.UnsatisfiableConstraintAction `json:"whenUnsatisfiable,omitempty" protobuf:"bytes,3,opt,name=whenUnsatisfiable,casttype=k8s.io/api/core/v1.UnsatisfiableConstraintAction"`
 }
 
 // PreferredDuringSchedulingIgnoredDuringExecution defines the weight of the anti-affinity injection
@@ -318,6 +493,11 @@ type CanaryStrategy struct {
 	// ScaleDownDelayRevisionLimit limits the number of old RS that can run at one time before getting scaled down
 	// +optional
 	ScaleDownDelayRevisionLimit *int32 `json:"scaleDownDelayRevisionLimit,omitempty" protobuf:"varint,12,opt,name=scaleDownDelayRevisionLimit"`
+	// ScaleDownDelayOverrides overrides scaleDownDelaySeconds for specific old revisions, e.g. to keep the
+	// previous stable ReplicaSet running longer than older ones for instant rollback.
+	// This value is ignored with basic, replica-weighted canary without traffic routing.
+	// +optional
+	ScaleDownDelayOverrides []ScaleDownDelayOverride `json:"scaleDownDelayOverrides,omitempty" protobuf:"bytes,18,rep,name=scaleDownDelayOverrides"`
 	// AbortScaleDownDelaySeconds adds a delay in second before scaling down the canary pods when update
 	// is aborted for canary strategy with traffic routing (not applicable for basic canary).
 	// 0 means canary pods are not scaled down.
@@ -339,6 +519,92 @@ type CanaryStrategy struct {
 	// Defaults to 100% of total replicas.
 	// +optional
 	ReplicaProgressThreshold *ReplicaProgressThreshold `json:"replicaProgressThreshold,omitempty" protobuf:"bytes,17,opt,name=replicaProgressThreshold"`
+	// CreateServices instructs the controller to create the canaryService and stableService when
+	// they do not exist. Created services select the rollout's pods and expose the container ports
+	// of the pod template. They are owned by the rollout and deleted along with it.
+	// +optional
+	CreateServices bool `json:"createServices,omitempty" protobuf:"varint,19,opt,name=createServices"`
+	// Guardrail is an analysis which is continuously evaluated for the entire update, independent of
+	// the canary steps, and pauses or aborts the rollout as soon as it fails
+	// +optional
+	Guardrail *RolloutGuardrail `json:"guardrail,omitempty" protobuf:"bytes,20,opt,name=guardrail"`
+	// ScaleDownVerification delays the scale down of the previous stable ReplicaSet after the scale down
+	// delay, until the traffic router verified the traffic weights and, optionally, until its pods report
+	// no active connections. Requires trafficRouting.
+	// +optional
+	ScaleDownVerification *ScaleDownVerification `json:"scaleDownVerification,omitempty" protobuf:"bytes,21,opt,name=scaleDownVerification"`
+	// KEDA coordinates the KEDA ScaledObjects which scale the rollout with its updates, so that KEDA and the
+	// controller do not fight over the replica counts
+	// +optional
+	KEDA *KEDACoordination `json:"keda,omitempty" protobuf:"bytes,22,opt,name=keda"`
+	// PodDisruptionBudget creates a PodDisruptionBudget for the pods of the stable ReplicaSet and one for the pods of
+	// the canary ReplicaSet while the canary is rolled out, so that voluntary disruptions, such as the drain of a node,
+	// cannot evict all the pods of a small canary and invalidate its analysis
+	// +optional
+	PodDisruptionBudget *ReplicaSetPodDisruptionBudget `json:"podDisruptionBudget,omitempty" protobuf:"bytes,23,opt,name=podDisruptionBudget"`
+	// HPAScalesStable reports the pods of the stable ReplicaSet, instead of the pods of every ReplicaSet, to the HPA
+	// through status.HPAReplicas and status.selector while the canary is rolled out with traffic routing and without
+	// dynamic stable scale. spec.replicas is then the replica count of the stable ReplicaSet, which the canary
+	// ReplicaSet is scaled to the canary weight of, so the HPA follows the load of the stable pods only.
+	// +optional
+	HPAScalesStable bool `json:"hpaScalesStable,omitempty" protobuf:"varint,24,opt,name=hpaScalesStable"`
+}
+
+// ReplicaSetPodDisruptionBudget defines the PodDisruptionBudgets of the stable and canary ReplicaSets. Only one of
+// MinAvailable and MaxUnavailable can be set. Defaults to a MinAvailable of 1.
+type ReplicaSetPodDisruptionBudget struct {
+	// MinAvailable is the number or percentage of the pods of a ReplicaSet which must remain available after an
+	// eviction
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty" protobuf:"bytes,1,opt,name=minAvailable"`
+	// MaxUnavailable is the number or percentage of the pods of a ReplicaSet which can be unavailable after an
+	// eviction
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty" protobuf:"bytes,2,opt,name=maxUnavailable"`
+}
+
+// KEDACoordinationMode is how the KEDA ScaledObjects of a rollout are coordinated with its updates
+type KEDACoordinationMode string
+
+const (
+	// KEDACoordinationModePause pauses the autoscaling of the ScaledObjects at the replica count of the rollout while
+	// it is updated, and resumes it once the update is completed or aborted
+	KEDACoordinationModePause KEDACoordinationMode = "Pause"
+)
+
+// KEDACoordination defines how the KEDA ScaledObjects which target the rollout are coordinated with its updates
+type KEDACoordination struct {
+	// Mode is Pause, the only supported mode. Defaults to Pause.
+	// +optional
+	Mode KEDACoordinationMode `json:"mode,omitempty" protobuf:"bytes,1,opt,name=mode,casttype=KEDACoordinationMode"`
+}
+
+// ScaleDownVerification defines the checks performed before an old ReplicaSet is scaled down
+type ScaleDownVerification struct {
+	// DrainProbe is sent to every pod of the ReplicaSet. The ReplicaSet is only scaled down once all of
+	// its pods report zero active connections.
+	// +optional
+	DrainProbe *DrainProbe `json:"drainProbe,omitempty" protobuf:"bytes,1,opt,name=drainProbe"`
+	// TimeoutSeconds is the maximum number of seconds to wait for the verification after the scale down
+	// deadline passed. Afterwards, the ReplicaSet is scaled down regardless. Defaults to 300.
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty" protobuf:"varint,2,opt,name=timeoutSeconds"`
+}
+
+// DrainProbe is an HTTP endpoint of the pods which reports the number of active connections as a
+// plain integer in the response body
+type DrainProbe struct {
+	// Path to access on the HTTP server. Defaults to /.
+	// +optional
+	Path string `json:"path,omitempty" protobuf:"bytes,1,opt,name=path"`
+	// Port is the container port of the HTTP server
+	Port int32 `json:"port" protobuf:"varint,2,opt,name=port"`
+	// Scheme to use for connecting to the pods. Defaults to HTTP.
+	// +optional
+	Scheme corev1.URIScheme `json:"scheme,omitempty" protobuf:"bytes,3,opt,name=scheme,casttype=k8s.io/api/core/v1.URIScheme"`
+	// TimeoutSeconds is the number of seconds after which a probe request times out. Defaults to 1.
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty" protobuf:"varint,4,opt,name=timeoutSeconds"`
 }
 
 // PingPongSpec holds the ping and pong service name.
@@ -412,6 +678,8 @@ type RolloutTrafficRouting struct {
 
 	// MaxTrafficWeight The total weight of traffic. If unspecified, it defaults to 100
 	MaxTrafficWeight *int32 `json:"maxTrafficWeight,omitempty" protobuf:"varint,11,opt,name=maxTrafficWeight"`
+	// Knative holds specific configuration to split the traffic of a Knative Service between revisions
+	Knative *KnativeTrafficRouting `json:"knative,omitempty" protobuf:"bytes,12,opt,name=knative"`
 }
 
 type MangedRoutes struct {
@@ -426,6 +694,13 @@ type TraefikTrafficRouting struct {
 	WeightedTraefikServiceName string `json:"weightedTraefikServiceName" protobuf:"bytes,1,name=weightedTraefikServiceName"`
 }
 
+// KnativeTrafficRouting defines the configuration required to split the traffic of a Knative Service between the
+// revisions of the stable and canary pod templates
+type KnativeTrafficRouting struct {
+	// Service refers to the name of the Knative Service whose traffic is split between the stable and canary revisions
+	Service string `json:"service" protobuf:"bytes,1,opt,name=service"`
+}
+
 // ApisixTrafficRouting defines the configuration required to use APISIX as traffic router
 type ApisixTrafficRouting struct {
 	// Route references an Apisix Route to modify to shape traffic
@@ -578,6 +853,14 @@ type RolloutExperimentStep struct {
 	// ScaleDownDelaySeconds is the number of seconds to wait before scaling down the old ReplicaSet
 	// +optional
 	ScaleDownDelaySeconds *int32 `json:"scaleDownDelaySeconds,omitempty" protobuf:"varint,6,opt,name=scaleDownDelaySeconds"`
+	// WinnerSelection selects the template which won the experiment from the analyses of the templates
+	// +optional
+	WinnerSelection *ExperimentWinnerSelection `json:"winnerSelection,omitempty" protobuf:"bytes,7,opt,name=winnerSelection"`
+	// PromoteWinner updates the pod template of the rollout with the patch of the template which won the experiment.
+	// The update is rolled out as a new revision, which skips the experiment step. A GitOps tool which reverts the pod
+	// template to its source runs the experiment and promotes the winner again, in a loop.
+	// +optional
+	PromoteWinner bool `json:"promoteWinner,omitempty" protobuf:"varint,8,opt,name=promoteWinner"`
 }
 
 type RolloutExperimentStepAnalysisTemplateRef struct {
@@ -622,6 +905,17 @@ type RolloutExperimentTemplate struct {
 	Weight *int32 `json:"weight,omitempty" protobuf:"varint,6,opt,name=weight"`
 	// Service controls the optionally generated service
 	Service *TemplateService `json:"service,omitempty" protobuf:"bytes,7,opt,name=service"`
+	// Patch is a strategic merge patch (in YAML or JSON) of the pod template referenced by the specRef, e.g. to run
+	// a variant of the canary with another image. Only the spec of the pod template can be patched.
+	// +optional
+	Patch string `json:"patch,omitempty" protobuf:"bytes,8,opt,name=patch"`
+	// HeaderRoute routes the requests matching the headers to the template's replicas while the experiment is running.
+	// The name of the route also needs to be included in the `spec.strategy.canary.trafficRouting.managedRoutes` field
+	// +optional
+	HeaderRoute *SetHeaderRoute `json:"headerRoute,omitempty" protobuf:"bytes,9,opt,name=headerRoute"`
+	// Autoscaling attaches a HorizontalPodAutoscaler to the template's replicas while the experiment is running
+	// +optional
+	Autoscaling *TemplateAutoscaling `json:"autoscaling,omitempty" protobuf:"bytes,10,opt,name=autoscaling"`
 }
 
 // PodTemplateMetadata extra labels to add to the template
@@ -632,6 +926,22 @@ type PodTemplateMetadata struct {
 	// Annotations additional annotations to add to the experiment
 	// +optional
 	Annotations map[string]string `json:"annotations,omitempty" protobuf:"bytes,2,rep,name=annotations"`
+	// Env exposes the ephemeral labels and annotations as environment variables of the pod containers.
+	// Only applies to ephemeral metadata (canary/stable and preview/active metadata)
+	// +optional
+	Env []EphemeralMetadataEnvVar `json:"env,omitempty" protobuf:"bytes,3,rep,name=env"`
+}
+
+// EphemeralMetadataEnvVar exposes a pod label or annotation as an environment variable using the downward API
+type EphemeralMetadataEnvVar struct {
+	// Name of the environment variable
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+	// Label is the key of the pod label exposed by the environment variable
+	// +optional
+	Label string `json:"label,omitempty" protobuf:"bytes,2,opt,name=label"`
+	// Annotation is the key of the pod annotation exposed by the environment variable
+	// +optional
+	Annotation string `json:"annotation,omitempty" protobuf:"bytes,3,opt,name=annotation"`
 }
 
 // AnalysisRunMetadata extra labels to add to the AnalysisRun
@@ -677,6 +987,267 @@ type CanaryStep struct {
 	SetMirrorRoute *SetMirrorRoute `json:"setMirrorRoute,omitempty" protobuf:"bytes,8,opt,name=setMirrorRoute"`
 	// Plugin defines a plugin to execute for a step
 	Plugin *PluginStep `json:"plugin,omitempty" protobuf:"bytes,9,opt,name=plugin"`
+	// ProgressDeadline overrides the rollout's progressDeadlineSeconds while this step is in progress
+	// and defines the action taken when the step makes no progress within the deadline
+	// +optional
+	ProgressDeadline *StepProgressDeadline `json:"progressDeadline,omitempty" protobuf:"bytes,10,opt,name=progressDeadline"`
+	// SetCanaryPodTemplateOverlay applies a strategic merge patch to the pod template of the canary
+	// ReplicaSet. The overlay stays in effect for the following steps until it is replaced by another
+	// setCanaryPodTemplateOverlay step, and is reverted once the canary is promoted
+	// +optional
+	SetCanaryPodTemplateOverlay *PodTemplateOverlay `json:"setCanaryPodTemplateOverlay,omitempty" protobuf:"bytes,11,opt,name=setCanaryPodTemplateOverlay"`
+	// LoadTest launches a Job which generates load against the canary service, so that the following
+	// analysis steps measure the canary under load even when it receives little traffic
+	// +optional
+	LoadTest *RolloutLoadTestStep `json:"loadTest,omitempty" protobuf:"bytes,12,opt,name=loadTest"`
+	// PreProvision creates low priority placeholder pods with the resource requests of the canary pods, so that
+	// the cluster autoscaler provisions the capacity of a following setWeight step before the canary is scaled up
+	// +optional
+	PreProvision *RolloutPreProvisionStep `json:"preProvision,omitempty" protobuf:"bytes,13,opt,name=preProvision"`
+	// SetFeatureFlag sets the percentage of the users a feature flag is enabled for, so that a code-level flag
+	// moves together with the traffic weights
+	// +optional
+	SetFeatureFlag *SetFeatureFlag `json:"setFeatureFlag,omitempty" protobuf:"bytes,14,opt,name=setFeatureFlag"`
+	// Migration runs the database migrations of the update, or waits on an external system to complete them,
+	// before the rollout progresses to the following steps
+	// +optional
+	Migration *RolloutMigrationStep `json:"migration,omitempty" protobuf:"bytes,15,opt,name=migration"`
+	// Gate calls a webhook with the context of the rollout, which decides whether the rollout proceeds, pauses or
+	// aborts, e.g. to integrate an external approval system
+	// +optional
+	Gate *RolloutGateStep `json:"gate,omitempty" protobuf:"bytes,16,opt,name=gate"`
+}
+
+// SetFeatureFlag defines the percentage a canary step sets a feature flag to. The flag keeps the percentage of the
+// last setFeatureFlag step of the flag during the update, is disabled when the update is aborted, and is enabled for
+// all users once the update is fully promoted. Exactly one of launchDarkly and openFeature must be set.
+type SetFeatureFlag struct {
+	// Flag is the key of the feature flag
+	Flag string `json:"flag" protobuf:"bytes,1,opt,name=flag"`
+	// Percentage is the percentage of the users the flag is enabled for. Defaults to the canary weight of the step
+	// +optional
+	Percentage *int32 `json:"percentage,omitempty" protobuf:"varint,2,opt,name=percentage"`
+	// LaunchDarkly sets the percentage rollout of a boolean flag in LaunchDarkly
+	// +optional
+	LaunchDarkly *LaunchDarklyFeatureFlag `json:"launchDarkly,omitempty" protobuf:"bytes,3,opt,name=launchDarkly"`
+	// OpenFeature serves the flag with the OpenFeature Remote Evaluation Protocol (OFREP) endpoint of the controller
+	// +optional
+	OpenFeature *OpenFeatureFeatureFlag `json:"openFeature,omitempty" protobuf:"bytes,4,opt,name=openFeature"`
+}
+
+// LaunchDarklyFeatureFlag defines a boolean flag of a LaunchDarkly environment
+type LaunchDarklyFeatureFlag struct {
+	// ProjectKey is the key of the project of the flag
+	ProjectKey string `json:"projectKey" protobuf:"bytes,1,opt,name=projectKey"`
+	// EnvironmentKey is the key of the environment the percentage is set in
+	EnvironmentKey string `json:"environmentKey" protobuf:"bytes,2,opt,name=environmentKey"`
+	// APITokenSecretRef references the key of a secret holding a LaunchDarkly API access token, which is allowed
+	// to update the flag
+	APITokenSecretRef SecretKeyRef `json:"apiTokenSecretRef" protobuf:"bytes,3,opt,name=apiTokenSecretRef"`
+	// APIURL is the URL of the LaunchDarkly API. Defaults to https://app.launchdarkly.com
+	// +optional
+	APIURL string `json:"apiURL,omitempty" protobuf:"bytes,4,opt,name=apiURL"`
+}
+
+// OpenFeatureFeatureFlag defines a boolean flag served by the controller to OpenFeature providers with the
+// OpenFeature Remote Evaluation Protocol. The flag is evaluated per targeting key, which is enabled for the
+// percentage of the targeting keys
+type OpenFeatureFeatureFlag struct {
+	// Variants are the values of the flag when it is enabled and disabled. Defaults to true and false
+	// +optional
+	Variants *FeatureFlagVariants `json:"variants,omitempty" protobuf:"bytes,1,opt,name=variants"`
+}
+
+// FeatureFlagVariants defines the values of a flag served with the OpenFeature Remote Evaluation Protocol
+type FeatureFlagVariants struct {
+	// On is the value of the flag when it is enabled
+	On string `json:"on" protobuf:"bytes,1,opt,name=on"`
+	// Off is the value of the flag when it is disabled
+	Off string `json:"off" protobuf:"bytes,2,opt,name=off"`
+}
+
+// RolloutMigrationStep defines the database migrations gating a canary step. Exactly one of jobSpec and statusCheck
+// must be set. The step completes once the migrations are completed, and aborts the rollout if they fail or time out.
+// The containers of the Jobs receive the name and namespace of the rollout, and the pod template hash of the update
+// in the ROLLOUT_NAME, ROLLOUT_NAMESPACE and ROLLOUT_POD_TEMPLATE_HASH environment variables
+type RolloutMigrationStep struct {
+	// JobSpec is the spec of a Job which runs the migrations
+	// +optional
+	JobSpec *batchv1.JobSpec `json:"jobSpec,omitempty" protobuf:"bytes,1,opt,name=jobSpec"`
+	// StatusCheck polls an endpoint reporting the status of migrations run by an external system
+	// +optional
+	StatusCheck *MigrationStatusCheck `json:"statusCheck,omitempty" protobuf:"bytes,2,opt,name=statusCheck"`
+	// RollbackJobSpec is the spec of a Job which reverts the migrations when the update is aborted after the step
+	// was started. The rollback Jobs of several migration steps are run one after another, the last step first
+	// +optional
+	RollbackJobSpec *batchv1.JobSpec `json:"rollbackJobSpec,omitempty" protobuf:"bytes,3,opt,name=rollbackJobSpec"`
+	// Timeout is the maximum time the migrations may take (e.g. 30m), after which the rollout is aborted. Waits
+	// indefinitely by default
+	// +optional
+	Timeout DurationString `json:"timeout,omitempty" protobuf:"bytes,4,opt,name=timeout,casttype=DurationString"`
+}
+
+// MigrationStatusCheck defines an HTTP endpoint reporting the status of the migrations of an update
+type MigrationStatusCheck struct {
+	// URL of the endpoint. The pod template hash of the update replaces {{hash}} in the URL
+	URL string `json:"url" protobuf:"bytes,1,opt,name=url"`
+	// Headers are the headers of the requests
+	// +optional
+	Headers []WebMetricHeader `json:"headers,omitempty" protobuf:"bytes,2,rep,name=headers"`
+	// AuthorizationSecretRef references the key of a secret holding the value of the Authorization header
+	// +optional
+	AuthorizationSecretRef *SecretKeyRef `json:"authorizationSecretRef,omitempty" protobuf:"bytes,3,opt,name=authorizationSecretRef"`
+	// JSONPath selects the status of the migrations from the JSON response (e.g. {$.status}). When empty, the
+	// migrations are completed once the endpoint responds with a 2xx status code
+	// +optional
+	JSONPath string `json:"jsonPath,omitempty" protobuf:"bytes,4,opt,name=jsonPath"`
+	// CompletedValue is the status of completed migrations. Defaults to completed
+	// +optional
+	CompletedValue string `json:"completedValue,omitempty" protobuf:"bytes,5,opt,name=completedValue"`
+	// FailedValue is the status of failed migrations, which aborts the rollout. Defaults to failed
+	// +optional
+	FailedValue string `json:"failedValue,omitempty" protobuf:"bytes,6,opt,name=failedValue"`
+	// Interval is the interval between the checks (e.g. 30s). Defaults to 10s
+	// +optional
+	Interval DurationString `json:"interval,omitempty" protobuf:"bytes,7,opt,name=interval,casttype=DurationString"`
+}
+
+// GateFailurePolicy is the policy applied when the webhook of a gate step fails
+type GateFailurePolicy string
+
+const (
+	// GateFailurePolicyAbort aborts the rollout
+	GateFailurePolicyAbort GateFailurePolicy = "Abort"
+	// GateFailurePolicyPause pauses the rollout, and keeps calling the webhook
+	GateFailurePolicyPause GateFailurePolicy = "Pause"
+)
+
+// RolloutGateStep defines a webhook which gates the progression of a canary step. The webhook receives a POST
+// request with the rollout, the step and the update, and responds with a JSON object whose decision is proceed,
+// pause or abort. A paused rollout calls the webhook again until it decides otherwise, or is promoted manually
+type RolloutGateStep struct {
+	// URL of the webhook
+	URL string `json:"url" protobuf:"bytes,1,opt,name=url"`
+	// Headers are the headers of the requests
+	// +optional
+	Headers []WebMetricHeader `json:"headers,omitempty" protobuf:"bytes,2,rep,name=headers"`
+	// AuthorizationSecretRef references the key of a secret holding the value of the Authorization header
+	// +optional
+	AuthorizationSecretRef *SecretKeyRef `json:"authorizationSecretRef,omitempty" protobuf:"bytes,3,opt,name=authorizationSecretRef"`
+	// Params are passed to the webhook in the params of the request
+	// +optional
+	Params map[string]string `json:"params,omitempty" protobuf:"bytes,4,rep,name=params"`
+	// Timeout of the requests (e.g. 5s), at most 60s. Defaults to 10s
+	// +optional
+	Timeout DurationString `json:"timeout,omitempty" protobuf:"bytes,5,opt,name=timeout,casttype=DurationString"`
+	// Interval at which the webhook is called again while it pauses the rollout (e.g. 1m). Defaults to 30s
+	// +optional
+	Interval DurationString `json:"interval,omitempty" protobuf:"bytes,6,opt,name=interval,casttype=DurationString"`
+	// Retries is the number of consecutive failed requests which are retried before the failure policy applies.
+	// Defaults to 3
+	// +optional
+	Retries *int32 `json:"retries,omitempty" protobuf:"varint,7,opt,name=retries"`
+	// FailurePolicy is applied once the retries of the webhook are exhausted. Abort aborts the rollout, and Pause
+	// pauses it. Defaults to Abort
+	// +optional
+	FailurePolicy GateFailurePolicy `json:"failurePolicy,omitempty" protobuf:"bytes,8,opt,name=failurePolicy,casttype=GateFailurePolicy"`
+}
+
+// RolloutPreProvisionStep defines the capacity pre-provisioned by a canary step. The placeholder pods are
+// scaled down as the canary pods become available, and are deleted once the update is completed or aborted
+type RolloutPreProvisionStep struct {
+	// Weight is the canary weight the capacity is provisioned for, i.e. a placeholder pod is created for each
+	// canary pod missing at this weight. Defaults to the weight of the next setWeight step
+	// +optional
+	Weight *int32 `json:"weight,omitempty" protobuf:"varint,1,opt,name=weight"`
+	// PriorityClassName is the priority class of the placeholder pods. Its priority must be lower than the one
+	// of the canary pods, so that the placeholder pods are preempted by them, but must not be below the
+	// priority cutoff of the cluster autoscaler (-10 by default), so that it provisions nodes for them
+	PriorityClassName string `json:"priorityClassName" protobuf:"bytes,2,opt,name=priorityClassName"`
+	// Timeout is the maximum time the step waits for the placeholder pods to be scheduled (e.g. 10m), after
+	// which the step completes regardless. Defaults to 10m
+	// +optional
+	Timeout DurationString `json:"timeout,omitempty" protobuf:"bytes,3,opt,name=timeout,casttype=DurationString"`
+	// Image is the image of the placeholder pods. Defaults to registry.k8s.io/pause:3.10
+	// +optional
+	Image string `json:"image,omitempty" protobuf:"bytes,4,opt,name=image"`
+}
+
+// RolloutLoadTestStep defines the load test of a canary step. Exactly one of jobSpec, k6 and vegeta must be set.
+// The containers of the load test receive the address of the canary service in the CANARY_URL (e.g.
+// http://canary.ns.svc.cluster.local:8080) and CANARY_HOST environment variables
+type RolloutLoadTestStep struct {
+	// JobSpec is the spec of a custom Job which generates the load
+	// +optional
+	JobSpec *batchv1.JobSpec `json:"jobSpec,omitempty" protobuf:"bytes,1,opt,name=jobSpec"`
+	// K6 runs a k6 script against the canary
+	// +optional
+	K6 *K6LoadTest `json:"k6,omitempty" protobuf:"bytes,2,opt,name=k6"`
+	// Vegeta sends requests to the canary at a constant rate with vegeta
+	// +optional
+	Vegeta *VegetaLoadTest `json:"vegeta,omitempty" protobuf:"bytes,3,opt,name=vegeta"`
+	// Wait holds the step until the Job of the load test has completed, and aborts the rollout if the Job fails.
+	// By default, the step completes once the load test is started, so that the load is generated during the
+	// following steps
+	// +optional
+	Wait bool `json:"wait,omitempty" protobuf:"varint,4,opt,name=wait"`
+}
+
+// K6LoadTest defines a load test run with k6
+type K6LoadTest struct {
+	// Script is the k6 script. The address of the canary is available to the script as __ENV.CANARY_URL
+	Script string `json:"script" protobuf:"bytes,1,opt,name=script"`
+	// Image is the k6 image. Defaults to grafana/k6
+	// +optional
+	Image string `json:"image,omitempty" protobuf:"bytes,2,opt,name=image"`
+}
+
+// VegetaLoadTest defines a load test run with vegeta
+type VegetaLoadTest struct {
+	// Rate is the number of requests per second. Defaults to 10
+	// +optional
+	Rate int32 `json:"rate,omitempty" protobuf:"varint,1,opt,name=rate"`
+	// Duration of the load test (e.g. 5m). Defaults to 1m
+	// +optional
+	Duration DurationString `json:"duration,omitempty" protobuf:"bytes,2,opt,name=duration,casttype=DurationString"`
+	// Method is the HTTP method of the requests. Defaults to GET
+	// +optional
+	Method string `json:"method,omitempty" protobuf:"bytes,3,opt,name=method"`
+	// Path is the HTTP path of the requests. Defaults to /
+	// +optional
+	Path string `json:"path,omitempty" protobuf:"bytes,4,opt,name=path"`
+	// Image is the vegeta image. Defaults to peterevans/vegeta
+	// +optional
+	Image string `json:"image,omitempty" protobuf:"bytes,5,opt,name=image"`
+}
+
+// PodTemplateOverlay defines a patch of the canary pod template
+type PodTemplateOverlay struct {
+	// Patch is a strategic merge patch (in YAML or JSON) of the pod template. Only the spec of the
+	// pod template can be patched. An empty patch removes a previous overlay
+	// +optional
+	Patch string `json:"patch,omitempty" protobuf:"bytes,1,opt,name=patch"`
+}
+
+// StepProgressDeadlineAction is the action taken when a canary step exceeds its progress deadline
+type StepProgressDeadlineAction string
+
+const (
+	// StepProgressDeadlineActionAbort aborts the rollout when the step exceeds its progress deadline
+	StepProgressDeadlineActionAbort StepProgressDeadlineAction = "Abort"
+	// StepProgressDeadlineActionPause pauses the rollout at the step when it exceeds its progress deadline
+	StepProgressDeadlineActionPause StepProgressDeadlineAction = "Pause"
+)
+
+// StepProgressDeadline defines the progress deadline of a canary step
+type StepProgressDeadline struct {
+	// Seconds is the maximum time in seconds for the step to make progress before it is considered
+	// to have failed. Unlike the rollout's progress deadline, it also applies to analysis and experiment steps.
+	Seconds int32 `json:"seconds" protobuf:"varint,1,opt,name=seconds"`
+	// Action is the action taken when the step exceeds its progress deadline (Abort or Pause).
+	// Defaults to the behavior of the rollout's progressDeadlineAbort.
+	// +optional
+	Action StepProgressDeadlineAction `json:"action,omitempty" protobuf:"bytes,2,opt,name=action,casttype=StepProgressDeadlineAction"`
 }
 
 type PluginStep struct {
@@ -762,6 +1333,25 @@ type RolloutAnalysisBackground struct {
 	StartingStep *int32 `json:"startingStep,omitempty" protobuf:"varint,2,opt,name=startingStep"`
 }
 
+// GuardrailAction is the action taken when a guardrail analysis fails
+type GuardrailAction string
+
+const (
+	// GuardrailActionAbort aborts the rollout when the guardrail analysis fails
+	GuardrailActionAbort GuardrailAction = "Abort"
+	// GuardrailActionPause pauses the rollout when the guardrail analysis fails
+	GuardrailActionPause GuardrailAction = "Pause"
+)
+
+// RolloutGuardrail defines a template that is used to create a guardrail analysisRun
+type RolloutGuardrail struct {
+	RolloutAnalysis `json:",inline" protobuf:"bytes,1,opt,name=rolloutAnalysis"`
+	// Action is the action taken when the guardrail analysis fails or errors (Abort or Pause).
+	// Defaults to Abort.
+	// +optional
+	Action GuardrailAction `json:"action,omitempty" protobuf:"bytes,2,opt,name=action,casttype=GuardrailAction"`
+}
+
 // RolloutAnalysis defines a template that is used to create a analysisRun
 type RolloutAnalysis struct {
 	// Templates reference to a list of analysis templates to combine for an AnalysisRun
@@ -846,6 +1436,10 @@ const (
 	RolloutTypePrePromotionLabel = "PrePromotion"
 	// RolloutTypePostPromotionLabel indicates that the analysisRun was created after the active service promotion
 	RolloutTypePostPromotionLabel = "PostPromotion"
+	// RolloutTypeGuardrailLabel indicates that the analysisRun was created as the guardrail of an update
+	RolloutTypeGuardrailLabel = "Guardrail"
+	// RolloutTypeRestartLabel indicates that the analysisRun was created after a batch of restarted pods
+	RolloutTypeRestartLabel = "Restart"
 	// RolloutCanaryStepIndexLabel indicates which step created this analysisRun
 	RolloutCanaryStepIndexLabel = "step-index"
 )
@@ -904,6 +1498,12 @@ const (
 	PauseReasonCanaryPauseStep PauseReason = "CanaryPauseStep"
 	// PauseReasonBlueGreenPause pause rollout before promoting rollout
 	PauseReasonBlueGreenPause PauseReason = "BlueGreenPause"
+	// PauseReasonStepProgressDeadline pauses rollout when a canary step exceeds its progress deadline
+	PauseReasonStepProgressDeadline PauseReason = "StepProgressDeadlineExceeded"
+	// PauseReasonGuardrailAnalysis pauses rollout when the guardrail analysis fails
+	PauseReasonGuardrailAnalysis PauseReason = "GuardrailAnalysisRun"
+	// PauseReasonGate pauses rollout when the webhook of a gate step decides so
+	PauseReasonGate PauseReason = "Gate"
 )
 
 // PauseCondition the reason for a pause and when it started
@@ -1011,6 +1611,95 @@ type RolloutStatus struct {
 	ALB *ALBStatus `json:"alb,omitempty" protobuf:"bytes,25,opt,name=alb"`
 	/// ALBs keeps information regarding multiple ALBs and TargetGroups in a multi ingress scenario
 	ALBs []ALBStatus `json:"albs,omitempty" protobuf:"bytes,26,opt,name=albs"`
+	// RestartStatus holds the progress of a restart performed in batches
+	// +optional
+	RestartStatus *RolloutRestartStatus `json:"restartStatus,omitempty" protobuf:"bytes,27,opt,name=restartStatus"`
+	// Health summarizes the health and the progress of the rollout in a stable format, which GitOps tools
+	// such as Argo CD can rely on
+	// +optional
+	Health *RolloutHealth `json:"health,omitempty" protobuf:"bytes,28,opt,name=health"`
+	// SyncAction is the last value of the rollout.argoproj.io/sync-action annotation which was handled
+	// +optional
+	SyncAction string `json:"syncAction,omitempty" protobuf:"bytes,29,opt,name=syncAction"`
+	// LastUserAction records the user who last promoted, aborted or retried the rollout. It is recorded by the
+	// mutating admission webhook of the controller
+	// +optional
+	LastUserAction *RolloutUserAction `json:"lastUserAction,omitempty" protobuf:"bytes,30,opt,name=lastUserAction"`
+}
+
+// RolloutUserActionType is an action of a user on a rollout
+type RolloutUserActionType string
+
+const (
+	// RolloutUserActionPromote is the promotion of a rollout to its next step
+	RolloutUserActionPromote RolloutUserActionType = "Promote"
+	// RolloutUserActionPromoteFull is the full promotion of a rollout
+	RolloutUserActionPromoteFull RolloutUserActionType = "PromoteFull"
+	// RolloutUserActionAbort is the abort of an update
+	RolloutUserActionAbort RolloutUserActionType = "Abort"
+	// RolloutUserActionRetry is the retry of an aborted update
+	RolloutUserActionRetry RolloutUserActionType = "Retry"
+)
+
+// RolloutUserAction is an action of a user on a rollout
+type RolloutUserAction struct {
+	// Action is the action of the user
+	Action RolloutUserActionType `json:"action" protobuf:"bytes,1,opt,name=action,casttype=RolloutUserActionType"`
+	// User is the name of the user
+	User string `json:"user" protobuf:"bytes,2,opt,name=user"`
+	// Groups are the groups of the user
+	// +optional
+	Groups []string `json:"groups,omitempty" protobuf:"bytes,3,rep,name=groups"`
+	// Time is the time of the action
+	Time metav1.Time `json:"time" protobuf:"bytes,4,opt,name=time"`
+}
+
+// RolloutHealthStatus is the health status of a rollout, as defined by Argo CD
+type RolloutHealthStatus string
+
+const (
+	// RolloutHealthStatusHealthy indicates that the rollout is fully promoted and available
+	RolloutHealthStatusHealthy RolloutHealthStatus = "Healthy"
+	// RolloutHealthStatusProgressing indicates that the rollout is being updated
+	RolloutHealthStatusProgressing RolloutHealthStatus = "Progressing"
+	// RolloutHealthStatusSuspended indicates that the update of the rollout is paused
+	RolloutHealthStatusSuspended RolloutHealthStatus = "Suspended"
+	// RolloutHealthStatusDegraded indicates that the update of the rollout was aborted or failed
+	RolloutHealthStatusDegraded RolloutHealthStatus = "Degraded"
+)
+
+// RolloutHealth summarizes the health and the progress of a rollout
+type RolloutHealth struct {
+	// Status is the health status of the rollout (Healthy, Progressing, Suspended or Degraded)
+	Status RolloutHealthStatus `json:"status" protobuf:"bytes,1,opt,name=status,casttype=RolloutHealthStatus"`
+	// Reason is a CamelCase reason for the status (e.g. CanaryPauseStep or RolloutAborted)
+	// +optional
+	Reason string `json:"reason,omitempty" protobuf:"bytes,2,opt,name=reason"`
+	// CurrentStep is the current canary step (e.g. "setWeight: 20")
+	// +optional
+	CurrentStep string `json:"currentStep,omitempty" protobuf:"bytes,3,opt,name=currentStep"`
+	// PercentComplete is the percentage of the update which is completed, from 0 to 100
+	PercentComplete int32 `json:"percentComplete" protobuf:"varint,4,opt,name=percentComplete"`
+}
+
+// RolloutRestartStatus holds the progress of a restart performed in batches
+type RolloutRestartStatus struct {
+	// RestartAt is the restartAt time of the restart in progress
+	RestartAt metav1.Time `json:"restartAt" protobuf:"bytes,1,opt,name=restartAt"`
+	// CompletedBatches is the number of batches which were restarted and became available
+	CompletedBatches int32 `json:"completedBatches,omitempty" protobuf:"varint,2,opt,name=completedBatches"`
+	// RestartedReplicas is the number of pods created after restartAt when the last batch completed
+	RestartedReplicas int32 `json:"restartedReplicas,omitempty" protobuf:"varint,3,opt,name=restartedReplicas"`
+	// BatchCompletedAt is the time the last batch became available
+	// +optional
+	BatchCompletedAt *metav1.Time `json:"batchCompletedAt,omitempty" protobuf:"bytes,4,opt,name=batchCompletedAt"`
+	// CurrentAnalysisRunStatus indicates the status of the analysis run of the last batch
+	// +optional
+	CurrentAnalysisRunStatus *RolloutAnalysisRunStatus `json:"currentAnalysisRunStatus,omitempty" protobuf:"bytes,5,opt,name=currentAnalysisRunStatus"`
+	// Halted indicates the restart was halted because the analysis of a batch did not succeed
+	Halted bool `json:"halted,omitempty" protobuf:"varint,6,opt,name=halted"`
+	// Message explains why the restart was halted
+	Message string `json:"message,omitempty" protobuf:"bytes,7,opt,name=message"`
 }
 
 // BlueGreenStatus status fields that only pertain to the blueGreen rollout
@@ -1044,6 +1733,120 @@ type CanaryStatus struct {
 	StablePingPong PingPongType `json:"stablePingPong,omitempty" protobuf:"bytes,5,opt,name=stablePingPong"`
 	// StepPluginStatuses holds the status of the step plugins executed
 	StepPluginStatuses []StepPluginStatus `json:"stepPluginStatuses,omitempty" protobuf:"bytes,6,rep,name=stepPluginStatuses"`
+	// CurrentGuardrailAnalysisRunStatus indicates the status of the current guardrail analysis run
+	CurrentGuardrailAnalysisRunStatus *RolloutAnalysisRunStatus `json:"currentGuardrailAnalysisRunStatus,omitempty" protobuf:"bytes,7,opt,name=currentGuardrailAnalysisRunStatus"`
+	// WeightOverride is a canary traffic weight set manually, which is used instead of the weight of the steps
+	// until the rollout moves to another step
+	WeightOverride *WeightOverride `json:"weightOverride,omitempty" protobuf:"bytes,8,opt,name=weightOverride"`
+	// WeightHistory records the last changes of the canary traffic weight set on the traffic routers, and of its
+	// verification, the oldest first. Only valid when using traffic routing
+	WeightHistory []TrafficWeightRecord `json:"weightHistory,omitempty" protobuf:"bytes,9,rep,name=weightHistory"`
+	// StableReplicas is the number of pods of the stable ReplicaSet while the canary ReplicaSet is rolled out
+	StableReplicas int32 `json:"stableReplicas,omitempty" protobuf:"varint,10,opt,name=stableReplicas"`
+	// CanaryReplicas is the number of pods of the canary ReplicaSet while it is rolled out
+	CanaryReplicas int32 `json:"canaryReplicas,omitempty" protobuf:"varint,11,opt,name=canaryReplicas"`
+	// CanarySelector is the label selector of the pods of the canary ReplicaSet while it is rolled out, which the
+	// metrics of an HPA or of an external autoscaler can target to follow the load of the canary
+	CanarySelector string `json:"canarySelector,omitempty" protobuf:"bytes,12,opt,name=canarySelector"`
+	// FeatureFlags records the percentages the LaunchDarkly flags of the setFeatureFlag steps were last set to
+	FeatureFlags []FeatureFlagStatus `json:"featureFlags,omitempty" protobuf:"bytes,13,rep,name=featureFlags"`
+	// Migrations records the migration steps started by the current update, and their rollbacks
+	Migrations []MigrationStatus `json:"migrations,omitempty" protobuf:"bytes,14,rep,name=migrations"`
+	// Gate is the status of the webhook of the current gate step
+	Gate *GateStatus `json:"gate,omitempty" protobuf:"bytes,15,opt,name=gate"`
+	// CurrentStepStartedAt is the time the current canary step started. It is only recorded for steps with a
+	// progress deadline, which is measured from it.
+	CurrentStepStartedAt *metav1.Time `json:"currentStepStartedAt,omitempty" protobuf:"bytes,16,opt,name=currentStepStartedAt"`
+	// StableFeatureFlags are the feature flags of the setFeatureFlag steps the stable ReplicaSet was promoted with,
+	// which are enabled for all users outside of an update
+	StableFeatureFlags []string `json:"stableFeatureFlags,omitempty" protobuf:"bytes,17,rep,name=stableFeatureFlags"`
+}
+
+// GateDecision is the decision of the webhook of a gate step
+type GateDecision string
+
+const (
+	GateDecisionProceed GateDecision = "proceed"
+	GateDecisionPause   GateDecision = "pause"
+	GateDecisionAbort   GateDecision = "abort"
+)
+
+// GateStatus is the status of the webhook of a gate step
+type GateStatus struct {
+	// StepIndex is the index of the gate step
+	StepIndex int32 `json:"stepIndex" protobuf:"varint,1,opt,name=stepIndex"`
+	// PodTemplateHash is the pod template hash of the update the decision belongs to
+	PodTemplateHash string `json:"podTemplateHash" protobuf:"bytes,2,opt,name=podTemplateHash"`
+	// Decision is the last decision of the webhook
+	// +optional
+	Decision GateDecision `json:"decision,omitempty" protobuf:"bytes,3,opt,name=decision,casttype=GateDecision"`
+	// Message is the message of the last decision, or the error of the last failed request
+	// +optional
+	Message string `json:"message,omitempty" protobuf:"bytes,4,opt,name=message"`
+	// LastCheckTime is the time of the last request
+	// +optional
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty" protobuf:"bytes,5,opt,name=lastCheckTime"`
+	// ConsecutiveErrors is the number of consecutive failed requests
+	// +optional
+	ConsecutiveErrors int32 `json:"consecutiveErrors,omitempty" protobuf:"varint,6,opt,name=consecutiveErrors"`
+}
+
+// MigrationPhase is the phase of the migrations of a migration step
+type MigrationPhase string
+
+const (
+	MigrationPhaseRunning        MigrationPhase = "Running"
+	MigrationPhaseSuccessful     MigrationPhase = "Successful"
+	MigrationPhaseFailed         MigrationPhase = "Failed"
+	MigrationPhaseRollingBack    MigrationPhase = "RollingBack"
+	MigrationPhaseRolledBack     MigrationPhase = "RolledBack"
+	MigrationPhaseRollbackFailed MigrationPhase = "RollbackFailed"
+)
+
+// MigrationStatus is the status of the migrations of a migration step
+type MigrationStatus struct {
+	// StepIndex is the index of the migration step
+	StepIndex int32 `json:"stepIndex" protobuf:"varint,1,opt,name=stepIndex"`
+	// PodTemplateHash is the pod template hash of the update the migrations belong to
+	PodTemplateHash string `json:"podTemplateHash" protobuf:"bytes,2,opt,name=podTemplateHash"`
+	// Phase is the phase of the migrations
+	Phase MigrationPhase `json:"phase" protobuf:"bytes,3,opt,name=phase,casttype=MigrationPhase"`
+	// StartedAt is the time the step started the migrations
+	StartedAt metav1.Time `json:"startedAt" protobuf:"bytes,4,opt,name=startedAt"`
+	// Message is a message about the phase, e.g. the reason of a failure
+	// +optional
+	Message string `json:"message,omitempty" protobuf:"bytes,5,opt,name=message"`
+}
+
+// FeatureFlagStatus is the percentage a feature flag was last set to
+type FeatureFlagStatus struct {
+	// Flag is the key of the feature flag
+	Flag string `json:"flag" protobuf:"bytes,1,opt,name=flag"`
+	// Percentage is the percentage of the users the flag is enabled for
+	Percentage int32 `json:"percentage" protobuf:"varint,2,opt,name=percentage"`
+}
+
+// TrafficWeightRecord is a change of the canary traffic weight set on a traffic router, or of its verification
+type TrafficWeightRecord struct {
+	// Time is when the weight was set, or its verification changed
+	Time metav1.Time `json:"time" protobuf:"bytes,1,opt,name=time"`
+	// Router is the type of the traffic router the weight was set on
+	Router string `json:"router" protobuf:"bytes,2,opt,name=router"`
+	// DesiredWeight is the percentage of traffic desired for the canary
+	DesiredWeight int32 `json:"desiredWeight" protobuf:"varint,3,opt,name=desiredWeight"`
+	// Verified indicates whether the traffic router verified that the weight took effect. It is not set when the
+	// traffic router does not verify weights
+	Verified *bool `json:"verified,omitempty" protobuf:"varint,4,opt,name=verified"`
+}
+
+// WeightOverride is a canary traffic weight set manually, e.g. to drain the canary in an emergency
+type WeightOverride struct {
+	// Weight is the percentage of traffic sent to the canary
+	// +kubebuilder:validation:Minimum=0
+	Weight int32 `json:"weight" protobuf:"varint,1,opt,name=weight"`
+	// StepIndex is the index of the step during which the weight was overridden. The override is removed once the
+	// rollout moves to another step, is aborted or is promoted.
+	StepIndex int32 `json:"stepIndex" protobuf:"varint,2,opt,name=stepIndex"`
 }
 
 type PingPongType string
@@ -1110,6 +1913,21 @@ type StepPluginStatus struct {
 	// +kubebuilder:validation:Type=object
 	// Status holds the internal status of the plugin for this operation
 	Status json.RawMessage `json:"status,omitempty" protobuf:"bytes,12,opt,name=status"`
+	// StateVersion is the version of the Status, set by the plugins of the v2 step plugin API. It is passed back to the
+	// plugin with the Status, so that a new version of the plugin can migrate the Status of an operation it resumes
+	StateVersion int32 `json:"stateVersion,omitempty" protobuf:"varint,13,opt,name=stateVersion"`
+	// Progress is the progress of the operation reported by the plugin
+	Progress *StepPluginProgress `json:"progress,omitempty" protobuf:"bytes,14,opt,name=progress"`
+}
+
+// StepPluginProgress is the progress of a running operation of a step plugin
+type StepPluginProgress struct {
+	// Percent is the completion of the operation, from 0 to 100
+	Percent int32 `json:"percent,omitempty" protobuf:"varint,1,opt,name=percent"`
+	// Stage describes the current stage of the operation, e.g. migrating table orders (3/10)
+	Stage string `json:"stage,omitempty" protobuf:"bytes,2,opt,name=stage"`
+	// UpdatedAt indicates when the plugin last reported its progress
+	UpdatedAt *metav1.Time `json:"updatedAt,omitempty" protobuf:"bytes,3,opt,name=updatedAt"`
 }
 
 // StepPluginPhase is the overall phase of a StepPlugin
@@ -1196,6 +2014,10 @@ const (
 	// RolloutHealthy means that rollout is in a completed state and is healthy. Which means that all the pods have been updated
 	// and are passing their health checks and are ready to serve traffic.
 	RolloutHealthy RolloutConditionType = "Healthy"
+	// RolloutNotificationDelivered means that the last notification about the rollout was delivered.
+	RolloutNotificationDelivered RolloutConditionType = "NotificationDelivered"
+	// RolloutProgressionDenied means that a progression policy denies the rollout to progress to its next step.
+	RolloutProgressionDenied RolloutConditionType = "ProgressionDenied"
 )
 
 // RolloutCondition describes the state of a rollout at a certain point.
@@ -22,8 +22,9 @@ limitations under the License.
 package v1alpha1
 
 import (
-	json "encoding/json"
+	jsontext "encoding/json/jsontext"
 
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	intstr "k8s.io/apimachinery/pkg/util/intstr"
@@ -456,6 +457,11 @@ func (in *AntiAffinity) DeepCopyInto(out *AntiAffinity) {
 		*out = new(RequiredDuringSchedulingIgnoredDuringExecution)
 		**out = **in
 	}
+	if in.TopologySpread != nil {
+		in, out := &in.TopologySpread, &out.TopologySpread
+		*out = new(AntiAffinityTopologySpread)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -469,6 +475,32 @@ func (in *AntiAffinity) DeepCopy() *AntiAffinity {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AntiAffinityTopologySpread) DeepCopyInto(out *AntiAffinityTopologySpread) {
+	*out = *in
+	if in.TopologyKeys != nil {
+		in, out := &in.TopologyKeys, &out.TopologyKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxSkew != nil {
+		in, out := &in.MaxSkew, &out.MaxSkew
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AntiAffinityTopologySpread.
+func (in *AntiAffinityTopologySpread) DeepCopy() *AntiAffinityTopologySpread {
+	if in == nil {
+		return nil
+	}
+	out := new(AntiAffinityTopologySpread)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApisixRoute) DeepCopyInto(out *ApisixRoute) {
 	*out = *in
@@ -686,6 +718,87 @@ func (in *AwsResourceRef) DeepCopy() *AwsResourceRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMonitorMetric) DeepCopyInto(out *AzureMonitorMetric) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMonitorMetric.
+func (in *AzureMonitorMetric) DeepCopy() *AzureMonitorMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMonitorMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlueGreenGatewayAPIPreviewRouting) DeepCopyInto(out *BlueGreenGatewayAPIPreviewRouting) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueGreenGatewayAPIPreviewRouting.
+func (in *BlueGreenGatewayAPIPreviewRouting) DeepCopy() *BlueGreenGatewayAPIPreviewRouting {
+	if in == nil {
+		return nil
+	}
+	out := new(BlueGreenGatewayAPIPreviewRouting)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlueGreenIstioPreviewRouting) DeepCopyInto(out *BlueGreenIstioPreviewRouting) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueGreenIstioPreviewRouting.
+func (in *BlueGreenIstioPreviewRouting) DeepCopy() *BlueGreenIstioPreviewRouting {
+	if in == nil {
+		return nil
+	}
+	out := new(BlueGreenIstioPreviewRouting)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlueGreenPreviewRouting) DeepCopyInto(out *BlueGreenPreviewRouting) {
+	*out = *in
+	if in.Match != nil {
+		in, out := &in.Match, &out.Match
+		*out = make([]HeaderRoutingMatch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Istio != nil {
+		in, out := &in.Istio, &out.Istio
+		*out = new(BlueGreenIstioPreviewRouting)
+		**out = **in
+	}
+	if in.GatewayAPI != nil {
+		in, out := &in.GatewayAPI, &out.GatewayAPI
+		*out = new(BlueGreenGatewayAPIPreviewRouting)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueGreenPreviewRouting.
+func (in *BlueGreenPreviewRouting) DeepCopy() *BlueGreenPreviewRouting {
+	if in == nil {
+		return nil
+	}
+	out := new(BlueGreenPreviewRouting)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BlueGreenStatus) DeepCopyInto(out *BlueGreenStatus) {
 	*out = *in
@@ -740,6 +853,11 @@ func (in *BlueGreenStrategy) DeepCopyInto(out *BlueGreenStrategy) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.ScaleDownDelayOverrides != nil {
+		in, out := &in.ScaleDownDelayOverrides, &out.ScaleDownDelayOverrides
+		*out = make([]ScaleDownDelayOverride, len(*in))
+		copy(*out, *in)
+	}
 	if in.PrePromotionAnalysis != nil {
 		in, out := &in.PrePromotionAnalysis, &out.PrePromotionAnalysis
 		*out = new(RolloutAnalysis)
@@ -755,6 +873,11 @@ func (in *BlueGreenStrategy) DeepCopyInto(out *BlueGreenStrategy) {
 		*out = new(RolloutAnalysis)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PostPromotionSmokeTest != nil {
+		in, out := &in.PostPromotionSmokeTest, &out.PostPromotionSmokeTest
+		*out = new(PostPromotionSmokeTest)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.PreviewMetadata != nil {
 		in, out := &in.PreviewMetadata, &out.PreviewMetadata
 		*out = new(PodTemplateMetadata)
@@ -770,6 +893,16 @@ func (in *BlueGreenStrategy) DeepCopyInto(out *BlueGreenStrategy) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.PreviewReplicaProfile != nil {
+		in, out := &in.PreviewReplicaProfile, &out.PreviewReplicaProfile
+		*out = new(PreviewReplicaProfile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreviewRouting != nil {
+		in, out := &in.PreviewRouting, &out.PreviewRouting
+		*out = new(BlueGreenPreviewRouting)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -808,6 +941,49 @@ func (in *CanaryStatus) DeepCopyInto(out *CanaryStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CurrentGuardrailAnalysisRunStatus != nil {
+		in, out := &in.CurrentGuardrailAnalysisRunStatus, &out.CurrentGuardrailAnalysisRunStatus
+		*out = new(RolloutAnalysisRunStatus)
+		**out = **in
+	}
+	if in.WeightOverride != nil {
+		in, out := &in.WeightOverride, &out.WeightOverride
+		*out = new(WeightOverride)
+		**out = **in
+	}
+	if in.WeightHistory != nil {
+		in, out := &in.WeightHistory, &out.WeightHistory
+		*out = make([]TrafficWeightRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FeatureFlags != nil {
+		in, out := &in.FeatureFlags, &out.FeatureFlags
+		*out = make([]FeatureFlagStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Migrations != nil {
+		in, out := &in.Migrations, &out.Migrations
+		*out = make([]MigrationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Gate != nil {
+		in, out := &in.Gate, &out.Gate
+		*out = new(GateStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CurrentStepStartedAt != nil {
+		in, out := &in.CurrentStepStartedAt, &out.CurrentStepStartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.StableFeatureFlags != nil {
+		in, out := &in.StableFeatureFlags, &out.StableFeatureFlags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -864,6 +1040,41 @@ func (in *CanaryStep) DeepCopyInto(out *CanaryStep) {
 		*out = new(PluginStep)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ProgressDeadline != nil {
+		in, out := &in.ProgressDeadline, &out.ProgressDeadline
+		*out = new(StepProgressDeadline)
+		**out = **in
+	}
+	if in.SetCanaryPodTemplateOverlay != nil {
+		in, out := &in.SetCanaryPodTemplateOverlay, &out.SetCanaryPodTemplateOverlay
+		*out = new(PodTemplateOverlay)
+		**out = **in
+	}
+	if in.LoadTest != nil {
+		in, out := &in.LoadTest, &out.LoadTest
+		*out = new(RolloutLoadTestStep)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreProvision != nil {
+		in, out := &in.PreProvision, &out.PreProvision
+		*out = new(RolloutPreProvisionStep)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SetFeatureFlag != nil {
+		in, out := &in.SetFeatureFlag, &out.SetFeatureFlag
+		*out = new(SetFeatureFlag)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(RolloutMigrationStep)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Gate != nil {
+		in, out := &in.Gate, &out.Gate
+		*out = new(RolloutGateStep)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -932,6 +1143,11 @@ func (in *CanaryStrategy) DeepCopyInto(out *CanaryStrategy) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.ScaleDownDelayOverrides != nil {
+		in, out := &in.ScaleDownDelayOverrides, &out.ScaleDownDelayOverrides
+		*out = make([]ScaleDownDelayOverride, len(*in))
+		copy(*out, *in)
+	}
 	if in.AbortScaleDownDelaySeconds != nil {
 		in, out := &in.AbortScaleDownDelaySeconds, &out.AbortScaleDownDelaySeconds
 		*out = new(int32)
@@ -952,6 +1168,26 @@ func (in *CanaryStrategy) DeepCopyInto(out *CanaryStrategy) {
 		*out = new(ReplicaProgressThreshold)
 		**out = **in
 	}
+	if in.Guardrail != nil {
+		in, out := &in.Guardrail, &out.Guardrail
+		*out = new(RolloutGuardrail)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScaleDownVerification != nil {
+		in, out := &in.ScaleDownVerification, &out.ScaleDownVerification
+		*out = new(ScaleDownVerification)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KEDA != nil {
+		in, out := &in.KEDA, &out.KEDA
+		*out = new(KEDACoordination)
+		**out = **in
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(ReplicaSetPodDisruptionBudget)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1173,6 +1409,22 @@ func (in *DatadogMetric) DeepCopy() *DatadogMetric {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrainProbe) DeepCopyInto(out *DrainProbe) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrainProbe.
+func (in *DrainProbe) DeepCopy() *DrainProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(DrainProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DryRun) DeepCopyInto(out *DryRun) {
 	*out = *in
@@ -1189,6 +1441,22 @@ func (in *DryRun) DeepCopy() *DryRun {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EphemeralMetadataEnvVar) DeepCopyInto(out *EphemeralMetadataEnvVar) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EphemeralMetadataEnvVar.
+func (in *EphemeralMetadataEnvVar) DeepCopy() *EphemeralMetadataEnvVar {
+	if in == nil {
+		return nil
+	}
+	out := new(EphemeralMetadataEnvVar)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Experiment) DeepCopyInto(out *Experiment) {
 	*out = *in
@@ -1350,6 +1618,25 @@ func (in *ExperimentSpec) DeepCopyInto(out *ExperimentSpec) {
 		copy(*out, *in)
 	}
 	in.AnalysisRunMetadata.DeepCopyInto(&out.AnalysisRunMetadata)
+	if in.WinnerSelection != nil {
+		in, out := &in.WinnerSelection, &out.WinnerSelection
+		*out = new(ExperimentWinnerSelection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartAt != nil {
+		in, out := &in.StartAt, &out.StartAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(ExperimentWindow)
+		**out = **in
+	}
+	if in.TTLStrategy != nil {
+		in, out := &in.TTLStrategy, &out.TTLStrategy
+		*out = new(TTLStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1389,6 +1676,18 @@ func (in *ExperimentStatus) DeepCopyInto(out *ExperimentStatus) {
 		*out = make([]ExperimentAnalysisRunStatus, len(*in))
 		copy(*out, *in)
 	}
+	if in.PausedAt != nil {
+		in, out := &in.PausedAt, &out.PausedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.ResultExportedAt != nil {
+		in, out := &in.ResultExportedAt, &out.ResultExportedAt
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
@@ -1403,165 +1702,338 @@ func (in *ExperimentStatus) DeepCopy() *ExperimentStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FieldRef) DeepCopyInto(out *FieldRef) {
+func (in *ExperimentWindow) DeepCopyInto(out *ExperimentWindow) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FieldRef.
-func (in *FieldRef) DeepCopy() *FieldRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExperimentWindow.
+func (in *ExperimentWindow) DeepCopy() *ExperimentWindow {
 	if in == nil {
 		return nil
 	}
-	out := new(FieldRef)
+	out := new(ExperimentWindow)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GraphiteMetric) DeepCopyInto(out *GraphiteMetric) {
+func (in *ExperimentWinnerCandidate) DeepCopyInto(out *ExperimentWinnerCandidate) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GraphiteMetric.
-func (in *GraphiteMetric) DeepCopy() *GraphiteMetric {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExperimentWinnerCandidate.
+func (in *ExperimentWinnerCandidate) DeepCopy() *ExperimentWinnerCandidate {
 	if in == nil {
 		return nil
 	}
-	out := new(GraphiteMetric)
+	out := new(ExperimentWinnerCandidate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HeaderRoutingMatch) DeepCopyInto(out *HeaderRoutingMatch) {
+func (in *ExperimentWinnerSelection) DeepCopyInto(out *ExperimentWinnerSelection) {
 	*out = *in
-	if in.HeaderValue != nil {
-		in, out := &in.HeaderValue, &out.HeaderValue
-		*out = new(StringMatch)
-		**out = **in
+	if in.Candidates != nil {
+		in, out := &in.Candidates, &out.Candidates
+		*out = make([]ExperimentWinnerCandidate, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeaderRoutingMatch.
-func (in *HeaderRoutingMatch) DeepCopy() *HeaderRoutingMatch {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExperimentWinnerSelection.
+func (in *ExperimentWinnerSelection) DeepCopy() *ExperimentWinnerSelection {
 	if in == nil {
 		return nil
 	}
-	out := new(HeaderRoutingMatch)
+	out := new(ExperimentWinnerSelection)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InfluxdbMetric) DeepCopyInto(out *InfluxdbMetric) {
+func (in *ExperimentWorkloadRef) DeepCopyInto(out *ExperimentWorkloadRef) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InfluxdbMetric.
-func (in *InfluxdbMetric) DeepCopy() *InfluxdbMetric {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExperimentWorkloadRef.
+func (in *ExperimentWorkloadRef) DeepCopy() *ExperimentWorkloadRef {
 	if in == nil {
 		return nil
 	}
-	out := new(InfluxdbMetric)
+	out := new(ExperimentWorkloadRef)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IstioDestinationRule) DeepCopyInto(out *IstioDestinationRule) {
+func (in *FeatureFlagStatus) DeepCopyInto(out *FeatureFlagStatus) {
 	*out = *in
-	if in.AdditionalSubsetNames != nil {
-		in, out := &in.AdditionalSubsetNames, &out.AdditionalSubsetNames
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IstioDestinationRule.
-func (in *IstioDestinationRule) DeepCopy() *IstioDestinationRule {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureFlagStatus.
+func (in *FeatureFlagStatus) DeepCopy() *FeatureFlagStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(IstioDestinationRule)
+	out := new(FeatureFlagStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IstioTrafficRouting) DeepCopyInto(out *IstioTrafficRouting) {
+func (in *FeatureFlagVariants) DeepCopyInto(out *FeatureFlagVariants) {
 	*out = *in
-	if in.VirtualService != nil {
-		in, out := &in.VirtualService, &out.VirtualService
-		*out = new(IstioVirtualService)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.DestinationRule != nil {
-		in, out := &in.DestinationRule, &out.DestinationRule
-		*out = new(IstioDestinationRule)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.VirtualServices != nil {
-		in, out := &in.VirtualServices, &out.VirtualServices
-		*out = make([]IstioVirtualService, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IstioTrafficRouting.
-func (in *IstioTrafficRouting) DeepCopy() *IstioTrafficRouting {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureFlagVariants.
+func (in *FeatureFlagVariants) DeepCopy() *FeatureFlagVariants {
 	if in == nil {
 		return nil
 	}
-	out := new(IstioTrafficRouting)
+	out := new(FeatureFlagVariants)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IstioVirtualService) DeepCopyInto(out *IstioVirtualService) {
+func (in *FieldRef) DeepCopyInto(out *FieldRef) {
 	*out = *in
-	if in.Routes != nil {
-		in, out := &in.Routes, &out.Routes
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.TLSRoutes != nil {
-		in, out := &in.TLSRoutes, &out.TLSRoutes
-		*out = make([]TLSRoute, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.TCPRoutes != nil {
-		in, out := &in.TCPRoutes, &out.TCPRoutes
-		*out = make([]TCPRoute, len(*in))
-		copy(*out, *in)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IstioVirtualService.
-func (in *IstioVirtualService) DeepCopy() *IstioVirtualService {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FieldRef.
+func (in *FieldRef) DeepCopy() *FieldRef {
 	if in == nil {
 		return nil
 	}
-	out := new(IstioVirtualService)
+	out := new(FieldRef)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *JobMetric) DeepCopyInto(out *JobMetric) {
+func (in *GateStatus) DeepCopyInto(out *GateStatus) {
 	*out = *in
-	in.Metadata.DeepCopyInto(&out.Metadata)
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GateStatus.
+func (in *GateStatus) DeepCopy() *GateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GraphiteMetric) DeepCopyInto(out *GraphiteMetric) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GraphiteMetric.
+func (in *GraphiteMetric) DeepCopy() *GraphiteMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(GraphiteMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HeaderRoutingMatch) DeepCopyInto(out *HeaderRoutingMatch) {
+	*out = *in
+	if in.HeaderValue != nil {
+		in, out := &in.HeaderValue, &out.HeaderValue
+		*out = new(StringMatch)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeaderRoutingMatch.
+func (in *HeaderRoutingMatch) DeepCopy() *HeaderRoutingMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(HeaderRoutingMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageVerification) DeepCopyInto(out *ImageVerification) {
+	*out = *in
+	if in.PublicKeys != nil {
+		in, out := &in.PublicKeys, &out.PublicKeys
+		*out = make([]SecretKeyRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.Identities != nil {
+		in, out := &in.Identities, &out.Identities
+		*out = make([]ImageVerificationIdentity, len(*in))
+		copy(*out, *in)
+	}
+	if in.CertificateAuthorities != nil {
+		in, out := &in.CertificateAuthorities, &out.CertificateAuthorities
+		*out = new(SecretKeyRef)
+		**out = **in
+	}
+	if in.TransparencyLogPublicKey != nil {
+		in, out := &in.TransparencyLogPublicKey, &out.TransparencyLogPublicKey
+		*out = new(SecretKeyRef)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageVerification.
+func (in *ImageVerification) DeepCopy() *ImageVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageVerificationIdentity) DeepCopyInto(out *ImageVerificationIdentity) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageVerificationIdentity.
+func (in *ImageVerificationIdentity) DeepCopy() *ImageVerificationIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageVerificationIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InfluxdbMetric) DeepCopyInto(out *InfluxdbMetric) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InfluxdbMetric.
+func (in *InfluxdbMetric) DeepCopy() *InfluxdbMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(InfluxdbMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IstioDestinationRule) DeepCopyInto(out *IstioDestinationRule) {
+	*out = *in
+	if in.AdditionalSubsetNames != nil {
+		in, out := &in.AdditionalSubsetNames, &out.AdditionalSubsetNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IstioDestinationRule.
+func (in *IstioDestinationRule) DeepCopy() *IstioDestinationRule {
+	if in == nil {
+		return nil
+	}
+	out := new(IstioDestinationRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IstioTrafficRouting) DeepCopyInto(out *IstioTrafficRouting) {
+	*out = *in
+	if in.VirtualService != nil {
+		in, out := &in.VirtualService, &out.VirtualService
+		*out = new(IstioVirtualService)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DestinationRule != nil {
+		in, out := &in.DestinationRule, &out.DestinationRule
+		*out = new(IstioDestinationRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VirtualServices != nil {
+		in, out := &in.VirtualServices, &out.VirtualServices
+		*out = make([]IstioVirtualService, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IstioTrafficRouting.
+func (in *IstioTrafficRouting) DeepCopy() *IstioTrafficRouting {
+	if in == nil {
+		return nil
+	}
+	out := new(IstioTrafficRouting)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IstioVirtualService) DeepCopyInto(out *IstioVirtualService) {
+	*out = *in
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TLSRoutes != nil {
+		in, out := &in.TLSRoutes, &out.TLSRoutes
+		*out = make([]TLSRoute, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TCPRoutes != nil {
+		in, out := &in.TCPRoutes, &out.TCPRoutes
+		*out = make([]TCPRoute, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IstioVirtualService.
+func (in *IstioVirtualService) DeepCopy() *IstioVirtualService {
+	if in == nil {
+		return nil
+	}
+	out := new(IstioVirtualService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobMetric) DeepCopyInto(out *JobMetric) {
+	*out = *in
+	in.Metadata.DeepCopyInto(&out.Metadata)
 	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
@@ -1576,6 +2048,38 @@ func (in *JobMetric) DeepCopy() *JobMetric {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *K6LoadTest) DeepCopyInto(out *K6LoadTest) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new K6LoadTest.
+func (in *K6LoadTest) DeepCopy() *K6LoadTest {
+	if in == nil {
+		return nil
+	}
+	out := new(K6LoadTest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KEDACoordination) DeepCopyInto(out *KEDACoordination) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KEDACoordination.
+func (in *KEDACoordination) DeepCopy() *KEDACoordination {
+	if in == nil {
+		return nil
+	}
+	out := new(KEDACoordination)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KayentaMetric) DeepCopyInto(out *KayentaMetric) {
 	*out = *in
@@ -1632,6 +2136,39 @@ func (in *KayentaThreshold) DeepCopy() *KayentaThreshold {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KnativeTrafficRouting) DeepCopyInto(out *KnativeTrafficRouting) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KnativeTrafficRouting.
+func (in *KnativeTrafficRouting) DeepCopy() *KnativeTrafficRouting {
+	if in == nil {
+		return nil
+	}
+	out := new(KnativeTrafficRouting)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LaunchDarklyFeatureFlag) DeepCopyInto(out *LaunchDarklyFeatureFlag) {
+	*out = *in
+	out.APITokenSecretRef = in.APITokenSecretRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LaunchDarklyFeatureFlag.
+func (in *LaunchDarklyFeatureFlag) DeepCopy() *LaunchDarklyFeatureFlag {
+	if in == nil {
+		return nil
+	}
+	out := new(LaunchDarklyFeatureFlag)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MangedRoutes) DeepCopyInto(out *MangedRoutes) {
 	*out = *in
@@ -1801,19 +2338,24 @@ func (in *MetricProvider) DeepCopyInto(out *MetricProvider) {
 	}
 	if in.Plugin != nil {
 		in, out := &in.Plugin, &out.Plugin
-		*out = make(map[string]json.RawMessage, len(*in))
+		*out = make(map[string]jsontext.Value, len(*in))
 		for key, val := range *in {
 			var outVal []byte
 			if val == nil {
 				(*out)[key] = nil
 			} else {
 				in, out := &val, &outVal
-				*out = make(json.RawMessage, len(*in))
+				*out = make(jsontext.Value, len(*in))
 				copy(*out, *in)
 			}
 			(*out)[key] = outVal
 		}
 	}
+	if in.AzureMonitor != nil {
+		in, out := &in.AzureMonitor, &out.AzureMonitor
+		*out = new(AzureMonitorMetric)
+		**out = **in
+	}
 	return
 }
 
@@ -1858,35 +2400,78 @@ func (in *MetricResult) DeepCopy() *MetricResult {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NewRelicMetric) DeepCopyInto(out *NewRelicMetric) {
+func (in *MigrationStatus) DeepCopyInto(out *MigrationStatus) {
 	*out = *in
-	if in.Timeout != nil {
-		in, out := &in.Timeout, &out.Timeout
-		*out = new(int64)
-		**out = **in
-	}
+	in.StartedAt.DeepCopyInto(&out.StartedAt)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NewRelicMetric.
-func (in *NewRelicMetric) DeepCopy() *NewRelicMetric {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationStatus.
+func (in *MigrationStatus) DeepCopy() *MigrationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(NewRelicMetric)
+	out := new(MigrationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NginxTrafficRouting) DeepCopyInto(out *NginxTrafficRouting) {
+func (in *MigrationStatusCheck) DeepCopyInto(out *MigrationStatusCheck) {
 	*out = *in
-	if in.AdditionalIngressAnnotations != nil {
-		in, out := &in.AdditionalIngressAnnotations, &out.AdditionalIngressAnnotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]WebMetricHeader, len(*in))
+		copy(*out, *in)
+	}
+	if in.AuthorizationSecretRef != nil {
+		in, out := &in.AuthorizationSecretRef, &out.AuthorizationSecretRef
+		*out = new(SecretKeyRef)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationStatusCheck.
+func (in *MigrationStatusCheck) DeepCopy() *MigrationStatusCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationStatusCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NewRelicMetric) DeepCopyInto(out *NewRelicMetric) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NewRelicMetric.
+func (in *NewRelicMetric) DeepCopy() *NewRelicMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(NewRelicMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NginxTrafficRouting) DeepCopyInto(out *NginxTrafficRouting) {
+	*out = *in
+	if in.AdditionalIngressAnnotations != nil {
+		in, out := &in.AdditionalIngressAnnotations, &out.AdditionalIngressAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
 	if in.StableIngresses != nil {
 		in, out := &in.StableIngresses, &out.StableIngresses
@@ -1913,6 +2498,32 @@ func (in *NginxTrafficRouting) DeepCopy() *NginxTrafficRouting {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationSubscription) DeepCopyInto(out *NotificationSubscription) {
+	*out = *in
+	if in.Triggers != nil {
+		in, out := &in.Triggers, &out.Triggers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Recipients != nil {
+		in, out := &in.Recipients, &out.Recipients
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationSubscription.
+func (in *NotificationSubscription) DeepCopy() *NotificationSubscription {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationSubscription)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OAuth2Config) DeepCopyInto(out *OAuth2Config) {
 	*out = *in
@@ -1950,6 +2561,27 @@ func (in *ObjectRef) DeepCopy() *ObjectRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenFeatureFeatureFlag) DeepCopyInto(out *OpenFeatureFeatureFlag) {
+	*out = *in
+	if in.Variants != nil {
+		in, out := &in.Variants, &out.Variants
+		*out = new(FeatureFlagVariants)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenFeatureFeatureFlag.
+func (in *OpenFeatureFeatureFlag) DeepCopy() *OpenFeatureFeatureFlag {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenFeatureFeatureFlag)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PauseCondition) DeepCopyInto(out *PauseCondition) {
 	*out = *in
@@ -1988,7 +2620,7 @@ func (in *PluginStep) DeepCopyInto(out *PluginStep) {
 	*out = *in
 	if in.Config != nil {
 		in, out := &in.Config, &out.Config
-		*out = make(json.RawMessage, len(*in))
+		*out = make(jsontext.Value, len(*in))
 		copy(*out, *in)
 	}
 	return
@@ -2021,6 +2653,11 @@ func (in *PodTemplateMetadata) DeepCopyInto(out *PodTemplateMetadata) {
 			(*out)[key] = val
 		}
 	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]EphemeralMetadataEnvVar, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -2034,6 +2671,62 @@ func (in *PodTemplateMetadata) DeepCopy() *PodTemplateMetadata {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodTemplateOverlay) DeepCopyInto(out *PodTemplateOverlay) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodTemplateOverlay.
+func (in *PodTemplateOverlay) DeepCopy() *PodTemplateOverlay {
+	if in == nil {
+		return nil
+	}
+	out := new(PodTemplateOverlay)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostPromotionSmokeTest) DeepCopyInto(out *PostPromotionSmokeTest) {
+	*out = *in
+	if in.Job != nil {
+		in, out := &in.Job, &out.Job
+		*out = new(JobMetric)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make([]AnalysisTemplateRef, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]AnalysisRunArgument, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.WindowSeconds != nil {
+		in, out := &in.WindowSeconds, &out.WindowSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostPromotionSmokeTest.
+func (in *PostPromotionSmokeTest) DeepCopy() *PostPromotionSmokeTest {
+	if in == nil {
+		return nil
+	}
+	out := new(PostPromotionSmokeTest)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PreferredDuringSchedulingIgnoredDuringExecution) DeepCopyInto(out *PreferredDuringSchedulingIgnoredDuringExecution) {
 	*out = *in
@@ -2050,6 +2743,32 @@ func (in *PreferredDuringSchedulingIgnoredDuringExecution) DeepCopy() *Preferred
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreviewReplicaProfile) DeepCopyInto(out *PreviewReplicaProfile) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxReplicas != nil {
+		in, out := &in.MaxReplicas, &out.MaxReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreviewReplicaProfile.
+func (in *PreviewReplicaProfile) DeepCopy() *PreviewReplicaProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(PreviewReplicaProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PrometheusMetric) DeepCopyInto(out *PrometheusMetric) {
 	*out = *in
@@ -2114,6 +2833,32 @@ func (in *ReplicaProgressThreshold) DeepCopy() *ReplicaProgressThreshold {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaSetPodDisruptionBudget) DeepCopyInto(out *ReplicaSetPodDisruptionBudget) {
+	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaSetPodDisruptionBudget.
+func (in *ReplicaSetPodDisruptionBudget) DeepCopy() *ReplicaSetPodDisruptionBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaSetPodDisruptionBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RequiredDuringSchedulingIgnoredDuringExecution) DeepCopyInto(out *RequiredDuringSchedulingIgnoredDuringExecution) {
 	*out = *in
@@ -2276,28 +3021,104 @@ func (in *RolloutCondition) DeepCopy() *RolloutCondition {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutExperimentStep) DeepCopyInto(out *RolloutExperimentStep) {
+func (in *RolloutControllerConfig) DeepCopyInto(out *RolloutControllerConfig) {
 	*out = *in
-	if in.Templates != nil {
-		in, out := &in.Templates, &out.Templates
-		*out = make([]RolloutExperimentTemplate, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutControllerConfig.
+func (in *RolloutControllerConfig) DeepCopy() *RolloutControllerConfig {
+	if in == nil {
+		return nil
 	}
-	if in.Analyses != nil {
-		in, out := &in.Analyses, &out.Analyses
-		*out = make([]RolloutExperimentStepAnalysisTemplateRef, len(*in))
+	out := new(RolloutControllerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RolloutControllerConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutControllerConfigList) DeepCopyInto(out *RolloutControllerConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RolloutControllerConfig, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.DryRun != nil {
-		in, out := &in.DryRun, &out.DryRun
-		*out = make([]DryRun, len(*in))
-		copy(*out, *in)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutControllerConfigList.
+func (in *RolloutControllerConfigList) DeepCopy() *RolloutControllerConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutControllerConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RolloutControllerConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutControllerConfigSpec) DeepCopyInto(out *RolloutControllerConfigSpec) {
+	*out = *in
+	if in.Defaults != nil {
+		in, out := &in.Defaults, &out.Defaults
+		*out = new(RolloutDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Restrictions != nil {
+		in, out := &in.Restrictions, &out.Restrictions
+		*out = new(RolloutRestrictions)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutControllerConfigSpec.
+func (in *RolloutControllerConfigSpec) DeepCopy() *RolloutControllerConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutControllerConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutDefaults) DeepCopyInto(out *RolloutDefaults) {
+	*out = *in
+	if in.ProgressDeadlineSeconds != nil {
+		in, out := &in.ProgressDeadlineSeconds, &out.ProgressDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
 	}
-	in.AnalysisRunMetadata.DeepCopyInto(&out.AnalysisRunMetadata)
 	if in.ScaleDownDelaySeconds != nil {
 		in, out := &in.ScaleDownDelaySeconds, &out.ScaleDownDelaySeconds
 		*out = new(int32)
@@ -2306,131 +3127,507 @@ func (in *RolloutExperimentStep) DeepCopyInto(out *RolloutExperimentStep) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutExperimentStep.
-func (in *RolloutExperimentStep) DeepCopy() *RolloutExperimentStep {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutDefaults.
+func (in *RolloutDefaults) DeepCopy() *RolloutDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutExperimentStep) DeepCopyInto(out *RolloutExperimentStep) {
+	*out = *in
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make([]RolloutExperimentTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Analyses != nil {
+		in, out := &in.Analyses, &out.Analyses
+		*out = make([]RolloutExperimentStepAnalysisTemplateRef, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DryRun != nil {
+		in, out := &in.DryRun, &out.DryRun
+		*out = make([]DryRun, len(*in))
+		copy(*out, *in)
+	}
+	in.AnalysisRunMetadata.DeepCopyInto(&out.AnalysisRunMetadata)
+	if in.ScaleDownDelaySeconds != nil {
+		in, out := &in.ScaleDownDelaySeconds, &out.ScaleDownDelaySeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.WinnerSelection != nil {
+		in, out := &in.WinnerSelection, &out.WinnerSelection
+		*out = new(ExperimentWinnerSelection)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutExperimentStep.
+func (in *RolloutExperimentStep) DeepCopy() *RolloutExperimentStep {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutExperimentStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutExperimentStepAnalysisTemplateRef) DeepCopyInto(out *RolloutExperimentStepAnalysisTemplateRef) {
+	*out = *in
+	if in.ClusterScope != nil {
+		in, out := &in.ClusterScope, &out.ClusterScope
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]AnalysisRunArgument, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutExperimentStepAnalysisTemplateRef.
+func (in *RolloutExperimentStepAnalysisTemplateRef) DeepCopy() *RolloutExperimentStepAnalysisTemplateRef {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutExperimentStepAnalysisTemplateRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutExperimentTemplate) DeepCopyInto(out *RolloutExperimentTemplate) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	in.Metadata.DeepCopyInto(&out.Metadata)
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(TemplateService)
+		**out = **in
+	}
+	if in.HeaderRoute != nil {
+		in, out := &in.HeaderRoute, &out.HeaderRoute
+		*out = new(SetHeaderRoute)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(TemplateAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutExperimentTemplate.
+func (in *RolloutExperimentTemplate) DeepCopy() *RolloutExperimentTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutExperimentTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutGateStep) DeepCopyInto(out *RolloutGateStep) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]WebMetricHeader, len(*in))
+		copy(*out, *in)
+	}
+	if in.AuthorizationSecretRef != nil {
+		in, out := &in.AuthorizationSecretRef, &out.AuthorizationSecretRef
+		*out = new(SecretKeyRef)
+		**out = **in
+	}
+	if in.Params != nil {
+		in, out := &in.Params, &out.Params
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Retries != nil {
+		in, out := &in.Retries, &out.Retries
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutGateStep.
+func (in *RolloutGateStep) DeepCopy() *RolloutGateStep {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutGateStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutGuardrail) DeepCopyInto(out *RolloutGuardrail) {
+	*out = *in
+	in.RolloutAnalysis.DeepCopyInto(&out.RolloutAnalysis)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutGuardrail.
+func (in *RolloutGuardrail) DeepCopy() *RolloutGuardrail {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutGuardrail)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutHealth) DeepCopyInto(out *RolloutHealth) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutHealth.
+func (in *RolloutHealth) DeepCopy() *RolloutHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutList) DeepCopyInto(out *RolloutList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Rollout, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutList.
+func (in *RolloutList) DeepCopy() *RolloutList {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RolloutList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutLoadTestStep) DeepCopyInto(out *RolloutLoadTestStep) {
+	*out = *in
+	if in.JobSpec != nil {
+		in, out := &in.JobSpec, &out.JobSpec
+		*out = new(batchv1.JobSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.K6 != nil {
+		in, out := &in.K6, &out.K6
+		*out = new(K6LoadTest)
+		**out = **in
+	}
+	if in.Vegeta != nil {
+		in, out := &in.Vegeta, &out.Vegeta
+		*out = new(VegetaLoadTest)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutLoadTestStep.
+func (in *RolloutLoadTestStep) DeepCopy() *RolloutLoadTestStep {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutLoadTestStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutMigrationStep) DeepCopyInto(out *RolloutMigrationStep) {
+	*out = *in
+	if in.JobSpec != nil {
+		in, out := &in.JobSpec, &out.JobSpec
+		*out = new(batchv1.JobSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StatusCheck != nil {
+		in, out := &in.StatusCheck, &out.StatusCheck
+		*out = new(MigrationStatusCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RollbackJobSpec != nil {
+		in, out := &in.RollbackJobSpec, &out.RollbackJobSpec
+		*out = new(batchv1.JobSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutMigrationStep.
+func (in *RolloutMigrationStep) DeepCopy() *RolloutMigrationStep {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutMigrationStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutNotificationPolicy) DeepCopyInto(out *RolloutNotificationPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutNotificationPolicy.
+func (in *RolloutNotificationPolicy) DeepCopy() *RolloutNotificationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutNotificationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RolloutNotificationPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutNotificationPolicyList) DeepCopyInto(out *RolloutNotificationPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RolloutNotificationPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutNotificationPolicyList.
+func (in *RolloutNotificationPolicyList) DeepCopy() *RolloutNotificationPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutNotificationPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RolloutNotificationPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutNotificationPolicySpec) DeepCopyInto(out *RolloutNotificationPolicySpec) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Subscriptions != nil {
+		in, out := &in.Subscriptions, &out.Subscriptions
+		*out = make([]NotificationSubscription, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutNotificationPolicySpec.
+func (in *RolloutNotificationPolicySpec) DeepCopy() *RolloutNotificationPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutNotificationPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutPause) DeepCopyInto(out *RolloutPause) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutPause.
+func (in *RolloutPause) DeepCopy() *RolloutPause {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutExperimentStep)
+	out := new(RolloutPause)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutExperimentStepAnalysisTemplateRef) DeepCopyInto(out *RolloutExperimentStepAnalysisTemplateRef) {
+func (in *RolloutPreProvisionStep) DeepCopyInto(out *RolloutPreProvisionStep) {
 	*out = *in
-	if in.ClusterScope != nil {
-		in, out := &in.ClusterScope, &out.ClusterScope
-		*out = new(bool)
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int32)
 		**out = **in
 	}
-	if in.Args != nil {
-		in, out := &in.Args, &out.Args
-		*out = make([]AnalysisRunArgument, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutExperimentStepAnalysisTemplateRef.
-func (in *RolloutExperimentStepAnalysisTemplateRef) DeepCopy() *RolloutExperimentStepAnalysisTemplateRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutPreProvisionStep.
+func (in *RolloutPreProvisionStep) DeepCopy() *RolloutPreProvisionStep {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutExperimentStepAnalysisTemplateRef)
+	out := new(RolloutPreProvisionStep)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutExperimentTemplate) DeepCopyInto(out *RolloutExperimentTemplate) {
+func (in *RolloutRestartStatus) DeepCopyInto(out *RolloutRestartStatus) {
 	*out = *in
-	if in.Replicas != nil {
-		in, out := &in.Replicas, &out.Replicas
-		*out = new(int32)
-		**out = **in
-	}
-	in.Metadata.DeepCopyInto(&out.Metadata)
-	if in.Selector != nil {
-		in, out := &in.Selector, &out.Selector
-		*out = new(v1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Weight != nil {
-		in, out := &in.Weight, &out.Weight
-		*out = new(int32)
-		**out = **in
+	in.RestartAt.DeepCopyInto(&out.RestartAt)
+	if in.BatchCompletedAt != nil {
+		in, out := &in.BatchCompletedAt, &out.BatchCompletedAt
+		*out = (*in).DeepCopy()
 	}
-	if in.Service != nil {
-		in, out := &in.Service, &out.Service
-		*out = new(TemplateService)
+	if in.CurrentAnalysisRunStatus != nil {
+		in, out := &in.CurrentAnalysisRunStatus, &out.CurrentAnalysisRunStatus
+		*out = new(RolloutAnalysisRunStatus)
 		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutExperimentTemplate.
-func (in *RolloutExperimentTemplate) DeepCopy() *RolloutExperimentTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutRestartStatus.
+func (in *RolloutRestartStatus) DeepCopy() *RolloutRestartStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutExperimentTemplate)
+	out := new(RolloutRestartStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutList) DeepCopyInto(out *RolloutList) {
+func (in *RolloutRestartStrategy) DeepCopyInto(out *RolloutRestartStrategy) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Rollout, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.BatchSize != nil {
+		in, out := &in.BatchSize, &out.BatchSize
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.Analysis != nil {
+		in, out := &in.Analysis, &out.Analysis
+		*out = new(RolloutAnalysis)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutList.
-func (in *RolloutList) DeepCopy() *RolloutList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutRestartStrategy.
+func (in *RolloutRestartStrategy) DeepCopy() *RolloutRestartStrategy {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutList)
+	out := new(RolloutRestartStrategy)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RolloutList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutPause) DeepCopyInto(out *RolloutPause) {
+func (in *RolloutRestrictions) DeepCopyInto(out *RolloutRestrictions) {
 	*out = *in
-	if in.Duration != nil {
-		in, out := &in.Duration, &out.Duration
-		*out = new(intstr.IntOrString)
+	if in.AllowedTrafficRouters != nil {
+		in, out := &in.AllowedTrafficRouters, &out.AllowedTrafficRouters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxCanaryWeightJump != nil {
+		in, out := &in.MaxCanaryWeightJump, &out.MaxCanaryWeightJump
+		*out = new(int32)
 		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutPause.
-func (in *RolloutPause) DeepCopy() *RolloutPause {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutRestrictions.
+func (in *RolloutRestrictions) DeepCopy() *RolloutRestrictions {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutPause)
+	out := new(RolloutRestrictions)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -2474,11 +3671,21 @@ func (in *RolloutSpec) DeepCopyInto(out *RolloutSpec) {
 		in, out := &in.RestartAt, &out.RestartAt
 		*out = (*in).DeepCopy()
 	}
+	if in.RestartStrategy != nil {
+		in, out := &in.RestartStrategy, &out.RestartStrategy
+		*out = new(RolloutRestartStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Analysis != nil {
 		in, out := &in.Analysis, &out.Analysis
 		*out = new(AnalysisRunStrategy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ImageVerification != nil {
+		in, out := &in.ImageVerification, &out.ImageVerification
+		*out = new(ImageVerification)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -2539,6 +3746,21 @@ func (in *RolloutStatus) DeepCopyInto(out *RolloutStatus) {
 		*out = make([]ALBStatus, len(*in))
 		copy(*out, *in)
 	}
+	if in.RestartStatus != nil {
+		in, out := &in.RestartStatus, &out.RestartStatus
+		*out = new(RolloutRestartStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Health != nil {
+		in, out := &in.Health, &out.Health
+		*out = new(RolloutHealth)
+		**out = **in
+	}
+	if in.LastUserAction != nil {
+		in, out := &in.LastUserAction, &out.LastUserAction
+		*out = new(RolloutUserAction)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -2628,14 +3850,14 @@ func (in *RolloutTrafficRouting) DeepCopyInto(out *RolloutTrafficRouting) {
 	}
 	if in.Plugins != nil {
 		in, out := &in.Plugins, &out.Plugins
-		*out = make(map[string]json.RawMessage, len(*in))
+		*out = make(map[string]jsontext.Value, len(*in))
 		for key, val := range *in {
 			var outVal []byte
 			if val == nil {
 				(*out)[key] = nil
 			} else {
 				in, out := &val, &outVal
-				*out = make(json.RawMessage, len(*in))
+				*out = make(jsontext.Value, len(*in))
 				copy(*out, *in)
 			}
 			(*out)[key] = outVal
@@ -2646,6 +3868,11 @@ func (in *RolloutTrafficRouting) DeepCopyInto(out *RolloutTrafficRouting) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Knative != nil {
+		in, out := &in.Knative, &out.Knative
+		*out = new(KnativeTrafficRouting)
+		**out = **in
+	}
 	return
 }
 
@@ -2659,6 +3886,28 @@ func (in *RolloutTrafficRouting) DeepCopy() *RolloutTrafficRouting {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutUserAction) DeepCopyInto(out *RolloutUserAction) {
+	*out = *in
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutUserAction.
+func (in *RolloutUserAction) DeepCopy() *RolloutUserAction {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutUserAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RouteMatch) DeepCopyInto(out *RouteMatch) {
 	*out = *in
@@ -2724,6 +3973,48 @@ func (in *SMITrafficRouting) DeepCopy() *SMITrafficRouting {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleDownDelayOverride) DeepCopyInto(out *ScaleDownDelayOverride) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleDownDelayOverride.
+func (in *ScaleDownDelayOverride) DeepCopy() *ScaleDownDelayOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaleDownDelayOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleDownVerification) DeepCopyInto(out *ScaleDownVerification) {
+	*out = *in
+	if in.DrainProbe != nil {
+		in, out := &in.DrainProbe, &out.DrainProbe
+		*out = new(DrainProbe)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleDownVerification.
+func (in *ScaleDownVerification) DeepCopy() *ScaleDownVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaleDownVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScopeDetail) DeepCopyInto(out *ScopeDetail) {
 	*out = *in
@@ -2798,6 +4089,37 @@ func (in *SetCanaryScale) DeepCopy() *SetCanaryScale {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SetFeatureFlag) DeepCopyInto(out *SetFeatureFlag) {
+	*out = *in
+	if in.Percentage != nil {
+		in, out := &in.Percentage, &out.Percentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LaunchDarkly != nil {
+		in, out := &in.LaunchDarkly, &out.LaunchDarkly
+		*out = new(LaunchDarklyFeatureFlag)
+		**out = **in
+	}
+	if in.OpenFeature != nil {
+		in, out := &in.OpenFeature, &out.OpenFeature
+		*out = new(OpenFeatureFeatureFlag)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SetFeatureFlag.
+func (in *SetFeatureFlag) DeepCopy() *SetFeatureFlag {
+	if in == nil {
+		return nil
+	}
+	out := new(SetFeatureFlag)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SetHeaderRoute) DeepCopyInto(out *SetHeaderRoute) {
 	*out = *in
@@ -2881,6 +4203,26 @@ func (in *SkyWalkingMetric) DeepCopy() *SkyWalkingMetric {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepPluginProgress) DeepCopyInto(out *StepPluginProgress) {
+	*out = *in
+	if in.UpdatedAt != nil {
+		in, out := &in.UpdatedAt, &out.UpdatedAt
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepPluginProgress.
+func (in *StepPluginProgress) DeepCopy() *StepPluginProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(StepPluginProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StepPluginStatus) DeepCopyInto(out *StepPluginStatus) {
 	*out = *in
@@ -2898,9 +4240,14 @@ func (in *StepPluginStatus) DeepCopyInto(out *StepPluginStatus) {
 	}
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
-		*out = make(json.RawMessage, len(*in))
+		*out = make(jsontext.Value, len(*in))
 		copy(*out, *in)
 	}
+	if in.Progress != nil {
+		in, out := &in.Progress, &out.Progress
+		*out = new(StepPluginProgress)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -2914,6 +4261,22 @@ func (in *StepPluginStatus) DeepCopy() *StepPluginStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepProgressDeadline) DeepCopyInto(out *StepProgressDeadline) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepProgressDeadline.
+func (in *StepProgressDeadline) DeepCopy() *StepProgressDeadline {
+	if in == nil {
+		return nil
+	}
+	out := new(StepProgressDeadline)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StickinessConfig) DeepCopyInto(out *StickinessConfig) {
 	*out = *in
@@ -3014,6 +4377,37 @@ func (in *TTLStrategy) DeepCopy() *TTLStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateAutoscaling) DeepCopyInto(out *TemplateAutoscaling) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetCPUUtilizationPercentage != nil {
+		in, out := &in.TargetCPUUtilizationPercentage, &out.TargetCPUUtilizationPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetMemoryUtilizationPercentage != nil {
+		in, out := &in.TargetMemoryUtilizationPercentage, &out.TargetMemoryUtilizationPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateAutoscaling.
+func (in *TemplateAutoscaling) DeepCopy() *TemplateAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TemplateService) DeepCopyInto(out *TemplateService) {
 	*out = *in
@@ -3049,6 +4443,16 @@ func (in *TemplateSpec) DeepCopyInto(out *TemplateSpec) {
 		*out = new(TemplateService)
 		**out = **in
 	}
+	if in.WorkloadRef != nil {
+		in, out := &in.WorkloadRef, &out.WorkloadRef
+		*out = new(ExperimentWorkloadRef)
+		**out = **in
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(TemplateAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -3103,6 +4507,28 @@ func (in *TraefikTrafficRouting) DeepCopy() *TraefikTrafficRouting {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficWeightRecord) DeepCopyInto(out *TrafficWeightRecord) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Verified != nil {
+		in, out := &in.Verified, &out.Verified
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficWeightRecord.
+func (in *TrafficWeightRecord) DeepCopy() *TrafficWeightRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficWeightRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TrafficWeights) DeepCopyInto(out *TrafficWeights) {
 	*out = *in
@@ -3157,6 +4583,22 @@ func (in *ValueFrom) DeepCopy() *ValueFrom {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VegetaLoadTest) DeepCopyInto(out *VegetaLoadTest) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VegetaLoadTest.
+func (in *VegetaLoadTest) DeepCopy() *VegetaLoadTest {
+	if in == nil {
+		return nil
+	}
+	out := new(VegetaLoadTest)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WavefrontMetric) DeepCopyInto(out *WavefrontMetric) {
 	*out = *in
@@ -3183,7 +4625,7 @@ func (in *WebMetric) DeepCopyInto(out *WebMetric) {
 	}
 	if in.JSONBody != nil {
 		in, out := &in.JSONBody, &out.JSONBody
-		*out = make(json.RawMessage, len(*in))
+		*out = make(jsontext.Value, len(*in))
 		copy(*out, *in)
 	}
 	in.Authentication.DeepCopyInto(&out.Authentication)
@@ -3231,3 +4673,19 @@ func (in *WeightDestination) DeepCopy() *WeightDestination {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WeightOverride) DeepCopyInto(out *WeightOverride) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WeightOverride.
+func (in *WeightOverride) DeepCopy() *WeightOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(WeightOverride)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -3,8 +3,12 @@ package validation
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -12,14 +16,17 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	validationutil "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/util/jsonpath"
 	"k8s.io/kubernetes/pkg/apis/apps/validation"
 	"k8s.io/kubernetes/pkg/apis/core"
 	corev1defaults "k8s.io/kubernetes/pkg/apis/core/v1"
 	apivalidation "k8s.io/kubernetes/pkg/apis/core/validation"
 	"k8s.io/kubernetes/pkg/fieldpath"
+	"sigs.k8s.io/yaml"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	"github.com/argoproj/argo-rollouts/utils/defaults"
+	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
 	"github.com/argoproj/argo-rollouts/utils/weightutil"
 )
 
@@ -35,7 +42,9 @@ const (
 	// InvalidSetCanaryScaleTrafficPolicy indicates that TrafficRouting, required for SetCanaryScale, is missing
 	InvalidSetCanaryScaleTrafficPolicy = "SetCanaryScale requires TrafficRouting to be set"
 	// InvalidSetHeaderRouteTrafficPolicy indicates that TrafficRouting required for SetHeaderRoute is missing
-	InvalidSetHeaderRouteTrafficPolicy = "SetHeaderRoute requires TrafficRouting, supports Istio and ALB and Apisix"
+	InvalidSetHeaderRouteTrafficPolicy = "SetHeaderRoute requires TrafficRouting, supports Istio and ALB and Apisix and SMI"
+	// InvalidSetHeaderRouteSMIVersionMessage indicates that the SMI TrafficSplits of the controller cannot match headers
+	InvalidSetHeaderRouteSMIVersionMessage = "SetHeaderRoute with SMI requires TrafficSplits of API version v1alpha3 or later, but the API version is %s"
 	// InvalidSetMirrorRouteTrafficPolicy indicates that TrafficRouting, required for SetMirrorRoute, is missing
 	InvalidSetMirrorRouteTrafficPolicy = "SetMirrorRoute requires TrafficRouting, supports Istio and Plugins"
 	// InvalidStringMatchMultipleValuePolicy indicates that SetCanaryScale, has multiple values set
@@ -60,6 +69,76 @@ const (
 	InvalidAntiAffinityStrategyMessage = "AntiAffinity must have exactly one strategy listed"
 	// InvalidAntiAffinityWeightMessage indicates that Anti-Affinity must have weight between 1-100
 	InvalidAntiAffinityWeightMessage = "AntiAffinity weight must be between 1-100"
+	// InvalidPodTemplateOverlayMessage indicates that the pod template overlay is not a valid strategic merge patch of the pod template
+	InvalidPodTemplateOverlayMessage = "patch must be a valid strategic merge patch of the pod template: %v"
+	// InvalidPodTemplateOverlayFieldsMessage indicates that the pod template overlay patches fields other than the spec
+	InvalidPodTemplateOverlayFieldsMessage = "patch can only patch the spec of the pod template"
+	// InvalidExperimentPromoteWinnerMessage indicates that an experiment step promotes its winner without selecting one
+	InvalidExperimentPromoteWinnerMessage = "experiment promoteWinner requires a winnerSelection"
+	// InvalidExperimentPromoteWinnerSpecRefMessage indicates that a winner candidate of an experiment step which promotes its winner is not a variant of the canary
+	InvalidExperimentPromoteWinnerSpecRefMessage = "experiment promoteWinner requires the winner candidates to reference the canary spec"
+	// InvalidExperimentPromoteWinnerWorkloadRefMessage indicates that an experiment step promotes its winner to a rollout which references a workload
+	InvalidExperimentPromoteWinnerWorkloadRefMessage = "experiment promoteWinner cannot update the pod template of a rollout which references a workload"
+	// InvalidExperimentTemplateHeaderRouteTrafficPolicy indicates that the traffic routing of the rollout cannot route headers to an experiment template
+	InvalidExperimentTemplateHeaderRouteTrafficPolicy = "Experiment template headerRoute is only available for TrafficRouting with Istio at this time"
+	// InvalidExperimentTemplateWeightsMessage indicates that the experiment templates and the canary of a step receive more than the max traffic weight
+	InvalidExperimentTemplateWeightsMessage = "Experiment template weights and the canary weight add up to %d, more than the maximum traffic weight of %d"
+	// InvalidLoadTestMessage indicates that a load test step does not define exactly one load test
+	InvalidLoadTestMessage = "loadTest must have exactly one of jobSpec, k6 or vegeta"
+	// InvalidLoadTestCanaryServiceMessage indicates that a load test step is used without a canary service
+	InvalidLoadTestCanaryServiceMessage = "loadTest requires a canaryService to send the load to"
+	// InvalidLoadTestJobMessage indicates that the Job of a load test has no containers
+	InvalidLoadTestJobMessage = "loadTest jobSpec must have at least one container"
+	// InvalidPreProvisionPriorityClassMessage indicates that a preProvision step has no priority class for its placeholder pods
+	InvalidPreProvisionPriorityClassMessage = "preProvision requires a priorityClassName with a lower priority than the canary pods, so that the placeholder pods are preempted by them"
+	// InvalidSetFeatureFlagProviderMessage indicates that a setFeatureFlag step does not define exactly one provider
+	InvalidSetFeatureFlagProviderMessage = "setFeatureFlag must have exactly one of launchDarkly or openFeature"
+	// InvalidSetFeatureFlagProviderChangeMessage indicates that the setFeatureFlag steps of a flag use different providers
+	InvalidSetFeatureFlagProviderChangeMessage = "all setFeatureFlag steps of a flag must use the same provider"
+	// InvalidMigrationMessage indicates that a migration step does not define exactly one of a Job and a status check
+	InvalidMigrationMessage = "migration must have exactly one of jobSpec or statusCheck"
+	// InvalidMigrationJobMessage indicates that the Job of a migration step has no containers
+	InvalidMigrationJobMessage = "migration Job must have at least one container"
+	// InvalidGateFailurePolicyMessage indicates that the failure policy of a gate step is unknown
+	InvalidGateFailurePolicyMessage = "gate failurePolicy must be Abort or Pause"
+	// InvalidGateTimeoutMessage indicates that the timeout of a gate step exceeds MaxGateTimeout
+	InvalidGateTimeoutMessage = "gate timeout must be at most 60s"
+	// InvalidSetFeatureFlagPercentageMessage indicates that the percentage of a setFeatureFlag step is out of range
+	InvalidSetFeatureFlagPercentageMessage = "setFeatureFlag percentage must be between 0 and 100"
+	// InvalidImageVerificationMessage indicates that an image verification trusts neither keys nor identities
+	InvalidImageVerificationMessage = "imageVerification requires at least one of publicKeys and identities"
+	// InvalidImageVerificationKeylessMessage indicates that the identities of an image verification lack the trust roots of keyless signatures
+	InvalidImageVerificationKeylessMessage = "imageVerification identities require certificateAuthorities and transparencyLogPublicKey"
+	// InvalidImageVerificationSubjectMessage indicates that an identity does not define exactly one of subject and subjectRegex
+	InvalidImageVerificationSubjectMessage = "identity must define exactly one of subject and subjectRegex"
+	// InvalidEphemeralMetadataEnvSourceMessage indicates that an ephemeral metadata env var must reference exactly one label or annotation
+	InvalidEphemeralMetadataEnvSourceMessage = "Ephemeral metadata env must reference exactly one label or annotation"
+	// InvalidEphemeralMetadataEnvNameMessage indicates that the ephemeral metadata env var names must be unique
+	InvalidEphemeralMetadataEnvNameMessage = "Ephemeral metadata env names must be unique"
+	// InvalidAntiAffinityTopologySpreadMaxSkewMessage indicates that the injected topology spread constraint must have a positive maxSkew
+	InvalidAntiAffinityTopologySpreadMaxSkewMessage = "AntiAffinity topologySpread maxSkew must be greater than 0"
+	// InvalidAntiAffinityTopologySpreadKeyMessage indicates that the topology keys of the injected topology spread constraints must be unique and not empty
+	InvalidAntiAffinityTopologySpreadKeyMessage = "AntiAffinity topologySpread topologyKeys must be unique and not empty"
+	// InvalidRestartBatchSizeMessage indicates that the restart batch size is not positive
+	InvalidRestartBatchSizeMessage = "restartStrategy batchSize must be greater than 0"
+	// InvalidRestartPauseBetweenBatchesMessage indicates that the pause between restart batches is not a valid duration
+	InvalidRestartPauseBetweenBatchesMessage = "restartStrategy pauseBetweenBatches must be a valid duration: %v"
+	// InvalidPreviewReplicaProfileMessage indicates that previewReplicaCount and previewReplicaProfile cannot both be set
+	InvalidPreviewReplicaProfileMessage = "previewReplicaProfile cannot be used with previewReplicaCount"
+	// InvalidPreviewReplicaPercentMessage indicates that previewReplicaProfile.percent must be between 0-100
+	InvalidPreviewReplicaPercentMessage = "previewReplicaProfile percent must be between 0-100"
+	// InvalidPreviewReplicaBoundsMessage indicates that previewReplicaProfile.minReplicas is larger than maxReplicas
+	InvalidPreviewReplicaBoundsMessage = "previewReplicaProfile minReplicas cannot be larger than maxReplicas"
+	// InvalidPostPromotionSmokeTestMessage indicates that postPromotionSmokeTest has neither a job nor templates
+	InvalidPostPromotionSmokeTestMessage = "postPromotionSmokeTest requires a job or templates"
+	// InvalidPostPromotionSmokeTestWindowMessage indicates that postPromotionSmokeTest.windowSeconds is not positive
+	InvalidPostPromotionSmokeTestWindowMessage = "postPromotionSmokeTest windowSeconds must be greater than 0"
+	// InvalidPreviewRoutingServiceMessage indicates that previewRouting requires a previewService to route to
+	InvalidPreviewRoutingServiceMessage = "previewRouting requires previewService"
+	// InvalidPreviewRoutingProviderMessage indicates that previewRouting requires exactly one of istio or gatewayAPI
+	InvalidPreviewRoutingProviderMessage = "previewRouting requires exactly one of istio or gatewayAPI"
+	// InvalidPreviewRoutingMatchMessage indicates that previewRouting requires at least one header match
+	InvalidPreviewRoutingMatchMessage = "previewRouting requires at least one header match"
 	// ScaleDownLimitLargerThanRevisionLimit the message to indicate that the rollout's revision history limit can not be smaller than the rollout's scale down limit
 	ScaleDownLimitLargerThanRevisionLimit = "This rollout's revision history limit can not be smaller than the rollout's scale down limit"
 	// InvalidTrafficRoutingMessage indicates that both canary and stable service must be set to use Traffic Routing
@@ -69,10 +148,50 @@ const (
 	InvalidAnalysisArgsMessage = "Analyses arguments must refer to valid object metadata supported by downwardAPI"
 	// InvalidCanaryScaleDownDelay indicates that canary.scaleDownDelaySeconds cannot be used
 	InvalidCanaryScaleDownDelay = "Canary scaleDownDelaySeconds can only be used with traffic routing"
+	// InvalidCanaryScaleDownDelayOverrides indicates that canary.scaleDownDelayOverrides cannot be used
+	InvalidCanaryScaleDownDelayOverrides = "Canary scaleDownDelayOverrides can only be used with traffic routing"
+	// InvalidGuardrailMessage indicates that the guardrail does not reference any analysis templates
+	InvalidGuardrailMessage = "Canary guardrail requires at least one analysis template"
+	// InvalidGuardrailActionMessage indicates that the guardrail action is unknown
+	InvalidGuardrailActionMessage = "Canary guardrail action must be one of: Abort, Pause"
+	// InvalidCreateServicesMessage indicates that canary.createServices is set without a canary or stable service
+	InvalidCreateServicesMessage = "Canary createServices requires a canaryService or stableService"
+	// InvalidCreateServicesSelectorMessage indicates that the services cannot be created from the rollout selector
+	InvalidCreateServicesSelectorMessage = "Canary createServices requires the rollout selector to use matchLabels"
+	// InvalidCreateServicesPortsMessage indicates that the pod template does not declare any container ports
+	InvalidCreateServicesPortsMessage = "Canary createServices requires at least one container port in the pod template"
+	// InvalidStepProgressDeadlineSecondsMessage indicates that a step progress deadline is not positive
+	InvalidStepProgressDeadlineSecondsMessage = "Step progressDeadline seconds must be greater than 0"
+	// InvalidStepProgressDeadlineActionMessage indicates that a step progress deadline action is unknown
+	InvalidStepProgressDeadlineActionMessage = "Step progressDeadline action must be one of: Abort, Pause"
+	// InvalidStepProgressDeadlinePauseStepMessage indicates that a step progress deadline is set on a pause step
+	InvalidStepProgressDeadlinePauseStepMessage = "Step progressDeadline cannot be used with a pause step"
+	// InvalidScaleDownDelayOverrideRevisionMessage indicates that a scaleDownDelayOverrides revision is not positive
+	InvalidScaleDownDelayOverrideRevisionMessage = "scaleDownDelayOverrides revision must be greater than 0"
+	// InvalidScaleDownDelayOverrideDelayMessage indicates that a scaleDownDelayOverrides delay is negative
+	InvalidScaleDownDelayOverrideDelayMessage = "scaleDownDelayOverrides delaySeconds must be greater than or equal to 0"
+	// DuplicatedScaleDownDelayOverrideMessage indicates that a revision is listed more than once in scaleDownDelayOverrides
+	DuplicatedScaleDownDelayOverrideMessage = "scaleDownDelayOverrides revision must be unique"
 	// InvalidCanaryDynamicStableScale indicates that canary.dynamicStableScale cannot be used
 	InvalidCanaryDynamicStableScale = "Canary dynamicStableScale can only be used with traffic routing"
 	// InvalidCanaryDynamicStableScaleWithScaleDownDelay indicates that canary.dynamicStableScale cannot be used with scaleDownDelaySeconds
 	InvalidCanaryDynamicStableScaleWithScaleDownDelay = "Canary dynamicStableScale cannot be used with scaleDownDelaySeconds"
+	// InvalidCanaryScaleDownVerification indicates that canary.scaleDownVerification cannot be used without traffic routing
+	InvalidCanaryScaleDownVerification = "Canary scaleDownVerification can only be used with traffic routing"
+	// InvalidCanaryScaleDownVerificationWithDynamicStableScale indicates that canary.scaleDownVerification cannot be used with dynamicStableScale
+	InvalidCanaryScaleDownVerificationWithDynamicStableScale = "Canary scaleDownVerification cannot be used with dynamicStableScale"
+	// InvalidScaleDownVerificationTimeoutMessage indicates that the scale down verification timeout is negative
+	InvalidScaleDownVerificationTimeoutMessage = "scaleDownVerification timeoutSeconds must be greater than or equal to 0"
+	// InvalidDrainProbePortMessage indicates that the drain probe port is not a valid port number
+	InvalidDrainProbePortMessage = "drainProbe port must be between 1-65535"
+	// InvalidDrainProbeSchemeMessage indicates that the drain probe scheme is unknown
+	InvalidDrainProbeSchemeMessage = "drainProbe scheme must be one of: HTTP, HTTPS"
+	// InvalidKEDACoordinationModeMessage indicates that the mode of the KEDA coordination is unknown
+	InvalidKEDACoordinationModeMessage = "keda mode must be Pause"
+	// InvalidPodDisruptionBudgetMessage indicates that both minAvailable and maxUnavailable of the PodDisruptionBudget are set
+	InvalidPodDisruptionBudgetMessage = "podDisruptionBudget minAvailable and maxUnavailable cannot both be set"
+	// InvalidPodDisruptionBudgetValueMessage indicates that minAvailable or maxUnavailable of the PodDisruptionBudget is invalid
+	InvalidPodDisruptionBudgetValueMessage = "podDisruptionBudget value must be a non-negative integer or a percentage"
 	// InvalidCanaryMaxWeightOnlySupportInNginxAndPlugins indicates that canary.maxTrafficWeight cannot be used
 	InvalidCanaryMaxWeightOnlySupportInNginxAndPlugins = "Canary maxTrafficWeight in traffic routing only supported in Nginx and Plugins"
 	// InvalidPingPongProvidedMessage indicates that both ping and pong service must be set to use Ping-Pong feature
@@ -86,6 +205,21 @@ const (
 	// InvalideStepRouteNameNotFoundInManagedRoutes A step has been configured that requires managedRoutes and the route name
 	// is missing from managedRoutes
 	InvalideStepRouteNameNotFoundInManagedRoutes = "Steps define a route that does not exist in spec.strategy.canary.trafficRouting.managedRoutes"
+	// InvalidKnativeServiceMessage indicates that the Knative traffic routing does not name the Knative Service
+	InvalidKnativeServiceMessage = "Knative traffic routing requires the name of the Knative Service"
+	// InvalidKnativeReplicasMessage indicates that the ReplicaSets of a rollout with Knative traffic routing run pods,
+	// whereas the revisions of the Knative Service are the workload of the rollout
+	InvalidKnativeReplicasMessage = "Knative traffic routing requires replicas to be 0, since the revisions of the Knative Service run the pod template"
+	// InvalidKnativeDynamicStableScaleMessage indicates that dynamic stable scale is used with Knative traffic routing
+	InvalidKnativeDynamicStableScaleMessage = "Knative traffic routing does not support dynamicStableScale, since the Knative Service scales the revisions"
+	// ForbiddenTrafficRouterMessage indicates that a traffic router is not allowed in the namespace of the rollout
+	ForbiddenTrafficRouterMessage = "Traffic router %s is not allowed by RolloutControllerConfig %s"
+	// ForbiddenCanaryWeightJumpMessage indicates that a canary step increases the weight by more than is allowed in the
+	// namespace of the rollout
+	ForbiddenCanaryWeightJumpMessage = "Canary weight increases by %d, more than the maximum of %d allowed by RolloutControllerConfig %s"
+	// MissingRequiredAnalysisMessage indicates that the rollout does not run an analysis which is required in its
+	// namespace
+	MissingRequiredAnalysisMessage = "Rollout must run an analysis as required by RolloutControllerConfig %s"
 )
 
 // allowAllPodValidationOptions allows all pod options to be true for the purposes of rollout pod
@@ -176,7 +310,62 @@ func ValidateRolloutSpec(rollout *v1alpha1.Rollout, fldPath *field.Path) field.E
 	}
 
 	allErrs = append(allErrs, ValidateRolloutStrategy(rollout, fldPath.Child("strategy"))...)
+	if spec.RestartStrategy != nil {
+		allErrs = append(allErrs, ValidateRestartStrategy(spec.RestartStrategy, fldPath.Child("restartStrategy"))...)
+	}
+	if spec.ImageVerification != nil {
+		allErrs = append(allErrs, ValidateImageVerification(spec.ImageVerification, fldPath.Child("imageVerification"))...)
+	}
+
+	return allErrs
+}
 
+// ValidateImageVerification checks that the image verification trusts keys or identities, and that keyless signatures
+// of the identities can be verified
+func ValidateImageVerification(verification *v1alpha1.ImageVerification, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(verification.PublicKeys) == 0 && len(verification.Identities) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath, InvalidImageVerificationMessage))
+	}
+	for i, key := range verification.PublicKeys {
+		if key.Name == "" || key.Key == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("publicKeys").Index(i), "secret name and key are required"))
+		}
+	}
+	if len(verification.Identities) > 0 && (verification.CertificateAuthorities == nil || verification.TransparencyLogPublicKey == nil) {
+		allErrs = append(allErrs, field.Required(fldPath, InvalidImageVerificationKeylessMessage))
+	}
+	for i, identity := range verification.Identities {
+		identityFldPath := fldPath.Child("identities").Index(i)
+		if identity.Issuer == "" {
+			allErrs = append(allErrs, field.Required(identityFldPath.Child("issuer"), "issuer is required"))
+		}
+		if (identity.Subject == "") == (identity.SubjectRegex == "") {
+			allErrs = append(allErrs, field.Invalid(identityFldPath, identity.Subject+identity.SubjectRegex, InvalidImageVerificationSubjectMessage))
+		} else if identity.SubjectRegex != "" {
+			if _, err := regexp.Compile(identity.SubjectRegex); err != nil {
+				allErrs = append(allErrs, field.Invalid(identityFldPath.Child("subjectRegex"), identity.SubjectRegex, err.Error()))
+			}
+		}
+	}
+	return allErrs
+}
+
+// ValidateRestartStrategy checks that the batch size and pause of the restart strategy are valid
+func ValidateRestartStrategy(restartStrategy *v1alpha1.RolloutRestartStrategy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if restartStrategy.BatchSize != nil {
+		batchSize := *restartStrategy.BatchSize
+		_, isPercent := getPercentValue(batchSize)
+		if batchSize.Type == intstr.String && !isPercent || getIntOrPercentValue(batchSize) <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("batchSize"), batchSize.String(), InvalidRestartBatchSizeMessage))
+		}
+	}
+	if restartStrategy.PauseBetweenBatches != "" {
+		if _, err := restartStrategy.PauseBetweenBatches.Duration(); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("pauseBetweenBatches"), restartStrategy.PauseBetweenBatches, fmt.Sprintf(InvalidRestartPauseBetweenBatchesMessage, err)))
+		}
+	}
 	return allErrs
 }
 
@@ -225,6 +414,85 @@ func ValidateRolloutStrategyBlueGreen(rollout *v1alpha1.Rollout, fldPath *field.
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("scaleDownDelayRevisionLimit"), *blueGreen.ScaleDownDelayRevisionLimit, ScaleDownLimitLargerThanRevisionLimit))
 	}
 	allErrs = append(allErrs, ValidateRolloutStrategyAntiAffinity(blueGreen.AntiAffinity, fldPath.Child("antiAffinity"))...)
+	allErrs = append(allErrs, ValidateEphemeralMetadataEnv(blueGreen.PreviewMetadata, fldPath.Child("previewMetadata"))...)
+	allErrs = append(allErrs, ValidateEphemeralMetadataEnv(blueGreen.ActiveMetadata, fldPath.Child("activeMetadata"))...)
+	allErrs = append(allErrs, ValidatePreviewReplicaProfile(blueGreen, fldPath.Child("previewReplicaProfile"))...)
+	allErrs = append(allErrs, ValidatePreviewRouting(blueGreen, fldPath.Child("previewRouting"))...)
+	allErrs = append(allErrs, ValidateScaleDownDelayOverrides(blueGreen.ScaleDownDelayOverrides, fldPath.Child("scaleDownDelayOverrides"))...)
+	allErrs = append(allErrs, ValidatePostPromotionSmokeTest(blueGreen.PostPromotionSmokeTest, fldPath.Child("postPromotionSmokeTest"))...)
+	return allErrs
+}
+
+func ValidatePostPromotionSmokeTest(smokeTest *v1alpha1.PostPromotionSmokeTest, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if smokeTest == nil {
+		return allErrs
+	}
+	if smokeTest.Job == nil && len(smokeTest.Templates) == 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath, smokeTest, InvalidPostPromotionSmokeTestMessage))
+	}
+	if smokeTest.WindowSeconds != nil && *smokeTest.WindowSeconds <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("windowSeconds"), *smokeTest.WindowSeconds, InvalidPostPromotionSmokeTestWindowMessage))
+	}
+	return allErrs
+}
+
+func ValidateScaleDownDelayOverrides(overrides []v1alpha1.ScaleDownDelayOverride, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	revisions := map[int32]bool{}
+	for i, override := range overrides {
+		if override.Revision <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("revision"), override.Revision, InvalidScaleDownDelayOverrideRevisionMessage))
+		} else if revisions[override.Revision] {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("revision"), override.Revision, DuplicatedScaleDownDelayOverrideMessage))
+		}
+		revisions[override.Revision] = true
+		if override.DelaySeconds < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("delaySeconds"), override.DelaySeconds, InvalidScaleDownDelayOverrideDelayMessage))
+		}
+	}
+	return allErrs
+}
+
+func ValidatePreviewReplicaProfile(blueGreen *v1alpha1.BlueGreenStrategy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	profile := blueGreen.PreviewReplicaProfile
+	if profile == nil {
+		return allErrs
+	}
+	if blueGreen.PreviewReplicaCount != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath, profile, InvalidPreviewReplicaProfileMessage))
+	}
+	if profile.Percent < 0 || profile.Percent > 100 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("percent"), profile.Percent, InvalidPreviewReplicaPercentMessage))
+	}
+	if profile.MinReplicas != nil && profile.MaxReplicas != nil && *profile.MinReplicas > *profile.MaxReplicas {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("minReplicas"), *profile.MinReplicas, InvalidPreviewReplicaBoundsMessage))
+	}
+	return allErrs
+}
+
+func ValidatePreviewRouting(blueGreen *v1alpha1.BlueGreenStrategy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	routing := blueGreen.PreviewRouting
+	if routing == nil {
+		return allErrs
+	}
+	if routing.Name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("name"), "previewRouting name is required"))
+	}
+	if blueGreen.PreviewService == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath, routing, InvalidPreviewRoutingServiceMessage))
+	}
+	if (routing.Istio == nil) == (routing.GatewayAPI == nil) {
+		allErrs = append(allErrs, field.Invalid(fldPath, routing, InvalidPreviewRoutingProviderMessage))
+	}
+	if len(routing.Match) == 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("match"), routing.Match, InvalidPreviewRoutingMatchMessage))
+	}
+	for i, match := range routing.Match {
+		allErrs = append(allErrs, hasMultipleMatchValues(match.HeaderValue, fldPath.Child("match").Index(i).Child("headerValue"))...)
+	}
 	return allErrs
 }
 
@@ -257,6 +525,188 @@ func requireCanaryStableServices(rollout *v1alpha1.Rollout) bool {
 	}
 }
 
+// ValidateStepProgressDeadline checks that the progress deadline of a canary step is valid
+func ValidateStepProgressDeadline(step v1alpha1.CanaryStep, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if step.Pause != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath, step.ProgressDeadline, InvalidStepProgressDeadlinePauseStepMessage))
+	}
+	if step.ProgressDeadline.Seconds <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("seconds"), step.ProgressDeadline.Seconds, InvalidStepProgressDeadlineSecondsMessage))
+	}
+	switch step.ProgressDeadline.Action {
+	case "", v1alpha1.StepProgressDeadlineActionAbort, v1alpha1.StepProgressDeadlineActionPause:
+	default:
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("action"), step.ProgressDeadline.Action, InvalidStepProgressDeadlineActionMessage))
+	}
+	return allErrs
+}
+
+// ValidateGuardrail checks that the guardrail analysis of a canary is valid
+func ValidateGuardrail(guardrail *v1alpha1.RolloutGuardrail, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(guardrail.Templates) == 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("templates"), guardrail.Templates, InvalidGuardrailMessage))
+	}
+	switch guardrail.Action {
+	case "", v1alpha1.GuardrailActionAbort, v1alpha1.GuardrailActionPause:
+	default:
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("action"), guardrail.Action, InvalidGuardrailActionMessage))
+	}
+	return allErrs
+}
+
+// ValidateReplicaSetPodDisruptionBudget checks that at most one of minAvailable and maxUnavailable is set, and that
+// it is a non-negative integer or a percentage
+func ValidateReplicaSetPodDisruptionBudget(pdb *v1alpha1.ReplicaSetPodDisruptionBudget, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if pdb.MinAvailable != nil && pdb.MaxUnavailable != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath, pdb, InvalidPodDisruptionBudgetMessage))
+	}
+	for name, value := range map[string]*intstr.IntOrString{"minAvailable": pdb.MinAvailable, "maxUnavailable": pdb.MaxUnavailable} {
+		if value == nil {
+			continue
+		}
+		_, isPercent := getPercentValue(*value)
+		if value.Type == intstr.String && !isPercent || getIntOrPercentValue(*value) < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child(name), value.String(), InvalidPodDisruptionBudgetValueMessage))
+		}
+	}
+	return allErrs
+}
+
+// ValidateRolloutRestrictions checks that the rollout complies with the restrictions of a RolloutControllerConfig of
+// its namespace
+func ValidateRolloutRestrictions(rollout *v1alpha1.Rollout, config *v1alpha1.RolloutControllerConfig) field.ErrorList {
+	allErrs := field.ErrorList{}
+	restrictions := config.Spec.Restrictions
+	if restrictions == nil {
+		return allErrs
+	}
+	strategy := rollout.Spec.Strategy
+	fldPath := field.NewPath("spec", "strategy")
+
+	if canary := strategy.Canary; canary != nil {
+		if len(restrictions.AllowedTrafficRouters) > 0 && canary.TrafficRouting != nil {
+			for _, router := range trafficRouters(canary.TrafficRouting) {
+				if !slices.Contains(restrictions.AllowedTrafficRouters, router) {
+					allErrs = append(allErrs, field.Forbidden(fldPath.Child("canary", "trafficRouting"), fmt.Sprintf(ForbiddenTrafficRouterMessage, router, config.Name)))
+				}
+			}
+		}
+		if restrictions.MaxCanaryWeightJump != nil {
+			maxJump := *restrictions.MaxCanaryWeightJump
+			weight := int32(0)
+			for i, step := range canary.Steps {
+				if step.SetWeight == nil {
+					continue
+				}
+				if jump := *step.SetWeight - weight; jump > maxJump {
+					allErrs = append(allErrs, field.Forbidden(fldPath.Child("canary", "steps").Index(i).Child("setWeight"), fmt.Sprintf(ForbiddenCanaryWeightJumpMessage, jump, maxJump, config.Name)))
+				}
+				weight = *step.SetWeight
+			}
+			// the canary is fully promoted after the last step
+			if jump := weightutil.MaxTrafficWeight(rollout) - weight; jump > maxJump {
+				allErrs = append(allErrs, field.Forbidden(fldPath.Child("canary", "steps"), fmt.Sprintf(ForbiddenCanaryWeightJumpMessage, jump, maxJump, config.Name)))
+			}
+		}
+	}
+
+	if restrictions.RequireAnalysis && !hasAnalysis(strategy) {
+		allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf(MissingRequiredAnalysisMessage, config.Name)))
+	}
+	return allErrs
+}
+
+// trafficRouters returns the names of the traffic routers of a canary, which are the names of the fields of the
+// traffic routing, and the names of the traffic router plugins
+func trafficRouters(routing *v1alpha1.RolloutTrafficRouting) []string {
+	routers := []string{}
+	for _, router := range []struct {
+		name string
+		set  bool
+	}{
+		{"istio", routing.Istio != nil},
+		{"nginx", routing.Nginx != nil},
+		{"alb", routing.ALB != nil},
+		{"smi", routing.SMI != nil},
+		{"ambassador", routing.Ambassador != nil},
+		{"appMesh", routing.AppMesh != nil},
+		{"traefik", routing.Traefik != nil},
+		{"apisix", routing.Apisix != nil},
+		{"knative", routing.Knative != nil},
+	} {
+		if router.set {
+			routers = append(routers, router.name)
+		}
+	}
+	return append(routers, slices.Sorted(maps.Keys(routing.Plugins))...)
+}
+
+// hasAnalysis returns whether a canary runs a background analysis or an analysis step, or a blue-green runs a
+// pre-promotion or post-promotion analysis
+func hasAnalysis(strategy v1alpha1.RolloutStrategy) bool {
+	if canary := strategy.Canary; canary != nil {
+		if canary.Analysis != nil {
+			return true
+		}
+		for _, step := range canary.Steps {
+			if step.Analysis != nil {
+				return true
+			}
+		}
+	}
+	if blueGreen := strategy.BlueGreen; blueGreen != nil {
+		return blueGreen.PrePromotionAnalysis != nil || blueGreen.PostPromotionAnalysis != nil
+	}
+	return false
+}
+
+// ValidateScaleDownVerification checks that the timeout and drain probe of the scale down verification are valid
+func ValidateScaleDownVerification(verification *v1alpha1.ScaleDownVerification, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if verification.TimeoutSeconds != nil && *verification.TimeoutSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("timeoutSeconds"), *verification.TimeoutSeconds, InvalidScaleDownVerificationTimeoutMessage))
+	}
+	if probe := verification.DrainProbe; probe != nil {
+		probeFldPath := fldPath.Child("drainProbe")
+		if probe.Port < 1 || probe.Port > 65535 {
+			allErrs = append(allErrs, field.Invalid(probeFldPath.Child("port"), probe.Port, InvalidDrainProbePortMessage))
+		}
+		switch probe.Scheme {
+		case "", corev1.URISchemeHTTP, corev1.URISchemeHTTPS:
+		default:
+			allErrs = append(allErrs, field.Invalid(probeFldPath.Child("scheme"), probe.Scheme, InvalidDrainProbeSchemeMessage))
+		}
+		allErrs = append(allErrs, apivalidation.ValidateNonnegativeField(int64(probe.TimeoutSeconds), probeFldPath.Child("timeoutSeconds"))...)
+	}
+	return allErrs
+}
+
+// ValidateCreateServices checks that the canary and stable services can be created for the rollout
+func ValidateCreateServices(rollout *v1alpha1.Rollout, fldPath *field.Path) field.ErrorList {
+	canary := rollout.Spec.Strategy.Canary
+	allErrs := field.ErrorList{}
+	if canary.CanaryService == "" && canary.StableService == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath, canary.CreateServices, InvalidCreateServicesMessage))
+	}
+	if rollout.Spec.Selector == nil || len(rollout.Spec.Selector.MatchLabels) == 0 || len(rollout.Spec.Selector.MatchExpressions) > 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath, canary.CreateServices, InvalidCreateServicesSelectorMessage))
+	}
+	hasPorts := false
+	for _, container := range rollout.Spec.Template.Spec.Containers {
+		if len(container.Ports) > 0 {
+			hasPorts = true
+			break
+		}
+	}
+	if !hasPorts {
+		allErrs = append(allErrs, field.Invalid(fldPath, canary.CreateServices, InvalidCreateServicesPortsMessage))
+	}
+	return allErrs
+}
+
 func ValidateRolloutStrategyCanary(rollout *v1alpha1.Rollout, fldPath *field.Path) field.ErrorList {
 	canary := rollout.Spec.Strategy.Canary
 	allErrs := field.ErrorList{}
@@ -264,6 +714,12 @@ func ValidateRolloutStrategyCanary(rollout *v1alpha1.Rollout, fldPath *field.Pat
 	if canary.CanaryService != "" && canary.StableService != "" && canary.CanaryService == canary.StableService {
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("stableService"), canary.StableService, DuplicatedServicesCanaryMessage))
 	}
+	if canary.Guardrail != nil {
+		allErrs = append(allErrs, ValidateGuardrail(canary.Guardrail, fldPath.Child("guardrail"))...)
+	}
+	if canary.CreateServices {
+		allErrs = append(allErrs, ValidateCreateServices(rollout, fldPath.Child("createServices"))...)
+	}
 	if canary.PingPong != nil {
 		if canary.TrafficRouting != nil && canary.TrafficRouting.ALB == nil && canary.TrafficRouting.Istio == nil && len(canary.TrafficRouting.Plugins) == 0 {
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("trafficRouting").Child("alb"), canary.TrafficRouting.ALB, PingPongWithRouterOnlyMessage))
@@ -281,6 +737,17 @@ func ValidateRolloutStrategyCanary(rollout *v1alpha1.Rollout, fldPath *field.Pat
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("trafficRouting").Child("alb").Child("rootService"), canary.TrafficRouting.ALB.RootService, MissedAlbRootServiceMessage))
 		}
 	}
+	if canary.TrafficRouting != nil && canary.TrafficRouting.Knative != nil {
+		if canary.TrafficRouting.Knative.Service == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("trafficRouting", "knative", "service"), canary.TrafficRouting.Knative.Service, InvalidKnativeServiceMessage))
+		}
+		if rollout.Spec.Replicas == nil || *rollout.Spec.Replicas != 0 {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "replicas"), rollout.Spec.Replicas, InvalidKnativeReplicasMessage))
+		}
+		if canary.DynamicStableScale {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("dynamicStableScale"), canary.DynamicStableScale, InvalidKnativeDynamicStableScaleMessage))
+		}
+	}
 	if requireCanaryStableServices(rollout) {
 		if canary.StableService == "" {
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("stableService"), canary.StableService, InvalidTrafficRoutingMessage))
@@ -290,17 +757,42 @@ func ValidateRolloutStrategyCanary(rollout *v1alpha1.Rollout, fldPath *field.Pat
 		}
 	}
 
+	if canary.KEDA != nil {
+		switch canary.KEDA.Mode {
+		case "", v1alpha1.KEDACoordinationModePause:
+		default:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("keda", "mode"), canary.KEDA.Mode, InvalidKEDACoordinationModeMessage))
+		}
+	}
+
+	if canary.PodDisruptionBudget != nil {
+		allErrs = append(allErrs, ValidateReplicaSetPodDisruptionBudget(canary.PodDisruptionBudget, fldPath.Child("podDisruptionBudget"))...)
+	}
+
 	if canary.TrafficRouting == nil {
 		if canary.ScaleDownDelaySeconds != nil {
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("scaleDownDelaySeconds"), *canary.ScaleDownDelaySeconds, InvalidCanaryScaleDownDelay))
 		}
+		if len(canary.ScaleDownDelayOverrides) > 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("scaleDownDelayOverrides"), canary.ScaleDownDelayOverrides, InvalidCanaryScaleDownDelayOverrides))
+		}
 		if canary.DynamicStableScale {
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("dynamicStableScale"), canary.DynamicStableScale, InvalidCanaryDynamicStableScale))
 		}
+		if canary.ScaleDownVerification != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("scaleDownVerification"), canary.ScaleDownVerification, InvalidCanaryScaleDownVerification))
+		}
 	} else {
+		if canary.ScaleDownVerification != nil {
+			if canary.DynamicStableScale {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("scaleDownVerification"), canary.ScaleDownVerification, InvalidCanaryScaleDownVerificationWithDynamicStableScale))
+			}
+			allErrs = append(allErrs, ValidateScaleDownVerification(canary.ScaleDownVerification, fldPath.Child("scaleDownVerification"))...)
+		}
 		if canary.ScaleDownDelaySeconds != nil && canary.DynamicStableScale {
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("dynamicStableScale"), canary.DynamicStableScale, InvalidCanaryDynamicStableScaleWithScaleDownDelay))
 		}
+		allErrs = append(allErrs, ValidateScaleDownDelayOverrides(canary.ScaleDownDelayOverrides, fldPath.Child("scaleDownDelayOverrides"))...)
 		// only the nginx and plugin have this support for now
 		if canary.TrafficRouting.MaxTrafficWeight != nil {
 			if canary.TrafficRouting.Nginx == nil && len(canary.TrafficRouting.Plugins) == 0 {
@@ -309,18 +801,48 @@ func ValidateRolloutStrategyCanary(rollout *v1alpha1.Rollout, fldPath *field.Pat
 		}
 	}
 
+	// featureFlagProviders records whether the steps of each flag set it in LaunchDarkly
+	featureFlagProviders := map[string]bool{}
 	for i, step := range canary.Steps {
 		stepFldPath := fldPath.Child("steps").Index(i)
 		allErrs = append(allErrs, hasMultipleStepsType(step, stepFldPath)...)
 		if step.Experiment == nil && step.Pause == nil && step.SetWeight == nil && step.Analysis == nil && step.SetCanaryScale == nil &&
-			step.SetHeaderRoute == nil && step.SetMirrorRoute == nil && step.Plugin == nil {
-			errVal := fmt.Sprintf("step.Experiment: %t step.Pause: %t step.SetWeight: %t step.Analysis: %t step.SetCanaryScale: %t step.SetHeaderRoute: %t step.SetMirrorRoute: %t step.Plugin: %t",
-				step.Experiment == nil, step.Pause == nil, step.SetWeight == nil, step.Analysis == nil, step.SetCanaryScale == nil, step.SetHeaderRoute == nil, step.SetMirrorRoute == nil, step.Plugin == nil)
+			step.SetHeaderRoute == nil && step.SetMirrorRoute == nil && step.Plugin == nil && step.SetCanaryPodTemplateOverlay == nil && step.LoadTest == nil && step.PreProvision == nil && step.SetFeatureFlag == nil && step.Migration == nil && step.Gate == nil {
+			errVal := fmt.Sprintf("step.Experiment: %t step.Pause: %t step.SetWeight: %t step.Analysis: %t step.SetCanaryScale: %t step.SetHeaderRoute: %t step.SetMirrorRoute: %t step.Plugin: %t step.SetCanaryPodTemplateOverlay: %t step.LoadTest: %t step.PreProvision: %t step.SetFeatureFlag: %t step.Migration: %t step.Gate: %t",
+				step.Experiment == nil, step.Pause == nil, step.SetWeight == nil, step.Analysis == nil, step.SetCanaryScale == nil, step.SetHeaderRoute == nil, step.SetMirrorRoute == nil, step.Plugin == nil, step.SetCanaryPodTemplateOverlay == nil, step.LoadTest == nil, step.PreProvision == nil, step.SetFeatureFlag == nil, step.Migration == nil, step.Gate == nil)
 			allErrs = append(allErrs, field.Invalid(stepFldPath, errVal, InvalidStepMessage))
 		}
+		if step.SetCanaryPodTemplateOverlay != nil {
+			allErrs = append(allErrs, ValidatePodTemplateOverlay(rollout, step.SetCanaryPodTemplateOverlay, stepFldPath.Child("setCanaryPodTemplateOverlay"))...)
+		}
+		if step.LoadTest != nil {
+			if canary.CanaryService == "" {
+				allErrs = append(allErrs, field.Required(fldPath.Child("canaryService"), InvalidLoadTestCanaryServiceMessage))
+			}
+			allErrs = append(allErrs, ValidateLoadTestStep(step.LoadTest, stepFldPath.Child("loadTest"))...)
+		}
 
 		maxTrafficWeight := weightutil.MaxTrafficWeight(rollout)
 
+		if step.PreProvision != nil {
+			allErrs = append(allErrs, ValidatePreProvisionStep(step.PreProvision, maxTrafficWeight, stepFldPath.Child("preProvision"))...)
+		}
+
+		if step.Migration != nil {
+			allErrs = append(allErrs, ValidateMigrationStep(step.Migration, stepFldPath.Child("migration"))...)
+		}
+		if step.Gate != nil {
+			allErrs = append(allErrs, ValidateGateStep(step.Gate, stepFldPath.Child("gate"))...)
+		}
+
+		if step.SetFeatureFlag != nil {
+			allErrs = append(allErrs, ValidateSetFeatureFlagStep(step.SetFeatureFlag, stepFldPath.Child("setFeatureFlag"))...)
+			if previous, ok := featureFlagProviders[step.SetFeatureFlag.Flag]; ok && previous != (step.SetFeatureFlag.LaunchDarkly != nil) {
+				allErrs = append(allErrs, field.Invalid(stepFldPath.Child("setFeatureFlag"), step.SetFeatureFlag.Flag, InvalidSetFeatureFlagProviderChangeMessage))
+			}
+			featureFlagProviders[step.SetFeatureFlag.Flag] = step.SetFeatureFlag.LaunchDarkly != nil
+		}
+
 		if step.SetWeight != nil && (*step.SetWeight < 0 || *step.SetWeight > maxTrafficWeight) {
 			allErrs = append(allErrs, field.Invalid(stepFldPath.Child("setWeight"), *canary.Steps[i].SetWeight, fmt.Sprintf(InvalidSetWeightMessage, maxTrafficWeight)))
 		}
@@ -333,8 +855,10 @@ func ValidateRolloutStrategyCanary(rollout *v1alpha1.Rollout, fldPath *field.Pat
 
 		if step.SetHeaderRoute != nil {
 			trafficRouting := rollout.Spec.Strategy.Canary.TrafficRouting
-			if trafficRouting == nil || (trafficRouting.Istio == nil && trafficRouting.ALB == nil && trafficRouting.Apisix == nil && len(trafficRouting.Plugins) == 0) {
+			if trafficRouting == nil || (trafficRouting.Istio == nil && trafficRouting.ALB == nil && trafficRouting.Apisix == nil && trafficRouting.SMI == nil && len(trafficRouting.Plugins) == 0) {
 				allErrs = append(allErrs, field.Invalid(stepFldPath.Child("setHeaderRoute"), step.SetHeaderRoute, InvalidSetHeaderRouteTrafficPolicy))
+			} else if trafficRouting.Istio == nil && trafficRouting.ALB == nil && trafficRouting.Apisix == nil && len(trafficRouting.Plugins) == 0 && !smiSupportsHeaderRoutes() {
+				allErrs = append(allErrs, field.Invalid(stepFldPath.Child("setHeaderRoute"), step.SetHeaderRoute, fmt.Sprintf(InvalidSetHeaderRouteSMIVersionMessage, defaults.GetSMIAPIVersion())))
 			} else if step.SetHeaderRoute.Match != nil && len(step.SetHeaderRoute.Match) > 0 {
 				for j, match := range step.SetHeaderRoute.Match {
 					if trafficRouting.ALB != nil {
@@ -348,6 +872,10 @@ func ValidateRolloutStrategyCanary(rollout *v1alpha1.Rollout, fldPath *field.Pat
 			}
 		}
 
+		if step.ProgressDeadline != nil {
+			allErrs = append(allErrs, ValidateStepProgressDeadline(step, stepFldPath.Child("progressDeadline"))...)
+		}
+
 		if step.SetMirrorRoute != nil {
 			trafficRouting := rollout.Spec.Strategy.Canary.TrafficRouting
 			if trafficRouting == nil || (trafficRouting.Istio == nil && len(trafficRouting.Plugins) == 0) {
@@ -388,7 +916,12 @@ func ValidateRolloutStrategyCanary(rollout *v1alpha1.Rollout, fldPath *field.Pat
 
 		analysisRunArgs := make([]v1alpha1.AnalysisRunArgument, 0)
 		if step.Experiment != nil {
+			allErrs = append(allErrs, validateExperimentStepWinner(rollout, step.Experiment, stepFldPath.Child("experiment"))...)
+			allErrs = append(allErrs, validateExperimentStepTraffic(rollout, i, fldPath)...)
 			for tmplIndex, template := range step.Experiment.Templates {
+				if template.Patch != "" {
+					allErrs = append(allErrs, ValidatePodTemplateOverlay(rollout, &v1alpha1.PodTemplateOverlay{Patch: template.Patch}, stepFldPath.Child("experiment").Child("templates").Index(tmplIndex))...)
+				}
 				if template.Weight != nil {
 					if canary.TrafficRouting == nil {
 						allErrs = append(allErrs, field.Invalid(stepFldPath.Child("experiment").Child("templates").Index(tmplIndex).Child("weight"), *canary.Steps[i].Experiment.Templates[tmplIndex].Weight, InvalidCanaryExperimentTemplateWeightWithoutTrafficRouting))
@@ -422,6 +955,8 @@ func ValidateRolloutStrategyCanary(rollout *v1alpha1.Rollout, fldPath *field.Pat
 
 	}
 	allErrs = append(allErrs, ValidateRolloutStrategyAntiAffinity(canary.AntiAffinity, fldPath.Child("antiAffinity"))...)
+	allErrs = append(allErrs, ValidateEphemeralMetadataEnv(canary.CanaryMetadata, fldPath.Child("canaryMetadata"))...)
+	allErrs = append(allErrs, ValidateEphemeralMetadataEnv(canary.StableMetadata, fldPath.Child("stableMetadata"))...)
 	return allErrs
 }
 
@@ -439,6 +974,281 @@ func ValidateStepRouteFoundInManagedRoute(stepFldPath *field.Path, stepRoutName
 	return allErrs
 }
 
+// ValidateEphemeralMetadataEnv validates the environment variables which expose the ephemeral metadata
+func ValidateEphemeralMetadataEnv(podMetadata *v1alpha1.PodTemplateMetadata, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if podMetadata == nil {
+		return allErrs
+	}
+	names := map[string]bool{}
+	for i, envVar := range podMetadata.Env {
+		envFldPath := fldPath.Child("env").Index(i)
+		for _, msg := range validationutil.IsEnvVarName(envVar.Name) {
+			allErrs = append(allErrs, field.Invalid(envFldPath.Child("name"), envVar.Name, msg))
+		}
+		if names[envVar.Name] {
+			allErrs = append(allErrs, field.Invalid(envFldPath.Child("name"), envVar.Name, InvalidEphemeralMetadataEnvNameMessage))
+		}
+		names[envVar.Name] = true
+		if (envVar.Label == "") == (envVar.Annotation == "") {
+			allErrs = append(allErrs, field.Invalid(envFldPath, envVar, InvalidEphemeralMetadataEnvSourceMessage))
+		}
+	}
+	return allErrs
+}
+
+// ValidatePreProvisionStep validates the weight, priority class and timeout of a preProvision step
+func ValidatePreProvisionStep(preProvision *v1alpha1.RolloutPreProvisionStep, maxTrafficWeight int32, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if preProvision.PriorityClassName == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("priorityClassName"), InvalidPreProvisionPriorityClassMessage))
+	}
+	if preProvision.Weight != nil && (*preProvision.Weight < 0 || *preProvision.Weight > maxTrafficWeight) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("weight"), *preProvision.Weight, fmt.Sprintf(InvalidSetWeightMessage, maxTrafficWeight)))
+	}
+	if preProvision.Timeout != "" {
+		if _, err := preProvision.Timeout.Duration(); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("timeout"), preProvision.Timeout, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+// ValidateMigrationStep validates that a migration step defines exactly one of a Job and a status check, and its
+// rollback Job and durations
+func ValidateMigrationStep(migration *v1alpha1.RolloutMigrationStep, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if (migration.JobSpec == nil) == (migration.StatusCheck == nil) {
+		return append(allErrs, field.Invalid(fldPath, migration, InvalidMigrationMessage))
+	}
+	if migration.JobSpec != nil && len(migration.JobSpec.Template.Spec.Containers) == 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("jobSpec", "template", "spec", "containers"), migration.JobSpec.Template.Spec.Containers, InvalidMigrationJobMessage))
+	}
+	if migration.RollbackJobSpec != nil && len(migration.RollbackJobSpec.Template.Spec.Containers) == 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("rollbackJobSpec", "template", "spec", "containers"), migration.RollbackJobSpec.Template.Spec.Containers, InvalidMigrationJobMessage))
+	}
+	if migration.Timeout != "" {
+		if _, err := migration.Timeout.Duration(); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("timeout"), migration.Timeout, err.Error()))
+		}
+	}
+	if check := migration.StatusCheck; check != nil {
+		if check.URL == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("statusCheck", "url"), "migration statusCheck url must be set"))
+		}
+		if check.JSONPath != "" {
+			if err := jsonpath.New("migration").Parse(check.JSONPath); err != nil {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("statusCheck", "jsonPath"), check.JSONPath, err.Error()))
+			}
+		}
+		if check.Interval != "" {
+			if _, err := check.Interval.Duration(); err != nil {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("statusCheck", "interval"), check.Interval, err.Error()))
+			}
+		}
+	}
+	return allErrs
+}
+
+// smiSupportsHeaderRoutes returns whether the SMI TrafficSplits of the API version of the controller can match the
+// headers of a header route
+func smiSupportsHeaderRoutes() bool {
+	switch defaults.GetSMIAPIVersion() {
+	case "v1alpha3", "v1alpha4":
+		return true
+	default:
+		return false
+	}
+}
+
+// MaxGateTimeout is the maximum timeout of the requests of a gate step
+const MaxGateTimeout = 60 * time.Second
+
+// ValidateGateStep validates the URL, durations, retries and failure policy of a gate step
+func ValidateGateStep(gate *v1alpha1.RolloutGateStep, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if gate.URL == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("url"), "gate url must be set"))
+	}
+	if gate.Timeout != "" {
+		if timeout, err := gate.Timeout.Duration(); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("timeout"), gate.Timeout, err.Error()))
+		} else if timeout > MaxGateTimeout {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("timeout"), gate.Timeout, InvalidGateTimeoutMessage))
+		}
+	}
+	if gate.Interval != "" {
+		if _, err := gate.Interval.Duration(); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("interval"), gate.Interval, err.Error()))
+		}
+	}
+	if gate.Retries != nil && *gate.Retries < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("retries"), *gate.Retries, "gate retries must not be negative"))
+	}
+	switch gate.FailurePolicy {
+	case "", v1alpha1.GateFailurePolicyAbort, v1alpha1.GateFailurePolicyPause:
+	default:
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("failurePolicy"), gate.FailurePolicy, InvalidGateFailurePolicyMessage))
+	}
+	return allErrs
+}
+
+// ValidateSetFeatureFlagStep validates the flag, percentage and provider of a setFeatureFlag step
+func ValidateSetFeatureFlagStep(setFeatureFlag *v1alpha1.SetFeatureFlag, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if setFeatureFlag.Flag == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("flag"), "setFeatureFlag flag must be set"))
+	}
+	if setFeatureFlag.Percentage != nil && (*setFeatureFlag.Percentage < 0 || *setFeatureFlag.Percentage > 100) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("percentage"), *setFeatureFlag.Percentage, InvalidSetFeatureFlagPercentageMessage))
+	}
+	if (setFeatureFlag.LaunchDarkly == nil) == (setFeatureFlag.OpenFeature == nil) {
+		return append(allErrs, field.Invalid(fldPath, setFeatureFlag.Flag, InvalidSetFeatureFlagProviderMessage))
+	}
+	if launchDarkly := setFeatureFlag.LaunchDarkly; launchDarkly != nil {
+		if launchDarkly.ProjectKey == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("launchDarkly", "projectKey"), "LaunchDarkly projectKey must be set"))
+		}
+		if launchDarkly.EnvironmentKey == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("launchDarkly", "environmentKey"), "LaunchDarkly environmentKey must be set"))
+		}
+		if launchDarkly.APITokenSecretRef.Name == "" || launchDarkly.APITokenSecretRef.Key == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("launchDarkly", "apiTokenSecretRef"), "LaunchDarkly apiTokenSecretRef must reference the key of a secret"))
+		}
+	}
+	return allErrs
+}
+
+// ValidateLoadTestStep validates that a load test step defines exactly one valid load test
+func ValidateLoadTestStep(loadTest *v1alpha1.RolloutLoadTestStep, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	count := 0
+	for _, set := range []bool{loadTest.JobSpec != nil, loadTest.K6 != nil, loadTest.Vegeta != nil} {
+		if set {
+			count++
+		}
+	}
+	if count != 1 {
+		return append(allErrs, field.Invalid(fldPath, loadTest, InvalidLoadTestMessage))
+	}
+	if loadTest.JobSpec != nil && len(loadTest.JobSpec.Template.Spec.Containers) == 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("jobSpec", "template", "spec", "containers"), loadTest.JobSpec.Template.Spec.Containers, InvalidLoadTestJobMessage))
+	}
+	if loadTest.K6 != nil && loadTest.K6.Script == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("k6", "script"), "k6 script must be set"))
+	}
+	if loadTest.Vegeta != nil {
+		if loadTest.Vegeta.Rate < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("vegeta", "rate"), loadTest.Vegeta.Rate, "vegeta rate must not be negative"))
+		}
+		if loadTest.Vegeta.Duration != "" {
+			if _, err := loadTest.Vegeta.Duration.Duration(); err != nil {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("vegeta", "duration"), loadTest.Vegeta.Duration, err.Error()))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateExperimentStepWinner validates that an experiment step which promotes its winner selects the winner among
+// variants of the canary, and that the pod template of the rollout can be updated
+func validateExperimentStepWinner(rollout *v1alpha1.Rollout, step *v1alpha1.RolloutExperimentStep, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !step.PromoteWinner {
+		return allErrs
+	}
+	if step.WinnerSelection == nil {
+		return append(allErrs, field.Required(fldPath.Child("winnerSelection"), InvalidExperimentPromoteWinnerMessage))
+	}
+	if rollout.Spec.WorkloadRef != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("promoteWinner"), step.PromoteWinner, InvalidExperimentPromoteWinnerWorkloadRefMessage))
+	}
+	for i, candidate := range step.WinnerSelection.Candidates {
+		for _, template := range step.Templates {
+			if template.Name == candidate.TemplateName && template.SpecRef != v1alpha1.CanarySpecRef {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("winnerSelection", "candidates").Index(i).Child("templateName"), candidate.TemplateName, InvalidExperimentPromoteWinnerSpecRefMessage))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateExperimentStepTraffic validates that the templates of an experiment step and the canary receive no more than
+// the max traffic weight, and that the header routes of the templates are supported by the traffic routing
+func validateExperimentStepTraffic(rollout *v1alpha1.Rollout, stepIndex int, canaryFldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	canary := rollout.Spec.Strategy.Canary
+	step := canary.Steps[stepIndex].Experiment
+	fldPath := canaryFldPath.Child("steps").Index(stepIndex).Child("experiment")
+	if canary.TrafficRouting == nil {
+		// the weights of the templates are validated along with the template
+		for i, template := range step.Templates {
+			if template.HeaderRoute != nil {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("templates").Index(i).Child("headerRoute"), template.HeaderRoute.Name, InvalidExperimentTemplateHeaderRouteTrafficPolicy))
+			}
+		}
+		return allErrs
+	}
+
+	// the canary keeps the weight of the last setWeight step
+	totalWeight := int32(0)
+	for i := stepIndex - 1; i >= 0; i-- {
+		if canary.Steps[i].SetWeight != nil {
+			totalWeight = *canary.Steps[i].SetWeight
+			break
+		}
+	}
+	hasTemplateWeight := false
+	for i, template := range step.Templates {
+		if template.Weight != nil {
+			hasTemplateWeight = true
+			totalWeight += *template.Weight
+		}
+		if template.HeaderRoute == nil {
+			continue
+		}
+		headerRouteFldPath := fldPath.Child("templates").Index(i).Child("headerRoute")
+		if canary.TrafficRouting.Istio == nil {
+			allErrs = append(allErrs, field.Invalid(headerRouteFldPath, template.HeaderRoute.Name, InvalidExperimentTemplateHeaderRouteTrafficPolicy))
+			continue
+		}
+		if canary.TrafficRouting.ManagedRoutes == nil {
+			message := fmt.Sprintf(MissingFieldMessage, "spec.strategy.canary.trafficRouting.managedRoutes")
+			allErrs = append(allErrs, field.Required(canaryFldPath.Child("trafficRouting", "managedRoutes"), message))
+		} else {
+			allErrs = append(allErrs, ValidateStepRouteFoundInManagedRoute(headerRouteFldPath, template.HeaderRoute.Name, canary.TrafficRouting.ManagedRoutes)...)
+		}
+		for j, match := range template.HeaderRoute.Match {
+			allErrs = append(allErrs, hasMultipleMatchValues(match.HeaderValue, headerRouteFldPath.Child("match").Index(j))...)
+		}
+	}
+	if maxTrafficWeight := weightutil.MaxTrafficWeight(rollout); hasTemplateWeight && totalWeight > maxTrafficWeight {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("templates"), totalWeight, fmt.Sprintf(InvalidExperimentTemplateWeightsMessage, totalWeight, maxTrafficWeight)))
+	}
+	return allErrs
+}
+
+// ValidatePodTemplateOverlay validates that the overlay only patches the pod spec and can be applied to the pod template
+func ValidatePodTemplateOverlay(rollout *v1alpha1.Rollout, overlay *v1alpha1.PodTemplateOverlay, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if overlay.Patch == "" {
+		return allErrs
+	}
+	var patch map[string]any
+	if err := yaml.Unmarshal([]byte(overlay.Patch), &patch); err != nil {
+		return append(allErrs, field.Invalid(fldPath.Child("patch"), overlay.Patch, fmt.Sprintf(InvalidPodTemplateOverlayMessage, err)))
+	}
+	for key := range patch {
+		if key != "spec" {
+			return append(allErrs, field.Invalid(fldPath.Child("patch"), overlay.Patch, InvalidPodTemplateOverlayFieldsMessage))
+		}
+	}
+	if _, err := replicasetutil.ApplyPodTemplateOverlay(&rollout.Spec.Template, overlay); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("patch"), overlay.Patch, fmt.Sprintf(InvalidPodTemplateOverlayMessage, err)))
+	}
+	return allErrs
+}
+
 func ValidateRolloutStrategyAntiAffinity(antiAffinity *v1alpha1.AntiAffinity, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	if antiAffinity != nil {
@@ -450,6 +1260,19 @@ func ValidateRolloutStrategyAntiAffinity(antiAffinity *v1alpha1.AntiAffinity, fl
 		if preferred != nil && (preferred.Weight < 1 || preferred.Weight > 100) {
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("weight"), preferred.Weight, InvalidAntiAffinityWeightMessage))
 		}
+		if topologySpread := antiAffinity.TopologySpread; topologySpread != nil {
+			topologySpreadFldPath := fldPath.Child("topologySpread")
+			if topologySpread.MaxSkew != nil && *topologySpread.MaxSkew < 1 {
+				allErrs = append(allErrs, field.Invalid(topologySpreadFldPath.Child("maxSkew"), *topologySpread.MaxSkew, InvalidAntiAffinityTopologySpreadMaxSkewMessage))
+			}
+			seen := map[string]bool{}
+			for i, topologyKey := range topologySpread.TopologyKeys {
+				if topologyKey == "" || seen[topologyKey] {
+					allErrs = append(allErrs, field.Invalid(topologySpreadFldPath.Child("topologyKeys").Index(i), topologyKey, InvalidAntiAffinityTopologySpreadKeyMessage))
+				}
+				seen[topologyKey] = true
+			}
+		}
 	}
 	return allErrs
 }
@@ -492,11 +1315,16 @@ func hasMultipleStepsType(s v1alpha1.CanaryStep, fldPath *field.Path) field.Erro
 	oneOf = append(oneOf, s.Pause != nil)
 	oneOf = append(oneOf, s.Experiment != nil)
 	oneOf = append(oneOf, s.Analysis != nil)
+	oneOf = append(oneOf, s.LoadTest != nil)
+	oneOf = append(oneOf, s.PreProvision != nil)
+	oneOf = append(oneOf, s.SetFeatureFlag != nil)
+	oneOf = append(oneOf, s.Migration != nil)
+	oneOf = append(oneOf, s.Gate != nil)
 	hasMultipleStepTypes := false
 	for i := range oneOf {
 		if oneOf[i] {
 			if hasMultipleStepTypes {
-				errVal := fmt.Sprintf("step.Experiment: %t step.Pause: %t step.SetWeight: %t step.Analysis: %t", s.Experiment != nil, s.Pause != nil, s.SetWeight != nil, s.Analysis != nil)
+				errVal := fmt.Sprintf("step.Experiment: %t step.Pause: %t step.SetWeight: %t step.Analysis: %t step.LoadTest: %t step.PreProvision: %t step.SetFeatureFlag: %t step.Migration: %t step.Gate: %t", s.Experiment != nil, s.Pause != nil, s.SetWeight != nil, s.Analysis != nil, s.LoadTest != nil, s.PreProvision != nil, s.SetFeatureFlag != nil, s.Migration != nil, s.Gate != nil)
 				allErrs = append(allErrs, field.Invalid(fldPath, errVal, InvalidStepMessage))
 				break
 			}
@@ -32,6 +32,8 @@ const (
 	PostPromotionAnalysis AnalysisTemplateType = "PostPromotionAnalysis"
 	InlineAnalysis        AnalysisTemplateType = "InlineAnalysis"
 	BackgroundAnalysis    AnalysisTemplateType = "BackgroundAnalysis"
+	GuardrailAnalysis     AnalysisTemplateType = "GuardrailAnalysis"
+	RestartAnalysis       AnalysisTemplateType = "RestartAnalysis"
 )
 
 type AnalysisTemplatesWithType struct {
@@ -167,7 +169,7 @@ func ValidateAnalysisTemplateWithType(rollout *v1alpha1.Rollout, template *v1alp
 		templateName, templateSpec = template.Name, template.Spec
 	}
 
-	if templateType != BackgroundAnalysis {
+	if templateType != BackgroundAnalysis && templateType != GuardrailAnalysis {
 		setArgValuePlaceHolder(templateSpec.Args)
 		resolvedMetrics, err := validateAnalysisMetrics(templateSpec.Metrics, templateSpec.Args)
 		if err != nil {
@@ -182,18 +184,19 @@ func ValidateAnalysisTemplateWithType(rollout *v1alpha1.Rollout, template *v1alp
 				}
 			}
 		}
-	} else if templateType == BackgroundAnalysis && len(templateSpec.Args) > 0 {
+	} else if len(templateSpec.Args) > 0 {
+		rolloutArgs := getContinuousAnalysisArgs(rollout, templateType)
 		for _, arg := range templateSpec.Args {
 			if arg.Value != nil || arg.ValueFrom != nil {
 				continue
 			}
-			if rollout.Spec.Strategy.Canary == nil || rollout.Spec.Strategy.Canary.Analysis == nil || rollout.Spec.Strategy.Canary.Analysis.Args == nil {
+			if rolloutArgs == nil {
 				allErrs = append(allErrs, field.Invalid(fldPath, templateName, "missing analysis arguments in rollout spec"))
 				continue
 			}
 
 			foundArg := false
-			for _, rolloutArg := range rollout.Spec.Strategy.Canary.Analysis.Args {
+			for _, rolloutArg := range rolloutArgs {
 				if arg.Name == rolloutArg.Name {
 					foundArg = true
 					break
@@ -208,6 +211,24 @@ func ValidateAnalysisTemplateWithType(rollout *v1alpha1.Rollout, template *v1alp
 	return allErrs
 }
 
+// getContinuousAnalysisArgs returns the rollout args of the background or guardrail analysis
+func getContinuousAnalysisArgs(rollout *v1alpha1.Rollout, templateType AnalysisTemplateType) []v1alpha1.AnalysisRunArgument {
+	canary := rollout.Spec.Strategy.Canary
+	if canary == nil {
+		return nil
+	}
+	if templateType == GuardrailAnalysis {
+		if canary.Guardrail == nil {
+			return nil
+		}
+		return canary.Guardrail.Args
+	}
+	if canary.Analysis == nil {
+		return nil
+	}
+	return canary.Analysis.Args
+}
+
 func setArgValuePlaceHolder(Args []v1alpha1.Argument) {
 	for i, arg := range Args {
 		if arg.ValueFrom == nil && arg.Value == nil {
@@ -558,6 +579,11 @@ func GetAnalysisTemplateWithTypeFieldPath(templateType AnalysisTemplateType, can
 		fldPath = fldPath.Child("canary", "steps").Index(canaryStepIndex).Child("analysis", "templates")
 	case BackgroundAnalysis:
 		fldPath = fldPath.Child("canary", "analysis", "templates")
+	case GuardrailAnalysis:
+		fldPath = fldPath.Child("canary", "guardrail", "templates")
+	case RestartAnalysis:
+		// the restart strategy is not part of the update strategy
+		return field.NewPath("spec", "restartStrategy", "analysis", "templates")
 	default:
 		// No path specified
 		return nil
@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -290,6 +292,32 @@ func TestValidateRolloutStrategyCanary(t *testing.T) {
 		assert.Empty(t, allErrs)
 	})
 
+	t.Run("valid Knative missing canary and stable service", func(t *testing.T) {
+		validRo := ro.DeepCopy()
+		validRo.Spec.Strategy.Canary.Steps[0].SetWeight = ptr.To[int32](10)
+		validRo.Spec.Strategy.Canary.CanaryService = ""
+		validRo.Spec.Strategy.Canary.StableService = ""
+		validRo.Spec.Strategy.Canary.TrafficRouting.ALB = nil
+		validRo.Spec.Strategy.Canary.TrafficRouting.Knative = &v1alpha1.KnativeTrafficRouting{Service: "guestbook"}
+		validRo.Spec.Replicas = ptr.To[int32](0)
+		allErrs := ValidateRolloutStrategyCanary(validRo, field.NewPath(""))
+		assert.Empty(t, allErrs)
+
+		validRo.Spec.Strategy.Canary.TrafficRouting.Knative.Service = ""
+		allErrs = ValidateRolloutStrategyCanary(validRo, field.NewPath(""))
+		require.Len(t, allErrs, 1)
+		assert.Equal(t, InvalidKnativeServiceMessage, allErrs[0].Detail)
+
+		// the revisions of the Knative Service are the workload
+		validRo.Spec.Strategy.Canary.TrafficRouting.Knative.Service = "guestbook"
+		validRo.Spec.Replicas = ptr.To[int32](1)
+		validRo.Spec.Strategy.Canary.DynamicStableScale = true
+		allErrs = ValidateRolloutStrategyCanary(validRo, field.NewPath(""))
+		require.Len(t, allErrs, 2)
+		assert.Equal(t, InvalidKnativeReplicasMessage, allErrs[0].Detail)
+		assert.Equal(t, InvalidKnativeDynamicStableScaleMessage, allErrs[1].Detail)
+	})
+
 	t.Run("valid Istio with ping pong", func(t *testing.T) {
 		validRo := ro.DeepCopy()
 		validRo.Spec.Strategy.Canary.Steps[0].SetWeight = ptr.To[int32](10)
@@ -518,6 +546,520 @@ func TestValidateRolloutStrategyAntiAffinity(t *testing.T) {
 	}
 	allErrs = ValidateRolloutStrategyAntiAffinity(&antiAffinity, field.NewPath("antiAffinity"))
 	assert.Equal(t, InvalidAntiAffinityWeightMessage, allErrs[0].Detail)
+
+	antiAffinity = v1alpha1.AntiAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &v1alpha1.RequiredDuringSchedulingIgnoredDuringExecution{},
+		TopologySpread: &v1alpha1.AntiAffinityTopologySpread{
+			TopologyKeys: []string{"kubernetes.io/hostname", "topology.kubernetes.io/zone"},
+			MaxSkew:      ptr.To[int32](1),
+		},
+	}
+	allErrs = ValidateRolloutStrategyAntiAffinity(&antiAffinity, field.NewPath("antiAffinity"))
+	assert.Empty(t, allErrs)
+
+	antiAffinity.TopologySpread.MaxSkew = ptr.To[int32](0)
+	allErrs = ValidateRolloutStrategyAntiAffinity(&antiAffinity, field.NewPath("antiAffinity"))
+	assert.Len(t, allErrs, 1)
+	assert.Equal(t, InvalidAntiAffinityTopologySpreadMaxSkewMessage, allErrs[0].Detail)
+
+	antiAffinity.TopologySpread.MaxSkew = nil
+	antiAffinity.TopologySpread.TopologyKeys = []string{"kubernetes.io/hostname", "", "kubernetes.io/hostname"}
+	allErrs = ValidateRolloutStrategyAntiAffinity(&antiAffinity, field.NewPath("antiAffinity"))
+	assert.Len(t, allErrs, 2)
+	assert.Equal(t, InvalidAntiAffinityTopologySpreadKeyMessage, allErrs[0].Detail)
+	assert.Equal(t, "antiAffinity.topologySpread.topologyKeys[1]", allErrs[0].Field)
+	assert.Equal(t, "antiAffinity.topologySpread.topologyKeys[2]", allErrs[1].Field)
+}
+
+func TestValidatePreviewReplicaProfile(t *testing.T) {
+	blueGreen := v1alpha1.BlueGreenStrategy{
+		PreviewReplicaProfile: &v1alpha1.PreviewReplicaProfile{Percent: 50},
+	}
+	allErrs := ValidatePreviewReplicaProfile(&blueGreen, field.NewPath("previewReplicaProfile"))
+	assert.Empty(t, allErrs)
+
+	blueGreen.PreviewReplicaCount = ptr.To[int32](1)
+	allErrs = ValidatePreviewReplicaProfile(&blueGreen, field.NewPath("previewReplicaProfile"))
+	assert.Len(t, allErrs, 1)
+	assert.Equal(t, InvalidPreviewReplicaProfileMessage, allErrs[0].Detail)
+
+	blueGreen = v1alpha1.BlueGreenStrategy{
+		PreviewReplicaProfile: &v1alpha1.PreviewReplicaProfile{
+			Percent:     101,
+			MinReplicas: ptr.To[int32](3),
+			MaxReplicas: ptr.To[int32](2),
+		},
+	}
+	allErrs = ValidatePreviewReplicaProfile(&blueGreen, field.NewPath("previewReplicaProfile"))
+	assert.Len(t, allErrs, 2)
+	assert.Equal(t, InvalidPreviewReplicaPercentMessage, allErrs[0].Detail)
+	assert.Equal(t, InvalidPreviewReplicaBoundsMessage, allErrs[1].Detail)
+}
+
+func TestValidatePostPromotionSmokeTest(t *testing.T) {
+	allErrs := ValidatePostPromotionSmokeTest(&v1alpha1.PostPromotionSmokeTest{
+		Templates: []v1alpha1.AnalysisTemplateRef{{TemplateName: "smoke"}},
+	}, field.NewPath("postPromotionSmokeTest"))
+	assert.Empty(t, allErrs)
+
+	allErrs = ValidatePostPromotionSmokeTest(&v1alpha1.PostPromotionSmokeTest{
+		WindowSeconds: ptr.To[int32](0),
+	}, field.NewPath("postPromotionSmokeTest"))
+	assert.Len(t, allErrs, 2)
+	assert.Equal(t, InvalidPostPromotionSmokeTestMessage, allErrs[0].Detail)
+	assert.Equal(t, InvalidPostPromotionSmokeTestWindowMessage, allErrs[1].Detail)
+}
+
+func TestValidateStepProgressDeadline(t *testing.T) {
+	step := v1alpha1.CanaryStep{
+		SetWeight:        ptr.To[int32](10),
+		ProgressDeadline: &v1alpha1.StepProgressDeadline{Seconds: 60, Action: v1alpha1.StepProgressDeadlineActionPause},
+	}
+	allErrs := ValidateStepProgressDeadline(step, field.NewPath("progressDeadline"))
+	assert.Empty(t, allErrs)
+
+	invalid := v1alpha1.CanaryStep{
+		Pause:            &v1alpha1.RolloutPause{},
+		ProgressDeadline: &v1alpha1.StepProgressDeadline{Action: "Retry"},
+	}
+	allErrs = ValidateStepProgressDeadline(invalid, field.NewPath("progressDeadline"))
+	assert.Len(t, allErrs, 3)
+	assert.Equal(t, InvalidStepProgressDeadlinePauseStepMessage, allErrs[0].Detail)
+	assert.Equal(t, InvalidStepProgressDeadlineSecondsMessage, allErrs[1].Detail)
+	assert.Equal(t, InvalidStepProgressDeadlineActionMessage, allErrs[2].Detail)
+}
+
+func TestValidateScaleDownVerification(t *testing.T) {
+	verification := &v1alpha1.ScaleDownVerification{
+		DrainProbe:     &v1alpha1.DrainProbe{Path: "/connections", Port: 8080, Scheme: corev1.URISchemeHTTPS},
+		TimeoutSeconds: ptr.To[int32](60),
+	}
+	allErrs := ValidateScaleDownVerification(verification, field.NewPath("scaleDownVerification"))
+	assert.Empty(t, allErrs)
+
+	invalid := &v1alpha1.ScaleDownVerification{
+		DrainProbe:     &v1alpha1.DrainProbe{Scheme: "TCP", TimeoutSeconds: -1},
+		TimeoutSeconds: ptr.To[int32](-1),
+	}
+	allErrs = ValidateScaleDownVerification(invalid, field.NewPath("scaleDownVerification"))
+	require.Len(t, allErrs, 4)
+	assert.Equal(t, InvalidScaleDownVerificationTimeoutMessage, allErrs[0].Detail)
+	assert.Equal(t, InvalidDrainProbePortMessage, allErrs[1].Detail)
+	assert.Equal(t, InvalidDrainProbeSchemeMessage, allErrs[2].Detail)
+	assert.Equal(t, "scaleDownVerification.drainProbe.timeoutSeconds", allErrs[3].Field)
+
+	ro := &v1alpha1.Rollout{}
+	ro.Spec.Strategy.Canary = &v1alpha1.CanaryStrategy{
+		ScaleDownVerification: verification,
+	}
+	allErrs = ValidateRolloutStrategyCanary(ro, field.NewPath("canary"))
+	assert.Contains(t, allErrs.ToAggregate().Error(), InvalidCanaryScaleDownVerification)
+
+	ro.Spec.Strategy.Canary.StableService = "stable"
+	ro.Spec.Strategy.Canary.CanaryService = "canary"
+	ro.Spec.Strategy.Canary.TrafficRouting = &v1alpha1.RolloutTrafficRouting{SMI: &v1alpha1.SMITrafficRouting{}}
+	ro.Spec.Strategy.Canary.DynamicStableScale = true
+	allErrs = ValidateRolloutStrategyCanary(ro, field.NewPath("canary"))
+	assert.Contains(t, allErrs.ToAggregate().Error(), InvalidCanaryScaleDownVerificationWithDynamicStableScale)
+}
+
+func TestValidateKEDACoordination(t *testing.T) {
+	ro := &v1alpha1.Rollout{}
+	ro.Spec.Strategy.Canary = &v1alpha1.CanaryStrategy{
+		KEDA: &v1alpha1.KEDACoordination{Mode: v1alpha1.KEDACoordinationModePause},
+	}
+	allErrs := ValidateRolloutStrategyCanary(ro, field.NewPath("canary"))
+	assert.Empty(t, allErrs)
+
+	ro.Spec.Strategy.Canary.KEDA.Mode = "Proportional"
+	allErrs = ValidateRolloutStrategyCanary(ro, field.NewPath("canary"))
+	require.Len(t, allErrs, 1)
+	assert.Equal(t, "canary.keda.mode", allErrs[0].Field)
+	assert.Equal(t, InvalidKEDACoordinationModeMessage, allErrs[0].Detail)
+}
+
+func TestValidateReplicaSetPodDisruptionBudget(t *testing.T) {
+	minAvailable := intstr.FromString("50%")
+	maxUnavailable := intstr.FromInt(1)
+	allErrs := ValidateReplicaSetPodDisruptionBudget(&v1alpha1.ReplicaSetPodDisruptionBudget{MinAvailable: &minAvailable}, field.NewPath("podDisruptionBudget"))
+	assert.Empty(t, allErrs)
+
+	allErrs = ValidateReplicaSetPodDisruptionBudget(&v1alpha1.ReplicaSetPodDisruptionBudget{MinAvailable: &minAvailable, MaxUnavailable: &maxUnavailable}, field.NewPath("podDisruptionBudget"))
+	require.Len(t, allErrs, 1)
+	assert.Equal(t, InvalidPodDisruptionBudgetMessage, allErrs[0].Detail)
+
+	invalid := intstr.FromString("half")
+	allErrs = ValidateReplicaSetPodDisruptionBudget(&v1alpha1.ReplicaSetPodDisruptionBudget{MaxUnavailable: &invalid}, field.NewPath("podDisruptionBudget"))
+	require.Len(t, allErrs, 1)
+	assert.Equal(t, "podDisruptionBudget.maxUnavailable", allErrs[0].Field)
+	assert.Equal(t, InvalidPodDisruptionBudgetValueMessage, allErrs[0].Detail)
+}
+
+func TestValidateLoadTestStep(t *testing.T) {
+	ro := &v1alpha1.Rollout{}
+	ro.Spec.Strategy.Canary = &v1alpha1.CanaryStrategy{
+		CanaryService: "canary",
+		StableService: "stable",
+		Steps: []v1alpha1.CanaryStep{{
+			LoadTest: &v1alpha1.RolloutLoadTestStep{Vegeta: &v1alpha1.VegetaLoadTest{Rate: 50, Duration: "5m"}},
+		}},
+	}
+	allErrs := ValidateRolloutStrategyCanary(ro, field.NewPath("canary"))
+	assert.Empty(t, allErrs)
+
+	ro.Spec.Strategy.Canary.Steps[0].LoadTest.K6 = &v1alpha1.K6LoadTest{Script: "export default function () {}"}
+	allErrs = ValidateRolloutStrategyCanary(ro, field.NewPath("canary"))
+	require.Len(t, allErrs, 1)
+	assert.Equal(t, "canary.steps[0].loadTest", allErrs[0].Field)
+	assert.Equal(t, InvalidLoadTestMessage, allErrs[0].Detail)
+
+	ro.Spec.Strategy.Canary.Steps[0].LoadTest = &v1alpha1.RolloutLoadTestStep{JobSpec: &batchv1.JobSpec{}}
+	ro.Spec.Strategy.Canary.CanaryService = ""
+	allErrs = ValidateRolloutStrategyCanary(ro, field.NewPath("canary"))
+	require.Len(t, allErrs, 2)
+	assert.Equal(t, InvalidLoadTestCanaryServiceMessage, allErrs[0].Detail)
+	assert.Equal(t, InvalidLoadTestJobMessage, allErrs[1].Detail)
+}
+
+func TestValidatePreProvisionStep(t *testing.T) {
+	ro := &v1alpha1.Rollout{}
+	ro.Spec.Strategy.Canary = &v1alpha1.CanaryStrategy{
+		Steps: []v1alpha1.CanaryStep{
+			{PreProvision: &v1alpha1.RolloutPreProvisionStep{PriorityClassName: "placeholder", Timeout: "15m"}},
+			{SetWeight: ptr.To[int32](50)},
+		},
+	}
+	allErrs := ValidateRolloutStrategyCanary(ro, field.NewPath("canary"))
+	assert.Empty(t, allErrs)
+
+	ro.Spec.Strategy.Canary.Steps[0].PreProvision = &v1alpha1.RolloutPreProvisionStep{Weight: ptr.To[int32](101), Timeout: "soon"}
+	allErrs = ValidateRolloutStrategyCanary(ro, field.NewPath("canary"))
+	require.Len(t, allErrs, 3)
+	assert.Equal(t, "canary.steps[0].preProvision.priorityClassName", allErrs[0].Field)
+	assert.Equal(t, InvalidPreProvisionPriorityClassMessage, allErrs[0].Detail)
+	assert.Equal(t, "canary.steps[0].preProvision.weight", allErrs[1].Field)
+	assert.Equal(t, "canary.steps[0].preProvision.timeout", allErrs[2].Field)
+
+	ro.Spec.Strategy.Canary.Steps[0] = v1alpha1.CanaryStep{
+		SetWeight:    ptr.To[int32](10),
+		PreProvision: &v1alpha1.RolloutPreProvisionStep{PriorityClassName: "placeholder"},
+	}
+	allErrs = ValidateRolloutStrategyCanary(ro, field.NewPath("canary"))
+	require.Len(t, allErrs, 1)
+	assert.Equal(t, InvalidStepMessage, allErrs[0].Detail)
+}
+
+func TestValidateMigrationStep(t *testing.T) {
+	jobSpec := &batchv1.JobSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "migrate", Image: "migrate"}},
+	}}}
+	ro := &v1alpha1.Rollout{}
+	ro.Spec.Strategy.Canary = &v1alpha1.CanaryStrategy{
+		Steps: []v1alpha1.CanaryStep{
+			{Migration: &v1alpha1.RolloutMigrationStep{JobSpec: jobSpec, RollbackJobSpec: jobSpec, Timeout: "30m"}},
+			{Migration: &v1alpha1.RolloutMigrationStep{StatusCheck: &v1alpha1.MigrationStatusCheck{URL: "http://migrations/{{hash}}", JSONPath: "{$.status}"}}},
+			{SetWeight: ptr.To[int32](50)},
+		},
+	}
+	allErrs := ValidateRolloutStrategyCanary(ro, field.NewPath("canary"))
+	assert.Empty(t, allErrs)
+
+	ro.Spec.Strategy.Canary.Steps[0].Migration.StatusCheck = &v1alpha1.MigrationStatusCheck{}
+	allErrs = ValidateRolloutStrategyCanary(ro, field.NewPath("canary"))
+	require.Len(t, allErrs, 1)
+	assert.Equal(t, "canary.steps[0].migration", allErrs[0].Field)
+	assert.Equal(t, InvalidMigrationMessage, allErrs[0].Detail)
+
+	ro.Spec.Strategy.Canary.Steps[0].Migration = &v1alpha1.RolloutMigrationStep{JobSpec: &batchv1.JobSpec{}, RollbackJobSpec: &batchv1.JobSpec{}, Timeout: "soon"}
+	ro.Spec.Strategy.Canary.Steps[1].Migration.StatusCheck = &v1alpha1.MigrationStatusCheck{JSONPath: "{$.status", Interval: "often"}
+	allErrs = ValidateRolloutStrategyCanary(ro, field.NewPath("canary"))
+	require.Len(t, allErrs, 6)
+	assert.Equal(t, "canary.steps[0].migration.jobSpec.template.spec.containers", allErrs[0].Field)
+	assert.Equal(t, InvalidMigrationJobMessage, allErrs[0].Detail)
+	assert.Equal(t, "canary.steps[0].migration.rollbackJobSpec.template.spec.containers", allErrs[1].Field)
+	assert.Equal(t, "canary.steps[0].migration.timeout", allErrs[2].Field)
+	assert.Equal(t, "canary.steps[1].migration.statusCheck.url", allErrs[3].Field)
+	assert.Equal(t, "canary.steps[1].migration.statusCheck.jsonPath", allErrs[4].Field)
+	assert.Equal(t, "canary.steps[1].migration.statusCheck.interval", allErrs[5].Field)
+}
+
+func TestValidateGateStep(t *testing.T) {
+	ro := &v1alpha1.Rollout{}
+	ro.Spec.Strategy.Canary = &v1alpha1.CanaryStrategy{
+		Steps: []v1alpha1.CanaryStep{
+			{SetWeight: ptr.To[int32](10)},
+			{Gate: &v1alpha1.RolloutGateStep{URL: "http://approvals/gate", Timeout: "5s", Interval: "1m", Retries: ptr.To[int32](0), FailurePolicy: v1alpha1.GateFailurePolicyPause}},
+		},
+	}
+	allErrs := ValidateRolloutStrategyCanary(ro, field.NewPath("canary"))
+	assert.Empty(t, allErrs)
+
+	ro.Spec.Strategy.Canary.Steps[1].Gate = &v1alpha1.RolloutGateStep{Timeout: "soon", Interval: "often", Retries: ptr.To[int32](-1), FailurePolicy: "Ignore"}
+	allErrs = ValidateRolloutStrategyCanary(ro, field.NewPath("canary"))
+	require.Len(t, allErrs, 5)
+	assert.Equal(t, "canary.steps[1].gate.url", allErrs[0].Field)
+	assert.Equal(t, "canary.steps[1].gate.timeout", allErrs[1].Field)
+	assert.Equal(t, "canary.steps[1].gate.interval", allErrs[2].Field)
+	assert.Equal(t, "canary.steps[1].gate.retries", allErrs[3].Field)
+	assert.Equal(t, "canary.steps[1].gate.failurePolicy", allErrs[4].Field)
+	assert.Equal(t, InvalidGateFailurePolicyMessage, allErrs[4].Detail)
+
+	// the timeout of the requests of a gate step is bounded
+	ro.Spec.Strategy.Canary.Steps[1].Gate = &v1alpha1.RolloutGateStep{URL: "http://approvals/gate", Timeout: "60s"}
+	assert.Empty(t, ValidateRolloutStrategyCanary(ro, field.NewPath("canary")))
+	ro.Spec.Strategy.Canary.Steps[1].Gate.Timeout = "10m"
+	allErrs = ValidateRolloutStrategyCanary(ro, field.NewPath("canary"))
+	require.Len(t, allErrs, 1)
+	assert.Equal(t, "canary.steps[1].gate.timeout", allErrs[0].Field)
+	assert.Equal(t, InvalidGateTimeoutMessage, allErrs[0].Detail)
+}
+
+func TestValidateSetFeatureFlagStep(t *testing.T) {
+	launchDarkly := &v1alpha1.LaunchDarklyFeatureFlag{
+		ProjectKey:        "default",
+		EnvironmentKey:    "production",
+		APITokenSecretRef: v1alpha1.SecretKeyRef{Name: "launchdarkly", Key: "token"},
+	}
+	ro := &v1alpha1.Rollout{}
+	ro.Spec.Strategy.Canary = &v1alpha1.CanaryStrategy{
+		Steps: []v1alpha1.CanaryStep{
+			{SetWeight: ptr.To[int32](10)},
+			{SetFeatureFlag: &v1alpha1.SetFeatureFlag{Flag: "new-checkout", LaunchDarkly: launchDarkly}},
+			{SetFeatureFlag: &v1alpha1.SetFeatureFlag{Flag: "new-search", Percentage: ptr.To[int32](5), OpenFeature: &v1alpha1.OpenFeatureFeatureFlag{}}},
+		},
+	}
+	allErrs := ValidateRolloutStrategyCanary(ro, field.NewPath("canary"))
+	assert.Empty(t, allErrs)
+
+	ro.Spec.Strategy.Canary.Steps = append(ro.Spec.Strategy.Canary.Steps, v1alpha1.CanaryStep{
+		SetFeatureFlag: &v1alpha1.SetFeatureFlag{Flag: "new-checkout", Percentage: ptr.To[int32](101), OpenFeature: &v1alpha1.OpenFeatureFeatureFlag{}},
+	})
+	allErrs = ValidateRolloutStrategyCanary(ro, field.NewPath("canary"))
+	require.Len(t, allErrs, 2)
+	assert.Equal(t, "canary.steps[3].setFeatureFlag.percentage", allErrs[0].Field)
+	assert.Equal(t, InvalidSetFeatureFlagPercentageMessage, allErrs[0].Detail)
+	assert.Equal(t, InvalidSetFeatureFlagProviderChangeMessage, allErrs[1].Detail)
+
+	ro.Spec.Strategy.Canary.Steps = []v1alpha1.CanaryStep{{SetFeatureFlag: &v1alpha1.SetFeatureFlag{
+		LaunchDarkly: &v1alpha1.LaunchDarklyFeatureFlag{},
+		OpenFeature:  &v1alpha1.OpenFeatureFeatureFlag{},
+	}}}
+	allErrs = ValidateRolloutStrategyCanary(ro, field.NewPath("canary"))
+	require.Len(t, allErrs, 2)
+	assert.Equal(t, "canary.steps[0].setFeatureFlag.flag", allErrs[0].Field)
+	assert.Equal(t, InvalidSetFeatureFlagProviderMessage, allErrs[1].Detail)
+
+	ro.Spec.Strategy.Canary.Steps = []v1alpha1.CanaryStep{{SetFeatureFlag: &v1alpha1.SetFeatureFlag{
+		Flag:         "new-checkout",
+		LaunchDarkly: &v1alpha1.LaunchDarklyFeatureFlag{},
+	}}}
+	allErrs = ValidateRolloutStrategyCanary(ro, field.NewPath("canary"))
+	require.Len(t, allErrs, 3)
+	assert.Equal(t, "canary.steps[0].setFeatureFlag.launchDarkly.apiTokenSecretRef", allErrs[2].Field)
+
+	ro.Spec.Strategy.Canary.Steps = []v1alpha1.CanaryStep{{
+		SetWeight:      ptr.To[int32](10),
+		SetFeatureFlag: &v1alpha1.SetFeatureFlag{Flag: "new-checkout", LaunchDarkly: launchDarkly},
+	}}
+	allErrs = ValidateRolloutStrategyCanary(ro, field.NewPath("canary"))
+	require.Len(t, allErrs, 1)
+	assert.Equal(t, InvalidStepMessage, allErrs[0].Detail)
+}
+
+func TestValidateImageVerification(t *testing.T) {
+	fldPath := field.NewPath("spec", "imageVerification")
+	verification := &v1alpha1.ImageVerification{
+		PublicKeys: []v1alpha1.SecretKeyRef{{Name: "cosign", Key: "cosign.pub"}},
+	}
+	assert.Empty(t, ValidateImageVerification(verification, fldPath))
+
+	allErrs := ValidateImageVerification(&v1alpha1.ImageVerification{}, fldPath)
+	require.Len(t, allErrs, 1)
+	assert.Equal(t, InvalidImageVerificationMessage, allErrs[0].Detail)
+
+	verification.Identities = []v1alpha1.ImageVerificationIdentity{
+		{Issuer: "https://token.actions.githubusercontent.com", SubjectRegex: "^https://github.com/argoproj/"},
+		{Issuer: "https://accounts.google.com", Subject: "dev@example.com", SubjectRegex: "dev"},
+		{Subject: "dev@example.com"},
+		{Issuer: "https://accounts.google.com", SubjectRegex: "("},
+	}
+	allErrs = ValidateImageVerification(verification, fldPath)
+	require.Len(t, allErrs, 4)
+	assert.Equal(t, InvalidImageVerificationKeylessMessage, allErrs[0].Detail)
+	assert.Equal(t, "spec.imageVerification.identities[1]", allErrs[1].Field)
+	assert.Equal(t, InvalidImageVerificationSubjectMessage, allErrs[1].Detail)
+	assert.Equal(t, "spec.imageVerification.identities[2].issuer", allErrs[2].Field)
+	assert.Equal(t, "spec.imageVerification.identities[3].subjectRegex", allErrs[3].Field)
+
+	verification.Identities = verification.Identities[:1]
+	verification.CertificateAuthorities = &v1alpha1.SecretKeyRef{Name: "sigstore", Key: "fulcio.pem"}
+	verification.TransparencyLogPublicKey = &v1alpha1.SecretKeyRef{Name: "sigstore", Key: "rekor.pub"}
+	assert.Empty(t, ValidateImageVerification(verification, fldPath))
+}
+
+func TestValidateRestartStrategy(t *testing.T) {
+	batchSize := intstr.FromString("25%")
+	restartStrategy := &v1alpha1.RolloutRestartStrategy{
+		BatchSize:           &batchSize,
+		PauseBetweenBatches: "5m",
+	}
+	allErrs := ValidateRestartStrategy(restartStrategy, field.NewPath("restartStrategy"))
+	assert.Empty(t, allErrs)
+
+	for _, invalidBatchSize := range []intstr.IntOrString{intstr.FromInt(0), intstr.FromString("0%"), intstr.FromString("two")} {
+		restartStrategy := &v1alpha1.RolloutRestartStrategy{BatchSize: &invalidBatchSize}
+		allErrs := ValidateRestartStrategy(restartStrategy, field.NewPath("restartStrategy"))
+		require.Len(t, allErrs, 1)
+		assert.Equal(t, InvalidRestartBatchSizeMessage, allErrs[0].Detail)
+	}
+
+	allErrs = ValidateRestartStrategy(&v1alpha1.RolloutRestartStrategy{PauseBetweenBatches: "5 minutes"}, field.NewPath("restartStrategy"))
+	require.Len(t, allErrs, 1)
+	assert.Equal(t, "restartStrategy.pauseBetweenBatches", allErrs[0].Field)
+}
+
+func TestValidateGuardrail(t *testing.T) {
+	guardrail := &v1alpha1.RolloutGuardrail{
+		RolloutAnalysis: v1alpha1.RolloutAnalysis{
+			Templates: []v1alpha1.AnalysisTemplateRef{{TemplateName: "error-rate"}},
+		},
+		Action: v1alpha1.GuardrailActionPause,
+	}
+	allErrs := ValidateGuardrail(guardrail, field.NewPath("guardrail"))
+	assert.Empty(t, allErrs)
+
+	allErrs = ValidateGuardrail(&v1alpha1.RolloutGuardrail{Action: "Rollback"}, field.NewPath("guardrail"))
+	assert.Len(t, allErrs, 2)
+	assert.Equal(t, InvalidGuardrailMessage, allErrs[0].Detail)
+	assert.Equal(t, InvalidGuardrailActionMessage, allErrs[1].Detail)
+}
+
+func TestValidateEphemeralMetadataEnv(t *testing.T) {
+	fldPath := field.NewPath("canaryMetadata")
+	assert.Empty(t, ValidateEphemeralMetadataEnv(nil, fldPath))
+
+	podMetadata := &v1alpha1.PodTemplateMetadata{
+		Labels: map[string]string{"role": "canary"},
+		Env: []v1alpha1.EphemeralMetadataEnvVar{
+			{Name: "ROLE", Label: "role"},
+			{Name: "TRACK", Annotation: "track"},
+		},
+	}
+	assert.Empty(t, ValidateEphemeralMetadataEnv(podMetadata, fldPath))
+
+	podMetadata.Env = []v1alpha1.EphemeralMetadataEnvVar{
+		{Name: "ROLE", Label: "role"},
+		{Name: "ROLE", Label: "role", Annotation: "role"},
+		{Name: "1ROLE"},
+	}
+	allErrs := ValidateEphemeralMetadataEnv(podMetadata, fldPath)
+	require.Len(t, allErrs, 4)
+	assert.Equal(t, InvalidEphemeralMetadataEnvNameMessage, allErrs[0].Detail)
+	assert.Equal(t, InvalidEphemeralMetadataEnvSourceMessage, allErrs[1].Detail)
+	assert.Equal(t, "canaryMetadata.env[2].name", allErrs[2].Field)
+	assert.Equal(t, InvalidEphemeralMetadataEnvSourceMessage, allErrs[3].Detail)
+}
+
+func TestValidatePodTemplateOverlay(t *testing.T) {
+	ro := &v1alpha1.Rollout{}
+	ro.Spec.Template.Spec.Containers = []corev1.Container{{Name: "foo"}}
+	fldPath := field.NewPath("setCanaryPodTemplateOverlay")
+
+	allErrs := ValidatePodTemplateOverlay(ro, &v1alpha1.PodTemplateOverlay{}, fldPath)
+	assert.Empty(t, allErrs)
+
+	allErrs = ValidatePodTemplateOverlay(ro, &v1alpha1.PodTemplateOverlay{Patch: "spec:\n  containers:\n  - name: debug\n    image: busybox"}, fldPath)
+	assert.Empty(t, allErrs)
+
+	allErrs = ValidatePodTemplateOverlay(ro, &v1alpha1.PodTemplateOverlay{Patch: "metadata:\n  labels:\n    debug: 'true'"}, fldPath)
+	assert.Len(t, allErrs, 1)
+	assert.Equal(t, InvalidPodTemplateOverlayFieldsMessage, allErrs[0].Detail)
+
+	allErrs = ValidatePodTemplateOverlay(ro, &v1alpha1.PodTemplateOverlay{Patch: "spec: ["}, fldPath)
+	assert.Len(t, allErrs, 1)
+	assert.Contains(t, allErrs[0].Detail, "must be a valid strategic merge patch")
+
+	allErrs = ValidatePodTemplateOverlay(ro, &v1alpha1.PodTemplateOverlay{Patch: "spec:\n  containers: foo"}, fldPath)
+	assert.Len(t, allErrs, 1)
+	assert.Contains(t, allErrs[0].Detail, "must be a valid strategic merge patch")
+}
+
+func TestValidateCreateServices(t *testing.T) {
+	ro := &v1alpha1.Rollout{}
+	ro.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}}
+	ro.Spec.Template.Spec.Containers = []corev1.Container{{
+		Name:  "foo",
+		Ports: []corev1.ContainerPort{{ContainerPort: 8080}},
+	}}
+	ro.Spec.Strategy.Canary = &v1alpha1.CanaryStrategy{
+		CanaryService:  "canary",
+		StableService:  "stable",
+		CreateServices: true,
+	}
+	allErrs := ValidateCreateServices(ro, field.NewPath("createServices"))
+	assert.Empty(t, allErrs)
+
+	invalid := ro.DeepCopy()
+	invalid.Spec.Strategy.Canary.CanaryService = ""
+	invalid.Spec.Strategy.Canary.StableService = ""
+	invalid.Spec.Selector = &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: metav1.LabelSelectorOpExists}}}
+	invalid.Spec.Template.Spec.Containers[0].Ports = nil
+	allErrs = ValidateCreateServices(invalid, field.NewPath("createServices"))
+	assert.Len(t, allErrs, 3)
+	assert.Equal(t, InvalidCreateServicesMessage, allErrs[0].Detail)
+	assert.Equal(t, InvalidCreateServicesSelectorMessage, allErrs[1].Detail)
+	assert.Equal(t, InvalidCreateServicesPortsMessage, allErrs[2].Detail)
+}
+
+func TestValidatePreviewRouting(t *testing.T) {
+	blueGreen := v1alpha1.BlueGreenStrategy{
+		ActiveService:  "active",
+		PreviewService: "preview",
+		PreviewRouting: &v1alpha1.BlueGreenPreviewRouting{
+			Name: "preview",
+			Match: []v1alpha1.HeaderRoutingMatch{{
+				HeaderName:  "x-preview",
+				HeaderValue: &v1alpha1.StringMatch{Exact: "true"},
+			}},
+			Istio: &v1alpha1.BlueGreenIstioPreviewRouting{VirtualService: "vsvc"},
+		},
+	}
+	t.Run("valid", func(t *testing.T) {
+		allErrs := ValidatePreviewRouting(&blueGreen, field.NewPath("previewRouting"))
+		assert.Empty(t, allErrs)
+	})
+
+	t.Run("missing preview service", func(t *testing.T) {
+		invalid := blueGreen.DeepCopy()
+		invalid.PreviewService = ""
+		allErrs := ValidatePreviewRouting(invalid, field.NewPath("previewRouting"))
+		assert.Len(t, allErrs, 1)
+		assert.Equal(t, InvalidPreviewRoutingServiceMessage, allErrs[0].Detail)
+	})
+
+	t.Run("both providers", func(t *testing.T) {
+		invalid := blueGreen.DeepCopy()
+		invalid.PreviewRouting.GatewayAPI = &v1alpha1.BlueGreenGatewayAPIPreviewRouting{HTTPRoute: "route"}
+		allErrs := ValidatePreviewRouting(invalid, field.NewPath("previewRouting"))
+		assert.Len(t, allErrs, 1)
+		assert.Equal(t, InvalidPreviewRoutingProviderMessage, allErrs[0].Detail)
+	})
+
+	t.Run("invalid matches", func(t *testing.T) {
+		invalid := blueGreen.DeepCopy()
+		invalid.PreviewRouting.Match = nil
+		allErrs := ValidatePreviewRouting(invalid, field.NewPath("previewRouting"))
+		assert.Len(t, allErrs, 1)
+		assert.Equal(t, InvalidPreviewRoutingMatchMessage, allErrs[0].Detail)
+
+		invalid.PreviewRouting.Match = []v1alpha1.HeaderRoutingMatch{{
+			HeaderName:  "x-preview",
+			HeaderValue: &v1alpha1.StringMatch{Exact: "true", Prefix: "t"},
+		}}
+		allErrs = ValidatePreviewRouting(invalid, field.NewPath("previewRouting"))
+		assert.Len(t, allErrs, 1)
+		assert.Equal(t, InvalidStringMatchMultipleValuePolicy, allErrs[0].Detail)
+	})
 }
 
 func TestValidateRolloutStrategyCanarySetHeaderRoute(t *testing.T) {
@@ -578,6 +1120,49 @@ func TestValidateRolloutStrategyCanarySetHeaderRoutePlugins(t *testing.T) {
 		allErrs := ValidateRolloutStrategyCanary(validRo, field.NewPath(""))
 		assert.Equal(t, 0, len(allErrs))
 	})
+
+	t.Run("using SetHeaderRoute step with SMI", func(t *testing.T) {
+		defaults.SetSMIAPIVersion("v1alpha3")
+		defer defaults.SetSMIAPIVersion(defaults.DefaultSMITrafficSplitVersion)
+		validRo := ro.DeepCopy()
+		validRo.Spec.Strategy.Canary.Steps = []v1alpha1.CanaryStep{{
+			SetHeaderRoute: &v1alpha1.SetHeaderRoute{
+				Name:  "test",
+				Match: []v1alpha1.HeaderRoutingMatch{{HeaderName: "agent", HeaderValue: &v1alpha1.StringMatch{Prefix: "chrome"}}},
+			},
+		}}
+		validRo.Spec.Strategy.Canary.TrafficRouting = &v1alpha1.RolloutTrafficRouting{
+			ManagedRoutes: []v1alpha1.MangedRoutes{{Name: "test"}},
+			SMI:           &v1alpha1.SMITrafficRouting{},
+		}
+		allErrs := ValidateRolloutStrategyCanary(validRo, field.NewPath(""))
+		assert.Empty(t, allErrs)
+	})
+
+	t.Run("using SetHeaderRoute step with SMI TrafficSplits which can't match headers", func(t *testing.T) {
+		invalidRo := ro.DeepCopy()
+		invalidRo.Spec.Strategy.Canary.Steps = []v1alpha1.CanaryStep{{
+			SetHeaderRoute: &v1alpha1.SetHeaderRoute{
+				Name:  "test",
+				Match: []v1alpha1.HeaderRoutingMatch{{HeaderName: "agent", HeaderValue: &v1alpha1.StringMatch{Prefix: "chrome"}}},
+			},
+		}}
+		invalidRo.Spec.Strategy.Canary.TrafficRouting = &v1alpha1.RolloutTrafficRouting{
+			ManagedRoutes: []v1alpha1.MangedRoutes{{Name: "test"}},
+			SMI:           &v1alpha1.SMITrafficRouting{},
+		}
+		allErrs := ValidateRolloutStrategyCanary(invalidRo, field.NewPath("canary"))
+		require.Len(t, allErrs, 1)
+		assert.Equal(t, "canary.steps[0].setHeaderRoute", allErrs[0].Field)
+		assert.Equal(t, fmt.Sprintf(InvalidSetHeaderRouteSMIVersionMessage, defaults.DefaultSMITrafficSplitVersion), allErrs[0].Detail)
+
+		// the header route is set by Istio next to the TrafficSplit of the weights
+		invalidRo.Spec.Strategy.Canary.TrafficRouting.Istio = &v1alpha1.IstioTrafficRouting{
+			VirtualService: &v1alpha1.IstioVirtualService{Name: "vsvc", Routes: []string{"primary"}},
+		}
+		allErrs = ValidateRolloutStrategyCanary(invalidRo, field.NewPath("canary"))
+		assert.Empty(t, allErrs)
+	})
 }
 
 func TestValidateRolloutStrategyCanarySetMirrorRoute(t *testing.T) {
@@ -979,6 +1564,30 @@ func TestCanaryScaleDownDelaySeconds(t *testing.T) {
 		allErrs := ValidateRollout(ro)
 		assert.Empty(t, allErrs)
 	})
+	t.Run("scaleDownDelayOverrides with basic canary", func(t *testing.T) {
+		ro := ro.DeepCopy()
+		ro.Spec.Strategy.Canary.ScaleDownDelaySeconds = nil
+		ro.Spec.Strategy.Canary.ScaleDownDelayOverrides = []v1alpha1.ScaleDownDelayOverride{{Revision: 1, DelaySeconds: 3600}}
+		allErrs := ValidateRollout(ro)
+		assert.Len(t, allErrs, 1)
+		assert.Equal(t, InvalidCanaryScaleDownDelayOverrides, allErrs[0].Detail)
+	})
+	t.Run("invalid scaleDownDelayOverrides with traffic weight canary", func(t *testing.T) {
+		ro := ro.DeepCopy()
+		ro.Spec.Strategy.Canary.TrafficRouting = &v1alpha1.RolloutTrafficRouting{
+			SMI: &v1alpha1.SMITrafficRouting{},
+		}
+		ro.Spec.Strategy.Canary.ScaleDownDelayOverrides = []v1alpha1.ScaleDownDelayOverride{
+			{Revision: 1, DelaySeconds: 3600},
+			{Revision: 1, DelaySeconds: 60},
+			{Revision: 0, DelaySeconds: -1},
+		}
+		allErrs := ValidateRollout(ro)
+		assert.Len(t, allErrs, 3)
+		assert.Equal(t, DuplicatedScaleDownDelayOverrideMessage, allErrs[0].Detail)
+		assert.Equal(t, InvalidScaleDownDelayOverrideRevisionMessage, allErrs[1].Detail)
+		assert.Equal(t, InvalidScaleDownDelayOverrideDelayMessage, allErrs[2].Detail)
+	})
 }
 
 func TestCanaryDynamicStableScale(t *testing.T) {
@@ -1179,3 +1788,192 @@ func TestCanaryExperimentStepWithWeight(t *testing.T) {
 		assert.Equal(t, 0, len(allErrs))
 	})
 }
+
+func TestCanaryExperimentStepTraffic(t *testing.T) {
+	ro := &v1alpha1.Rollout{}
+	ro.Spec.Strategy.Canary = &v1alpha1.CanaryStrategy{
+		CanaryService: "canary",
+		StableService: "stable",
+		TrafficRouting: &v1alpha1.RolloutTrafficRouting{
+			Istio: &v1alpha1.IstioTrafficRouting{
+				VirtualService: &v1alpha1.IstioVirtualService{Name: "virtualSvc"},
+			},
+			ManagedRoutes: []v1alpha1.MangedRoutes{{Name: "variant-a"}},
+		},
+		Steps: []v1alpha1.CanaryStep{{
+			SetWeight: ptr.To[int32](40),
+		}, {
+			Experiment: &v1alpha1.RolloutExperimentStep{
+				Templates: []v1alpha1.RolloutExperimentTemplate{{
+					Name:    "variant-a",
+					SpecRef: v1alpha1.CanarySpecRef,
+					Weight:  ptr.To[int32](20),
+					HeaderRoute: &v1alpha1.SetHeaderRoute{
+						Name:  "variant-a",
+						Match: []v1alpha1.HeaderRoutingMatch{{HeaderName: "variant", HeaderValue: &v1alpha1.StringMatch{Exact: "a"}}},
+					},
+				}, {
+					Name:    "variant-b",
+					SpecRef: v1alpha1.CanarySpecRef,
+					Weight:  ptr.To[int32](20),
+				}},
+			},
+		}},
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		allErrs := ValidateRolloutStrategyCanary(ro, field.NewPath(""))
+		assert.Empty(t, allErrs)
+	})
+
+	t.Run("invalid - weights exceed the max traffic weight", func(t *testing.T) {
+		invalidRo := ro.DeepCopy()
+		invalidRo.Spec.Strategy.Canary.Steps[1].Experiment.Templates[1].Weight = ptr.To[int32](50)
+		allErrs := ValidateRolloutStrategyCanary(invalidRo, field.NewPath(""))
+		assert.Len(t, allErrs, 1)
+		assert.Equal(t, fmt.Sprintf(InvalidExperimentTemplateWeightsMessage, 110, 100), allErrs[0].Detail)
+	})
+
+	t.Run("invalid - header route not in managedRoutes", func(t *testing.T) {
+		invalidRo := ro.DeepCopy()
+		invalidRo.Spec.Strategy.Canary.TrafficRouting.ManagedRoutes = []v1alpha1.MangedRoutes{{Name: "other"}}
+		allErrs := ValidateRolloutStrategyCanary(invalidRo, field.NewPath(""))
+		assert.Len(t, allErrs, 1)
+		assert.Equal(t, InvalideStepRouteNameNotFoundInManagedRoutes, allErrs[0].Detail)
+	})
+
+	t.Run("invalid - header route with multiple match values", func(t *testing.T) {
+		invalidRo := ro.DeepCopy()
+		invalidRo.Spec.Strategy.Canary.Steps[1].Experiment.Templates[0].HeaderRoute.Match[0].HeaderValue.Prefix = "a"
+		allErrs := ValidateRolloutStrategyCanary(invalidRo, field.NewPath(""))
+		assert.Len(t, allErrs, 1)
+		assert.Equal(t, InvalidStringMatchMultipleValuePolicy, allErrs[0].Detail)
+	})
+
+	t.Run("invalid - header route unsupported by the traffic routing", func(t *testing.T) {
+		invalidRo := ro.DeepCopy()
+		invalidRo.Spec.Strategy.Canary.TrafficRouting.Istio = nil
+		invalidRo.Spec.Strategy.Canary.TrafficRouting.SMI = &v1alpha1.SMITrafficRouting{}
+		allErrs := ValidateRolloutStrategyCanary(invalidRo, field.NewPath(""))
+		assert.Len(t, allErrs, 1)
+		assert.Equal(t, InvalidExperimentTemplateHeaderRouteTrafficPolicy, allErrs[0].Detail)
+	})
+}
+
+func TestCanaryExperimentStepPromoteWinner(t *testing.T) {
+	ro := &v1alpha1.Rollout{}
+	ro.Spec.Template.Spec.Containers = []corev1.Container{{Name: "foo", Image: "bar"}}
+	ro.Spec.Strategy.Canary = &v1alpha1.CanaryStrategy{
+		Steps: []v1alpha1.CanaryStep{{
+			Experiment: &v1alpha1.RolloutExperimentStep{
+				Templates: []v1alpha1.RolloutExperimentTemplate{{
+					Name:    "canary",
+					SpecRef: v1alpha1.CanarySpecRef,
+				}, {
+					Name:    "canary-large",
+					SpecRef: v1alpha1.CanarySpecRef,
+					Patch:   `{"spec":{"containers":[{"name":"foo","image":"bar:large"}]}}`,
+				}},
+				WinnerSelection: &v1alpha1.ExperimentWinnerSelection{
+					Candidates: []v1alpha1.ExperimentWinnerCandidate{
+						{TemplateName: "canary", AnalysisName: "canary"},
+						{TemplateName: "canary-large", AnalysisName: "canary-large"},
+					},
+				},
+				PromoteWinner: true,
+			},
+		}},
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		allErrs := ValidateRolloutStrategyCanary(ro, field.NewPath(""))
+		assert.Empty(t, allErrs)
+	})
+
+	t.Run("invalid - no winnerSelection", func(t *testing.T) {
+		invalidRo := ro.DeepCopy()
+		invalidRo.Spec.Strategy.Canary.Steps[0].Experiment.WinnerSelection = nil
+		allErrs := ValidateRolloutStrategyCanary(invalidRo, field.NewPath(""))
+		assert.Len(t, allErrs, 1)
+		assert.Equal(t, InvalidExperimentPromoteWinnerMessage, allErrs[0].Detail)
+	})
+
+	t.Run("invalid - candidate references the stable spec", func(t *testing.T) {
+		invalidRo := ro.DeepCopy()
+		invalidRo.Spec.Strategy.Canary.Steps[0].Experiment.Templates[0].SpecRef = v1alpha1.StableSpecRef
+		allErrs := ValidateRolloutStrategyCanary(invalidRo, field.NewPath(""))
+		assert.Len(t, allErrs, 1)
+		assert.Equal(t, InvalidExperimentPromoteWinnerSpecRefMessage, allErrs[0].Detail)
+	})
+
+	t.Run("invalid - workloadRef", func(t *testing.T) {
+		invalidRo := ro.DeepCopy()
+		invalidRo.Spec.WorkloadRef = &v1alpha1.ObjectRef{Kind: "Deployment", Name: "foo"}
+		allErrs := validateExperimentStepWinner(invalidRo, invalidRo.Spec.Strategy.Canary.Steps[0].Experiment, field.NewPath(""))
+		assert.Len(t, allErrs, 1)
+		assert.Equal(t, InvalidExperimentPromoteWinnerWorkloadRefMessage, allErrs[0].Detail)
+	})
+
+	t.Run("invalid - patch of the metadata", func(t *testing.T) {
+		invalidRo := ro.DeepCopy()
+		invalidRo.Spec.Strategy.Canary.Steps[0].Experiment.Templates[1].Patch = `{"metadata":{"labels":{"foo":"bar"}}}`
+		allErrs := ValidateRolloutStrategyCanary(invalidRo, field.NewPath(""))
+		assert.Len(t, allErrs, 1)
+		assert.Equal(t, InvalidPodTemplateOverlayFieldsMessage, allErrs[0].Detail)
+	})
+}
+
+func TestValidateRolloutRestrictions(t *testing.T) {
+	config := &v1alpha1.RolloutControllerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+		Spec: v1alpha1.RolloutControllerConfigSpec{
+			Restrictions: &v1alpha1.RolloutRestrictions{
+				AllowedTrafficRouters: []string{"istio", "argoproj-labs/gatewayAPI"},
+				MaxCanaryWeightJump:   ptr.To[int32](40),
+				RequireAnalysis:       true,
+			},
+		},
+	}
+	ro := &v1alpha1.Rollout{}
+	ro.Spec.Strategy.Canary = &v1alpha1.CanaryStrategy{
+		TrafficRouting: &v1alpha1.RolloutTrafficRouting{
+			Istio:   &v1alpha1.IstioTrafficRouting{},
+			Plugins: map[string]json.RawMessage{"argoproj-labs/gatewayAPI": nil},
+		},
+		Steps: []v1alpha1.CanaryStep{
+			{SetWeight: ptr.To[int32](20)},
+			{Analysis: &v1alpha1.RolloutAnalysis{Templates: []v1alpha1.AnalysisTemplateRef{{TemplateName: "success-rate"}}}},
+			{SetWeight: ptr.To[int32](60)},
+		},
+	}
+	assert.Empty(t, ValidateRolloutRestrictions(ro, config))
+
+	// a rollout without restrictions is always valid
+	assert.Empty(t, ValidateRolloutRestrictions(ro, &v1alpha1.RolloutControllerConfig{}))
+
+	ro.Spec.Strategy.Canary.TrafficRouting.Nginx = &v1alpha1.NginxTrafficRouting{}
+	ro.Spec.Strategy.Canary.Steps = []v1alpha1.CanaryStep{{SetWeight: ptr.To[int32](50)}, {SetWeight: ptr.To[int32](80)}}
+	allErrs := ValidateRolloutRestrictions(ro, config)
+	require.Len(t, allErrs, 3)
+	assert.Equal(t, "spec.strategy.canary.trafficRouting", allErrs[0].Field)
+	assert.Equal(t, fmt.Sprintf(ForbiddenTrafficRouterMessage, "nginx", "team-a"), allErrs[0].Detail)
+	assert.Equal(t, "spec.strategy.canary.steps[0].setWeight", allErrs[1].Field)
+	assert.Equal(t, fmt.Sprintf(ForbiddenCanaryWeightJumpMessage, 50, 40, "team-a"), allErrs[1].Detail)
+	assert.Equal(t, "spec.strategy", allErrs[2].Field)
+	assert.Equal(t, fmt.Sprintf(MissingRequiredAnalysisMessage, "team-a"), allErrs[2].Detail)
+
+	// the full promotion after the last step is a weight increase as well
+	ro.Spec.Strategy.Canary.Steps = []v1alpha1.CanaryStep{{SetWeight: ptr.To[int32](40)}}
+	ro.Spec.Strategy.Canary.Analysis = &v1alpha1.RolloutAnalysisBackground{}
+	ro.Spec.Strategy.Canary.TrafficRouting.Nginx = nil
+	allErrs = ValidateRolloutRestrictions(ro, config)
+	require.Len(t, allErrs, 1)
+	assert.Equal(t, "spec.strategy.canary.steps", allErrs[0].Field)
+	assert.Equal(t, fmt.Sprintf(ForbiddenCanaryWeightJumpMessage, 60, 40, "team-a"), allErrs[0].Detail)
+
+	blueGreen := &v1alpha1.Rollout{}
+	blueGreen.Spec.Strategy.BlueGreen = &v1alpha1.BlueGreenStrategy{}
+	assert.Len(t, ValidateRolloutRestrictions(blueGreen, config), 1)
+	blueGreen.Spec.Strategy.BlueGreen.PostPromotionAnalysis = &v1alpha1.RolloutAnalysis{}
+	assert.Empty(t, ValidateRolloutRestrictions(blueGreen, config))
+}
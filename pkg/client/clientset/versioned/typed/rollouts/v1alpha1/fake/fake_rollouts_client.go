@@ -48,6 +48,14 @@ func (c *FakeArgoprojV1alpha1) Rollouts(namespace string) v1alpha1.RolloutInterf
 	return newFakeRollouts(c, namespace)
 }
 
+func (c *FakeArgoprojV1alpha1) RolloutControllerConfigs(namespace string) v1alpha1.RolloutControllerConfigInterface {
+	return newFakeRolloutControllerConfigs(c, namespace)
+}
+
+func (c *FakeArgoprojV1alpha1) RolloutNotificationPolicies(namespace string) v1alpha1.RolloutNotificationPolicyInterface {
+	return newFakeRolloutNotificationPolicies(c, namespace)
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeArgoprojV1alpha1) RESTClient() rest.Interface {
@@ -27,3 +27,7 @@ type ClusterAnalysisTemplateExpansion interface{}
 type ExperimentExpansion interface{}
 
 type RolloutExpansion interface{}
+
+type RolloutControllerConfigExpansion interface{}
+
+type RolloutNotificationPolicyExpansion interface{}
@@ -33,6 +33,8 @@ type ArgoprojV1alpha1Interface interface {
 	ClusterAnalysisTemplatesGetter
 	ExperimentsGetter
 	RolloutsGetter
+	RolloutControllerConfigsGetter
+	RolloutNotificationPoliciesGetter
 }
 
 // ArgoprojV1alpha1Client is used to interact with features provided by the argoproj.io group.
@@ -60,6 +62,14 @@ func (c *ArgoprojV1alpha1Client) Rollouts(namespace string) RolloutInterface {
 	return newRollouts(c, namespace)
 }
 
+func (c *ArgoprojV1alpha1Client) RolloutControllerConfigs(namespace string) RolloutControllerConfigInterface {
+	return newRolloutControllerConfigs(c, namespace)
+}
+
+func (c *ArgoprojV1alpha1Client) RolloutNotificationPolicies(namespace string) RolloutNotificationPolicyInterface {
+	return newRolloutNotificationPolicies(c, namespace)
+}
+
 // NewForConfig creates a new ArgoprojV1alpha1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
@@ -63,6 +63,10 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Argoproj().V1alpha1().Experiments().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("rollouts"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Argoproj().V1alpha1().Rollouts().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("rolloutcontrollerconfigs"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Argoproj().V1alpha1().RolloutControllerConfigs().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("rolloutnotificationpolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Argoproj().V1alpha1().RolloutNotificationPolicies().Informer()}, nil
 
 	}
 
@@ -34,6 +34,10 @@ type Interface interface {
 	Experiments() ExperimentInformer
 	// Rollouts returns a RolloutInformer.
 	Rollouts() RolloutInformer
+	// RolloutControllerConfigs returns a RolloutControllerConfigInformer.
+	RolloutControllerConfigs() RolloutControllerConfigInformer
+	// RolloutNotificationPolicies returns a RolloutNotificationPolicyInformer.
+	RolloutNotificationPolicies() RolloutNotificationPolicyInformer
 }
 
 type version struct {
@@ -71,3 +75,13 @@ func (v *version) Experiments() ExperimentInformer {
 func (v *version) Rollouts() RolloutInformer {
 	return &rolloutInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// RolloutControllerConfigs returns a RolloutControllerConfigInformer.
+func (v *version) RolloutControllerConfigs() RolloutControllerConfigInformer {
+	return &rolloutControllerConfigInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// RolloutNotificationPolicies returns a RolloutNotificationPolicyInformer.
+func (v *version) RolloutNotificationPolicies() RolloutNotificationPolicyInformer {
+	return &rolloutNotificationPolicyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
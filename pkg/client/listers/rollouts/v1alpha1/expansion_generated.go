@@ -53,3 +53,19 @@ type RolloutListerExpansion interface{}
 // RolloutNamespaceListerExpansion allows custom methods to be added to
 // RolloutNamespaceLister.
 type RolloutNamespaceListerExpansion interface{}
+
+// RolloutControllerConfigListerExpansion allows custom methods to be added to
+// RolloutControllerConfigLister.
+type RolloutControllerConfigListerExpansion interface{}
+
+// RolloutControllerConfigNamespaceListerExpansion allows custom methods to be added to
+// RolloutControllerConfigNamespaceLister.
+type RolloutControllerConfigNamespaceListerExpansion interface{}
+
+// RolloutNotificationPolicyListerExpansion allows custom methods to be added to
+// RolloutNotificationPolicyLister.
+type RolloutNotificationPolicyListerExpansion interface{}
+
+// RolloutNotificationPolicyNamespaceListerExpansion allows custom methods to be added to
+// RolloutNotificationPolicyNamespaceLister.
+type RolloutNotificationPolicyNamespaceListerExpansion interface{}
@@ -0,0 +1,38 @@
+package analysis
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
+)
+
+const (
+	analysisExample = `
+  # List the analysis runs of a rollout
+  %[1]s analysis list my-rollout
+
+  # Show the measurements of an analysis run
+  %[1]s analysis get my-rollout-6d8f9c-2
+
+  # Print the logs of the job metrics of an analysis run
+  %[1]s analysis logs my-rollout-6d8f9c-2`
+)
+
+// NewCmdAnalysis returns a new instance of an `rollouts analysis` command
+func NewCmdAnalysis(o *options.ArgoRolloutsOptions) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:          "analysis COMMAND",
+		Short:        "Inspect the analysis runs of rollouts",
+		Long:         "This command consists of multiple subcommands which can be used to debug the analysis runs of rollouts.",
+		Example:      o.Example(analysisExample),
+		Aliases:      []string{"analysisrun", "ar"},
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			return o.UsageErr(c)
+		},
+	}
+	cmd.AddCommand(NewCmdAnalysisList(o))
+	cmd.AddCommand(NewCmdAnalysisGet(o))
+	cmd.AddCommand(NewCmdAnalysisLogs(o))
+	return cmd
+}
@@ -0,0 +1,143 @@
+package analysis
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
+	completionutil "github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/util/completion"
+	timeutil "github.com/argoproj/argo-rollouts/utils/time"
+)
+
+const (
+	analysisGetExample = `
+  # Show the metrics and measurements of an analysis run
+  %[1]s analysis get my-rollout-6d8f9c-2
+
+  # Only show the measurements of a metric
+  %[1]s analysis get my-rollout-6d8f9c-2 --metric success-rate`
+
+	metricsHeaderFmtString      = "METRIC\tSTATUS\tSUCCESS CONDITION\tFAILURE CONDITION\tCOUNT\tSUCCESSFUL\tFAILED\tINCONCLUSIVE\tERROR\n"
+	metricsColumnFmtString      = "%s\t%s\t%s\t%s\t%s\t%d\t%d\t%d\t%d\n"
+	measurementsHeaderFmtString = "METRIC\t#\tSTATUS\tVALUE\tFINISHED\tMESSAGE\n"
+	measurementsColumnFmtString = "%s\t%d\t%s\t%s\t%s\t%s\n"
+)
+
+// NewCmdAnalysisGet returns a new instance of an `rollouts analysis get` command
+func NewCmdAnalysisGet(o *options.ArgoRolloutsOptions) *cobra.Command {
+	var metric string
+	var cmd = &cobra.Command{
+		Use:   "get ANALYSISRUN_NAME",
+		Short: "Show the metrics and measurements of an analysis run",
+		Long: "This command shows the conditions and results of the metrics of an analysis run, and the value and " +
+			"status of each of their measurements.",
+		Example:      o.Example(analysisGetExample),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return o.UsageErr(c)
+			}
+			run, err := o.RolloutsClientset().ArgoprojV1alpha1().AnalysisRuns(o.Namespace()).Get(c.Context(), args[0], metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			if metric != "" && metricSpec(run, metric) == nil {
+				return fmt.Errorf("analysis run '%s' has no metric '%s'", run.Name, metric)
+			}
+			printAnalysisRun(o.Out, run, metric)
+			return nil
+		},
+		ValidArgsFunction: completionutil.AnalysisRunNameCompletionFunc(o),
+	}
+	cmd.Flags().StringVar(&metric, "metric", "", "Only show the measurements of this metric")
+	_ = cmd.RegisterFlagCompletionFunc("metric", completionutil.MetricNameCompletionFunc(o))
+	return cmd
+}
+
+// printAnalysisRun prints the status of the analysis run, a table of its metrics and a table of their measurements
+func printAnalysisRun(out io.Writer, run *v1alpha1.AnalysisRun, metricName string) {
+	fmt.Fprintf(out, "Name:       %s\n", run.Name)
+	fmt.Fprintf(out, "Namespace:  %s\n", run.Namespace)
+	fmt.Fprintf(out, "Status:     %s\n", phaseOrDash(run.Status.Phase))
+	if run.Status.Message != "" {
+		fmt.Fprintf(out, "Message:    %s\n", run.Status.Message)
+	}
+	if len(run.Spec.Args) > 0 {
+		var args []string
+		for _, arg := range run.Spec.Args {
+			value := "<valueFrom>"
+			if arg.Value != nil {
+				value = *arg.Value
+			}
+			args = append(args, arg.Name+"="+value)
+		}
+		fmt.Fprintf(out, "Args:       %s\n", strings.Join(args, ", "))
+	}
+
+	fmt.Fprintln(out)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprint(w, metricsHeaderFmtString)
+	for _, metric := range run.Spec.Metrics {
+		if metricName != "" && metric.Name != metricName {
+			continue
+		}
+		result := metricResult(run, metric.Name)
+		count := "-"
+		if metric.Count != nil {
+			count = metric.Count.String()
+		}
+		fmt.Fprintf(w, metricsColumnFmtString, metric.Name, phaseOrDash(result.Phase), orDash(metric.SuccessCondition), orDash(metric.FailureCondition),
+			count, result.Successful, result.Failed, result.Inconclusive, result.Error)
+	}
+	_ = w.Flush()
+
+	fmt.Fprintln(out)
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprint(w, measurementsHeaderFmtString)
+	for _, metric := range run.Spec.Metrics {
+		if metricName != "" && metric.Name != metricName {
+			continue
+		}
+		result := metricResult(run, metric.Name)
+		for i, measurement := range result.Measurements {
+			finished := "-"
+			if measurement.FinishedAt != nil {
+				finished = duration.HumanDuration(timeutil.MetaNow().Sub(measurement.FinishedAt.Time)) + " ago"
+			}
+			fmt.Fprintf(w, measurementsColumnFmtString, metric.Name, i+1, phaseOrDash(measurement.Phase), orDash(measurement.Value), finished, orDash(measurement.Message))
+		}
+	}
+	_ = w.Flush()
+}
+
+func metricSpec(run *v1alpha1.AnalysisRun, name string) *v1alpha1.Metric {
+	for i := range run.Spec.Metrics {
+		if run.Spec.Metrics[i].Name == name {
+			return &run.Spec.Metrics[i]
+		}
+	}
+	return nil
+}
+
+func metricResult(run *v1alpha1.AnalysisRun, name string) v1alpha1.MetricResult {
+	for _, result := range run.Status.MetricResults {
+		if result.Name == name {
+			return result
+		}
+	}
+	return v1alpha1.MetricResult{Name: name}
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
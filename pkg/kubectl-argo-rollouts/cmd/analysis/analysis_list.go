@@ -0,0 +1,104 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
+	completionutil "github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/util/completion"
+	"github.com/argoproj/argo-rollouts/utils/annotations"
+	timeutil "github.com/argoproj/argo-rollouts/utils/time"
+)
+
+const (
+	analysisListExample = `
+  # List the analysis runs of a rollout
+  %[1]s analysis list my-rollout`
+
+	analysisListHeaderFmtString = "NAME\tTYPE\tREVISION\tSTATUS\tSUCCESSFUL\tFAILED\tINCONCLUSIVE\tERROR\tAGE\n"
+	analysisListColumnFmtString = "%s\t%s\t%s\t%s\t%d\t%d\t%d\t%d\t%s\n"
+)
+
+// NewCmdAnalysisList returns a new instance of an `rollouts analysis list` command
+func NewCmdAnalysisList(o *options.ArgoRolloutsOptions) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:          "list ROLLOUT_NAME",
+		Short:        "List the analysis runs of a rollout",
+		Long:         "This command lists the analysis runs of a rollout, the most recent first.",
+		Example:      o.Example(analysisListExample),
+		Aliases:      []string{"ls"},
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return o.UsageErr(c)
+			}
+			ctx := c.Context()
+			namespace := o.Namespace()
+			ro, err := o.RolloutsClientset().ArgoprojV1alpha1().Rollouts(namespace).Get(ctx, args[0], metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			arList, err := o.RolloutsClientset().ArgoprojV1alpha1().AnalysisRuns(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return err
+			}
+			var runs []v1alpha1.AnalysisRun
+			for _, run := range arList.Items {
+				if ownerRef := metav1.GetControllerOf(&run); ownerRef != nil && ownerRef.UID == ro.UID {
+					runs = append(runs, run)
+				}
+			}
+			printAnalysisRunTable(o, runs)
+			return nil
+		},
+		ValidArgsFunction: completionutil.RolloutNameCompletionFunc(o),
+	}
+	return cmd
+}
+
+// printAnalysisRunTable prints the analysis runs in table format, the most recent first
+func printAnalysisRunTable(o *options.ArgoRolloutsOptions, runs []v1alpha1.AnalysisRun) {
+	if len(runs) == 0 {
+		fmt.Fprintln(o.ErrOut, "No resources found.")
+		return
+	}
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[j].CreationTimestamp.Before(&runs[i].CreationTimestamp)
+	})
+	w := tabwriter.NewWriter(o.Out, 0, 0, 2, ' ', 0)
+	fmt.Fprint(w, analysisListHeaderFmtString)
+	for _, run := range runs {
+		runType := run.Labels[v1alpha1.RolloutTypeLabel]
+		if runType == "" {
+			runType = "-"
+		}
+		revision := "-"
+		if rev, ok := annotations.GetRevisionAnnotation(&run); ok {
+			revision = strconv.Itoa(int(rev))
+		}
+		var successful, failed, inconclusive, errored int32
+		for _, result := range run.Status.MetricResults {
+			successful += result.Successful
+			failed += result.Failed
+			inconclusive += result.Inconclusive
+			errored += result.Error
+		}
+		age := duration.HumanDuration(timeutil.MetaNow().Sub(run.CreationTimestamp.Time))
+		fmt.Fprintf(w, analysisListColumnFmtString, run.Name, runType, revision, phaseOrDash(run.Status.Phase), successful, failed, inconclusive, errored, age)
+	}
+	_ = w.Flush()
+}
+
+func phaseOrDash(phase v1alpha1.AnalysisPhase) string {
+	if phase == "" {
+		return "-"
+	}
+	return string(phase)
+}
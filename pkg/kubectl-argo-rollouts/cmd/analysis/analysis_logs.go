@@ -0,0 +1,142 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/argoproj/argo-rollouts/metricproviders/job"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
+	completionutil "github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/util/completion"
+)
+
+const (
+	analysisLogsExample = `
+  # Print the logs of the pods of all the job measurements of an analysis run
+  %[1]s analysis logs my-rollout-6d8f9c-2
+
+  # Stream the logs of the pods of the latest job measurement of a metric
+  %[1]s analysis logs my-rollout-6d8f9c-2 --metric integration-test --latest --follow`
+)
+
+type analysisLogsOptions struct {
+	metric    string
+	container string
+	latest    bool
+	follow    bool
+}
+
+// NewCmdAnalysisLogs returns a new instance of an `rollouts analysis logs` command
+func NewCmdAnalysisLogs(o *options.ArgoRolloutsOptions) *cobra.Command {
+	var logsOptions analysisLogsOptions
+	var cmd = &cobra.Command{
+		Use:   "logs ANALYSISRUN_NAME",
+		Short: "Print the logs of the job metrics of an analysis run",
+		Long: "This command prints the logs of the pods of the jobs which were run for the job metrics of an analysis " +
+			"run. The logs are only available as long as the jobs and their pods were not deleted.",
+		Example:      o.Example(analysisLogsExample),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return o.UsageErr(c)
+			}
+			run, err := o.RolloutsClientset().ArgoprojV1alpha1().AnalysisRuns(o.Namespace()).Get(c.Context(), args[0], metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			if logsOptions.metric != "" {
+				metric := metricSpec(run, logsOptions.metric)
+				if metric == nil {
+					return fmt.Errorf("analysis run '%s' has no metric '%s'", run.Name, logsOptions.metric)
+				}
+				if metric.Provider.Job == nil {
+					return fmt.Errorf("metric '%s' of analysis run '%s' is not a job metric", logsOptions.metric, run.Name)
+				}
+			}
+			return printJobLogs(c.Context(), o.Out, o.KubeClientset(), run, logsOptions)
+		},
+		ValidArgsFunction: completionutil.AnalysisRunNameCompletionFunc(o),
+	}
+	cmd.Flags().StringVar(&logsOptions.metric, "metric", "", "Only print the logs of this metric")
+	_ = cmd.RegisterFlagCompletionFunc("metric", completionutil.MetricNameCompletionFunc(o))
+	cmd.Flags().StringVarP(&logsOptions.container, "container", "c", "", "Container of the job pods to print the logs of. Defaults to the only container of the pods")
+	cmd.Flags().BoolVar(&logsOptions.latest, "latest", false, "Only print the logs of the latest measurement of each metric")
+	cmd.Flags().BoolVarP(&logsOptions.follow, "follow", "f", false, "Stream the logs until the pods terminate")
+	return cmd
+}
+
+// jobMeasurement is a measurement of a job metric
+type jobMeasurement struct {
+	metric    string
+	number    int
+	namespace string
+	jobName   string
+}
+
+// jobMeasurements returns the measurements of the job metrics of the analysis run which created a job
+func jobMeasurements(run *v1alpha1.AnalysisRun, metricName string, latest bool) []jobMeasurement {
+	var measurements []jobMeasurement
+	for _, metric := range run.Spec.Metrics {
+		if metric.Provider.Job == nil || (metricName != "" && metric.Name != metricName) {
+			continue
+		}
+		var metricMeasurements []jobMeasurement
+		for i, measurement := range metricResult(run, metric.Name).Measurements {
+			jobName := measurement.Metadata[job.JobNameKey]
+			if jobName == "" {
+				continue
+			}
+			namespace := run.Namespace
+			if jobNamespace := measurement.Metadata[job.JobNamespaceKey]; jobNamespace != "" {
+				namespace = jobNamespace
+			}
+			metricMeasurements = append(metricMeasurements, jobMeasurement{metric: metric.Name, number: i + 1, namespace: namespace, jobName: jobName})
+		}
+		if latest && len(metricMeasurements) > 0 {
+			metricMeasurements = metricMeasurements[len(metricMeasurements)-1:]
+		}
+		measurements = append(measurements, metricMeasurements...)
+	}
+	return measurements
+}
+
+// printJobLogs prints the logs of the pods of the jobs of the job measurements, each preceded by a header line
+func printJobLogs(ctx context.Context, out io.Writer, kubeClient kubernetes.Interface, run *v1alpha1.AnalysisRun, logsOptions analysisLogsOptions) error {
+	measurements := jobMeasurements(run, logsOptions.metric, logsOptions.latest)
+	if len(measurements) == 0 {
+		return fmt.Errorf("analysis run '%s' has no job measurements", run.Name)
+	}
+	for _, measurement := range measurements {
+		selector := labels.SelectorFromSet(labels.Set{"job-name": measurement.jobName}).String()
+		pods, err := kubeClient.CoreV1().Pods(measurement.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return err
+		}
+		if len(pods.Items) == 0 {
+			fmt.Fprintf(out, "==> metric %s, measurement %d: no pods found for job %s <==\n", measurement.metric, measurement.number, measurement.jobName)
+			continue
+		}
+		for _, pod := range pods.Items {
+			fmt.Fprintf(out, "==> metric %s, measurement %d: pod %s <==\n", measurement.metric, measurement.number, pod.Name)
+			logOpts := &corev1.PodLogOptions{Container: logsOptions.container, Follow: logsOptions.follow}
+			stream, err := kubeClient.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, logOpts).Stream(ctx)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(out, stream)
+			stream.Close()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(out)
+		}
+	}
+	return nil
+}
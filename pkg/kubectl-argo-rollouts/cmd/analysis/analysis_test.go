@@ -0,0 +1,183 @@
+package analysis
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/metricproviders/job"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options/fake"
+	timeutil "github.com/argoproj/argo-rollouts/utils/time"
+)
+
+func newRollout() *v1alpha1.Rollout {
+	return &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: "test", UID: "rollout-uid"},
+	}
+}
+
+func newAnalysisRun(name string, created time.Time) *v1alpha1.AnalysisRun {
+	finishedAt := metav1.NewTime(timeutil.Now().Add(-time.Minute))
+	return &v1alpha1.AnalysisRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "test",
+			CreationTimestamp: metav1.NewTime(created),
+			Labels:            map[string]string{v1alpha1.RolloutTypeLabel: v1alpha1.RolloutTypeStepLabel},
+			Annotations:       map[string]string{"rollout.argoproj.io/revision": "2"},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "argoproj.io/v1alpha1",
+				Kind:       "Rollout",
+				Name:       "guestbook",
+				UID:        "rollout-uid",
+				Controller: ptr.To(true),
+			}},
+		},
+		Spec: v1alpha1.AnalysisRunSpec{
+			Args: []v1alpha1.Argument{{Name: "service-name", Value: ptr.To("guestbook")}},
+			Metrics: []v1alpha1.Metric{
+				{
+					Name:             "success-rate",
+					SuccessCondition: "result[0] >= 0.95",
+					Provider:         v1alpha1.MetricProvider{Prometheus: &v1alpha1.PrometheusMetric{}},
+				},
+				{
+					Name:     "integration-test",
+					Provider: v1alpha1.MetricProvider{Job: &v1alpha1.JobMetric{}},
+				},
+			},
+		},
+		Status: v1alpha1.AnalysisRunStatus{
+			Phase:   v1alpha1.AnalysisPhaseFailed,
+			Message: "Metric \"success-rate\" assessed Failed due to failed (1) > failureLimit (0)",
+			MetricResults: []v1alpha1.MetricResult{
+				{
+					Name:       "success-rate",
+					Phase:      v1alpha1.AnalysisPhaseFailed,
+					Successful: 1,
+					Failed:     1,
+					Measurements: []v1alpha1.Measurement{
+						{Phase: v1alpha1.AnalysisPhaseSuccessful, Value: "[0.99]", FinishedAt: &finishedAt},
+						{Phase: v1alpha1.AnalysisPhaseFailed, Value: "[0.42]", FinishedAt: &finishedAt},
+					},
+				},
+				{
+					Name:       "integration-test",
+					Phase:      v1alpha1.AnalysisPhaseSuccessful,
+					Successful: 2,
+					Measurements: []v1alpha1.Measurement{
+						{Phase: v1alpha1.AnalysisPhaseSuccessful, Metadata: map[string]string{job.JobNameKey: "integration-test-1"}},
+						{Phase: v1alpha1.AnalysisPhaseSuccessful, Metadata: map[string]string{job.JobNameKey: "integration-test-2"}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newJobPod(jobName string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName + "-pod",
+			Namespace: "test",
+			Labels:    map[string]string{"job-name": jobName},
+		},
+	}
+}
+
+func runAnalysisCmd(t *testing.T, args []string, objs ...runtime.Object) (string, string, error) {
+	tf, o := options.NewFakeArgoRolloutsOptions(objs...)
+	defer tf.Cleanup()
+	o.RESTClientGetter = tf.WithNamespace("test")
+	cmd := NewCmdAnalysis(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return o.Out.(*bytes.Buffer).String(), o.ErrOut.(*bytes.Buffer).String(), err
+}
+
+func TestAnalysisCmdUsage(t *testing.T) {
+	_, stderr, err := runAnalysisCmd(t, []string{})
+	assert.Error(t, err)
+	assert.Contains(t, stderr, "Usage:")
+	assert.Contains(t, stderr, "analysis COMMAND")
+}
+
+func TestAnalysisList(t *testing.T) {
+	now := timeutil.Now()
+	older := newAnalysisRun("guestbook-older", now.Add(-time.Hour))
+	newer := newAnalysisRun("guestbook-newer", now.Add(-time.Minute))
+	other := newAnalysisRun("other", now)
+	other.OwnerReferences[0].UID = "other-uid"
+
+	stdout, stderr, err := runAnalysisCmd(t, []string{"list", "guestbook"}, newRollout(), older, newer, other)
+	assert.NoError(t, err)
+	assert.Empty(t, stderr)
+	expected := `NAME             TYPE  REVISION  STATUS  SUCCESSFUL  FAILED  INCONCLUSIVE  ERROR  AGE
+guestbook-newer  Step  2         Failed  3           1       0             0      60s
+guestbook-older  Step  2         Failed  3           1       0             0      60m
+`
+	assert.Equal(t, expected, stdout)
+}
+
+func TestAnalysisListEmpty(t *testing.T) {
+	stdout, stderr, err := runAnalysisCmd(t, []string{"list", "guestbook"}, newRollout())
+	assert.NoError(t, err)
+	assert.Empty(t, stdout)
+	assert.Equal(t, "No resources found.\n", stderr)
+}
+
+func TestAnalysisGet(t *testing.T) {
+	run := newAnalysisRun("guestbook-run", timeutil.Now())
+	stdout, _, err := runAnalysisCmd(t, []string{"get", "guestbook-run"}, run)
+	assert.NoError(t, err)
+	expected := `Name:       guestbook-run
+Namespace:  test
+Status:     Failed
+Message:    Metric "success-rate" assessed Failed due to failed (1) > failureLimit (0)
+Args:       service-name=guestbook
+
+METRIC            STATUS      SUCCESS CONDITION  FAILURE CONDITION  COUNT  SUCCESSFUL  FAILED  INCONCLUSIVE  ERROR
+success-rate      Failed      result[0] >= 0.95  -                  -      1           1       0             0
+integration-test  Successful  -                  -                  -      2           0       0             0
+
+METRIC            #  STATUS      VALUE   FINISHED  MESSAGE
+success-rate      1  Successful  [0.99]  60s ago   -
+success-rate      2  Failed      [0.42]  60s ago   -
+integration-test  1  Successful  -       -         -
+integration-test  2  Successful  -       -         -
+`
+	assert.Equal(t, expected, stdout)
+
+	stdout, _, err = runAnalysisCmd(t, []string{"get", "guestbook-run", "--metric", "success-rate"}, run)
+	assert.NoError(t, err)
+	assert.NotContains(t, stdout, "integration-test")
+
+	_, _, err = runAnalysisCmd(t, []string{"get", "guestbook-run", "--metric", "latency"}, run)
+	assert.EqualError(t, err, "analysis run 'guestbook-run' has no metric 'latency'")
+}
+
+func TestAnalysisLogs(t *testing.T) {
+	run := newAnalysisRun("guestbook-run", timeutil.Now())
+	stdout, _, err := runAnalysisCmd(t, []string{"logs", "guestbook-run"}, run, newJobPod("integration-test-2"))
+	assert.NoError(t, err)
+	expected := `==> metric integration-test, measurement 1: no pods found for job integration-test-1 <==
+==> metric integration-test, measurement 2: pod integration-test-2-pod <==
+fake logs
+`
+	assert.Equal(t, expected, stdout)
+
+	stdout, _, err = runAnalysisCmd(t, []string{"logs", "guestbook-run", "--latest"}, run, newJobPod("integration-test-2"))
+	assert.NoError(t, err)
+	assert.Equal(t, "==> metric integration-test, measurement 2: pod integration-test-2-pod <==\nfake logs\n", stdout)
+
+	_, _, err = runAnalysisCmd(t, []string{"logs", "guestbook-run", "--metric", "success-rate"}, run)
+	assert.EqualError(t, err, "metric 'success-rate' of analysis run 'guestbook-run' is not a job metric")
+}
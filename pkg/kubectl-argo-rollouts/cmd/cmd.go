@@ -1,27 +1,33 @@
 package cmd
 
 import (
-	notificationcmd "github.com/argoproj/notifications-engine/pkg/cmd"
-
-	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd/abort"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd/analysis"
 	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd/completion"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd/convert"
 	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd/create"
 	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd/dashboard"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd/extend"
 	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd/get"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd/history"
 	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd/lint"
 	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd/list"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd/notifications"
 	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd/pause"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd/plugin"
 	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd/promote"
 	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd/restart"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd/resume"
 	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd/retry"
 	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd/set"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd/simulate"
 	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd/status"
 	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd/terminate"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd/top"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd/trace"
 	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd/undo"
 	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd/version"
 	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
-	"github.com/argoproj/argo-rollouts/utils/record"
 
 	"github.com/spf13/cobra"
 )
@@ -59,9 +65,15 @@ func NewCmdArgoRollouts(o *options.ArgoRolloutsOptions) *cobra.Command {
 	}
 
 	o.AddKubectlFlags(cmd)
+	cmd.AddCommand(convert.NewCmdConvert(o))
+	cmd.AddCommand(analysis.NewCmdAnalysis(o))
 	cmd.AddCommand(create.NewCmdCreate(o))
 	cmd.AddCommand(get.NewCmdGet(o))
+	cmd.AddCommand(history.NewCmdHistory(o))
+	cmd.AddCommand(trace.NewCmdTrace(o))
+	cmd.AddCommand(top.NewCmdTop(o))
 	cmd.AddCommand(lint.NewCmdLint(o))
+	cmd.AddCommand(simulate.NewCmdSimulate(o))
 	cmd.AddCommand(list.NewCmdList(o))
 	cmd.AddCommand(pause.NewCmdPause(o))
 	cmd.AddCommand(promote.NewCmdPromote(o))
@@ -70,11 +82,15 @@ func NewCmdArgoRollouts(o *options.ArgoRolloutsOptions) *cobra.Command {
 	cmd.AddCommand(abort.NewCmdAbort(o))
 	cmd.AddCommand(retry.NewCmdRetry(o))
 	cmd.AddCommand(terminate.NewCmdTerminate(o))
+	cmd.AddCommand(resume.NewCmdResume(o))
+	cmd.AddCommand(extend.NewCmdExtend(o))
 	cmd.AddCommand(set.NewCmdSet(o))
 	cmd.AddCommand(undo.NewCmdUndo(o))
+	cmd.AddCommand(undo.NewCmdRollback(o))
 	cmd.AddCommand(dashboard.NewCmdDashboard(o))
 	cmd.AddCommand(status.NewCmdStatus(o))
-	cmd.AddCommand(notificationcmd.NewToolsCommand("notifications", "kubectl argo rollouts notifications", v1alpha1.RolloutGVR, record.NewAPIFactorySettings(nil)))
+	cmd.AddCommand(notifications.NewCmdNotifications(o))
+	cmd.AddCommand(plugin.NewCmdPlugin(o))
 	cmd.AddCommand(completion.NewCmdCompletion(o))
 
 	return cmd
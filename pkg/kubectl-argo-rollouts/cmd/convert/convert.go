@@ -0,0 +1,325 @@
+package convert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
+	"github.com/argoproj/argo-rollouts/utils/annotations"
+)
+
+const (
+	convertExample = `
+	# Convert a Deployment to a Rollout with the canary strategy
+	%[1]s convert deployment/guestbook --strategy canary
+
+	# Convert a Deployment to a blue-green Rollout which references the Deployment and scales it down once healthy
+	%[1]s convert deployment/guestbook --strategy bluegreen --active-service guestbook --workload-ref --scale-down onsuccess
+
+	# Convert a Deployment manifest to a Rollout
+	%[1]s convert -f guestbook-deployment.yaml --strategy canary
+
+	# Convert a Rollout back to a Deployment
+	%[1]s convert rollout/guestbook`
+)
+
+const (
+	strategyCanary    = "canary"
+	strategyBlueGreen = "bluegreen"
+
+	deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+)
+
+// annotations of the live objects which are not carried over to the converted object
+var skippedAnnotations = []string{
+	deploymentRevisionAnnotation,
+	annotations.RevisionAnnotation,
+	corev1.LastAppliedConfigAnnotation,
+}
+
+type ConvertOptions struct {
+	options.ArgoRolloutsOptions
+
+	File           string
+	Strategy       string
+	WorkloadRef    bool
+	ScaleDown      string
+	ActiveService  string
+	PreviewService string
+	Output         string
+}
+
+// NewCmdConvert returns a new instance of a `rollouts convert` command
+func NewCmdConvert(o *options.ArgoRolloutsOptions) *cobra.Command {
+	convertOptions := ConvertOptions{
+		ArgoRolloutsOptions: *o,
+		Strategy:            strategyCanary,
+		Output:              "yaml",
+	}
+	var cmd = &cobra.Command{
+		Use:   "convert (deployment/DEPLOYMENT_NAME | rollout/ROLLOUT_NAME | -f FILENAME)",
+		Short: "Convert a Deployment to a Rollout or a Rollout to a Deployment",
+		Long: "This command converts a Deployment to a Rollout, optionally referencing the Deployment with workloadRef, " +
+			"or a Rollout back to a Deployment. The converted resource is printed so that it can be reviewed and applied.",
+		Example:      o.Example(convertExample),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if (len(args) == 1) == (convertOptions.File != "") || len(args) > 1 {
+				return o.UsageErr(c)
+			}
+			if convertOptions.Output != "yaml" && convertOptions.Output != "json" {
+				return fmt.Errorf("unknown output format '%s', must be one of: json, yaml", convertOptions.Output)
+			}
+			obj, err := convertOptions.getObject(args)
+			if err != nil {
+				return err
+			}
+			converted, err := convertOptions.convert(obj)
+			if err != nil {
+				return err
+			}
+			return printObject(convertOptions.Out, converted, convertOptions.Output)
+		},
+	}
+	cmd.Flags().StringVarP(&convertOptions.File, "filename", "f", "", "File of the Deployment or Rollout to convert")
+	cmd.Flags().StringVar(&convertOptions.Strategy, "strategy", convertOptions.Strategy, "Strategy of the Rollout converted from a Deployment: canary or bluegreen")
+	cmd.Flags().BoolVar(&convertOptions.WorkloadRef, "workload-ref", false, "Reference the Deployment from the Rollout with workloadRef instead of copying its pod template")
+	cmd.Flags().StringVar(&convertOptions.ScaleDown, "scale-down", "", "How the referenced Deployment is scaled down with --workload-ref: never, onsuccess or progressively")
+	cmd.Flags().StringVar(&convertOptions.ActiveService, "active-service", "", "Active service of a blue-green Rollout")
+	cmd.Flags().StringVar(&convertOptions.PreviewService, "preview-service", "", "Preview service of a blue-green Rollout")
+	cmd.Flags().StringVarP(&convertOptions.Output, "output", "o", convertOptions.Output, "Output format: yaml or json")
+	return cmd
+}
+
+// getObject returns the Deployment or Rollout to convert, either from the file or from the cluster
+func (c *ConvertOptions) getObject(args []string) (runtime.Object, error) {
+	if c.File != "" {
+		return readObject(c.File)
+	}
+	kind, name, ok := strings.Cut(args[0], "/")
+	if !ok || name == "" {
+		return nil, fmt.Errorf("invalid resource '%s', must be deployment/NAME or rollout/NAME", args[0])
+	}
+	ctx := context.TODO()
+	switch strings.ToLower(kind) {
+	case "deployment", "deployments", "deploy":
+		return c.KubeClientset().AppsV1().Deployments(c.Namespace()).Get(ctx, name, metav1.GetOptions{})
+	case "rollout", "rollouts", "ro":
+		return c.RolloutsClientset().ArgoprojV1alpha1().Rollouts(c.Namespace()).Get(ctx, name, metav1.GetOptions{})
+	}
+	return nil, fmt.Errorf("cannot convert resources of type '%s', must be deployment or rollout", kind)
+}
+
+func readObject(path string) (runtime.Object, error) {
+	fileBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var un unstructured.Unstructured
+	if err := yaml.Unmarshal(fileBytes, &un.Object); err != nil {
+		return nil, err
+	}
+	gvk := un.GroupVersionKind()
+	switch {
+	case gvk.Group == appsv1.GroupName && gvk.Kind == "Deployment":
+		var deploy appsv1.Deployment
+		if err := yaml.UnmarshalStrict(fileBytes, &deploy); err != nil {
+			return nil, err
+		}
+		return &deploy, nil
+	case gvk.Group == rollouts.Group && gvk.Kind == rollouts.RolloutKind:
+		var ro v1alpha1.Rollout
+		if err := yaml.UnmarshalStrict(fileBytes, &ro); err != nil {
+			return nil, err
+		}
+		return &ro, nil
+	}
+	return nil, fmt.Errorf("cannot convert resources of kind '%s', must be Deployment or Rollout", gvk.Kind)
+}
+
+func (c *ConvertOptions) convert(obj runtime.Object) (runtime.Object, error) {
+	switch typed := obj.(type) {
+	case *appsv1.Deployment:
+		return DeploymentToRollout(typed, c.Strategy, c.WorkloadRef, c.ScaleDown, c.ActiveService, c.PreviewService)
+	case *v1alpha1.Rollout:
+		if typed.Spec.WorkloadRef == nil {
+			return RolloutToDeployment(typed, nil)
+		}
+		if c.File != "" {
+			return nil, errors.New("cannot convert a Rollout referencing a workload from a file, convert the live Rollout instead")
+		}
+		if typed.Spec.WorkloadRef.Kind != "Deployment" {
+			return nil, fmt.Errorf("cannot convert a Rollout referencing a workload of kind '%s'", typed.Spec.WorkloadRef.Kind)
+		}
+		deploy, err := c.KubeClientset().AppsV1().Deployments(typed.Namespace).Get(context.TODO(), typed.Spec.WorkloadRef.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return RolloutToDeployment(typed, deploy)
+	}
+	return nil, fmt.Errorf("cannot convert objects of type %T", obj)
+}
+
+// DeploymentToRollout returns a Rollout with the given strategy which replaces the Deployment. With workloadRef, the
+// Rollout references the Deployment instead of copying its pod template.
+func DeploymentToRollout(deploy *appsv1.Deployment, strategy string, workloadRef bool, scaleDown, activeService, previewService string) (*v1alpha1.Rollout, error) {
+	if scaleDown != "" && !workloadRef {
+		return nil, errors.New("--scale-down requires --workload-ref")
+	}
+	switch scaleDown {
+	case "", v1alpha1.ScaleDownNever, v1alpha1.ScaleDownOnSuccess, v1alpha1.ScaleDownProgressively:
+	default:
+		return nil, fmt.Errorf("invalid scale down '%s', must be one of: %s, %s, %s", scaleDown, v1alpha1.ScaleDownNever, v1alpha1.ScaleDownOnSuccess, v1alpha1.ScaleDownProgressively)
+	}
+	if strategy != strategyBlueGreen && (activeService != "" || previewService != "") {
+		return nil, errors.New("--active-service and --preview-service require --strategy bluegreen")
+	}
+
+	ro := &v1alpha1.Rollout{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       rollouts.RolloutKind,
+		},
+		ObjectMeta: convertedObjectMeta(deploy.ObjectMeta),
+		Spec: v1alpha1.RolloutSpec{
+			Replicas:                deploy.Spec.Replicas,
+			Selector:                deploy.Spec.Selector.DeepCopy(),
+			MinReadySeconds:         deploy.Spec.MinReadySeconds,
+			RevisionHistoryLimit:    deploy.Spec.RevisionHistoryLimit,
+			ProgressDeadlineSeconds: deploy.Spec.ProgressDeadlineSeconds,
+		},
+	}
+	if workloadRef {
+		ro.Spec.WorkloadRef = &v1alpha1.ObjectRef{
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+			Kind:       "Deployment",
+			Name:       deploy.Name,
+			ScaleDown:  scaleDown,
+		}
+	} else {
+		ro.Spec.Template = *deploy.Spec.Template.DeepCopy()
+	}
+
+	switch strategy {
+	case strategyCanary:
+		ro.Spec.Strategy.Canary = &v1alpha1.CanaryStrategy{}
+		if rollingUpdate := deploy.Spec.Strategy.RollingUpdate; rollingUpdate != nil {
+			ro.Spec.Strategy.Canary.MaxSurge = rollingUpdate.MaxSurge
+			ro.Spec.Strategy.Canary.MaxUnavailable = rollingUpdate.MaxUnavailable
+		}
+	case strategyBlueGreen:
+		if activeService == "" {
+			return nil, errors.New("--active-service is required with --strategy bluegreen")
+		}
+		ro.Spec.Strategy.BlueGreen = &v1alpha1.BlueGreenStrategy{
+			ActiveService:  activeService,
+			PreviewService: previewService,
+		}
+	default:
+		return nil, fmt.Errorf("invalid strategy '%s', must be one of: %s, %s", strategy, strategyCanary, strategyBlueGreen)
+	}
+	return ro, nil
+}
+
+// RolloutToDeployment returns a Deployment with a rolling update strategy which replaces the Rollout. The pod template
+// is taken from the referenced Deployment if the Rollout references one.
+func RolloutToDeployment(ro *v1alpha1.Rollout, referenced *appsv1.Deployment) (*appsv1.Deployment, error) {
+	deploy := &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+			Kind:       "Deployment",
+		},
+		ObjectMeta: convertedObjectMeta(ro.ObjectMeta),
+		Spec: appsv1.DeploymentSpec{
+			Replicas:                ro.Spec.Replicas,
+			Selector:                ro.Spec.Selector.DeepCopy(),
+			Template:                *ro.Spec.Template.DeepCopy(),
+			MinReadySeconds:         ro.Spec.MinReadySeconds,
+			RevisionHistoryLimit:    ro.Spec.RevisionHistoryLimit,
+			ProgressDeadlineSeconds: ro.Spec.ProgressDeadlineSeconds,
+			Strategy: appsv1.DeploymentStrategy{
+				Type: appsv1.RollingUpdateDeploymentStrategyType,
+			},
+		},
+	}
+	if referenced != nil {
+		// the referenced Deployment was possibly scaled down by the rollout, so that only its template is kept
+		deploy.ObjectMeta = convertedObjectMeta(referenced.ObjectMeta)
+		deploy.Spec.Template = *referenced.Spec.Template.DeepCopy()
+		if deploy.Spec.Selector == nil {
+			deploy.Spec.Selector = referenced.Spec.Selector.DeepCopy()
+		}
+	}
+	delete(deploy.Spec.Template.Labels, v1alpha1.DefaultRolloutUniqueLabelKey)
+	if deploy.Spec.Selector == nil {
+		return nil, errors.New("cannot convert a Rollout without selector")
+	}
+	if canary := ro.Spec.Strategy.Canary; canary != nil && (canary.MaxSurge != nil || canary.MaxUnavailable != nil) {
+		deploy.Spec.Strategy.RollingUpdate = &appsv1.RollingUpdateDeployment{
+			MaxSurge:       canary.MaxSurge,
+			MaxUnavailable: canary.MaxUnavailable,
+		}
+	}
+	return deploy, nil
+}
+
+// convertedObjectMeta returns the metadata of the converted object, without the fields set by the cluster
+func convertedObjectMeta(meta metav1.ObjectMeta) metav1.ObjectMeta {
+	converted := metav1.ObjectMeta{
+		Name:        meta.Name,
+		Namespace:   meta.Namespace,
+		Labels:      meta.Labels,
+		Annotations: map[string]string{},
+	}
+	for k, v := range meta.Annotations {
+		converted.Annotations[k] = v
+	}
+	for _, k := range skippedAnnotations {
+		delete(converted.Annotations, k)
+	}
+	if len(converted.Annotations) == 0 {
+		converted.Annotations = nil
+	}
+	return converted
+}
+
+// printObject prints the object without its status and creation timestamps
+func printObject(out io.Writer, obj runtime.Object, format string) error {
+	un, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	unstructured.RemoveNestedField(un, "status")
+	unstructured.RemoveNestedField(un, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(un, "spec", "template", "metadata", "creationTimestamp")
+	if ro, ok := obj.(*v1alpha1.Rollout); ok && ro.Spec.WorkloadRef != nil {
+		unstructured.RemoveNestedField(un, "spec", "template")
+	}
+	var data []byte
+	if format == "json" {
+		data, err = json.MarshalIndent(un, "", "  ")
+		data = append(data, '\n')
+	} else {
+		data, err = yaml.Marshal(un)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(data)
+	return err
+}
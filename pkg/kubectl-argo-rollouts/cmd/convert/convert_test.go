@@ -0,0 +1,179 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	options "github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options/fake"
+)
+
+func newDeployment() *appsv1.Deployment {
+	maxSurge := intstr.FromString("50%")
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "guestbook",
+			Namespace: "test",
+			Labels:    map[string]string{"app": "guestbook"},
+			Annotations: map[string]string{
+				"deployment.kubernetes.io/revision": "4",
+				"team":                              "guestbook",
+			},
+			ResourceVersion: "123",
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To[int32](3),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "guestbook"}},
+			Strategy: appsv1.DeploymentStrategy{
+				Type:          appsv1.RollingUpdateDeploymentStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDeployment{MaxSurge: &maxSurge},
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "guestbook"}},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "guestbook", Image: "argoproj/rollouts-demo:blue"}},
+				},
+			},
+		},
+		Status: appsv1.DeploymentStatus{Replicas: 3},
+	}
+}
+
+func runConvert(t *testing.T, args []string, objs ...runtime.Object) (string, error) {
+	tf, o := options.NewFakeArgoRolloutsOptions(objs...)
+	defer tf.Cleanup()
+	o.RESTClientGetter = tf.WithNamespace("test")
+	cmd := NewCmdConvert(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return o.Out.(*bytes.Buffer).String(), err
+}
+
+func TestConvertCmdUsage(t *testing.T) {
+	tf, o := options.NewFakeArgoRolloutsOptions()
+	defer tf.Cleanup()
+	cmd := NewCmdConvert(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{})
+	err := cmd.Execute()
+	assert.Error(t, err)
+	stderr := o.ErrOut.(*bytes.Buffer).String()
+	assert.Contains(t, stderr, "Usage:")
+	assert.Contains(t, stderr, "convert (deployment/DEPLOYMENT_NAME | rollout/ROLLOUT_NAME | -f FILENAME)")
+}
+
+func TestConvertDeploymentToCanaryRollout(t *testing.T) {
+	out, err := runConvert(t, []string{"deployment/guestbook", "--strategy", "canary"}, newDeployment())
+	require.NoError(t, err)
+	assert.NotContains(t, out, "status")
+	assert.NotContains(t, out, "creationTimestamp")
+
+	var ro v1alpha1.Rollout
+	require.NoError(t, yaml.UnmarshalStrict([]byte(out), &ro))
+	assert.Equal(t, "Rollout", ro.Kind)
+	assert.Equal(t, "argoproj.io/v1alpha1", ro.APIVersion)
+	assert.Equal(t, "guestbook", ro.Name)
+	assert.Empty(t, ro.ResourceVersion)
+	assert.Equal(t, map[string]string{"team": "guestbook"}, ro.Annotations)
+	assert.Equal(t, int32(3), *ro.Spec.Replicas)
+	assert.Equal(t, "argoproj/rollouts-demo:blue", ro.Spec.Template.Spec.Containers[0].Image)
+	assert.Nil(t, ro.Spec.WorkloadRef)
+	require.NotNil(t, ro.Spec.Strategy.Canary)
+	assert.Equal(t, "50%", ro.Spec.Strategy.Canary.MaxSurge.String())
+	assert.Nil(t, ro.Spec.Strategy.BlueGreen)
+}
+
+func TestConvertDeploymentToBlueGreenRolloutWithWorkloadRef(t *testing.T) {
+	out, err := runConvert(t, []string{"deploy/guestbook", "--strategy", "bluegreen", "--active-service", "guestbook-active", "--workload-ref", "--scale-down", "onsuccess"}, newDeployment())
+	require.NoError(t, err)
+	assert.NotContains(t, out, "template")
+
+	var ro v1alpha1.Rollout
+	require.NoError(t, yaml.UnmarshalStrict([]byte(out), &ro))
+	assert.Equal(t, &v1alpha1.ObjectRef{APIVersion: "apps/v1", Kind: "Deployment", Name: "guestbook", ScaleDown: "onsuccess"}, ro.Spec.WorkloadRef)
+	require.NotNil(t, ro.Spec.Strategy.BlueGreen)
+	assert.Equal(t, "guestbook-active", ro.Spec.Strategy.BlueGreen.ActiveService)
+	assert.Nil(t, ro.Spec.Strategy.Canary)
+}
+
+func TestConvertDeploymentFromFile(t *testing.T) {
+	out, err := runConvert(t, []string{"-f", "testdata/deployment.yaml", "-o", "json"})
+	require.NoError(t, err)
+
+	var ro v1alpha1.Rollout
+	require.NoError(t, json.Unmarshal([]byte(out), &ro))
+	assert.Equal(t, "guestbook", ro.Name)
+	assert.Equal(t, intstr.FromInt(1), *ro.Spec.Strategy.Canary.MaxSurge)
+	assert.Equal(t, intstr.FromInt(0), *ro.Spec.Strategy.Canary.MaxUnavailable)
+}
+
+func TestConvertRolloutToDeployment(t *testing.T) {
+	deploy := newDeployment()
+	ro, err := DeploymentToRollout(deploy, "canary", false, "", "", "")
+	require.NoError(t, err)
+	ro.Spec.Template.Labels[v1alpha1.DefaultRolloutUniqueLabelKey] = "abc123"
+	ro.Annotations = map[string]string{"rollout.argoproj.io/revision": "2"}
+
+	out, err := runConvert(t, []string{"rollout/guestbook"}, ro)
+	require.NoError(t, err)
+
+	var converted appsv1.Deployment
+	require.NoError(t, yaml.UnmarshalStrict([]byte(out), &converted))
+	assert.Equal(t, "Deployment", converted.Kind)
+	assert.Equal(t, "apps/v1", converted.APIVersion)
+	assert.Empty(t, converted.Annotations)
+	assert.Equal(t, map[string]string{"app": "guestbook"}, converted.Spec.Template.Labels)
+	assert.Equal(t, appsv1.RollingUpdateDeploymentStrategyType, converted.Spec.Strategy.Type)
+	assert.Equal(t, "50%", converted.Spec.Strategy.RollingUpdate.MaxSurge.String())
+}
+
+func TestConvertRolloutWithWorkloadRefToDeployment(t *testing.T) {
+	deploy := newDeployment()
+	deploy.Spec.Replicas = ptr.To[int32](0)
+	ro, err := DeploymentToRollout(deploy, "canary", true, "progressively", "", "")
+	require.NoError(t, err)
+	ro.Spec.Replicas = ptr.To[int32](5)
+
+	out, err := runConvert(t, []string{"rollout/guestbook"}, ro, deploy)
+	require.NoError(t, err)
+
+	var converted appsv1.Deployment
+	require.NoError(t, yaml.UnmarshalStrict([]byte(out), &converted))
+	assert.Equal(t, int32(5), *converted.Spec.Replicas)
+	assert.Equal(t, map[string]string{"team": "guestbook"}, converted.Annotations)
+	assert.Equal(t, "argoproj/rollouts-demo:blue", converted.Spec.Template.Spec.Containers[0].Image)
+}
+
+func TestConvertErrors(t *testing.T) {
+	tests := []struct {
+		args   []string
+		errmsg string
+	}{
+		{[]string{"deployment/guestbook", "--strategy", "bluegreen"}, "--active-service is required with --strategy bluegreen"},
+		{[]string{"deployment/guestbook", "--active-service", "guestbook"}, "--active-service and --preview-service require --strategy bluegreen"},
+		{[]string{"deployment/guestbook", "--scale-down", "onsuccess"}, "--scale-down requires --workload-ref"},
+		{[]string{"deployment/guestbook", "--workload-ref", "--scale-down", "later"}, "invalid scale down 'later', must be one of: never, onsuccess, progressively"},
+		{[]string{"deployment/guestbook", "--strategy", "recreate"}, "invalid strategy 'recreate', must be one of: canary, bluegreen"},
+		{[]string{"deployment/guestbook", "-o", "wide"}, "unknown output format 'wide', must be one of: json, yaml"},
+		{[]string{"statefulset/guestbook"}, "cannot convert resources of type 'statefulset', must be deployment or rollout"},
+		{[]string{"guestbook"}, "invalid resource 'guestbook', must be deployment/NAME or rollout/NAME"},
+	}
+	for _, test := range tests {
+		t.Run(test.errmsg, func(t *testing.T) {
+			_, err := runConvert(t, test.args, newDeployment())
+			assert.EqualError(t, err, test.errmsg)
+		})
+	}
+}
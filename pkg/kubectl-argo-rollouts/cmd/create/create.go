@@ -7,7 +7,10 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/spf13/cobra"
@@ -41,9 +44,12 @@ type CreateAnalysisRunOptions struct {
 	GenerateName string
 	InstanceID   string
 	ArgFlags     []string
+	ArgsFile     string
 	From         string
 	FromFile     string
 	Global       bool
+	Wait         bool
+	Timeout      time.Duration
 }
 
 const (
@@ -62,9 +68,15 @@ const (
   	%[1]s create analysisrun --global --from my-analysis-cluster-template.yaml
 
   	# Create an AnalysisRun from a ClusterAnalysisTemplate in the cluster
-  	%[1]s create analysisrun --global --from my-analysis-cluster-template`
+  	%[1]s create analysisrun --global --from my-analysis-cluster-template
+
+  	# Create an AnalysisRun with the arguments of a file, and wait until it completes
+  	%[1]s create analysisrun --from my-analysis-template --args-file args.yaml --wait --timeout 10m`
 )
 
+// analysisRunWaitPollInterval is the interval at which the phase of an AnalysisRun is checked when waiting for it
+var analysisRunWaitPollInterval = 2 * time.Second
+
 // NewCmdCreate returns a new instance of an `rollouts create` command
 func NewCmdCreate(o *options.ArgoRolloutsOptions) *cobra.Command {
 	createOptions := CreateOptions{
@@ -255,10 +267,15 @@ func NewCmdCreateAnalysisRun(o *options.ArgoRolloutsOptions) *cobra.Command {
 			if froms != 1 {
 				return fmt.Errorf("one of --from or --from-file must be specified")
 			}
-			templateArgs, err := createOptions.ParseArgFlags()
+			templateArgs, err := createOptions.ParseArgsFile()
+			if err != nil {
+				return err
+			}
+			flagArgs, err := createOptions.ParseArgFlags()
 			if err != nil {
 				return err
 			}
+			templateArgs = overrideArgs(templateArgs, flagArgs)
 			var templateName string
 			var obj *unstructured.Unstructured
 
@@ -314,19 +331,67 @@ func NewCmdCreateAnalysisRun(o *options.ArgoRolloutsOptions) *cobra.Command {
 				return err
 			}
 			fmt.Fprintf(createOptions.Out, "analysisrun.argoproj.io/%s created\n", obj.GetName())
-			return nil
+			if !createOptions.Wait {
+				return nil
+			}
+			if createOptions.Timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, createOptions.Timeout)
+				defer cancel()
+			}
+			return createOptions.waitForAnalysisRun(ctx, obj.GetName())
 		},
 	}
 	cmd.Flags().StringVar(&createOptions.Name, "name", "", "Use the specified name for the run")
 	cmd.Flags().StringVar(&createOptions.GenerateName, "generate-name", "", "Use the specified generateName for the run")
 	cmd.Flags().StringVar(&createOptions.InstanceID, "instance-id", "", "Instance-ID for the AnalysisRun")
 	cmd.Flags().StringArrayVarP(&createOptions.ArgFlags, "argument", "a", []string{}, "Arguments to the parameter template")
+	cmd.Flags().StringVar(&createOptions.ArgsFile, "args-file", "", "YAML or JSON file with the arguments to the parameter template, as a map of names to values or as a list of arguments. Arguments of --argument take precedence")
 	cmd.Flags().StringVar(&createOptions.From, "from", "", "Create an AnalysisRun from an AnalysisTemplate or ClusterAnalysisTemplate in the cluster")
 	cmd.Flags().StringVar(&createOptions.FromFile, "from-file", "", "Create an AnalysisRun from an AnalysisTemplate or ClusterAnalysisTemplate in a local file")
 	cmd.Flags().BoolVar(&createOptions.Global, "global", false, "Use a ClusterAnalysisTemplate instead of a AnalysisTemplate")
+	cmd.Flags().BoolVar(&createOptions.Wait, "wait", false, "Wait until the AnalysisRun completes, and return an error unless it is successful")
+	cmd.Flags().DurationVar(&createOptions.Timeout, "timeout", 0, "The length of time to wait for the AnalysisRun before giving up. Zero means wait forever")
 	return cmd
 }
 
+// waitForAnalysisRun blocks until the AnalysisRun completes, printing its phase whenever it changes. Returns an error
+// if the AnalysisRun did not succeed or the context is done.
+func (c *CreateAnalysisRunOptions) waitForAnalysisRun(ctx context.Context, name string) error {
+	ticker := time.NewTicker(analysisRunWaitPollInterval)
+	defer ticker.Stop()
+	arIf := c.DynamicClient.Resource(v1alpha1.AnalysisRunGVR).Namespace(c.Namespace())
+	var prevPhase v1alpha1.AnalysisPhase
+	for {
+		obj, err := arIf.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		var ar v1alpha1.AnalysisRun
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &ar); err != nil {
+			return err
+		}
+		if ar.Status.Phase != prevPhase && ar.Status.Phase != "" {
+			fmt.Fprintf(c.Out, "analysisrun.argoproj.io/%s %s\n", name, ar.Status.Phase)
+			prevPhase = ar.Status.Phase
+		}
+		if ar.Status.Phase.Completed() {
+			if ar.Status.Phase == v1alpha1.AnalysisPhaseSuccessful {
+				return nil
+			}
+			if ar.Status.Message != "" {
+				return fmt.Errorf("AnalysisRun '%s' completed with phase %s: %s", name, ar.Status.Phase, ar.Status.Message)
+			}
+			return fmt.Errorf("AnalysisRun '%s' completed with phase %s", name, ar.Status.Phase)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for AnalysisRun '%s' to complete", name)
+		case <-ticker.C:
+		}
+	}
+}
+
 func (c *CreateAnalysisRunOptions) getAnalysisTemplate() (*unstructured.Unstructured, error) {
 	ctx := context.TODO()
 	if c.From != "" {
@@ -363,6 +428,84 @@ func (c *CreateAnalysisRunOptions) getClusterAnalysisTemplate() (*unstructured.U
 	}
 }
 
+// ParseArgsFile returns the arguments of the args file, which is either a map of argument names to values or a list
+// of arguments
+func (c *CreateAnalysisRunOptions) ParseArgsFile() ([]v1alpha1.Argument, error) {
+	if c.ArgsFile == "" {
+		return nil, nil
+	}
+	fileBytes, err := os.ReadFile(c.ArgsFile)
+	if err != nil {
+		return nil, err
+	}
+	var args []v1alpha1.Argument
+	if err := unmarshal(fileBytes, &args); err == nil {
+		for _, arg := range args {
+			if arg.Name == "" {
+				return nil, fmt.Errorf("invalid args file %s: arguments must have a name", c.ArgsFile)
+			}
+		}
+		return args, nil
+	}
+	var values map[string]any
+	if err := unmarshal(fileBytes, &values); err != nil {
+		return nil, fmt.Errorf("invalid args file %s: must be a map of argument names to values or a list of arguments", c.ArgsFile)
+	}
+	for name, value := range values {
+		strValue, err := argValueString(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid args file %s: argument %s %v", c.ArgsFile, name, err)
+		}
+		args = append(args, v1alpha1.Argument{
+			Name:  name,
+			Value: ptr.To[string](strValue),
+		})
+	}
+	sort.Slice(args, func(i, j int) bool {
+		return args[i].Name < args[j].Name
+	})
+	return args, nil
+}
+
+// argValueString returns the value of an argument of the args file as a string, so that unquoted numbers and
+// booleans can be used as values. Lists and maps are encoded as JSON.
+func argValueString(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", errors.New("has no value")
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		valueBytes, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(valueBytes), nil
+	}
+}
+
+// overrideArgs returns the arguments with the values of the overrides of the same name, followed by the other overrides
+func overrideArgs(args, overrides []v1alpha1.Argument) []v1alpha1.Argument {
+	merged := append(args[:0:0], args...)
+	for _, override := range overrides {
+		found := false
+		for i := range merged {
+			if merged[i].Name == override.Name {
+				merged[i] = override
+				found = true
+			}
+		}
+		if !found {
+			merged = append(merged, override)
+		}
+	}
+	return merged
+}
+
 func (c *CreateAnalysisRunOptions) ParseArgFlags() ([]v1alpha1.Argument, error) {
 	var args []v1alpha1.Argument
 	for _, argFlag := range c.ArgFlags {
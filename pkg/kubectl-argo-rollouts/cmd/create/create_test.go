@@ -2,16 +2,20 @@ package create
 
 import (
 	"bytes"
+	"context"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	core "k8s.io/client-go/testing"
 
 	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/utils/ptr"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	options "github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options/fake"
@@ -307,3 +311,120 @@ func TestCreateAnalysisFromClusterTemplateRunUnresolvedArg(t *testing.T) {
 	assert.Empty(t, stdout)
 	assert.Equal(t, "Error: args.foo was not resolved\n", stderr)
 }
+
+func TestCreateAnalysisRunArgsFile(t *testing.T) {
+	for _, file := range []string{"testdata/args.yaml", "testdata/args-list.json"} {
+		tf, o := options.NewFakeArgoRolloutsOptions()
+		cmd := NewCmdCreateAnalysisRun(o)
+		cmd.PersistentPreRunE = o.PersistentPreRunE
+		cmd.SetArgs([]string{"--from-file", "testdata/analysis-template.yaml", "--args-file", file, "--name", "my-run"})
+		err := cmd.Execute()
+		assert.NoError(t, err)
+		assert.Equal(t, "analysisrun.argoproj.io/my-run created\n", o.Out.(*bytes.Buffer).String())
+
+		obj, err := o.DynamicClient.Resource(v1alpha1.AnalysisRunGVR).Namespace(o.Namespace()).Get(context.TODO(), "my-run", metav1.GetOptions{})
+		assert.NoError(t, err)
+		args, _, _ := unstructured.NestedSlice(obj.Object, "spec", "args")
+		assert.Equal(t, []any{map[string]any{"name": "foo", "value": "bar"}}, args)
+		tf.Cleanup()
+	}
+}
+
+func TestCreateAnalysisRunArgsFileOverride(t *testing.T) {
+	tf, o := options.NewFakeArgoRolloutsOptions()
+	defer tf.Cleanup()
+	cmd := NewCmdCreateAnalysisRun(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{"--from-file", "testdata/analysis-template.yaml", "--args-file", "testdata/args.yaml", "-a", "foo=baz", "--name", "my-run"})
+	err := cmd.Execute()
+	assert.NoError(t, err)
+
+	obj, err := o.DynamicClient.Resource(v1alpha1.AnalysisRunGVR).Namespace(o.Namespace()).Get(context.TODO(), "my-run", metav1.GetOptions{})
+	assert.NoError(t, err)
+	args, _, _ := unstructured.NestedSlice(obj.Object, "spec", "args")
+	assert.Equal(t, []any{map[string]any{"name": "foo", "value": "baz"}}, args)
+}
+
+func TestCreateAnalysisRunInvalidArgsFile(t *testing.T) {
+	tf, o := options.NewFakeArgoRolloutsOptions()
+	defer tf.Cleanup()
+	cmd := NewCmdCreateAnalysisRun(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{"--from-file", "testdata/analysis-template.yaml", "--args-file", "testdata/args-invalid.yaml"})
+	err := cmd.Execute()
+	assert.EqualError(t, err, "invalid args file testdata/args-invalid.yaml: must be a map of argument names to values or a list of arguments")
+}
+
+func TestParseArgsFileValues(t *testing.T) {
+	c := CreateAnalysisRunOptions{ArgsFile: "testdata/args-values.yaml"}
+	args, err := c.ParseArgsFile()
+	assert.NoError(t, err)
+	assert.Equal(t, []v1alpha1.Argument{
+		{Name: "enabled", Value: ptr.To[string]("true")},
+		{Name: "foo", Value: ptr.To[string]("bar")},
+		{Name: "hosts", Value: ptr.To[string](`["a","b"]`)},
+		{Name: "port", Value: ptr.To[string]("8080")},
+		{Name: "ratio", Value: ptr.To[string]("0.5")},
+	}, args)
+
+	c = CreateAnalysisRunOptions{ArgsFile: "testdata/args-no-value.yaml"}
+	_, err = c.ParseArgsFile()
+	assert.EqualError(t, err, "invalid args file testdata/args-no-value.yaml: argument foo has no value")
+}
+
+// prependAnalysisRunPhaseReactor returns the AnalysisRun with the phases in order on each get
+func prependAnalysisRunPhaseReactor(fakeClient *dynamicfake.FakeDynamicClient, phases ...v1alpha1.AnalysisPhase) {
+	gets := 0
+	fakeClient.PrependReactor("get", "analysisruns", func(action core.Action) (bool, runtime.Object, error) {
+		phase := phases[min(gets, len(phases)-1)]
+		gets++
+		obj := &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "argoproj.io/v1alpha1",
+			"kind":       "AnalysisRun",
+			"metadata":   map[string]any{"name": "my-run", "namespace": "test"},
+			"status":     map[string]any{"phase": string(phase), "message": "metric \"pass\" assessed Failed"},
+		}}
+		return true, obj, nil
+	})
+}
+
+func TestCreateAnalysisRunWait(t *testing.T) {
+	defer func(interval time.Duration) { analysisRunWaitPollInterval = interval }(analysisRunWaitPollInterval)
+	analysisRunWaitPollInterval = time.Millisecond
+	tf, o := options.NewFakeArgoRolloutsOptions()
+	defer tf.Cleanup()
+	prependAnalysisRunPhaseReactor(o.DynamicClient.(*dynamicfake.FakeDynamicClient), v1alpha1.AnalysisPhaseRunning, v1alpha1.AnalysisPhaseSuccessful)
+	cmd := NewCmdCreateAnalysisRun(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{"--from-file", "testdata/analysis-template.yaml", "-a", "foo=bar", "--name", "my-run", "--wait"})
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	stdout := o.Out.(*bytes.Buffer).String()
+	assert.Equal(t, "analysisrun.argoproj.io/my-run created\nanalysisrun.argoproj.io/my-run Running\nanalysisrun.argoproj.io/my-run Successful\n", stdout)
+}
+
+func TestCreateAnalysisRunWaitFailed(t *testing.T) {
+	defer func(interval time.Duration) { analysisRunWaitPollInterval = interval }(analysisRunWaitPollInterval)
+	analysisRunWaitPollInterval = time.Millisecond
+	tf, o := options.NewFakeArgoRolloutsOptions()
+	defer tf.Cleanup()
+	prependAnalysisRunPhaseReactor(o.DynamicClient.(*dynamicfake.FakeDynamicClient), v1alpha1.AnalysisPhaseFailed)
+	cmd := NewCmdCreateAnalysisRun(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{"--from-file", "testdata/analysis-template.yaml", "-a", "foo=bar", "--name", "my-run", "--wait"})
+	err := cmd.Execute()
+	assert.EqualError(t, err, "AnalysisRun 'my-run' completed with phase Failed: metric \"pass\" assessed Failed")
+}
+
+func TestCreateAnalysisRunWaitTimeout(t *testing.T) {
+	defer func(interval time.Duration) { analysisRunWaitPollInterval = interval }(analysisRunWaitPollInterval)
+	analysisRunWaitPollInterval = time.Millisecond
+	tf, o := options.NewFakeArgoRolloutsOptions()
+	defer tf.Cleanup()
+	prependAnalysisRunPhaseReactor(o.DynamicClient.(*dynamicfake.FakeDynamicClient), v1alpha1.AnalysisPhaseRunning)
+	cmd := NewCmdCreateAnalysisRun(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{"--from-file", "testdata/analysis-template.yaml", "-a", "foo=bar", "--name", "my-run", "--wait", "--timeout", "10ms"})
+	err := cmd.Execute()
+	assert.EqualError(t, err, "timed out waiting for AnalysisRun 'my-run' to complete")
+}
@@ -2,11 +2,13 @@ package dashboard
 
 import (
 	"context"
+	"os"
 
 	"github.com/spf13/cobra"
 
 	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
 	"github.com/argoproj/argo-rollouts/server"
+	"github.com/argoproj/argo-rollouts/utils/certs"
 )
 
 var (
@@ -15,12 +17,35 @@ var (
 	%[1]s dashboard
 
 	# Start UI dashboard on a specific port
-	%[1]s dashboard --port 8080`
+	%[1]s dashboard --port 8080
+
+	# Start UI dashboard which only shows the rollouts of some namespaces, without allowing any action on them
+	%[1]s dashboard --read-only --allowed-namespaces team-a,team-b
+
+	# Start UI dashboard linking the pods to their logs in Grafana
+	%[1]s dashboard --pod-log-url-template 'https://grafana.example.com/explore?query={namespace="${metadata.namespace}",pod="${metadata.name}"}'
+
+	# Start UI dashboard over HTTPS with a self-signed certificate for the argo-rollouts-dashboard Service
+	%[1]s dashboard --self-signed-tls
+
+	# Start UI dashboard requiring the users to log in with an OIDC provider
+	ARGO_ROLLOUTS_OIDC_CLIENT_SECRET=... %[1]s dashboard --oidc-issuer-url https://accounts.example.com \
+	  --oidc-client-id argo-rollouts --oidc-redirect-url https://rollouts.example.com/rollouts/auth/callback`
+
+	// oidcClientSecretEnv is the environment variable of the OIDC client secret, so that it is not part of the command line
+	oidcClientSecretEnv = "ARGO_ROLLOUTS_OIDC_CLIENT_SECRET"
 )
 
 func NewCmdDashboard(o *options.ArgoRolloutsOptions) *cobra.Command {
 	var rootPath string
 	var port int
+	var auth server.AuthOptions
+	var readOnly bool
+	var allowedNamespaces []string
+	var podLogURLTemplate string
+	var selfSignedTLS bool
+	var selfSignedTLSConfig certs.Config
+	var selfSignedTLSService string
 	var cmd = &cobra.Command{
 		Use:     "dashboard",
 		Short:   "Start UI dashboard",
@@ -36,6 +61,32 @@ func NewCmdDashboard(o *options.ArgoRolloutsOptions) *cobra.Command {
 				RolloutsClientset: rolloutclientset,
 				DynamicClientset:  o.DynamicClientset(),
 				RootPath:          rootPath,
+				Auth:              auth,
+				ReadOnly:          readOnly,
+				AllowedNamespaces: allowedNamespaces,
+				PodLogURLTemplate: podLogURLTemplate,
+			}
+			if opts.Auth.Enabled() {
+				restConfig, err := o.RESTClientGetter.ToRESTConfig()
+				if err != nil {
+					return err
+				}
+				opts.RESTConfig = restConfig
+			}
+			if opts.Auth.ClientSecret == "" {
+				opts.Auth.ClientSecret = os.Getenv(oidcClientSecretEnv)
+			}
+			if selfSignedTLS {
+				selfSignedTLSConfig.Namespace = namespace
+				if len(selfSignedTLSConfig.DNSNames) == 0 {
+					selfSignedTLSConfig.DNSNames = certs.DefaultDNSNames(selfSignedTLSService, namespace)
+				}
+				rotator := certs.NewRotator(kubeclientset, selfSignedTLSConfig)
+				if err := rotator.Rotate(context.Background()); err != nil {
+					return err
+				}
+				go rotator.Run(context.Background())
+				opts.TLS = rotator
 			}
 
 			for {
@@ -49,6 +100,21 @@ func NewCmdDashboard(o *options.ArgoRolloutsOptions) *cobra.Command {
 	}
 	cmd.Flags().StringVar(&rootPath, "root-path", "rollouts", "changes the root path of the dashboard")
 	cmd.Flags().IntVarP(&port, "port", "p", 3100, "port to listen on")
+	cmd.Flags().BoolVar(&readOnly, "read-only", false, "reject every action on the rollouts, so that the dashboard can only be used to view them")
+	cmd.Flags().StringSliceVar(&allowedNamespaces, "allowed-namespaces", nil, "namespaces the dashboard is restricted to. Defaults to every namespace")
+	cmd.Flags().StringVar(&podLogURLTemplate, "pod-log-url-template", "", "URL of the logs of a pod in the log system of the cluster, linked from the pods of the dashboard. ${metadata.namespace} and ${metadata.name} are replaced by the namespace and the name of the pod")
+	cmd.Flags().StringVar(&auth.IssuerURL, "oidc-issuer-url", "", "URL of the OIDC provider the users log in with. The access of the users to rollouts is checked against the Kubernetes RBAC. Defaults to no login")
+	cmd.Flags().StringVar(&auth.ClientID, "oidc-client-id", "", "client ID of the dashboard at the OIDC provider")
+	cmd.Flags().StringVar(&auth.ClientSecret, "oidc-client-secret", "", "client secret of the dashboard at the OIDC provider. Defaults to the "+oidcClientSecretEnv+" environment variable")
+	cmd.Flags().StringVar(&auth.RedirectURL, "oidc-redirect-url", "", "external URL of the callback of the dashboard, e.g. https://rollouts.example.com/rollouts/auth/callback")
+	cmd.Flags().StringSliceVar(&auth.Scopes, "oidc-scopes", []string{"email", "groups"}, "scopes requested in addition to openid")
+	cmd.Flags().StringVar(&auth.UsernameClaim, "oidc-username-claim", "email", "claim of the ID token which is the Kubernetes username of the user")
+	cmd.Flags().StringVar(&auth.GroupsClaim, "oidc-groups-claim", "groups", "claim of the ID token which holds the Kubernetes groups of the user")
+	cmd.Flags().BoolVar(&selfSignedTLS, "self-signed-tls", false, "serve the dashboard over HTTPS with a serving certificate generated and rotated by the dashboard, signed by a self-signed CA whose bundle is published to a ConfigMap of the namespace")
+	cmd.Flags().StringVar(&selfSignedTLSConfig.SecretName, "self-signed-tls-secret", "argo-rollouts-dashboard-serving-certs", "name of the Secret the self-signed CA and serving certificate of the dashboard are persisted to")
+	cmd.Flags().StringVar(&selfSignedTLSConfig.CABundleConfigMapName, "self-signed-tls-ca-bundle-configmap", "argo-rollouts-dashboard-ca-bundle", "name of the ConfigMap the bundle of the self-signed CA of the dashboard is published to, under the key "+certs.CABundleKey)
+	cmd.Flags().StringVar(&selfSignedTLSService, "self-signed-tls-service", "argo-rollouts-dashboard", "name of the Service whose DNS names the self-signed serving certificate is valid for. Ignored if --self-signed-tls-dns-names is set")
+	cmd.Flags().StringSliceVar(&selfSignedTLSConfig.DNSNames, "self-signed-tls-dns-names", nil, "DNS names the self-signed serving certificate is valid for. Defaults to the DNS names of --self-signed-tls-service")
 
 	return cmd
 }
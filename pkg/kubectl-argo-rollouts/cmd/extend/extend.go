@@ -0,0 +1,117 @@
+package extend
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	clientset "github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned/typed/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
+	completionutil "github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/util/completion"
+)
+
+const (
+	// the patch carries the resourceVersion of the experiment so that it conflicts with a concurrent extension rather
+	// than overwriting it
+	extendPatch = `{"metadata":{"resourceVersion":"%s"},"spec":{"extension":"%s"}}`
+
+	invalidDurationError     = "invalid duration '%s', must be a positive duration string (e.g. 30s, 5m, 1h)"
+	withoutDurationError     = "experiment '%s' has no duration and runs until it is terminated"
+	experimentCompletedError = "experiment '%s' has already completed"
+)
+
+const (
+	extendExample = `
+	# Extend the duration of an experiment by 30 minutes
+	%[1]s extend experiment my-experiment 30m`
+
+	extendExperimentExample = `
+	# Extend the duration of an experiment by 30 minutes
+	%[1]s extend experiment my-experiment 30m`
+)
+
+// NewCmdExtend returns a new instance of an `argo rollouts extend` command
+func NewCmdExtend(o *options.ArgoRolloutsOptions) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:          "extend <experiment> RESOURCE_NAME DURATION",
+		Short:        "Extend the duration of an experiment",
+		Long:         "This command consists of multiple subcommands which can be used to extend the duration of a running Experiment.",
+		Example:      o.Example(extendExample),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			return o.UsageErr(c)
+		},
+	}
+	cmd.AddCommand(NewCmdExtendExperiment(o))
+	return cmd
+}
+
+// NewCmdExtendExperiment returns a new instance of an `argo rollouts extend experiment` command
+func NewCmdExtendExperiment(o *options.ArgoRolloutsOptions) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:          "experiment EXPERIMENT_NAME DURATION",
+		Aliases:      []string{"exp", "experiments"},
+		Short:        "Extend the duration of an experiment",
+		Long:         "This command extends the duration of a running Experiment. The duration is added to any previous extension.",
+		Example:      o.Example(extendExperimentExample),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return o.UsageErr(c)
+			}
+			duration, err := time.ParseDuration(args[1])
+			if err != nil || duration <= 0 {
+				return fmt.Errorf(invalidDurationError, args[1])
+			}
+			experimentIf := o.RolloutsClientset().ArgoprojV1alpha1().Experiments(o.Namespace())
+			ex, err := ExtendExperiment(experimentIf, args[0], duration)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(o.Out, "experiment '%s' extended by %s\n", ex.Name, duration)
+			return nil
+		},
+		ValidArgsFunction: completionutil.ExperimentNameCompletionFunc(o),
+	}
+	return cmd
+}
+
+// ExtendExperiment adds the duration to the extension of a running experiment. The extension is retried on conflicts
+// with other updates of the experiment, so that concurrent extensions add up.
+func ExtendExperiment(experimentIf clientset.ExperimentInterface, name string, duration time.Duration) (*v1alpha1.Experiment, error) {
+	ctx := context.TODO()
+	var extended *v1alpha1.Experiment
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ex, err := experimentIf.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if ex.Spec.Duration == "" {
+			return fmt.Errorf(withoutDurationError, name)
+		}
+		if ex.Status.Phase.Completed() {
+			return fmt.Errorf(experimentCompletedError, name)
+		}
+		extension := duration
+		if ex.Spec.Extension != "" {
+			previous, err := ex.Spec.Extension.Duration()
+			if err != nil {
+				return err
+			}
+			extension += previous
+		}
+		patch := fmt.Sprintf(extendPatch, ex.ResourceVersion, extension.String())
+		extended, err = experimentIf.Patch(ctx, name, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return extended, nil
+}
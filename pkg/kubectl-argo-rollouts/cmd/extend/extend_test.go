@@ -0,0 +1,151 @@
+package extend
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kubetesting "k8s.io/client-go/testing"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	fakeroclient "github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned/fake"
+	options "github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options/fake"
+)
+
+func newExperiment(duration, extension v1alpha1.DurationString, phase v1alpha1.AnalysisPhase) *v1alpha1.Experiment {
+	return &v1alpha1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-experiment",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: v1alpha1.ExperimentSpec{
+			Duration:  duration,
+			Extension: extension,
+		},
+		Status: v1alpha1.ExperimentStatus{
+			Phase: phase,
+		},
+	}
+}
+
+func runExtendCmd(ex *v1alpha1.Experiment, args ...string) (*v1alpha1.Experiment, string, string, error) {
+	tf, o := options.NewFakeArgoRolloutsOptions(ex)
+	defer tf.Cleanup()
+	cmd := NewCmdExtend(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	ex, getErr := o.RolloutsClientset().ArgoprojV1alpha1().Experiments(metav1.NamespaceDefault).Get(context.TODO(), ex.Name, metav1.GetOptions{})
+	if getErr != nil {
+		panic(getErr)
+	}
+	return ex, o.Out.(*bytes.Buffer).String(), o.ErrOut.(*bytes.Buffer).String(), err
+}
+
+func TestExtendCmdUsage(t *testing.T) {
+	_, stdout, stderr, err := runExtendCmd(newExperiment("1h", "", v1alpha1.AnalysisPhaseRunning))
+	assert.Error(t, err)
+	assert.Empty(t, stdout)
+	assert.Contains(t, stderr, "Usage:\n  extend <experiment> RESOURCE")
+
+	_, stdout, stderr, err = runExtendCmd(newExperiment("1h", "", v1alpha1.AnalysisPhaseRunning), "experiment", "my-experiment")
+	assert.Error(t, err)
+	assert.Empty(t, stdout)
+	assert.Contains(t, stderr, "Usage:\n  extend experiment EXPERIMENT_NAME DURATION")
+}
+
+func TestExtendExperimentCmd(t *testing.T) {
+	ex, stdout, stderr, err := runExtendCmd(newExperiment("1h", "", v1alpha1.AnalysisPhaseRunning), "experiment", "my-experiment", "30m")
+	assert.NoError(t, err)
+	assert.Equal(t, v1alpha1.DurationString("30m0s"), ex.Spec.Extension)
+	assert.Equal(t, "experiment 'my-experiment' extended by 30m0s\n", stdout)
+	assert.Empty(t, stderr)
+
+	// the duration is added to the previous extension
+	ex, _, _, err = runExtendCmd(newExperiment("1h", "30m", v1alpha1.AnalysisPhaseRunning), "exp", "my-experiment", "90s")
+	assert.NoError(t, err)
+	assert.Equal(t, v1alpha1.DurationString("31m30s"), ex.Spec.Extension)
+}
+
+func TestExtendExperimentCmdConflict(t *testing.T) {
+	ex := newExperiment("1h", "", v1alpha1.AnalysisPhaseRunning)
+	ex.ResourceVersion = "1"
+	tf, o := options.NewFakeArgoRolloutsOptions(ex)
+	defer tf.Cleanup()
+	fakeClient := o.RolloutsClient.(*fakeroclient.Clientset)
+	var patches []string
+	fakeClient.PrependReactor("patch", "experiments", func(action kubetesting.Action) (bool, runtime.Object, error) {
+		patches = append(patches, string(action.(kubetesting.PatchAction).GetPatch()))
+		if len(patches) > 1 {
+			return false, nil, nil
+		}
+		// another extension updates the experiment in the meantime
+		concurrent := ex.DeepCopy()
+		concurrent.ResourceVersion = "2"
+		concurrent.Spec.Extension = "10m"
+		if err := fakeClient.Tracker().Update(v1alpha1.SchemeGroupVersion.WithResource("experiments"), concurrent, ex.Namespace); err != nil {
+			return true, nil, err
+		}
+		return true, nil, k8serrors.NewConflict(schema.GroupResource{Group: "argoproj.io", Resource: "experiments"}, ex.Name, nil)
+	})
+
+	cmd := NewCmdExtend(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{"experiment", "my-experiment", "30m"})
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		`{"metadata":{"resourceVersion":"1"},"spec":{"extension":"30m0s"}}`,
+		`{"metadata":{"resourceVersion":"2"},"spec":{"extension":"40m0s"}}`,
+	}, patches)
+	extended, err := fakeClient.ArgoprojV1alpha1().Experiments(metav1.NamespaceDefault).Get(context.TODO(), ex.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, v1alpha1.DurationString("40m0s"), extended.Spec.Extension)
+}
+
+func TestExtendExperimentCmdError(t *testing.T) {
+	tests := []struct {
+		name       string
+		experiment *v1alpha1.Experiment
+		duration   string
+		err        string
+	}{
+		{
+			name:       "invalid duration",
+			experiment: newExperiment("1h", "", v1alpha1.AnalysisPhaseRunning),
+			duration:   "ten minutes",
+			err:        "invalid duration 'ten minutes', must be a positive duration string (e.g. 30s, 5m, 1h)",
+		},
+		{
+			name:       "zero duration",
+			experiment: newExperiment("1h", "", v1alpha1.AnalysisPhaseRunning),
+			duration:   "0s",
+			err:        "invalid duration '0s', must be a positive duration string (e.g. 30s, 5m, 1h)",
+		},
+		{
+			name:       "without duration",
+			experiment: newExperiment("", "", v1alpha1.AnalysisPhaseRunning),
+			duration:   "10m",
+			err:        "experiment 'my-experiment' has no duration and runs until it is terminated",
+		},
+		{
+			name:       "completed",
+			experiment: newExperiment("1h", "", v1alpha1.AnalysisPhaseSuccessful),
+			duration:   "10m",
+			err:        "experiment 'my-experiment' has already completed",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ex, stdout, _, err := runExtendCmd(test.experiment, "experiment", "my-experiment", test.duration)
+			assert.EqualError(t, err, test.err)
+			assert.Empty(t, stdout)
+			assert.Empty(t, ex.Spec.Extension)
+		})
+	}
+}
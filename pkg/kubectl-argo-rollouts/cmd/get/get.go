@@ -6,6 +6,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -100,6 +101,15 @@ type GetOptions struct {
 	Watch          bool
 	NoColor        bool
 	TimeoutSeconds int
+	Output         string
+	// Plain prints a single line per change of the rollout instead of redrawing the terminal
+	Plain bool
+	// Refresh is the minimum interval between two updates of the watch output
+	Refresh time.Duration
+	// Columns are the columns of the plain output
+	Columns []string
+
+	lastPlainLine string
 
 	options.ArgoRolloutsOptions
 }
@@ -2,6 +2,7 @@ package get
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"strings"
@@ -10,6 +11,9 @@ import (
 
 	"github.com/juju/ansiterm"
 	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"sigs.k8s.io/yaml"
 
 	"github.com/argoproj/argo-rollouts/pkg/apiclient/rollout"
 	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd/signals"
@@ -28,7 +32,13 @@ const (
   	%[1]s get rollout guestbook -w
 
 	# Watch the rollout, fail if it takes more than 60 seconds
-	%[1]s get rollout guestbook -w --timeout-seconds 60`
+	%[1]s get rollout guestbook -w --timeout-seconds 60
+
+	# Get the progress of a rollout through its steps as JSON
+	%[1]s get rollout guestbook -o json
+
+	# Watch the rollout in CI logs, printing a line with the selected columns whenever they change
+	%[1]s get rollout guestbook -w --plain --refresh 10s --columns status,step,setWeight,ready`
 )
 
 // NewCmdGetRollout returns a new instance of an `rollouts get rollout` command
@@ -49,6 +59,26 @@ func NewCmdGetRollout(o *options.ArgoRolloutsOptions) *cobra.Command {
 				return o.UsageErr(c)
 			}
 			name := args[0]
+			if getOptions.Output != "" {
+				if getOptions.Output != "json" && getOptions.Output != "yaml" {
+					return fmt.Errorf("unknown output format %q, must be one of: json, yaml", getOptions.Output)
+				}
+				if getOptions.Watch {
+					return fmt.Errorf("--output cannot be used with --watch")
+				}
+				if getOptions.Plain {
+					return fmt.Errorf("--output cannot be used with --plain")
+				}
+			}
+			columns, err := parsePlainColumns(getOptions.Columns)
+			if err != nil {
+				return err
+			}
+			getOptions.Columns = columns
+			if getOptions.Watch && !c.Flags().Changed("plain") && !getOptions.isTerminal() {
+				// redrawing the output only makes sense on a terminal, e.g. not in CI logs or through tee
+				getOptions.Plain = true
+			}
 			controller := viewcontroller.NewRolloutViewController(o.Namespace(), name, getOptions.KubeClientset(), getOptions.RolloutsClientset())
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
@@ -59,8 +89,15 @@ func NewCmdGetRollout(o *options.ArgoRolloutsOptions) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if getOptions.Output != "" {
+				return getOptions.PrintRolloutTimeline(ctx, ri)
+			}
 			if !getOptions.Watch {
-				getOptions.PrintRollout(ri)
+				if getOptions.Plain {
+					getOptions.PrintRolloutPlain(ri)
+				} else {
+					getOptions.PrintRollout(ri)
+				}
 			} else {
 				rolloutUpdates := make(chan *rollout.RolloutInfo)
 				var rolloutUpdatesMutex sync.Mutex
@@ -91,11 +128,46 @@ func NewCmdGetRollout(o *options.ArgoRolloutsOptions) *cobra.Command {
 	cmd.Flags().BoolVarP(&getOptions.Watch, "watch", "w", false, "Watch live updates to the rollout")
 	cmd.Flags().BoolVar(&getOptions.NoColor, "no-color", false, "Do not colorize output")
 	cmd.Flags().IntVar(&getOptions.TimeoutSeconds, "timeout-seconds", 0, "Timeout after specified seconds")
+	cmd.Flags().StringVarP(&getOptions.Output, "output", "o", "", "Output format. One of: json|yaml. Prints the rollout along with the timeline of the steps, analysis runs and traffic weights of its latest revision")
+	cmd.Flags().BoolVar(&getOptions.Plain, "plain", false, "Print a timestamped line with the selected columns instead of the tree, and when watching, append a line whenever they change instead of redrawing the terminal. Defaults to true when watching and the output is not a terminal")
+	cmd.Flags().DurationVar(&getOptions.Refresh, "refresh", 0, "Minimum interval between two updates of the watch output, e.g. 10s. Defaults to 200ms, or at most 1s without changes of the rollout")
+	cmd.Flags().StringSliceVar(&getOptions.Columns, "columns", nil, fmt.Sprintf("Columns of the plain output. Any of: %s (default %s)", strings.Join(PlainColumns, ","), strings.Join(DefaultPlainColumns, ",")))
 	return cmd
 }
 
+// PrintRolloutTimeline prints the timeline of the rollout in the output format
+func (o *GetOptions) PrintRolloutTimeline(ctx context.Context, roInfo *rollout.RolloutInfo) error {
+	selector := fields.Set{
+		"involvedObject.kind": "Rollout",
+		"involvedObject.uid":  string(roInfo.ObjectMeta.UID),
+	}.AsSelector().String()
+	eventList, err := o.KubeClientset().CoreV1().Events(roInfo.ObjectMeta.Namespace).List(ctx, metav1.ListOptions{FieldSelector: selector})
+	if err != nil {
+		return err
+	}
+	timeline := info.NewRolloutTimeline(roInfo, eventList.Items)
+	var data []byte
+	if o.Output == "yaml" {
+		data, err = yaml.Marshal(timeline)
+	} else {
+		data, err = json.MarshalIndent(timeline, "", "  ")
+		data = append(data, '\n')
+	}
+	if err != nil {
+		return err
+	}
+	_, err = o.Out.Write(data)
+	return err
+}
+
 func Watch(stopCh <-chan struct{}, rolloutUpdates chan *rollout.RolloutInfo, callback func(*rollout.RolloutInfo)) {
-	ticker := time.NewTicker(time.Second)
+	watch(stopCh, rolloutUpdates, time.Second, 200*time.Millisecond, callback)
+}
+
+// watch calls back with the latest update of the rollout at most every minInterval, and at least every interval
+func watch(stopCh <-chan struct{}, rolloutUpdates chan *rollout.RolloutInfo, interval, minInterval time.Duration, callback func(*rollout.RolloutInfo)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
 	var currRolloutInfo *rollout.RolloutInfo
 	// preventFlicker is used to rate-limit the updates we print to the terminal when updates occur
 	// so rapidly that it causes the terminal to flicker
@@ -109,7 +181,7 @@ func Watch(stopCh <-chan struct{}, rolloutUpdates chan *rollout.RolloutInfo, cal
 		case <-stopCh:
 			return
 		}
-		if currRolloutInfo != nil && time.Now().After(preventFlicker.Add(200*time.Millisecond)) {
+		if currRolloutInfo != nil && time.Now().After(preventFlicker.Add(minInterval)) {
 			callback(currRolloutInfo)
 			preventFlicker = time.Now()
 		}
@@ -117,8 +189,16 @@ func Watch(stopCh <-chan struct{}, rolloutUpdates chan *rollout.RolloutInfo, cal
 }
 
 func (o *GetOptions) WatchRollout(stopCh <-chan struct{}, rolloutUpdates chan *rollout.RolloutInfo) {
-	Watch(stopCh, rolloutUpdates,
+	interval, minInterval := time.Second, 200*time.Millisecond
+	if o.Refresh > 0 {
+		interval, minInterval = o.Refresh, o.Refresh
+	}
+	watch(stopCh, rolloutUpdates, interval, minInterval,
 		func(i *rollout.RolloutInfo) {
+			if o.Plain {
+				o.PrintRolloutPlain(i)
+				return
+			}
 			o.Clear()
 			o.PrintRollout(i)
 		})
@@ -147,6 +227,9 @@ func (o *GetOptions) PrintRollout(roInfo *rollout.RolloutInfo) {
 	fmt.Fprintf(o.Out, tableFormat, "Strategy:", roInfo.Strategy)
 	if roInfo.Strategy == "Canary" {
 		fmt.Fprintf(o.Out, tableFormat, "  Step:", roInfo.Step)
+		if roInfo.StepProgress != "" {
+			fmt.Fprintf(o.Out, tableFormat, "  StepProgress:", roInfo.StepProgress)
+		}
 		fmt.Fprintf(o.Out, tableFormat, "  SetWeight:", roInfo.SetWeight)
 		fmt.Fprintf(o.Out, tableFormat, "  ActualWeight:", roInfo.ActualWeight)
 	}
@@ -9,8 +9,11 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/info"
 	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/info/testdata"
 	options "github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options/fake"
 	timeutil "github.com/argoproj/argo-rollouts/utils/time"
@@ -109,7 +112,7 @@ func TestWatchBlueGreenRollout(t *testing.T) {
 	defer tf.Cleanup()
 	cmd := NewCmdGetRollout(o)
 	cmd.PersistentPreRunE = o.PersistentPreRunE
-	cmd.SetArgs([]string{rolloutObjs.Rollouts[0].Name, "--no-color", "--watch", "--timeout-seconds", "10"})
+	cmd.SetArgs([]string{rolloutObjs.Rollouts[0].Name, "--no-color", "--watch", "--plain=false", "--timeout-seconds", "10"})
 	err := cmd.Execute()
 	assert.NoError(t, err)
 	rolloutObjs = nil
@@ -304,6 +307,105 @@ NAME                                     KIND        STATUS              AGE  IN
 	assertStdout(t, expectedOut, o.IOStreams)
 }
 
+func TestGetCanaryRolloutOutput(t *testing.T) {
+	for _, output := range []string{"json", "yaml"} {
+		rolloutObjs := testdata.NewCanaryRollout()
+
+		tf, o := options.NewFakeArgoRolloutsOptions(rolloutObjs.AllObjects()...)
+		o.RESTClientGetter = tf.WithNamespace(rolloutObjs.Rollouts[0].Namespace)
+		cmd := NewCmdGetRollout(o)
+		cmd.PersistentPreRunE = o.PersistentPreRunE
+		cmd.SetArgs([]string{rolloutObjs.Rollouts[0].Name, "-o", output})
+		err := cmd.Execute()
+		assert.NoError(t, err)
+
+		var timeline info.RolloutTimeline
+		stdout := o.Out.(*bytes.Buffer).Bytes()
+		assert.NoError(t, yaml.Unmarshal(stdout, &timeline))
+		assert.Equal(t, "canary-demo", timeline.Name)
+		assert.Equal(t, "Degraded", timeline.Status)
+		assert.Equal(t, int64(31), timeline.Revision)
+		assert.Equal(t, ptr.To[int32](0), timeline.CurrentStep)
+		assert.Len(t, timeline.Steps, 8)
+		assert.Equal(t, info.StepStatusRunning, timeline.Steps[0].Status)
+		assert.Equal(t, info.StepStatusPending, timeline.Steps[1].Status)
+		assert.Empty(t, o.ErrOut.(*bytes.Buffer).String())
+		tf.Cleanup()
+	}
+}
+
+func TestGetRolloutOutputErrors(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"-o", "wide"}, `unknown output format "wide", must be one of: json, yaml`},
+		{[]string{"-o", "json", "-w"}, "--output cannot be used with --watch"},
+		{[]string{"-o", "json", "--plain"}, "--output cannot be used with --plain"},
+		{[]string{"--plain", "--columns", "status,weight"}, `unknown column "weight", must be one of: status, message, strategy, step, setWeight, actualWeight, images, desired, current, updated, ready, available`},
+	}
+	for _, test := range tests {
+		tf, o := options.NewFakeArgoRolloutsOptions()
+		cmd := NewCmdGetRollout(o)
+		cmd.PersistentPreRunE = o.PersistentPreRunE
+		cmd.SetArgs(append([]string{"guestbook"}, test.args...))
+		err := cmd.Execute()
+		assert.EqualError(t, err, test.expected)
+		tf.Cleanup()
+	}
+}
+
+func TestGetCanaryRolloutPlain(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	timeutil.SetNowTimeFunc(func() time.Time { return now })
+	defer timeutil.SetNowTimeFunc(time.Now)
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{
+			[]string{"--plain"},
+			"2024-01-01T00:00:00Z name=canary-demo status=Degraded step=0/8 setWeight=20 actualWeight=0 desired=5 ready=5 available=5 message=\"ProgressDeadlineExceeded: ReplicaSet \\\"canary-demo-65fb5ffc84\\\" has timed out progressing.\"\n",
+		},
+		{
+			[]string{"--plain", "--columns", "ready,STATUS,images"},
+			"2024-01-01T00:00:00Z name=canary-demo status=Degraded images=argoproj/rollouts-demo:does-not-exist,argoproj/rollouts-demo:green ready=5\n",
+		},
+	}
+	for _, test := range tests {
+		rolloutObjs := testdata.NewCanaryRollout()
+		tf, o := options.NewFakeArgoRolloutsOptions(rolloutObjs.AllObjects()...)
+		o.RESTClientGetter = tf.WithNamespace(rolloutObjs.Rollouts[0].Namespace)
+		cmd := NewCmdGetRollout(o)
+		cmd.PersistentPreRunE = o.PersistentPreRunE
+		cmd.SetArgs(append([]string{rolloutObjs.Rollouts[0].Name}, test.args...))
+		err := cmd.Execute()
+		assert.NoError(t, err)
+		assertStdout(t, test.expected, o.IOStreams)
+		tf.Cleanup()
+	}
+}
+
+func TestWatchCanaryRolloutPlain(t *testing.T) {
+	rolloutObjs := testdata.NewCanaryRollout()
+
+	tf, o := options.NewFakeArgoRolloutsOptions(rolloutObjs.AllObjects()...)
+	o.RESTClientGetter = tf.WithNamespace(rolloutObjs.Rollouts[0].Namespace)
+	defer tf.Cleanup()
+	cmd := NewCmdGetRollout(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{rolloutObjs.Rollouts[0].Name, "--watch", "--timeout-seconds", "1", "--refresh", "100ms", "--columns", "status"})
+	err := cmd.Execute()
+	assert.NoError(t, err)
+
+	// the output is not a terminal, so that the unchanged rollout is printed once without redraws
+	stdout := o.Out.(*bytes.Buffer).String()
+	assert.NotContains(t, stdout, "\033[")
+	lines := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+	assert.Len(t, lines, 1)
+	assert.True(t, strings.HasSuffix(lines[0], " name=canary-demo status=Degraded"), lines[0])
+}
+
 func TestGetCanaryPingPongRollout(t *testing.T) {
 	rolloutObjs := testdata.NewCanaryRollout()
 
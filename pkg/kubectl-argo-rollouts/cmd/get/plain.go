@@ -0,0 +1,132 @@
+package get
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/argoproj/argo-rollouts/pkg/apiclient/rollout"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/info"
+	timeutil "github.com/argoproj/argo-rollouts/utils/time"
+)
+
+// PlainColumns are the columns which can be selected for the plain output of a rollout, in the order they are printed
+var PlainColumns = []string{
+	"status",
+	"message",
+	"strategy",
+	"step",
+	"setWeight",
+	"actualWeight",
+	"images",
+	"desired",
+	"current",
+	"updated",
+	"ready",
+	"available",
+}
+
+// DefaultPlainColumns are the columns of the plain output of a rollout when none are selected
+var DefaultPlainColumns = []string{"status", "step", "setWeight", "actualWeight", "desired", "ready", "available", "message"}
+
+// plainColumnValue returns the value of a column of the plain output of a rollout
+func plainColumnValue(roInfo *rollout.RolloutInfo, column string) string {
+	switch column {
+	case "status":
+		return roInfo.Status
+	case "message":
+		return roInfo.Message
+	case "strategy":
+		return roInfo.Strategy
+	case "step":
+		return roInfo.Step
+	case "setWeight":
+		return roInfo.SetWeight
+	case "actualWeight":
+		return roInfo.ActualWeight
+	case "images":
+		var images []string
+		for _, image := range info.Images(roInfo) {
+			images = append(images, image.Image)
+		}
+		return strings.Join(images, ",")
+	case "desired":
+		return strconv.Itoa(int(roInfo.Desired))
+	case "current":
+		return strconv.Itoa(int(roInfo.Current))
+	case "updated":
+		return strconv.Itoa(int(roInfo.Updated))
+	case "ready":
+		return strconv.Itoa(int(roInfo.Ready))
+	case "available":
+		return strconv.Itoa(int(roInfo.Available))
+	}
+	return ""
+}
+
+// parsePlainColumns returns the columns of the plain output in the order they are printed. The names of the columns
+// are case-insensitive.
+func parsePlainColumns(columns []string) ([]string, error) {
+	if len(columns) == 0 {
+		return DefaultPlainColumns, nil
+	}
+	selected := map[string]bool{}
+	for _, column := range columns {
+		found := false
+		for _, known := range PlainColumns {
+			if strings.EqualFold(column, known) {
+				selected[known] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown column %q, must be one of: %s", column, strings.Join(PlainColumns, ", "))
+		}
+	}
+	var parsed []string
+	for _, column := range PlainColumns {
+		if selected[column] {
+			parsed = append(parsed, column)
+		}
+	}
+	return parsed, nil
+}
+
+// plainLine returns the selected columns of a rollout as space separated key=value pairs. The columns without a value
+// are omitted, and the values with spaces are quoted.
+func plainLine(roInfo *rollout.RolloutInfo, columns []string) string {
+	pairs := []string{"name=" + roInfo.ObjectMeta.Name}
+	for _, column := range columns {
+		value := plainColumnValue(roInfo, column)
+		if value == "" {
+			continue
+		}
+		if strings.ContainsAny(value, " \t\"=") {
+			value = strconv.Quote(value)
+		}
+		pairs = append(pairs, column+"="+value)
+	}
+	return strings.Join(pairs, " ")
+}
+
+// PrintRolloutPlain prints a timestamped line with the selected columns of the rollout, unless it is the same as the
+// last printed line
+func (o *GetOptions) PrintRolloutPlain(roInfo *rollout.RolloutInfo) {
+	line := plainLine(roInfo, o.Columns)
+	if line == o.lastPlainLine {
+		return
+	}
+	o.lastPlainLine = line
+	fmt.Fprintf(o.Out, "%s %s\n", timeutil.Now().UTC().Format(time.RFC3339), line)
+}
+
+// isTerminal returns whether the output is a terminal, which the watch output can be redrawn on
+func (o *GetOptions) isTerminal() bool {
+	f, ok := o.Out.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
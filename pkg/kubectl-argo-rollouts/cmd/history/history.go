@@ -0,0 +1,220 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	clientset "github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
+	completionutil "github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/util/completion"
+	"github.com/argoproj/argo-rollouts/utils/annotations"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
+)
+
+const (
+	historyExample = `
+	# List the revisions of a rollout
+	%[1]s history guestbook`
+
+	historyHeaderFmtString = "REVISION\tIMAGES\tCHANGE-CAUSE\tCREATED\tPROMOTED\tANALYSIS\n"
+	historyColumnFmtString = "%d\t%s\t%s\t%s\t%s\t%s\n"
+)
+
+// RevisionHistory is a revision of a rollout
+type RevisionHistory struct {
+	Revision    int64
+	Images      []string
+	ChangeCause string
+	CreatedAt   metav1.Time
+	// PromotedAt is the time the rollout completed the update to the revision. It is derived from the events of the
+	// rollout, and is missing once the events expired.
+	PromotedAt *metav1.Time
+	// Analysis is the outcome of the analysis runs of the revision
+	Analysis v1alpha1.AnalysisPhase
+	Stable   bool
+}
+
+// NewCmdHistory returns a new instance of an `rollouts history` command
+func NewCmdHistory(o *options.ArgoRolloutsOptions) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "history ROLLOUT_NAME",
+		Short: "List the revisions of a rollout",
+		Long: "List the revisions of a rollout with their images, change-cause, the time they were promoted and the " +
+			"outcome of their analysis runs, the most recent first. The promotion times are derived from the events " +
+			"of the rollout and are missing once the events expired.",
+		Example:      o.Example(historyExample),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return o.UsageErr(c)
+			}
+			history, err := GetRolloutHistory(c.Context(), o.KubeClientset(), o.RolloutsClientset(), o.Namespace(), args[0])
+			if err != nil {
+				return err
+			}
+			printHistoryTable(o.Out, o.ErrOut, history)
+			return nil
+		},
+		ValidArgsFunction: completionutil.RolloutNameCompletionFunc(o),
+	}
+	return cmd
+}
+
+// GetRolloutHistory returns the revisions of a rollout from its ReplicaSets, analysis runs and events, the most
+// recent first
+func GetRolloutHistory(ctx context.Context, kubeClient kubernetes.Interface, rolloutClient clientset.Interface, namespace, name string) ([]RevisionHistory, error) {
+	ro, err := rolloutClient.ArgoprojV1alpha1().Rollouts(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	rsList, err := kubeClient.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	arList, err := rolloutClient.ArgoprojV1alpha1().AnalysisRuns(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	selector := fields.Set{
+		"involvedObject.kind": "Rollout",
+		"involvedObject.uid":  string(ro.UID),
+	}.AsSelector().String()
+	eventList, err := kubeClient.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{FieldSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+
+	promotedAt := promotionTimes(eventList.Items)
+	analysisPhases := map[int64][]v1alpha1.AnalysisPhase{}
+	for i := range arList.Items {
+		ar := &arList.Items[i]
+		if ownerRef := metav1.GetControllerOf(ar); ownerRef == nil || ownerRef.UID != ro.UID {
+			continue
+		}
+		if revision, ok := annotations.GetRevisionAnnotation(ar); ok {
+			analysisPhases[int64(revision)] = append(analysisPhases[int64(revision)], ar.Status.Phase)
+		}
+	}
+
+	var history []RevisionHistory
+	for i := range rsList.Items {
+		rs := &rsList.Items[i]
+		if ownerRef := metav1.GetControllerOf(rs); ownerRef == nil || ownerRef.UID != ro.UID {
+			continue
+		}
+		revision, err := replicasetutil.Revision(rs)
+		if err != nil || revision == 0 {
+			continue
+		}
+		history = append(history, RevisionHistory{
+			Revision:    revision,
+			Images:      images(rs),
+			ChangeCause: rs.Annotations[genericclioptions.ChangeCauseAnnotation],
+			CreatedAt:   rs.CreationTimestamp,
+			PromotedAt:  promotedAt[revision],
+			Analysis:    analysisOutcome(analysisPhases[revision]),
+			Stable:      ro.Status.StableRS != "" && rs.Labels[v1alpha1.DefaultRolloutUniqueLabelKey] == ro.Status.StableRS,
+		})
+	}
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].Revision > history[j].Revision
+	})
+	return history, nil
+}
+
+func images(rs *appsv1.ReplicaSet) []string {
+	var images []string
+	for _, c := range rs.Spec.Template.Spec.Containers {
+		images = append(images, c.Image)
+	}
+	return images
+}
+
+// promotionTimes returns the last time the rollout completed the update to each revision
+func promotionTimes(events []corev1.Event) map[int64]*metav1.Time {
+	promotedAt := map[int64]*metav1.Time{}
+	for i := range events {
+		event := &events[i]
+		if event.Reason != conditions.RolloutCompletedReason {
+			continue
+		}
+		var revision int64
+		if _, err := fmt.Sscanf(event.Message, "Rollout completed update to revision %d", &revision); err != nil {
+			continue
+		}
+		eventTime := event.LastTimestamp
+		if eventTime.IsZero() {
+			eventTime = metav1.NewTime(event.EventTime.Time)
+		}
+		if eventTime.IsZero() {
+			continue
+		}
+		if prev := promotedAt[revision]; prev == nil || prev.Before(&eventTime) {
+			promotedAt[revision] = &eventTime
+		}
+	}
+	return promotedAt
+}
+
+// analysisOutcome returns the overall phase of the analysis runs of a revision: the first unsuccessful phase of the
+// completed runs, Running while any run is still in progress, or Successful
+func analysisOutcome(phases []v1alpha1.AnalysisPhase) v1alpha1.AnalysisPhase {
+	if len(phases) == 0 {
+		return ""
+	}
+	outcome := v1alpha1.AnalysisPhaseSuccessful
+	for _, phase := range phases {
+		switch {
+		case phase == v1alpha1.AnalysisPhaseFailed || phase == v1alpha1.AnalysisPhaseError:
+			return phase
+		case phase == v1alpha1.AnalysisPhaseInconclusive:
+			outcome = phase
+		case !phase.Completed() && outcome == v1alpha1.AnalysisPhaseSuccessful:
+			outcome = v1alpha1.AnalysisPhaseRunning
+		}
+	}
+	return outcome
+}
+
+// printHistoryTable prints the revisions in table format
+func printHistoryTable(out, errOut io.Writer, history []RevisionHistory) {
+	if len(history) == 0 {
+		fmt.Fprintln(errOut, "No resources found.")
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprint(w, historyHeaderFmtString)
+	for _, rev := range history {
+		promoted := "-"
+		if rev.PromotedAt != nil {
+			promoted = rev.PromotedAt.UTC().Format(time.RFC3339)
+		}
+		if rev.Stable {
+			promoted += " (stable)"
+		}
+		fmt.Fprintf(w, historyColumnFmtString, rev.Revision, strings.Join(rev.Images, ","), orDash(rev.ChangeCause), rev.CreatedAt.UTC().Format(time.RFC3339), promoted, orDash(string(rev.Analysis)))
+	}
+	_ = w.Flush()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
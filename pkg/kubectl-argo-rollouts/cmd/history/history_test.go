@@ -0,0 +1,146 @@
+package history
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	options "github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options/fake"
+)
+
+var (
+	created  = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ownerRef = metav1.OwnerReference{
+		APIVersion: "argoproj.io/v1alpha1",
+		Kind:       "Rollout",
+		Name:       "guestbook",
+		UID:        "rollout-uid",
+		Controller: ptr.To(true),
+	}
+)
+
+func newReplicaSet(revision, hash, image, changeCause string, created time.Time) *appsv1.ReplicaSet {
+	rs := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "guestbook-" + hash,
+			Namespace:         "test",
+			CreationTimestamp: metav1.NewTime(created),
+			Labels:            map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: hash},
+			Annotations:       map[string]string{"rollout.argoproj.io/revision": revision},
+			OwnerReferences:   []metav1.OwnerReference{ownerRef},
+		},
+		Spec: appsv1.ReplicaSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "guestbook", Image: image}}},
+			},
+		},
+	}
+	if changeCause != "" {
+		rs.Annotations["kubernetes.io/change-cause"] = changeCause
+	}
+	return rs
+}
+
+func newAnalysisRun(name, revision string, phase v1alpha1.AnalysisPhase) *v1alpha1.AnalysisRun {
+	return &v1alpha1.AnalysisRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "test",
+			Annotations:     map[string]string{"rollout.argoproj.io/revision": revision},
+			OwnerReferences: []metav1.OwnerReference{ownerRef},
+		},
+		Status: v1alpha1.AnalysisRunStatus{Phase: phase},
+	}
+}
+
+func newCompletedEvent(name, message string, at time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "test"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Rollout", Name: "guestbook", UID: "rollout-uid"},
+		Reason:         "RolloutCompleted",
+		Message:        message,
+		LastTimestamp:  metav1.NewTime(at),
+	}
+}
+
+func TestHistoryCmdUsage(t *testing.T) {
+	tf, o := options.NewFakeArgoRolloutsOptions()
+	defer tf.Cleanup()
+	cmd := NewCmdHistory(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{})
+	err := cmd.Execute()
+	assert.Error(t, err)
+	stderr := o.ErrOut.(*bytes.Buffer).String()
+	assert.Contains(t, stderr, "Usage:")
+	assert.Contains(t, stderr, "history ROLLOUT_NAME")
+}
+
+func TestHistoryCmd(t *testing.T) {
+	ro := &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: "test", UID: "rollout-uid"},
+		Status:     v1alpha1.RolloutStatus{StableRS: "abc"},
+	}
+	other := newReplicaSet("5", "other", "other:v1", "", created)
+	other.OwnerReferences = nil
+	objs := []runtime.Object{
+		ro,
+		newReplicaSet("1", "abc", "guestbook:v1", "kubectl create", created),
+		newReplicaSet("2", "def", "guestbook:v2", "image updated to v2", created.Add(time.Hour)),
+		newReplicaSet("3", "ghi", "guestbook:v3", "", created.Add(2*time.Hour)),
+		other,
+		newAnalysisRun("guestbook-def-2-1", "2", v1alpha1.AnalysisPhaseSuccessful),
+		newAnalysisRun("guestbook-def-2-2", "2", v1alpha1.AnalysisPhaseFailed),
+		newAnalysisRun("guestbook-ghi-3-1", "3", v1alpha1.AnalysisPhaseRunning),
+		newAnalysisRun("guestbook-abc-1-1", "1", v1alpha1.AnalysisPhaseSuccessful),
+		newCompletedEvent("completed-1", "Rollout completed update to revision 1 (abc): Rollout completed", created.Add(5*time.Minute)),
+	}
+
+	tf, o := options.NewFakeArgoRolloutsOptions(objs...)
+	o.RESTClientGetter = tf.WithNamespace("test")
+	defer tf.Cleanup()
+	cmd := NewCmdHistory(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{"guestbook"})
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	stdout := o.Out.(*bytes.Buffer).String()
+	expected := `REVISION  IMAGES        CHANGE-CAUSE         CREATED               PROMOTED                       ANALYSIS
+3         guestbook:v3  -                    2024-01-01T02:00:00Z  -                              Running
+2         guestbook:v2  image updated to v2  2024-01-01T01:00:00Z  -                              Failed
+1         guestbook:v1  kubectl create       2024-01-01T00:00:00Z  2024-01-01T00:05:00Z (stable)  Successful
+`
+	assert.Equal(t, expected, stdout)
+}
+
+func TestHistoryCmdNoRevisions(t *testing.T) {
+	ro := &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: "test", UID: "rollout-uid"},
+	}
+	tf, o := options.NewFakeArgoRolloutsOptions(ro)
+	o.RESTClientGetter = tf.WithNamespace("test")
+	defer tf.Cleanup()
+	cmd := NewCmdHistory(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{"guestbook"})
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	assert.Empty(t, o.Out.(*bytes.Buffer).String())
+	assert.Equal(t, "No resources found.\n", o.ErrOut.(*bytes.Buffer).String())
+}
+
+func TestAnalysisOutcome(t *testing.T) {
+	assert.Equal(t, v1alpha1.AnalysisPhase(""), analysisOutcome(nil))
+	assert.Equal(t, v1alpha1.AnalysisPhaseSuccessful, analysisOutcome([]v1alpha1.AnalysisPhase{v1alpha1.AnalysisPhaseSuccessful}))
+	assert.Equal(t, v1alpha1.AnalysisPhaseRunning, analysisOutcome([]v1alpha1.AnalysisPhase{v1alpha1.AnalysisPhaseSuccessful, v1alpha1.AnalysisPhasePending}))
+	assert.Equal(t, v1alpha1.AnalysisPhaseInconclusive, analysisOutcome([]v1alpha1.AnalysisPhase{v1alpha1.AnalysisPhaseRunning, v1alpha1.AnalysisPhaseInconclusive}))
+	assert.Equal(t, v1alpha1.AnalysisPhaseError, analysisOutcome([]v1alpha1.AnalysisPhase{v1alpha1.AnalysisPhaseSuccessful, v1alpha1.AnalysisPhaseError}))
+}
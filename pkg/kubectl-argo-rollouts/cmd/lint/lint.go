@@ -24,7 +24,11 @@ import (
 
 type LintOptions struct {
 	options.ArgoRolloutsOptions
-	File string
+	Files []string
+	// CheckReferences reports the resources referenced by the rollouts which were not found
+	CheckReferences bool
+	// FromCluster looks up the referenced resources which are not part of the files in the live cluster
+	FromCluster bool
 }
 
 type roAndReferences struct {
@@ -35,7 +39,14 @@ type roAndReferences struct {
 const (
 	lintExample = `
 	# Lint a rollout
-	%[1]s lint -f my-rollout.yaml`
+	%[1]s lint -f my-rollout.yaml
+
+	# Lint a rollout and verify that the Services, Ingresses, VirtualServices and AnalysisTemplates it references
+	# are part of the files
+	%[1]s lint -f my-rollout.yaml -f my-services.yaml --check-references
+
+	# Lint a rollout and verify its references against the files and the live cluster
+	%[1]s lint -f my-rollout.yaml --check-references --from-cluster`
 )
 
 // NewCmdLint returns a new instance of a `rollouts lint` command
@@ -44,20 +55,24 @@ func NewCmdLint(o *options.ArgoRolloutsOptions) *cobra.Command {
 		ArgoRolloutsOptions: *o,
 	}
 	var cmd = &cobra.Command{
-		Use:          "lint",
-		Short:        "Lint and validate a Rollout",
-		Long:         "This command lints and validates a new Rollout resource from a file.",
+		Use:   "lint",
+		Short: "Lint and validate a Rollout",
+		Long: "This command lints and validates a new Rollout resource from one or more files. The Services, Ingresses, " +
+			"VirtualServices and AnalysisTemplates referenced by the rollout are validated against the rollout when they are " +
+			"part of the files, or of the live cluster with --from-cluster.",
 		Example:      o.Example(lintExample),
 		SilenceUsage: true,
 		RunE: func(c *cobra.Command, args []string) error {
-			if lintOptions.File == "" {
+			if len(lintOptions.Files) == 0 {
 				return o.UsageErr(c)
 			}
 
-			return lintOptions.lintResource(lintOptions.File)
+			return lintOptions.lintResource(lintOptions.Files...)
 		},
 	}
-	cmd.Flags().StringVarP(&lintOptions.File, "filename", "f", "", "File to lint")
+	cmd.Flags().StringArrayVarP(&lintOptions.Files, "filename", "f", nil, "File to lint. Can be repeated to lint the resources of several files together")
+	cmd.Flags().BoolVar(&lintOptions.CheckReferences, "check-references", false, "Report the Services, Ingresses, VirtualServices and AnalysisTemplates referenced by a rollout which were not found")
+	cmd.Flags().BoolVar(&lintOptions.FromCluster, "from-cluster", false, "Look up the referenced resources which are not part of the files in the namespace of the live cluster")
 	return cmd
 }
 
@@ -65,14 +80,71 @@ func unmarshal(fileBytes []byte, obj any) error {
 	return yaml.UnmarshalStrict(fileBytes, &obj, yaml.DisallowUnknownFields)
 }
 
-func (l *LintOptions) lintResource(path string) error {
+func (l *LintOptions) lintResource(paths ...string) error {
+	var refResource validation.ReferencedResources
+	var fileRollouts []v1alpha1.Rollout
+	templates := newAnalysisTemplates()
+
+	for _, path := range paths {
+		rollouts, err := readResources(path, &refResource, templates)
+		if err != nil {
+			return err
+		}
+		fileRollouts = append(fileRollouts, rollouts...)
+	}
+
+	resolver := referenceResolver{
+		lintOptions:     l,
+		refResource:     &refResource,
+		templates:       templates,
+		checkReferences: l.CheckReferences,
+		cluster:         l.FromCluster,
+	}
+	var errList field.ErrorList
+	for i := range fileRollouts {
+		errs, err := resolver.resolveReferences(&fileRollouts[i])
+		if err != nil {
+			return err
+		}
+		errList = append(errList, errs...)
+	}
+
+	setServiceTypeAndManagedAnnotation(fileRollouts, refResource)
+	setIngressManagedAnnotation(fileRollouts, refResource)
+	setVirtualServiceManagedAnnotation(fileRollouts, refResource)
+
+	for _, rollout := range fileRollouts {
+		roRef := matchRolloutToReferences(rollout, refResource)
+		analyses, errs, err := resolver.resolveAnalysisTemplates(&rollout)
+		if err != nil {
+			return err
+		}
+		errList = append(errList, errs...)
+		roRef.References.AnalysisTemplatesWithType = analyses
+
+		errList = append(errList, validation.ValidateRollout(&roRef.Rollout)...)
+		errList = append(errList, validation.ValidateRolloutReferencedResources(&roRef.Rollout, roRef.References)...)
+	}
+
+	for _, e := range errList {
+		fmt.Println(e.ErrorBody())
+	}
+	if len(errList) > 0 {
+		return errList[0]
+	} else {
+		return nil
+	}
+}
+
+// readResources decodes the documents of a file and returns its rollouts. The resources which may be referenced by
+// the rollouts are added to refResource and templates.
+func readResources(path string, refResource *validation.ReferencedResources, templates analysisTemplates) ([]v1alpha1.Rollout, error) {
 	fileBytes, err := os.ReadFile(path)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var un unstructured.Unstructured
-	var refResource validation.ReferencedResources
 	var fileRollouts []v1alpha1.Rollout
 
 	decoder := goyaml.NewDecoder(bytes.NewReader(fileBytes))
@@ -80,7 +152,7 @@ func (l *LintOptions) lintResource(path string) error {
 		var value any
 		if err := decoder.Decode(&value); err != nil {
 			if err != io.EOF {
-				return err
+				return nil, err
 			}
 			break
 		}
@@ -89,11 +161,11 @@ func (l *LintOptions) lintResource(path string) error {
 		}
 		valueBytes, err := goyaml.Marshal(value)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		if err = yaml.UnmarshalStrict(valueBytes, &un, yaml.DisallowUnknownFields); err != nil {
-			return err
+			return nil, err
 		}
 
 		gvk := un.GroupVersionKind()
@@ -101,42 +173,22 @@ func (l *LintOptions) lintResource(path string) error {
 			var ro v1alpha1.Rollout
 			err := unmarshal(valueBytes, &ro)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			fileRollouts = append(fileRollouts, ro)
 		}
-		err = buildAllReferencedResources(un, &refResource)
+		err = buildAllReferencedResources(un, refResource, templates)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
-
-	setServiceTypeAndManagedAnnotation(fileRollouts, refResource)
-	setIngressManagedAnnotation(fileRollouts, refResource)
-	setVirtualServiceManagedAnnotation(fileRollouts, refResource)
-
-	var errList field.ErrorList
-	for _, rollout := range fileRollouts {
-		roRef := matchRolloutToReferences(rollout, refResource)
-
-		errList = append(errList, validation.ValidateRollout(&roRef.Rollout)...)
-		errList = append(errList, validation.ValidateRolloutReferencedResources(&roRef.Rollout, roRef.References)...)
-	}
-
-	for _, e := range errList {
-		fmt.Println(e.ErrorBody())
-	}
-	if len(errList) > 0 {
-		return errList[0]
-	} else {
-		return nil
-	}
+	return fileRollouts, nil
 }
 
 // buildAllReferencedResources This builds a ReferencedResources object that has all the external resources for every
 // rollout resource in the manifest. We will need to later match each referenced resource to its own rollout resource
 // before passing the rollout object and its managed reference on to validation.
-func buildAllReferencedResources(un unstructured.Unstructured, refResource *validation.ReferencedResources) error {
+func buildAllReferencedResources(un unstructured.Unstructured, refResource *validation.ReferencedResources, templates analysisTemplates) error {
 
 	valueBytes, err := un.MarshalJSON()
 	if err != nil {
@@ -155,6 +207,22 @@ func buildAllReferencedResources(un unstructured.Unstructured, refResource *vali
 			Service: &svc,
 		})
 
+	case gvk.Group == rollouts.Group && gvk.Kind == rollouts.AnalysisTemplateKind:
+		var template v1alpha1.AnalysisTemplate
+		err := unmarshal(valueBytes, &template)
+		if err != nil {
+			return err
+		}
+		templates.Templates[template.Name] = &template
+
+	case gvk.Group == rollouts.Group && gvk.Kind == rollouts.ClusterAnalysisTemplateKind:
+		var template v1alpha1.ClusterAnalysisTemplate
+		err := unmarshal(valueBytes, &template)
+		if err != nil {
+			return err
+		}
+		templates.ClusterTemplates[template.Name] = &template
+
 	case gvk.Group == "networking.istio.io" && gvk.Kind == "VirtualService":
 		refResource.VirtualServices = append(refResource.VirtualServices, un)
 
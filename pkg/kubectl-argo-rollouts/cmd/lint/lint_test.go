@@ -5,6 +5,12 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 
 	options "github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options/fake"
 )
@@ -31,6 +37,7 @@ func TestLintValidRollout(t *testing.T) {
 		"testdata/valid-nginx-basic-canary.yml",
 		"testdata/valid-istio-v1beta1-mulitiple-virtualsvcs.yml",
 		"testdata/valid-nginx-smi-with-vsvc.yaml",
+		"testdata/valid-analysis-template.yml",
 	}
 
 	for _, filename := range tests {
@@ -88,6 +95,10 @@ func TestLintInvalidRollout(t *testing.T) {
 			filename: "testdata/invalid-nginx-canary.yml",
 			errmsg:   "Error: spec.strategy.steps[1].experiment.templates[0].weight: Invalid value: 20: Experiment template weight is only available for TrafficRouting with SMI, ALB, Istio and Plugins at this time\n",
 		},
+		{
+			"testdata/invalid-analysis-args.yml",
+			"Error: spec.strategy.canary.steps[1].analysis.templates: Invalid value: \"templateNames: [success-rate]\": args.service-name was not resolved\n",
+		},
 	}
 
 	runCmd = func(filename string, errmsg string) {
@@ -112,3 +123,109 @@ func TestLintInvalidRollout(t *testing.T) {
 		runCmd(t.filename, t.errmsg)
 	}
 }
+
+func TestLintCheckReferences(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		objs   []runtime.Object
+		errmsg string
+	}{
+		{
+			// the references are only checked with --check-references
+			name: "ReferencesNotChecked",
+			args: []string{"-f", "testdata/invalid-missing-references.yml"},
+		},
+		{
+			name:   "MissingReferences",
+			args:   []string{"-f", "testdata/invalid-missing-references.yml", "--check-references"},
+			errmsg: "Error: spec.strategy.canary.canaryService: Invalid value: \"missing-references-canary\": Service 'missing-references-canary' not found\n",
+		},
+		{
+			name: "MultipleFiles",
+			args: []string{"-f", "testdata/invalid-missing-references.yml", "-f", "testdata/missing-references-dependencies.yml", "--check-references"},
+		},
+		{
+			name: "ReferencesNotLookedUpInCluster",
+			args: []string{"-f", "testdata/invalid-missing-references.yml", "--check-references"},
+			objs: []runtime.Object{
+				&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "missing-references-canary", Namespace: "default"}},
+			},
+			errmsg: "Error: spec.strategy.canary.canaryService: Invalid value: \"missing-references-canary\": Service 'missing-references-canary' not found\n",
+		},
+		{
+			name: "MissingReferencesInCluster",
+			args: []string{"-f", "testdata/invalid-missing-references.yml", "--check-references", "--from-cluster"},
+			objs: []runtime.Object{
+				&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "missing-references-canary", Namespace: "default"}},
+			},
+			errmsg: "Error: spec.strategy.canary.trafficRouting.nginx.stableIngress: Invalid value: \"missing-references-ingress\": Ingress 'missing-references-ingress' not found\n",
+		},
+		{
+			name: "ReferencesInCluster",
+			args: []string{"-f", "testdata/invalid-missing-references.yml", "--check-references", "--from-cluster"},
+			objs: []runtime.Object{
+				&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "missing-references-canary", Namespace: "default"}},
+				newStableIngress("missing-references-ingress", "missing-references-stable"),
+				&v1alpha1.AnalysisTemplate{ObjectMeta: metav1.ObjectMeta{Name: "success-rate", Namespace: "default"}},
+			},
+		},
+		{
+			// the Service of the files is preferred over the one of the cluster with unmatched labels
+			name: "FilesPreferredOverCluster",
+			args: []string{"-f", "testdata/invalid-missing-references.yml", "-f", "testdata/missing-references-dependencies.yml", "--from-cluster"},
+			objs: []runtime.Object{
+				&corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{Name: "missing-references-canary", Namespace: "default"},
+					Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "other"}},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tf, o := options.NewFakeArgoRolloutsOptions(test.objs...)
+			defer tf.Cleanup()
+			o.RESTClientGetter = tf.WithNamespace("default")
+
+			cmd := NewCmdLint(o)
+			cmd.PersistentPreRunE = o.PersistentPreRunE
+			cmd.SetArgs(test.args)
+			err := cmd.Execute()
+
+			stderr := o.ErrOut.(*bytes.Buffer).String()
+			if test.errmsg == "" {
+				assert.NoError(t, err)
+				assert.Empty(t, stderr)
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, test.errmsg, stderr)
+			}
+		})
+	}
+}
+
+func newStableIngress(name, stableService string) *networkingv1.Ingress {
+	pathType := networkingv1.PathTypePrefix
+	return &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{{
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{
+							Path:     "/",
+							PathType: &pathType,
+							Backend: networkingv1.IngressBackend{
+								Service: &networkingv1.IngressServiceBackend{
+									Name: stableService,
+									Port: networkingv1.ServiceBackendPort{Number: 80},
+								},
+							},
+						}},
+					},
+				},
+			}},
+		},
+	}
+}
@@ -0,0 +1,349 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/validation"
+	analysisutil "github.com/argoproj/argo-rollouts/utils/analysis"
+	ingressutil "github.com/argoproj/argo-rollouts/utils/ingress"
+	istioutil "github.com/argoproj/argo-rollouts/utils/istio"
+)
+
+const (
+	serviceKind        = "Service"
+	ingressKind        = "Ingress"
+	virtualServiceKind = "VirtualService"
+)
+
+// rolloutReference is a Service, Ingress or VirtualService referenced by a field of a rollout
+type rolloutReference struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Path      *field.Path
+}
+
+// rolloutAnalysisReference is an analysis of a rollout which references analysis templates
+type rolloutAnalysisReference struct {
+	Analysis        *v1alpha1.RolloutAnalysis
+	TemplateType    validation.AnalysisTemplateType
+	CanaryStepIndex int
+}
+
+// analysisTemplates holds the analysis templates of the linted files by name
+type analysisTemplates struct {
+	Templates        map[string]*v1alpha1.AnalysisTemplate
+	ClusterTemplates map[string]*v1alpha1.ClusterAnalysisTemplate
+}
+
+func newAnalysisTemplates() analysisTemplates {
+	return analysisTemplates{
+		Templates:        map[string]*v1alpha1.AnalysisTemplate{},
+		ClusterTemplates: map[string]*v1alpha1.ClusterAnalysisTemplate{},
+	}
+}
+
+// referenceResolver looks up the resources referenced by the rollouts in the linted files, and in the live cluster if
+// enabled. The resources found in the cluster are added to the referenced resources of the files, so that they are
+// validated the same way.
+type referenceResolver struct {
+	lintOptions     *LintOptions
+	refResource     *validation.ReferencedResources
+	templates       analysisTemplates
+	checkReferences bool
+	cluster         bool
+}
+
+// getRolloutReferences returns the Services, Ingresses and VirtualServices referenced by the rollout
+func getRolloutReferences(rollout *v1alpha1.Rollout) []rolloutReference {
+	var refs []rolloutReference
+	add := func(kind, name string, path *field.Path) {
+		if name != "" {
+			refs = append(refs, rolloutReference{Kind: kind, Namespace: rollout.Namespace, Name: name, Path: path})
+		}
+	}
+	strategyPath := field.NewPath("spec", "strategy")
+	if blueGreen := rollout.Spec.Strategy.BlueGreen; blueGreen != nil {
+		add(serviceKind, blueGreen.ActiveService, strategyPath.Child("blueGreen", "activeService"))
+		add(serviceKind, blueGreen.PreviewService, strategyPath.Child("blueGreen", "previewService"))
+	}
+	canary := rollout.Spec.Strategy.Canary
+	if canary == nil {
+		return refs
+	}
+	canaryPath := strategyPath.Child("canary")
+	add(serviceKind, canary.CanaryService, canaryPath.Child("canaryService"))
+	add(serviceKind, canary.StableService, canaryPath.Child("stableService"))
+	if canary.PingPong != nil {
+		add(serviceKind, canary.PingPong.PingService, canaryPath.Child("pingPong", "pingService"))
+		add(serviceKind, canary.PingPong.PongService, canaryPath.Child("pingPong", "pongService"))
+	}
+	trafficRouting := canary.TrafficRouting
+	if trafficRouting == nil {
+		return refs
+	}
+	trafficRoutingPath := canaryPath.Child("trafficRouting")
+	if nginx := trafficRouting.Nginx; nginx != nil {
+		add(ingressKind, nginx.StableIngress, trafficRoutingPath.Child("nginx", "stableIngress"))
+		for i, ingress := range nginx.StableIngresses {
+			add(ingressKind, ingress, trafficRoutingPath.Child("nginx", "stableIngresses").Index(i))
+		}
+	}
+	if alb := trafficRouting.ALB; alb != nil {
+		add(ingressKind, alb.Ingress, trafficRoutingPath.Child("alb", "ingress"))
+		for i, ingress := range alb.Ingresses {
+			add(ingressKind, ingress, trafficRoutingPath.Child("alb", "ingresses").Index(i))
+		}
+		add(serviceKind, alb.RootService, trafficRoutingPath.Child("alb", "rootService"))
+	}
+	if smi := trafficRouting.SMI; smi != nil {
+		add(serviceKind, smi.RootService, trafficRoutingPath.Child("smi", "rootService"))
+	}
+	if istio := trafficRouting.Istio; istio != nil {
+		if istio.VirtualService != nil {
+			add(virtualServiceKind, istio.VirtualService.Name, trafficRoutingPath.Child("istio", "virtualService", "name"))
+		}
+		for i, virtualService := range istio.VirtualServices {
+			add(virtualServiceKind, virtualService.Name, trafficRoutingPath.Child("istio", "virtualServices").Index(i).Child("name"))
+		}
+	}
+	return refs
+}
+
+// getRolloutAnalysisReferences returns the analyses of the rollout which reference analysis templates
+func getRolloutAnalysisReferences(rollout *v1alpha1.Rollout) []rolloutAnalysisReference {
+	var refs []rolloutAnalysisReference
+	add := func(analysis *v1alpha1.RolloutAnalysis, templateType validation.AnalysisTemplateType, canaryStepIndex int) {
+		if analysis != nil && len(analysis.Templates) > 0 {
+			refs = append(refs, rolloutAnalysisReference{Analysis: analysis, TemplateType: templateType, CanaryStepIndex: canaryStepIndex})
+		}
+	}
+	if blueGreen := rollout.Spec.Strategy.BlueGreen; blueGreen != nil {
+		add(blueGreen.PrePromotionAnalysis, validation.PrePromotionAnalysis, 0)
+		add(blueGreen.PostPromotionAnalysis, validation.PostPromotionAnalysis, 0)
+		if blueGreen.PostPromotionSmokeTest != nil {
+			add(&v1alpha1.RolloutAnalysis{
+				Templates: blueGreen.PostPromotionSmokeTest.Templates,
+				Args:      blueGreen.PostPromotionSmokeTest.Args,
+			}, validation.PostPromotionAnalysis, 0)
+		}
+	} else if canary := rollout.Spec.Strategy.Canary; canary != nil {
+		for i, step := range canary.Steps {
+			add(step.Analysis, validation.InlineAnalysis, i)
+		}
+		if canary.Analysis != nil {
+			add(&canary.Analysis.RolloutAnalysis, validation.BackgroundAnalysis, 0)
+		}
+		if canary.Guardrail != nil {
+			add(&canary.Guardrail.RolloutAnalysis, validation.GuardrailAnalysis, 0)
+		}
+	}
+	if rollout.Spec.RestartStrategy != nil {
+		add(rollout.Spec.RestartStrategy.Analysis, validation.RestartAnalysis, 0)
+	}
+	return refs
+}
+
+func (r *referenceResolver) namespace(namespace string) string {
+	if namespace != "" {
+		return namespace
+	}
+	return r.lintOptions.Namespace()
+}
+
+// resolveReferences looks up the Services, Ingresses and VirtualServices referenced by the rollout and returns an
+// error for each one which was not found if references are checked
+func (r *referenceResolver) resolveReferences(rollout *v1alpha1.Rollout) (field.ErrorList, error) {
+	allErrs := field.ErrorList{}
+	for _, ref := range getRolloutReferences(rollout) {
+		var found bool
+		var err error
+		switch ref.Kind {
+		case serviceKind:
+			found, err = r.resolveService(ref)
+		case ingressKind:
+			found, err = r.resolveIngress(ref)
+		case virtualServiceKind:
+			found, err = r.resolveVirtualService(ref)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !found && r.checkReferences {
+			allErrs = append(allErrs, field.Invalid(ref.Path, ref.Name, fmt.Sprintf("%s '%s' not found", ref.Kind, ref.Name)))
+		}
+	}
+	return allErrs, nil
+}
+
+func (r *referenceResolver) resolveService(ref rolloutReference) (bool, error) {
+	for _, service := range r.refResource.ServiceWithType {
+		if service.Service.Name == ref.Name {
+			return true, nil
+		}
+	}
+	if !r.cluster {
+		return false, nil
+	}
+	service, err := r.lintOptions.KubeClientset().CoreV1().Services(r.namespace(ref.Namespace)).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return false, ignoreNotFound(err)
+	}
+	r.refResource.ServiceWithType = append(r.refResource.ServiceWithType, validation.ServiceWithType{Service: service})
+	return true, nil
+}
+
+func (r *referenceResolver) resolveIngress(ref rolloutReference) (bool, error) {
+	for _, ingress := range r.refResource.Ingresses {
+		if ingress.GetName() == ref.Name {
+			return true, nil
+		}
+	}
+	if !r.cluster {
+		return false, nil
+	}
+	ingress, err := r.lintOptions.KubeClientset().NetworkingV1().Ingresses(r.namespace(ref.Namespace)).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return false, ignoreNotFound(err)
+	}
+	r.refResource.Ingresses = append(r.refResource.Ingresses, *ingressutil.NewIngress(ingress))
+	return true, nil
+}
+
+func (r *referenceResolver) resolveVirtualService(ref rolloutReference) (bool, error) {
+	namespace, name := istioutil.GetVirtualServiceNamespaceName(ref.Name)
+	for _, virtualService := range r.refResource.VirtualServices {
+		if virtualService.GetName() == ref.Name || virtualService.GetName() == name {
+			return true, nil
+		}
+	}
+	if !r.cluster {
+		return false, nil
+	}
+	if namespace == "" {
+		namespace = ref.Namespace
+	}
+	virtualServiceIf := r.lintOptions.DynamicClientset().Resource(istioutil.GetIstioVirtualServiceGVR()).Namespace(r.namespace(namespace))
+	virtualService, err := virtualServiceIf.Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return false, ignoreNotFound(err)
+	}
+	r.refResource.VirtualServices = append(r.refResource.VirtualServices, *virtualService)
+	return true, nil
+}
+
+// resolveAnalysisTemplates looks up the analysis templates referenced by the analyses of the rollout, including the
+// nested ones. The templates of an analysis are only returned for validation if all of them were found, and an error
+// is returned for each missing one if references are checked.
+func (r *referenceResolver) resolveAnalysisTemplates(rollout *v1alpha1.Rollout) ([]validation.AnalysisTemplatesWithType, field.ErrorList, error) {
+	var analyses []validation.AnalysisTemplatesWithType
+	allErrs := field.ErrorList{}
+	for _, ref := range getRolloutAnalysisReferences(rollout) {
+		fldPath := validation.GetAnalysisTemplateWithTypeFieldPath(ref.TemplateType, ref.CanaryStepIndex)
+		templates, clusterTemplates, errs, err := r.resolveAnalysisTemplatesFromRef(rollout.Namespace, ref.Analysis.Templates, fldPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(errs) > 0 {
+			if r.checkReferences {
+				allErrs = append(allErrs, errs...)
+			}
+			continue
+		}
+		templates, clusterTemplates = analysisutil.FilterUniqueTemplates(templates, clusterTemplates)
+		analyses = append(analyses, validation.AnalysisTemplatesWithType{
+			AnalysisTemplates:        templates,
+			ClusterAnalysisTemplates: clusterTemplates,
+			TemplateType:             ref.TemplateType,
+			CanaryStepIndex:          ref.CanaryStepIndex,
+			Args:                     ref.Analysis.Args,
+		})
+	}
+	return analyses, allErrs, nil
+}
+
+func (r *referenceResolver) resolveAnalysisTemplatesFromRef(namespace string, templateRefs []v1alpha1.AnalysisTemplateRef, fldPath *field.Path) ([]*v1alpha1.AnalysisTemplate, []*v1alpha1.ClusterAnalysisTemplate, field.ErrorList, error) {
+	var templates []*v1alpha1.AnalysisTemplate
+	var clusterTemplates []*v1alpha1.ClusterAnalysisTemplate
+	allErrs := field.ErrorList{}
+	for _, templateRef := range templateRefs {
+		var nestedRefs []v1alpha1.AnalysisTemplateRef
+		if templateRef.IsClusterScope() {
+			template, err := r.resolveClusterAnalysisTemplate(templateRef.TemplateName)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			if template == nil {
+				allErrs = append(allErrs, field.Invalid(fldPath, templateRef.TemplateName, fmt.Sprintf("%s '%s' not found", rollouts.ClusterAnalysisTemplateKind, templateRef.TemplateName)))
+				continue
+			}
+			clusterTemplates = append(clusterTemplates, template)
+			nestedRefs = template.Spec.Templates
+		} else {
+			template, err := r.resolveAnalysisTemplate(namespace, templateRef.TemplateName)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			if template == nil {
+				allErrs = append(allErrs, field.Invalid(fldPath, templateRef.TemplateName, fmt.Sprintf("%s '%s' not found", rollouts.AnalysisTemplateKind, templateRef.TemplateName)))
+				continue
+			}
+			templates = append(templates, template)
+			nestedRefs = template.Spec.Templates
+		}
+		if len(nestedRefs) > 0 {
+			innerTemplates, innerClusterTemplates, innerErrs, err := r.resolveAnalysisTemplatesFromRef(namespace, nestedRefs, field.NewPath("spec", "templates"))
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			templates = append(templates, innerTemplates...)
+			clusterTemplates = append(clusterTemplates, innerClusterTemplates...)
+			allErrs = append(allErrs, innerErrs...)
+		}
+	}
+	return templates, clusterTemplates, allErrs, nil
+}
+
+func (r *referenceResolver) resolveAnalysisTemplate(namespace, name string) (*v1alpha1.AnalysisTemplate, error) {
+	if template, ok := r.templates.Templates[name]; ok {
+		return template, nil
+	}
+	if !r.cluster {
+		return nil, nil
+	}
+	template, err := r.lintOptions.RolloutsClientset().ArgoprojV1alpha1().AnalysisTemplates(r.namespace(namespace)).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, ignoreNotFound(err)
+	}
+	r.templates.Templates[name] = template
+	return template, nil
+}
+
+func (r *referenceResolver) resolveClusterAnalysisTemplate(name string) (*v1alpha1.ClusterAnalysisTemplate, error) {
+	if template, ok := r.templates.ClusterTemplates[name]; ok {
+		return template, nil
+	}
+	if !r.cluster {
+		return nil, nil
+	}
+	template, err := r.lintOptions.RolloutsClientset().ArgoprojV1alpha1().ClusterAnalysisTemplates().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, ignoreNotFound(err)
+	}
+	r.templates.ClusterTemplates[name] = template
+	return template, nil
+}
+
+func ignoreNotFound(err error) error {
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
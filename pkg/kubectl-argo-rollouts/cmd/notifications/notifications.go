@@ -0,0 +1,264 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/argoproj/notifications-engine/pkg/api"
+	notificationcmd "github.com/argoproj/notifications-engine/pkg/cmd"
+	"github.com/argoproj/notifications-engine/pkg/services"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	informersv1 "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/yaml"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
+	"github.com/argoproj/argo-rollouts/utils/record"
+)
+
+const (
+	notificationsTestExample = `
+	# Render the notification of the on-rollout-aborted trigger for a rollout in the console
+	%[1]s notifications test guestbook --trigger on-rollout-aborted
+
+	# Send the notification of the rollout-completed template for a rollout to a Slack channel
+	%[1]s notifications test guestbook --template rollout-completed --recipient slack:my-channel`
+)
+
+// TestOptions are the options of the `rollouts notifications test` command
+type TestOptions struct {
+	Settings   api.Settings
+	Trigger    string
+	Template   string
+	Recipients []string
+
+	// ConfigMapPath and SecretPath are the files of the notification configmap and secret. The configmap and the
+	// secret of the cluster are used if they are empty, and an empty secret if the SecretPath is ':empty'.
+	ConfigMapPath string
+	SecretPath    string
+
+	Namespace     string
+	KubeClient    kubernetes.Interface
+	DynamicClient dynamic.Interface
+	Out           io.Writer
+	clientConfig  clientcmd.ClientConfig
+}
+
+// NewCmdNotifications returns a new instance of an `rollouts notifications` command, which adds a test command to the
+// notification tools
+func NewCmdNotifications(o *options.ArgoRolloutsOptions) *cobra.Command {
+	testOptions := TestOptions{
+		Settings: record.NewAPIFactorySettings(nil),
+		Out:      o.Out,
+	}
+	cmd := notificationcmd.NewToolsCommand("notifications", "kubectl argo rollouts notifications", v1alpha1.RolloutGVR, testOptions.Settings,
+		func(_ context.Context, cfg clientcmd.ClientConfig) {
+			testOptions.clientConfig = cfg
+		})
+	cmd.AddCommand(NewCmdNotificationsTest(o, &testOptions))
+	return cmd
+}
+
+// NewCmdNotificationsTest returns a new instance of an `rollouts notifications test` command
+func NewCmdNotificationsTest(o *options.ArgoRolloutsOptions, testOptions *TestOptions) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "test ROLLOUT_NAME",
+		Short: "Render and send a test notification for a rollout",
+		Long: "Render the notification of a trigger or a template for a rollout and send it to the recipients, " +
+			"regardless of the conditions of the trigger. The notification is printed to the console unless " +
+			"recipients are given.",
+		Example:      o.Example(notificationsTestExample),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return o.UsageErr(c)
+			}
+			if (testOptions.Trigger == "") == (testOptions.Template == "") {
+				return fmt.Errorf("one of --trigger or --template must be specified")
+			}
+			if flag := c.Flags().Lookup("config-map"); flag != nil {
+				testOptions.ConfigMapPath = flag.Value.String()
+			}
+			if flag := c.Flags().Lookup("secret"); flag != nil {
+				testOptions.SecretPath = flag.Value.String()
+			}
+			if err := testOptions.initClients(); err != nil {
+				return err
+			}
+			return testOptions.Run(c.Context(), args[0])
+		},
+	}
+	cmd.Flags().StringVar(&testOptions.Trigger, "trigger", "", "Trigger whose templates are rendered")
+	cmd.Flags().StringVar(&testOptions.Template, "template", "", "Template which is rendered")
+	cmd.Flags().StringArrayVar(&testOptions.Recipients, "recipient", []string{"console:stdout"}, "Recipients of the notification in the form SERVICE:RECIPIENT (e.g. slack:my-channel)")
+	return cmd
+}
+
+// initClients creates the clients from the kubeconfig flags of the notification tools, unless they were set
+func (t *TestOptions) initClients() error {
+	if t.KubeClient != nil && t.DynamicClient != nil {
+		return nil
+	}
+	if t.clientConfig == nil {
+		return fmt.Errorf("kubeconfig is not initialized")
+	}
+	namespace, _, err := t.clientConfig.Namespace()
+	if err != nil {
+		return err
+	}
+	config, err := t.clientConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+	if t.KubeClient, err = kubernetes.NewForConfig(config); err != nil {
+		return err
+	}
+	if t.DynamicClient, err = dynamic.NewForConfig(config); err != nil {
+		return err
+	}
+	t.Namespace = namespace
+	return nil
+}
+
+// Run renders the notification of the trigger or the template for the rollout and sends it to the recipients
+func (t *TestOptions) Run(ctx context.Context, name string) error {
+	notificationAPI, err := t.getAPI(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load the notification configuration: %v", err)
+	}
+	notificationAPI.AddNotificationService("console", services.NewConsoleService(t.Out))
+
+	ro, err := t.DynamicClient.Resource(v1alpha1.RolloutGVR).Namespace(t.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	templates, err := t.templates(notificationAPI, ro)
+	if err != nil {
+		return err
+	}
+	for _, recipient := range t.Recipients {
+		service, to, _ := strings.Cut(recipient, ":")
+		dest := services.Destination{Service: service, Recipient: to}
+		if err := notificationAPI.Send(ro.Object, templates, dest); err != nil {
+			return fmt.Errorf("failed to notify '%s': %v", recipient, err)
+		}
+	}
+	return nil
+}
+
+// templates returns the template, or the templates of the conditions of the trigger. The outcome of the conditions of
+// the trigger for the rollout is printed, since the notification is sent regardless.
+func (t *TestOptions) templates(notificationAPI api.API, ro *unstructured.Unstructured) ([]string, error) {
+	if t.Template != "" {
+		if _, ok := notificationAPI.GetConfig().Templates[t.Template]; !ok {
+			return nil, fmt.Errorf("template '%s' is not configured", t.Template)
+		}
+		return []string{t.Template}, nil
+	}
+	conditions, ok := notificationAPI.GetConfig().Triggers[t.Trigger]
+	if !ok {
+		return nil, fmt.Errorf("trigger '%s' is not configured", t.Trigger)
+	}
+	results, err := notificationAPI.RunTrigger(t.Trigger, ro.Object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate trigger '%s': %v", t.Trigger, err)
+	}
+	for _, result := range results {
+		fmt.Fprintf(t.Out, "trigger '%s' condition triggered: %t\n", t.Trigger, result.Triggered)
+	}
+	seen := map[string]bool{}
+	var templates []string
+	for _, condition := range conditions {
+		for _, template := range condition.Send {
+			if !seen[template] {
+				seen[template] = true
+				templates = append(templates, template)
+			}
+		}
+	}
+	sort.Strings(templates)
+	return templates, nil
+}
+
+func (t *TestOptions) getAPI(ctx context.Context) (api.API, error) {
+	secret, err := t.getSecret(ctx)
+	if err != nil {
+		return nil, err
+	}
+	configMap, err := t.getConfigMap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	secretInformer := informersv1.NewSecretInformer(t.KubeClient, t.Namespace, 3*time.Minute, cache.Indexers{})
+	if err := secretInformer.GetStore().Add(secret); err != nil {
+		return nil, err
+	}
+	cmInformer := informersv1.NewConfigMapInformer(t.KubeClient, t.Namespace, 3*time.Minute, cache.Indexers{})
+	if err := cmInformer.GetStore().Add(configMap); err != nil {
+		return nil, err
+	}
+	return api.NewFactory(t.Settings, t.Namespace, secretInformer, cmInformer).GetAPI()
+}
+
+func (t *TestOptions) getSecret(ctx context.Context) (*corev1.Secret, error) {
+	var secret corev1.Secret
+	switch t.SecretPath {
+	case ":empty":
+	case "":
+		s, err := t.KubeClient.CoreV1().Secrets(t.Namespace).Get(ctx, t.Settings.SecretName, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		secret = *s
+	default:
+		if err := readFile(t.SecretPath, &secret); err != nil {
+			return nil, err
+		}
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		for k, v := range secret.StringData {
+			secret.Data[k] = []byte(v)
+		}
+	}
+	secret.Name = t.Settings.SecretName
+	secret.Namespace = t.Namespace
+	return &secret, nil
+}
+
+func (t *TestOptions) getConfigMap(ctx context.Context) (*corev1.ConfigMap, error) {
+	var configMap corev1.ConfigMap
+	if t.ConfigMapPath == "" {
+		cm, err := t.KubeClient.CoreV1().ConfigMaps(t.Namespace).Get(ctx, t.Settings.ConfigMapName, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		configMap = *cm
+	} else if err := readFile(t.ConfigMapPath, &configMap); err != nil {
+		return nil, err
+	}
+	configMap.Name = t.Settings.ConfigMapName
+	configMap.Namespace = t.Namespace
+	return &configMap, nil
+}
+
+func readFile(path string, obj any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(data, obj)
+}
@@ -0,0 +1,67 @@
+package notifications
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	options "github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options/fake"
+	"github.com/argoproj/argo-rollouts/utils/record"
+)
+
+func newTestCommand(args ...string) (*bytes.Buffer, error) {
+	ro := &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: "test"},
+	}
+	tf, o := options.NewFakeArgoRolloutsOptions(ro)
+	defer tf.Cleanup()
+	out := &bytes.Buffer{}
+	testOptions := TestOptions{
+		Settings:      record.NewAPIFactorySettings(nil),
+		Namespace:     "test",
+		KubeClient:    o.KubeClient,
+		DynamicClient: o.DynamicClient,
+		Out:           out,
+	}
+	cmd := NewCmdNotificationsTest(o, &testOptions)
+	testOptions.ConfigMapPath = "testdata/notification-configmap.yaml"
+	testOptions.SecretPath = ":empty"
+	cmd.SetArgs(args)
+	cmd.SetOut(out)
+	cmd.SetErr(out)
+	return out, cmd.Execute()
+}
+
+func TestNotificationsTestTemplate(t *testing.T) {
+	out, err := newTestCommand("guestbook", "--template", "rollout-paused")
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "Rollout guestbook has been paused.")
+}
+
+func TestNotificationsTestTrigger(t *testing.T) {
+	out, err := newTestCommand("guestbook", "--trigger", "on-rollout-aborted")
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "trigger 'on-rollout-aborted' condition triggered: false\n")
+	assert.Contains(t, out.String(), "Rollout guestbook has been aborted.")
+}
+
+func TestNotificationsTestErrors(t *testing.T) {
+	tests := []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"guestbook"}, "one of --trigger or --template must be specified"},
+		{[]string{"guestbook", "--trigger", "on-rollout-aborted", "--template", "rollout-paused"}, "one of --trigger or --template must be specified"},
+		{[]string{"guestbook", "--template", "does-not-exist"}, "template 'does-not-exist' is not configured"},
+		{[]string{"guestbook", "--trigger", "does-not-exist"}, "trigger 'does-not-exist' is not configured"},
+		{[]string{"guestbook", "--template", "rollout-paused", "--recipient", "slack:my-channel"}, "failed to notify 'slack:my-channel': notification service 'slack' is not supported"},
+		{[]string{"does-not-exist", "--template", "rollout-paused"}, "not found"},
+	}
+	for _, test := range tests {
+		_, err := newTestCommand(test.args...)
+		assert.ErrorContains(t, err, test.err)
+	}
+}
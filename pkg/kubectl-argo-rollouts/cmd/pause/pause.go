@@ -12,18 +12,26 @@ import (
 	completionutil "github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/util/completion"
 )
 
+const (
+	pausePatch = `{"spec":{"paused":true}}`
+)
+
 const (
 	pauseExample = `
   # Pause a rollout
-  %[1]s pause guestbook`
+  %[1]s pause guestbook
+
+  # Pause an experiment
+  %[1]s pause --experiment my-experiment`
 )
 
 // NewCmdPause returns a new instance of an `rollouts pause` command
 func NewCmdPause(o *options.ArgoRolloutsOptions) *cobra.Command {
+	var experiment bool
 	var cmd = &cobra.Command{
 		Use:          "pause ROLLOUT_NAME",
 		Short:        "Pause a rollout",
-		Long:         "Set the rollout paused state to 'true'",
+		Long:         "Set the rollout paused state to 'true'. With --experiment, set the paused state of experiments instead: a paused experiment keeps running, but the time it is paused does not count towards its duration and it does not complete until it is resumed.",
 		Example:      o.Example(pauseExample),
 		SilenceUsage: true,
 		RunE: func(c *cobra.Command, args []string) error {
@@ -31,9 +39,22 @@ func NewCmdPause(o *options.ArgoRolloutsOptions) *cobra.Command {
 				return o.UsageErr(c)
 			}
 			ns := o.Namespace()
+			if experiment {
+				// experiments are selected with a flag rather than a subcommand, which would shadow the rollouts
+				// named after it
+				experimentIf := o.RolloutsClientset().ArgoprojV1alpha1().Experiments(ns)
+				for _, name := range args {
+					ex, err := experimentIf.Patch(context.TODO(), name, types.MergePatchType, []byte(pausePatch), metav1.PatchOptions{})
+					if err != nil {
+						return err
+					}
+					fmt.Fprintf(o.Out, "experiment '%s' paused\n", ex.Name)
+				}
+				return nil
+			}
 			rolloutIf := o.RolloutsClientset().ArgoprojV1alpha1().Rollouts(ns)
 			for _, name := range args {
-				ro, err := rolloutIf.Patch(context.TODO(), name, types.MergePatchType, []byte(`{"spec":{"paused":true}}`), metav1.PatchOptions{})
+				ro, err := rolloutIf.Patch(context.TODO(), name, types.MergePatchType, []byte(pausePatch), metav1.PatchOptions{})
 				if err != nil {
 					return err
 				}
@@ -41,7 +62,13 @@ func NewCmdPause(o *options.ArgoRolloutsOptions) *cobra.Command {
 			}
 			return nil
 		},
-		ValidArgsFunction: completionutil.RolloutNameCompletionFunc(o),
+		ValidArgsFunction: func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if experiment {
+				return completionutil.ExperimentNameCompletionFunc(o)(c, args, toComplete)
+			}
+			return completionutil.RolloutNameCompletionFunc(o)(c, args, toComplete)
+		},
 	}
+	cmd.Flags().BoolVar(&experiment, "experiment", false, "Pause experiments rather than rollouts")
 	return cmd
 }
@@ -76,3 +76,56 @@ func TestPauseCmdError(t *testing.T) {
 	assert.Empty(t, stdout)
 	assert.Equal(t, "Error: rollouts.argoproj.io \"doesnotexist\" not found\n", stderr)
 }
+
+func TestPauseExperimentCmd(t *testing.T) {
+	ex := v1alpha1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-experiment",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+
+	tf, o := options.NewFakeArgoRolloutsOptions(&ex)
+	defer tf.Cleanup()
+	fakeClient := o.RolloutsClient.(*fakeroclient.Clientset)
+	fakeClient.PrependReactor("patch", "experiments", func(action kubetesting.Action) (handled bool, ret runtime.Object, err error) {
+		if patchAction, ok := action.(kubetesting.PatchAction); ok {
+			if string(patchAction.GetPatch()) == pausePatch {
+				ex.Spec.Paused = true
+			}
+		}
+		return true, &ex, nil
+	})
+
+	cmd := NewCmdPause(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{"--experiment", "my-experiment"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+
+	assert.True(t, ex.Spec.Paused)
+	stdout := o.Out.(*bytes.Buffer).String()
+	stderr := o.ErrOut.(*bytes.Buffer).String()
+	assert.Equal(t, "experiment 'my-experiment' paused\n", stdout)
+	assert.Empty(t, stderr)
+}
+
+func TestPauseRolloutNamedExperiment(t *testing.T) {
+	ro := v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "experiment",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+	tf, o := options.NewFakeArgoRolloutsOptions(&ro)
+	defer tf.Cleanup()
+
+	cmd := NewCmdPause(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{"experiment"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+
+	stdout := o.Out.(*bytes.Buffer).String()
+	assert.Equal(t, "rollout 'experiment' paused\n", stdout)
+}
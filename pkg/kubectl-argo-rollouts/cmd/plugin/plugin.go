@@ -0,0 +1,29 @@
+package plugin
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
+)
+
+const (
+	pluginExample = `
+  # Validate a step plugin executable against the step plugin RPC contract
+  %[1]s plugin conformance ./my-plugin --type step --name my-org/my-plugin`
+)
+
+// NewCmdPlugin returns a new instance of an `rollouts plugin` command
+func NewCmdPlugin(o *options.ArgoRolloutsOptions) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:          "plugin COMMAND",
+		Short:        "Develop Argo Rollouts plugins",
+		Long:         "This command consists of multiple subcommands which can be used by the authors of plugins.",
+		Example:      o.Example(pluginExample),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			return o.UsageErr(c)
+		},
+	}
+	cmd.AddCommand(NewCmdPluginConformance(o))
+	return cmd
+}
@@ -0,0 +1,121 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
+	"github.com/argoproj/argo-rollouts/utils/plugin/conformance"
+	"github.com/argoproj/argo-rollouts/utils/plugin/types"
+)
+
+const (
+	pluginConformanceExample = `
+  # Validate a traffic router plugin executable, with the config it expects in the rollouts
+  %[1]s plugin conformance ./my-plugin --type trafficrouter --name my-org/my-plugin --config config.yaml
+
+  # Validate a traffic router plugin with a rollout referencing existing services in the cluster
+  %[1]s plugin conformance ./my-plugin --type trafficrouter --name my-org/my-plugin --rollout rollout.yaml
+
+  # Validate a metric provider plugin executable started with arguments
+  %[1]s plugin conformance ./my-plugin --type metricprovider --name my-org/my-plugin --arg=--log-level=debug`
+)
+
+// pluginTypes are the plugin types accepted by --type
+var pluginTypes = map[string]types.PluginType{
+	"trafficrouter":  types.PluginTypeTrafficRouter,
+	"metricprovider": types.PluginTypeMetricProvider,
+	"step":           types.PluginTypeStep,
+}
+
+// NewCmdPluginConformance returns a new instance of an `rollouts plugin conformance` command
+func NewCmdPluginConformance(o *options.ArgoRolloutsOptions) *cobra.Command {
+	var (
+		pluginType  string
+		pluginName  string
+		configFile  string
+		rolloutFile string
+		pluginArgs  []string
+		timeout     time.Duration
+	)
+	var cmd = &cobra.Command{
+		Use:   "conformance PLUGIN_EXECUTABLE",
+		Short: "Validate a plugin against the RPC contract of its plugin type",
+		Long: "This command starts a plugin executable the way the controller does and runs golden scenarios of the RPC " +
+			"contract of its plugin type against it, e.g. shifting the traffic of a canary for a traffic router. The " +
+			"plugin operates on the resources it is configured with, so it should be run against a test environment.",
+		Example:      o.Example(pluginConformanceExample),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) != 1 || pluginName == "" {
+				return o.UsageErr(c)
+			}
+			typ, ok := pluginTypes[pluginType]
+			if !ok {
+				return fmt.Errorf("invalid plugin type '%s', must be one of trafficrouter, metricprovider or step", pluginType)
+			}
+			opts := conformance.Options{PluginName: pluginName, Timeout: timeout}
+			if configFile != "" {
+				config, err := readConfig(configFile)
+				if err != nil {
+					return err
+				}
+				opts.Config = config
+			}
+			if rolloutFile != "" {
+				ro, err := readRollout(rolloutFile)
+				if err != nil {
+					return err
+				}
+				opts.Rollout = ro
+			}
+			report, err := conformance.RunExecutable(typ, args[0], pluginArgs, opts)
+			if err != nil {
+				return err
+			}
+			report.Print(o.Out)
+			if failed := report.Failed(); failed > 0 {
+				return fmt.Errorf("plugin failed %d of %d scenarios", failed, len(report))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&pluginType, "type", "", "Type of the plugin: trafficrouter, metricprovider or step")
+	cmd.Flags().StringVar(&pluginName, "name", "", "Name of the plugin, under which its config is set in the rollouts and the metrics")
+	cmd.Flags().StringVar(&configFile, "config", "", "YAML or JSON file containing the config of the plugin in the rollouts, the metrics or the steps")
+	cmd.Flags().StringVar(&rolloutFile, "rollout", "", "File containing the rollout the traffic router and step scenarios are run with")
+	cmd.Flags().StringArrayVar(&pluginArgs, "arg", nil, "Command line argument of the plugin executable. Can be repeated")
+	cmd.Flags().DurationVar(&timeout, "timeout", conformance.DefaultTimeout, "Time given to the operations of the plugin which complete asynchronously")
+	return cmd
+}
+
+// readConfig returns the config of a YAML or JSON file as JSON
+func readConfig(path string) ([]byte, error) {
+	fileBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config, err := yaml.YAMLToJSON(fileBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config in %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// readRollout returns the rollout of a file
+func readRollout(path string) (*v1alpha1.Rollout, error) {
+	fileBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var ro v1alpha1.Rollout
+	if err := yaml.UnmarshalStrict(fileBytes, &ro); err != nil {
+		return nil, fmt.Errorf("invalid rollout in %s: %w", path, err)
+	}
+	return &ro, nil
+}
@@ -0,0 +1,114 @@
+package plugin
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	options "github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options/fake"
+)
+
+// buildStepPluginSample builds the sample step plugin and returns the path of its executable
+func buildStepPluginSample(t *testing.T) string {
+	t.Helper()
+	pluginBinary := filepath.Join(t.TempDir(), "step-plugin-sample")
+	out, err := exec.Command("go", "build", "-o", pluginBinary, "../../../../test/cmd/step-plugin-sample").CombinedOutput()
+	if err != nil {
+		t.Fatalf("failed to build the sample step plugin: %v\n%s", err, out)
+	}
+	return pluginBinary
+}
+
+func TestPluginCmdUsage(t *testing.T) {
+	tf, o := options.NewFakeArgoRolloutsOptions()
+	defer tf.Cleanup()
+	cmd := NewCmdPlugin(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{})
+	err := cmd.Execute()
+	assert.Error(t, err)
+	stdout := o.Out.(*bytes.Buffer).String()
+	stderr := o.ErrOut.(*bytes.Buffer).String()
+	assert.Empty(t, stdout)
+	assert.Contains(t, stderr, "Usage:")
+	assert.Contains(t, stderr, "plugin COMMAND")
+}
+
+func TestPluginConformanceCmdUsage(t *testing.T) {
+	tf, o := options.NewFakeArgoRolloutsOptions()
+	defer tf.Cleanup()
+	cmd := NewCmdPluginConformance(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{"./my-plugin", "--type", "step"})
+	err := cmd.Execute()
+	assert.Error(t, err)
+	stdout := o.Out.(*bytes.Buffer).String()
+	stderr := o.ErrOut.(*bytes.Buffer).String()
+	assert.Empty(t, stdout)
+	assert.Contains(t, stderr, "Usage:")
+	assert.Contains(t, stderr, "conformance PLUGIN_EXECUTABLE")
+}
+
+func TestPluginConformanceCmdInvalidType(t *testing.T) {
+	tf, o := options.NewFakeArgoRolloutsOptions()
+	defer tf.Cleanup()
+	cmd := NewCmdPluginConformance(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{"./my-plugin", "--type", "analysis", "--name", "my-org/my-plugin"})
+	err := cmd.Execute()
+	assert.EqualError(t, err, "invalid plugin type 'analysis', must be one of trafficrouter, metricprovider or step")
+}
+
+func TestPluginConformanceCmdInvalidFiles(t *testing.T) {
+	dir := t.TempDir()
+	invalid := filepath.Join(dir, "invalid.yaml")
+	assert.NoError(t, os.WriteFile(invalid, []byte("kind: Rollout\nspec: [invalid"), 0644))
+
+	for _, flag := range []string{"--config", "--rollout"} {
+		t.Run(flag, func(t *testing.T) {
+			tf, o := options.NewFakeArgoRolloutsOptions()
+			defer tf.Cleanup()
+			cmd := NewCmdPluginConformance(o)
+			cmd.PersistentPreRunE = o.PersistentPreRunE
+			cmd.SetArgs([]string{"./my-plugin", "--type", "step", "--name", "my-org/my-plugin", flag, invalid})
+			err := cmd.Execute()
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid.yaml")
+		})
+	}
+}
+
+func TestPluginConformanceCmd(t *testing.T) {
+	pluginBinary := buildStepPluginSample(t)
+	config := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(config, []byte("aggregate: false\n"), 0644))
+
+	t.Run("conformant plugin", func(t *testing.T) {
+		tf, o := options.NewFakeArgoRolloutsOptions()
+		defer tf.Cleanup()
+		cmd := NewCmdPluginConformance(o)
+		cmd.PersistentPreRunE = o.PersistentPreRunE
+		cmd.SetArgs([]string{pluginBinary, "--type", "step", "--name", "argoproj-labs/step-plugin-sample", "--config", config, "--arg=--seed", "--arg=1"})
+		err := cmd.Execute()
+		assert.NoError(t, err)
+		stdout := o.Out.(*bytes.Buffer).String()
+		assert.Contains(t, stdout, "SCENARIO")
+		assert.Regexp(t, `Run +PASS`, stdout)
+		assert.Regexp(t, `Terminate +SKIP +the step completes immediately`, stdout)
+	})
+
+	t.Run("wrong plugin type", func(t *testing.T) {
+		tf, o := options.NewFakeArgoRolloutsOptions()
+		defer tf.Cleanup()
+		cmd := NewCmdPluginConformance(o)
+		cmd.PersistentPreRunE = o.PersistentPreRunE
+		cmd.SetArgs([]string{pluginBinary, "--type", "trafficrouter", "--name", "argoproj-labs/step-plugin-sample"})
+		err := cmd.Execute()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unable to start plugin")
+	})
+}
@@ -22,12 +22,17 @@ const (
 	%[1]s promote guestbook
 
 	# Fully promote a rollout to desired version, skipping analysis, pauses, and steps
-	%[1]s promote guestbook --full`
+	%[1]s promote guestbook --full
+
+	# Promote a rollout directly to step 3, skipping the pauses and analysis of the steps before it
+	%[1]s promote guestbook --to-step 3 --yes`
 
 	promoteUsage = `Promote a rollout
 
 Promotes a rollout paused at a canary step, or a paused blue-green pre-promotion.
-To skip analysis, pauses and steps entirely, use '--full' to fully promote the rollout`
+To skip analysis, pauses and steps entirely, use '--full' to fully promote the rollout.
+To continue a canary rollout from a specific step, e.g. after a transient failure, use '--to-step'.
+Since the steps before it are skipped, promoting to a step must be confirmed with '--yes'`
 )
 
 const (
@@ -40,10 +45,15 @@ const (
 	clearPauseConditionsPatchWithStep           = `{"status":{"pauseConditions":null, "currentStepIndex":%d}}`
 	unpauseAndClearPauseConditionsPatchWithStep = `{"spec":{"paused":false},"status":{"pauseConditions":null, "currentStepIndex":%d}}`
 	unpauseAndPromoteFullPatch                  = `{"spec":{"paused":false},"status":{"promoteFull":true}}`
+	promoteToStepPatch                          = `{"status":{"abort":false,"pauseConditions":null,"controllerPause":false,"currentStepIndex":%d}}`
+	unpauseAndPromoteToStepPatch                = `{"spec":{"paused":false},"status":{"abort":false,"pauseConditions":null,"controllerPause":false,"currentStepIndex":%d}}`
 
 	useBothSkipFlagsError         = "Cannot use skip-current-step and skip-all-steps flags at the same time"
 	skipFlagsWithBlueGreenError   = "Cannot skip steps of a bluegreen rollout. Run without a flags"
 	skipFlagWithNoStepCanaryError = "Cannot skip steps of a rollout without steps"
+	toStepWithOtherFlagsError     = "Cannot use to-step with the full, skip-current-step or skip-all-steps flags"
+	toStepOutOfRangeError         = "Step %d is out of range, the rollout has %d steps"
+	toStepNotConfirmedError       = "Promoting rollout '%s' to step %d %s. Rerun with --yes to confirm"
 )
 
 // NewCmdPromote returns a new instance of an `rollouts promote` command
@@ -52,6 +62,8 @@ func NewCmdPromote(o *options.ArgoRolloutsOptions) *cobra.Command {
 		skipCurrentStep = false
 		skipAllSteps    = false
 		full            = false
+		toStep          = int32(0)
+		yes             = false
 	)
 	var cmd = &cobra.Command{
 		Use:          "promote ROLLOUT_NAME",
@@ -68,6 +80,17 @@ func NewCmdPromote(o *options.ArgoRolloutsOptions) *cobra.Command {
 			}
 			name := args[0]
 			rolloutIf := o.RolloutsClientset().ArgoprojV1alpha1().Rollouts(o.Namespace())
+			if c.Flags().Changed("to-step") {
+				if skipCurrentStep || skipAllSteps || full {
+					return fmt.Errorf(toStepWithOtherFlagsError)
+				}
+				ro, err := PromoteRolloutToStep(rolloutIf, name, toStep, yes)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(o.Out, "rollout '%s' promoted to step %d\n", ro.Name, toStep)
+				return nil
+			}
 			ro, err := PromoteRollout(rolloutIf, name, skipCurrentStep, skipAllSteps, full)
 			if err != nil {
 				return err
@@ -89,6 +112,9 @@ func NewCmdPromote(o *options.ArgoRolloutsOptions) *cobra.Command {
 	cmd.Flags().MarkDeprecated("skip-all-steps", "use --full instead")
 	cmd.Flags().MarkShorthandDeprecated("a", "use --full instead")
 	cmd.Flags().BoolVar(&full, "full", false, "Perform a full promotion, skipping analysis, pauses, and steps")
+	cmd.Flags().Int32Var(&toStep, "to-step", toStep, "Promote a canary rollout directly to the step with this index, skipping the analysis and pauses of the steps before it")
+	_ = cmd.RegisterFlagCompletionFunc("to-step", completionutil.StepCompletionFunc(o))
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Confirm skipping steps when promoting with --to-step")
 	return cmd
 }
 
@@ -135,6 +161,64 @@ func PromoteRollout(rolloutIf clientset.RolloutInterface, name string, skipCurre
 	return ro, nil
 }
 
+// PromoteRolloutToStep promotes a canary rollout directly to the step with the given index. Since the analysis and
+// pauses of the skipped steps do not run, it fails unless confirmed.
+func PromoteRolloutToStep(rolloutIf clientset.RolloutInterface, name string, step int32, confirmed bool) (*v1alpha1.Rollout, error) {
+	ctx := context.TODO()
+	ro, err := rolloutIf.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if ro.Spec.Strategy.Canary == nil {
+		return nil, fmt.Errorf(skipFlagsWithBlueGreenError)
+	}
+	steps := ro.Spec.Strategy.Canary.Steps
+	if len(steps) == 0 {
+		return nil, fmt.Errorf(skipFlagWithNoStepCanaryError)
+	}
+	if step < 0 || step > int32(len(steps)) {
+		return nil, fmt.Errorf(toStepOutOfRangeError, step, len(steps))
+	}
+	if !confirmed {
+		return nil, fmt.Errorf(toStepNotConfirmedError, name, step, describeStepChange(ro, step))
+	}
+
+	statusPatch := []byte(fmt.Sprintf(promoteToStepPatch, step))
+	ro, err = rolloutIf.Patch(ctx, name, types.MergePatchType, statusPatch, metav1.PatchOptions{}, "status")
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return nil, err
+		}
+		// status subresource is not being used (v0.9), so perform a unified patch
+		return rolloutIf.Patch(ctx, name, types.MergePatchType, []byte(fmt.Sprintf(unpauseAndPromoteToStepPatch, step)), metav1.PatchOptions{})
+	}
+	if ro.Spec.Paused {
+		ro, err = rolloutIf.Patch(ctx, name, types.MergePatchType, []byte(unpausePatch), metav1.PatchOptions{})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ro, nil
+}
+
+// describeStepChange describes the effect of promoting the rollout from its current step to the given one
+func describeStepChange(ro *v1alpha1.Rollout, step int32) string {
+	current := int32(0)
+	if ro.Status.CurrentStepIndex != nil {
+		current = *ro.Status.CurrentStepIndex
+	}
+	switch {
+	case step < current:
+		return fmt.Sprintf("goes back from step %d and runs the steps from there again", current)
+	case step == current:
+		return fmt.Sprintf("runs step %d again", current)
+	case step-current == 1:
+		return fmt.Sprintf("skips step %d", current)
+	default:
+		return fmt.Sprintf("skips steps %d to %d", current, step-1)
+	}
+}
+
 func isInconclusive(rollout *v1alpha1.Rollout) bool {
 	return rollout.Spec.Strategy.Canary != nil && rollout.Status.Canary.CurrentStepAnalysisRunStatus != nil && rollout.Status.Canary.CurrentStepAnalysisRunStatus.Status == v1alpha1.AnalysisPhaseInconclusive
 }
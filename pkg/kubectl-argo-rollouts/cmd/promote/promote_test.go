@@ -624,3 +624,119 @@ func TestPromoteInconclusiveStep(t *testing.T) {
 	assert.Equal(t, stdout, "rollout 'guestbook' promoted\n")
 	assert.Empty(t, stderr)
 }
+
+func newToStepRollout() *v1alpha1.Rollout {
+	return &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "guestbook",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: v1alpha1.RolloutSpec{
+			Paused: true,
+			Strategy: v1alpha1.RolloutStrategy{
+				Canary: &v1alpha1.CanaryStrategy{
+					Steps: []v1alpha1.CanaryStep{
+						{SetWeight: ptr.To[int32](10)},
+						{Pause: &v1alpha1.RolloutPause{}},
+						{SetWeight: ptr.To[int32](50)},
+						{Pause: &v1alpha1.RolloutPause{}},
+						{SetWeight: ptr.To[int32](80)},
+					},
+				},
+			},
+		},
+		Status: v1alpha1.RolloutStatus{
+			Abort:            true,
+			ControllerPause:  true,
+			CurrentStepIndex: ptr.To[int32](1),
+			PauseConditions: []v1alpha1.PauseCondition{{
+				Reason: v1alpha1.PauseReasonCanaryPauseStep,
+			}},
+		},
+	}
+}
+
+func TestPromoteCmdToStep(t *testing.T) {
+	ro := newToStepRollout()
+	tf, o := options.NewFakeArgoRolloutsOptions(ro)
+	defer tf.Cleanup()
+	fakeClient := o.RolloutsClient.(*fakeroclient.Clientset)
+	var patches []string
+	fakeClient.PrependReactor("patch", "*", func(action kubetesting.Action) (handled bool, ret runtime.Object, err error) {
+		if patchAction, ok := action.(kubetesting.PatchAction); ok {
+			patches = append(patches, string(patchAction.GetPatch()))
+			if patchAction.GetSubresource() == "status" {
+				ro.Status.CurrentStepIndex = ptr.To[int32](4)
+				ro.Status.PauseConditions = nil
+			} else {
+				ro.Spec.Paused = false
+			}
+		}
+		return true, ro, nil
+	})
+
+	cmd := NewCmdPromote(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{"guestbook", "--to-step", "4", "--yes"})
+
+	err := cmd.Execute()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		`{"status":{"abort":false,"pauseConditions":null,"controllerPause":false,"currentStepIndex":4}}`,
+		unpausePatch,
+	}, patches)
+	stdout := o.Out.(*bytes.Buffer).String()
+	stderr := o.ErrOut.(*bytes.Buffer).String()
+	assert.Equal(t, "rollout 'guestbook' promoted to step 4\n", stdout)
+	assert.Empty(t, stderr)
+}
+
+func TestPromoteCmdToStepNotConfirmed(t *testing.T) {
+	tests := []struct {
+		step     string
+		expected string
+	}{
+		{"4", "Promoting rollout 'guestbook' to step 4 skips steps 1 to 3. Rerun with --yes to confirm"},
+		{"2", "Promoting rollout 'guestbook' to step 2 skips step 1. Rerun with --yes to confirm"},
+		{"1", "Promoting rollout 'guestbook' to step 1 runs step 1 again. Rerun with --yes to confirm"},
+		{"0", "Promoting rollout 'guestbook' to step 0 goes back from step 1 and runs the steps from there again. Rerun with --yes to confirm"},
+	}
+	for _, test := range tests {
+		tf, o := options.NewFakeArgoRolloutsOptions(newToStepRollout())
+		cmd := NewCmdPromote(o)
+		cmd.PersistentPreRunE = o.PersistentPreRunE
+		cmd.SetArgs([]string{"guestbook", "--to-step", test.step})
+		err := cmd.Execute()
+		assert.EqualError(t, err, test.expected)
+		assert.Empty(t, o.RolloutsClient.(*fakeroclient.Clientset).Actions()[1:])
+		tf.Cleanup()
+	}
+}
+
+func TestPromoteCmdToStepErrors(t *testing.T) {
+	blueGreen := newToStepRollout()
+	blueGreen.Spec.Strategy = v1alpha1.RolloutStrategy{BlueGreen: &v1alpha1.BlueGreenStrategy{}}
+	noSteps := newToStepRollout()
+	noSteps.Spec.Strategy.Canary.Steps = nil
+
+	tests := []struct {
+		ro       *v1alpha1.Rollout
+		args     []string
+		expected string
+	}{
+		{newToStepRollout(), []string{"--to-step", "6", "--yes"}, "Step 6 is out of range, the rollout has 5 steps"},
+		{newToStepRollout(), []string{"--to-step", "-1", "--yes"}, "Step -1 is out of range, the rollout has 5 steps"},
+		{newToStepRollout(), []string{"--to-step", "2", "--full"}, toStepWithOtherFlagsError},
+		{blueGreen, []string{"--to-step", "2", "--yes"}, skipFlagsWithBlueGreenError},
+		{noSteps, []string{"--to-step", "0", "--yes"}, skipFlagWithNoStepCanaryError},
+	}
+	for _, test := range tests {
+		tf, o := options.NewFakeArgoRolloutsOptions(test.ro)
+		cmd := NewCmdPromote(o)
+		cmd.PersistentPreRunE = o.PersistentPreRunE
+		cmd.SetArgs(append([]string{"guestbook"}, test.args...))
+		err := cmd.Execute()
+		assert.EqualError(t, err, test.expected)
+		tf.Cleanup()
+	}
+}
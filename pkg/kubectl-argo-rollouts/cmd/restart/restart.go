@@ -2,12 +2,16 @@ package restart
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	clientset "github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned/typed/rollouts/v1alpha1"
@@ -22,7 +26,10 @@ const (
 	%[1]s restart ROLLOUT_NAME
 
 	# Restart the pods of a rollout in ten seconds
-	%[1]s restart ROLLOUT_NAME --in 10s`
+	%[1]s restart ROLLOUT_NAME --in 10s
+
+	# Restart the pods of a rollout two at a time, waiting a minute between batches, until all pods are restarted
+	%[1]s restart ROLLOUT_NAME --batch-size 2 --pause-between-batches 1m --wait`
 
 	restartPatch = `{
 	"spec": {
@@ -31,9 +38,23 @@ const (
 }`
 )
 
+// waitPollInterval is the interval at which the progress of a restart is checked when waiting for it
+var waitPollInterval = 2 * time.Second
+
+// RestartOptions are the options of a restart other than its time
+type RestartOptions struct {
+	// BatchSize is the number or percentage of pods restarted at a time
+	BatchSize string
+	// PauseBetweenBatches is the duration to wait between batches
+	PauseBetweenBatches string
+}
+
 func NewCmdRestart(o *options.ArgoRolloutsOptions) *cobra.Command {
 	var (
-		in string
+		in             string
+		restartOptions RestartOptions
+		wait           bool
+		timeout        time.Duration
 	)
 	var cmd = &cobra.Command{
 		Use:          "restart ROLLOUT",
@@ -54,28 +75,155 @@ func NewCmdRestart(o *options.ArgoRolloutsOptions) *cobra.Command {
 			} else {
 				in = "0s"
 			}
+			if err := restartOptions.validate(); err != nil {
+				return err
+			}
 			name := args[0]
 			rolloutIf := o.RolloutsClientset().ArgoprojV1alpha1().Rollouts(o.Namespace())
-			ro, err := RestartRollout(rolloutIf, name, &restartAt)
+			ro, err := RestartRolloutWithOptions(rolloutIf, name, &restartAt, restartOptions)
 			if err != nil {
 				return err
 			}
 			fmt.Fprintf(o.Out, "rollout '%s' restarts in %s\n", ro.Name, in)
-			return nil
+			if !wait {
+				return nil
+			}
+			ctx := context.Background()
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+			if ro.Spec.RestartAt != nil {
+				restartAt = ro.Spec.RestartAt.Time
+			}
+			return WaitForRestart(ctx, o.Out, rolloutIf, o.KubeClientset(), name, restartAt)
 		},
 		ValidArgsFunction: completionutil.RolloutNameCompletionFunc(o),
 	}
 	cmd.Flags().StringVarP(&in, "in", "i", "", "Amount of time before a restart. (e.g. 30s, 5m, 1h)")
+	cmd.Flags().StringVar(&restartOptions.BatchSize, "batch-size", "", "Number or percentage of pods restarted at a time (e.g. 2, 25%). Defaults to maxUnavailable")
+	cmd.Flags().StringVar(&restartOptions.PauseBetweenBatches, "pause-between-batches", "", "Amount of time to wait after a batch became available before restarting the next one (e.g. 30s, 5m)")
+	cmd.Flags().BoolVarP(&wait, "wait", "w", false, "Wait until all the pods of the rollout are restarted")
+	cmd.Flags().DurationVarP(&timeout, "timeout", "t", time.Duration(0), "The length of time to wait for the restart before giving up. Zero means wait forever")
 	return cmd
 }
 
+func (r RestartOptions) validate() error {
+	if r.BatchSize != "" {
+		batchSize := intstr.Parse(r.BatchSize)
+		value, err := intstr.GetScaledValueFromIntOrPercent(&batchSize, 100, true)
+		if err != nil {
+			return fmt.Errorf("invalid batch size '%s': %v", r.BatchSize, err)
+		}
+		if value < 1 {
+			return fmt.Errorf("invalid batch size '%s': must be greater than 0", r.BatchSize)
+		}
+	}
+	if r.PauseBetweenBatches != "" {
+		if _, err := v1alpha1.DurationString(r.PauseBetweenBatches).Duration(); err != nil {
+			return fmt.Errorf("invalid pause between batches '%s': %v", r.PauseBetweenBatches, err)
+		}
+	}
+	return nil
+}
+
 // RestartRollout restarts a rollout
 func RestartRollout(rolloutIf clientset.RolloutInterface, name string, restartAt *time.Time) (*v1alpha1.Rollout, error) {
+	return RestartRolloutWithOptions(rolloutIf, name, restartAt, RestartOptions{})
+}
+
+// RestartRolloutWithOptions restarts a rollout and sets its restart strategy when a batch size or a pause between
+// batches is given
+func RestartRolloutWithOptions(rolloutIf clientset.RolloutInterface, name string, restartAt *time.Time, restartOptions RestartOptions) (*v1alpha1.Rollout, error) {
 	ctx := context.TODO()
 	if restartAt == nil {
 		t := timeutil.Now().UTC()
 		restartAt = &t
 	}
-	patch := fmt.Sprintf(restartPatch, restartAt.Format(time.RFC3339))
-	return rolloutIf.Patch(ctx, name, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	patch := []byte(fmt.Sprintf(restartPatch, restartAt.Format(time.RFC3339)))
+	if restartOptions.BatchSize != "" || restartOptions.PauseBetweenBatches != "" {
+		strategy := map[string]any{}
+		if restartOptions.BatchSize != "" {
+			strategy["batchSize"] = intstr.Parse(restartOptions.BatchSize)
+		}
+		if restartOptions.PauseBetweenBatches != "" {
+			strategy["pauseBetweenBatches"] = restartOptions.PauseBetweenBatches
+		}
+		var err error
+		patch, err = json.Marshal(map[string]any{
+			"spec": map[string]any{
+				"restartAt":       restartAt.Format(time.RFC3339),
+				"restartStrategy": strategy,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rolloutIf.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+}
+
+// WaitForRestart blocks until all the pods of a rollout were restarted after restartAt, reporting the number of
+// restarted pods whenever it changes. Returns an error if the restart was halted or the context is done.
+func WaitForRestart(ctx context.Context, out io.Writer, rolloutIf clientset.RolloutInterface, kubeClient kubernetes.Interface, name string, restartAt time.Time) error {
+	ticker := time.NewTicker(waitPollInterval)
+	defer ticker.Stop()
+	prevProgress := ""
+	for {
+		ro, err := rolloutIf.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if ro.Status.RestartedAt != nil && !ro.Status.RestartedAt.Time.Before(restartAt) {
+			fmt.Fprintf(out, "rollout '%s' restarted\n", name)
+			return nil
+		}
+		if ro.Status.RestartStatus != nil && ro.Status.RestartStatus.Halted {
+			return fmt.Errorf("restart of rollout '%s' halted: %s", name, ro.Status.RestartStatus.Message)
+		}
+		progress, err := restartProgress(ctx, kubeClient, ro, restartAt)
+		if err != nil {
+			return err
+		}
+		if progress != prevProgress {
+			fmt.Fprintln(out, progress)
+			prevProgress = progress
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for rollout '%s' to restart", name)
+		case <-ticker.C:
+		}
+	}
+}
+
+// restartProgress returns the number of running pods of the rollout which were created after restartAt
+func restartProgress(ctx context.Context, kubeClient kubernetes.Interface, ro *v1alpha1.Rollout, restartAt time.Time) (string, error) {
+	if timeutil.Now().Before(restartAt) {
+		return fmt.Sprintf("waiting for the restart at %s", restartAt.Format(time.RFC3339)), nil
+	}
+	selector := ro.Status.Selector
+	if selector == "" && ro.Spec.Selector != nil {
+		selector = metav1.FormatLabelSelector(ro.Spec.Selector)
+	}
+	pods, err := kubeClient.CoreV1().Pods(ro.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return "", err
+	}
+	total, restarted := 0, 0
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		total++
+		if !pod.CreationTimestamp.Time.Before(restartAt) {
+			restarted++
+		}
+	}
+	progress := fmt.Sprintf("%d/%d pods restarted", restarted, total)
+	if status := ro.Status.RestartStatus; status != nil && status.CompletedBatches > 0 {
+		progress += fmt.Sprintf(" (%d batches completed)", status.CompletedBatches)
+	}
+	return progress, nil
 }
@@ -2,13 +2,16 @@ package restart
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	kubetesting "k8s.io/client-go/testing"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
@@ -162,3 +165,160 @@ func TestRestartCmdNotFoundError(t *testing.T) {
 	assert.Empty(t, stdout)
 	assert.Equal(t, "Error: rollouts.argoproj.io \"doesnotexist\" not found\n", stderr)
 }
+
+func TestRestartCmdBatchSize(t *testing.T) {
+	ro := v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "guestbook",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+
+	tf, o := options.NewFakeArgoRolloutsOptions(&ro)
+	defer tf.Cleanup()
+	cmd := NewCmdRestart(o)
+	o.AddKubectlFlags(cmd)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{"guestbook", "--batch-size", "25%", "--pause-between-batches", "1m"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+
+	patched, err := o.RolloutsClient.ArgoprojV1alpha1().Rollouts(metav1.NamespaceDefault).Get(context.TODO(), "guestbook", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.NotNil(t, patched.Spec.RestartAt)
+	assert.Equal(t, intstr.FromString("25%"), *patched.Spec.RestartStrategy.BatchSize)
+	assert.Equal(t, v1alpha1.DurationString("1m"), patched.Spec.RestartStrategy.PauseBetweenBatches)
+	stdout := o.Out.(*bytes.Buffer).String()
+	assert.Equal(t, "rollout 'guestbook' restarts in 0s\n", stdout)
+}
+
+func TestRestartCmdInvalidBatchSize(t *testing.T) {
+	tests := []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"--batch-size", "0"}, "invalid batch size '0': must be greater than 0"},
+		{[]string{"--batch-size", "abc"}, "invalid batch size 'abc'"},
+		{[]string{"--pause-between-batches", "abc"}, "invalid pause between batches 'abc'"},
+	}
+	for _, test := range tests {
+		ro := v1alpha1.Rollout{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "guestbook",
+				Namespace: metav1.NamespaceDefault,
+			},
+		}
+		tf, o := options.NewFakeArgoRolloutsOptions(&ro)
+		cmd := NewCmdRestart(o)
+		o.AddKubectlFlags(cmd)
+		cmd.PersistentPreRunE = o.PersistentPreRunE
+		cmd.SetArgs(append([]string{"guestbook"}, test.args...))
+		err := cmd.Execute()
+		assert.ErrorContains(t, err, test.err)
+		tf.Cleanup()
+	}
+}
+
+func newRestartPod(name string, created time.Time) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         metav1.NamespaceDefault,
+			Labels:            map[string]string{"app": "guestbook"},
+			CreationTimestamp: metav1.NewTime(created),
+		},
+	}
+}
+
+func TestRestartCmdWait(t *testing.T) {
+	now := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	ro := v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "guestbook",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: v1alpha1.RolloutSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "guestbook"}},
+		},
+	}
+	oldPod := newRestartPod("guestbook-old", now.Add(-time.Hour))
+	newPod := newRestartPod("guestbook-new", now.Add(time.Minute))
+
+	tf, o := options.NewFakeArgoRolloutsOptions(&ro, oldPod, newPod)
+	o.Now = func() metav1.Time {
+		return now
+	}
+	defer tf.Cleanup()
+	defer func(interval time.Duration) { waitPollInterval = interval }(waitPollInterval)
+	waitPollInterval = time.Millisecond
+
+	gets := 0
+	fakeClient := o.RolloutsClient.(*fakeroclient.Clientset)
+	fakeClient.PrependReactor("get", "rollouts", func(action kubetesting.Action) (handled bool, ret runtime.Object, err error) {
+		gets++
+		restarted := ro.DeepCopy()
+		restarted.Spec.RestartAt = &now
+		if gets > 1 {
+			restarted.Status.RestartedAt = &now
+		}
+		return true, restarted, nil
+	})
+
+	cmd := NewCmdRestart(o)
+	o.AddKubectlFlags(cmd)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{"guestbook", "--wait"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+	stdout := o.Out.(*bytes.Buffer).String()
+	assert.Equal(t, "rollout 'guestbook' restarts in 0s\n1/2 pods restarted\nrollout 'guestbook' restarted\n", stdout)
+}
+
+func TestRestartCmdWaitHalted(t *testing.T) {
+	now := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	ro := v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "guestbook",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Status: v1alpha1.RolloutStatus{
+			RestartStatus: &v1alpha1.RolloutRestartStatus{
+				RestartAt: now,
+				Halted:    true,
+				Message:   "AnalysisRun 'guestbook-restart-1' of restart batch 1 is Failed",
+			},
+		},
+	}
+
+	tf, o := options.NewFakeArgoRolloutsOptions(&ro)
+	o.Now = func() metav1.Time {
+		return now
+	}
+	defer tf.Cleanup()
+	cmd := NewCmdRestart(o)
+	o.AddKubectlFlags(cmd)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{"guestbook", "--wait"})
+	err := cmd.Execute()
+	assert.EqualError(t, err, "restart of rollout 'guestbook' halted: AnalysisRun 'guestbook-restart-1' of restart batch 1 is Failed")
+}
+
+func TestRestartCmdWaitTimeout(t *testing.T) {
+	ro := v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "guestbook",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+
+	tf, o := options.NewFakeArgoRolloutsOptions(&ro)
+	defer tf.Cleanup()
+	defer func(interval time.Duration) { waitPollInterval = interval }(waitPollInterval)
+	waitPollInterval = time.Millisecond
+	cmd := NewCmdRestart(o)
+	o.AddKubectlFlags(cmd)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{"guestbook", "--wait", "--timeout", "10ms"})
+	err := cmd.Execute()
+	assert.EqualError(t, err, "timed out waiting for rollout 'guestbook' to restart")
+}
@@ -0,0 +1,72 @@
+package resume
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
+	completionutil "github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/util/completion"
+)
+
+const (
+	resumePatch = `{"spec":{"paused":false}}`
+)
+
+const (
+	resumeExample = `
+	# Resume a paused experiment
+	%[1]s resume experiment my-experiment`
+
+	resumeExperimentExample = `
+	# Resume a paused experiment
+	%[1]s resume experiment my-experiment`
+)
+
+// NewCmdResume returns a new instance of an `argo rollouts resume` command
+func NewCmdResume(o *options.ArgoRolloutsOptions) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:          "resume <experiment> RESOURCE_NAME",
+		Short:        "Resume a paused experiment",
+		Long:         "This command consists of multiple subcommands which can be used to resume a paused Experiment. Use the promote command to resume a paused rollout.",
+		Example:      o.Example(resumeExample),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			return o.UsageErr(c)
+		},
+	}
+	cmd.AddCommand(NewCmdResumeExperiment(o))
+	return cmd
+}
+
+// NewCmdResumeExperiment returns a new instance of an `argo rollouts resume experiment` command
+func NewCmdResumeExperiment(o *options.ArgoRolloutsOptions) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:          "experiment EXPERIMENT_NAME",
+		Aliases:      []string{"exp", "experiments"},
+		Short:        "Resume a paused experiment",
+		Long:         "Set the experiment paused state to 'false'. The experiment runs for the rest of its duration, which is extended by the time it was paused.",
+		Example:      o.Example(resumeExperimentExample),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return o.UsageErr(c)
+			}
+			ns := o.Namespace()
+			experimentIf := o.RolloutsClientset().ArgoprojV1alpha1().Experiments(ns)
+			for _, name := range args {
+				ex, err := experimentIf.Patch(context.TODO(), name, types.MergePatchType, []byte(resumePatch), metav1.PatchOptions{})
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(o.Out, "experiment '%s' resumed\n", ex.Name)
+			}
+			return nil
+		},
+		ValidArgsFunction: completionutil.ExperimentNameCompletionFunc(o),
+	}
+	return cmd
+}
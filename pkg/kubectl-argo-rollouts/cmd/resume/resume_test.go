@@ -0,0 +1,96 @@
+package resume
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubetesting "k8s.io/client-go/testing"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	fakeroclient "github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned/fake"
+	options "github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options/fake"
+)
+
+func TestResumeCmdUsage(t *testing.T) {
+	tf, o := options.NewFakeArgoRolloutsOptions()
+	defer tf.Cleanup()
+	cmd := NewCmdResume(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{})
+	err := cmd.Execute()
+	assert.Error(t, err)
+	stdout := o.Out.(*bytes.Buffer).String()
+	stderr := o.ErrOut.(*bytes.Buffer).String()
+	assert.Empty(t, stdout)
+	assert.Contains(t, stderr, "Usage:\n  resume <experiment> RESOURCE")
+}
+
+func TestResumeExperimentCmdUsage(t *testing.T) {
+	tf, o := options.NewFakeArgoRolloutsOptions()
+	defer tf.Cleanup()
+	cmd := NewCmdResumeExperiment(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{})
+	err := cmd.Execute()
+	assert.Error(t, err)
+	stdout := o.Out.(*bytes.Buffer).String()
+	stderr := o.ErrOut.(*bytes.Buffer).String()
+	assert.Empty(t, stdout)
+	assert.Contains(t, stderr, "Usage:\n  experiment EXPERIMENT")
+	assert.Contains(t, stderr, "Aliases:\n  experiment, exp, experiments")
+}
+
+func TestResumeExperimentCmd(t *testing.T) {
+	ex := v1alpha1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-experiment",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.ExperimentSpec{
+			Paused: true,
+		},
+	}
+
+	tf, o := options.NewFakeArgoRolloutsOptions(&ex)
+	defer tf.Cleanup()
+	fakeClient := o.RolloutsClient.(*fakeroclient.Clientset)
+	fakeClient.PrependReactor("patch", "*", func(action kubetesting.Action) (handled bool, ret runtime.Object, err error) {
+		if patchAction, ok := action.(kubetesting.PatchAction); ok {
+			if string(patchAction.GetPatch()) == resumePatch {
+				ex.Spec.Paused = false
+			}
+		}
+		return true, &ex, nil
+	})
+
+	cmd := NewCmdResumeExperiment(o)
+	o.AddKubectlFlags(cmd)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{"my-experiment", "-n", "test"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+
+	assert.False(t, ex.Spec.Paused)
+	stdout := o.Out.(*bytes.Buffer).String()
+	stderr := o.ErrOut.(*bytes.Buffer).String()
+	assert.Equal(t, "experiment 'my-experiment' resumed\n", stdout)
+	assert.Empty(t, stderr)
+}
+
+func TestResumeExperimentCmdError(t *testing.T) {
+	tf, o := options.NewFakeArgoRolloutsOptions(&v1alpha1.Experiment{})
+	defer tf.Cleanup()
+	cmd := NewCmdResumeExperiment(o)
+	o.AddKubectlFlags(cmd)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{"doesnotexist", "-n", "test"})
+	err := cmd.Execute()
+	assert.Error(t, err)
+	stdout := o.Out.(*bytes.Buffer).String()
+	stderr := o.ErrOut.(*bytes.Buffer).String()
+	assert.Empty(t, stdout)
+	assert.Equal(t, "Error: experiments.argoproj.io \"doesnotexist\" not found\n", stderr)
+}
@@ -25,5 +25,6 @@ func NewCmdSet(o *options.ArgoRolloutsOptions) *cobra.Command {
 		},
 	}
 	cmd.AddCommand(NewCmdSetImage(o))
+	cmd.AddCommand(NewCmdSetWeight(o))
 	return cmd
 }
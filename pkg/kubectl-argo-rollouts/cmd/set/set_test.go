@@ -15,6 +15,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	kubetesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	cliopts "github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
@@ -381,3 +382,99 @@ func TestSetImageWorkloadRef(t *testing.T) {
 	assert.Equal(t, stdout, "deployment \"guestbook\" image updated\n")
 	assert.Empty(t, stderr)
 }
+
+func newWeightRollout() *v1alpha1.Rollout {
+	return &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: "test"},
+		Spec: v1alpha1.RolloutSpec{
+			Strategy: v1alpha1.RolloutStrategy{
+				Canary: &v1alpha1.CanaryStrategy{
+					TrafficRouting: &v1alpha1.RolloutTrafficRouting{},
+					Steps: []v1alpha1.CanaryStep{
+						{SetWeight: ptr.To[int32](20)},
+						{Pause: &v1alpha1.RolloutPause{}},
+					},
+				},
+			},
+		},
+		Status: v1alpha1.RolloutStatus{CurrentStepIndex: ptr.To[int32](1)},
+	}
+}
+
+func runSetWeight(t *testing.T, ro *v1alpha1.Rollout, args ...string) (*v1alpha1.Rollout, string, error) {
+	tf, o := options.NewFakeArgoRolloutsOptions(ro)
+	defer tf.Cleanup()
+	o.RESTClientGetter = tf.WithNamespace("test")
+	cmd := NewCmdSetWeight(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	updated, getErr := o.RolloutsClient.ArgoprojV1alpha1().Rollouts("test").Get(context.TODO(), ro.Name, metav1.GetOptions{})
+	assert.NoError(t, getErr)
+	return updated, o.Out.(*bytes.Buffer).String(), err
+}
+
+func TestSetWeightCmdUsage(t *testing.T) {
+	tf, o := options.NewFakeArgoRolloutsOptions()
+	defer tf.Cleanup()
+	for _, args := range [][]string{
+		{},
+		{"guestbook"},
+		{"guestbook", "10", "--clear"},
+	} {
+		cmd := NewCmdSetWeight(o)
+		cmd.PersistentPreRunE = o.PersistentPreRunE
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		assert.Error(t, err)
+		assert.Contains(t, o.ErrOut.(*bytes.Buffer).String(), "weight ROLLOUT_NAME WEIGHT")
+	}
+}
+
+func TestSetWeight(t *testing.T) {
+	ro, stdout, err := runSetWeight(t, newWeightRollout(), "guestbook", "0")
+	assert.NoError(t, err)
+	assert.Equal(t, &v1alpha1.WeightOverride{Weight: 0, StepIndex: 1}, ro.Status.Canary.WeightOverride)
+	assert.Equal(t, "rollout 'guestbook' canary weight overridden to 0 until step 1 completes\n", stdout)
+}
+
+func TestSetWeightClear(t *testing.T) {
+	ro := newWeightRollout()
+	ro.Status.Canary.WeightOverride = &v1alpha1.WeightOverride{Weight: 0, StepIndex: 1}
+	ro, stdout, err := runSetWeight(t, ro, "guestbook", "--clear")
+	assert.NoError(t, err)
+	assert.Nil(t, ro.Status.Canary.WeightOverride)
+	assert.Equal(t, "rollout 'guestbook' canary weight override removed\n", stdout)
+}
+
+func TestSetWeightErrors(t *testing.T) {
+	withoutTrafficRouting := newWeightRollout()
+	withoutTrafficRouting.Spec.Strategy.Canary.TrafficRouting = nil
+	maxTrafficWeight := newWeightRollout()
+	maxTrafficWeight.Spec.Strategy.Canary.TrafficRouting.MaxTrafficWeight = ptr.To[int32](1000)
+	completed := newWeightRollout()
+	completed.Status.CurrentStepIndex = ptr.To[int32](2)
+	aborted := newWeightRollout()
+	aborted.Status.Abort = true
+
+	tests := []struct {
+		ro     *v1alpha1.Rollout
+		weight string
+		errmsg string
+	}{
+		{newWeightRollout(), "ten", "invalid weight 'ten': strconv.ParseInt: parsing \"ten\": invalid syntax"},
+		{newWeightRollout(), "101", "weight 101 must be between 0 and 100"},
+		{newWeightRollout(), "-1", "weight -1 must be between 0 and 100"},
+		{maxTrafficWeight, "1001", "weight 1001 must be between 0 and 1000"},
+		{withoutTrafficRouting, "0", "the weight can only be overridden for canary rollouts with traffic routing"},
+		{completed, "0", "rollout 'guestbook' is not progressing through its steps"},
+		{aborted, "0", "rollout 'guestbook' is not progressing through its steps"},
+	}
+	for _, test := range tests {
+		t.Run(test.errmsg, func(t *testing.T) {
+			ro, _, err := runSetWeight(t, test.ro, "guestbook", "--", test.weight)
+			assert.EqualError(t, err, test.errmsg)
+			assert.Nil(t, ro.Status.Canary.WeightOverride)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package set
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	clientset "github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned/typed/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
+	completionutil "github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/util/completion"
+	"github.com/argoproj/argo-rollouts/utils/weightutil"
+)
+
+const (
+	setWeightExample = `
+  # Drain the canary of a rollout until its current step completes
+  %[1]s set weight my-rollout 0
+
+  # Remove the override and restore the weight of the current step
+  %[1]s set weight my-rollout --clear`
+
+	setWeightPatch   = `{"status":{"canary":{"weightOverride":{"weight":%d,"stepIndex":%d}}}}`
+	clearWeightPatch = `{"status":{"canary":{"weightOverride":null}}}`
+
+	weightWithoutTrafficRoutingError = "the weight can only be overridden for canary rollouts with traffic routing"
+	weightOutOfRangeError            = "weight %d must be between 0 and %d"
+	weightNotProgressingError        = "rollout '%s' is not progressing through its steps"
+)
+
+// NewCmdSetWeight returns a new instance of an `rollouts set weight` command
+func NewCmdSetWeight(o *options.ArgoRolloutsOptions) *cobra.Command {
+	clearOverride := false
+	var cmd = &cobra.Command{
+		Use:   "weight ROLLOUT_NAME WEIGHT",
+		Short: "Override the canary traffic weight of a rollout",
+		Long: "This command overrides the canary traffic weight of a rollout until it moves to another step, e.g. to " +
+			"drain the canary in an emergency. The weight of the steps is restored once the current step completes, " +
+			"the rollout is aborted or promoted, or the override is cleared. The number of canary pods is not changed.",
+		Example:      o.Example(setWeightExample),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if (clearOverride && len(args) != 1) || (!clearOverride && len(args) != 2) {
+				return o.UsageErr(c)
+			}
+			name := args[0]
+			rolloutIf := o.RolloutsClientset().ArgoprojV1alpha1().Rollouts(o.Namespace())
+			if clearOverride {
+				if _, err := ClearWeightOverride(rolloutIf, name); err != nil {
+					return err
+				}
+				fmt.Fprintf(o.Out, "rollout '%s' canary weight override removed\n", name)
+				return nil
+			}
+			weight, err := strconv.ParseInt(args[1], 10, 32)
+			if err != nil {
+				return fmt.Errorf("invalid weight '%s': %v", args[1], err)
+			}
+			ro, err := SetWeightOverride(rolloutIf, name, int32(weight))
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(o.Out, "rollout '%s' canary weight overridden to %d until step %d completes\n", name, weight, ro.Status.Canary.WeightOverride.StepIndex)
+			return nil
+		},
+		ValidArgsFunction: completionutil.RolloutNameCompletionFunc(o),
+	}
+	cmd.Flags().BoolVar(&clearOverride, "clear", false, "Remove the override and restore the weight of the current step")
+	return cmd
+}
+
+// SetWeightOverride overrides the canary weight of the rollout until it moves to another step
+func SetWeightOverride(rolloutIf clientset.RolloutInterface, name string, weight int32) (*v1alpha1.Rollout, error) {
+	ctx := context.TODO()
+	ro, err := rolloutIf.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if ro.Spec.Strategy.Canary == nil || ro.Spec.Strategy.Canary.TrafficRouting == nil {
+		return nil, errors.New(weightWithoutTrafficRoutingError)
+	}
+	if maxWeight := weightutil.MaxTrafficWeight(ro); weight < 0 || weight > maxWeight {
+		return nil, fmt.Errorf(weightOutOfRangeError, weight, maxWeight)
+	}
+	stepIndex := ro.Status.CurrentStepIndex
+	if stepIndex == nil || *stepIndex >= int32(len(ro.Spec.Strategy.Canary.Steps)) || ro.Status.Abort {
+		return nil, fmt.Errorf(weightNotProgressingError, name)
+	}
+	return patchWeightOverride(ctx, rolloutIf, name, []byte(fmt.Sprintf(setWeightPatch, weight, *stepIndex)))
+}
+
+// ClearWeightOverride removes the override of the canary weight of the rollout
+func ClearWeightOverride(rolloutIf clientset.RolloutInterface, name string) (*v1alpha1.Rollout, error) {
+	return patchWeightOverride(context.TODO(), rolloutIf, name, []byte(clearWeightPatch))
+}
+
+func patchWeightOverride(ctx context.Context, rolloutIf clientset.RolloutInterface, name string, patch []byte) (*v1alpha1.Rollout, error) {
+	ro, err := rolloutIf.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}, "status")
+	if err != nil && k8serrors.IsNotFound(err) {
+		// status subresource is not being used (v0.9), so perform a unified patch
+		return rolloutIf.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	}
+	return ro, err
+}
@@ -0,0 +1,284 @@
+package simulate
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	goyaml "go.yaml.in/yaml/v2"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
+	rolloututil "github.com/argoproj/argo-rollouts/utils/rollout"
+	"github.com/argoproj/argo-rollouts/utils/weightutil"
+)
+
+const (
+	simulateExample = `
+	# Show the expected replica counts and traffic weights of each step of a rollout
+	%[1]s simulate -f my-rollout.yaml`
+
+	simulateHeaderFmtString = "STEP\tACTION\tCANARY\tSTABLE\tTOTAL\tWEIGHT\n"
+	simulateColumnFmtString = "%s\t%s\t%d\t%d\t%d\t%d\n"
+
+	// maxReconciliations bounds the reconciliations simulated for a single step, in case the replica counts never
+	// settle
+	maxReconciliations = 100
+)
+
+// SimulationRow is the state of the canary and stable ReplicaSets after a reconciliation of the rollout
+type SimulationRow struct {
+	// Step is the index of the step, or -1 before the first and after the last step
+	Step int32
+	// Action is the step or the transition of the rollout which caused the reconciliation. It is empty for the
+	// following reconciliations of the same action.
+	Action string
+	Canary int32
+	Stable int32
+	// Weight is the percentage of the traffic sent to the canary. Without traffic routing, it is the share of the
+	// canary pods.
+	Weight int32
+}
+
+// NewCmdSimulate returns a new instance of a `rollouts simulate` command
+func NewCmdSimulate(o *options.ArgoRolloutsOptions) *cobra.Command {
+	var file string
+	var cmd = &cobra.Command{
+		Use:   "simulate",
+		Short: "Simulate the steps of a Rollout",
+		Long: "Simulate the update of a Rollout from a file, without a cluster. For each step, the replica counts of the " +
+			"canary and stable ReplicaSets and the canary traffic weight are computed the way the controller does, " +
+			"including the scaling limited by maxSurge and maxUnavailable. The simulation assumes that the pods become " +
+			"available immediately and that analyses and experiments succeed.",
+		Example:      o.Example(simulateExample),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if file == "" {
+				return o.UsageErr(c)
+			}
+			ros, err := readRollouts(file)
+			if err != nil {
+				return err
+			}
+			if len(ros) == 0 {
+				return fmt.Errorf("no rollout found in %s", file)
+			}
+			for i := range ros {
+				if i > 0 {
+					fmt.Fprintln(o.Out)
+				}
+				printSimulation(o.Out, &ros[i], SimulateRollout(&ros[i]))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&file, "filename", "f", "", "File containing the rollout to simulate")
+	return cmd
+}
+
+// readRollouts returns the rollouts of the documents of a file
+func readRollouts(path string) ([]v1alpha1.Rollout, error) {
+	fileBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var ros []v1alpha1.Rollout
+	decoder := goyaml.NewDecoder(bytes.NewReader(fileBytes))
+	for {
+		var value any
+		if err := decoder.Decode(&value); err != nil {
+			if err != io.EOF {
+				return nil, err
+			}
+			break
+		}
+		if value == nil {
+			continue
+		}
+		valueBytes, err := goyaml.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		var un unstructured.Unstructured
+		if err := yaml.Unmarshal(valueBytes, &un); err != nil {
+			return nil, err
+		}
+		gvk := un.GroupVersionKind()
+		if gvk.Group != rollouts.Group || gvk.Kind != rollouts.RolloutKind {
+			continue
+		}
+		var ro v1alpha1.Rollout
+		if err := yaml.UnmarshalStrict(valueBytes, &ro); err != nil {
+			return nil, err
+		}
+		ros = append(ros, ro)
+	}
+	return ros, nil
+}
+
+// SimulateRollout returns the replica counts and the traffic weights of an update of a rollout, from a fully
+// available stable ReplicaSet to the promotion of the canary
+func SimulateRollout(ro *v1alpha1.Rollout) []SimulationRow {
+	replicas := defaults.GetReplicasOrDefault(ro.Spec.Replicas)
+	if ro.Spec.Strategy.BlueGreen != nil {
+		return simulateBlueGreen(ro, replicas)
+	}
+
+	s := newCanarySimulation(ro, replicas)
+	rows := []SimulationRow{s.row(-1, "initial")}
+	for i, step := range s.rollout.Spec.Strategy.Canary.Steps {
+		s.rollout.Status.CurrentStepIndex = ptr.To(int32(i))
+		rows = append(rows, s.reconcile(int32(i), rolloututil.CanaryStepString(step))...)
+	}
+	s.rollout.Status.CurrentStepIndex = ptr.To(int32(len(s.rollout.Spec.Strategy.Canary.Steps)))
+	rows = append(rows, s.reconcile(-1, "promote")...)
+	if s.trafficRouted() && s.stableRS.Spec.Replicas != nil && *s.stableRS.Spec.Replicas > 0 {
+		// once the canary is promoted, the previous stable ReplicaSet is scaled down after scaleDownDelaySeconds
+		delay := defaults.GetScaleDownDelaySecondsOrDefault(ro)
+		s.setReplicas(s.stableRS, 0)
+		rows = append(rows, s.row(-1, fmt.Sprintf("scale down stable after %s", delay)))
+	}
+	return rows
+}
+
+type canarySimulation struct {
+	rollout   *v1alpha1.Rollout
+	newRS     *appsv1.ReplicaSet
+	stableRS  *appsv1.ReplicaSet
+	maxWeight int32
+	weights   *v1alpha1.TrafficWeights
+}
+
+func newCanarySimulation(ro *v1alpha1.Rollout, replicas int32) *canarySimulation {
+	s := &canarySimulation{
+		rollout:   ro.DeepCopy(),
+		newRS:     &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "canary"}},
+		stableRS:  &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "stable"}},
+		maxWeight: weightutil.MaxTrafficWeight(ro),
+	}
+	if s.rollout.Spec.Strategy.Canary == nil {
+		s.rollout.Spec.Strategy.Canary = &v1alpha1.CanaryStrategy{}
+	}
+	s.rollout.Status = v1alpha1.RolloutStatus{
+		StableRS:       "stable",
+		CurrentPodHash: "canary",
+	}
+	s.setReplicas(s.newRS, 0)
+	s.setReplicas(s.stableRS, replicas)
+	s.weights = &v1alpha1.TrafficWeights{Stable: v1alpha1.WeightDestination{Weight: s.maxWeight}}
+	return s
+}
+
+func (s *canarySimulation) trafficRouted() bool {
+	return s.rollout.Spec.Strategy.Canary.TrafficRouting != nil
+}
+
+// setReplicas scales a ReplicaSet, whose pods are assumed to become available immediately
+func (s *canarySimulation) setReplicas(rs *appsv1.ReplicaSet, replicas int32) {
+	rs.Spec.Replicas = ptr.To(replicas)
+	rs.Status.Replicas = replicas
+	rs.Status.AvailableReplicas = replicas
+}
+
+// reconcile simulates the reconciliations of the rollout until the replica counts settle
+func (s *canarySimulation) reconcile(step int32, action string) []SimulationRow {
+	var rows []SimulationRow
+	for i := 0; i < maxReconciliations; i++ {
+		var canary, stable int32
+		if s.trafficRouted() {
+			canary, stable = replicasetutil.CalculateReplicaCountsForTrafficRoutedCanary(s.rollout, s.newRS, s.stableRS, s.weights)
+		} else {
+			canary, stable = replicasetutil.CalculateReplicaCountsForBasicCanary(s.rollout, s.newRS, s.stableRS, nil)
+		}
+		// the traffic is only shifted once the canary pods are available, which is assumed to be the case after the
+		// first reconciliation
+		weight := replicasetutil.GetDesiredCanaryWeight(s.rollout, s.newRS, s.stableRS)
+		changed := canary != *s.newRS.Spec.Replicas || stable != *s.stableRS.Spec.Replicas ||
+			(s.trafficRouted() && weight != s.weights.Canary.Weight)
+		if !changed && i > 0 {
+			break
+		}
+		s.setReplicas(s.newRS, canary)
+		s.setReplicas(s.stableRS, stable)
+		if s.trafficRouted() {
+			s.weights.Canary.Weight = weight
+			s.weights.Stable.Weight = s.maxWeight - weight
+		}
+		rows = append(rows, s.row(step, action))
+		action = ""
+	}
+	return rows
+}
+
+func (s *canarySimulation) row(step int32, action string) SimulationRow {
+	row := SimulationRow{
+		Step:   step,
+		Action: action,
+		Canary: *s.newRS.Spec.Replicas,
+		Stable: *s.stableRS.Spec.Replicas,
+	}
+	if s.trafficRouted() {
+		row.Weight = s.weights.Canary.Weight
+	} else if total := row.Canary + row.Stable; total > 0 {
+		row.Weight = int32(math.Round(float64(row.Canary*s.maxWeight) / float64(total)))
+	}
+	return row
+}
+
+// simulateBlueGreen returns the replica counts of the preview and active ReplicaSets of a blue-green update. The
+// canary is the preview ReplicaSet, which receives all the traffic once it is promoted.
+func simulateBlueGreen(ro *v1alpha1.Rollout, replicas int32) []SimulationRow {
+	preview := replicas
+	if previewReplicaCount := replicasetutil.GetPreviewReplicaCount(ro); previewReplicaCount != nil {
+		preview = *previewReplicaCount
+	}
+	delay := defaults.GetScaleDownDelaySecondsOrDefault(ro)
+	return []SimulationRow{
+		{Step: -1, Action: "initial", Canary: 0, Stable: replicas, Weight: 0},
+		{Step: -1, Action: "scale up preview", Canary: preview, Stable: replicas, Weight: 0},
+		{Step: -1, Action: "promote", Canary: replicas, Stable: replicas, Weight: 100},
+		{Step: -1, Action: fmt.Sprintf("scale down previous active after %s", delay), Canary: replicas, Stable: 0, Weight: 100},
+	}
+}
+
+// printSimulation prints the limits of a rollout and its simulation in table format
+func printSimulation(out io.Writer, ro *v1alpha1.Rollout, rows []SimulationRow) {
+	replicas := defaults.GetReplicasOrDefault(ro.Spec.Replicas)
+	if ro.Spec.Strategy.BlueGreen != nil {
+		fmt.Fprintf(out, "Rollout %s: blueGreen with %d replicas\n", ro.Name, replicas)
+	} else {
+		maxSurge := replicasetutil.MaxSurge(ro)
+		maxUnavailable := replicasetutil.MaxUnavailable(ro)
+		fmt.Fprintf(out, "Rollout %s: canary with %d replicas, maxSurge %d, maxUnavailable %d\n", ro.Name, replicas, maxSurge, maxUnavailable)
+		if ro.Spec.Strategy.Canary == nil || ro.Spec.Strategy.Canary.TrafficRouting == nil {
+			fmt.Fprintf(out, "Pods: at most %d in total, at least %d available\n", replicas+maxSurge, replicas-maxUnavailable)
+		}
+	}
+	fmt.Fprintln(out)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprint(w, simulateHeaderFmtString)
+	for _, row := range rows {
+		step := "-"
+		if row.Step >= 0 {
+			step = strconv.Itoa(int(row.Step))
+		}
+		if row.Action == "" {
+			step = ""
+		}
+		fmt.Fprintf(w, simulateColumnFmtString, step, row.Action, row.Canary, row.Stable, row.Canary+row.Stable, row.Weight)
+	}
+	_ = w.Flush()
+}
@@ -0,0 +1,125 @@
+package simulate
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	options "github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options/fake"
+)
+
+func runSimulate(t *testing.T, args ...string) (string, error) {
+	tf, o := options.NewFakeArgoRolloutsOptions()
+	defer tf.Cleanup()
+	cmd := NewCmdSimulate(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return o.Out.(*bytes.Buffer).String(), err
+}
+
+func TestSimulateCmdUsage(t *testing.T) {
+	tf, o := options.NewFakeArgoRolloutsOptions()
+	defer tf.Cleanup()
+	cmd := NewCmdSimulate(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{})
+	err := cmd.Execute()
+	assert.Error(t, err)
+	stderr := o.ErrOut.(*bytes.Buffer).String()
+	assert.Contains(t, stderr, "Usage:")
+	assert.Contains(t, stderr, "simulate")
+}
+
+func TestSimulateBasicCanary(t *testing.T) {
+	stdout, err := runSimulate(t, "-f", "testdata/basic-canary.yaml")
+	assert.NoError(t, err)
+	expected := `Rollout guestbook: canary with 10 replicas, maxSurge 1, maxUnavailable 1
+Pods: at most 11 in total, at least 9 available
+
+STEP  ACTION         CANARY  STABLE  TOTAL  WEIGHT
+-     initial        0       10      10     0
+0     setWeight: 20  1       9       10     10
+                     2       8       10     20
+1     pause          2       8       10     20
+2     setWeight: 50  3       7       10     30
+                     4       6       10     40
+                     5       5       10     50
+-     promote        6       4       10     60
+                     7       3       10     70
+                     8       2       10     80
+                     9       1       10     90
+                     10      0       10     100
+`
+	assert.Equal(t, expected, stdout)
+}
+
+func TestSimulateTrafficRoutedCanary(t *testing.T) {
+	stdout, err := runSimulate(t, "-f", "testdata/traffic-routed-canary.yaml")
+	assert.NoError(t, err)
+	expected := `Rollout guestbook: canary with 4 replicas, maxSurge 1, maxUnavailable 1
+
+STEP  ACTION                       CANARY  STABLE  TOTAL  WEIGHT
+-     initial                      0       4       4      0
+0     setCanaryScale{replicas: 1}  1       4       5      0
+1     setWeight: 25                1       4       5      25
+2     setWeight: 50                1       4       5      50
+-     promote                      4       2       6      100
+                                   4       0       4      100
+`
+	assert.Equal(t, expected, stdout)
+}
+
+func TestSimulateTrafficRoutedCanaryScaleDownStable(t *testing.T) {
+	ros, err := readRollouts("testdata/traffic-routed-canary.yaml")
+	assert.NoError(t, err)
+	ro := &ros[0]
+	ro.Spec.Strategy.Canary.DynamicStableScale = false
+	ro.Spec.Strategy.Canary.Steps = ro.Spec.Strategy.Canary.Steps[1:]
+
+	rows := SimulateRollout(ro)
+	assert.Equal(t, []SimulationRow{
+		{Step: -1, Action: "initial", Canary: 0, Stable: 4, Weight: 0},
+		{Step: 0, Action: "setWeight: 25", Canary: 1, Stable: 4, Weight: 25},
+		{Step: 1, Action: "setWeight: 50", Canary: 2, Stable: 4, Weight: 50},
+		{Step: -1, Action: "promote", Canary: 4, Stable: 4, Weight: 100},
+		{Step: -1, Action: "scale down stable after 30s", Canary: 4, Stable: 0, Weight: 100},
+	}, rows)
+}
+
+func TestSimulateBlueGreen(t *testing.T) {
+	stdout, err := runSimulate(t, "-f", "testdata/bluegreen.yaml")
+	assert.NoError(t, err)
+	expected := `Rollout guestbook: blueGreen with 3 replicas
+
+STEP  ACTION                                CANARY  STABLE  TOTAL  WEIGHT
+-     initial                               0       3       3      0
+-     scale up preview                      1       3       4      0
+-     promote                               3       3       6      100
+-     scale down previous active after 30s  3       0       3      100
+`
+	assert.Equal(t, expected, stdout)
+}
+
+func TestSimulateBlueGreenPreviewReplicaProfile(t *testing.T) {
+	stdout, err := runSimulate(t, "-f", "testdata/bluegreen-profile.yaml")
+	assert.NoError(t, err)
+	expected := `Rollout guestbook: blueGreen with 10 replicas
+
+STEP  ACTION                                CANARY  STABLE  TOTAL  WEIGHT
+-     initial                               0       10      10     0
+-     scale up preview                      3       10      13     0
+-     promote                               10      10      20     100
+-     scale down previous active after 30s  10      0       10     100
+`
+	assert.Equal(t, expected, stdout)
+}
+
+func TestSimulateNoRollout(t *testing.T) {
+	_, err := runSimulate(t, "-f", "testdata/no-rollout.yaml")
+	assert.EqualError(t, err, "no rollout found in testdata/no-rollout.yaml")
+
+	_, err = runSimulate(t, "-f", "testdata/does-not-exist.yaml")
+	assert.ErrorContains(t, err, "no such file or directory")
+}
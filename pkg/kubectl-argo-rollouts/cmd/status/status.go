@@ -2,21 +2,27 @@ package status
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/argoproj/argo-rollouts/pkg/apiclient/rollout"
 	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd/signals"
 	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
 	completionutil "github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/util/completion"
 	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/viewcontroller"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	timeutil "github.com/argoproj/argo-rollouts/utils/time"
 )
 
 const (
 	statusLong = `Watch rollout until it finishes or the timeout is exceeded. Returns success if
-the rollout is healthy upon completion and an error otherwise.`
+the rollout is healthy upon completion and an error otherwise. The exit code tells the failures
+apart: 2 if the rollout is degraded, 3 if it was aborted and 4 if the timeout was exceeded.`
 	statusExample = `
 	# Watch the rollout until it succeeds
 	%[1]s status guestbook
@@ -26,12 +32,71 @@ the rollout is healthy upon completion and an error otherwise.`
 
 	# Watch the rollout until it succeeds, fail if it takes more than 60 seconds
 	%[1]s status --timeout 60s guestbook
+
+	# Watch the rollout and print a JSON object per status change
+	%[1]s status guestbook -o json
 	`
 )
 
+const (
+	// ExitCodeDegraded is the exit code of the command when the rollout is degraded
+	ExitCodeDegraded = 2
+	// ExitCodeAborted is the exit code of the command when the rollout was aborted
+	ExitCodeAborted = 3
+	// ExitCodeTimeout is the exit code of the command when the timeout was exceeded
+	ExitCodeTimeout = 4
+)
+
+const (
+	ResultHealthy  = "Healthy"
+	ResultDegraded = "Degraded"
+	ResultAborted  = "Aborted"
+	ResultTimeout  = "Timeout"
+)
+
+// ExitError is an error with the exit code of the command
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit code of the command
+func (e *ExitError) ExitCode() int {
+	return e.Code
+}
+
+// StatusEvent is the JSON representation of a status change of a rollout
+type StatusEvent struct {
+	Time         metav1.Time `json:"time"`
+	Name         string      `json:"name"`
+	Namespace    string      `json:"namespace"`
+	Status       string      `json:"status"`
+	Message      string      `json:"message,omitempty"`
+	Step         string      `json:"step,omitempty"`
+	SetWeight    string      `json:"setWeight,omitempty"`
+	ActualWeight string      `json:"actualWeight,omitempty"`
+	Desired      int32       `json:"desired"`
+	Updated      int32       `json:"updated"`
+	Ready        int32       `json:"ready"`
+	Available    int32       `json:"available"`
+	// Result is set on the last event, to one of Healthy, Degraded, Aborted or Timeout
+	Result string `json:"result,omitempty"`
+	// ExitCode is set on the last event to the exit code of the command
+	ExitCode *int `json:"exitCode,omitempty"`
+}
+
 type StatusOptions struct {
 	Watch   bool
 	Timeout time.Duration
+	Output  string
 
 	options.ArgoRolloutsOptions
 }
@@ -53,6 +118,9 @@ func NewCmdStatus(o *options.ArgoRolloutsOptions) *cobra.Command {
 				return o.UsageErr(c)
 			}
 			name := args[0]
+			if statusOptions.Output != "" && statusOptions.Output != "json" {
+				return fmt.Errorf("unknown output format %q, must be one of: json", statusOptions.Output)
+			}
 			controller := viewcontroller.NewRolloutViewController(o.Namespace(), name, statusOptions.KubeClientset(), statusOptions.RolloutsClientset())
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
@@ -65,10 +133,12 @@ func NewCmdStatus(o *options.ArgoRolloutsOptions) *cobra.Command {
 			}
 
 			if !statusOptions.Watch {
-				if ri.Status == "Healthy" || ri.Status == "Degraded" {
-					fmt.Fprintln(o.Out, ri.Status)
-				} else {
-					fmt.Fprintf(o.Out, "%s - %s\n", ri.Status, ri.Message)
+				if statusOptions.Output == "" {
+					if ri.Status == "Healthy" || ri.Status == "Degraded" {
+						fmt.Fprintln(o.Out, ri.Status)
+					} else {
+						fmt.Fprintf(o.Out, "%s - %s\n", ri.Status, ri.Message)
+					}
 				}
 			} else {
 				rolloutUpdates := make(chan *rollout.RolloutInfo)
@@ -86,25 +156,74 @@ func NewCmdStatus(o *options.ArgoRolloutsOptions) *cobra.Command {
 				}
 			}
 
-			if ri.Status == "Degraded" {
-				return fmt.Errorf("The rollout is in a degraded state with message: %s", ri.Message)
-			} else if ri.Status != "Healthy" && statusOptions.Watch {
-				return fmt.Errorf("Rollout status watch exceeded timeout")
+			result, err := statusResult(ri, statusOptions.Watch)
+			if statusOptions.Output == "json" {
+				exitCode := 0
+				if exitErr, ok := err.(*ExitError); ok {
+					exitCode = exitErr.Code
+				}
+				event := newStatusEvent(ri)
+				event.Result = result
+				event.ExitCode = &exitCode
+				statusOptions.printEvent(event)
 			}
-
-			return nil
+			return err
 		},
 		ValidArgsFunction: completionutil.RolloutNameCompletionFunc(o),
 	}
 	cmd.Flags().BoolVarP(&statusOptions.Watch, "watch", "w", true, "Watch the status of the rollout until it's done")
 	cmd.Flags().DurationVarP(&statusOptions.Timeout, "timeout", "t", time.Duration(0), "The length of time to watch before giving up. Non-zero values should contain a corresponding time unit (e.g. 1s, 2m, 3h). Zero means wait forever")
+	cmd.Flags().StringVarP(&statusOptions.Output, "output", "o", "", "Output format. One of: json. Prints a JSON object per status change, the last one with the result and the exit code")
 	return cmd
 }
 
+// statusResult returns the result of the status of the rollout, and an ExitError if the rollout did not succeed. The
+// result is empty while the rollout is in progress and not watched.
+func statusResult(ri *rollout.RolloutInfo, watch bool) (string, error) {
+	switch {
+	case ri.Status == "Healthy":
+		return ResultHealthy, nil
+	case ri.Status == "Degraded" && strings.HasPrefix(ri.Message, conditions.RolloutAbortedReason+":"):
+		return ResultAborted, &ExitError{Code: ExitCodeAborted, Err: fmt.Errorf("The rollout is in a degraded state with message: %s", ri.Message)}
+	case ri.Status == "Degraded":
+		return ResultDegraded, &ExitError{Code: ExitCodeDegraded, Err: fmt.Errorf("The rollout is in a degraded state with message: %s", ri.Message)}
+	case watch:
+		return ResultTimeout, &ExitError{Code: ExitCodeTimeout, Err: fmt.Errorf("Rollout status watch exceeded timeout")}
+	}
+	return "", nil
+}
+
+func newStatusEvent(roInfo *rollout.RolloutInfo) StatusEvent {
+	return StatusEvent{
+		Time:         timeutil.MetaNow(),
+		Name:         roInfo.ObjectMeta.Name,
+		Namespace:    roInfo.ObjectMeta.Namespace,
+		Status:       roInfo.Status,
+		Message:      roInfo.Message,
+		Step:         roInfo.Step,
+		SetWeight:    roInfo.SetWeight,
+		ActualWeight: roInfo.ActualWeight,
+		Desired:      roInfo.Desired,
+		Updated:      roInfo.Updated,
+		Ready:        roInfo.Ready,
+		Available:    roInfo.Available,
+	}
+}
+
+func (o *StatusOptions) printEvent(event StatusEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		o.Log.Warnf("Failed to marshal the status event: %v", err)
+		return
+	}
+	fmt.Fprintln(o.Out, string(data))
+}
+
 func (o *StatusOptions) WatchStatus(stopCh <-chan struct{}, rolloutUpdates <-chan *rollout.RolloutInfo) string {
 	timeout := make(chan bool)
 	var roInfo *rollout.RolloutInfo
 	var prevMessage string
+	var prevEvent StatusEvent
 
 	if o.Timeout != 0 {
 		go func() {
@@ -114,6 +233,17 @@ func (o *StatusOptions) WatchStatus(stopCh <-chan struct{}, rolloutUpdates <-cha
 	}
 
 	printStatus := func(roInfo rollout.RolloutInfo) {
+		if o.Output == "json" {
+			event := newStatusEvent(&roInfo)
+			// only the changes of the rollout are printed, regardless of the time
+			event.Time = prevEvent.Time
+			if event != prevEvent {
+				event.Time = timeutil.MetaNow()
+				o.printEvent(event)
+				prevEvent = event
+			}
+			return
+		}
 		message := roInfo.Status
 		if roInfo.Message != "" {
 			message = fmt.Sprintf("%s - %s", roInfo.Status, roInfo.Message)
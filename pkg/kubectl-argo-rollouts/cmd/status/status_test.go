@@ -2,6 +2,9 @@ package status
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -142,3 +145,87 @@ func TestWatchTimeoutRollout(t *testing.T) {
 	assert.Equal(t, "Paused - BlueGreenPause\n", stdout)
 	assert.Equal(t, "Error: Rollout status watch exceeded timeout\n", stderr)
 }
+
+func TestStatusExitCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		objs     *testdata.RolloutObjects
+		args     []string
+		exitCode int
+	}{
+		{"degraded", testdata.NewInvalidRollout(), []string{noWatch}, ExitCodeDegraded},
+		{"aborted", testdata.NewAbortedRollout(), []string{noWatch}, ExitCodeAborted},
+		{"timeout", testdata.NewBlueGreenRollout(), []string{"--timeout=1s"}, ExitCodeTimeout},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tf, o := options.NewFakeArgoRolloutsOptions(test.objs.AllObjects()...)
+			o.RESTClientGetter = tf.WithNamespace(test.objs.Rollouts[0].Namespace)
+			defer tf.Cleanup()
+			cmd := NewCmdStatus(o)
+			cmd.PersistentPreRunE = o.PersistentPreRunE
+			cmd.SetArgs(append([]string{test.objs.Rollouts[0].Name}, test.args...))
+			err := cmd.Execute()
+
+			var exitErr *ExitError
+			assert.True(t, errors.As(err, &exitErr))
+			assert.Equal(t, test.exitCode, exitErr.ExitCode())
+		})
+	}
+}
+
+func TestStatusJSONOutput(t *testing.T) {
+	rolloutObjs := testdata.NewAbortedRollout()
+
+	tf, o := options.NewFakeArgoRolloutsOptions(rolloutObjs.AllObjects()...)
+	o.RESTClientGetter = tf.WithNamespace(rolloutObjs.Rollouts[0].Namespace)
+	defer tf.Cleanup()
+	cmd := NewCmdStatus(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{rolloutObjs.Rollouts[0].Name, "-o", "json"})
+	err := cmd.Execute()
+	assert.Error(t, err)
+
+	lines := strings.Split(strings.TrimSpace(o.Out.(*bytes.Buffer).String()), "\n")
+	assert.Len(t, lines, 2)
+	var progress, last StatusEvent
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &progress))
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &last))
+	assert.Equal(t, rolloutObjs.Rollouts[0].Name, progress.Name)
+	assert.Equal(t, "Degraded", progress.Status)
+	assert.Empty(t, progress.Result)
+	assert.Nil(t, progress.ExitCode)
+	assert.Equal(t, ResultAborted, last.Result)
+	assert.Equal(t, ExitCodeAborted, *last.ExitCode)
+	assert.Equal(t, "RolloutAborted: metric \"web\" assessed Failed due to failed (1) > failureLimit (0)", last.Message)
+}
+
+func TestStatusJSONOutputNoWatch(t *testing.T) {
+	rolloutObjs := testdata.NewBlueGreenRollout()
+
+	tf, o := options.NewFakeArgoRolloutsOptions(rolloutObjs.AllObjects()...)
+	o.RESTClientGetter = tf.WithNamespace(rolloutObjs.Rollouts[0].Namespace)
+	defer tf.Cleanup()
+	cmd := NewCmdStatus(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{rolloutObjs.Rollouts[0].Name, noWatch, "-o", "json"})
+	err := cmd.Execute()
+	assert.NoError(t, err)
+
+	var event StatusEvent
+	assert.NoError(t, json.Unmarshal(o.Out.(*bytes.Buffer).Bytes(), &event))
+	assert.Equal(t, "Paused", event.Status)
+	assert.Equal(t, "BlueGreenPause", event.Message)
+	assert.Empty(t, event.Result)
+	assert.Equal(t, 0, *event.ExitCode)
+}
+
+func TestStatusInvalidOutput(t *testing.T) {
+	tf, o := options.NewFakeArgoRolloutsOptions()
+	defer tf.Cleanup()
+	cmd := NewCmdStatus(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{"guestbook", "-o", "yaml"})
+	err := cmd.Execute()
+	assert.EqualError(t, err, "unknown output format \"yaml\", must be one of: json")
+}
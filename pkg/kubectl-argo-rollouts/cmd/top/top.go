@@ -0,0 +1,221 @@
+package top
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	clientset "github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
+	completionutil "github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/util/completion"
+)
+
+const (
+	topExample = `
+	# Compare the resource usage of the canary and stable pods of a rollout
+	%[1]s top guestbook`
+
+	topHeaderFmtString = "ROLE\tPOD-TEMPLATE-HASH\tPODS\tCPU\tMEMORY\tCPU/POD\tMEMORY/POD\n"
+	topColumnFmtString = "%s\t%s\t%d\t%s\t%s\t%s\t%s\n"
+)
+
+const (
+	// RoleStable is the role of the pods of the stable ReplicaSet
+	RoleStable = "stable"
+	// RoleCanary is the role of the pods of the current ReplicaSet while it is not yet the stable one
+	RoleCanary = "canary"
+	// RoleOld is the role of the pods of any other ReplicaSet, e.g. one which is scaling down
+	RoleOld = "old"
+)
+
+// PodMetricsGVR is the resource of the pod metrics served by metrics-server
+var PodMetricsGVR = schema.GroupVersionResource{
+	Group:    "metrics.k8s.io",
+	Version:  "v1beta1",
+	Resource: "pods",
+}
+
+// ResourceUsage is the aggregated resource usage of the pods of a pod-template-hash of a rollout
+type ResourceUsage struct {
+	Role            string
+	PodTemplateHash string
+	// Pods is the number of pods which reported metrics
+	Pods   int
+	CPU    resource.Quantity
+	Memory resource.Quantity
+}
+
+// NewCmdTop returns a new instance of an `rollouts top` command
+func NewCmdTop(o *options.ArgoRolloutsOptions) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "top ROLLOUT_NAME",
+		Short: "Show the resource usage of the canary and stable pods of a rollout",
+		Long: "Show the CPU and memory usage of the pods of a rollout, aggregated by pod-template-hash, so that the " +
+			"canary and the stable pods can be compared side by side. The usage is read from the metrics API, which " +
+			"requires metrics-server to be installed in the cluster.",
+		Example:      o.Example(topExample),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return o.UsageErr(c)
+			}
+			usages, err := GetRolloutResourceUsage(c.Context(), o.KubeClientset(), o.RolloutsClientset(), o.DynamicClientset(), o.Namespace(), args[0])
+			if err != nil {
+				return err
+			}
+			printTopTable(o.Out, o.ErrOut, usages)
+			return nil
+		},
+		ValidArgsFunction: completionutil.RolloutNameCompletionFunc(o),
+	}
+	return cmd
+}
+
+// GetRolloutResourceUsage returns the resource usage of the pods of a rollout aggregated by pod-template-hash, the
+// stable pods first followed by the canary pods
+func GetRolloutResourceUsage(ctx context.Context, kubeClient kubernetes.Interface, rolloutClient clientset.Interface, dynamicClient dynamic.Interface, namespace, name string) ([]ResourceUsage, error) {
+	ro, err := rolloutClient.ArgoprojV1alpha1().Rollouts(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if ro.Spec.Selector == nil {
+		return nil, fmt.Errorf("rollout '%s' has no selector", name)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(ro.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	listOpts := metav1.ListOptions{LabelSelector: selector.String()}
+	podList, err := kubeClient.CoreV1().Pods(namespace).List(ctx, listOpts)
+	if err != nil {
+		return nil, err
+	}
+	metricsList, err := dynamicClient.Resource(PodMetricsGVR).Namespace(namespace).List(ctx, listOpts)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, fmt.Errorf("metrics API not available, make sure metrics-server is installed: %w", err)
+		}
+		return nil, err
+	}
+
+	podHashes := map[string]string{}
+	for _, pod := range podList.Items {
+		if hash, ok := pod.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]; ok {
+			podHashes[pod.Name] = hash
+		}
+	}
+	usageByHash := map[string]*ResourceUsage{}
+	for i := range metricsList.Items {
+		podMetrics := &metricsList.Items[i]
+		hash, ok := podHashes[podMetrics.GetName()]
+		if !ok {
+			continue
+		}
+		cpu, memory, err := podUsage(podMetrics)
+		if err != nil {
+			return nil, fmt.Errorf("invalid metrics of pod '%s': %w", podMetrics.GetName(), err)
+		}
+		usage, ok := usageByHash[hash]
+		if !ok {
+			usage = &ResourceUsage{Role: role(ro, hash), PodTemplateHash: hash}
+			usageByHash[hash] = usage
+		}
+		usage.Pods++
+		usage.CPU.Add(cpu)
+		usage.Memory.Add(memory)
+	}
+
+	usages := make([]ResourceUsage, 0, len(usageByHash))
+	for _, usage := range usageByHash {
+		usages = append(usages, *usage)
+	}
+	rolePriority := map[string]int{RoleStable: 0, RoleCanary: 1, RoleOld: 2}
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].Role != usages[j].Role {
+			return rolePriority[usages[i].Role] < rolePriority[usages[j].Role]
+		}
+		return usages[i].PodTemplateHash < usages[j].PodTemplateHash
+	})
+	return usages, nil
+}
+
+func role(ro *v1alpha1.Rollout, hash string) string {
+	switch hash {
+	case ro.Status.StableRS:
+		return RoleStable
+	case ro.Status.CurrentPodHash:
+		return RoleCanary
+	default:
+		return RoleOld
+	}
+}
+
+// podUsage returns the sum of the CPU and memory usage of the containers of a PodMetrics object
+func podUsage(podMetrics *unstructured.Unstructured) (resource.Quantity, resource.Quantity, error) {
+	var cpu, memory resource.Quantity
+	containers, _, err := unstructured.NestedSlice(podMetrics.Object, "containers")
+	if err != nil {
+		return cpu, memory, err
+	}
+	for _, container := range containers {
+		containerMap, ok := container.(map[string]any)
+		if !ok {
+			continue
+		}
+		usage, _, err := unstructured.NestedStringMap(containerMap, "usage")
+		if err != nil {
+			return cpu, memory, err
+		}
+		for name, value := range usage {
+			quantity, err := resource.ParseQuantity(value)
+			if err != nil {
+				return cpu, memory, err
+			}
+			switch name {
+			case "cpu":
+				cpu.Add(quantity)
+			case "memory":
+				memory.Add(quantity)
+			}
+		}
+	}
+	return cpu, memory, nil
+}
+
+func formatCPU(milliCPU int64) string {
+	return fmt.Sprintf("%dm", milliCPU)
+}
+
+func formatMemory(bytes int64) string {
+	return fmt.Sprintf("%dMi", bytes/(1024*1024))
+}
+
+// printTopTable prints the resource usage in table format
+func printTopTable(out, errOut io.Writer, usages []ResourceUsage) {
+	if len(usages) == 0 {
+		fmt.Fprintln(errOut, "No resources found.")
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprint(w, topHeaderFmtString)
+	for _, usage := range usages {
+		cpu := usage.CPU.MilliValue()
+		memory := usage.Memory.Value()
+		fmt.Fprintf(w, topColumnFmtString, usage.Role, usage.PodTemplateHash, usage.Pods,
+			formatCPU(cpu), formatMemory(memory),
+			formatCPU(cpu/int64(usage.Pods)), formatMemory(memory/int64(usage.Pods)))
+	}
+	_ = w.Flush()
+}
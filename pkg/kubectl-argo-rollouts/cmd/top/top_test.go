@@ -0,0 +1,149 @@
+package top
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	options "github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options/fake"
+)
+
+func newRollout() *v1alpha1.Rollout {
+	return &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: "test"},
+		Spec: v1alpha1.RolloutSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "guestbook"}},
+		},
+		Status: v1alpha1.RolloutStatus{
+			StableRS:       "stable-hash",
+			CurrentPodHash: "canary-hash",
+		},
+	}
+}
+
+func newPod(name, hash string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "test",
+			Labels: map[string]string{
+				"app":                                 "guestbook",
+				v1alpha1.DefaultRolloutUniqueLabelKey: hash,
+			},
+		},
+	}
+}
+
+func newPodMetrics(name string, usages ...map[string]any) *unstructured.Unstructured {
+	var containers []any
+	for _, usage := range usages {
+		containers = append(containers, map[string]any{"name": "guestbook", "usage": usage})
+	}
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "metrics.k8s.io/v1beta1",
+		"kind":       "PodMetrics",
+		"metadata": map[string]any{
+			"name":      name,
+			"namespace": "test",
+			"labels":    map[string]any{"app": "guestbook"},
+		},
+		"containers": containers,
+	}}
+}
+
+// newMetricsClient returns a fake dynamic client serving the pod metrics. The metrics are added to the tracker with
+// their resource, since it cannot be guessed from their kind.
+func newMetricsClient(objs ...*unstructured.Unstructured) *dynamicfake.FakeDynamicClient {
+	listMapping := map[schema.GroupVersionResource]string{
+		PodMetricsGVR: "PodMetricsList",
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listMapping)
+	for _, obj := range objs {
+		if err := client.Tracker().Create(PodMetricsGVR, obj, obj.GetNamespace()); err != nil {
+			panic(err)
+		}
+	}
+	return client
+}
+
+func TestTopCmdUsage(t *testing.T) {
+	tf, o := options.NewFakeArgoRolloutsOptions()
+	defer tf.Cleanup()
+	cmd := NewCmdTop(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{})
+	err := cmd.Execute()
+	assert.Error(t, err)
+	stderr := o.ErrOut.(*bytes.Buffer).String()
+	assert.Contains(t, stderr, "Usage:")
+	assert.Contains(t, stderr, "top ROLLOUT_NAME")
+}
+
+func TestTopCmd(t *testing.T) {
+	tf, o := options.NewFakeArgoRolloutsOptions(
+		newRollout(),
+		newPod("guestbook-stable-1", "stable-hash"),
+		newPod("guestbook-stable-2", "stable-hash"),
+		newPod("guestbook-canary-1", "canary-hash"),
+		newPod("guestbook-old-1", "old-hash"),
+	)
+	defer tf.Cleanup()
+	o.RESTClientGetter = tf.WithNamespace("test")
+	o.DynamicClient = newMetricsClient(
+		newPodMetrics("guestbook-stable-1", map[string]any{"cpu": "100m", "memory": "64Mi"}),
+		newPodMetrics("guestbook-stable-2", map[string]any{"cpu": "200m", "memory": "128Mi"}),
+		newPodMetrics("guestbook-canary-1",
+			map[string]any{"cpu": "250m", "memory": "100Mi"},
+			map[string]any{"cpu": "50m", "memory": "28Mi"}),
+		newPodMetrics("guestbook-old-1", map[string]any{"cpu": "1", "memory": "1Gi"}),
+		newPodMetrics("unrelated", map[string]any{"cpu": "5", "memory": "5Gi"}),
+	)
+
+	cmd := NewCmdTop(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{"guestbook"})
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	stdout := o.Out.(*bytes.Buffer).String()
+	expected := `ROLE    POD-TEMPLATE-HASH  PODS  CPU    MEMORY  CPU/POD  MEMORY/POD
+stable  stable-hash        2     300m   192Mi   150m     96Mi
+canary  canary-hash        1     300m   128Mi   300m     128Mi
+old     old-hash           1     1000m  1024Mi  1000m    1024Mi
+`
+	assert.Equal(t, expected, stdout)
+}
+
+func TestTopCmdNoMetrics(t *testing.T) {
+	tf, o := options.NewFakeArgoRolloutsOptions(newRollout(), newPod("guestbook-stable-1", "stable-hash"))
+	defer tf.Cleanup()
+	o.RESTClientGetter = tf.WithNamespace("test")
+	o.DynamicClient = newMetricsClient()
+
+	cmd := NewCmdTop(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{"guestbook"})
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	assert.Empty(t, o.Out.(*bytes.Buffer).String())
+	assert.Equal(t, "No resources found.\n", o.ErrOut.(*bytes.Buffer).String())
+}
+
+func TestTopCmdRolloutNotFound(t *testing.T) {
+	tf, o := options.NewFakeArgoRolloutsOptions()
+	defer tf.Cleanup()
+	o.RESTClientGetter = tf.WithNamespace("test")
+
+	cmd := NewCmdTop(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{"guestbook"})
+	err := cmd.Execute()
+	assert.EqualError(t, err, "rollouts.argoproj.io \"guestbook\" not found")
+}
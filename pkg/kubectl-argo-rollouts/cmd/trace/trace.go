@@ -0,0 +1,209 @@
+package trace
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	clientset "github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
+	completionutil "github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/util/completion"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	timeutil "github.com/argoproj/argo-rollouts/utils/time"
+)
+
+const (
+	traceExample = `
+	# Show the timeline of a rollout
+	%[1]s trace guestbook
+
+	# Show the timeline of the last hour of a rollout
+	%[1]s trace guestbook --since 1h`
+
+	traceHeaderFmtString = "TIME\tSOURCE\tREASON\tMESSAGE\n"
+	traceColumnFmtString = "%s\t%s\t%s\t%s\n"
+)
+
+const (
+	// SourceCondition is an entry of a transition of a rollout condition
+	SourceCondition = "Condition"
+	// SourceEvent is an entry of a Kubernetes event of the rollout
+	SourceEvent = "Event"
+	// SourceStep is an entry of a transition between the steps of the rollout
+	SourceStep = "Step"
+	// SourceAnalysis is an entry of a measurement of an analysis run of the rollout
+	SourceAnalysis = "Analysis"
+)
+
+// TraceEntry is an entry of the timeline of a rollout
+type TraceEntry struct {
+	Time    metav1.Time
+	Source  string
+	Reason  string
+	Message string
+}
+
+// NewCmdTrace returns a new instance of an `rollouts trace` command
+func NewCmdTrace(o *options.ArgoRolloutsOptions) *cobra.Command {
+	var since time.Duration
+	var cmd = &cobra.Command{
+		Use:   "trace ROLLOUT_NAME",
+		Short: "Show the timeline of a rollout",
+		Long: "Show a chronological timeline of a rollout, which merges the transitions of its conditions, its events, " +
+			"its step transitions and the measurements of its analysis runs. Events expire and analysis runs only keep " +
+			"their latest measurements, so that older entries might be missing.",
+		Example:      o.Example(traceExample),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return o.UsageErr(c)
+			}
+			entries, err := GetRolloutTrace(c.Context(), o.KubeClientset(), o.RolloutsClientset(), o.Namespace(), args[0])
+			if err != nil {
+				return err
+			}
+			if since > 0 {
+				entries = entriesSince(entries, timeutil.Now().Add(-since))
+			}
+			printTraceTable(o.Out, o.ErrOut, entries)
+			return nil
+		},
+		ValidArgsFunction: completionutil.RolloutNameCompletionFunc(o),
+	}
+	cmd.Flags().DurationVar(&since, "since", 0, "Only show the entries newer than a relative duration like 5m or 1h. Defaults to all entries")
+	return cmd
+}
+
+// GetRolloutTrace returns the timeline of a rollout, the oldest entry first
+func GetRolloutTrace(ctx context.Context, kubeClient kubernetes.Interface, rolloutClient clientset.Interface, namespace, name string) ([]TraceEntry, error) {
+	ro, err := rolloutClient.ArgoprojV1alpha1().Rollouts(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	selector := fields.Set{
+		"involvedObject.kind": "Rollout",
+		"involvedObject.uid":  string(ro.UID),
+	}.AsSelector().String()
+	eventList, err := kubeClient.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{FieldSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	arList, err := rolloutClient.ArgoprojV1alpha1().AnalysisRuns(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []TraceEntry
+	for _, cond := range ro.Status.Conditions {
+		entries = append(entries, TraceEntry{
+			Time:    cond.LastTransitionTime,
+			Source:  SourceCondition,
+			Reason:  cond.Reason,
+			Message: fmt.Sprintf("%s=%s: %s", cond.Type, cond.Status, cond.Message),
+		})
+	}
+	for i := range eventList.Items {
+		if entry, ok := eventEntry(&eventList.Items[i]); ok {
+			entries = append(entries, entry)
+		}
+	}
+	for i := range arList.Items {
+		ar := &arList.Items[i]
+		if ownerRef := metav1.GetControllerOf(ar); ownerRef == nil || ownerRef.UID != ro.UID {
+			continue
+		}
+		entries = append(entries, analysisEntries(ar)...)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(&entries[j].Time)
+	})
+	return entries, nil
+}
+
+func eventEntry(event *corev1.Event) (TraceEntry, bool) {
+	eventTime := event.LastTimestamp
+	if event.Series != nil && !event.Series.LastObservedTime.IsZero() {
+		eventTime = metav1.NewTime(event.Series.LastObservedTime.Time)
+	} else if eventTime.IsZero() {
+		eventTime = metav1.NewTime(event.EventTime.Time)
+	}
+	if eventTime.IsZero() {
+		return TraceEntry{}, false
+	}
+	source := SourceEvent
+	if event.Reason == conditions.RolloutStepCompletedReason {
+		source = SourceStep
+	}
+	message := event.Message
+	if event.Count > 1 {
+		message = fmt.Sprintf("%s (x%d)", message, event.Count)
+	}
+	return TraceEntry{
+		Time:    eventTime,
+		Source:  source,
+		Reason:  event.Reason,
+		Message: message,
+	}, true
+}
+
+// analysisEntries returns an entry for each of the completed measurements of an analysis run
+func analysisEntries(ar *v1alpha1.AnalysisRun) []TraceEntry {
+	var entries []TraceEntry
+	for _, result := range ar.Status.MetricResults {
+		for _, measurement := range result.Measurements {
+			if measurement.FinishedAt == nil {
+				continue
+			}
+			message := fmt.Sprintf("%s/%s: %s", ar.Name, result.Name, measurement.Phase)
+			if measurement.Value != "" {
+				message += fmt.Sprintf(" (value: %s)", measurement.Value)
+			}
+			if measurement.Message != "" {
+				message += ": " + measurement.Message
+			}
+			entries = append(entries, TraceEntry{
+				Time:    *measurement.FinishedAt,
+				Source:  SourceAnalysis,
+				Reason:  "Measurement" + string(measurement.Phase),
+				Message: message,
+			})
+		}
+	}
+	return entries
+}
+
+func entriesSince(entries []TraceEntry, since time.Time) []TraceEntry {
+	var filtered []TraceEntry
+	for _, entry := range entries {
+		if !entry.Time.Time.Before(since) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// printTraceTable prints the timeline in table format
+func printTraceTable(out, errOut io.Writer, entries []TraceEntry) {
+	if len(entries) == 0 {
+		fmt.Fprintln(errOut, "No resources found.")
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprint(w, traceHeaderFmtString)
+	for _, entry := range entries {
+		message := strings.ReplaceAll(entry.Message, "\n", " ")
+		fmt.Fprintf(w, traceColumnFmtString, entry.Time.UTC().Format(time.RFC3339), entry.Source, entry.Reason, message)
+	}
+	_ = w.Flush()
+}
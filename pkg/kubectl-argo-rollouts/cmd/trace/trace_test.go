@@ -0,0 +1,125 @@
+package trace
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	options "github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options/fake"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func at(minutes int) metav1.Time {
+	return metav1.NewTime(start.Add(time.Duration(minutes) * time.Minute))
+}
+
+func newEvent(name, reason, message string, minutes int) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "test"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Rollout", Name: "guestbook", UID: "rollout-uid"},
+		Reason:         reason,
+		Message:        message,
+		LastTimestamp:  at(minutes),
+	}
+}
+
+func newTraceObjects() (*v1alpha1.Rollout, *v1alpha1.AnalysisRun, []*corev1.Event) {
+	ro := &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: "test", UID: "rollout-uid"},
+		Status: v1alpha1.RolloutStatus{
+			Conditions: []v1alpha1.RolloutCondition{{
+				Type:               v1alpha1.RolloutProgressing,
+				Status:             corev1.ConditionTrue,
+				Reason:             "ReplicaSetUpdated",
+				Message:            "ReplicaSet \"guestbook-abc\" is progressing.",
+				LastTransitionTime: at(1),
+			}},
+		},
+	}
+	ar := &v1alpha1.AnalysisRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "guestbook-abc-2-1",
+			Namespace: "test",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "argoproj.io/v1alpha1",
+				Kind:       "Rollout",
+				Name:       "guestbook",
+				UID:        "rollout-uid",
+				Controller: ptr.To(true),
+			}},
+		},
+		Status: v1alpha1.AnalysisRunStatus{
+			MetricResults: []v1alpha1.MetricResult{{
+				Name: "success-rate",
+				Measurements: []v1alpha1.Measurement{
+					{Phase: v1alpha1.AnalysisPhaseSuccessful, Value: "[0.99]", FinishedAt: ptr.To(at(3))},
+					{Phase: v1alpha1.AnalysisPhaseRunning},
+				},
+			}},
+		},
+	}
+	events := []*corev1.Event{
+		newEvent("updated", "RolloutUpdated", "Rollout updated to revision 2", 0),
+		newEvent("step", "RolloutStepCompleted", "Rollout step 1/3 completed (setWeight: 20)", 2),
+	}
+	return ro, ar, events
+}
+
+func TestTraceCmdUsage(t *testing.T) {
+	tf, o := options.NewFakeArgoRolloutsOptions()
+	defer tf.Cleanup()
+	cmd := NewCmdTrace(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{})
+	err := cmd.Execute()
+	assert.Error(t, err)
+	stderr := o.ErrOut.(*bytes.Buffer).String()
+	assert.Contains(t, stderr, "Usage:")
+	assert.Contains(t, stderr, "trace ROLLOUT_NAME")
+}
+
+func TestTraceCmd(t *testing.T) {
+	ro, ar, events := newTraceObjects()
+	tf, o := options.NewFakeArgoRolloutsOptions(ro, ar, events[0], events[1])
+	o.RESTClientGetter = tf.WithNamespace("test")
+	defer tf.Cleanup()
+	cmd := NewCmdTrace(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{"guestbook"})
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	stdout := o.Out.(*bytes.Buffer).String()
+	expected := `TIME                  SOURCE     REASON                 MESSAGE
+2024-01-01T00:00:00Z  Event      RolloutUpdated         Rollout updated to revision 2
+2024-01-01T00:01:00Z  Condition  ReplicaSetUpdated      Progressing=True: ReplicaSet "guestbook-abc" is progressing.
+2024-01-01T00:02:00Z  Step       RolloutStepCompleted   Rollout step 1/3 completed (setWeight: 20)
+2024-01-01T00:03:00Z  Analysis   MeasurementSuccessful  guestbook-abc-2-1/success-rate: Successful (value: [0.99])
+`
+	assert.Equal(t, expected, stdout)
+}
+
+func TestTraceCmdSince(t *testing.T) {
+	ro, ar, events := newTraceObjects()
+	tf, o := options.NewFakeArgoRolloutsOptions(ro, ar, events[0], events[1])
+	o.RESTClientGetter = tf.WithNamespace("test")
+	defer tf.Cleanup()
+	cmd := NewCmdTrace(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{"guestbook", "--since", "1h"})
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	assert.Empty(t, o.Out.(*bytes.Buffer).String())
+	assert.Equal(t, "No resources found.\n", o.ErrOut.(*bytes.Buffer).String())
+}
+
+func TestEntriesSince(t *testing.T) {
+	entries := []TraceEntry{{Time: at(0)}, {Time: at(2)}, {Time: at(4)}}
+	assert.Equal(t, entries[1:], entriesSince(entries, start.Add(2*time.Minute)))
+}
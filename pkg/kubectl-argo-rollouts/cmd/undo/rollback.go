@@ -0,0 +1,173 @@
+package undo
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
+	completionutil "github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/util/completion"
+	routils "github.com/argoproj/argo-rollouts/utils/unstructured"
+)
+
+const (
+	rollbackExample = `
+	# Show the changes of rolling back a rollout to revision 3 and roll it back
+	%[1]s rollback guestbook --to-revision=3
+
+	# Only show the changes of rolling back a rollout to its previous revision
+	%[1]s rollback guestbook --dry-run`
+)
+
+// NewCmdRollback returns a new instance of an `rollouts rollback` command
+func NewCmdRollback(o *options.ArgoRolloutsOptions) *cobra.Command {
+	var (
+		toRevision = int64(0)
+		dryRun     = false
+	)
+	var cmd = &cobra.Command{
+		Use:   "rollback ROLLOUT_NAME",
+		Short: "Rollback a rollout after showing the changes",
+		Long: "Rollback to a previous revision of the rollout. The changes of the pod template are computed by a " +
+			"server-side dry run of the rollback and shown as a diff before the rollback is performed.",
+		Example:      o.Example(rollbackExample),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return o.UsageErr(c)
+			}
+			name := args[0]
+			rolloutIf := o.DynamicClientset().Resource(v1alpha1.RolloutGVR).Namespace(o.Namespace())
+			clientset := o.KubeClientset()
+			return RunRollback(o.Out, rolloutIf, clientset, name, toRevision, dryRun)
+		},
+		ValidArgsFunction: completionutil.RolloutNameCompletionFunc(o),
+	}
+	cmd.Flags().Int64Var(&toRevision, "to-revision", toRevision, "The revision to rollback to. Default to 0 (last revision).")
+	_ = cmd.RegisterFlagCompletionFunc("to-revision", completionutil.RevisionCompletionFunc(o))
+	cmd.Flags().BoolVar(&dryRun, "dry-run", dryRun, "Only show the changes without rolling back")
+	return cmd
+}
+
+// RunRollback writes the diff between the current pod template of the rollout and the one of the revision to
+// rollback to, and performs the rollback unless dryRun is set
+func RunRollback(out io.Writer, rolloutIf dynamic.ResourceInterface, c kubernetes.Interface, name string, toRevision int64, dryRun bool) error {
+	ctx := context.TODO()
+	ro, err := rolloutIf.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	rsForRevision, err := rolloutRevision(ro, c, toRevision)
+	if err != nil {
+		return err
+	}
+	targetRevision, err := revision(rsForRevision)
+	if err != nil {
+		return err
+	}
+
+	equal, err := equalIgnoreHash(ro, rsForRevision)
+	if err != nil {
+		return err
+	}
+	if equal {
+		fmt.Fprintf(out, "skipped rollback (current template already matches revision %d)\n", targetRevision)
+		return nil
+	}
+
+	delete(rsForRevision.Spec.Template.Labels, v1alpha1.DefaultRolloutUniqueLabelKey)
+	patchType, patch, err := getRolloutPatch(&rsForRevision.Spec.Template, nil)
+	if err != nil {
+		return fmt.Errorf("failed restoring revision %d: %v", targetRevision, err)
+	}
+	rollout := routils.ObjectToRollout(ro)
+	if rollout == nil {
+		return fmt.Errorf("Invalid rollout object")
+	}
+
+	// the diff is computed from a server-side dry run, so that it reflects defaulting and admission webhooks
+	dryRunOpts := metav1.PatchOptions{DryRun: []string{metav1.DryRunAll}}
+	var current, target *corev1.PodTemplateSpec
+	if rollout.Spec.WorkloadRef != nil {
+		if current, err = workloadRefTemplate(c, rollout); err != nil {
+			return err
+		}
+		if target, err = patchWorkloadRef(c, rollout, patchType, patch, dryRunOpts); err != nil {
+			return fmt.Errorf("failed to dry run rollback to revision %d: %v", targetRevision, err)
+		}
+	} else {
+		if current, err = rolloutTemplate(ro); err != nil {
+			return err
+		}
+		patched, err := rolloutIf.Patch(ctx, name, patchType, patch, dryRunOpts)
+		if err != nil {
+			return fmt.Errorf("failed to dry run rollback to revision %d: %v", targetRevision, err)
+		}
+		if target, err = rolloutTemplate(patched); err != nil {
+			return err
+		}
+	}
+
+	currentRevision := ro.GetAnnotations()[revisionAnnotation]
+	diff, err := templateDiff(current, target, fmt.Sprintf("%s (revision %s)", name, currentRevision), fmt.Sprintf("%s (revision %d)", name, targetRevision))
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(out, diff)
+	if dryRun {
+		return nil
+	}
+
+	if rollout.Spec.WorkloadRef != nil {
+		err = undoWorkloadRef(c, rollout, patchType, patch)
+	} else {
+		_, err = rolloutIf.Patch(ctx, name, patchType, patch, metav1.PatchOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed restoring revision %d: %v", targetRevision, err)
+	}
+	fmt.Fprintf(out, "rollout '%s' rolled back to revision %d\n", name, targetRevision)
+	return nil
+}
+
+func rolloutTemplate(ro *unstructured.Unstructured) (*corev1.PodTemplateSpec, error) {
+	obj, _, err := unstructured.NestedMap(ro.Object, "spec", "template")
+	if err != nil {
+		return nil, err
+	}
+	var template corev1.PodTemplateSpec
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, &template); err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// templateDiff returns the unified diff of the YAML of two pod templates
+func templateDiff(from, to *corev1.PodTemplateSpec, fromName, toName string) (string, error) {
+	fromYAML, err := yaml.Marshal(from)
+	if err != nil {
+		return "", err
+	}
+	toYAML, err := yaml.Marshal(to)
+	if err != nil {
+		return "", err
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(fromYAML)),
+		B:        difflib.SplitLines(string(toYAML)),
+		FromFile: fromName,
+		ToFile:   toName,
+		Context:  3,
+	})
+}
@@ -0,0 +1,154 @@
+package undo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubetesting "k8s.io/client-go/testing"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/info/testdata"
+	options "github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options/fake"
+)
+
+// prependRolloutPatchReactor applies the pod template of patches to the rollout, except for the first one which is
+// the dry run, and returns the number of the dry runs and the patches. The fake dynamic client drops the patch
+// options, so that the dry run can only be told apart by its order.
+func prependRolloutPatchReactor(fakeClient *dynamicfake.FakeDynamicClient, ro *v1alpha1.Rollout) (*int, *int) {
+	dryRuns, patches := 0, 0
+	fakeClient.PrependReactor("patch", "*", func(action kubetesting.Action) (handled bool, ret runtime.Object, err error) {
+		patchAction := action.(kubetesting.PatchAction)
+		type patch struct {
+			Value corev1.PodTemplateSpec `json:"value"`
+		}
+		patchRo := []patch{}
+		if err := json.Unmarshal(patchAction.GetPatch(), &patchRo); err != nil {
+			panic(err)
+		}
+		patched := ro.DeepCopy()
+		patched.Spec.Template = patchRo[0].Value
+		if dryRuns == 0 {
+			dryRuns++
+		} else {
+			patches++
+			ro.Spec.Template = patchRo[0].Value
+		}
+		return true, patched, nil
+	})
+	return &dryRuns, &patches
+}
+
+func TestRollbackCmdUsage(t *testing.T) {
+	tf, o := options.NewFakeArgoRolloutsOptions()
+	defer tf.Cleanup()
+	cmd := NewCmdRollback(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{})
+	err := cmd.Execute()
+	assert.Error(t, err)
+	stdout := o.Out.(*bytes.Buffer).String()
+	stderr := o.ErrOut.(*bytes.Buffer).String()
+	assert.Empty(t, stdout)
+	assert.Contains(t, stderr, "Usage:")
+	assert.Contains(t, stderr, "rollback ROLLOUT")
+}
+
+func TestRollbackCmd(t *testing.T) {
+	rolloutObjs := testdata.NewCanaryRollout()
+	ro := rolloutObjs.Rollouts[0]
+	tf, o := options.NewFakeArgoRolloutsOptions(rolloutObjs.AllObjects()...)
+	o.RESTClientGetter = tf.WithNamespace(ro.Namespace)
+	defer tf.Cleanup()
+	dryRuns, patches := prependRolloutPatchReactor(o.DynamicClient.(*dynamicfake.FakeDynamicClient), ro)
+
+	cmd := NewCmdRollback(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{ro.Name, "--to-revision=29"})
+
+	err := cmd.Execute()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, *dryRuns)
+	assert.Equal(t, 1, *patches)
+	assert.Equal(t, "argoproj/rollouts-demo:asdf", ro.Spec.Template.Spec.Containers[0].Image)
+	stdout := o.Out.(*bytes.Buffer).String()
+	stderr := o.ErrOut.(*bytes.Buffer).String()
+	assert.Contains(t, stdout, "--- canary-demo (revision 31)\n+++ canary-demo (revision 29)\n")
+	assert.Contains(t, stdout, "+  - image: argoproj/rollouts-demo:asdf\n")
+	assert.Contains(t, stdout, "rollout 'canary-demo' rolled back to revision 29\n")
+	assert.Empty(t, stderr)
+}
+
+func TestRollbackCmdDryRun(t *testing.T) {
+	rolloutObjs := testdata.NewCanaryRollout()
+	ro := rolloutObjs.Rollouts[0]
+	image := ro.Spec.Template.Spec.Containers[0].Image
+	tf, o := options.NewFakeArgoRolloutsOptions(rolloutObjs.AllObjects()...)
+	o.RESTClientGetter = tf.WithNamespace(ro.Namespace)
+	defer tf.Cleanup()
+	dryRuns, patches := prependRolloutPatchReactor(o.DynamicClient.(*dynamicfake.FakeDynamicClient), ro)
+
+	cmd := NewCmdRollback(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{ro.Name, "--to-revision=29", "--dry-run"})
+
+	err := cmd.Execute()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, *dryRuns)
+	assert.Equal(t, 0, *patches)
+	assert.Equal(t, image, ro.Spec.Template.Spec.Containers[0].Image)
+	stdout := o.Out.(*bytes.Buffer).String()
+	assert.Contains(t, stdout, "+  - image: argoproj/rollouts-demo:asdf\n")
+	assert.NotContains(t, stdout, "rolled back")
+}
+
+func TestRollbackCmdWorkloadRef(t *testing.T) {
+	rolloutObjs := testdata.NewCanaryRollout()
+	ro := rolloutObjs.Rollouts[2]
+	ro.Spec.Template = corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+	}
+	tf, o := options.NewFakeArgoRolloutsOptions(rolloutObjs.AllObjects()...)
+	o.RESTClientGetter = tf.WithNamespace(ro.Namespace)
+	defer tf.Cleanup()
+
+	cmd := NewCmdRollback(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{ro.Name, "--to-revision=29"})
+
+	err := cmd.Execute()
+	assert.Nil(t, err)
+	deploy, _ := o.KubeClient.AppsV1().Deployments(ro.Namespace).Get(context.TODO(), "canary-demo-deploy", metav1.GetOptions{})
+	assert.Equal(t, "argoproj/rollouts-demo:asdf", deploy.Spec.Template.Spec.Containers[0].Image)
+	stdout := o.Out.(*bytes.Buffer).String()
+	assert.Contains(t, stdout, "+  - image: argoproj/rollouts-demo:asdf\n")
+	assert.Contains(t, stdout, "rolled back to revision 29\n")
+}
+
+func TestRollbackCmdSkipCurrent(t *testing.T) {
+	rolloutObjs := testdata.NewCanaryRollout()
+	ro := rolloutObjs.Rollouts[0]
+	tf, o := options.NewFakeArgoRolloutsOptions(rolloutObjs.AllObjects()...)
+	o.RESTClientGetter = tf.WithNamespace(ro.Namespace)
+	defer tf.Cleanup()
+	dryRuns, patches := prependRolloutPatchReactor(o.DynamicClient.(*dynamicfake.FakeDynamicClient), ro)
+
+	cmd := NewCmdRollback(o)
+	cmd.PersistentPreRunE = o.PersistentPreRunE
+	cmd.SetArgs([]string{ro.Name, "--to-revision=31"})
+
+	err := cmd.Execute()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, *dryRuns)
+	assert.Equal(t, 0, *patches)
+	stdout := o.Out.(*bytes.Buffer).String()
+	assert.Equal(t, "skipped rollback (current template already matches revision 31)\n", stdout)
+}
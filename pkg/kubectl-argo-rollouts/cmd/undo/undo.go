@@ -65,6 +65,7 @@ func NewCmdUndo(o *options.ArgoRolloutsOptions) *cobra.Command {
 		ValidArgsFunction: completionutil.RolloutNameCompletionFunc(o),
 	}
 	cmd.Flags().Int64Var(&toRevision, "to-revision", toRevision, "The revision to rollback to. Default to 0 (last revision).")
+	_ = cmd.RegisterFlagCompletionFunc("to-revision", completionutil.RevisionCompletionFunc(o))
 	return cmd
 }
 
@@ -116,22 +117,66 @@ func RunUndoRollout(rolloutIf dynamic.ResourceInterface, c kubernetes.Interface,
 }
 
 func undoWorkloadRef(c kubernetes.Interface, rollout *v1alpha1.Rollout, patchType types.PatchType, patch []byte) error {
-	var err error
+	_, err := patchWorkloadRef(c, rollout, patchType, patch, metav1.PatchOptions{})
+	return err
+}
 
+// patchWorkloadRef patches the workload referenced by the rollout and returns its resulting pod template
+func patchWorkloadRef(c kubernetes.Interface, rollout *v1alpha1.Rollout, patchType types.PatchType, patch []byte, opts metav1.PatchOptions) (*corev1.PodTemplateSpec, error) {
 	refName := rollout.Spec.WorkloadRef.Name
 	namespace := rollout.GetNamespace()
 
 	switch rollout.Spec.WorkloadRef.Kind {
 	case "Deployment":
-		_, err = c.AppsV1().Deployments(namespace).Patch(context.TODO(), refName, patchType, patch, metav1.PatchOptions{})
+		deploy, err := c.AppsV1().Deployments(namespace).Patch(context.TODO(), refName, patchType, patch, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &deploy.Spec.Template, nil
 	case "ReplicaSet":
-		_, err = c.AppsV1().ReplicaSets(namespace).Patch(context.TODO(), refName, patchType, patch, metav1.PatchOptions{})
+		rs, err := c.AppsV1().ReplicaSets(namespace).Patch(context.TODO(), refName, patchType, patch, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &rs.Spec.Template, nil
 	case "PodTemplate":
-		_, err = c.CoreV1().PodTemplates(namespace).Patch(context.TODO(), refName, patchType, patch, metav1.PatchOptions{})
+		podTemplate, err := c.CoreV1().PodTemplates(namespace).Patch(context.TODO(), refName, patchType, patch, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &podTemplate.Template, nil
 	default:
-		return fmt.Errorf("workload of type %s is not supported", rollout.Spec.WorkloadRef.Kind)
+		return nil, fmt.Errorf("workload of type %s is not supported", rollout.Spec.WorkloadRef.Kind)
+	}
+}
+
+// workloadRefTemplate returns the pod template of the workload referenced by the rollout
+func workloadRefTemplate(c kubernetes.Interface, rollout *v1alpha1.Rollout) (*corev1.PodTemplateSpec, error) {
+	refName := rollout.Spec.WorkloadRef.Name
+	namespace := rollout.GetNamespace()
+
+	switch rollout.Spec.WorkloadRef.Kind {
+	case "Deployment":
+		deploy, err := c.AppsV1().Deployments(namespace).Get(context.TODO(), refName, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &deploy.Spec.Template, nil
+	case "ReplicaSet":
+		rs, err := c.AppsV1().ReplicaSets(namespace).Get(context.TODO(), refName, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &rs.Spec.Template, nil
+	case "PodTemplate":
+		podTemplate, err := c.CoreV1().PodTemplates(namespace).Get(context.TODO(), refName, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &podTemplate.Template, nil
+	default:
+		return nil, fmt.Errorf("workload of type %s is not supported", rollout.Spec.WorkloadRef.Kind)
 	}
-	return err
 }
 
 func rolloutRevision(ro *unstructured.Unstructured, c kubernetes.Interface, toRevision int64) (*appsv1.ReplicaSet, error) {
@@ -24,6 +24,7 @@ func getAnalysisRunInfo(ownerUID types.UID, allAnalysisRuns []*v1alpha1.Analysis
 				Namespace:         run.Namespace,
 				CreationTimestamp: run.CreationTimestamp,
 				UID:               run.UID,
+				Labels:            run.Labels,
 			},
 		}
 
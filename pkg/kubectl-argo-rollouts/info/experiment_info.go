@@ -31,6 +31,11 @@ func NewExperimentInfo(
 		Message: exp.Status.Message,
 	}
 	expInfo.Icon = analysisIcon(exp.Status.Phase)
+	if exp.Spec.Paused && !exp.Status.Phase.Completed() {
+		// a paused experiment keeps running, but does not complete until it is resumed
+		expInfo.Icon = IconPaused
+		expInfo.Message = "Paused"
+	}
 	expInfo.Revision = int64(parseRevision(exp.ObjectMeta.Annotations))
 	expInfo.ReplicaSets = GetReplicaSetInfo(exp.UID, nil, allReplicaSets, allPods)
 	expInfo.AnalysisRuns = getAnalysisRunInfo(exp.UID, allAnalysisRuns)
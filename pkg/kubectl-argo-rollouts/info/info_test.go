@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/info/testdata"
@@ -38,6 +39,34 @@ func TestCanaryRolloutInfo(t *testing.T) {
 	})
 }
 
+func TestCanaryRolloutInfoWeightHistory(t *testing.T) {
+	rolloutObjs := testdata.NewCanaryRollout()
+	ro := rolloutObjs.Rollouts[0].DeepCopy()
+	ro.Status.Canary.WeightHistory = []v1alpha1.TrafficWeightRecord{
+		{Router: "ALB", DesiredWeight: 10},
+		{Router: "ALB", DesiredWeight: 20},
+	}
+	roInfo := NewRolloutInfo(ro, nil, nil, nil, nil, nil)
+	assert.Len(t, roInfo.WeightHistory, 2)
+	assert.Equal(t, int32(20), roInfo.WeightHistory[1].DesiredWeight)
+}
+
+func TestCanaryRolloutInfoStepProgress(t *testing.T) {
+	rolloutObjs := testdata.NewCanaryRollout()
+	ro := rolloutObjs.Rollouts[0].DeepCopy()
+	ro.Status.CurrentStepIndex = ptr.To[int32](1)
+	ro.Status.Canary.StepPluginStatuses = []v1alpha1.StepPluginStatus{
+		{Index: 0, Operation: v1alpha1.StepPluginOperationRun, Phase: v1alpha1.StepPluginPhaseSuccessful, Progress: &v1alpha1.StepPluginProgress{Percent: 100}},
+		{Index: 1, Operation: v1alpha1.StepPluginOperationRun, Phase: v1alpha1.StepPluginPhaseRunning, Progress: &v1alpha1.StepPluginProgress{Percent: 40, Stage: "migrating table orders"}},
+	}
+	roInfo := NewRolloutInfo(ro, nil, nil, nil, nil, nil)
+	assert.Equal(t, "40% migrating table orders", roInfo.StepProgress)
+
+	ro.Status.Canary.StepPluginStatuses[1].Phase = v1alpha1.StepPluginPhaseSuccessful
+	roInfo = NewRolloutInfo(ro, nil, nil, nil, nil, nil)
+	assert.Empty(t, roInfo.StepProgress)
+}
+
 func TestCanaryRolloutInfoWeights(t *testing.T) {
 	rolloutObjs := testdata.NewCanaryRollout()
 
@@ -52,6 +52,10 @@ func NewRolloutInfo(
 				steps = append(steps, &ro.Spec.Strategy.Canary.Steps[i])
 			}
 			roInfo.Steps = steps
+			roInfo.StepProgress = stepProgress(ro)
+		}
+		for i := range ro.Status.Canary.WeightHistory {
+			roInfo.WeightHistory = append(roInfo.WeightHistory, &ro.Status.Canary.WeightHistory[i])
 		}
 		// NOTE that this is desired weight, not the actual current weight
 		roInfo.SetWeight = strconv.Itoa(int(replicasetutil.GetCurrentSetWeight(ro)))
@@ -266,3 +270,21 @@ func AnalysisRunsByRevision(r *rollout.RolloutInfo, rev int) []*rollout.Analysis
 	}
 	return runs
 }
+
+// stepProgress formats the progress reported by the step plugin of the current step while it runs
+func stepProgress(ro *v1alpha1.Rollout) string {
+	for _, status := range ro.Status.Canary.StepPluginStatuses {
+		if status.Index != *ro.Status.CurrentStepIndex || status.Operation != v1alpha1.StepPluginOperationRun {
+			continue
+		}
+		if status.Phase != v1alpha1.StepPluginPhaseRunning || status.Progress == nil {
+			return ""
+		}
+		progress := fmt.Sprintf("%d%%", status.Progress.Percent)
+		if status.Progress.Stage != "" {
+			progress += " " + status.Progress.Stage
+		}
+		return progress
+	}
+	return ""
+}
@@ -0,0 +1,240 @@
+package info
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apiclient/rollout"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	rolloututil "github.com/argoproj/argo-rollouts/utils/rollout"
+)
+
+const (
+	StepStatusCompleted = "Completed"
+	StepStatusRunning   = "Running"
+	StepStatusPending   = "Pending"
+)
+
+// RolloutTimeline is the machine-readable representation of a rollout and of the progress of its latest revision
+// through its steps
+type RolloutTimeline struct {
+	Name         string              `json:"name"`
+	Namespace    string              `json:"namespace"`
+	Status       string              `json:"status"`
+	Message      string              `json:"message,omitempty"`
+	Strategy     string              `json:"strategy"`
+	Revision     int64               `json:"revision"`
+	StartedAt    *v1.Time            `json:"startedAt,omitempty"`
+	CurrentStep  *int32              `json:"currentStep,omitempty"`
+	SetWeight    string              `json:"setWeight,omitempty"`
+	ActualWeight string              `json:"actualWeight,omitempty"`
+	Containers   []TimelineContainer `json:"containers,omitempty"`
+	Replicas     TimelineReplicas    `json:"replicas"`
+	Steps        []TimelineStep      `json:"steps,omitempty"`
+	// AnalysisRuns are the analysis runs of the revision which were not started by a step, e.g. background analysis
+	AnalysisRuns []TimelineAnalysisRun `json:"analysisRuns,omitempty"`
+	Weights      []TimelineWeight      `json:"weights,omitempty"`
+}
+
+type TimelineContainer struct {
+	Name  string `json:"name"`
+	Image string `json:"image"`
+}
+
+type TimelineReplicas struct {
+	Desired   int32 `json:"desired"`
+	Current   int32 `json:"current"`
+	Updated   int32 `json:"updated"`
+	Ready     int32 `json:"ready"`
+	Available int32 `json:"available"`
+}
+
+// TimelineStep is a canary step of the revision. The timestamps are derived from the events of the rollout, and are
+// missing once the events expired.
+type TimelineStep struct {
+	Index        int32                 `json:"index"`
+	Step         string                `json:"step"`
+	Status       string                `json:"status"`
+	StartedAt    *v1.Time              `json:"startedAt,omitempty"`
+	FinishedAt   *v1.Time              `json:"finishedAt,omitempty"`
+	AnalysisRuns []TimelineAnalysisRun `json:"analysisRuns,omitempty"`
+}
+
+type TimelineAnalysisRun struct {
+	Name       string   `json:"name"`
+	Type       string   `json:"type,omitempty"`
+	Phase      string   `json:"phase"`
+	Message    string   `json:"message,omitempty"`
+	StartedAt  *v1.Time `json:"startedAt,omitempty"`
+	FinishedAt *v1.Time `json:"finishedAt,omitempty"`
+}
+
+// TimelineWeight is a change of the canary traffic weight
+type TimelineWeight struct {
+	Time   v1.Time `json:"time"`
+	Weight int32   `json:"weight"`
+}
+
+// NewRolloutTimeline returns the timeline of the latest revision of a rollout from its info and its events
+func NewRolloutTimeline(roInfo *rollout.RolloutInfo, events []corev1.Event) *RolloutTimeline {
+	timeline := RolloutTimeline{
+		Name:         roInfo.ObjectMeta.Name,
+		Namespace:    roInfo.ObjectMeta.Namespace,
+		Status:       roInfo.Status,
+		Message:      roInfo.Message,
+		Strategy:     roInfo.Strategy,
+		SetWeight:    roInfo.SetWeight,
+		ActualWeight: roInfo.ActualWeight,
+		Replicas: TimelineReplicas{
+			Desired:   roInfo.Desired,
+			Current:   roInfo.Current,
+			Updated:   roInfo.Updated,
+			Ready:     roInfo.Ready,
+			Available: roInfo.Available,
+		},
+	}
+	for _, c := range roInfo.Containers {
+		timeline.Containers = append(timeline.Containers, TimelineContainer{Name: c.Name, Image: c.Image})
+	}
+	for _, rs := range roInfo.ReplicaSets {
+		if rs.Revision > timeline.Revision {
+			timeline.Revision = rs.Revision
+			created := rs.ObjectMeta.CreationTimestamp
+			timeline.StartedAt = &created
+		}
+	}
+
+	// events are aggregated by message, so that only their last occurrence since the revision started is relevant
+	stepFinishedAt := map[int32]*v1.Time{}
+	for i := range events {
+		event := &events[i]
+		eventTime := lastEventTime(event)
+		if eventTime == nil || (timeline.StartedAt != nil && eventTime.Before(timeline.StartedAt)) {
+			continue
+		}
+		switch event.Reason {
+		case conditions.RolloutStepCompletedReason:
+			// the message holds the 1-based number of the completed step
+			var number, count int32
+			if _, err := fmt.Sscanf(event.Message, "Rollout step %d/%d completed", &number, &count); err == nil {
+				stepFinishedAt[number-1] = eventTime
+			}
+		case conditions.TrafficWeightUpdatedReason:
+			if weight, ok := parseTrafficWeight(event.Message); ok {
+				timeline.Weights = append(timeline.Weights, TimelineWeight{Time: *eventTime, Weight: weight})
+			}
+		}
+	}
+	sort.SliceStable(timeline.Weights, func(i, j int) bool {
+		return timeline.Weights[i].Time.Before(&timeline.Weights[j].Time)
+	})
+
+	stepAnalysisRuns := map[int32][]TimelineAnalysisRun{}
+	for _, ar := range roInfo.AnalysisRuns {
+		if ar.Revision != timeline.Revision {
+			continue
+		}
+		arType := ar.ObjectMeta.Labels[v1alpha1.RolloutTypeLabel]
+		run := newTimelineAnalysisRun(ar, arType)
+		if arType == v1alpha1.RolloutTypeStepLabel {
+			if index, err := strconv.ParseInt(ar.ObjectMeta.Labels[v1alpha1.RolloutCanaryStepIndexLabel], 10, 32); err == nil {
+				stepAnalysisRuns[int32(index)] = append(stepAnalysisRuns[int32(index)], run)
+				continue
+			}
+		}
+		timeline.AnalysisRuns = append(timeline.AnalysisRuns, run)
+	}
+
+	if len(roInfo.Steps) > 0 {
+		current, _, _ := strings.Cut(roInfo.Step, "/")
+		if index, err := strconv.ParseInt(current, 10, 32); err == nil {
+			timeline.CurrentStep = ptr.To(int32(index))
+		}
+	}
+	for i, step := range roInfo.Steps {
+		index := int32(i)
+		timelineStep := TimelineStep{
+			Index:        index,
+			Step:         rolloututil.CanaryStepString(*step),
+			Status:       StepStatusPending,
+			FinishedAt:   stepFinishedAt[index],
+			AnalysisRuns: stepAnalysisRuns[index],
+		}
+		if index == 0 {
+			timelineStep.StartedAt = timeline.StartedAt
+		} else {
+			timelineStep.StartedAt = stepFinishedAt[index-1]
+		}
+		if timeline.CurrentStep != nil {
+			switch {
+			case index < *timeline.CurrentStep:
+				timelineStep.Status = StepStatusCompleted
+			case index == *timeline.CurrentStep:
+				timelineStep.Status = StepStatusRunning
+			}
+		}
+		if timelineStep.Status == StepStatusPending {
+			timelineStep.StartedAt = nil
+		}
+		timeline.Steps = append(timeline.Steps, timelineStep)
+	}
+	return &timeline
+}
+
+func newTimelineAnalysisRun(ar *rollout.AnalysisRunInfo, arType string) TimelineAnalysisRun {
+	run := TimelineAnalysisRun{
+		Name:  ar.ObjectMeta.Name,
+		Type:  arType,
+		Phase: ar.Status,
+	}
+	if ar.SpecAndStatus == nil || ar.SpecAndStatus.Status == nil {
+		return run
+	}
+	status := ar.SpecAndStatus.Status
+	run.Message = status.Message
+	run.StartedAt = status.StartedAt
+	if status.Phase.Completed() {
+		for _, metric := range status.MetricResults {
+			for _, measurement := range metric.Measurements {
+				if measurement.FinishedAt != nil && (run.FinishedAt == nil || run.FinishedAt.Before(measurement.FinishedAt)) {
+					run.FinishedAt = measurement.FinishedAt
+				}
+			}
+		}
+	}
+	return run
+}
+
+func lastEventTime(event *corev1.Event) *v1.Time {
+	if event.Series != nil && !event.Series.LastObservedTime.IsZero() {
+		return &v1.Time{Time: event.Series.LastObservedTime.Time}
+	}
+	if !event.LastTimestamp.IsZero() {
+		return &event.LastTimestamp
+	}
+	if !event.EventTime.IsZero() {
+		return &v1.Time{Time: event.EventTime.Time}
+	}
+	return nil
+}
+
+// parseTrafficWeight returns the canary weight of a TrafficWeightUpdated event message, e.g. "Traffic weight updated
+// from 10 to 20"
+func parseTrafficWeight(message string) (int32, bool) {
+	_, details, ok := strings.Cut(message, "to ")
+	if !ok {
+		return 0, false
+	}
+	var weight int32
+	if _, err := fmt.Sscanf(details, "%d", &weight); err != nil {
+		return 0, false
+	}
+	return weight, true
+}
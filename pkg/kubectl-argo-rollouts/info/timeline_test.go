@@ -0,0 +1,115 @@
+package info
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apiclient/rollout"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+func TestNewRolloutTimeline(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) metav1.Time {
+		return metav1.NewTime(start.Add(time.Duration(minutes) * time.Minute))
+	}
+	roInfo := &rollout.RolloutInfo{
+		ObjectMeta: &metav1.ObjectMeta{Name: "guestbook", Namespace: "default"},
+		Status:     "Paused",
+		Strategy:   "Canary",
+		Step:       "2/4",
+		SetWeight:  "50",
+		Desired:    4,
+		Containers: []*rollout.ContainerInfo{{Name: "app", Image: "app:v2"}},
+		ReplicaSets: []*rollout.ReplicaSetInfo{
+			{ObjectMeta: &metav1.ObjectMeta{Name: "guestbook-old", CreationTimestamp: at(-60)}, Revision: 1},
+			{ObjectMeta: &metav1.ObjectMeta{Name: "guestbook-new", CreationTimestamp: at(0)}, Revision: 2},
+		},
+		Steps: []*v1alpha1.CanaryStep{
+			{SetWeight: ptr.To[int32](20)},
+			{Analysis: &v1alpha1.RolloutAnalysis{}},
+			{SetWeight: ptr.To[int32](50)},
+			{Pause: &v1alpha1.RolloutPause{}},
+		},
+		AnalysisRuns: []*rollout.AnalysisRunInfo{
+			{
+				ObjectMeta: &metav1.ObjectMeta{Name: "guestbook-old-1", Labels: map[string]string{
+					v1alpha1.RolloutTypeLabel:            v1alpha1.RolloutTypeStepLabel,
+					v1alpha1.RolloutCanaryStepIndexLabel: "1",
+				}},
+				Revision: 1,
+				Status:   "Failed",
+			},
+			{
+				ObjectMeta: &metav1.ObjectMeta{Name: "guestbook-new-1", Labels: map[string]string{
+					v1alpha1.RolloutTypeLabel:            v1alpha1.RolloutTypeStepLabel,
+					v1alpha1.RolloutCanaryStepIndexLabel: "1",
+				}},
+				Revision: 2,
+				Status:   "Successful",
+				SpecAndStatus: &rollout.AnalysisRunSpecAndStatus{
+					Status: &v1alpha1.AnalysisRunStatus{
+						Phase:     v1alpha1.AnalysisPhaseSuccessful,
+						StartedAt: ptr.To(at(1)),
+						MetricResults: []v1alpha1.MetricResult{{
+							Measurements: []v1alpha1.Measurement{
+								{FinishedAt: ptr.To(at(2))},
+								{FinishedAt: ptr.To(at(3))},
+							},
+						}},
+					},
+				},
+			},
+			{
+				ObjectMeta: &metav1.ObjectMeta{Name: "guestbook-new-background", Labels: map[string]string{
+					v1alpha1.RolloutTypeLabel: v1alpha1.RolloutTypeBackgroundRunLabel,
+				}},
+				Revision: 2,
+				Status:   "Running",
+			},
+		},
+	}
+	events := []corev1.Event{
+		{Reason: "RolloutStepCompleted", Message: "Rollout step 1/4 completed (setWeight: 20)", LastTimestamp: at(1)},
+		{Reason: "RolloutStepCompleted", Message: "Rollout step 2/4 completed (analysis: 1 templates)", LastTimestamp: at(3)},
+		// the completion of the third step of the previous revision
+		{Reason: "RolloutStepCompleted", Message: "Rollout step 3/4 completed (setWeight: 50)", LastTimestamp: at(-30)},
+		{Reason: "TrafficWeightUpdated", Message: "Traffic weight updated from 20 to 50", LastTimestamp: at(4)},
+		{Reason: "TrafficWeightUpdated", Message: "Traffic weight updated from 0 to 20", LastTimestamp: at(0)},
+		{Reason: "TrafficWeightUpdated", Message: "Traffic weight updated from 50 to 0", LastTimestamp: at(-40)},
+	}
+
+	timeline := NewRolloutTimeline(roInfo, events)
+	startedAt := at(0)
+	assert.Equal(t, int64(2), timeline.Revision)
+	assert.Equal(t, &startedAt, timeline.StartedAt)
+	assert.Equal(t, ptr.To[int32](2), timeline.CurrentStep)
+	assert.Equal(t, []TimelineContainer{{Name: "app", Image: "app:v2"}}, timeline.Containers)
+	assert.Equal(t, []TimelineWeight{{Time: at(0), Weight: 20}, {Time: at(4), Weight: 50}}, timeline.Weights)
+	assert.Equal(t, []TimelineAnalysisRun{{Name: "guestbook-new-background", Type: v1alpha1.RolloutTypeBackgroundRunLabel, Phase: "Running"}}, timeline.AnalysisRuns)
+
+	assert.Len(t, timeline.Steps, 4)
+	assert.Equal(t, TimelineStep{Index: 0, Step: "setWeight: 20", Status: StepStatusCompleted, StartedAt: ptr.To(at(0)), FinishedAt: ptr.To(at(1))}, timeline.Steps[0])
+	assert.Equal(t, StepStatusCompleted, timeline.Steps[1].Status)
+	assert.Equal(t, ptr.To(at(1)), timeline.Steps[1].StartedAt)
+	assert.Equal(t, ptr.To(at(3)), timeline.Steps[1].FinishedAt)
+	assert.Equal(t, []TimelineAnalysisRun{{Name: "guestbook-new-1", Type: v1alpha1.RolloutTypeStepLabel, Phase: "Successful", StartedAt: ptr.To(at(1)), FinishedAt: ptr.To(at(3))}}, timeline.Steps[1].AnalysisRuns)
+	assert.Equal(t, TimelineStep{Index: 2, Step: "setWeight: 50", Status: StepStatusRunning, StartedAt: ptr.To(at(3))}, timeline.Steps[2])
+	assert.Equal(t, TimelineStep{Index: 3, Step: "pause", Status: StepStatusPending}, timeline.Steps[3])
+}
+
+func TestParseTrafficWeight(t *testing.T) {
+	weight, ok := parseTrafficWeight("Traffic weight updated to 10")
+	assert.True(t, ok)
+	assert.Equal(t, int32(10), weight)
+	weight, ok = parseTrafficWeight("Traffic weight updated from 10 to 20, additional: []")
+	assert.True(t, ok)
+	assert.Equal(t, int32(20), weight)
+	_, ok = parseTrafficWeight("Traffic weight updated additional: []")
+	assert.False(t, ok)
+}
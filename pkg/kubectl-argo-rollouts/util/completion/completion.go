@@ -1,12 +1,17 @@
 package completion
 
 import (
+	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
+	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
+	rolloututil "github.com/argoproj/argo-rollouts/utils/rollout"
 )
 
 // RolloutNameCompletionFunc Returns a completion function that completes as a first argument
@@ -89,3 +94,105 @@ func AnalysisRunNameCompletionFunc(o *options.ArgoRolloutsOptions) func(*cobra.C
 		return arNames, cobra.ShellCompDirectiveNoFileComp
 	}
 }
+
+// RevisionCompletionFunc Returns a completion function that completes the revisions of the Rollout
+// given as first argument, the most recent first, along with their images.
+func RevisionCompletionFunc(o *options.ArgoRolloutsOptions) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 1 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		ctx := c.Context()
+		ro, err := o.RolloutsClientset().ArgoprojV1alpha1().Rollouts(o.Namespace()).Get(ctx, args[0], metav1.GetOptions{})
+		if err != nil {
+			return []string{}, cobra.ShellCompDirectiveError
+		}
+		rsList, err := o.KubeClientset().AppsV1().ReplicaSets(o.Namespace()).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return []string{}, cobra.ShellCompDirectiveError
+		}
+
+		revisions := map[int64]string{}
+		for i := range rsList.Items {
+			rs := &rsList.Items[i]
+			if ownerRef := metav1.GetControllerOf(rs); ownerRef == nil || ownerRef.UID != ro.UID {
+				continue
+			}
+			revision, err := replicasetutil.Revision(rs)
+			if err != nil || revision == 0 || !strings.HasPrefix(strconv.FormatInt(revision, 10), toComplete) {
+				continue
+			}
+			var images []string
+			for _, container := range rs.Spec.Template.Spec.Containers {
+				images = append(images, container.Image)
+			}
+			revisions[revision] = strings.Join(images, ",")
+		}
+		var sorted []int64
+		for revision := range revisions {
+			sorted = append(sorted, revision)
+		}
+		sort.Slice(sorted, func(i, j int) bool {
+			return sorted[i] > sorted[j]
+		})
+
+		var comps []string
+		for _, revision := range sorted {
+			comps = append(comps, fmt.Sprintf("%d\t%s", revision, revisions[revision]))
+		}
+		return comps, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+	}
+}
+
+// StepCompletionFunc Returns a completion function that completes the indexes of the canary steps
+// of the Rollout given as first argument, along with the steps.
+func StepCompletionFunc(o *options.ArgoRolloutsOptions) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 1 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		ro, err := o.RolloutsClientset().ArgoprojV1alpha1().Rollouts(o.Namespace()).Get(c.Context(), args[0], metav1.GetOptions{})
+		if err != nil {
+			return []string{}, cobra.ShellCompDirectiveError
+		}
+		if ro.Spec.Strategy.Canary == nil {
+			return []string{}, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		var comps []string
+		for i, step := range ro.Spec.Strategy.Canary.Steps {
+			index := strconv.Itoa(i)
+			if !strings.HasPrefix(index, toComplete) {
+				continue
+			}
+			description := rolloututil.CanaryStepString(step)
+			if ro.Status.CurrentStepIndex != nil && int(*ro.Status.CurrentStepIndex) == i {
+				description += " (current)"
+			}
+			comps = append(comps, fmt.Sprintf("%s\t%s", index, description))
+		}
+		return comps, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+	}
+}
+
+// MetricNameCompletionFunc Returns a completion function that completes the names of the metrics
+// of the AnalysisRun given as first argument.
+func MetricNameCompletionFunc(o *options.ArgoRolloutsOptions) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 1 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		ar, err := o.RolloutsClientset().ArgoprojV1alpha1().AnalysisRuns(o.Namespace()).Get(c.Context(), args[0], metav1.GetOptions{})
+		if err != nil {
+			return []string{}, cobra.ShellCompDirectiveError
+		}
+
+		var metricNames []string
+		for _, metric := range ar.Spec.Metrics {
+			if strings.HasPrefix(metric.Name, toComplete) {
+				metricNames = append(metricNames, metric.Name)
+			}
+		}
+		return metricNames, cobra.ShellCompDirectiveNoFileComp
+	}
+}
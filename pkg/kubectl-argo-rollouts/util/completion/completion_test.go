@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
 
 	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/info/testdata"
@@ -91,6 +92,55 @@ func TestAnalysisRunNameCompletionFuncOneArg(t *testing.T) {
 
 }
 
+func TestRevisionCompletionFunc(t *testing.T) {
+	rolloutObjs := testdata.NewCanaryRollout()
+	_, cmd, o := prepareCompletionTest(rolloutObjs)
+	compFunc := RevisionCompletionFunc(o)
+
+	comps, directive := compFunc(cmd, []string{"canary-demo"}, "")
+	assert.Equal(t, []string{"31\targoproj/rollouts-demo:does-not-exist", "30\targoproj/rollouts-demo:green", "29\targoproj/rollouts-demo:asdf"}, comps)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp|cobra.ShellCompDirectiveKeepOrder, directive)
+
+	comps, _ = compFunc(cmd, []string{"canary-demo"}, "3")
+	assert.Equal(t, []string{"31\targoproj/rollouts-demo:does-not-exist", "30\targoproj/rollouts-demo:green"}, comps)
+
+	comps, directive = compFunc(cmd, []string{}, "")
+	checkCompletion(t, comps, []string{}, directive, cobra.ShellCompDirectiveNoFileComp)
+
+	_, directive = compFunc(cmd, []string{"seagull"}, "")
+	assert.Equal(t, cobra.ShellCompDirectiveError, directive)
+}
+
+func TestStepCompletionFunc(t *testing.T) {
+	rolloutObjs := testdata.NewCanaryRollout()
+	_, cmd, o := prepareCompletionTest(rolloutObjs)
+	compFunc := StepCompletionFunc(o)
+
+	comps, directive := compFunc(cmd, []string{"canary-demo"}, "")
+	assert.Equal(t, []string{"0\tsetWeight: 20 (current)", "1\tpause", "2\tsetWeight: 40", "3\tpause: 10s", "4\tsetWeight: 60", "5\tpause: 10s", "6\tsetWeight: 80", "7\tpause: 10s"}, comps)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp|cobra.ShellCompDirectiveKeepOrder, directive)
+
+	comps, _ = compFunc(cmd, []string{"canary-demo"}, "2")
+	assert.Equal(t, []string{"2\tsetWeight: 40"}, comps)
+
+	bgObjs := testdata.NewBlueGreenRollout()
+	_, cmd, o = prepareCompletionTest(bgObjs)
+	comps, directive = StepCompletionFunc(o)(cmd, []string{bgObjs.Rollouts[0].Name}, "")
+	checkCompletion(t, comps, []string{}, directive, cobra.ShellCompDirectiveNoFileComp)
+}
+
+func TestMetricNameCompletionFunc(t *testing.T) {
+	rolloutObjs := testdata.NewExperimentAnalysisJobRollout()
+	_, cmd, o := prepareCompletionTest(rolloutObjs)
+	compFunc := MetricNameCompletionFunc(o)
+
+	comps, directive := compFunc(cmd, []string{"canary-demo-645d5dbc4c-2-0-stress-test"}, "")
+	checkCompletion(t, comps, []string{"stress"}, directive, cobra.ShellCompDirectiveNoFileComp)
+
+	comps, directive = compFunc(cmd, []string{"canary-demo-645d5dbc4c-2-0-stress-test"}, "seagull")
+	checkCompletion(t, comps, []string{}, directive, cobra.ShellCompDirectiveNoFileComp)
+}
+
 func prepareCompletionTest(r *testdata.RolloutObjects) (*cmdtesting.TestFactory, *cobra.Command, *options.ArgoRolloutsOptions) {
 	tf, o := fakeoptions.NewFakeArgoRolloutsOptions(r.AllObjects()...)
 	o.RESTClientGetter = tf.WithNamespace(r.Rollouts[0].Namespace)
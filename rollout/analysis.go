@@ -11,20 +11,26 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	patchtypes "k8s.io/apimachinery/pkg/types"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	analysisutil "github.com/argoproj/argo-rollouts/utils/analysis"
 	"github.com/argoproj/argo-rollouts/utils/annotations"
+	controllerutil "github.com/argoproj/argo-rollouts/utils/controller"
 	"github.com/argoproj/argo-rollouts/utils/defaults"
 	logutil "github.com/argoproj/argo-rollouts/utils/log"
 	"github.com/argoproj/argo-rollouts/utils/record"
 	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
 	rolloututil "github.com/argoproj/argo-rollouts/utils/rollout"
+	"github.com/argoproj/argo-rollouts/utils/sharding"
+	timeutil "github.com/argoproj/argo-rollouts/utils/time"
 )
 
 const (
+	// PostPromotionSmokeTestMetricName is the name of the metric added to the post-promotion AnalysisRun
+	// for the inline smoke test Job
+	PostPromotionSmokeTestMetricName = "smoke-test"
+
 	cancelAnalysisRun = `{
 		"spec": {
 			"terminate": true
@@ -35,26 +41,27 @@ const (
 // getAnalysisRunsForRollout get all analysisRuns owned by the Rollout
 func (c *Controller) getAnalysisRunsForRollout(rollout *v1alpha1.Rollout) ([]*v1alpha1.AnalysisRun, error) {
 	ctx := context.TODO()
-	analysisRuns, err := c.analysisRunLister.AnalysisRuns(rollout.Namespace).List(labels.Everything())
+	analysisRuns, err := controllerutil.ByOwner(c.analysisRunIndexer, rollout.Namespace, rollout.UID, false)
 	if err != nil {
 		return nil, err
 	}
 	ownedByRollout := make([]*v1alpha1.AnalysisRun, 0)
 	seen := make(map[string]bool)
 	for i := range analysisRuns {
-		e := analysisRuns[i]
-		controllerRef := metav1.GetControllerOf(e)
-		if controllerRef != nil && controllerRef.UID == rollout.UID {
-			ownedByRollout = append(ownedByRollout, e)
-			seen[e.Name] = true
-		}
+		e := analysisRuns[i].(*v1alpha1.AnalysisRun)
+		ownedByRollout = append(ownedByRollout, e)
+		seen[e.Name] = true
 	}
 	arStatuses := []*v1alpha1.RolloutAnalysisRunStatus{
 		rollout.Status.Canary.CurrentBackgroundAnalysisRunStatus,
+		rollout.Status.Canary.CurrentGuardrailAnalysisRunStatus,
 		rollout.Status.Canary.CurrentStepAnalysisRunStatus,
 		rollout.Status.BlueGreen.PrePromotionAnalysisRunStatus,
 		rollout.Status.BlueGreen.PostPromotionAnalysisRunStatus,
 	}
+	if rollout.Status.RestartStatus != nil {
+		arStatuses = append(arStatuses, rollout.Status.RestartStatus.CurrentAnalysisRunStatus)
+	}
 	for _, arStatus := range arStatuses {
 		if arStatus == nil || seen[arStatus.Name] {
 			continue
@@ -96,6 +103,11 @@ func (c *rolloutContext) reconcileAnalysisRuns() error {
 		}
 		newCurrentAnalysisRuns.CanaryBackground = backgroundAnalysisRun
 
+		guardrailAnalysisRun, err := c.reconcileGuardrailAnalysisRun()
+		if err != nil {
+			return err
+		}
+		newCurrentAnalysisRuns.CanaryGuardrail = guardrailAnalysisRun
 	}
 	if c.rollout.Spec.Strategy.BlueGreen != nil {
 		prePromotionAr, err := c.reconcilePrePromotionAnalysisRun()
@@ -110,8 +122,14 @@ func (c *rolloutContext) reconcileAnalysisRuns() error {
 			return err
 		}
 		c.setPauseOrAbortForBlueGreen(postPromotionAr, true)
+		c.checkPostPromotionSmokeTestWindow(postPromotionAr)
 		newCurrentAnalysisRuns.BlueGreenPostPromotion = postPromotionAr
 	}
+	restartAnalysisRun, err := c.reconcileRestartAnalysisRun()
+	if err != nil {
+		return err
+	}
+	newCurrentAnalysisRuns.Restart = restartAnalysisRun
 	c.SetCurrentAnalysisRuns(newCurrentAnalysisRuns)
 
 	// Due to the possibility that we are operating on stale/inconsistent data in the informer, it's
@@ -129,7 +147,7 @@ func (c *rolloutContext) reconcileAnalysisRuns() error {
 		return true
 	})
 
-	err := c.cancelAnalysisRuns(otherArs)
+	err = c.cancelAnalysisRuns(otherArs)
 	if err != nil {
 		return err
 	}
@@ -236,6 +254,12 @@ func (c *rolloutContext) reconcileAnalysisRunStatusChanges(currARs analysisutil.
 		currARs.CanaryBackground,
 		v1alpha1.RolloutTypeBackgroundRunLabel,
 	)
+
+	c.emitAnalysisRunStatusChanges(
+		c.rollout.Status.Canary.CurrentGuardrailAnalysisRunStatus,
+		currARs.CanaryGuardrail,
+		v1alpha1.RolloutTypeGuardrailLabel,
+	)
 }
 
 func (c *rolloutContext) reconcilePrePromotionAnalysisRun() (*v1alpha1.AnalysisRun, error) {
@@ -287,8 +311,8 @@ func skipPrePromotionAnalysisRun(rollout *v1alpha1.Rollout, newRS *appsv1.Replic
 	// Checking saturation is different if the previewReplicaCount feature is being used because
 	// annotations.IsSaturated() also looks at the desired annotation on the ReplicaSet, and the
 	// check using previewReplicaCount does not.
-	if rollout.Spec.Strategy.BlueGreen.PreviewReplicaCount != nil {
-		desiredPreviewCount := *rollout.Spec.Strategy.BlueGreen.PreviewReplicaCount
+	if previewReplicaCount := replicasetutil.GetPreviewReplicaCount(rollout); previewReplicaCount != nil {
+		desiredPreviewCount := *previewReplicaCount
 		return *(newRS.Spec.Replicas) != desiredPreviewCount || newRS.Status.AvailableReplicas != desiredPreviewCount
 	}
 	return !annotations.IsSaturated(rollout, newRS)
@@ -315,7 +339,9 @@ func skipPostPromotionAnalysisRun(rollout *v1alpha1.Rollout, newRS *appsv1.Repli
 
 func (c *rolloutContext) reconcilePostPromotionAnalysisRun() (*v1alpha1.AnalysisRun, error) {
 	currentAr := c.currentArs.BlueGreenPostPromotion
-	if c.rollout.Spec.Strategy.BlueGreen.PostPromotionAnalysis == nil || len(c.rollout.Spec.Strategy.BlueGreen.PostPromotionAnalysis.Templates) == 0 {
+	postPromotionAnalysis := getPostPromotionAnalysis(c.rollout.Spec.Strategy.BlueGreen)
+	smokeTestMetrics := getPostPromotionSmokeTestMetrics(c.rollout.Spec.Strategy.BlueGreen)
+	if postPromotionAnalysis == nil || len(postPromotionAnalysis.Templates) == 0 && len(smokeTestMetrics) == 0 {
 		err := c.cancelAnalysisRuns([]*v1alpha1.AnalysisRun{currentAr})
 		return nil, err
 	}
@@ -335,7 +361,7 @@ func (c *rolloutContext) reconcilePostPromotionAnalysisRun() (*v1alpha1.Analysis
 		podHash := replicasetutil.GetPodTemplateHash(c.newRS)
 		instanceID := analysisutil.GetInstanceID(c.rollout)
 		postPromotionLabels := analysisutil.PostPromotionLabels(podHash, instanceID)
-		currentAr, err := c.createAnalysisRun(c.rollout.Spec.Strategy.BlueGreen.PostPromotionAnalysis, "post", postPromotionLabels)
+		currentAr, err := c.createAnalysisRun(postPromotionAnalysis, "post", postPromotionLabels, smokeTestMetrics...)
 		if err == nil {
 			c.log.WithField(logutil.AnalysisRunKey, currentAr.Name).Info("Created Post Promotion AnalysisRun")
 		}
@@ -344,6 +370,53 @@ func (c *rolloutContext) reconcilePostPromotionAnalysisRun() (*v1alpha1.Analysis
 	return currentAr, nil
 }
 
+// getPostPromotionAnalysis returns the RolloutAnalysis of the post-promotion AnalysisRun, which combines
+// postPromotionAnalysis with the templates and args of postPromotionSmokeTest
+func getPostPromotionAnalysis(blueGreen *v1alpha1.BlueGreenStrategy) *v1alpha1.RolloutAnalysis {
+	smokeTest := blueGreen.PostPromotionSmokeTest
+	if smokeTest == nil {
+		return blueGreen.PostPromotionAnalysis
+	}
+	rolloutAnalysis := &v1alpha1.RolloutAnalysis{}
+	if blueGreen.PostPromotionAnalysis != nil {
+		rolloutAnalysis = blueGreen.PostPromotionAnalysis.DeepCopy()
+	}
+	rolloutAnalysis.Templates = append(rolloutAnalysis.Templates, smokeTest.Templates...)
+	rolloutAnalysis.Args = append(rolloutAnalysis.Args, smokeTest.Args...)
+	return rolloutAnalysis
+}
+
+// getPostPromotionSmokeTestMetrics returns the metrics added to the post-promotion AnalysisRun for the
+// inline smoke test Job
+func getPostPromotionSmokeTestMetrics(blueGreen *v1alpha1.BlueGreenStrategy) []v1alpha1.Metric {
+	smokeTest := blueGreen.PostPromotionSmokeTest
+	if smokeTest == nil || smokeTest.Job == nil {
+		return nil
+	}
+	return []v1alpha1.Metric{{
+		Name: PostPromotionSmokeTestMetricName,
+		Provider: v1alpha1.MetricProvider{
+			Job: smokeTest.Job.DeepCopy(),
+		},
+	}}
+}
+
+// checkPostPromotionSmokeTestWindow aborts the rollout if the post-promotion AnalysisRun did not succeed
+// within the smoke test window, and otherwise requeues the rollout at the end of the window
+func (c *rolloutContext) checkPostPromotionSmokeTestWindow(ar *v1alpha1.AnalysisRun) {
+	smokeTest := c.rollout.Spec.Strategy.BlueGreen.PostPromotionSmokeTest
+	if smokeTest == nil || ar == nil || ar.Status.Phase.Completed() {
+		return
+	}
+	window := defaults.GetPostPromotionSmokeTestWindowOrDefault(smokeTest)
+	remaining := ar.CreationTimestamp.Add(window).Sub(timeutil.Now())
+	if remaining > 0 {
+		c.enqueueRolloutAfter(c.rollout, remaining)
+		return
+	}
+	c.pauseContext.AddAbort(fmt.Sprintf("Blue/green post-promotion smoke test did not succeed within %s", window))
+}
+
 func (c *rolloutContext) reconcileBackgroundAnalysisRun() (*v1alpha1.AnalysisRun, error) {
 	currentAr := c.currentArs.CanaryBackground
 	if c.rollout.Spec.Strategy.Canary.Analysis == nil || len(c.rollout.Spec.Strategy.Canary.Analysis.Templates) == 0 {
@@ -383,7 +456,71 @@ func (c *rolloutContext) reconcileBackgroundAnalysisRun() (*v1alpha1.AnalysisRun
 	return currentAr, nil
 }
 
-func (c *rolloutContext) createAnalysisRun(rolloutAnalysis *v1alpha1.RolloutAnalysis, infix string, labels map[string]string) (*v1alpha1.AnalysisRun, error) {
+// reconcileGuardrailAnalysisRun runs the guardrail analysis for the entire update, regardless of the
+// current step, and pauses or aborts the rollout as soon as it fails
+func (c *rolloutContext) reconcileGuardrailAnalysisRun() (*v1alpha1.AnalysisRun, error) {
+	currentAr := c.currentArs.CanaryGuardrail
+	guardrail := c.rollout.Spec.Strategy.Canary.Guardrail
+	if guardrail == nil || len(guardrail.Templates) == 0 {
+		err := c.cancelAnalysisRuns([]*v1alpha1.AnalysisRun{currentAr})
+		return nil, err
+	}
+
+	// Do not create a guardrail run if the rollout is completely rolled out or just created
+	if rolloututil.IsFullyPromoted(c.rollout) || c.rollout.Status.StableRS == "" || c.rollout.Status.CurrentPodHash == "" {
+		return nil, nil
+	}
+
+	if getPauseCondition(c.rollout, v1alpha1.PauseReasonInconclusiveAnalysis) != nil {
+		return currentAr, nil
+	}
+	// The failed run is released while the rollout is paused, so that a new run is started once
+	// the rollout is promoted
+	if getPauseCondition(c.rollout, v1alpha1.PauseReasonGuardrailAnalysis) != nil {
+		return nil, nil
+	}
+
+	if needsNewAnalysisRun(currentAr, c.rollout) {
+		podHash := replicasetutil.GetPodTemplateHash(c.newRS)
+		instanceID := analysisutil.GetInstanceID(c.rollout)
+		guardrailLabels := analysisutil.GuardrailLabels(podHash, instanceID)
+		currentAr, err := c.createAnalysisRun(&guardrail.RolloutAnalysis, "guardrail", guardrailLabels)
+		if err == nil {
+			c.log.WithField(logutil.AnalysisRunKey, currentAr.Name).Info("Created guardrail AnalysisRun")
+		}
+		return currentAr, err
+	}
+	switch currentAr.Status.Phase {
+	case v1alpha1.AnalysisPhaseInconclusive:
+		c.pauseContext.AddPauseCondition(v1alpha1.PauseReasonInconclusiveAnalysis)
+	case v1alpha1.AnalysisPhaseError, v1alpha1.AnalysisPhaseFailed:
+		if guardrail.Action == v1alpha1.GuardrailActionPause {
+			c.pauseContext.AddPauseCondition(v1alpha1.PauseReasonGuardrailAnalysis)
+			return currentAr, nil
+		}
+		message := "Guardrail analysis phase error/failed"
+		if currentAr.Status.Message != "" {
+			message += ": " + currentAr.Status.Message
+		}
+		c.pauseContext.AddAbort(message)
+	}
+	return currentAr, nil
+}
+
+// reconcileRestartAnalysisRun retains the analysis run of the last restarted batch, which is created and
+// evaluated by the pod restarter, for as long as it is recorded in the restart status
+func (c *rolloutContext) reconcileRestartAnalysisRun() (*v1alpha1.AnalysisRun, error) {
+	currentAr := c.currentArs.Restart
+	if c.newStatus.RestartStatus == nil || c.newStatus.RestartStatus.CurrentAnalysisRunStatus == nil {
+		err := c.cancelAnalysisRuns([]*v1alpha1.AnalysisRun{currentAr})
+		return nil, err
+	}
+	return currentAr, nil
+}
+
+// createAnalysisRun creates an AnalysisRun from the templates of the RolloutAnalysis. Additional metrics
+// which are not defined by a template can be appended to the run.
+func (c *rolloutContext) createAnalysisRun(rolloutAnalysis *v1alpha1.RolloutAnalysis, infix string, labels map[string]string, metrics ...v1alpha1.Metric) (*v1alpha1.AnalysisRun, error) {
 	args, err := analysisutil.BuildArgumentsForRolloutAnalysisRun(rolloutAnalysis.Args, c.stableRS, c.newRS, c.rollout)
 	if err != nil {
 		return nil, err
@@ -397,6 +534,8 @@ func (c *rolloutContext) createAnalysisRun(rolloutAnalysis *v1alpha1.RolloutAnal
 	if err != nil {
 		return nil, err
 	}
+	ar.Spec.Metrics = append(ar.Spec.Metrics, metrics...)
+	sharding.PropagateShard(c.rollout, ar)
 	analysisRunIf := c.argoprojclientset.ArgoprojV1alpha1().AnalysisRuns(c.rollout.Namespace)
 	return analysisutil.CreateWithCollisionCounter(c.log, analysisRunIf, *ar)
 }
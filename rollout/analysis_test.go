@@ -12,6 +12,7 @@ import (
 	timeutil "github.com/argoproj/argo-rollouts/utils/time"
 
 	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -188,6 +189,9 @@ func analysisRun(at *v1alpha1.AnalysisTemplate, analysisRunType string, r *v1alp
 	} else if analysisRunType == v1alpha1.RolloutTypePostPromotionLabel {
 		labels = analysisutil.PostPromotionLabels(podHash, "")
 		name = fmt.Sprintf("%s-%s-%s-post", r.Name, podHash, "2")
+	} else if analysisRunType == v1alpha1.RolloutTypeGuardrailLabel {
+		labels = analysisutil.GuardrailLabels(podHash, "")
+		name = fmt.Sprintf("%s-%s-%s-guardrail", r.Name, podHash, "2")
 	}
 	return &v1alpha1.AnalysisRun{
 		ObjectMeta: metav1.ObjectMeta{
@@ -2063,7 +2067,8 @@ func TestDoNotCreatePrePromotionAnalysisAfterPromotionRollout(t *testing.T) {
 	newConditions := generateConditionsPatchWithHealthy(true, conditions.NewRSAvailableReason, rs2, true, "", true, true)
 	expectedPatch := fmt.Sprintf(`{
 		"status":{
-			"conditions":%s
+			"conditions":%s,
+			"health":{"percentComplete":100,"status":"Healthy"}
 		}
 	}`, newConditions)
 	patch := f.getPatchedRollout(patchIndex)
@@ -3164,3 +3169,218 @@ func TestSkipPostPromotionAnalysisRun(t *testing.T) {
 		assert.False(t, result, "Should not skip when currentAr is not nil, even if ReplicaSet becomes unsaturated")
 	})
 }
+
+func TestCreatePostPromotionSmokeTestAnalysisRun(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	r1 := newBlueGreenRollout("foo", 1, nil, "active", "")
+	r2 := bumpVersion(r1)
+	r2.Spec.Strategy.BlueGreen.PostPromotionSmokeTest = &v1alpha1.PostPromotionSmokeTest{
+		Job: &v1alpha1.JobMetric{
+			Spec: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "smoke", Image: "curl"}},
+					},
+				},
+			},
+		},
+	}
+	rs1 := newReplicaSetWithStatus(r1, 1, 1)
+	rs2 := newReplicaSetWithStatus(r2, 1, 1)
+	rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	rs2PodHash := rs2.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+
+	r2 = updateBlueGreenRolloutStatus(r2, "", rs2PodHash, rs1PodHash, 1, 1, 2, 1, false, true, false)
+
+	activeSelector := map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: rs2PodHash}
+	activeSvc := newService("active", 80, activeSelector, r2)
+
+	f.objects = append(f.objects, r2)
+	f.kubeobjects = append(f.kubeobjects, activeSvc, rs1, rs2)
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	f.serviceLister = append(f.serviceLister, activeSvc)
+
+	createdIndex := f.expectCreateAnalysisRunAction(&v1alpha1.AnalysisRun{})
+	f.expectPatchRolloutActionWithPatch(r2, OnlyObservedGenerationPatch)
+	f.run(getKey(r2, t))
+
+	createdAr := f.getCreatedAnalysisRun(createdIndex)
+	assert.Equal(t, v1alpha1.RolloutTypePostPromotionLabel, createdAr.Labels[v1alpha1.RolloutTypeLabel])
+	assert.Len(t, createdAr.Spec.Metrics, 1)
+	assert.Equal(t, PostPromotionSmokeTestMetricName, createdAr.Spec.Metrics[0].Name)
+	assert.Equal(t, "curl", createdAr.Spec.Metrics[0].Provider.Job.Spec.Template.Spec.Containers[0].Image)
+}
+
+func TestAbortRolloutOnPostPromotionSmokeTestWindow(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	at := analysisTemplate("bar")
+	r1 := newBlueGreenRollout("foo", 1, nil, "active", "")
+	r2 := bumpVersion(r1)
+	r2.Spec.Strategy.BlueGreen.PostPromotionSmokeTest = &v1alpha1.PostPromotionSmokeTest{
+		Templates: []v1alpha1.AnalysisTemplateRef{{
+			TemplateName: at.Name,
+		}},
+		WindowSeconds: ptr.To[int32](60),
+	}
+	ar := analysisRun(at, v1alpha1.RolloutTypePostPromotionLabel, r2)
+	ar.Status.Phase = v1alpha1.AnalysisPhaseRunning
+	ar.CreationTimestamp = metav1.NewTime(timeutil.Now().Add(-2 * time.Minute))
+	r2.Status.BlueGreen.PostPromotionAnalysisRunStatus = &v1alpha1.RolloutAnalysisRunStatus{
+		Name:   ar.Name,
+		Status: v1alpha1.AnalysisPhaseRunning,
+	}
+
+	rs1 := newReplicaSetWithStatus(r1, 1, 1)
+	rs2 := newReplicaSetWithStatus(r2, 1, 1)
+	rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	rs2PodHash := rs2.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+
+	r2 = updateBlueGreenRolloutStatus(r2, "", rs2PodHash, rs1PodHash, 1, 1, 2, 1, false, true, false)
+
+	activeSelector := map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: rs2PodHash}
+	activeSvc := newService("active", 80, activeSelector, r2)
+
+	f.objects = append(f.objects, r2, at, ar)
+	f.kubeobjects = append(f.kubeobjects, activeSvc, rs1, rs2)
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.analysisTemplateLister = append(f.analysisTemplateLister, at)
+	f.analysisRunLister = append(f.analysisRunLister, ar)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	f.serviceLister = append(f.serviceLister, activeSvc)
+
+	patchIndex := f.expectPatchRolloutActionWithPatch(r2, OnlyObservedGenerationPatch)
+	f.run(getKey(r2, t))
+	patch := f.getPatchedRolloutAsObject(patchIndex)
+	assert.True(t, patch.Status.Abort)
+	assert.Contains(t, patch.Status.Message, "Blue/green post-promotion smoke test did not succeed within 1m0s")
+}
+
+func TestCreateGuardrailAnalysisRun(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	steps := []v1alpha1.CanaryStep{{
+		SetWeight: int32Ptr(10),
+	}}
+	at := analysisTemplate("bar")
+	r1 := newCanaryRollout("foo", 10, nil, steps, ptr.To[int32](0), intstr.FromInt(0), intstr.FromInt(1))
+	r2 := bumpVersion(r1)
+	ar := analysisRun(at, v1alpha1.RolloutTypeGuardrailLabel, r2)
+	r2.Spec.Strategy.Canary.Guardrail = &v1alpha1.RolloutGuardrail{
+		RolloutAnalysis: v1alpha1.RolloutAnalysis{
+			Templates: []v1alpha1.AnalysisTemplateRef{{TemplateName: at.Name}},
+		},
+	}
+
+	rs1 := newReplicaSetWithStatus(r1, 10, 10)
+	rs2 := newReplicaSetWithStatus(r2, 0, 0)
+	f.kubeobjects = append(f.kubeobjects, rs1, rs2)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	rs2PodHash := rs2.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+
+	r2 = updateCanaryRolloutStatus(r2, rs1PodHash, 10, 0, 10, false)
+	progressingCondition, _ := newProgressingCondition(conditions.ReplicaSetUpdatedReason, rs2, "")
+	conditions.SetRolloutCondition(&r2.Status, progressingCondition)
+	availableCondition, _ := newAvailableCondition(true)
+	conditions.SetRolloutCondition(&r2.Status, availableCondition)
+	completeCond, _ := newCompletedCondition(false)
+	conditions.SetRolloutCondition(&r2.Status, completeCond)
+
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.analysisTemplateLister = append(f.analysisTemplateLister, at)
+	f.objects = append(f.objects, r2, at)
+
+	createdIndex := f.expectCreateAnalysisRunAction(ar)
+	f.expectUpdateReplicaSetAction(rs2)
+	index := f.expectPatchRolloutAction(r1)
+
+	f.run(getKey(r2, t))
+	createdAr := f.getCreatedAnalysisRun(createdIndex)
+	expectedArName := fmt.Sprintf("%s-%s-%s-%s", r2.Name, rs2PodHash, "2", "guardrail")
+	assert.Equal(t, expectedArName, createdAr.Name)
+	assert.Equal(t, v1alpha1.RolloutTypeGuardrailLabel, createdAr.Labels[v1alpha1.RolloutTypeLabel])
+
+	patch := f.getPatchedRollout(index)
+	expectedPatch := `{
+		"status": {
+			"canary": {
+				"currentGuardrailAnalysisRunStatus": {
+					"name": "%s",
+					"status": ""
+				}
+			}
+		}
+	}`
+	assert.JSONEq(t, calculatePatch(r2, fmt.Sprintf(expectedPatch, expectedArName)), patch)
+}
+
+func TestPausedOnFailedGuardrailAnalysisRun(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	at := analysisTemplate("bar")
+	steps := []v1alpha1.CanaryStep{
+		{SetWeight: ptr.To[int32](10)},
+		{SetWeight: ptr.To[int32](20)},
+	}
+
+	r1 := newCanaryRollout("foo", 1, nil, steps, ptr.To[int32](0), intstr.FromInt(1), intstr.FromInt(1))
+	r2 := bumpVersion(r1)
+	ar := analysisRun(at, v1alpha1.RolloutTypeGuardrailLabel, r2)
+	r2.Spec.Strategy.Canary.Guardrail = &v1alpha1.RolloutGuardrail{
+		RolloutAnalysis: v1alpha1.RolloutAnalysis{
+			Templates: []v1alpha1.AnalysisTemplateRef{{TemplateName: at.Name}},
+		},
+		Action: v1alpha1.GuardrailActionPause,
+	}
+	ar.Status = v1alpha1.AnalysisRunStatus{
+		Phase: v1alpha1.AnalysisPhaseFailed,
+	}
+
+	rs1 := newReplicaSetWithStatus(r1, 1, 1)
+	rs2 := newReplicaSetWithStatus(r2, 0, 0)
+	f.kubeobjects = append(f.kubeobjects, rs1, rs2)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+
+	r2 = updateCanaryRolloutStatus(r2, rs1PodHash, 1, 0, 1, false)
+	r2.Status.Canary.CurrentGuardrailAnalysisRunStatus = &v1alpha1.RolloutAnalysisRunStatus{
+		Name: ar.Name,
+	}
+
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.analysisTemplateLister = append(f.analysisTemplateLister, at)
+	f.analysisRunLister = append(f.analysisRunLister, ar)
+	f.objects = append(f.objects, r2, at, ar)
+
+	patchIndex := f.expectPatchRolloutAction(r2)
+	f.run(getKey(r2, t))
+	patch := f.getPatchedRollout(patchIndex)
+	now := timeutil.MetaNow().UTC().Format(time.RFC3339)
+	expectedPatch := `{
+		"status": {
+			"conditions": %s,
+			"canary": {
+				"currentGuardrailAnalysisRunStatus": {
+					"status": "Failed"
+				}
+			},
+			"pauseConditions": [{
+					"reason": "%s",
+					"startTime": "%s"
+			}],
+			"controllerPause": true,
+			"phase": "Paused",
+			"message": "%s"
+		}
+	}`
+	condition := generateConditionsPatch(true, conditions.ReplicaSetUpdatedReason, r2, false, "", false)
+
+	assert.JSONEq(t, calculatePatch(r2, fmt.Sprintf(expectedPatch, condition, v1alpha1.PauseReasonGuardrailAnalysis, now, v1alpha1.PauseReasonGuardrailAnalysis)), patch)
+}
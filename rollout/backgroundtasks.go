@@ -0,0 +1,70 @@
+package rollout
+
+import (
+	"strings"
+	"sync"
+)
+
+// backgroundTasks tracks calls of external services which run in the background, so that slow services do not block
+// the workers of the controller. A task is keyed by the key of its rollout followed by a slash and the key of the task
+// within the rollout. The reconciliation started once a task completed consumes its result.
+type backgroundTasks[T any] struct {
+	lock    sync.Mutex
+	entries map[string]*backgroundTask[T]
+}
+
+// backgroundTask is a running task, or a completed one whose result was not yet consumed
+type backgroundTask[T any] struct {
+	// done indicates that the task completed with the given value or error
+	done  bool
+	value T
+	err   error
+}
+
+func newBackgroundTasks[T any]() *backgroundTasks[T] {
+	return &backgroundTasks[T]{entries: map[string]*backgroundTask[T]{}}
+}
+
+// result returns and consumes the result of the completed task, if any
+func (b *backgroundTasks[T]) result(key string) (T, bool, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	entry, ok := b.entries[key]
+	if !ok || !entry.done {
+		var zero T
+		return zero, false, nil
+	}
+	delete(b.entries, key)
+	return entry.value, true, entry.err
+}
+
+// start runs the task in the background unless it is already running or completed, and calls onDone once it completed
+func (b *backgroundTasks[T]) start(key string, task func() (T, error), onDone func()) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if _, ok := b.entries[key]; ok {
+		return
+	}
+	entry := &backgroundTask[T]{}
+	b.entries[key] = entry
+	go func() {
+		value, err := task()
+		b.lock.Lock()
+		entry.done = true
+		entry.value, entry.err = value, err
+		b.lock.Unlock()
+		onDone()
+	}()
+}
+
+// Forget removes the results of the tasks of a deleted rollout. Running tasks are not cancelled, but their results are
+// discarded.
+func (b *backgroundTasks[T]) Forget(rolloutKey string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	for key := range b.entries {
+		if strings.HasPrefix(key, rolloutKey+"/") {
+			delete(b.entries, key)
+		}
+	}
+}
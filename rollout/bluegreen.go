@@ -9,7 +9,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/argoproj/argo-rollouts/controller/metrics"
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
 	"github.com/argoproj/argo-rollouts/utils/defaults"
 	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
 	serviceutil "github.com/argoproj/argo-rollouts/utils/service"
@@ -41,14 +43,25 @@ func (c *rolloutContext) rolloutBlueGreen() error {
 		return err
 	}
 
-	err = c.reconcileBlueGreenReplicaSets(activeSvc)
+	err = c.reconcilePhase(metrics.RolloutPhaseReplicaSets, func() error {
+		return c.reconcileBlueGreenReplicaSets(activeSvc)
+	})
 	if err != nil {
 		return err
 	}
 
 	c.reconcileBlueGreenPause(activeSvc, previewSvc)
 
-	err = c.reconcileActiveService(activeSvc)
+	err = c.reconcilePhase(metrics.RolloutPhaseServices, func() error {
+		return c.reconcileActiveService(activeSvc)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = c.reconcilePhase(metrics.RolloutPhaseTrafficRouting, func() error {
+		return c.reconcilePreviewRouting(activeSvc)
+	})
 	if err != nil {
 		return err
 	}
@@ -58,7 +71,7 @@ func (c *rolloutContext) rolloutBlueGreen() error {
 		return err
 	}
 
-	err = c.reconcileAnalysisRuns()
+	err = c.reconcilePhase(metrics.RolloutPhaseAnalysisRuns, c.reconcileAnalysisRuns)
 	if err != nil {
 		return err
 	}
@@ -214,6 +227,14 @@ func (c *rolloutContext) scaleDownOldReplicaSetsForBlueGreen(oldRSs []*appsv1.Re
 			return false, nil
 		}
 	}
+	// The previous stable ReplicaSet must remain available until the smoke test succeeds so that the
+	// active service can be switched back to it
+	if c.rollout.Spec.Strategy.BlueGreen != nil && c.rollout.Spec.Strategy.BlueGreen.PostPromotionSmokeTest != nil && !skipPostPromotionAnalysisRun(c.rollout, c.newRS, currentPostAr) {
+		if currentPostAr == nil || currentPostAr.Status.Phase != v1alpha1.AnalysisPhaseSuccessful {
+			c.log.Infof("Cannot scale down old ReplicaSets while the post-promotion smoke test is running")
+			return false, nil
+		}
+	}
 	sort.Sort(sort.Reverse(replicasetutil.ReplicaSetsByRevisionNumber(oldRSs)))
 
 	hasScaled := false
@@ -276,6 +297,11 @@ func (c *rolloutContext) syncRolloutStatusBlueGreen(previewSvc *corev1.Service,
 		c.pauseContext.ClearPauseConditions()
 		c.pauseContext.RemoveAbort()
 	}
+	// the progression policies are evaluated again on the next switch of the active service
+	conditions.RemoveRolloutCondition(&newStatus, v1alpha1.RolloutProgressionDenied)
+	if c.promotionDenial != "" {
+		c.setProgressionDenied(&newStatus, c.promotionDenial)
+	}
 
 	previewSelector := serviceutil.GetRolloutSelectorLabel(previewSvc)
 	if previewSelector != c.rollout.Status.BlueGreen.PreviewSelector {
@@ -319,7 +345,8 @@ func (c *rolloutContext) syncRolloutStatusBlueGreen(previewSvc *corev1.Service,
 // matches scaleUpPreviewCheckPoint and prePromotionAnalysis (if used) completes. It get reset to
 // false when the pod template changes, or the rollout fully promotes (stableRS == newRS)
 func (c *rolloutContext) calculateScaleUpPreviewCheckPoint(newStatus v1alpha1.RolloutStatus) bool {
-	if c.rollout.Spec.Strategy.BlueGreen.PreviewReplicaCount == nil {
+	previewReplicaCount := replicasetutil.GetPreviewReplicaCount(c.rollout)
+	if previewReplicaCount == nil {
 		// previewReplicaCount feature is not being used
 		return false
 	}
@@ -334,7 +361,7 @@ func (c *rolloutContext) calculateScaleUpPreviewCheckPoint(newStatus v1alpha1.Ro
 		// do not set the checkpoint unless prePromotionAnalysis was successful and we completed our pause
 		return false
 	}
-	previewCountAvailable := *c.rollout.Spec.Strategy.BlueGreen.PreviewReplicaCount == replicasetutil.GetAvailableReplicaCountForReplicaSets([]*appsv1.ReplicaSet{c.newRS})
+	previewCountAvailable := *previewReplicaCount == replicasetutil.GetAvailableReplicaCountForReplicaSets([]*appsv1.ReplicaSet{c.newRS})
 	if prevValue != previewCountAvailable {
 		c.log.Infof("setting scaleUpPreviewCheckPoint to %v: preview replica count availability is %v", previewCountAvailable, previewCountAvailable)
 	}
@@ -243,6 +243,47 @@ func TestBlueGreenProgressDeadlineAbort(t *testing.T) {
 	}
 }
 
+// TestBlueGreenProgressDeadlineExceededSendsDegradedEvent verifies a RolloutDegraded event is emitted once an update
+// times out progressing
+func TestBlueGreenProgressDeadlineExceededSendsDegradedEvent(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	r1 := newBlueGreenRollout("foo", 1, nil, "active", "preview")
+	r1.Spec.ProgressDeadlineSeconds = ptr.To[int32](1)
+	r2 := bumpVersion(r1)
+
+	rs1 := newReplicaSetWithStatus(r1, 1, 1)
+	rs2 := newReplicaSetWithStatus(r2, 1, 0)
+	rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	rs2PodHash := rs2.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+
+	r2.Status.StableRS = rs1PodHash
+	r2.Status.BlueGreen.ActiveSelector = rs1PodHash
+	r2.Status.BlueGreen.PreviewSelector = rs2PodHash
+	r2.Status.UpdatedReplicas = 1
+	r2.Status.ReadyReplicas = 1
+	r2.Status.AvailableReplicas = 1
+	progressingCond := conditions.NewRolloutCondition(v1alpha1.RolloutProgressing, corev1.ConditionTrue, conditions.ReplicaSetUpdatedReason, conditions.ReplicaSetUpdatedReason)
+	progressingCond.LastUpdateTime = metav1.NewTime(timeutil.Now().Add(-time.Minute))
+	conditions.RemoveRolloutCondition(&r2.Status, v1alpha1.RolloutProgressing)
+	conditions.SetRolloutCondition(&r2.Status, *progressingCond)
+
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.objects = append(f.objects, r2)
+
+	activeSvc := newService("active", 80, map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: rs1PodHash}, r2)
+	previewSvc := newService("preview", 80, map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: rs2PodHash}, r2)
+	f.kubeobjects = append(f.kubeobjects, previewSvc, activeSvc, rs1, rs2)
+	f.serviceLister = append(f.serviceLister, previewSvc, activeSvc)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+
+	f.expectPatchRolloutAction(r2)
+	f.run(getKey(r2, t))
+
+	assert.Contains(t, f.events, conditions.RolloutDegradedReason)
+}
+
 // TestSetServiceManagedBy ensures the managed by annotation is set in the service is set
 func TestSetServiceManagedBy(t *testing.T) {
 	f := newFixture(t)
@@ -843,7 +884,8 @@ func TestBlueGreenHandlePause(t *testing.T) {
 				"conditions": %s,
 				"selector": "%s",
 				"phase": "Healthy",
-				"message": null
+				"message": null,
+				"health": {"percentComplete": 100, "reason": null, "status": "Healthy"}
 			}
 		}`
 		newSelector := metav1.FormatLabelSelector(rs2.Spec.Selector)
@@ -1113,6 +1155,36 @@ func TestBlueGreenStableRSReconciliationShouldNotScaleOnFirstTimeRollout(t *test
 	assert.True(t, strings.Contains(logMessage, "msg=\"Stable ReplicaSet doesn't exist and hence no reconciliation is required.\""), logMessage)
 }
 
+// TestPreviewReplicaProfileIgnoresHPAReplicas verifies that the preview stack of a previewReplicaProfile is not scaled
+// down while the replicas of the active stack dip, e.g. while its pods are replaced
+func TestPreviewReplicaProfileIgnoresHPAReplicas(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	r1 := newBlueGreenRollout("foo", 10, nil, "active", "")
+	r1.Spec.Strategy.BlueGreen.PreviewReplicaProfile = &v1alpha1.PreviewReplicaProfile{Percent: 50}
+	rs1 := newReplicaSetWithStatus(r1, 10, 10)
+	r2 := bumpVersion(r1)
+	rs2 := newReplicaSetWithStatus(r2, 5, 5)
+	f.kubeobjects = append(f.kubeobjects, rs1, rs2)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+
+	rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	rs2PodHash := rs2.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	activeSvc := newService("active", 80, map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: rs1PodHash}, r2)
+
+	// the active stack runs fewer replicas than spec.replicas while its pods churn
+	r2 = updateBlueGreenRolloutStatus(r2, rs2PodHash, rs1PodHash, rs1PodHash, 5, 5, 15, 6, false, true, false)
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.objects = append(f.objects, r2)
+	f.kubeobjects = append(f.kubeobjects, activeSvc)
+	f.serviceLister = append(f.serviceLister, activeSvc)
+
+	// the preview ReplicaSet keeps its 5 replicas, i.e. it is not updated
+	f.expectPatchRolloutAction(r2)
+	f.run(getKey(r2, t))
+}
+
 func TestPreviewReplicaCountHandleScaleUpPreviewCheckPoint(t *testing.T) {
 	t.Run("TrueAfterMeetingMinAvailable", func(t *testing.T) {
 		f := newFixture(t)
@@ -1276,7 +1348,8 @@ func TestBlueGreenRolloutCompleted(t *testing.T) {
 	newConditions := generateConditionsPatchWithHealthy(true, conditions.NewRSAvailableReason, rs2, true, "", true, true)
 	expectedPatch := fmt.Sprintf(`{
 		"status":{
-			"conditions":%s
+			"conditions":%s,
+			"health":{"percentComplete":100,"status":"Healthy"}
 		}
 	}`, newConditions)
 	patch := f.getPatchedRollout(patchIndex)
@@ -1671,6 +1744,7 @@ func TestBlueGreenAddScaleDownDelay(t *testing.T) {
 
 	f.kubeobjects = append(f.kubeobjects, rs1, rs2, activeSvc)
 	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	r2.Status.Health = rolloututil.CalculateRolloutHealth(r2.Spec, r2.Status)
 	f.rolloutLister = append(f.rolloutLister, r2)
 	f.objects = append(f.objects, r2)
 
@@ -1679,3 +1753,73 @@ func TestBlueGreenAddScaleDownDelay(t *testing.T) {
 
 	f.verifyPatchedReplicaSet(rs1Patch, 30)
 }
+
+func TestBlueGreenAddScaleDownDelayOverride(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	r1 := newBlueGreenRollout("foo", 1, nil, "active", "")
+	r1.Spec.Strategy.BlueGreen.ScaleDownDelayOverrides = []v1alpha1.ScaleDownDelayOverride{{Revision: 1, DelaySeconds: 86400}}
+	r2 := bumpVersion(r1)
+
+	rs1 := newReplicaSetWithStatus(r1, 1, 1)
+	rs2 := newReplicaSetWithStatus(r2, 1, 1)
+	rs2PodHash := rs2.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	r2.Status.ObservedGeneration = strconv.Itoa(int(r2.Generation))
+	r2 = updateBlueGreenRolloutStatus(r2, "", rs2PodHash, rs2PodHash, 1, 1, 2, 1, false, true, true)
+	completedCondition, _ := newHealthyCondition(true)
+	conditions.SetRolloutCondition(&r2.Status, completedCondition)
+	progressingCondition, _ := newProgressingCondition(conditions.NewRSAvailableReason, rs2, "")
+	conditions.SetRolloutCondition(&r2.Status, progressingCondition)
+
+	activeSelector := map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: rs2PodHash}
+	activeSvc := newService("active", 80, activeSelector, r2)
+
+	f.kubeobjects = append(f.kubeobjects, rs1, rs2, activeSvc)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	r2.Status.Health = rolloututil.CalculateRolloutHealth(r2.Spec, r2.Status)
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.objects = append(f.objects, r2)
+
+	rs1Patch := f.expectPatchReplicaSetAction(rs1) // set scale-down-deadline annotation
+	f.run(getKey(r2, t))
+
+	f.verifyPatchedReplicaSet(rs1Patch, 86400)
+}
+
+func TestBlueGreenAddScaleDownDelayOverrideMatchesRevision(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	r1 := newBlueGreenRollout("foo", 1, nil, "active", "")
+	r1.Spec.Strategy.BlueGreen.ScaleDownDelayOverrides = []v1alpha1.ScaleDownDelayOverride{
+		{Revision: 1, DelaySeconds: 86400},
+		{Revision: 2, DelaySeconds: 3600},
+	}
+	// revision 2 was superseded, so the only old ReplicaSet is two revisions behind
+	r3 := bumpVersion(bumpVersion(r1))
+
+	rs1 := newReplicaSetWithStatus(r1, 1, 1)
+	rs3 := newReplicaSetWithStatus(r3, 1, 1)
+	rs3PodHash := rs3.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	r3.Status.ObservedGeneration = strconv.Itoa(int(r3.Generation))
+	r3 = updateBlueGreenRolloutStatus(r3, "", rs3PodHash, rs3PodHash, 1, 1, 2, 1, false, true, true)
+	completedCondition, _ := newHealthyCondition(true)
+	conditions.SetRolloutCondition(&r3.Status, completedCondition)
+	progressingCondition, _ := newProgressingCondition(conditions.NewRSAvailableReason, rs3, "")
+	conditions.SetRolloutCondition(&r3.Status, progressingCondition)
+
+	activeSelector := map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: rs3PodHash}
+	activeSvc := newService("active", 80, activeSelector, r3)
+
+	f.kubeobjects = append(f.kubeobjects, rs1, rs3, activeSvc)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs3)
+	f.rolloutLister = append(f.rolloutLister, r3)
+	f.objects = append(f.objects, r3)
+
+	rs1Patch := f.expectPatchReplicaSetAction(rs1) // set scale-down-deadline annotation
+	f.expectPatchRolloutAction(r3)
+	f.run(getKey(r3, t))
+
+	f.verifyPatchedReplicaSet(rs1Patch, 3600)
+}
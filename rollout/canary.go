@@ -8,6 +8,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 
+	"github.com/argoproj/argo-rollouts/controller/metrics"
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	"github.com/argoproj/argo-rollouts/rollout/trafficrouting"
 	"github.com/argoproj/argo-rollouts/utils/conditions"
@@ -31,6 +32,12 @@ func (c *rolloutContext) rolloutCanary() error {
 	if err != nil {
 		return fmt.Errorf("failed to getAllReplicaSetsAndSyncRevision in rolloutCanary create true: %w", err)
 	}
+	if c.rollout.Status.PromoteFull && !c.fullPromotionAllowed() {
+		// the rollout continues with its steps while the progression policies deny the full promotion
+		c.log.Info("Full promotion held back by the progression policies")
+		c.rollout.Status.PromoteFull = false
+		c.promoteFullDenied = true
+	}
 
 	restarted, err := c.podRestarter.Reconcile(c)
 	if err != nil {
@@ -47,6 +54,16 @@ func (c *rolloutContext) rolloutCanary() error {
 		return nil
 	}
 
+	replaced, err := c.reconcileCanaryPodTemplateOverlay()
+	if err != nil {
+		return err
+	}
+	if replaced > 0 {
+		// Same as restarted pods, the availability counts can no longer be trusted after pods were replaced
+		c.log.Infof("Finished reconciliation due to %d replaced pods", replaced)
+		return nil
+	}
+
 	err = c.reconcileEphemeralMetadata()
 	if err != nil {
 		return err
@@ -60,20 +77,48 @@ func (c *rolloutContext) rolloutCanary() error {
 		return err
 	}
 
-	if err := c.reconcileStableAndCanaryService(); err != nil {
+	if err := c.reconcileKEDAScaledObjects(); err != nil {
+		return err
+	}
+
+	if err := c.reconcilePodDisruptionBudgets(); err != nil {
+		return err
+	}
+
+	if err := c.reconcileLoadTest(); err != nil {
+		return err
+	}
+
+	if err := c.reconcilePreProvision(); err != nil {
+		return err
+	}
+
+	if err := c.reconcileFeatureFlags(); err != nil {
+		return err
+	}
+
+	if err := c.reconcileMigrations(); err != nil {
+		return err
+	}
+
+	if err := c.reconcileGate(); err != nil {
+		return err
+	}
+
+	if err := c.reconcilePhase(metrics.RolloutPhaseServices, c.reconcileStableAndCanaryService); err != nil {
 		return err
 	}
 
-	if err := c.reconcileTrafficRouting(); err != nil {
+	if err := c.reconcilePhase(metrics.RolloutPhaseTrafficRouting, c.reconcileTrafficRouting); err != nil {
 		return err
 	}
 
-	err = c.reconcileExperiments()
+	err = c.reconcilePhase(metrics.RolloutPhaseExperiments, c.reconcileExperiments)
 	if err != nil {
 		return err
 	}
 
-	err = c.reconcileAnalysisRuns()
+	err = c.reconcilePhase(metrics.RolloutPhaseAnalysisRuns, c.reconcileAnalysisRuns)
 	if c.pauseContext.HasAddPause() {
 		c.log.Info("Detected pause due to inconclusive AnalysisRun")
 		return c.syncRolloutStatusCanary()
@@ -82,7 +127,11 @@ func (c *rolloutContext) rolloutCanary() error {
 		return err
 	}
 
-	noScalingOccurred, err := c.reconcileCanaryReplicaSets()
+	var noScalingOccurred bool
+	err = c.reconcilePhase(metrics.RolloutPhaseReplicaSets, func() (err error) {
+		noScalingOccurred, err = c.reconcileCanaryReplicaSets()
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -231,6 +280,9 @@ func (c *rolloutContext) scaleDownOldReplicaSetsForCanary(oldRSs []*appsv1.Repli
 					if err != nil {
 						return totalScaledDown, err
 					}
+					if desiredReplicaCount < *targetRS.Spec.Replicas && !c.isScaleDownVerified(targetRS) {
+						continue
+					}
 				}
 			} else {
 				// If we get here, we are *not* fully promoted and are in the middle of an update.
@@ -251,6 +303,9 @@ func (c *rolloutContext) scaleDownOldReplicaSetsForCanary(oldRSs []*appsv1.Repli
 		if err != nil {
 			return totalScaledDown, fmt.Errorf("failed to scaleReplicaSetAndRecordEvent in scaleDownOldReplicaSetsForCanary: %w", err)
 		}
+		if desiredReplicaCount == 0 {
+			c.drainProbes.forget(drainProbeKey(c.rollout, targetRS))
+		}
 		scaleDownCount := *targetRS.Spec.Replicas - desiredReplicaCount
 		maxScaleDown -= scaleDownCount
 		totalScaledDown += scaleDownCount
@@ -261,14 +316,16 @@ func (c *rolloutContext) scaleDownOldReplicaSetsForCanary(oldRSs []*appsv1.Repli
 
 // canProceedWithScaleDownAnnotation returns whether or not it is safe to proceed with annotating
 // old replicasets with the scale-down-deadline in the traffic-routed canary strategy.
-// This method only matters with ALB canary + the target group verification feature.
+// This method only matters with ALB canary + the target group verification feature, and with traffic router plugins.
 // The safety guarantees we provide are that we will not scale down *anything* unless we can verify
 // stable target group endpoints are registered properly.
 // NOTE: this method was written in a way which avoids AWS API calls.
 func (c *rolloutContext) canProceedWithScaleDownAnnotation(oldRSs []*appsv1.ReplicaSet) (bool, error) {
 	isALBCanary := c.rollout.Spec.Strategy.Canary != nil && c.rollout.Spec.Strategy.Canary.TrafficRouting != nil && c.rollout.Spec.Strategy.Canary.TrafficRouting.ALB != nil
-	if !isALBCanary {
-		// Only ALB
+	// the traffic router plugins were verified when the traffic routing was reconciled
+	isPluginCanary := c.rollout.Spec.Strategy.Canary != nil && c.rollout.Spec.Strategy.Canary.TrafficRouting != nil && len(c.rollout.Spec.Strategy.Canary.TrafficRouting.Plugins) > 0
+	if !isALBCanary && !isPluginCanary {
+		// Only ALB and traffic router plugins
 		return true, nil
 	}
 
@@ -289,17 +346,20 @@ func (c *rolloutContext) canProceedWithScaleDownAnnotation(oldRSs []*appsv1.Repl
 		// AWS API calls.
 		return true, nil
 	}
-	stableSvcName, _ := trafficrouting.GetStableAndCanaryServices(c.rollout, true)
-	stableSvc, err := c.servicesLister.Services(c.rollout.Namespace).Get(stableSvcName)
-	if err != nil {
-		return false, err
-	}
-	err = c.awsVerifyTargetGroups(stableSvc)
-	if err != nil {
-		return false, err
+	routersVerified := c.areTargetsVerified()
+	if isALBCanary {
+		stableSvcName, _ := trafficrouting.GetStableAndCanaryServices(c.rollout, true)
+		stableSvc, err := c.getService(stableSvcName)
+		if err != nil {
+			return false, err
+		}
+		err = c.awsVerifyTargetGroups(stableSvc)
+		if err != nil {
+			return false, err
+		}
 	}
 
-	canProceed := c.areTargetsVerified()
+	canProceed := routersVerified && c.areTargetsVerified()
 	c.log.Infof("Proceed with scaledown: %v", canProceed)
 	return canProceed, nil
 }
@@ -312,6 +372,10 @@ func (c *rolloutContext) completedCurrentCanaryStep() bool {
 	if currentStep == nil {
 		return false
 	}
+	// a step which exceeded its progress deadline or failed its guardrail is held until the rollout is promoted
+	if getPauseCondition(c.rollout, v1alpha1.PauseReasonStepProgressDeadline) != nil || getPauseCondition(c.rollout, v1alpha1.PauseReasonGuardrailAnalysis) != nil {
+		return false
+	}
 	switch {
 	case currentStep.Pause != nil:
 		return c.pauseContext.CompletedCanaryPauseStep(*currentStep.Pause)
@@ -327,8 +391,11 @@ func (c *rolloutContext) completedCurrentCanaryStep() bool {
 		}
 		return true
 	case currentStep.Experiment != nil:
+		if currentStep.Experiment.PromoteWinner && experimentWinnerPromoted(c.rollout) {
+			return true
+		}
 		experiment := c.currentEx
-		return experiment != nil && experiment.Status.Phase == v1alpha1.AnalysisPhaseSuccessful
+		return experiment != nil && experiment.Status.Phase == v1alpha1.AnalysisPhaseSuccessful && !c.promotingExperimentWinner
 	case currentStep.Analysis != nil:
 		currentStepAr := c.currentArs.CanaryStep
 		analysisExistsAndCompleted := currentStepAr != nil && currentStepAr.Status.Phase.Completed()
@@ -339,22 +406,75 @@ func (c *rolloutContext) completedCurrentCanaryStep() bool {
 		return true
 	case currentStep.Plugin != nil:
 		return c.stepPluginContext.isStepPluginCompleted(*currentStepIndex, currentStep.Plugin)
+	case currentStep.SetCanaryPodTemplateOverlay != nil:
+		return c.podTemplateOverlaySynced
+	case currentStep.LoadTest != nil:
+		return c.loadTestCompleted
+	case currentStep.PreProvision != nil:
+		return c.preProvisionCompleted
+	case currentStep.SetFeatureFlag != nil:
+		return c.featureFlagsSynced
+	case currentStep.Migration != nil:
+		return c.migrationCompleted
+	case currentStep.Gate != nil:
+		return c.gateCompleted
 	}
 	return false
 }
 
+// setCanaryHPAStatus sets the replica count and the selector of the scale subresource, which an HPA scales the rollout
+// with, and publishes the canary and stable pods separately while the canary is rolled out with traffic routing. The HPA
+// follows the pods of every ReplicaSet, unless hpaScalesStable is set: without dynamic stable scale, spec.replicas is
+// then the replica count of the stable ReplicaSet and the canary is scaled to the canary weight of it, so the HPA only
+// follows the stable pods, like it follows the active pods of a blue-green rollout.
+func (c *rolloutContext) setCanaryHPAStatus(newStatus *v1alpha1.RolloutStatus) {
+	newStatus.HPAReplicas = replicasetutil.GetActualReplicaCountForReplicaSets(c.allRSs)
+	newStatus.Selector = metav1.FormatLabelSelector(c.rollout.Spec.Selector)
+	newStatus.Canary.StableReplicas = 0
+	newStatus.Canary.CanaryReplicas = 0
+	newStatus.Canary.CanarySelector = ""
+	canary := c.rollout.Spec.Strategy.Canary
+	if canary.TrafficRouting == nil || c.newRS == nil || !replicasetutil.CheckStableRSExists(c.newRS, c.stableRS) {
+		return
+	}
+	newStatus.Canary.StableReplicas = c.stableRS.Status.Replicas
+	newStatus.Canary.CanaryReplicas = c.newRS.Status.Replicas
+	newStatus.Canary.CanarySelector = metav1.FormatLabelSelector(c.newRS.Spec.Selector)
+	if canary.HPAScalesStable && !canary.DynamicStableScale {
+		newStatus.HPAReplicas = c.stableRS.Status.Replicas
+		newStatus.Selector = metav1.FormatLabelSelector(c.stableRS.Spec.Selector)
+	}
+}
+
 func (c *rolloutContext) syncRolloutStatusCanary() error {
 	newStatus := c.calculateBaseStatus()
 	newStatus.AvailableReplicas = replicasetutil.GetAvailableReplicaCountForReplicaSets(c.allRSs)
-	newStatus.HPAReplicas = replicasetutil.GetActualReplicaCountForReplicaSets(c.allRSs)
-	newStatus.Selector = metav1.FormatLabelSelector(c.rollout.Spec.Selector)
+	c.setCanaryHPAStatus(&newStatus)
 
 	newStatus.Canary.StablePingPong = c.rollout.Status.Canary.StablePingPong
 	newStatus.Canary.StepPluginStatuses = c.rollout.Status.Canary.StepPluginStatuses
+	newStatus.Canary.StableFeatureFlags = c.rollout.Status.Canary.StableFeatureFlags
+	newStatus.Canary.FeatureFlags = c.rollout.Status.Canary.FeatureFlags
+	if c.featureFlags != nil {
+		newStatus.Canary.FeatureFlags = c.featureFlags
+	}
+	newStatus.Canary.Migrations = c.rollout.Status.Canary.Migrations
+	if c.migrations != nil {
+		newStatus.Canary.Migrations = c.migrations
+	}
+	newStatus.Canary.Gate = c.rollout.Status.Canary.Gate
+	if c.gateReconciled {
+		newStatus.Canary.Gate = c.gate
+	}
 	c.stepPluginContext.updateStatus(&newStatus)
 
 	currentStep, currentStepIndex := replicasetutil.GetCurrentCanaryStep(c.rollout)
 	newStatus.StableRS = c.rollout.Status.StableRS
+	// the progression policies are evaluated again once the current step completed
+	conditions.RemoveRolloutCondition(&newStatus, v1alpha1.RolloutProgressionDenied)
+	if c.promotionDenial != "" {
+		c.setProgressionDenied(&newStatus, c.promotionDenial)
+	}
 	newStatus.CurrentStepHash = conditions.ComputeStepHash(c.rollout)
 	stepCount := int32(len(c.rollout.Spec.Strategy.Canary.Steps))
 
@@ -402,16 +522,18 @@ func (c *rolloutContext) syncRolloutStatusCanary() error {
 		return c.persistRolloutStatus(&newStatus)
 	}
 
-	if c.completedCurrentCanaryStep() {
+	if c.completedCurrentCanaryStep() && c.progressionAllowed(&newStatus, *currentStepIndex, *currentStepIndex+1) {
 		stepStr := rolloututil.CanaryStepString(*currentStep)
 		*currentStepIndex++
 		newStatus.Canary.CurrentStepAnalysisRunStatus = nil
 
 		c.recorder.Eventf(c.rollout, record.EventOptions{EventReason: conditions.RolloutStepCompletedReason}, conditions.RolloutStepCompletedMessage, int(*currentStepIndex), stepCount, stepStr)
 		c.pauseContext.RemovePauseCondition(v1alpha1.PauseReasonCanaryPauseStep)
+		c.pauseContext.RemovePauseCondition(v1alpha1.PauseReasonGate)
 	}
 
 	newStatus.CurrentStepIndex = currentStepIndex
+	newStatus.Canary.WeightOverride = weightOverrideOfStep(c.rollout, currentStepIndex)
 	return c.persistRolloutStatus(&newStatus)
 }
 
@@ -8,6 +8,10 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -1164,6 +1168,7 @@ func TestSyncRolloutWaitAddToQueue(t *testing.T) {
 	conditions.SetRolloutCondition(&r2.Status, completedCondition)
 
 	r2.Status.ObservedGeneration = strconv.Itoa(int(r2.Generation))
+	r2.Status.Health = rolloututil.CalculateRolloutHealth(r2.Spec, r2.Status)
 	f.rolloutLister = append(f.rolloutLister, r2)
 	f.objects = append(f.objects, r2)
 
@@ -1215,6 +1220,7 @@ func TestSyncRolloutIgnoreWaitOutsideOfReconciliationPeriod(t *testing.T) {
 	completedCondition, _ := newCompletedCondition(false)
 	conditions.SetRolloutCondition(&r2.Status, completedCondition)
 
+	r2.Status.Health = rolloututil.CalculateRolloutHealth(r2.Spec, r2.Status)
 	f.rolloutLister = append(f.rolloutLister, r2)
 	f.objects = append(f.objects, r2)
 
@@ -1328,6 +1334,70 @@ func TestCanaryRolloutStatusHPAStatusFields(t *testing.T) {
 	assert.JSONEq(t, calculatePatch(r2, expectedPatchWithSub), patch)
 }
 
+func TestCanaryRolloutStatusHPAStatusFieldsWithTrafficRouting(t *testing.T) {
+	run := func(t *testing.T, hpaScalesStable bool) (string, string, string) {
+		f := newFixture(t)
+		defer f.Close()
+
+		steps := []v1alpha1.CanaryStep{
+			{
+				SetWeight: ptr.To[int32](20),
+			}, {
+				Pause: &v1alpha1.RolloutPause{},
+			},
+		}
+		r1 := newCanaryRollout("foo", 5, nil, steps, ptr.To[int32](1), intstr.FromInt(1), intstr.FromInt(0))
+		r1.Spec.Strategy.Canary.TrafficRouting = &v1alpha1.RolloutTrafficRouting{}
+		r1.Spec.Strategy.Canary.HPAScalesStable = hpaScalesStable
+		r2 := bumpVersion(r1)
+		progressingCondition, _ := newProgressingCondition(conditions.RolloutPausedReason, r2, "")
+		conditions.SetRolloutCondition(&r2.Status, progressingCondition)
+
+		pausedCondition, _ := newPausedCondition(true)
+		conditions.SetRolloutCondition(&r2.Status, pausedCondition)
+
+		rs1 := newReplicaSetWithStatus(r1, 5, 5)
+		rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+		rs2 := newReplicaSetWithStatus(r2, 1, 1)
+		rs2PodHash := rs2.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+		f.kubeobjects = append(f.kubeobjects, rs1, rs2)
+		f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+
+		r2 = updateCanaryRolloutStatus(r2, rs1PodHash, 6, 1, 6, true)
+		f.rolloutLister = append(f.rolloutLister, r2)
+		f.objects = append(f.objects, r2)
+
+		index := f.expectPatchRolloutAction(r2)
+		f.run(getKey(r2, t))
+
+		return f.getPatchedRolloutWithoutConditions(index), rs1PodHash, rs2PodHash
+	}
+
+	t.Run("AllReplicaSets", func(t *testing.T) {
+		patch, _, rs2PodHash := run(t, false)
+		var patched v1alpha1.Rollout
+		assert.NoError(t, json.Unmarshal([]byte(patch), &patched))
+		// the HPA keeps following the pods of every ReplicaSet, which the status already reports
+		assert.NotContains(t, patch, `"HPAReplicas"`)
+		assert.NotContains(t, patch, `"selector"`)
+		assert.Equal(t, int32(5), patched.Status.Canary.StableReplicas)
+		assert.Equal(t, int32(1), patched.Status.Canary.CanaryReplicas)
+		assert.Equal(t, "foo=bar,rollouts-pod-template-hash="+rs2PodHash, patched.Status.Canary.CanarySelector)
+	})
+
+	t.Run("HPAScalesStable", func(t *testing.T) {
+		patch, rs1PodHash, rs2PodHash := run(t, true)
+		var patched v1alpha1.Rollout
+		assert.NoError(t, json.Unmarshal([]byte(patch), &patched))
+		// the HPA follows the stable pods, since spec.replicas is the replica count of the stable ReplicaSet
+		assert.Equal(t, int32(5), patched.Status.HPAReplicas)
+		assert.Equal(t, "foo=bar,rollouts-pod-template-hash="+rs1PodHash, patched.Status.Selector)
+		assert.Equal(t, int32(5), patched.Status.Canary.StableReplicas)
+		assert.Equal(t, int32(1), patched.Status.Canary.CanaryReplicas)
+		assert.Equal(t, "foo=bar,rollouts-pod-template-hash="+rs2PodHash, patched.Status.Canary.CanarySelector)
+	})
+}
+
 func TestCanaryRolloutWithCanaryService(t *testing.T) {
 	f := newFixture(t)
 	defer f.Close()
@@ -2124,3 +2194,50 @@ func TestIsDynamicallyRollingBackToStable(t *testing.T) {
 		})
 	}
 }
+
+func TestCanaryRolloutReconcileIsTraced(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(provider)
+
+	f := newFixture(t)
+	defer f.Close()
+
+	steps := []v1alpha1.CanaryStep{
+		{
+			Pause: &v1alpha1.RolloutPause{},
+		},
+	}
+	r1 := newCanaryRollout("foo", 10, nil, steps, ptr.To[int32](0), intstr.FromInt(1), intstr.FromInt(0))
+	r2 := bumpVersion(r1)
+
+	rs1 := newReplicaSetWithStatus(r1, 10, 10)
+	rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	rs2 := newReplicaSetWithStatus(r2, 0, 0)
+	f.kubeobjects = append(f.kubeobjects, rs1, rs2)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+
+	r2 = updateCanaryRolloutStatus(r2, rs1PodHash, 10, 0, 10, false)
+
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.objects = append(f.objects, r2)
+
+	f.expectPatchRolloutAction(r2)
+	f.run(getKey(r2, t))
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	reconcileSpan, ok := spans["rollout.reconcile"]
+	assert.True(t, ok)
+	assert.Contains(t, reconcileSpan.Attributes(), attribute.String("rollout.name", "foo"))
+	assert.Contains(t, reconcileSpan.Attributes(), attribute.String("rollout.strategy", "canary"))
+	for _, phase := range []string{"rollout.reconcileServices", "rollout.reconcileTrafficRouting", "rollout.reconcileExperiments", "rollout.reconcileAnalysisRuns", "rollout.reconcileReplicaSets", "rollout.reconcileStatusPatch"} {
+		span, ok := spans[phase]
+		if assert.True(t, ok, phase) {
+			assert.Equal(t, reconcileSpan.SpanContext().SpanID(), span.Parent().SpanID())
+		}
+	}
+}
@@ -1,11 +1,17 @@
 package rollout
 
 import (
+	"context"
+
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	analysisutil "github.com/argoproj/argo-rollouts/utils/analysis"
+	timeutil "github.com/argoproj/argo-rollouts/utils/time"
+	"github.com/argoproj/argo-rollouts/utils/tracing"
 )
 
 type rolloutContext struct {
@@ -47,10 +53,85 @@ type rolloutContext struct {
 	// (e.g. a setWeight step, after a blue-green active switch, after stable service switch),
 	// since we do not want to continually verify weight in case it could incur rate-limiting or other expenses.
 	targetsVerified *bool
+
+	// createdServices are the services created by the controller during this reconciliation
+	// (see canary.createServices). They are used until the services appear in the informer cache.
+	createdServices map[string]*corev1.Service
+
+	// podTemplateOverlaySynced indicates that all pods of the new ReplicaSet run with the current pod
+	// template overlay (see setCanaryPodTemplateOverlay)
+	podTemplateOverlaySynced bool
+
+	// promotingExperimentWinner indicates that the rollout was updated to the template which won the experiment of
+	// the current step, so the step does not complete (see promoteExperimentWinner)
+	promotingExperimentWinner bool
+
+	// loadTestCompleted indicates that the load test of the current step is started, or completed if the step waits
+	// on it (see reconcileLoadTest)
+	loadTestCompleted bool
+
+	// preProvisionCompleted indicates that the placeholder pods of the current step were scheduled, or that the step
+	// timed out waiting on them (see reconcilePreProvision)
+	preProvisionCompleted bool
+
+	// featureFlags are the percentages the LaunchDarkly flags of the setFeatureFlag steps were set to, and
+	// featureFlagsSynced indicates that all of them are set to the percentages of the current step
+	// (see reconcileFeatureFlags)
+	featureFlags       []v1alpha1.FeatureFlagStatus
+	featureFlagsSynced bool
+
+	// migrations are the statuses of the migration steps started by the current update, and migrationCompleted
+	// indicates that the migrations of the current step are completed (see reconcileMigrations)
+	migrations         []v1alpha1.MigrationStatus
+	migrationCompleted bool
+
+	// gate is the status of the webhook of the current gate step, and gateCompleted indicates that the webhook decided
+	// to proceed or that the rollout was promoted past the step. gateReconciled indicates that the status of the gate
+	// was reconciled (see reconcileGate)
+	gate           *v1alpha1.GateStatus
+	gateCompleted  bool
+	gateReconciled bool
+
+	// progressionPending indicates that the update of the rollout is held back by the progression limits of the
+	// controller (see reconcileProgressionGate)
+	progressionPending bool
+	// imageVerificationPending indicates that the images of the new ReplicaSet are being verified, during which the
+	// update is held back (see reconcileImageVerification)
+	imageVerificationPending bool
+	// promotionDenial is the message of a blue-green promotion or a full promotion which the progression policies deny
+	promotionDenial string
+	// promoteFullDenied indicates that the full promotion requested in status.promoteFull is held back by the
+	// progression policies. The request is kept, and status.promoteFull is disregarded until the policies allow it.
+	promoteFullDenied bool
+
+	// traceCtx carries the span of the reconciliation. Phases of the reconciliation are traced as its children
+	// (see reconcilePhase)
+	traceCtx context.Context
 }
 
-func (c *rolloutContext) reconcile() error {
-	err := c.checkPausedConditions()
+func (c *rolloutContext) reconcile() (err error) {
+	strategy := "canary"
+	if c.rollout.Spec.Strategy.BlueGreen != nil {
+		strategy = "blueGreen"
+	}
+	ctx, span := tracing.StartSpan(context.Background(), "rollout.reconcile",
+		attribute.String("rollout.namespace", c.rollout.Namespace),
+		attribute.String("rollout.name", c.rollout.Name),
+		attribute.String("rollout.strategy", strategy),
+	)
+	c.traceCtx = ctx
+	defer func() { tracing.EndSpan(span, err) }()
+
+	err = c.reconcileSyncAction()
+	if err != nil {
+		return err
+	}
+	if c.newRollout != nil {
+		// exit early since we modified the rollout
+		return nil
+	}
+
+	err = c.checkPausedConditions()
 	if err != nil {
 		return err
 	}
@@ -59,6 +140,20 @@ func (c *rolloutContext) reconcile() error {
 		return nil
 	}
 
+	err = c.reconcileImageVerification()
+	if err != nil {
+		return err
+	}
+	if c.imageVerificationPending {
+		// The update is held back until the images are verified, which enqueues the rollout again
+		return nil
+	}
+
+	err = c.reconcileCreatedServices()
+	if err != nil {
+		return err
+	}
+
 	isScalingEvent, err := c.isScalingEvent()
 	if err != nil {
 		return err
@@ -76,6 +171,23 @@ func (c *rolloutContext) reconcile() error {
 	return c.rolloutCanary()
 }
 
+// reconcilePhase runs a phase of the reconciliation, which is traced in a child span of the reconcile span
+// and whose duration is recorded in the rollout_reconcile_phase metric
+func (c *rolloutContext) reconcilePhase(phase string, reconcile func() error) error {
+	ctx := c.traceCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	_, span := tracing.StartSpan(ctx, "rollout.reconcile"+phase)
+	startTime := timeutil.Now()
+	err := reconcile()
+	if c.metricsServer != nil {
+		c.metricsServer.ObserveRolloutReconcilePhase(c.rollout, phase, timeutil.Now().Sub(startTime))
+	}
+	tracing.EndSpan(span, err)
+	return err
+}
+
 func (c *rolloutContext) SetRestartedAt() {
 	c.newStatus.RestartedAt = c.rollout.Spec.RestartAt
 }
@@ -95,6 +207,15 @@ func (c *rolloutContext) SetCurrentExperiment(ex *v1alpha1.Experiment) {
 func (c *rolloutContext) SetCurrentAnalysisRuns(currARs analysisutil.CurrentAnalysisRuns) {
 	c.currentArs = currARs
 
+	currRestartAr := currARs.Restart
+	if currRestartAr != nil && c.newStatus.RestartStatus != nil {
+		c.newStatus.RestartStatus.CurrentAnalysisRunStatus = &v1alpha1.RolloutAnalysisRunStatus{
+			Name:    currRestartAr.Name,
+			Status:  currRestartAr.Status.Phase,
+			Message: currRestartAr.Status.Message,
+		}
+	}
+
 	if c.rollout.Spec.Strategy.Canary != nil {
 		currBackgroundAr := currARs.CanaryBackground
 		if currBackgroundAr != nil {
@@ -104,6 +225,14 @@ func (c *rolloutContext) SetCurrentAnalysisRuns(currARs analysisutil.CurrentAnal
 				Message: currBackgroundAr.Status.Message,
 			}
 		}
+		currGuardrailAr := currARs.CanaryGuardrail
+		if currGuardrailAr != nil {
+			c.newStatus.Canary.CurrentGuardrailAnalysisRunStatus = &v1alpha1.RolloutAnalysisRunStatus{
+				Name:    currGuardrailAr.Name,
+				Status:  currGuardrailAr.Status.Phase,
+				Message: currGuardrailAr.Status.Message,
+			}
+		}
 		currStepAr := currARs.CanaryStep
 		if currStepAr != nil {
 			c.newStatus.Canary.CurrentStepAnalysisRunStatus = &v1alpha1.RolloutAnalysisRunStatus{
@@ -134,7 +263,8 @@ func (c *rolloutContext) SetCurrentAnalysisRuns(currARs analysisutil.CurrentAnal
 
 // haltProgress returns a reason on whether or not we should halt all progress with an update
 // to ReplicaSet counts (e.g. due to canary steps or blue-green promotion). This is either because
-// user explicitly paused the rollout by setting `spec.paused`, or the analysis was inconclusive
+// user explicitly paused the rollout by setting `spec.paused`, the analysis was inconclusive, or the
+// guardrail analysis failed
 func (c *rolloutContext) haltProgress() string {
 	if c.rollout.Spec.Paused {
 		return "user paused"
@@ -142,5 +272,8 @@ func (c *rolloutContext) haltProgress() string {
 	if getPauseCondition(c.rollout, v1alpha1.PauseReasonInconclusiveAnalysis) != nil {
 		return "inconclusive analysis"
 	}
+	if getPauseCondition(c.rollout, v1alpha1.PauseReasonGuardrailAnalysis) != nil {
+		return "failed guardrail analysis"
+	}
 	return ""
 }
@@ -20,11 +20,16 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamiclister"
 	appsinformers "k8s.io/client-go/informers/apps/v1"
+	batchinformers "k8s.io/client-go/informers/batch/v1"
 	coreinformers "k8s.io/client-go/informers/core/v1"
+	policyinformers "k8s.io/client-go/informers/policy/v1"
 	"k8s.io/client-go/kubernetes"
 	appslisters "k8s.io/client-go/listers/apps/v1"
+	batchlisters "k8s.io/client-go/listers/batch/v1"
 	v1 "k8s.io/client-go/listers/core/v1"
+	policylisters "k8s.io/client-go/listers/policy/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/kubectl/pkg/util/slice"
@@ -44,18 +49,21 @@ import (
 	"github.com/argoproj/argo-rollouts/rollout/trafficrouting/appmesh"
 	"github.com/argoproj/argo-rollouts/rollout/trafficrouting/istio"
 	analysisutil "github.com/argoproj/argo-rollouts/utils/analysis"
+	"github.com/argoproj/argo-rollouts/utils/annotations"
 	"github.com/argoproj/argo-rollouts/utils/conditions"
 	controllerutil "github.com/argoproj/argo-rollouts/utils/controller"
 	"github.com/argoproj/argo-rollouts/utils/defaults"
 	experimentutil "github.com/argoproj/argo-rollouts/utils/experiment"
 	ingressutil "github.com/argoproj/argo-rollouts/utils/ingress"
 	istioutil "github.com/argoproj/argo-rollouts/utils/istio"
+	kedautil "github.com/argoproj/argo-rollouts/utils/keda"
 	logutil "github.com/argoproj/argo-rollouts/utils/log"
 	"github.com/argoproj/argo-rollouts/utils/record"
 	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
 	resourceversionutil "github.com/argoproj/argo-rollouts/utils/resourceversion"
 	rolloututil "github.com/argoproj/argo-rollouts/utils/rollout"
 	serviceutil "github.com/argoproj/argo-rollouts/utils/service"
+	"github.com/argoproj/argo-rollouts/utils/sharding"
 	timeutil "github.com/argoproj/argo-rollouts/utils/time"
 	unstructuredutil "github.com/argoproj/argo-rollouts/utils/unstructured"
 )
@@ -73,8 +81,6 @@ type Controller struct {
 	// rsControl is used for adopting/releasing replica sets.
 	replicaSetControl controller.RSControlInterface
 
-	metricsServer *metrics.MetricsServer
-
 	// workqueue is a rate limited work queue. This is used to queue work to be
 	// processed instead of performing it as soon as a change happens. This
 	// means we can ensure we only process a fixed amount of resources at a
@@ -86,6 +92,21 @@ type Controller struct {
 	// rolloutVersionTracker remembers ResourceVersions from our last successful writes so
 	// syncHandler can requeue when the informer cache hasn't caught up yet.
 	rolloutVersionTracker *resourceversionutil.Tracker
+	// sharder selects the rollouts reconciled by this controller replica when running in sharding mode
+	sharder *sharding.Sharder
+	// workerScaling configures the scaling of the rollout workers
+	workerScaling controllerutil.WorkerScaling
+	// warmStart persists the state of the rollouts, so that unchanged rollouts are not all re-evaluated at once after
+	// a restart
+	warmStart *warmStart
+	// reconcileCache skips the reconciliations of idle rollouts which did not change since they were last reconciled
+	reconcileCache *reconcileCache
+	// replicaSetIndexer, analysisRunIndexer and experimentsIndexer index the objects by the UID of their controller
+	replicaSetIndexer  cache.Indexer
+	analysisRunIndexer cache.Indexer
+	experimentsIndexer cache.Indexer
+	// garbageCollector periodically deletes the analysis runs and experiments which are no longer needed
+	garbageCollector *garbageCollector
 }
 
 // ControllerConfig describes the data required to instantiate a new rollout controller
@@ -100,13 +121,18 @@ type ControllerConfig struct {
 	AnalysisRunInformer             informers.AnalysisRunInformer
 	AnalysisTemplateInformer        informers.AnalysisTemplateInformer
 	ClusterAnalysisTemplateInformer informers.ClusterAnalysisTemplateInformer
+	ControllerConfigInformer        informers.RolloutControllerConfigInformer
 	ReplicaSetInformer              appsinformers.ReplicaSetInformer
 	ServicesInformer                coreinformers.ServiceInformer
+	PodInformer                     coreinformers.PodInformer
+	PodDisruptionBudgetInformer     policyinformers.PodDisruptionBudgetInformer
+	JobInformer                     batchinformers.JobInformer
 	IngressWrapper                  IngressWrapper
 	RolloutsInformer                informers.RolloutInformer
 	IstioPrimaryDynamicClient       dynamic.Interface
 	IstioVirtualServiceInformer     cache.SharedIndexInformer
 	IstioDestinationRuleInformer    cache.SharedIndexInformer
+	ScaledObjectInformer            cache.SharedIndexInformer
 	ResyncPeriod                    time.Duration
 	RolloutWorkQueue                workqueue.RateLimitingInterface
 	ServiceWorkQueue                workqueue.RateLimitingInterface
@@ -115,6 +141,14 @@ type ControllerConfig struct {
 	Recorder                        record.EventRecorder
 	EphemeralMetadataThreads        int
 	EphemeralMetadataPodRetries     int
+	RolloutResyncPeriod             time.Duration
+	ReplicaSetResyncPeriod          time.Duration
+	Sharder                         *sharding.Sharder
+	WorkerScaling                   controllerutil.WorkerScaling
+	WarmStart                       WarmStartConfig
+	GarbageCollection               GarbageCollectionConfig
+	ProgressionLimits               ProgressionLimitsConfig
+	ReconcileCache                  ReconcileCacheConfig
 }
 
 // reconcilerBase is a shared datastructure containing all clients and configuration necessary to
@@ -139,15 +173,38 @@ type reconcilerBase struct {
 	rolloutsSynced                cache.InformerSynced
 	rolloutsIndexer               cache.Indexer
 	servicesLister                v1.ServiceLister
+	podLister                     v1.PodLister
+	podDisruptionBudgetLister     policylisters.PodDisruptionBudgetLister
+	jobLister                     batchlisters.JobLister
 	ingressWrapper                IngressWrapper
 	experimentsLister             listers.ExperimentLister
 	analysisRunLister             listers.AnalysisRunLister
 	analysisTemplateLister        listers.AnalysisTemplateLister
 	clusterAnalysisTemplateLister listers.ClusterAnalysisTemplateLister
+	controllerConfigLister        listers.RolloutControllerConfigLister
+	scaledObjectLister            dynamiclister.Lister
 	IstioController               *istio.IstioController
 
 	podRestarter RolloutPodRestarter
 
+	metricsServer *metrics.MetricsServer
+	// statusCoalescer coalesces rapid successive status updates of a rollout
+	statusCoalescer *statusCoalescer
+	// progressionGate limits the number of rollouts progressing through an update at once
+	progressionGate *progressionGate
+	// previewRoutes remembers the preview routes synced into HTTPRoutes
+	previewRoutes *previewRouteCache
+	// drainProbes runs the drain probes of the scale down verification in the background
+	drainProbes *drainProbes
+	// imageVerifications runs the image verifications of the new ReplicaSets in the background
+	imageVerifications *imageVerifications
+	// featureFlagUpdates sets the LaunchDarkly flags of the setFeatureFlag steps in the background
+	featureFlagUpdates *backgroundTasks[int32]
+	// migrationStatusChecks runs the status checks of the migration steps in the background
+	migrationStatusChecks *backgroundTasks[migrationStatusCheckResult]
+	// gateCalls calls the webhooks of the gate steps in the background
+	gateCalls *backgroundTasks[*GateResponse]
+
 	// used for unit testing
 	enqueueRollout              func(obj any)                                                                  //nolint:structcheck
 	enqueueRolloutAfter         func(obj any, duration time.Duration)                                          //nolint:structcheck
@@ -199,17 +256,41 @@ func NewController(cfg ControllerConfig) *Controller {
 		rolloutsLister:                cfg.RolloutsInformer.Lister(),
 		rolloutsSynced:                cfg.RolloutsInformer.Informer().HasSynced,
 		servicesLister:                cfg.ServicesInformer.Lister(),
+		podLister:                     cfg.PodInformer.Lister(),
+		podDisruptionBudgetLister:     cfg.PodDisruptionBudgetInformer.Lister(),
+		jobLister:                     cfg.JobInformer.Lister(),
 		ingressWrapper:                cfg.IngressWrapper,
 		experimentsLister:             cfg.ExperimentInformer.Lister(),
 		analysisRunLister:             cfg.AnalysisRunInformer.Lister(),
 		analysisTemplateLister:        cfg.AnalysisTemplateInformer.Lister(),
 		clusterAnalysisTemplateLister: cfg.ClusterAnalysisTemplateInformer.Lister(),
+		controllerConfigLister:        cfg.ControllerConfigInformer.Lister(),
+		scaledObjectLister:            dynamiclister.New(cfg.ScaledObjectInformer.GetIndexer(), kedautil.GetScaledObjectGVR()),
 		recorder:                      cfg.Recorder,
 		resyncPeriod:                  cfg.ResyncPeriod,
 		podRestarter:                  podRestarter,
 		refResolver:                   cfg.RefResolver,
 		ephemeralMetadataThreads:      cfg.EphemeralMetadataThreads,
 		ephemeralMetadataPodRetries:   cfg.EphemeralMetadataPodRetries,
+		metricsServer:                 cfg.MetricsServer,
+		statusCoalescer:               newStatusCoalescer(),
+		progressionGate:               newProgressionGate(cfg.ProgressionLimits),
+		previewRoutes:                 newPreviewRouteCache(cfg.ResyncPeriod),
+		drainProbes:                   newDrainProbes(),
+		imageVerifications:            newImageVerifications(),
+		featureFlagUpdates:            newBackgroundTasks[int32](),
+		migrationStatusChecks:         newBackgroundTasks[migrationStatusCheckResult](),
+		gateCalls:                     newBackgroundTasks[*GateResponse](),
+	}
+
+	for _, informer := range []cache.SharedIndexInformer{
+		cfg.ReplicaSetInformer.Informer(),
+		cfg.AnalysisRunInformer.Informer(),
+		cfg.ExperimentInformer.Informer(),
+	} {
+		if err := controllerutil.AddOwnerIndexer(informer); err != nil {
+			panic(err)
+		}
 	}
 
 	controller := &Controller{
@@ -219,9 +300,16 @@ func NewController(cfg ControllerConfig) *Controller {
 		rolloutWorkqueue:      cfg.RolloutWorkQueue,
 		serviceWorkqueue:      cfg.ServiceWorkQueue,
 		ingressWorkqueue:      cfg.IngressWorkQueue,
-		metricsServer:         cfg.MetricsServer,
 		rolloutVersionTracker: resourceversionutil.NewTracker(),
+		sharder:               cfg.Sharder,
+		workerScaling:         cfg.WorkerScaling,
+		warmStart:             newWarmStart(cfg.KubeClientSet, cfg.WarmStart),
+		reconcileCache:        newReconcileCache(cfg.ReconcileCache),
+		replicaSetIndexer:     cfg.ReplicaSetInformer.Informer().GetIndexer(),
+		analysisRunIndexer:    cfg.AnalysisRunInformer.Informer().GetIndexer(),
+		experimentsIndexer:    cfg.ExperimentInformer.Informer().GetIndexer(),
 	}
+	controller.garbageCollector = newGarbageCollector(controller, cfg.GarbageCollection)
 	controller.enqueueRollout = func(obj any) {
 		controllerutil.EnqueueRateLimited(obj, cfg.RolloutWorkQueue)
 	}
@@ -241,7 +329,7 @@ func NewController(cfg ControllerConfig) *Controller {
 
 	log.Info("Setting up event handlers")
 	// Set up an event handler for when rollout resources change
-	cfg.RolloutsInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+	controllerutil.AddEventHandlerWithResyncPeriod(cfg.RolloutsInformer.Informer(), cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj any) {
 			controller.enqueueRollout(obj)
 			ro := unstructuredutil.ObjectToRollout(obj)
@@ -272,6 +360,15 @@ func NewController(cfg ControllerConfig) *Controller {
 					controller.IstioController.EnqueueDestinationRule(key)
 				}
 			}
+			if oldRollout != nil && newRollout != nil {
+				controller.recordUserAction(oldRollout, newRollout)
+			}
+			if oldRollout != nil && newRollout != nil && isPriorityUpdate(oldRollout, newRollout) {
+				logCtx := logutil.WithRollout(newRollout)
+				logCtx.Info("rollout enqueue with priority due to user initiated update event")
+				controllerutil.EnqueuePriority(new, cfg.RolloutWorkQueue)
+				return
+			}
 			if newRollout != nil {
 				logCtx := logutil.WithRollout(newRollout)
 				logCtx.Info("rollout enqueue due to update event")
@@ -283,6 +380,17 @@ func NewController(cfg ControllerConfig) *Controller {
 				logCtx := logutil.WithRollout(ro)
 				logCtx.Info("rollout enqueue due to delete event")
 				controller.metricsServer.Remove(ro.Namespace, ro.Name, logutil.RolloutKey)
+				controller.statusCoalescer.Forget(ro.Namespace + "/" + ro.Name)
+				controller.warmStart.Forget(ro.Namespace + "/" + ro.Name)
+				controller.reconcileCache.Forget(ro.Namespace + "/" + ro.Name)
+				controller.releaseProgression(ro.Namespace + "/" + ro.Name)
+				controller.previewRoutes.Forget(ro.Namespace + "/" + ro.Name)
+				controller.drainProbes.Forget(ro.Namespace + "/" + ro.Name)
+				controller.imageVerifications.Forget(ro.Namespace + "/" + ro.Name)
+				controller.featureFlagUpdates.Forget(ro.Namespace + "/" + ro.Name)
+				controller.migrationStatusChecks.Forget(ro.Namespace + "/" + ro.Name)
+				controller.gateCalls.Forget(ro.Namespace + "/" + ro.Name)
+				controller.recorder.ForgetNotificationDeliveries(ro)
 				// Rollout is deleted, queue up the referenced Service and/or DestinationRules so
 				// that the rollouts-pod-template-hash can be cleared from each
 				for _, s := range serviceutil.GetRolloutServiceKeys(ro) {
@@ -294,18 +402,19 @@ func NewController(cfg ControllerConfig) *Controller {
 				controller.recorder.Eventf(ro, record.EventOptions{EventReason: conditions.RolloutDeletedReason}, conditions.RolloutDeletedMessage, ro.Name, ro.Namespace)
 			}
 		},
-	})
+	}, cfg.RolloutResyncPeriod)
 
-	cfg.ReplicaSetInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+	controllerutil.AddEventHandlerWithResyncPeriod(cfg.ReplicaSetInformer.Informer(), cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj any) {
 			controllerutil.EnqueueParentObject(obj, register.RolloutKind, controller.enqueueRollout)
 		},
 		UpdateFunc: func(old, new any) {
 			newRS := new.(*appsv1.ReplicaSet)
 			oldRS := old.(*appsv1.ReplicaSet)
-			if newRS.ResourceVersion == oldRS.ResourceVersion {
+			if newRS.ResourceVersion == oldRS.ResourceVersion && cfg.ReplicaSetResyncPeriod <= 0 {
 				// Periodic resync will send update events for all known replicas.
 				// Two different versions of the same Replica will always have different RVs.
+				// The resyncs are only of interest when ReplicaSets are resynced explicitly.
 				return
 			}
 			controllerutil.EnqueueParentObject(new, register.RolloutKind, controller.enqueueRollout)
@@ -313,7 +422,7 @@ func NewController(cfg ControllerConfig) *Controller {
 		DeleteFunc: func(obj any) {
 			controllerutil.EnqueueParentObject(obj, register.RolloutKind, controller.enqueueRollout)
 		},
-	})
+	}, cfg.ReplicaSetResyncPeriod)
 
 	cfg.AnalysisRunInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj any) {
@@ -336,6 +445,24 @@ func NewController(cfg ControllerConfig) *Controller {
 		},
 	})
 
+	// Apply the defaults and restrictions of the RolloutControllerConfigs to the rollouts of their namespace
+	syncControllerConfigs := func(obj any) {
+		key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+		if err != nil {
+			return
+		}
+		if namespace, _, err := cache.SplitMetaNamespaceKey(key); err == nil {
+			controller.syncControllerConfigs(namespace)
+		}
+	}
+	cfg.ControllerConfigInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: syncControllerConfigs,
+		UpdateFunc: func(old, new any) {
+			syncControllerConfigs(new)
+		},
+		DeleteFunc: syncControllerConfigs,
+	})
+
 	return controller
 }
 
@@ -363,29 +490,68 @@ func removedKeys(name string, old, new *v1alpha1.Rollout, keyFunc func(ro *v1alp
 func (c *Controller) Run(ctx context.Context, threadiness int) error {
 	log.Info("Starting Rollout workers")
 	wg := sync.WaitGroup{}
-	for i := 0; i < threadiness; i++ {
-		wg.Add(1)
-		go wait.Until(func() {
-			controllerutil.RunWorker(ctx, c.rolloutWorkqueue, logutil.RolloutKey, c.syncHandler, c.metricsServer)
-			log.Debug("Rollout worker has stopped")
-			wg.Done()
-		}, time.Second, ctx.Done())
-	}
+	c.seedProgressionGate()
+	workers := controllerutil.StartWorkers(ctx, c.rolloutWorkqueue, logutil.RolloutKey, c.syncHandler, c.metricsServer, threadiness, c.workerScaling)
 	log.Info("Started rollout workers")
 
 	wg.Add(1)
 	go c.IstioController.Run(ctx)
 
+	if c.warmStart != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			wait.Until(func() {
+				if err := c.warmStart.Persist(ctx); err != nil {
+					log.Warn(err)
+				}
+			}, c.warmStart.config.PersistInterval, ctx.Done())
+		}()
+	}
+
+	if c.garbageCollector != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			wait.Until(func() {
+				if err := c.garbageCollector.Collect(ctx, timeutil.Now()); err != nil && ctx.Err() == nil {
+					log.Warn(err)
+				}
+			}, c.garbageCollector.config.Interval, ctx.Done())
+		}()
+	}
+
 	<-ctx.Done()
 	c.IstioController.ShutDownWithDrain()
 	wg.Done()
 
 	wg.Wait()
+	workers.Wait(controllerutil.DefaultWorkerDrainTimeout)
 	log.Info("All rollout workers have stopped")
 
+	// persist the state of the last reconciliations before the controller exits
+	persistCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := c.warmStart.Persist(persistCtx); err != nil {
+		log.Warn(err)
+	}
+
 	return nil
 }
 
+// RestoreWarmStart restores the persisted state of the rollouts, so that the first reconciliations of the rollouts
+// which did not change since the controller last ran are spread over time. It must be called before the rollouts
+// informer starts.
+func (c *Controller) RestoreWarmStart(ctx context.Context) error {
+	return c.warmStart.Restore(ctx, timeutil.Now())
+}
+
+// HandOff persists the state of the rollouts for the next leader, which then skips the reconciliations of the
+// unchanged rollouts. It must be called once the controller stopped after a graceful shutdown.
+func (c *Controller) HandOff(ctx context.Context) error {
+	return c.warmStart.HandOff(ctx, timeutil.Now())
+}
+
 // syncHandler compares the actual state with the desired, and attempts to
 // converge the two. It then updates the Phase block of the Rollout resource
 // with the current status of the resource.
@@ -398,16 +564,35 @@ func (c *Controller) syncHandler(ctx context.Context, key string) error {
 	rollout, err := c.rolloutsLister.Rollouts(namespace).Get(name)
 	if k8serrors.IsNotFound(err) {
 		c.rolloutVersionTracker.Forget(key)
+		c.warmStart.Forget(key)
+		c.reconcileCache.Forget(key)
+		c.releaseProgression(key)
 		return nil
 	}
 	if err != nil {
 		return err
 	}
 
+	if !c.sharder.Owns(rollout) {
+		return nil
+	}
+
 	if c.rolloutVersionTracker.IsCacheStale(key, rollout.ResourceVersion) {
 		return controllerutil.StaleCacheError
 	}
 
+	if delay := c.warmStart.Defer(key, rollout, timeutil.Now()); delay > 0 {
+		logutil.WithRollout(rollout).Infof("Deferring reconciliation of unchanged rollout for %s after restart", delay)
+		c.enqueueRolloutAfter(rollout, delay)
+		return nil
+	}
+
+	fingerprint, idle := c.reconcileFingerprint(rollout)
+	if idle && c.reconcileCache.Fresh(key, fingerprint, timeutil.Now()) {
+		logutil.WithRollout(rollout).Debug("Skipping reconciliation of unchanged idle rollout")
+		return nil
+	}
+
 	// Remarshal the rollout to normalize all fields so that when we calculate hashes against the
 	// rollout spec and pod template spec, the hash will be consistent. See issue #70
 	// This also returns a copy of the rollout to prevent mutation of the informer cache.
@@ -449,6 +634,10 @@ func (c *Controller) syncHandler(ctx context.Context, key string) error {
 		c.rolloutVersionTracker.Record(key, roCtx.newRollout.ResourceVersion)
 		return nil
 	}
+	if roCtx.progressionPending {
+		// The update is held back until a progressing rollout releases its slot, which enqueues the rollout again
+		return nil
+	}
 
 	// In order to work with HPA, the rollout.Spec.Replica field cannot be nil. As a result, the controller will update
 	// the rollout to have the replicas field set to the default value. see https://github.com/argoproj/argo-rollouts/issues/119
@@ -474,6 +663,14 @@ func (c *Controller) syncHandler(ctx context.Context, key string) error {
 	}
 	if roCtx.newRollout != nil {
 		c.rolloutVersionTracker.Record(key, roCtx.newRollout.ResourceVersion)
+		c.warmStart.Observe(key, roCtx.newRollout)
+	} else {
+		c.warmStart.Observe(key, rollout)
+	}
+	if idle {
+		c.reconcileCache.Observe(key, fingerprint, timeutil.Now())
+	} else {
+		c.reconcileCache.Forget(key)
 	}
 	return nil
 }
@@ -491,6 +688,10 @@ func (c *Controller) newRolloutContext(rollout *v1alpha1.Rollout) (*rolloutConte
 	if err != nil {
 		return nil, err
 	}
+	rsList, err = c.hydrateReplicaSets(rollout, rsList)
+	if err != nil {
+		return nil, err
+	}
 
 	newRS := replicasetutil.FindNewReplicaSet(rollout, rsList)
 	olderRSs := replicasetutil.FindOldReplicaSets(rollout, rsList, newRS)
@@ -524,9 +725,12 @@ func (c *Controller) newRolloutContext(rollout *v1alpha1.Rollout) (*rolloutConte
 		currentEx:  currentEx,
 		otherExs:   otherExs,
 		newStatus: v1alpha1.RolloutStatus{
-			RestartedAt: rollout.Status.RestartedAt,
-			ALB:         rollout.Status.ALB,
-			ALBs:        rollout.Status.ALBs,
+			RestartedAt:   rollout.Status.RestartedAt,
+			RestartStatus: rollout.Status.RestartStatus.DeepCopy(),
+			ALB:           rollout.Status.ALB,
+			ALBs:          rollout.Status.ALBs,
+			// the last action of a user is recorded by the admission webhook of the controller
+			LastUserAction: rollout.Status.LastUserAction,
 		},
 		pauseContext: &pauseContext{
 			rollout: rollout,
@@ -561,6 +765,14 @@ func (c *Controller) newRolloutContext(rollout *v1alpha1.Rollout) (*rolloutConte
 		return nil, err
 	}
 
+	roCtx.progressionPending, err = roCtx.reconcileProgressionGate()
+	if err != nil {
+		return nil, err
+	}
+	if roCtx.progressionPending {
+		return &roCtx, nil
+	}
+
 	if roCtx.newRS == nil {
 		roCtx.newRS, err = roCtx.createDesiredReplicaSet()
 		if err != nil {
@@ -578,6 +790,7 @@ func (c *Controller) newRolloutContext(rollout *v1alpha1.Rollout) (*rolloutConte
 	}
 	// carry over existing recorded weights
 	roCtx.newStatus.Canary.Weights = rollout.Status.Canary.Weights
+	roCtx.newStatus.Canary.WeightHistory = rollout.Status.Canary.WeightHistory
 	return &roCtx, nil
 }
 
@@ -587,6 +800,10 @@ func (c *rolloutContext) getRolloutValidationErrors() error {
 		return rolloutValidationErrors[0]
 	}
 
+	if err := c.getRestrictionErrors(); err != nil {
+		return err
+	}
+
 	refResources, err := c.getRolloutReferencedResources()
 	if err != nil {
 		return err
@@ -784,7 +1001,11 @@ func (c *rolloutContext) getReferencedServices() (*[]validation.ServiceWithType,
 
 func (c *rolloutContext) getReferencedService(serviceName string, serviceType validation.ServiceType) (*validation.ServiceWithType, error) {
 	if serviceName != "" {
-		svc, err := c.servicesLister.Services(c.rollout.Namespace).Get(serviceName)
+		svc, err := c.getService(serviceName)
+		if k8serrors.IsNotFound(err) && c.shouldCreateService(serviceType) {
+			// the service is created by reconcileCreatedServices and validated once it exists
+			return nil, nil
+		}
 		if k8serrors.IsNotFound(err) {
 			fldPath := validation.GetServiceWithTypeFieldPath(serviceType)
 			return nil, field.Invalid(fldPath, serviceName, err.Error())
@@ -820,6 +1041,19 @@ func (c *rolloutContext) getReferencedRolloutAnalyses() (*[]validation.AnalysisT
 			templates.Args = blueGreen.PostPromotionAnalysis.Args
 			analysisTemplates = append(analysisTemplates, *templates)
 		}
+
+		if blueGreen.PostPromotionSmokeTest != nil && len(blueGreen.PostPromotionSmokeTest.Templates) > 0 {
+			smokeTestAnalysis := &v1alpha1.RolloutAnalysis{
+				Templates: blueGreen.PostPromotionSmokeTest.Templates,
+				Args:      blueGreen.PostPromotionSmokeTest.Args,
+			}
+			templates, err := c.getReferencedAnalysisTemplates(c.rollout, smokeTestAnalysis, validation.PostPromotionAnalysis, 0)
+			if err != nil {
+				return nil, err
+			}
+			templates.Args = smokeTestAnalysis.Args
+			analysisTemplates = append(analysisTemplates, *templates)
+		}
 	} else if c.rollout.Spec.Strategy.Canary != nil {
 		canary := c.rollout.Spec.Strategy.Canary
 		for i, step := range canary.Steps {
@@ -840,6 +1074,22 @@ func (c *rolloutContext) getReferencedRolloutAnalyses() (*[]validation.AnalysisT
 			templates.Args = canary.Analysis.Args
 			analysisTemplates = append(analysisTemplates, *templates)
 		}
+		if canary.Guardrail != nil {
+			templates, err := c.getReferencedAnalysisTemplates(c.rollout, &canary.Guardrail.RolloutAnalysis, validation.GuardrailAnalysis, 0)
+			if err != nil {
+				return nil, err
+			}
+			templates.Args = canary.Guardrail.Args
+			analysisTemplates = append(analysisTemplates, *templates)
+		}
+	}
+	if restartStrategy := c.rollout.Spec.RestartStrategy; restartStrategy != nil && restartStrategy.Analysis != nil {
+		templates, err := c.getReferencedAnalysisTemplates(c.rollout, restartStrategy.Analysis, validation.RestartAnalysis, 0)
+		if err != nil {
+			return nil, err
+		}
+		templates.Args = restartStrategy.Analysis.Args
+		analysisTemplates = append(analysisTemplates, *templates)
 	}
 	return &analysisTemplates, nil
 }
@@ -975,6 +1225,19 @@ func handleCacheError(name string, childFields []string, value any, err error) (
 	}
 }
 
+// isPriorityUpdate returns whether the update of the rollout is a change of the spec or a user initiated action, such
+// as promote, abort, retry or a sync action, which is reconciled ahead of periodic resyncs
+func isPriorityUpdate(old, new *v1alpha1.Rollout) bool {
+	if old.ResourceVersion == new.ResourceVersion {
+		return false
+	}
+	return old.Generation != new.Generation ||
+		old.Status.Abort != new.Status.Abort ||
+		old.Status.PromoteFull != new.Status.PromoteFull ||
+		len(old.Status.PauseConditions) != len(new.Status.PauseConditions) ||
+		old.Annotations[annotations.SyncActionAnnotation] != new.Annotations[annotations.SyncActionAnnotation]
+}
+
 func remarshalRollout(r *v1alpha1.Rollout) *v1alpha1.Rollout {
 	rolloutBytes, err := json.Marshal(r)
 	if err != nil {
@@ -991,7 +1254,15 @@ func remarshalRollout(r *v1alpha1.Rollout) *v1alpha1.Rollout {
 // updateReplicaSet updates the replicaset using kubeclient update. It returns the updated replicaset and copies the updated replicaset
 // into the passed in pointer as well.
 func (c *rolloutContext) updateReplicaSet(ctx context.Context, rs *appsv1.ReplicaSet) (*appsv1.ReplicaSet, error) {
-	updatedRS, err := c.kubeclientset.AppsV1().ReplicaSets(rs.Namespace).Update(ctx, rs, metav1.UpdateOptions{})
+	if replicasetutil.IsCompacted(rs) {
+		// never overwrite the pod template of a ReplicaSet with the empty pod template spec of a compacted ReplicaSet
+		hydratedRS, err := c.hydrateReplicaSet(ctx, rs)
+		if err != nil {
+			return nil, err
+		}
+		rs.Spec.Template.Spec = hydratedRS.Spec.Template.Spec
+	}
+	updatedRS, err := c.kubeclientset.AppsV1().ReplicaSets(rs.Namespace).Update(ctx, rs, metav1.UpdateOptions{FieldManager: defaults.DefaultFieldManager})
 	if err != nil {
 		return nil, fmt.Errorf("error updating replicaset in updateReplicaSet %s: %w", rs.Name, err)
 	}
@@ -54,10 +54,12 @@ import (
 	"github.com/argoproj/argo-rollouts/utils/hash"
 	ingressutil "github.com/argoproj/argo-rollouts/utils/ingress"
 	istioutil "github.com/argoproj/argo-rollouts/utils/istio"
+	kedautil "github.com/argoproj/argo-rollouts/utils/keda"
 	"github.com/argoproj/argo-rollouts/utils/queue"
 	"github.com/argoproj/argo-rollouts/utils/record"
 	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
 	rolloututil "github.com/argoproj/argo-rollouts/utils/rollout"
+	"github.com/argoproj/argo-rollouts/utils/sharding"
 	timeutil "github.com/argoproj/argo-rollouts/utils/time"
 	unstructuredutil "github.com/argoproj/argo-rollouts/utils/unstructured"
 )
@@ -118,8 +120,10 @@ type fixture struct {
 	analysisRunLister             []*v1alpha1.AnalysisRun
 	clusterAnalysisTemplateLister []*v1alpha1.ClusterAnalysisTemplate
 	analysisTemplateLister        []*v1alpha1.AnalysisTemplate
+	controllerConfigLister        []*v1alpha1.RolloutControllerConfig
 	replicaSetLister              []*appsv1.ReplicaSet
 	serviceLister                 []*corev1.Service
+	podLister                     []*corev1.Pod
 	ingressLister                 []*ingressutil.Ingress
 	virtualServiceLister          []*unstructured.Unstructured
 	// Actions expected to happen on the client.
@@ -546,6 +550,16 @@ func calculatePatch(ro *v1alpha1.Rollout, patch string) string {
 	}
 	newStatus := newPatch["status"].(map[string]any)
 	newStatus["observedGeneration"] = newObservedGen
+	if _, ok := newStatus["health"]; !ok {
+		// the health summary follows the phase of the patched rollout
+		health := rolloututil.CalculateRolloutHealth(newRO.Spec, newRO.Status)
+		if !reflect.DeepEqual(health, ro.Status.Health) {
+			healthBytes, _ := json.Marshal(health)
+			var healthMap map[string]any
+			json.Unmarshal(healthBytes, &healthMap)
+			newStatus["health"] = healthMap
+		}
+	}
 	newPatch["status"] = newStatus
 	newPatchBytes, _ := json.Marshal(newPatch)
 	return string(newPatchBytes)
@@ -599,15 +613,17 @@ func (f *fixture) newController(resync resyncFunc) (*Controller, informers.Share
 	vsvcGVR := istioutil.GetIstioVirtualServiceGVR()
 	destGVR := istioutil.GetIstioDestinationRuleGVR()
 	listMapping := map[schema.GroupVersionResource]string{
-		tgbGVR:  "TargetGroupBindingList",
-		vsvcGVR: vsvcGVR.Resource + "List",
-		destGVR: destGVR.Resource + "List",
+		tgbGVR:                        "TargetGroupBindingList",
+		vsvcGVR:                       vsvcGVR.Resource + "List",
+		destGVR:                       destGVR.Resource + "List",
+		kedautil.GetScaledObjectGVR(): "ScaledObjectList",
 	}
 
 	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping, dynamicClientObjects...)
 	dynamicInformerFactory := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 0)
 	istioVirtualServiceInformer := dynamicInformerFactory.ForResource(istioutil.GetIstioVirtualServiceGVR()).Informer()
 	istioDestinationRuleInformer := dynamicInformerFactory.ForResource(istioutil.GetIstioDestinationRuleGVR()).Informer()
+	scaledObjectInformer := dynamicInformerFactory.ForResource(kedautil.GetScaledObjectGVR()).Informer()
 
 	rolloutWorkqueue := workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Millisecond, 10*time.Second), "Rollouts")
 	serviceWorkqueue := workqueue.NewNamedRateLimitingQueue(queue.DefaultArgoRolloutsRateLimiter(), "Services")
@@ -632,13 +648,18 @@ func (f *fixture) newController(resync resyncFunc) (*Controller, informers.Share
 		AnalysisRunInformer:             i.Argoproj().V1alpha1().AnalysisRuns(),
 		AnalysisTemplateInformer:        i.Argoproj().V1alpha1().AnalysisTemplates(),
 		ClusterAnalysisTemplateInformer: i.Argoproj().V1alpha1().ClusterAnalysisTemplates(),
+		ControllerConfigInformer:        i.Argoproj().V1alpha1().RolloutControllerConfigs(),
 		ReplicaSetInformer:              k8sI.Apps().V1().ReplicaSets(),
 		ServicesInformer:                k8sI.Core().V1().Services(),
+		PodInformer:                     k8sI.Core().V1().Pods(),
+		PodDisruptionBudgetInformer:     k8sI.Policy().V1().PodDisruptionBudgets(),
+		JobInformer:                     k8sI.Batch().V1().Jobs(),
 		IngressWrapper:                  ingressWrapper,
 		RolloutsInformer:                i.Argoproj().V1alpha1().Rollouts(),
 		IstioPrimaryDynamicClient:       dynamicClient,
 		IstioVirtualServiceInformer:     istioVirtualServiceInformer,
 		IstioDestinationRuleInformer:    istioDestinationRuleInformer,
+		ScaledObjectInformer:            scaledObjectInformer,
 		ResyncPeriod:                    resync(),
 		RolloutWorkQueue:                rolloutWorkqueue,
 		ServiceWorkQueue:                serviceWorkqueue,
@@ -695,6 +716,9 @@ func (f *fixture) newController(resync resyncFunc) (*Controller, informers.Share
 	for _, s := range f.serviceLister {
 		k8sI.Core().V1().Services().Informer().GetIndexer().Add(s)
 	}
+	for _, p := range f.podLister {
+		k8sI.Core().V1().Pods().Informer().GetIndexer().Add(p)
+	}
 	for _, i := range f.ingressLister {
 		ing, err := i.GetExtensionsIngress()
 		if err != nil {
@@ -711,6 +735,9 @@ func (f *fixture) newController(resync resyncFunc) (*Controller, informers.Share
 	for _, ar := range f.analysisRunLister {
 		i.Argoproj().V1alpha1().AnalysisRuns().Informer().GetIndexer().Add(ar)
 	}
+	for _, config := range f.controllerConfigLister {
+		i.Argoproj().V1alpha1().RolloutControllerConfigs().Informer().GetIndexer().Add(config)
+	}
 	for _, vs := range f.virtualServiceLister {
 		c.IstioController.VirtualServiceInformer.GetIndexer().Add(vs)
 	}
@@ -912,6 +939,8 @@ func filterInformerActions(actions []core.Action) []core.Action {
 			action.Matches("watch", "analysistemplates") ||
 			action.Matches("list", "clusteranalysistemplates") ||
 			action.Matches("watch", "clusteranalysistemplates") ||
+			action.Matches("list", "rolloutcontrollerconfigs") ||
+			action.Matches("watch", "rolloutcontrollerconfigs") ||
 			action.Matches("list", "rollouts") ||
 			action.Matches("watch", "rollouts") ||
 			action.Matches("list", "replicaSets") ||
@@ -920,7 +949,12 @@ func filterInformerActions(actions []core.Action) []core.Action {
 			action.Matches("watch", "services") ||
 			action.Matches("list", "ingresses") ||
 			action.Matches("watch", "ingresses") ||
-			action.Matches("list", "pods") {
+			action.Matches("list", "pods") ||
+			action.Matches("watch", "pods") ||
+			action.Matches("list", "poddisruptionbudgets") ||
+			action.Matches("watch", "poddisruptionbudgets") ||
+			action.Matches("list", "jobs") ||
+			action.Matches("watch", "jobs") {
 			continue
 		}
 		ret = append(ret, action)
@@ -930,13 +964,14 @@ func filterInformerActions(actions []core.Action) []core.Action {
 }
 
 func (f *fixture) expectPatchServiceAction(s *corev1.Service, newLabel string) int {
-	patch := fmt.Sprintf(switchSelectorPatch, newLabel)
 	serviceSchema := schema.GroupVersionResource{
 		Resource: "services",
 		Version:  "v1",
 	}
+	// the selector is applied from a fresh read of the service
+	f.kubeactions = append(f.kubeactions, core.NewGetAction(serviceSchema, s.Namespace, s.Name))
 	len := len(f.kubeactions)
-	f.kubeactions = append(f.kubeactions, core.NewPatchAction(serviceSchema, s.Namespace, s.Name, types.MergePatchType, []byte(patch)))
+	f.kubeactions = append(f.kubeactions, core.NewPatchAction(serviceSchema, s.Namespace, s.Name, types.ApplyPatchType, nil))
 	return len
 }
 
@@ -1137,9 +1172,11 @@ func (f *fixture) verifyPatchedReplicaSet(index int, scaleDownDelaySeconds int32
 	if !ok {
 		assert.Fail(f.t, "Expected Patch action, not %s", action.GetVerb())
 	}
+	assert.Equal(f.t, types.ApplyPatchType, patchAction.GetPatchType())
 	now := timeutil.Now().Add(time.Duration(scaleDownDelaySeconds) * time.Second).UTC().Format(time.RFC3339)
-	patch := fmt.Sprintf(addScaleDownAtAnnotationsPatch, v1alpha1.DefaultReplicaSetScaleDownDeadlineAnnotationKey, now)
-	assert.Equal(f.t, string(patchAction.GetPatch()), patch)
+	rs := appsv1.ReplicaSet{}
+	assert.NoError(f.t, json.Unmarshal(patchAction.GetPatch(), &rs))
+	assert.Equal(f.t, map[string]string{v1alpha1.DefaultReplicaSetScaleDownDeadlineAnnotationKey: now}, rs.Annotations)
 }
 
 func (f *fixture) verifyPatchedService(index int, newPodHash string, managedBy string) {
@@ -1148,11 +1185,13 @@ func (f *fixture) verifyPatchedService(index int, newPodHash string, managedBy s
 	if !ok {
 		assert.Fail(f.t, "Expected Patch action, not %s", action.GetVerb())
 	}
-	patch := fmt.Sprintf(switchSelectorPatch, newPodHash)
+	assert.Equal(f.t, types.ApplyPatchType, patchAction.GetPatchType())
+	svc := corev1.Service{}
+	assert.NoError(f.t, json.Unmarshal(patchAction.GetPatch(), &svc))
+	assert.Equal(f.t, newPodHash, svc.Spec.Selector[v1alpha1.DefaultRolloutUniqueLabelKey])
 	if managedBy != "" {
-		patch = fmt.Sprintf(switchSelectorAndAddManagedByPatch, managedBy, newPodHash)
+		assert.Equal(f.t, managedBy, svc.Annotations[v1alpha1.ManagedByRolloutsKey])
 	}
-	assert.Equal(f.t, patch, string(patchAction.GetPatch()))
 }
 
 func (f *fixture) verifyPatchedRolloutAborted(index int, rsName string) {
@@ -1372,6 +1411,62 @@ func TestDontSyncRolloutsWithEmptyPodSelector(t *testing.T) {
 	f.runWithSyncs(getKey(r, t), 2)
 }
 
+func TestIsPriorityUpdate(t *testing.T) {
+	old := newCanaryRollout("foo", 1, nil, nil, nil, intstr.FromInt(0), intstr.FromInt(1))
+	old.ResourceVersion = "1"
+	old.Generation = 1
+
+	resync := old.DeepCopy()
+	assert.False(t, isPriorityUpdate(old, resync))
+
+	statusUpdate := old.DeepCopy()
+	statusUpdate.ResourceVersion = "2"
+	statusUpdate.Status.AvailableReplicas = 1
+	assert.False(t, isPriorityUpdate(old, statusUpdate))
+
+	specChange := old.DeepCopy()
+	specChange.ResourceVersion = "2"
+	specChange.Generation = 2
+	assert.True(t, isPriorityUpdate(old, specChange))
+
+	abort := old.DeepCopy()
+	abort.ResourceVersion = "2"
+	abort.Status.Abort = true
+	assert.True(t, isPriorityUpdate(old, abort))
+	assert.True(t, isPriorityUpdate(abort, old))
+
+	promoteFull := old.DeepCopy()
+	promoteFull.ResourceVersion = "2"
+	promoteFull.Status.PromoteFull = true
+	assert.True(t, isPriorityUpdate(old, promoteFull))
+
+	paused := old.DeepCopy()
+	paused.Status.PauseConditions = []v1alpha1.PauseCondition{{Reason: v1alpha1.PauseReasonCanaryPauseStep}}
+	promote := paused.DeepCopy()
+	promote.ResourceVersion = "2"
+	promote.Status.PauseConditions = nil
+	assert.True(t, isPriorityUpdate(paused, promote))
+}
+
+func TestDontSyncRolloutsOfOtherShards(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	r := newBlueGreenRollout("foo", 1, nil, "active", "")
+	r.Labels = map[string]string{v1alpha1.LabelKeyControllerShard: "1"}
+	activeSvc := newService("active", 80, nil, r)
+	f.rolloutLister = append(f.rolloutLister, r)
+	f.serviceLister = append(f.serviceLister, activeSvc)
+	f.objects = append(f.objects, r)
+	f.kubeobjects = append(f.kubeobjects, activeSvc)
+
+	c, i, k8sI := f.newController(noResyncPeriodFunc)
+	sharder, err := sharding.NewSharder(2, 0)
+	require.NoError(t, err)
+	c.sharder = sharder
+	f.runController(getKey(r, t), true, false, c, i, k8sI)
+}
+
 func TestAdoptReplicaSet(t *testing.T) {
 	f := newFixture(t)
 	defer f.Close()
@@ -1693,6 +1788,7 @@ func TestComputeHashChangeTolerationBlueGreen(t *testing.T) {
 	corev1defaults.SetObjectDefaults_PodTemplate(&podTemplate)
 	rs.Spec.Template = podTemplate.Template
 
+	r.Status.Health = rolloututil.CalculateRolloutHealth(r.Spec, r.Status)
 	f.rolloutLister = append(f.rolloutLister, r)
 	f.objects = append(f.objects, r)
 	activeSvc := newService("active", 80, selector.MatchLabels, r)
@@ -1738,6 +1834,7 @@ func TestComputeHashChangeTolerationCanary(t *testing.T) {
 	corev1defaults.SetObjectDefaults_PodTemplate(&podTemplate)
 	rs.Spec.Template = podTemplate.Template
 
+	r.Status.Health = rolloututil.CalculateRolloutHealth(r.Spec, r.Status)
 	f.rolloutLister = append(f.rolloutLister, r)
 	f.objects = append(f.objects, r)
 
@@ -0,0 +1,86 @@
+package rollout
+
+import (
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/validation"
+	listers "github.com/argoproj/argo-rollouts/pkg/client/listers/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+)
+
+// listControllerConfigs returns the RolloutControllerConfigs of a namespace, sorted by name
+func listControllerConfigs(lister listers.RolloutControllerConfigLister, namespace string) ([]*v1alpha1.RolloutControllerConfig, error) {
+	configs, err := lister.RolloutControllerConfigs(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(configs, func(i, j int) bool {
+		return configs[i].Name < configs[j].Name
+	})
+	return configs, nil
+}
+
+// mergeRolloutDefaults merges the defaults of the RolloutControllerConfigs of a namespace, where the first config
+// setting a default takes precedence. It returns nil when none of the configs sets a default
+func mergeRolloutDefaults(configs []*v1alpha1.RolloutControllerConfig) *v1alpha1.RolloutDefaults {
+	var merged *v1alpha1.RolloutDefaults
+	for i := len(configs) - 1; i >= 0; i-- {
+		d := configs[i].Spec.Defaults
+		if d == nil {
+			continue
+		}
+		if merged == nil {
+			merged = &v1alpha1.RolloutDefaults{}
+		}
+		if d.ProgressDeadlineSeconds != nil {
+			merged.ProgressDeadlineSeconds = d.ProgressDeadlineSeconds
+		}
+		if d.RevisionHistoryLimit != nil {
+			merged.RevisionHistoryLimit = d.RevisionHistoryLimit
+		}
+		if d.ScaleDownDelaySeconds != nil {
+			merged.ScaleDownDelaySeconds = d.ScaleDownDelaySeconds
+		}
+	}
+	return merged
+}
+
+// syncControllerConfigs applies the defaults of the RolloutControllerConfigs of a namespace, and enqueues the rollouts
+// of the namespace so that they are reconciled with the changed defaults and restrictions
+func (c *Controller) syncControllerConfigs(namespace string) {
+	logCtx := log.WithField("namespace", namespace)
+	configs, err := listControllerConfigs(c.controllerConfigLister, namespace)
+	if err != nil {
+		logCtx.Warnf("Failed to list RolloutControllerConfigs: %v", err)
+		return
+	}
+	defaults.SetNamespaceDefaults(namespace, mergeRolloutDefaults(configs))
+	rollouts, err := c.rolloutsLister.Rollouts(namespace).List(labels.Everything())
+	if err != nil {
+		logCtx.Warnf("Failed to list rollouts: %v", err)
+		return
+	}
+	logCtx.Infof("RolloutControllerConfigs changed, enqueueing %d rollouts", len(rollouts))
+	for _, ro := range rollouts {
+		c.enqueueRollout(ro)
+	}
+}
+
+// getRestrictionErrors returns the first violation of the restrictions of the RolloutControllerConfigs of the
+// namespace of the rollout
+func (c *rolloutContext) getRestrictionErrors() error {
+	configs, err := listControllerConfigs(c.controllerConfigLister, c.rollout.Namespace)
+	if err != nil {
+		return err
+	}
+	for _, config := range configs {
+		if errs := validation.ValidateRolloutRestrictions(c.rollout, config); len(errs) > 0 {
+			return errs[0]
+		}
+	}
+	return nil
+}
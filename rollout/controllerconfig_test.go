@@ -0,0 +1,82 @@
+package rollout
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/validation"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+)
+
+func newControllerConfig(name string, spec v1alpha1.RolloutControllerConfigSpec) *v1alpha1.RolloutControllerConfig {
+	return &v1alpha1.RolloutControllerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceDefault},
+		Spec:       spec,
+	}
+}
+
+func TestMergeRolloutDefaults(t *testing.T) {
+	assert.Nil(t, mergeRolloutDefaults(nil))
+	assert.Nil(t, mergeRolloutDefaults([]*v1alpha1.RolloutControllerConfig{newControllerConfig("a", v1alpha1.RolloutControllerConfigSpec{})}))
+
+	merged := mergeRolloutDefaults([]*v1alpha1.RolloutControllerConfig{
+		newControllerConfig("a", v1alpha1.RolloutControllerConfigSpec{Defaults: &v1alpha1.RolloutDefaults{
+			ProgressDeadlineSeconds: ptr.To[int32](1200),
+		}}),
+		newControllerConfig("b", v1alpha1.RolloutControllerConfigSpec{Defaults: &v1alpha1.RolloutDefaults{
+			ProgressDeadlineSeconds: ptr.To[int32](300),
+			RevisionHistoryLimit:    ptr.To[int32](3),
+		}}),
+	})
+	// the first config setting a default takes precedence
+	assert.Equal(t, &v1alpha1.RolloutDefaults{
+		ProgressDeadlineSeconds: ptr.To[int32](1200),
+		RevisionHistoryLimit:    ptr.To[int32](3),
+	}, merged)
+}
+
+func TestSyncControllerConfigs(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	r := newCanaryRollout("foo", 1, nil, nil, nil, intstr.FromInt(0), intstr.FromInt(1))
+	f.rolloutLister = append(f.rolloutLister, r)
+	f.objects = append(f.objects, r)
+	f.controllerConfigLister = append(f.controllerConfigLister, newControllerConfig("team", v1alpha1.RolloutControllerConfigSpec{
+		Defaults: &v1alpha1.RolloutDefaults{ProgressDeadlineSeconds: ptr.To[int32](1200)},
+	}))
+	c, _, _ := f.newController(noResyncPeriodFunc)
+	defer defaults.SetNamespaceDefaults(r.Namespace, nil)
+
+	c.syncControllerConfigs(r.Namespace)
+	assert.Equal(t, int32(1200), defaults.GetProgressDeadlineSecondsOrDefault(r))
+	assert.Equal(t, 1, f.enqueuedObjects[getKey(r, t)])
+}
+
+func TestRolloutRestrictionsInvalidSpec(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	steps := []v1alpha1.CanaryStep{{SetWeight: ptr.To[int32](50)}}
+	r := newCanaryRollout("foo", 1, nil, steps, nil, intstr.FromInt(0), intstr.FromInt(1))
+	f.rolloutLister = append(f.rolloutLister, r)
+	f.objects = append(f.objects, r)
+	f.controllerConfigLister = append(f.controllerConfigLister, newControllerConfig("team", v1alpha1.RolloutControllerConfigSpec{
+		Restrictions: &v1alpha1.RolloutRestrictions{MaxCanaryWeightJump: ptr.To[int32](25)},
+	}))
+	c, _, _ := f.newController(noResyncPeriodFunc)
+
+	_, err := c.newRolloutContext(r)
+	var fieldErr *field.Error
+	require.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "spec.strategy.canary.steps[0].setWeight", fieldErr.Field)
+	assert.Equal(t, fmt.Sprintf(validation.ForbiddenCanaryWeightJumpMessage, 50, 25, "team"), fieldErr.Detail)
+}
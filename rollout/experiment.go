@@ -8,16 +8,17 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	analysisutil "github.com/argoproj/argo-rollouts/utils/analysis"
 	"github.com/argoproj/argo-rollouts/utils/annotations"
+	controllerutil "github.com/argoproj/argo-rollouts/utils/controller"
 	"github.com/argoproj/argo-rollouts/utils/defaults"
 	experimentutil "github.com/argoproj/argo-rollouts/utils/experiment"
 	"github.com/argoproj/argo-rollouts/utils/hash"
 	"github.com/argoproj/argo-rollouts/utils/record"
 	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
+	"github.com/argoproj/argo-rollouts/utils/sharding"
 )
 
 // GetExperimentFromTemplate takes the canary experiment step and converts it to an experiment
@@ -58,6 +59,7 @@ func GetExperimentFromTemplate(r *v1alpha1.Rollout, stableRS, newRS *appsv1.Repl
 			DryRun:                  step.DryRun,
 			AnalysisRunMetadata:     step.AnalysisRunMetadata,
 			ScaleDownDelaySeconds:   step.ScaleDownDelaySeconds,
+			WinnerSelection:         step.WinnerSelection.DeepCopy(),
 		},
 	}
 
@@ -65,14 +67,16 @@ func GetExperimentFromTemplate(r *v1alpha1.Rollout, stableRS, newRS *appsv1.Repl
 	if instanceID != "" {
 		experiment.Labels[v1alpha1.LabelKeyControllerInstanceID] = instanceID
 	}
+	sharding.PropagateShard(r, experiment)
 
 	for i := range step.Templates {
 		templateStep := step.Templates[i]
 		template := v1alpha1.TemplateSpec{
-			Name:     templateStep.Name,
-			Replicas: templateStep.Replicas,
+			Name:        templateStep.Name,
+			Replicas:    templateStep.Replicas,
+			Autoscaling: templateStep.Autoscaling.DeepCopy(),
 		}
-		if templateStep.Weight != nil || templateStep.Service != nil {
+		if templateStep.Weight != nil || templateStep.Service != nil || templateStep.HeaderRoute != nil {
 			template.Service = &v1alpha1.TemplateService{}
 			// Need to check if Service is not nil for the case where Weight is not nil and Service is
 			if templateStep.Service != nil && templateStep.Service.Name != "" {
@@ -88,6 +92,13 @@ func GetExperimentFromTemplate(r *v1alpha1.Rollout, stableRS, newRS *appsv1.Repl
 		default:
 			return nil, fmt.Errorf("Invalid template step SpecRef: must be canary or stable")
 		}
+		if templateStep.Patch != "" {
+			patchedTemplate, err := replicasetutil.ApplyPodTemplateOverlay(&templateRS.Spec.Template, &v1alpha1.PodTemplateOverlay{Patch: templateStep.Patch})
+			if err != nil {
+				return nil, fmt.Errorf("failed to patch experiment template '%s': %w", templateStep.Name, err)
+			}
+			templateRS.Spec.Template = *patchedTemplate
+		}
 		template.Template = templateRS.Spec.Template
 		template.MinReadySeconds = templateRS.Spec.MinReadySeconds
 
@@ -140,17 +151,13 @@ func GetExperimentFromTemplate(r *v1alpha1.Rollout, stableRS, newRS *appsv1.Repl
 // getExperimentsForRollout get all experiments owned by the Rollout
 // changing steps in the Rollout Spec would cause multiple experiments to exist which is why it returns an array
 func (c *Controller) getExperimentsForRollout(rollout *v1alpha1.Rollout) ([]*v1alpha1.Experiment, error) {
-	experiments, err := c.experimentsLister.Experiments(rollout.Namespace).List(labels.Everything())
+	experiments, err := controllerutil.ByOwner(c.experimentsIndexer, rollout.Namespace, rollout.UID, false)
 	if err != nil {
 		return nil, err
 	}
 	ownedByRollout := make([]*v1alpha1.Experiment, 0)
 	for i := range experiments {
-		e := experiments[i]
-		controllerRef := metav1.GetControllerOf(e)
-		if controllerRef != nil && controllerRef.UID == rollout.UID {
-			ownedByRollout = append(ownedByRollout, e)
-		}
+		ownedByRollout = append(ownedByRollout, experiments[i].(*v1alpha1.Experiment))
 	}
 	return ownedByRollout, nil
 }
@@ -168,7 +175,9 @@ func (c *rolloutContext) reconcileExperiments() error {
 	step, stepIdx := replicasetutil.GetCurrentCanaryStep(c.rollout)
 
 	currentEx := c.currentEx
-	if step != nil && step.Experiment != nil {
+	if step != nil && step.Experiment != nil && step.Experiment.PromoteWinner && experimentWinnerPromoted(c.rollout) {
+		c.log.Infof("Skipping experiment step (stepIndex: %d): the rollout was updated to the winner of its experiment", *stepIdx)
+	} else if step != nil && step.Experiment != nil {
 		c.log.Infof("Reconciling experiment step (stepIndex: %d)", *stepIdx)
 		if currentEx == nil {
 			// An new experiment can not be created if the stableRS is not created yet
@@ -199,6 +208,17 @@ func (c *rolloutContext) reconcileExperiments() error {
 			c.pauseContext.AddAbort(message)
 		case v1alpha1.AnalysisPhaseSuccessful:
 			// Do not set current Experiment after successful experiment
+			if step.Experiment.PromoteWinner {
+				promoted, err := c.promoteExperimentWinner(step.Experiment, currentEx)
+				if err != nil {
+					return err
+				}
+				if promoted {
+					// the experiment stays current until the update to its winner is rolled out
+					c.promotingExperimentWinner = true
+					c.SetCurrentExperiment(currentEx)
+				}
+			}
 		default:
 			c.SetCurrentExperiment(currentEx)
 		}
@@ -13,7 +13,10 @@ import (
 	"k8s.io/utils/ptr"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/annotations"
 	"github.com/argoproj/argo-rollouts/utils/conditions"
+	"github.com/argoproj/argo-rollouts/utils/hash"
+	"github.com/argoproj/argo-rollouts/utils/sharding"
 	timeutil "github.com/argoproj/argo-rollouts/utils/time"
 )
 
@@ -578,6 +581,30 @@ func TestGetExperimentFromTemplate(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestGetExperimentFromTemplatePropagatesShard(t *testing.T) {
+	steps := []v1alpha1.CanaryStep{{
+		Experiment: &v1alpha1.RolloutExperimentStep{
+			Templates: []v1alpha1.RolloutExperimentTemplate{{
+				Name:     "canary-template",
+				SpecRef:  v1alpha1.CanarySpecRef,
+				Replicas: ptr.To[int32](1),
+			}},
+		},
+	}}
+	r1 := newCanaryRollout("foo", 1, nil, steps, ptr.To[int32](0), intstr.FromInt(0), intstr.FromInt(1))
+	r1.Labels = map[string]string{v1alpha1.LabelKeyControllerShard: "2"}
+	r2 := bumpVersion(r1)
+	rs1 := newReplicaSetWithStatus(r1, 1, 1)
+	rs2 := newReplicaSetWithStatus(r2, 1, 1)
+	r2.Status.CurrentStepIndex = ptr.To[int32](0)
+
+	ex, err := GetExperimentFromTemplate(r2, rs1, rs2)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", ex.Labels[v1alpha1.LabelKeyControllerShard])
+	// the experiment is hashed onto the shard of its rollout
+	assert.Equal(t, sharding.ShardKey(r2), sharding.ShardKey(ex))
+}
+
 func TestGetExperimentFromTemplateModifiedLabelsDoesntChangeRefReplicatSet(t *testing.T) {
 	steps := []v1alpha1.CanaryStep{{
 		Experiment: &v1alpha1.RolloutExperimentStep{
@@ -983,3 +1010,194 @@ func TestRolloutCreateExperimentWithDryRunAndMetadata(t *testing.T) {
 	assert.Equal(t, createdEx.Spec.DryRun[0].MetricName, "someMetric")
 	assert.Equal(t, createdEx.Spec.DryRun[1].MetricName, "someOtherMetric")
 }
+
+func TestGetExperimentFromTemplateWithWinnerSelectionAndPatch(t *testing.T) {
+	selection := &v1alpha1.ExperimentWinnerSelection{
+		Candidates: []v1alpha1.ExperimentWinnerCandidate{{TemplateName: "canary-large", AnalysisName: "canary-large"}},
+	}
+	steps := []v1alpha1.CanaryStep{{
+		Experiment: &v1alpha1.RolloutExperimentStep{
+			Templates: []v1alpha1.RolloutExperimentTemplate{{
+				Name:    "canary-large",
+				SpecRef: v1alpha1.CanarySpecRef,
+				Patch:   `{"spec":{"containers":[{"name":"foo","image":"bar:large"}]}}`,
+			}},
+			WinnerSelection: selection,
+		},
+	}}
+
+	r1 := newCanaryRollout("foo", 1, nil, steps, ptr.To[int32](0), intstr.FromInt(0), intstr.FromInt(1))
+	r2 := bumpVersion(r1)
+	rs1 := newReplicaSetWithStatus(r1, 1, 1)
+	rs2 := newReplicaSetWithStatus(r2, 1, 1)
+	canaryRsTemplate := rs2.Spec.Template.DeepCopy()
+
+	ex, err := GetExperimentFromTemplate(r2, rs1, rs2)
+	assert.NoError(t, err)
+	assert.Equal(t, selection, ex.Spec.WinnerSelection)
+	assert.Equal(t, "bar:large", ex.Spec.Templates[0].Template.Spec.Containers[0].Image)
+	assert.Equal(t, canaryRsTemplate, &rs2.Spec.Template)
+
+	r2.Spec.Strategy.Canary.Steps[0].Experiment.Templates[0].Patch = `{"spec":{"containers":"invalid"}}`
+	_, err = GetExperimentFromTemplate(r2, rs1, rs2)
+	assert.ErrorContains(t, err, "failed to patch experiment template 'canary-large'")
+}
+
+func TestGetExperimentFromTemplateWithAutoscaling(t *testing.T) {
+	autoscaling := &v1alpha1.TemplateAutoscaling{
+		MinReplicas:                    ptr.To[int32](2),
+		MaxReplicas:                    10,
+		TargetCPUUtilizationPercentage: ptr.To[int32](60),
+	}
+	steps := []v1alpha1.CanaryStep{{
+		Experiment: &v1alpha1.RolloutExperimentStep{
+			Templates: []v1alpha1.RolloutExperimentTemplate{{
+				Name:        "canary",
+				SpecRef:     v1alpha1.CanarySpecRef,
+				Replicas:    ptr.To[int32](2),
+				Autoscaling: autoscaling,
+			}},
+		},
+	}}
+
+	r1 := newCanaryRollout("foo", 1, nil, steps, ptr.To[int32](0), intstr.FromInt(0), intstr.FromInt(1))
+	r2 := bumpVersion(r1)
+	rs1 := newReplicaSetWithStatus(r1, 1, 1)
+	rs2 := newReplicaSetWithStatus(r2, 1, 1)
+
+	ex, err := GetExperimentFromTemplate(r2, rs1, rs2)
+	assert.NoError(t, err)
+	assert.Equal(t, autoscaling, ex.Spec.Templates[0].Autoscaling)
+}
+
+func newPromoteWinnerSteps() []v1alpha1.CanaryStep {
+	return []v1alpha1.CanaryStep{{
+		Experiment: &v1alpha1.RolloutExperimentStep{
+			Templates: []v1alpha1.RolloutExperimentTemplate{{
+				Name:    "canary",
+				SpecRef: v1alpha1.CanarySpecRef,
+			}, {
+				Name:    "canary-large",
+				SpecRef: v1alpha1.CanarySpecRef,
+				Patch:   `{"spec":{"containers":[{"name":"foo","image":"bar:large"}]}}`,
+			}},
+			WinnerSelection: &v1alpha1.ExperimentWinnerSelection{
+				Candidates: []v1alpha1.ExperimentWinnerCandidate{
+					{TemplateName: "canary", AnalysisName: "canary"},
+					{TemplateName: "canary-large", AnalysisName: "canary-large"},
+				},
+			},
+			PromoteWinner: true,
+		},
+	}}
+}
+
+func TestRolloutPromoteExperimentWinner(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	r1 := newCanaryRollout("foo", 1, nil, newPromoteWinnerSteps(), ptr.To[int32](0), intstr.FromInt(0), intstr.FromInt(1))
+	r2 := bumpVersion(r1)
+
+	rs1 := newReplicaSetWithStatus(r1, 1, 1)
+	rs2 := newReplicaSetWithStatus(r2, 0, 0)
+	f.kubeobjects = append(f.kubeobjects, rs1, rs2)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+
+	r2 = updateCanaryRolloutStatus(r2, rs1PodHash, 1, 0, 1, false)
+
+	ex, _ := GetExperimentFromTemplate(r2, rs1, rs2)
+	ex.Status.Phase = v1alpha1.AnalysisPhaseSuccessful
+	ex.Status.Winner = "canary-large"
+	now := metav1.Now()
+	ex.Status.AvailableAt = &now
+	r2.Status.Canary.CurrentExperiment = ex.Name
+
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.experimentLister = append(f.experimentLister, ex)
+	f.objects = append(f.objects, r2, ex)
+
+	updateIndex := f.expectUpdateRolloutAction(r2)
+	f.expectPatchRolloutAction(r2)
+	f.run(getKey(r2, t))
+	updatedRollout := f.getUpdatedRollout(updateIndex)
+	assert.Equal(t, "bar:large", updatedRollout.Spec.Template.Spec.Containers[0].Image)
+	assert.True(t, experimentWinnerPromoted(updatedRollout))
+}
+
+func TestRolloutPromoteExperimentWinnerCanaryWon(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	r1 := newCanaryRollout("foo", 1, nil, newPromoteWinnerSteps(), ptr.To[int32](0), intstr.FromInt(0), intstr.FromInt(1))
+	r2 := bumpVersion(r1)
+
+	rs1 := newReplicaSetWithStatus(r1, 1, 1)
+	rs2 := newReplicaSetWithStatus(r2, 0, 0)
+	f.kubeobjects = append(f.kubeobjects, rs1, rs2)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+
+	r2 = updateCanaryRolloutStatus(r2, rs1PodHash, 1, 0, 1, false)
+
+	ex, _ := GetExperimentFromTemplate(r2, rs1, rs2)
+	ex.Status.Phase = v1alpha1.AnalysisPhaseSuccessful
+	ex.Status.Winner = "canary"
+	now := metav1.Now()
+	ex.Status.AvailableAt = &now
+	r2.Status.Canary.CurrentExperiment = ex.Name
+
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.experimentLister = append(f.experimentLister, ex)
+	f.objects = append(f.objects, r2, ex)
+
+	patchIndex := f.expectPatchRolloutAction(r2)
+	f.run(getKey(r2, t))
+	patchedRollout := f.getPatchedRolloutAsObject(patchIndex)
+	assert.Equal(t, int32(1), *patchedRollout.Status.CurrentStepIndex)
+	assert.Empty(t, patchedRollout.Status.Canary.CurrentExperiment)
+}
+
+func TestExperimentWinnerPromoted(t *testing.T) {
+	ro := newCanaryRollout("foo", 1, nil, newPromoteWinnerSteps(), ptr.To[int32](0), intstr.FromInt(0), intstr.FromInt(1))
+	assert.False(t, experimentWinnerPromoted(ro))
+
+	ro.Annotations[annotations.PromotedExperimentWinnerAnnotation] = promotedTemplateHash(&ro.Spec.Template)
+	assert.True(t, experimentWinnerPromoted(ro))
+
+	// a collision while the promoted template is rolled out does not run the experiment step again
+	ro.Status.CollisionCount = ptr.To[int32](1)
+	assert.True(t, experimentWinnerPromoted(ro))
+
+	// the pod template was changed since it was promoted, e.g. reverted by a GitOps tool
+	ro.Spec.Template.Spec.Containers[0].Image = "bar:reverted"
+	assert.False(t, experimentWinnerPromoted(ro))
+}
+
+func TestRolloutSkipExperimentStepAfterWinnerPromoted(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	steps := append(newPromoteWinnerSteps(), v1alpha1.CanaryStep{Pause: &v1alpha1.RolloutPause{}})
+	r1 := newCanaryRollout("foo", 1, nil, steps, ptr.To[int32](0), intstr.FromInt(0), intstr.FromInt(1))
+	r2 := bumpVersion(r1)
+	r2.Annotations[annotations.PromotedExperimentWinnerAnnotation] = hash.ComputePodTemplateHash(&r2.Spec.Template, nil)
+
+	rs1 := newReplicaSetWithStatus(r1, 1, 1)
+	rs2 := newReplicaSetWithStatus(r2, 0, 0)
+	f.kubeobjects = append(f.kubeobjects, rs1, rs2)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+
+	r2 = updateCanaryRolloutStatus(r2, rs1PodHash, 1, 0, 1, false)
+
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.objects = append(f.objects, r2)
+
+	f.expectUpdateReplicaSetAction(rs2)
+	patchIndex := f.expectPatchRolloutAction(r2)
+	f.run(getKey(r2, t))
+	patchedRollout := f.getPatchedRolloutAsObject(patchIndex)
+	assert.Equal(t, int32(1), *patchedRollout.Status.CurrentStepIndex)
+}
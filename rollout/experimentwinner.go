@@ -0,0 +1,70 @@
+package rollout
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/annotations"
+	"github.com/argoproj/argo-rollouts/utils/hash"
+	"github.com/argoproj/argo-rollouts/utils/record"
+	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
+)
+
+// experimentWinnerPromoted returns true if the rollout was updated to the pod template which won the experiment of
+// an experiment step. The update skips the experiment steps which promote their winner.
+func experimentWinnerPromoted(rollout *v1alpha1.Rollout) bool {
+	promotedHash, ok := rollout.Annotations[annotations.PromotedExperimentWinnerAnnotation]
+	return ok && promotedHash == promotedTemplateHash(&rollout.Spec.Template)
+}
+
+// promotedTemplateHash returns the hash of a pod template promoted by an experiment step. Unlike the pod template
+// hash of the ReplicaSets, it does not depend on the collision count of the rollout, which would run the experiment
+// step again if it was bumped while the promoted template is rolled out.
+func promotedTemplateHash(template *corev1.PodTemplateSpec) string {
+	return hash.ComputePodTemplateHash(template, nil)
+}
+
+// promoteExperimentWinner updates the pod template of the rollout with the patch of the template which won the
+// experiment of the step. Returns true if the rollout was updated, which is rolled out as a new revision.
+func (c *rolloutContext) promoteExperimentWinner(step *v1alpha1.RolloutExperimentStep, ex *v1alpha1.Experiment) (bool, error) {
+	if ex.Status.Winner == "" {
+		return false, nil
+	}
+	var winner *v1alpha1.RolloutExperimentTemplate
+	for i := range step.Templates {
+		if step.Templates[i].Name == ex.Status.Winner {
+			winner = &step.Templates[i]
+		}
+	}
+	if winner == nil || winner.Patch == "" {
+		// the canary itself won the experiment
+		return false, nil
+	}
+	template, err := replicasetutil.ApplyPodTemplateOverlay(&c.rollout.Spec.Template, &v1alpha1.PodTemplateOverlay{Patch: winner.Patch})
+	if err != nil {
+		return false, err
+	}
+	if equality.Semantic.DeepEqual(*template, c.rollout.Spec.Template) {
+		return false, nil
+	}
+
+	ro := c.rollout.DeepCopy()
+	ro.Spec.Template = *template
+	if ro.Annotations == nil {
+		ro.Annotations = map[string]string{}
+	}
+	ro.Annotations[annotations.PromotedExperimentWinnerAnnotation] = promotedTemplateHash(template)
+	updatedRollout, err := c.argoprojclientset.ArgoprojV1alpha1().Rollouts(ro.Namespace).Update(context.TODO(), ro, metav1.UpdateOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to promote the winner of experiment '%s': %w", ex.Name, err)
+	}
+	c.log.Infof("Updated the pod template to template '%s' which won experiment '%s'", winner.Name, ex.Name)
+	c.recorder.Eventf(c.rollout, record.EventOptions{EventReason: "ExperimentWinnerPromoted"}, "Updated the pod template to template '%s' which won Experiment '%s'", winner.Name, ex.Name)
+	c.newRollout = updatedRollout
+	return true, nil
+}
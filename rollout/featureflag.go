@@ -0,0 +1,104 @@
+package rollout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	"github.com/argoproj/argo-rollouts/utils/featureflag"
+	"github.com/argoproj/argo-rollouts/utils/record"
+)
+
+const (
+	// featureFlagRetryPeriod is the period at which setting a feature flag which failed is retried
+	featureFlagRetryPeriod = 30 * time.Second
+	// featureFlagTimeout is the timeout of the requests setting a feature flag
+	featureFlagTimeout = 30 * time.Second
+)
+
+// reconcileFeatureFlags sets the LaunchDarkly flags of the setFeatureFlag steps to their percentages at the current
+// step of the rollout (see featureflag.Percentage), and records the percentages the flags were set to. The flags are
+// set in the background, and the rollout is reconciled again once they are set. The OpenFeature flags need no
+// reconciliation, since the controller evaluates them from the rollout itself. It records whether all flags are set,
+// which completes a setFeatureFlag step.
+func (c *rolloutContext) reconcileFeatureFlags() error {
+	flags := featureflag.Flags(c.rollout)
+	c.featureFlagsSynced = true
+	if len(flags) == 0 && len(c.rollout.Status.Canary.FeatureFlags) == 0 {
+		return nil
+	}
+	recorded := map[string]int32{}
+	for _, status := range c.rollout.Status.Canary.FeatureFlags {
+		recorded[status.Flag] = status.Percentage
+	}
+
+	c.featureFlags = []v1alpha1.FeatureFlagStatus{}
+	failed := false
+	for _, key := range featureflag.Keys(c.rollout) {
+		launchDarkly := flags[key].LaunchDarkly
+		if launchDarkly == nil {
+			continue
+		}
+		percentage, _ := featureflag.Percentage(c.rollout, key)
+		if last, ok := recorded[key]; ok && last == percentage {
+			c.featureFlags = append(c.featureFlags, v1alpha1.FeatureFlagStatus{Flag: key, Percentage: percentage})
+			continue
+		}
+		err := c.setLaunchDarklyFlag(key, launchDarkly, percentage)
+		if err == nil {
+			c.recorder.Eventf(c.rollout, record.EventOptions{EventReason: conditions.FeatureFlagSetReason}, conditions.FeatureFlagSetMessage, key, percentage)
+			c.featureFlags = append(c.featureFlags, v1alpha1.FeatureFlagStatus{Flag: key, Percentage: percentage})
+			continue
+		}
+		c.featureFlagsSynced = false
+		if !errors.Is(err, errFeatureFlagPending) {
+			c.recorder.Warnf(c.rollout, record.EventOptions{EventReason: conditions.FeatureFlagErrorReason}, conditions.FeatureFlagErrorMessage, key, percentage, err)
+			failed = true
+		}
+		// the flag keeps its recorded percentage, so that it is set again
+		if last, ok := recorded[key]; ok {
+			c.featureFlags = append(c.featureFlags, v1alpha1.FeatureFlagStatus{Flag: key, Percentage: last})
+		}
+	}
+	if failed {
+		c.enqueueRolloutAfter(c.rollout, featureFlagRetryPeriod)
+	}
+	return nil
+}
+
+// errFeatureFlagPending is returned while a flag is being set in the background
+var errFeatureFlagPending = errors.New("feature flag is being set")
+
+// setLaunchDarklyFlag sets the percentage rollout of the LaunchDarkly flag with the API token of the flag. The token
+// is read right away, and the flag is set in the background. It returns errFeatureFlagPending until the flag was set
+// to the percentage.
+func (c *rolloutContext) setLaunchDarklyFlag(key string, flag *v1alpha1.LaunchDarklyFeatureFlag, percentage int32) error {
+	// the flag is set by one task at a time, so that a task setting an outdated percentage cannot complete last
+	taskKey := fmt.Sprintf("%s/%s/launchdarkly/%s", c.rollout.Namespace, c.rollout.Name, key)
+	if set, done, err := c.featureFlagUpdates.result(taskKey); done && set == percentage {
+		return err
+	}
+	token, err := c.getSecretKey(context.TODO(), flag.APITokenSecretRef)
+	if err != nil {
+		return err
+	}
+	client := featureflag.LaunchDarklyClient{
+		APIURL:     flag.APIURL,
+		APIToken:   string(token),
+		HTTPClient: &http.Client{Timeout: featureFlagTimeout},
+	}
+	projectKey, environmentKey := flag.ProjectKey, flag.EnvironmentKey
+	rollout, enqueueRollout := c.rollout, c.enqueueRollout
+	c.featureFlagUpdates.start(taskKey, func() (int32, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), featureFlagTimeout)
+		defer cancel()
+		return percentage, client.SetPercentage(ctx, projectKey, environmentKey, key, percentage)
+	}, func() {
+		enqueueRollout(rollout)
+	})
+	return errFeatureFlagPending
+}
@@ -0,0 +1,143 @@
+package rollout
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	"github.com/argoproj/argo-rollouts/utils/record"
+)
+
+// newLaunchDarklyServer returns a fake LaunchDarkly API which records the percentages the flags are set to
+func newLaunchDarklyServer(t *testing.T) (*httptest.Server, func() []int32) {
+	var mu sync.Mutex
+	var percentages []int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`{"variations": [{"_id": "on", "value": true}, {"_id": "off", "value": false}]}`))
+			return
+		}
+		var patch struct {
+			Instructions []struct {
+				RolloutWeights map[string]int32 `json:"rolloutWeights"`
+			} `json:"instructions"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&patch))
+		mu.Lock()
+		defer mu.Unlock()
+		percentages = append(percentages, patch.Instructions[0].RolloutWeights["on"]/1000)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []int32 {
+		mu.Lock()
+		defer mu.Unlock()
+		return percentages
+	}
+}
+
+// newFeatureFlagContext returns the context of the rollout, which sets the flags with the given background tasks, and
+// a channel which receives the requeues of the rollout once a flag was set
+func newFeatureFlagContext(ro *v1alpha1.Rollout, client *k8sfake.Clientset, tasks *backgroundTasks[int32]) (*rolloutContext, *record.FakeEventRecorder, chan struct{}) {
+	ctx, recorder := newLoadTestContext(ro, true, client)
+	ctx.featureFlagUpdates = tasks
+	enqueued := make(chan struct{}, 1)
+	ctx.enqueueRollout = func(obj any) { enqueued <- struct{}{} }
+	return ctx, recorder, enqueued
+}
+
+func waitEnqueued(t *testing.T, enqueued chan struct{}) {
+	t.Helper()
+	select {
+	case <-enqueued:
+	case <-time.After(10 * time.Second):
+		t.Fatal("rollout was not requeued")
+	}
+}
+
+func TestReconcileFeatureFlags(t *testing.T) {
+	server, percentages := newLaunchDarklyServer(t)
+	launchDarkly := &v1alpha1.LaunchDarklyFeatureFlag{
+		ProjectKey:        "default",
+		EnvironmentKey:    "production",
+		APITokenSecretRef: v1alpha1.SecretKeyRef{Name: "launchdarkly", Key: "token"},
+		APIURL:            server.URL,
+	}
+	steps := []v1alpha1.CanaryStep{
+		{SetWeight: ptr.To[int32](20)},
+		{SetFeatureFlag: &v1alpha1.SetFeatureFlag{Flag: "new-checkout", LaunchDarkly: launchDarkly}},
+		{Pause: &v1alpha1.RolloutPause{}},
+	}
+	ro := newCanaryRollout("foo", 5, nil, steps, ptr.To[int32](1), intstr.FromInt(1), intstr.FromInt(0))
+	client := k8sfake.NewSimpleClientset()
+	tasks := newBackgroundTasks[int32]()
+	ctx, recorder, _ := newFeatureFlagContext(ro, client, tasks)
+
+	// the step is not completed while the token is missing
+	assert.NoError(t, ctx.reconcileFeatureFlags())
+	assert.False(t, ctx.featureFlagsSynced)
+	assert.Empty(t, ctx.featureFlags)
+	assert.Equal(t, []string{conditions.FeatureFlagErrorReason}, recorder.Events())
+
+	_, err := client.CoreV1().Secrets(ro.Namespace).Create(t.Context(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "launchdarkly", Namespace: ro.Namespace},
+		Data:       map[string][]byte{"token": []byte("api-token")},
+	}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	// the flag is set in the background, and the rollout is requeued once it is set
+	ctx, recorder, enqueued := newFeatureFlagContext(ro, client, tasks)
+	assert.NoError(t, ctx.reconcileFeatureFlags())
+	assert.False(t, ctx.featureFlagsSynced)
+	assert.Empty(t, ctx.featureFlags)
+	assert.Empty(t, recorder.Events())
+	waitEnqueued(t, enqueued)
+	assert.Equal(t, []int32{20}, percentages())
+	assert.NoError(t, ctx.reconcileFeatureFlags())
+	assert.True(t, ctx.featureFlagsSynced)
+	assert.Equal(t, []v1alpha1.FeatureFlagStatus{{Flag: "new-checkout", Percentage: 20}}, ctx.featureFlags)
+	assert.Equal(t, []string{conditions.FeatureFlagSetReason}, recorder.Events())
+
+	// the flag is not set again at the same percentage
+	ro.Status.Canary.FeatureFlags = ctx.featureFlags
+	ctx, _, _ = newFeatureFlagContext(ro, client, tasks)
+	assert.NoError(t, ctx.reconcileFeatureFlags())
+	assert.True(t, ctx.featureFlagsSynced)
+	assert.Equal(t, []int32{20}, percentages())
+
+	// the flag is restored to its stable percentage when the update is aborted
+	ro.Status.Abort = true
+	ro.Status.Canary.StableFeatureFlags = []string{"new-checkout"}
+	ctx, _, enqueued = newFeatureFlagContext(ro, client, tasks)
+	assert.NoError(t, ctx.reconcileFeatureFlags())
+	assert.Equal(t, []v1alpha1.FeatureFlagStatus{{Flag: "new-checkout", Percentage: 20}}, ctx.featureFlags)
+	waitEnqueued(t, enqueued)
+	assert.NoError(t, ctx.reconcileFeatureFlags())
+	assert.Equal(t, []v1alpha1.FeatureFlagStatus{{Flag: "new-checkout", Percentage: 100}}, ctx.featureFlags)
+	assert.Equal(t, []int32{20, 100}, percentages())
+}
+
+func TestCompletedSetFeatureFlagStep(t *testing.T) {
+	steps := []v1alpha1.CanaryStep{
+		{SetFeatureFlag: &v1alpha1.SetFeatureFlag{Flag: "new-checkout", OpenFeature: &v1alpha1.OpenFeatureFeatureFlag{}}},
+		{Pause: &v1alpha1.RolloutPause{}},
+	}
+	ro := newCanaryRollout("foo", 5, nil, steps, ptr.To[int32](0), intstr.FromInt(1), intstr.FromInt(0))
+	ctx, recorder := newLoadTestContext(ro, true, k8sfake.NewSimpleClientset())
+	assert.False(t, ctx.completedCurrentCanaryStep())
+	assert.NoError(t, ctx.reconcileFeatureFlags())
+	assert.True(t, ctx.completedCurrentCanaryStep())
+	assert.Empty(t, ctx.featureFlags)
+	assert.Empty(t, recorder.Events())
+}
@@ -0,0 +1,254 @@
+package rollout
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	"github.com/argoproj/argo-rollouts/utils/record"
+	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
+	timeutil "github.com/argoproj/argo-rollouts/utils/time"
+)
+
+const (
+	// defaultGateTimeout, defaultGateInterval and defaultGateRetries are the defaults of a gate step
+	defaultGateTimeout  = 10 * time.Second
+	defaultGateInterval = 30 * time.Second
+	defaultGateRetries  = int32(3)
+	// gateRetryBackoff is the delay before the first retry of a failed request, which doubles with every retry up to
+	// the interval of the gate step
+	gateRetryBackoff = 5 * time.Second
+	// maxGateResponseBytes is the maximum size of the response of a gate webhook
+	maxGateResponseBytes = 1 << 20
+)
+
+// GateRequest is the body of the requests to the webhook of a gate step
+type GateRequest struct {
+	Rollout               GateRolloutRef    `json:"rollout"`
+	Step                  GateStepRef       `json:"step"`
+	PodTemplateHash       string            `json:"podTemplateHash"`
+	StablePodTemplateHash string            `json:"stablePodTemplateHash,omitempty"`
+	Images                []string          `json:"images,omitempty"`
+	Weight                int32             `json:"weight"`
+	Params                map[string]string `json:"params,omitempty"`
+}
+
+// GateRolloutRef identifies the rollout in a gate request
+type GateRolloutRef struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace"`
+	UID         types.UID         `json:"uid"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// GateStepRef identifies the gate step in a gate request
+type GateStepRef struct {
+	Index int32 `json:"index"`
+	Count int32 `json:"count"`
+}
+
+// GateResponse is the body of the responses of the webhook of a gate step
+type GateResponse struct {
+	Decision v1alpha1.GateDecision `json:"decision"`
+	Message  string                `json:"message,omitempty"`
+}
+
+// reconcileGate calls the webhook of the current gate step, and proceeds, pauses or aborts the rollout with its
+// decision. The webhook is called in the background, and the rollout waits at the step until the call completed. A
+// paused rollout calls the webhook again at the interval of the step, until the webhook decides otherwise or the
+// rollout is promoted. Failed requests are retried with a backoff, and the failure policy of the step applies once the
+// retries are exhausted. The status of the gate is only kept while the rollout is at the gate step.
+func (c *rolloutContext) reconcileGate() error {
+	c.gateReconciled = true
+	c.gate = nil
+	step, index := replicasetutil.GetCurrentCanaryStep(c.rollout)
+	if step == nil || step.Gate == nil || c.pauseContext.IsAborted() ||
+		c.newRS == nil || !replicasetutil.CheckStableRSExists(c.newRS, c.stableRS) {
+		return nil
+	}
+	gate := step.Gate
+	podHash := c.newRS.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+
+	c.gate = &v1alpha1.GateStatus{StepIndex: *index, PodTemplateHash: podHash}
+	if last := c.rollout.Status.Canary.Gate; last != nil && last.StepIndex == *index && last.PodTemplateHash == podHash {
+		c.gate = last.DeepCopy()
+	}
+	switch {
+	case c.gate.Decision == v1alpha1.GateDecisionProceed:
+		c.gateCompleted = true
+		return nil
+	case c.gate.Decision == v1alpha1.GateDecisionPause && c.rollout.Status.ControllerPause &&
+		getPauseCondition(c.rollout, v1alpha1.PauseReasonGate) == nil:
+		c.log.Info("Rollout has been promoted past the gate step")
+		c.gateCompleted = true
+		return nil
+	}
+
+	interval := durationOrDefault(gate.Interval, defaultGateInterval)
+	taskKey := fmt.Sprintf("%s/%s/gate/%s/%d", c.rollout.Namespace, c.rollout.Name, podHash, *index)
+	resp, done, err := c.gateCalls.result(taskKey)
+	if !done {
+		if c.gate.LastCheckTime != nil {
+			delay := interval
+			if c.gate.ConsecutiveErrors > 0 && c.gate.Decision != v1alpha1.GateDecisionPause {
+				delay = min(gateRetryBackoff<<(c.gate.ConsecutiveErrors-1), interval)
+			}
+			if remaining := c.gate.LastCheckTime.Add(delay).Sub(timeutil.Now()); remaining > 0 {
+				c.enqueueRolloutAfter(c.rollout, remaining)
+				return nil
+			}
+		}
+		var req *http.Request
+		if req, err = c.newGateRequest(gate, *index, podHash); err == nil {
+			timeout := durationOrDefault(gate.Timeout, defaultGateTimeout)
+			rollout, enqueueRollout := c.rollout, c.enqueueRollout
+			c.gateCalls.start(taskKey, func() (*GateResponse, error) {
+				return callGate(req, timeout)
+			}, func() {
+				enqueueRollout(rollout)
+			})
+			return nil
+		}
+	}
+
+	now := metav1.NewTime(timeutil.Now())
+	c.gate.LastCheckTime = &now
+	if err != nil {
+		c.gate.ConsecutiveErrors++
+		c.gate.Message = err.Error()
+		c.recorder.Warnf(c.rollout, record.EventOptions{EventReason: conditions.GateErrorReason}, conditions.GateErrorMessage, *index+1, err)
+		retries := defaultGateRetries
+		if gate.Retries != nil {
+			retries = *gate.Retries
+		}
+		if c.gate.ConsecutiveErrors <= retries {
+			c.enqueueRolloutAfter(c.rollout, min(gateRetryBackoff<<(c.gate.ConsecutiveErrors-1), interval))
+			return nil
+		}
+		if gate.FailurePolicy == v1alpha1.GateFailurePolicyPause {
+			c.pauseGate(interval)
+			return nil
+		}
+		c.gate.Decision = v1alpha1.GateDecisionAbort
+		c.pauseContext.AddAbort(fmt.Sprintf(conditions.GateAbortedMessage, *index+1, c.gate.Message))
+		return nil
+	}
+
+	lastDecision := c.gate.Decision
+	c.gate.ConsecutiveErrors = 0
+	c.gate.Message = resp.Message
+	switch resp.Decision {
+	case v1alpha1.GateDecisionProceed:
+		c.gate.Decision = resp.Decision
+		c.gateCompleted = true
+		c.pauseContext.RemovePauseCondition(v1alpha1.PauseReasonGate)
+		c.recorder.Eventf(c.rollout, record.EventOptions{EventReason: conditions.GateProceededReason}, conditions.GateProceededMessage, *index+1, resp.Message)
+	case v1alpha1.GateDecisionPause:
+		if lastDecision != v1alpha1.GateDecisionPause {
+			c.recorder.Eventf(c.rollout, record.EventOptions{EventReason: conditions.GatePausedReason}, conditions.GatePausedMessage, *index+1, resp.Message)
+		}
+		c.pauseGate(interval)
+	case v1alpha1.GateDecisionAbort:
+		c.gate.Decision = resp.Decision
+		failure := fmt.Sprintf(conditions.GateAbortedMessage, *index+1, resp.Message)
+		c.recorder.Warnf(c.rollout, record.EventOptions{EventReason: conditions.GateAbortedReason}, "%s", failure)
+		c.pauseContext.AddAbort(failure)
+	}
+	return nil
+}
+
+// pauseGate pauses the rollout at the gate step, and calls the webhook again after the interval
+func (c *rolloutContext) pauseGate(interval time.Duration) {
+	c.gate.Decision = v1alpha1.GateDecisionPause
+	c.pauseContext.AddPauseCondition(v1alpha1.PauseReasonGate)
+	c.enqueueRolloutAfter(c.rollout, interval)
+}
+
+// newGateRequest returns the request to the webhook of the gate step with the context of the rollout. The authorization
+// secret is read right away.
+func (c *rolloutContext) newGateRequest(gate *v1alpha1.RolloutGateStep, index int32, podHash string) (*http.Request, error) {
+	images := []string{}
+	for _, container := range c.newRS.Spec.Template.Spec.Containers {
+		images = append(images, container.Image)
+	}
+	body, err := json.Marshal(GateRequest{
+		Rollout: GateRolloutRef{
+			Name:        c.rollout.Name,
+			Namespace:   c.rollout.Namespace,
+			UID:         c.rollout.UID,
+			Labels:      c.rollout.Labels,
+			Annotations: c.rollout.Annotations,
+		},
+		Step:                  GateStepRef{Index: index, Count: int32(len(c.rollout.Spec.Strategy.Canary.Steps))},
+		PodTemplateHash:       podHash,
+		StablePodTemplateHash: c.rollout.Status.StableRS,
+		Images:                images,
+		Weight:                replicasetutil.GetCurrentSetWeight(c.rollout),
+		Params:                gate.Params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, gate.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for _, header := range gate.Headers {
+		req.Header.Set(header.Key, header.Value)
+	}
+	if gate.AuthorizationSecretRef != nil {
+		authorization, err := c.getSecretKey(context.TODO(), *gate.AuthorizationSecretRef)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", string(authorization))
+	}
+	return req, nil
+}
+
+// callGate sends the request to the webhook of a gate step, and returns its decision. It runs in the background, so
+// that a slow webhook does not block the workers of the controller.
+func callGate(req *http.Request, timeout time.Duration) (*GateResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	resp, err := (&http.Client{Timeout: timeout}).Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxGateResponseBytes))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	var decision GateResponse
+	if err := json.Unmarshal(data, &decision); err != nil {
+		return nil, fmt.Errorf("webhook returned invalid JSON: %w", err)
+	}
+	switch decision.Decision {
+	case v1alpha1.GateDecisionProceed, v1alpha1.GateDecisionPause, v1alpha1.GateDecisionAbort:
+		return &decision, nil
+	}
+	return nil, fmt.Errorf("webhook returned unknown decision %q", decision.Decision)
+}
+
+// durationOrDefault returns the duration, or the default when it is empty or invalid
+func durationOrDefault(duration v1alpha1.DurationString, defaultDuration time.Duration) time.Duration {
+	if d, err := duration.Duration(); duration != "" && err == nil && d > 0 {
+		return d
+	}
+	return defaultDuration
+}
@@ -0,0 +1,237 @@
+package rollout
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	"github.com/argoproj/argo-rollouts/utils/record"
+	timeutil "github.com/argoproj/argo-rollouts/utils/time"
+)
+
+func newGateRollout(gate *v1alpha1.RolloutGateStep) *v1alpha1.Rollout {
+	steps := []v1alpha1.CanaryStep{{SetWeight: ptr.To[int32](20)}, {Gate: gate}, {SetWeight: ptr.To[int32](50)}}
+	return newCanaryRollout("foo", 5, nil, steps, ptr.To[int32](1), intstr.FromInt(1), intstr.FromInt(0))
+}
+
+// newGateServer returns a webhook which responds with the decision, and records the requests
+func newGateServer(t *testing.T, status int, decision v1alpha1.GateDecision, requests *[]GateRequest) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GateRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "token", r.Header.Get("X-Token"))
+		*requests = append(*requests, req)
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(GateResponse{Decision: decision, Message: "change window"})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newGateContext returns the context of the rollout, which calls the webhook with the given background tasks, and a
+// channel which receives the requeues of the rollout once a call completed
+func newGateContext(ro *v1alpha1.Rollout, tasks *backgroundTasks[*GateResponse]) (*rolloutContext, *record.FakeEventRecorder, chan struct{}) {
+	ctx, recorder := newLoadTestContext(ro, true, k8sfake.NewSimpleClientset())
+	ctx.gateCalls = tasks
+	enqueued := make(chan struct{}, 1)
+	ctx.enqueueRollout = func(obj any) { enqueued <- struct{}{} }
+	return ctx, recorder, enqueued
+}
+
+// reconcileGateCall reconciles the gate step, which calls the webhook in the background, and reconciles it again once
+// the call completed. It returns the context which consumed the result of the call.
+func reconcileGateCall(t *testing.T, ro *v1alpha1.Rollout, tasks *backgroundTasks[*GateResponse]) (*rolloutContext, *record.FakeEventRecorder) {
+	t.Helper()
+	ctx, recorder, enqueued := newGateContext(ro, tasks)
+	require.NoError(t, ctx.reconcileGate())
+	assert.False(t, ctx.completedCurrentCanaryStep())
+	assert.Empty(t, recorder.Events())
+	waitEnqueued(t, enqueued)
+	ctx, recorder, _ = newGateContext(ro, tasks)
+	require.NoError(t, ctx.reconcileGate())
+	return ctx, recorder
+}
+
+func TestReconcileGateProceed(t *testing.T) {
+	var requests []GateRequest
+	server := newGateServer(t, http.StatusOK, v1alpha1.GateDecisionProceed, &requests)
+	ro := newGateRollout(&v1alpha1.RolloutGateStep{
+		URL:     server.URL,
+		Headers: []v1alpha1.WebMetricHeader{{Key: "X-Token", Value: "token"}},
+		Params:  map[string]string{"ticket": "CHG-1"},
+	})
+	tasks := newBackgroundTasks[*GateResponse]()
+	ctx, recorder := reconcileGateCall(t, ro, tasks)
+	assert.True(t, ctx.completedCurrentCanaryStep())
+	assert.Equal(t, v1alpha1.GateDecisionProceed, ctx.gate.Decision)
+	assert.Equal(t, []string{conditions.GateProceededReason}, recorder.Events())
+	require.Len(t, requests, 1)
+	assert.Equal(t, "foo", requests[0].Rollout.Name)
+	assert.Equal(t, GateStepRef{Index: 1, Count: 3}, requests[0].Step)
+	assert.Equal(t, ctx.newRS.Labels[v1alpha1.DefaultRolloutUniqueLabelKey], requests[0].PodTemplateHash)
+	assert.Equal(t, int32(20), requests[0].Weight)
+	assert.Equal(t, map[string]string{"ticket": "CHG-1"}, requests[0].Params)
+
+	// the decision is kept until the step completes
+	ro.Status.Canary.Gate = ctx.gate
+	ctx, _, _ = newGateContext(ro, tasks)
+	require.NoError(t, ctx.reconcileGate())
+	assert.True(t, ctx.completedCurrentCanaryStep())
+	assert.Len(t, requests, 1)
+}
+
+func TestReconcileGatePause(t *testing.T) {
+	var requests []GateRequest
+	server := newGateServer(t, http.StatusOK, v1alpha1.GateDecisionPause, &requests)
+	ro := newGateRollout(&v1alpha1.RolloutGateStep{
+		URL:      server.URL,
+		Headers:  []v1alpha1.WebMetricHeader{{Key: "X-Token", Value: "token"}},
+		Interval: "1m",
+	})
+	tasks := newBackgroundTasks[*GateResponse]()
+	ctx, recorder := reconcileGateCall(t, ro, tasks)
+	assert.False(t, ctx.completedCurrentCanaryStep())
+	assert.Equal(t, v1alpha1.GateDecisionPause, ctx.gate.Decision)
+	assert.Equal(t, "change window", ctx.gate.Message)
+	assert.Equal(t, []v1alpha1.PauseReason{v1alpha1.PauseReasonGate}, ctx.pauseContext.addPauseReasons)
+	assert.Equal(t, []string{conditions.GatePausedReason}, recorder.Events())
+
+	// the webhook is not called again before the interval
+	ro.Status.Canary.Gate = ctx.gate
+	ro.Status.ControllerPause = true
+	ro.Status.PauseConditions = []v1alpha1.PauseCondition{{Reason: v1alpha1.PauseReasonGate, StartTime: timeutil.MetaNow()}}
+	ctx, _, _ = newGateContext(ro, tasks)
+	require.NoError(t, ctx.reconcileGate())
+	assert.False(t, ctx.completedCurrentCanaryStep())
+	assert.Len(t, requests, 1)
+
+	// the webhook is called again after the interval, without another event
+	ro.Status.Canary.Gate.LastCheckTime = ptr.To(metav1.NewTime(timeutil.Now().Add(-time.Minute)))
+	ctx, recorder = reconcileGateCall(t, ro, tasks)
+	assert.False(t, ctx.completedCurrentCanaryStep())
+	assert.Len(t, requests, 2)
+	assert.Empty(t, recorder.Events())
+
+	// a promoted rollout proceeds past the gate step
+	ro.Status.PauseConditions = nil
+	ctx, _, _ = newGateContext(ro, tasks)
+	require.NoError(t, ctx.reconcileGate())
+	assert.True(t, ctx.completedCurrentCanaryStep())
+	assert.Len(t, requests, 2)
+}
+
+func TestReconcileGateAbort(t *testing.T) {
+	var requests []GateRequest
+	server := newGateServer(t, http.StatusOK, v1alpha1.GateDecisionAbort, &requests)
+	ro := newGateRollout(&v1alpha1.RolloutGateStep{URL: server.URL, Headers: []v1alpha1.WebMetricHeader{{Key: "X-Token", Value: "token"}}})
+	tasks := newBackgroundTasks[*GateResponse]()
+	ctx, recorder := reconcileGateCall(t, ro, tasks)
+	assert.False(t, ctx.completedCurrentCanaryStep())
+	assert.True(t, ctx.pauseContext.IsAborted())
+	assert.Equal(t, "Gate of step 2 aborted the rollout: change window", ctx.pauseContext.abortMessage)
+	assert.Equal(t, []string{conditions.GateAbortedReason}, recorder.Events())
+
+	// the status of the gate is dropped once the rollout is aborted
+	ro.Status.Abort = true
+	ro.Status.Canary.Gate = ctx.gate
+	ctx, _, _ = newGateContext(ro, tasks)
+	require.NoError(t, ctx.reconcileGate())
+	assert.True(t, ctx.gateReconciled)
+	assert.Nil(t, ctx.gate)
+}
+
+func TestReconcileGateFailurePolicy(t *testing.T) {
+	var requests []GateRequest
+	server := newGateServer(t, http.StatusInternalServerError, "", &requests)
+	for _, policy := range []v1alpha1.GateFailurePolicy{v1alpha1.GateFailurePolicyAbort, v1alpha1.GateFailurePolicyPause} {
+		t.Run(string(policy), func(t *testing.T) {
+			ro := newGateRollout(&v1alpha1.RolloutGateStep{
+				URL:           server.URL,
+				Headers:       []v1alpha1.WebMetricHeader{{Key: "X-Token", Value: "token"}},
+				Retries:       ptr.To[int32](1),
+				FailurePolicy: policy,
+			})
+			tasks := newBackgroundTasks[*GateResponse]()
+
+			// the failed request is retried
+			ctx, recorder := reconcileGateCall(t, ro, tasks)
+			assert.False(t, ctx.completedCurrentCanaryStep())
+			assert.False(t, ctx.pauseContext.IsAborted())
+			assert.Empty(t, ctx.pauseContext.addPauseReasons)
+			assert.Equal(t, int32(1), ctx.gate.ConsecutiveErrors)
+			assert.Equal(t, "webhook returned 500 Internal Server Error", ctx.gate.Message)
+			assert.Equal(t, []string{conditions.GateErrorReason}, recorder.Events())
+
+			// the retry waits on the backoff
+			ro.Status.Canary.Gate = ctx.gate
+			ctx, _, _ = newGateContext(ro, tasks)
+			require.NoError(t, ctx.reconcileGate())
+			assert.Equal(t, int32(1), ctx.gate.ConsecutiveErrors)
+
+			ro.Status.Canary.Gate.LastCheckTime = ptr.To(metav1.NewTime(timeutil.Now().Add(-gateRetryBackoff)))
+			ctx, _ = reconcileGateCall(t, ro, tasks)
+			assert.Equal(t, int32(2), ctx.gate.ConsecutiveErrors)
+			assert.False(t, ctx.completedCurrentCanaryStep())
+			if policy == v1alpha1.GateFailurePolicyAbort {
+				assert.True(t, ctx.pauseContext.IsAborted())
+				assert.Equal(t, v1alpha1.GateDecisionAbort, ctx.gate.Decision)
+			} else {
+				assert.False(t, ctx.pauseContext.IsAborted())
+				assert.Equal(t, v1alpha1.GateDecisionPause, ctx.gate.Decision)
+				assert.Equal(t, []v1alpha1.PauseReason{v1alpha1.PauseReasonGate}, ctx.pauseContext.addPauseReasons)
+			}
+			ro.Status.Canary.Gate = nil
+		})
+	}
+}
+
+func TestReconcileGateOtherStep(t *testing.T) {
+	ro := newGateRollout(&v1alpha1.RolloutGateStep{URL: "http://gate"})
+	ro.Status.CurrentStepIndex = ptr.To[int32](2)
+	ro.Status.Canary.Gate = &v1alpha1.GateStatus{StepIndex: 1, Decision: v1alpha1.GateDecisionProceed}
+	ctx, recorder, _ := newGateContext(ro, newBackgroundTasks[*GateResponse]())
+
+	require.NoError(t, ctx.reconcileGate())
+	assert.Nil(t, ctx.gate)
+	assert.Empty(t, recorder.Events())
+}
+
+func TestReconcileGateSlowWebhook(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		_ = json.NewEncoder(w).Encode(GateResponse{Decision: v1alpha1.GateDecisionProceed})
+	}))
+	t.Cleanup(server.Close)
+	ro := newGateRollout(&v1alpha1.RolloutGateStep{URL: server.URL, Timeout: "1m"})
+	tasks := newBackgroundTasks[*GateResponse]()
+
+	// the reconciliation does not wait on the webhook
+	ctx, _, enqueued := newGateContext(ro, tasks)
+	require.NoError(t, ctx.reconcileGate())
+	assert.False(t, ctx.completedCurrentCanaryStep())
+	assert.Nil(t, ctx.gate.LastCheckTime)
+
+	// the call is not started again while it is running
+	ctx, _, _ = newGateContext(ro, tasks)
+	require.NoError(t, ctx.reconcileGate())
+	assert.False(t, ctx.completedCurrentCanaryStep())
+
+	close(release)
+	waitEnqueued(t, enqueued)
+	ctx, _, _ = newGateContext(ro, tasks)
+	require.NoError(t, ctx.reconcileGate())
+	assert.True(t, ctx.completedCurrentCanaryStep())
+	assert.Equal(t, v1alpha1.GateDecisionProceed, ctx.gate.Decision)
+}
@@ -0,0 +1,196 @@
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/flowcontrol"
+
+	register "github.com/argoproj/argo-rollouts/pkg/apis/rollouts"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	clientset "github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned"
+	listers "github.com/argoproj/argo-rollouts/pkg/client/listers/rollouts/v1alpha1"
+	analysisutil "github.com/argoproj/argo-rollouts/utils/analysis"
+	controllerutil "github.com/argoproj/argo-rollouts/utils/controller"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+	experimentutil "github.com/argoproj/argo-rollouts/utils/experiment"
+	logutil "github.com/argoproj/argo-rollouts/utils/log"
+	"github.com/argoproj/argo-rollouts/utils/sharding"
+)
+
+const (
+	// DefaultGarbageCollectionQPS is the default maximum number of deletions per second of the garbage collector
+	DefaultGarbageCollectionQPS = 5
+)
+
+// GarbageCollectionConfig configures the periodic garbage collection of the AnalysisRuns and Experiments of rollouts
+type GarbageCollectionConfig struct {
+	// Interval is the interval in which AnalysisRuns and Experiments are collected. Disabled when zero.
+	Interval time.Duration
+	// QPS is the maximum number of deletions per second
+	QPS float32
+}
+
+// garbageCollector periodically deletes the AnalysisRuns and Experiments of rollouts which are no longer needed,
+// independently of the reconciliations of the rollouts. These are the ones whose rollout no longer exists, whose
+// revision no longer exists, and the completed ones exceeding the history limits of their rollout. A nil
+// garbageCollector collects nothing.
+type garbageCollector struct {
+	argoprojclientset  clientset.Interface
+	rolloutsLister     listers.RolloutLister
+	replicaSetIndexer  cache.Indexer
+	analysisRunIndexer cache.Indexer
+	experimentsIndexer cache.Indexer
+	sharder            *sharding.Sharder
+	config             GarbageCollectionConfig
+	limiter            flowcontrol.RateLimiter
+}
+
+func newGarbageCollector(c *Controller, config GarbageCollectionConfig) *garbageCollector {
+	if config.Interval <= 0 {
+		return nil
+	}
+	if config.QPS <= 0 {
+		config.QPS = DefaultGarbageCollectionQPS
+	}
+	return &garbageCollector{
+		argoprojclientset:  c.argoprojclientset,
+		rolloutsLister:     c.rolloutsLister,
+		replicaSetIndexer:  c.replicaSetIndexer,
+		analysisRunIndexer: c.analysisRunIndexer,
+		experimentsIndexer: c.experimentsIndexer,
+		sharder:            c.sharder,
+		config:             config,
+		limiter:            flowcontrol.NewTokenBucketRateLimiter(config.QPS, 1),
+	}
+}
+
+// owner returns the rollout controlling the object, and whether the object may be collected. The rollout is nil
+// when the object is orphaned, i.e. its rollout no longer exists.
+func (gc *garbageCollector) owner(obj metav1.Object, now time.Time) (*v1alpha1.Rollout, bool) {
+	controllerRef := metav1.GetControllerOfNoCopy(obj)
+	if controllerRef == nil || controllerRef.Kind != register.RolloutKind || obj.GetDeletionTimestamp() != nil || !gc.sharder.Owns(obj) {
+		return nil, false
+	}
+	// objects are only collected once they are older than the collection interval, so that objects which were just
+	// created are not collected before the informer caches caught up
+	if now.Sub(obj.GetCreationTimestamp().Time) < gc.config.Interval {
+		return nil, false
+	}
+	ro, err := gc.rolloutsLister.Rollouts(obj.GetNamespace()).Get(controllerRef.Name)
+	if k8serrors.IsNotFound(err) {
+		return nil, true
+	}
+	if err != nil {
+		return nil, false
+	}
+	if ro.UID != controllerRef.UID {
+		// the rollout was recreated
+		return nil, true
+	}
+	if ro.DeletionTimestamp != nil {
+		return nil, false
+	}
+	return ro, true
+}
+
+// Collect deletes the AnalysisRuns and Experiments of rollouts which are no longer needed, limited to the configured
+// number of deletions per second
+func (gc *garbageCollector) Collect(ctx context.Context, now time.Time) error {
+	if gc == nil {
+		return nil
+	}
+	rollouts := map[types.UID]*v1alpha1.Rollout{}
+	arsByRollout := map[types.UID][]*v1alpha1.AnalysisRun{}
+	exsByRollout := map[types.UID][]*v1alpha1.Experiment{}
+	var arsToDelete []*v1alpha1.AnalysisRun
+	var exsToDelete []*v1alpha1.Experiment
+
+	for _, obj := range gc.analysisRunIndexer.List() {
+		ar := obj.(*v1alpha1.AnalysisRun)
+		ro, ok := gc.owner(ar, now)
+		switch {
+		case !ok:
+		case ro == nil:
+			arsToDelete = append(arsToDelete, ar)
+		case ar.Status.Phase.Completed() && !currentAnalysisRunNames(ro)[ar.Name]:
+			rollouts[ro.UID] = ro
+			arsByRollout[ro.UID] = append(arsByRollout[ro.UID], ar)
+		}
+	}
+	for _, obj := range gc.experimentsIndexer.List() {
+		ex := obj.(*v1alpha1.Experiment)
+		ro, ok := gc.owner(ex, now)
+		switch {
+		case !ok:
+		case ro == nil:
+			exsToDelete = append(exsToDelete, ex)
+		case ex.Status.Phase.Completed() && ex.Name != ro.Status.Canary.CurrentExperiment:
+			rollouts[ro.UID] = ro
+			exsByRollout[ro.UID] = append(exsByRollout[ro.UID], ex)
+		}
+	}
+
+	for uid, ro := range rollouts {
+		objs, err := controllerutil.ByOwner(gc.replicaSetIndexer, ro.Namespace, uid, false)
+		if err != nil {
+			return err
+		}
+		allRSs := make([]*appsv1.ReplicaSet, 0, len(objs))
+		for _, obj := range objs {
+			allRSs = append(allRSs, obj.(*appsv1.ReplicaSet))
+		}
+		limitSuccessful := defaults.GetAnalysisRunSuccessfulHistoryLimitOrDefault(ro)
+		limitUnsuccessful := defaults.GetAnalysisRunUnsuccessfulHistoryLimitOrDefault(ro)
+		arsToDelete = append(arsToDelete, analysisutil.FilterAnalysisRunsToDelete(arsByRollout[uid], allRSs, limitSuccessful, limitUnsuccessful)...)
+		exsToDelete = append(exsToDelete, experimentutil.FilterExperimentsToDelete(exsByRollout[uid], allRSs, limitSuccessful, limitUnsuccessful)...)
+	}
+
+	for _, ar := range arsToDelete {
+		if err := gc.limiter.Wait(ctx); err != nil {
+			return err
+		}
+		logutil.WithAnalysisRun(ar).Info("Garbage collecting analysis run")
+		err := gc.argoprojclientset.ArgoprojV1alpha1().AnalysisRuns(ar.Namespace).Delete(ctx, ar.Name, metav1.DeleteOptions{Preconditions: metav1.NewUIDPreconditions(string(ar.UID))})
+		if err != nil && !k8serrors.IsNotFound(err) && !k8serrors.IsConflict(err) {
+			return fmt.Errorf("failed to garbage collect analysis run '%s': %w", ar.Name, err)
+		}
+	}
+	for _, ex := range exsToDelete {
+		if err := gc.limiter.Wait(ctx); err != nil {
+			return err
+		}
+		logutil.WithExperiment(ex).Info("Garbage collecting experiment")
+		err := gc.argoprojclientset.ArgoprojV1alpha1().Experiments(ex.Namespace).Delete(ctx, ex.Name, metav1.DeleteOptions{Preconditions: metav1.NewUIDPreconditions(string(ex.UID))})
+		if err != nil && !k8serrors.IsNotFound(err) && !k8serrors.IsConflict(err) {
+			return fmt.Errorf("failed to garbage collect experiment '%s': %w", ex.Name, err)
+		}
+	}
+	return nil
+}
+
+// currentAnalysisRunNames returns the names of the analysis runs referenced by the status of the rollout
+func currentAnalysisRunNames(ro *v1alpha1.Rollout) map[string]bool {
+	names := map[string]bool{}
+	for _, arStatus := range []*v1alpha1.RolloutAnalysisRunStatus{
+		ro.Status.Canary.CurrentBackgroundAnalysisRunStatus,
+		ro.Status.Canary.CurrentGuardrailAnalysisRunStatus,
+		ro.Status.Canary.CurrentStepAnalysisRunStatus,
+		ro.Status.BlueGreen.PrePromotionAnalysisRunStatus,
+		ro.Status.BlueGreen.PostPromotionAnalysisRunStatus,
+	} {
+		if arStatus != nil {
+			names[arStatus.Name] = true
+		}
+	}
+	if ro.Status.RestartStatus != nil && ro.Status.RestartStatus.CurrentAnalysisRunStatus != nil {
+		names[ro.Status.RestartStatus.CurrentAnalysisRunStatus.Name] = true
+	}
+	return names
+}
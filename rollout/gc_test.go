@@ -0,0 +1,123 @@
+package rollout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	core "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/sharding"
+)
+
+func newGarbageCollectorObjectMeta(name string, ro *v1alpha1.Rollout, podHash string, created time.Time) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:              name,
+		Namespace:         ro.Namespace,
+		UID:               types.UID(name + "-uid"),
+		CreationTimestamp: metav1.NewTime(created),
+		Labels:            map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: podHash},
+		OwnerReferences:   []metav1.OwnerReference{*metav1.NewControllerRef(ro, controllerKind)},
+	}
+}
+
+func deletedObjectNames(actions []core.Action, resource string) []string {
+	var names []string
+	for _, action := range actions {
+		if deleteAction, ok := action.(core.DeleteAction); ok && action.GetResource().Resource == resource {
+			names = append(names, deleteAction.GetName())
+		}
+	}
+	return names
+}
+
+func TestGarbageCollectorDisabled(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+	c, _, _ := f.newController(noResyncPeriodFunc)
+	assert.Nil(t, c.garbageCollector)
+	assert.NoError(t, c.garbageCollector.Collect(context.Background(), time.Now()))
+}
+
+func TestGarbageCollectorCollect(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	now := time.Now()
+	old := now.Add(-time.Hour)
+	r := newCanaryRollout("foo", 1, nil, nil, nil, intstr.FromInt(1), intstr.FromInt(0))
+	r.UID = "foo-uid"
+	r.Spec.Analysis = &v1alpha1.AnalysisRunStrategy{SuccessfulRunHistoryLimit: ptr.To[int32](1)}
+	rs := newReplicaSetWithStatus(r, 1, 1)
+	rs.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(r, controllerKind)}
+	podHash := rs.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	r.Status.Canary.CurrentStepAnalysisRunStatus = &v1alpha1.RolloutAnalysisRunStatus{Name: "current"}
+	deleted := r.DeepCopy()
+	deleted.Name = "deleted"
+	deleted.UID = "deleted-uid"
+
+	newAnalysisRun := func(name string, ro *v1alpha1.Rollout, podHash string, phase v1alpha1.AnalysisPhase, created time.Time) *v1alpha1.AnalysisRun {
+		return &v1alpha1.AnalysisRun{
+			ObjectMeta: newGarbageCollectorObjectMeta(name, ro, podHash, created),
+			Status:     v1alpha1.AnalysisRunStatus{Phase: phase},
+		}
+	}
+	ars := []*v1alpha1.AnalysisRun{
+		newAnalysisRun("retained", r, podHash, v1alpha1.AnalysisPhaseSuccessful, old),
+		newAnalysisRun("exceeds-history-limit", r, podHash, v1alpha1.AnalysisPhaseSuccessful, old.Add(-time.Minute)),
+		newAnalysisRun("revision-gone", r, "gone", v1alpha1.AnalysisPhaseSuccessful, old),
+		newAnalysisRun("current", r, "gone", v1alpha1.AnalysisPhaseSuccessful, old),
+		newAnalysisRun("running", r, "gone", v1alpha1.AnalysisPhaseRunning, old),
+		newAnalysisRun("just-created", r, "gone", v1alpha1.AnalysisPhaseSuccessful, now),
+		newAnalysisRun("orphaned", deleted, podHash, v1alpha1.AnalysisPhaseRunning, old),
+	}
+	exs := []*v1alpha1.Experiment{
+		{ObjectMeta: newGarbageCollectorObjectMeta("experiment", r, podHash, old), Status: v1alpha1.ExperimentStatus{Phase: v1alpha1.AnalysisPhaseSuccessful}},
+		{ObjectMeta: newGarbageCollectorObjectMeta("orphaned-experiment", deleted, podHash, old)},
+	}
+
+	f.rolloutLister = append(f.rolloutLister, r)
+	f.objects = append(f.objects, r)
+	f.replicaSetLister = append(f.replicaSetLister, rs)
+	f.kubeobjects = append(f.kubeobjects, rs)
+	for _, ar := range ars {
+		f.analysisRunLister = append(f.analysisRunLister, ar)
+		f.objects = append(f.objects, ar)
+	}
+	for _, ex := range exs {
+		f.experimentLister = append(f.experimentLister, ex)
+		f.objects = append(f.objects, ex)
+	}
+	c, _, _ := f.newController(noResyncPeriodFunc)
+	gc := newGarbageCollector(c, GarbageCollectionConfig{Interval: time.Minute, QPS: 1000})
+
+	assert.NoError(t, gc.Collect(context.Background(), now))
+	assert.ElementsMatch(t, []string{"exceeds-history-limit", "revision-gone", "orphaned"}, deletedObjectNames(f.client.Actions(), "analysisruns"))
+	assert.ElementsMatch(t, []string{"orphaned-experiment"}, deletedObjectNames(f.client.Actions(), "experiments"))
+}
+
+func TestGarbageCollectorSkipsOtherShards(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	deleted := newCanaryRollout("deleted", 1, nil, nil, nil, intstr.FromInt(1), intstr.FromInt(0))
+	ar := &v1alpha1.AnalysisRun{ObjectMeta: newGarbageCollectorObjectMeta("orphaned", deleted, "abc", time.Now().Add(-time.Hour))}
+	ar.Labels[v1alpha1.LabelKeyControllerShard] = "1"
+	f.analysisRunLister = append(f.analysisRunLister, ar)
+	f.objects = append(f.objects, ar)
+	c, _, _ := f.newController(noResyncPeriodFunc)
+	sharder, err := sharding.NewSharder(2, 0)
+	require.NoError(t, err)
+	c.sharder = sharder
+	gc := newGarbageCollector(c, GarbageCollectionConfig{Interval: time.Minute})
+
+	assert.NoError(t, gc.Collect(context.Background(), time.Now()))
+	assert.Empty(t, deletedObjectNames(f.client.Actions(), "analysisruns"))
+}
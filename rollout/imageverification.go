@@ -0,0 +1,336 @@
+package rollout
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	"github.com/argoproj/argo-rollouts/utils/cosign"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+	"github.com/argoproj/argo-rollouts/utils/record"
+	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
+)
+
+// imageVerificationTimeout limits the time the verification of the images of a ReplicaSet takes
+const imageVerificationTimeout = time.Minute
+
+// verifyImageSignature verifies the signature of the image with the verifier and returns its digest
+var verifyImageSignature = func(ctx context.Context, verifier *cosign.Verifier, image string, credentials map[string]cosign.Credentials) (string, error) {
+	return verifier.Verify(ctx, image, credentials)
+}
+
+// imageVerifications tracks the image verifications of the new ReplicaSets, which run in the background so that slow
+// registries do not block the reconciliation of rollouts. The ReplicaSets whose images were verified are only
+// remembered in memory, so that they cannot be marked as verified through the API, and are verified again after a
+// restart of the controller.
+type imageVerifications struct {
+	lock    sync.Mutex
+	entries map[string]*imageVerificationEntry
+}
+
+type imageVerificationEntry struct {
+	// running indicates that the images are being verified
+	running bool
+	// done indicates that the verification completed with the given digests or error, which were not yet consumed
+	done    bool
+	digests map[string]string
+	err     error
+	// verified holds the images of the ReplicaSet once they were verified and pinned to their digests
+	verified []string
+}
+
+func newImageVerifications() *imageVerifications {
+	return &imageVerifications{entries: map[string]*imageVerificationEntry{}}
+}
+
+func imageVerificationKey(ro *v1alpha1.Rollout, rs *appsv1.ReplicaSet) string {
+	return fmt.Sprintf("%s/%s/%s", ro.Namespace, ro.Name, rs.UID)
+}
+
+func (v *imageVerifications) entry(key string) *imageVerificationEntry {
+	entry, ok := v.entries[key]
+	if !ok {
+		entry = &imageVerificationEntry{}
+		v.entries[key] = entry
+	}
+	return entry
+}
+
+// isVerified returns whether the images were verified for the ReplicaSet
+func (v *imageVerifications) isVerified(key string, images []string) bool {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	entry, ok := v.entries[key]
+	return ok && entry.verified != nil && slices.Equal(entry.verified, images)
+}
+
+// setVerified records that the images were verified for the ReplicaSet
+func (v *imageVerifications) setVerified(key string, images []string) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	v.entry(key).verified = images
+}
+
+// result returns and consumes the result of the completed verification, if any
+func (v *imageVerifications) result(key string) (map[string]string, bool, error) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	entry, ok := v.entries[key]
+	if !ok || !entry.done {
+		return nil, false, nil
+	}
+	entry.done = false
+	return entry.digests, true, entry.err
+}
+
+// start runs the verification in the background unless it is already running, and calls onDone once it completed
+func (v *imageVerifications) start(key string, verify func() (map[string]string, error), onDone func()) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	entry := v.entry(key)
+	if entry.running {
+		return
+	}
+	entry.running = true
+	go func() {
+		digests, err := verify()
+		v.lock.Lock()
+		entry.running = false
+		entry.done = true
+		entry.digests, entry.err = digests, err
+		v.lock.Unlock()
+		onDone()
+	}()
+}
+
+// Forget removes the verifications of a deleted rollout, or of a rollout whose update completed
+func (v *imageVerifications) Forget(rolloutKey string) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	for key := range v.entries {
+		if strings.HasPrefix(key, rolloutKey+"/") {
+			delete(v.entries, key)
+		}
+	}
+}
+
+// reconcileImageVerification verifies the cosign signatures of the images of the new ReplicaSet before the update
+// progresses, and pins the images of the ReplicaSet to the verified digests, so that its pods run the verified images
+// even if a tag is pushed again. The images are verified in the background, during which the reconciliation of the
+// rollout is held back (see imageVerificationPending). The update is aborted if an image is not signed by one of the
+// trusted keys or identities. Errors accessing the registries are returned, so that the update is held back until the
+// images could be verified. The initial deploy is held back in either case, since there is no stable ReplicaSet to
+// abort to.
+func (c *rolloutContext) reconcileImageVerification() error {
+	rolloutKey := c.rollout.Namespace + "/" + c.rollout.Name
+	verification := c.rollout.Spec.ImageVerification
+	if verification == nil || c.newRS == nil || c.pauseContext.IsAborted() {
+		c.imageVerifications.Forget(rolloutKey)
+		return nil
+	}
+	if c.stableRS != nil && c.newRS.Name == c.stableRS.Name {
+		c.imageVerifications.Forget(rolloutKey)
+		return nil
+	}
+	key := imageVerificationKey(c.rollout, c.newRS)
+	if c.imageVerifications.isVerified(key, podImages(c.newRS.Spec.Template.Spec)) {
+		return nil
+	}
+	digests, done, err := c.imageVerifications.result(key)
+	if !done {
+		c.imageVerificationPending = true
+		return c.startImageVerification(key, verification)
+	}
+	if cosign.IsVerificationError(err) {
+		message := fmt.Sprintf(conditions.ImageVerificationFailedMessage, c.newRS.Name, err.Error())
+		c.recorder.Warnf(c.rollout, record.EventOptions{EventReason: conditions.ImageVerificationFailedReason}, "%s", message)
+		if c.stableRS == nil {
+			return errors.New(message)
+		}
+		c.pauseContext.AddAbort(message)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to verify the images of ReplicaSet %s: %w", c.newRS.Name, err)
+	}
+
+	rs, err := c.pinImageDigests(digests)
+	if err != nil {
+		return err
+	}
+	c.imageVerifications.setVerified(key, podImages(rs.Spec.Template.Spec))
+	c.recorder.Eventf(c.rollout, record.EventOptions{EventReason: conditions.ImagesVerifiedReason}, conditions.ImagesVerifiedMessage, rs.Name)
+	return nil
+}
+
+// startImageVerification verifies the images of the new ReplicaSet in the background, and requeues the rollout once
+// the verification completed. The keys and credentials are read before, so that only the registries are accessed
+// in the background.
+func (c *rolloutContext) startImageVerification(key string, verification *v1alpha1.ImageVerification) error {
+	ctx := context.TODO()
+	verifier, err := c.newImageVerifier(ctx, verification)
+	if err != nil {
+		return err
+	}
+	credentials, err := c.imagePullCredentials(ctx, c.newRS.Spec.Template.Spec)
+	if err != nil {
+		return err
+	}
+	images := podImages(c.newRS.Spec.Template.Spec)
+	rollout, verify, enqueueRollout := c.rollout, verifyImageSignature, c.enqueueRollout
+	c.imageVerifications.start(key, func() (map[string]string, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), imageVerificationTimeout)
+		defer cancel()
+		digests := map[string]string{}
+		for _, image := range images {
+			digest, err := verify(ctx, verifier, image, credentials)
+			if err != nil {
+				return nil, err
+			}
+			digests[image] = digest
+		}
+		return digests, nil
+	}, func() {
+		enqueueRollout(rollout)
+	})
+	return nil
+}
+
+// pinImageDigests references the images of the new ReplicaSet by their verified digests. The pod template hash of the
+// ReplicaSet is not changed, so that it remains the ReplicaSet of the pod template of the rollout.
+func (c *rolloutContext) pinImageDigests(digests map[string]string) (*appsv1.ReplicaSet, error) {
+	rsCopy := c.newRS.DeepCopy()
+	podSpec := &rsCopy.Spec.Template.Spec
+	changed := false
+	for _, containers := range [][]corev1.Container{podSpec.InitContainers, podSpec.Containers} {
+		for i := range containers {
+			if pinned := replicasetutil.PinImageDigest(containers[i].Image, digests[containers[i].Image]); pinned != containers[i].Image {
+				containers[i].Image = pinned
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return c.newRS, nil
+	}
+	rs, err := c.kubeclientset.AppsV1().ReplicaSets(rsCopy.Namespace).Update(context.TODO(), rsCopy, metav1.UpdateOptions{FieldManager: defaults.DefaultFieldManager})
+	if err != nil {
+		return nil, fmt.Errorf("failed to pin the verified images of ReplicaSet %s: %w", rsCopy.Name, err)
+	}
+	for i := range c.allRSs {
+		if c.allRSs[i].Name == rs.Name {
+			c.allRSs[i] = rs
+		}
+	}
+	c.newRS = rs
+	return rs, nil
+}
+
+// newImageVerifier returns a verifier which trusts the public keys and identities of the image verification
+func (c *rolloutContext) newImageVerifier(ctx context.Context, verification *v1alpha1.ImageVerification) (*cosign.Verifier, error) {
+	verifier := &cosign.Verifier{HTTPClient: &http.Client{Timeout: imageVerificationTimeout}}
+	for _, ref := range verification.PublicKeys {
+		data, err := c.getSecretKey(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		key, err := cosign.ParsePublicKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %s/%s: %w", ref.Name, ref.Key, err)
+		}
+		verifier.PublicKeys = append(verifier.PublicKeys, key)
+	}
+	if len(verification.Identities) == 0 {
+		return verifier, nil
+	}
+	for _, identity := range verification.Identities {
+		trusted := cosign.Identity{Issuer: identity.Issuer, Subject: identity.Subject}
+		if identity.SubjectRegex != "" {
+			subjectRegex, err := regexp.Compile(identity.SubjectRegex)
+			if err != nil {
+				return nil, err
+			}
+			trusted.SubjectRegex = subjectRegex
+		}
+		verifier.Identities = append(verifier.Identities, trusted)
+	}
+	data, err := c.getSecretKey(ctx, *verification.CertificateAuthorities)
+	if err != nil {
+		return nil, err
+	}
+	if verifier.Roots, verifier.Intermediates, err = cosign.ParseCertificateAuthorities(data); err != nil {
+		return nil, fmt.Errorf("invalid certificate authorities %s/%s: %w", verification.CertificateAuthorities.Name, verification.CertificateAuthorities.Key, err)
+	}
+	data, err = c.getSecretKey(ctx, *verification.TransparencyLogPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	key, err := cosign.ParsePublicKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transparency log public key %s/%s: %w", verification.TransparencyLogPublicKey.Name, verification.TransparencyLogPublicKey.Key, err)
+	}
+	verifier.TransparencyLogKeys = []crypto.PublicKey{key}
+	return verifier, nil
+}
+
+// imagePullCredentials returns the registry credentials of the image pull secrets of the pod spec
+func (c *rolloutContext) imagePullCredentials(ctx context.Context, podSpec corev1.PodSpec) (map[string]cosign.Credentials, error) {
+	credentials := map[string]cosign.Credentials{}
+	for _, ref := range podSpec.ImagePullSecrets {
+		secret, err := c.kubeclientset.CoreV1().Secrets(c.rollout.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get image pull secret %s: %w", ref.Name, err)
+		}
+		data, ok := secret.Data[corev1.DockerConfigJsonKey]
+		if !ok {
+			data = secret.Data[corev1.DockerConfigKey]
+		}
+		secretCredentials, err := cosign.ParseDockerConfig(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid image pull secret %s: %w", ref.Name, err)
+		}
+		for registry, creds := range secretCredentials {
+			if _, ok := credentials[registry]; !ok {
+				credentials[registry] = creds
+			}
+		}
+	}
+	return credentials, nil
+}
+
+// getSecretKey returns the value of the key of a secret in the namespace of the rollout
+func (c *rolloutContext) getSecretKey(ctx context.Context, ref v1alpha1.SecretKeyRef) ([]byte, error) {
+	secret, err := c.kubeclientset.CoreV1().Secrets(c.rollout.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret %s: %w", ref.Name, err)
+	}
+	data, ok := secret.Data[ref.Key]
+	if !ok {
+		return nil, fmt.Errorf("secret %s has no key %s", ref.Name, ref.Key)
+	}
+	return data, nil
+}
+
+// podImages returns the distinct images of the init containers and containers of the pod spec
+func podImages(podSpec corev1.PodSpec) []string {
+	var images []string
+	for _, container := range slices.Concat(podSpec.InitContainers, podSpec.Containers) {
+		if !slices.Contains(images, container.Image) {
+			images = append(images, container.Image)
+		}
+	}
+	return images
+}
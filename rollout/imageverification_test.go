@@ -0,0 +1,154 @@
+package rollout
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	"github.com/argoproj/argo-rollouts/utils/cosign"
+	"github.com/argoproj/argo-rollouts/utils/record"
+)
+
+func newImageVerificationRollout() (*v1alpha1.Rollout, *k8sfake.Clientset) {
+	steps := []v1alpha1.CanaryStep{{SetWeight: ptr.To[int32](10)}, {Pause: &v1alpha1.RolloutPause{}}}
+	ro := newCanaryRollout("foo", 5, nil, steps, ptr.To[int32](0), intstr.FromInt(1), intstr.FromInt(0))
+	ro.Spec.ImageVerification = &v1alpha1.ImageVerification{
+		PublicKeys: []v1alpha1.SecretKeyRef{{Name: "cosign", Key: "cosign.pub"}},
+	}
+	ro.Spec.Template.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "registry"}}
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	der, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	client := k8sfake.NewSimpleClientset(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "cosign", Namespace: metav1.NamespaceDefault},
+			Data:       map[string][]byte{"cosign.pub": pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "registry", Namespace: metav1.NamespaceDefault},
+			Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{"auths":{"ghcr.io":{"username":"bot","password":"token"}}}`)},
+		},
+	)
+	return ro, client
+}
+
+func mockVerifyImageSignature(t *testing.T, verify func(image string, credentials map[string]cosign.Credentials) (string, error)) {
+	original := verifyImageSignature
+	t.Cleanup(func() { verifyImageSignature = original })
+	verifyImageSignature = func(ctx context.Context, verifier *cosign.Verifier, image string, credentials map[string]cosign.Credentials) (string, error) {
+		assert.Len(t, verifier.PublicKeys, 1)
+		return verify(image, credentials)
+	}
+}
+
+// newImageVerificationContext returns the context of a rollout whose new ReplicaSet exists
+func newImageVerificationContext(t *testing.T, ro *v1alpha1.Rollout, client *k8sfake.Clientset) (*rolloutContext, *record.FakeEventRecorder) {
+	ctx, recorder := newLoadTestContext(ro, true, client)
+	ctx.imageVerifications = newImageVerifications()
+	_, err := client.AppsV1().ReplicaSets(metav1.NamespaceDefault).Create(context.TODO(), ctx.newRS, metav1.CreateOptions{})
+	require.NoError(t, err)
+	return ctx, recorder
+}
+
+// verifyImages reconciles the image verification, which holds back the rollout until the verification in the
+// background completed and requeued the rollout, and reconciles it again
+func verifyImages(t *testing.T, ctx *rolloutContext) error {
+	enqueued := make(chan struct{}, 1)
+	ctx.enqueueRollout = func(obj any) { enqueued <- struct{}{} }
+	ctx.imageVerificationPending = false
+	require.NoError(t, ctx.reconcileImageVerification())
+	require.True(t, ctx.imageVerificationPending)
+	select {
+	case <-enqueued:
+	case <-time.After(10 * time.Second):
+		t.Fatal("image verification did not complete")
+	}
+	ctx.imageVerificationPending = false
+	return ctx.reconcileImageVerification()
+}
+
+func TestReconcileImageVerification(t *testing.T) {
+	ro, client := newImageVerificationRollout()
+	ctx, recorder := newImageVerificationContext(t, ro, client)
+	var verified []string
+	mockVerifyImageSignature(t, func(image string, credentials map[string]cosign.Credentials) (string, error) {
+		verified = append(verified, image)
+		assert.Equal(t, cosign.Credentials{Username: "bot", Password: "token"}, credentials["ghcr.io"])
+		return "sha256:abc", nil
+	})
+
+	require.NoError(t, verifyImages(t, ctx))
+	assert.False(t, ctx.imageVerificationPending)
+	assert.False(t, ctx.pauseContext.IsAborted())
+	assert.Equal(t, []string{conditions.ImagesVerifiedReason}, recorder.Events())
+	// the pods of the ReplicaSet run the verified images, even if the tag is pushed again
+	assert.Equal(t, "foo/bar2@sha256:abc", ctx.newRS.Spec.Template.Spec.Containers[0].Image)
+	rs, err := client.AppsV1().ReplicaSets(metav1.NamespaceDefault).Get(context.TODO(), ctx.newRS.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "foo/bar2@sha256:abc", rs.Spec.Template.Spec.Containers[0].Image)
+
+	// the images of a ReplicaSet are only verified once
+	require.NoError(t, ctx.reconcileImageVerification())
+	assert.False(t, ctx.imageVerificationPending)
+	assert.Equal(t, []string{"foo/bar2"}, verified)
+
+	// but the ReplicaSets are not marked as verified beyond the controller, e.g. after a restart the pinned images
+	// are verified again
+	ctx.imageVerifications = newImageVerifications()
+	require.NoError(t, verifyImages(t, ctx))
+	assert.Equal(t, []string{"foo/bar2", "foo/bar2@sha256:abc"}, verified)
+	assert.Equal(t, "foo/bar2@sha256:abc", ctx.newRS.Spec.Template.Spec.Containers[0].Image)
+}
+
+func TestReconcileImageVerificationFailed(t *testing.T) {
+	ro, client := newImageVerificationRollout()
+	ctx, recorder := newImageVerificationContext(t, ro, client)
+	mockVerifyImageSignature(t, func(image string, credentials map[string]cosign.Credentials) (string, error) {
+		return "", &cosign.VerificationError{Image: image, Reason: "no signatures found"}
+	})
+
+	require.NoError(t, verifyImages(t, ctx))
+	assert.True(t, ctx.pauseContext.IsAborted())
+	assert.Contains(t, ctx.pauseContext.abortMessage, "image foo/bar2 is not signed by a trusted key or identity: no signatures found")
+	assert.Equal(t, []string{conditions.ImageVerificationFailedReason}, recorder.Events())
+	assert.Equal(t, "foo/bar2", ctx.newRS.Spec.Template.Spec.Containers[0].Image)
+
+	// the initial deploy is held back instead, since there is no stable ReplicaSet to abort to
+	ctx, _ = newLoadTestContext(ro, true, client)
+	ctx.imageVerifications = newImageVerifications()
+	ctx.stableRS = nil
+	assert.Error(t, verifyImages(t, ctx))
+	assert.False(t, ctx.pauseContext.IsAborted())
+}
+
+func TestReconcileImageVerificationRegistryError(t *testing.T) {
+	ro, client := newImageVerificationRollout()
+	ctx, _ := newImageVerificationContext(t, ro, client)
+	mockVerifyImageSignature(t, func(image string, credentials map[string]cosign.Credentials) (string, error) {
+		return "", errors.New("connection refused")
+	})
+
+	// the update is held back until the images could be verified
+	assert.ErrorContains(t, verifyImages(t, ctx), "connection refused")
+	assert.False(t, ctx.pauseContext.IsAborted())
+	assert.Equal(t, "foo/bar2", ctx.newRS.Spec.Template.Spec.Containers[0].Image)
+
+	// and the verification is retried
+	require.NoError(t, ctx.reconcileImageVerification())
+	assert.True(t, ctx.imageVerificationPending)
+}
@@ -0,0 +1,69 @@
+package rollout
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	patchtypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+	kedautil "github.com/argoproj/argo-rollouts/utils/keda"
+	"github.com/argoproj/argo-rollouts/utils/record"
+	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
+)
+
+// reconcileKEDAScaledObjects coordinates the KEDA ScaledObjects which scale the rollout with its update. The autoscaling
+// of the ScaledObjects is paused at the replica count of the rollout while the canary is rolled out, and resumed once
+// the update is completed or aborted, or once the coordination is removed from the rollout. ScaledObjects which were
+// paused by someone else are left untouched.
+func (c *rolloutContext) reconcileKEDAScaledObjects() error {
+	scaledObjects, err := kedautil.GetScaledObjectsForRollout(c.scaledObjectLister, c.rollout)
+	if err != nil {
+		return fmt.Errorf("failed to list the ScaledObjects of the rollout: %w", err)
+	}
+	if len(scaledObjects) == 0 {
+		return nil
+	}
+	ctx := context.TODO()
+	updating := c.newRS != nil && replicasetutil.CheckStableRSExists(c.newRS, c.stableRS) && !c.pauseContext.IsAborted()
+	pause := c.rollout.Spec.Strategy.Canary.KEDA != nil && updating
+	replicas := defaults.GetReplicasOrDefault(c.rollout.Spec.Replicas)
+	client := c.dynamicclientset.Resource(kedautil.GetScaledObjectGVR()).Namespace(c.rollout.Namespace)
+	for _, so := range scaledObjects {
+		annots := so.GetAnnotations()
+		pausedBy, pausedByRollout := annots[kedautil.PausedByAnnotation]
+		_, paused := annots[kedautil.PausedReplicasAnnotation]
+		var patchAnnotations map[string]any
+		switch {
+		case pause && !paused:
+			patchAnnotations = map[string]any{
+				kedautil.PausedReplicasAnnotation: strconv.Itoa(int(replicas)),
+				kedautil.PausedByAnnotation:       c.rollout.Name,
+			}
+		case !pause && pausedByRollout && pausedBy == c.rollout.Name:
+			patchAnnotations = map[string]any{
+				kedautil.PausedReplicasAnnotation: nil,
+				kedautil.PausedByAnnotation:       nil,
+			}
+		default:
+			continue
+		}
+		patch, err := json.Marshal(map[string]any{"metadata": map[string]any{"annotations": patchAnnotations}})
+		if err != nil {
+			return err
+		}
+		if _, err := client.Patch(ctx, so.GetName(), patchtypes.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return fmt.Errorf("failed to patch ScaledObject %s: %w", so.GetName(), err)
+		}
+		if pause {
+			c.recorder.Eventf(c.rollout, record.EventOptions{EventReason: conditions.ScaledObjectPausedReason}, conditions.ScaledObjectPausedMessage, so.GetName(), replicas)
+		} else {
+			c.recorder.Eventf(c.rollout, record.EventOptions{EventReason: conditions.ScaledObjectResumedReason}, conditions.ScaledObjectResumedMessage, so.GetName())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package rollout
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/dynamic/dynamiclister"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	kedautil "github.com/argoproj/argo-rollouts/utils/keda"
+	logutil "github.com/argoproj/argo-rollouts/utils/log"
+	"github.com/argoproj/argo-rollouts/utils/record"
+	unstructuredutil "github.com/argoproj/argo-rollouts/utils/unstructured"
+)
+
+const fooScaledObject = `apiVersion: keda.sh/v1alpha1
+kind: ScaledObject
+metadata:
+  name: foo
+  namespace: default
+spec:
+  scaleTargetRef:
+    apiVersion: argoproj.io/v1alpha1
+    kind: Rollout
+    name: foo
+  triggers:
+  - type: kafka`
+
+const barScaledObject = `apiVersion: keda.sh/v1alpha1
+kind: ScaledObject
+metadata:
+  name: bar
+  namespace: default
+spec:
+  scaleTargetRef:
+    name: foo
+  triggers:
+  - type: kafka`
+
+func newKEDAContext(t *testing.T, ro *v1alpha1.Rollout, updating bool, objs ...runtime.Object) (*rolloutContext, cache.Indexer, *record.FakeEventRecorder) {
+	listMapping := map[schema.GroupVersionResource]string{
+		kedautil.GetScaledObjectGVR(): "ScaledObjectList",
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listMapping, objs...)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	recorder := record.NewFakeEventRecorder()
+	stableRS := newReplicaSetWithStatus(ro, 5, 5)
+	newRS := stableRS
+	if updating {
+		newRS = newReplicaSetWithStatus(bumpVersion(ro), 1, 1)
+	}
+	ctx := &rolloutContext{
+		rollout:      ro,
+		newRS:        newRS,
+		stableRS:     stableRS,
+		log:          logutil.WithRollout(ro),
+		pauseContext: &pauseContext{rollout: ro},
+		reconcilerBase: reconcilerBase{
+			dynamicclientset:   client,
+			scaledObjectLister: dynamiclister.New(indexer, kedautil.GetScaledObjectGVR()),
+			recorder:           recorder,
+		},
+	}
+	syncScaledObjects(t, ctx, indexer)
+	return ctx, indexer, recorder
+}
+
+// syncScaledObjects replaces the ScaledObjects of the informer cache with the ones of the client, like the informer
+// does once it is notified of the patches
+func syncScaledObjects(t *testing.T, ctx *rolloutContext, indexer cache.Indexer) {
+	list, err := ctx.dynamicclientset.Resource(kedautil.GetScaledObjectGVR()).Namespace("default").List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	var items []any
+	for i := range list.Items {
+		items = append(items, &list.Items[i])
+	}
+	require.NoError(t, indexer.Replace(items, ""))
+}
+
+func getScaledObjectAnnotations(t *testing.T, ctx *rolloutContext, name string) map[string]string {
+	so, err := ctx.dynamicclientset.Resource(kedautil.GetScaledObjectGVR()).Namespace("default").Get(context.TODO(), name, metav1.GetOptions{})
+	require.NoError(t, err)
+	return so.GetAnnotations()
+}
+
+func TestReconcileKEDAScaledObjectsPause(t *testing.T) {
+	ro := newCanaryRollout("foo", 5, nil, nil, ptr.To[int32](0), intstr.FromInt(1), intstr.FromInt(0))
+	ro.Spec.Strategy.Canary.KEDA = &v1alpha1.KEDACoordination{}
+	ctx, indexer, recorder := newKEDAContext(t, ro, true, unstructuredutil.StrToUnstructuredUnsafe(fooScaledObject), unstructuredutil.StrToUnstructuredUnsafe(barScaledObject))
+
+	require.NoError(t, ctx.reconcileKEDAScaledObjects())
+	assert.Equal(t, map[string]string{
+		kedautil.PausedReplicasAnnotation: "5",
+		kedautil.PausedByAnnotation:       "foo",
+	}, getScaledObjectAnnotations(t, ctx, "foo"))
+	// the ScaledObject scales a Deployment named foo
+	assert.Empty(t, getScaledObjectAnnotations(t, ctx, "bar"))
+	assert.Equal(t, []string{conditions.ScaledObjectPausedReason}, recorder.Events())
+
+	// the ScaledObject is resumed once the update is aborted
+	syncScaledObjects(t, ctx, indexer)
+	ro.Status.Abort = true
+	require.NoError(t, ctx.reconcileKEDAScaledObjects())
+	assert.Empty(t, getScaledObjectAnnotations(t, ctx, "foo"))
+	assert.Equal(t, []string{conditions.ScaledObjectPausedReason, conditions.ScaledObjectResumedReason}, recorder.Events())
+}
+
+func TestReconcileKEDAScaledObjectsResume(t *testing.T) {
+	ro := newCanaryRollout("foo", 5, nil, nil, ptr.To[int32](0), intstr.FromInt(1), intstr.FromInt(0))
+	ro.Spec.Strategy.Canary.KEDA = &v1alpha1.KEDACoordination{Mode: v1alpha1.KEDACoordinationModePause}
+	so := unstructuredutil.StrToUnstructuredUnsafe(fooScaledObject)
+	so.SetAnnotations(map[string]string{kedautil.PausedReplicasAnnotation: "5", kedautil.PausedByAnnotation: "foo"})
+	ctx, _, recorder := newKEDAContext(t, ro, false, so)
+
+	require.NoError(t, ctx.reconcileKEDAScaledObjects())
+	assert.Empty(t, getScaledObjectAnnotations(t, ctx, "foo"))
+	assert.Equal(t, []string{conditions.ScaledObjectResumedReason}, recorder.Events())
+}
+
+func TestReconcileKEDAScaledObjectsCoordinationRemoved(t *testing.T) {
+	ro := newCanaryRollout("foo", 5, nil, nil, ptr.To[int32](0), intstr.FromInt(1), intstr.FromInt(0))
+	so := unstructuredutil.StrToUnstructuredUnsafe(fooScaledObject)
+	so.SetAnnotations(map[string]string{kedautil.PausedReplicasAnnotation: "5", kedautil.PausedByAnnotation: "foo"})
+
+	// the ScaledObject paused by the rollout is resumed, even in the middle of the update
+	ctx, _, recorder := newKEDAContext(t, ro, true, so)
+	require.NoError(t, ctx.reconcileKEDAScaledObjects())
+	assert.Empty(t, getScaledObjectAnnotations(t, ctx, "foo"))
+	assert.Equal(t, []string{conditions.ScaledObjectResumedReason}, recorder.Events())
+}
+
+func TestReconcileKEDAScaledObjectsPausedByUser(t *testing.T) {
+	ro := newCanaryRollout("foo", 5, nil, nil, ptr.To[int32](0), intstr.FromInt(1), intstr.FromInt(0))
+	ro.Spec.Strategy.Canary.KEDA = &v1alpha1.KEDACoordination{}
+	so := unstructuredutil.StrToUnstructuredUnsafe(fooScaledObject)
+	so.SetAnnotations(map[string]string{kedautil.PausedReplicasAnnotation: "3"})
+
+	// the ScaledObject is neither paused again during the update, nor resumed after it
+	for _, updating := range []bool{true, false} {
+		ctx, _, recorder := newKEDAContext(t, ro, updating, so.DeepCopy())
+		require.NoError(t, ctx.reconcileKEDAScaledObjects())
+		assert.Equal(t, map[string]string{kedautil.PausedReplicasAnnotation: "3"}, getScaledObjectAnnotations(t, ctx, "foo"))
+		assert.Empty(t, recorder.Events())
+	}
+}
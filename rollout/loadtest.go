@@ -0,0 +1,244 @@
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+	"github.com/argoproj/argo-rollouts/utils/record"
+	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
+)
+
+const (
+	// DefaultK6Image is the image of k6 load tests
+	DefaultK6Image = "grafana/k6"
+	// DefaultVegetaImage is the image of vegeta load tests
+	DefaultVegetaImage = "peterevans/vegeta"
+	// loadTestResyncPeriod is the period at which a rollout waiting on a load test checks the Job of the load test
+	loadTestResyncPeriod = 10 * time.Second
+	// LoadTestLabelKey labels the Jobs of load test steps with the index of their step
+	LoadTestLabelKey = "rollouts-load-test"
+)
+
+// reconcileLoadTest starts the Job of the load test of the current step, and deletes the load test Jobs which do
+// not belong to the current update once the update is completed, aborted or replaced. It records whether the load
+// test step is completed, and aborts the rollout if the Job of a load test step which waits on it failed.
+func (c *rolloutContext) reconcileLoadTest() error {
+	if !slices.ContainsFunc(c.rollout.Spec.Strategy.Canary.Steps, func(step v1alpha1.CanaryStep) bool { return step.LoadTest != nil }) {
+		return nil
+	}
+	ctx := context.TODO()
+	client := c.kubeclientset.BatchV1().Jobs(c.rollout.Namespace)
+
+	updating := c.newRS != nil && replicasetutil.CheckStableRSExists(c.newRS, c.stableRS) && !c.pauseContext.IsAborted()
+	var podHash string
+	if updating {
+		podHash = c.newRS.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	}
+	selector, err := labels.Parse(LoadTestLabelKey)
+	if err != nil {
+		return err
+	}
+	jobs, err := c.jobLister.Jobs(c.rollout.Namespace).List(selector)
+	if err != nil {
+		return fmt.Errorf("failed to list load test Jobs: %w", err)
+	}
+	for _, job := range jobs {
+		if !metav1.IsControlledBy(job, c.rollout) || (podHash != "" && job.Labels[v1alpha1.DefaultRolloutUniqueLabelKey] == podHash) {
+			continue
+		}
+		err := client.Delete(ctx, job.Name, metav1.DeleteOptions{PropagationPolicy: ptr.To(metav1.DeletePropagationBackground)})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete load test Job %s: %w", job.Name, err)
+		}
+		c.log.Infof("Deleted load test Job %s", job.Name)
+	}
+
+	step, index := replicasetutil.GetCurrentCanaryStep(c.rollout)
+	if !updating || step == nil || step.LoadTest == nil {
+		return nil
+	}
+	name := fmt.Sprintf("%s-%s-%d-loadtest", c.rollout.Name, podHash, *index)
+	job, err := c.jobLister.Jobs(c.rollout.Namespace).Get(name)
+	if k8serrors.IsNotFound(err) {
+		job, err = c.newLoadTestJob(name, podHash, *index, step.LoadTest)
+		if err != nil {
+			return err
+		}
+		job, err = client.Create(ctx, job, metav1.CreateOptions{FieldManager: defaults.DefaultFieldManager})
+		if k8serrors.IsAlreadyExists(err) {
+			// the Job was created by a previous reconciliation which the informer has not seen yet
+			c.enqueueRolloutAfter(c.rollout, loadTestResyncPeriod)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create load test Job %s: %w", name, err)
+		}
+		c.recorder.Eventf(c.rollout, record.EventOptions{EventReason: conditions.LoadTestStartedReason}, conditions.LoadTestStartedMessage, job.Name, *index+1)
+		if !step.LoadTest.Wait {
+			c.loadTestCompleted = true
+		} else {
+			c.enqueueRolloutAfter(c.rollout, loadTestResyncPeriod)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get load test Job %s: %w", name, err)
+	}
+	if !step.LoadTest.Wait {
+		c.loadTestCompleted = true
+		return nil
+	}
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			c.loadTestCompleted = true
+			return nil
+		case batchv1.JobFailed:
+			message := fmt.Sprintf(conditions.LoadTestFailedMessage, name, *index+1, cond.Message)
+			c.recorder.Warnf(c.rollout, record.EventOptions{EventReason: conditions.LoadTestFailedReason}, "%s", message)
+			c.pauseContext.AddAbort(message)
+			return nil
+		}
+	}
+	c.enqueueRolloutAfter(c.rollout, loadTestResyncPeriod)
+	return nil
+}
+
+// newLoadTestJob returns the Job of a load test. The address of the canary service is passed to all containers of the
+// Job in the CANARY_URL and CANARY_HOST environment variables.
+func (c *rolloutContext) newLoadTestJob(name, podHash string, stepIndex int32, loadTest *v1alpha1.RolloutLoadTestStep) (*batchv1.Job, error) {
+	canaryService := c.rollout.Spec.Strategy.Canary.CanaryService
+	host := fmt.Sprintf("%s.%s.svc.cluster.local", canaryService, c.rollout.Namespace)
+	svc, err := c.servicesLister.Services(c.rollout.Namespace).Get(canaryService)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get canary service %s: %w", canaryService, err)
+	}
+	if len(svc.Spec.Ports) > 0 {
+		host = fmt.Sprintf("%s:%d", host, svc.Spec.Ports[0].Port)
+	}
+
+	var spec batchv1.JobSpec
+	switch {
+	case loadTest.JobSpec != nil:
+		spec = *loadTest.JobSpec.DeepCopy()
+	case loadTest.K6 != nil:
+		spec = newK6JobSpec(loadTest.K6)
+	case loadTest.Vegeta != nil:
+		vegetaSpec, err := newVegetaJobSpec(loadTest.Vegeta)
+		if err != nil {
+			return nil, err
+		}
+		spec = vegetaSpec
+	default:
+		return nil, fmt.Errorf("load test of rollout %s has no Job", c.rollout.Name)
+	}
+	env := []corev1.EnvVar{
+		{Name: "CANARY_HOST", Value: host},
+		{Name: "CANARY_URL", Value: "http://" + host},
+		{Name: "ROLLOUT_NAME", Value: c.rollout.Name},
+		{Name: "ROLLOUT_NAMESPACE", Value: c.rollout.Namespace},
+	}
+	for i := range spec.Template.Spec.Containers {
+		spec.Template.Spec.Containers[i].Env = append(spec.Template.Spec.Containers[i].Env, env...)
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: c.rollout.Namespace,
+			Labels: map[string]string{
+				v1alpha1.DefaultRolloutUniqueLabelKey: podHash,
+				LoadTestLabelKey:                      strconv.Itoa(int(stepIndex)),
+			},
+			Annotations: map[string]string{
+				v1alpha1.ManagedByRolloutsKey: c.rollout.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(c.rollout, controllerKind)},
+		},
+		Spec: spec,
+	}, nil
+}
+
+// newK6JobSpec returns the spec of a Job which runs a k6 script
+func newK6JobSpec(k6 *v1alpha1.K6LoadTest) batchv1.JobSpec {
+	image := k6.Image
+	if image == "" {
+		image = DefaultK6Image
+	}
+	return newLoadTestJobSpec(corev1.Container{
+		Name:  "k6",
+		Image: image,
+		Env:   []corev1.EnvVar{{Name: "K6_SCRIPT", Value: k6.Script}},
+		// k6 reads the script from its standard input
+		Command: []string{"sh", "-c", `echo "$K6_SCRIPT" | k6 run -`},
+	})
+}
+
+// newVegetaJobSpec returns the spec of a Job which sends requests to the canary at a constant rate with vegeta
+func newVegetaJobSpec(vegeta *v1alpha1.VegetaLoadTest) (batchv1.JobSpec, error) {
+	image := vegeta.Image
+	if image == "" {
+		image = DefaultVegetaImage
+	}
+	rate := vegeta.Rate
+	if rate == 0 {
+		rate = 10
+	}
+	duration := time.Minute
+	if vegeta.Duration != "" {
+		d, err := vegeta.Duration.Duration()
+		if err != nil {
+			return batchv1.JobSpec{}, fmt.Errorf("invalid vegeta duration %s: %w", vegeta.Duration, err)
+		}
+		duration = d
+	}
+	method := vegeta.Method
+	if method == "" {
+		method = "GET"
+	}
+	path := vegeta.Path
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	// the method and the path are passed in environment variables, so that the shell does not interpret them
+	attack := fmt.Sprintf(`echo "$VEGETA_METHOD $CANARY_URL$VEGETA_PATH" | vegeta attack -rate=%d -duration=%s | vegeta report`, rate, duration)
+	return newLoadTestJobSpec(corev1.Container{
+		Name:  "vegeta",
+		Image: image,
+		Env: []corev1.EnvVar{
+			{Name: "VEGETA_METHOD", Value: strings.ToUpper(method)},
+			{Name: "VEGETA_PATH", Value: path},
+		},
+		Command: []string{"sh", "-c", attack},
+	}), nil
+}
+
+// newLoadTestJobSpec returns the spec of a Job which runs a load test container once
+func newLoadTestJobSpec(container corev1.Container) batchv1.JobSpec {
+	return batchv1.JobSpec{
+		BackoffLimit: ptr.To[int32](0),
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers:    []corev1.Container{container},
+				RestartPolicy: corev1.RestartPolicyNever,
+			},
+		},
+	}
+}
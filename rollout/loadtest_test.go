@@ -0,0 +1,196 @@
+package rollout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	batchlisters "k8s.io/client-go/listers/batch/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	logutil "github.com/argoproj/argo-rollouts/utils/log"
+	"github.com/argoproj/argo-rollouts/utils/record"
+)
+
+func newLoadTestContext(ro *v1alpha1.Rollout, updating bool, client *k8sfake.Clientset) (*rolloutContext, *record.FakeEventRecorder) {
+	recorder := record.NewFakeEventRecorder()
+	stableRS := newReplicaSetWithStatus(ro, 5, 5)
+	newRS := stableRS
+	if updating {
+		newRS = newReplicaSetWithStatus(bumpVersion(ro), 1, 1)
+	}
+	return &rolloutContext{
+		rollout:      ro,
+		newRS:        newRS,
+		stableRS:     stableRS,
+		log:          logutil.WithRollout(ro),
+		pauseContext: &pauseContext{rollout: ro},
+		reconcilerBase: reconcilerBase{
+			kubeclientset:       client,
+			jobLister:           newJobLister(client),
+			servicesLister:      newServiceLister(client),
+			replicaSetLister:    newReplicaSetLister(client),
+			recorder:            recorder,
+			enqueueRolloutAfter: func(obj any, duration time.Duration) {},
+		},
+	}, recorder
+}
+
+// newJobLister returns a lister of the Jobs of the client, like the informer once it is notified of their changes
+func newJobLister(client *k8sfake.Clientset) batchlisters.JobLister {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	jobs, _ := client.BatchV1().Jobs(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	for i := range jobs.Items {
+		_ = indexer.Add(&jobs.Items[i])
+	}
+	return batchlisters.NewJobLister(indexer)
+}
+
+// newServiceLister returns a lister of the Services of the client
+func newServiceLister(client *k8sfake.Clientset) corelisters.ServiceLister {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	services, _ := client.CoreV1().Services(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	for i := range services.Items {
+		_ = indexer.Add(&services.Items[i])
+	}
+	return corelisters.NewServiceLister(indexer)
+}
+
+// newReplicaSetLister returns a lister of the ReplicaSets of the client
+func newReplicaSetLister(client *k8sfake.Clientset) appslisters.ReplicaSetLister {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	replicaSets, _ := client.AppsV1().ReplicaSets(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	for i := range replicaSets.Items {
+		_ = indexer.Add(&replicaSets.Items[i])
+	}
+	return appslisters.NewReplicaSetLister(indexer)
+}
+
+func newLoadTestRollout(loadTest *v1alpha1.RolloutLoadTestStep) *v1alpha1.Rollout {
+	steps := []v1alpha1.CanaryStep{{LoadTest: loadTest}, {Pause: &v1alpha1.RolloutPause{}}}
+	ro := newCanaryRollout("foo", 5, nil, steps, ptr.To[int32](0), intstr.FromInt(1), intstr.FromInt(0))
+	ro.Spec.Strategy.Canary.CanaryService = "canary"
+	return ro
+}
+
+func newCanaryServiceWithPort() *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "canary", Namespace: metav1.NamespaceDefault},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 8080}}},
+	}
+}
+
+func listLoadTestJobs(t *testing.T, client *k8sfake.Clientset) []batchv1.Job {
+	jobs, err := client.BatchV1().Jobs(metav1.NamespaceDefault).List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	return jobs.Items
+}
+
+func TestReconcileLoadTest(t *testing.T) {
+	ro := newLoadTestRollout(&v1alpha1.RolloutLoadTestStep{Vegeta: &v1alpha1.VegetaLoadTest{Rate: 50, Path: "healthz"}})
+	client := k8sfake.NewSimpleClientset(newCanaryServiceWithPort())
+	ctx, recorder := newLoadTestContext(ro, true, client)
+
+	require.NoError(t, ctx.reconcileLoadTest())
+	assert.True(t, ctx.loadTestCompleted)
+	assert.Equal(t, []string{conditions.LoadTestStartedReason}, recorder.Events())
+	jobs := listLoadTestJobs(t, client)
+	require.Len(t, jobs, 1)
+	podHash := ctx.newRS.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	assert.Equal(t, "foo-"+podHash+"-0-loadtest", jobs[0].Name)
+	assert.Equal(t, "0", jobs[0].Labels[LoadTestLabelKey])
+	container := jobs[0].Spec.Template.Spec.Containers[0]
+	assert.Equal(t, DefaultVegetaImage, container.Image)
+	assert.Equal(t, []string{"sh", "-c", `echo "$VEGETA_METHOD $CANARY_URL$VEGETA_PATH" | vegeta attack -rate=50 -duration=1m0s | vegeta report`}, container.Command)
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "VEGETA_METHOD", Value: "GET"})
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "VEGETA_PATH", Value: "/healthz"})
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "CANARY_URL", Value: "http://canary.default.svc.cluster.local:8080"})
+
+	// the load test keeps running during the next steps
+	ctx.jobLister = newJobLister(client)
+	ro.Status.CurrentStepIndex = ptr.To[int32](1)
+	ctx.loadTestCompleted = false
+	require.NoError(t, ctx.reconcileLoadTest())
+	assert.Len(t, listLoadTestJobs(t, client), 1)
+
+	// the Job is deleted once the update is aborted
+	ro.Status.Abort = true
+	require.NoError(t, ctx.reconcileLoadTest())
+	assert.Empty(t, listLoadTestJobs(t, client))
+	assert.False(t, ctx.loadTestCompleted)
+}
+
+func TestReconcileLoadTestWait(t *testing.T) {
+	ro := newLoadTestRollout(&v1alpha1.RolloutLoadTestStep{K6: &v1alpha1.K6LoadTest{Script: "export default function () {}"}, Wait: true})
+	client := k8sfake.NewSimpleClientset(newCanaryServiceWithPort())
+	ctx, _ := newLoadTestContext(ro, true, client)
+
+	require.NoError(t, ctx.reconcileLoadTest())
+	assert.False(t, ctx.loadTestCompleted)
+	jobs := listLoadTestJobs(t, client)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, DefaultK6Image, jobs[0].Spec.Template.Spec.Containers[0].Image)
+
+	job := jobs[0].DeepCopy()
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	_, err := client.BatchV1().Jobs(metav1.NamespaceDefault).UpdateStatus(context.TODO(), job, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	ctx.jobLister = newJobLister(client)
+	require.NoError(t, ctx.reconcileLoadTest())
+	assert.True(t, ctx.loadTestCompleted)
+}
+
+func TestReconcileLoadTestFailed(t *testing.T) {
+	ro := newLoadTestRollout(&v1alpha1.RolloutLoadTestStep{JobSpec: &batchv1.JobSpec{}, Wait: true})
+	client := k8sfake.NewSimpleClientset(newCanaryServiceWithPort())
+	ctx, recorder := newLoadTestContext(ro, true, client)
+	require.NoError(t, ctx.reconcileLoadTest())
+
+	job := listLoadTestJobs(t, client)[0].DeepCopy()
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"}}
+	_, err := client.BatchV1().Jobs(metav1.NamespaceDefault).UpdateStatus(context.TODO(), job, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	ctx.jobLister = newJobLister(client)
+	require.NoError(t, ctx.reconcileLoadTest())
+	assert.False(t, ctx.loadTestCompleted)
+	assert.True(t, ctx.pauseContext.addAbort)
+	assert.Equal(t, []string{conditions.LoadTestStartedReason, conditions.LoadTestFailedReason}, recorder.Events())
+}
+
+func TestReconcileLoadTestIgnoresOtherJobs(t *testing.T) {
+	ro := newLoadTestRollout(&v1alpha1.RolloutLoadTestStep{JobSpec: &batchv1.JobSpec{}})
+	// a Job of the rollout which is not a load test, e.g. the Job of a migration step
+	migration := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+		Name:            "foo-migration",
+		Namespace:       metav1.NamespaceDefault,
+		Labels:          map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: "abc", MigrationLabelKey: "migration"},
+		OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(ro, controllerKind)},
+	}}
+	client := k8sfake.NewSimpleClientset(newCanaryServiceWithPort(), migration)
+	ctx, _ := newLoadTestContext(ro, false, client)
+
+	require.NoError(t, ctx.reconcileLoadTest())
+	assert.Len(t, listLoadTestJobs(t, client), 1)
+}
+
+func TestNewVegetaJobSpecDoesNotInterpretMethodAndPath(t *testing.T) {
+	spec, err := newVegetaJobSpec(&v1alpha1.VegetaLoadTest{Method: "get$(id)", Path: "/\"; rm -rf /; echo \""})
+	require.NoError(t, err)
+	container := spec.Template.Spec.Containers[0]
+	assert.NotContains(t, container.Command[2], "rm -rf")
+	assert.NotContains(t, container.Command[2], "$(id)")
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "VEGETA_METHOD", Value: "GET$(ID)"})
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "VEGETA_PATH", Value: "/\"; rm -rf /; echo \""})
+}
@@ -0,0 +1,471 @@
+package rollout
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/jsonpath"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+	"github.com/argoproj/argo-rollouts/utils/record"
+	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
+	timeutil "github.com/argoproj/argo-rollouts/utils/time"
+)
+
+const (
+	// MigrationLabelKey labels the Jobs of migration steps with the kind of the Job, i.e. migration or rollback
+	MigrationLabelKey = "rollouts-migration"
+	migrationJobKind  = "migration"
+	rollbackJobKind   = "rollback"
+	// migrationResyncPeriod is the period at which a rollout waiting on the Job of a migration step checks the Job
+	migrationResyncPeriod = 10 * time.Second
+	// migrationStatusCheckTimeout is the timeout of the requests of migration status checks
+	migrationStatusCheckTimeout = 30 * time.Second
+	// defaultMigrationCompletedValue and defaultMigrationFailedValue are the statuses of completed and failed
+	// migrations reported by a migration status check
+	defaultMigrationCompletedValue = "completed"
+	defaultMigrationFailedValue    = "failed"
+)
+
+// reconcileMigrations runs the migrations of the current migration step, and records whether they are completed. The
+// rollout is aborted when the migrations fail or time out. When the update is aborted, the rollback Jobs of the
+// started migration steps are run one after another, the last step first. The Jobs of the migration steps are kept
+// until the update is fully promoted or replaced, except for the rollback Jobs which are still running, e.g. when the
+// spec of an aborted update is reverted to the stable one. These are kept, along with their status, until they
+// complete. A migration step which is reached again after its migrations were rolled back, i.e. when an aborted update
+// is retried, runs the migrations again.
+func (c *rolloutContext) reconcileMigrations() error {
+	steps := c.rollout.Spec.Strategy.Canary.Steps
+	if !slices.ContainsFunc(steps, func(step v1alpha1.CanaryStep) bool { return step.Migration != nil }) &&
+		len(c.rollout.Status.Canary.Migrations) == 0 {
+		return nil
+	}
+	ctx := context.TODO()
+
+	var podHash string
+	if c.newRS != nil && replicasetutil.CheckStableRSExists(c.newRS, c.stableRS) {
+		podHash = c.newRS.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	}
+	c.migrations = []v1alpha1.MigrationStatus{}
+	// previous holds the rollbacks of previous updates which are still running
+	var previous []v1alpha1.MigrationStatus
+	rollingBack := map[string]bool{}
+	for _, status := range c.rollout.Status.Canary.Migrations {
+		if podHash != "" && status.PodTemplateHash == podHash && int(status.StepIndex) < len(steps) && steps[status.StepIndex].Migration != nil {
+			c.migrations = append(c.migrations, *status.DeepCopy())
+		} else if status.PodTemplateHash != podHash && status.Phase == v1alpha1.MigrationPhaseRollingBack {
+			previous = append(previous, *status.DeepCopy())
+			rollingBack[migrationJobName(c.rollout, status.PodTemplateHash, status.StepIndex, rollbackJobKind)] = true
+		}
+	}
+
+	client := c.kubeclientset.BatchV1().Jobs(c.rollout.Namespace)
+	jobs, err := client.List(ctx, metav1.ListOptions{LabelSelector: MigrationLabelKey})
+	if err != nil {
+		return fmt.Errorf("failed to list migration Jobs: %w", err)
+	}
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if !metav1.IsControlledBy(job, c.rollout) || (podHash != "" && job.Labels[v1alpha1.DefaultRolloutUniqueLabelKey] == podHash) || rollingBack[job.Name] {
+			continue
+		}
+		if err := c.deleteMigrationJob(ctx, job.Name); err != nil {
+			return err
+		}
+	}
+
+	previous, err = c.syncPreviousMigrationRollbacks(ctx, previous)
+	if err == nil && podHash != "" {
+		err = c.reconcileUpdateMigrations(ctx, podHash)
+	}
+	c.migrations = append(c.migrations, previous...)
+	return err
+}
+
+// reconcileUpdateMigrations runs the migrations, or the rollbacks, of the update with the pod template hash
+func (c *rolloutContext) reconcileUpdateMigrations(ctx context.Context, podHash string) error {
+	if c.pauseContext.IsAborted() {
+		return c.rollbackMigrations(ctx, podHash)
+	}
+	step, index := replicasetutil.GetCurrentCanaryStep(c.rollout)
+	if step == nil || step.Migration == nil {
+		return nil
+	}
+	return c.runMigration(ctx, podHash, *index, step.Migration)
+}
+
+// syncPreviousMigrationRollbacks updates the rollbacks of previous updates from their rollback Jobs, and returns the
+// ones which are still running. The rollback Jobs which finished are deleted.
+func (c *rolloutContext) syncPreviousMigrationRollbacks(ctx context.Context, previous []v1alpha1.MigrationStatus) ([]v1alpha1.MigrationStatus, error) {
+	var running []v1alpha1.MigrationStatus
+	for _, status := range previous {
+		name := migrationJobName(c.rollout, status.PodTemplateHash, status.StepIndex, rollbackJobKind)
+		job, err := c.kubeclientset.BatchV1().Jobs(c.rollout.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get migration Job %s: %w", name, err)
+		}
+		phase, message := migrationJobPhase(job)
+		switch phase {
+		case v1alpha1.MigrationPhaseSuccessful:
+			c.recorder.Eventf(c.rollout, record.EventOptions{EventReason: conditions.MigrationRolledBackReason}, conditions.MigrationRolledBackMessage, status.StepIndex+1)
+		case v1alpha1.MigrationPhaseFailed:
+			c.recorder.Warnf(c.rollout, record.EventOptions{EventReason: conditions.MigrationRollbackFailedReason}, conditions.MigrationRollbackFailedMessage, name, status.StepIndex+1, message)
+		default:
+			running = append(running, status)
+			c.enqueueRolloutAfter(c.rollout, migrationResyncPeriod)
+			continue
+		}
+		if err := c.deleteMigrationJob(ctx, name); err != nil {
+			return nil, err
+		}
+	}
+	return running, nil
+}
+
+// runMigration starts the migrations of the migration step, and checks whether they are completed
+func (c *rolloutContext) runMigration(ctx context.Context, podHash string, index int32, migration *v1alpha1.RolloutMigrationStep) error {
+	name := migrationJobName(c.rollout, podHash, index, migrationJobKind)
+	i := slices.IndexFunc(c.migrations, func(status v1alpha1.MigrationStatus) bool { return status.StepIndex == index })
+	if i >= 0 {
+		switch c.migrations[i].Phase {
+		case v1alpha1.MigrationPhaseRollingBack:
+			// the migrations are run again once they are rolled back
+			_, err := c.syncMigrationRollback(ctx, podHash, &c.migrations[i])
+			return err
+		case v1alpha1.MigrationPhaseFailed, v1alpha1.MigrationPhaseRolledBack, v1alpha1.MigrationPhaseRollbackFailed:
+			if err := c.deleteMigrationJob(ctx, name); err != nil {
+				return err
+			}
+			if err := c.deleteMigrationJob(ctx, migrationJobName(c.rollout, podHash, index, rollbackJobKind)); err != nil {
+				return err
+			}
+			c.migrations = slices.Delete(c.migrations, i, i+1)
+			c.enqueueRolloutAfter(c.rollout, migrationResyncPeriod)
+			return nil
+		}
+	} else {
+		c.migrations = append(c.migrations, v1alpha1.MigrationStatus{
+			StepIndex:       index,
+			PodTemplateHash: podHash,
+			Phase:           v1alpha1.MigrationPhaseRunning,
+			StartedAt:       metav1.NewTime(timeutil.Now()),
+		})
+		i = len(c.migrations) - 1
+		c.recorder.Eventf(c.rollout, record.EventOptions{EventReason: conditions.MigrationStartedReason}, conditions.MigrationStartedMessage, index+1)
+	}
+	status := &c.migrations[i]
+	if status.Phase == v1alpha1.MigrationPhaseSuccessful {
+		c.migrationCompleted = true
+		return nil
+	}
+
+	resyncPeriod := migrationResyncPeriod
+	var phase v1alpha1.MigrationPhase
+	var message string
+	if migration.JobSpec != nil {
+		job, err := c.getOrCreateMigrationJob(ctx, name, podHash, migrationJobKind, migration.JobSpec)
+		if err != nil {
+			return err
+		}
+		phase, message = migrationJobPhase(job)
+	} else if migration.StatusCheck != nil {
+		if migration.StatusCheck.Interval != "" {
+			if interval, err := migration.StatusCheck.Interval.Duration(); err == nil && interval > 0 {
+				resyncPeriod = interval
+			}
+		}
+		phase, message = c.checkMigrationStatus(ctx, podHash, index, migration.StatusCheck)
+		if phase == "" {
+			// the status check is running, and the rollout is reconciled again once it completed
+			phase, message = v1alpha1.MigrationPhaseRunning, status.Message
+			resyncPeriod = migrationStatusCheckTimeout
+		}
+	}
+	if phase == v1alpha1.MigrationPhaseRunning && migration.Timeout != "" {
+		if timeout, err := migration.Timeout.Duration(); err == nil && timeutil.Now().After(status.StartedAt.Add(timeout)) {
+			phase, message = v1alpha1.MigrationPhaseFailed, fmt.Sprintf("migrations did not complete within %s", migration.Timeout)
+		}
+	}
+	status.Message = message
+	switch phase {
+	case v1alpha1.MigrationPhaseSuccessful:
+		status.Phase = phase
+		c.migrationCompleted = true
+		c.recorder.Eventf(c.rollout, record.EventOptions{EventReason: conditions.MigrationCompletedReason}, conditions.MigrationCompletedMessage, index+1)
+	case v1alpha1.MigrationPhaseFailed:
+		status.Phase = phase
+		failure := fmt.Sprintf(conditions.MigrationFailedMessage, index+1, message)
+		c.recorder.Warnf(c.rollout, record.EventOptions{EventReason: conditions.MigrationFailedReason}, "%s", failure)
+		c.pauseContext.AddAbort(failure)
+	default:
+		c.enqueueRolloutAfter(c.rollout, resyncPeriod)
+	}
+	return nil
+}
+
+// rollbackMigrations runs the rollback Jobs of the started migration steps of the aborted update one after another,
+// the last step first. The rollback of a step waits on the migration Job of the step to finish, and the rollbacks stop
+// at the first failed rollback Job.
+func (c *rolloutContext) rollbackMigrations(ctx context.Context, podHash string) error {
+	steps := c.rollout.Spec.Strategy.Canary.Steps
+	slices.SortFunc(c.migrations, func(a, b v1alpha1.MigrationStatus) int { return int(a.StepIndex - b.StepIndex) })
+	for i := len(c.migrations) - 1; i >= 0; i-- {
+		status := &c.migrations[i]
+		migration := steps[status.StepIndex].Migration
+		if migration.RollbackJobSpec == nil {
+			continue
+		}
+		switch status.Phase {
+		case v1alpha1.MigrationPhaseRolledBack:
+			continue
+		case v1alpha1.MigrationPhaseRollbackFailed:
+			return nil
+		case v1alpha1.MigrationPhaseRunning, v1alpha1.MigrationPhaseSuccessful, v1alpha1.MigrationPhaseFailed:
+			if migration.JobSpec != nil {
+				job, err := c.kubeclientset.BatchV1().Jobs(c.rollout.Namespace).Get(ctx, migrationJobName(c.rollout, podHash, status.StepIndex, migrationJobKind), metav1.GetOptions{})
+				if err != nil && !k8serrors.IsNotFound(err) {
+					return fmt.Errorf("failed to get migration Job: %w", err)
+				}
+				if err == nil {
+					if phase, _ := migrationJobPhase(job); phase == v1alpha1.MigrationPhaseRunning {
+						c.enqueueRolloutAfter(c.rollout, migrationResyncPeriod)
+						return nil
+					}
+				}
+			}
+			name := migrationJobName(c.rollout, podHash, status.StepIndex, rollbackJobKind)
+			if _, err := c.getOrCreateMigrationJob(ctx, name, podHash, rollbackJobKind, migration.RollbackJobSpec); err != nil {
+				return err
+			}
+			status.Phase = v1alpha1.MigrationPhaseRollingBack
+			status.Message = ""
+			c.recorder.Eventf(c.rollout, record.EventOptions{EventReason: conditions.MigrationRollbackStartedReason}, conditions.MigrationRollbackStartedMessage, name, status.StepIndex+1)
+			c.enqueueRolloutAfter(c.rollout, migrationResyncPeriod)
+			return nil
+		}
+		done, err := c.syncMigrationRollback(ctx, podHash, status)
+		if err != nil || !done || status.Phase == v1alpha1.MigrationPhaseRollbackFailed {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncMigrationRollback updates the phase of a rolling back migration step from its rollback Job, and returns whether
+// the rollback Job finished
+func (c *rolloutContext) syncMigrationRollback(ctx context.Context, podHash string, status *v1alpha1.MigrationStatus) (bool, error) {
+	name := migrationJobName(c.rollout, podHash, status.StepIndex, rollbackJobKind)
+	rollback := c.rollout.Spec.Strategy.Canary.Steps[status.StepIndex].Migration.RollbackJobSpec
+	if rollback == nil {
+		status.Phase = v1alpha1.MigrationPhaseRolledBack
+		return true, nil
+	}
+	job, err := c.getOrCreateMigrationJob(ctx, name, podHash, rollbackJobKind, rollback)
+	if err != nil {
+		return false, err
+	}
+	phase, message := migrationJobPhase(job)
+	switch phase {
+	case v1alpha1.MigrationPhaseSuccessful:
+		status.Phase = v1alpha1.MigrationPhaseRolledBack
+		c.recorder.Eventf(c.rollout, record.EventOptions{EventReason: conditions.MigrationRolledBackReason}, conditions.MigrationRolledBackMessage, status.StepIndex+1)
+		return true, nil
+	case v1alpha1.MigrationPhaseFailed:
+		status.Phase = v1alpha1.MigrationPhaseRollbackFailed
+		status.Message = message
+		c.recorder.Warnf(c.rollout, record.EventOptions{EventReason: conditions.MigrationRollbackFailedReason}, conditions.MigrationRollbackFailedMessage, name, status.StepIndex+1, message)
+		return true, nil
+	}
+	c.enqueueRolloutAfter(c.rollout, migrationResyncPeriod)
+	return false, nil
+}
+
+// getOrCreateMigrationJob returns the migration or rollback Job, and creates it if it does not exist
+func (c *rolloutContext) getOrCreateMigrationJob(ctx context.Context, name, podHash, kind string, spec *batchv1.JobSpec) (*batchv1.Job, error) {
+	client := c.kubeclientset.BatchV1().Jobs(c.rollout.Namespace)
+	job, err := client.Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		return job, nil
+	}
+	if !k8serrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get migration Job %s: %w", name, err)
+	}
+	job = c.newMigrationJob(name, podHash, kind, spec)
+	created, err := client.Create(ctx, job, metav1.CreateOptions{FieldManager: defaults.DefaultFieldManager})
+	if k8serrors.IsAlreadyExists(err) {
+		return job, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migration Job %s: %w", name, err)
+	}
+	c.log.Infof("Created %s Job %s", kind, name)
+	return created, nil
+}
+
+// newMigrationJob returns a migration or rollback Job. The name and namespace of the rollout and the pod template hash
+// of the update are passed to all containers of the Job.
+func (c *rolloutContext) newMigrationJob(name, podHash, kind string, spec *batchv1.JobSpec) *batchv1.Job {
+	jobSpec := *spec.DeepCopy()
+	env := []corev1.EnvVar{
+		{Name: "ROLLOUT_NAME", Value: c.rollout.Name},
+		{Name: "ROLLOUT_NAMESPACE", Value: c.rollout.Namespace},
+		{Name: "ROLLOUT_POD_TEMPLATE_HASH", Value: podHash},
+	}
+	for i := range jobSpec.Template.Spec.Containers {
+		jobSpec.Template.Spec.Containers[i].Env = append(jobSpec.Template.Spec.Containers[i].Env, env...)
+	}
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: c.rollout.Namespace,
+			Labels: map[string]string{
+				v1alpha1.DefaultRolloutUniqueLabelKey: podHash,
+				MigrationLabelKey:                     kind,
+			},
+			Annotations: map[string]string{
+				v1alpha1.ManagedByRolloutsKey: c.rollout.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(c.rollout, controllerKind)},
+		},
+		Spec: jobSpec,
+	}
+}
+
+func (c *rolloutContext) deleteMigrationJob(ctx context.Context, name string) error {
+	err := c.kubeclientset.BatchV1().Jobs(c.rollout.Namespace).Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: ptr.To(metav1.DeletePropagationBackground)})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete migration Job %s: %w", name, err)
+	}
+	c.log.Infof("Deleted migration Job %s", name)
+	return nil
+}
+
+// migrationStatusCheckResult is the phase and message of the migrations reported by a status check
+type migrationStatusCheckResult struct {
+	phase   v1alpha1.MigrationPhase
+	message string
+}
+
+// checkMigrationStatus returns the status of the migrations of the step reported by the last status check, and starts
+// the next status check in the background. The authorization secret is read right away. It returns an empty phase
+// while the status check is running.
+func (c *rolloutContext) checkMigrationStatus(ctx context.Context, podHash string, index int32, check *v1alpha1.MigrationStatusCheck) (v1alpha1.MigrationPhase, string) {
+	taskKey := fmt.Sprintf("%s/%s/migration/%s/%d", c.rollout.Namespace, c.rollout.Name, podHash, index)
+	if result, done, _ := c.migrationStatusChecks.result(taskKey); done {
+		return result.phase, result.message
+	}
+	req, err := http.NewRequest(http.MethodGet, strings.ReplaceAll(check.URL, "{{hash}}", podHash), nil)
+	if err != nil {
+		return v1alpha1.MigrationPhaseFailed, err.Error()
+	}
+	for _, header := range check.Headers {
+		req.Header.Set(header.Key, header.Value)
+	}
+	if check.AuthorizationSecretRef != nil {
+		authorization, err := c.getSecretKey(ctx, *check.AuthorizationSecretRef)
+		if err != nil {
+			return v1alpha1.MigrationPhaseRunning, err.Error()
+		}
+		req.Header.Set("Authorization", string(authorization))
+	}
+	rollout, enqueueRollout := c.rollout, c.enqueueRollout
+	c.migrationStatusChecks.start(taskKey, func() (migrationStatusCheckResult, error) {
+		phase, message := requestMigrationStatus(req, check)
+		return migrationStatusCheckResult{phase: phase, message: message}, nil
+	}, func() {
+		enqueueRollout(rollout)
+	})
+	return "", ""
+}
+
+// requestMigrationStatus requests the status of the migrations of the update from the endpoint of the status check. An
+// endpoint which can't be reached, or responds with an error, leaves the migrations running.
+func requestMigrationStatus(req *http.Request, check *v1alpha1.MigrationStatusCheck) (v1alpha1.MigrationPhase, string) {
+	ctx, cancel := context.WithTimeout(context.Background(), migrationStatusCheckTimeout)
+	defer cancel()
+	resp, err := (&http.Client{Timeout: migrationStatusCheckTimeout}).Do(req.WithContext(ctx))
+	if err != nil {
+		return v1alpha1.MigrationPhaseRunning, fmt.Sprintf("status check failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return v1alpha1.MigrationPhaseRunning, fmt.Sprintf("status check failed: %v", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return v1alpha1.MigrationPhaseRunning, fmt.Sprintf("status check returned %s", resp.Status)
+	}
+	if check.JSONPath == "" {
+		return v1alpha1.MigrationPhaseSuccessful, ""
+	}
+	parser := jsonpath.New("migration")
+	if err := parser.Parse(check.JSONPath); err != nil {
+		return v1alpha1.MigrationPhaseFailed, fmt.Sprintf("invalid jsonPath %s: %v", check.JSONPath, err)
+	}
+	var data any
+	if err := json.Unmarshal(body, &data); err != nil {
+		return v1alpha1.MigrationPhaseRunning, fmt.Sprintf("status check returned invalid JSON: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := parser.Execute(&buf, data); err != nil {
+		return v1alpha1.MigrationPhaseRunning, fmt.Sprintf("status check returned no status: %v", err)
+	}
+	completedValue, failedValue := check.CompletedValue, check.FailedValue
+	if completedValue == "" {
+		completedValue = defaultMigrationCompletedValue
+	}
+	if failedValue == "" {
+		failedValue = defaultMigrationFailedValue
+	}
+	switch value := buf.String(); value {
+	case completedValue:
+		return v1alpha1.MigrationPhaseSuccessful, ""
+	case failedValue:
+		return v1alpha1.MigrationPhaseFailed, fmt.Sprintf("status check reported %s", value)
+	default:
+		return v1alpha1.MigrationPhaseRunning, fmt.Sprintf("status check reported %s", value)
+	}
+}
+
+// migrationJobPhase returns the phase of the migrations of a Job, and the message of a failed Job
+func migrationJobPhase(job *batchv1.Job) (v1alpha1.MigrationPhase, string) {
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			return v1alpha1.MigrationPhaseSuccessful, ""
+		case batchv1.JobFailed:
+			return v1alpha1.MigrationPhaseFailed, cond.Message
+		}
+	}
+	return v1alpha1.MigrationPhaseRunning, ""
+}
+
+func migrationJobName(ro *v1alpha1.Rollout, podHash string, index int32, kind string) string {
+	if kind == rollbackJobKind {
+		return fmt.Sprintf("%s-%s-%d-migration-rollback", ro.Name, podHash, index)
+	}
+	return fmt.Sprintf("%s-%s-%d-migration", ro.Name, podHash, index)
+}
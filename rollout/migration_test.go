@@ -0,0 +1,257 @@
+package rollout
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	timeutil "github.com/argoproj/argo-rollouts/utils/time"
+)
+
+func newMigrationRollout(migration *v1alpha1.RolloutMigrationStep) *v1alpha1.Rollout {
+	steps := []v1alpha1.CanaryStep{{Migration: migration}, {SetWeight: ptr.To[int32](50)}}
+	return newCanaryRollout("foo", 5, nil, steps, ptr.To[int32](0), intstr.FromInt(1), intstr.FromInt(0))
+}
+
+func newMigrationJobSpec(name string) *batchv1.JobSpec {
+	return &batchv1.JobSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+		Containers: []corev1.Container{{Name: name, Image: name}},
+	}}}
+}
+
+// finishJob sets the Job to completed or failed
+func finishJob(t *testing.T, client *k8sfake.Clientset, name string, condition batchv1.JobConditionType) {
+	job, err := client.BatchV1().Jobs(metav1.NamespaceDefault).Get(t.Context(), name, metav1.GetOptions{})
+	require.NoError(t, err)
+	job.Status.Conditions = []batchv1.JobCondition{{Type: condition, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"}}
+	_, err = client.BatchV1().Jobs(metav1.NamespaceDefault).UpdateStatus(t.Context(), job, metav1.UpdateOptions{})
+	require.NoError(t, err)
+}
+
+// reconcileMigrationStatusCheck reconciles the migrations of the rollout once the status check started by a first
+// reconciliation completed
+func reconcileMigrationStatusCheck(t *testing.T, ro *v1alpha1.Rollout, client *k8sfake.Clientset) *rolloutContext {
+	t.Helper()
+	ctx, _ := newLoadTestContext(ro, true, client)
+	ctx.migrationStatusChecks = newBackgroundTasks[migrationStatusCheckResult]()
+	enqueued := make(chan struct{}, 1)
+	ctx.enqueueRollout = func(obj any) { enqueued <- struct{}{} }
+	require.NoError(t, ctx.reconcileMigrations())
+	waitEnqueued(t, enqueued)
+	require.NoError(t, ctx.reconcileMigrations())
+	return ctx
+}
+
+func TestReconcileMigrationJob(t *testing.T) {
+	ro := newMigrationRollout(&v1alpha1.RolloutMigrationStep{JobSpec: newMigrationJobSpec("migrate")})
+	client := k8sfake.NewSimpleClientset()
+	ctx, recorder := newLoadTestContext(ro, true, client)
+
+	require.NoError(t, ctx.reconcileMigrations())
+	assert.False(t, ctx.completedCurrentCanaryStep())
+	require.Len(t, ctx.migrations, 1)
+	assert.Equal(t, v1alpha1.MigrationPhaseRunning, ctx.migrations[0].Phase)
+	assert.Equal(t, []string{conditions.MigrationStartedReason}, recorder.Events())
+
+	podHash := ctx.newRS.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	name := "foo-" + podHash + "-0-migration"
+	job, err := client.BatchV1().Jobs(metav1.NamespaceDefault).Get(t.Context(), name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "migration", job.Labels[MigrationLabelKey])
+	assert.Contains(t, job.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "ROLLOUT_POD_TEMPLATE_HASH", Value: podHash})
+
+	finishJob(t, client, name, batchv1.JobComplete)
+	ro.Status.Canary.Migrations = ctx.migrations
+	ctx, recorder = newLoadTestContext(ro, true, client)
+	require.NoError(t, ctx.reconcileMigrations())
+	assert.True(t, ctx.completedCurrentCanaryStep())
+	assert.Equal(t, v1alpha1.MigrationPhaseSuccessful, ctx.migrations[0].Phase)
+	assert.Equal(t, []string{conditions.MigrationCompletedReason}, recorder.Events())
+
+	// the Jobs are deleted once the update is fully promoted
+	ro.Status.Canary.Migrations = ctx.migrations
+	ctx, _ = newLoadTestContext(ro, false, client)
+	require.NoError(t, ctx.reconcileMigrations())
+	assert.Empty(t, ctx.migrations)
+	jobs, err := client.BatchV1().Jobs(metav1.NamespaceDefault).List(t.Context(), metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, jobs.Items)
+}
+
+func TestReconcileMigrationJobFailed(t *testing.T) {
+	ro := newMigrationRollout(&v1alpha1.RolloutMigrationStep{JobSpec: newMigrationJobSpec("migrate")})
+	client := k8sfake.NewSimpleClientset()
+	ctx, _ := newLoadTestContext(ro, true, client)
+	require.NoError(t, ctx.reconcileMigrations())
+	finishJob(t, client, "foo-"+ctx.newRS.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]+"-0-migration", batchv1.JobFailed)
+
+	ro.Status.Canary.Migrations = ctx.migrations
+	ctx, recorder := newLoadTestContext(ro, true, client)
+	require.NoError(t, ctx.reconcileMigrations())
+	assert.False(t, ctx.completedCurrentCanaryStep())
+	assert.True(t, ctx.pauseContext.IsAborted())
+	assert.Equal(t, v1alpha1.MigrationPhaseFailed, ctx.migrations[0].Phase)
+	assert.Equal(t, "BackoffLimitExceeded", ctx.migrations[0].Message)
+	assert.Equal(t, []string{conditions.MigrationFailedReason}, recorder.Events())
+}
+
+func TestReconcileMigrationRollback(t *testing.T) {
+	ro := newMigrationRollout(&v1alpha1.RolloutMigrationStep{
+		JobSpec:         newMigrationJobSpec("migrate"),
+		RollbackJobSpec: newMigrationJobSpec("rollback"),
+	})
+	client := k8sfake.NewSimpleClientset()
+	ctx, _ := newLoadTestContext(ro, true, client)
+	require.NoError(t, ctx.reconcileMigrations())
+	podHash := ctx.newRS.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+
+	// the rollback waits on the migration Job
+	ro.Status.Abort = true
+	ro.Status.Canary.Migrations = ctx.migrations
+	ctx, recorder := newLoadTestContext(ro, true, client)
+	require.NoError(t, ctx.reconcileMigrations())
+	assert.Equal(t, v1alpha1.MigrationPhaseRunning, ctx.migrations[0].Phase)
+	assert.Empty(t, recorder.Events())
+
+	finishJob(t, client, "foo-"+podHash+"-0-migration", batchv1.JobComplete)
+	ctx, recorder = newLoadTestContext(ro, true, client)
+	require.NoError(t, ctx.reconcileMigrations())
+	assert.Equal(t, v1alpha1.MigrationPhaseRollingBack, ctx.migrations[0].Phase)
+	assert.Equal(t, []string{conditions.MigrationRollbackStartedReason}, recorder.Events())
+
+	finishJob(t, client, "foo-"+podHash+"-0-migration-rollback", batchv1.JobComplete)
+	ro.Status.Canary.Migrations = ctx.migrations
+	ctx, recorder = newLoadTestContext(ro, true, client)
+	require.NoError(t, ctx.reconcileMigrations())
+	assert.Equal(t, v1alpha1.MigrationPhaseRolledBack, ctx.migrations[0].Phase)
+	assert.Equal(t, []string{conditions.MigrationRolledBackReason}, recorder.Events())
+
+	// a retried update runs the migrations again
+	ro.Status.Abort = false
+	ro.Status.Canary.Migrations = ctx.migrations
+	ctx, _ = newLoadTestContext(ro, true, client)
+	require.NoError(t, ctx.reconcileMigrations())
+	assert.Empty(t, ctx.migrations)
+	jobs, err := client.BatchV1().Jobs(metav1.NamespaceDefault).List(t.Context(), metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, jobs.Items)
+
+	ro.Status.Canary.Migrations = ctx.migrations
+	ctx, _ = newLoadTestContext(ro, true, client)
+	require.NoError(t, ctx.reconcileMigrations())
+	assert.Equal(t, v1alpha1.MigrationPhaseRunning, ctx.migrations[0].Phase)
+}
+
+func TestReconcileMigrationRollbackAfterRevert(t *testing.T) {
+	migration := &v1alpha1.RolloutMigrationStep{
+		JobSpec:         newMigrationJobSpec("migrate"),
+		RollbackJobSpec: newMigrationJobSpec("rollback"),
+	}
+	ro := newMigrationRollout(migration)
+	client := k8sfake.NewSimpleClientset()
+	ctx, _ := newLoadTestContext(ro, false, client)
+	for _, kind := range []string{migrationJobKind, rollbackJobKind} {
+		name := migrationJobName(ro, "abc123", 0, kind)
+		_, err := client.BatchV1().Jobs(metav1.NamespaceDefault).Create(t.Context(), ctx.newMigrationJob(name, "abc123", kind, migration.JobSpec), metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	// the spec of the aborted update was reverted to the stable one while its migrations are rolled back
+	ro.Status.Canary.Migrations = []v1alpha1.MigrationStatus{{StepIndex: 0, PodTemplateHash: "abc123", Phase: v1alpha1.MigrationPhaseRollingBack}}
+	ctx, recorder := newLoadTestContext(ro, false, client)
+	require.NoError(t, ctx.reconcileMigrations())
+	assert.Equal(t, ro.Status.Canary.Migrations, ctx.migrations)
+	assert.Empty(t, recorder.Events())
+	jobs, err := client.BatchV1().Jobs(metav1.NamespaceDefault).List(t.Context(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, jobs.Items, 1)
+	assert.Equal(t, "foo-abc123-0-migration-rollback", jobs.Items[0].Name)
+
+	// the rollback Job is deleted once it completed
+	finishJob(t, client, "foo-abc123-0-migration-rollback", batchv1.JobComplete)
+	ctx, recorder = newLoadTestContext(ro, false, client)
+	require.NoError(t, ctx.reconcileMigrations())
+	assert.Empty(t, ctx.migrations)
+	assert.Equal(t, []string{conditions.MigrationRolledBackReason}, recorder.Events())
+	jobs, err = client.BatchV1().Jobs(metav1.NamespaceDefault).List(t.Context(), metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, jobs.Items)
+}
+
+func TestReconcileMigrationStatusCheck(t *testing.T) {
+	status := `{"status": "running"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret-token", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(status))
+	}))
+	defer server.Close()
+	ro := newMigrationRollout(&v1alpha1.RolloutMigrationStep{
+		StatusCheck: &v1alpha1.MigrationStatusCheck{
+			URL:                    server.URL + "/migrations/{{hash}}",
+			AuthorizationSecretRef: &v1alpha1.SecretKeyRef{Name: "migrations", Key: "token"},
+			JSONPath:               "{$.status}",
+		},
+		Timeout: "30m",
+	})
+	client := k8sfake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "migrations", Namespace: metav1.NamespaceDefault},
+		Data:       map[string][]byte{"token": []byte("secret-token")},
+	})
+	// the status is checked in the background, and the migrations keep running meanwhile
+	ctx, _ := newLoadTestContext(ro, true, client)
+	ctx.migrationStatusChecks = newBackgroundTasks[migrationStatusCheckResult]()
+	ctx.enqueueRollout = func(obj any) {}
+	require.NoError(t, ctx.reconcileMigrations())
+	assert.False(t, ctx.completedCurrentCanaryStep())
+	assert.Equal(t, v1alpha1.MigrationPhaseRunning, ctx.migrations[0].Phase)
+
+	ctx = reconcileMigrationStatusCheck(t, ro, client)
+	assert.False(t, ctx.completedCurrentCanaryStep())
+	assert.Equal(t, "status check reported running", ctx.migrations[0].Message)
+
+	status = `{"status": "completed"}`
+	ro.Status.Canary.Migrations = ctx.migrations
+	ctx = reconcileMigrationStatusCheck(t, ro, client)
+	assert.True(t, ctx.completedCurrentCanaryStep())
+
+	status = `{"status": "failed"}`
+	ro.Status.Canary.Migrations = nil
+	ctx = reconcileMigrationStatusCheck(t, ro, client)
+	assert.True(t, ctx.pauseContext.IsAborted())
+	assert.Equal(t, v1alpha1.MigrationPhaseFailed, ctx.migrations[0].Phase)
+}
+
+func TestReconcileMigrationTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	ro := newMigrationRollout(&v1alpha1.RolloutMigrationStep{
+		StatusCheck: &v1alpha1.MigrationStatusCheck{URL: server.URL},
+		Timeout:     "30m",
+	})
+	ctx := reconcileMigrationStatusCheck(t, ro, k8sfake.NewSimpleClientset())
+	assert.Equal(t, v1alpha1.MigrationPhaseRunning, ctx.migrations[0].Phase)
+	assert.Equal(t, "status check returned 503 Service Unavailable", ctx.migrations[0].Message)
+
+	ro.Status.Canary.Migrations = ctx.migrations
+	ro.Status.Canary.Migrations[0].StartedAt = metav1.NewTime(timeutil.Now().Add(-time.Hour))
+	ctx, _ = newLoadTestContext(ro, true, k8sfake.NewSimpleClientset())
+	ctx.migrationStatusChecks = newBackgroundTasks[migrationStatusCheckResult]()
+	ctx.enqueueRollout = func(obj any) {}
+	require.NoError(t, ctx.reconcileMigrations())
+	assert.True(t, ctx.pauseContext.IsAborted())
+	assert.Equal(t, "migrations did not complete within 30m", ctx.migrations[0].Message)
+}
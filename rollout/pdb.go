@@ -0,0 +1,122 @@
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+	"github.com/argoproj/argo-rollouts/utils/record"
+	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
+)
+
+// reconcilePodDisruptionBudgets creates a PodDisruptionBudget for the pods of the stable ReplicaSet and one for the
+// pods of the canary ReplicaSet while the canary is rolled out, and deletes them once the update is completed or once
+// they are removed from the rollout. The PodDisruptionBudgets are named after their ReplicaSet, labeled with its pod
+// template hash, which the informer of the PodDisruptionBudgets selects, and owned by the rollout.
+func (c *rolloutContext) reconcilePodDisruptionBudgets() error {
+	spec := c.rollout.Spec.Strategy.Canary.PodDisruptionBudget
+	ctx := context.TODO()
+	client := c.kubeclientset.PolicyV1().PodDisruptionBudgets(c.rollout.Namespace)
+
+	var desired []*appsv1.ReplicaSet
+	if spec != nil && c.newRS != nil && replicasetutil.CheckStableRSExists(c.newRS, c.stableRS) {
+		desired = []*appsv1.ReplicaSet{c.stableRS, c.newRS}
+	}
+
+	selector, err := labels.Parse(v1alpha1.DefaultRolloutUniqueLabelKey)
+	if err != nil {
+		return err
+	}
+	pdbs, err := c.podDisruptionBudgetLister.PodDisruptionBudgets(c.rollout.Namespace).List(selector)
+	if err != nil {
+		return fmt.Errorf("failed to list PodDisruptionBudgets: %w", err)
+	}
+	existing := map[string]*policyv1.PodDisruptionBudget{}
+	for _, pdb := range pdbs {
+		if !metav1.IsControlledBy(pdb, c.rollout) {
+			continue
+		}
+		if slices.ContainsFunc(desired, func(rs *appsv1.ReplicaSet) bool { return rs.Name == pdb.Name }) {
+			existing[pdb.Name] = pdb
+			continue
+		}
+		err := client.Delete(ctx, pdb.Name, metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete PodDisruptionBudget %s: %w", pdb.Name, err)
+		}
+		c.recorder.Eventf(c.rollout, record.EventOptions{EventReason: "PodDisruptionBudgetDeleted"}, "Deleted PodDisruptionBudget '%s'", pdb.Name)
+	}
+
+	for _, rs := range desired {
+		name := rs.Name
+		pdbSpec := newReplicaSetPodDisruptionBudgetSpec(spec, rs)
+		pdb, ok := existing[name]
+		if !ok {
+			pdb = &policyv1.PodDisruptionBudget{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: c.rollout.Namespace,
+					Labels: map[string]string{
+						v1alpha1.DefaultRolloutUniqueLabelKey: rs.Labels[v1alpha1.DefaultRolloutUniqueLabelKey],
+					},
+					Annotations: map[string]string{
+						v1alpha1.ManagedByRolloutsKey: c.rollout.Name,
+					},
+					OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(c.rollout, controllerKind)},
+				},
+				Spec: pdbSpec,
+			}
+			_, err := client.Create(ctx, pdb, metav1.CreateOptions{FieldManager: defaults.DefaultFieldManager})
+			if k8serrors.IsAlreadyExists(err) {
+				// the PodDisruptionBudget was created by a previous reconciliation which the informer has not seen yet,
+				// unless it belongs to someone else
+				pdb, err = client.Get(ctx, name, metav1.GetOptions{})
+				if err != nil {
+					return fmt.Errorf("failed to get PodDisruptionBudget %s: %w", name, err)
+				}
+				if !metav1.IsControlledBy(pdb, c.rollout) {
+					return fmt.Errorf("PodDisruptionBudget %s already exists and is not controlled by the rollout", name)
+				}
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to create PodDisruptionBudget %s: %w", name, err)
+			}
+			c.recorder.Eventf(c.rollout, record.EventOptions{EventReason: "PodDisruptionBudgetCreated"}, "Created PodDisruptionBudget '%s'", name)
+			continue
+		}
+		if equality.Semantic.DeepEqual(pdb.Spec, pdbSpec) {
+			continue
+		}
+		pdb = pdb.DeepCopy()
+		pdb.Spec = pdbSpec
+		if _, err := client.Update(ctx, pdb, metav1.UpdateOptions{FieldManager: defaults.DefaultFieldManager}); err != nil {
+			return fmt.Errorf("failed to update PodDisruptionBudget %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// newReplicaSetPodDisruptionBudgetSpec returns the spec of the PodDisruptionBudget of the pods of a ReplicaSet
+func newReplicaSetPodDisruptionBudgetSpec(spec *v1alpha1.ReplicaSetPodDisruptionBudget, rs *appsv1.ReplicaSet) policyv1.PodDisruptionBudgetSpec {
+	pdbSpec := policyv1.PodDisruptionBudgetSpec{
+		Selector:       rs.Spec.Selector.DeepCopy(),
+		MinAvailable:   spec.MinAvailable,
+		MaxUnavailable: spec.MaxUnavailable,
+	}
+	if pdbSpec.MinAvailable == nil && pdbSpec.MaxUnavailable == nil {
+		minAvailable := intstr.FromInt32(1)
+		pdbSpec.MinAvailable = &minAvailable
+	}
+	return pdbSpec
+}
@@ -0,0 +1,146 @@
+package rollout
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	policylisters "k8s.io/client-go/listers/policy/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	logutil "github.com/argoproj/argo-rollouts/utils/log"
+	"github.com/argoproj/argo-rollouts/utils/record"
+)
+
+func newPodDisruptionBudgetContext(t *testing.T, ro *v1alpha1.Rollout, updating bool, client *k8sfake.Clientset) (*rolloutContext, cache.Indexer, *record.FakeEventRecorder) {
+	recorder := record.NewFakeEventRecorder()
+	stableRS := newReplicaSetWithStatus(ro, 5, 5)
+	newRS := stableRS
+	if updating {
+		newRS = newReplicaSetWithStatus(bumpVersion(ro), 1, 1)
+	}
+	indexer := syncPodDisruptionBudgets(t, client, nil)
+	return &rolloutContext{
+		rollout:  ro,
+		newRS:    newRS,
+		stableRS: stableRS,
+		log:      logutil.WithRollout(ro),
+		reconcilerBase: reconcilerBase{
+			kubeclientset:             client,
+			podDisruptionBudgetLister: policylisters.NewPodDisruptionBudgetLister(indexer),
+			recorder:                  recorder,
+		},
+	}, indexer, recorder
+}
+
+// syncPodDisruptionBudgets replaces the PodDisruptionBudgets of the informer cache with the ones of the client, like
+// the informer does once it is notified of the changes
+func syncPodDisruptionBudgets(t *testing.T, client *k8sfake.Clientset, indexer cache.Indexer) cache.Indexer {
+	if indexer == nil {
+		indexer = cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	}
+	pdbs, err := client.PolicyV1().PodDisruptionBudgets(metav1.NamespaceDefault).List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	var items []any
+	for i := range pdbs.Items {
+		items = append(items, &pdbs.Items[i])
+	}
+	require.NoError(t, indexer.Replace(items, ""))
+	return indexer
+}
+
+func listPodDisruptionBudgets(t *testing.T, client *k8sfake.Clientset) map[string]policyv1.PodDisruptionBudgetSpec {
+	pdbs, err := client.PolicyV1().PodDisruptionBudgets(metav1.NamespaceDefault).List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	specs := map[string]policyv1.PodDisruptionBudgetSpec{}
+	for _, pdb := range pdbs.Items {
+		specs[pdb.Name] = pdb.Spec
+	}
+	return specs
+}
+
+func TestReconcilePodDisruptionBudgets(t *testing.T) {
+	ro := newCanaryRollout("foo", 5, nil, nil, ptr.To[int32](0), intstr.FromInt(1), intstr.FromInt(0))
+	ro.Spec.Strategy.Canary.PodDisruptionBudget = &v1alpha1.ReplicaSetPodDisruptionBudget{}
+	client := k8sfake.NewSimpleClientset()
+	ctx, indexer, recorder := newPodDisruptionBudgetContext(t, ro, true, client)
+
+	require.NoError(t, ctx.reconcilePodDisruptionBudgets())
+	minAvailable := intstr.FromInt32(1)
+	assert.Equal(t, map[string]policyv1.PodDisruptionBudgetSpec{
+		ctx.stableRS.Name: {Selector: ctx.stableRS.Spec.Selector, MinAvailable: &minAvailable},
+		ctx.newRS.Name:    {Selector: ctx.newRS.Spec.Selector, MinAvailable: &minAvailable},
+	}, listPodDisruptionBudgets(t, client))
+	assert.Equal(t, []string{"PodDisruptionBudgetCreated", "PodDisruptionBudgetCreated"}, recorder.Events())
+
+	// the PodDisruptionBudgets which the informer has not seen yet are not created again
+	require.NoError(t, ctx.reconcilePodDisruptionBudgets())
+	assert.Len(t, recorder.Events(), 2)
+
+	// the PodDisruptionBudgets follow the spec of the rollout
+	syncPodDisruptionBudgets(t, client, indexer)
+	maxUnavailable := intstr.FromString("50%")
+	ro.Spec.Strategy.Canary.PodDisruptionBudget.MaxUnavailable = &maxUnavailable
+	require.NoError(t, ctx.reconcilePodDisruptionBudgets())
+	assert.Equal(t, &maxUnavailable, listPodDisruptionBudgets(t, client)[ctx.newRS.Name].MaxUnavailable)
+	assert.Nil(t, listPodDisruptionBudgets(t, client)[ctx.newRS.Name].MinAvailable)
+
+	// the PodDisruptionBudgets are deleted once the canary is promoted
+	syncPodDisruptionBudgets(t, client, indexer)
+	ctx.stableRS = ctx.newRS
+	require.NoError(t, ctx.reconcilePodDisruptionBudgets())
+	assert.Empty(t, listPodDisruptionBudgets(t, client))
+	assert.Equal(t, []string{"PodDisruptionBudgetCreated", "PodDisruptionBudgetCreated", "PodDisruptionBudgetDeleted", "PodDisruptionBudgetDeleted"}, recorder.Events())
+}
+
+func TestReconcilePodDisruptionBudgetsRemoved(t *testing.T) {
+	ro := newCanaryRollout("foo", 5, nil, nil, ptr.To[int32](0), intstr.FromInt(1), intstr.FromInt(0))
+	ro.Spec.Strategy.Canary.PodDisruptionBudget = &v1alpha1.ReplicaSetPodDisruptionBudget{}
+	client := k8sfake.NewSimpleClientset()
+	ctx, indexer, recorder := newPodDisruptionBudgetContext(t, ro, true, client)
+	require.NoError(t, ctx.reconcilePodDisruptionBudgets())
+	assert.Len(t, listPodDisruptionBudgets(t, client), 2)
+
+	// the PodDisruptionBudgets are deleted once they are removed from the rollout, even in the middle of the update
+	syncPodDisruptionBudgets(t, client, indexer)
+	ro.Spec.Strategy.Canary.PodDisruptionBudget = nil
+	require.NoError(t, ctx.reconcilePodDisruptionBudgets())
+	assert.Empty(t, listPodDisruptionBudgets(t, client))
+	assert.Equal(t, []string{"PodDisruptionBudgetCreated", "PodDisruptionBudgetCreated", "PodDisruptionBudgetDeleted", "PodDisruptionBudgetDeleted"}, recorder.Events())
+}
+
+func TestReconcilePodDisruptionBudgetsIgnoresOthers(t *testing.T) {
+	ro := newCanaryRollout("foo", 5, nil, nil, ptr.To[int32](0), intstr.FromInt(1), intstr.FromInt(0))
+	ro.Spec.Strategy.Canary.PodDisruptionBudget = &v1alpha1.ReplicaSetPodDisruptionBudget{}
+	other := &policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{
+		Name:      "foo",
+		Namespace: metav1.NamespaceDefault,
+		Labels:    map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: "abc"},
+	}}
+	client := k8sfake.NewSimpleClientset(other)
+	ctx, _, recorder := newPodDisruptionBudgetContext(t, ro, false, client)
+
+	require.NoError(t, ctx.reconcilePodDisruptionBudgets())
+	assert.Contains(t, listPodDisruptionBudgets(t, client), "foo")
+	assert.Empty(t, recorder.Events())
+}
+
+func TestReconcilePodDisruptionBudgetsAlreadyExists(t *testing.T) {
+	ro := newCanaryRollout("foo", 5, nil, nil, ptr.To[int32](0), intstr.FromInt(1), intstr.FromInt(0))
+	ro.Spec.Strategy.Canary.PodDisruptionBudget = &v1alpha1.ReplicaSetPodDisruptionBudget{}
+	stableRS := newReplicaSetWithStatus(ro, 5, 5)
+	other := &policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Name: stableRS.Name, Namespace: metav1.NamespaceDefault}}
+	client := k8sfake.NewSimpleClientset(other)
+	ctx, _, _ := newPodDisruptionBudgetContext(t, ro, true, client)
+
+	// the PodDisruptionBudget named after the stable ReplicaSet belongs to someone else
+	err := ctx.reconcilePodDisruptionBudgets()
+	assert.EqualError(t, err, "PodDisruptionBudget "+stableRS.Name+" already exists and is not controlled by the rollout")
+}
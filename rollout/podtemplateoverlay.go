@@ -0,0 +1,140 @@
+package rollout
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1beta1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+	"github.com/argoproj/argo-rollouts/utils/record"
+	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
+)
+
+// usesPodTemplateOverlay returns true if any of the canary steps overlays the canary pod template
+func usesPodTemplateOverlay(rollout *v1alpha1.Rollout) bool {
+	if rollout.Spec.Strategy.Canary == nil {
+		return false
+	}
+	for _, step := range rollout.Spec.Strategy.Canary.Steps {
+		if step.SetCanaryPodTemplateOverlay != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileCanaryPodTemplateOverlay applies the overlay of the current setCanaryPodTemplateOverlay step to the
+// pod template of the new ReplicaSet, or reverts it when the overlay is no longer in effect. Since a change to
+// the pod template of a ReplicaSet does not affect its existing pods, pods created with a different overlay are
+// evicted, up to maxUnavailable at a time. Returns the number of evicted pods.
+// The overlay is only ever changed while the new ReplicaSet is the canary: the promotion to stable waits until
+// the overlay was reverted (see shouldFullPromote), and an aborted canary is left alone while it scales down.
+func (c *rolloutContext) reconcileCanaryPodTemplateOverlay() (int, error) {
+	if c.newRS == nil || !usesPodTemplateOverlay(c.rollout) || c.pauseContext.IsAborted() ||
+		(c.stableRS != nil && c.stableRS.Name == c.newRS.Name) {
+		c.podTemplateOverlaySynced = true
+		return 0, nil
+	}
+	ctx := context.TODO()
+	overlay := replicasetutil.GetCurrentPodTemplateOverlay(c.rollout)
+	overlayHash := replicasetutil.ComputePodTemplateOverlayHash(overlay)
+
+	if c.newRS.Spec.Template.Annotations[replicasetutil.PodTemplateOverlayAnnotation] != overlayHash {
+		if err := c.syncReplicaSetPodTemplateOverlay(ctx, overlay, overlayHash); err != nil {
+			return 0, err
+		}
+	}
+	return c.replaceOverlaidPods(ctx, overlayHash)
+}
+
+// syncReplicaSetPodTemplateOverlay rebuilds the pod spec of the new ReplicaSet from the rollout and applies the overlay
+func (c *rolloutContext) syncReplicaSetPodTemplateOverlay(ctx context.Context, overlay *v1alpha1.PodTemplateOverlay, overlayHash string) error {
+	rsCopy := c.newRS.DeepCopy()
+	rsCopy.Spec.Template.Spec = *c.rollout.Spec.Template.Spec.DeepCopy()
+	rsCopy.Spec.Template.Spec.Affinity = replicasetutil.GenerateReplicaSetAffinity(*c.rollout)
+	rsCopy.Spec.Template.Spec.TopologySpreadConstraints = replicasetutil.GenerateReplicaSetTopologySpreadConstraints(*c.rollout)
+	template, err := replicasetutil.ApplyPodTemplateOverlay(&rsCopy.Spec.Template, overlay)
+	if err != nil {
+		return err
+	}
+	if overlayHash == "" {
+		delete(template.Annotations, replicasetutil.PodTemplateOverlayAnnotation)
+	} else {
+		if template.Annotations == nil {
+			template.Annotations = map[string]string{}
+		}
+		template.Annotations[replicasetutil.PodTemplateOverlayAnnotation] = overlayHash
+	}
+	rsCopy.Spec.Template = *template
+
+	rs, err := c.updateReplicaSet(ctx, rsCopy)
+	if err != nil {
+		return fmt.Errorf("failed to sync pod template overlay to ReplicaSet %s: %w", rsCopy.Name, err)
+	}
+	if overlayHash == "" {
+		c.recorder.Eventf(c.rollout, record.EventOptions{EventReason: "PodTemplateOverlayReverted"}, "Reverted pod template overlay of ReplicaSet %s", rs.Name)
+	} else {
+		c.recorder.Eventf(c.rollout, record.EventOptions{EventReason: "PodTemplateOverlayApplied"}, "Applied pod template overlay to ReplicaSet %s", rs.Name)
+	}
+	c.newRS = rs
+	return nil
+}
+
+// replaceOverlaidPods evicts the pods of the new ReplicaSet which were created with a different overlay
+func (c *rolloutContext) replaceOverlaidPods(ctx context.Context, overlayHash string) (int, error) {
+	pods, err := c.getPodsOwnedByReplicaSet(c.newRS)
+	if err != nil {
+		return 0, err
+	}
+	var outdated []*corev1.Pod
+	for _, pod := range pods {
+		if pod.Annotations[replicasetutil.PodTemplateOverlayAnnotation] != overlayHash {
+			outdated = append(outdated, pod)
+		}
+	}
+	c.podTemplateOverlaySynced = len(outdated) == 0
+	if len(outdated) == 0 {
+		return 0, nil
+	}
+
+	// maxUnavailable might be 0. we ignore this because need to be able to replace at least 1
+	concurrentReplace := maxInt(replicasetutil.MaxUnavailable(c.rollout), int32(1))
+	available := getAvailablePodCount(pods, c.rollout.Spec.MinReadySeconds)
+	canReplace := available - (defaults.GetReplicasOrDefault(c.newRS.Spec.Replicas) - concurrentReplace)
+
+	replaced := 0
+	for _, pod := range outdated {
+		if canReplace <= 0 {
+			break
+		}
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		evictTarget := policy.Eviction{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      pod.Name,
+				Namespace: pod.Namespace,
+			},
+		}
+		err := c.kubeclientset.CoreV1().Pods(pod.Namespace).Evict(ctx, &evictTarget)
+		if err != nil {
+			if k8serrors.IsTooManyRequests(err) {
+				// A PodDisruptionBudget prevented us from evicting the pod. Try again on the next reconciliation
+				c.log.WithField("Pod", pod.Name).Warn(err)
+				continue
+			}
+			return replaced, err
+		}
+		c.log.WithField("Pod", pod.Name).Info("Replacing Pod with outdated pod template overlay")
+		canReplace--
+		replaced++
+	}
+	c.log.Infof("%d/%d pods have an outdated pod template overlay. replaced %d", len(outdated), len(pods), replaced)
+	c.enqueueRolloutAfter(c.rollout, restartPodCheckTime)
+	return replaced, nil
+}
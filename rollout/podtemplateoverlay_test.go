@@ -0,0 +1,215 @@
+package rollout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	logutil "github.com/argoproj/argo-rollouts/utils/log"
+	"github.com/argoproj/argo-rollouts/utils/record"
+	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
+)
+
+func overlayPod(name string, rs *appsv1.ReplicaSet, overlayHash string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         rs.Namespace,
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour)),
+			Labels:            rs.Spec.Selector.MatchLabels,
+			OwnerReferences:   []metav1.OwnerReference{*metav1.NewControllerRef(rs, appsv1.SchemeGroupVersion.WithKind("ReplicaSet"))},
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{
+				Type:   corev1.PodReady,
+				Status: corev1.ConditionTrue,
+			}},
+		},
+	}
+	if overlayHash != "" {
+		pod.Annotations = map[string]string{replicasetutil.PodTemplateOverlayAnnotation: overlayHash}
+	}
+	return pod
+}
+
+func TestReconcileCanaryPodTemplateOverlay(t *testing.T) {
+	overlay := &v1alpha1.PodTemplateOverlay{Patch: `
+spec:
+  containers:
+  - name: container-name
+    args: ["--verbose"]
+`}
+	overlayHash := replicasetutil.ComputePodTemplateOverlayHash(overlay)
+	steps := []v1alpha1.CanaryStep{
+		{SetWeight: ptr.To[int32](10)},
+		{SetCanaryPodTemplateOverlay: overlay},
+		{SetWeight: ptr.To[int32](50)},
+	}
+	r1 := newCanaryRollout("foo", 4, nil, steps, ptr.To[int32](1), intstr.FromInt(1), intstr.FromInt(1))
+	r2 := bumpVersion(r1)
+	rs1 := newReplicaSetWithStatus(r1, 4, 4)
+	rs2 := newReplicaSetWithStatus(r2, 2, 2)
+	r2.Status.StableRS = rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	r2.Status.CurrentPodHash = rs2.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+
+	newContext := func(ro *v1alpha1.Rollout, rs *appsv1.ReplicaSet, client *fake.Clientset, pods ...*corev1.Pod) *rolloutContext {
+		podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+		for _, pod := range pods {
+			require.NoError(t, podIndexer.Add(pod))
+		}
+		return &rolloutContext{
+			rollout:      ro,
+			newRS:        rs,
+			stableRS:     rs1,
+			log:          logutil.WithRollout(ro),
+			pauseContext: &pauseContext{rollout: ro},
+			reconcilerBase: reconcilerBase{
+				kubeclientset:       client,
+				podLister:           corelisters.NewPodLister(podIndexer),
+				recorder:            record.NewFakeEventRecorder(),
+				enqueueRolloutAfter: func(obj any, duration time.Duration) {},
+			},
+		}
+	}
+	evictions := func(client *fake.Clientset) int {
+		count := 0
+		for _, action := range client.Actions() {
+			if action.Matches("create", "pods") && action.GetSubresource() == "eviction" {
+				count++
+			}
+		}
+		return count
+	}
+
+	t.Run("Applies overlay and replaces pods", func(t *testing.T) {
+		pods := []*corev1.Pod{overlayPod("pod-a", rs2, ""), overlayPod("pod-b", rs2, "")}
+		client := fake.NewSimpleClientset(rs2, pods[0], pods[1])
+		ctx := newContext(r2, rs2.DeepCopy(), client, pods...)
+
+		replaced, err := ctx.reconcileCanaryPodTemplateOverlay()
+		require.NoError(t, err)
+		assert.Equal(t, 1, replaced)
+		assert.Equal(t, 1, evictions(client))
+		assert.False(t, ctx.podTemplateOverlaySynced)
+
+		rs, err := client.AppsV1().ReplicaSets(rs2.Namespace).Get(context.TODO(), rs2.Name, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, overlayHash, rs.Spec.Template.Annotations[replicasetutil.PodTemplateOverlayAnnotation])
+		assert.Equal(t, []string{"--verbose"}, rs.Spec.Template.Spec.Containers[0].Args)
+		require.Len(t, rs.Spec.Template.Spec.Containers, 1)
+		assert.Equal(t, r2.Spec.Template.Spec.Containers[0].Image, rs.Spec.Template.Spec.Containers[0].Image)
+	})
+
+	t.Run("Does nothing once all pods are overlaid", func(t *testing.T) {
+		rs := rs2.DeepCopy()
+		rs.Spec.Template.Annotations = map[string]string{replicasetutil.PodTemplateOverlayAnnotation: overlayHash}
+		pods := []*corev1.Pod{overlayPod("pod-a", rs, overlayHash), overlayPod("pod-b", rs, overlayHash)}
+		client := fake.NewSimpleClientset(rs, pods[0], pods[1])
+		ro := r2.DeepCopy()
+		ro.Status.CurrentStepIndex = ptr.To[int32](2)
+		ctx := newContext(ro, rs, client, pods...)
+
+		replaced, err := ctx.reconcileCanaryPodTemplateOverlay()
+		require.NoError(t, err)
+		assert.Equal(t, 0, replaced)
+		assert.True(t, ctx.podTemplateOverlaySynced)
+		assert.Empty(t, client.Actions())
+	})
+
+	t.Run("Reverts overlay of the canary before promotion", func(t *testing.T) {
+		rs := rs2.DeepCopy()
+		rs.Spec.Template.Annotations = map[string]string{replicasetutil.PodTemplateOverlayAnnotation: overlayHash}
+		rs.Spec.Template.Spec.Containers[0].Args = []string{"--verbose"}
+		pods := []*corev1.Pod{overlayPod("pod-a", rs, overlayHash), overlayPod("pod-b", rs, overlayHash)}
+		client := fake.NewSimpleClientset(rs, pods[0], pods[1])
+		ro := r2.DeepCopy()
+		ro.Spec.Replicas = ptr.To[int32](2)
+		ro.Status.CurrentStepIndex = ptr.To[int32](3)
+		ctx := newContext(ro, rs, client, pods...)
+
+		replaced, err := ctx.reconcileCanaryPodTemplateOverlay()
+		require.NoError(t, err)
+		assert.Equal(t, 1, replaced)
+		assert.False(t, ctx.podTemplateOverlaySynced)
+		assert.Empty(t, ctx.shouldFullPromote(ro.Status))
+
+		updated, err := client.AppsV1().ReplicaSets(rs.Namespace).Get(context.TODO(), rs.Name, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.NotContains(t, updated.Spec.Template.Annotations, replicasetutil.PodTemplateOverlayAnnotation)
+		assert.Empty(t, updated.Spec.Template.Spec.Containers[0].Args)
+	})
+
+	t.Run("Leaves the stable ReplicaSet alone", func(t *testing.T) {
+		rs := rs2.DeepCopy()
+		rs.Spec.Template.Annotations = map[string]string{replicasetutil.PodTemplateOverlayAnnotation: overlayHash}
+		pods := []*corev1.Pod{overlayPod("pod-a", rs, overlayHash), overlayPod("pod-b", rs, overlayHash)}
+		client := fake.NewSimpleClientset(rs, pods[0], pods[1])
+		ro := r2.DeepCopy()
+		ro.Status.CurrentStepIndex = ptr.To[int32](3)
+		ro.Status.StableRS = ro.Status.CurrentPodHash
+		ctx := newContext(ro, rs, client, pods...)
+		ctx.stableRS = rs
+
+		replaced, err := ctx.reconcileCanaryPodTemplateOverlay()
+		require.NoError(t, err)
+		assert.Equal(t, 0, replaced)
+		assert.True(t, ctx.podTemplateOverlaySynced)
+		assert.Empty(t, client.Actions())
+	})
+
+	t.Run("Leaves an aborted canary alone", func(t *testing.T) {
+		pods := []*corev1.Pod{overlayPod("pod-a", rs2, ""), overlayPod("pod-b", rs2, "")}
+		client := fake.NewSimpleClientset(rs2, pods[0], pods[1])
+		ro := r2.DeepCopy()
+		ro.Status.Abort = true
+		ctx := newContext(ro, rs2.DeepCopy(), client, pods...)
+
+		replaced, err := ctx.reconcileCanaryPodTemplateOverlay()
+		require.NoError(t, err)
+		assert.Equal(t, 0, replaced)
+		assert.True(t, ctx.podTemplateOverlaySynced)
+		assert.Empty(t, client.Actions())
+	})
+
+	t.Run("Skips rollouts without overlay steps", func(t *testing.T) {
+		client := fake.NewSimpleClientset()
+		ro := r2.DeepCopy()
+		ro.Spec.Strategy.Canary.Steps = steps[:1]
+		ctx := newContext(ro, rs2.DeepCopy(), client)
+
+		replaced, err := ctx.reconcileCanaryPodTemplateOverlay()
+		require.NoError(t, err)
+		assert.Equal(t, 0, replaced)
+		assert.True(t, ctx.podTemplateOverlaySynced)
+		assert.Empty(t, client.Actions())
+	})
+
+	t.Run("Eviction blocked by PodDisruptionBudget", func(t *testing.T) {
+		pods := []*corev1.Pod{overlayPod("pod-a", rs2, ""), overlayPod("pod-b", rs2, "")}
+		client := fake.NewSimpleClientset(rs2, pods[0], pods[1])
+		client.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, k8serrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 10)
+		})
+		ctx := newContext(r2, rs2.DeepCopy(), client, pods...)
+
+		replaced, err := ctx.reconcileCanaryPodTemplateOverlay()
+		require.NoError(t, err)
+		assert.Equal(t, 0, replaced)
+		assert.False(t, ctx.podTemplateOverlaySynced)
+	})
+}
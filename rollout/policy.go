@@ -0,0 +1,106 @@
+package rollout
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	"github.com/argoproj/argo-rollouts/utils/policy"
+	"github.com/argoproj/argo-rollouts/utils/record"
+	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
+	timeutil "github.com/argoproj/argo-rollouts/utils/time"
+)
+
+// progressionPolicyRecheckInterval is the interval in which a rollout is reconciled while a progression policy denies
+// it to progress, since the policies can depend on the time
+const progressionPolicyRecheckInterval = time.Minute
+
+// progressionAllowed evaluates the progression policies before the rollout progresses from its completed step to the
+// next one, and records a denial in the ProgressionDenied condition of the new status. The rollout stays at its
+// completed step while the progression is denied, and the policies are evaluated again periodically. The condition
+// must be removed from the new status beforehand.
+func (c *rolloutContext) progressionAllowed(newStatus *v1alpha1.RolloutStatus, from, to int32) bool {
+	denial := c.evaluateProgressionPolicies(from, to)
+	if denial == "" {
+		return true
+	}
+	stepCount := int32(len(c.rollout.Spec.Strategy.Canary.Steps))
+	c.setProgressionDenied(newStatus, fmt.Sprintf(conditions.ProgressionDeniedMessage, from+1, stepCount, denial))
+	return false
+}
+
+// fullPromotionAllowed evaluates the progression policies before the rollout is fully promoted without completing the
+// steps, i.e. before a blue-green rollout switches its active service, and before a canary rollout is fully promoted
+// by status.promoteFull. step.from is the current step and step.to the number of steps. A denial is kept in
+// promotionDenial, and recorded by the status sync of the strategy.
+func (c *rolloutContext) fullPromotionAllowed() bool {
+	var from, to int32
+	if c.rollout.Spec.Strategy.Canary != nil {
+		to = int32(len(c.rollout.Spec.Strategy.Canary.Steps))
+		if _, currentStepIndex := replicasetutil.GetCurrentCanaryStep(c.rollout); currentStepIndex != nil {
+			from = *currentStepIndex
+		}
+	}
+	denial := c.evaluateProgressionPolicies(from, to)
+	if denial == "" {
+		return true
+	}
+	c.promotionDenial = fmt.Sprintf(conditions.PromotionDeniedMessage, denial)
+	return false
+}
+
+// evaluateProgressionPolicies evaluates the progression policies for a progression from the step from to the step to,
+// and returns the message of the policy which denies it, or an empty string if the policies allow it
+func (c *rolloutContext) evaluateProgressionPolicies(from, to int32) string {
+	prevCond := conditions.GetRolloutCondition(c.rollout.Status, v1alpha1.RolloutProgressionDenied)
+	if !policy.Enabled() {
+		return ""
+	}
+	input := policy.Input{
+		Rollout:  c.rollout,
+		FromStep: from,
+		ToStep:   to,
+		Now:      timeutil.Now(),
+	}
+	if c.newRS != nil {
+		input.UpdateStartedAt = c.newRS.CreationTimestamp.Time
+	}
+	if c.rolloutsLister != nil {
+		rollouts, err := c.rolloutsLister.Rollouts(c.rollout.Namespace).List(labels.Everything())
+		if err != nil {
+			c.log.Warnf("Failed to list the rollouts of the namespace for the progression policies: %v", err)
+		}
+		input.Rollouts = rollouts
+	}
+
+	denial := policy.Evaluate(input)
+	if denial == "" && prevCond != nil {
+		c.recorder.Eventf(c.rollout, record.EventOptions{EventReason: conditions.ProgressionAllowedReason}, conditions.ProgressionAllowedMessage)
+	}
+	return denial
+}
+
+// setProgressionDenied records a denied progression in the ProgressionDenied condition of the new status, and
+// reconciles the rollout again once the policies are to be evaluated again
+func (c *rolloutContext) setProgressionDenied(newStatus *v1alpha1.RolloutStatus, message string) {
+	prevCond := conditions.GetRolloutCondition(c.rollout.Status, v1alpha1.RolloutProgressionDenied)
+	condition := conditions.NewRolloutCondition(v1alpha1.RolloutProgressionDenied, corev1.ConditionTrue, conditions.ProgressionDeniedReason, message)
+	switch {
+	case prevCond == nil:
+		c.recorder.Warnf(c.rollout, record.EventOptions{EventReason: conditions.ProgressionDeniedReason}, "%s", message)
+	case prevCond.Message == message:
+		condition = prevCond
+	default:
+		condition.LastTransitionTime = prevCond.LastTransitionTime
+		c.recorder.Warnf(c.rollout, record.EventOptions{EventReason: conditions.ProgressionDeniedReason}, "%s", message)
+	}
+	conditions.SetRolloutCondition(newStatus, *condition)
+	c.log.Info(message)
+	// a promoted pause step stays promoted until the progression is allowed
+	newStatus.ControllerPause = c.rollout.Status.ControllerPause
+	c.enqueueRolloutAfter(c.rollout, progressionPolicyRecheckInterval)
+}
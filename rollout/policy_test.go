@@ -0,0 +1,76 @@
+package rollout
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	"github.com/argoproj/argo-rollouts/utils/policy"
+)
+
+func TestProgressionAllowed(t *testing.T) {
+	t.Cleanup(func() { policy.SetPolicies(nil) })
+	steps := []v1alpha1.CanaryStep{{SetWeight: ptr.To[int32](10)}, {Pause: &v1alpha1.RolloutPause{}}}
+	ro := newCanaryRollout("foo", 5, nil, steps, ptr.To[int32](1), intstr.FromInt(1), intstr.FromInt(0))
+	ro.Status.ControllerPause = true
+	ctx, recorder := newLoadTestContext(ro, true, k8sfake.NewSimpleClientset())
+
+	newStatus := ro.Status.DeepCopy()
+	assert.True(t, ctx.progressionAllowed(newStatus, 1, 2))
+	assert.Nil(t, conditions.GetRolloutCondition(*newStatus, v1alpha1.RolloutProgressionDenied))
+
+	policy.SetPolicies([]policy.Policy{{Name: "freeze", Expression: `rollout.metadata.name != "foo"`, Message: "Deployments are frozen"}})
+	newStatus = ro.Status.DeepCopy()
+	newStatus.ControllerPause = false
+	assert.False(t, ctx.progressionAllowed(newStatus, 1, 2))
+	cond := conditions.GetRolloutCondition(*newStatus, v1alpha1.RolloutProgressionDenied)
+	require.NotNil(t, cond)
+	assert.Equal(t, "Progression after step 2/2 denied: Deployments are frozen", cond.Message)
+	// the promotion of the pause step is kept until the progression is allowed
+	assert.True(t, newStatus.ControllerPause)
+	assert.Equal(t, []string{conditions.ProgressionDeniedReason}, recorder.Events())
+
+	// the denial is only reported once
+	ro.Status.Conditions = newStatus.Conditions
+	newStatus = ro.Status.DeepCopy()
+	conditions.RemoveRolloutCondition(newStatus, v1alpha1.RolloutProgressionDenied)
+	assert.False(t, ctx.progressionAllowed(newStatus, 1, 2))
+	assert.Equal(t, *cond, *conditions.GetRolloutCondition(*newStatus, v1alpha1.RolloutProgressionDenied))
+	assert.Len(t, recorder.Events(), 1)
+
+	policy.SetPolicies([]policy.Policy{{Name: "freeze", Expression: `true`}})
+	newStatus = ro.Status.DeepCopy()
+	conditions.RemoveRolloutCondition(newStatus, v1alpha1.RolloutProgressionDenied)
+	assert.True(t, ctx.progressionAllowed(newStatus, 1, 2))
+	assert.Nil(t, conditions.GetRolloutCondition(*newStatus, v1alpha1.RolloutProgressionDenied))
+	assert.Equal(t, []string{conditions.ProgressionDeniedReason, conditions.ProgressionAllowedReason}, recorder.Events())
+}
+
+func TestFullPromotionAllowed(t *testing.T) {
+	t.Cleanup(func() { policy.SetPolicies(nil) })
+	steps := []v1alpha1.CanaryStep{{SetWeight: ptr.To[int32](10)}, {Pause: &v1alpha1.RolloutPause{}}}
+	ro := newCanaryRollout("foo", 5, nil, steps, ptr.To[int32](1), intstr.FromInt(1), intstr.FromInt(0))
+	ctx, recorder := newLoadTestContext(ro, true, k8sfake.NewSimpleClientset())
+
+	assert.True(t, ctx.fullPromotionAllowed())
+
+	// a full promotion progresses from the current step to the number of steps
+	policy.SetPolicies([]policy.Policy{{Name: "soak", Expression: `step.to < step.count || has(step.completed.pause)`}})
+	assert.True(t, ctx.fullPromotionAllowed())
+	ro.Status.CurrentStepIndex = ptr.To[int32](0)
+	assert.False(t, ctx.fullPromotionAllowed())
+	assert.Equal(t, "Full promotion denied: denied by progression policy soak", ctx.promotionDenial)
+
+	newStatus := ro.Status.DeepCopy()
+	ctx.setProgressionDenied(newStatus, ctx.promotionDenial)
+	cond := conditions.GetRolloutCondition(*newStatus, v1alpha1.RolloutProgressionDenied)
+	require.NotNil(t, cond)
+	assert.Equal(t, "Full promotion denied: denied by progression policy soak", cond.Message)
+	assert.Equal(t, []string{conditions.ProgressionDeniedReason}, recorder.Events())
+}
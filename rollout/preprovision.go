@@ -0,0 +1,244 @@
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+	"github.com/argoproj/argo-rollouts/utils/record"
+	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
+	timeutil "github.com/argoproj/argo-rollouts/utils/time"
+	"github.com/argoproj/argo-rollouts/utils/weightutil"
+)
+
+const (
+	// PlaceholderLabelKey labels the placeholder ReplicaSets of preProvision steps and their pods with the name of the
+	// ReplicaSet
+	PlaceholderLabelKey = "rollouts-placeholder"
+	// DefaultPlaceholderImage is the image of the placeholder pods
+	DefaultPlaceholderImage = "registry.k8s.io/pause:3.10"
+	// defaultPreProvisionTimeout is the maximum time a preProvision step waits for the placeholder pods to be scheduled
+	defaultPreProvisionTimeout = 10 * time.Minute
+	// preProvisionResyncPeriod is the period at which a rollout waiting on the placeholder pods checks them
+	preProvisionResyncPeriod = 10 * time.Second
+)
+
+// placeholderSelector selects the placeholder ReplicaSets of all rollouts
+var placeholderSelector = func() labels.Selector {
+	requirement, _ := labels.NewRequirement(PlaceholderLabelKey, selection.Exists, nil)
+	return labels.NewSelector().Add(*requirement)
+}()
+
+// reconcilePreProvision creates the placeholder ReplicaSet of the current preProvision step, whose low priority pods
+// request the resources of the canary pods missing at the weight of the step, so that the cluster autoscaler
+// provisions the capacity before the canary is scaled up. The placeholder pods are scaled down as the canary pods
+// become available, and are deleted once the update is completed, aborted or replaced. It records whether the
+// preProvision step is completed, i.e. the placeholder pods were scheduled or the timeout of the step elapsed.
+func (c *rolloutContext) reconcilePreProvision() error {
+	steps := c.rollout.Spec.Strategy.Canary.Steps
+	if !slices.ContainsFunc(steps, func(step v1alpha1.CanaryStep) bool { return step.PreProvision != nil }) {
+		return nil
+	}
+	ctx := context.TODO()
+	client := c.kubeclientset.AppsV1().ReplicaSets(c.rollout.Namespace)
+
+	updating := c.newRS != nil && replicasetutil.CheckStableRSExists(c.newRS, c.stableRS) && !c.pauseContext.IsAborted()
+	currentStep, currentIndex := replicasetutil.GetCurrentCanaryStep(c.rollout)
+	// the placeholders of the last preProvision step up to the current step are kept until the canary pods are
+	// available
+	var step *v1alpha1.RolloutPreProvisionStep
+	var stepIndex int
+	if updating && currentIndex != nil {
+		for i := min(int(*currentIndex), len(steps)-1); i >= 0; i-- {
+			if steps[i].PreProvision != nil {
+				step, stepIndex = steps[i].PreProvision, i
+				break
+			}
+		}
+	}
+	var name string
+	var missing int32
+	if step != nil {
+		podHash := c.newRS.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+		name = fmt.Sprintf("%s-%s-%d-placeholder", c.rollout.Name, podHash, stepIndex)
+		missing = max(c.preProvisionReplicas(stepIndex)-c.newRS.Status.AvailableReplicas, 0)
+	}
+
+	placeholders, err := c.replicaSetLister.ReplicaSets(c.rollout.Namespace).List(placeholderSelector)
+	if err != nil {
+		return fmt.Errorf("failed to list placeholder ReplicaSets: %w", err)
+	}
+	var placeholder *appsv1.ReplicaSet
+	for _, rs := range placeholders {
+		if !c.ownsPlaceholder(rs) {
+			continue
+		}
+		if rs.Name == name && missing > 0 {
+			placeholder = rs.DeepCopy()
+			continue
+		}
+		err := client.Delete(ctx, rs.Name, metav1.DeleteOptions{PropagationPolicy: ptr.To(metav1.DeletePropagationBackground)})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete placeholder ReplicaSet %s: %w", rs.Name, err)
+		}
+		c.log.Infof("Deleted placeholder ReplicaSet %s", rs.Name)
+	}
+
+	isCurrentStep := step != nil && currentStep != nil && currentStep.PreProvision != nil && stepIndex == int(*currentIndex)
+	if missing == 0 {
+		c.preProvisionCompleted = isCurrentStep
+		return nil
+	}
+	if placeholder == nil {
+		// the placeholders are only created by the step itself, and are not recreated once they were deleted
+		if !isCurrentStep {
+			return nil
+		}
+		placeholder, err = client.Create(ctx, c.newPlaceholderReplicaSet(name, missing, step), metav1.CreateOptions{FieldManager: defaults.DefaultFieldManager})
+		if k8serrors.IsAlreadyExists(err) {
+			// the informer is not yet notified of the placeholder ReplicaSet
+			c.enqueueRolloutAfter(c.rollout, preProvisionResyncPeriod)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create placeholder ReplicaSet %s: %w", name, err)
+		}
+		c.recorder.Eventf(c.rollout, record.EventOptions{EventReason: conditions.PreProvisionStartedReason}, conditions.PreProvisionStartedMessage, placeholder.Name, missing, stepIndex+1)
+		c.enqueueRolloutAfter(c.rollout, preProvisionResyncPeriod)
+		return nil
+	}
+	if placeholder.Spec.Replicas == nil || *placeholder.Spec.Replicas != missing {
+		placeholder.Spec.Replicas = ptr.To(missing)
+		if _, err := client.Update(ctx, placeholder, metav1.UpdateOptions{FieldManager: defaults.DefaultFieldManager}); err != nil {
+			return fmt.Errorf("failed to scale placeholder ReplicaSet %s: %w", placeholder.Name, err)
+		}
+		c.log.Infof("Scaled placeholder ReplicaSet %s to %d", placeholder.Name, missing)
+	}
+	if !isCurrentStep {
+		return nil
+	}
+	if placeholder.Status.ReadyReplicas >= missing {
+		c.preProvisionCompleted = true
+		return nil
+	}
+	timeout := defaultPreProvisionTimeout
+	if step.Timeout != "" {
+		if timeout, err = step.Timeout.Duration(); err != nil {
+			return fmt.Errorf("invalid preProvision timeout %s: %w", step.Timeout, err)
+		}
+	}
+	if elapsed := timeutil.Now().Sub(placeholder.CreationTimestamp.Time); elapsed >= timeout {
+		c.recorder.Warnf(c.rollout, record.EventOptions{EventReason: conditions.PreProvisionTimedOutReason}, conditions.PreProvisionTimedOutMessage, placeholder.Status.ReadyReplicas, missing, stepIndex+1, timeout)
+		c.preProvisionCompleted = true
+		return nil
+	}
+	c.enqueueRolloutAfter(c.rollout, preProvisionResyncPeriod)
+	return nil
+}
+
+// preProvisionReplicas returns the number of canary pods at the weight of the preProvision step of the index, which
+// defaults to the weight of the next setWeight step, or the full weight if there is none
+func (c *rolloutContext) preProvisionReplicas(index int) int32 {
+	steps := c.rollout.Spec.Strategy.Canary.Steps
+	maxWeight := weightutil.MaxTrafficWeight(c.rollout)
+	weight := maxWeight
+	if steps[index].PreProvision.Weight != nil {
+		weight = *steps[index].PreProvision.Weight
+	} else {
+		for _, step := range steps[index+1:] {
+			if step.SetWeight != nil {
+				weight = *step.SetWeight
+				break
+			}
+		}
+	}
+	replicas := defaults.GetReplicasOrDefault(c.rollout.Spec.Replicas)
+	return int32(math.Ceil(float64(weight) * float64(replicas) / float64(maxWeight)))
+}
+
+// ownsPlaceholder returns whether the placeholder ReplicaSet belongs to the rollout. The placeholder ReplicaSets are
+// not controlled by the rollout, so that they are not mistaken for the ReplicaSets of its revisions, but are garbage
+// collected with it.
+func (c *rolloutContext) ownsPlaceholder(rs *appsv1.ReplicaSet) bool {
+	return slices.ContainsFunc(rs.OwnerReferences, func(ref metav1.OwnerReference) bool { return ref.UID == c.rollout.UID })
+}
+
+// newPlaceholderReplicaSet returns a ReplicaSet of placeholder pods which request the resources of the canary pods and
+// are scheduled like them
+func (c *rolloutContext) newPlaceholderReplicaSet(name string, replicas int32, step *v1alpha1.RolloutPreProvisionStep) *appsv1.ReplicaSet {
+	ownerRef := metav1.NewControllerRef(c.rollout, controllerKind)
+	ownerRef.Controller = ptr.To(false)
+	placeholderLabels := map[string]string{PlaceholderLabelKey: name}
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: c.rollout.Namespace,
+			Labels:    placeholderLabels,
+			Annotations: map[string]string{
+				v1alpha1.ManagedByRolloutsKey: c.rollout.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{*ownerRef},
+		},
+		Spec: appsv1.ReplicaSetSpec{
+			Replicas: ptr.To(replicas),
+			Selector: &metav1.LabelSelector{MatchLabels: placeholderLabels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: placeholderLabels},
+				Spec:       newPlaceholderPodSpec(c.newRS.Spec.Template.Spec, step),
+			},
+		},
+	}
+}
+
+// newPlaceholderPodSpec returns the spec of a placeholder pod, which requests the resources of a pod of the pod spec
+// and has its scheduling constraints
+func newPlaceholderPodSpec(podSpec corev1.PodSpec, step *v1alpha1.RolloutPreProvisionStep) corev1.PodSpec {
+	requests := corev1.ResourceList{}
+	for _, container := range podSpec.Containers {
+		for name, quantity := range container.Resources.Requests {
+			total := requests[name]
+			total.Add(quantity)
+			requests[name] = total
+		}
+	}
+	// the init containers run before the containers, so that a pod requests the maximum of both
+	for _, container := range podSpec.InitContainers {
+		for name, quantity := range container.Resources.Requests {
+			if total, ok := requests[name]; !ok || quantity.Cmp(total) > 0 {
+				requests[name] = quantity.DeepCopy()
+			}
+		}
+	}
+	image := step.Image
+	if image == "" {
+		image = DefaultPlaceholderImage
+	}
+	return corev1.PodSpec{
+		Containers: []corev1.Container{{
+			Name:      "placeholder",
+			Image:     image,
+			Resources: corev1.ResourceRequirements{Requests: requests},
+		}},
+		PriorityClassName:             step.PriorityClassName,
+		NodeSelector:                  podSpec.NodeSelector,
+		Affinity:                      podSpec.Affinity,
+		Tolerations:                   podSpec.Tolerations,
+		TopologySpreadConstraints:     podSpec.TopologySpreadConstraints,
+		SchedulerName:                 podSpec.SchedulerName,
+		TerminationGracePeriodSeconds: ptr.To[int64](0),
+		AutomountServiceAccountToken:  ptr.To(false),
+	}
+}
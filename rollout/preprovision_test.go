@@ -0,0 +1,151 @@
+package rollout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+)
+
+func newPreProvisionRollout(preProvision *v1alpha1.RolloutPreProvisionStep) *v1alpha1.Rollout {
+	steps := []v1alpha1.CanaryStep{{PreProvision: preProvision}, {SetWeight: ptr.To[int32](60)}, {Pause: &v1alpha1.RolloutPause{}}}
+	ro := newCanaryRollout("foo", 5, nil, steps, ptr.To[int32](0), intstr.FromInt(1), intstr.FromInt(0))
+	ro.Spec.Template.Spec.Containers[0].Resources.Requests = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("500m"),
+		corev1.ResourceMemory: resource.MustParse("256Mi"),
+	}
+	ro.Spec.Template.Spec.NodeSelector = map[string]string{"pool": "canary"}
+	return ro
+}
+
+func listPlaceholders(t *testing.T, client *k8sfake.Clientset) []appsv1.ReplicaSet {
+	placeholders, err := client.AppsV1().ReplicaSets(metav1.NamespaceDefault).List(context.TODO(), metav1.ListOptions{LabelSelector: PlaceholderLabelKey})
+	require.NoError(t, err)
+	return placeholders.Items
+}
+
+func updatePlaceholder(t *testing.T, client *k8sfake.Clientset, rs *appsv1.ReplicaSet) {
+	_, err := client.AppsV1().ReplicaSets(metav1.NamespaceDefault).Update(context.TODO(), rs, metav1.UpdateOptions{})
+	require.NoError(t, err)
+}
+
+// reconcilePreProvision reconciles the preProvision steps once the informer is notified of the placeholder ReplicaSets
+func reconcilePreProvision(ctx *rolloutContext, client *k8sfake.Clientset) error {
+	ctx.replicaSetLister = newReplicaSetLister(client)
+	return ctx.reconcilePreProvision()
+}
+
+func TestReconcilePreProvision(t *testing.T) {
+	ro := newPreProvisionRollout(&v1alpha1.RolloutPreProvisionStep{PriorityClassName: "placeholder"})
+	client := k8sfake.NewSimpleClientset()
+	ctx, recorder := newLoadTestContext(ro, true, client)
+
+	// 3 canary pods at the weight of the next setWeight step, of which 1 is available
+	require.NoError(t, reconcilePreProvision(ctx, client))
+	assert.False(t, ctx.preProvisionCompleted)
+	assert.Equal(t, []string{conditions.PreProvisionStartedReason}, recorder.Events())
+	placeholders := listPlaceholders(t, client)
+	require.Len(t, placeholders, 1)
+	placeholder := placeholders[0]
+	podHash := ctx.newRS.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	assert.Equal(t, "foo-"+podHash+"-0-placeholder", placeholder.Name)
+	assert.Equal(t, int32(2), *placeholder.Spec.Replicas)
+	assert.Nil(t, metav1.GetControllerOf(&placeholder))
+	assert.True(t, ctx.ownsPlaceholder(&placeholder))
+	podSpec := placeholder.Spec.Template.Spec
+	assert.Equal(t, "placeholder", podSpec.PriorityClassName)
+	assert.Equal(t, DefaultPlaceholderImage, podSpec.Containers[0].Image)
+	requests := podSpec.Containers[0].Resources.Requests
+	assert.True(t, resource.MustParse("500m").Equal(requests[corev1.ResourceCPU]))
+	assert.True(t, resource.MustParse("256Mi").Equal(requests[corev1.ResourceMemory]))
+	assert.Equal(t, map[string]string{"pool": "canary"}, podSpec.NodeSelector)
+
+	// the placeholder ReplicaSet is not recreated before the informer is notified of it
+	require.NoError(t, ctx.reconcilePreProvision())
+	assert.Len(t, listPlaceholders(t, client), 1)
+
+	// the step waits until the placeholder pods are scheduled
+	placeholder.CreationTimestamp = metav1.Now()
+	updatePlaceholder(t, client, &placeholder)
+	require.NoError(t, reconcilePreProvision(ctx, client))
+	assert.False(t, ctx.preProvisionCompleted)
+	placeholder.Status.ReadyReplicas = 2
+	updatePlaceholder(t, client, &placeholder)
+	require.NoError(t, reconcilePreProvision(ctx, client))
+	assert.True(t, ctx.preProvisionCompleted)
+
+	// the placeholder pods are scaled down as the canary pods become available during the next steps
+	ro.Status.CurrentStepIndex = ptr.To[int32](1)
+	ctx.preProvisionCompleted = false
+	ctx.newRS.Status.AvailableReplicas = 2
+	require.NoError(t, reconcilePreProvision(ctx, client))
+	assert.False(t, ctx.preProvisionCompleted)
+	placeholders = listPlaceholders(t, client)
+	require.Len(t, placeholders, 1)
+	assert.Equal(t, int32(1), *placeholders[0].Spec.Replicas)
+
+	// and are deleted once all canary pods are available
+	ctx.newRS.Status.AvailableReplicas = 3
+	require.NoError(t, reconcilePreProvision(ctx, client))
+	assert.Empty(t, listPlaceholders(t, client))
+}
+
+func TestReconcilePreProvisionTimeout(t *testing.T) {
+	ro := newPreProvisionRollout(&v1alpha1.RolloutPreProvisionStep{PriorityClassName: "placeholder", Weight: ptr.To[int32](100), Timeout: "1m"})
+	client := k8sfake.NewSimpleClientset()
+	ctx, recorder := newLoadTestContext(ro, true, client)
+	require.NoError(t, reconcilePreProvision(ctx, client))
+	placeholder := listPlaceholders(t, client)[0]
+	assert.Equal(t, int32(4), *placeholder.Spec.Replicas)
+
+	placeholder.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	updatePlaceholder(t, client, &placeholder)
+	require.NoError(t, reconcilePreProvision(ctx, client))
+	assert.True(t, ctx.preProvisionCompleted)
+	assert.Equal(t, []string{conditions.PreProvisionStartedReason, conditions.PreProvisionTimedOutReason}, recorder.Events())
+
+	// the placeholders are deleted once the update is aborted
+	ro.Status.Abort = true
+	require.NoError(t, reconcilePreProvision(ctx, client))
+	assert.Empty(t, listPlaceholders(t, client))
+	assert.False(t, ctx.preProvisionCompleted)
+}
+
+func TestReconcilePreProvisionNoMissingPods(t *testing.T) {
+	ro := newPreProvisionRollout(&v1alpha1.RolloutPreProvisionStep{PriorityClassName: "placeholder", Weight: ptr.To[int32](20)})
+	client := k8sfake.NewSimpleClientset()
+	ctx, _ := newLoadTestContext(ro, true, client)
+	require.NoError(t, reconcilePreProvision(ctx, client))
+	assert.True(t, ctx.preProvisionCompleted)
+	assert.Empty(t, listPlaceholders(t, client))
+}
+
+func TestNewPlaceholderPodSpec(t *testing.T) {
+	podSpec := corev1.PodSpec{
+		InitContainers: []corev1.Container{{Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}}}},
+		Containers: []corev1.Container{
+			{Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("1Gi")}}},
+			{Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m"), corev1.ResourceMemory: resource.MustParse("512Mi")}}},
+		},
+		Tolerations: []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+	}
+	spec := newPlaceholderPodSpec(podSpec, &v1alpha1.RolloutPreProvisionStep{PriorityClassName: "placeholder", Image: "pause"})
+	requests := spec.Containers[0].Resources.Requests
+	assert.True(t, resource.MustParse("2").Equal(requests[corev1.ResourceCPU]))
+	assert.True(t, resource.MustParse("1536Mi").Equal(requests[corev1.ResourceMemory]))
+	assert.Equal(t, "pause", spec.Containers[0].Image)
+	assert.Equal(t, podSpec.Tolerations, spec.Tolerations)
+	assert.Equal(t, ptr.To[int64](0), spec.TerminationGracePeriodSeconds)
+}
@@ -0,0 +1,390 @@
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	istioutil "github.com/argoproj/argo-rollouts/utils/istio"
+	"github.com/argoproj/argo-rollouts/utils/record"
+	timeutil "github.com/argoproj/argo-rollouts/utils/time"
+)
+
+// gatewayAPIHTTPRouteGVR returns the GroupVersionResource of Gateway API HTTPRoutes
+func gatewayAPIHTTPRouteGVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    "gateway.networking.k8s.io",
+		Version:  "v1",
+		Resource: "httproutes",
+	}
+}
+
+// reconcilePreviewRouting adds the header-matched preview route to the configured VirtualService or
+// HTTPRoute while the active service points at a different ReplicaSet than the preview service, and
+// removes it once the rollout is promoted or aborted.
+func (c *rolloutContext) reconcilePreviewRouting(activeSvc *corev1.Service) error {
+	blueGreen := c.rollout.Spec.Strategy.BlueGreen
+	if blueGreen == nil || blueGreen.PreviewRouting == nil || activeSvc == nil {
+		return nil
+	}
+	activeHash := activeSvc.Spec.Selector[v1alpha1.DefaultRolloutUniqueLabelKey]
+	enabled := c.newRS != nil && blueGreen.PreviewService != "" && activeHash != "" &&
+		activeHash != c.newRS.Labels[v1alpha1.DefaultRolloutUniqueLabelKey] && !c.rollout.Status.Abort
+
+	routing := blueGreen.PreviewRouting
+	if routing.Istio != nil {
+		if c.IstioController == nil {
+			return fmt.Errorf("preview routing requires Istio, but the Istio controller is not available")
+		}
+		namespace, name := istioutil.GetVirtualServiceNamespaceName(routing.Istio.VirtualService)
+		if namespace == "" {
+			namespace = c.rollout.Namespace
+		}
+		err := c.updatePreviewRoutes(c.IstioController.DynamicClientSet, istioutil.GetIstioVirtualServiceGVR(), namespace, name, enabled, c.getPreviewVirtualService, syncIstioPreviewRoute)
+		if err != nil {
+			return err
+		}
+	}
+	if routing.GatewayAPI != nil {
+		if c.dynamicclientset == nil {
+			return fmt.Errorf("preview routing requires Gateway API, but the dynamic client is not available")
+		}
+		err := c.updatePreviewRoutes(c.dynamicclientset, gatewayAPIHTTPRouteGVR(), c.rollout.Namespace, routing.GatewayAPI.HTTPRoute, enabled, c.getPreviewHTTPRoute, syncGatewayAPIPreviewRule)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// previewRouteGetFunc returns a copy of the routing object which may be modified
+type previewRouteGetFunc func(namespace, name string, enabled bool) (*unstructured.Unstructured, error)
+
+// getPreviewVirtualService returns the VirtualService from the informer of the Istio controller, and
+// only falls back to the API server while the informer is not synced (e.g. Istio was installed after
+// the controller started)
+func (c *rolloutContext) getPreviewVirtualService(namespace, name string, _ bool) (*unstructured.Unstructured, error) {
+	istioController := c.IstioController
+	if istioController.VirtualServiceInformer != nil && istioController.VirtualServiceInformer.HasSynced() {
+		vsvc, err := istioController.VirtualServiceLister.Namespace(namespace).Get(name)
+		if err != nil {
+			return nil, err
+		}
+		return vsvc.DeepCopy(), nil
+	}
+	return istioController.DynamicClientSet.Resource(istioutil.GetIstioVirtualServiceGVR()).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+}
+
+// getPreviewHTTPRoute returns the HTTPRoute from the API server. The controller does not watch HTTPRoutes,
+// so the HTTPRoute is only read when the preview route was not synced into it recently. A nil object means
+// the HTTPRoute is up to date.
+func (c *rolloutContext) getPreviewHTTPRoute(namespace, name string, enabled bool) (*unstructured.Unstructured, error) {
+	key := previewRouteKey(c.rollout, namespace, name)
+	if c.previewRoutes.isSynced(key, enabled) {
+		return nil, nil
+	}
+	return c.dynamicclientset.Resource(gatewayAPIHTTPRouteGVR()).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+}
+
+// previewRouteSyncFunc adds (enabled) or removes the preview route from the routing object and
+// returns whether the object was modified
+type previewRouteSyncFunc func(obj *unstructured.Unstructured, blueGreen *v1alpha1.BlueGreenStrategy, enabled bool) (bool, error)
+
+func (c *rolloutContext) updatePreviewRoutes(client dynamic.Interface, gvr schema.GroupVersionResource, namespace, name string, enabled bool, get previewRouteGetFunc, sync previewRouteSyncFunc) error {
+	ctx := context.TODO()
+	logCtx := c.log.WithField(gvr.Resource, namespace+"/"+name)
+	obj, err := get(namespace, name, enabled)
+	if err != nil || obj == nil {
+		return err
+	}
+	key := previewRouteKey(c.rollout, namespace, name)
+	modified, err := sync(obj, c.rollout.Spec.Strategy.BlueGreen, enabled)
+	if err != nil {
+		return err
+	}
+	if !modified {
+		c.previewRoutes.setSynced(key, enabled)
+		return nil
+	}
+	_, err = client.Resource(gvr).Namespace(namespace).Update(ctx, obj, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	c.previewRoutes.setSynced(key, enabled)
+	routeName := c.rollout.Spec.Strategy.BlueGreen.PreviewRouting.Name
+	if enabled {
+		logCtx.Infof("Added preview route '%s'", routeName)
+		c.recorder.Eventf(c.rollout, record.EventOptions{EventReason: "PreviewRouteAdded"}, "Added preview route '%s' to %s '%s'", routeName, gvr.Resource, name)
+	} else {
+		logCtx.Infof("Removed preview route '%s'", routeName)
+		c.recorder.Eventf(c.rollout, record.EventOptions{EventReason: "PreviewRouteRemoved"}, "Removed preview route '%s' from %s '%s'", routeName, gvr.Resource, name)
+	}
+	return nil
+}
+
+func previewRouteKey(ro *v1alpha1.Rollout, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", ro.Namespace, ro.Name, namespace, name)
+}
+
+// previewRouteCache remembers the preview routes which were synced into routing objects that the
+// controller has no informer for, so that they are not read on every reconcile. Entries expire after
+// the resync period, so that changes made by others are eventually corrected.
+type previewRouteCache struct {
+	lock    sync.Mutex
+	ttl     time.Duration
+	entries map[string]previewRouteCacheEntry
+}
+
+type previewRouteCacheEntry struct {
+	enabled  bool
+	syncedAt time.Time
+}
+
+func newPreviewRouteCache(ttl time.Duration) *previewRouteCache {
+	return &previewRouteCache{
+		ttl:     ttl,
+		entries: map[string]previewRouteCacheEntry{},
+	}
+}
+
+func (p *previewRouteCache) isSynced(key string, enabled bool) bool {
+	if p == nil {
+		return false
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	entry, ok := p.entries[key]
+	if !ok || entry.enabled != enabled || timeutil.Now().Sub(entry.syncedAt) > p.ttl {
+		delete(p.entries, key)
+		return false
+	}
+	return true
+}
+
+// Forget removes the preview routes of a deleted rollout
+func (p *previewRouteCache) Forget(rolloutKey string) {
+	if p == nil {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for key := range p.entries {
+		if strings.HasPrefix(key, rolloutKey+"/") {
+			delete(p.entries, key)
+		}
+	}
+}
+
+func (p *previewRouteCache) setSynced(key string, enabled bool) {
+	if p == nil {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.entries[key] = previewRouteCacheEntry{enabled: enabled, syncedAt: timeutil.Now()}
+}
+
+// syncIstioPreviewRoute prepends a named HTTP route to the VirtualService sending header-matched
+// requests to the preview service. The destination port is copied from the route to the active service.
+func syncIstioPreviewRoute(obj *unstructured.Unstructured, blueGreen *v1alpha1.BlueGreenStrategy, enabled bool) (bool, error) {
+	routing := blueGreen.PreviewRouting
+	httpRoutes, _, err := unstructured.NestedSlice(obj.Object, "spec", "http")
+	if err != nil {
+		return false, err
+	}
+	var routes []any
+	var existing map[string]any
+	existingIdx := -1
+	var port any
+	for i, r := range httpRoutes {
+		route, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+		if route["name"] == routing.Name {
+			existing, existingIdx = route, i
+			continue
+		}
+		routes = append(routes, route)
+		if port == nil {
+			port = istioDestinationPort(route, blueGreen.ActiveService)
+		}
+	}
+	if !enabled {
+		if existingIdx < 0 {
+			return false, nil
+		}
+		return true, unstructured.SetNestedSlice(obj.Object, routes, "spec", "http")
+	}
+
+	var matches []any
+	for _, hrm := range routing.Match {
+		matches = append(matches, createIstioHeaderMatch(hrm))
+	}
+	destination := map[string]any{
+		"host": blueGreen.PreviewService,
+	}
+	if port != nil {
+		destination["port"] = port
+	}
+	desired := map[string]any{
+		"name":  routing.Name,
+		"match": matches,
+		"route": []any{
+			map[string]any{
+				"destination": destination,
+				"weight":      int64(100),
+			},
+		},
+	}
+	if existingIdx == 0 && equality.Semantic.DeepEqual(existing, desired) {
+		return false, nil
+	}
+	return true, unstructured.SetNestedSlice(obj.Object, append([]any{desired}, routes...), "spec", "http")
+}
+
+// istioDestinationPort returns the destination port of the first destination in the route which targets
+// the given service
+func istioDestinationPort(route map[string]any, service string) any {
+	destinations, _, _ := unstructured.NestedSlice(route, "route")
+	for _, d := range destinations {
+		dest, ok := d.(map[string]any)
+		if !ok {
+			continue
+		}
+		host, _, _ := unstructured.NestedString(dest, "destination", "host")
+		if host != service && !strings.HasPrefix(host, service+".") {
+			continue
+		}
+		port, found, _ := unstructured.NestedFieldCopy(dest, "destination", "port")
+		if found {
+			return port
+		}
+	}
+	return nil
+}
+
+func createIstioHeaderMatch(hrm v1alpha1.HeaderRoutingMatch) any {
+	value := map[string]any{}
+	if hrm.HeaderValue != nil {
+		setMapValueIfNotEmpty(value, "exact", hrm.HeaderValue.Exact)
+		setMapValueIfNotEmpty(value, "regex", hrm.HeaderValue.Regex)
+		setMapValueIfNotEmpty(value, "prefix", hrm.HeaderValue.Prefix)
+	}
+	return map[string]any{
+		"headers": map[string]any{hrm.HeaderName: value},
+	}
+}
+
+// syncGatewayAPIPreviewRule prepends a named rule to the HTTPRoute sending header-matched requests to
+// the preview service. The backend port is copied from the backendRef to the active service.
+func syncGatewayAPIPreviewRule(obj *unstructured.Unstructured, blueGreen *v1alpha1.BlueGreenStrategy, enabled bool) (bool, error) {
+	routing := blueGreen.PreviewRouting
+	httpRules, _, err := unstructured.NestedSlice(obj.Object, "spec", "rules")
+	if err != nil {
+		return false, err
+	}
+	var rules []any
+	var existing map[string]any
+	existingIdx := -1
+	var port any
+	for i, r := range httpRules {
+		rule, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+		if rule["name"] == routing.Name {
+			existing, existingIdx = rule, i
+			continue
+		}
+		rules = append(rules, rule)
+		if port == nil {
+			port = gatewayAPIBackendPort(rule, blueGreen.ActiveService)
+		}
+	}
+	if !enabled {
+		if existingIdx < 0 {
+			return false, nil
+		}
+		return true, unstructured.SetNestedSlice(obj.Object, rules, "spec", "rules")
+	}
+
+	var matches []any
+	for _, hrm := range routing.Match {
+		matches = append(matches, map[string]any{
+			"headers": []any{createGatewayAPIHeaderMatch(hrm)},
+		})
+	}
+	if port == nil {
+		return false, fmt.Errorf("HTTPRoute '%s' has no backendRef to active service '%s'", obj.GetName(), blueGreen.ActiveService)
+	}
+	desired := map[string]any{
+		"name":    routing.Name,
+		"matches": matches,
+		"backendRefs": []any{
+			map[string]any{
+				"name": blueGreen.PreviewService,
+				"port": port,
+			},
+		},
+	}
+	if existingIdx == 0 && equality.Semantic.DeepEqual(existing, desired) {
+		return false, nil
+	}
+	return true, unstructured.SetNestedSlice(obj.Object, append([]any{desired}, rules...), "spec", "rules")
+}
+
+// gatewayAPIBackendPort returns the port of the first backendRef in the rule which targets the given service
+func gatewayAPIBackendPort(rule map[string]any, service string) any {
+	backendRefs, _, _ := unstructured.NestedSlice(rule, "backendRefs")
+	for _, b := range backendRefs {
+		ref, ok := b.(map[string]any)
+		if !ok || ref["name"] != service {
+			continue
+		}
+		if port, ok := ref["port"]; ok {
+			return port
+		}
+	}
+	return nil
+}
+
+// createGatewayAPIHeaderMatch converts a header match to an HTTPHeaderMatch. Gateway API has no prefix
+// match type, so prefix matches are expressed as a regular expression.
+func createGatewayAPIHeaderMatch(hrm v1alpha1.HeaderRoutingMatch) map[string]any {
+	match := map[string]any{
+		"name": hrm.HeaderName,
+	}
+	if hrm.HeaderValue == nil {
+		return match
+	}
+	switch {
+	case hrm.HeaderValue.Exact != "":
+		match["type"] = "Exact"
+		match["value"] = hrm.HeaderValue.Exact
+	case hrm.HeaderValue.Regex != "":
+		match["type"] = "RegularExpression"
+		match["value"] = hrm.HeaderValue.Regex
+	case hrm.HeaderValue.Prefix != "":
+		match["type"] = "RegularExpression"
+		match["value"] = "^" + regexp.QuoteMeta(hrm.HeaderValue.Prefix) + ".*"
+	}
+	return match
+}
+
+func setMapValueIfNotEmpty(m map[string]any, key string, value string) {
+	if value != "" {
+		m[key] = value
+	}
+}
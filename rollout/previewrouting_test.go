@@ -0,0 +1,215 @@
+package rollout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/dynamic/dynamiclister"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/rollout/trafficrouting/istio"
+	istioutil "github.com/argoproj/argo-rollouts/utils/istio"
+	logutil "github.com/argoproj/argo-rollouts/utils/log"
+	"github.com/argoproj/argo-rollouts/utils/record"
+	unstructuredutil "github.com/argoproj/argo-rollouts/utils/unstructured"
+)
+
+const previewRoutingVsvc = `apiVersion: networking.istio.io/v1alpha3
+kind: VirtualService
+metadata:
+  name: vsvc
+  namespace: default
+spec:
+  hosts:
+  - example.com
+  http:
+  - name: primary
+    route:
+    - destination:
+        host: active
+        port:
+          number: 8080
+      weight: 100`
+
+const previewRoutingHTTPRoute = `apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  name: route
+  namespace: default
+spec:
+  hostnames:
+  - example.com
+  rules:
+  - backendRefs:
+    - name: active
+      port: 8080`
+
+func newPreviewRoutingRollout() *v1alpha1.Rollout {
+	ro := newBlueGreenRollout("foo", 1, nil, "active", "preview")
+	ro.Spec.Strategy.BlueGreen.PreviewRouting = &v1alpha1.BlueGreenPreviewRouting{
+		Name: "preview",
+		Match: []v1alpha1.HeaderRoutingMatch{{
+			HeaderName:  "x-preview",
+			HeaderValue: &v1alpha1.StringMatch{Prefix: "v1."},
+		}},
+	}
+	return ro
+}
+
+func newPreviewRoutingContext(ro *v1alpha1.Rollout, objs ...runtime.Object) (*rolloutContext, *dynamicfake.FakeDynamicClient) {
+	scheme := runtime.NewScheme()
+	listMapping := map[schema.GroupVersionResource]string{
+		istioutil.GetIstioVirtualServiceGVR(): "VirtualServiceList",
+		gatewayAPIHTTPRouteGVR():              "HTTPRouteList",
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping, objs...)
+	rs := newReplicaSetWithStatus(ro, 1, 1)
+	return &rolloutContext{
+		rollout: ro,
+		newRS:   rs,
+		log:     logutil.WithRollout(ro),
+		reconcilerBase: reconcilerBase{
+			dynamicclientset: client,
+			recorder:         record.NewFakeEventRecorder(),
+			IstioController: &istio.IstioController{
+				IstioControllerConfig: istio.IstioControllerConfig{DynamicClientSet: client},
+			},
+			previewRoutes: newPreviewRouteCache(time.Hour),
+		},
+	}, client
+}
+
+// withVirtualServiceInformer makes the Istio controller of the context read VirtualServices from a synced informer
+func withVirtualServiceInformer(t *testing.T, ctx *rolloutContext, client *dynamicfake.FakeDynamicClient) cache.SharedIndexInformer {
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(client, 0)
+	informer := factory.ForResource(istioutil.GetIstioVirtualServiceGVR()).Informer()
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	factory.Start(stopCh)
+	require.True(t, cache.WaitForCacheSync(stopCh, informer.HasSynced))
+	ctx.IstioController.VirtualServiceInformer = informer
+	ctx.IstioController.VirtualServiceLister = dynamiclister.New(informer.GetIndexer(), istioutil.GetIstioVirtualServiceGVR())
+	return informer
+}
+
+func TestReconcilePreviewRoutingIstio(t *testing.T) {
+	ro := newPreviewRoutingRollout()
+	ro.Spec.Strategy.BlueGreen.PreviewRouting.Istio = &v1alpha1.BlueGreenIstioPreviewRouting{VirtualService: "vsvc"}
+	ctx, client := newPreviewRoutingContext(ro, unstructuredutil.StrToUnstructuredUnsafe(previewRoutingVsvc))
+	informer := withVirtualServiceInformer(t, ctx, client)
+	activeSvc := newService("active", 8080, map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: "stable"}, ro)
+	waitForInformer := func(routes int) {
+		assert.Eventually(t, func() bool {
+			cached, exists, _ := informer.GetIndexer().GetByKey("default/vsvc")
+			if !exists {
+				return false
+			}
+			cachedRoutes, _, _ := unstructured.NestedSlice(cached.(*unstructured.Unstructured).Object, "spec", "http")
+			return len(cachedRoutes) == routes
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	getRoutes := func() []any {
+		obj, err := client.Resource(istioutil.GetIstioVirtualServiceGVR()).Namespace("default").Get(context.TODO(), "vsvc", metav1.GetOptions{})
+		require.NoError(t, err)
+		routes, _, err := unstructured.NestedSlice(obj.Object, "spec", "http")
+		require.NoError(t, err)
+		return routes
+	}
+
+	require.NoError(t, ctx.reconcilePreviewRouting(activeSvc))
+	routes := getRoutes()
+	require.Len(t, routes, 2)
+	route := routes[0].(map[string]any)
+	assert.Equal(t, "preview", route["name"])
+	host, _, _ := unstructured.NestedString(route["route"].([]any)[0].(map[string]any), "destination", "host")
+	assert.Equal(t, "preview", host)
+	port, _, _ := unstructured.NestedFieldNoCopy(route["route"].([]any)[0].(map[string]any), "destination", "port", "number")
+	assert.EqualValues(t, 8080, port)
+	prefix, _, _ := unstructured.NestedString(route["match"].([]any)[0].(map[string]any), "headers", "x-preview", "prefix")
+	assert.Equal(t, "v1.", prefix)
+
+	// a second reconcile reads the VirtualService from the informer and does not update it
+	waitForInformer(2)
+	actions := len(client.Actions())
+	require.NoError(t, ctx.reconcilePreviewRouting(activeSvc))
+	assert.Len(t, client.Actions(), actions)
+
+	// the route is removed once the active service points at the new ReplicaSet
+	activeSvc.Spec.Selector[v1alpha1.DefaultRolloutUniqueLabelKey] = ctx.newRS.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	require.NoError(t, ctx.reconcilePreviewRouting(activeSvc))
+	routes = getRoutes()
+	require.Len(t, routes, 1)
+	assert.Equal(t, "primary", routes[0].(map[string]any)["name"])
+}
+
+func TestReconcilePreviewRoutingGatewayAPI(t *testing.T) {
+	ro := newPreviewRoutingRollout()
+	ro.Spec.Strategy.BlueGreen.PreviewRouting.GatewayAPI = &v1alpha1.BlueGreenGatewayAPIPreviewRouting{HTTPRoute: "route"}
+	ctx, client := newPreviewRoutingContext(ro, unstructuredutil.StrToUnstructuredUnsafe(previewRoutingHTTPRoute))
+	activeSvc := newService("active", 8080, map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: "stable"}, ro)
+
+	getRules := func() []any {
+		obj, err := client.Resource(gatewayAPIHTTPRouteGVR()).Namespace("default").Get(context.TODO(), "route", metav1.GetOptions{})
+		require.NoError(t, err)
+		rules, _, err := unstructured.NestedSlice(obj.Object, "spec", "rules")
+		require.NoError(t, err)
+		return rules
+	}
+
+	require.NoError(t, ctx.reconcilePreviewRouting(activeSvc))
+	rules := getRules()
+	// the HTTPRoute is not read again while the preview route is synced
+	actions := len(client.Actions())
+	require.NoError(t, ctx.reconcilePreviewRouting(activeSvc))
+	assert.Len(t, client.Actions(), actions)
+
+	require.Len(t, rules, 2)
+	rule := rules[0].(map[string]any)
+	assert.Equal(t, "preview", rule["name"])
+	backendRef := rule["backendRefs"].([]any)[0].(map[string]any)
+	assert.Equal(t, "preview", backendRef["name"])
+	assert.EqualValues(t, 8080, backendRef["port"])
+	header := rule["matches"].([]any)[0].(map[string]any)["headers"].([]any)[0].(map[string]any)
+	assert.Equal(t, "RegularExpression", header["type"])
+	assert.Equal(t, `^v1\..*`, header["value"])
+
+	// the rule is removed when the rollout is aborted
+	ro.Status.Abort = true
+	require.NoError(t, ctx.reconcilePreviewRouting(activeSvc))
+	rules = getRules()
+	require.Len(t, rules, 1)
+	assert.NotContains(t, rules[0].(map[string]any), "name")
+}
+
+func TestReconcilePreviewRoutingGatewayAPIMissingActiveBackend(t *testing.T) {
+	ro := newPreviewRoutingRollout()
+	ro.Spec.Strategy.BlueGreen.ActiveService = "other"
+	ro.Spec.Strategy.BlueGreen.PreviewRouting.GatewayAPI = &v1alpha1.BlueGreenGatewayAPIPreviewRouting{HTTPRoute: "route"}
+	ctx, _ := newPreviewRoutingContext(ro, unstructuredutil.StrToUnstructuredUnsafe(previewRoutingHTTPRoute))
+	activeSvc := newService("other", 8080, map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: "stable"}, ro)
+
+	err := ctx.reconcilePreviewRouting(activeSvc)
+	assert.EqualError(t, err, "HTTPRoute 'route' has no backendRef to active service 'other'")
+}
+
+func TestReconcilePreviewRoutingIstioUnavailable(t *testing.T) {
+	ro := newPreviewRoutingRollout()
+	ro.Spec.Strategy.BlueGreen.PreviewRouting.Istio = &v1alpha1.BlueGreenIstioPreviewRouting{VirtualService: "vsvc"}
+	ctx, _ := newPreviewRoutingContext(ro)
+	ctx.IstioController = nil
+	activeSvc := newService("active", 8080, map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: "stable"}, ro)
+
+	err := ctx.reconcilePreviewRouting(activeSvc)
+	assert.EqualError(t, err, "preview routing requires Istio, but the Istio controller is not available")
+}
@@ -0,0 +1,169 @@
+package rollout
+
+import (
+	"sort"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	"github.com/argoproj/argo-rollouts/utils/record"
+	rolloututil "github.com/argoproj/argo-rollouts/utils/rollout"
+)
+
+// ProgressionLimitsConfig limits the number of rollouts which progress through an update at once
+type ProgressionLimitsConfig struct {
+	// MaxConcurrent is the maximum number of rollouts progressing at once. Unlimited when zero.
+	MaxConcurrent int
+	// MaxConcurrentPerNamespace is the maximum number of rollouts of a namespace progressing at once. Unlimited when
+	// zero.
+	MaxConcurrentPerNamespace int
+}
+
+// progressionGate keeps track of the rollouts progressing through an update and holds back the updates of other
+// rollouts once the limits are reached. A held back rollout is pending until a progressing rollout completes or is
+// aborted. A nil progressionGate lets all rollouts progress.
+type progressionGate struct {
+	config ProgressionLimitsConfig
+
+	lock sync.Mutex
+	// progressing holds the namespaces of the progressing rollouts, keyed by rollout
+	progressing map[string]string
+	// pending holds the keys of the rollouts which are held back
+	pending map[string]bool
+}
+
+func newProgressionGate(config ProgressionLimitsConfig) *progressionGate {
+	if config.MaxConcurrent <= 0 && config.MaxConcurrentPerNamespace <= 0 {
+		return nil
+	}
+	return &progressionGate{
+		config:      config,
+		progressing: map[string]string{},
+		pending:     map[string]bool{},
+	}
+}
+
+// Acquire returns whether the rollout may progress, and records it as progressing if so. A rollout which already
+// progresses always may. When force is set, the rollout is recorded as progressing regardless of the limits, e.g.
+// because its update started before the controller was restarted.
+func (g *progressionGate) Acquire(key, namespace string, force bool) bool {
+	if g == nil {
+		return true
+	}
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if _, ok := g.progressing[key]; ok {
+		return true
+	}
+	if !force && g.limited(namespace) {
+		g.pending[key] = true
+		return false
+	}
+	g.progressing[key] = namespace
+	delete(g.pending, key)
+	return true
+}
+
+func (g *progressionGate) limited(namespace string) bool {
+	if g.config.MaxConcurrent > 0 && len(g.progressing) >= g.config.MaxConcurrent {
+		return true
+	}
+	if g.config.MaxConcurrentPerNamespace > 0 {
+		count := 0
+		for _, ns := range g.progressing {
+			if ns == namespace {
+				count++
+			}
+		}
+		return count >= g.config.MaxConcurrentPerNamespace
+	}
+	return false
+}
+
+// Release records that the rollout no longer progresses, e.g. once it is fully promoted, aborted or deleted. It
+// returns the keys of the pending rollouts which should be reconciled again, since they might progress now.
+func (g *progressionGate) Release(key string) []string {
+	if g == nil {
+		return nil
+	}
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	delete(g.pending, key)
+	if _, ok := g.progressing[key]; !ok {
+		return nil
+	}
+	delete(g.progressing, key)
+	keys := make([]string, 0, len(g.pending))
+	for pendingKey := range g.pending {
+		keys = append(keys, pendingKey)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// reconcileProgressionGate records whether the rollout progresses through an update, and holds back the start of
+// its update while the limits of the controller are reached. It must be called before the ReplicaSet of an update is
+// created. It returns whether the rollout is pending, in which case the update must not be started. Initial deploys, aborted rollouts and updates
+// back to an existing ReplicaSet are not held back.
+func (c *rolloutContext) reconcileProgressionGate() (bool, error) {
+	if c.progressionGate == nil {
+		return false, nil
+	}
+	key := c.rollout.Namespace + "/" + c.rollout.Name
+	switch {
+	case c.stableRS == nil || c.rollout.Status.Abort || (c.newRS != nil && c.newRS.Name == c.stableRS.Name):
+		c.releaseProgression(key)
+		return false, nil
+	case c.newRS != nil:
+		c.progressionGate.Acquire(key, c.rollout.Namespace, true)
+		return false, nil
+	}
+	if c.progressionGate.Acquire(key, c.rollout.Namespace, false) {
+		return false, nil
+	}
+	c.log.Info("Holding back update, since the maximum number of rollouts are progressing")
+	progCond := conditions.GetRolloutCondition(c.rollout.Status, v1alpha1.RolloutProgressing)
+	if progCond != nil && progCond.Reason == conditions.RolloutProgressionPendingReason {
+		return true, nil
+	}
+	c.recorder.Eventf(c.rollout, record.EventOptions{EventReason: conditions.RolloutProgressionPendingReason}, conditions.RolloutProgressionPendingMessage)
+	condition := conditions.NewRolloutCondition(v1alpha1.RolloutProgressing, corev1.ConditionUnknown, conditions.RolloutProgressionPendingReason, conditions.RolloutProgressionPendingMessage)
+	return true, c.patchCondition(c.rollout, c.rollout.Status.DeepCopy(), condition)
+}
+
+// releaseProgression releases the slot of the rollout and enqueues the pending rollouts, which might progress now
+func (c *reconcilerBase) releaseProgression(key string) {
+	for _, pendingKey := range c.progressionGate.Release(key) {
+		namespace, name, err := cache.SplitMetaNamespaceKey(pendingKey)
+		if err != nil {
+			continue
+		}
+		if ro, err := c.rolloutsLister.Rollouts(namespace).Get(name); err == nil {
+			c.enqueueRollout(ro)
+		}
+	}
+}
+
+// seedProgressionGate records the rollouts whose update already started as progressing, so that the limits also
+// account for them before they are reconciled for the first time after a restart of the controller
+func (c *Controller) seedProgressionGate() {
+	if c.progressionGate == nil {
+		return
+	}
+	rollouts, err := c.rolloutsLister.List(labels.Everything())
+	if err != nil {
+		log.Warnf("Failed to list rollouts to seed the progression limits: %v", err)
+		return
+	}
+	for _, ro := range rollouts {
+		if !c.sharder.Owns(ro) || ro.Status.StableRS == "" || ro.Status.Abort || rolloututil.IsFullyPromoted(ro) {
+			continue
+		}
+		c.progressionGate.Acquire(ro.Namespace+"/"+ro.Name, ro.Namespace, true)
+	}
+}
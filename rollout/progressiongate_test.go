@@ -0,0 +1,76 @@
+package rollout
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+)
+
+func TestProgressionGate(t *testing.T) {
+	assert.Nil(t, newProgressionGate(ProgressionLimitsConfig{}))
+	var nilGate *progressionGate
+	assert.True(t, nilGate.Acquire("default/foo", "default", false))
+	assert.Nil(t, nilGate.Release("default/foo"))
+
+	g := newProgressionGate(ProgressionLimitsConfig{MaxConcurrent: 2, MaxConcurrentPerNamespace: 1})
+	assert.True(t, g.Acquire("default/foo", "default", false))
+	assert.True(t, g.Acquire("default/foo", "default", false))
+	assert.False(t, g.Acquire("default/bar", "default", false))
+	assert.True(t, g.Acquire("other/foo", "other", false))
+	assert.False(t, g.Acquire("third/foo", "third", false))
+	// updates which already started are recorded regardless of the limits
+	assert.True(t, g.Acquire("default/baz", "default", true))
+
+	assert.Nil(t, g.Release("default/unknown"))
+	assert.Equal(t, []string{"default/bar", "third/foo"}, g.Release("default/baz"))
+	assert.False(t, g.Acquire("default/bar", "default", false))
+	assert.Equal(t, []string{"default/bar", "third/foo"}, g.Release("default/foo"))
+	assert.True(t, g.Acquire("default/bar", "default", false))
+	assert.Equal(t, []string{"third/foo"}, g.Release("default/bar"))
+}
+
+func TestReconcileProgressionGatePending(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	steps := []v1alpha1.CanaryStep{{
+		SetWeight: ptr.To[int32](10),
+	}}
+	r1 := newCanaryRollout("foo", 10, nil, steps, ptr.To[int32](0), intstr.FromInt(1), intstr.FromInt(0))
+	rs1 := newReplicaSetWithStatus(r1, 10, 10)
+	r2 := bumpVersion(r1)
+	r2 = updateCanaryRolloutStatus(r2, rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey], 10, 10, 10, false)
+
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.objects = append(f.objects, r2)
+	f.kubeobjects = append(f.kubeobjects, rs1)
+	f.replicaSetLister = append(f.replicaSetLister, rs1)
+
+	patchIndex := f.expectPatchRolloutAction(r2)
+	c, i, k8sI := f.newController(noResyncPeriodFunc)
+	c.progressionGate = newProgressionGate(ProgressionLimitsConfig{MaxConcurrent: 1})
+	c.progressionGate.Acquire("default/bar", "default", true)
+	f.runController(getKey(r2, t), true, false, c, i, k8sI)
+
+	patched := f.getPatchedRolloutAsObject(patchIndex)
+	progressingCondition := conditions.GetRolloutCondition(patched.Status, v1alpha1.RolloutProgressing)
+	assert.NotNil(t, progressingCondition)
+	assert.Equal(t, conditions.RolloutProgressionPendingReason, progressingCondition.Reason)
+	assert.Equal(t, "ProgressionPending: "+conditions.RolloutProgressionPendingMessage, patched.Status.Message)
+
+	// the pending rollout is enqueued once the progressing rollout releases its slot. The release is tested with a
+	// reconciler of its own, since the informers of the controller still call its enqueueRollout
+	var enqueued []any
+	base := reconcilerBase{
+		progressionGate: c.progressionGate,
+		rolloutsLister:  c.rolloutsLister,
+		enqueueRollout:  func(obj any) { enqueued = append(enqueued, obj) },
+	}
+	base.releaseProgression("default/bar")
+	assert.Len(t, enqueued, 1)
+}
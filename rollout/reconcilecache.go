@@ -0,0 +1,155 @@
+package rollout
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	controllerutil "github.com/argoproj/argo-rollouts/utils/controller"
+	rolloututil "github.com/argoproj/argo-rollouts/utils/rollout"
+)
+
+// ReconcileCacheConfig configures the short-circuiting of the reconciliations of idle rollouts which did not change
+// since their last successful reconciliation
+type ReconcileCacheConfig struct {
+	// MaxAge is how long the result of a reconciliation is reused at most. An unchanged rollout is reconciled again
+	// once it is older, e.g. to repair drift of the Services it manages. Disabled when zero.
+	MaxAge time.Duration
+}
+
+// reconcileFingerprint identifies the state of a rollout and of the objects it owns
+type reconcileFingerprint struct {
+	generation      int64
+	resourceVersion string
+	// owned is a hash of the resourceVersions of the owned ReplicaSets and Experiments and the phases of the owned
+	// AnalysisRuns
+	owned uint64
+}
+
+type reconcileCacheEntry struct {
+	fingerprint  reconcileFingerprint
+	reconciledAt time.Time
+}
+
+// reconcileCache remembers the fingerprints of the idle rollouts at their last successful reconciliation, so that
+// reconciliations of unchanged rollouts, e.g. triggered by a resync, can be skipped. A nil reconcileCache skips
+// nothing.
+type reconcileCache struct {
+	config ReconcileCacheConfig
+
+	lock    sync.Mutex
+	entries map[string]reconcileCacheEntry
+}
+
+func newReconcileCache(config ReconcileCacheConfig) *reconcileCache {
+	if config.MaxAge <= 0 {
+		return nil
+	}
+	return &reconcileCache{
+		config:  config,
+		entries: map[string]reconcileCacheEntry{},
+	}
+}
+
+// Fresh returns whether the rollout was successfully reconciled with the same fingerprint within the max age
+func (r *reconcileCache) Fresh(key string, fingerprint reconcileFingerprint, now time.Time) bool {
+	if r == nil {
+		return false
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	entry, ok := r.entries[key]
+	return ok && entry.fingerprint == fingerprint && now.Sub(entry.reconciledAt) < r.config.MaxAge
+}
+
+// Observe records the fingerprint of a successfully reconciled rollout. The reconciliation time of an unchanged
+// fingerprint is kept, so that the rollout is reconciled again once the max age expired.
+func (r *reconcileCache) Observe(key string, fingerprint reconcileFingerprint, now time.Time) {
+	if r == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if entry, ok := r.entries[key]; ok && entry.fingerprint == fingerprint && now.Sub(entry.reconciledAt) < r.config.MaxAge {
+		return
+	}
+	r.entries[key] = reconcileCacheEntry{fingerprint: fingerprint, reconciledAt: now}
+}
+
+// Forget removes the fingerprint of the rollout, e.g. after it was deleted or is no longer idle
+func (r *reconcileCache) Forget(key string) {
+	if r == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.entries, key)
+}
+
+// reconcileFingerprint returns the fingerprint of the rollout, and whether the rollout is idle, i.e. fully promoted
+// and healthy without any pending scale down, restart or running analysis. Only the reconciliations of idle rollouts
+// may be skipped, since the others depend on time as well, e.g. for pauses and scale down delays.
+func (c *Controller) reconcileFingerprint(ro *v1alpha1.Rollout) (reconcileFingerprint, bool) {
+	fingerprint := reconcileFingerprint{generation: ro.Generation, resourceVersion: ro.ResourceVersion}
+	if c.reconcileCache == nil {
+		return fingerprint, false
+	}
+	if ro.Status.ObservedGeneration != strconv.FormatInt(ro.Generation, 10) ||
+		ro.Status.Phase != v1alpha1.RolloutPhaseHealthy ||
+		!rolloututil.IsFullyPromoted(ro) ||
+		ro.Spec.Paused || ro.Status.Abort || ro.Spec.WorkloadRef != nil ||
+		(ro.Spec.RestartAt != nil && (ro.Status.RestartedAt == nil || ro.Status.RestartedAt.Before(ro.Spec.RestartAt))) {
+		return fingerprint, false
+	}
+
+	var parts []string
+	// orphans are included since they might be adopted by the rollout
+	rsObjs, err := controllerutil.ByOwner(c.replicaSetIndexer, ro.Namespace, ro.UID, true)
+	if err != nil {
+		return fingerprint, false
+	}
+	for _, obj := range rsObjs {
+		rs := obj.(*appsv1.ReplicaSet)
+		if rs.Labels[v1alpha1.DefaultRolloutUniqueLabelKey] != ro.Status.StableRS && rs.Spec.Replicas != nil && *rs.Spec.Replicas > 0 {
+			// an old ReplicaSet is about to be scaled down
+			return fingerprint, false
+		}
+		parts = append(parts, "rs/"+string(rs.UID)+"/"+rs.ResourceVersion)
+	}
+	arObjs, err := controllerutil.ByOwner(c.analysisRunIndexer, ro.Namespace, ro.UID, false)
+	if err != nil {
+		return fingerprint, false
+	}
+	for _, obj := range arObjs {
+		ar := obj.(*v1alpha1.AnalysisRun)
+		if !ar.Status.Phase.Completed() {
+			return fingerprint, false
+		}
+		parts = append(parts, "ar/"+string(ar.UID)+"/"+string(ar.Status.Phase))
+	}
+	exObjs, err := controllerutil.ByOwner(c.experimentsIndexer, ro.Namespace, ro.UID, false)
+	if err != nil {
+		return fingerprint, false
+	}
+	for _, obj := range exObjs {
+		ex := obj.(*v1alpha1.Experiment)
+		if !ex.Status.Phase.Completed() {
+			return fingerprint, false
+		}
+		parts = append(parts, "ex/"+string(ex.UID)+"/"+ex.ResourceVersion)
+	}
+
+	sort.Strings(parts)
+	h := fnv.New64a()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	fingerprint.owned = h.Sum64()
+	return fingerprint, true
+}
@@ -0,0 +1,134 @@
+package rollout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/hash"
+)
+
+func newIdleRollout() *v1alpha1.Rollout {
+	r := newCanaryRollout("foo", 1, nil, nil, nil, intstr.FromInt(1), intstr.FromInt(0))
+	r.UID = "rollout-uid"
+	r.Generation = 2
+	r.ResourceVersion = "100"
+	podHash := hash.ComputePodTemplateHash(&r.Spec.Template, r.Status.CollisionCount)
+	r.Status.ObservedGeneration = "2"
+	r.Status.Phase = v1alpha1.RolloutPhaseHealthy
+	r.Status.CurrentPodHash = podHash
+	r.Status.StableRS = podHash
+	return r
+}
+
+func TestReconcileCacheDisabled(t *testing.T) {
+	assert.Nil(t, newReconcileCache(ReconcileCacheConfig{}))
+
+	var r *reconcileCache
+	fingerprint := reconcileFingerprint{generation: 1, resourceVersion: "1"}
+	r.Observe("default/foo", fingerprint, time.Now())
+	r.Forget("default/foo")
+	assert.False(t, r.Fresh("default/foo", fingerprint, time.Now()))
+}
+
+func TestReconcileCache(t *testing.T) {
+	r := newReconcileCache(ReconcileCacheConfig{MaxAge: time.Hour})
+	now := time.Now()
+	fingerprint := reconcileFingerprint{generation: 1, resourceVersion: "1", owned: 42}
+
+	assert.False(t, r.Fresh("default/foo", fingerprint, now))
+	r.Observe("default/foo", fingerprint, now)
+	assert.True(t, r.Fresh("default/foo", fingerprint, now.Add(time.Minute)))
+	assert.False(t, r.Fresh("default/bar", fingerprint, now.Add(time.Minute)))
+
+	changed := fingerprint
+	changed.owned = 43
+	assert.False(t, r.Fresh("default/foo", changed, now.Add(time.Minute)))
+
+	// observing an unchanged fingerprint keeps the time of the first reconciliation
+	r.Observe("default/foo", fingerprint, now.Add(30*time.Minute))
+	assert.False(t, r.Fresh("default/foo", fingerprint, now.Add(time.Hour)))
+	r.Observe("default/foo", fingerprint, now.Add(time.Hour))
+	assert.True(t, r.Fresh("default/foo", fingerprint, now.Add(time.Hour+time.Minute)))
+
+	r.Forget("default/foo")
+	assert.False(t, r.Fresh("default/foo", fingerprint, now.Add(time.Hour+time.Minute)))
+}
+
+func TestReconcileFingerprint(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	r := newIdleRollout()
+	rs := newReplicaSetWithStatus(r, 1, 1)
+	f.rolloutLister = append(f.rolloutLister, r)
+	f.replicaSetLister = append(f.replicaSetLister, rs)
+	c, _, k8sI := f.newController(noResyncPeriodFunc)
+	c.reconcileCache = newReconcileCache(ReconcileCacheConfig{MaxAge: time.Hour})
+
+	fingerprint, idle := c.reconcileFingerprint(r)
+	assert.True(t, idle)
+
+	t.Run("ReplicaSetChanged", func(t *testing.T) {
+		updated := rs.DeepCopy()
+		updated.ResourceVersion = "200"
+		k8sI.Apps().V1().ReplicaSets().Informer().GetIndexer().Update(updated)
+		defer k8sI.Apps().V1().ReplicaSets().Informer().GetIndexer().Update(rs)
+		changed, idle := c.reconcileFingerprint(r)
+		assert.True(t, idle)
+		assert.NotEqual(t, fingerprint, changed)
+	})
+	t.Run("OldReplicaSetScaledUp", func(t *testing.T) {
+		old := newReplicaSetWithStatus(r, 1, 1)
+		old.Name = "foo-old"
+		old.UID = "old-uid"
+		old.Labels[v1alpha1.DefaultRolloutUniqueLabelKey] = "old"
+		k8sI.Apps().V1().ReplicaSets().Informer().GetIndexer().Add(old)
+		defer k8sI.Apps().V1().ReplicaSets().Informer().GetIndexer().Delete(old)
+		_, idle := c.reconcileFingerprint(r)
+		assert.False(t, idle)
+	})
+	t.Run("NotIdle", func(t *testing.T) {
+		for _, mutate := range []func(*v1alpha1.Rollout){
+			func(ro *v1alpha1.Rollout) { ro.Status.Phase = v1alpha1.RolloutPhaseProgressing },
+			func(ro *v1alpha1.Rollout) { ro.Status.ObservedGeneration = "1" },
+			func(ro *v1alpha1.Rollout) { ro.Status.CurrentPodHash = "other" },
+			func(ro *v1alpha1.Rollout) { ro.Spec.Paused = true },
+			func(ro *v1alpha1.Rollout) { ro.Spec.WorkloadRef = &v1alpha1.ObjectRef{Name: "foo"} },
+			func(ro *v1alpha1.Rollout) { ro.Spec.RestartAt = &metav1.Time{Time: time.Now().Add(time.Hour)} },
+		} {
+			ro := r.DeepCopy()
+			mutate(ro)
+			_, idle := c.reconcileFingerprint(ro)
+			assert.False(t, idle)
+		}
+	})
+}
+
+func TestSyncHandlerSkipsUnchangedIdleRollout(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	r := newIdleRollout()
+	rs := newReplicaSetWithStatus(r, 1, 1)
+	f.rolloutLister = append(f.rolloutLister, r)
+	f.objects = append(f.objects, r)
+	f.replicaSetLister = append(f.replicaSetLister, rs)
+	f.kubeobjects = append(f.kubeobjects, rs)
+
+	c, _, _ := f.newController(noResyncPeriodFunc)
+	c.reconcileCache = newReconcileCache(ReconcileCacheConfig{MaxAge: time.Hour})
+	roKey := getKey(r, t)
+	fingerprint, idle := c.reconcileFingerprint(r)
+	assert.True(t, idle)
+	c.reconcileCache.Observe(roKey, fingerprint, time.Now())
+
+	assert.NoError(t, c.syncHandler(context.Background(), roKey))
+	assert.Empty(t, f.client.Actions())
+	assert.Empty(t, f.kubeclient.Actions())
+}
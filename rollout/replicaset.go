@@ -7,14 +7,17 @@ import (
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/labels"
 	patchtypes "k8s.io/apimachinery/pkg/types"
+	appsv1ac "k8s.io/client-go/applyconfigurations/apps/v1"
 	"k8s.io/kubernetes/pkg/controller"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/annotations"
+	controllerutil "github.com/argoproj/argo-rollouts/utils/controller"
 	"github.com/argoproj/argo-rollouts/utils/defaults"
 	logutil "github.com/argoproj/argo-rollouts/utils/log"
 	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
@@ -25,7 +28,6 @@ import (
 var controllerKind = v1alpha1.SchemeGroupVersion.WithKind("Rollout")
 
 const (
-	addScaleDownAtAnnotationsPatch    = `[{ "op": "add", "path": "/metadata/annotations/%s", "value": "%s"}]`
 	removeScaleDownAtAnnotationsPatch = `[{ "op": "remove", "path": "/metadata/annotations/%s"}]`
 )
 
@@ -36,7 +38,7 @@ func (c *rolloutContext) removeScaleDownDelay(rs *appsv1.ReplicaSet) error {
 		return nil
 	}
 	patch := fmt.Sprintf(removeScaleDownAtAnnotationsPatch, v1alpha1.DefaultReplicaSetScaleDownDeadlineAnnotationKey)
-	rs, err := c.kubeclientset.AppsV1().ReplicaSets(rs.Namespace).Patch(ctx, rs.Name, patchtypes.JSONPatchType, []byte(patch), metav1.PatchOptions{})
+	rs, err := c.kubeclientset.AppsV1().ReplicaSets(rs.Namespace).Patch(ctx, rs.Name, patchtypes.JSONPatchType, []byte(patch), metav1.PatchOptions{FieldManager: defaults.DefaultFieldManager})
 	if err != nil {
 		return fmt.Errorf("error removing scale-down-deadline annotation from RS '%s': %w", rs.Name, err)
 	}
@@ -60,8 +62,9 @@ func (c *rolloutContext) addScaleDownDelay(rs *appsv1.ReplicaSet, scaleDownDelay
 		return nil
 	}
 	deadline := timeutil.MetaNow().Add(scaleDownDelaySeconds).UTC().Format(time.RFC3339)
-	patch := fmt.Sprintf(addScaleDownAtAnnotationsPatch, v1alpha1.DefaultReplicaSetScaleDownDeadlineAnnotationKey, deadline)
-	rs, err := c.kubeclientset.AppsV1().ReplicaSets(rs.Namespace).Patch(ctx, rs.Name, patchtypes.JSONPatchType, []byte(patch), metav1.PatchOptions{})
+	applyConfig := appsv1ac.ReplicaSet(rs.Name, rs.Namespace).
+		WithAnnotations(map[string]string{v1alpha1.DefaultReplicaSetScaleDownDeadlineAnnotationKey: deadline})
+	rs, err := c.kubeclientset.AppsV1().ReplicaSets(rs.Namespace).Apply(ctx, applyConfig, metav1.ApplyOptions{FieldManager: defaults.DefaultFieldManager, Force: true})
 	if err != nil {
 		return fmt.Errorf("error adding scale-down-deadline annotation to RS '%s': %w", rs.Name, err)
 	}
@@ -69,14 +72,76 @@ func (c *rolloutContext) addScaleDownDelay(rs *appsv1.ReplicaSet, scaleDownDelay
 	return err
 }
 
+// hydrateReplicaSets replaces the compacted ReplicaSets of the rollout whose pod template is needed during the
+// reconciliation with the full ReplicaSets from the API server. These are the new and the stable ReplicaSet, e.g.
+// when rolling back to a scaled down revision, or all ReplicaSets when the new ReplicaSet can only be found by
+// comparing the pod templates. The older ReplicaSets are only told apart by their pod template hash, so that they
+// stay compacted. The hydrated ReplicaSets are stored back in the informer cache, so that they are not hydrated
+// again on every reconciliation. They are compacted again by the next update of the informer.
+func (c *Controller) hydrateReplicaSets(r *v1alpha1.Rollout, rsList []*appsv1.ReplicaSet) ([]*appsv1.ReplicaSet, error) {
+	ctx := context.TODO()
+	newRS := replicasetutil.FindNewReplicaSet(r, rsList)
+	var hydratedList []*appsv1.ReplicaSet
+	for i, rs := range rsList {
+		if !replicasetutil.IsCompacted(rs) {
+			continue
+		}
+		if newRS != nil && rs.Name != newRS.Name && replicasetutil.GetPodTemplateHash(rs) != r.Status.StableRS {
+			continue
+		}
+		if hydratedList == nil {
+			// the list must not be modified in place, since it might be shared
+			hydratedList = append([]*appsv1.ReplicaSet{}, rsList...)
+		}
+		hydratedRS, err := c.hydrateReplicaSet(ctx, rs)
+		if err != nil {
+			return nil, err
+		}
+		hydratedList[i] = hydratedRS
+		c.storeHydratedReplicaSet(r, hydratedRS)
+	}
+	if hydratedList == nil {
+		return rsList, nil
+	}
+	return hydratedList, nil
+}
+
+// storeHydratedReplicaSet replaces a compacted ReplicaSet in the informer cache with its hydrated ReplicaSet, unless
+// the informer has observed a newer version of the ReplicaSet in the meantime
+func (c *Controller) storeHydratedReplicaSet(r *v1alpha1.Rollout, hydratedRS *appsv1.ReplicaSet) {
+	obj, exists, err := c.replicaSetIndexer.Get(hydratedRS)
+	if err != nil || !exists {
+		return
+	}
+	if cachedRS, ok := obj.(*appsv1.ReplicaSet); !ok || cachedRS.ResourceVersion != hydratedRS.ResourceVersion {
+		return
+	}
+	if err := c.replicaSetIndexer.Update(hydratedRS); err != nil {
+		logutil.WithRollout(r).Warnf("Failed to store hydrated ReplicaSet '%s' in the informer cache: %v", hydratedRS.Name, err)
+	}
+}
+
+// hydrateReplicaSet gets the full ReplicaSet of a compacted ReplicaSet from the API server
+func (c *reconcilerBase) hydrateReplicaSet(ctx context.Context, rs *appsv1.ReplicaSet) (*appsv1.ReplicaSet, error) {
+	hydratedRS, err := c.kubeclientset.AppsV1().ReplicaSets(rs.Namespace).Get(ctx, rs.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error hydrating compacted ReplicaSet '%s': %w", rs.Name, err)
+	}
+	return hydratedRS, nil
+}
+
 func (c *Controller) getReplicaSetsForRollouts(r *v1alpha1.Rollout) ([]*appsv1.ReplicaSet, error) {
 	ctx := context.TODO()
-	// List all ReplicaSets to find those we own but that no longer match our
-	// selector. They will be orphaned by ClaimReplicaSets().
-	rsList, err := c.replicaSetLister.ReplicaSets(r.Namespace).List(labels.Everything())
+	// Get all ReplicaSets we own, including those that no longer match our selector, along with the orphaned
+	// ReplicaSets we may adopt. The former will be orphaned and the latter adopted by ClaimReplicaSets().
+	objs, err := controllerutil.ByOwner(c.replicaSetIndexer, r.Namespace, r.UID, true)
 	if err != nil {
 		return nil, err
 	}
+	rsList := make([]*appsv1.ReplicaSet, 0, len(objs))
+	for _, obj := range objs {
+		rsList = append(rsList, obj.(*appsv1.ReplicaSet))
+	}
 	replicaSetSelector, err := metav1.LabelSelectorAsSelector(r.Spec.Selector)
 	if err != nil {
 		return nil, fmt.Errorf("rollout %s/%s has invalid label selector: %v", r.Namespace, r.Name, err)
@@ -301,6 +366,41 @@ func (c *rolloutContext) cleanupUnhealthyReplicas(oldRSs []*appsv1.ReplicaSet) (
 	return oldRSs, totalScaledDown, nil
 }
 
+// getPodsOwnedByReplicaSet returns the pods controlled by the ReplicaSet from the informer cache
+func (c *reconcilerBase) getPodsOwnedByReplicaSet(rs *appsv1.ReplicaSet) ([]*corev1.Pod, error) {
+	selector, err := metav1.LabelSelectorAsSelector(rs.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := c.podLister.Pods(rs.Namespace).List(selector)
+	if err != nil {
+		return nil, err
+	}
+	var podsOwnedByRS []*corev1.Pod
+	for _, pod := range pods {
+		if metav1.IsControlledBy(pod, rs) {
+			podsOwnedByRS = append(podsOwnedByRS, pod)
+		}
+	}
+	return podsOwnedByRS, nil
+}
+
+// revisionsBehind returns the number of revisions the given old ReplicaSet is behind the current revision of the
+// rollout, according to their revision annotations. It returns 0 if either revision is unknown.
+func (c *rolloutContext) revisionsBehind(rs *appsv1.ReplicaSet) int32 {
+	var currentRevision int64
+	if c.newRS != nil {
+		currentRevision, _ = replicasetutil.Revision(c.newRS)
+	} else if revision, ok := annotations.GetRevisionAnnotation(c.rollout); ok {
+		currentRevision = int64(revision)
+	}
+	revision, err := replicasetutil.Revision(rs)
+	if err != nil || revision == 0 || currentRevision <= revision {
+		return 0
+	}
+	return int32(currentRevision - revision)
+}
+
 func (c *rolloutContext) scaleDownDelayHelper(rs *appsv1.ReplicaSet, annotationedRSs int32, rolloutReplicas int32) (int32, int32, error) {
 	desiredReplicaCount := int32(0)
 	scaleDownRevisionLimit := GetScaleDownRevisionLimit(c.rollout)
@@ -309,7 +409,7 @@ func (c *rolloutContext) scaleDownDelayHelper(rs *appsv1.ReplicaSet, annotatione
 		if annotationedRSs < scaleDownRevisionLimit {
 			annotationedRSs++
 			desiredReplicaCount = *rs.Spec.Replicas
-			scaleDownDelaySeconds := defaults.GetScaleDownDelaySecondsOrDefault(c.rollout)
+			scaleDownDelaySeconds := defaults.GetScaleDownDelaySecondsForRevisionOrDefault(c.rollout, c.revisionsBehind(rs))
 			err := c.addScaleDownDelay(rs, scaleDownDelaySeconds)
 			if err != nil {
 				return annotationedRSs, desiredReplicaCount, err
@@ -8,7 +8,9 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -17,6 +19,7 @@ import (
 	k8sinformers "k8s.io/client-go/informers"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
 	core "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/utils/ptr"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
@@ -24,6 +27,7 @@ import (
 	"github.com/argoproj/argo-rollouts/rollout/trafficrouting/istio"
 	testutil "github.com/argoproj/argo-rollouts/test/util"
 	"github.com/argoproj/argo-rollouts/utils/annotations"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
 	istioutil "github.com/argoproj/argo-rollouts/utils/istio"
 	logutil "github.com/argoproj/argo-rollouts/utils/log"
 	"github.com/argoproj/argo-rollouts/utils/record"
@@ -896,3 +900,66 @@ func TestScaleDownProgressively(t *testing.T) {
 		})
 	}
 }
+
+func TestHydrateReplicaSets(t *testing.T) {
+	defaults.SetCompactReplicaSetHistory(true)
+	defer defaults.SetCompactReplicaSetHistory(false)
+
+	f := newFixture(t)
+	defer f.Close()
+
+	r1 := newCanaryRollout("foo", 1, nil, nil, nil, intstr.FromInt(1), intstr.FromInt(0))
+	r2 := bumpVersion(r1)
+	r3 := bumpVersion(r2)
+	rs1 := newReplicaSetWithStatus(r1, 0, 0)
+	rs2 := newReplicaSetWithStatus(r2, 0, 0)
+	rs3 := newReplicaSetWithStatus(r3, 1, 1)
+	f.kubeobjects = append(f.kubeobjects, rs1, rs2, rs3)
+
+	compactedRS1 := rs1.DeepCopy()
+	compactedRS1.Spec.Template.Spec = corev1.PodSpec{}
+	compactedRS2 := rs2.DeepCopy()
+	compactedRS2.Spec.Template.Spec = corev1.PodSpec{}
+	c, _, _ := f.newController(noResyncPeriodFunc)
+
+	// rolling back to the first revision only hydrates the new ReplicaSet
+	rollback := r3.DeepCopy()
+	rollback.Spec.Template = r1.Spec.Template
+	rollback.Status.StableRS = rs3.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	rsList := []*appsv1.ReplicaSet{compactedRS1, compactedRS2, rs3}
+	hydratedList, err := c.hydrateReplicaSets(rollback, rsList)
+	assert.NoError(t, err)
+	assert.Equal(t, rs1, hydratedList[0])
+	assert.Equal(t, compactedRS2, hydratedList[1])
+	assert.Equal(t, rs3, hydratedList[2])
+	assert.Equal(t, compactedRS1, rsList[0])
+
+	// the hydrated ReplicaSet is stored back in the informer cache, unless the cache holds a newer version
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, indexer.Add(compactedRS1))
+	staleRS2 := compactedRS2.DeepCopy()
+	staleRS2.ResourceVersion = "2"
+	require.NoError(t, indexer.Add(staleRS2))
+	c.replicaSetIndexer = indexer
+	rollback.Status.StableRS = rs2.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	_, err = c.hydrateReplicaSets(rollback, []*appsv1.ReplicaSet{compactedRS1, compactedRS2, rs3})
+	assert.NoError(t, err)
+	cachedRS1, _, err := indexer.Get(rs1)
+	require.NoError(t, err)
+	assert.Equal(t, rs1, cachedRS1)
+	cachedRS2, _, err := indexer.Get(rs2)
+	require.NoError(t, err)
+	assert.Equal(t, staleRS2, cachedRS2)
+	rollback.Status.StableRS = rs3.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+
+	// nothing is hydrated when the older ReplicaSets are not needed
+	hydratedList, err = c.hydrateReplicaSets(r3, []*appsv1.ReplicaSet{compactedRS1, compactedRS2, rs3})
+	assert.NoError(t, err)
+	assert.Equal(t, []*appsv1.ReplicaSet{compactedRS1, compactedRS2, rs3}, hydratedList)
+
+	// a ReplicaSet which no longer exists cannot be hydrated
+	deletedRS := compactedRS1.DeepCopy()
+	deletedRS.Name = "foo-deleted"
+	_, err = c.hydrateReplicaSets(rollback, []*appsv1.ReplicaSet{deletedRS, rs3})
+	assert.Error(t, err)
+}
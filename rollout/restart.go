@@ -2,6 +2,7 @@ package rollout
 
 import (
 	"context"
+	"fmt"
 	"sort"
 	"time"
 
@@ -15,7 +16,10 @@ import (
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	analysisutil "github.com/argoproj/argo-rollouts/utils/analysis"
 	"github.com/argoproj/argo-rollouts/utils/defaults"
+	logutil "github.com/argoproj/argo-rollouts/utils/log"
+	"github.com/argoproj/argo-rollouts/utils/record"
 	"github.com/argoproj/argo-rollouts/utils/replicaset"
 	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
 	timeutil "github.com/argoproj/argo-rollouts/utils/time"
@@ -74,6 +78,9 @@ func (p *RolloutPodRestarter) Reconcile(roCtx *rolloutContext) (int, error) {
 	if err != nil {
 		return restarted, err
 	}
+	if roCtx.rollout.Spec.RestartStrategy != nil {
+		return p.reconcileBatches(ctx, roCtx, s.allRSs, rolloutPods)
+	}
 	// total replicas can be higher than spec.replicas (e.g. when we are a canary weight that is not
 	// evenly divisible by the spec.replicas)
 	totalReplicas := replicasetutil.GetReplicaCountForReplicaSets(s.allRSs)
@@ -133,10 +140,161 @@ func (p *RolloutPodRestarter) Reconcile(roCtx *rolloutContext) (int, error) {
 	} else {
 		logCtx.Infof("all %d pods are current. setting restartedAt", len(rolloutPods))
 		roCtx.SetRestartedAt()
+		roCtx.newStatus.RestartStatus = nil
 	}
 	return restarted, err
 }
 
+// reconcileBatches restarts the pods of a Rollout in batches of the restart strategy's batch size.
+// The next batch is only restarted once all pods are available, the pause between batches has passed,
+// and the analysis of the previous batch succeeded. The progress is recorded in status.restartStatus
+// in reconciliations which do not restart any pods, since the rollout status is not persisted when
+// pods are restarted. Returns the number of pods restarted.
+func (p *RolloutPodRestarter) reconcileBatches(ctx context.Context, roCtx *rolloutContext, allRSs []*appsv1.ReplicaSet, rolloutPods []*corev1.Pod) (int, error) {
+	logCtx := roCtx.log.WithField("Reconciler", "PodRestarter")
+	strategy := roCtx.rollout.Spec.RestartStrategy
+	restartAt := roCtx.rollout.Spec.RestartAt
+	now := timeutil.MetaNow()
+
+	var outdated []*corev1.Pod
+	var restartedReplicas int32
+	for _, pod := range rolloutPods {
+		if pod.CreationTimestamp.After(restartAt.Time) || pod.CreationTimestamp.Equal(restartAt) {
+			restartedReplicas++
+			continue
+		}
+		outdated = append(outdated, pod)
+	}
+
+	status := roCtx.rollout.Status.RestartStatus.DeepCopy()
+	if status == nil || !status.RestartAt.Equal(restartAt) {
+		// Record the pods which are already newer than restartAt before restarting the first batch,
+		// so that they are not mistaken for a completed batch
+		logCtx.Infof("Starting restart in batches of %d pods", replicasetutil.RestartBatchSize(roCtx.rollout))
+		roCtx.newStatus.RestartStatus = &v1alpha1.RolloutRestartStatus{
+			RestartAt:         *restartAt,
+			RestartedReplicas: restartedReplicas,
+		}
+		p.enqueueAfter(roCtx.rollout, restartPodCheckTime)
+		return 0, nil
+	}
+	roCtx.newStatus.RestartStatus = status
+	if status.Halted {
+		logCtx.Infof("Restart is halted: %s", status.Message)
+		return 0, nil
+	}
+
+	// Wait for the current batch to become available
+	totalReplicas := replicasetutil.GetReplicaCountForReplicaSets(allRSs)
+	replicas := maxInt(defaults.GetReplicasOrDefault(roCtx.rollout.Spec.Replicas), totalReplicas)
+	available := getAvailablePodCount(rolloutPods, roCtx.rollout.Spec.MinReadySeconds)
+	if available < replicas {
+		logCtx.Infof("Waiting for restarted pods to become available (available:%d, replicas:%d)", available, replicas)
+		p.enqueueAfter(roCtx.rollout, restartPodCheckTime)
+		return 0, nil
+	}
+
+	if restartedReplicas > status.RestartedReplicas {
+		status.CompletedBatches++
+		status.RestartedReplicas = restartedReplicas
+		status.BatchCompletedAt = &now
+		status.CurrentAnalysisRunStatus = nil
+		roCtx.currentArs.Restart = nil
+		roCtx.recorder.Eventf(roCtx.rollout, record.EventOptions{EventReason: "RestartBatchCompleted"}, "Restart batch %d completed (%d/%d pods restarted)", status.CompletedBatches, restartedReplicas, len(rolloutPods))
+		if len(outdated) > 0 && strategy.Analysis != nil && len(strategy.Analysis.Templates) > 0 {
+			podHash := replicasetutil.GetPodTemplateHash(roCtx.newRS)
+			instanceID := analysisutil.GetInstanceID(roCtx.rollout)
+			ar, err := roCtx.createAnalysisRun(strategy.Analysis, fmt.Sprintf("restart-%d", status.CompletedBatches), analysisutil.RestartLabels(podHash, instanceID))
+			if err != nil {
+				return 0, err
+			}
+			logCtx.WithField(logutil.AnalysisRunKey, ar.Name).Infof("Created AnalysisRun for restart batch %d", status.CompletedBatches)
+			roCtx.currentArs.Restart = ar
+			status.CurrentAnalysisRunStatus = &v1alpha1.RolloutAnalysisRunStatus{
+				Name:   ar.Name,
+				Status: ar.Status.Phase,
+			}
+		}
+		if len(outdated) > 0 {
+			// Persist the completed batch before restarting the next one
+			p.enqueueAfter(roCtx.rollout, restartPodCheckTime)
+			return 0, nil
+		}
+	}
+
+	if len(outdated) == 0 {
+		logCtx.Infof("all %d pods are current. setting restartedAt", len(rolloutPods))
+		roCtx.SetRestartedAt()
+		roCtx.newStatus.RestartStatus = nil
+		return 0, nil
+	}
+
+	if status.BatchCompletedAt != nil && strategy.PauseBetweenBatches != "" {
+		// Error caught by validation
+		pause, _ := strategy.PauseBetweenBatches.Duration()
+		if remaining := status.BatchCompletedAt.Add(pause).Sub(now.Time); remaining > 0 {
+			logCtx.Infof("Pausing %s before restarting the next batch", remaining.String())
+			p.enqueueAfter(roCtx.rollout, remaining)
+			return 0, nil
+		}
+	}
+
+	if status.CurrentAnalysisRunStatus != nil {
+		ar := roCtx.currentArs.Restart
+		if ar == nil {
+			logCtx.Warnf("AnalysisRun '%s' of restart batch %d not found", status.CurrentAnalysisRunStatus.Name, status.CompletedBatches)
+			return 0, nil
+		}
+		switch ar.Status.Phase {
+		case v1alpha1.AnalysisPhaseSuccessful:
+		case v1alpha1.AnalysisPhaseFailed, v1alpha1.AnalysisPhaseError, v1alpha1.AnalysisPhaseInconclusive:
+			status.Halted = true
+			status.Message = fmt.Sprintf("AnalysisRun '%s' of restart batch %d is %s", ar.Name, status.CompletedBatches, ar.Status.Phase)
+			if ar.Status.Message != "" {
+				status.Message += ": " + ar.Status.Message
+			}
+			roCtx.recorder.Warnf(roCtx.rollout, record.EventOptions{EventReason: "RestartHalted"}, "%s", status.Message)
+			return 0, nil
+		default:
+			logCtx.WithField(logutil.AnalysisRunKey, ar.Name).Infof("Waiting for analysis of restart batch %d", status.CompletedBatches)
+			return 0, nil
+		}
+	}
+
+	batchSize := replicasetutil.RestartBatchSize(roCtx.rollout)
+	restarted := 0
+	for _, pod := range outdated {
+		if int32(restarted) >= batchSize {
+			break
+		}
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		newLogCtx := logCtx.WithField("Pod", pod.Name).WithField("CreatedAt", pod.CreationTimestamp.Format(time.RFC3339)).WithField("RestartAt", restartAt.Format(time.RFC3339))
+		newLogCtx.Infof("restarting Pod in batch %d", status.CompletedBatches+1)
+		evictTarget := policy.Eviction{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      pod.Name,
+				Namespace: pod.Namespace,
+			},
+		}
+		err := p.client.CoreV1().Pods(pod.Namespace).Evict(ctx, &evictTarget)
+		if err != nil {
+			if k8serrors.IsTooManyRequests(err) {
+				// A PodDisruptionBudget prevented us from evicting the pod.
+				// Continue and allow rollout requeue to try again later.
+				newLogCtx.Warn(err)
+				continue
+			}
+			return restarted, err
+		}
+		restarted += 1
+	}
+	logCtx.Infof("%d/%d pods require restart. restarted %d in batch %d. retrying in %v", len(outdated), len(rolloutPods), restarted, status.CompletedBatches+1, restartPodCheckTime)
+	p.enqueueAfter(roCtx.rollout, restartPodCheckTime)
+	return restarted, nil
+}
+
 func maxInt(left, right int32) int32 {
 	if left > right {
 		return left
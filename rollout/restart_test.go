@@ -20,6 +20,7 @@ import (
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	"github.com/argoproj/argo-rollouts/utils/log"
+	"github.com/argoproj/argo-rollouts/utils/record"
 )
 
 func rollout(selector string, restartAt metav1.Time, restartedAt *metav1.Time) *v1alpha1.Rollout {
@@ -677,3 +678,183 @@ func TestRestartRespectPodDisruptionBudget(t *testing.T) {
 	assert.Equal(t, 0, restarted)
 	assert.True(t, enqueueCalled)
 }
+
+func TestRestartBatches(t *testing.T) {
+	now := metav1.Now()
+	restartAt := metav1.NewTime(now.Add(-time.Minute))
+	ro := rollout("test", restartAt, nil)
+	ro.Spec.Replicas = ptr.To[int32](3)
+	ro.Spec.Strategy.Canary = &v1alpha1.CanaryStrategy{}
+	batchSize := intstr.FromInt(2)
+	ro.Spec.RestartStrategy = &v1alpha1.RolloutRestartStrategy{
+		BatchSize:           &batchSize,
+		PauseBetweenBatches: "1m",
+	}
+	rs := replicaSet("rollout-restart-abc123", "test", 3, 3)
+	olderPod := func(name string) *corev1.Pod {
+		return pod(name, "test", metav1.NewTime(restartAt.Add(-time.Hour)), rs)
+	}
+	newerPod := func(name string) *corev1.Pod {
+		return pod(name, "test", metav1.NewTime(restartAt.Add(10*time.Second)), rs)
+	}
+	newContext := func(ro *v1alpha1.Rollout) *rolloutContext {
+		return &rolloutContext{
+			rollout:  ro,
+			log:      log.WithRollout(ro),
+			stableRS: rs,
+			allRSs:   []*appsv1.ReplicaSet{rs},
+			reconcilerBase: reconcilerBase{
+				recorder: record.NewFakeEventRecorder(),
+			},
+		}
+	}
+	withStatus := func(status v1alpha1.RolloutRestartStatus) *v1alpha1.Rollout {
+		ro := ro.DeepCopy()
+		status.RestartAt = restartAt
+		ro.Status.RestartStatus = &status
+		return ro
+	}
+	evictions := func(client *fake.Clientset) int {
+		count := 0
+		for _, action := range client.Actions() {
+			if action.Matches("create", "pods") && action.GetSubresource() == "eviction" {
+				count++
+			}
+		}
+		return count
+	}
+
+	t.Run("Starts restart without restarting pods", func(t *testing.T) {
+		roCtx := newContext(ro)
+		client := fake.NewSimpleClientset(rs, olderPod("a"), olderPod("b"), newerPod("c"))
+		r := RolloutPodRestarter{client: client, enqueueAfter: func(obj any, duration time.Duration) {}}
+		restarted, err := r.Reconcile(roCtx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, restarted)
+		assert.Equal(t, 0, evictions(client))
+		assert.Equal(t, restartAt, roCtx.newStatus.RestartStatus.RestartAt)
+		assert.Equal(t, int32(1), roCtx.newStatus.RestartStatus.RestartedReplicas)
+	})
+
+	t.Run("Restarts a batch", func(t *testing.T) {
+		roCtx := newContext(withStatus(v1alpha1.RolloutRestartStatus{}))
+		client := fake.NewSimpleClientset(rs, olderPod("a"), olderPod("b"), olderPod("c"))
+		r := RolloutPodRestarter{client: client, enqueueAfter: func(obj any, duration time.Duration) {}}
+		restarted, err := r.Reconcile(roCtx)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, restarted)
+		assert.Equal(t, 2, evictions(client))
+		assert.Nil(t, roCtx.newStatus.RestartedAt)
+	})
+
+	t.Run("Waits for the batch to become available", func(t *testing.T) {
+		roCtx := newContext(withStatus(v1alpha1.RolloutRestartStatus{}))
+		unavailable := newerPod("c")
+		unavailable.Status.Conditions = nil
+		client := fake.NewSimpleClientset(rs, olderPod("a"), olderPod("b"), unavailable)
+		enqueued := false
+		r := RolloutPodRestarter{client: client, enqueueAfter: func(obj any, duration time.Duration) { enqueued = true }}
+		restarted, err := r.Reconcile(roCtx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, restarted)
+		assert.Equal(t, 0, evictions(client))
+		assert.True(t, enqueued)
+		assert.Equal(t, int32(0), roCtx.newStatus.RestartStatus.CompletedBatches)
+	})
+
+	t.Run("Records completed batch", func(t *testing.T) {
+		roCtx := newContext(withStatus(v1alpha1.RolloutRestartStatus{}))
+		client := fake.NewSimpleClientset(rs, olderPod("a"), newerPod("b"), newerPod("c"))
+		r := RolloutPodRestarter{client: client, enqueueAfter: func(obj any, duration time.Duration) {}}
+		restarted, err := r.Reconcile(roCtx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, restarted)
+		assert.Equal(t, 0, evictions(client))
+		status := roCtx.newStatus.RestartStatus
+		assert.Equal(t, int32(1), status.CompletedBatches)
+		assert.Equal(t, int32(2), status.RestartedReplicas)
+		assert.NotNil(t, status.BatchCompletedAt)
+	})
+
+	t.Run("Pauses between batches", func(t *testing.T) {
+		roCtx := newContext(withStatus(v1alpha1.RolloutRestartStatus{
+			CompletedBatches:  1,
+			RestartedReplicas: 2,
+			BatchCompletedAt:  &now,
+		}))
+		client := fake.NewSimpleClientset(rs, olderPod("a"), newerPod("b"), newerPod("c"))
+		var enqueuedAfter time.Duration
+		r := RolloutPodRestarter{client: client, enqueueAfter: func(obj any, duration time.Duration) { enqueuedAfter = duration }}
+		restarted, err := r.Reconcile(roCtx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, restarted)
+		assert.Equal(t, 0, evictions(client))
+		assert.Greater(t, enqueuedAfter, 50*time.Second)
+	})
+
+	t.Run("Restarts next batch after pause", func(t *testing.T) {
+		batchCompletedAt := metav1.NewTime(now.Add(-2 * time.Minute))
+		roCtx := newContext(withStatus(v1alpha1.RolloutRestartStatus{
+			CompletedBatches:  1,
+			RestartedReplicas: 2,
+			BatchCompletedAt:  &batchCompletedAt,
+		}))
+		client := fake.NewSimpleClientset(rs, olderPod("a"), newerPod("b"), newerPod("c"))
+		r := RolloutPodRestarter{client: client, enqueueAfter: func(obj any, duration time.Duration) {}}
+		restarted, err := r.Reconcile(roCtx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, restarted)
+	})
+
+	t.Run("Waits for analysis of the batch", func(t *testing.T) {
+		batchCompletedAt := metav1.NewTime(now.Add(-2 * time.Minute))
+		for _, tc := range []struct {
+			phase     v1alpha1.AnalysisPhase
+			restarted int
+			halted    bool
+		}{
+			{v1alpha1.AnalysisPhaseRunning, 0, false},
+			{v1alpha1.AnalysisPhaseSuccessful, 1, false},
+			{v1alpha1.AnalysisPhaseFailed, 0, true},
+		} {
+			ar := &v1alpha1.AnalysisRun{
+				ObjectMeta: metav1.ObjectMeta{Name: "restart-1"},
+				Status:     v1alpha1.AnalysisRunStatus{Phase: tc.phase},
+			}
+			roCtx := newContext(withStatus(v1alpha1.RolloutRestartStatus{
+				CompletedBatches:         1,
+				RestartedReplicas:        2,
+				BatchCompletedAt:         &batchCompletedAt,
+				CurrentAnalysisRunStatus: &v1alpha1.RolloutAnalysisRunStatus{Name: ar.Name},
+			}))
+			roCtx.currentArs.Restart = ar
+			client := fake.NewSimpleClientset(rs, olderPod("a"), newerPod("b"), newerPod("c"))
+			r := RolloutPodRestarter{client: client, enqueueAfter: func(obj any, duration time.Duration) {}}
+			restarted, err := r.Reconcile(roCtx)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.restarted, restarted, tc.phase)
+			assert.Equal(t, tc.halted, roCtx.newStatus.RestartStatus.Halted, tc.phase)
+		}
+	})
+
+	t.Run("Does not restart pods once halted", func(t *testing.T) {
+		roCtx := newContext(withStatus(v1alpha1.RolloutRestartStatus{Halted: true, Message: "halted"}))
+		client := fake.NewSimpleClientset(rs, olderPod("a"), olderPod("b"), olderPod("c"))
+		r := RolloutPodRestarter{client: client, enqueueAfter: func(obj any, duration time.Duration) {}}
+		restarted, err := r.Reconcile(roCtx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, restarted)
+		assert.Nil(t, roCtx.newStatus.RestartedAt)
+	})
+
+	t.Run("Completes restart", func(t *testing.T) {
+		roCtx := newContext(withStatus(v1alpha1.RolloutRestartStatus{CompletedBatches: 1, RestartedReplicas: 2}))
+		client := fake.NewSimpleClientset(rs, newerPod("a"), newerPod("b"), newerPod("c"))
+		r := RolloutPodRestarter{client: client, enqueueAfter: func(obj any, duration time.Duration) {}}
+		restarted, err := r.Reconcile(roCtx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, restarted)
+		assert.Equal(t, &restartAt, roCtx.newStatus.RestartedAt)
+		assert.Nil(t, roCtx.newStatus.RestartStatus)
+	})
+}
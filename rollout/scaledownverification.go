@@ -0,0 +1,243 @@
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+	"github.com/argoproj/argo-rollouts/utils/record"
+	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
+	tlsutil "github.com/argoproj/argo-rollouts/utils/tls"
+)
+
+// drainProbeTransport skips the verification of certificates, the same as the HTTPS probes of the kubelet, while the
+// rest of the outbound TLS policy of the controller applies. It is created once the policy is set
+var drainProbeTransport = sync.OnceValue(func() *http.Transport {
+	return &http.Transport{TLSClientConfig: tlsutil.ClientConfig(true)}
+})
+
+// probeActiveConnections sends the drain probe to the pod and returns the number of active connections it reports
+var probeActiveConnections = func(ctx context.Context, pod *corev1.Pod, probe *v1alpha1.DrainProbe) (int, error) {
+	scheme := strings.ToLower(string(probe.Scheme))
+	if scheme == "" {
+		scheme = "http"
+	}
+	path := probe.Path
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	probeURL := fmt.Sprintf("%s://%s%s", scheme, net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(probe.Port))), path)
+
+	ctx, cancel := context.WithTimeout(ctx, defaults.GetDrainProbeTimeoutOrDefault(probe))
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, probeURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	client := &http.Client{Transport: drainProbeTransport()}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("received status code %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64))
+	if err != nil {
+		return 0, err
+	}
+	activeConnections, err := strconv.Atoi(strings.TrimSpace(string(body)))
+	if err != nil {
+		return 0, fmt.Errorf("response is not a number of active connections: %w", err)
+	}
+	return activeConnections, nil
+}
+
+// drainProbes tracks the drain probes of the old ReplicaSets, which run in the background so that slow or
+// unresponsive pods do not block the reconciliation of rollouts
+type drainProbes struct {
+	lock    sync.Mutex
+	entries map[string]*drainProbeEntry
+}
+
+type drainProbeEntry struct {
+	// running indicates that the pods are being probed
+	running bool
+	// done indicates that the probe completed with the given result, which was not yet consumed
+	done   bool
+	result string
+	// timeoutReported indicates that the timeout of the verification was reported
+	timeoutReported bool
+}
+
+func newDrainProbes() *drainProbes {
+	return &drainProbes{entries: map[string]*drainProbeEntry{}}
+}
+
+func drainProbeKey(ro *v1alpha1.Rollout, rs *appsv1.ReplicaSet) string {
+	return fmt.Sprintf("%s/%s/%s", ro.Namespace, ro.Name, rs.UID)
+}
+
+func (d *drainProbes) entry(key string) *drainProbeEntry {
+	entry, ok := d.entries[key]
+	if !ok {
+		entry = &drainProbeEntry{}
+		d.entries[key] = entry
+	}
+	return entry
+}
+
+// result returns and consumes the result of the completed probe, if any
+func (d *drainProbes) result(key string) (string, bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	entry, ok := d.entries[key]
+	if !ok || !entry.done {
+		return "", false
+	}
+	entry.done = false
+	return entry.result, true
+}
+
+// start runs the probe in the background unless it is already running, and calls onDone once it completed
+func (d *drainProbes) start(key string, probe func() string, onDone func()) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	entry := d.entry(key)
+	if entry.running {
+		return
+	}
+	entry.running = true
+	go func() {
+		result := probe()
+		d.lock.Lock()
+		entry.running = false
+		entry.done = true
+		entry.result = result
+		d.lock.Unlock()
+		onDone()
+	}()
+}
+
+// reportTimeout returns true the first time it is called for the ReplicaSet
+func (d *drainProbes) reportTimeout(key string) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	entry := d.entry(key)
+	if entry.timeoutReported {
+		return false
+	}
+	entry.timeoutReported = true
+	return true
+}
+
+// forget removes the probe of a ReplicaSet which was scaled down
+func (d *drainProbes) forget(key string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	delete(d.entries, key)
+}
+
+// Forget removes the probes of a deleted rollout
+func (d *drainProbes) Forget(rolloutKey string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for key := range d.entries {
+		if strings.HasPrefix(key, rolloutKey+"/") {
+			delete(d.entries, key)
+		}
+	}
+}
+
+// isScaleDownVerified returns whether the previous stable ReplicaSet, whose scale down deadline passed, can be
+// scaled down according to the scale down verification of the canary strategy. The verification is skipped
+// once it did not succeed within its timeout.
+func (c *rolloutContext) isScaleDownVerified(rs *appsv1.ReplicaSet) bool {
+	verification := c.rollout.Spec.Strategy.Canary.ScaleDownVerification
+	if verification == nil || !replicasetutil.HasScaleDownDeadline(rs) {
+		return true
+	}
+	timeout := defaults.GetScaleDownVerificationTimeoutOrDefault(c.rollout)
+	if c.scaleDownVerificationTimedOut(rs, timeout) {
+		if c.drainProbes.reportTimeout(drainProbeKey(c.rollout, rs)) {
+			c.recorder.Warnf(c.rollout, record.EventOptions{EventReason: "ScaleDownVerificationTimedOut"}, "Scaling down ReplicaSet %s without verification after %s", rs.Name, timeout.String())
+		}
+		return true
+	}
+	if reason := c.verifyScaleDown(rs, verification); reason != "" {
+		c.log.Infof("Skip scale down of older RS '%s': %s", rs.Name, reason)
+		c.enqueueRolloutAfter(c.rollout, defaults.GetRolloutVerifyRetryInterval())
+		return false
+	}
+	return true
+}
+
+// scaleDownVerificationTimedOut returns whether the timeout passed since the scale down deadline of the ReplicaSet
+func (c *rolloutContext) scaleDownVerificationTimedOut(rs *appsv1.ReplicaSet, timeout time.Duration) bool {
+	scaleDownAt, err := time.Parse(time.RFC3339, rs.Annotations[v1alpha1.DefaultReplicaSetScaleDownDeadlineAnnotationKey])
+	if err != nil {
+		c.log.Warnf("unable to read scaleDownAt label on rs '%s'", rs.Name)
+		return true
+	}
+	return nowFn().After(scaleDownAt.Add(timeout))
+}
+
+// verifyScaleDown returns the reason why the ReplicaSet cannot be scaled down yet, or an empty string if it can.
+// The pods are probed in the background, and the rollout is requeued once the probe completed.
+func (c *rolloutContext) verifyScaleDown(rs *appsv1.ReplicaSet, verification *v1alpha1.ScaleDownVerification) string {
+	if weights := c.newStatus.Canary.Weights; weights != nil && weights.Verified != nil && !*weights.Verified {
+		return "traffic weights not yet verified"
+	}
+	if verification.DrainProbe == nil {
+		return ""
+	}
+	key := drainProbeKey(c.rollout, rs)
+	if result, done := c.drainProbes.result(key); done {
+		return result
+	}
+	pods, err := c.getPodsOwnedByReplicaSet(rs)
+	if err != nil {
+		return fmt.Sprintf("failed to list pods: %v", err)
+	}
+	var probedPods []*corev1.Pod
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil || pod.Status.PodIP == "" {
+			continue
+		}
+		probedPods = append(probedPods, pod.DeepCopy())
+	}
+	drainProbe := verification.DrainProbe.DeepCopy()
+	rollout := c.rollout
+	c.drainProbes.start(key, func() string {
+		return probeDrainedPods(probedPods, drainProbe)
+	}, func() {
+		c.enqueueRollout(rollout)
+	})
+	return "drain probe in progress"
+}
+
+// probeDrainedPods returns the reason why the pods are not drained, or an empty string if they are
+func probeDrainedPods(pods []*corev1.Pod, drainProbe *v1alpha1.DrainProbe) string {
+	for _, pod := range pods {
+		activeConnections, err := probeActiveConnections(context.Background(), pod, drainProbe)
+		if err != nil {
+			return fmt.Sprintf("drain probe of pod '%s' failed: %v", pod.Name, err)
+		}
+		if activeConnections > 0 {
+			return fmt.Sprintf("pod '%s' reports %d active connections", pod.Name, activeConnections)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,175 @@
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	logutil "github.com/argoproj/argo-rollouts/utils/log"
+	"github.com/argoproj/argo-rollouts/utils/record"
+)
+
+func TestProbeActiveConnections(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/connections":
+			fmt.Fprint(w, "3\n")
+		case "/invalid":
+			fmt.Fprint(w, "three")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	host, portStr, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+	pod := &corev1.Pod{Status: corev1.PodStatus{PodIP: host}}
+
+	activeConnections, err := probeActiveConnections(context.TODO(), pod, &v1alpha1.DrainProbe{Path: "connections", Port: int32(port)})
+	require.NoError(t, err)
+	assert.Equal(t, 3, activeConnections)
+
+	_, err = probeActiveConnections(context.TODO(), pod, &v1alpha1.DrainProbe{Path: "/invalid", Port: int32(port)})
+	assert.ErrorContains(t, err, "not a number")
+
+	_, err = probeActiveConnections(context.TODO(), pod, &v1alpha1.DrainProbe{Path: "/missing", Port: int32(port)})
+	assert.EqualError(t, err, "received status code 404")
+}
+
+func TestIsScaleDownVerified(t *testing.T) {
+	ro := newCanaryRollout("foo", 2, nil, nil, nil, intstr.FromInt(1), intstr.FromInt(1))
+	ro.Spec.Strategy.Canary.ScaleDownVerification = &v1alpha1.ScaleDownVerification{
+		DrainProbe: &v1alpha1.DrainProbe{Port: 8080},
+	}
+	rs := newReplicaSetWithStatus(ro, 2, 2)
+	rs.Annotations[v1alpha1.DefaultReplicaSetScaleDownDeadlineAnnotationKey] = metav1.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	newPod := func(name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       rs.Namespace,
+				Labels:          rs.Spec.Selector.MatchLabels,
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(rs, appsv1.SchemeGroupVersion.WithKind("ReplicaSet"))},
+			},
+			Status: corev1.PodStatus{PodIP: "10.0.0.1"},
+		}
+	}
+	newContext := func(ro *v1alpha1.Rollout) (*rolloutContext, chan struct{}) {
+		podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+		require.NoError(t, podIndexer.Add(newPod("pod-a")))
+		require.NoError(t, podIndexer.Add(newPod("pod-b")))
+		probed := make(chan struct{}, 1)
+		return &rolloutContext{
+			rollout: ro,
+			log:     logutil.WithRollout(ro),
+			reconcilerBase: reconcilerBase{
+				podLister:           corelisters.NewPodLister(podIndexer),
+				recorder:            record.NewFakeEventRecorder(),
+				drainProbes:         newDrainProbes(),
+				enqueueRollout:      func(obj any) { probed <- struct{}{} },
+				enqueueRolloutAfter: func(obj any, duration time.Duration) {},
+			},
+		}, probed
+	}
+	stubProbe := func(t *testing.T, connections map[string]int) {
+		original := probeActiveConnections
+		t.Cleanup(func() { probeActiveConnections = original })
+		probeActiveConnections = func(ctx context.Context, pod *corev1.Pod, probe *v1alpha1.DrainProbe) (int, error) {
+			if n, ok := connections[pod.Name]; ok {
+				return n, nil
+			}
+			return 0, fmt.Errorf("connection refused")
+		}
+	}
+	// verifyAfterProbe starts the drain probe in the background and returns the verification once it completed
+	verifyAfterProbe := func(t *testing.T, ctx *rolloutContext, probed chan struct{}) bool {
+		require.False(t, ctx.isScaleDownVerified(rs))
+		select {
+		case <-probed:
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "drain probe did not complete")
+		}
+		return ctx.isScaleDownVerified(rs)
+	}
+
+	t.Run("Verified once pods are drained", func(t *testing.T) {
+		stubProbe(t, map[string]int{"pod-a": 0, "pod-b": 0})
+		ctx, probed := newContext(ro)
+		assert.True(t, verifyAfterProbe(t, ctx, probed))
+	})
+
+	t.Run("Waits for active connections", func(t *testing.T) {
+		stubProbe(t, map[string]int{"pod-a": 0, "pod-b": 2})
+		ctx, probed := newContext(ro)
+		assert.False(t, verifyAfterProbe(t, ctx, probed))
+	})
+
+	t.Run("Waits for failed probes", func(t *testing.T) {
+		stubProbe(t, map[string]int{"pod-a": 0})
+		ctx, probed := newContext(ro)
+		assert.False(t, verifyAfterProbe(t, ctx, probed))
+	})
+
+	t.Run("Probes in the background", func(t *testing.T) {
+		release := make(chan struct{})
+		original := probeActiveConnections
+		t.Cleanup(func() { probeActiveConnections = original })
+		probeActiveConnections = func(ctx context.Context, pod *corev1.Pod, probe *v1alpha1.DrainProbe) (int, error) {
+			<-release
+			return 0, nil
+		}
+		ctx, probed := newContext(ro)
+		assert.False(t, ctx.isScaleDownVerified(rs))
+		assert.False(t, ctx.isScaleDownVerified(rs))
+		close(release)
+		<-probed
+		assert.True(t, ctx.isScaleDownVerified(rs))
+		assert.Empty(t, probed)
+	})
+
+	t.Run("Waits for weight verification", func(t *testing.T) {
+		stubProbe(t, map[string]int{"pod-a": 0, "pod-b": 0})
+		ctx, probed := newContext(ro)
+		ctx.newStatus.Canary.Weights = &v1alpha1.TrafficWeights{Verified: ptr.To(false)}
+		assert.False(t, ctx.isScaleDownVerified(rs))
+		ctx.newStatus.Canary.Weights.Verified = ptr.To(true)
+		assert.True(t, verifyAfterProbe(t, ctx, probed))
+	})
+
+	t.Run("Scales down after timeout", func(t *testing.T) {
+		stubProbe(t, map[string]int{"pod-a": 5, "pod-b": 5})
+		timedOut := ro.DeepCopy()
+		timedOut.Spec.Strategy.Canary.ScaleDownVerification.TimeoutSeconds = ptr.To[int32](30)
+		ctx, _ := newContext(timedOut)
+		recorder := ctx.recorder.(*record.FakeEventRecorder)
+		assert.True(t, ctx.isScaleDownVerified(rs))
+		assert.True(t, ctx.isScaleDownVerified(rs))
+		assert.Equal(t, []string{"ScaleDownVerificationTimedOut"}, recorder.Events())
+	})
+
+	t.Run("Skips ReplicaSets without scale down deadline", func(t *testing.T) {
+		stubProbe(t, map[string]int{"pod-a": 5, "pod-b": 5})
+		ctx, _ := newContext(ro)
+		rs := rs.DeepCopy()
+		delete(rs.Annotations, v1alpha1.DefaultReplicaSetScaleDownDeadlineAnnotationKey)
+		assert.True(t, ctx.isScaleDownVerified(rs))
+	})
+}
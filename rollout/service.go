@@ -3,14 +3,18 @@ package rollout
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	patchtypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
 	"k8s.io/utils/ptr"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/validation"
 	"github.com/argoproj/argo-rollouts/rollout/trafficrouting"
 	"github.com/argoproj/argo-rollouts/utils/annotations"
 	"github.com/argoproj/argo-rollouts/utils/aws"
@@ -21,35 +25,29 @@ import (
 	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
 	rolloututils "github.com/argoproj/argo-rollouts/utils/rollout"
 	serviceutil "github.com/argoproj/argo-rollouts/utils/service"
+	"github.com/argoproj/argo-rollouts/utils/sharding"
 )
 
-const (
-	switchSelectorPatch = `{
-	"spec": {
-		"selector": {
-			"` + v1alpha1.DefaultRolloutUniqueLabelKey + `": "%s"
-		}
-	}
-}`
-	switchSelectorAndAddManagedByPatch = `{
-	"metadata": {
-		"annotations": {
-			"` + v1alpha1.ManagedByRolloutsKey + `": "%s"
-		}
-	},
-	"spec": {
-		"selector": {
-			"` + v1alpha1.DefaultRolloutUniqueLabelKey + `": "%s"
-		}
-	}
-}`
-)
-
-func generatePatch(service *corev1.Service, newRolloutUniqueLabelValue string, r *v1alpha1.Rollout) string {
-	if _, ok := service.Annotations[v1alpha1.ManagedByRolloutsKey]; !ok {
-		return fmt.Sprintf(switchSelectorAndAddManagedByPatch, r.Name, newRolloutUniqueLabelValue)
-	}
-	return fmt.Sprintf(switchSelectorPatch, newRolloutUniqueLabelValue)
+// generateSwitchSelectorApplyConfiguration returns the configuration to server-side apply the new selector to the
+// service. The selector of a service is atomic, so the complete selector including the rollout unique label is applied.
+func generateSwitchSelectorApplyConfiguration(service *corev1.Service, newRolloutUniqueLabelValue string, r *v1alpha1.Rollout) *corev1ac.ServiceApplyConfiguration {
+	selector := make(map[string]string, len(service.Spec.Selector)+1)
+	for k, v := range service.Spec.Selector {
+		selector[k] = v
+	}
+	selector[v1alpha1.DefaultRolloutUniqueLabelKey] = newRolloutUniqueLabelValue
+	managedBy, ok := service.Annotations[v1alpha1.ManagedByRolloutsKey]
+	if !ok {
+		managedBy = r.Name
+	}
+	svcApplyConfig := corev1ac.Service(service.Name, service.Namespace).
+		WithAnnotations(map[string]string{v1alpha1.ManagedByRolloutsKey: managedBy}).
+		WithSpec(corev1ac.ServiceSpec().WithSelector(selector))
+	// keep the service on the shard of an explicitly sharded rollout
+	if shard, ok := r.Labels[v1alpha1.LabelKeyControllerShard]; ok {
+		svcApplyConfig = svcApplyConfig.WithLabels(map[string]string{v1alpha1.LabelKeyControllerShard: shard})
+	}
+	return svcApplyConfig
 }
 
 // switchSelector switch the selector on an existing service to a new value
@@ -63,8 +61,14 @@ func (c rolloutContext) switchServiceSelector(service *corev1.Service, newRollou
 	if ok && oldPodHash == newRolloutUniqueLabelValue && hasManagedRollout {
 		return nil
 	}
-	patch := generatePatch(service, newRolloutUniqueLabelValue, r)
-	_, err := c.kubeclientset.CoreV1().Services(service.Namespace).Patch(ctx, service.Name, patchtypes.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{})
+	// The selector is atomic and force applied, so it is built from a fresh read of the service instead of the
+	// informer cache. Otherwise a selector change which the cache has not observed yet would be reverted.
+	liveService, err := c.kubeclientset.CoreV1().Services(service.Namespace).Get(ctx, service.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	applyConfig := generateSwitchSelectorApplyConfiguration(liveService, newRolloutUniqueLabelValue, r)
+	_, err = c.kubeclientset.CoreV1().Services(service.Namespace).Apply(ctx, applyConfig, metav1.ApplyOptions{FieldManager: defaults.DefaultFieldManager, Force: true})
 	if err != nil {
 		return err
 	}
@@ -113,6 +117,15 @@ func (c *rolloutContext) reconcileActiveService(activeSvc *corev1.Service) error
 		newPodHash = c.rollout.Status.StableRS
 	}
 
+	// switching the active service from the stable ReplicaSet to the new one promotes the rollout, which is subject
+	// to the progression policies. The initial deploy and a rollback are not.
+	activePodHash, hasActivePodHash := activeSvc.Spec.Selector[v1alpha1.DefaultRolloutUniqueLabelKey]
+	if hasActivePodHash && newPodHash != activePodHash && newPodHash != c.rollout.Status.StableRS &&
+		!c.isRollbackWithinWindow() && !c.fullPromotionAllowed() {
+		c.log.Infof("Skipping active service switch: %s", c.promotionDenial)
+		newPodHash = activePodHash
+	}
+
 	err := c.switchServiceSelector(activeSvc, newPodHash, c.rollout)
 	if err != nil {
 		return err
@@ -296,7 +309,7 @@ func (c *rolloutContext) ensureSVCTargets(svcName string, rs *appsv1.ReplicaSet,
 	if rs == nil || svcName == "" {
 		return nil
 	}
-	svc, err := c.servicesLister.Services(c.rollout.Namespace).Get(svcName)
+	svc, err := c.getService(svcName)
 	if err != nil {
 		return err
 	}
@@ -334,3 +347,121 @@ func (c *rolloutContext) ensureSVCTargets(svcName string, rs *appsv1.ReplicaSet,
 	}
 	return nil
 }
+
+// getService returns the service with the given name from the informer cache, falling back to the
+// services created during this reconciliation which may not be in the cache yet.
+func (c *rolloutContext) getService(svcName string) (*corev1.Service, error) {
+	svc, err := c.servicesLister.Services(c.rollout.Namespace).Get(svcName)
+	if k8serrors.IsNotFound(err) {
+		if createdSvc, ok := c.createdServices[svcName]; ok {
+			return createdSvc, nil
+		}
+	}
+	return svc, err
+}
+
+// reconcileCreatedServices creates the stable and canary services which do not exist yet when the
+// rollout asks the controller to create them (see canary.createServices)
+func (c *rolloutContext) reconcileCreatedServices() error {
+	canary := c.rollout.Spec.Strategy.Canary
+	if canary == nil || !canary.CreateServices {
+		return nil
+	}
+	for _, svcName := range []string{canary.StableService, canary.CanaryService} {
+		if svcName == "" {
+			continue
+		}
+		_, err := c.getService(svcName)
+		if k8serrors.IsNotFound(err) {
+			_, err = c.createService(svcName)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// shouldCreateService returns whether the controller creates the given service type when it does not exist
+func (c *rolloutContext) shouldCreateService(serviceType validation.ServiceType) bool {
+	canary := c.rollout.Spec.Strategy.Canary
+	if canary == nil || !canary.CreateServices {
+		return false
+	}
+	return serviceType == validation.StableService || serviceType == validation.CanaryService
+}
+
+// createService creates a service owned by the rollout which selects the rollout's pods and exposes
+// the container ports of the pod template. The rollouts-pod-template-hash selector is added later
+// when the service is pointed at a ReplicaSet.
+func (c *rolloutContext) createService(svcName string) (*corev1.Service, error) {
+	ctx := context.TODO()
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      svcName,
+			Namespace: c.rollout.Namespace,
+			Annotations: map[string]string{
+				v1alpha1.ManagedByRolloutsKey: c.rollout.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(c.rollout, controllerKind)},
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: make(map[string]string),
+			Ports:    newServicePorts(c.rollout.Spec.Template),
+		},
+	}
+	for k, v := range c.rollout.Spec.Selector.MatchLabels {
+		svc.Spec.Selector[k] = v
+	}
+	sharding.PropagateShard(c.rollout, svc)
+
+	createdSvc, err := c.kubeclientset.CoreV1().Services(c.rollout.Namespace).Create(ctx, svc, metav1.CreateOptions{FieldManager: defaults.DefaultFieldManager})
+	if k8serrors.IsAlreadyExists(err) {
+		// the informer cache has not yet observed the service
+		return c.kubeclientset.CoreV1().Services(c.rollout.Namespace).Get(ctx, svcName, metav1.GetOptions{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.log.WithField(logutil.ServiceKey, svcName).Info("created service")
+	c.recorder.Eventf(c.rollout, record.EventOptions{EventReason: "ServiceCreated"}, "Created service '%s'", svcName)
+	if c.createdServices == nil {
+		c.createdServices = make(map[string]*corev1.Service)
+	}
+	c.createdServices[svcName] = createdSvc
+	return createdSvc, nil
+}
+
+// newServicePorts returns a service port for every distinct container port in the pod template.
+// Unnamed container ports are named after their protocol and port number.
+func newServicePorts(template corev1.PodTemplateSpec) []corev1.ServicePort {
+	var ports []corev1.ServicePort
+	seen := make(map[string]bool)
+	for _, container := range template.Spec.Containers {
+		for _, containerPort := range container.Ports {
+			protocol := containerPort.Protocol
+			if protocol == "" {
+				protocol = corev1.ProtocolTCP
+			}
+			name := containerPort.Name
+			targetPort := intstr.FromString(containerPort.Name)
+			if name == "" {
+				name = fmt.Sprintf("%s-%d", strings.ToLower(string(protocol)), containerPort.ContainerPort)
+				targetPort = intstr.FromInt32(containerPort.ContainerPort)
+			}
+			portKey := fmt.Sprintf("%s/%d", protocol, containerPort.ContainerPort)
+			if seen[name] || seen[portKey] {
+				continue
+			}
+			seen[name] = true
+			seen[portKey] = true
+			ports = append(ports, corev1.ServicePort{
+				Name:       name,
+				Protocol:   protocol,
+				Port:       containerPort.ContainerPort,
+				TargetPort: targetPort,
+			})
+		}
+	}
+	return ports
+}
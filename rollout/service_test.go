@@ -1,6 +1,7 @@
 package rollout
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -11,11 +12,16 @@ import (
 	elbv2types "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	core "k8s.io/client-go/testing"
 	"k8s.io/utils/ptr"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
@@ -24,6 +30,9 @@ import (
 	"github.com/argoproj/argo-rollouts/utils/conditions"
 	"github.com/argoproj/argo-rollouts/utils/defaults"
 	ingressutil "github.com/argoproj/argo-rollouts/utils/ingress"
+	logutil "github.com/argoproj/argo-rollouts/utils/log"
+	"github.com/argoproj/argo-rollouts/utils/record"
+	rolloututil "github.com/argoproj/argo-rollouts/utils/rollout"
 	timeutil "github.com/argoproj/argo-rollouts/utils/time"
 	unstructuredutil "github.com/argoproj/argo-rollouts/utils/unstructured"
 )
@@ -105,6 +114,48 @@ func TestGetPreviewAndActiveServices(t *testing.T) {
 	})
 }
 
+func TestGenerateSwitchSelectorApplyConfiguration(t *testing.T) {
+	ro := newCanaryRollout("foo", 1, nil, nil, nil, intstr.FromInt(0), intstr.FromInt(1))
+	svc := newService("active", 80, map[string]string{"app": "foo", v1alpha1.DefaultRolloutUniqueLabelKey: "abc123"}, nil)
+
+	applyConfig := generateSwitchSelectorApplyConfiguration(svc, "def456", ro)
+	assert.Equal(t, "active", *applyConfig.Name)
+	assert.Equal(t, map[string]string{"app": "foo", v1alpha1.DefaultRolloutUniqueLabelKey: "def456"}, applyConfig.Spec.Selector)
+	assert.Equal(t, map[string]string{v1alpha1.ManagedByRolloutsKey: "foo"}, applyConfig.Annotations)
+	assert.Equal(t, "abc123", svc.Spec.Selector[v1alpha1.DefaultRolloutUniqueLabelKey])
+
+	svc.Annotations = map[string]string{v1alpha1.ManagedByRolloutsKey: "bar"}
+	applyConfig = generateSwitchSelectorApplyConfiguration(svc, "def456", ro)
+	assert.Equal(t, map[string]string{v1alpha1.ManagedByRolloutsKey: "bar"}, applyConfig.Annotations)
+}
+
+func TestSwitchServiceSelectorAppliesFromLiveService(t *testing.T) {
+	ro := newCanaryRollout("foo", 1, nil, nil, nil, intstr.FromInt(0), intstr.FromInt(1))
+	cachedSvc := newService("active", 80, map[string]string{"app": "foo", v1alpha1.DefaultRolloutUniqueLabelKey: "abc123"}, ro)
+	liveSvc := cachedSvc.DeepCopy()
+	liveSvc.Spec.Selector["tier"] = "web"
+	client := k8sfake.NewSimpleClientset(liveSvc)
+	ctx := &rolloutContext{
+		rollout: ro,
+		log:     logutil.WithRollout(ro),
+		reconcilerBase: reconcilerBase{
+			kubeclientset: client,
+			recorder:      record.NewFakeEventRecorder(),
+		},
+	}
+
+	err := ctx.switchServiceSelector(cachedSvc, "def456", ro)
+	require.NoError(t, err)
+	actions := client.Actions()
+	require.Len(t, actions, 2)
+	assert.True(t, actions[0].Matches("get", "services"))
+	patch, ok := actions[1].(core.PatchAction)
+	require.True(t, ok)
+	assert.Equal(t, types.ApplyPatchType, patch.GetPatchType())
+	assert.Contains(t, string(patch.GetPatch()), `"tier":"web"`)
+	assert.Contains(t, string(patch.GetPatch()), `"rollouts-pod-template-hash":"def456"`)
+}
+
 func TestActiveServiceNotFound(t *testing.T) {
 	f := newFixture(t)
 	defer f.Close()
@@ -308,7 +359,7 @@ func TestBlueGreenAWSVerifyTargetGroupsNotYetReady(t *testing.T) {
 	f.run(getKey(r2, t))
 
 	patch := f.getPatchedRollout(patchIndex)
-	expectedPatch := `{"status":{"message":"waiting for post-promotion verification to complete"}}`
+	expectedPatch := `{"status":{"health":{"percentComplete":99,"reason":"WaitingForVerification","status":"Progressing"},"message":"waiting for post-promotion verification to complete"}}`
 	assert.Equal(t, expectedPatch, patch)
 	f.assertEvents([]string{
 		conditions.TargetGroupUnverifiedReason,
@@ -393,7 +444,7 @@ func TestBlueGreenAWSVerifyTargetGroupsReady(t *testing.T) {
 	f.run(getKey(r2, t))
 
 	patch := f.getPatchedRollout(patchIndex)
-	expectedPatch := fmt.Sprintf(`{"status":{"message":null,"phase":"Healthy","stableRS":"%s"}}`, rs2PodHash)
+	expectedPatch := fmt.Sprintf(`{"status":{"health":{"percentComplete":100,"status":"Healthy"},"message":null,"phase":"Healthy","stableRS":"%s"}}`, rs2PodHash)
 	assert.Equal(t, expectedPatch, patch)
 	f.assertEvents([]string{
 		conditions.TargetGroupVerifiedReason,
@@ -488,6 +539,7 @@ func TestCanaryAWSVerifyTargetGroupsNotYetReady(t *testing.T) {
 	conditions.SetRolloutCondition(&r2.Status, completedCondition)
 	_, r2.Status.Canary.Weights = calculateWeightStatus(r2, rs2PodHash, rs2PodHash, 0)
 
+	r2.Status.Health = rolloututil.CalculateRolloutHealth(r2.Spec, r2.Status)
 	f.rolloutLister = append(f.rolloutLister, r2)
 	f.objects = append(f.objects, r2, tgb)
 	f.kubeobjects = append(f.kubeobjects, rs1, rs2, ing, rootSvc, canarySvc, stableSvc, ep)
@@ -587,6 +639,7 @@ func TestCanaryAWSVerifyTargetGroupsReady(t *testing.T) {
 	conditions.SetRolloutCondition(&r2.Status, completedCondition)
 	_, r2.Status.Canary.Weights = calculateWeightStatus(r2, rs2PodHash, rs2PodHash, 0)
 
+	r2.Status.Health = rolloututil.CalculateRolloutHealth(r2.Spec, r2.Status)
 	f.rolloutLister = append(f.rolloutLister, r2)
 	f.objects = append(f.objects, r2, tgb)
 	f.kubeobjects = append(f.kubeobjects, rs1, rs2, ing, rootSvc, canarySvc, stableSvc, ep)
@@ -652,6 +705,7 @@ func TestCanaryAWSVerifyTargetGroupsSkip(t *testing.T) {
 	conditions.SetRolloutCondition(&r2.Status, completedCondition)
 	_, r2.Status.Canary.Weights = calculateWeightStatus(r2, rs2PodHash, rs2PodHash, 0)
 
+	r2.Status.Health = rolloututil.CalculateRolloutHealth(r2.Spec, r2.Status)
 	f.rolloutLister = append(f.rolloutLister, r2)
 	f.objects = append(f.objects, r2)
 	f.kubeobjects = append(f.kubeobjects, rs1, rs2, ing, rootSvc, canarySvc, stableSvc)
@@ -856,3 +910,48 @@ func TestDelayCanaryStableServiceDelayOnAdoptedService(t *testing.T) {
 	})
 
 }
+
+func TestCreateCanaryStableServices(t *testing.T) {
+	ro := newCanaryRollout("foo", 3, nil, nil, nil, intstr.FromInt(1), intstr.FromInt(1))
+	ro.Spec.Strategy.Canary.CanaryService = "canary"
+	ro.Spec.Strategy.Canary.StableService = "stable"
+	ro.Spec.Strategy.Canary.CreateServices = true
+	ro.Spec.Template.Spec.Containers[0].Ports = []corev1.ContainerPort{
+		{Name: "http", ContainerPort: 8080},
+		{ContainerPort: 9090, Protocol: corev1.ProtocolUDP},
+	}
+
+	f := newFixture(t)
+	defer f.Close()
+	f.objects = append(f.objects, ro)
+	f.rolloutLister = append(f.rolloutLister, ro)
+
+	ctrl, _, _ := f.newController(noResyncPeriodFunc)
+	roCtx, err := ctrl.newRolloutContext(ro)
+	require.NoError(t, err)
+	// the services are not created while the rollout is validated
+	_, err = ctrl.kubeclientset.CoreV1().Services(ro.Namespace).Get(context.TODO(), "stable", metav1.GetOptions{})
+	assert.True(t, k8serrors.IsNotFound(err))
+
+	require.NoError(t, roCtx.reconcileCreatedServices())
+	for _, svcName := range []string{"canary", "stable"} {
+		svc, err := ctrl.kubeclientset.CoreV1().Services(ro.Namespace).Get(context.TODO(), svcName, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, ro.Name, svc.Annotations[v1alpha1.ManagedByRolloutsKey])
+		assert.True(t, metav1.IsControlledBy(svc, ro))
+		assert.Equal(t, ro.Spec.Selector.MatchLabels, svc.Spec.Selector)
+		assert.Equal(t, []corev1.ServicePort{
+			{Name: "http", Protocol: corev1.ProtocolTCP, Port: 8080, TargetPort: intstr.FromString("http")},
+			{Name: "udp-9090", Protocol: corev1.ProtocolUDP, Port: 9090, TargetPort: intstr.FromInt32(9090)},
+		}, svc.Spec.Ports)
+		assert.Contains(t, roCtx.createdServices, svcName)
+	}
+
+	// the created services are used before they are observed by the informer
+	roCtx.newRS = newReplicaSetWithStatus(ro, 3, 3)
+	roCtx.stableRS = roCtx.newRS
+	require.NoError(t, roCtx.reconcileStableAndCanaryService())
+	stableSvc, err := ctrl.kubeclientset.CoreV1().Services(ro.Namespace).Get(context.TODO(), "stable", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, roCtx.newRS.Labels[v1alpha1.DefaultRolloutUniqueLabelKey], stableSvc.Spec.Selector[v1alpha1.DefaultRolloutUniqueLabelKey])
+}
@@ -0,0 +1,100 @@
+package rollout
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+// statusCoalescer remembers when the status of each rollout was last patched, so that rapid successive status
+// updates which only change the replica counts of a rollout (e.g. while its pods become ready one by one) can be
+// coalesced into a single patch. A nil statusCoalescer never defers patches.
+type statusCoalescer struct {
+	lock        sync.Mutex
+	lastPatched map[string]time.Time
+}
+
+func newStatusCoalescer() *statusCoalescer {
+	return &statusCoalescer{
+		lastPatched: map[string]time.Time{},
+	}
+}
+
+// Delay returns how long the patch of the status of the rollout should be deferred, or zero if the status should
+// be patched right away. Only patches within the window after the last patch of the rollout, which change nothing
+// but the replica counts, are deferred.
+func (s *statusCoalescer) Delay(key string, window time.Duration, prevStatus, newStatus *v1alpha1.RolloutStatus, now time.Time) time.Duration {
+	if s == nil || window <= 0 || !onlyReplicaCountsChanged(prevStatus, newStatus) {
+		return 0
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	lastPatched, ok := s.lastPatched[key]
+	if !ok {
+		return 0
+	}
+	if delay := lastPatched.Add(window).Sub(now); delay > 0 {
+		return delay
+	}
+	return 0
+}
+
+// Patched records that the status of the rollout was patched
+func (s *statusCoalescer) Patched(key string, now time.Time) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.lastPatched[key] = now
+}
+
+// Forget forgets the rollout, e.g. once it is deleted
+func (s *statusCoalescer) Forget(key string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.lastPatched, key)
+}
+
+// onlyReplicaCountsChanged returns whether the statuses only differ in the replica counts, and the messages and
+// the update times of the conditions which accompany them
+func onlyReplicaCountsChanged(prevStatus, newStatus *v1alpha1.RolloutStatus) bool {
+	prev := prevStatus.DeepCopy()
+	curr := newStatus.DeepCopy()
+	for _, status := range []*v1alpha1.RolloutStatus{prev, curr} {
+		status.Replicas = 0
+		status.UpdatedReplicas = 0
+		status.ReadyReplicas = 0
+		status.AvailableReplicas = 0
+		status.HPAReplicas = 0
+		status.Canary.StableReplicas = 0
+		status.Canary.CanaryReplicas = 0
+		status.Message = ""
+		if status.Health != nil {
+			status.Health.PercentComplete = 0
+		}
+		for i := range status.Conditions {
+			status.Conditions[i].LastUpdateTime = metav1.Time{}
+			status.Conditions[i].Message = ""
+		}
+	}
+	return statusSemanticallyEqual(prev, curr)
+}
+
+// statusSemanticallyEqual returns whether the statuses are semantically equal, regardless of the order of
+// their conditions
+func statusSemanticallyEqual(prevStatus, newStatus *v1alpha1.RolloutStatus) bool {
+	prev := prevStatus.DeepCopy()
+	curr := newStatus.DeepCopy()
+	for _, status := range []*v1alpha1.RolloutStatus{prev, curr} {
+		sort.SliceStable(status.Conditions, func(i, j int) bool {
+			return status.Conditions[i].Type < status.Conditions[j].Type
+		})
+	}
+	return equality.Semantic.DeepEqual(prev, curr)
+}
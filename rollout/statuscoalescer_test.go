@@ -0,0 +1,85 @@
+package rollout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+func newCoalescerStatus() *v1alpha1.RolloutStatus {
+	return &v1alpha1.RolloutStatus{
+		CurrentPodHash:    "abc",
+		CurrentStepIndex:  ptr.To[int32](1),
+		Replicas:          5,
+		UpdatedReplicas:   2,
+		AvailableReplicas: 3,
+		Phase:             v1alpha1.RolloutPhaseProgressing,
+		Conditions: []v1alpha1.RolloutCondition{
+			{Type: v1alpha1.RolloutProgressing, Status: corev1.ConditionTrue, Reason: "ReplicaSetUpdated", LastUpdateTime: metav1.NewTime(time.Unix(0, 0))},
+			{Type: v1alpha1.RolloutAvailable, Status: corev1.ConditionFalse, Reason: "AvailableReason"},
+		},
+	}
+}
+
+func TestStatusSemanticallyEqual(t *testing.T) {
+	prev := newCoalescerStatus()
+	curr := prev.DeepCopy()
+	curr.Conditions[0], curr.Conditions[1] = curr.Conditions[1], curr.Conditions[0]
+	assert.True(t, statusSemanticallyEqual(prev, curr))
+
+	curr.AvailableReplicas = 4
+	assert.False(t, statusSemanticallyEqual(prev, curr))
+}
+
+func TestOnlyReplicaCountsChanged(t *testing.T) {
+	prev := newCoalescerStatus()
+	curr := prev.DeepCopy()
+	curr.AvailableReplicas = 4
+	curr.Canary.CanaryReplicas = 2
+	curr.Conditions[0].LastUpdateTime = metav1.NewTime(time.Unix(10, 0))
+	curr.Conditions[0].Message = "progressing"
+	assert.True(t, onlyReplicaCountsChanged(prev, curr))
+
+	curr.CurrentStepIndex = ptr.To[int32](2)
+	assert.False(t, onlyReplicaCountsChanged(prev, curr))
+
+	curr = prev.DeepCopy()
+	curr.Phase = v1alpha1.RolloutPhaseHealthy
+	assert.False(t, onlyReplicaCountsChanged(prev, curr))
+}
+
+func TestStatusCoalescerDelay(t *testing.T) {
+	prev := newCoalescerStatus()
+	curr := prev.DeepCopy()
+	curr.AvailableReplicas = 4
+	now := time.Now()
+
+	var nilCoalescer *statusCoalescer
+	assert.Equal(t, time.Duration(0), nilCoalescer.Delay("default/foo", time.Second, prev, curr, now))
+	nilCoalescer.Patched("default/foo", now)
+
+	s := newStatusCoalescer()
+	// the rollout was not patched before
+	assert.Equal(t, time.Duration(0), s.Delay("default/foo", time.Second, prev, curr, now))
+
+	s.Patched("default/foo", now)
+	assert.Equal(t, 600*time.Millisecond, s.Delay("default/foo", time.Second, prev, curr, now.Add(400*time.Millisecond)))
+	// the window elapsed
+	assert.Equal(t, time.Duration(0), s.Delay("default/foo", time.Second, prev, curr, now.Add(time.Second)))
+	// coalescing is disabled
+	assert.Equal(t, time.Duration(0), s.Delay("default/foo", 0, prev, curr, now.Add(400*time.Millisecond)))
+
+	// other changes are patched right away
+	curr.Phase = v1alpha1.RolloutPhaseHealthy
+	assert.Equal(t, time.Duration(0), s.Delay("default/foo", time.Second, prev, curr, now.Add(400*time.Millisecond)))
+
+	s.Forget("default/foo")
+	curr.Phase = prev.Phase
+	assert.Equal(t, time.Duration(0), s.Delay("default/foo", time.Second, prev, curr, now.Add(400*time.Millisecond)))
+}
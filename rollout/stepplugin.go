@@ -129,6 +129,10 @@ func (spc *stepPluginContext) reconcile(c *rolloutContext) error {
 
 		// Get the remaining time until the backoff + a little buffer
 		remaining := time.Until(status.UpdatedAt.Add(backoff)) + defaultBackoffDelay
+		if interval := stepPlugin.ProgressInterval(); status.Phase == v1alpha1.StepPluginPhaseRunning && interval > 0 && interval < remaining {
+			// refresh the progress of the plugin before it runs again
+			remaining = interval
+		}
 		c.log.Debugf("queueing up rollout in %s because step plugin phase is %s", remaining, status.Phase)
 		c.enqueueRolloutAfter(rollout, remaining)
 		return nil
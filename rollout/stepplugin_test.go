@@ -176,10 +176,12 @@ func Test_stepPluginContext_reconcile_SuccessfulReconciliation(t *testing.T) {
 }
 
 func Test_stepPluginContext_reconcile_RunningReconciliation(t *testing.T) {
+	var progressInterval time.Duration
 	setup := func(t *testing.T, phase v1alpha1.StepPluginPhase, backoff *time.Duration) (*rolloutContext, *v1alpha1.StepPluginStatus) {
 		stepPluginResolver := mocks.NewResolver(t)
 		stepPluginMock := mocks.NewStepPlugin(t)
 		stepPluginResolver.On("Resolve", int32(0), mock.Anything, mock.Anything).Return(stepPluginMock, nil)
+		stepPluginMock.On("ProgressInterval").Return(func() time.Duration { return progressInterval }).Maybe()
 
 		r := newStepPluginRollout()
 		logCtx := logutil.WithRollout(r)
@@ -252,6 +254,22 @@ func Test_stepPluginContext_reconcile_RunningReconciliation(t *testing.T) {
 		assert.GreaterOrEqual(t, requeuedAfter, expectedRequeueAfter)
 		assert.LessOrEqual(t, requeuedAfter, expectedRequeueAfter+defaultBackoffDelay)
 	})
+	t.Run("Rollout is added to the queue to refresh the progress", func(t *testing.T) {
+		progressInterval = 10 * time.Second
+		defer func() { progressInterval = 0 }()
+		backoff := 123 * time.Second
+		roCtx, _ := setup(t, v1alpha1.StepPluginPhaseRunning, &backoff)
+
+		var requeuedAfter time.Duration
+		roCtx.enqueueRolloutAfter = func(obj any, duration time.Duration) {
+			requeuedAfter = duration
+		}
+
+		err := roCtx.stepPluginContext.reconcile(roCtx)
+
+		require.NoError(t, err)
+		assert.Equal(t, progressInterval, requeuedAfter)
+	})
 }
 
 func Test_stepPluginContext_reconcile_FailedReconciliation(t *testing.T) {
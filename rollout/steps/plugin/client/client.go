@@ -2,7 +2,6 @@ package client
 
 import (
 	"fmt"
-	"os/exec"
 	"sync"
 
 	goPlugin "github.com/hashicorp/go-plugin"
@@ -14,7 +13,7 @@ import (
 
 type stepPlugin struct {
 	client map[string]*goPlugin.Client
-	plugin map[string]rpc.StepPlugin
+	plugin map[string]rpc.StepPluginClient
 }
 
 var pluginClients *stepPlugin
@@ -32,13 +31,33 @@ var pluginMap = map[string]goPlugin.Plugin{
 	"RpcStepPlugin": &rpc.RpcStepPlugin{},
 }
 
+func init() {
+	plugin.RegisterReloader(types.PluginTypeStep, reloadPlugin)
+}
+
+// reloadPlugin stops the running process of the plugin once its in-flight calls have completed, so that the plugin is
+// started again with its new definition the next time it is used
+func reloadPlugin(pluginName string) {
+	mutex.Lock()
+	if pluginClients == nil || pluginClients.client[pluginName] == nil {
+		mutex.Unlock()
+		return
+	}
+	pluginClient, rpcPlugin := pluginClients.client[pluginName], pluginClients.plugin[pluginName]
+	delete(pluginClients.client, pluginName)
+	delete(pluginClients.plugin, pluginName)
+	mutex.Unlock()
+
+	plugin.StopClient(pluginName, pluginClient, rpcPlugin)
+}
+
 // GetPlugin returns a singleton plugin client for the given plugin. Calling this multiple times
 // returns the same plugin client instance for the plugin name defined in the rollout object.
-func GetPlugin(pluginName string) (rpc.StepPlugin, error) {
+func GetPlugin(pluginName string) (rpc.StepPluginClient, error) {
 	once.Do(func() {
 		pluginClients = &stepPlugin{
 			client: make(map[string]*goPlugin.Client),
-			plugin: make(map[string]rpc.StepPlugin),
+			plugin: make(map[string]rpc.StepPluginClient),
 		}
 	})
 	plugin, err := pluginClients.startPlugin(pluginName)
@@ -48,7 +67,7 @@ func GetPlugin(pluginName string) (rpc.StepPlugin, error) {
 	return plugin, nil
 }
 
-func (t *stepPlugin) startPlugin(pluginName string) (rpc.StepPlugin, error) {
+func (t *stepPlugin) startPlugin(pluginName string) (rpc.StepPluginClient, error) {
 	mutex.Lock()
 	defer mutex.Unlock()
 
@@ -59,14 +78,10 @@ func (t *stepPlugin) startPlugin(pluginName string) (rpc.StepPlugin, error) {
 			return nil, fmt.Errorf("unable to find plugin (%s): %w", pluginName, err)
 		}
 
-		t.client[pluginName] = goPlugin.NewClient(&goPlugin.ClientConfig{
-			HandshakeConfig: handshakeConfig,
-			Plugins:         pluginMap,
-			Cmd:             exec.Command(pluginPath, args...),
-			Managed:         true,
-		})
+		pluginConfig := plugin.GetPlugin(pluginName, types.PluginTypeStep)
+		t.client[pluginName] = plugin.NewClient(pluginPath, args, pluginConfig, handshakeConfig, pluginMap)
 
-		rpcClient, err := t.client[pluginName].Client()
+		rpcClient, err := plugin.StartClient(t.client[pluginName], pluginConfig)
 		if err != nil {
 			return nil, fmt.Errorf("unable to get plugin client (%s): %w", pluginName, err)
 		}
@@ -77,7 +92,7 @@ func (t *stepPlugin) startPlugin(pluginName string) (rpc.StepPlugin, error) {
 			return nil, fmt.Errorf("unable to dispense plugin (%s): %w", pluginName, err)
 		}
 
-		pluginType, ok := plugin.(rpc.StepPlugin)
+		pluginType, ok := plugin.(rpc.StepPluginClient)
 		if !ok {
 			return nil, fmt.Errorf("unexpected type from plugin")
 		}
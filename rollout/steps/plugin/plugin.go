@@ -2,10 +2,16 @@ package plugin
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	"github.com/argoproj/argo-rollouts/rollout/steps/plugin/rpc"
@@ -14,7 +20,9 @@ import (
 )
 
 type stepPlugin struct {
-	rpc    rpc.StepPlugin
+	rpc    rpc.StepPluginClient
+	info   types.RpcStepPluginInfo
+	schema *spec.Schema
 	index  int32
 	name   string
 	config json.RawMessage
@@ -34,12 +42,16 @@ type StepPlugin interface {
 	Terminate(*v1alpha1.Rollout) (*v1alpha1.StepPluginStatus, error)
 	// Abort reverts a completed Run operation and returns a Terminate status if it did
 	Abort(*v1alpha1.Rollout) (*v1alpha1.StepPluginStatus, error)
+	// ProgressInterval returns the interval at which the progress of a running Run operation is refreshed, or 0 if
+	// the plugin does not report its progress between the executions of the operation
+	ProgressInterval() time.Duration
 }
 
 var (
 	minRequeueDuration    = time.Second * 10
 	defaultRequeuDuration = time.Second * 30
 	defaultErrorBackoff   = time.Second * 30
+	progressInterval      = time.Second * 10
 )
 
 func (p *stepPlugin) Run(rollout *v1alpha1.Rollout) (*v1alpha1.StepPluginStatus, error) {
@@ -69,10 +81,22 @@ func (p *stepPlugin) Run(rollout *v1alpha1.Rollout) (*v1alpha1.StepPluginStatus,
 		}
 		if stepStatus.UpdatedAt.Add(backoff).After(metatime.Now()) {
 			p.log.Debug("skipping plugin Run due to backoff")
+			if p.info.Progress && stepStatus.Phase == v1alpha1.StepPluginPhaseRunning {
+				p.refreshProgress(rollout, stepStatus)
+			}
 			return stepStatus, nil
 		}
 	}
 
+	if err := p.validateConfig(); err != nil {
+		finishedAt := metatime.MetaNow()
+		stepStatus.Phase = v1alpha1.StepPluginPhaseFailed
+		stepStatus.Message = fmt.Sprintf("invalid plugin config: %s", err)
+		stepStatus.UpdatedAt = &finishedAt
+		stepStatus.FinishedAt = &finishedAt
+		return stepStatus, nil
+	}
+
 	p.log.Debug("calling RPC Run")
 	resp, err := p.rpc.Run(rollout.DeepCopy(), p.getStepContext(stepStatus))
 	finishedAt := metatime.MetaNow()
@@ -102,6 +126,11 @@ func (p *stepPlugin) Run(rollout *v1alpha1.Rollout) (*v1alpha1.StepPluginStatus,
 	if stepStatus.Phase != v1alpha1.StepPluginPhaseError {
 		// do not update status on error because it can be invalid and we want to retry later on current status
 		stepStatus.Status = resp.Status
+		stepStatus.StateVersion = p.info.StateVersion
+	}
+
+	if resp.Progress != nil {
+		stepStatus.Progress = newProgress(*resp.Progress, finishedAt)
 	}
 
 	if stepStatus.Phase == v1alpha1.StepPluginPhaseRunning {
@@ -215,6 +244,55 @@ func (p *stepPlugin) Abort(rollout *v1alpha1.Rollout) (*v1alpha1.StepPluginStatu
 	return abortStatus, nil
 }
 
+func (p *stepPlugin) ProgressInterval() time.Duration {
+	if !p.info.Progress {
+		return 0
+	}
+	return progressInterval
+}
+
+// refreshProgress updates the progress of a running Run operation with the progress reported by the plugin
+func (p *stepPlugin) refreshProgress(rollout *v1alpha1.Rollout, stepStatus *v1alpha1.StepPluginStatus) {
+	p.log.Debug("calling RPC Progress")
+	progress, err := p.rpc.Progress(rollout.DeepCopy(), p.getStepContext(stepStatus))
+	if err.HasError() {
+		// the progress is informative, the operation itself is retried after the backoff
+		p.log.Warnf("failed to get progress of plugin: %s", err.Error())
+		return
+	}
+	stepStatus.Progress = newProgress(progress, metatime.MetaNow())
+}
+
+// validateConfig validates the config of the step with the config schema of the plugin, if any
+func (p *stepPlugin) validateConfig() error {
+	if p.schema == nil {
+		return nil
+	}
+	var config any
+	if len(p.config) > 0 {
+		if err := json.Unmarshal(p.config, &config); err != nil {
+			return err
+		}
+	}
+	result := validate.NewSchemaValidator(p.schema, nil, "config", strfmt.Default).Validate(config)
+	if result.IsValid() {
+		return nil
+	}
+	var messages []string
+	for _, err := range result.Errors {
+		messages = append(messages, err.Error())
+	}
+	return errors.New(strings.Join(messages, ", "))
+}
+
+func newProgress(progress types.RpcStepProgress, updatedAt metav1.Time) *v1alpha1.StepPluginProgress {
+	return &v1alpha1.StepPluginProgress{
+		Percent:   progress.Percent,
+		Stage:     progress.Stage,
+		UpdatedAt: &updatedAt,
+	}
+}
+
 func (p *disabledStepPlugin) Run(_ *v1alpha1.Rollout) (*v1alpha1.StepPluginStatus, error) {
 	return &v1alpha1.StepPluginStatus{
 		Index:     p.index,
@@ -232,6 +310,10 @@ func (p *disabledStepPlugin) Abort(_ *v1alpha1.Rollout) (*v1alpha1.StepPluginSta
 	return nil, nil
 }
 
+func (p *disabledStepPlugin) ProgressInterval() time.Duration {
+	return 0
+}
+
 // getStepStatus returns the existing status for the current operation
 func (p *stepPlugin) getStepStatus(rollout *v1alpha1.Rollout, operation v1alpha1.StepPluginOperation) *v1alpha1.StepPluginStatus {
 	for _, s := range rollout.Status.Canary.StepPluginStatuses {
@@ -245,12 +327,15 @@ func (p *stepPlugin) getStepStatus(rollout *v1alpha1.Rollout, operation v1alpha1
 // getStepContext returns the current step configuration with the from a previous operation, if any
 func (p *stepPlugin) getStepContext(stepStatus *v1alpha1.StepPluginStatus) *types.RpcStepContext {
 	var status json.RawMessage = nil
+	var stateVersion int32
 	if stepStatus != nil {
 		status = stepStatus.Status
+		stateVersion = stepStatus.StateVersion
 	}
 	return &types.RpcStepContext{
-		PluginName: p.name,
-		Config:     p.config,
-		Status:     status,
+		PluginName:   p.name,
+		Config:       p.config,
+		Status:       status,
+		StateVersion: stateVersion,
 	}
 }
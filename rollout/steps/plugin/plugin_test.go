@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kube-openapi/pkg/validation/spec"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	"github.com/argoproj/argo-rollouts/rollout/steps/plugin/rpc/mocks"
@@ -18,14 +19,14 @@ import (
 )
 
 func Test_stepPlugin_Run(t *testing.T) {
-	setup := func(t *testing.T) (*stepPlugin, *mocks.StepPlugin) {
+	setup := func(t *testing.T) (*stepPlugin, *mocks.StepPluginClient) {
 		plugin := &stepPlugin{
 			name:   "test-plugin",
 			index:  0,
 			config: json.RawMessage("value"),
 			log:    log.WithFields(log.Fields{}),
 		}
-		rpcPluginMock := mocks.NewStepPlugin(t)
+		rpcPluginMock := mocks.NewStepPluginClient(t)
 		plugin.rpc = rpcPluginMock
 		return plugin, rpcPluginMock
 	}
@@ -381,15 +382,144 @@ func Test_stepPlugin_Run(t *testing.T) {
 	})
 }
 
+func Test_stepPlugin_RunV2(t *testing.T) {
+	setup := func(t *testing.T, info types.RpcStepPluginInfo, config string) (*stepPlugin, *mocks.StepPluginClient) {
+		plugin := &stepPlugin{
+			name:   "test-plugin",
+			index:  0,
+			info:   info,
+			config: json.RawMessage(config),
+			log:    log.WithFields(log.Fields{}),
+		}
+		if len(info.ConfigSchema) > 0 {
+			plugin.schema = &spec.Schema{}
+			require.NoError(t, json.Unmarshal(info.ConfigSchema, plugin.schema))
+		}
+		rpcPluginMock := mocks.NewStepPluginClient(t)
+		plugin.rpc = rpcPluginMock
+		return plugin, rpcPluginMock
+	}
+	newRollout := func(stepStatus *v1alpha1.StepPluginStatus) *v1alpha1.Rollout {
+		r := &v1alpha1.Rollout{}
+		if stepStatus != nil {
+			r.Status.Canary.StepPluginStatuses = []v1alpha1.StepPluginStatus{*stepStatus}
+		}
+		return r
+	}
+	schema := `{"type":"object","required":["table"],"properties":{"table":{"type":"string"}}}`
+
+	t.Run("State version and progress", func(t *testing.T) {
+		p, rpcMock := setup(t, types.RpcStepPluginInfo{APIVersion: 2, StateVersion: 2}, `{}`)
+		r := newRollout(&v1alpha1.StepPluginStatus{
+			Index:        0,
+			Name:         p.name,
+			Status:       json.RawMessage(`{"migrated":1}`),
+			StateVersion: 1,
+			Phase:        v1alpha1.StepPluginPhaseRunning,
+			Operation:    v1alpha1.StepPluginOperationRun,
+		})
+		rpcResult := types.RpcStepResult{
+			Phase:    types.PhaseRunning,
+			Status:   json.RawMessage(`{"tables":["orders"]}`),
+			Progress: &types.RpcStepProgress{Percent: 40, Stage: "migrating table orders"},
+		}
+		rpcMock.On("Run", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			context := args.Get(1).(*types.RpcStepContext)
+			assert.Equal(t, int32(1), context.StateVersion)
+			assert.Equal(t, json.RawMessage(`{"migrated":1}`), context.Status)
+		}).Return(rpcResult, types.RpcError{}).Once()
+
+		status, err := p.Run(r)
+
+		require.NoError(t, err)
+		assert.Equal(t, v1alpha1.StepPluginPhaseRunning, status.Phase)
+		assert.Equal(t, rpcResult.Status, status.Status)
+		assert.Equal(t, int32(2), status.StateVersion)
+		require.NotNil(t, status.Progress)
+		assert.Equal(t, int32(40), status.Progress.Percent)
+		assert.Equal(t, "migrating table orders", status.Progress.Stage)
+		assert.Equal(t, status.UpdatedAt, status.Progress.UpdatedAt)
+	})
+	t.Run("Valid config", func(t *testing.T) {
+		p, rpcMock := setup(t, types.RpcStepPluginInfo{APIVersion: 2, ConfigSchema: json.RawMessage(schema)}, `{"table":"orders"}`)
+		rpcMock.On("Run", mock.Anything, mock.Anything).Return(types.RpcStepResult{Phase: types.PhaseSuccessful}, types.RpcError{}).Once()
+
+		status, err := p.Run(newRollout(nil))
+
+		require.NoError(t, err)
+		assert.Equal(t, v1alpha1.StepPluginPhaseSuccessful, status.Phase)
+	})
+	t.Run("Invalid config", func(t *testing.T) {
+		p, _ := setup(t, types.RpcStepPluginInfo{APIVersion: 2, ConfigSchema: json.RawMessage(schema)}, `{"table":1}`)
+
+		status, err := p.Run(newRollout(nil))
+
+		require.NoError(t, err)
+		assert.Equal(t, v1alpha1.StepPluginPhaseFailed, status.Phase)
+		assert.Contains(t, status.Message, "invalid plugin config: config.table in body must be of type string")
+		assert.NotNil(t, status.FinishedAt)
+
+		p, _ = setup(t, types.RpcStepPluginInfo{APIVersion: 2, ConfigSchema: json.RawMessage(schema)}, ``)
+		status, err = p.Run(newRollout(nil))
+		require.NoError(t, err)
+		assert.Equal(t, v1alpha1.StepPluginPhaseFailed, status.Phase)
+	})
+	t.Run("Progress is refreshed during the backoff", func(t *testing.T) {
+		p, rpcMock := setup(t, types.RpcStepPluginInfo{APIVersion: 2, Progress: true}, `{}`)
+		assert.Equal(t, progressInterval, p.ProgressInterval())
+		currentStatus := &v1alpha1.StepPluginStatus{
+			Index:      0,
+			Name:       p.name,
+			UpdatedAt:  &v1.Time{Time: time.Now()},
+			Phase:      v1alpha1.StepPluginPhaseRunning,
+			Operation:  v1alpha1.StepPluginOperationRun,
+			Executions: 1,
+			Backoff:    "1m",
+		}
+		rpcMock.On("Progress", mock.Anything, mock.Anything).Return(types.RpcStepProgress{Percent: 50, Stage: "copying"}, types.RpcError{}).Once()
+
+		status, err := p.Run(newRollout(currentStatus))
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), status.Executions)
+		require.NotNil(t, status.Progress)
+		assert.Equal(t, int32(50), status.Progress.Percent)
+		assert.Equal(t, "copying", status.Progress.Stage)
+
+		// errors getting the progress are ignored
+		rpcMock.On("Progress", mock.Anything, mock.Anything).Return(types.RpcStepProgress{}, types.RpcError{ErrorString: "error"}).Once()
+		status, err = p.Run(newRollout(currentStatus))
+		require.NoError(t, err)
+		assert.Nil(t, status.Progress)
+	})
+	t.Run("Progress is not refreshed for plugins which do not report it", func(t *testing.T) {
+		p, _ := setup(t, types.RpcStepPluginInfo{APIVersion: 1}, `{}`)
+		assert.Equal(t, time.Duration(0), p.ProgressInterval())
+
+		status, err := p.Run(newRollout(&v1alpha1.StepPluginStatus{
+			Index:      0,
+			Name:       p.name,
+			UpdatedAt:  &v1.Time{Time: time.Now()},
+			Phase:      v1alpha1.StepPluginPhaseRunning,
+			Operation:  v1alpha1.StepPluginOperationRun,
+			Executions: 1,
+			Backoff:    "1m",
+		}))
+
+		require.NoError(t, err)
+		assert.Nil(t, status.Progress)
+	})
+}
+
 func Test_stepPlugin_Terminate(t *testing.T) {
-	setup := func(t *testing.T) (*stepPlugin, *mocks.StepPlugin) {
+	setup := func(t *testing.T) (*stepPlugin, *mocks.StepPluginClient) {
 		plugin := &stepPlugin{
 			name:   "test-plugin",
 			index:  0,
 			config: json.RawMessage("value"),
 			log:    log.WithFields(log.Fields{}),
 		}
-		rpcPluginMock := mocks.NewStepPlugin(t)
+		rpcPluginMock := mocks.NewStepPluginClient(t)
 		plugin.rpc = rpcPluginMock
 		return plugin, rpcPluginMock
 	}
@@ -531,14 +661,14 @@ func Test_stepPlugin_Terminate(t *testing.T) {
 }
 
 func Test_stepPlugin_Abort(t *testing.T) {
-	setup := func(t *testing.T) (*stepPlugin, *mocks.StepPlugin) {
+	setup := func(t *testing.T) (*stepPlugin, *mocks.StepPluginClient) {
 		plugin := &stepPlugin{
 			name:   "test-plugin",
 			index:  0,
 			config: json.RawMessage("value"),
 			log:    log.WithFields(log.Fields{}),
 		}
-		rpcPluginMock := mocks.NewStepPlugin(t)
+		rpcPluginMock := mocks.NewStepPluginClient(t)
 		plugin.rpc = rpcPluginMock
 		return plugin, rpcPluginMock
 	}
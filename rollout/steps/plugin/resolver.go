@@ -1,10 +1,12 @@
 package plugin
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/sirupsen/logrus"
 	log "github.com/sirupsen/logrus"
+	"k8s.io/kube-openapi/pkg/validation/spec"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	"github.com/argoproj/argo-rollouts/rollout/steps/plugin/client"
@@ -44,8 +46,22 @@ func (r *resolver) Resolve(index int32, plugin v1alpha1.PluginStep, log *log.Ent
 		return nil, fmt.Errorf("failed to get step plugin %s: %w", plugin.Name, err)
 	}
 
+	info, rpcErr := pluginClient.Info()
+	if rpcErr.HasError() {
+		return nil, fmt.Errorf("failed to get info of step plugin %s: %w", plugin.Name, rpcErr)
+	}
+	var schema *spec.Schema
+	if len(info.ConfigSchema) > 0 {
+		schema = &spec.Schema{}
+		if err := json.Unmarshal(info.ConfigSchema, schema); err != nil {
+			return nil, fmt.Errorf("invalid config schema of step plugin %s: %w", plugin.Name, err)
+		}
+	}
+
 	return &stepPlugin{
 		rpc:    pluginClient,
+		info:   info,
+		schema: schema,
 		index:  index,
 		name:   plugin.Name,
 		config: plugin.Config,
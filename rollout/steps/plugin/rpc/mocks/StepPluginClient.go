@@ -0,0 +1,205 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	v1alpha1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	types "github.com/argoproj/argo-rollouts/utils/plugin/types"
+)
+
+// StepPluginClient is an autogenerated mock type for the StepPluginClient type
+type StepPluginClient struct {
+	mock.Mock
+}
+
+// Abort provides a mock function with given fields: _a0, _a1
+func (_m *StepPluginClient) Abort(_a0 *v1alpha1.Rollout, _a1 *types.RpcStepContext) (types.RpcStepResult, types.RpcError) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Abort")
+	}
+
+	var r0 types.RpcStepResult
+	var r1 types.RpcError
+	if rf, ok := ret.Get(0).(func(*v1alpha1.Rollout, *types.RpcStepContext) (types.RpcStepResult, types.RpcError)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(*v1alpha1.Rollout, *types.RpcStepContext) types.RpcStepResult); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(types.RpcStepResult)
+	}
+
+	if rf, ok := ret.Get(1).(func(*v1alpha1.Rollout, *types.RpcStepContext) types.RpcError); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Get(1).(types.RpcError)
+	}
+
+	return r0, r1
+}
+
+// Info provides a mock function with no fields
+func (_m *StepPluginClient) Info() (types.RpcStepPluginInfo, types.RpcError) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Info")
+	}
+
+	var r0 types.RpcStepPluginInfo
+	var r1 types.RpcError
+	if rf, ok := ret.Get(0).(func() (types.RpcStepPluginInfo, types.RpcError)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() types.RpcStepPluginInfo); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(types.RpcStepPluginInfo)
+	}
+
+	if rf, ok := ret.Get(1).(func() types.RpcError); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(types.RpcError)
+	}
+
+	return r0, r1
+}
+
+// InitPlugin provides a mock function with no fields
+func (_m *StepPluginClient) InitPlugin() types.RpcError {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for InitPlugin")
+	}
+
+	var r0 types.RpcError
+	if rf, ok := ret.Get(0).(func() types.RpcError); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(types.RpcError)
+	}
+
+	return r0
+}
+
+// Progress provides a mock function with given fields: _a0, _a1
+func (_m *StepPluginClient) Progress(_a0 *v1alpha1.Rollout, _a1 *types.RpcStepContext) (types.RpcStepProgress, types.RpcError) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Progress")
+	}
+
+	var r0 types.RpcStepProgress
+	var r1 types.RpcError
+	if rf, ok := ret.Get(0).(func(*v1alpha1.Rollout, *types.RpcStepContext) (types.RpcStepProgress, types.RpcError)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(*v1alpha1.Rollout, *types.RpcStepContext) types.RpcStepProgress); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(types.RpcStepProgress)
+	}
+
+	if rf, ok := ret.Get(1).(func(*v1alpha1.Rollout, *types.RpcStepContext) types.RpcError); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Get(1).(types.RpcError)
+	}
+
+	return r0, r1
+}
+
+// Run provides a mock function with given fields: _a0, _a1
+func (_m *StepPluginClient) Run(_a0 *v1alpha1.Rollout, _a1 *types.RpcStepContext) (types.RpcStepResult, types.RpcError) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Run")
+	}
+
+	var r0 types.RpcStepResult
+	var r1 types.RpcError
+	if rf, ok := ret.Get(0).(func(*v1alpha1.Rollout, *types.RpcStepContext) (types.RpcStepResult, types.RpcError)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(*v1alpha1.Rollout, *types.RpcStepContext) types.RpcStepResult); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(types.RpcStepResult)
+	}
+
+	if rf, ok := ret.Get(1).(func(*v1alpha1.Rollout, *types.RpcStepContext) types.RpcError); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Get(1).(types.RpcError)
+	}
+
+	return r0, r1
+}
+
+// Terminate provides a mock function with given fields: _a0, _a1
+func (_m *StepPluginClient) Terminate(_a0 *v1alpha1.Rollout, _a1 *types.RpcStepContext) (types.RpcStepResult, types.RpcError) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Terminate")
+	}
+
+	var r0 types.RpcStepResult
+	var r1 types.RpcError
+	if rf, ok := ret.Get(0).(func(*v1alpha1.Rollout, *types.RpcStepContext) (types.RpcStepResult, types.RpcError)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(*v1alpha1.Rollout, *types.RpcStepContext) types.RpcStepResult); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(types.RpcStepResult)
+	}
+
+	if rf, ok := ret.Get(1).(func(*v1alpha1.Rollout, *types.RpcStepContext) types.RpcError); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Get(1).(types.RpcError)
+	}
+
+	return r0, r1
+}
+
+// Type provides a mock function with no fields
+func (_m *StepPluginClient) Type() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Type")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// NewStepPluginClient creates a new instance of StepPluginClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStepPluginClient(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *StepPluginClient {
+	mock := &StepPluginClient{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/gob"
 	"fmt"
 	"net/rpc"
+	"sync"
+	"time"
 
 	"github.com/argoproj/argo-rollouts/utils/plugin/types"
 
@@ -27,15 +29,37 @@ type AbortArgs struct {
 	Context *types.RpcStepContext
 }
 
+type InfoArgs struct {
+	// APIVersion is the latest step plugin API version supported by the controller
+	APIVersion int32
+}
+
+type ProgressArgs struct {
+	Rollout *v1alpha1.Rollout
+	Context *types.RpcStepContext
+}
+
 type Response struct {
 	Result types.RpcStepResult
 	Error  types.RpcError
 }
 
+type InfoResponse struct {
+	Info  types.RpcStepPluginInfo
+	Error types.RpcError
+}
+
+type ProgressResponse struct {
+	Progress types.RpcStepProgress
+	Error    types.RpcError
+}
+
 func init() {
 	gob.RegisterName("step.RunArgs", new(RunArgs))
 	gob.RegisterName("step.TerminateArgs", new(TerminateArgs))
 	gob.RegisterName("step.AbortArgs", new(AbortArgs))
+	gob.RegisterName("step.InfoArgs", new(InfoArgs))
+	gob.RegisterName("step.ProgressArgs", new(ProgressArgs))
 }
 
 // StepPlugin is the interface that we're exposing as a plugin. It needs to match metricproviders.Providers but we can
@@ -45,8 +69,22 @@ type StepPlugin interface {
 	types.RpcStep
 }
 
+// StepPluginClient is the controller side of a step plugin, which also calls the operations of the v2 API. The
+// plugins of the v1 API are described as such by Info
+type StepPluginClient interface {
+	StepPlugin
+	types.RpcStepInfo
+	types.RpcStepProgressReporter
+}
+
 // StepPluginRPC Here is an implementation that talks over RPC
-type StepPluginRPC struct{ client *rpc.Client }
+type StepPluginRPC struct {
+	client *types.RpcClient
+
+	lock sync.Mutex
+	// info caches the description of the plugin, which does not change while its process runs
+	info *types.RpcStepPluginInfo
+}
 
 // InitPlugin is the client aka the controller side function that calls the server side rpc (plugin)
 // this gets called once during startup of the plugin and can be used to set up informers, k8s clients, etc.
@@ -101,6 +139,50 @@ func (g *StepPluginRPC) Abort(rollout *v1alpha1.Rollout, context *types.RpcStepC
 	return resp.Result, resp.Error
 }
 
+// Info describes the plugin. The plugins built before the v2 API do not implement Info, and are of the v1 API
+func (g *StepPluginRPC) Info() (types.RpcStepPluginInfo, types.RpcError) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if g.info != nil {
+		return *g.info, types.RpcError{}
+	}
+	var resp InfoResponse
+	// the args are not nil, since plugins of the v1 API cannot skip a nil interface in the request of a method they
+	// do not have
+	var args any = InfoArgs{APIVersion: types.StepPluginAPIVersion}
+	err := g.client.Call("Plugin.Info", &args, &resp)
+	if types.IsMethodNotFound(err) {
+		resp = InfoResponse{Info: types.RpcStepPluginInfo{APIVersion: 1}}
+	} else if err != nil {
+		return types.RpcStepPluginInfo{}, types.RpcError{ErrorString: fmt.Sprintf("Info rpc call error: %s", err)}
+	}
+	if resp.Error.HasError() {
+		return types.RpcStepPluginInfo{}, resp.Error
+	}
+	g.info = &resp.Info
+	return resp.Info, types.RpcError{}
+}
+
+// Progress returns the progress of a running Run operation
+func (g *StepPluginRPC) Progress(rollout *v1alpha1.Rollout, context *types.RpcStepContext) (types.RpcStepProgress, types.RpcError) {
+	var resp ProgressResponse
+	var args any = ProgressArgs{
+		Rollout: rollout,
+		Context: context,
+	}
+	err := g.client.Call("Plugin.Progress", &args, &resp)
+	if err != nil {
+		return types.RpcStepProgress{}, types.RpcError{ErrorString: fmt.Sprintf("Progress rpc call error: %s", err)}
+	}
+	return resp.Progress, resp.Error
+}
+
+// Drain waits for the in-flight calls to the plugin to complete, and returns false if they did not complete within the
+// timeout
+func (g *StepPluginRPC) Drain(timeout time.Duration) bool {
+	return g.client.Drain(timeout)
+}
+
 // Type returns the type of the traffic routing reconciler
 func (g *StepPluginRPC) Type() string {
 	var resp string
@@ -168,6 +250,43 @@ func (s *StepRPCServer) Abort(args any, resp *Response) error {
 	return nil
 }
 
+// Info describes the plugin, as a plugin of the v1 API if it does not implement types.RpcStepInfo
+func (s *StepRPCServer) Info(args any, resp *InfoResponse) error {
+	impl, ok := s.Impl.(types.RpcStepInfo)
+	if !ok {
+		*resp = InfoResponse{Info: types.RpcStepPluginInfo{APIVersion: 1}}
+		return nil
+	}
+	info, err := impl.Info()
+	if info.APIVersion == 0 {
+		info.APIVersion = types.StepPluginAPIVersion
+	}
+	*resp = InfoResponse{
+		Info:  info,
+		Error: err,
+	}
+	return nil
+}
+
+// Progress returns the progress of a running Run operation
+func (s *StepRPCServer) Progress(args any, resp *ProgressResponse) error {
+	progressArgs, ok := args.(*ProgressArgs)
+	if !ok {
+		return fmt.Errorf("invalid args %s", args)
+	}
+	impl, ok := s.Impl.(types.RpcStepProgressReporter)
+	if !ok {
+		*resp = ProgressResponse{Error: types.RpcError{ErrorString: "Progress is not implemented by the plugin"}}
+		return nil
+	}
+	progress, err := impl.Progress(progressArgs.Rollout, progressArgs.Context)
+	*resp = ProgressResponse{
+		Progress: progress,
+		Error:    err,
+	}
+	return nil
+}
+
 // Type returns the type of the traffic routing reconciler
 func (s *StepRPCServer) Type(args any, resp *string) error {
 	*resp = s.Impl.Type()
@@ -194,5 +313,5 @@ func (p *RpcStepPlugin) Server(*plugin.MuxBroker) (any, error) {
 }
 
 func (RpcStepPlugin) Client(b *plugin.MuxBroker, c *rpc.Client) (any, error) {
-	return &StepPluginRPC{client: c}, nil
+	return &StepPluginRPC{client: types.NewRpcClient(c)}, nil
 }
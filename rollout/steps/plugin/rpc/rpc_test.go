@@ -2,6 +2,8 @@ package rpc
 
 import (
 	"context"
+	"net"
+	"net/rpc"
 	"testing"
 	"time"
 
@@ -19,11 +21,9 @@ var testHandshake = goPlugin.HandshakeConfig{
 	MagicCookieValue: "step",
 }
 
-func pluginClient(t *testing.T) (StepPlugin, goPlugin.ClientProtocol, func(), chan struct{}) {
+func pluginClient(t *testing.T, pluginImpl StepPlugin) (StepPluginClient, goPlugin.ClientProtocol, func(), chan struct{}) {
 	ctx, cancel := context.WithCancel(context.Background())
 
-	pluginImpl := &testRpcPlugin{}
-
 	// pluginMap is the map of plugins we can dispense.
 	var pluginMap = map[string]goPlugin.Plugin{
 		"RpcStepPlugin": &RpcStepPlugin{Impl: pluginImpl},
@@ -70,7 +70,7 @@ func pluginClient(t *testing.T) (StepPlugin, goPlugin.ClientProtocol, func(), ch
 		t.Fail()
 	}
 
-	plugin, ok := raw.(StepPlugin)
+	plugin, ok := raw.(StepPluginClient)
 	if !ok {
 		t.Fail()
 	}
@@ -79,7 +79,7 @@ func pluginClient(t *testing.T) (StepPlugin, goPlugin.ClientProtocol, func(), ch
 }
 
 func TestPlugin(t *testing.T) {
-	plugin, _, cancel, closeCh := pluginClient(t)
+	plugin, _, cancel, closeCh := pluginClient(t, &testRpcPlugin{})
 	defer cancel()
 
 	err := plugin.InitPlugin()
@@ -101,13 +101,64 @@ func TestPlugin(t *testing.T) {
 	typeString := plugin.Type()
 	assert.Equal(t, "StepPlugin Test", typeString)
 
+	info, err := plugin.Info()
+	assert.Equal(t, "", err.Error())
+	assert.Equal(t, types.RpcStepPluginInfo{APIVersion: 1}, info)
+
+	_, err = plugin.Progress(&ro, &types.RpcStepContext{})
+	assert.Equal(t, "Progress is not implemented by the plugin", err.Error())
+
 	// Canceling should cause an exit
 	cancel()
 	<-closeCh
 }
 
+func TestPluginV2(t *testing.T) {
+	plugin, _, cancel, closeCh := pluginClient(t, &testRpcPluginV2{})
+	defer cancel()
+
+	info, err := plugin.Info()
+	assert.Equal(t, "", err.Error())
+	assert.Equal(t, types.RpcStepPluginInfo{
+		APIVersion:   types.StepPluginAPIVersion,
+		ConfigSchema: []byte(`{"type":"object"}`),
+		StateVersion: 3,
+		Progress:     true,
+	}, info)
+
+	progress, err := plugin.Progress(&v1alpha1.Rollout{}, &types.RpcStepContext{StateVersion: 2})
+	assert.Equal(t, "", err.Error())
+	assert.Equal(t, types.RpcStepProgress{Percent: 50, Stage: "state version 2"}, progress)
+
+	cancel()
+	<-closeCh
+}
+
+// v1RPCServer is the RPC server of a plugin built before the v2 API, which does not have the Info method
+type v1RPCServer struct{}
+
+func (s *v1RPCServer) Type(args any, resp *string) error {
+	*resp = "StepPlugin v1"
+	return nil
+}
+
+func TestInfoOfV1Plugin(t *testing.T) {
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName("Plugin", &v1RPCServer{}))
+	serverConn, clientConn := net.Pipe()
+	go server.ServeConn(serverConn)
+	client := rpc.NewClient(clientConn)
+	defer client.Close()
+
+	plugin := &StepPluginRPC{client: types.NewRpcClient(client)}
+	info, err := plugin.Info()
+	assert.Equal(t, "", err.Error())
+	assert.Equal(t, types.RpcStepPluginInfo{APIVersion: 1}, info)
+	assert.Equal(t, "StepPlugin v1", plugin.Type())
+}
+
 func TestPluginClosedConnection(t *testing.T) {
-	plugin, client, cancel, closeCh := pluginClient(t)
+	plugin, client, cancel, closeCh := pluginClient(t, &testRpcPlugin{})
 	defer cancel()
 
 	client.Close()
@@ -127,6 +178,12 @@ func TestPluginClosedConnection(t *testing.T) {
 	_, err = plugin.Abort(&v1alpha1.Rollout{}, &types.RpcStepContext{})
 	assert.Contains(t, err.Error(), expectedError)
 
+	_, err = plugin.Info()
+	assert.Contains(t, err.Error(), expectedError)
+
+	_, err = plugin.Progress(&v1alpha1.Rollout{}, &types.RpcStepContext{})
+	assert.Contains(t, err.Error(), expectedError)
+
 	cancel()
 	<-closeCh
 }
@@ -147,4 +204,7 @@ func TestInvalidArgs(t *testing.T) {
 	err = server.Abort(badtype, &resp)
 	assert.Error(t, err)
 
+	err = server.Progress(badtype, &ProgressResponse{})
+	assert.Error(t, err)
+
 }
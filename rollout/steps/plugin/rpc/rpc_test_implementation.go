@@ -1,6 +1,8 @@
 package rpc
 
 import (
+	"fmt"
+
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	"github.com/argoproj/argo-rollouts/utils/plugin/types"
 )
@@ -27,3 +29,20 @@ func (p *testRpcPlugin) Abort(_ *v1alpha1.Rollout, _ *types.RpcStepContext) (typ
 func (p *testRpcPlugin) Type() string {
 	return "StepPlugin Test"
 }
+
+// testRpcPluginV2 is a step plugin of the v2 API
+type testRpcPluginV2 struct {
+	testRpcPlugin
+}
+
+func (p *testRpcPluginV2) Info() (types.RpcStepPluginInfo, types.RpcError) {
+	return types.RpcStepPluginInfo{
+		ConfigSchema: []byte(`{"type":"object"}`),
+		StateVersion: 3,
+		Progress:     true,
+	}, types.RpcError{}
+}
+
+func (p *testRpcPluginV2) Progress(_ *v1alpha1.Rollout, context *types.RpcStepContext) (types.RpcStepProgress, types.RpcError) {
+	return types.RpcStepProgress{Percent: 50, Stage: fmt.Sprintf("state version %d", context.StateVersion)}, types.RpcError{}
+}
@@ -16,6 +16,7 @@ import (
 	labelsutil "k8s.io/kubernetes/pkg/util/labels"
 	"k8s.io/utils/ptr"
 
+	"github.com/argoproj/argo-rollouts/controller/metrics"
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	"github.com/argoproj/argo-rollouts/rollout/trafficrouting"
 	analysisutil "github.com/argoproj/argo-rollouts/utils/analysis"
@@ -24,11 +25,13 @@ import (
 	"github.com/argoproj/argo-rollouts/utils/defaults"
 	"github.com/argoproj/argo-rollouts/utils/diff"
 	experimentutil "github.com/argoproj/argo-rollouts/utils/experiment"
+	"github.com/argoproj/argo-rollouts/utils/featureflag"
 	"github.com/argoproj/argo-rollouts/utils/hash"
 	logutil "github.com/argoproj/argo-rollouts/utils/log"
 	"github.com/argoproj/argo-rollouts/utils/record"
 	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
 	rolloututil "github.com/argoproj/argo-rollouts/utils/rollout"
+	timeutil "github.com/argoproj/argo-rollouts/utils/time"
 )
 
 // getAllReplicaSetsAndSyncRevision returns all the replica sets for the provided rollout (new and all old), with new RS's and rollout's revision updated.
@@ -76,11 +79,13 @@ func (c *rolloutContext) syncReplicaSetRevision() (*appsv1.ReplicaSet, error) {
 	annotationsUpdated := annotations.SetNewReplicaSetAnnotations(c.rollout, rsCopy, newRevision, true)
 	minReadySecondsNeedsUpdate := rsCopy.Spec.MinReadySeconds != c.rollout.Spec.MinReadySeconds
 	affinityNeedsUpdate := replicasetutil.IfInjectedAntiAffinityRuleNeedsUpdate(rsCopy.Spec.Template.Spec.Affinity, *c.rollout)
+	topologySpreadNeedsUpdate := replicasetutil.IfInjectedTopologySpreadConstraintsNeedUpdate(rsCopy.Spec.Template.Spec.TopologySpreadConstraints, *c.rollout)
 
-	if annotationsUpdated || minReadySecondsNeedsUpdate || affinityNeedsUpdate {
+	if annotationsUpdated || minReadySecondsNeedsUpdate || affinityNeedsUpdate || topologySpreadNeedsUpdate {
 
 		rsCopy.Spec.MinReadySeconds = c.rollout.Spec.MinReadySeconds
 		rsCopy.Spec.Template.Spec.Affinity = replicasetutil.GenerateReplicaSetAffinity(*c.rollout)
+		rsCopy.Spec.Template.Spec.TopologySpreadConstraints = replicasetutil.GenerateReplicaSetTopologySpreadConstraints(*c.rollout)
 
 		rs, err := c.updateReplicaSet(ctx, rsCopy)
 		if err != nil {
@@ -142,6 +147,7 @@ func (c *rolloutContext) createDesiredReplicaSet() (*appsv1.ReplicaSet, error) {
 	newRSTemplate := *c.rollout.Spec.Template.DeepCopy()
 	// Add default anti-affinity rule if antiAffinity bool set and RSTemplate meets requirements
 	newRSTemplate.Spec.Affinity = replicasetutil.GenerateReplicaSetAffinity(*c.rollout)
+	newRSTemplate.Spec.TopologySpreadConstraints = replicasetutil.GenerateReplicaSetTopologySpreadConstraints(*c.rollout)
 	podTemplateSpecHash := hash.ComputePodTemplateHash(&c.rollout.Spec.Template, c.rollout.Status.CollisionCount)
 	newRSTemplate.Labels = labelsutil.CloneAndAddLabel(c.rollout.Spec.Template.Labels, v1alpha1.DefaultRolloutUniqueLabelKey, podTemplateSpecHash)
 	// Add podTemplateHash label to selector.
@@ -192,7 +198,7 @@ func (c *rolloutContext) createDesiredReplicaSet() (*appsv1.ReplicaSet, error) {
 	// hash collisions. If there is any other error, we need to report it in the status of
 	// the Rollout.
 	alreadyExists := false
-	createdRS, err := c.kubeclientset.AppsV1().ReplicaSets(c.rollout.Namespace).Create(ctx, newRS, metav1.CreateOptions{})
+	createdRS, err := c.kubeclientset.AppsV1().ReplicaSets(c.rollout.Namespace).Create(ctx, newRS, metav1.CreateOptions{FieldManager: defaults.DefaultFieldManager})
 	switch {
 	// We may end up hitting this due to a slow cache or a fast resync of the Rollout.
 	case errors.IsAlreadyExists(err):
@@ -308,7 +314,7 @@ func (c *rolloutContext) syncReplicasOnly() error {
 			return fmt.Errorf("failed to reconcileCanaryReplicaSets in syncReplicasOnly: %w", err)
 		}
 		newStatus.AvailableReplicas = replicasetutil.GetAvailableReplicaCountForReplicaSets(c.allRSs)
-		newStatus.HPAReplicas = replicasetutil.GetActualReplicaCountForReplicaSets(c.allRSs)
+		c.setCanaryHPAStatus(newStatus)
 	}
 	return c.persistRolloutStatus(newStatus)
 }
@@ -419,7 +425,7 @@ func (c *rolloutContext) calculateBaseStatus() v1alpha1.RolloutStatus {
 	newStatus.CollisionCount = c.rollout.Status.CollisionCount
 	newStatus.Conditions = prevStatus.Conditions
 	newStatus.RestartedAt = c.newStatus.RestartedAt
-	newStatus.PromoteFull = (newStatus.CurrentPodHash != newStatus.StableRS) && prevStatus.PromoteFull
+	newStatus.PromoteFull = (newStatus.CurrentPodHash != newStatus.StableRS) && (prevStatus.PromoteFull || c.promoteFullDenied)
 	return newStatus
 }
 
@@ -531,6 +537,7 @@ func (c *rolloutContext) patchCondition(r *v1alpha1.Rollout, newStatus *v1alpha1
 	}
 	newStatus.ObservedGeneration = strconv.Itoa(int(c.rollout.Generation))
 	newStatus.Phase, newStatus.Message = rolloututil.CalculateRolloutPhase(r.Spec, *newStatus)
+	newStatus.Health = rolloututil.CalculateRolloutHealth(r.Spec, *newStatus)
 
 	logCtx := logutil.WithVersionFields(c.log, r)
 	patch, modified, err := diff.CreateTwoWayMergePatch(
@@ -562,7 +569,11 @@ func (c *rolloutContext) patchCondition(r *v1alpha1.Rollout, newStatus *v1alpha1
 // not affect the progressDeadlineSeconds
 func isIndefiniteStep(r *v1alpha1.Rollout) bool {
 	currentStep, _ := replicasetutil.GetCurrentCanaryStep(r)
-	if currentStep != nil && (currentStep.Experiment != nil || currentStep.Analysis != nil || currentStep.Pause != nil) {
+	if currentStep != nil && currentStep.Pause != nil {
+		return true
+	}
+	// experiment and analysis steps only count against the lack of progress when the step has its own deadline
+	if currentStep != nil && (currentStep.Experiment != nil || currentStep.Analysis != nil) && currentStep.ProgressDeadline == nil {
 		return true
 	}
 	// also check the pause condition to cover blueGreen
@@ -571,6 +582,37 @@ func isIndefiniteStep(r *v1alpha1.Rollout) bool {
 	return pausedCondTrue
 }
 
+// calculateCurrentStepStartedAt records the time the current canary step started when the step has its
+// own progress deadline, which is measured from it. The time is kept while the rollout stays at the same
+// step of the same update.
+func (c *rolloutContext) calculateCurrentStepStartedAt(newStatus *v1alpha1.RolloutStatus) {
+	canary := c.rollout.Spec.Strategy.Canary
+	if canary == nil || newStatus.CurrentStepIndex == nil || int(*newStatus.CurrentStepIndex) >= len(canary.Steps) ||
+		canary.Steps[*newStatus.CurrentStepIndex].ProgressDeadline == nil {
+		newStatus.Canary.CurrentStepStartedAt = nil
+		return
+	}
+	prevStatus := c.rollout.Status
+	sameStep := prevStatus.CurrentStepIndex != nil && *prevStatus.CurrentStepIndex == *newStatus.CurrentStepIndex &&
+		prevStatus.CurrentPodHash == newStatus.CurrentPodHash && prevStatus.CurrentStepHash == newStatus.CurrentStepHash
+	if sameStep && prevStatus.Canary.CurrentStepStartedAt != nil {
+		newStatus.Canary.CurrentStepStartedAt = prevStatus.Canary.CurrentStepStartedAt
+		return
+	}
+	now := timeutil.MetaNow()
+	newStatus.Canary.CurrentStepStartedAt = &now
+}
+
+// hasPauseCondition returns whether the status has a pause condition with the given reason
+func hasPauseCondition(status *v1alpha1.RolloutStatus, reason v1alpha1.PauseReason) bool {
+	for _, cond := range status.PauseConditions {
+		if cond.Reason == reason {
+			return true
+		}
+	}
+	return false
+}
+
 // isWaitingForReplicaSetScaleDown returns whether or not the rollout still has other replica sets with a scale down deadline annotation
 func isWaitingForReplicaSetScaleDown(r *v1alpha1.Rollout, newRS, stableRS *appsv1.ReplicaSet, allRSs []*appsv1.ReplicaSet) bool {
 	otherRSs := replicasetutil.GetOtherRSs(r, newRS, stableRS, allRSs)
@@ -584,10 +626,25 @@ func isWaitingForReplicaSetScaleDown(r *v1alpha1.Rollout, newRS, stableRS *appsv
 	return false
 }
 
-// evaluateProgressDeadlineAbort aborts the rollout (via the pause context) when an in-flight
-// update has exceeded its progress deadline and spec.progressDeadlineAbort is set.
+// getProgressDeadlineAction returns the action to take when the rollout exceeds its progress deadline.
+// The action of the current canary step takes precedence over spec.progressDeadlineAbort.
+func (c *rolloutContext) getProgressDeadlineAction() v1alpha1.StepProgressDeadlineAction {
+	currentStep, _ := replicasetutil.GetCurrentCanaryStep(c.rollout)
+	if currentStep != nil && currentStep.ProgressDeadline != nil && currentStep.ProgressDeadline.Action != "" {
+		return currentStep.ProgressDeadline.Action
+	}
+	if c.rollout.Spec.ProgressDeadlineAbort {
+		return v1alpha1.StepProgressDeadlineActionAbort
+	}
+	return ""
+}
+
+// evaluateProgressDeadlineAbort aborts or pauses the rollout (via the pause context) when an in-flight
+// update has exceeded its progress deadline and spec.progressDeadlineAbort or a step progress deadline
+// action is set.
 func (c *rolloutContext) evaluateProgressDeadlineAbort(newStatus *v1alpha1.RolloutStatus) {
-	if !c.rollout.Spec.ProgressDeadlineAbort {
+	action := c.getProgressDeadlineAction()
+	if action == "" {
 		return
 	}
 	if c.pauseContext == nil || c.pauseContext.IsAborted() {
@@ -599,16 +656,19 @@ func (c *rolloutContext) evaluateProgressDeadlineAbort(newStatus *v1alpha1.Rollo
 	if conditions.RolloutCompleted(newStatus) {
 		return
 	}
-	// Don't abort an update that is still making progress during this reconciliation
-	if conditions.RolloutProgressing(c.rollout, newStatus) {
+	// Don't abort an update that is still making progress during this reconciliation, unless the
+	// current step has its own deadline, which bounds the duration of the step
+	if newStatus.Canary.CurrentStepStartedAt == nil && conditions.RolloutProgressing(c.rollout, newStatus) {
 		return
 	}
 	// Some steps do not count against the lack of progress
 	if isIndefiniteStep(c.rollout) || isWaitingForReplicaSetScaleDown(c.rollout, c.newRS, c.stableRS, c.allRSs) {
 		return
 	}
-	// Check if the existing Progressing condition has timed out
-	if !conditions.RolloutTimedOut(c.rollout, &c.rollout.Status) {
+	// Check if the existing Progressing condition (or the current step) has timed out
+	timedOutStatus := c.rollout.Status.DeepCopy()
+	timedOutStatus.Canary.CurrentStepStartedAt = newStatus.Canary.CurrentStepStartedAt
+	if !conditions.RolloutTimedOut(c.rollout, timedOutStatus) {
 		return
 	}
 
@@ -616,10 +676,37 @@ func (c *rolloutContext) evaluateProgressDeadlineAbort(newStatus *v1alpha1.Rollo
 	if c.newRS != nil {
 		msg = fmt.Sprintf(conditions.ReplicaSetTimeOutMessage, c.newRS.Name)
 	}
+	if action == v1alpha1.StepProgressDeadlineActionPause {
+		if getPauseCondition(c.rollout, v1alpha1.PauseReasonStepProgressDeadline) != nil {
+			return
+		}
+		c.pauseContext.AddPauseCondition(v1alpha1.PauseReasonStepProgressDeadline)
+		c.recorder.Warnf(c.rollout, record.EventOptions{EventReason: conditions.StepProgressDeadlineExceededReason}, msg)
+		return
+	}
 	c.pauseContext.AddAbort(msg)
 	c.recorder.Warnf(c.rollout, record.EventOptions{EventReason: conditions.RolloutAbortedReason}, msg)
 }
 
+// lastNotificationDelivery returns the most recent failed delivery of the last deliveries to each destination, or the
+// most recent one if none failed, so that a failed destination is not hidden by the deliveries to the other ones
+func lastNotificationDelivery(deliveries []record.NotificationDelivery) *record.NotificationDelivery {
+	var last, lastFailed *record.NotificationDelivery
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		if last == nil || delivery.Time.After(last.Time) {
+			last = delivery
+		}
+		if delivery.Error != nil && (lastFailed == nil || delivery.Time.After(lastFailed.Time)) {
+			lastFailed = delivery
+		}
+	}
+	if lastFailed != nil {
+		return lastFailed
+	}
+	return last
+}
+
 func (c *rolloutContext) calculateRolloutConditions(newStatus *v1alpha1.RolloutStatus) {
 	isPaused := len(newStatus.PauseConditions) > 0 || c.rollout.Spec.Paused
 	isAborted := c.pauseContext.IsAborted()
@@ -705,7 +792,7 @@ func (c *rolloutContext) calculateRolloutConditions(newStatus *v1alpha1.RolloutS
 				conditions.RemoveRolloutCondition(newStatus, v1alpha1.RolloutProgressing)
 			}
 			conditions.SetRolloutCondition(newStatus, *condition)
-		case !isIndefiniteStep(c.rollout) && !isWaitingForReplicaSetScaleDown(c.rollout, c.newRS, c.stableRS, c.allRSs) && conditions.RolloutTimedOut(c.rollout, newStatus):
+		case !isIndefiniteStep(c.rollout) && !isWaitingForReplicaSetScaleDown(c.rollout, c.newRS, c.stableRS, c.allRSs) && !hasPauseCondition(newStatus, v1alpha1.PauseReasonStepProgressDeadline) && conditions.RolloutTimedOut(c.rollout, newStatus):
 
 			// Update the rollout with a timeout condition. If the condition already exists,
 			// we ignore this update.
@@ -715,7 +802,9 @@ func (c *rolloutContext) calculateRolloutConditions(newStatus *v1alpha1.RolloutS
 			}
 
 			condition := conditions.NewRolloutCondition(v1alpha1.RolloutProgressing, corev1.ConditionFalse, conditions.TimedOutReason, msg)
-			conditions.SetRolloutCondition(newStatus, *condition)
+			if conditions.SetRolloutCondition(newStatus, *condition) {
+				c.recorder.Warnf(c.rollout, record.EventOptions{EventReason: conditions.RolloutDegradedReason}, conditions.RolloutDegradedMessage, msg)
+			}
 		}
 	}
 
@@ -740,6 +829,18 @@ func (c *rolloutContext) calculateRolloutConditions(newStatus *v1alpha1.RolloutS
 		conditions.RemoveRolloutCondition(newStatus, v1alpha1.RolloutReplicaFailure)
 	}
 
+	// the condition is kept as is until a notification is sent, e.g. after a restart of the controller
+	if delivery := lastNotificationDelivery(c.recorder.LastNotificationDeliveries(c.rollout)); delivery != nil {
+		dest := delivery.Destination
+		deliveredCond := conditions.NewRolloutCondition(v1alpha1.RolloutNotificationDelivered, corev1.ConditionTrue,
+			conditions.NotificationDeliveredReason, fmt.Sprintf(conditions.NotificationDeliveredMessage, dest.Service, dest.Recipient))
+		if delivery.Error != nil {
+			deliveredCond = conditions.NewRolloutCondition(v1alpha1.RolloutNotificationDelivered, corev1.ConditionFalse,
+				conditions.NotificationDeliveryFailedReason, fmt.Sprintf(conditions.NotificationDeliveryFailedMessage, dest.Service, dest.Recipient, delivery.Error))
+		}
+		conditions.SetRolloutCondition(newStatus, *deliveredCond)
+	}
+
 	if conditions.RolloutCompleted(newStatus) {
 		// The event gets triggered in function promoteStable
 		updateCompletedCond := conditions.NewRolloutCondition(v1alpha1.RolloutCompleted, corev1.ConditionTrue,
@@ -773,6 +874,8 @@ func (c *rolloutContext) persistRolloutStatus(newStatus *v1alpha1.RolloutStatus)
 		newStatus.WorkloadObservedGeneration = ""
 	}
 
+	c.calculateCurrentStepStartedAt(newStatus)
+
 	// Evaluate the progress-deadline abort first so that any resulting abort is reflected in
 	// the abort/pause fields below and persisted in this same reconcile.
 	c.evaluateProgressDeadlineAbort(newStatus)
@@ -785,6 +888,7 @@ func (c *rolloutContext) persistRolloutStatus(newStatus *v1alpha1.RolloutStatus)
 
 	// Calculate the phase. This requires the conditions to be calculated first
 	newStatus.Phase, newStatus.Message = rolloututil.CalculateRolloutPhase(c.rollout.Spec, *newStatus)
+	newStatus.Health = rolloututil.CalculateRolloutHealth(c.rollout.Spec, *newStatus)
 
 	prevStatus := c.rollout.Status
 	patch, modified, err := diff.CreateTwoWayMergePatch(
@@ -798,17 +902,31 @@ func (c *rolloutContext) persistRolloutStatus(newStatus *v1alpha1.RolloutStatus)
 		logCtx.Errorf("Error constructing app status patch: %v", err)
 		return err
 	}
-	if !modified {
+	if !modified || statusSemanticallyEqual(&prevStatus, newStatus) {
 		logCtx.Info("No status changes. Skipping patch")
 		c.requeueStuckRollout(*newStatus)
 		return nil
 	}
+	key := c.rollout.Namespace + "/" + c.rollout.Name
+	if delay := c.statusCoalescer.Delay(key, defaults.GetRolloutStatusUpdateWindow(), &prevStatus, newStatus, timeutil.Now()); delay > 0 {
+		logCtx.Infof("Only replica counts changed. Deferring status patch by %v", delay)
+		c.enqueueRolloutAfter(c.rollout, delay)
+		return nil
+	}
 
-	newRollout, err := c.argoprojclientset.ArgoprojV1alpha1().Rollouts(c.rollout.Namespace).Patch(ctx, c.rollout.Name, patchtypes.MergePatchType, patch, metav1.PatchOptions{}, "status")
+	var newRollout *v1alpha1.Rollout
+	err = c.reconcilePhase(metrics.RolloutPhaseStatusPatch, func() (err error) {
+		newRollout, err = c.argoprojclientset.ArgoprojV1alpha1().Rollouts(c.rollout.Namespace).Patch(ctx, c.rollout.Name, patchtypes.MergePatchType, patch, metav1.PatchOptions{}, "status")
+		return err
+	})
 	if err != nil {
 		logCtx.Warningf("Error updating rollout: %v", err)
 		return err
 	}
+	c.statusCoalescer.Patched(key, timeutil.Now())
+	if c.metricsServer != nil {
+		c.metricsServer.ObserveRolloutStep(c.rollout, newStatus, timeutil.Now())
+	}
 
 	c.sendStateChangeEvents(&prevStatus, newStatus)
 	logCtx.Infof("Patched: %s", patch)
@@ -864,8 +982,9 @@ func (c *rolloutContext) requeueStuckRollout(newStatus v1alpha1.RolloutStatus) t
 	// progressDeadlineSeconds: 600 (10 minutes)
 	//
 	// lastUpdated + progressDeadlineSeconds - now => 00:00:00 + 00:10:00 - 00:03:00 => 07:00
-	progressDeadlineSeconds := defaults.GetProgressDeadlineSecondsOrDefault(c.rollout)
-	after := currentCond.LastUpdateTime.Time.Add(time.Duration(progressDeadlineSeconds) * time.Second).Sub(nowFn())
+	progressDeadlineSeconds := defaults.GetCurrentStepProgressDeadlineSecondsOrDefault(c.rollout)
+	from := conditions.ProgressDeadlineStart(&newStatus, *currentCond)
+	after := from.Time.Add(time.Duration(progressDeadlineSeconds) * time.Second).Sub(nowFn())
 	// If the remaining time is less than a second, then requeue the deployment immediately.
 	// Make it ratelimited so we stay on the safe side, eventually the Deployment should
 	// transition either to a Complete or to a TimedOut condition.
@@ -922,13 +1041,16 @@ func (c *rolloutContext) resetRolloutStatus(newStatus *v1alpha1.RolloutStatus) {
 	c.pauseContext.ClearPauseConditions()
 	c.pauseContext.RemoveAbort()
 	c.SetRestartedAt()
+	newStatus.RestartStatus = nil
 	newStatus.PromoteFull = false
 	newStatus.BlueGreen.PrePromotionAnalysisRunStatus = nil
 	newStatus.BlueGreen.PostPromotionAnalysisRunStatus = nil
 	newStatus.BlueGreen.ScaleUpPreviewCheckPoint = false
 	newStatus.Canary.CurrentStepAnalysisRunStatus = nil
 	newStatus.Canary.CurrentBackgroundAnalysisRunStatus = nil
+	newStatus.Canary.CurrentGuardrailAnalysisRunStatus = nil
 	newStatus.Canary.StepPluginStatuses = nil
+	newStatus.Canary.Gate = nil
 	newStatus.CurrentStepIndex = replicasetutil.ResetCurrentStepIndex(c.rollout)
 }
 
@@ -982,6 +1104,10 @@ func (c *rolloutContext) shouldFullPromote(newStatus v1alpha1.RolloutStatus) str
 				!replicasetutil.ReplicaProgressThresholdMet(c.rollout.Spec.Strategy.Canary.ReplicaProgressThreshold, c.newRS, defaults.GetReplicasOrDefault(c.rollout.Spec.Replicas))) {
 			return ""
 		}
+		if usesPodTemplateOverlay(c.rollout) && !c.podTemplateOverlaySynced {
+			// the canary pods still run with the overlay of a setCanaryPodTemplateOverlay step
+			return ""
+		}
 		if c.rollout.Status.PromoteFull {
 			return "Full promotion requested"
 		}
@@ -1009,6 +1135,10 @@ func (c *rolloutContext) shouldFullPromote(newStatus v1alpha1.RolloutStatus) str
 			// active selector is pointing to desired RS, but we have not verify the target group yet
 			return ""
 		}
+		if usesPodTemplateOverlay(c.rollout) && !c.podTemplateOverlaySynced {
+			// the canary pods still run with the overlay of a setCanaryPodTemplateOverlay step
+			return ""
+		}
 		if c.rollout.Status.PromoteFull {
 			return "Full promotion requested"
 		}
@@ -1018,7 +1148,7 @@ func (c *rolloutContext) shouldFullPromote(newStatus v1alpha1.RolloutStatus) str
 		if c.pauseContext.IsAborted() {
 			return ""
 		}
-		if c.rollout.Spec.Strategy.BlueGreen.PostPromotionAnalysis != nil {
+		if rolloututil.HasPostPromotionAnalysis(c.rollout) {
 			// corner case - we fast-track the StableRS to be updated to CurrentPodHash when we are
 			// moving to a ReplicaSet within scaleDownDelay and wish to skip analysis.
 			if replicasetutil.HasScaleDownDeadline(c.newRS) {
@@ -1049,6 +1179,8 @@ func (c *rolloutContext) promoteStable(newStatus *v1alpha1.RolloutStatus, reason
 		} else {
 			newStatus.CurrentStepIndex = nil
 		}
+		// the flags of the promoted update stay enabled for all users during the next update, until its steps set them
+		newStatus.Canary.StableFeatureFlags = featureflag.Keys(c.rollout)
 	}
 	previousStableHash := newStatus.StableRS
 	revision, _ := replicasetutil.Revision(c.rollout)
@@ -2,11 +2,14 @@ package rollout
 
 import (
 	"encoding/json"
+	"errors"
 	"strconv"
 	"testing"
 	"time"
 
+	"github.com/argoproj/notifications-engine/pkg/services"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/apps/v1"
@@ -23,6 +26,7 @@ import (
 	"github.com/argoproj/argo-rollouts/utils/annotations"
 	"github.com/argoproj/argo-rollouts/utils/conditions"
 	logutil "github.com/argoproj/argo-rollouts/utils/log"
+	"github.com/argoproj/argo-rollouts/utils/policy"
 	"github.com/argoproj/argo-rollouts/utils/record"
 	timeutil "github.com/argoproj/argo-rollouts/utils/time"
 
@@ -406,6 +410,47 @@ func TestBlueGreenPromoteFull(t *testing.T) {
 	assert.False(t, patchedRollout.Status.PromoteFull)
 }
 
+// TestBlueGreenPromoteFullDeniedByPolicy verifies the active service is not switched while the progression policies
+// deny the promotion
+func TestBlueGreenPromoteFullDeniedByPolicy(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+	t.Cleanup(func() { policy.SetPolicies(nil) })
+	policy.SetPolicies([]policy.Policy{{Name: "freeze", Expression: `false`, Message: "Deployments are frozen"}})
+
+	r1 := newBlueGreenRollout("foo", 10, nil, "active", "preview")
+	rs1 := newReplicaSetWithStatus(r1, 10, 10)
+	rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	r1.Status.StableRS = rs1PodHash
+	r1.Status.BlueGreen.ActiveSelector = rs1PodHash
+	r1.Status.BlueGreen.PreviewSelector = rs1PodHash
+	activeSvc := newService("active", 80, map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: rs1PodHash}, r1)
+
+	r2 := bumpVersion(r1)
+	r2.Status.PromoteFull = true
+	rs2 := newReplicaSetWithStatus(r2, 10, 10)
+	rs2PodHash := rs2.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	r2.Status.BlueGreen.PreviewSelector = rs2PodHash
+	previewSvc := newService("preview", 80, map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: rs2PodHash}, r2)
+
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.objects = append(f.objects, r2)
+	f.serviceLister = append(f.serviceLister, activeSvc, previewSvc)
+	f.kubeobjects = append(f.kubeobjects, rs1, rs2, previewSvc, activeSvc)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+
+	patchRolloutIdx := f.expectPatchRolloutAction(r2) // record the denial, without updating the active service
+	f.run(getKey(r2, t))
+
+	// the patch neither changes the stable ReplicaSet nor the full promotion, which stays requested
+	assert.NotContains(t, f.getPatchedRollout(patchRolloutIdx), "stableRS")
+	assert.NotContains(t, f.getPatchedRollout(patchRolloutIdx), "promoteFull")
+	patchedRollout := f.getPatchedRolloutAsObject(patchRolloutIdx)
+	cond := conditions.GetRolloutCondition(patchedRollout.Status, v1alpha1.RolloutProgressionDenied)
+	require.NotNil(t, cond)
+	assert.Equal(t, "Full promotion denied: Deployments are frozen", cond.Message)
+}
+
 // TestSendStateChangeEvents verifies we emit appropriate events on rollout state changes
 func TestSendStateChangeEvents(t *testing.T) {
 	now := timeutil.MetaNow()
@@ -878,3 +923,114 @@ func TestIsScalingEventMissMatchedDesiredOldReplicas(t *testing.T) {
 	assert.Equal(t, int32(13), roStatus.Status.ReadyReplicas)
 	assert.Equal(t, int32(0), roStatus.Status.UpdatedReplicas)
 }
+
+func TestStepProgressDeadline(t *testing.T) {
+	newStepProgressDeadlineContext := func(action v1alpha1.StepProgressDeadlineAction) *rolloutContext {
+		steps := []v1alpha1.CanaryStep{{
+			Analysis: &v1alpha1.RolloutAnalysis{
+				Templates: []v1alpha1.AnalysisTemplateRef{{TemplateName: "slow"}},
+			},
+			ProgressDeadline: &v1alpha1.StepProgressDeadline{Seconds: 30, Action: action},
+		}}
+		ro := newCanaryRollout("foo", 1, nil, steps, ptr.To[int32](0), intstr.FromInt(1), intstr.FromInt(0))
+		ro.Status.StableRS = "stable"
+		ro.Status.CurrentPodHash = "canary"
+		progressingCond := conditions.NewRolloutCondition(v1alpha1.RolloutProgressing, corev1.ConditionTrue, conditions.ReplicaSetUpdatedReason, "")
+		progressingCond.LastUpdateTime = metav1.NewTime(timeutil.Now().Add(-time.Minute))
+		conditions.RemoveRolloutCondition(&ro.Status, v1alpha1.RolloutProgressing)
+		conditions.SetRolloutCondition(&ro.Status, *progressingCond)
+		logCtx := logutil.WithRollout(ro)
+		return &rolloutContext{
+			rollout:      ro,
+			log:          logCtx,
+			pauseContext: &pauseContext{rollout: ro, log: logCtx},
+			reconcilerBase: reconcilerBase{
+				recorder: record.NewFakeEventRecorder(),
+			},
+		}
+	}
+
+	t.Run("Pause", func(t *testing.T) {
+		roCtx := newStepProgressDeadlineContext(v1alpha1.StepProgressDeadlineActionPause)
+		assert.False(t, isIndefiniteStep(roCtx.rollout))
+		assert.True(t, conditions.RolloutTimedOut(roCtx.rollout, &roCtx.rollout.Status))
+
+		newStatus := roCtx.rollout.Status.DeepCopy()
+		roCtx.evaluateProgressDeadlineAbort(newStatus)
+		assert.False(t, roCtx.pauseContext.IsAborted())
+		assert.Equal(t, []v1alpha1.PauseReason{v1alpha1.PauseReasonStepProgressDeadline}, roCtx.pauseContext.addPauseReasons)
+	})
+
+	t.Run("Abort", func(t *testing.T) {
+		roCtx := newStepProgressDeadlineContext(v1alpha1.StepProgressDeadlineActionAbort)
+		newStatus := roCtx.rollout.Status.DeepCopy()
+		roCtx.evaluateProgressDeadlineAbort(newStatus)
+		assert.True(t, roCtx.pauseContext.IsAborted())
+	})
+
+	t.Run("NoAction", func(t *testing.T) {
+		roCtx := newStepProgressDeadlineContext("")
+		newStatus := roCtx.rollout.Status.DeepCopy()
+		roCtx.evaluateProgressDeadlineAbort(newStatus)
+		assert.False(t, roCtx.pauseContext.IsAborted())
+		assert.False(t, roCtx.pauseContext.HasAddPause())
+	})
+
+	t.Run("NotTimedOut", func(t *testing.T) {
+		roCtx := newStepProgressDeadlineContext(v1alpha1.StepProgressDeadlineActionPause)
+		roCtx.rollout.Spec.Strategy.Canary.Steps[0].ProgressDeadline.Seconds = 600
+		assert.False(t, conditions.RolloutTimedOut(roCtx.rollout, &roCtx.rollout.Status))
+	})
+
+	t.Run("MeasuredFromStepStart", func(t *testing.T) {
+		roCtx := newStepProgressDeadlineContext(v1alpha1.StepProgressDeadlineActionPause)
+		// another update of the rollout reset the Progressing condition
+		progressingCond := conditions.GetRolloutCondition(roCtx.rollout.Status, v1alpha1.RolloutProgressing)
+		progressingCond.LastUpdateTime = timeutil.MetaNow()
+		roCtx.rollout.Status.Canary.CurrentStepStartedAt = &metav1.Time{Time: timeutil.Now().Add(-time.Minute)}
+		assert.True(t, conditions.RolloutTimedOut(roCtx.rollout, &roCtx.rollout.Status))
+
+		// the step times out even though the rollout is making progress in this reconciliation
+		newStatus := roCtx.rollout.Status.DeepCopy()
+		newStatus.AvailableReplicas++
+		roCtx.calculateCurrentStepStartedAt(newStatus)
+		assert.Equal(t, roCtx.rollout.Status.Canary.CurrentStepStartedAt, newStatus.Canary.CurrentStepStartedAt)
+		roCtx.evaluateProgressDeadlineAbort(newStatus)
+		assert.Equal(t, []v1alpha1.PauseReason{v1alpha1.PauseReasonStepProgressDeadline}, roCtx.pauseContext.addPauseReasons)
+	})
+
+	t.Run("StepStartedAt", func(t *testing.T) {
+		roCtx := newStepProgressDeadlineContext(v1alpha1.StepProgressDeadlineActionPause)
+		roCtx.rollout.Spec.Strategy.Canary.Steps = append(roCtx.rollout.Spec.Strategy.Canary.Steps, v1alpha1.CanaryStep{SetWeight: ptr.To[int32](50)})
+
+		// the start of a step with a progress deadline is recorded
+		newStatus := roCtx.rollout.Status.DeepCopy()
+		roCtx.calculateCurrentStepStartedAt(newStatus)
+		assert.NotNil(t, newStatus.Canary.CurrentStepStartedAt)
+
+		// the start is reset for a new update
+		startedAt := metav1.NewTime(timeutil.Now().Add(-time.Hour))
+		roCtx.rollout.Status.Canary.CurrentStepStartedAt = &startedAt
+		newStatus = roCtx.rollout.Status.DeepCopy()
+		newStatus.CurrentPodHash = "new-canary"
+		roCtx.calculateCurrentStepStartedAt(newStatus)
+		assert.True(t, newStatus.Canary.CurrentStepStartedAt.After(startedAt.Time))
+
+		// and cleared at a step without a progress deadline
+		newStatus = roCtx.rollout.Status.DeepCopy()
+		newStatus.CurrentStepIndex = ptr.To[int32](1)
+		roCtx.calculateCurrentStepStartedAt(newStatus)
+		assert.Nil(t, newStatus.Canary.CurrentStepStartedAt)
+	})
+}
+
+func TestLastNotificationDelivery(t *testing.T) {
+	now := timeutil.Now()
+	delivered := record.NotificationDelivery{Time: now, Destination: services.Destination{Service: "slack", Recipient: "deploys"}}
+	failed := record.NotificationDelivery{Time: now.Add(-time.Minute), Destination: services.Destination{Service: "webhook", Recipient: "bus"}, Error: errors.New("timeout")}
+
+	assert.Nil(t, lastNotificationDelivery(nil))
+	assert.Equal(t, &delivered, lastNotificationDelivery([]record.NotificationDelivery{delivered}))
+	// a failed destination is reported even when another destination was delivered to afterwards
+	assert.Equal(t, &failed, lastNotificationDelivery([]record.NotificationDelivery{delivered, failed}))
+}
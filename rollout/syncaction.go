@@ -0,0 +1,84 @@
+package rollout
+
+import (
+	"encoding/json"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/annotations"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+	"github.com/argoproj/argo-rollouts/utils/record"
+	rolloututil "github.com/argoproj/argo-rollouts/utils/rollout"
+	timeutil "github.com/argoproj/argo-rollouts/utils/time"
+)
+
+// reconcileSyncAction performs the action requested by the sync-action annotation of the rollout, so that a GitOps
+// tool such as Argo CD can retry or fully promote an update when it syncs the rollout. Sync actions are only performed
+// when enabled with the --sync-actions flag of the controller. An annotation value is only handled once, and is
+// recorded in the status of the rollout along with the user who set it.
+func (c *rolloutContext) reconcileSyncAction() error {
+	if !defaults.GetSyncActionsEnabled() {
+		return nil
+	}
+	value, ok := c.rollout.Annotations[annotations.SyncActionAnnotation]
+	if !ok || value == c.rollout.Status.SyncAction {
+		return nil
+	}
+	newStatus := c.rollout.Status.DeepCopy()
+	newStatus.SyncAction = value
+	user := syncActionUser(c.rollout)
+	action, _, _ := strings.Cut(value, ":")
+	switch action {
+	case annotations.SyncActionRetry:
+		if c.rollout.Status.Abort {
+			newStatus.Abort = false
+			newStatus.LastUserAction = &v1alpha1.RolloutUserAction{Action: v1alpha1.RolloutUserActionRetry, User: user, Time: timeutil.MetaNow()}
+			c.recorder.Eventf(c.rollout, record.EventOptions{EventReason: "SyncActionRetry"}, "Retrying the update as requested by the sync action '%s' of '%s'", value, user)
+		}
+	case annotations.SyncActionPromoteFull:
+		if !rolloututil.IsFullyPromoted(c.rollout) {
+			newStatus.PromoteFull = true
+			newStatus.LastUserAction = &v1alpha1.RolloutUserAction{Action: v1alpha1.RolloutUserActionPromoteFull, User: user, Time: timeutil.MetaNow()}
+			c.recorder.Eventf(c.rollout, record.EventOptions{EventReason: "SyncActionPromoteFull"}, "Fully promoting the update as requested by the sync action '%s' of '%s'", value, user)
+		}
+	default:
+		c.recorder.Warnf(c.rollout, record.EventOptions{EventReason: "InvalidSyncAction"}, "Ignored the sync action '%s': the action must be one of %s, %s", value, annotations.SyncActionRetry, annotations.SyncActionPromoteFull)
+	}
+	return c.patchCondition(c.rollout, newStatus)
+}
+
+// syncActionUser returns the user who set the sync-action annotation of a rollout, as recorded by the mutating
+// admission webhook. Without the webhook, it falls back to the field manager which last set the annotation.
+func syncActionUser(ro *v1alpha1.Rollout) string {
+	if user, ok := ro.Annotations[annotations.SyncActionUserAnnotation]; ok {
+		return user
+	}
+	var manager string
+	var lastTime *metav1.Time
+	for _, entry := range ro.ManagedFields {
+		if entry.FieldsV1 == nil || !managesSyncAction(entry.FieldsV1.Raw) {
+			continue
+		}
+		if lastTime == nil || (entry.Time != nil && lastTime.Before(entry.Time)) {
+			manager = entry.Manager
+			lastTime = entry.Time
+		}
+	}
+	return manager
+}
+
+// managesSyncAction returns whether managed fields include the sync-action annotation
+func managesSyncAction(raw []byte) bool {
+	var fields struct {
+		Metadata struct {
+			Annotations map[string]json.RawMessage `json:"f:annotations"`
+		} `json:"f:metadata"`
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return false
+	}
+	_, ok := fields.Metadata.Annotations["f:"+annotations.SyncActionAnnotation]
+	return ok
+}
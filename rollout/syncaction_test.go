@@ -0,0 +1,111 @@
+package rollout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned/fake"
+	"github.com/argoproj/argo-rollouts/utils/annotations"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+	logutil "github.com/argoproj/argo-rollouts/utils/log"
+	"github.com/argoproj/argo-rollouts/utils/record"
+)
+
+func newSyncActionContext(ro *v1alpha1.Rollout) (*rolloutContext, *record.FakeEventRecorder) {
+	recorder := record.NewFakeEventRecorder()
+	return &rolloutContext{
+		rollout: ro,
+		log:     logutil.WithRollout(ro),
+		reconcilerBase: reconcilerBase{
+			argoprojclientset: fake.NewSimpleClientset(ro),
+			recorder:          recorder,
+		},
+	}, recorder
+}
+
+func TestReconcileSyncActionRetry(t *testing.T) {
+	defaults.SetSyncActionsEnabled(true)
+	defer defaults.SetSyncActionsEnabled(false)
+	ro := newCanaryRollout("foo", 5, nil, nil, ptr.To[int32](0), intstr.FromInt(1), intstr.FromInt(0))
+	ro.Annotations = map[string]string{annotations.SyncActionAnnotation: "retry:3f2a1c", annotations.SyncActionUserAnnotation: "alice"}
+	ro.Status.Abort = true
+	ctx, recorder := newSyncActionContext(ro)
+
+	require.NoError(t, ctx.reconcileSyncAction())
+	require.NotNil(t, ctx.newRollout)
+	assert.False(t, ctx.newRollout.Status.Abort)
+	assert.Equal(t, "retry:3f2a1c", ctx.newRollout.Status.SyncAction)
+	require.NotNil(t, ctx.newRollout.Status.LastUserAction)
+	assert.Equal(t, v1alpha1.RolloutUserActionRetry, ctx.newRollout.Status.LastUserAction.Action)
+	assert.Equal(t, "alice", ctx.newRollout.Status.LastUserAction.User)
+	assert.Equal(t, []string{"SyncActionRetry"}, recorder.Events())
+
+	// the same annotation value is only handled once
+	ctx, recorder = newSyncActionContext(ctx.newRollout)
+	ctx.rollout.Status.Abort = true
+	require.NoError(t, ctx.reconcileSyncAction())
+	assert.Nil(t, ctx.newRollout)
+	assert.Empty(t, recorder.Events())
+}
+
+func TestReconcileSyncActionPromoteFull(t *testing.T) {
+	defaults.SetSyncActionsEnabled(true)
+	defer defaults.SetSyncActionsEnabled(false)
+	ro := newCanaryRollout("foo", 5, nil, nil, ptr.To[int32](0), intstr.FromInt(1), intstr.FromInt(0))
+	ro.Annotations = map[string]string{annotations.SyncActionAnnotation: "promote-full"}
+	ro.Status.CurrentPodHash = "abc123"
+	ro.Status.StableRS = "def456"
+	ctx, recorder := newSyncActionContext(ro)
+
+	require.NoError(t, ctx.reconcileSyncAction())
+	require.NotNil(t, ctx.newRollout)
+	assert.True(t, ctx.newRollout.Status.PromoteFull)
+	assert.Equal(t, []string{"SyncActionPromoteFull"}, recorder.Events())
+}
+
+func TestReconcileSyncActionInvalid(t *testing.T) {
+	defaults.SetSyncActionsEnabled(true)
+	defer defaults.SetSyncActionsEnabled(false)
+	ro := newCanaryRollout("foo", 5, nil, nil, ptr.To[int32](0), intstr.FromInt(1), intstr.FromInt(0))
+	ro.Annotations = map[string]string{annotations.SyncActionAnnotation: "rollback"}
+	ctx, recorder := newSyncActionContext(ro)
+
+	require.NoError(t, ctx.reconcileSyncAction())
+	require.NotNil(t, ctx.newRollout)
+	assert.Equal(t, "rollback", ctx.newRollout.Status.SyncAction)
+	assert.False(t, ctx.newRollout.Status.PromoteFull)
+	assert.Equal(t, []string{"InvalidSyncAction"}, recorder.Events())
+}
+
+func TestReconcileSyncActionDisabled(t *testing.T) {
+	ro := newCanaryRollout("foo", 5, nil, nil, ptr.To[int32](0), intstr.FromInt(1), intstr.FromInt(0))
+	ro.Annotations = map[string]string{annotations.SyncActionAnnotation: "retry"}
+	ro.Status.Abort = true
+	ctx, recorder := newSyncActionContext(ro)
+
+	require.NoError(t, ctx.reconcileSyncAction())
+	assert.Nil(t, ctx.newRollout)
+	assert.Empty(t, recorder.Events())
+}
+
+func TestSyncActionUserFromManagedFields(t *testing.T) {
+	ro := newCanaryRollout("foo", 5, nil, nil, ptr.To[int32](0), intstr.FromInt(1), intstr.FromInt(0))
+	earlier := metav1.NewTime(time.Now().Add(-time.Hour))
+	later := metav1.NewTime(time.Now())
+	ro.ManagedFields = []metav1.ManagedFieldsEntry{
+		{Manager: "kubectl-edit", Time: &later, FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:replicas":{}}}`)}},
+		{Manager: "kubectl-annotate", Time: &earlier, FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:annotations":{"f:rollout.argoproj.io/sync-action":{}}}}`)}},
+		{Manager: "argocd-controller", Time: &later, FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:annotations":{".":{},"f:rollout.argoproj.io/sync-action":{}}}}`)}},
+	}
+	assert.Equal(t, "argocd-controller", syncActionUser(ro))
+
+	ro.Annotations = map[string]string{annotations.SyncActionUserAnnotation: "alice"}
+	assert.Equal(t, "alice", syncActionUser(ro))
+}
@@ -3,6 +3,7 @@ package rollout
 import (
 	"fmt"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -21,18 +22,24 @@ import (
 	a6 "github.com/argoproj/argo-rollouts/rollout/trafficrouting/apisix"
 	"github.com/argoproj/argo-rollouts/rollout/trafficrouting/appmesh"
 	"github.com/argoproj/argo-rollouts/rollout/trafficrouting/istio"
+	"github.com/argoproj/argo-rollouts/rollout/trafficrouting/knative"
 	"github.com/argoproj/argo-rollouts/rollout/trafficrouting/nginx"
 	"github.com/argoproj/argo-rollouts/rollout/trafficrouting/smi"
 	"github.com/argoproj/argo-rollouts/rollout/trafficrouting/traefik"
 	a6util "github.com/argoproj/argo-rollouts/utils/apisix"
 	"github.com/argoproj/argo-rollouts/utils/conditions"
 	"github.com/argoproj/argo-rollouts/utils/defaults"
+	experimentutil "github.com/argoproj/argo-rollouts/utils/experiment"
 	"github.com/argoproj/argo-rollouts/utils/record"
 	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
 	rolloututil "github.com/argoproj/argo-rollouts/utils/rollout"
+	timeutil "github.com/argoproj/argo-rollouts/utils/time"
 	"github.com/argoproj/argo-rollouts/utils/weightutil"
 )
 
+// maxWeightHistory is the number of changes of the traffic weight kept in the status of a rollout
+const maxWeightHistory = 30
+
 // NewTrafficRoutingReconciler identifies return the TrafficRouting Plugin that the rollout wants to modify
 func (c *Controller) NewTrafficRoutingReconciler(roCtx *rolloutContext) ([]trafficrouting.TrafficRoutingReconciler, error) {
 	rollout := roCtx.rollout
@@ -79,6 +86,7 @@ func (c *Controller) NewTrafficRoutingReconciler(roCtx *rolloutContext) ([]traff
 			Client:         c.smiclientset,
 			Recorder:       c.recorder,
 			ControllerKind: controllerKind,
+			DynamicClient:  c.dynamicclientset,
 		})
 		if err != nil {
 			return trafficReconcilers, err
@@ -114,6 +122,15 @@ func (c *Controller) NewTrafficRoutingReconciler(roCtx *rolloutContext) ([]traff
 		}))
 	}
 
+	if rollout.Spec.Strategy.Canary.TrafficRouting.Knative != nil {
+		trafficReconcilers = append(trafficReconcilers, knative.NewReconciler(&knative.ReconcilerConfig{
+			Rollout:  rollout,
+			Client:   knative.NewDynamicClient(c.dynamicclientset, rollout.GetNamespace()),
+			Recorder: c.recorder,
+			CanaryRS: roCtx.newRS,
+		}))
+	}
+
 	if rollout.Spec.Strategy.Canary.TrafficRouting.Plugins != nil {
 		for pluginName := range rollout.Spec.Strategy.Canary.TrafficRouting.Plugins {
 			pluginReconciler, err := plugin.NewReconciler(&plugin.ReconcilerConfig{
@@ -157,6 +174,19 @@ func (c *rolloutContext) checkReplicasAvailable(rs *appsv1.ReplicaSet, desiredWe
 
 }
 
+// newRSServesTraffic returns whether the new ReplicaSet has available pods which traffic can be routed to. With
+// Knative, the ReplicaSets have no pods, and the revision of the pod template of the new ReplicaSet serves the
+// traffic once Knative reports it as ready.
+func (c *rolloutContext) newRSServesTraffic() bool {
+	if c.newRS == nil {
+		return false
+	}
+	if c.rollout.Spec.Strategy.Canary.TrafficRouting.Knative != nil {
+		return true
+	}
+	return c.newRS.Status.AvailableReplicas > 0
+}
+
 // this currently only be used in the canary strategy
 func (c *rolloutContext) reconcileTrafficRouting() error {
 	reconcilers, err := c.newTrafficRoutingReconciler(c)
@@ -219,7 +249,7 @@ func (c *rolloutContext) reconcileTrafficRouting() error {
 				return err
 			}
 
-		} else if c.newRS == nil || c.newRS.Status.AvailableReplicas == 0 {
+		} else if !c.newRSServesTraffic() {
 			// when newRS is not available or replicas num is 0. never weight to canary
 			weightDestinations = append(weightDestinations, c.calculateWeightDestinationsFromExperiment()...)
 			// If a user changes their mind in the middle of an V1 -> V2 update, and then applies a V3
@@ -266,6 +296,10 @@ func (c *rolloutContext) reconcileTrafficRouting() error {
 			} else {
 				desiredWeight = weightutil.MaxTrafficWeight(c.rollout)
 			}
+			if weightOverride := weightOverrideOfStep(c.rollout, index); weightOverride != nil {
+				// a manually overridden weight replaces the one of the steps until the rollout moves to another step
+				desiredWeight = min(weightOverride.Weight, weightutil.MaxTrafficWeight(c.rollout))
+			}
 		}
 
 		// check if the stable RS has enough pods before recalculating the new
@@ -287,6 +321,9 @@ func (c *rolloutContext) reconcileTrafficRouting() error {
 					return err
 				}
 			}
+			if err = c.reconcileExperimentHeaderRoutes(reconciler); err != nil {
+				return err
+			}
 		}
 
 		// If there was a previous canary weight > 0 and the new canary has no available
@@ -295,7 +332,7 @@ func (c *rolloutContext) reconcileTrafficRouting() error {
 		// old weight is still in effect, routing traffic to non-existent pods.
 		// This runs after checkReplicasAvailable so we only reset when stable can handle
 		// the full traffic load.
-		if (c.newRS == nil || c.newRS.Status.AvailableReplicas == 0) &&
+		if !c.newRSServesTraffic() &&
 			c.rollout.Status.Canary.Weights != nil && c.rollout.Status.Canary.Weights.Canary.Weight > 0 {
 			if err := reconciler.SetWeight(desiredWeight, weightDestinations...); err != nil {
 				c.recorder.Warnf(c.rollout, record.EventOptions{EventReason: "TrafficRoutingError"}, err.Error())
@@ -314,7 +351,8 @@ func (c *rolloutContext) reconcileTrafficRouting() error {
 			return err
 		}
 
-		if modified, newWeights := calculateWeightStatus(c.rollout, canaryHash, stableHash, desiredWeight, weightDestinations...); modified {
+		modified, newWeights := calculateWeightStatus(c.rollout, canaryHash, stableHash, desiredWeight, weightDestinations...)
+		if modified {
 			c.log.Infof("Previous weights: %v", c.rollout.Status.Canary.Weights)
 			c.log.Infof("New weights: %v", newWeights)
 			c.recorder.Eventf(c.rollout, record.EventOptions{EventReason: conditions.TrafficWeightUpdatedReason}, trafficWeightUpdatedMessage(c.rollout.Status.Canary.Weights, newWeights))
@@ -323,6 +361,10 @@ func (c *rolloutContext) reconcileTrafficRouting() error {
 
 		weightVerified, err := reconciler.VerifyWeight(desiredWeight, weightDestinations...)
 		c.newStatus.Canary.Weights.Verified = weightVerified
+		if prevWeights := c.rollout.Status.Canary.Weights; modified || prevWeights == nil || !reflect.DeepEqual(prevWeights.Verified, weightVerified) {
+			c.recordWeightHistory(reconciler.Type(), desiredWeight, weightVerified)
+		}
+		c.verifyRouterTargets(reconciler, weightVerified, err)
 		if err != nil {
 			c.recorder.Warnf(c.rollout, record.EventOptions{EventReason: conditions.WeightVerifyErrorReason}, conditions.WeightVerifyErrorMessage, err)
 			return nil // return nil instead of error since we want to continue with normal reconciliation
@@ -338,11 +380,19 @@ func (c *rolloutContext) reconcileTrafficRouting() error {
 		if weightVerified != nil {
 			if *weightVerified {
 				c.log.Infof("Desired weight (stepIdx: %s) %d verified", indexString, desiredWeight)
+				if prevWeights := c.rollout.Status.Canary.Weights; modified || prevWeights == nil || prevWeights.Verified == nil || !*prevWeights.Verified {
+					c.recorder.Eventf(c.rollout, record.EventOptions{EventReason: conditions.WeightVerifiedReason}, conditions.WeightVerifiedMessage, desiredWeight, reconciler.Type())
+				}
 			} else {
 				c.log.Infof("Desired weight (stepIdx: %s) %d not yet verified", indexString, desiredWeight)
-				logCtx := logutil.WithRollout(c.rollout)
-				logCtx.Info("rollout enqueue due to trafficrouting")
-				c.enqueueRolloutAfter(c.rollout, defaults.GetRolloutVerifyRetryInterval())
+				if watcher, ok := reconciler.(trafficrouting.WatchedWeightVerifier); ok && watcher.WeightVerificationWatched() {
+					// the rollout is enqueued once the status of the traffic router changes
+					c.log.Info("Waiting for the status of the traffic router to verify the weight")
+				} else {
+					logCtx := logutil.WithRollout(c.rollout)
+					logCtx.Info("rollout enqueue due to trafficrouting")
+					c.enqueueRolloutAfter(c.rollout, defaults.GetRolloutVerifyRetryInterval())
+				}
 				// At the end of the rollout we need to verify the weight is correct, and return an error if not because we don't want the rest of the
 				// reconcile process to continue. We don't need to do this if we are in the middle of the rollout because the rest of the reconcile
 				// process won't scale down the old replicasets yet due to being in the middle of some steps.
@@ -355,6 +405,59 @@ func (c *rolloutContext) reconcileTrafficRouting() error {
 	return nil
 }
 
+// verifyRouterTargets records in targetsVerified whether the traffic router of a reconciler which reports its health,
+// such as a plugin, routes the traffic of a fully promoted rollout to the stable pods, so that the old ReplicaSets are
+// not scaled down before. The other traffic routers are verified by the target group verification of ALB, if at all.
+func (c *rolloutContext) verifyRouterTargets(reconciler trafficrouting.TrafficRoutingReconciler, weightVerified *bool, verifyErr error) {
+	checker, ok := reconciler.(trafficrouting.HealthChecker)
+	if !ok || !rolloututil.IsFullyPromoted(c.rollout) {
+		return
+	}
+	verified := false
+	switch {
+	case verifyErr != nil:
+		c.enqueueRolloutAfter(c.rollout, defaults.GetRolloutVerifyRetryInterval())
+	case weightVerified != nil && !*weightVerified:
+		// the rollout is enqueued until the weight is verified
+	default:
+		if err := checker.Healthz(); err != nil {
+			c.recorder.Warnf(c.rollout, record.EventOptions{EventReason: conditions.TrafficRouterUnhealthyReason}, conditions.TrafficRouterUnhealthyMessage, reconciler.Type(), err)
+			c.enqueueRolloutAfter(c.rollout, defaults.GetRolloutVerifyRetryInterval())
+		} else {
+			verified = true
+		}
+	}
+	if c.areTargetsVerified() {
+		c.targetsVerified = &verified
+	}
+}
+
+// recordWeightHistory records the weight set on a traffic router in the weight history of the rollout, when the weight
+// or its verification changed since the last weight recorded for the router
+func (c *rolloutContext) recordWeightHistory(router string, desiredWeight int32, verified *bool) {
+	history := c.newStatus.Canary.WeightHistory
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Router != router {
+			continue
+		}
+		if history[i].DesiredWeight == desiredWeight && reflect.DeepEqual(history[i].Verified, verified) {
+			return
+		}
+		break
+	}
+	// the history is cloned since it is shared with the status of the rollout
+	history = append(slices.Clone(history), v1alpha1.TrafficWeightRecord{
+		Time:          timeutil.MetaNow(),
+		Router:        router,
+		DesiredWeight: desiredWeight,
+		Verified:      verified,
+	})
+	if len(history) > maxWeightHistory {
+		history = history[len(history)-maxWeightHistory:]
+	}
+	c.newStatus.Canary.WeightHistory = history
+}
+
 // calculateDesiredWeightOnAbortOrStableRollback returns the desired weight to use when we are either
 // aborting, or rolling back to stable RS.
 func (c *rolloutContext) calculateDesiredWeightOnAbortOrStableRollback() int32 {
@@ -462,3 +565,72 @@ func (c *rolloutContext) calculateWeightDestinationsFromExperiment() []v1alpha1.
 	}
 	return weightDestinations
 }
+
+// reconcileExperimentHeaderRoutes routes the requests matching the header routes of the templates of the running
+// experiment to the services of the templates, and removes the header routes of the templates of all other experiment
+// steps. The routes are removed as soon as the experiment terminates or passes its duration, before the experiment
+// scales down the templates and deletes their services. Only applies to the traffic routing reconcilers which support
+// it.
+func (c *rolloutContext) reconcileExperimentHeaderRoutes(reconciler trafficrouting.TrafficRoutingReconciler) error {
+	router, ok := reconciler.(trafficrouting.ExperimentHeaderRouter)
+	if !ok {
+		return nil
+	}
+	destinations := map[string]v1alpha1.WeightDestination{}
+	exStep := replicasetutil.GetCurrentExperimentStep(c.rollout)
+	if exStep != nil && c.currentEx != nil && experimentRoutable(c.currentEx) {
+		for _, templateStatus := range c.currentEx.Status.TemplateStatuses {
+			if templateStatus.ServiceName == "" || templateStatus.Status.Completed() {
+				continue
+			}
+			for _, tmpl := range exStep.Templates {
+				if tmpl.Name == templateStatus.Name && tmpl.HeaderRoute != nil {
+					destinations[tmpl.HeaderRoute.Name] = v1alpha1.WeightDestination{
+						ServiceName:     templateStatus.ServiceName,
+						PodTemplateHash: templateStatus.PodTemplateHash,
+						Weight:          weightutil.MaxTrafficWeight(c.rollout),
+					}
+				}
+			}
+		}
+	}
+	reconciled := map[string]bool{}
+	for _, step := range c.rollout.Spec.Strategy.Canary.Steps {
+		if step.Experiment == nil {
+			continue
+		}
+		for _, tmpl := range step.Experiment.Templates {
+			if tmpl.HeaderRoute == nil || reconciled[tmpl.HeaderRoute.Name] {
+				continue
+			}
+			reconciled[tmpl.HeaderRoute.Name] = true
+			var destination *v1alpha1.WeightDestination
+			if dest, ok := destinations[tmpl.HeaderRoute.Name]; ok {
+				destination = &dest
+			}
+			if err := router.SetExperimentHeaderRoute(tmpl.HeaderRoute, destination); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// experimentRoutable returns true if the experiment is running and neither terminating nor past its duration, which
+// would scale down its templates
+func experimentRoutable(ex *v1alpha1.Experiment) bool {
+	if ex.Status.Phase != v1alpha1.AnalysisPhaseRunning || experimentutil.IsTerminating(ex) {
+		return false
+	}
+	passedDuration, _ := experimentutil.PassedDurations(ex)
+	return !passedDuration
+}
+
+// weightOverrideOfStep returns the canary weight override of the given step. Overrides of other steps are ignored.
+func weightOverrideOfStep(ro *v1alpha1.Rollout, stepIndex *int32) *v1alpha1.WeightOverride {
+	weightOverride := ro.Status.Canary.WeightOverride
+	if weightOverride == nil || stepIndex == nil || weightOverride.StepIndex != *stepIndex {
+		return nil
+	}
+	return weightOverride
+}
@@ -57,6 +57,13 @@ func (f *FakeRecorder) K8sRecorder() record.EventRecorder {
 	return nil
 }
 
+func (f *FakeRecorder) LastNotificationDeliveries(object runtime.Object) []argoRecord.NotificationDelivery {
+	return nil
+}
+
+func (f *FakeRecorder) ForgetNotificationDeliveries(object runtime.Object) {
+}
+
 func (f *FakeClient) Create(ctx context.Context, obj *unstructured.Unstructured, options metav1.CreateOptions, subresources ...string) (*unstructured.Unstructured, error) {
 	if f.IsCreateError {
 		return nil, errors.New("create apisix route error!")
@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/mitchellh/mapstructure"
@@ -21,8 +23,10 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/dynamic/dynamiclister"
+	"k8s.io/utils/ptr"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
 	evalUtils "github.com/argoproj/argo-rollouts/utils/evaluate"
 	istioutil "github.com/argoproj/argo-rollouts/utils/istio"
 	logutil "github.com/argoproj/argo-rollouts/utils/log"
@@ -59,6 +63,8 @@ type Reconciler struct {
 	virtualServiceLister  dynamiclister.Lister
 	destinationRuleLister dynamiclister.Lister
 	replicaSets           []*appsv1.ReplicaSet
+	// weightVerificationWatched is whether the last weight verification was decided by the status of the VirtualServices
+	weightVerificationWatched bool
 }
 
 type virtualServicePatch struct {
@@ -859,6 +865,54 @@ func (r *Reconciler) SetHeaderRoute(headerRouting *v1alpha1.SetHeaderRoute) erro
 	return nil
 }
 
+// SetExperimentHeaderRoute routes the requests matching the header route to the service of an experiment template, or
+// removes the route if the destination is nil. The VirtualServices are only updated if the route changes.
+func (r *Reconciler) SetExperimentHeaderRoute(headerRouting *v1alpha1.SetHeaderRoute, destination *v1alpha1.WeightDestination) error {
+	ctx := context.TODO()
+	for _, virtualService := range r.getVirtualServices() {
+		namespace, vsvcName := istioutil.GetVirtualServiceNamespaceName(virtualService.Name)
+		if namespace == "" {
+			namespace = r.rollout.Namespace
+		}
+
+		client := r.client.Resource(istioutil.GetIstioVirtualServiceGVR()).Namespace(namespace)
+		vsvc, err := r.getVirtualService(namespace, vsvcName, client, ctx)
+		if err != nil {
+			return fmt.Errorf("[SetExperimentHeaderRoute] failed to get istio virtual service: %w", err)
+		}
+		newVsvc := vsvc.DeepCopy()
+		if err := removeRoute(newVsvc, headerRouting.Name); err != nil {
+			return fmt.Errorf("[SetExperimentHeaderRoute] failed to remove http route from virtual service: %w", err)
+		}
+		if destination != nil && len(headerRouting.Match) > 0 {
+			httpRoutesI, err := GetHttpRoutesI(newVsvc)
+			if err != nil {
+				return err
+			}
+			httpRoutesI = append(httpRoutesI, createHeaderRoute(virtualService, newVsvc, headerRouting, destination.ServiceName, ""))
+			if err := unstructured.SetNestedSlice(newVsvc.Object, httpRoutesI, "spec", Http); err != nil {
+				return err
+			}
+		}
+		if err := r.orderRoutes(newVsvc); err != nil && err.Error() != SpecHttpNotFound {
+			return fmt.Errorf("[SetExperimentHeaderRoute] failed to order routes: %w", err)
+		}
+		if reflect.DeepEqual(vsvc.Object, newVsvc.Object) {
+			continue
+		}
+		if _, err := client.Update(ctx, newVsvc, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("[SetExperimentHeaderRoute] failed to update routes: %w", err)
+		}
+		r.log.Debugf("Updated VirtualService: %s", newVsvc)
+		if destination != nil {
+			r.recorder.Eventf(r.rollout, record.EventOptions{EventReason: "Updated VirtualService"}, "VirtualService `%s` set headerRoute '%v' to service '%s'", vsvcName, headerRouting.Name, destination.ServiceName)
+		} else {
+			r.recorder.Eventf(r.rollout, record.EventOptions{EventReason: "Updated VirtualService"}, "VirtualService `%s` removed headerRoute '%v'", vsvcName, headerRouting.Name)
+		}
+	}
+	return nil
+}
+
 func (r *Reconciler) getDestinationRuleHost() (string, error) {
 	if r.rollout.Spec.Strategy.Canary.TrafficRouting.Istio.DestinationRule != nil {
 		ctx := context.TODO()
@@ -947,8 +1001,68 @@ func routeDestination(host string, port uint32, subset string, weight int64) map
 	return routeValue
 }
 
+// VerifyWeight verifies that the desired weight was distributed to the proxies from the status istiod reports on the
+// VirtualServices, i.e. the Reconciled condition for the current generation. The VirtualServices are watched, so that
+// the rollout is enqueued once the status changes. Returns nil if the verification is not enabled with
+// --istio-verify-weight, or if istiod does not report the status of the VirtualServices, e.g. since config
+// distribution tracking is disabled.
 func (r *Reconciler) VerifyWeight(desiredWeight int32, additionalDestinations ...v1alpha1.WeightDestination) (*bool, error) {
-	return nil, nil
+	r.weightVerificationWatched = false
+	if !defaults.IstioVerifyWeight() || r.virtualServiceLister == nil {
+		return nil, nil
+	}
+	for _, virtualService := range r.getVirtualServices() {
+		namespace, vsvcName := istioutil.GetVirtualServiceNamespaceName(virtualService.Name)
+		if namespace == "" {
+			namespace = r.rollout.Namespace
+		}
+		vsvc, err := r.virtualServiceLister.Namespace(namespace).Get(vsvcName)
+		if err != nil {
+			return ptr.To[bool](false), err
+		}
+		reconciled, reported := virtualServiceReconciled(vsvc)
+		if !reported {
+			return nil, nil
+		}
+		// the cached VirtualService might not reflect the desired weight yet
+		_, modified, err := r.reconcileVirtualService(vsvc.DeepCopy(), virtualService.Routes, virtualService.TLSRoutes, virtualService.TCPRoutes, desiredWeight, additionalDestinations...)
+		if err != nil {
+			return ptr.To[bool](false), err
+		}
+		if modified || !reconciled {
+			r.log.Infof("VirtualService %s/%s not yet reconciled with desired weight %d", namespace, vsvcName, desiredWeight)
+			r.weightVerificationWatched = true
+			return ptr.To[bool](false), nil
+		}
+	}
+	r.weightVerificationWatched = true
+	return ptr.To[bool](true), nil
+}
+
+// WeightVerificationWatched returns whether the last weight verification was decided by the status of the
+// VirtualServices
+func (r *Reconciler) WeightVerificationWatched() bool {
+	return r.weightVerificationWatched
+}
+
+// virtualServiceReconciled returns whether istiod reported the current generation of the VirtualService as distributed
+// to the proxies, and whether istiod reports the distribution of the VirtualService at all
+func virtualServiceReconciled(vsvc *unstructured.Unstructured) (bool, bool) {
+	conditions, _, _ := unstructured.NestedSlice(vsvc.Object, "status", "conditions")
+	for _, condition := range conditions {
+		c, ok := condition.(map[string]any)
+		if !ok || c["type"] != "Reconciled" {
+			continue
+		}
+		if c["status"] != "True" {
+			return false, true
+		}
+		// istiod reports the observed generation as a string
+		observedGeneration, _, _ := unstructured.NestedFieldNoCopy(vsvc.Object, "status", "observedGeneration")
+		generation, err := strconv.ParseInt(fmt.Sprint(observedGeneration), 10, 64)
+		return err == nil && generation >= vsvc.GetGeneration(), true
+	}
+	return false, false
 }
 
 // getHttpRouteIndexesToPatch returns array indices of the httpRoutes which need to be patched when updating weights
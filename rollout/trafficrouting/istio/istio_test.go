@@ -23,6 +23,7 @@ import (
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	testutil "github.com/argoproj/argo-rollouts/test/util"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
 	evalUtils "github.com/argoproj/argo-rollouts/utils/evaluate"
 	istioutil "github.com/argoproj/argo-rollouts/utils/istio"
 	"github.com/argoproj/argo-rollouts/utils/record"
@@ -972,6 +973,51 @@ func TestHttpReconcileHeaderRouteHostBased(t *testing.T) {
 	assert.Equal(t, httpRoutes[1].Name, "secondary")
 }
 
+func TestSetExperimentHeaderRoute(t *testing.T) {
+	ro := rolloutWithHttpRoutes("stable", "canary", "vsvc", []string{"primary"})
+	obj := unstructuredutil.StrToUnstructuredUnsafe(regularVsvc)
+	client := testutil.NewFakeDynamicClient(obj)
+	r := NewReconciler(ro, client, record.NewFakeEventRecorder(), nil, nil, nil)
+	client.ClearActions()
+
+	const headerName = "test-experiment-header-route"
+	r.rollout.Spec.Strategy.Canary.TrafficRouting.ManagedRoutes = []v1alpha1.MangedRoutes{{Name: headerName}}
+	hr := &v1alpha1.SetHeaderRoute{
+		Name: headerName,
+		Match: []v1alpha1.HeaderRoutingMatch{{
+			HeaderName:  "agent",
+			HeaderValue: &v1alpha1.StringMatch{Exact: "firefox"},
+		}},
+	}
+	destination := &v1alpha1.WeightDestination{ServiceName: "experiment-svc", PodTemplateHash: "abc123", Weight: 100}
+
+	err := r.SetExperimentHeaderRoute(hr, destination)
+	assert.NoError(t, err)
+	iVirtualService, err := client.Resource(istioutil.GetIstioVirtualServiceGVR()).Namespace(r.rollout.Namespace).Get(context.TODO(), "vsvc", metav1.GetOptions{})
+	assert.NoError(t, err)
+	httpRoutes := extractHttpRoutes(t, iVirtualService)
+	assert.Equal(t, headerName, httpRoutes[0].Name)
+	assert.Len(t, httpRoutes[0].Route, 1)
+	checkDestination(t, httpRoutes[0].Route, "experiment-svc", 100)
+	assert.Equal(t, "primary", httpRoutes[1].Name)
+
+	// an unchanged route does not update the VirtualService
+	client.ClearActions()
+	err = r.SetExperimentHeaderRoute(hr, destination)
+	assert.NoError(t, err)
+	for _, action := range client.Actions() {
+		assert.NotEqual(t, "update", action.GetVerb())
+	}
+
+	err = r.SetExperimentHeaderRoute(hr, nil)
+	assert.NoError(t, err)
+	iVirtualService, err = client.Resource(istioutil.GetIstioVirtualServiceGVR()).Namespace(r.rollout.Namespace).Get(context.TODO(), "vsvc", metav1.GetOptions{})
+	assert.NoError(t, err)
+	httpRoutes = extractHttpRoutes(t, iVirtualService)
+	assert.Equal(t, "primary", httpRoutes[0].Name)
+	assert.Equal(t, "secondary", httpRoutes[1].Name)
+}
+
 func TestHttpReconcileHeaderRouteSubsetBased(t *testing.T) {
 	ro := rolloutWithDestinationRule(nil)
 	const StableSubsetName = "stable-subset"
@@ -4103,3 +4149,71 @@ spec:
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to update kubernetes virtual service")
 }
+
+func TestVerifyWeight(t *testing.T) {
+	defaults.SetIstioVerifyWeight(true)
+	defer defaults.SetIstioVerifyWeight(false)
+	newVsvc := func(weight int64, status map[string]any) *unstructured.Unstructured {
+		obj := unstructuredutil.StrToUnstructuredUnsafe(regularVsvc)
+		obj.SetGeneration(2)
+		routes, _, _ := unstructured.NestedSlice(obj.Object, "spec", "http")
+		for i := range routes {
+			destinations := routes[i].(map[string]any)["route"].([]any)
+			destinations[0].(map[string]any)["weight"] = 100 - weight
+			destinations[1].(map[string]any)["weight"] = weight
+		}
+		_ = unstructured.SetNestedSlice(obj.Object, routes, "spec", "http")
+		if status != nil {
+			obj.Object["status"] = status
+		}
+		return obj
+	}
+	reconciled := func(status, observedGeneration string) map[string]any {
+		return map[string]any{
+			"observedGeneration": observedGeneration,
+			"conditions": []any{
+				map[string]any{"type": "Reconciled", "status": status},
+			},
+		}
+	}
+	tests := []struct {
+		name      string
+		vsvc      *unstructured.Unstructured
+		verified  *bool
+		withWatch bool
+	}{
+		{name: "status not reported", vsvc: newVsvc(10, nil)},
+		{name: "reconciled", vsvc: newVsvc(10, reconciled("True", "2")), verified: ptr.To(true), withWatch: true},
+		{name: "not yet reconciled", vsvc: newVsvc(10, reconciled("False", "2")), verified: ptr.To(false), withWatch: true},
+		{name: "previous generation reconciled", vsvc: newVsvc(10, reconciled("True", "1")), verified: ptr.To(false), withWatch: true},
+		{name: "desired weight not yet cached", vsvc: newVsvc(0, reconciled("True", "2")), verified: ptr.To(false), withWatch: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ro := rolloutWithHttpRoutes("stable", "canary", "vsvc", []string{"primary", "secondary"})
+			client := testutil.NewFakeDynamicClient(test.vsvc)
+			vsvcLister, druleLister := getIstioListers(client)
+			r := NewReconciler(ro, client, record.NewFakeEventRecorder(), vsvcLister, druleLister, nil)
+			verified, err := r.VerifyWeight(10)
+			assert.NoError(t, err)
+			assert.Equal(t, test.verified, verified)
+			assert.Equal(t, test.withWatch, r.WeightVerificationWatched())
+		})
+	}
+
+	r := NewReconciler(rolloutWithHttpRoutes("stable", "canary", "vsvc", []string{"primary"}), nil, record.NewFakeEventRecorder(), nil, nil, nil)
+	verified, err := r.VerifyWeight(10)
+	assert.NoError(t, err)
+	assert.Nil(t, verified)
+
+	// the weights are not verified without --istio-verify-weight
+	defaults.SetIstioVerifyWeight(false)
+	ro := rolloutWithHttpRoutes("stable", "canary", "vsvc", []string{"primary", "secondary"})
+	client := testutil.NewFakeDynamicClient(newVsvc(10, reconciled("False", "2")))
+	vsvcLister, druleLister := getIstioListers(client)
+	r = NewReconciler(ro, client, record.NewFakeEventRecorder(), vsvcLister, druleLister, nil)
+	verified, err = r.VerifyWeight(10)
+	assert.NoError(t, err)
+	assert.Nil(t, verified)
+	assert.False(t, r.WeightVerificationWatched())
+}
@@ -0,0 +1,258 @@
+package knative
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/record"
+)
+
+// Type holds this controller type
+const Type = "Knative"
+
+const (
+	// KnativeServiceUpdateError is the reason of the event of a failed update of a Knative Service
+	KnativeServiceUpdateError = "KnativeServiceUpdateError"
+	// StableTag is the tag of the traffic target of the stable revision
+	StableTag = "stable"
+	// CanaryTag is the tag of the traffic target of the canary revision
+	CanaryTag = "canary"
+)
+
+// GVR is the resource of the Knative Services
+var GVR = schema.GroupVersionResource{
+	Group:    "serving.knative.dev",
+	Version:  "v1",
+	Resource: "services",
+}
+
+type ReconcilerConfig struct {
+	Rollout  *v1alpha1.Rollout
+	Client   ClientInterface
+	Recorder record.EventRecorder
+	// CanaryRS is the ReplicaSet of the canary pod template, which the canary revision runs
+	CanaryRS *appsv1.ReplicaSet
+}
+
+// Reconciler splits the traffic of a Knative Service between the revisions of the stable and canary pod templates. The
+// revisions are named after the Knative Service and the pod template hash, e.g. guestbook-5cb4fd98cf, so that the
+// revision of a pod template is created once and is reused when rolling back to it. The revisions are the workload of
+// the rollout, whose ReplicaSets are scaled to zero.
+type Reconciler struct {
+	Rollout    *v1alpha1.Rollout
+	Client     ClientInterface
+	Recorder   record.EventRecorder
+	CanaryRS   *appsv1.ReplicaSet
+	canaryHash string
+	stableHash string
+}
+
+type ClientInterface interface {
+	Get(ctx context.Context, name string, options metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error)
+	Update(ctx context.Context, obj *unstructured.Unstructured, options metav1.UpdateOptions, subresources ...string) (*unstructured.Unstructured, error)
+}
+
+func NewReconciler(cfg *ReconcilerConfig) *Reconciler {
+	return &Reconciler{
+		Rollout:  cfg.Rollout,
+		Client:   cfg.Client,
+		Recorder: cfg.Recorder,
+		CanaryRS: cfg.CanaryRS,
+	}
+}
+
+func NewDynamicClient(di dynamic.Interface, namespace string) dynamic.ResourceInterface {
+	return di.Resource(GVR).Namespace(namespace)
+}
+
+// RevisionName returns the name of the revision of a Knative Service for a pod template hash
+func RevisionName(serviceName, podHash string) string {
+	return fmt.Sprintf("%s-%s", serviceName, podHash)
+}
+
+// UpdateHash records the pod template hashes of the canary and stable revisions, which are routed to by SetWeight
+func (r *Reconciler) UpdateHash(canaryHash, stableHash string, additionalDestinations ...v1alpha1.WeightDestination) error {
+	r.canaryHash = canaryHash
+	r.stableHash = stableHash
+	return nil
+}
+
+// SetWeight creates the canary revision, if needed, and splits the traffic of the Knative Service between the stable
+// and canary revisions
+func (r *Reconciler) SetWeight(desiredWeight int32, additionalDestinations ...v1alpha1.WeightDestination) error {
+	ctx := context.TODO()
+	serviceName := r.Rollout.Spec.Strategy.Canary.TrafficRouting.Knative.Service
+	ksvc, err := r.Client.Get(ctx, serviceName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	stableHash, canaryHash := r.stableHash, r.canaryHash
+	if stableHash == "" {
+		stableHash = canaryHash
+	}
+	if stableHash == "" {
+		return errors.New("the stable and canary pod template hashes are unknown")
+	}
+
+	if r.CanaryRS != nil && canaryHash != "" && r.CanaryRS.Labels[v1alpha1.DefaultRolloutUniqueLabelKey] == canaryHash {
+		if err := setRevisionTemplate(ksvc, RevisionName(serviceName, canaryHash), r.CanaryRS.Spec.Template); err != nil {
+			return err
+		}
+	}
+	traffic := []any{
+		map[string]any{"revisionName": RevisionName(serviceName, stableHash), "percent": int64(100 - desiredWeight), "tag": StableTag},
+	}
+	if canaryHash != "" && canaryHash != stableHash {
+		traffic = append(traffic, map[string]any{"revisionName": RevisionName(serviceName, canaryHash), "percent": int64(desiredWeight), "tag": CanaryTag})
+	} else {
+		traffic[0].(map[string]any)["percent"] = int64(100)
+	}
+	if err := unstructured.SetNestedSlice(ksvc.Object, traffic, "spec", "traffic"); err != nil {
+		return err
+	}
+	_, err = r.Client.Update(ctx, ksvc, metav1.UpdateOptions{})
+	if err != nil {
+		msg := fmt.Sprintf("Error updating Knative Service %q: %s", ksvc.GetName(), err)
+		r.Recorder.Warnf(r.Rollout, record.EventOptions{EventReason: KnativeServiceUpdateError}, "%s", msg)
+	}
+	return err
+}
+
+// revisionSpecFields are the fields of the revision template of a Knative Service which are not part of the pod
+// spec, and are kept when the pod spec of the revision template is replaced
+var revisionSpecFields = []string{"containerConcurrency", "timeoutSeconds", "responseStartTimeoutSeconds", "idleTimeoutSeconds"}
+
+// setRevisionTemplate names the revision template of a Knative Service and replaces its pod spec with the one of the
+// pod template, so that the revision runs the pod template of the rollout as is. The labels and annotations of the pod
+// template are added to the ones of the revision template, except for the pod template hash label.
+func setRevisionTemplate(ksvc *unstructured.Unstructured, revisionName string, template corev1.PodTemplateSpec) error {
+	podSpec, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&template.Spec)
+	if err != nil {
+		return err
+	}
+	revisionSpec, _, err := unstructured.NestedMap(ksvc.Object, "spec", "template", "spec")
+	if err != nil {
+		return err
+	}
+	for _, field := range revisionSpecFields {
+		if value, ok := revisionSpec[field]; ok {
+			podSpec[field] = value
+		}
+	}
+	if err := unstructured.SetNestedMap(ksvc.Object, podSpec, "spec", "template", "spec"); err != nil {
+		return err
+	}
+	if err := unstructured.SetNestedField(ksvc.Object, revisionName, "spec", "template", "metadata", "name"); err != nil {
+		return err
+	}
+	labels, _, err := unstructured.NestedStringMap(ksvc.Object, "spec", "template", "metadata", "labels")
+	if err != nil {
+		return err
+	}
+	labels = mergeStringMaps(labels, template.Labels)
+	delete(labels, v1alpha1.DefaultRolloutUniqueLabelKey)
+	annotations, _, err := unstructured.NestedStringMap(ksvc.Object, "spec", "template", "metadata", "annotations")
+	if err != nil {
+		return err
+	}
+	annotations = mergeStringMaps(annotations, template.Annotations)
+	for field, values := range map[string]map[string]string{"labels": labels, "annotations": annotations} {
+		if len(values) == 0 {
+			continue
+		}
+		if err := unstructured.SetNestedStringMap(ksvc.Object, values, "spec", "template", "metadata", field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mergeStringMaps(dst, src map[string]string) map[string]string {
+	if dst == nil {
+		dst = map[string]string{}
+	}
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
+
+// VerifyWeight returns whether Knative observed the latest traffic split, reports the Knative Service as ready, i.e. its
+// revisions which receive traffic are ready, and routes the desired weight of the traffic to the canary revision
+func (r *Reconciler) VerifyWeight(desiredWeight int32, additionalDestinations ...v1alpha1.WeightDestination) (*bool, error) {
+	serviceName := r.Rollout.Spec.Strategy.Canary.TrafficRouting.Knative.Service
+	ksvc, err := r.Client.Get(context.TODO(), serviceName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	observedGeneration, _, _ := unstructured.NestedInt64(ksvc.Object, "status", "observedGeneration")
+	if observedGeneration < ksvc.GetGeneration() {
+		return ptr.To(false), nil
+	}
+	if !isReady(ksvc) {
+		return ptr.To(false), nil
+	}
+	if r.canaryHash == "" || r.canaryHash == r.stableHash {
+		return ptr.To(true), nil
+	}
+	traffic, _, err := unstructured.NestedSlice(ksvc.Object, "status", "traffic")
+	if err != nil {
+		return nil, err
+	}
+	canaryRevision := RevisionName(serviceName, r.canaryHash)
+	var weight int64
+	for _, target := range traffic {
+		target, ok := target.(map[string]any)
+		if !ok {
+			continue
+		}
+		if revisionName, _, _ := unstructured.NestedString(target, "revisionName"); revisionName == canaryRevision {
+			percent, _, _ := unstructured.NestedInt64(target, "percent")
+			weight += percent
+		}
+	}
+	return ptr.To(weight == int64(desiredWeight)), nil
+}
+
+// isReady returns whether the Ready condition of a Knative Service is true
+func isReady(ksvc *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(ksvc.Object, "status", "conditions")
+	for _, condition := range conditions {
+		condition, ok := condition.(map[string]any)
+		if !ok {
+			continue
+		}
+		if conditionType, _, _ := unstructured.NestedString(condition, "type"); conditionType == "Ready" {
+			status, _, _ := unstructured.NestedString(condition, "status")
+			return status == string(corev1.ConditionTrue)
+		}
+	}
+	return false
+}
+
+func (r *Reconciler) SetHeaderRoute(headerRouting *v1alpha1.SetHeaderRoute) error {
+	return nil
+}
+
+func (r *Reconciler) SetMirrorRoute(setMirrorRoute *v1alpha1.SetMirrorRoute) error {
+	return nil
+}
+
+func (r *Reconciler) RemoveManagedRoutes() error {
+	return nil
+}
+
+func (r *Reconciler) Type() string {
+	return Type
+}
@@ -0,0 +1,179 @@
+package knative
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer/yaml"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/record"
+)
+
+const knativeService = `
+apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: guestbook
+  namespace: default
+  generation: 2
+spec:
+  template:
+    metadata:
+      name: guestbook-stable123
+      annotations:
+        autoscaling.knative.dev/max-scale: "5"
+    spec:
+      containerConcurrency: 10
+      containers:
+      - image: argoproj/rollouts-demo:blue
+  traffic:
+  - revisionName: guestbook-stable123
+    percent: 100
+status:
+  observedGeneration: 2
+  conditions:
+  - type: Ready
+    status: "True"
+  traffic:
+  - revisionName: guestbook-stable123
+    percent: 90
+  - revisionName: guestbook-canary456
+    percent: 10
+`
+
+func newKnativeService(t *testing.T) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	_, _, err := yaml.NewDecodingSerializer(unstructured.UnstructuredJSONScheme).Decode([]byte(knativeService), nil, obj)
+	require.NoError(t, err)
+	return obj
+}
+
+func newRollout() *v1alpha1.Rollout {
+	return &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: "default"},
+		Spec: v1alpha1.RolloutSpec{
+			Strategy: v1alpha1.RolloutStrategy{
+				Canary: &v1alpha1.CanaryStrategy{
+					TrafficRouting: &v1alpha1.RolloutTrafficRouting{
+						Knative: &v1alpha1.KnativeTrafficRouting{Service: "guestbook"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newReconciler(t *testing.T, canaryRS *appsv1.ReplicaSet) (*Reconciler, ClientInterface) {
+	scheme := runtime.NewScheme()
+	listKinds := map[schema.GroupVersionResource]string{GVR: "ServiceList"}
+	client := NewDynamicClient(dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme, listKinds, newKnativeService(t)), "default")
+	return NewReconciler(&ReconcilerConfig{
+		Rollout:  newRollout(),
+		Client:   client,
+		Recorder: record.NewFakeEventRecorder(),
+		CanaryRS: canaryRS,
+	}), client
+}
+
+func getTraffic(t *testing.T, client ClientInterface) (*unstructured.Unstructured, []any) {
+	ksvc, err := client.Get(context.TODO(), "guestbook", metav1.GetOptions{})
+	require.NoError(t, err)
+	traffic, _, err := unstructured.NestedSlice(ksvc.Object, "spec", "traffic")
+	require.NoError(t, err)
+	return ksvc, traffic
+}
+
+func TestSetWeight(t *testing.T) {
+	canaryRS := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: "canary456"}},
+		Spec: appsv1.ReplicaSetSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "guestbook", v1alpha1.DefaultRolloutUniqueLabelKey: "canary456"}},
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{
+					Name:  "guestbook",
+					Image: "argoproj/rollouts-demo:yellow",
+					Env:   []corev1.EnvVar{{Name: "COLOR", Value: "yellow"}},
+				}}},
+			},
+		},
+	}
+	r, client := newReconciler(t, canaryRS)
+	require.NoError(t, r.UpdateHash("canary456", "stable123"))
+	require.NoError(t, r.SetWeight(10))
+
+	ksvc, traffic := getTraffic(t, client)
+	assert.Equal(t, []any{
+		map[string]any{"revisionName": "guestbook-stable123", "percent": int64(90), "tag": StableTag},
+		map[string]any{"revisionName": "guestbook-canary456", "percent": int64(10), "tag": CanaryTag},
+	}, traffic)
+	name, _, _ := unstructured.NestedString(ksvc.Object, "spec", "template", "metadata", "name")
+	assert.Equal(t, "guestbook-canary456", name)
+	containers, _, _ := unstructured.NestedSlice(ksvc.Object, "spec", "template", "spec", "containers")
+	require.Len(t, containers, 1)
+	assert.Equal(t, "argoproj/rollouts-demo:yellow", containers[0].(map[string]any)["image"])
+	assert.Equal(t, []any{map[string]any{"name": "COLOR", "value": "yellow"}}, containers[0].(map[string]any)["env"])
+	// the fields of the revision which are not part of the pod spec are kept
+	containerConcurrency, _, _ := unstructured.NestedInt64(ksvc.Object, "spec", "template", "spec", "containerConcurrency")
+	assert.Equal(t, int64(10), containerConcurrency)
+	labels, _, _ := unstructured.NestedStringMap(ksvc.Object, "spec", "template", "metadata", "labels")
+	assert.Equal(t, map[string]string{"app": "guestbook"}, labels)
+	annotations, _, _ := unstructured.NestedStringMap(ksvc.Object, "spec", "template", "metadata", "annotations")
+	assert.Equal(t, map[string]string{"autoscaling.knative.dev/max-scale": "5"}, annotations)
+
+	// once promoted, all the traffic goes to the stable revision
+	require.NoError(t, r.UpdateHash("canary456", "canary456"))
+	require.NoError(t, r.SetWeight(0))
+	_, traffic = getTraffic(t, client)
+	assert.Equal(t, []any{
+		map[string]any{"revisionName": "guestbook-canary456", "percent": int64(100), "tag": StableTag},
+	}, traffic)
+}
+
+func TestSetWeightWithoutHashes(t *testing.T) {
+	r, _ := newReconciler(t, nil)
+	assert.Error(t, r.SetWeight(10))
+}
+
+func TestVerifyWeight(t *testing.T) {
+	r, _ := newReconciler(t, nil)
+	require.NoError(t, r.UpdateHash("canary456", "stable123"))
+	verified, err := r.VerifyWeight(10)
+	require.NoError(t, err)
+	assert.True(t, *verified)
+
+	verified, err = r.VerifyWeight(20)
+	require.NoError(t, err)
+	assert.False(t, *verified)
+
+	require.NoError(t, r.UpdateHash("stable123", "stable123"))
+	verified, err = r.VerifyWeight(0)
+	require.NoError(t, err)
+	assert.True(t, *verified)
+
+	// the revisions which receive traffic must be ready
+	ksvc := newKnativeService(t)
+	require.NoError(t, unstructured.SetNestedSlice(ksvc.Object, []any{map[string]any{"type": "Ready", "status": "False"}}, "status", "conditions"))
+	_, err = r.Client.Update(context.TODO(), ksvc, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	verified, err = r.VerifyWeight(0)
+	require.NoError(t, err)
+	assert.False(t, *verified)
+}
+
+func TestType(t *testing.T) {
+	r, _ := newReconciler(t, nil)
+	assert.Equal(t, Type, r.Type())
+	assert.NoError(t, r.SetHeaderRoute(nil))
+	assert.NoError(t, r.SetMirrorRoute(nil))
+	assert.NoError(t, r.RemoveManagedRoutes())
+}
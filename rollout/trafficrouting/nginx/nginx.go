@@ -97,6 +97,10 @@ func (r *Reconciler) buildCanaryIngress(stableIngress *networkingv1.Ingress, nam
 
 	// Ensure canaryIngress is owned by this Rollout for cleanup
 	desiredCanaryIngress.SetOwnerReferences([]metav1.OwnerReference{*metav1.NewControllerRef(r.cfg.Rollout, r.cfg.ControllerKind)})
+	// The canary ingress is controlled by the rollout and only needs the shard label of an explicitly sharded rollout
+	if shard, ok := r.cfg.Rollout.Labels[v1alpha1.LabelKeyControllerShard]; ok {
+		desiredCanaryIngress.Labels = map[string]string{v1alpha1.LabelKeyControllerShard: shard}
+	}
 
 	// Copy only the rules which reference the stableService from the stableIngress to the canaryIngress
 	// and change service backend to canaryService. Rules **not** referencing the stableIngress will be ignored.
@@ -183,6 +187,10 @@ func (r *Reconciler) buildLegacyCanaryIngress(stableIngress *extensionsv1beta1.I
 
 	// Ensure canaryIngress is owned by this Rollout for cleanup
 	desiredCanaryIngress.SetOwnerReferences([]metav1.OwnerReference{*metav1.NewControllerRef(r.cfg.Rollout, r.cfg.ControllerKind)})
+	// The canary ingress is controlled by the rollout and only needs the shard label of an explicitly sharded rollout
+	if shard, ok := r.cfg.Rollout.Labels[v1alpha1.LabelKeyControllerShard]; ok {
+		desiredCanaryIngress.Labels = map[string]string{v1alpha1.LabelKeyControllerShard: shard}
+	}
 
 	// Copy only the rules which reference the stableService from the stableIngress to the canaryIngress
 	// and change service backend to canaryService. Rules **not** referencing the stableIngress will be ignored.
@@ -2,7 +2,6 @@ package client
 
 import (
 	"fmt"
-	"os/exec"
 	"sync"
 
 	goPlugin "github.com/hashicorp/go-plugin"
@@ -40,6 +39,27 @@ var getPluginInfo = plugin.GetPluginInfo
 // It is nil in production and only set during tests to simulate ping failures.
 var testBeforePing func(pluginName string)
 
+func init() {
+	plugin.RegisterReloader(types.PluginTypeTrafficRouter, reloadPlugin)
+}
+
+// reloadPlugin stops the running process of the plugin once its in-flight calls have completed, so that the plugin is
+// started again with its new definition the next time it is used
+func reloadPlugin(pluginName string) {
+	mutex.Lock()
+	if pluginClients == nil || pluginClients.pluginClient[pluginName] == nil {
+		mutex.Unlock()
+		return
+	}
+	pluginClient, rpcPlugin := pluginClients.pluginClient[pluginName], pluginClients.plugin[pluginName]
+	delete(pluginClients.pluginClient, pluginName)
+	delete(pluginClients.rpcClient, pluginName)
+	delete(pluginClients.plugin, pluginName)
+	mutex.Unlock()
+
+	plugin.StopClient(pluginName, pluginClient, rpcPlugin)
+}
+
 // GetTrafficPlugin returns a singleton plugin client for the given traffic router plugin. Calling this multiple times
 // returns the same plugin client instance for the plugin name defined in the rollout object.
 func GetTrafficPlugin(pluginName string) (rpc.TrafficRouterPlugin, error) {
@@ -72,14 +92,10 @@ func (t *trafficPlugin) startPluginLocked(pluginName string) (rpc.TrafficRouterP
 			return nil, fmt.Errorf("unable to find plugin (%s): %w", pluginName, err)
 		}
 
-		t.pluginClient[pluginName] = goPlugin.NewClient(&goPlugin.ClientConfig{
-			HandshakeConfig: handshakeConfig,
-			Plugins:         pluginMap,
-			Cmd:             exec.Command(pluginPath, args...),
-			Managed:         true,
-		})
+		pluginConfig := plugin.GetPlugin(pluginName, types.PluginTypeTrafficRouter)
+		t.pluginClient[pluginName] = plugin.NewClient(pluginPath, args, pluginConfig, handshakeConfig, pluginMap)
 
-		rpcClient, err := t.pluginClient[pluginName].Client()
+		rpcClient, err := plugin.StartClient(t.pluginClient[pluginName], pluginConfig)
 		if err != nil {
 			return nil, fmt.Errorf("unable to get plugin client (%s): %w", pluginName, err)
 		}
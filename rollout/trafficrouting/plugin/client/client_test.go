@@ -222,6 +222,36 @@ func TestStartPlugin_ReinitializeWhenRpcClientNil(t *testing.T) {
 	assert.Contains(t, err.Error(), "unable to find plugin")
 }
 
+// TestReloadPlugin calls reloadPlugin() on an initialized plugin.
+// Covers: removal of all references, unknown plugins.
+func TestReloadPlugin(t *testing.T) {
+	resetSingleton()
+	reloadPlugin("test-plugin")
+
+	pluginClient, rpcClient, cleanup := setupTestPlugin(t)
+	defer cleanup()
+
+	initSingleton()
+
+	pluginName := "test-plugin"
+	pluginClients.pluginClient[pluginName] = pluginClient
+	pluginClients.rpcClient[pluginName] = rpcClient
+
+	raw, err := rpcClient.Dispense("RpcTrafficRouterPlugin")
+	assert.NoError(t, err)
+	p, ok := raw.(rolloutsRpc.TrafficRouterPlugin)
+	assert.True(t, ok)
+	pluginClients.plugin[pluginName] = p
+
+	reloadPlugin("other-plugin")
+	assert.NotNil(t, pluginClients.plugin[pluginName])
+
+	reloadPlugin(pluginName)
+	assert.NotContains(t, pluginClients.pluginClient, pluginName)
+	assert.NotContains(t, pluginClients.rpcClient, pluginName)
+	assert.NotContains(t, pluginClients.plugin, pluginName)
+}
+
 // TestGetTrafficPlugin_NotConfigured calls GetTrafficPlugin with a non-existent plugin.
 // Covers: GetTrafficPlugin, once.Do initialization, startPlugin if branch, getPluginInfo error.
 func TestGetTrafficPlugin_NotConfigured(t *testing.T) {
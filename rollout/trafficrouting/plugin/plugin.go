@@ -8,6 +8,7 @@ import (
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	"github.com/argoproj/argo-rollouts/rollout/trafficrouting/plugin/client"
 	"github.com/argoproj/argo-rollouts/rollout/trafficrouting/plugin/rpc"
+	"github.com/argoproj/argo-rollouts/utils/plugin/types"
 	"github.com/argoproj/argo-rollouts/utils/record"
 )
 
@@ -79,6 +80,19 @@ func (r *Reconciler) VerifyWeight(desiredWeight int32, additionalDestinations ..
 	return verified.IsVerified(), nil
 }
 
+// Healthz returns an error if the traffic router is not able to route the traffic of the rollout
+func (r *Reconciler) Healthz() error {
+	checker, ok := r.TrafficRouterPlugin.(types.RpcTrafficRouterHealthChecker)
+	if !ok {
+		return nil
+	}
+	resp := checker.Healthz(r.Rollout)
+	if resp.HasError() {
+		return fmt.Errorf("traffic router plugin is unhealthy: %w", resp)
+	}
+	return nil
+}
+
 // SetMirrorRoute sets up the traffic router to mirror traffic to a service
 func (r *Reconciler) SetMirrorRoute(setMirrorRoute *v1alpha1.SetMirrorRoute) error {
 	resp := r.TrafficRouterPlugin.SetMirrorRoute(r.Rollout, setMirrorRoute)
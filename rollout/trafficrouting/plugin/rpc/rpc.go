@@ -4,6 +4,7 @@ import (
 	"encoding/gob"
 	"fmt"
 	"net/rpc"
+	"time"
 
 	"github.com/argoproj/argo-rollouts/utils/plugin/types"
 
@@ -39,6 +40,10 @@ type RemoveManagedRoutesArgs struct {
 	Rollout v1alpha1.Rollout
 }
 
+type HealthzArgs struct {
+	Rollout v1alpha1.Rollout
+}
+
 type VerifyWeightResponse struct {
 	Verified types.RpcVerified
 	Err      types.RpcError
@@ -50,6 +55,7 @@ func init() {
 	gob.RegisterName("SetHeaderArgs", new(SetHeaderArgs))
 	gob.RegisterName("SetMirrorArgs", new(SetMirrorArgs))
 	gob.RegisterName("RemoveManagedRoutesArgs", new(RemoveManagedRoutesArgs))
+	gob.RegisterName("HealthzArgs", new(HealthzArgs))
 }
 
 // TrafficRouterPlugin is the interface that we're exposing as a plugin. It needs to match metricproviders.Providers but we can
@@ -60,7 +66,7 @@ type TrafficRouterPlugin interface {
 }
 
 // TrafficRouterPluginRPC Here is an implementation that talks over RPC
-type TrafficRouterPluginRPC struct{ client *rpc.Client }
+type TrafficRouterPluginRPC struct{ client *types.RpcClient }
 
 // NewTrafficRouterPlugin this is the client aka the controller side function that calls the server side rpc (plugin)
 // this gets called once during startup of the plugin and can be used to set up informers or k8s clients etc.
@@ -132,6 +138,12 @@ func (g *TrafficRouterPluginRPC) SetMirrorRoute(rollout *v1alpha1.Rollout, setMi
 	return resp
 }
 
+// Drain waits for the in-flight calls to the plugin to complete, and returns false if they did not complete within the
+// timeout
+func (g *TrafficRouterPluginRPC) Drain(timeout time.Duration) bool {
+	return g.client.Drain(timeout)
+}
+
 // Type returns the type of the traffic routing reconciler
 func (g *TrafficRouterPluginRPC) Type() string {
 	var resp string
@@ -172,6 +184,23 @@ func (g *TrafficRouterPluginRPC) RemoveManagedRoutes(rollout *v1alpha1.Rollout)
 	return resp
 }
 
+// Healthz returns an error if the traffic router is not able to route the traffic of the rollout. The plugins which
+// do not implement the Healthz method are considered healthy
+func (g *TrafficRouterPluginRPC) Healthz(rollout *v1alpha1.Rollout) types.RpcError {
+	var resp types.RpcError
+	var args any = HealthzArgs{
+		Rollout: *rollout,
+	}
+	err := g.client.Call("Plugin.Healthz", &args, &resp)
+	if types.IsMethodNotFound(err) {
+		return types.RpcError{}
+	}
+	if err != nil {
+		return types.RpcError{ErrorString: fmt.Sprintf("Healthz rpc call error: %s", err)}
+	}
+	return resp
+}
+
 // TrafficRouterRPCServer Here is the RPC server that MetricsPluginRPC talks to, conforming to
 // the requirements of net/rpc
 type TrafficRouterRPCServer struct {
@@ -257,6 +286,19 @@ func (s *TrafficRouterRPCServer) RemoveManagedRoutes(args any, resp *types.RpcEr
 	return nil
 }
 
+// Healthz returns an error if the traffic router is not able to route the traffic of the rollout. The plugins which
+// do not implement types.RpcTrafficRouterHealthChecker are considered healthy
+func (s *TrafficRouterRPCServer) Healthz(args any, resp *types.RpcError) error {
+	healthzArgs, ok := args.(*HealthzArgs)
+	if !ok {
+		return fmt.Errorf("invalid args %s", args)
+	}
+	if checker, ok := s.Impl.(types.RpcTrafficRouterHealthChecker); ok {
+		*resp = checker.Healthz(&healthzArgs.Rollout)
+	}
+	return nil
+}
+
 // RpcTrafficRouterPlugin This is the implementation of plugin.Plugin so we can serve/consume
 //
 // This has two methods: Server must return an RPC server for this plugin
@@ -277,5 +319,5 @@ func (p *RpcTrafficRouterPlugin) Server(*plugin.MuxBroker) (any, error) {
 }
 
 func (RpcTrafficRouterPlugin) Client(b *plugin.MuxBroker, c *rpc.Client) (any, error) {
-	return &TrafficRouterPluginRPC{client: c}, nil
+	return &TrafficRouterPluginRPC{client: types.NewRpcClient(c)}, nil
 }
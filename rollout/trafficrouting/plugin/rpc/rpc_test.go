@@ -2,6 +2,8 @@ package rpc
 
 import (
 	"context"
+	"net"
+	"net/rpc"
 	"testing"
 	"time"
 
@@ -9,6 +11,7 @@ import (
 
 	goPlugin "github.com/hashicorp/go-plugin"
 	"github.com/tj/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 )
@@ -19,11 +22,9 @@ var testHandshake = goPlugin.HandshakeConfig{
 	MagicCookieValue: "trafficrouter",
 }
 
-func pluginClient(t *testing.T) (TrafficRouterPlugin, goPlugin.ClientProtocol, func(), chan struct{}) {
+func pluginClient(t *testing.T, rpcPluginImp TrafficRouterPlugin) (TrafficRouterPlugin, goPlugin.ClientProtocol, func(), chan struct{}) {
 	ctx, cancel := context.WithCancel(context.Background())
 
-	rpcPluginImp := &testRpcPlugin{}
-
 	// pluginMap is the map of plugins we can dispense.
 	var pluginMap = map[string]goPlugin.Plugin{
 		"RpcTrafficRouterPlugin": &RpcTrafficRouterPlugin{Impl: rpcPluginImp},
@@ -79,7 +80,7 @@ func pluginClient(t *testing.T) (TrafficRouterPlugin, goPlugin.ClientProtocol, f
 }
 
 func TestPlugin(t *testing.T) {
-	plugin, _, cancel, closeCh := pluginClient(t)
+	plugin, _, cancel, closeCh := pluginClient(t, &testRpcPlugin{})
 	defer cancel()
 
 	err := plugin.InitPlugin()
@@ -111,13 +112,55 @@ func TestPlugin(t *testing.T) {
 	typeString := plugin.Type()
 	assert.Equal(t, "TestRPCPlugin", typeString)
 
+	err = plugin.(types.RpcTrafficRouterHealthChecker).Healthz(&ro)
+	assert.Equal(t, "", err.Error())
+
 	// Canceling should cause an exit
 	cancel()
 	<-closeCh
 }
 
+func TestPluginHealthz(t *testing.T) {
+	plugin, _, cancel, closeCh := pluginClient(t, &testRpcPluginWithHealthz{})
+	defer cancel()
+
+	healthChecker, ok := plugin.(types.RpcTrafficRouterHealthChecker)
+	assert.True(t, ok)
+
+	err := healthChecker.Healthz(&v1alpha1.Rollout{})
+	assert.Equal(t, "", err.Error())
+
+	err = healthChecker.Healthz(&v1alpha1.Rollout{ObjectMeta: metav1.ObjectMeta{Name: "unhealthy"}})
+	assert.Equal(t, "route of unhealthy not found", err.Error())
+
+	cancel()
+	<-closeCh
+}
+
+// previousRPCServer is the RPC server of a plugin built before the Healthz method was added
+type previousRPCServer struct{}
+
+func (s *previousRPCServer) Type(args any, resp *string) error {
+	*resp = "TestRPCPlugin"
+	return nil
+}
+
+func TestHealthzOfPreviousPlugin(t *testing.T) {
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName("Plugin", &previousRPCServer{}))
+	serverConn, clientConn := net.Pipe()
+	go server.ServeConn(serverConn)
+	client := rpc.NewClient(clientConn)
+	defer client.Close()
+
+	plugin := &TrafficRouterPluginRPC{client: types.NewRpcClient(client)}
+	err := plugin.Healthz(&v1alpha1.Rollout{})
+	assert.Equal(t, "", err.Error())
+	assert.Equal(t, "TestRPCPlugin", plugin.Type())
+}
+
 func TestPluginClosedConnection(t *testing.T) {
-	plugin, client, cancel, closeCh := pluginClient(t)
+	plugin, client, cancel, closeCh := pluginClient(t, &testRpcPlugin{})
 	defer cancel()
 
 	client.Close()
@@ -143,6 +186,9 @@ func TestPluginClosedConnection(t *testing.T) {
 	_, err = plugin.VerifyWeight(&v1alpha1.Rollout{}, 0, []v1alpha1.WeightDestination{})
 	assert.Contains(t, err.Error(), expectedError)
 
+	err = plugin.(types.RpcTrafficRouterHealthChecker).Healthz(&v1alpha1.Rollout{})
+	assert.Contains(t, err.Error(), expectedError)
+
 	cancel()
 	<-closeCh
 }
@@ -175,4 +221,7 @@ func TestInvalidArgs(t *testing.T) {
 
 	err = server.UpdateHash(badtype, &errRpc)
 	assert.Error(t, err)
+
+	err = server.Healthz(badtype, &errRpc)
+	assert.Error(t, err)
 }
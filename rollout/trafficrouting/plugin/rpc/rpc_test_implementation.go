@@ -1,6 +1,8 @@
 package rpc
 
 import (
+	"fmt"
+
 	"github.com/argoproj/argo-rollouts/utils/plugin/types"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
@@ -40,3 +42,15 @@ func (r *testRpcPlugin) RemoveManagedRoutes(ro *v1alpha1.Rollout) types.RpcError
 func (r *testRpcPlugin) Type() string {
 	return "TestRPCPlugin"
 }
+
+// testRpcPluginWithHealthz is a plugin which reports the rollouts named unhealthy as unhealthy
+type testRpcPluginWithHealthz struct {
+	testRpcPlugin
+}
+
+func (r *testRpcPluginWithHealthz) Healthz(ro *v1alpha1.Rollout) types.RpcError {
+	if ro.Name == "unhealthy" {
+		return types.RpcError{ErrorString: fmt.Sprintf("route of %s not found", ro.Name)}
+	}
+	return types.RpcError{}
+}
@@ -0,0 +1,201 @@
+package smi
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+	"github.com/argoproj/argo-rollouts/utils/record"
+)
+
+const (
+	// SpecsAPIGroup is the API group of the SMI HTTPRouteGroups
+	SpecsAPIGroup = "specs.smi-spec.io"
+	// HTTPRouteGroupKind is the kind of the SMI HTTPRouteGroups
+	HTTPRouteGroupKind = "HTTPRouteGroup"
+)
+
+// httpRouteGroupGVR returns the resource of the HTTPRouteGroups of the same version as the TrafficSplits
+func httpRouteGroupGVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: SpecsAPIGroup, Version: defaults.GetSMIAPIVersion(), Resource: "httproutegroups"}
+}
+
+// supportsMatches returns whether the TrafficSplits of the configured API version can match HTTPRouteGroups
+func supportsMatches() bool {
+	switch defaults.GetSMIAPIVersion() {
+	case "v1alpha3", "v1alpha4":
+		return true
+	default:
+		return false
+	}
+}
+
+// trafficSplitName returns the name of the TrafficSplit of the rollout, which defaults to the name of the rollout
+func (r *Reconciler) trafficSplitName() string {
+	if name := r.cfg.Rollout.Spec.Strategy.Canary.TrafficRouting.SMI.TrafficSplitName; name != "" {
+		return name
+	}
+	return r.cfg.Rollout.Name
+}
+
+// headerRouteName returns the name of the TrafficSplit and HTTPRouteGroup of a header route
+func (r *Reconciler) headerRouteName(routeName string) string {
+	return fmt.Sprintf("%s-%s", r.trafficSplitName(), routeName)
+}
+
+func (r *Reconciler) routeGroups() dynamic.ResourceInterface {
+	return r.cfg.DynamicClient.Resource(httpRouteGroupGVR()).Namespace(r.cfg.Rollout.Namespace)
+}
+
+// SetHeaderRoute sends the requests matching the headers of the route to the canary service with a TrafficSplit which
+// matches an HTTPRouteGroup of the headers, next to the TrafficSplit of the weights. A route without matches removes
+// the TrafficSplit and HTTPRouteGroup of the route. TrafficSplits of API versions which can't match headers leave the
+// header route to the other traffic routers of the rollout.
+func (r *Reconciler) SetHeaderRoute(headerRouting *v1alpha1.SetHeaderRoute) error {
+	if headerRouting == nil {
+		return nil
+	}
+	if len(headerRouting.Match) == 0 {
+		return r.removeHeaderRoute(headerRouting.Name)
+	}
+	if !supportsMatches() {
+		// the header route is left to the other traffic routers of the rollout, as the validation rejects header routes
+		// with only SMI TrafficSplits which can't match headers
+		r.log.Infof("Header route `%s` is not set in SMI, which requires TrafficSplits of API version v1alpha3 or later, but the API version is `%s`", headerRouting.Name, defaults.GetSMIAPIVersion())
+		return nil
+	}
+	name := r.headerRouteName(headerRouting.Name)
+	headers := map[string]any{}
+	for _, match := range headerRouting.Match {
+		headers[match.HeaderName] = headerValueRegex(match.HeaderValue)
+	}
+	if err := r.applyHTTPRouteGroup(name, headerRouting.Name, headers); err != nil {
+		return err
+	}
+
+	trafficSplits := r.generateTrafficSplits(name, 100)
+	matches := []corev1.TypedLocalObjectReference{{
+		APIGroup: ptr.To(SpecsAPIGroup),
+		Kind:     HTTPRouteGroupKind,
+		Name:     name,
+	}}
+	if trafficSplits.ts3 != nil {
+		trafficSplits.ts3.Spec.Matches = matches
+	}
+	if trafficSplits.ts4 != nil {
+		trafficSplits.ts4.Spec.Matches = matches
+	}
+	existing, err := r.getTrafficSplit(name)
+	if k8serrors.IsNotFound(err) {
+		if err := r.createTrafficSplit(trafficSplits); err != nil {
+			return err
+		}
+		r.cfg.Recorder.Eventf(r.cfg.Rollout, record.EventOptions{EventReason: "TrafficSplitCreated"}, "TrafficSplit `%s` created", name)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !r.trafficSplitIsControlledBy(existing) {
+		return fmt.Errorf("Rollout does not own TrafficSplit `%s`", name)
+	}
+	return r.patchTrafficSplit(existing, trafficSplits)
+}
+
+// applyHTTPRouteGroup creates or updates the HTTPRouteGroup of a header route
+func (r *Reconciler) applyHTTPRouteGroup(name, routeName string, headers map[string]any) error {
+	ctx := context.TODO()
+	matches := []any{map[string]any{"name": routeName, "headers": headers}}
+	routeGroup, err := r.routeGroups().Get(ctx, name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		routeGroup = &unstructured.Unstructured{Object: map[string]any{}}
+		routeGroup.SetAPIVersion(SpecsAPIGroup + "/" + defaults.GetSMIAPIVersion())
+		routeGroup.SetKind(HTTPRouteGroupKind)
+		routeGroup.SetName(name)
+		routeGroup.SetNamespace(r.cfg.Rollout.Namespace)
+		routeGroup.SetOwnerReferences([]metav1.OwnerReference{*metav1.NewControllerRef(r.cfg.Rollout, r.cfg.ControllerKind)})
+		if err := unstructured.SetNestedSlice(routeGroup.Object, matches, "spec", "matches"); err != nil {
+			return err
+		}
+		_, err = r.routeGroups().Create(ctx, routeGroup, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(routeGroup, r.cfg.Rollout) {
+		return fmt.Errorf("Rollout does not own HTTPRouteGroup `%s`", name)
+	}
+	if err := unstructured.SetNestedSlice(routeGroup.Object, matches, "spec", "matches"); err != nil {
+		return err
+	}
+	_, err = r.routeGroups().Update(ctx, routeGroup, metav1.UpdateOptions{})
+	return err
+}
+
+// removeHeaderRoute deletes the TrafficSplit and HTTPRouteGroup of a header route, if they exist and are owned by the
+// rollout
+func (r *Reconciler) removeHeaderRoute(routeName string) error {
+	if !supportsMatches() {
+		return nil
+	}
+	ctx := context.TODO()
+	name := r.headerRouteName(routeName)
+	existing, err := r.getTrafficSplit(name)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	if err == nil && r.trafficSplitIsControlledBy(existing) {
+		if err := r.deleteTrafficSplit(name); err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+	routeGroup, err := r.routeGroups().Get(ctx, name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(routeGroup, r.cfg.Rollout) {
+		return nil
+	}
+	if err := r.routeGroups().Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// RemoveManagedRoutes removes the header routes of all the managed routes
+func (r *Reconciler) RemoveManagedRoutes() error {
+	for _, route := range r.cfg.Rollout.Spec.Strategy.Canary.TrafficRouting.ManagedRoutes {
+		if err := r.removeHeaderRoute(route.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// headerValueRegex returns the regular expression of an HTTPRouteGroup which matches the header value
+func headerValueRegex(value *v1alpha1.StringMatch) string {
+	switch {
+	case value == nil:
+		return ".*"
+	case value.Exact != "":
+		return "^" + regexp.QuoteMeta(value.Exact) + "$"
+	case value.Prefix != "":
+		return "^" + regexp.QuoteMeta(value.Prefix)
+	default:
+		return value.Regex
+	}
+}
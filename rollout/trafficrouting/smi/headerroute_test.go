@@ -0,0 +1,93 @@
+package smi
+
+import (
+	"context"
+	"testing"
+
+	smiv1alpha4 "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha4"
+	fake "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/split/clientset/versioned/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+	"github.com/argoproj/argo-rollouts/utils/record"
+)
+
+func newHeaderRouteReconciler(t *testing.T) (*Reconciler, *fake.Clientset) {
+	ro := fakeRollout("stable-service", "canary-service", "root-service", "traffic-split-name")
+	ro.Spec.Strategy.Canary.TrafficRouting.ManagedRoutes = []v1alpha1.MangedRoutes{{Name: "header"}}
+	client := fake.NewSimpleClientset()
+	listKinds := map[schema.GroupVersionResource]string{httpRouteGroupGVR(): "HTTPRouteGroupList"}
+	r, err := NewReconciler(ReconcilerConfig{
+		Rollout:        ro,
+		Client:         client,
+		Recorder:       record.NewFakeEventRecorder(),
+		ControllerKind: schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Rollout"},
+		DynamicClient:  dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds),
+	})
+	require.NoError(t, err)
+	return r, client
+}
+
+func TestSetHeaderRoute(t *testing.T) {
+	defaults.SetSMIAPIVersion("v1alpha4")
+	defer defaults.SetSMIAPIVersion(defaults.DefaultSMITrafficSplitVersion)
+	r, client := newHeaderRouteReconciler(t)
+	ctx := context.TODO()
+
+	headerRoute := &v1alpha1.SetHeaderRoute{
+		Name: "header",
+		Match: []v1alpha1.HeaderRoutingMatch{
+			{HeaderName: "agent", HeaderValue: &v1alpha1.StringMatch{Prefix: "chrome."}},
+			{HeaderName: "canary", HeaderValue: &v1alpha1.StringMatch{Exact: "true"}},
+		},
+	}
+	require.NoError(t, r.SetHeaderRoute(headerRoute))
+
+	routeGroup, err := r.routeGroups().Get(ctx, "traffic-split-name-header", metav1.GetOptions{})
+	require.NoError(t, err)
+	matches, _, _ := unstructured.NestedSlice(routeGroup.Object, "spec", "matches")
+	assert.Equal(t, []any{map[string]any{
+		"name":    "header",
+		"headers": map[string]any{"agent": `^chrome\.`, "canary": "^true$"},
+	}}, matches)
+
+	ts, err := client.SplitV1alpha4().TrafficSplits(metav1.NamespaceDefault).Get(ctx, "traffic-split-name-header", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "root-service", ts.Spec.Service)
+	assert.Equal(t, []smiv1alpha4.TrafficSplitBackend{{Service: "canary-service", Weight: 100}, {Service: "stable-service", Weight: 0}}, ts.Spec.Backends)
+	require.Len(t, ts.Spec.Matches, 1)
+	assert.Equal(t, HTTPRouteGroupKind, ts.Spec.Matches[0].Kind)
+	assert.Equal(t, "traffic-split-name-header", ts.Spec.Matches[0].Name)
+
+	// the header route is updated in place
+	headerRoute.Match = headerRoute.Match[:1]
+	require.NoError(t, r.SetHeaderRoute(headerRoute))
+	routeGroup, err = r.routeGroups().Get(ctx, "traffic-split-name-header", metav1.GetOptions{})
+	require.NoError(t, err)
+	matches, _, _ = unstructured.NestedSlice(routeGroup.Object, "spec", "matches")
+	assert.Equal(t, map[string]any{"agent": `^chrome\.`}, matches[0].(map[string]any)["headers"])
+
+	require.NoError(t, r.RemoveManagedRoutes())
+	_, err = client.SplitV1alpha4().TrafficSplits(metav1.NamespaceDefault).Get(ctx, "traffic-split-name-header", metav1.GetOptions{})
+	assert.True(t, k8serrors.IsNotFound(err))
+	_, err = r.routeGroups().Get(ctx, "traffic-split-name-header", metav1.GetOptions{})
+	assert.True(t, k8serrors.IsNotFound(err))
+
+	// removing a route which does not exist is a no-op
+	require.NoError(t, r.SetHeaderRoute(&v1alpha1.SetHeaderRoute{Name: "header"}))
+}
+
+func TestHeaderValueRegex(t *testing.T) {
+	assert.Equal(t, "^a\\.b$", headerValueRegex(&v1alpha1.StringMatch{Exact: "a.b"}))
+	assert.Equal(t, "^a\\.b", headerValueRegex(&v1alpha1.StringMatch{Prefix: "a.b"}))
+	assert.Equal(t, "a.*b", headerValueRegex(&v1alpha1.StringMatch{Regex: "a.*b"}))
+	assert.Equal(t, ".*", headerValueRegex(nil))
+}
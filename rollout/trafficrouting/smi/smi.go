@@ -7,6 +7,7 @@ import (
 	smiv1alpha1 "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha1"
 	smiv1alpha2 "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha2"
 	smiv1alpha3 "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha3"
+	smiv1alpha4 "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha4"
 	smiclientset "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/split/clientset/versioned"
 	"github.com/sirupsen/logrus"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -14,6 +15,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	patchtypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	"github.com/argoproj/argo-rollouts/utils/defaults"
@@ -33,6 +35,8 @@ type ReconcilerConfig struct {
 	Client         smiclientset.Interface
 	Recorder       record.EventRecorder
 	ControllerKind schema.GroupVersionKind
+	// DynamicClient manages the HTTPRouteGroups of the header routes
+	DynamicClient dynamic.Interface
 }
 
 // Reconciler holds required fields to reconcile SMI resources
@@ -42,6 +46,7 @@ type Reconciler struct {
 	getTrafficSplit            func(trafficSplitName string) (VersionedTrafficSplits, error)
 	createTrafficSplit         func(ts VersionedTrafficSplits) error
 	patchTrafficSplit          func(existing VersionedTrafficSplits, desired VersionedTrafficSplits) error
+	deleteTrafficSplit         func(trafficSplitName string) error
 	trafficSplitIsControlledBy func(ts VersionedTrafficSplits) bool
 }
 
@@ -49,6 +54,7 @@ type VersionedTrafficSplits struct {
 	ts1 *smiv1alpha1.TrafficSplit
 	ts2 *smiv1alpha2.TrafficSplit
 	ts3 *smiv1alpha3.TrafficSplit
+	ts4 *smiv1alpha4.TrafficSplit
 }
 
 // NewReconciler returns a reconciler struct that brings the SMI into the desired state
@@ -92,6 +98,9 @@ func NewReconciler(cfg ReconcilerConfig) (*Reconciler, error) {
 			_, err = r.cfg.Client.SplitV1alpha1().TrafficSplits(r.cfg.Rollout.Namespace).Patch(ctx, existing.ts1.Name, patchtypes.MergePatchType, patch, metav1.PatchOptions{})
 			return err
 		}
+		r.deleteTrafficSplit = func(trafficSplitName string) error {
+			return r.cfg.Client.SplitV1alpha1().TrafficSplits(r.cfg.Rollout.Namespace).Delete(ctx, trafficSplitName, metav1.DeleteOptions{})
+		}
 		r.trafficSplitIsControlledBy = func(ts VersionedTrafficSplits) bool {
 			return metav1.IsControlledBy(ts.ts1, r.cfg.Rollout)
 		}
@@ -128,6 +137,9 @@ func NewReconciler(cfg ReconcilerConfig) (*Reconciler, error) {
 			_, err = r.cfg.Client.SplitV1alpha2().TrafficSplits(r.cfg.Rollout.Namespace).Patch(ctx, existing.ts2.Name, patchtypes.MergePatchType, patch, metav1.PatchOptions{})
 			return err
 		}
+		r.deleteTrafficSplit = func(trafficSplitName string) error {
+			return r.cfg.Client.SplitV1alpha2().TrafficSplits(r.cfg.Rollout.Namespace).Delete(ctx, trafficSplitName, metav1.DeleteOptions{})
+		}
 		r.trafficSplitIsControlledBy = func(ts VersionedTrafficSplits) bool {
 			return metav1.IsControlledBy(ts.ts2, r.cfg.Rollout)
 		}
@@ -164,9 +176,51 @@ func NewReconciler(cfg ReconcilerConfig) (*Reconciler, error) {
 			_, err = r.cfg.Client.SplitV1alpha3().TrafficSplits(r.cfg.Rollout.Namespace).Patch(ctx, existing.ts3.Name, patchtypes.MergePatchType, patch, metav1.PatchOptions{})
 			return err
 		}
+		r.deleteTrafficSplit = func(trafficSplitName string) error {
+			return r.cfg.Client.SplitV1alpha3().TrafficSplits(r.cfg.Rollout.Namespace).Delete(ctx, trafficSplitName, metav1.DeleteOptions{})
+		}
 		r.trafficSplitIsControlledBy = func(ts VersionedTrafficSplits) bool {
 			return metav1.IsControlledBy(ts.ts3, r.cfg.Rollout)
 		}
+	case "v1alpha4":
+		r.getTrafficSplit = func(trafficSplitName string) (VersionedTrafficSplits, error) {
+			ts4, err := r.cfg.Client.SplitV1alpha4().TrafficSplits(r.cfg.Rollout.Namespace).Get(ctx, trafficSplitName, metav1.GetOptions{})
+			ts := VersionedTrafficSplits{}
+			if ts4 != nil {
+				ts.ts4 = ts4
+			}
+			return ts, err
+		}
+		r.createTrafficSplit = func(ts VersionedTrafficSplits) error {
+			_, err := r.cfg.Client.SplitV1alpha4().TrafficSplits(r.cfg.Rollout.Namespace).Create(ctx, ts.ts4, metav1.CreateOptions{})
+			return err
+		}
+		r.patchTrafficSplit = func(existing VersionedTrafficSplits, desired VersionedTrafficSplits) error {
+			patch, modified, err := diff.CreateTwoWayMergePatch(
+				smiv1alpha4.TrafficSplit{
+					Spec: existing.ts4.Spec,
+				},
+				smiv1alpha4.TrafficSplit{
+					Spec: desired.ts4.Spec,
+				},
+				smiv1alpha4.TrafficSplit{},
+			)
+			if err != nil {
+				panic(err)
+			}
+			if !modified {
+				r.log.Infof("Traffic Split `%s` was not modified", existing.ts4.Name)
+				return nil
+			}
+			_, err = r.cfg.Client.SplitV1alpha4().TrafficSplits(r.cfg.Rollout.Namespace).Patch(ctx, existing.ts4.Name, patchtypes.MergePatchType, patch, metav1.PatchOptions{})
+			return err
+		}
+		r.deleteTrafficSplit = func(trafficSplitName string) error {
+			return r.cfg.Client.SplitV1alpha4().TrafficSplits(r.cfg.Rollout.Namespace).Delete(ctx, trafficSplitName, metav1.DeleteOptions{})
+		}
+		r.trafficSplitIsControlledBy = func(ts VersionedTrafficSplits) bool {
+			return metav1.IsControlledBy(ts.ts4, r.cfg.Rollout)
+		}
 	default:
 		err := fmt.Errorf("Unsupported TrafficSplit API version `%s`", defaults.GetSMIAPIVersion())
 		return nil, err
@@ -185,11 +239,7 @@ func (r *Reconciler) Type() string {
 
 // SetWeight creates and modifies traffic splits based on the desired weight
 func (r *Reconciler) SetWeight(desiredWeight int32, additionalDestinations ...v1alpha1.WeightDestination) error {
-	// If TrafficSplitName not set, then set to Rollout name
-	trafficSplitName := r.cfg.Rollout.Spec.Strategy.Canary.TrafficRouting.SMI.TrafficSplitName
-	if trafficSplitName == "" {
-		trafficSplitName = r.cfg.Rollout.Name
-	}
+	trafficSplitName := r.trafficSplitName()
 	trafficSplits := r.generateTrafficSplits(trafficSplitName, desiredWeight, additionalDestinations...)
 
 	// Check if Traffic Split exists in namespace
@@ -218,10 +268,6 @@ func (r *Reconciler) SetWeight(desiredWeight int32, additionalDestinations ...v1
 	return r.patchTrafficSplit(existingTrafficSplit, trafficSplits)
 }
 
-func (r *Reconciler) SetHeaderRoute(headerRouting *v1alpha1.SetHeaderRoute) error {
-	return nil
-}
-
 func (r *Reconciler) generateTrafficSplits(trafficSplitName string, desiredWeight int32, additionalDestinations ...v1alpha1.WeightDestination) VersionedTrafficSplits {
 	// If root service not set, then set root service to be stable service
 	rootSvc := r.cfg.Rollout.Spec.Strategy.Canary.TrafficRouting.SMI.RootService
@@ -240,6 +286,8 @@ func (r *Reconciler) generateTrafficSplits(trafficSplitName string, desiredWeigh
 		trafficSplits.ts2 = trafficSplitV1Alpha2(r.cfg.Rollout, objectMeta, rootSvc, desiredWeight, additionalDestinations...)
 	case "v1alpha3":
 		trafficSplits.ts3 = trafficSplitV1Alpha3(r.cfg.Rollout, objectMeta, rootSvc, desiredWeight, additionalDestinations...)
+	case "v1alpha4":
+		trafficSplits.ts4 = trafficSplitV1Alpha4(r.cfg.Rollout, objectMeta, rootSvc, desiredWeight, additionalDestinations...)
 	}
 	return trafficSplits
 }
@@ -347,6 +395,37 @@ func trafficSplitV1Alpha3(ro *v1alpha1.Rollout, objectMeta metav1.ObjectMeta, ro
 	}
 }
 
+func trafficSplitV1Alpha4(ro *v1alpha1.Rollout, objectMeta metav1.ObjectMeta, rootSvc string, desiredWeight int32, additionalDestinations ...v1alpha1.WeightDestination) *smiv1alpha4.TrafficSplit {
+	backends := []smiv1alpha4.TrafficSplitBackend{{
+		Service: ro.Spec.Strategy.Canary.CanaryService,
+		Weight:  int(desiredWeight),
+	}}
+	stableWeight := int(100 - desiredWeight)
+	for _, dest := range additionalDestinations {
+		// Create backend entry
+		backends = append(backends, smiv1alpha4.TrafficSplitBackend{
+			Service: dest.ServiceName,
+			Weight:  int(dest.Weight),
+		})
+		// Update stableWeight
+		stableWeight -= int(dest.Weight)
+	}
+
+	// Add stable backend with fully updated stableWeight
+	backends = append(backends, smiv1alpha4.TrafficSplitBackend{
+		Service: ro.Spec.Strategy.Canary.StableService,
+		Weight:  stableWeight,
+	})
+
+	return &smiv1alpha4.TrafficSplit{
+		ObjectMeta: objectMeta,
+		Spec: smiv1alpha4.TrafficSplitSpec{
+			Service:  rootSvc,
+			Backends: backends,
+		},
+	}
+}
+
 // UpdateHash informs a traffic routing reconciler about new canary/stable pod hashes
 func (r *Reconciler) UpdateHash(canaryHash, stableHash string, additionalDestinations ...v1alpha1.WeightDestination) error {
 	return nil
@@ -355,7 +434,3 @@ func (r *Reconciler) UpdateHash(canaryHash, stableHash string, additionalDestina
 func (r *Reconciler) SetMirrorRoute(setMirrorRoute *v1alpha1.SetMirrorRoute) error {
 	return nil
 }
-
-func (r *Reconciler) RemoveManagedRoutes() error {
-	return nil
-}
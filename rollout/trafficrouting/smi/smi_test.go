@@ -9,6 +9,7 @@ import (
 	smiv1alpha1 "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha1"
 	smiv1alpha2 "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha2"
 	smiv1alpha3 "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha3"
+	smiv1alpha4 "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha4"
 	fake "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/split/clientset/versioned/fake"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -171,6 +172,32 @@ func TestReconcileCreateNewTrafficSplit(t *testing.T) {
 		expectedTs3 := trafficSplitV1Alpha3(ro, objectMeta, "root-service", desiredWeight)
 		assert.Equal(t, expectedTs3, ts3)
 	})
+
+	t.Run("v1alpha4", func(t *testing.T) {
+		ro := fakeRollout("stable-service", "canary-service", "root-service", "traffic-split-name")
+		client := fake.NewSimpleClientset()
+		defaults.SetSMIAPIVersion("v1alpha4")
+		defer defaults.SetSMIAPIVersion(defaults.DefaultSMITrafficSplitVersion)
+		r, err := NewReconciler(ReconcilerConfig{
+			Rollout:        ro,
+			Client:         client,
+			Recorder:       record.NewFakeEventRecorder(),
+			ControllerKind: schema.GroupVersionKind{},
+		})
+		assert.Nil(t, err)
+
+		err = r.SetWeight(desiredWeight)
+		assert.Nil(t, err)
+		actions := client.Actions()
+		assert.Len(t, actions, 2)
+		assert.Equal(t, "get", actions[0].GetVerb())
+		assert.Equal(t, "create", actions[1].GetVerb())
+
+		ts4 := actions[1].(core.CreateAction).GetObject().(*smiv1alpha4.TrafficSplit)
+		objectMeta := objectMeta("traffic-split-name", ro, r.cfg.ControllerKind)
+		expectedTs4 := trafficSplitV1Alpha4(ro, objectMeta, "root-service", desiredWeight)
+		assert.Equal(t, expectedTs4, ts4)
+	})
 }
 
 func TestReconcilePatchExistingTrafficSplit(t *testing.T) {
@@ -578,7 +605,7 @@ func TestCreateTrafficSplitForMultipleBackends(t *testing.T) {
 }
 
 func TestReconcileSetHeaderRoute(t *testing.T) {
-	t.Run("not implemented", func(t *testing.T) {
+	t.Run("unsupported API version", func(t *testing.T) {
 		ro := fakeRollout("stable-service", "canary-service", "", "")
 		client := fake.NewSimpleClientset()
 		r, err := NewReconciler(ReconcilerConfig{
@@ -598,6 +625,7 @@ func TestReconcileSetHeaderRoute(t *testing.T) {
 				},
 			}},
 		})
+		// the header route is left to the other traffic routers of the rollout
 		assert.Nil(t, err)
 
 		err = r.RemoveManagedRoutes()
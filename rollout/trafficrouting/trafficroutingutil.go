@@ -22,3 +22,28 @@ type TrafficRoutingReconciler interface {
 	// Type returns the type of the traffic routing reconciler
 	Type() string
 }
+
+// WatchedWeightVerifier is implemented by the traffic routing reconcilers which verify the weight from the status of
+// resources watched by the controller. Since a change of such a status enqueues the rollout, an unverified weight does
+// not have to be verified again periodically, which avoids polling the APIs of the traffic router. Only the Istio
+// reconciler implements it. The other reconcilers which verify the weight, e.g. ALB, are polled.
+type WatchedWeightVerifier interface {
+	// WeightVerificationWatched returns whether the last weight verification was decided by the status of watched resources
+	WeightVerificationWatched() bool
+}
+
+// HealthChecker is implemented by the traffic routing reconcilers which report the health of the traffic router, such as
+// the plugins. Once a rollout is fully promoted, the old ReplicaSets are only scaled down after the traffic router of
+// such a reconciler is healthy and verified the weight.
+type HealthChecker interface {
+	// Healthz returns an error if the traffic router is not able to route the traffic of the rollout
+	Healthz() error
+}
+
+// ExperimentHeaderRouter is implemented by the traffic routing reconcilers which route the requests matching the header
+// route of an experiment template to the service of the template, rather than to the canary service.
+type ExperimentHeaderRouter interface {
+	// SetExperimentHeaderRoute routes the requests matching the header route to the destination, or removes the route
+	// if the destination is nil
+	SetExperimentHeaderRoute(headerRoute *v1alpha1.SetHeaderRoute, destination *v1alpha1.WeightDestination) error
+}
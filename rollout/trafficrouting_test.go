@@ -2,6 +2,7 @@ package rollout
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -27,6 +29,7 @@ import (
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	"github.com/argoproj/argo-rollouts/rollout/mocks"
+	"github.com/argoproj/argo-rollouts/rollout/trafficrouting"
 	"github.com/argoproj/argo-rollouts/rollout/trafficrouting/alb"
 	apisixMocks "github.com/argoproj/argo-rollouts/rollout/trafficrouting/apisix/mocks"
 	"github.com/argoproj/argo-rollouts/rollout/trafficrouting/appmesh"
@@ -40,7 +43,9 @@ import (
 	ingressutil "github.com/argoproj/argo-rollouts/utils/ingress"
 	istioutil "github.com/argoproj/argo-rollouts/utils/istio"
 	logutil "github.com/argoproj/argo-rollouts/utils/log"
+	"github.com/argoproj/argo-rollouts/utils/record"
 	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
+	rolloututil "github.com/argoproj/argo-rollouts/utils/rollout"
 	timeutil "github.com/argoproj/argo-rollouts/utils/time"
 	unstructuredutil "github.com/argoproj/argo-rollouts/utils/unstructured"
 )
@@ -141,6 +146,51 @@ func TestReconcileTrafficRoutingVerifyWeightFalse(t *testing.T) {
 	assert.True(t, enqueued)
 }
 
+// verify an event is emitted once the desired weight is verified
+func TestReconcileTrafficRoutingVerifyWeightTrue(t *testing.T) {
+	f, ro := newTrafficWeightFixture(t)
+	defer f.Close()
+	f.fakeTrafficRouting = newUnmockedFakeTrafficRoutingReconciler()
+	f.fakeTrafficRouting.On("UpdateHash", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	f.fakeTrafficRouting.On("SetWeight", mock.Anything, mock.Anything).Return(nil)
+	f.fakeTrafficRouting.On("SetHeaderRoute", mock.Anything, mock.Anything).Return(nil)
+	f.fakeTrafficRouting.On("VerifyWeight", mock.Anything).Return(ptr.To[bool](true), nil)
+	f.expectPatchRolloutAction(ro)
+	f.run(getKey(ro, t))
+	assert.Contains(t, f.events, conditions.WeightVerifiedReason)
+}
+
+// watchedTrafficRoutingReconciler verifies the weight from the status of watched resources
+type watchedTrafficRoutingReconciler struct {
+	*mocks.TrafficRoutingReconciler
+}
+
+func (r watchedTrafficRoutingReconciler) WeightVerificationWatched() bool {
+	return true
+}
+
+// verify we do not requeue when VerifyWeight returns false from the status of watched resources
+func TestReconcileTrafficRoutingVerifyWeightWatched(t *testing.T) {
+	f, ro := newTrafficWeightFixture(t)
+	defer f.Close()
+	f.fakeTrafficRouting = newUnmockedFakeTrafficRoutingReconciler()
+	f.fakeTrafficRouting.On("UpdateHash", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	f.fakeTrafficRouting.On("SetWeight", mock.Anything, mock.Anything).Return(nil)
+	f.fakeTrafficRouting.On("SetHeaderRoute", mock.Anything, mock.Anything).Return(nil)
+	f.fakeTrafficRouting.On("VerifyWeight", mock.Anything).Return(ptr.To[bool](false), nil)
+	c, i, k8sI := f.newController(noResyncPeriodFunc)
+	c.newTrafficRoutingReconciler = func(roCtx *rolloutContext) ([]trafficrouting.TrafficRoutingReconciler, error) {
+		return []trafficrouting.TrafficRoutingReconciler{watchedTrafficRoutingReconciler{f.fakeTrafficRouting}}, nil
+	}
+	enqueued := false
+	c.enqueueRolloutAfter = func(obj any, duration time.Duration) {
+		enqueued = true
+	}
+	f.expectPatchRolloutAction(ro)
+	f.runController(getKey(ro, t), true, false, c, i, k8sI)
+	assert.False(t, enqueued)
+}
+
 func TestReconcileTrafficRoutingVerifyWeightEndOfRollout(t *testing.T) {
 	f := newFixture(t)
 	defer f.Close()
@@ -243,6 +293,137 @@ func TestRolloutUseDesiredWeight(t *testing.T) {
 	f.run(getKey(r2, t))
 }
 
+func TestRolloutUseDesiredWeightWithKnative(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	steps := []v1alpha1.CanaryStep{
+		{
+			SetWeight: ptr.To[int32](10),
+		},
+		{
+			Pause: &v1alpha1.RolloutPause{},
+		},
+	}
+	r1 := newCanaryRollout("foo", 0, nil, steps, ptr.To[int32](1), intstr.FromInt(1), intstr.FromInt(0))
+	r1.Spec.Strategy.Canary.TrafficRouting = &v1alpha1.RolloutTrafficRouting{Knative: &v1alpha1.KnativeTrafficRouting{Service: "foo"}}
+	r2 := bumpVersion(r1)
+
+	progressingCondition, _ := newProgressingCondition(conditions.RolloutPausedReason, r2, "")
+	conditions.SetRolloutCondition(&r2.Status, progressingCondition)
+	pausedCondition, _ := newPausedCondition(true)
+	conditions.SetRolloutCondition(&r2.Status, pausedCondition)
+
+	rs1 := newReplicaSetWithStatus(r1, 0, 0)
+	rs2 := newReplicaSetWithStatus(r2, 0, 0)
+	rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	f.kubeobjects = append(f.kubeobjects, rs1, rs2)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+
+	r2 = updateCanaryRolloutStatus(r2, rs1PodHash, 0, 0, 0, true)
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.objects = append(f.objects, r2)
+
+	f.expectPatchRolloutAction(r2)
+
+	// the canary revision of the Knative Service serves the traffic although the canary ReplicaSet has no pods
+	f.fakeTrafficRouting = newUnmockedFakeTrafficRoutingReconciler()
+	f.fakeTrafficRouting.On("UpdateHash", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	f.fakeTrafficRouting.On("SetWeight", mock.Anything, mock.Anything).Return(func(desiredWeight int32, additionalDestinations ...v1alpha1.WeightDestination) error {
+		assert.Equal(t, int32(10), desiredWeight)
+		return nil
+	})
+	f.fakeTrafficRouting.On("SetHeaderRoute", mock.Anything, mock.Anything).Return(nil)
+	f.fakeTrafficRouting.On("RemoveManagedRoutes").Return(nil)
+	f.fakeTrafficRouting.On("VerifyWeight", mock.Anything).Return(ptr.To[bool](true), nil)
+	f.run(getKey(r2, t))
+	f.fakeTrafficRouting.AssertCalled(t, "SetWeight", int32(10))
+}
+
+func TestRolloutUseWeightOverride(t *testing.T) {
+	tests := []struct {
+		name           string
+		weightOverride *v1alpha1.WeightOverride
+		expectedWeight int32
+		expectCleared  bool
+	}{
+		{
+			name:           "OverrideOfCurrentStep",
+			weightOverride: &v1alpha1.WeightOverride{Weight: 0, StepIndex: 1},
+			expectedWeight: 0,
+		},
+		{
+			name:           "OverrideOfPreviousStep",
+			weightOverride: &v1alpha1.WeightOverride{Weight: 50, StepIndex: 0},
+			expectedWeight: 10,
+			expectCleared:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := newFixture(t)
+			defer f.Close()
+
+			steps := []v1alpha1.CanaryStep{
+				{
+					SetWeight: ptr.To[int32](10),
+				},
+				{
+					Pause: &v1alpha1.RolloutPause{},
+				},
+			}
+			r1 := newCanaryRollout("foo", 10, nil, steps, ptr.To[int32](1), intstr.FromInt(1), intstr.FromInt(0))
+			r2 := bumpVersion(r1)
+			r2.Spec.Strategy.Canary.TrafficRouting = &v1alpha1.RolloutTrafficRouting{}
+			r2.Spec.Strategy.Canary.CanaryService = "canary"
+			r2.Spec.Strategy.Canary.StableService = "stable"
+
+			progressingCondition, _ := newProgressingCondition(conditions.RolloutPausedReason, r2, "")
+			conditions.SetRolloutCondition(&r2.Status, progressingCondition)
+
+			pausedCondition, _ := newPausedCondition(true)
+			conditions.SetRolloutCondition(&r2.Status, pausedCondition)
+
+			rs1 := newReplicaSetWithStatus(r1, 10, 10)
+			rs2 := newReplicaSetWithStatus(r2, 1, 1)
+
+			rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+			rs2PodHash := rs2.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+			canarySelector := map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: rs2PodHash}
+			stableSelector := map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: rs1PodHash}
+			canarySvc := newService("canary", 80, canarySelector, r2)
+			stableSvc := newService("stable", 80, stableSelector, r2)
+
+			f.kubeobjects = append(f.kubeobjects, rs1, rs2, canarySvc, stableSvc)
+			f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+
+			r2 = updateCanaryRolloutStatus(r2, rs1PodHash, 10, 0, 10, true)
+			r2.Status.Canary.WeightOverride = test.weightOverride
+			f.rolloutLister = append(f.rolloutLister, r2)
+			f.objects = append(f.objects, r2)
+
+			patchIndex := f.expectPatchRolloutAction(r2)
+
+			f.fakeTrafficRouting = newUnmockedFakeTrafficRoutingReconciler()
+			f.fakeTrafficRouting.On("UpdateHash", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			f.fakeTrafficRouting.On("SetWeight", mock.Anything, mock.Anything).Return(func(desiredWeight int32, additionalDestinations ...v1alpha1.WeightDestination) error {
+				assert.Equal(t, test.expectedWeight, desiredWeight)
+				return nil
+			})
+			f.fakeTrafficRouting.On("SetHeaderRoute", mock.Anything, mock.Anything).Return(nil)
+			f.fakeTrafficRouting.On("VerifyWeight", mock.Anything).Return(ptr.To[bool](true), nil)
+			f.run(getKey(r2, t))
+
+			patch := f.getPatchedRollout(patchIndex)
+			if test.expectCleared {
+				assert.Contains(t, patch, `"weightOverride":null`)
+			} else {
+				assert.NotContains(t, patch, `weightOverride`)
+			}
+		})
+	}
+}
+
 func TestRolloutUseDesiredWeight100(t *testing.T) {
 	f := newFixture(t)
 	defer f.Close()
@@ -853,6 +1034,7 @@ func TestCanaryWithTrafficRoutingAddScaleDownDelay(t *testing.T) {
 
 	f.kubeobjects = append(f.kubeobjects, rs1, rs2, canarySvc, stableSvc)
 	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	r2.Status.Health = rolloututil.CalculateRolloutHealth(r2.Spec, r2.Status)
 	f.rolloutLister = append(f.rolloutLister, r2)
 	f.objects = append(f.objects, r2)
 
@@ -2289,3 +2471,232 @@ func TestDynamicStableScaleNewCanarySupersedeShouldNotOverloadStable(t *testing.
 			"checkReplicasAvailable should return early. Calls observed: %v", setWeightCalls)
 	f.fakeTrafficRouting.AssertNotCalled(t, "UpdateHash", mock.Anything, mock.Anything, mock.Anything)
 }
+
+func TestRecordWeightHistory(t *testing.T) {
+	r := newCanaryRollout("foo", 10, nil, nil, ptr.To[int32](1), intstr.FromInt(1), intstr.FromInt(0))
+	r.Status.Canary.WeightHistory = []v1alpha1.TrafficWeightRecord{
+		{Router: "ALB", DesiredWeight: 10, Verified: ptr.To(true)},
+		{Router: "Istio", DesiredWeight: 10},
+	}
+	roCtx := &rolloutContext{rollout: r}
+	roCtx.newStatus.Canary.WeightHistory = r.Status.Canary.WeightHistory
+
+	// unchanged weights are not recorded again
+	roCtx.recordWeightHistory("ALB", 10, ptr.To(true))
+	roCtx.recordWeightHistory("Istio", 10, nil)
+	assert.Len(t, roCtx.newStatus.Canary.WeightHistory, 2)
+
+	roCtx.recordWeightHistory("ALB", 20, ptr.To(false))
+	roCtx.recordWeightHistory("ALB", 20, ptr.To(true))
+	history := roCtx.newStatus.Canary.WeightHistory
+	assert.Len(t, history, 4)
+	assert.Equal(t, "ALB", history[2].Router)
+	assert.Equal(t, int32(20), history[2].DesiredWeight)
+	assert.False(t, *history[2].Verified)
+	assert.True(t, *history[3].Verified)
+	assert.False(t, history[3].Time.IsZero())
+	// the history of the rollout is left untouched
+	assert.Len(t, r.Status.Canary.WeightHistory, 2)
+
+	for i := int32(0); i < maxWeightHistory; i++ {
+		roCtx.recordWeightHistory("Istio", i, nil)
+	}
+	history = roCtx.newStatus.Canary.WeightHistory
+	assert.Len(t, history, maxWeightHistory)
+	assert.Equal(t, int32(maxWeightHistory-1), history[maxWeightHistory-1].DesiredWeight)
+}
+
+// healthCheckedTrafficRoutingReconciler reports the health of the traffic router, like the traffic router plugins
+type healthCheckedTrafficRoutingReconciler struct {
+	*mocks.TrafficRoutingReconciler
+	err error
+}
+
+func (r healthCheckedTrafficRoutingReconciler) Healthz() error {
+	return r.err
+}
+
+func TestVerifyRouterTargets(t *testing.T) {
+	newRolloutContext := func(fullyPromoted bool) (*rolloutContext, *record.FakeEventRecorder, *bool) {
+		r := newCanaryRollout("foo", 10, nil, nil, ptr.To[int32](1), intstr.FromInt(1), intstr.FromInt(0))
+		r.Status.StableRS = r.Status.CurrentPodHash
+		if !fullyPromoted {
+			r.Status.StableRS = "stable"
+		}
+		recorder := record.NewFakeEventRecorder()
+		enqueued := false
+		roCtx := &rolloutContext{
+			rollout: r,
+			log:     logutil.WithRollout(r),
+			reconcilerBase: reconcilerBase{
+				recorder: recorder,
+				enqueueRolloutAfter: func(obj any, duration time.Duration) {
+					enqueued = true
+				},
+			},
+		}
+		return roCtx, recorder, &enqueued
+	}
+	reconciler := newUnmockedFakeTrafficRoutingReconciler()
+
+	t.Run("healthy router", func(t *testing.T) {
+		roCtx, _, enqueued := newRolloutContext(true)
+		roCtx.verifyRouterTargets(healthCheckedTrafficRoutingReconciler{TrafficRoutingReconciler: reconciler}, ptr.To(true), nil)
+		assert.True(t, *roCtx.targetsVerified)
+		assert.False(t, *enqueued)
+	})
+
+	t.Run("unhealthy router", func(t *testing.T) {
+		roCtx, recorder, enqueued := newRolloutContext(true)
+		roCtx.verifyRouterTargets(healthCheckedTrafficRoutingReconciler{TrafficRoutingReconciler: reconciler, err: errors.New("route not found")}, nil, nil)
+		assert.False(t, *roCtx.targetsVerified)
+		assert.True(t, *enqueued)
+		assert.Equal(t, []string{conditions.TrafficRouterUnhealthyReason}, recorder.Events())
+	})
+
+	t.Run("weight not verified", func(t *testing.T) {
+		roCtx, _, enqueued := newRolloutContext(true)
+		roCtx.verifyRouterTargets(healthCheckedTrafficRoutingReconciler{TrafficRoutingReconciler: reconciler}, ptr.To(false), nil)
+		assert.False(t, *roCtx.targetsVerified)
+		assert.False(t, *enqueued)
+	})
+
+	t.Run("weight verification error", func(t *testing.T) {
+		roCtx, _, enqueued := newRolloutContext(true)
+		roCtx.verifyRouterTargets(healthCheckedTrafficRoutingReconciler{TrafficRoutingReconciler: reconciler}, nil, errors.New("verify error"))
+		assert.False(t, *roCtx.targetsVerified)
+		assert.True(t, *enqueued)
+	})
+
+	t.Run("unverified router is not overridden", func(t *testing.T) {
+		roCtx, _, _ := newRolloutContext(true)
+		roCtx.verifyRouterTargets(healthCheckedTrafficRoutingReconciler{TrafficRoutingReconciler: reconciler, err: errors.New("route not found")}, nil, nil)
+		roCtx.verifyRouterTargets(healthCheckedTrafficRoutingReconciler{TrafficRoutingReconciler: reconciler}, nil, nil)
+		assert.False(t, *roCtx.targetsVerified)
+	})
+
+	t.Run("rollout in progress", func(t *testing.T) {
+		roCtx, _, _ := newRolloutContext(false)
+		roCtx.verifyRouterTargets(healthCheckedTrafficRoutingReconciler{TrafficRoutingReconciler: reconciler, err: errors.New("route not found")}, nil, nil)
+		assert.Nil(t, roCtx.targetsVerified)
+	})
+
+	t.Run("router without health", func(t *testing.T) {
+		roCtx, _, _ := newRolloutContext(true)
+		roCtx.verifyRouterTargets(reconciler, nil, nil)
+		assert.Nil(t, roCtx.targetsVerified)
+	})
+}
+
+func TestCanProceedWithScaleDownAnnotationPlugin(t *testing.T) {
+	r := newCanaryRollout("foo", 10, nil, nil, ptr.To[int32](1), intstr.FromInt(1), intstr.FromInt(0))
+	r.Spec.Strategy.Canary.TrafficRouting = &v1alpha1.RolloutTrafficRouting{
+		Plugins: map[string]json.RawMessage{"argoproj-labs/test": []byte(`{}`)},
+	}
+	oldRS := newReplicaSetWithStatus(r, 10, 10)
+	roCtx := &rolloutContext{rollout: r, log: logutil.WithRollout(r)}
+
+	canProceed, err := roCtx.canProceedWithScaleDownAnnotation([]*appsv1.ReplicaSet{oldRS})
+	assert.NoError(t, err)
+	assert.True(t, canProceed)
+
+	roCtx.targetsVerified = ptr.To(false)
+	canProceed, err = roCtx.canProceedWithScaleDownAnnotation([]*appsv1.ReplicaSet{oldRS})
+	assert.NoError(t, err)
+	assert.False(t, canProceed)
+}
+
+// experimentHeaderRoutingReconciler records the header routes of the experiment templates, like the Istio reconciler
+type experimentHeaderRoutingReconciler struct {
+	*mocks.TrafficRoutingReconciler
+	routes map[string]*v1alpha1.WeightDestination
+}
+
+func (r experimentHeaderRoutingReconciler) SetExperimentHeaderRoute(headerRoute *v1alpha1.SetHeaderRoute, destination *v1alpha1.WeightDestination) error {
+	r.routes[headerRoute.Name] = destination
+	return nil
+}
+
+func TestReconcileExperimentHeaderRoutes(t *testing.T) {
+	headerRoute := func(name string) *v1alpha1.SetHeaderRoute {
+		return &v1alpha1.SetHeaderRoute{
+			Name:  name,
+			Match: []v1alpha1.HeaderRoutingMatch{{HeaderName: "variant", HeaderValue: &v1alpha1.StringMatch{Exact: name}}},
+		}
+	}
+	steps := []v1alpha1.CanaryStep{{
+		Experiment: &v1alpha1.RolloutExperimentStep{
+			Templates: []v1alpha1.RolloutExperimentTemplate{
+				{Name: "a", SpecRef: v1alpha1.CanarySpecRef, HeaderRoute: headerRoute("route-a")},
+				{Name: "b", SpecRef: v1alpha1.CanarySpecRef, HeaderRoute: headerRoute("route-b")},
+			},
+		},
+	}, {
+		Experiment: &v1alpha1.RolloutExperimentStep{
+			Templates: []v1alpha1.RolloutExperimentTemplate{
+				{Name: "c", SpecRef: v1alpha1.CanarySpecRef, HeaderRoute: headerRoute("route-c")},
+			},
+		},
+	}}
+	r := newCanaryRollout("foo", 10, nil, steps, ptr.To[int32](0), intstr.FromInt(1), intstr.FromInt(0))
+	ex := &v1alpha1.Experiment{
+		Status: v1alpha1.ExperimentStatus{
+			Phase: v1alpha1.AnalysisPhaseRunning,
+			TemplateStatuses: []v1alpha1.TemplateStatus{
+				{Name: "a", ServiceName: "foo-a", PodTemplateHash: "hash-a"},
+				{Name: "b", PodTemplateHash: "hash-b"},
+			},
+		},
+	}
+	roCtx := &rolloutContext{rollout: r, currentEx: ex, log: logutil.WithRollout(r)}
+
+	t.Run("router without experiment header routes", func(t *testing.T) {
+		assert.NoError(t, roCtx.reconcileExperimentHeaderRoutes(newUnmockedFakeTrafficRoutingReconciler()))
+	})
+
+	t.Run("running experiment", func(t *testing.T) {
+		reconciler := experimentHeaderRoutingReconciler{TrafficRoutingReconciler: newUnmockedFakeTrafficRoutingReconciler(), routes: map[string]*v1alpha1.WeightDestination{}}
+		assert.NoError(t, roCtx.reconcileExperimentHeaderRoutes(reconciler))
+		assert.Equal(t, map[string]*v1alpha1.WeightDestination{
+			"route-a": {ServiceName: "foo-a", PodTemplateHash: "hash-a", Weight: 100},
+			// the template has no service yet
+			"route-b": nil,
+			// the template of another experiment step
+			"route-c": nil,
+		}, reconciler.routes)
+	})
+
+	t.Run("completed experiment", func(t *testing.T) {
+		completedCtx := *roCtx
+		completedCtx.currentEx = ex.DeepCopy()
+		completedCtx.currentEx.Status.Phase = v1alpha1.AnalysisPhaseSuccessful
+		reconciler := experimentHeaderRoutingReconciler{TrafficRoutingReconciler: newUnmockedFakeTrafficRoutingReconciler(), routes: map[string]*v1alpha1.WeightDestination{}}
+		assert.NoError(t, completedCtx.reconcileExperimentHeaderRoutes(reconciler))
+		assert.Equal(t, map[string]*v1alpha1.WeightDestination{"route-a": nil, "route-b": nil, "route-c": nil}, reconciler.routes)
+	})
+
+	// the routes are removed while the experiment is still running, before its templates are scaled down
+	terminating := map[string]func(ex *v1alpha1.Experiment){
+		"terminating experiment": func(ex *v1alpha1.Experiment) {
+			ex.Spec.Terminate = true
+		},
+		"experiment past its duration": func(ex *v1alpha1.Experiment) {
+			availableAt := metav1.NewTime(timeutil.Now().Add(-time.Hour))
+			ex.Spec.Duration = "30m"
+			ex.Status.AvailableAt = &availableAt
+		},
+		"completed template": func(ex *v1alpha1.Experiment) {
+			ex.Status.TemplateStatuses[0].Status = v1alpha1.TemplateStatusSuccessful
+		},
+	}
+	for name, update := range terminating {
+		t.Run(name, func(t *testing.T) {
+			terminatingCtx := *roCtx
+			terminatingCtx.currentEx = ex.DeepCopy()
+			update(terminatingCtx.currentEx)
+			reconciler := experimentHeaderRoutingReconciler{TrafficRoutingReconciler: newUnmockedFakeTrafficRoutingReconciler(), routes: map[string]*v1alpha1.WeightDestination{}}
+			assert.NoError(t, terminatingCtx.reconcileExperimentHeaderRoutes(reconciler))
+			assert.Equal(t, map[string]*v1alpha1.WeightDestination{"route-a": nil, "route-b": nil, "route-c": nil}, reconciler.routes)
+		})
+	}
+}
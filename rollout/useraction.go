@@ -0,0 +1,29 @@
+package rollout
+
+import (
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	logutil "github.com/argoproj/argo-rollouts/utils/log"
+	"github.com/argoproj/argo-rollouts/utils/record"
+)
+
+// recordUserAction emits an audit event and log entry when the admission webhook recorded a new action of a user on
+// the rollout, i.e. a promotion, abort or retry
+func (c *Controller) recordUserAction(old, new *v1alpha1.Rollout) {
+	action := new.Status.LastUserAction
+	if action == nil || equality.Semantic.DeepEqual(old.Status.LastUserAction, action) {
+		return
+	}
+	logutil.WithRollout(new).WithFields(log.Fields{
+		"action": action.Action,
+		"user":   action.User,
+		"groups": strings.Join(action.Groups, ","),
+		"time":   action.Time.UTC(),
+	}).Info("Audit: rollout user action")
+	c.recorder.Eventf(new, record.EventOptions{EventReason: conditions.RolloutUserActionReason}, conditions.RolloutUserActionMessage, action.Action, action.User)
+}
@@ -0,0 +1,50 @@
+package rollout
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	"github.com/argoproj/argo-rollouts/utils/record"
+	timeutil "github.com/argoproj/argo-rollouts/utils/time"
+)
+
+func TestRecordUserAction(t *testing.T) {
+	recorder := record.NewFakeEventRecorder()
+	c := &Controller{reconcilerBase: reconcilerBase{recorder: recorder}}
+	old := newCanaryRollout("foo", 1, nil, nil, nil, intstr.FromInt(0), intstr.FromInt(1))
+	ro := old.DeepCopy()
+
+	// no event without a new action
+	c.recordUserAction(old, ro)
+	assert.Empty(t, recorder.Events())
+
+	ro.Status.LastUserAction = &v1alpha1.RolloutUserAction{
+		Action: v1alpha1.RolloutUserActionAbort,
+		User:   "alice",
+		Groups: []string{"system:authenticated"},
+		Time:   timeutil.MetaNow(),
+	}
+	c.recordUserAction(old, ro)
+	assert.Equal(t, []string{conditions.RolloutUserActionReason}, recorder.Events())
+
+	// the same action is only recorded once
+	c.recordUserAction(ro, ro.DeepCopy())
+	assert.Len(t, recorder.Events(), 1)
+}
+
+func TestUserActionIsKeptInStatus(t *testing.T) {
+	ro := newCanaryRollout("foo", 1, nil, nil, nil, intstr.FromInt(0), intstr.FromInt(1))
+	ro.Status.LastUserAction = &v1alpha1.RolloutUserAction{Action: v1alpha1.RolloutUserActionPromote, User: "alice"}
+	f := newFixture(t)
+	defer f.Close()
+	f.rolloutLister = append(f.rolloutLister, ro)
+	f.objects = append(f.objects, ro)
+	c, _, _ := f.newController(noResyncPeriodFunc)
+	roCtx, err := c.newRolloutContext(ro)
+	assert.NoError(t, err)
+	assert.Equal(t, ro.Status.LastUserAction, roCtx.newStatus.LastUserAction)
+}
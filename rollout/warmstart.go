@@ -0,0 +1,272 @@
+package rollout
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"slices"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	rolloututil "github.com/argoproj/argo-rollouts/utils/rollout"
+)
+
+const (
+	// warmStartConfigMapKey is the key of the ConfigMap data holding the warm start state of the rollouts
+	warmStartConfigMapKey = "rollouts"
+	// warmStartHandOffKey is the key of the ConfigMap data holding the time the previous leader handed off gracefully
+	warmStartHandOffKey = "handOffTime"
+	// maxWarmStartStateBytes is the maximum size of the persisted state, which leaves room for the metadata of the
+	// ConfigMap within its limit of 1MiB
+	maxWarmStartStateBytes = 900 * 1024
+)
+
+// WarmStartConfig configures the persistence of the state of the rollouts at their last successful reconciliation
+type WarmStartConfig struct {
+	// Namespace and Name of the ConfigMap the state is persisted to
+	Namespace string
+	Name      string
+	// PersistInterval is the interval in which changes of the state are persisted
+	PersistInterval time.Duration
+	// Spread is the period over which the first reconciliations of the unchanged rollouts are spread after a restart
+	Spread time.Duration
+	// HandOffDelay is how long the first reconciliations of the unchanged rollouts are skipped after the previous
+	// leader handed off gracefully, typically the resync period of the rollouts
+	HandOffDelay time.Duration
+}
+
+// warmStartEntry is the state of a rollout at its last successful reconciliation
+type warmStartEntry struct {
+	Generation      int64  `json:"generation"`
+	ResourceVersion string `json:"resourceVersion"`
+	CurrentPodHash  string `json:"currentPodHash,omitempty"`
+	StableRS        string `json:"stableRS,omitempty"`
+}
+
+func newWarmStartEntry(ro *v1alpha1.Rollout) warmStartEntry {
+	return warmStartEntry{
+		Generation:      ro.Generation,
+		ResourceVersion: ro.ResourceVersion,
+		CurrentPodHash:  ro.Status.CurrentPodHash,
+		StableRS:        ro.Status.StableRS,
+	}
+}
+
+// warmStart remembers the state of each rollout at its last successful reconciliation and persists it to a
+// ConfigMap. After a restart of the controller, the first reconciliations of the fully promoted rollouts which did
+// not change since are spread over a period, instead of re-evaluating all rollouts at once. A nil warmStart
+// neither records nor defers anything.
+type warmStart struct {
+	kubeclientset kubernetes.Interface
+	config        WarmStartConfig
+
+	lock sync.Mutex
+	// observed holds the state of the rollouts at their last successful reconciliation
+	observed map[string]warmStartEntry
+	// dirty is whether observed changed since it was last persisted
+	dirty bool
+	// truncated is whether the state was truncated to fit into the ConfigMap when it was last persisted, so that the
+	// truncation is logged once
+	truncated bool
+	// deferredUntil holds the time until which the first reconciliation of an unchanged rollout is deferred, along
+	// with its restored state
+	deferredUntil map[string]deferredReconciliation
+}
+
+type deferredReconciliation struct {
+	entry warmStartEntry
+	until time.Time
+}
+
+func newWarmStart(kubeclientset kubernetes.Interface, config WarmStartConfig) *warmStart {
+	if config.PersistInterval <= 0 {
+		return nil
+	}
+	return &warmStart{
+		kubeclientset: kubeclientset,
+		config:        config,
+		observed:      map[string]warmStartEntry{},
+		deferredUntil: map[string]deferredReconciliation{},
+	}
+}
+
+// Restore reads the persisted state, so that the first reconciliations of the rollouts which did not change since
+// can be deferred. It must be called before the rollouts informer starts.
+func (w *warmStart) Restore(ctx context.Context, now time.Time) error {
+	if w == nil {
+		return nil
+	}
+	cm, err := w.kubeclientset.CoreV1().ConfigMaps(w.config.Namespace).Get(ctx, w.config.Name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get warm start configmap: %w", err)
+	}
+	entries := map[string]warmStartEntry{}
+	if data, ok := cm.Data[warmStartConfigMapKey]; ok {
+		if err := json.Unmarshal([]byte(data), &entries); err != nil {
+			return fmt.Errorf("failed to unmarshal warm start state: %w", err)
+		}
+	}
+	// after a graceful hand-off, the state is up to date and the previous leader reconciled the unchanged rollouts
+	// recently, so that their reconciliations can be skipped until they would have been resynced by it
+	start := now
+	if handOff, err := time.Parse(time.RFC3339, cm.Data[warmStartHandOffKey]); err == nil && now.Sub(handOff) < w.config.HandOffDelay {
+		start = handOff.Add(w.config.HandOffDelay)
+		log.Infof("Previous leader handed off gracefully at %s, skipping the reconciliations of the unchanged rollouts until %s", handOff, start)
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	for key, entry := range entries {
+		w.observed[key] = entry
+		var delay time.Duration
+		if w.config.Spread > 0 {
+			delay = time.Duration(rand.Int63n(int64(w.config.Spread)))
+		}
+		w.deferredUntil[key] = deferredReconciliation{entry: entry, until: start.Add(delay)}
+	}
+	log.Infof("Restored the warm start state of %d rollouts", len(entries))
+	return nil
+}
+
+// Defer returns how long the reconciliation of the rollout should be deferred. Only the first reconciliation after
+// a restart of a fully promoted rollout which did not change since its state was persisted is deferred.
+func (w *warmStart) Defer(key string, ro *v1alpha1.Rollout, now time.Time) time.Duration {
+	if w == nil {
+		return 0
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	deferred, ok := w.deferredUntil[key]
+	if !ok {
+		return 0
+	}
+	delay := deferred.until.Sub(now)
+	if delay <= 0 || deferred.entry != newWarmStartEntry(ro) || !rolloututil.IsFullyPromoted(ro) {
+		delete(w.deferredUntil, key)
+		return 0
+	}
+	return delay
+}
+
+// Observe records the state of the rollout after a successful reconciliation
+func (w *warmStart) Observe(key string, ro *v1alpha1.Rollout) {
+	if w == nil {
+		return
+	}
+	entry := newWarmStartEntry(ro)
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if prev, ok := w.observed[key]; ok && prev == entry {
+		return
+	}
+	w.observed[key] = entry
+	w.dirty = true
+}
+
+// Forget forgets the rollout, e.g. once it is deleted
+func (w *warmStart) Forget(key string) {
+	if w == nil {
+		return
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if _, ok := w.observed[key]; ok {
+		delete(w.observed, key)
+		w.dirty = true
+	}
+	delete(w.deferredUntil, key)
+}
+
+// Persist writes the state to the ConfigMap if it changed since it was last persisted
+func (w *warmStart) Persist(ctx context.Context) error {
+	return w.persist(ctx, time.Time{})
+}
+
+// HandOff writes the state to the ConfigMap along with the time of the hand-off, so that the next leader can skip
+// the reconciliations of the unchanged rollouts. It must only be called by the leader once it stopped reconciling.
+func (w *warmStart) HandOff(ctx context.Context, now time.Time) error {
+	return w.persist(ctx, now)
+}
+
+func (w *warmStart) persist(ctx context.Context, handOff time.Time) error {
+	if w == nil {
+		return nil
+	}
+	w.lock.Lock()
+	if !w.dirty && handOff.IsZero() {
+		w.lock.Unlock()
+		return nil
+	}
+	observed, dropped := capWarmStartState(w.observed, maxWarmStartStateBytes)
+	if dropped > 0 && !w.truncated {
+		log.Warnf("The warm start state of %d rollouts exceeds the size of a ConfigMap, the state of %d of them is not persisted and they are reconciled right away after a restart", len(w.observed), dropped)
+	}
+	w.truncated = dropped > 0
+	data, err := json.Marshal(observed)
+	w.dirty = false
+	w.lock.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal warm start state: %w", err)
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      w.config.Name,
+			Namespace: w.config.Namespace,
+		},
+		Data: map[string]string{
+			warmStartConfigMapKey: string(data),
+		},
+	}
+	if !handOff.IsZero() {
+		cm.Data[warmStartHandOffKey] = handOff.UTC().Format(time.RFC3339)
+	}
+	configMaps := w.kubeclientset.CoreV1().ConfigMaps(w.config.Namespace)
+	_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+	if k8serrors.IsNotFound(err) {
+		_, err = configMaps.Create(ctx, cm, metav1.CreateOptions{})
+	}
+	if err != nil {
+		w.lock.Lock()
+		w.dirty = true
+		w.lock.Unlock()
+		return fmt.Errorf("failed to persist warm start state: %w", err)
+	}
+	return nil
+}
+
+// capWarmStartState returns the entries of the state, in the order of their keys, whose JSON encoding fits into
+// maxBytes, along with the number of dropped entries
+func capWarmStartState(observed map[string]warmStartEntry, maxBytes int) (map[string]warmStartEntry, int) {
+	keys := make([]string, 0, len(observed))
+	for key := range observed {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	capped := make(map[string]warmStartEntry, len(observed))
+	// the braces of the object
+	size := 2
+	for i, key := range keys {
+		entry, err := json.Marshal(observed[key])
+		if err != nil {
+			continue
+		}
+		// the quoted key, the colon and the comma separating the entries
+		size += len(key) + len(entry) + 4
+		if size > maxBytes {
+			return capped, len(keys) - i
+		}
+		capped[key] = observed[key]
+	}
+	return capped, 0
+}
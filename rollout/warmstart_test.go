@@ -0,0 +1,218 @@
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+func newWarmStartRollout() *v1alpha1.Rollout {
+	return &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "foo",
+			Namespace:       metav1.NamespaceDefault,
+			Generation:      2,
+			ResourceVersion: "100",
+		},
+		Status: v1alpha1.RolloutStatus{
+			CurrentPodHash: "abc",
+			StableRS:       "abc",
+		},
+	}
+}
+
+func newTestWarmStart(objects ...*corev1.ConfigMap) *warmStart {
+	kubeclient := k8sfake.NewSimpleClientset()
+	for _, cm := range objects {
+		kubeclient.Tracker().Add(cm)
+	}
+	return newWarmStart(kubeclient, WarmStartConfig{
+		Namespace:       "argo-rollouts",
+		Name:            "argo-rollouts-warm-start",
+		PersistInterval: time.Second,
+		Spread:          time.Minute,
+	})
+}
+
+func TestWarmStartDisabled(t *testing.T) {
+	assert.Nil(t, newWarmStart(k8sfake.NewSimpleClientset(), WarmStartConfig{}))
+
+	var w *warmStart
+	ro := newWarmStartRollout()
+	assert.NoError(t, w.Restore(context.Background(), time.Now()))
+	w.Observe("default/foo", ro)
+	w.Forget("default/foo")
+	assert.Equal(t, time.Duration(0), w.Defer("default/foo", ro, time.Now()))
+	assert.NoError(t, w.Persist(context.Background()))
+}
+
+func TestWarmStartPersistAndRestore(t *testing.T) {
+	w := newTestWarmStart()
+	ro := newWarmStartRollout()
+	w.Observe("default/foo", ro)
+	require.NoError(t, w.Persist(context.Background()))
+
+	cm, err := w.kubeclientset.CoreV1().ConfigMaps("argo-rollouts").Get(context.Background(), "argo-rollouts-warm-start", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"default/foo":{"generation":2,"resourceVersion":"100","currentPodHash":"abc","stableRS":"abc"}}`, cm.Data[warmStartConfigMapKey])
+
+	// the configmap is updated once the state changes
+	ro.ResourceVersion = "101"
+	w.Observe("default/foo", ro)
+	require.NoError(t, w.Persist(context.Background()))
+	cm, err = w.kubeclientset.CoreV1().ConfigMaps("argo-rollouts").Get(context.Background(), "argo-rollouts-warm-start", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Contains(t, cm.Data[warmStartConfigMapKey], `"resourceVersion":"101"`)
+
+	restarted := newTestWarmStart(cm)
+	now := time.Now()
+	require.NoError(t, restarted.Restore(context.Background(), now))
+	delay := restarted.Defer("default/foo", ro, now)
+	assert.GreaterOrEqual(t, delay, time.Duration(0))
+	assert.Less(t, delay, time.Minute)
+	assert.Equal(t, w.observed, restarted.observed)
+}
+
+func TestWarmStartPersistOversizedState(t *testing.T) {
+	w := newTestWarmStart()
+	ro := newWarmStartRollout()
+	for i := 0; i < 10000; i++ {
+		ro.Name = fmt.Sprintf("rollout-with-a-rather-long-name-%05d", i)
+		w.Observe("namespace-of-the-rollouts/"+ro.Name, ro)
+	}
+	require.NoError(t, w.Persist(context.Background()))
+	assert.False(t, w.dirty)
+	assert.True(t, w.truncated)
+
+	// the state is truncated to fit into the configmap
+	cm, err := w.kubeclientset.CoreV1().ConfigMaps("argo-rollouts").Get(context.Background(), "argo-rollouts-warm-start", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(cm.Data[warmStartConfigMapKey]), maxWarmStartStateBytes)
+	restarted := newTestWarmStart(cm)
+	require.NoError(t, restarted.Restore(context.Background(), time.Now()))
+	assert.Greater(t, len(restarted.observed), 5000)
+	assert.Less(t, len(restarted.observed), 10000)
+	for key, entry := range restarted.observed {
+		assert.Equal(t, w.observed[key], entry)
+	}
+
+	// the rollouts whose state was not persisted are reconciled right away
+	ro.Name = "rollout-with-a-rather-long-name-09999"
+	assert.Equal(t, time.Duration(0), restarted.Defer("namespace-of-the-rollouts/"+ro.Name, ro, time.Now()))
+}
+
+func TestWarmStartHandOff(t *testing.T) {
+	w := newTestWarmStart()
+	ro := newWarmStartRollout()
+	w.Observe("default/foo", ro)
+	require.NoError(t, w.Persist(context.Background()))
+
+	// the state is written on hand-off even if it did not change
+	handOff := time.Now().Add(-time.Minute).Truncate(time.Second)
+	require.NoError(t, w.HandOff(context.Background(), handOff))
+	cm, err := w.kubeclientset.CoreV1().ConfigMaps("argo-rollouts").Get(context.Background(), "argo-rollouts-warm-start", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, handOff.UTC().Format(time.RFC3339), cm.Data[warmStartHandOffKey])
+
+	// the unchanged rollouts are skipped until the previous leader would have resynced them
+	restarted := newTestWarmStart(cm)
+	restarted.config.HandOffDelay = 15 * time.Minute
+	now := time.Now()
+	require.NoError(t, restarted.Restore(context.Background(), now))
+	delay := restarted.Defer("default/foo", ro, now)
+	assert.GreaterOrEqual(t, delay, handOff.Add(15*time.Minute).Sub(now))
+	assert.Less(t, delay, handOff.Add(16*time.Minute).Sub(now))
+
+	// a hand-off older than the delay is ignored
+	expired := newTestWarmStart(cm)
+	expired.config.HandOffDelay = 30 * time.Second
+	require.NoError(t, expired.Restore(context.Background(), now))
+	assert.Less(t, expired.Defer("default/foo", ro, now), time.Minute)
+
+	// the next periodic persist removes the hand-off time
+	ro.ResourceVersion = "101"
+	w.Observe("default/foo", ro)
+	require.NoError(t, w.Persist(context.Background()))
+	cm, err = w.kubeclientset.CoreV1().ConfigMaps("argo-rollouts").Get(context.Background(), "argo-rollouts-warm-start", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, cm.Data, warmStartHandOffKey)
+}
+
+func TestWarmStartRestoreWithoutConfigMap(t *testing.T) {
+	w := newTestWarmStart()
+	assert.NoError(t, w.Restore(context.Background(), time.Now()))
+	assert.Empty(t, w.deferredUntil)
+}
+
+func TestWarmStartDefer(t *testing.T) {
+	ro := newWarmStartRollout()
+	now := time.Now()
+	newDeferred := func() *warmStart {
+		w := newTestWarmStart()
+		w.deferredUntil["default/foo"] = deferredReconciliation{entry: newWarmStartEntry(ro), until: now.Add(time.Minute)}
+		return w
+	}
+
+	t.Run("unchanged rollout is deferred", func(t *testing.T) {
+		w := newDeferred()
+		assert.Equal(t, time.Minute, w.Defer("default/foo", ro, now))
+		assert.Equal(t, 30*time.Second, w.Defer("default/foo", ro, now.Add(30*time.Second)))
+		assert.Equal(t, time.Duration(0), w.Defer("default/foo", ro, now.Add(time.Minute)))
+		// the rollout is only deferred once
+		assert.Equal(t, time.Duration(0), w.Defer("default/foo", ro, now))
+	})
+	t.Run("changed rollout is not deferred", func(t *testing.T) {
+		w := newDeferred()
+		changed := ro.DeepCopy()
+		changed.ResourceVersion = "101"
+		assert.Equal(t, time.Duration(0), w.Defer("default/foo", changed, now))
+		assert.Equal(t, time.Duration(0), w.Defer("default/foo", ro, now))
+	})
+	t.Run("progressing rollout is not deferred", func(t *testing.T) {
+		progressing := ro.DeepCopy()
+		progressing.Status.CurrentPodHash = "def"
+		w := newTestWarmStart()
+		w.deferredUntil["default/foo"] = deferredReconciliation{entry: newWarmStartEntry(progressing), until: now.Add(time.Minute)}
+		assert.Equal(t, time.Duration(0), w.Defer("default/foo", progressing, now))
+	})
+	t.Run("unknown rollout is not deferred", func(t *testing.T) {
+		w := newDeferred()
+		assert.Equal(t, time.Duration(0), w.Defer("default/bar", ro, now))
+	})
+	t.Run("forgotten rollout is not deferred", func(t *testing.T) {
+		w := newDeferred()
+		w.Forget("default/foo")
+		assert.Equal(t, time.Duration(0), w.Defer("default/foo", ro, now))
+	})
+}
+
+func TestSyncHandlerDefersUnchangedRolloutAfterRestart(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	r := newCanaryRollout("foo", 10, nil, nil, int32Ptr(0), intstr.FromInt(1), intstr.FromInt(0))
+	r.ResourceVersion = "100"
+	r.Status.CurrentPodHash = "abc"
+	r.Status.StableRS = "abc"
+	f.rolloutLister = append(f.rolloutLister, r)
+	f.objects = append(f.objects, r)
+
+	c, _, _ := f.newController(noResyncPeriodFunc)
+	roKey := getKey(r, t)
+	c.warmStart = newTestWarmStart()
+	c.warmStart.deferredUntil[roKey] = deferredReconciliation{entry: newWarmStartEntry(r), until: time.Now().Add(time.Minute)}
+
+	assert.NoError(t, c.syncHandler(context.Background(), roKey))
+	assert.Empty(t, f.client.Actions())
+	assert.Equal(t, 1, f.enqueuedObjects[roKey])
+}
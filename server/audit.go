@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj/argo-rollouts/pkg/apiclient/rollout"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/info"
+	timeutil "github.com/argoproj/argo-rollouts/utils/time"
+)
+
+const (
+	// AuditEventReason is the reason of the events recording the actions taken on rollouts through the dashboard
+	AuditEventReason = "DashboardAction"
+	// auditEventComponent is the source of the events recording the actions
+	auditEventComponent = "argo-rollouts-dashboard"
+
+	auditActionAnnotation     = "argo-rollouts.argoproj.io/audit-action"
+	auditUserAnnotation       = "argo-rollouts.argoproj.io/audit-user"
+	auditPriorStateAnnotation = "argo-rollouts.argoproj.io/audit-prior-state"
+)
+
+// audited takes an action on a rollout and records it as an event of the rollout, with the user who took it and the
+// state of the rollout before the action. The action is not recorded when it fails.
+func (s *ArgoRolloutsServer) audited(ctx context.Context, namespace, name, action, description string, act func() (*v1alpha1.Rollout, error)) (*v1alpha1.Rollout, error) {
+	prior, err := s.Options.RolloutsClientset.ArgoprojV1alpha1().Rollouts(namespace).Get(ctx, name, v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	ro, err := act()
+	if err != nil {
+		return nil, err
+	}
+	s.recordAction(ctx, prior, action, description)
+	return ro, nil
+}
+
+// recordAction records an action taken on a rollout as an event of the rollout. Failing to record the action is only
+// logged, since the action was taken.
+func (s *ArgoRolloutsServer) recordAction(ctx context.Context, prior *v1alpha1.Rollout, action, description string) {
+	username := s.username(ctx)
+	actor := "a user of the dashboard"
+	if username != "" {
+		actor = fmt.Sprintf("user '%s'", username)
+	}
+	now := timeutil.MetaNow()
+	event := &corev1.Event{
+		ObjectMeta: v1.ObjectMeta{
+			GenerateName: prior.Name + "-",
+			Namespace:    prior.Namespace,
+			Annotations: map[string]string{
+				auditActionAnnotation:     action,
+				auditUserAnnotation:       username,
+				auditPriorStateAnnotation: priorState(prior),
+			},
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion:      v1alpha1.SchemeGroupVersion.String(),
+			Kind:            "Rollout",
+			Namespace:       prior.Namespace,
+			Name:            prior.Name,
+			UID:             prior.UID,
+			ResourceVersion: prior.ResourceVersion,
+		},
+		Reason:         AuditEventReason,
+		Message:        fmt.Sprintf("%s %s", actor, description),
+		Type:           corev1.EventTypeNormal,
+		Source:         corev1.EventSource{Component: auditEventComponent},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+	if _, err := s.Options.KubeClientset.CoreV1().Events(prior.Namespace).Create(ctx, event, v1.CreateOptions{}); err != nil {
+		log.Warnf("failed to record %s action on rollout '%s/%s': %v", action, prior.Namespace, prior.Name, err)
+	}
+}
+
+// username returns the name of the user logged in to the dashboard, or an empty string when the users do not log in
+func (s *ArgoRolloutsServer) username(ctx context.Context) string {
+	if s.auth == nil {
+		return ""
+	}
+	user, err := s.auth.verifier.Verify(ctx, tokenFromMetadata(ctx))
+	if err != nil {
+		return ""
+	}
+	return user.Username
+}
+
+// priorState returns the phase, the step and the images of a rollout
+func priorState(ro *v1alpha1.Rollout) string {
+	roInfo := info.NewRolloutInfo(ro, nil, nil, nil, nil, nil)
+	state := []string{"phase=" + roInfo.Status}
+	if roInfo.Step != "" {
+		state = append(state, "step="+roInfo.Step)
+	}
+	var images []string
+	for _, c := range ro.Spec.Template.Spec.Containers {
+		images = append(images, c.Image)
+	}
+	if len(images) > 0 {
+		state = append(state, "images="+strings.Join(images, ","))
+	}
+	return strings.Join(state, " ")
+}
+
+// ListRolloutAuditEvents returns the actions taken on a rollout through the dashboard, the most recent first. The
+// actions are only kept as long as the events of the cluster.
+func (s *ArgoRolloutsServer) ListRolloutAuditEvents(ctx context.Context, q *rollout.RolloutInfoQuery) (*rollout.AuditEventList, error) {
+	eventList, err := s.Options.KubeClientset.CoreV1().Events(q.GetNamespace()).List(ctx, v1.ListOptions{
+		FieldSelector: fmt.Sprintf("involvedObject.kind=Rollout,involvedObject.name=%s,reason=%s", q.GetName(), AuditEventReason),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var events []*rollout.AuditEvent
+	for _, event := range eventList.Items {
+		if event.InvolvedObject.Kind != "Rollout" || event.InvolvedObject.Name != q.GetName() || event.Reason != AuditEventReason {
+			continue
+		}
+		timestamp := event.LastTimestamp
+		events = append(events, &rollout.AuditEvent{
+			Action:     event.Annotations[auditActionAnnotation],
+			User:       event.Annotations[auditUserAnnotation],
+			Timestamp:  &timestamp,
+			PriorState: event.Annotations[auditPriorStateAnnotation],
+			Message:    event.Message,
+		})
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[j].Timestamp.Before(events[i].Timestamp)
+	})
+	return &rollout.AuditEventList{Events: events}, nil
+}
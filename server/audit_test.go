@@ -0,0 +1,189 @@
+package server
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apiclient/rollout"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	fakeclientset "github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned/fake"
+	timeutil "github.com/argoproj/argo-rollouts/utils/time"
+)
+
+func newPausedRollout() *v1alpha1.Rollout {
+	ro := newListedRollout("default", "guestbook", v1alpha1.RolloutPhasePaused, false, nil)
+	ro.Spec.Strategy.Canary.Steps = []v1alpha1.CanaryStep{{SetWeight: ptr.To[int32](20)}, {Pause: &v1alpha1.RolloutPause{}}, {SetWeight: ptr.To[int32](100)}}
+	ro.Spec.Template.Spec.Containers = []corev1.Container{{Name: "guestbook", Image: "argoproj/rollouts-demo:blue"}}
+	ro.Status.CurrentStepIndex = ptr.To[int32](1)
+	ro.Status.PauseConditions = []v1alpha1.PauseCondition{{Reason: v1alpha1.PauseReasonCanaryPauseStep}}
+	return ro
+}
+
+func TestPriorState(t *testing.T) {
+	assert.Equal(t, "phase=Paused step=1/3 images=argoproj/rollouts-demo:blue", priorState(newPausedRollout()))
+	assert.Equal(t, "phase=Healthy", priorState(newListedRollout("default", "guestbook", v1alpha1.RolloutPhaseHealthy, true, nil)))
+}
+
+func TestAuditedActions(t *testing.T) {
+	now := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	timeutil.SetNowTimeFunc(func() time.Time { return now })
+	defer timeutil.SetNowTimeFunc(time.Now)
+
+	t.Run("without login", func(t *testing.T) {
+		s := NewServer(ServerOptions{
+			KubeClientset:     k8sfake.NewSimpleClientset(),
+			RolloutsClientset: fakeclientset.NewSimpleClientset(newPausedRollout()),
+		})
+		_, err := s.PromoteRollout(context.Background(), &rollout.PromoteRolloutRequest{Namespace: "default", Name: "guestbook"})
+		require.NoError(t, err)
+
+		list, err := s.ListRolloutAuditEvents(context.Background(), &rollout.RolloutInfoQuery{Namespace: "default", Name: "guestbook"})
+		require.NoError(t, err)
+		require.Len(t, list.Events, 1)
+		event := list.Events[0]
+		assert.Equal(t, "promote", event.Action)
+		assert.Empty(t, event.User)
+		assert.Equal(t, "phase=Paused step=1/3 images=argoproj/rollouts-demo:blue", event.PriorState)
+		assert.Equal(t, "a user of the dashboard promoted the rollout", event.Message)
+		assert.True(t, now.Equal(event.Timestamp.Time))
+	})
+
+	t.Run("logged in", func(t *testing.T) {
+		s, _ := newAuthServer()
+		s.Options.RolloutsClientset = fakeclientset.NewSimpleClientset(newPausedRollout())
+		ctx := contextWithMetadata("authorization", "Bearer valid-token")
+		_, err := s.AbortRollout(ctx, &rollout.AbortRolloutRequest{Namespace: "default", Name: "guestbook"})
+		require.NoError(t, err)
+
+		list, err := s.ListRolloutAuditEvents(ctx, &rollout.RolloutInfoQuery{Namespace: "default", Name: "guestbook"})
+		require.NoError(t, err)
+		require.Len(t, list.Events, 1)
+		assert.Equal(t, "abort", list.Events[0].Action)
+		assert.Equal(t, "alice@example.com", list.Events[0].User)
+		assert.Equal(t, "user 'alice@example.com' aborted the rollout", list.Events[0].Message)
+	})
+
+	t.Run("impersonated", func(t *testing.T) {
+		var users, groups []string
+		apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			users = append(users, r.Header.Get("Impersonate-User"))
+			groups = append(groups, r.Header.Values("Impersonate-Group")...)
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(newPausedRollout())
+		}))
+		defer apiServer.Close()
+		s, _ := newAuthServer()
+		s.Options.RolloutsClientset = fakeclientset.NewSimpleClientset(newPausedRollout())
+		s.Options.RESTConfig = &rest.Config{Host: apiServer.URL}
+		ctx := contextWithMetadata("authorization", "Bearer valid-token")
+		_, err := s.AbortRollout(ctx, &rollout.AbortRolloutRequest{Namespace: "default", Name: "guestbook"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"alice@example.com"}, users)
+		assert.Equal(t, []string{"developers"}, groups)
+	})
+
+	t.Run("failed action", func(t *testing.T) {
+		kubeClient := k8sfake.NewSimpleClientset()
+		s := NewServer(ServerOptions{KubeClientset: kubeClient, RolloutsClientset: fakeclientset.NewSimpleClientset()})
+		_, err := s.RetryRollout(context.Background(), &rollout.RetryRolloutRequest{Namespace: "default", Name: "guestbook"})
+		assert.Error(t, err)
+		events, err := kubeClient.CoreV1().Events("default").List(context.Background(), v1.ListOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, events.Items)
+	})
+}
+
+// signIDToken returns an RS256 ID token of the claims signed with the key
+func signIDToken(t *testing.T, key *rsa.PrivateKey, claims map[string]any) string {
+	encode := func(v any) string {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := encode(map[string]string{"alg": "RS256", "typ": "JWT"}) + "." + encode(claims)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestAuditedActionsThroughGRPCServer(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	issuer := "https://accounts.example.com"
+	s, _ := newAuthServer()
+	allowAllReviews(s)
+	s.auth.verifier = &oidcVerifier{
+		verifier:      oidc.NewVerifier(issuer, &oidc.StaticKeySet{PublicKeys: []crypto.PublicKey{&key.PublicKey}}, &oidc.Config{ClientID: "argo-rollouts"}),
+		usernameClaim: "email",
+		groupsClaim:   "groups",
+	}
+	s.Options.RolloutsClientset = fakeclientset.NewSimpleClientset(newPausedRollout())
+	client := newGRPCClient(t, s)
+
+	idToken := signIDToken(t, key, map[string]any{
+		"iss":    issuer,
+		"aud":    "argo-rollouts",
+		"sub":    "alice",
+		"email":  "alice@example.com",
+		"groups": []string{"developers"},
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"iat":    time.Now().Unix(),
+	})
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+idToken)
+	_, err = client.PromoteRollout(ctx, &rollout.PromoteRolloutRequest{Namespace: "default", Name: "guestbook"})
+	require.NoError(t, err)
+
+	list, err := client.ListRolloutAuditEvents(ctx, &rollout.RolloutInfoQuery{Namespace: "default", Name: "guestbook"})
+	require.NoError(t, err)
+	require.Len(t, list.Events, 1)
+	assert.Equal(t, "promote", list.Events[0].Action)
+	assert.Equal(t, "alice@example.com", list.Events[0].User)
+	assert.Equal(t, "user 'alice@example.com' promoted the rollout", list.Events[0].Message)
+}
+
+func TestListRolloutAuditEvents(t *testing.T) {
+	auditEvent := func(name, rolloutName, reason, action string, timestamp time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     v1.ObjectMeta{Namespace: "default", Name: name, Annotations: map[string]string{auditActionAnnotation: action}},
+			InvolvedObject: corev1.ObjectReference{Kind: "Rollout", Namespace: "default", Name: rolloutName},
+			Reason:         reason,
+			LastTimestamp:  v1.NewTime(timestamp),
+		}
+	}
+	start := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	kubeClient := k8sfake.NewSimpleClientset(
+		auditEvent("promote", "guestbook", AuditEventReason, "promote", start),
+		auditEvent("abort", "guestbook", AuditEventReason, "abort", start.Add(time.Minute)),
+		auditEvent("other-rollout", "api", AuditEventReason, "retry", start),
+		auditEvent("controller", "guestbook", "RolloutPaused", "", start),
+	)
+	s := NewServer(ServerOptions{KubeClientset: kubeClient})
+
+	list, err := s.ListRolloutAuditEvents(context.Background(), &rollout.RolloutInfoQuery{Namespace: "default", Name: "guestbook"})
+	require.NoError(t, err)
+	var actions []string
+	for _, event := range list.Events {
+		actions = append(actions, event.Action)
+	}
+	assert.Equal(t, []string{"abort", "promote"}, actions)
+}
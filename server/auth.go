@@ -0,0 +1,224 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// TokenCookieName is the cookie holding the ID token of the user logged in to the dashboard
+	TokenCookieName = "argo-rollouts.token"
+	// stateCookieName is the cookie holding the state of an authorization request to the OIDC provider
+	stateCookieName = "argo-rollouts.oauthstate"
+
+	defaultUsernameClaim = "email"
+	defaultGroupsClaim   = "groups"
+)
+
+// AuthOptions configures the authentication of the users of the dashboard with an OIDC provider. Authentication is
+// disabled when IssuerURL is empty.
+type AuthOptions struct {
+	// IssuerURL is the URL of the OIDC provider
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the external URL of the callback of the dashboard, e.g. https://rollouts.example.com/rollouts/auth/callback
+	RedirectURL string
+	// Scopes are requested in addition to the openid scope
+	Scopes []string
+	// UsernameClaim is the claim of the ID token which is the Kubernetes username of the user. Defaults to email.
+	UsernameClaim string
+	// GroupsClaim is the claim of the ID token which holds the Kubernetes groups of the user. Defaults to groups.
+	GroupsClaim string
+}
+
+// Enabled returns whether the users of the dashboard must log in
+func (o AuthOptions) Enabled() bool {
+	return o.IssuerURL != ""
+}
+
+// User is a user authenticated by the OIDC provider, whose access to rollouts is checked against the Kubernetes RBAC
+type User struct {
+	Username string
+	Groups   []string
+}
+
+// tokenVerifier verifies a raw ID token and returns the user it was issued to
+type tokenVerifier interface {
+	Verify(ctx context.Context, rawToken string) (*User, error)
+}
+
+type oidcVerifier struct {
+	verifier      *oidc.IDTokenVerifier
+	usernameClaim string
+	groupsClaim   string
+}
+
+// Verify verifies the signature and the expiry of an ID token and returns the user of its claims
+func (v *oidcVerifier) Verify(ctx context.Context, rawToken string) (*User, error) {
+	idToken, err := v.verifier.Verify(ctx, rawToken)
+	if err != nil {
+		return nil, err
+	}
+	var claims map[string]any
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, err
+	}
+	return userFromClaims(claims, v.usernameClaim, v.groupsClaim)
+}
+
+func userFromClaims(claims map[string]any, usernameClaim, groupsClaim string) (*User, error) {
+	username, _ := claims[usernameClaim].(string)
+	if username == "" {
+		return nil, fmt.Errorf("ID token has no %s claim", usernameClaim)
+	}
+	user := User{Username: username}
+	switch groups := claims[groupsClaim].(type) {
+	case string:
+		user.Groups = []string{groups}
+	case []any:
+		for _, group := range groups {
+			if g, ok := group.(string); ok {
+				user.Groups = append(user.Groups, g)
+			}
+		}
+	}
+	return &user, nil
+}
+
+// authenticator logs the users of the dashboard in with an OIDC provider
+type authenticator struct {
+	verifier     tokenVerifier
+	oauth2Config *oauth2.Config
+	// rootPath is the path the users are redirected to once logged in or out
+	rootPath string
+}
+
+// newAuthenticator discovers the OIDC provider of the options
+func newAuthenticator(ctx context.Context, o AuthOptions, rootPath string) (*authenticator, error) {
+	provider, err := oidc.NewProvider(ctx, o.IssuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover OIDC provider %s: %w", o.IssuerURL, err)
+	}
+	usernameClaim := o.UsernameClaim
+	if usernameClaim == "" {
+		usernameClaim = defaultUsernameClaim
+	}
+	groupsClaim := o.GroupsClaim
+	if groupsClaim == "" {
+		groupsClaim = defaultGroupsClaim
+	}
+	return &authenticator{
+		verifier: &oidcVerifier{
+			verifier:      provider.Verifier(&oidc.Config{ClientID: o.ClientID}),
+			usernameClaim: usernameClaim,
+			groupsClaim:   groupsClaim,
+		},
+		oauth2Config: &oauth2.Config{
+			ClientID:     o.ClientID,
+			ClientSecret: o.ClientSecret,
+			RedirectURL:  o.RedirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       append([]string{oidc.ScopeOpenID}, o.Scopes...),
+		},
+		rootPath: dashboardPath(rootPath),
+	}, nil
+}
+
+// dashboardPath returns the absolute path of the dashboard with a trailing slash
+func dashboardPath(rootPath string) string {
+	p := path.Join("/", rootPath)
+	if p != "/" {
+		p += "/"
+	}
+	return p
+}
+
+// registerHandlers registers the login, callback and logout handlers below the path of the dashboard
+func (a *authenticator) registerHandlers(mux *http.ServeMux, rootPath string) {
+	authPath := path.Join("/", rootPath, "auth")
+	mux.HandleFunc(authPath+"/login", a.handleLogin)
+	mux.HandleFunc(authPath+"/callback", a.handleCallback)
+	mux.HandleFunc(authPath+"/logout", a.handleLogout)
+}
+
+// handleLogin redirects to the OIDC provider
+func (a *authenticator) handleLogin(w http.ResponseWriter, r *http.Request) {
+	stateBytes := make([]byte, 16)
+	if _, err := rand.Read(stateBytes); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	state := base64.RawURLEncoding.EncodeToString(stateBytes)
+	http.SetCookie(w, &http.Cookie{
+		Name:     stateCookieName,
+		Value:    state,
+		Path:     a.rootPath,
+		MaxAge:   int((10 * time.Minute).Seconds()),
+		HttpOnly: true,
+		Secure:   a.secureCookies(),
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, a.oauth2Config.AuthCodeURL(state), http.StatusFound)
+}
+
+// handleCallback exchanges the authorization code of the OIDC provider for an ID token, which is stored in a cookie
+func (a *authenticator) handleCallback(w http.ResponseWriter, r *http.Request) {
+	stateCookie, err := r.Cookie(stateCookieName)
+	if err != nil || stateCookie.Value == "" || stateCookie.Value != r.URL.Query().Get("state") {
+		http.Error(w, "invalid state of the authorization request", http.StatusBadRequest)
+		return
+	}
+	if errMsg := r.URL.Query().Get("error"); errMsg != "" {
+		http.Error(w, fmt.Sprintf("login failed: %s: %s", errMsg, r.URL.Query().Get("error_description")), http.StatusUnauthorized)
+		return
+	}
+	token, err := a.oauth2Config.Exchange(r.Context(), r.URL.Query().Get("code"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to exchange the authorization code: %v", err), http.StatusUnauthorized)
+		return
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		http.Error(w, "no ID token in the token response", http.StatusUnauthorized)
+		return
+	}
+	user, err := a.verifier.Verify(r.Context(), rawIDToken)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid ID token: %v", err), http.StatusUnauthorized)
+		return
+	}
+	log.Infof("user '%s' logged in to the dashboard", user.Username)
+	http.SetCookie(w, &http.Cookie{Name: stateCookieName, Path: a.rootPath, MaxAge: -1})
+	http.SetCookie(w, &http.Cookie{
+		Name:     TokenCookieName,
+		Value:    rawIDToken,
+		Path:     a.rootPath,
+		Expires:  token.Expiry,
+		HttpOnly: true,
+		Secure:   a.secureCookies(),
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, a.rootPath, http.StatusFound)
+}
+
+// handleLogout clears the ID token of the user
+func (a *authenticator) handleLogout(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{Name: TokenCookieName, Path: a.rootPath, MaxAge: -1})
+	http.Redirect(w, r, a.rootPath, http.StatusFound)
+}
+
+// secureCookies returns whether the dashboard is served over HTTPS, so that the cookies must not be sent over HTTP
+func (a *authenticator) secureCookies() bool {
+	return strings.HasPrefix(a.oauth2Config.RedirectURL, "https://")
+}
@@ -0,0 +1,200 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	kubetesting "k8s.io/client-go/testing"
+
+	"github.com/argoproj/argo-rollouts/pkg/apiclient/rollout"
+)
+
+type fakeVerifier struct{}
+
+func (v *fakeVerifier) Verify(ctx context.Context, rawToken string) (*User, error) {
+	if rawToken != "valid-token" {
+		return nil, errors.New("token expired")
+	}
+	return &User{Username: "alice@example.com", Groups: []string{"developers"}}, nil
+}
+
+// newAuthServer returns a server with authentication enabled, whose SubjectAccessReviews only allow reading rollouts
+func newAuthServer() (*ArgoRolloutsServer, *[]authorizationv1.SubjectAccessReviewSpec) {
+	kubeClient := k8sfake.NewSimpleClientset()
+	var reviews []authorizationv1.SubjectAccessReviewSpec
+	kubeClient.PrependReactor("create", "subjectaccessreviews", func(action kubetesting.Action) (bool, runtime.Object, error) {
+		review := action.(kubetesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		reviews = append(reviews, review.Spec)
+		verb := review.Spec.ResourceAttributes.Verb
+		review.Status.Allowed = verb == "get" || verb == "list" || verb == "watch"
+		return true, review, nil
+	})
+	s := &ArgoRolloutsServer{
+		Options: ServerOptions{KubeClientset: kubeClient},
+		auth: &authenticator{
+			verifier: &fakeVerifier{},
+			oauth2Config: &oauth2.Config{
+				ClientID:    "argo-rollouts",
+				RedirectURL: "https://rollouts.example.com/rollouts/auth/callback",
+				Endpoint:    oauth2.Endpoint{AuthURL: "https://accounts.example.com/auth", TokenURL: "https://accounts.example.com/token"},
+				Scopes:      []string{"openid"},
+			},
+			rootPath: "/rollouts/",
+		},
+	}
+	return s, &reviews
+}
+
+func contextWithMetadata(pairs ...string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(pairs...))
+}
+
+func TestUserFromClaims(t *testing.T) {
+	user, err := userFromClaims(map[string]any{"email": "alice@example.com", "groups": []any{"developers", "admins"}}, "email", "groups")
+	assert.NoError(t, err)
+	assert.Equal(t, &User{Username: "alice@example.com", Groups: []string{"developers", "admins"}}, user)
+
+	user, err = userFromClaims(map[string]any{"sub": "alice", "roles": "admins"}, "sub", "roles")
+	assert.NoError(t, err)
+	assert.Equal(t, &User{Username: "alice", Groups: []string{"admins"}}, user)
+
+	_, err = userFromClaims(map[string]any{"sub": "alice"}, "email", "groups")
+	assert.EqualError(t, err, "ID token has no email claim")
+}
+
+func TestTokenFromMetadata(t *testing.T) {
+	assert.Equal(t, "", tokenFromMetadata(context.Background()))
+	assert.Equal(t, "abc", tokenFromMetadata(contextWithMetadata("authorization", "Bearer abc")))
+	assert.Equal(t, "abc", tokenFromMetadata(contextWithMetadata(gatewayCookieHeader, "theme=dark; argo-rollouts.token=abc")))
+	assert.Equal(t, "", tokenFromMetadata(contextWithMetadata("authorization", "Basic abc", gatewayCookieHeader, "theme=dark")))
+}
+
+func TestAuthorize(t *testing.T) {
+	promote := "/rollout.RolloutService/PromoteRollout"
+	get := "/rollout.RolloutService/GetRolloutInfo"
+
+	t.Run("authentication disabled", func(t *testing.T) {
+		s := &ArgoRolloutsServer{}
+		assert.NoError(t, s.authorize(context.Background(), promote, &rollout.PromoteRolloutRequest{}))
+	})
+
+	t.Run("not logged in", func(t *testing.T) {
+		s, _ := newAuthServer()
+		err := s.authorize(context.Background(), get, &rollout.RolloutInfoQuery{})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+		err = s.authorize(contextWithMetadata("authorization", "Bearer expired-token"), get, &rollout.RolloutInfoQuery{})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		assert.Contains(t, err.Error(), "token expired")
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		s, reviews := newAuthServer()
+		ctx := contextWithMetadata(gatewayCookieHeader, TokenCookieName+"=valid-token")
+		err := s.authorize(ctx, get, &rollout.RolloutInfoQuery{Namespace: "default", Name: "guestbook"})
+		assert.NoError(t, err)
+		assert.Len(t, *reviews, 1)
+		spec := (*reviews)[0]
+		assert.Equal(t, "alice@example.com", spec.User)
+		assert.Equal(t, []string{"developers"}, spec.Groups)
+		assert.Equal(t, &authorizationv1.ResourceAttributes{
+			Namespace: "default",
+			Verb:      "get",
+			Group:     "argoproj.io",
+			Resource:  "rollouts",
+			Name:      "guestbook",
+		}, spec.ResourceAttributes)
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		s, reviews := newAuthServer()
+		ctx := contextWithMetadata("authorization", "Bearer valid-token")
+		err := s.authorize(ctx, "/rollout.RolloutService/SetRolloutImage", &rollout.SetImageRequest{Namespace: "default", Rollout: "guestbook"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.Contains(t, err.Error(), "user 'alice@example.com' is not allowed to patch rollouts in namespace 'default'")
+		assert.Equal(t, "guestbook", (*reviews)[0].ResourceAttributes.Name)
+	})
+
+	t.Run("logged in only", func(t *testing.T) {
+		s, reviews := newAuthServer()
+		ctx := contextWithMetadata("authorization", "Bearer valid-token")
+		assert.NoError(t, s.authorize(ctx, "/rollout.RolloutService/Version", nil))
+		assert.Empty(t, *reviews)
+	})
+
+	t.Run("unknown method", func(t *testing.T) {
+		s, _ := newAuthServer()
+		ctx := contextWithMetadata("authorization", "Bearer valid-token")
+		err := s.authorize(ctx, "/rollout.RolloutService/DeleteRollout", nil)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+func TestHandleLogin(t *testing.T) {
+	s, _ := newAuthServer()
+	w := httptest.NewRecorder()
+	s.auth.handleLogin(w, httptest.NewRequest(http.MethodGet, "/rollouts/auth/login", nil))
+
+	assert.Equal(t, http.StatusFound, w.Code)
+	cookies := w.Result().Cookies()
+	assert.Len(t, cookies, 1)
+	assert.Equal(t, stateCookieName, cookies[0].Name)
+	assert.Equal(t, "/rollouts/", cookies[0].Path)
+	assert.True(t, cookies[0].Secure)
+	location, err := url.Parse(w.Header().Get("Location"))
+	assert.NoError(t, err)
+	assert.Equal(t, "accounts.example.com", location.Host)
+	assert.Equal(t, cookies[0].Value, location.Query().Get("state"))
+	assert.Equal(t, "argo-rollouts", location.Query().Get("client_id"))
+}
+
+func TestHandleCallbackInvalidState(t *testing.T) {
+	s, _ := newAuthServer()
+	req := httptest.NewRequest(http.MethodGet, "/rollouts/auth/callback?state=forged&code=abc", nil)
+	req.AddCookie(&http.Cookie{Name: stateCookieName, Value: "expected"})
+	w := httptest.NewRecorder()
+	s.auth.handleCallback(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, w.Result().Cookies())
+}
+
+func TestHandleLogout(t *testing.T) {
+	s, _ := newAuthServer()
+	w := httptest.NewRecorder()
+	s.auth.handleLogout(w, httptest.NewRequest(http.MethodGet, "/rollouts/auth/logout", nil))
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "/rollouts/", w.Header().Get("Location"))
+	cookies := w.Result().Cookies()
+	assert.Len(t, cookies, 1)
+	assert.Equal(t, TokenCookieName, cookies[0].Name)
+	assert.True(t, cookies[0].MaxAge < 0)
+}
+
+func TestDashboardPath(t *testing.T) {
+	assert.Equal(t, "/", dashboardPath(""))
+	assert.Equal(t, "/rollouts/", dashboardPath("rollouts"))
+	assert.Equal(t, "/custom/path/", dashboardPath("/custom/path/"))
+}
+
+func TestNewHTTPServerAuthHandlers(t *testing.T) {
+	s, _ := newAuthServer()
+	s.Options.RootPath = "rollouts"
+	httpServer := s.newHTTPServer(context.Background(), 8080)
+
+	w := httptest.NewRecorder()
+	httpServer.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/rollouts/auth/login", nil))
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Contains(t, w.Header().Get("Location"), "https://accounts.example.com/auth")
+}
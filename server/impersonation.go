@@ -0,0 +1,43 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+
+	rolloutclientset "github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned"
+)
+
+// actionClients returns the clients the actions of a call on rollouts are taken with. When the users log in, they
+// impersonate the user of the ID token of the call, so that the API server, its audit log and the admission webhooks
+// of the rollouts attribute the action to the user rather than to the dashboard. The clients of the server are
+// returned otherwise.
+func (s *ArgoRolloutsServer) actionClients(ctx context.Context) (rolloutclientset.Interface, dynamic.Interface, error) {
+	if s.auth == nil || s.Options.RESTConfig == nil {
+		return s.Options.RolloutsClientset, s.Options.DynamicClientset, nil
+	}
+	user, err := s.auth.verifier.Verify(ctx, tokenFromMetadata(ctx))
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+	}
+	config := impersonationConfig(s.Options.RESTConfig, user)
+	rolloutsClient, err := rolloutclientset.NewForConfig(config)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Internal, "failed to create the clients of user '%s': %v", user.Username, err)
+	}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Internal, "failed to create the clients of user '%s': %v", user.Username, err)
+	}
+	return rolloutsClient, dynamicClient, nil
+}
+
+// impersonationConfig returns a copy of the config which impersonates the user
+func impersonationConfig(config *rest.Config, user *User) *rest.Config {
+	config = rest.CopyConfig(config)
+	config.Impersonate = rest.ImpersonationConfig{UserName: user.Username, Groups: user.Groups}
+	return config
+}
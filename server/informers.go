@@ -0,0 +1,83 @@
+package server
+
+import (
+	"sync"
+
+	kubeinformers "k8s.io/client-go/informers"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	rolloutinformers "github.com/argoproj/argo-rollouts/pkg/client/informers/externalversions"
+	listers "github.com/argoproj/argo-rollouts/pkg/client/listers/rollouts/v1alpha1"
+)
+
+// watchInformers are the informers of the rollouts, replica sets and pods of a namespace, which are shared by the
+// streams of rollouts watching that namespace
+type watchInformers struct {
+	streams int
+	stopCh  chan struct{}
+
+	rolloutInformer cache.SharedIndexInformer
+	rolloutsLister  listers.RolloutLister
+	rsInformer      cache.SharedIndexInformer
+	rsLister        appslisters.ReplicaSetLister
+	podsInformer    cache.SharedIndexInformer
+	podsLister      corelisters.PodLister
+}
+
+// sharedWatchInformers are the informers of the streams of rollouts, by namespace
+type sharedWatchInformers struct {
+	lock        sync.Mutex
+	byNamespace map[string]*watchInformers
+}
+
+// acquireWatchInformers returns the informers of a namespace, or of all the namespaces when empty, and starts them if
+// no other stream watches the namespace. The informers must be released with releaseWatchInformers once the stream
+// ends.
+func (s *ArgoRolloutsServer) acquireWatchInformers(namespace string) *watchInformers {
+	s.watchInformers.lock.Lock()
+	defer s.watchInformers.lock.Unlock()
+	if w, ok := s.watchInformers.byNamespace[namespace]; ok {
+		w.streams++
+		return w
+	}
+
+	rolloutsInformerFactory := rolloutinformers.NewSharedInformerFactoryWithOptions(s.Options.RolloutsClientset, 0, rolloutinformers.WithNamespace(namespace))
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(s.Options.KubeClientset, 0, kubeinformers.WithNamespace(namespace))
+	w := &watchInformers{
+		streams:         1,
+		stopCh:          make(chan struct{}),
+		rolloutInformer: rolloutsInformerFactory.Argoproj().V1alpha1().Rollouts().Informer(),
+		rolloutsLister:  rolloutsInformerFactory.Argoproj().V1alpha1().Rollouts().Lister(),
+		rsInformer:      kubeInformerFactory.Apps().V1().ReplicaSets().Informer(),
+		rsLister:        kubeInformerFactory.Apps().V1().ReplicaSets().Lister(),
+		podsInformer:    kubeInformerFactory.Core().V1().Pods().Informer(),
+		podsLister:      kubeInformerFactory.Core().V1().Pods().Lister(),
+	}
+	kubeInformerFactory.Start(w.stopCh)
+	rolloutsInformerFactory.Start(w.stopCh)
+	s.watchInformers.byNamespace[namespace] = w
+	return w
+}
+
+// releaseWatchInformers releases the informers of a namespace acquired by a stream, and stops them once no stream
+// watches the namespace anymore
+func (s *ArgoRolloutsServer) releaseWatchInformers(namespace string) {
+	s.watchInformers.lock.Lock()
+	defer s.watchInformers.lock.Unlock()
+	w, ok := s.watchInformers.byNamespace[namespace]
+	if !ok {
+		return
+	}
+	w.streams--
+	if w.streams == 0 {
+		close(w.stopCh)
+		delete(s.watchInformers.byNamespace, namespace)
+	}
+}
+
+// hasSynced returns whether the informers have synced
+func (w *watchInformers) hasSynced() bool {
+	return w.rolloutInformer.HasSynced() && w.rsInformer.HasSynced() && w.podsInformer.HasSynced()
+}
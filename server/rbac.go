@@ -0,0 +1,166 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+const (
+	// gatewayCookieHeader is the metadata the gRPC gateway forwards the cookies of the HTTP requests as
+	gatewayCookieHeader = "grpcgateway-cookie"
+)
+
+// methodVerbs are the verbs on rollouts the users need to be allowed to by the Kubernetes RBAC to call the methods of
+// the API. The methods without a verb only require the users to be logged in.
+var methodVerbs = map[string]string{
+	"/rollout.RolloutService/GetRolloutInfo":    "get",
+	"/rollout.RolloutService/WatchRolloutInfo":  "watch",
+	"/rollout.RolloutService/ListRolloutInfos":  "list",
+	"/rollout.RolloutService/WatchRolloutInfos": "watch",
+	"/rollout.RolloutService/GetNamespace":      "",
+	"/rollout.RolloutService/Version":           "",
+	"/rollout.RolloutService/RestartRollout":    "patch",
+	"/rollout.RolloutService/PromoteRollout":    "patch",
+	"/rollout.RolloutService/AbortRollout":      "patch",
+	"/rollout.RolloutService/SetRolloutImage":   "patch",
+	"/rollout.RolloutService/UndoRollout":       "patch",
+	"/rollout.RolloutService/RetryRollout":      "patch",
+	// the actions taken on a rollout are readable by the users who can read the rollout
+	"/rollout.RolloutService/ListRolloutAuditEvents": "get",
+}
+
+// unaryAuthInterceptor rejects the calls the dashboard does not allow, and the calls of the users who are not logged in
+// or not allowed to call the method
+func (s *ArgoRolloutsServer) unaryAuthInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := s.checkScope(info.FullMethod, req); err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, info.FullMethod, req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// streamAuthInterceptor rejects the streams the dashboard does not allow, and the streams of the users who are not
+// logged in or not allowed to call the method. The access is checked once the request of the stream is received.
+func (s *ArgoRolloutsServer) streamAuthInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &authorizedStream{ServerStream: ss, server: s, method: info.FullMethod})
+}
+
+type authorizedStream struct {
+	grpc.ServerStream
+	server     *ArgoRolloutsServer
+	method     string
+	authorized bool
+}
+
+func (s *authorizedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if !s.authorized {
+		if err := s.server.checkScope(s.method, m); err != nil {
+			return err
+		}
+		if err := s.server.authorize(s.Context(), s.method, m); err != nil {
+			return err
+		}
+		s.authorized = true
+	}
+	return nil
+}
+
+// authorize checks that the user of the ID token of a call is allowed to call the method by a SubjectAccessReview.
+// Every call is allowed when authentication is disabled.
+func (s *ArgoRolloutsServer) authorize(ctx context.Context, method string, req any) error {
+	if s.auth == nil {
+		return nil
+	}
+	rawToken := tokenFromMetadata(ctx)
+	if rawToken == "" {
+		return status.Error(codes.Unauthenticated, "not logged in")
+	}
+	user, err := s.auth.verifier.Verify(ctx, rawToken)
+	if err != nil {
+		return status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+	}
+	verb, ok := methodVerbs[method]
+	if !ok {
+		return status.Errorf(codes.PermissionDenied, "unknown method %s", method)
+	}
+	if verb == "" {
+		return nil
+	}
+
+	namespace, name := requestRollout(req)
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user.Username,
+			Groups: user.Groups,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      verb,
+				Group:     v1alpha1.SchemeGroupVersion.Group,
+				Resource:  v1alpha1.RolloutGVR.Resource,
+				Name:      name,
+			},
+		},
+	}
+	review, err = s.Options.KubeClientset.AuthorizationV1().SubjectAccessReviews().Create(ctx, review, v1.CreateOptions{})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to review the access of user '%s': %v", user.Username, err)
+	}
+	if !review.Status.Allowed {
+		log.Infof("user '%s' is not allowed to %s rollout '%s' in namespace '%s'", user.Username, verb, name, namespace)
+		return status.Errorf(codes.PermissionDenied, "user '%s' is not allowed to %s rollouts in namespace '%s'", user.Username, verb, namespace)
+	}
+	return nil
+}
+
+// tokenFromMetadata returns the ID token of a call, from either a bearer authorization header or the cookie of the
+// dashboard
+func tokenFromMetadata(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	for _, authorization := range md.Get("authorization") {
+		if token, ok := strings.CutPrefix(authorization, "Bearer "); ok {
+			return token
+		}
+	}
+	for _, cookies := range md.Get(gatewayCookieHeader) {
+		req := http.Request{Header: http.Header{"Cookie": []string{cookies}}}
+		if cookie, err := req.Cookie(TokenCookieName); err == nil {
+			return cookie.Value
+		}
+	}
+	return ""
+}
+
+// requestRollout returns the namespace and the name of the rollout of a request. The name is empty for the requests
+// on all the rollouts of a namespace.
+func requestRollout(req any) (string, string) {
+	var namespace, name string
+	if r, ok := req.(interface{ GetNamespace() string }); ok {
+		namespace = r.GetNamespace()
+	}
+	switch r := req.(type) {
+	case interface{ GetName() string }:
+		name = r.GetName()
+	case interface{ GetRollout() string }:
+		name = r.GetRollout()
+	}
+	return namespace, name
+}
@@ -0,0 +1,28 @@
+package server
+
+import (
+	"slices"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// checkScope rejects the calls which the dashboard does not allow, whoever the user: the actions on the rollouts when
+// the dashboard is read-only, and the calls on the rollouts of the namespaces which are not allowed. The calls on all
+// namespaces are allowed, and only return the rollouts of the allowed namespaces.
+func (s *ArgoRolloutsServer) checkScope(method string, req any) error {
+	if s.Options.ReadOnly && methodVerbs[method] == "patch" {
+		return status.Error(codes.PermissionDenied, "the dashboard is read-only")
+	}
+	namespace, _ := requestRollout(req)
+	if namespace != "" && !s.namespaceAllowed(namespace) {
+		return status.Errorf(codes.PermissionDenied, "namespace '%s' is not allowed by the dashboard", namespace)
+	}
+	return nil
+}
+
+// namespaceAllowed returns whether the dashboard allows the rollouts of a namespace. Every namespace is allowed when
+// the dashboard is not restricted to a list of namespaces.
+func (s *ArgoRolloutsServer) namespaceAllowed(namespace string) bool {
+	return len(s.Options.AllowedNamespaces) == 0 || slices.Contains(s.Options.AllowedNamespaces, namespace)
+}
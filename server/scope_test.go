@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/argoproj/argo-rollouts/pkg/apiclient/rollout"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	fakeclientset "github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned/fake"
+)
+
+func TestCheckScope(t *testing.T) {
+	promote := "/rollout.RolloutService/PromoteRollout"
+	get := "/rollout.RolloutService/GetRolloutInfo"
+	list := "/rollout.RolloutService/ListRolloutInfos"
+
+	t.Run("unrestricted", func(t *testing.T) {
+		s := &ArgoRolloutsServer{}
+		assert.NoError(t, s.checkScope(promote, &rollout.PromoteRolloutRequest{Namespace: "default", Name: "guestbook"}))
+	})
+
+	t.Run("read-only", func(t *testing.T) {
+		s := &ArgoRolloutsServer{Options: ServerOptions{ReadOnly: true}}
+		err := s.checkScope(promote, &rollout.PromoteRolloutRequest{Namespace: "default", Name: "guestbook"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.Contains(t, err.Error(), "the dashboard is read-only")
+
+		err = s.checkScope("/rollout.RolloutService/SetRolloutImage", &rollout.SetImageRequest{Namespace: "default", Rollout: "guestbook"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		assert.NoError(t, s.checkScope(get, &rollout.RolloutInfoQuery{Namespace: "default", Name: "guestbook"}))
+		assert.NoError(t, s.checkScope("/rollout.RolloutService/Version", nil))
+	})
+
+	t.Run("allowed namespaces", func(t *testing.T) {
+		s := &ArgoRolloutsServer{Options: ServerOptions{AllowedNamespaces: []string{"team-a", "team-b"}}}
+		assert.NoError(t, s.checkScope(get, &rollout.RolloutInfoQuery{Namespace: "team-b", Name: "guestbook"}))
+		assert.NoError(t, s.checkScope(list, &rollout.RolloutInfoListQuery{}))
+
+		err := s.checkScope(get, &rollout.RolloutInfoQuery{Namespace: "kube-system", Name: "guestbook"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.Contains(t, err.Error(), "namespace 'kube-system' is not allowed by the dashboard")
+
+		err = s.checkScope(list, &rollout.RolloutInfoListQuery{Namespace: "kube-system"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("interceptor without authentication", func(t *testing.T) {
+		s := &ArgoRolloutsServer{Options: ServerOptions{ReadOnly: true}}
+		called := false
+		_, err := s.unaryAuthInterceptor(context.Background(), &rollout.AbortRolloutRequest{Namespace: "default", Name: "guestbook"},
+			&grpc.UnaryServerInfo{FullMethod: "/rollout.RolloutService/AbortRollout"},
+			func(ctx context.Context, req any) (any, error) {
+				called = true
+				return nil, nil
+			})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.False(t, called)
+	})
+}
+
+func TestAllowedNamespaces(t *testing.T) {
+	rolloutsClient := fakeclientset.NewSimpleClientset(
+		newListedRollout("team-a", "guestbook", v1alpha1.RolloutPhaseHealthy, false, nil),
+		newListedRollout("team-b", "api", v1alpha1.RolloutPhaseHealthy, false, nil),
+		newListedRollout("kube-system", "dns", v1alpha1.RolloutPhaseHealthy, false, nil),
+	)
+	s := NewServer(ServerOptions{
+		Namespace:         "default",
+		KubeClientset:     k8sfake.NewSimpleClientset(),
+		RolloutsClientset: rolloutsClient,
+		ReadOnly:          true,
+		AllowedNamespaces: []string{"team-a", "team-b", "team-c"},
+	})
+
+	t.Run("list all namespaces", func(t *testing.T) {
+		list, err := s.ListRolloutInfos(context.Background(), &rollout.RolloutInfoListQuery{})
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"team-a/guestbook", "team-b/api"}, listedNames(list))
+	})
+
+	t.Run("namespace info", func(t *testing.T) {
+		info, err := s.GetNamespace(context.Background(), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "team-a", info.Namespace)
+		assert.Equal(t, []string{"team-a", "team-b", "team-c"}, info.AvailableNamespaces)
+		assert.True(t, info.ReadOnly)
+		assert.True(t, info.NamespaceRestricted)
+		assert.Empty(t, info.PodLogURLTemplate)
+	})
+}
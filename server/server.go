@@ -2,10 +2,14 @@ package server
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"net"
 	"net/http"
 	"path"
+	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,15 +18,18 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/soheilhy/cmux"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/dynamic"
-	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	appslisters "k8s.io/client-go/listers/apps/v1"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/argoproj/argo-rollouts/pkg/apiclient/rollout"
@@ -39,6 +46,7 @@ import (
 	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd/undo"
 	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/info"
 	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/viewcontroller"
+	"github.com/argoproj/argo-rollouts/utils/certs"
 	"github.com/argoproj/argo-rollouts/utils/errors"
 	"github.com/argoproj/argo-rollouts/utils/json"
 	versionutils "github.com/argoproj/argo-rollouts/utils/version"
@@ -57,6 +65,24 @@ type ServerOptions struct {
 	DynamicClientset  dynamic.Interface
 	Namespace         string
 	RootPath          string
+	// Auth configures the login of the users with an OIDC provider. The access of the logged in users to rollouts is
+	// checked against the Kubernetes RBAC.
+	Auth AuthOptions
+	// ReadOnly rejects every action on the rollouts, so that the dashboard can only be used to view them
+	ReadOnly bool
+	// AllowedNamespaces restricts the dashboard to the rollouts of these namespaces. Every namespace is allowed when
+	// empty.
+	AllowedNamespaces []string
+	// PodLogURLTemplate is the URL of the logs of a pod in the log system of the cluster, with the ${metadata.namespace}
+	// and ${metadata.name} placeholders of the pod. The pods have no log link when empty.
+	PodLogURLTemplate string
+	// TLS serves the dashboard and the API over HTTPS with the certificates of the rotator. They are served over HTTP
+	// when nil.
+	TLS *certs.Rotator
+	// RESTConfig is the config of the clients of the server. When the users log in, the actions on rollouts are taken
+	// by clients created from it which impersonate the logged in user. The actions are taken by the clients of the
+	// server when nil.
+	RESTConfig *rest.Config
 }
 
 const (
@@ -68,11 +94,16 @@ const (
 type ArgoRolloutsServer struct {
 	Options ServerOptions
 	stopCh  chan struct{}
+	// auth is nil when authentication is disabled
+	auth *authenticator
+
+	// watchInformers are the informers shared by the streams of rollouts
+	watchInformers *sharedWatchInformers
 }
 
 // NewServer creates an ArgoRolloutsServer
 func NewServer(o ServerOptions) *ArgoRolloutsServer {
-	return &ArgoRolloutsServer{Options: o}
+	return &ArgoRolloutsServer{Options: o, watchInformers: &sharedWatchInformers{byNamespace: map[string]*watchInformers{}}}
 }
 
 const (
@@ -101,7 +132,11 @@ func (s *ArgoRolloutsServer) newHTTPServer(ctx context.Context, port int) *http.
 	opts := []grpc.DialOption{
 		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(MaxGRPCMessageSize)),
 	}
-	opts = append(opts, grpc.WithInsecure())
+	if s.Options.TLS != nil {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(s.Options.TLS.ClientTLSConfig())))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
 
 	endpoint := net.JoinHostPort(connectAddr, fmt.Sprintf("%d", port))
 	err := rollout.RegisterRolloutServiceHandlerFromEndpoint(ctx, gwmux, endpoint, opts)
@@ -119,14 +154,21 @@ func (s *ArgoRolloutsServer) newHTTPServer(ctx context.Context, port int) *http.
 		apiHandler = http.StripPrefix(stripPrefix, gwmux)
 	}
 	mux.Handle(apiPath, apiHandler)
+	if s.auth != nil {
+		s.auth.registerHandlers(mux, s.Options.RootPath)
+	}
 	mux.HandleFunc("/", s.staticFileHttpHandler)
 
 	return &httpS
 }
 
 func (s *ArgoRolloutsServer) newGRPCServer() *grpc.Server {
-	grpcS := grpc.NewServer()
-	var rolloutsServer rollout.RolloutServiceServer = NewServer(s.Options)
+	grpcS := grpc.NewServer(
+		grpc.UnaryInterceptor(s.unaryAuthInterceptor),
+		grpc.StreamInterceptor(s.streamAuthInterceptor),
+	)
+	// the handlers are served by the server itself, so that they impersonate and audit the users it authenticates
+	var rolloutsServer rollout.RolloutServiceServer = s
 	rollout.RegisterRolloutServiceServer(grpcS, rolloutsServer)
 	return grpcS
 }
@@ -145,6 +187,11 @@ func (s *ArgoRolloutsServer) checkServeErr(name string, err error) {
 
 // Run starts the server
 func (s *ArgoRolloutsServer) Run(ctx context.Context, port int, dashboard bool) {
+	if s.Options.Auth.Enabled() {
+		auth, err := newAuthenticator(ctx, s.Options.Auth, s.Options.RootPath)
+		errors.CheckError(err)
+		s.auth = auth
+	}
 	httpServer := s.newHTTPServer(ctx, port)
 	grpcServer := s.newGRPCServer()
 
@@ -161,13 +208,32 @@ func (s *ArgoRolloutsServer) Run(ctx context.Context, port int, dashboard bool)
 	})
 	errors.CheckError(realErr)
 
+	scheme := "http"
+	if s.Options.TLS != nil {
+		scheme = "https"
+	}
 	startupMessage := fmt.Sprintf("Argo Rollouts api-server serving on port %d (namespace: %s)", port, s.Options.Namespace)
 	if dashboard {
-		startupMessage = fmt.Sprintf("Argo Rollouts Dashboard is now available at http://localhost:%d/%s", port, s.Options.RootPath)
+		startupMessage = fmt.Sprintf("Argo Rollouts Dashboard is now available at %s://localhost:%d/%s", scheme, port, s.Options.RootPath)
 	}
 
 	log.Info(startupMessage)
 
+	s.stopCh = make(chan struct{})
+	if s.Options.TLS != nil {
+		// over TLS, HTTP/2 is negotiated with ALPN, so that the gRPC requests are told apart by their content type
+		// instead of by cmux
+		httpServer.TLSConfig = s.Options.TLS.TLSConfig()
+		httpServer.TLSConfig.NextProtos = []string{"h2", "http/1.1"}
+		httpServer.Handler = grpcHandlerFunc(grpcServer, httpServer.Handler)
+		go func() {
+			s.checkServeErr("httpServer", httpServer.ServeTLS(conn, "", ""))
+		}()
+		<-s.stopCh
+		errors.CheckError(conn.Close())
+		return
+	}
+
 	tcpm := cmux.New(conn)
 
 	httpL := tcpm.Match(cmux.HTTP1Fast())
@@ -181,11 +247,21 @@ func (s *ArgoRolloutsServer) Run(ctx context.Context, port int, dashboard bool)
 	}()
 	go func() { s.checkServeErr("tcpm", tcpm.Serve()) }()
 
-	s.stopCh = make(chan struct{})
 	<-s.stopCh
 	errors.CheckError(conn.Close())
 }
 
+// grpcHandlerFunc serves the gRPC requests with the gRPC server, and the other requests with the HTTP handler
+func grpcHandlerFunc(grpcServer *grpc.Server, httpHandler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			grpcServer.ServeHTTP(w, r)
+			return
+		}
+		httpHandler.ServeHTTP(w, r)
+	})
+}
+
 func (s *ArgoRolloutsServer) initRolloutViewController(namespace string, name string, ctx context.Context) *viewcontroller.RolloutViewController {
 	controller := viewcontroller.NewRolloutViewController(namespace, name, s.Options.KubeClientset, s.Options.RolloutsClientset)
 	controller.Start(ctx)
@@ -209,7 +285,8 @@ func (s *ArgoRolloutsServer) GetRolloutInfo(c context.Context, q *rollout.Rollou
 	return s.getRolloutInfo(q.GetNamespace(), q.GetName())
 }
 
-// WatchRolloutInfo returns a rollout stream
+// WatchRolloutInfo returns a stream of a rollout, which pushes the changes of the rollout and of its replica sets, pods,
+// experiments and analysis runs
 func (s *ArgoRolloutsServer) WatchRolloutInfo(q *rollout.RolloutInfoQuery, ws rollout.RolloutService_WatchRolloutInfoServer) error {
 	ctx := ws.Context()
 	controller := s.initRolloutViewController(q.GetNamespace(), q.GetName(), ctx)
@@ -219,7 +296,13 @@ func (s *ArgoRolloutsServer) WatchRolloutInfo(q *rollout.RolloutInfoQuery, ws ro
 		rolloutUpdates <- roInfo
 	})
 
+	// the watch calls back with the latest rollout at least every second, which is only sent when it changed
+	var lastSent *rollout.RolloutInfo
 	go get.Watch(ctx.Done(), rolloutUpdates, func(i *rollout.RolloutInfo) {
+		if i == lastSent {
+			return
+		}
+		lastSent = i
 		ws.Send(i)
 	})
 	controller.Run(ctx)
@@ -250,102 +333,299 @@ func (s *ArgoRolloutsServer) ListReplicaSetsAndPods(ctx context.Context, namespa
 	return allReplicaSetsP, allPodsP, nil
 }
 
-// ListRolloutInfos returns a list of all rollouts
-func (s *ArgoRolloutsServer) ListRolloutInfos(ctx context.Context, q *rollout.RolloutInfoListQuery) (*rollout.RolloutInfoList, error) {
-	rolloutIf := s.Options.RolloutsClientset.ArgoprojV1alpha1().Rollouts(q.GetNamespace())
-	rolloutList, err := rolloutIf.List(ctx, v1.ListOptions{})
+// rolloutInfoFilter selects the rollouts of the phase and the strategy of a list query
+type rolloutInfoFilter struct {
+	phase    string
+	strategy string
+}
+
+// newRolloutInfoFilter returns the filter of a list query, or an InvalidArgument error when the phase or the strategy
+// of the query is unknown
+func newRolloutInfoFilter(q *rollout.RolloutInfoListQuery) (*rolloutInfoFilter, error) {
+	f := rolloutInfoFilter{}
+	if phase := q.GetPhase(); phase != "" {
+		for _, p := range []v1alpha1.RolloutPhase{v1alpha1.RolloutPhaseHealthy, v1alpha1.RolloutPhaseProgressing, v1alpha1.RolloutPhasePaused, v1alpha1.RolloutPhaseDegraded} {
+			if strings.EqualFold(phase, string(p)) {
+				f.phase = string(p)
+			}
+		}
+		if f.phase == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown phase '%s', must be one of: Healthy, Progressing, Paused, Degraded", phase)
+		}
+	}
+	if strategy := q.GetStrategy(); strategy != "" {
+		for _, st := range []string{"Canary", "BlueGreen"} {
+			if strings.EqualFold(strategy, st) {
+				f.strategy = st
+			}
+		}
+		if f.strategy == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown strategy '%s', must be one of: Canary, BlueGreen", strategy)
+		}
+	}
+	return &f, nil
+}
+
+func (f *rolloutInfoFilter) matches(ri *rollout.RolloutInfo) bool {
+	return (f.phase == "" || ri.Status == f.phase) && (f.strategy == "" || ri.Strategy == f.strategy)
+}
 
+// ListRolloutInfos returns a list of the rollouts matching the query. The rollouts of all the namespaces are listed
+// when the namespace of the query is empty. When the query has a limit, the list has at most limit rollouts and a
+// token to list the next rollouts with.
+func (s *ArgoRolloutsServer) ListRolloutInfos(ctx context.Context, q *rollout.RolloutInfoListQuery) (*rollout.RolloutInfoList, error) {
+	filter, err := newRolloutInfoFilter(q)
 	if err != nil {
 		return nil, err
 	}
+	if q.GetLimit() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must not be negative")
+	}
 
-	allReplicaSets, allPods, err := s.ListReplicaSetsAndPods(ctx, q.GetNamespace())
+	offset, listContinue, err := decodeRolloutInfoListContinue(q.GetContinue())
 	if err != nil {
 		return nil, err
 	}
 
+	rolloutIf := s.Options.RolloutsClientset.ArgoprojV1alpha1().Rollouts(q.GetNamespace())
+	opts := v1.ListOptions{
+		LabelSelector: q.GetLabelSelector(),
+		Continue:      listContinue,
+	}
+	if q.GetLimit() > 0 {
+		opts.Limit = max(q.GetLimit(), rolloutListPageSize)
+	}
+	var rollouts []*v1alpha1.Rollout
 	var riList []*rollout.RolloutInfo
-	for i := range rolloutList.Items {
-		cur := rolloutList.Items[i]
-		ri := info.NewRolloutInfo(&cur, nil, nil, nil, nil, nil)
-		ri.ReplicaSets = info.GetReplicaSetInfo(cur.UID, &cur, allReplicaSets, allPods)
-		riList = append(riList, ri)
+	next := ""
+	for {
+		rolloutList, err := rolloutIf.List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for i := min(offset, len(rolloutList.Items)); i < len(rolloutList.Items); i++ {
+			if q.GetLimit() > 0 && int64(len(riList)) >= q.GetLimit() {
+				// the list is full in the middle of the page, so the next list lists the same page again and skips
+				// the rollouts before this one
+				next = encodeRolloutInfoListContinue(i, opts.Continue)
+				break
+			}
+			cur := &rolloutList.Items[i]
+			if !s.namespaceAllowed(cur.Namespace) {
+				continue
+			}
+			ri := info.NewRolloutInfo(cur, nil, nil, nil, nil, nil)
+			if filter.matches(ri) {
+				rollouts = append(rollouts, cur)
+				riList = append(riList, ri)
+			}
+		}
+		if next != "" || rolloutList.Continue == "" {
+			break
+		}
+		if q.GetLimit() > 0 && int64(len(riList)) >= q.GetLimit() {
+			next = encodeRolloutInfoListContinue(0, rolloutList.Continue)
+			break
+		}
+		opts.Continue = rolloutList.Continue
+		offset = 0
+	}
+
+	// the replica sets and pods are only listed in the namespaces of the listed rollouts, rather than in the whole
+	// cluster
+	namespaces := []string{q.GetNamespace()}
+	if q.GetNamespace() == "" {
+		namespaces = nil
+		for _, ro := range rollouts {
+			if !slices.Contains(namespaces, ro.Namespace) {
+				namespaces = append(namespaces, ro.Namespace)
+			}
+		}
+	}
+	var allReplicaSets []*appsv1.ReplicaSet
+	var allPods []*corev1.Pod
+	for _, namespace := range namespaces {
+		replicaSets, pods, err := s.ListReplicaSetsAndPods(ctx, namespace)
+		if err != nil {
+			return nil, err
+		}
+		allReplicaSets = append(allReplicaSets, replicaSets...)
+		allPods = append(allPods, pods...)
+	}
+	for i, ro := range rollouts {
+		riList[i].ReplicaSets = info.GetReplicaSetInfo(ro.UID, ro, allReplicaSets, allPods)
 	}
 
-	return &rollout.RolloutInfoList{Rollouts: riList}, nil
+	return &rollout.RolloutInfoList{Rollouts: riList, Continue: next}, nil
+}
+
+// rolloutListPageSize is the minimum number of rollouts of the pages listed from the API server when the query has a
+// limit. The rollouts which do not match the query are filtered out once listed, so the pages are larger than the
+// limit to fill the list with a few calls.
+var rolloutListPageSize int64 = 250
+
+// encodeRolloutInfoListContinue returns the continue token of a list of rollout infos, which is made of the continue
+// token of the page of rollouts to list next and of the number of rollouts of that page which were already listed
+func encodeRolloutInfoListContinue(offset int, listContinue string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset) + "/" + listContinue))
+}
+
+// decodeRolloutInfoListContinue returns the offset and the continue token of the page of rollouts of the continue
+// token of a list of rollout infos
+func decodeRolloutInfoListContinue(token string) (int, string, error) {
+	if token == "" {
+		return 0, "", nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, "", status.Errorf(codes.InvalidArgument, "invalid continue token")
+	}
+	offsetStr, listContinue, found := strings.Cut(string(decoded), "/")
+	offset, err := strconv.Atoi(offsetStr)
+	if !found || err != nil || offset < 0 {
+		return 0, "", status.Errorf(codes.InvalidArgument, "invalid continue token")
+	}
+	return offset, listContinue, nil
 }
 
 func (s *ArgoRolloutsServer) RestartRollout(ctx context.Context, q *rollout.RestartRolloutRequest) (*v1alpha1.Rollout, error) {
-	rolloutIf := s.Options.RolloutsClientset.ArgoprojV1alpha1().Rollouts(q.GetNamespace())
+	rolloutsClient, _, err := s.actionClients(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rolloutIf := rolloutsClient.ArgoprojV1alpha1().Rollouts(q.GetNamespace())
 	restartAt := time.Now().UTC()
-	return restart.RestartRollout(rolloutIf, q.GetName(), &restartAt)
+	return s.audited(ctx, q.GetNamespace(), q.GetName(), "restart", "restarted the pods of the rollout", func() (*v1alpha1.Rollout, error) {
+		return restart.RestartRollout(rolloutIf, q.GetName(), &restartAt)
+	})
 }
 
-// WatchRolloutInfos returns a stream of all rollouts
+// rolloutEvent is a change of a rollout, or of one of its replica sets or pods, to send to a stream of rollouts
+type rolloutEvent struct {
+	rollout *v1alpha1.Rollout
+	deleted bool
+}
+
+// WatchRolloutInfos returns a stream of the rollouts matching the query, which pushes the changes of the rollouts and
+// of their replica sets and pods. A rollout is only sent when it changed. The rollouts which are deleted, or stop
+// matching the phase or the strategy of the query, are sent as deleted.
 func (s *ArgoRolloutsServer) WatchRolloutInfos(q *rollout.RolloutInfoListQuery, ws rollout.RolloutService_WatchRolloutInfosServer) error {
-	send := func(r *rollout.RolloutInfo) {
-		err := ws.Send(&rollout.RolloutWatchEvent{
-			Type:        "Updated",
-			RolloutInfo: r,
-		})
-		if err != nil {
-			return
-		}
+	filter, err := newRolloutInfoFilter(q)
+	if err != nil {
+		return err
+	}
+	selector, err := labels.Parse(q.GetLabelSelector())
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid label selector: %v", err)
 	}
 	ctx := ws.Context()
 
-	rolloutsInformerFactory := rolloutinformers.NewSharedInformerFactoryWithOptions(s.Options.RolloutsClientset, 0, rolloutinformers.WithNamespace(q.Namespace))
-	rolloutsLister := rolloutsInformerFactory.Argoproj().V1alpha1().Rollouts().Lister().Rollouts(q.Namespace)
-	rolloutInformer := rolloutsInformerFactory.Argoproj().V1alpha1().Rollouts().Informer()
-
-	kubeInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(s.Options.KubeClientset, 0, kubeinformers.WithNamespace(q.Namespace))
-	podsLister := kubeInformerFactory.Core().V1().Pods().Lister().Pods(q.GetNamespace())
-	rsLister := kubeInformerFactory.Apps().V1().ReplicaSets().Lister().ReplicaSets(q.GetNamespace())
-	kubeInformerFactory.Start(ws.Context().Done())
-	podsInformer := kubeInformerFactory.Core().V1().Pods().Informer()
+	// the informers are shared with the other streams watching the namespace, so the label selector of the query is
+	// applied to the events rather than to the informers
+	w := s.acquireWatchInformers(q.GetNamespace())
+	defer s.releaseWatchInformers(q.GetNamespace())
 
-	rolloutUpdateChan := make(chan *v1alpha1.Rollout)
+	rolloutUpdateChan := make(chan rolloutEvent)
+	enqueue := func(ro *v1alpha1.Rollout, deleted bool) {
+		if ro == nil || !selector.Matches(labels.Set(ro.Labels)) {
+			return
+		}
+		select {
+		case rolloutUpdateChan <- rolloutEvent{rollout: ro, deleted: deleted}:
+		case <-ctx.Done():
+		}
+	}
 
-	rolloutInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+	rolloutRegistration, err := w.rolloutInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj any) {
-			rolloutUpdateChan <- obj.(*v1alpha1.Rollout)
+			enqueue(obj.(*v1alpha1.Rollout), false)
 		},
 		UpdateFunc: func(oldObj, newObj any) {
-			rolloutUpdateChan <- newObj.(*v1alpha1.Rollout)
+			enqueue(newObj.(*v1alpha1.Rollout), false)
 		},
-	})
-	podsInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		DeleteFunc: func(obj any) {
-			podUpdated(obj.(*corev1.Pod), rsLister, rolloutsLister, rolloutUpdateChan)
+			if ro, ok := deletedObject(obj).(*v1alpha1.Rollout); ok {
+				enqueue(ro, true)
+			}
 		},
 	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = w.rolloutInformer.RemoveEventHandler(rolloutRegistration) }()
+	rsUpdated := func(obj any) {
+		if rs, ok := deletedObject(obj).(*appsv1.ReplicaSet); ok {
+			enqueue(ownerRollout(rs, w.rolloutsLister), false)
+		}
+	}
+	rsRegistration, err := w.rsInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    rsUpdated,
+		UpdateFunc: func(oldObj, newObj any) { rsUpdated(newObj) },
+		DeleteFunc: rsUpdated,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = w.rsInformer.RemoveEventHandler(rsRegistration) }()
+	podUpdated := func(obj any) {
+		if pod, ok := deletedObject(obj).(*corev1.Pod); ok {
+			enqueue(podOwnerRollout(pod, w.rsLister, w.rolloutsLister), false)
+		}
+	}
+	podsRegistration, err := w.podsInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    podUpdated,
+		UpdateFunc: func(oldObj, newObj any) { podUpdated(newObj) },
+		DeleteFunc: podUpdated,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = w.podsInformer.RemoveEventHandler(podsRegistration) }()
 
-	go rolloutInformer.Run(ctx.Done())
-
-	cache.WaitForCacheSync(
-		ws.Context().Done(),
-		podsInformer.HasSynced,
-		kubeInformerFactory.Apps().V1().ReplicaSets().Informer().HasSynced,
-		rolloutInformer.HasSynced,
-	)
+	cache.WaitForCacheSync(ctx.Done(), w.hasSynced)
 
+	// sent holds the last rollout info sent of every rollout, so that the rollouts are only sent when they changed
+	sent := map[string]*rollout.RolloutInfo{}
 	for {
 		select {
 		case <-ctx.Done():
 			return nil
-		case ro := <-rolloutUpdateChan:
-			allPods, err := podsLister.List(labels.Everything())
+		case ev := <-rolloutUpdateChan:
+			ro := ev.rollout
+			if !s.namespaceAllowed(ro.Namespace) {
+				continue
+			}
+			key := ro.Namespace + "/" + ro.Name
+			if ev.deleted {
+				delete(sent, key)
+				if err := ws.Send(&rollout.RolloutWatchEvent{Type: "Deleted", RolloutInfo: info.NewRolloutInfo(ro, nil, nil, nil, nil, nil)}); err != nil {
+					return err
+				}
+				continue
+			}
+
+			allPods, err := w.podsLister.Pods(ro.Namespace).List(labels.Everything())
 			if err != nil {
 				return err
 			}
-			allReplicaSets, err := rsLister.List(labels.Everything())
+			allReplicaSets, err := w.rsLister.ReplicaSets(ro.Namespace).List(labels.Everything())
 			if err != nil {
 				return err
 			}
 
 			// get shallow rollout info
 			ri := info.NewRolloutInfo(ro, allReplicaSets, allPods, nil, nil, nil)
-			send(ri)
+			if reflect.DeepEqual(sent[key], ri) {
+				continue
+			}
+			sent[key] = ri
+			eventType := "Updated"
+			if !filter.matches(ri) {
+				eventType = "Deleted"
+			}
+			if err := ws.Send(&rollout.RolloutWatchEvent{Type: eventType, RolloutInfo: ri}); err != nil {
+				return err
+			}
 		}
 	}
 }
@@ -363,28 +643,59 @@ func (s *ArgoRolloutsServer) GetNamespace(ctx context.Context, e *empty.Empty) (
 	var m = make(map[string]bool)
 	var namespaces []string
 
-	rolloutList, err := s.Options.RolloutsClientset.ArgoprojV1alpha1().Rollouts("").List(ctx, v1.ListOptions{})
-	if err == nil {
-		for _, r := range rolloutList.Items {
-			ns := r.Namespace
-			if !m[ns] {
-				m[ns] = true
-				namespaces = append(namespaces, ns)
+	namespace := s.Options.Namespace
+	if len(s.Options.AllowedNamespaces) > 0 {
+		// the namespaces the dashboard is restricted to are available even when they have no rollouts yet
+		namespaces = s.Options.AllowedNamespaces
+		if !s.namespaceAllowed(namespace) {
+			namespace = namespaces[0]
+		}
+	} else {
+		rolloutList, err := s.Options.RolloutsClientset.ArgoprojV1alpha1().Rollouts("").List(ctx, v1.ListOptions{})
+		if err == nil {
+			for _, r := range rolloutList.Items {
+				ns := r.Namespace
+				if !m[ns] {
+					m[ns] = true
+					namespaces = append(namespaces, ns)
+				}
 			}
 		}
 	}
 
-	return &rollout.NamespaceInfo{Namespace: s.Options.Namespace, AvailableNamespaces: namespaces}, nil
+	return &rollout.NamespaceInfo{
+		Namespace:           namespace,
+		AvailableNamespaces: namespaces,
+		ReadOnly:            s.Options.ReadOnly,
+		NamespaceRestricted: len(s.Options.AllowedNamespaces) > 0,
+		PodLogURLTemplate:   s.Options.PodLogURLTemplate,
+	}, nil
 }
 
 func (s *ArgoRolloutsServer) PromoteRollout(ctx context.Context, q *rollout.PromoteRolloutRequest) (*v1alpha1.Rollout, error) {
-	rolloutIf := s.Options.RolloutsClientset.ArgoprojV1alpha1().Rollouts(q.GetNamespace())
-	return promote.PromoteRollout(rolloutIf, q.GetName(), false, false, q.GetFull())
+	rolloutsClient, _, err := s.actionClients(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rolloutIf := rolloutsClient.ArgoprojV1alpha1().Rollouts(q.GetNamespace())
+	action, description := "promote", "promoted the rollout"
+	if q.GetFull() {
+		action, description = "promote-full", "fully promoted the rollout"
+	}
+	return s.audited(ctx, q.GetNamespace(), q.GetName(), action, description, func() (*v1alpha1.Rollout, error) {
+		return promote.PromoteRollout(rolloutIf, q.GetName(), false, false, q.GetFull())
+	})
 }
 
 func (s *ArgoRolloutsServer) AbortRollout(ctx context.Context, q *rollout.AbortRolloutRequest) (*v1alpha1.Rollout, error) {
-	rolloutIf := s.Options.RolloutsClientset.ArgoprojV1alpha1().Rollouts(q.GetNamespace())
-	return abort.AbortRollout(rolloutIf, q.GetName())
+	rolloutsClient, _, err := s.actionClients(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rolloutIf := rolloutsClient.ArgoprojV1alpha1().Rollouts(q.GetNamespace())
+	return s.audited(ctx, q.GetNamespace(), q.GetName(), "abort", "aborted the rollout", func() (*v1alpha1.Rollout, error) {
+		return abort.AbortRollout(rolloutIf, q.GetName())
+	})
 }
 
 func (s *ArgoRolloutsServer) getRollout(namespace string, name string) (*v1alpha1.Rollout, error) {
@@ -395,31 +706,46 @@ func (s *ArgoRolloutsServer) getRollout(namespace string, name string) (*v1alpha
 }
 
 func (s *ArgoRolloutsServer) SetRolloutImage(ctx context.Context, q *rollout.SetImageRequest) (*v1alpha1.Rollout, error) {
-	imageString := fmt.Sprintf("%s:%s", q.GetImage(), q.GetTag())
-	_, err := set.SetImage(s.Options.DynamicClientset, q.GetNamespace(), q.GetRollout(), q.GetContainer(), imageString)
+	_, dynamicClient, err := s.actionClients(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return s.getRollout(q.GetNamespace(), q.GetRollout())
+	imageString := fmt.Sprintf("%s:%s", q.GetImage(), q.GetTag())
+	description := fmt.Sprintf("set the image of container '%s' to '%s'", q.GetContainer(), imageString)
+	return s.audited(ctx, q.GetNamespace(), q.GetRollout(), "set-image", description, func() (*v1alpha1.Rollout, error) {
+		_, err := set.SetImage(dynamicClient, q.GetNamespace(), q.GetRollout(), q.GetContainer(), imageString)
+		if err != nil {
+			return nil, err
+		}
+		return s.getRollout(q.GetNamespace(), q.GetRollout())
+	})
 }
 
 func (s *ArgoRolloutsServer) UndoRollout(ctx context.Context, q *rollout.UndoRolloutRequest) (*v1alpha1.Rollout, error) {
-	rolloutIf := s.Options.DynamicClientset.Resource(v1alpha1.RolloutGVR).Namespace(q.GetNamespace())
-	_, err := undo.RunUndoRollout(rolloutIf, s.Options.KubeClientset, q.GetRollout(), q.GetRevision())
+	_, dynamicClient, err := s.actionClients(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return s.getRollout(q.GetNamespace(), q.GetRollout())
+	rolloutIf := dynamicClient.Resource(v1alpha1.RolloutGVR).Namespace(q.GetNamespace())
+	description := fmt.Sprintf("rolled the rollout back to revision %d", q.GetRevision())
+	return s.audited(ctx, q.GetNamespace(), q.GetRollout(), "undo", description, func() (*v1alpha1.Rollout, error) {
+		_, err := undo.RunUndoRollout(rolloutIf, s.Options.KubeClientset, q.GetRollout(), q.GetRevision())
+		if err != nil {
+			return nil, err
+		}
+		return s.getRollout(q.GetNamespace(), q.GetRollout())
+	})
 }
 
 func (s *ArgoRolloutsServer) RetryRollout(ctx context.Context, q *rollout.RetryRolloutRequest) (*v1alpha1.Rollout, error) {
-	rolloutIf := s.Options.RolloutsClientset.ArgoprojV1alpha1().Rollouts(q.GetNamespace())
-	ro, err := retry.RetryRollout(rolloutIf, q.GetName())
+	rolloutsClient, _, err := s.actionClients(ctx)
 	if err != nil {
 		return nil, err
 	}
-
-	return ro, nil
+	rolloutIf := rolloutsClient.ArgoprojV1alpha1().Rollouts(q.GetNamespace())
+	return s.audited(ctx, q.GetNamespace(), q.GetName(), "retry", "retried the rollout", func() (*v1alpha1.Rollout, error) {
+		return retry.RetryRollout(rolloutIf, q.GetName())
+	})
 }
 
 func (s *ArgoRolloutsServer) Version(ctx context.Context, _ *empty.Empty) (*rollout.VersionInfo, error) {
@@ -429,21 +755,35 @@ func (s *ArgoRolloutsServer) Version(ctx context.Context, _ *empty.Empty) (*roll
 	}, nil
 }
 
-func podUpdated(pod *corev1.Pod, rsLister appslisters.ReplicaSetNamespaceLister,
-	rolloutLister listers.RolloutNamespaceLister, rolloutUpdated chan *v1alpha1.Rollout) {
+// deletedObject returns the object of an informer event, which is wrapped in a tombstone when the deletion of the
+// object was missed
+func deletedObject(obj any) any {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		return tombstone.Obj
+	}
+	return obj
+}
+
+// ownerRollout returns the rollout owning a replica set, or nil if a rollout does not own it
+func ownerRollout(rs *appsv1.ReplicaSet, rolloutLister listers.RolloutLister) *v1alpha1.Rollout {
+	for _, rsOwner := range rs.GetOwnerReferences() {
+		if rsOwner.APIVersion == v1alpha1.SchemeGroupVersion.String() && rsOwner.Kind == "Rollout" {
+			if ro, err := rolloutLister.Rollouts(rs.Namespace).Get(rsOwner.Name); err == nil {
+				return ro
+			}
+		}
+	}
+	return nil
+}
+
+// podOwnerRollout returns the rollout owning the replica set of a pod, or nil if a rollout does not own it
+func podOwnerRollout(pod *corev1.Pod, rsLister appslisters.ReplicaSetLister, rolloutLister listers.RolloutLister) *v1alpha1.Rollout {
 	for _, podOwner := range pod.GetOwnerReferences() {
 		if podOwner.Kind == "ReplicaSet" {
-			rs, err := rsLister.Get(podOwner.Name)
-			if err == nil {
-				for _, rsOwner := range rs.GetOwnerReferences() {
-					if rsOwner.APIVersion == v1alpha1.SchemeGroupVersion.String() && rsOwner.Kind == "Rollout" {
-						ro, err := rolloutLister.Get(rsOwner.Name)
-						if err == nil {
-							rolloutUpdated <- ro
-						}
-					}
-				}
+			if rs, err := rsLister.ReplicaSets(pod.Namespace).Get(podOwner.Name); err == nil {
+				return ownerRollout(rs, rolloutLister)
 			}
 		}
 	}
+	return nil
 }
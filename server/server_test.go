@@ -2,11 +2,32 @@ package server
 
 import (
 	"context"
+	"encoding/json"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	kubetesting "k8s.io/client-go/testing"
+
+	"github.com/argoproj/argo-rollouts/pkg/apiclient/rollout"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	fakeclientset "github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned/fake"
 )
 
 func TestNewHTTPServer(t *testing.T) {
@@ -96,3 +117,332 @@ func TestNewHTTPServer(t *testing.T) {
 		}
 	})
 }
+
+func newListedRollout(namespace, name string, phase v1alpha1.RolloutPhase, blueGreen bool, labels map[string]string) *v1alpha1.Rollout {
+	ro := &v1alpha1.Rollout{
+		ObjectMeta: v1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels},
+		Spec: v1alpha1.RolloutSpec{
+			Strategy: v1alpha1.RolloutStrategy{Canary: &v1alpha1.CanaryStrategy{}},
+		},
+		Status: v1alpha1.RolloutStatus{Phase: phase},
+	}
+	if blueGreen {
+		ro.Spec.Strategy = v1alpha1.RolloutStrategy{BlueGreen: &v1alpha1.BlueGreenStrategy{}}
+	}
+	return ro
+}
+
+func listedNames(list *rollout.RolloutInfoList) []string {
+	var names []string
+	for _, ri := range list.Rollouts {
+		names = append(names, ri.ObjectMeta.Namespace+"/"+ri.ObjectMeta.Name)
+	}
+	return names
+}
+
+func TestGetNamespace(t *testing.T) {
+	rolloutsClient := fakeclientset.NewSimpleClientset(
+		newListedRollout("default", "guestbook", v1alpha1.RolloutPhaseHealthy, false, nil),
+		newListedRollout("staging", "guestbook", v1alpha1.RolloutPhaseHealthy, false, nil),
+		newListedRollout("staging", "api", v1alpha1.RolloutPhaseHealthy, false, nil),
+	)
+	s := NewServer(ServerOptions{
+		Namespace:         "default",
+		RolloutsClientset: rolloutsClient,
+		PodLogURLTemplate: "https://logs.example.com/?ns=${metadata.namespace}&pod=${metadata.name}",
+	})
+	info, err := s.GetNamespace(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "default", info.Namespace)
+	assert.ElementsMatch(t, []string{"default", "staging"}, info.AvailableNamespaces)
+	assert.False(t, info.ReadOnly)
+	assert.False(t, info.NamespaceRestricted)
+	assert.Equal(t, "https://logs.example.com/?ns=${metadata.namespace}&pod=${metadata.name}", info.PodLogURLTemplate)
+}
+
+func TestListRolloutInfos(t *testing.T) {
+	rolloutsClient := fakeclientset.NewSimpleClientset(
+		newListedRollout("default", "guestbook", v1alpha1.RolloutPhaseHealthy, false, map[string]string{"team": "web"}),
+		newListedRollout("default", "api", v1alpha1.RolloutPhaseDegraded, true, map[string]string{"team": "backend"}),
+		newListedRollout("staging", "guestbook", v1alpha1.RolloutPhasePaused, false, map[string]string{"team": "web"}),
+	)
+	s := NewServer(ServerOptions{KubeClientset: k8sfake.NewSimpleClientset(), RolloutsClientset: rolloutsClient})
+
+	t.Run("namespace", func(t *testing.T) {
+		list, err := s.ListRolloutInfos(context.Background(), &rollout.RolloutInfoListQuery{Namespace: "default"})
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"default/guestbook", "default/api"}, listedNames(list))
+		assert.Empty(t, list.Continue)
+	})
+
+	t.Run("all namespaces", func(t *testing.T) {
+		list, err := s.ListRolloutInfos(context.Background(), &rollout.RolloutInfoListQuery{})
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"default/guestbook", "default/api", "staging/guestbook"}, listedNames(list))
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		list, err := s.ListRolloutInfos(context.Background(), &rollout.RolloutInfoListQuery{Phase: "paused"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"staging/guestbook"}, listedNames(list))
+
+		list, err = s.ListRolloutInfos(context.Background(), &rollout.RolloutInfoListQuery{Strategy: "bluegreen"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"default/api"}, listedNames(list))
+
+		list, err = s.ListRolloutInfos(context.Background(), &rollout.RolloutInfoListQuery{LabelSelector: "team=web", Phase: "Healthy"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"default/guestbook"}, listedNames(list))
+	})
+
+	t.Run("invalid filters", func(t *testing.T) {
+		_, err := s.ListRolloutInfos(context.Background(), &rollout.RolloutInfoListQuery{Phase: "Running"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Contains(t, err.Error(), "unknown phase 'Running'")
+
+		_, err = s.ListRolloutInfos(context.Background(), &rollout.RolloutInfoListQuery{Strategy: "Recreate"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = s.ListRolloutInfos(context.Background(), &rollout.RolloutInfoListQuery{Limit: -1})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestListRolloutInfosPagination(t *testing.T) {
+	var rollouts []v1alpha1.Rollout
+	for i, phase := range []v1alpha1.RolloutPhase{
+		v1alpha1.RolloutPhaseHealthy,
+		v1alpha1.RolloutPhaseDegraded,
+		v1alpha1.RolloutPhaseDegraded,
+		v1alpha1.RolloutPhaseHealthy,
+		v1alpha1.RolloutPhaseHealthy,
+	} {
+		rollouts = append(rollouts, *newListedRollout("default", "ro-"+strconv.Itoa(i), phase, false, nil))
+	}
+	rolloutsClient := fakeclientset.NewSimpleClientset()
+	// the fake clientset ignores the limit, so the pages are listed with the index of the next rollout as the token
+	var limits []int64
+	rolloutsClient.PrependReactor("list", "rollouts", func(action kubetesting.Action) (bool, runtime.Object, error) {
+		opts := action.(kubetesting.ListActionImpl).ListOptions
+		limits = append(limits, opts.Limit)
+		start, _ := strconv.Atoi(opts.Continue)
+		end := len(rollouts)
+		if opts.Limit > 0 && start+int(opts.Limit) < end {
+			end = start + int(opts.Limit)
+		}
+		list := &v1alpha1.RolloutList{Items: rollouts[start:end]}
+		if end < len(rollouts) {
+			list.Continue = strconv.Itoa(end)
+		}
+		return true, list, nil
+	})
+	s := NewServer(ServerOptions{KubeClientset: k8sfake.NewSimpleClientset(), RolloutsClientset: rolloutsClient})
+
+	// the pages are larger than the limit, so the lists continue in the middle of a page
+	list, err := s.ListRolloutInfos(context.Background(), &rollout.RolloutInfoListQuery{Namespace: "default", Limit: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"default/ro-0", "default/ro-1"}, listedNames(list))
+	assert.NotEmpty(t, list.Continue)
+	assert.Equal(t, []int64{rolloutListPageSize}, limits)
+
+	limits = nil
+	list, err = s.ListRolloutInfos(context.Background(), &rollout.RolloutInfoListQuery{Namespace: "default", Limit: 2, Phase: "Healthy"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"default/ro-0", "default/ro-3"}, listedNames(list))
+	assert.Equal(t, []int64{rolloutListPageSize}, limits)
+
+	list, err = s.ListRolloutInfos(context.Background(), &rollout.RolloutInfoListQuery{Namespace: "default", Limit: 2, Phase: "Healthy", Continue: list.Continue})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"default/ro-4"}, listedNames(list))
+	assert.Empty(t, list.Continue)
+
+	// the pages of the API server are listed until the list is full
+	defer func(pageSize int64) { rolloutListPageSize = pageSize }(rolloutListPageSize)
+	rolloutListPageSize = 2
+	limits = nil
+	list, err = s.ListRolloutInfos(context.Background(), &rollout.RolloutInfoListQuery{Namespace: "default", Limit: 2, Phase: "Healthy"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"default/ro-0", "default/ro-3"}, listedNames(list))
+	assert.Equal(t, []int64{2, 2}, limits)
+
+	list, err = s.ListRolloutInfos(context.Background(), &rollout.RolloutInfoListQuery{Namespace: "default", Limit: 2, Phase: "Healthy", Continue: list.Continue})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"default/ro-4"}, listedNames(list))
+	assert.Empty(t, list.Continue)
+
+	_, err = s.ListRolloutInfos(context.Background(), &rollout.RolloutInfoListQuery{Namespace: "default", Continue: "not-a-token"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+type fakeWatchRolloutInfosServer struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events chan *rollout.RolloutWatchEvent
+}
+
+func (s *fakeWatchRolloutInfosServer) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeWatchRolloutInfosServer) Send(ev *rollout.RolloutWatchEvent) error {
+	s.events <- ev
+	return nil
+}
+
+func nextWatchEvent(t *testing.T, events chan *rollout.RolloutWatchEvent) *rollout.RolloutWatchEvent {
+	t.Helper()
+	select {
+	case ev := <-events:
+		return ev
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no rollout event received")
+		return nil
+	}
+}
+
+func TestWatchRolloutInfos(t *testing.T) {
+	ro := newListedRollout("default", "guestbook", v1alpha1.RolloutPhaseHealthy, false, nil)
+	ro.UID = "guestbook-uid"
+	kubeClient := k8sfake.NewSimpleClientset()
+	rolloutsClient := fakeclientset.NewSimpleClientset(ro)
+	s := NewServer(ServerOptions{KubeClientset: kubeClient, RolloutsClientset: rolloutsClient})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ws := &fakeWatchRolloutInfosServer{ctx: ctx, events: make(chan *rollout.RolloutWatchEvent)}
+	done := make(chan error)
+	go func() {
+		done <- s.WatchRolloutInfos(&rollout.RolloutInfoListQuery{Namespace: "default"}, ws)
+	}()
+
+	ev := nextWatchEvent(t, ws.events)
+	assert.Equal(t, "Updated", ev.Type)
+	assert.Equal(t, "guestbook", ev.RolloutInfo.ObjectMeta.Name)
+	assert.Empty(t, ev.RolloutInfo.ReplicaSets)
+
+	// the changes of the replica sets of the rollout are pushed without a change of the rollout
+	rs := &appsv1.ReplicaSet{ObjectMeta: v1.ObjectMeta{
+		Namespace:       "default",
+		Name:            "guestbook-abc",
+		OwnerReferences: []v1.OwnerReference{*v1.NewControllerRef(ro, v1alpha1.SchemeGroupVersion.WithKind("Rollout"))},
+	}}
+	_, err := kubeClient.AppsV1().ReplicaSets("default").Create(ctx, rs, v1.CreateOptions{})
+	require.NoError(t, err)
+	ev = nextWatchEvent(t, ws.events)
+	assert.Equal(t, "Updated", ev.Type)
+	require.Len(t, ev.RolloutInfo.ReplicaSets, 1)
+	assert.Equal(t, "guestbook-abc", ev.RolloutInfo.ReplicaSets[0].ObjectMeta.Name)
+
+	err = rolloutsClient.ArgoprojV1alpha1().Rollouts("default").Delete(ctx, "guestbook", v1.DeleteOptions{})
+	require.NoError(t, err)
+	ev = nextWatchEvent(t, ws.events)
+	assert.Equal(t, "Deleted", ev.Type)
+	assert.Equal(t, "guestbook", ev.RolloutInfo.ObjectMeta.Name)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestWatchRolloutInfosSharesInformers(t *testing.T) {
+	ro := newListedRollout("default", "guestbook", v1alpha1.RolloutPhaseHealthy, false, nil)
+	ro.Labels = map[string]string{"app": "guestbook"}
+	other := newListedRollout("default", "other", v1alpha1.RolloutPhaseHealthy, false, nil)
+	s := NewServer(ServerOptions{KubeClientset: k8sfake.NewSimpleClientset(), RolloutsClientset: fakeclientset.NewSimpleClientset(ro, other)})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	var streams []*fakeWatchRolloutInfosServer
+	for range 2 {
+		ws := &fakeWatchRolloutInfosServer{ctx: ctx, events: make(chan *rollout.RolloutWatchEvent)}
+		streams = append(streams, ws)
+		go func() {
+			done <- s.WatchRolloutInfos(&rollout.RolloutInfoListQuery{Namespace: "default", LabelSelector: "app=guestbook"}, ws)
+		}()
+	}
+	// the label selector of the query filters the rollouts of the shared informers
+	for _, ws := range streams {
+		ev := nextWatchEvent(t, ws.events)
+		assert.Equal(t, "guestbook", ev.RolloutInfo.ObjectMeta.Name)
+	}
+	s.watchInformers.lock.Lock()
+	assert.Len(t, s.watchInformers.byNamespace, 1)
+	assert.Equal(t, 2, s.watchInformers.byNamespace["default"].streams)
+	s.watchInformers.lock.Unlock()
+
+	// the informers are stopped once every stream ended
+	cancel()
+	assert.NoError(t, <-done)
+	assert.NoError(t, <-done)
+	assert.Empty(t, s.watchInformers.byNamespace)
+}
+
+func TestGRPCHandlerFunc(t *testing.T) {
+	servedHTTP := false
+	handler := grpcHandlerFunc(grpc.NewServer(), http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		servedHTTP = true
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/rollouts/", nil))
+	assert.True(t, servedHTTP)
+
+	servedHTTP = false
+	req := httptest.NewRequest(http.MethodPost, "/rollout.RolloutService/ListRolloutInfos", nil)
+	req.ProtoMajor = 2
+	req.Header.Set("Content-Type", "application/grpc")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.False(t, servedHTTP)
+	assert.Equal(t, "application/grpc", w.Header().Get("Content-Type"))
+}
+
+// newGRPCClient serves the gRPC server of the server, and returns a client of it
+func newGRPCClient(t *testing.T, s *ArgoRolloutsServer) rollout.RolloutServiceClient {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	grpcS := s.newGRPCServer()
+	go func() {
+		_ = grpcS.Serve(lis)
+	}()
+	t.Cleanup(grpcS.Stop)
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return rollout.NewRolloutServiceClient(conn)
+}
+
+// allowAllReviews makes the SubjectAccessReviews of the server allow every action
+func allowAllReviews(s *ArgoRolloutsServer) {
+	s.Options.KubeClientset.(*k8sfake.Clientset).PrependReactor("create", "subjectaccessreviews", func(action kubetesting.Action) (bool, runtime.Object, error) {
+		review := action.(kubetesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		review.Status.Allowed = true
+		return true, review, nil
+	})
+}
+
+func TestGRPCServerImpersonatesUser(t *testing.T) {
+	var users []string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		users = append(users, r.Header.Get("Impersonate-User"))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(newPausedRollout())
+	}))
+	defer apiServer.Close()
+	s, _ := newAuthServer()
+	allowAllReviews(s)
+	s.Options.RolloutsClientset = fakeclientset.NewSimpleClientset(newPausedRollout())
+	s.Options.RESTConfig = &rest.Config{Host: apiServer.URL}
+	client := newGRPCClient(t, s)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer valid-token")
+	_, err := client.AbortRollout(ctx, &rollout.AbortRolloutRequest{Namespace: "default", Name: "guestbook"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, users)
+	for _, user := range users {
+		assert.Equal(t, "alice@example.com", user)
+	}
+
+	_, err = client.AbortRollout(context.Background(), &rollout.AbortRolloutRequest{Namespace: "default", Name: "guestbook"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -25,8 +25,10 @@ import (
 	clientset "github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned"
 	informers "github.com/argoproj/argo-rollouts/pkg/client/informers/externalversions/rollouts/v1alpha1"
 	controllerutil "github.com/argoproj/argo-rollouts/utils/controller"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
 	logutil "github.com/argoproj/argo-rollouts/utils/log"
 	serviceutil "github.com/argoproj/argo-rollouts/utils/service"
+	"github.com/argoproj/argo-rollouts/utils/sharding"
 	unstructuredutil "github.com/argoproj/argo-rollouts/utils/unstructured"
 )
 
@@ -67,6 +69,8 @@ type ControllerConfig struct {
 	ResyncPeriod time.Duration
 
 	MetricsServer *metrics.MetricsServer
+	// Sharder selects the services cleaned up by this controller replica when running in sharding mode
+	Sharder *sharding.Sharder
 }
 
 // Controller describes a service controller
@@ -83,6 +87,7 @@ type Controller struct {
 
 	metricServer   *metrics.MetricsServer
 	enqueueRollout func(obj any)
+	sharder        *sharding.Sharder
 }
 
 // NewController returns a new service controller
@@ -100,6 +105,7 @@ func NewController(cfg ControllerConfig) *Controller {
 		serviceWorkqueue: cfg.ServiceWorkqueue,
 		resyncPeriod:     cfg.ResyncPeriod,
 		metricServer:     cfg.MetricsServer,
+		sharder:          cfg.Sharder,
 	}
 
 	kubectlutil.CheckErr(cfg.RolloutsInformer.Informer().AddIndexers(cache.Indexers{
@@ -202,9 +208,13 @@ func (c *Controller) syncService(ctx context.Context, key string) error {
 		}
 	}
 
+	// Only the shard of the rollout which managed the service cleans it up
+	if !c.sharder.Owns(svc) {
+		return nil
+	}
 	patch := generateRemovePatch(svc)
 	if patch != "" {
-		_, err = c.kubeclientset.CoreV1().Services(svc.Namespace).Patch(ctx, svc.Name, patchtypes.MergePatchType, []byte(patch), metav1.PatchOptions{})
+		_, err = c.kubeclientset.CoreV1().Services(svc.Namespace).Patch(ctx, svc.Name, patchtypes.MergePatchType, []byte(patch), metav1.PatchOptions{FieldManager: defaults.DefaultFieldManager})
 		if err != nil {
 			if k8serrors.IsNotFound(err) {
 				return nil
@@ -22,6 +22,7 @@ import (
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	"github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned/fake"
 	informers "github.com/argoproj/argo-rollouts/pkg/client/informers/externalversions"
+	"github.com/argoproj/argo-rollouts/utils/sharding"
 )
 
 func newService(name string, port int, selector map[string]string) *corev1.Service {
@@ -145,6 +146,24 @@ func TestSyncServiceNotReferencedByRollout(t *testing.T) {
 	assert.Equal(t, string(patch.GetPatch()), removeSelectorPatch)
 }
 
+func TestSyncServiceNotReferencedByRolloutOfOtherShard(t *testing.T) {
+	svc := newService("test-service", 80, map[string]string{
+		v1alpha1.DefaultRolloutUniqueLabelKey: "abc",
+	})
+	svc.Annotations = map[string]string{v1alpha1.ManagedByRolloutsKey: "foo"}
+
+	ctrl, kubeclient, _, _ := newFakeServiceController(svc, nil)
+	sharder, err := sharding.NewSharder(3, (sharding.GetShard(svc, 3)+1)%3)
+	assert.NoError(t, err)
+	ctrl.sharder = sharder
+
+	err = ctrl.syncService(context.Background(), "default/test-service")
+	assert.NoError(t, err)
+	for _, action := range kubeclient.Actions() {
+		assert.False(t, action.Matches("patch", "services"))
+	}
+}
+
 // TestSyncServiceWithNoManagedBy ensures a Rollout without a managed-by but has a Rollout referencing it
 // does not have the controller delete the hash selector
 func TestSyncServiceWithNoManagedBy(t *testing.T) {
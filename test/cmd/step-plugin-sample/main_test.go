@@ -11,6 +11,8 @@ import (
 	"github.com/argoproj/argo-rollouts/rollout/steps/plugin/rpc"
 	rolloutsPlugin "github.com/argoproj/argo-rollouts/rollout/steps/plugin/rpc"
 	"github.com/argoproj/argo-rollouts/test/cmd/step-plugin-sample/internal/plugin"
+	"github.com/argoproj/argo-rollouts/utils/plugin/conformance"
+	"github.com/argoproj/argo-rollouts/utils/plugin/conformance/conformancetest"
 	"github.com/argoproj/argo-rollouts/utils/plugin/types"
 
 	"github.com/tj/assert"
@@ -107,3 +109,10 @@ func TestPlugin(t *testing.T) {
 	cancel()
 	<-closeCh
 }
+
+func TestConformance(t *testing.T) {
+	conformancetest.CheckStepPlugin(t, plugin.New(log.WithFields(log.Fields{}), 0), conformance.Options{
+		PluginName: "argoproj-labs/step-plugin-sample",
+		Config:     []byte(`{"aggregate":false}`),
+	})
+}
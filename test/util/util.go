@@ -47,14 +47,16 @@ func NewFakeDynamicClient(objects ...runtime.Object) *dynamicfake.FakeDynamicCli
 	}
 
 	listMapping := map[schema.GroupVersionResource]string{
-		vsvcGVR:                             "VirtualServiceList",
-		druleGVR:                            "DestinationRuleList",
-		v1alpha1.RolloutGVR:                 rollouts.RolloutKind + "List",
-		v1alpha1.AnalysisTemplateGVR:        rollouts.AnalysisTemplateKind + "List",
-		v1alpha1.AnalysisRunGVR:             rollouts.AnalysisRunKind + "List",
-		v1alpha1.ExperimentGVR:              rollouts.ExperimentKind + "List",
-		v1alpha1.ClusterAnalysisTemplateGVR: rollouts.ClusterAnalysisTemplateKind + "List",
-		tgbGVR:                              "TargetGroupBindingList",
+		vsvcGVR:                               "VirtualServiceList",
+		druleGVR:                              "DestinationRuleList",
+		v1alpha1.RolloutGVR:                   rollouts.RolloutKind + "List",
+		v1alpha1.AnalysisTemplateGVR:          rollouts.AnalysisTemplateKind + "List",
+		v1alpha1.AnalysisRunGVR:               rollouts.AnalysisRunKind + "List",
+		v1alpha1.ExperimentGVR:                rollouts.ExperimentKind + "List",
+		v1alpha1.ClusterAnalysisTemplateGVR:   rollouts.ClusterAnalysisTemplateKind + "List",
+		v1alpha1.RolloutNotificationPolicyGVR: rollouts.RolloutNotificationPolicyKind + "List",
+		v1alpha1.RolloutControllerConfigGVR:   rollouts.RolloutControllerConfigKind + "List",
+		tgbGVR:                                "TargetGroupBindingList",
 	}
 	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme, listMapping, objects...)
 }
@@ -4,7 +4,7 @@ import * as React from 'react';
 import {KeybindingProvider} from 'react-keyhooks';
 import {Route, Router, Switch} from 'react-router-dom';
 import './App.scss';
-import {NamespaceContext, RolloutAPI} from './shared/context/api';
+import {ALL_NAMESPACES, NamespaceContext, RolloutAPI} from './shared/context/api';
 import {Modal} from './components/modal/modal';
 import {Rollout} from './components/rollout/rollout';
 import {RolloutsHome} from './components/rollouts-home/rollouts-home';
@@ -56,6 +56,9 @@ const init = window.localStorage.getItem(NAMESPACE_KEY);
 const App = () => {
     const [namespace, setNamespace] = React.useState(init);
     const [availableNamespaces, setAvailableNamespaces] = React.useState([]);
+    const [readOnly, setReadOnly] = React.useState(false);
+    const [namespaceRestricted, setNamespaceRestricted] = React.useState(false);
+    const [podLogURLTemplate, setPodLogURLTemplate] = React.useState('');
     React.useEffect(() => {
         try {
             RolloutAPI.rolloutServiceGetNamespace()
@@ -63,10 +66,15 @@ const App = () => {
                     if (!info) {
                         throw new Error();
                     }
-                    if (!namespace) {
+                    // the namespace selected last may no longer be allowed by the dashboard
+                    const allowed = !info.namespaceRestricted || namespace === ALL_NAMESPACES || (info.availableNamespaces || []).includes(namespace);
+                    if (!namespace || !allowed) {
                         setNamespace(info.namespace);
                     }
                     setAvailableNamespaces(info.availableNamespaces);
+                    setReadOnly(!!info.readOnly);
+                    setNamespaceRestricted(!!info.namespaceRestricted);
+                    setPodLogURLTemplate(info.podLogURLTemplate || '');
                 })
                 .catch((e) => {
                     setAvailableNamespaces([namespace]);
@@ -89,7 +97,7 @@ const App = () => {
 
     return (
         namespace && (
-            <NamespaceContext.Provider value={{namespace, availableNamespaces}}>
+            <NamespaceContext.Provider value={{namespace, availableNamespaces, readOnly, namespaceRestricted, podLogURLTemplate}}>
                 <KeybindingProvider>
                     <Router history={history}>
                         <Switch>
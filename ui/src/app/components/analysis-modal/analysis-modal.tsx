@@ -4,7 +4,7 @@ import {RolloutAnalysisRunInfo} from '../../../models/rollout/generated';
 
 import MetricLabel from './metric-label/metric-label';
 import {MetricPanel, SummaryPanel} from './panels';
-import {analysisEndTime, analysisStartTime, getAdjustedMetricPhase, metricStatusLabel, metricSubstatus, transformMetrics} from './transforms';
+import {analysisEndTime, analysisStartTime, firstFailedMetricName, getAdjustedMetricPhase, metricStatusLabel, metricSubstatus, transformMetrics} from './transforms';
 import {AnalysisStatus} from './types';
 
 import classNames from 'classnames';
@@ -38,6 +38,8 @@ export const AnalysisModal = ({analysis, analysisName, images, onClose, open, re
     const transformedMetrics = transformMetrics(analysis.specAndStatus);
 
     const adjustedAnalysisStatus = getAdjustedMetricPhase(analysis.status as AnalysisStatus);
+    // the charts of the first failed metric are shown first, so that users see why the analysis failed
+    const defaultTab = firstFailedMetricName(Object.values(transformedMetrics)) ?? 'analysis-summary';
 
     const tabItems = [
         {
@@ -75,7 +77,7 @@ export const AnalysisModal = ({analysis, analysisName, images, onClose, open, re
 
     return (
         <Modal centered open={open} title={analysisName} onCancel={onClose} width={866} footer={null}>
-            <Tabs className={cx('tabs')} items={tabItems} tabPosition='left' size='small' tabBarGutter={12} />
+            <Tabs className={cx('tabs')} items={tabItems} defaultActiveKey={defaultTab} tabPosition='left' size='small' tabBarGutter={12} />
         </Modal>
     );
 };
@@ -13,6 +13,7 @@ import {
     argValue,
     chartMax,
     conditionDetails,
+    firstFailedMetricName,
     formatKeyValueMeasurement,
     formatMultiItemArrayMeasurement,
     formatSingleItemArrayMeasurement,
@@ -29,7 +30,7 @@ import {
     transformMeasurementValue,
     transformMeasurements,
 } from './transforms';
-import {AnalysisStatus, FunctionalStatus} from './types';
+import {AnalysisStatus, FunctionalStatus, TransformedMetric} from './types';
 
 const MOCK_METRICS_WITHOUT_END_TIMES: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1MetricResult[] = [
     {
@@ -620,3 +621,18 @@ describe('analysis modal transforms', () => {
         });
     });
 });
+
+describe('firstFailedMetricName', () => {
+    const metric = (name: string, adjustedPhase: AnalysisStatus) => ({name, status: {adjustedPhase}}) as TransformedMetric;
+
+    it('returns null when no metric failed', () => {
+        expect(firstFailedMetricName([])).toBeNull();
+        expect(firstFailedMetricName([metric('latency', AnalysisStatus.Successful), metric('errors', AnalysisStatus.Running)])).toBeNull();
+    });
+
+    it('returns the first failed or inconclusive metric in name order', () => {
+        expect(
+            firstFailedMetricName([metric('success-rate', AnalysisStatus.Failed), metric('latency', AnalysisStatus.Inconclusive), metric('errors', AnalysisStatus.Successful)])
+        ).toBe('latency');
+    });
+});
@@ -210,6 +210,16 @@ export const chartMax = (valueMax: number, failThresholds: number[] | null, succ
  */
 export const getAdjustedMetricPhase = (phase?: AnalysisStatus): AnalysisStatus => (phase === AnalysisStatus.Error ? AnalysisStatus.Failed : (phase ?? AnalysisStatus.Unknown));
 
+/**
+ *
+ * @param metrics transformed metrics of an analysis run
+ * @returns name of the first metric, in name order, which failed or was inconclusive, or null if there is none
+ */
+export const firstFailedMetricName = (metrics: TransformedMetric[]): string | null =>
+    [...metrics]
+        .sort((a, b) => a.name.localeCompare(b.name))
+        .find((metric) => metric.status.adjustedPhase === AnalysisStatus.Failed || metric.status.adjustedPhase === AnalysisStatus.Inconclusive)?.name ?? null;
+
 /**
  *
  * @param specAndStatus analysis spec and status information
@@ -2,7 +2,7 @@ import * as React from 'react';
 
 import {useParams} from 'react-router';
 import {Key, KeybindingContext} from 'react-keyhooks';
-import {NamespaceContext, RolloutAPIContext} from '../../shared/context/api';
+import {ALL_NAMESPACES, NamespaceContext, RolloutAPIContext} from '../../shared/context/api';
 
 import './header.scss';
 import {Link, useHistory} from 'react-router-dom';
@@ -89,7 +89,7 @@ export const Header = (props: {pageHasShortcuts: boolean; changeNamespace: (val:
                         <AutoComplete
                             style={{width: 200}}
                             className='rollouts-header__namespace-selector'
-                            options={(namespaceInfo.availableNamespaces || []).map((ns) => ({label: ns, value: ns}))}
+                            options={[{label: 'All namespaces', value: ALL_NAMESPACES}, ...(namespaceInfo.availableNamespaces || []).map((ns) => ({label: ns, value: ns}))]}
                             placeholder='Namespace'
                             onChange={(val) => setNsInput(val)}
                             onSelect={(val) => {
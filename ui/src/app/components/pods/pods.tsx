@@ -7,10 +7,12 @@ import './pods.scss';
 import {Tooltip} from 'antd';
 
 import {FontAwesomeIcon} from '@fortawesome/react-fontawesome';
-import {IconDefinition, faCheck, faCircleNotch, faClipboard, faExclamationTriangle, faQuestionCircle, faTimes} from '@fortawesome/free-solid-svg-icons';
+import {IconDefinition, faCheck, faCircleNotch, faClipboard, faExclamationTriangle, faExternalLinkAlt, faQuestionCircle, faTimes} from '@fortawesome/free-solid-svg-icons';
 import {EllipsisMiddle} from '../ellipsis-middle/ellipsis-middle';
 import {InfoItem} from '../info-item/info-item';
 import {Ticker} from '../ticker/ticker';
+import {NamespaceContext} from '../../shared/context/api';
+import {podLogURL} from '../../shared/utils/utils';
 
 export enum PodStatus {
     Pending = 'pending',
@@ -74,6 +76,7 @@ export const ReplicaSets = (props: {replicaSets: RolloutReplicaSetInfo[]; showRe
 
 export const ReplicaSet = (props: {rs: RolloutReplicaSetInfo; showRevision?: boolean}) => {
     const rsName = props.rs.objectMeta.name;
+    const namespaceCtx = React.useContext(NamespaceContext);
     return (
         <div className='pods'>
             {rsName && (
@@ -115,11 +118,14 @@ export const ReplicaSet = (props: {rs: RolloutReplicaSetInfo; showRevision?: boo
                               name={pod.objectMeta?.name}
                               status={pod.status}
                               ready={pod.ready}
+                              restarts={pod.restarts}
+                              logURL={podLogURL(namespaceCtx.podLogURLTemplate, pod.objectMeta?.namespace || props.rs.objectMeta.namespace, pod.objectMeta?.name)}
                               tooltip={
                                   <div>
                                       <div>{pod.objectMeta?.name}</div>
                                       <div>Status: {pod.status}</div>
                                       <div>Ready: {pod.ready}</div>
+                                      <div>Restarts: {pod.restarts || 0}</div>
                                   </div>
                               }
                           />
@@ -130,7 +136,23 @@ export const ReplicaSet = (props: {rs: RolloutReplicaSetInfo; showRevision?: boo
     );
 };
 
-export const PodWidget = ({name, status, ready, tooltip, customIcon}: {name: string; status: string; ready: string; tooltip: React.ReactNode; customIcon?: IconDefinition}) => {
+export const PodWidget = ({
+    name,
+    status,
+    ready,
+    restarts,
+    logURL,
+    tooltip,
+    customIcon,
+}: {
+    name: string;
+    status: string;
+    ready: string;
+    restarts?: number;
+    logURL?: string;
+    tooltip: React.ReactNode;
+    customIcon?: IconDefinition;
+}) => {
     let icon: IconDefinition;
     let spin = false;
     if (status.startsWith('Init:')) {
@@ -177,12 +199,18 @@ export const PodWidget = ({name, status, ready, tooltip, customIcon}: {name: str
                 <Tooltip title={tooltip}>
                     <div className={`pod-icon pod-icon--${className}`}>
                         <FontAwesomeIcon icon={icon} spin={spin} />
+                        {restarts > 0 && <span className='pod-icon__restarts'>{restarts}</span>}
                     </div>
                 </Tooltip>
             )}>
             <div onClick={() => navigator.clipboard.writeText(name)}>
                 <FontAwesomeIcon icon={faClipboard} style={{marginRight: '5px'}} /> Copy Name
             </div>
+            {logURL && (
+                <div onClick={() => window.open(logURL, '_blank', 'noopener,noreferrer')}>
+                    <FontAwesomeIcon icon={faExternalLinkAlt} style={{marginRight: '5px'}} /> View Logs
+                </div>
+            )}
         </DropDown>
     );
 };
@@ -125,7 +125,7 @@ export const RolloutActionButton = (props: {action: RolloutAction; rollout: Roll
             onClick={async (e) => {
                 setLoading(true);
                 try {
-                    await ap.action(ap.body || {}, namespaceCtx.namespace, props.rollout.objectMeta?.name || '');
+                    await ap.action(ap.body || {}, props.rollout.objectMeta?.namespace || namespaceCtx.namespace, props.rollout.objectMeta?.name || '');
                     if (props.callback) {
                         await props.callback();
                     }
@@ -135,9 +135,9 @@ export const RolloutActionButton = (props: {action: RolloutAction; rollout: Roll
                     setLoading(false);
                 }
             }}
-            disabled={ap.disabled}
+            disabled={ap.disabled || namespaceCtx.readOnly}
             loading={loading}
-            tooltip={ap.tooltip}
+            tooltip={namespaceCtx.readOnly ? 'The dashboard is read-only' : ap.tooltip}
             icon={<FontAwesomeIcon icon={ap.icon} style={{marginRight: '5px'}} />}
         >
             {props.action}
@@ -145,12 +145,18 @@ export const RolloutActionButton = (props: {action: RolloutAction; rollout: Roll
     );
 };
 
-export const RolloutActions = (props: {rollout: RolloutInfo}) => (
-    <div style={{display: 'flex'}}>
-        {Object.values(RolloutAction).map((action) => (
-            <RolloutActionButton key={action} action={action as RolloutAction} rollout={props.rollout} indicateLoading />
-        ))}
-    </div>
-);
+export const RolloutActions = (props: {rollout: RolloutInfo}) => {
+    const namespaceCtx = React.useContext(NamespaceContext);
+    if (namespaceCtx.readOnly) {
+        return null;
+    }
+    return (
+        <div style={{display: 'flex'}}>
+            {Object.values(RolloutAction).map((action) => (
+                <RolloutActionButton key={action} action={action as RolloutAction} rollout={props.rollout} indicateLoading />
+            ))}
+        </div>
+    );
+};
 
 export default RolloutActions;
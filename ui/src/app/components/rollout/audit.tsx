@@ -0,0 +1,72 @@
+import * as React from 'react';
+import {Table, notification} from 'antd';
+
+import {RolloutAuditEvent} from '../../../models/rollout/generated';
+import {RolloutAPIContext} from '../../shared/context/api';
+import {formatTimestamp} from '../../shared/utils/utils';
+
+// AuditLog lists the actions taken on a rollout through the dashboard, the most recent first
+export const AuditLog = (props: {namespace: string; name: string}) => {
+    const api = React.useContext(RolloutAPIContext);
+    const [events, setEvents] = React.useState<RolloutAuditEvent[]>([]);
+    const [loading, setLoading] = React.useState(true);
+
+    React.useEffect(() => {
+        setLoading(true);
+        api.rolloutServiceListRolloutAuditEvents(props.namespace, props.name)
+            .then((list) => setEvents(list.events || []))
+            .catch((error) => {
+                notification.error({
+                    message: 'Error fetching audit log',
+                    description: error.message || 'An unexpected error occurred while fetching the audit log.',
+                    duration: 8,
+                    placement: 'bottomRight',
+                });
+            })
+            .finally(() => setLoading(false));
+    }, [api, props.namespace, props.name]);
+
+    const columns = [
+        {
+            title: 'Time',
+            dataIndex: 'timestamp',
+            key: 'timestamp',
+            width: 200,
+            render: (timestamp: RolloutAuditEvent['timestamp']) => formatTimestamp(JSON.stringify(timestamp)),
+        },
+        {
+            title: 'User',
+            dataIndex: 'user',
+            key: 'user',
+            width: 200,
+            render: (user: string) => user || '-',
+        },
+        {
+            title: 'Action',
+            dataIndex: 'action',
+            key: 'action',
+            width: 120,
+        },
+        {
+            title: 'Prior State',
+            dataIndex: 'priorState',
+            key: 'priorState',
+        },
+        {
+            title: 'Message',
+            dataIndex: 'message',
+            key: 'message',
+        },
+    ];
+
+    return (
+        <Table
+            className='rollout__audit'
+            columns={columns}
+            dataSource={events.map((event, i) => ({...event, key: i}))}
+            loading={loading}
+            pagination={{pageSize: 20, hideOnSinglePage: true}}
+            locale={{emptyText: 'No actions were taken on this rollout through the dashboard'}}
+        />
+    );
+};
@@ -17,6 +17,8 @@ import {NamespaceContext, RolloutAPIContext} from '../../shared/context/api';
 import {useWatchRollout} from '../../shared/services/rollout';
 import {ImageTag} from '../../shared/utils/utils';
 import {RolloutStatus, StatusIcon} from '../status-icon/status-icon';
+import {AuditLog} from './audit';
+import {WeightHistory} from './weight-history';
 import {ContainersWidget} from './containers';
 import {Revision, RevisionWidget} from './revision';
 import './rollout.scss';
@@ -24,7 +26,7 @@ import {Fragment} from 'react';
 import {FontAwesomeIcon} from '@fortawesome/react-fontawesome';
 import {faChevronCircleDown, faChevronCircleUp, faCircleNotch} from '@fortawesome/free-solid-svg-icons';
 import {InfoItemKind, InfoItemRow} from '../info-item/info-item';
-import { notification } from 'antd';
+import { Tabs, notification } from 'antd';
 
 const RolloutActions = React.lazy(() => import('../rollout-actions/rollout-actions'));
 export interface ImageInfo {
@@ -208,6 +210,14 @@ export const RolloutWidget = (props: {rollout: RolloutRolloutInfo; interactive?:
                     </div>}
                 </div>
 
+                {rollout.weightHistory && rollout.weightHistory.length > 0 && (
+                    <div className='rollout__row'>
+                        <div className='info rollout__info'>
+                            <div className='info__title'>Traffic Weight History</div>
+                            <WeightHistory history={rollout.weightHistory} />
+                        </div>
+                    </div>
+                )}
                 <div className='rollout__row rollout__row--bottom'>
                     {rollout.replicaSets && rollout.replicaSets.length > 0 && (
                         <div className='info rollout__info rollout__revisions'>
@@ -281,7 +291,25 @@ export const Rollout = () => {
                 </div>
             </div>
 
-            <div className='rollout__body'>{!loading && <RolloutWidget rollout={rollout} interactive={{api, editState, namespace: namespaceCtx.namespace}} />}</div>
+            <Tabs
+                className='rollout__tabs'
+                items={[
+                    {
+                        key: 'overview',
+                        label: 'Overview',
+                        children: <div className='rollout__body'>{!loading && <RolloutWidget rollout={rollout} interactive={namespaceCtx.readOnly ? undefined : {api, editState, namespace: namespaceCtx.namespace}} />}</div>,
+                    },
+                    {
+                        key: 'audit',
+                        label: 'Audit',
+                        children: (
+                            <div className='rollout__body'>
+                                <AuditLog namespace={namespaceCtx.namespace} name={name} />
+                            </div>
+                        ),
+                    },
+                ]}
+            />
         </div>
     );
 };
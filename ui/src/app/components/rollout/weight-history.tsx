@@ -0,0 +1,93 @@
+import * as React from 'react';
+import * as moment from 'moment';
+import {CartesianGrid, DotProps, Legend, Line, LineChart, ResponsiveContainer, Tooltip, TooltipProps, XAxis, YAxis} from 'recharts';
+import {NameType, ValueType} from 'recharts/types/component/DefaultTooltipContent';
+
+import {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1TrafficWeightRecord as TrafficWeightRecord} from '../../../models/rollout/generated';
+
+import './weight-history.scss';
+
+const ROUTER_COLORS = ['#0dadea', '#8fa4b1', '#f4c030', '#18be94', '#e96d76'];
+
+interface WeightPoint {
+    time: number;
+    weight: number;
+    router: string;
+    verified?: boolean;
+    // extended is true for the point extending the last weight of a router to now
+    extended?: boolean;
+}
+
+// weightSeries returns the weights set on every traffic router over time. The last weight of every router is
+// extended to now, so that the steps show the weight the rollout is at.
+export const weightSeries = (history: TrafficWeightRecord[], now: number): Map<string, WeightPoint[]> => {
+    const series = new Map<string, WeightPoint[]>();
+    for (const record of history || []) {
+        const points = series.get(record.router) || [];
+        points.push({time: new Date(`${record.time}`).getTime(), weight: record.desiredWeight || 0, router: record.router, verified: record.verified});
+        series.set(record.router, points);
+    }
+    series.forEach((points) => {
+        const last = points[points.length - 1];
+        points.push({...last, time: Math.max(now, last.time), extended: true});
+    });
+    return series;
+};
+
+const verifiedLabel = (verified?: boolean) => {
+    if (verified === undefined || verified === null) {
+        return 'not verified by the router';
+    }
+    return verified ? 'verified' : 'not yet verified';
+};
+
+const WeightDot = ({cx, cy, payload, stroke}: DotProps & {payload?: WeightPoint}) =>
+    payload?.extended ? <g /> : <circle r={4} cx={cx} cy={cy} stroke={stroke} strokeWidth={2} fill={payload?.verified === false ? 'white' : stroke} />;
+
+const WeightTooltip = ({active, payload}: TooltipProps<ValueType, NameType>) => {
+    if (!active || !payload?.length) {
+        return null;
+    }
+    const point = payload[0].payload as WeightPoint;
+    return (
+        <div className='weight-history__tooltip'>
+            <div>{moment(point.time).format('MMM D YYYY [at] hh:mm:ss')}</div>
+            <div>
+                {point.router}: {point.weight}% ({verifiedLabel(point.verified)})
+            </div>
+        </div>
+    );
+};
+
+// WeightHistory charts the canary traffic weight set on every traffic router over time
+export const WeightHistory = (props: {history: TrafficWeightRecord[]}) => {
+    const series = weightSeries(props.history, Date.now());
+    if (series.size === 0) {
+        return null;
+    }
+    return (
+        <div className='weight-history'>
+            <ResponsiveContainer width='100%' height={220}>
+                <LineChart margin={{top: 10, right: 20, bottom: 5, left: 0}}>
+                    <CartesianGrid strokeDasharray='3 3' />
+                    <XAxis dataKey='time' type='number' scale='time' domain={['dataMin', 'dataMax']} tickFormatter={(time: number) => moment(time).format('LT')} />
+                    <YAxis domain={[0, 100]} unit='%' />
+                    <Tooltip content={<WeightTooltip />} />
+                    <Legend />
+                    {Array.from(series.entries()).map(([router, points], i) => (
+                        <Line
+                            key={router}
+                            name={router}
+                            data={points}
+                            dataKey='weight'
+                            type='stepAfter'
+                            stroke={ROUTER_COLORS[i % ROUTER_COLORS.length]}
+                            dot={<WeightDot />}
+                            isAnimationActive={false}
+                        />
+                    ))}
+                </LineChart>
+            </ResponsiveContainer>
+        </div>
+    );
+};
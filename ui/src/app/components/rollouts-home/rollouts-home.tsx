@@ -2,8 +2,9 @@ import * as React from 'react';
 
 import {FontAwesomeIcon} from '@fortawesome/react-fontawesome';
 import {faCircleNotch} from '@fortawesome/free-solid-svg-icons';
+import {Button} from 'antd';
 
-import {NamespaceContext} from '../../shared/context/api';
+import {ALL_NAMESPACES, NamespaceContext} from '../../shared/context/api';
 import {useWatchRollouts} from '../../shared/services/rollout';
 import {RolloutsToolbar, defaultDisplayMode, Filters} from '../rollouts-toolbar/rollouts-toolbar';
 import {RolloutsTable} from '../rollouts-table/rollouts-table';
@@ -14,6 +15,7 @@ export const RolloutsHome = () => {
     const rolloutsList = useWatchRollouts();
     const rollouts = rolloutsList.items;
     const loading = rolloutsList.loading;
+    const loadMore = rolloutsList.loadMore;
     const namespaceCtx = React.useContext(NamespaceContext);
 
     const [filters, setFilters] = React.useState<Filters>({
@@ -148,9 +150,14 @@ export const RolloutsHome = () => {
                     <React.Fragment>
                         {filters.displayMode === 'table' && <RolloutsTable rollouts={filteredRollouts} onFavoriteChange={handleFavoriteChange} favorites={favorites} />}
                         {filters.displayMode !== 'table' && <RolloutsGrid rollouts={filteredRollouts} onFavoriteChange={handleFavoriteChange} favorites={favorites} />}
+                        {loadMore && (
+                            <div className='rollouts-list__load-more'>
+                                <Button onClick={loadMore}>Load more rollouts</Button>
+                            </div>
+                        )}
                     </React.Fragment>
                 ) : (
-                    <EmptyMessage namespace={namespaceCtx.namespace} />
+                    <EmptyMessage namespace={namespaceCtx.namespace === ALL_NAMESPACES ? 'all namespaces' : namespaceCtx.namespace} />
                 )}
             </div>
         </div>
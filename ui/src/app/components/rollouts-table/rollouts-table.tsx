@@ -12,6 +12,7 @@ import {RolloutStatus, StatusIcon} from '../status-icon/status-icon';
 import {ReplicaSetStatus, ReplicaSetStatusIcon} from '../status-icon/status-icon';
 import {RolloutInfo} from '../../../models/rollout/rollout';
 import {InfoItemKind, InfoItemRow} from '../info-item/info-item';
+import {ALL_NAMESPACES, NamespaceContext} from '../../shared/context/api';
 import { AlignType } from 'rc-table/lib/interface';
 import './rollouts-table.scss';
 
@@ -25,6 +26,7 @@ export const RolloutsTable = ({
     favorites: {[key: string]: boolean};
 }) => {
     const tableRef = React.useRef(null);
+    const namespaceCtx = React.useContext(NamespaceContext);
 
     const handleFavoriteChange = (rolloutName: string, isFavorite: boolean) => {
         onFavoriteChange(rolloutName, isFavorite);
@@ -84,6 +86,18 @@ export const RolloutsTable = ({
             render: (objectMeta: {name?: string}) => objectMeta.name,
             sorter: (a: any, b: any) => a.objectMeta.name.localeCompare(b.objectMeta.name),
         },
+        ...(namespaceCtx.namespace === ALL_NAMESPACES
+            ? [
+                  {
+                      title: 'Namespace',
+                      dataIndex: 'objectMeta',
+                      key: 'namespace',
+                      width: 200,
+                      render: (objectMeta: {namespace?: string}) => objectMeta.namespace,
+                      sorter: (a: any, b: any) => a.objectMeta.namespace.localeCompare(b.objectMeta.namespace),
+                  },
+              ]
+            : []),
         {
             title: 'Strategy',
             dataIndex: 'strategy',
@@ -239,7 +253,7 @@ export const RolloutsTable = ({
     });
     useKeybinding(Key.ENTER, () => {
         if (selectedRow !== undefined) {
-            history.push(`/rollout/${data[selectedRow].objectMeta?.name}`);
+            history.push(`/rollout/${data[selectedRow].objectMeta?.namespace}/${data[selectedRow].objectMeta?.name}`);
             return true;
         }
         return false;
@@ -262,7 +276,7 @@ export const RolloutsTable = ({
             onRow={(record: RolloutInfo, index: number) => ({
                 className: selectedRow === index ? 'rollouts-table__row__selected' : '',
                 onClick: () => {
-                    history.push(`/rollout/${record.objectMeta?.name}`);
+                    history.push(`/rollout/${record.objectMeta?.namespace}/${record.objectMeta?.name}`);
                 },
                 style: {cursor: 'pointer'},
             })}
@@ -17,12 +17,26 @@ const getApiBasePath = (): string => {
 export { getApiBasePath };
 
 
+// authFetch redirects to the login of the dashboard when the API requires the user to log in
+const authFetch = (url: string, init?: any): Promise<Response> => {
+    return fetch(url, init).then((response) => {
+        if (response.status === 401) {
+            window.location.href = getApiBasePath() + '/auth/login';
+        }
+        return response;
+    });
+};
+
 const basePath = getApiBasePath();
-export const RolloutAPI = new RolloutServiceApi(new Configuration({ basePath }));
+export const RolloutAPI = new RolloutServiceApi(new Configuration({ basePath }), basePath, authFetch);
 export const RolloutAPIContext = React.createContext(RolloutAPI);
 
 export const APIProvider = (props: {children: React.ReactNode}) => {
     return <RolloutAPIContext.Provider value={RolloutAPI}>{props.children}</RolloutAPIContext.Provider>;
 };
 
+// ALL_NAMESPACES is the namespace of the dashboard listing the rollouts of all the namespaces. It cannot clash with a
+// namespace, whose name cannot start with an underscore.
+export const ALL_NAMESPACES = '_all';
+
 export const NamespaceContext = React.createContext<RolloutNamespaceInfo>({namespace: '', availableNamespaces: []});
@@ -2,19 +2,32 @@ import {RolloutRolloutWatchEvent, RolloutServiceApiFetchParamCreator} from '../.
 import {ListState, useLoading, useWatch, useWatchList} from '../utils/watch';
 import {RolloutInfo} from '../../../models/rollout/rollout';
 import * as React from 'react';
-import {NamespaceContext, RolloutAPIContext, getApiBasePath} from '../context/api';
+import {ALL_NAMESPACES, NamespaceContext, RolloutAPIContext, getApiBasePath} from '../context/api';
 import { notification } from 'antd';
 
-export const useRollouts = (): RolloutInfo[] => {
+// PAGE_SIZE is the number of rollouts listed at once, so that the dashboard does not list every rollout of a big cluster
+export const PAGE_SIZE = 100;
+
+export interface RolloutListState extends ListState<RolloutInfo> {
+    // loadMore lists the next page of rollouts. It is undefined once all the rollouts are listed.
+    loadMore?: () => void;
+}
+
+export const useRollouts = (): [RolloutInfo[], () => void] => {
     const api = React.useContext(RolloutAPIContext);
     const namespaceCtx = React.useContext(NamespaceContext);
     const [rollouts, setRollouts] = React.useState([]);
+    const [continueToken, setContinueToken] = React.useState('');
 
-    React.useEffect(() => {
-        const fetchList = async () => {
+    const fetchPage = React.useCallback(
+        async (previous: RolloutInfo[], token: string) => {
             try {
-                const list = await api.rolloutServiceListRolloutInfos(namespaceCtx.namespace);
-                setRollouts(list.rollouts || []);
+                const list =
+                    namespaceCtx.namespace === ALL_NAMESPACES
+                        ? await api.rolloutServiceListRolloutInfos2(undefined, undefined, undefined, undefined, `${PAGE_SIZE}`, token || undefined)
+                        : await api.rolloutServiceListRolloutInfos(namespaceCtx.namespace, undefined, undefined, undefined, `${PAGE_SIZE}`, token || undefined);
+                setRollouts([...previous, ...(list.rollouts || [])]);
+                setContinueToken(list.continue || '');
             } catch (error) {
                 console.error('Error fetching rollouts:', error);
                 notification.error({
@@ -24,20 +37,33 @@ export const useRollouts = (): RolloutInfo[] => {
                     placement: 'bottomRight',
                 });
             }
-        };
-        fetchList();
-    }, [api, namespaceCtx]);
+        },
+        [api, namespaceCtx]
+    );
+
+    React.useEffect(() => {
+        fetchPage([], '');
+    }, [fetchPage]);
 
-    return rollouts;
+    const loadMore = React.useCallback(() => fetchPage(rollouts, continueToken), [fetchPage, rollouts, continueToken]);
+    return [rollouts, continueToken ? loadMore : undefined];
 };
 
-export const useWatchRollouts = (): ListState<RolloutInfo> => {
-    const findRollout = React.useCallback((ri: RolloutInfo, change: RolloutRolloutWatchEvent) => ri.objectMeta.name === change.rolloutInfo?.objectMeta?.name, []);
+export const useWatchRollouts = (): RolloutListState => {
+    const findRollout = React.useCallback(
+        (ri: RolloutInfo, change: RolloutRolloutWatchEvent) =>
+            ri.objectMeta.name === change.rolloutInfo?.objectMeta?.name && ri.objectMeta.namespace === change.rolloutInfo?.objectMeta?.namespace,
+        []
+    );
     const getRollout = React.useCallback((c) => c.rolloutInfo as RolloutInfo, []);
     const namespaceCtx = React.useContext(NamespaceContext);
-    const streamUrl = getApiBasePath() + RolloutServiceApiFetchParamCreator().rolloutServiceWatchRolloutInfos(namespaceCtx.namespace).url;
+    const streamUrl =
+        getApiBasePath() +
+        (namespaceCtx.namespace === ALL_NAMESPACES
+            ? RolloutServiceApiFetchParamCreator().rolloutServiceWatchRolloutInfos2().url
+            : RolloutServiceApiFetchParamCreator().rolloutServiceWatchRolloutInfos(namespaceCtx.namespace).url);
 
-    const init = useRollouts();
+    const [init, loadMore] = useRollouts();
     const loading = useLoading(init);
 
     const [rollouts, setRollouts] = React.useState(init);
@@ -50,7 +76,8 @@ export const useWatchRollouts = (): ListState<RolloutInfo> => {
     return {
         items: liveList,
         loading,
-    } as ListState<RolloutInfo>;
+        loadMore,
+    };
 };
 
 export const useWatchRollout = (name: string, subscribe: boolean, timeoutAfter?: number, callback?: (ri: RolloutInfo) => void): [RolloutInfo, boolean] => {
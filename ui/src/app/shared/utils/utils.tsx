@@ -23,6 +23,15 @@ export function formatTimestamp(ts: string): string {
     return m.format('MMM D YYYY [at] hh:mm:ss');
 }
 
+// podLogURL returns the URL of the logs of a pod from the log URL template of the dashboard, or undefined when the
+// dashboard has no template
+export function podLogURL(template: string, namespace: string, name: string): string | undefined {
+    if (!template) {
+        return undefined;
+    }
+    return template.split('${metadata.namespace}').join(encodeURIComponent(namespace)).split('${metadata.name}').join(encodeURIComponent(name));
+}
+
 export enum ImageTag {
     Canary = 'canary',
     Stable = 'stable',
@@ -356,6 +356,37 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AntiAffinit
      * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AntiAffinity
      */
     requiredDuringSchedulingIgnoredDuringExecution?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RequiredDuringSchedulingIgnoredDuringExecution;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AntiAffinityTopologySpread}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AntiAffinity
+     */
+    topologySpread?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AntiAffinityTopologySpread;
+}
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AntiAffinityTopologySpread
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AntiAffinityTopologySpread {
+    /**
+     * 
+     * @type {Array<string>}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AntiAffinityTopologySpread
+     */
+    topologyKeys?: Array<string>;
+    /**
+     * 
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AntiAffinityTopologySpread
+     */
+    maxSkew?: number;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AntiAffinityTopologySpread
+     */
+    whenUnsatisfiable?: string;
 }
 /**
  * 
@@ -547,6 +578,106 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AwsResource
      */
     fullName?: string;
 }
+/**
+ * AzureMonitorMetric defines the Azure Monitor platform metric to query. The metric is queried with the credentials of the controller, such as Azure Workload Identity.
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AzureMonitorMetric
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AzureMonitorMetric {
+    /**
+     * ResourceID is the ID of the Azure resource of the metric, e.g. /subscriptions/<id>/resourceGroups/<group>/providers/Microsoft.Network/applicationGateways/<name>
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AzureMonitorMetric
+     */
+    resourceId?: string;
+    /**
+     * MetricName is the name of the metric
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AzureMonitorMetric
+     */
+    metricName?: string;
+    /**
+     * MetricNamespace is the namespace of the metric. Defaults to the namespace of the type of the resource
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AzureMonitorMetric
+     */
+    metricNamespace?: string;
+    /**
+     * Aggregation of the metric over the interval. Defaults to Average
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AzureMonitorMetric
+     */
+    aggregation?: string;
+    /**
+     * Filter selects the time series of the metric by their dimensions, e.g. "StatusCode eq '500'"
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AzureMonitorMetric
+     */
+    filter?: string;
+    /**
+     * Interval is the time range the metric is aggregated over, ending at the time of the measurement. Defaults to 5m
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AzureMonitorMetric
+     */
+    interval?: string;
+}
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1BlueGreenGatewayAPIPreviewRouting
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1BlueGreenGatewayAPIPreviewRouting {
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1BlueGreenGatewayAPIPreviewRouting
+     */
+    httpRoute?: string;
+}
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1BlueGreenIstioPreviewRouting
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1BlueGreenIstioPreviewRouting {
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1BlueGreenIstioPreviewRouting
+     */
+    virtualService?: string;
+}
+/**
+ * BlueGreenPreviewRouting defines a header-matched route which sends requests to the preview service before the switchover. The route is added by the controller when a preview ReplicaSet exists and removed after promotion or abort.
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1BlueGreenPreviewRouting
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1BlueGreenPreviewRouting {
+    /**
+     * Name of the route managed by the controller. It must not collide with a route defined in the VirtualService or HTTPRoute.
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1BlueGreenPreviewRouting
+     */
+    name?: string;
+    /**
+     * 
+     * @type {Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1HeaderRoutingMatch>}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1BlueGreenPreviewRouting
+     */
+    match?: Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1HeaderRoutingMatch>;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1BlueGreenIstioPreviewRouting}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1BlueGreenPreviewRouting
+     */
+    istio?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1BlueGreenIstioPreviewRouting;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1BlueGreenGatewayAPIPreviewRouting}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1BlueGreenPreviewRouting
+     */
+    gatewayAPI?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1BlueGreenGatewayAPIPreviewRouting;
+}
 /**
  * 
  * @export
@@ -638,6 +769,12 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1BlueGreenSt
      * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1BlueGreenStrategy
      */
     scaleDownDelayRevisionLimit?: number;
+    /**
+     * 
+     * @type {Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ScaleDownDelayOverride>}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1BlueGreenStrategy
+     */
+    scaleDownDelayOverrides?: Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ScaleDownDelayOverride>;
     /**
      * 
      * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutAnalysis}
@@ -656,6 +793,12 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1BlueGreenSt
      * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1BlueGreenStrategy
      */
     postPromotionAnalysis?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutAnalysis;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1PostPromotionSmokeTest}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1BlueGreenStrategy
+     */
+    postPromotionSmokeTest?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1PostPromotionSmokeTest;
     /**
      * 
      * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1PodTemplateMetadata}
@@ -674,6 +817,18 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1BlueGreenSt
      * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1BlueGreenStrategy
      */
     abortScaleDownDelaySeconds?: number;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1PreviewReplicaProfile}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1BlueGreenStrategy
+     */
+    previewReplicaProfile?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1PreviewReplicaProfile;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1BlueGreenPreviewRouting}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1BlueGreenStrategy
+     */
+    previewRouting?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1BlueGreenPreviewRouting;
 }
 /**
  * 
@@ -717,6 +872,72 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStatu
      * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStatus
      */
     stepPluginStatuses?: Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1StepPluginStatus>;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutAnalysisRunStatus}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStatus
+     */
+    currentGuardrailAnalysisRunStatus?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutAnalysisRunStatus;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1WeightOverride}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStatus
+     */
+    weightOverride?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1WeightOverride;
+    /**
+     * 
+     * @type {Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1TrafficWeightRecord>}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStatus
+     */
+    weightHistory?: Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1TrafficWeightRecord>;
+    /**
+     * 
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStatus
+     */
+    stableReplicas?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStatus
+     */
+    canaryReplicas?: number;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStatus
+     */
+    canarySelector?: string;
+    /**
+     * 
+     * @type {Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1FeatureFlagStatus>}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStatus
+     */
+    featureFlags?: Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1FeatureFlagStatus>;
+    /**
+     * 
+     * @type {Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1MigrationStatus>}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStatus
+     */
+    migrations?: Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1MigrationStatus>;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1GateStatus}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStatus
+     */
+    gate?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1GateStatus;
+    /**
+     * 
+     * @type {K8sIoApimachineryPkgApisMetaV1Time}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStatus
+     */
+    currentStepStartedAt?: K8sIoApimachineryPkgApisMetaV1Time;
+    /**
+     * 
+     * @type {Array<string>}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStatus
+     */
+    stableFeatureFlags?: Array<string>;
 }
 /**
  * CanaryStep defines a step of a canary deployment.
@@ -772,6 +993,48 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStep
      * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStep
      */
     plugin?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1PluginStep;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1StepProgressDeadline}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStep
+     */
+    progressDeadline?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1StepProgressDeadline;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1PodTemplateOverlay}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStep
+     */
+    setCanaryPodTemplateOverlay?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1PodTemplateOverlay;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutLoadTestStep}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStep
+     */
+    loadTest?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutLoadTestStep;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutPreProvisionStep}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStep
+     */
+    preProvision?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutPreProvisionStep;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1SetFeatureFlag}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStep
+     */
+    setFeatureFlag?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1SetFeatureFlag;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutMigrationStep}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStep
+     */
+    migration?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutMigrationStep;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutGateStep}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStep
+     */
+    gate?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutGateStep;
 }
 /**
  * 
@@ -851,6 +1114,12 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStrat
      * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStrategy
      */
     scaleDownDelayRevisionLimit?: number;
+    /**
+     * 
+     * @type {Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ScaleDownDelayOverride>}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStrategy
+     */
+    scaleDownDelayOverrides?: Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ScaleDownDelayOverride>;
     /**
      * 
      * @type {number}
@@ -881,6 +1150,42 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStrat
      * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStrategy
      */
     replicaProgressThreshold?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ReplicaProgressThreshold;
+    /**
+     * 
+     * @type {boolean}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStrategy
+     */
+    createServices?: boolean;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutGuardrail}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStrategy
+     */
+    guardrail?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutGuardrail;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ScaleDownVerification}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStrategy
+     */
+    scaleDownVerification?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ScaleDownVerification;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1KEDACoordination}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStrategy
+     */
+    keda?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1KEDACoordination;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ReplicaSetPodDisruptionBudget}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStrategy
+     */
+    podDisruptionBudget?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ReplicaSetPodDisruptionBudget;
+    /**
+     * HPAScalesStable reports the pods of the stable ReplicaSet, instead of the pods of every ReplicaSet, to the HPA through status.HPAReplicas and status.selector while the canary is rolled out with traffic routing and without dynamic stable scale. spec.replicas is then the replica count of the stable ReplicaSet, which the canary ReplicaSet is scaled to the canary weight of, so the HPA follows the load of the stable pods only.
+     * @type {boolean}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CanaryStrategy
+     */
+    hpaScalesStable?: boolean;
 }
 /**
  * 
@@ -900,6 +1205,18 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CloudWatchM
      * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CloudWatchMetric
      */
     metricDataQueries?: Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CloudWatchMetricDataQuery>;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CloudWatchMetric
+     */
+    region?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1CloudWatchMetric
+     */
+    roleArn?: string;
 }
 /**
  * 
@@ -1068,6 +1385,37 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1DatadogMetr
      */
     secretRef?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1SecretRef;
 }
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1DrainProbe
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1DrainProbe {
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1DrainProbe
+     */
+    path?: string;
+    /**
+     * 
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1DrainProbe
+     */
+    port?: number;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1DrainProbe
+     */
+    scheme?: string;
+    /**
+     * 
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1DrainProbe
+     */
+    timeoutSeconds?: number;
+}
 /**
  * DryRun defines the settings for running the analysis in Dry-Run mode.
  * @export
@@ -1084,22 +1432,172 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1DryRun {
 /**
  * 
  * @export
- * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1FieldRef
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1EphemeralMetadataEnvVar
  */
-export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1FieldRef {
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1EphemeralMetadataEnvVar {
     /**
      * 
      * @type {string}
-     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1FieldRef
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1EphemeralMetadataEnvVar
      */
-    fieldPath?: string;
-}
-/**
- * 
- * @export
- * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1GraphiteMetric
- */
-export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1GraphiteMetric {
+    name?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1EphemeralMetadataEnvVar
+     */
+    label?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1EphemeralMetadataEnvVar
+     */
+    annotation?: string;
+}
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ExperimentWinnerCandidate
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ExperimentWinnerCandidate {
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ExperimentWinnerCandidate
+     */
+    templateName?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ExperimentWinnerCandidate
+     */
+    analysisName?: string;
+}
+/**
+ * ExperimentWinnerSelection defines how the template which won an experiment is selected. A candidate whose analysis does not succeed loses the experiment, instead of failing it.
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ExperimentWinnerSelection
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ExperimentWinnerSelection {
+    /**
+     * 
+     * @type {Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ExperimentWinnerCandidate>}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ExperimentWinnerSelection
+     */
+    candidates?: Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ExperimentWinnerCandidate>;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ExperimentWinnerSelection
+     */
+    metricName?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ExperimentWinnerSelection
+     */
+    goal?: string;
+}
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1FeatureFlagStatus
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1FeatureFlagStatus {
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1FeatureFlagStatus
+     */
+    flag?: string;
+    /**
+     * 
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1FeatureFlagStatus
+     */
+    percentage?: number;
+}
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1FeatureFlagVariants
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1FeatureFlagVariants {
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1FeatureFlagVariants
+     */
+    on?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1FeatureFlagVariants
+     */
+    off?: string;
+}
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1FieldRef
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1FieldRef {
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1FieldRef
+     */
+    fieldPath?: string;
+}
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1GateStatus
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1GateStatus {
+    /**
+     * 
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1GateStatus
+     */
+    stepIndex?: number;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1GateStatus
+     */
+    podTemplateHash?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1GateStatus
+     */
+    decision?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1GateStatus
+     */
+    message?: string;
+    /**
+     * 
+     * @type {K8sIoApimachineryPkgApisMetaV1Time}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1GateStatus
+     */
+    lastCheckTime?: K8sIoApimachineryPkgApisMetaV1Time;
+    /**
+     * 
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1GateStatus
+     */
+    consecutiveErrors?: number;
+}
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1GraphiteMetric
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1GraphiteMetric {
     /**
      * 
      * @type {string}
@@ -1132,6 +1630,62 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1HeaderRouti
      */
     headerValue?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1StringMatch;
 }
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ImageVerification
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ImageVerification {
+    /**
+     * 
+     * @type {Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1SecretKeyRef>}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ImageVerification
+     */
+    publicKeys?: Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1SecretKeyRef>;
+    /**
+     * 
+     * @type {Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ImageVerificationIdentity>}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ImageVerification
+     */
+    identities?: Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ImageVerificationIdentity>;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1SecretKeyRef}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ImageVerification
+     */
+    certificateAuthorities?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1SecretKeyRef;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1SecretKeyRef}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ImageVerification
+     */
+    transparencyLogPublicKey?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1SecretKeyRef;
+}
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ImageVerificationIdentity
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ImageVerificationIdentity {
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ImageVerificationIdentity
+     */
+    issuer?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ImageVerificationIdentity
+     */
+    subject?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ImageVerificationIdentity
+     */
+    subjectRegex?: string;
+}
 /**
  * 
  * @export
@@ -1257,6 +1811,38 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1JobMetric {
      */
     spec?: K8sIoApiBatchV1JobSpec;
 }
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1K6LoadTest
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1K6LoadTest {
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1K6LoadTest
+     */
+    script?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1K6LoadTest
+     */
+    image?: string;
+}
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1KEDACoordination
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1KEDACoordination {
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1KEDACoordination
+     */
+    mode?: string;
+}
 /**
  * 
  * @export
@@ -1362,6 +1948,50 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1KayentaThre
      */
     marginal?: string;
 }
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1KnativeTrafficRouting
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1KnativeTrafficRouting {
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1KnativeTrafficRouting
+     */
+    service?: string;
+}
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1LaunchDarklyFeatureFlag
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1LaunchDarklyFeatureFlag {
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1LaunchDarklyFeatureFlag
+     */
+    projectKey?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1LaunchDarklyFeatureFlag
+     */
+    environmentKey?: string;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1SecretKeyRef}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1LaunchDarklyFeatureFlag
+     */
+    apiTokenSecretRef?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1SecretKeyRef;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1LaunchDarklyFeatureFlag
+     */
+    apiURL?: string;
+}
 /**
  * 
  * @export
@@ -1594,6 +2224,12 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1MetricProvi
      * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1MetricProvider
      */
     plugin?: { [key: string]: string; };
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AzureMonitorMetric}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1MetricProvider
+     */
+    azureMonitor?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AzureMonitorMetric;
 }
 /**
  * 
@@ -1683,66 +2319,152 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1MetricResul
 /**
  * 
  * @export
- * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1NewRelicMetric
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1MigrationStatus
  */
-export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1NewRelicMetric {
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1MigrationStatus {
+    /**
+     * 
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1MigrationStatus
+     */
+    stepIndex?: number;
     /**
      * 
      * @type {string}
-     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1NewRelicMetric
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1MigrationStatus
      */
-    profile?: string;
+    podTemplateHash?: string;
     /**
      * 
      * @type {string}
-     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1NewRelicMetric
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1MigrationStatus
      */
-    query?: string;
+    phase?: string;
+    /**
+     * 
+     * @type {K8sIoApimachineryPkgApisMetaV1Time}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1MigrationStatus
+     */
+    startedAt?: K8sIoApimachineryPkgApisMetaV1Time;
     /**
      * 
      * @type {string}
-     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1NewRelicMetric
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1MigrationStatus
      */
-    timeout?: string;
+    message?: string;
 }
 /**
  * 
  * @export
- * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1NginxTrafficRouting
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1MigrationStatusCheck
  */
-export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1NginxTrafficRouting {
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1MigrationStatusCheck {
     /**
      * 
      * @type {string}
-     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1NginxTrafficRouting
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1MigrationStatusCheck
      */
-    annotationPrefix?: string;
+    url?: string;
     /**
      * 
-     * @type {string}
-     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1NginxTrafficRouting
+     * @type {Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1WebMetricHeader>}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1MigrationStatusCheck
      */
-    stableIngress?: string;
+    headers?: Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1WebMetricHeader>;
     /**
      * 
-     * @type {{ [key: string]: string; }}
-     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1NginxTrafficRouting
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1SecretKeyRef}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1MigrationStatusCheck
      */
-    additionalIngressAnnotations?: { [key: string]: string; };
+    authorizationSecretRef?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1SecretKeyRef;
     /**
      * 
-     * @type {Array<string>}
-     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1NginxTrafficRouting
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1MigrationStatusCheck
      */
-    stableIngresses?: Array<string>;
+    jsonPath?: string;
     /**
      * 
-     * @type {{ [key: string]: string; }}
-     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1NginxTrafficRouting
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1MigrationStatusCheck
      */
-    canaryIngressAnnotations?: { [key: string]: string; };
-}
-/**
+    completedValue?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1MigrationStatusCheck
+     */
+    failedValue?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1MigrationStatusCheck
+     */
+    interval?: string;
+}
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1NewRelicMetric
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1NewRelicMetric {
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1NewRelicMetric
+     */
+    profile?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1NewRelicMetric
+     */
+    query?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1NewRelicMetric
+     */
+    timeout?: string;
+}
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1NginxTrafficRouting
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1NginxTrafficRouting {
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1NginxTrafficRouting
+     */
+    annotationPrefix?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1NginxTrafficRouting
+     */
+    stableIngress?: string;
+    /**
+     * 
+     * @type {{ [key: string]: string; }}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1NginxTrafficRouting
+     */
+    additionalIngressAnnotations?: { [key: string]: string; };
+    /**
+     * 
+     * @type {Array<string>}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1NginxTrafficRouting
+     */
+    stableIngresses?: Array<string>;
+    /**
+     * 
+     * @type {{ [key: string]: string; }}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1NginxTrafficRouting
+     */
+    canaryIngressAnnotations?: { [key: string]: string; };
+}
+/**
  * 
  * @export
  * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1OAuth2Config
@@ -1804,6 +2526,19 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ObjectRef {
      */
     scaleDown?: string;
 }
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1OpenFeatureFeatureFlag
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1OpenFeatureFeatureFlag {
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1FeatureFlagVariants}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1OpenFeatureFeatureFlag
+     */
+    variants?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1FeatureFlagVariants;
+}
 /**
  * 
  * @export
@@ -1879,6 +2614,56 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1PodTemplate
      * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1PodTemplateMetadata
      */
     annotations?: { [key: string]: string; };
+    /**
+     * 
+     * @type {Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1EphemeralMetadataEnvVar>}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1PodTemplateMetadata
+     */
+    env?: Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1EphemeralMetadataEnvVar>;
+}
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1PodTemplateOverlay
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1PodTemplateOverlay {
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1PodTemplateOverlay
+     */
+    patch?: string;
+}
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1PostPromotionSmokeTest
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1PostPromotionSmokeTest {
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1JobMetric}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1PostPromotionSmokeTest
+     */
+    job?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1JobMetric;
+    /**
+     * 
+     * @type {Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AnalysisTemplateRef>}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1PostPromotionSmokeTest
+     */
+    templates?: Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AnalysisTemplateRef>;
+    /**
+     * 
+     * @type {Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AnalysisRunArgument>}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1PostPromotionSmokeTest
+     */
+    args?: Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AnalysisRunArgument>;
+    /**
+     * 
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1PostPromotionSmokeTest
+     */
+    windowSeconds?: number;
 }
 /**
  * 
@@ -1893,6 +2678,31 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1PreferredDu
      */
     weight?: number;
 }
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1PreviewReplicaProfile
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1PreviewReplicaProfile {
+    /**
+     * Percent is the percentage of spec.replicas to run for the preview stack, rounded up. When a HorizontalPodAutoscaler targets the Rollout, the preview stack follows the autoscaled size.
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1PreviewReplicaProfile
+     */
+    percent?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1PreviewReplicaProfile
+     */
+    minReplicas?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1PreviewReplicaProfile
+     */
+    maxReplicas?: number;
+}
 /**
  * 
  * @export
@@ -1986,6 +2796,25 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ReplicaProg
      */
     value?: number;
 }
+/**
+ * ReplicaSetPodDisruptionBudget defines the PodDisruptionBudgets of the stable and canary ReplicaSets. Only one of MinAvailable and MaxUnavailable can be set. Defaults to a MinAvailable of 1.
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ReplicaSetPodDisruptionBudget
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ReplicaSetPodDisruptionBudget {
+    /**
+     * 
+     * @type {K8sIoApimachineryPkgUtilIntstrIntOrString}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ReplicaSetPodDisruptionBudget
+     */
+    minAvailable?: K8sIoApimachineryPkgUtilIntstrIntOrString;
+    /**
+     * 
+     * @type {K8sIoApimachineryPkgUtilIntstrIntOrString}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ReplicaSetPodDisruptionBudget
+     */
+    maxUnavailable?: K8sIoApimachineryPkgUtilIntstrIntOrString;
+}
 /**
  * 
  * @export
@@ -2197,6 +3026,18 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutExpe
      * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutExperimentStep
      */
     scaleDownDelaySeconds?: number;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ExperimentWinnerSelection}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutExperimentStep
+     */
+    winnerSelection?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ExperimentWinnerSelection;
+    /**
+     * 
+     * @type {boolean}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutExperimentStep
+     */
+    promoteWinner?: boolean;
 }
 /**
  * 
@@ -2283,6 +3124,191 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutExpe
      * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutExperimentTemplate
      */
     service?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1TemplateService;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutExperimentTemplate
+     */
+    patch?: string;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1SetHeaderRoute}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutExperimentTemplate
+     */
+    headerRoute?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1SetHeaderRoute;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1TemplateAutoscaling}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutExperimentTemplate
+     */
+    autoscaling?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1TemplateAutoscaling;
+}
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutGateStep
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutGateStep {
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutGateStep
+     */
+    url?: string;
+    /**
+     * 
+     * @type {Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1WebMetricHeader>}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutGateStep
+     */
+    headers?: Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1WebMetricHeader>;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1SecretKeyRef}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutGateStep
+     */
+    authorizationSecretRef?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1SecretKeyRef;
+    /**
+     * 
+     * @type {{ [key: string]: string; }}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutGateStep
+     */
+    params?: { [key: string]: string; };
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutGateStep
+     */
+    timeout?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutGateStep
+     */
+    interval?: string;
+    /**
+     * 
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutGateStep
+     */
+    retries?: number;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutGateStep
+     */
+    failurePolicy?: string;
+}
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutGuardrail
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutGuardrail {
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutAnalysis}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutGuardrail
+     */
+    rolloutAnalysis?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutAnalysis;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutGuardrail
+     */
+    action?: string;
+}
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutHealth
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutHealth {
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutHealth
+     */
+    status?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutHealth
+     */
+    reason?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutHealth
+     */
+    currentStep?: string;
+    /**
+     * 
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutHealth
+     */
+    percentComplete?: number;
+}
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutLoadTestStep
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutLoadTestStep {
+    /**
+     * 
+     * @type {K8sIoApiBatchV1JobSpec}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutLoadTestStep
+     */
+    jobSpec?: K8sIoApiBatchV1JobSpec;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1K6LoadTest}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutLoadTestStep
+     */
+    k6?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1K6LoadTest;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1VegetaLoadTest}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutLoadTestStep
+     */
+    vegeta?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1VegetaLoadTest;
+    /**
+     * 
+     * @type {boolean}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutLoadTestStep
+     */
+    wait?: boolean;
+}
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutMigrationStep
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutMigrationStep {
+    /**
+     * 
+     * @type {K8sIoApiBatchV1JobSpec}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutMigrationStep
+     */
+    jobSpec?: K8sIoApiBatchV1JobSpec;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1MigrationStatusCheck}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutMigrationStep
+     */
+    statusCheck?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1MigrationStatusCheck;
+    /**
+     * 
+     * @type {K8sIoApiBatchV1JobSpec}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutMigrationStep
+     */
+    rollbackJobSpec?: K8sIoApiBatchV1JobSpec;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutMigrationStep
+     */
+    timeout?: string;
 }
 /**
  * 
@@ -2297,6 +3323,111 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutPaus
      */
     duration?: K8sIoApimachineryPkgUtilIntstrIntOrString;
 }
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutPreProvisionStep
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutPreProvisionStep {
+    /**
+     * 
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutPreProvisionStep
+     */
+    weight?: number;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutPreProvisionStep
+     */
+    priorityClassName?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutPreProvisionStep
+     */
+    timeout?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutPreProvisionStep
+     */
+    image?: string;
+}
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutRestartStatus
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutRestartStatus {
+    /**
+     * 
+     * @type {K8sIoApimachineryPkgApisMetaV1Time}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutRestartStatus
+     */
+    restartAt?: K8sIoApimachineryPkgApisMetaV1Time;
+    /**
+     * 
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutRestartStatus
+     */
+    completedBatches?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutRestartStatus
+     */
+    restartedReplicas?: number;
+    /**
+     * 
+     * @type {K8sIoApimachineryPkgApisMetaV1Time}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutRestartStatus
+     */
+    batchCompletedAt?: K8sIoApimachineryPkgApisMetaV1Time;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutAnalysisRunStatus}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutRestartStatus
+     */
+    currentAnalysisRunStatus?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutAnalysisRunStatus;
+    /**
+     * 
+     * @type {boolean}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutRestartStatus
+     */
+    halted?: boolean;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutRestartStatus
+     */
+    message?: string;
+}
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutRestartStrategy
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutRestartStrategy {
+    /**
+     * 
+     * @type {K8sIoApimachineryPkgUtilIntstrIntOrString}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutRestartStrategy
+     */
+    batchSize?: K8sIoApimachineryPkgUtilIntstrIntOrString;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutRestartStrategy
+     */
+    pauseBetweenBatches?: string;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutAnalysis}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutRestartStrategy
+     */
+    analysis?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutAnalysis;
+}
 /**
  * 
  * @export
@@ -2375,12 +3506,24 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutSpec
      * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutSpec
      */
     restartAt?: K8sIoApimachineryPkgApisMetaV1Time;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutRestartStrategy}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutSpec
+     */
+    restartStrategy?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutRestartStrategy;
     /**
      * 
      * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AnalysisRunStrategy}
      * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutSpec
      */
     analysis?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AnalysisRunStrategy;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ImageVerification}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutSpec
+     */
+    imageVerification?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ImageVerification;
 }
 /**
  * 
@@ -2525,25 +3668,49 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutStat
      * @type {string}
      * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutStatus
      */
-    message?: string;
+    message?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutStatus
+     */
+    workloadObservedGeneration?: string;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ALBStatus}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutStatus
+     */
+    alb?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ALBStatus;
+    /**
+     * 
+     * @type {Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ALBStatus>}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutStatus
+     */
+    albs?: Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ALBStatus>;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutRestartStatus}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutStatus
+     */
+    restartStatus?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutRestartStatus;
     /**
      * 
-     * @type {string}
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutHealth}
      * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutStatus
      */
-    workloadObservedGeneration?: string;
+    health?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutHealth;
     /**
      * 
-     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ALBStatus}
+     * @type {string}
      * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutStatus
      */
-    alb?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ALBStatus;
+    syncAction?: string;
     /**
      * 
-     * @type {Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ALBStatus>}
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutUserAction}
      * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutStatus
      */
-    albs?: Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ALBStatus>;
+    lastUserAction?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutUserAction;
 }
 /**
  * 
@@ -2636,6 +3803,43 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutTraf
      * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutTrafficRouting
      */
     maxTrafficWeight?: number;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1KnativeTrafficRouting}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutTrafficRouting
+     */
+    knative?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1KnativeTrafficRouting;
+}
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutUserAction
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutUserAction {
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutUserAction
+     */
+    action?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutUserAction
+     */
+    user?: string;
+    /**
+     * 
+     * @type {Array<string>}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutUserAction
+     */
+    groups?: Array<string>;
+    /**
+     * 
+     * @type {K8sIoApimachineryPkgApisMetaV1Time}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1RolloutUserAction
+     */
+    time?: K8sIoApimachineryPkgApisMetaV1Time;
 }
 /**
  * 
@@ -2718,6 +3922,44 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1SMITrafficR
      */
     trafficSplitName?: string;
 }
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ScaleDownDelayOverride
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ScaleDownDelayOverride {
+    /**
+     * Revision is the number of revisions the old ReplicaSet is behind the current revision, according to their rollout.argoproj.io/revision annotations. 1 is the revision immediately preceding the current one.
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ScaleDownDelayOverride
+     */
+    revision?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ScaleDownDelayOverride
+     */
+    delaySeconds?: number;
+}
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ScaleDownVerification
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ScaleDownVerification {
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1DrainProbe}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ScaleDownVerification
+     */
+    drainProbe?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1DrainProbe;
+    /**
+     * 
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ScaleDownVerification
+     */
+    timeoutSeconds?: number;
+}
 /**
  * 
  * @export
@@ -2818,6 +4060,37 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1SetCanarySc
      */
     matchTrafficWeight?: boolean;
 }
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1SetFeatureFlag
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1SetFeatureFlag {
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1SetFeatureFlag
+     */
+    flag?: string;
+    /**
+     * 
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1SetFeatureFlag
+     */
+    percentage?: number;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1LaunchDarklyFeatureFlag}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1SetFeatureFlag
+     */
+    launchDarkly?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1LaunchDarklyFeatureFlag;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1OpenFeatureFeatureFlag}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1SetFeatureFlag
+     */
+    openFeature?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1OpenFeatureFeatureFlag;
+}
 /**
  * 
  * @export
@@ -2912,6 +4185,31 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1SkyWalkingM
      */
     interval?: string;
 }
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1StepPluginProgress
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1StepPluginProgress {
+    /**
+     * 
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1StepPluginProgress
+     */
+    percent?: number;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1StepPluginProgress
+     */
+    stage?: string;
+    /**
+     * 
+     * @type {K8sIoApimachineryPkgApisMetaV1Time}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1StepPluginProgress
+     */
+    updatedAt?: K8sIoApimachineryPkgApisMetaV1Time;
+}
 /**
  * 
  * @export
@@ -2990,6 +4288,37 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1StepPluginS
      * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1StepPluginStatus
      */
     status?: string;
+    /**
+     * 
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1StepPluginStatus
+     */
+    stateVersion?: number;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1StepPluginProgress}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1StepPluginStatus
+     */
+    progress?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1StepPluginProgress;
+}
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1StepProgressDeadline
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1StepProgressDeadline {
+    /**
+     * Seconds is the maximum time in seconds for the step to make progress before it is considered to have failed. Unlike the rollout's progress deadline, it also applies to analysis and experiment steps.
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1StepProgressDeadline
+     */
+    seconds?: number;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1StepProgressDeadline
+     */
+    action?: string;
 }
 /**
  * 
@@ -3092,6 +4421,37 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1TTLStrategy
      */
     secondsAfterSuccess?: number;
 }
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1TemplateAutoscaling
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1TemplateAutoscaling {
+    /**
+     * 
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1TemplateAutoscaling
+     */
+    minReplicas?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1TemplateAutoscaling
+     */
+    maxReplicas?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1TemplateAutoscaling
+     */
+    targetCPUUtilizationPercentage?: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1TemplateAutoscaling
+     */
+    targetMemoryUtilizationPercentage?: number;
+}
 /**
  * 
  * @export
@@ -3118,6 +4478,37 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1TraefikTraf
      */
     weightedTraefikServiceName?: string;
 }
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1TrafficWeightRecord
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1TrafficWeightRecord {
+    /**
+     * 
+     * @type {K8sIoApimachineryPkgApisMetaV1Time}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1TrafficWeightRecord
+     */
+    time?: K8sIoApimachineryPkgApisMetaV1Time;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1TrafficWeightRecord
+     */
+    router?: string;
+    /**
+     * 
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1TrafficWeightRecord
+     */
+    desiredWeight?: number;
+    /**
+     * 
+     * @type {boolean}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1TrafficWeightRecord
+     */
+    verified?: boolean;
+}
 /**
  * 
  * @export
@@ -3168,6 +4559,43 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1ValueFrom {
      */
     fieldRef?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1FieldRef;
 }
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1VegetaLoadTest
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1VegetaLoadTest {
+    /**
+     * 
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1VegetaLoadTest
+     */
+    rate?: number;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1VegetaLoadTest
+     */
+    duration?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1VegetaLoadTest
+     */
+    method?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1VegetaLoadTest
+     */
+    path?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1VegetaLoadTest
+     */
+    image?: string;
+}
 /**
  * 
  * @export
@@ -3292,6 +4720,25 @@ export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1WeightDesti
      */
     podTemplateHash?: string;
 }
+/**
+ * 
+ * @export
+ * @interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1WeightOverride
+ */
+export interface GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1WeightOverride {
+    /**
+     * 
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1WeightOverride
+     */
+    weight?: number;
+    /**
+     * StepIndex is the index of the step during which the weight was overridden. The override is removed once the rollout moves to another step, is aborted or is promoted.
+     * @type {number}
+     * @memberof GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1WeightOverride
+     */
+    stepIndex?: number;
+}
 /**
  * 
  * @export
@@ -7633,35 +9080,85 @@ export interface RolloutAnalysisRunInfo {
     nonJobInfo?: Array<RolloutNonJobInfo>;
     /**
      * 
-     * @type {Array<RolloutMetrics>}
-     * @memberof RolloutAnalysisRunInfo
+     * @type {Array<RolloutMetrics>}
+     * @memberof RolloutAnalysisRunInfo
+     */
+    metrics?: Array<RolloutMetrics>;
+    /**
+     * 
+     * @type {RolloutAnalysisRunSpecAndStatus}
+     * @memberof RolloutAnalysisRunInfo
+     */
+    specAndStatus?: RolloutAnalysisRunSpecAndStatus;
+}
+/**
+ * 
+ * @export
+ * @interface RolloutAnalysisRunSpecAndStatus
+ */
+export interface RolloutAnalysisRunSpecAndStatus {
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AnalysisRunSpec}
+     * @memberof RolloutAnalysisRunSpecAndStatus
+     */
+    spec?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AnalysisRunSpec;
+    /**
+     * 
+     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AnalysisRunStatus}
+     * @memberof RolloutAnalysisRunSpecAndStatus
+     */
+    status?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AnalysisRunStatus;
+}
+/**
+ * 
+ * @export
+ * @interface RolloutAuditEvent
+ */
+export interface RolloutAuditEvent {
+    /**
+     * 
+     * @type {string}
+     * @memberof RolloutAuditEvent
+     */
+    action?: string;
+    /**
+     * user is the user who took the action. It is empty when the users of the dashboard do not log in.
+     * @type {string}
+     * @memberof RolloutAuditEvent
+     */
+    user?: string;
+    /**
+     * 
+     * @type {K8sIoApimachineryPkgApisMetaV1Time}
+     * @memberof RolloutAuditEvent
+     */
+    timestamp?: K8sIoApimachineryPkgApisMetaV1Time;
+    /**
+     * 
+     * @type {string}
+     * @memberof RolloutAuditEvent
      */
-    metrics?: Array<RolloutMetrics>;
+    priorState?: string;
     /**
      * 
-     * @type {RolloutAnalysisRunSpecAndStatus}
-     * @memberof RolloutAnalysisRunInfo
+     * @type {string}
+     * @memberof RolloutAuditEvent
      */
-    specAndStatus?: RolloutAnalysisRunSpecAndStatus;
+    message?: string;
 }
 /**
  * 
  * @export
- * @interface RolloutAnalysisRunSpecAndStatus
+ * @interface RolloutAuditEventList
  */
-export interface RolloutAnalysisRunSpecAndStatus {
-    /**
-     * 
-     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AnalysisRunSpec}
-     * @memberof RolloutAnalysisRunSpecAndStatus
-     */
-    spec?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AnalysisRunSpec;
+export interface RolloutAuditEventList {
     /**
      * 
-     * @type {GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AnalysisRunStatus}
-     * @memberof RolloutAnalysisRunSpecAndStatus
+     * @type {Array<RolloutAuditEvent>}
+     * @memberof RolloutAuditEventList
      */
-    status?: GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1AnalysisRunStatus;
+    events?: Array<RolloutAuditEvent>;
 }
 /**
  * 
@@ -7823,6 +9320,24 @@ export interface RolloutNamespaceInfo {
      * @memberof RolloutNamespaceInfo
      */
     availableNamespaces?: Array<string>;
+    /**
+     * 
+     * @type {boolean}
+     * @memberof RolloutNamespaceInfo
+     */
+    readOnly?: boolean;
+    /**
+     * 
+     * @type {boolean}
+     * @memberof RolloutNamespaceInfo
+     */
+    namespaceRestricted?: boolean;
+    /**
+     * 
+     * @type {string}
+     * @memberof RolloutNamespaceInfo
+     */
+    podLogURLTemplate?: string;
 }
 /**
  * 
@@ -8196,6 +9711,18 @@ export interface RolloutRolloutInfo {
      * @memberof RolloutRolloutInfo
      */
     initContainers?: Array<RolloutContainerInfo>;
+    /**
+     * 
+     * @type {Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1TrafficWeightRecord>}
+     * @memberof RolloutRolloutInfo
+     */
+    weightHistory?: Array<GithubComArgoprojArgoRolloutsPkgApisRolloutsV1alpha1TrafficWeightRecord>;
+    /**
+     * 
+     * @type {string}
+     * @memberof RolloutRolloutInfo
+     */
+    stepProgress?: string;
 }
 /**
  * 
@@ -8209,6 +9736,12 @@ export interface RolloutRolloutInfoList {
      * @memberof RolloutRolloutInfoList
      */
     rollouts?: Array<RolloutRolloutInfo>;
+    /**
+     * continue is the token to list the next rollouts with. It is empty once all the rollouts are listed.
+     * @type {string}
+     * @memberof RolloutRolloutInfoList
+     */
+    continue?: string;
 }
 /**
  * 
@@ -8450,10 +9983,49 @@ export const RolloutServiceApiFetchParamCreator = function (configuration?: Conf
         /**
          * 
          * @param {string} namespace 
+         * @param {string} name 
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        rolloutServiceListRolloutAuditEvents(namespace: string, name: string, options: any = {}): FetchArgs {
+            // verify required parameter 'namespace' is not null or undefined
+            if (namespace === null || namespace === undefined) {
+                throw new RequiredError('namespace','Required parameter namespace was null or undefined when calling rolloutServiceListRolloutAuditEvents.');
+            }
+            // verify required parameter 'name' is not null or undefined
+            if (name === null || name === undefined) {
+                throw new RequiredError('name','Required parameter name was null or undefined when calling rolloutServiceListRolloutAuditEvents.');
+            }
+            const localVarPath = `/api/v1/rollouts/{namespace}/{name}/audit`
+                .replace(`{${"namespace"}}`, encodeURIComponent(String(namespace)))
+                .replace(`{${"name"}}`, encodeURIComponent(String(name)));
+            const localVarUrlObj = url.parse(localVarPath, true);
+            const localVarRequestOptions = Object.assign({ method: 'GET' }, options);
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            localVarUrlObj.query = Object.assign({}, localVarUrlObj.query, localVarQueryParameter, options.query);
+            // fix override query string Detail: https://stackoverflow.com/a/7517673/1077943
+            localVarUrlObj.search = null;
+            localVarRequestOptions.headers = Object.assign({}, localVarHeaderParameter, options.headers);
+
+            return {
+                url: url.format(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
+        /**
+         * 
+         * @param {string} namespace namespace of the rollouts. The rollouts of all the namespaces are listed when empty.
+         * @param {string} [labelSelector] labelSelector only selects the rollouts whose labels match the selector.
+         * @param {string} [phase] phase only selects the rollouts in the phase, e.g. Healthy, Progressing, Paused or Degraded.
+         * @param {string} [strategy] strategy only selects the rollouts with the strategy, i.e. Canary or BlueGreen.
+         * @param {string} [limit] limit is the maximum number of rollouts of a list. All the rollouts are listed when 0.
+         * @param {string} [_continue] continue is the token of the previous list to list the next rollouts from.
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        rolloutServiceListRolloutInfos(namespace: string, options: any = {}): FetchArgs {
+        rolloutServiceListRolloutInfos(namespace: string, labelSelector?: string, phase?: string, strategy?: string, limit?: string, _continue?: string, options: any = {}): FetchArgs {
             // verify required parameter 'namespace' is not null or undefined
             if (namespace === null || namespace === undefined) {
                 throw new RequiredError('namespace','Required parameter namespace was null or undefined when calling rolloutServiceListRolloutInfos.');
@@ -8465,6 +10037,78 @@ export const RolloutServiceApiFetchParamCreator = function (configuration?: Conf
             const localVarHeaderParameter = {} as any;
             const localVarQueryParameter = {} as any;
 
+            if (labelSelector !== undefined) {
+                localVarQueryParameter['labelSelector'] = labelSelector;
+            }
+
+            if (phase !== undefined) {
+                localVarQueryParameter['phase'] = phase;
+            }
+
+            if (strategy !== undefined) {
+                localVarQueryParameter['strategy'] = strategy;
+            }
+
+            if (limit !== undefined) {
+                localVarQueryParameter['limit'] = limit;
+            }
+
+            if (_continue !== undefined) {
+                localVarQueryParameter['continue'] = _continue;
+            }
+
+            localVarUrlObj.query = Object.assign({}, localVarUrlObj.query, localVarQueryParameter, options.query);
+            // fix override query string Detail: https://stackoverflow.com/a/7517673/1077943
+            localVarUrlObj.search = null;
+            localVarRequestOptions.headers = Object.assign({}, localVarHeaderParameter, options.headers);
+
+            return {
+                url: url.format(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
+        /**
+         * 
+         * @param {string} [namespace] namespace of the rollouts. The rollouts of all the namespaces are listed when empty.
+         * @param {string} [labelSelector] labelSelector only selects the rollouts whose labels match the selector.
+         * @param {string} [phase] phase only selects the rollouts in the phase, e.g. Healthy, Progressing, Paused or Degraded.
+         * @param {string} [strategy] strategy only selects the rollouts with the strategy, i.e. Canary or BlueGreen.
+         * @param {string} [limit] limit is the maximum number of rollouts of a list. All the rollouts are listed when 0.
+         * @param {string} [_continue] continue is the token of the previous list to list the next rollouts from.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        rolloutServiceListRolloutInfos2(namespace?: string, labelSelector?: string, phase?: string, strategy?: string, limit?: string, _continue?: string, options: any = {}): FetchArgs {
+            const localVarPath = `/api/v1/rollouts/info`;
+            const localVarUrlObj = url.parse(localVarPath, true);
+            const localVarRequestOptions = Object.assign({ method: 'GET' }, options);
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            if (namespace !== undefined) {
+                localVarQueryParameter['namespace'] = namespace;
+            }
+
+            if (labelSelector !== undefined) {
+                localVarQueryParameter['labelSelector'] = labelSelector;
+            }
+
+            if (phase !== undefined) {
+                localVarQueryParameter['phase'] = phase;
+            }
+
+            if (strategy !== undefined) {
+                localVarQueryParameter['strategy'] = strategy;
+            }
+
+            if (limit !== undefined) {
+                localVarQueryParameter['limit'] = limit;
+            }
+
+            if (_continue !== undefined) {
+                localVarQueryParameter['continue'] = _continue;
+            }
+
             localVarUrlObj.query = Object.assign({}, localVarUrlObj.query, localVarQueryParameter, options.query);
             // fix override query string Detail: https://stackoverflow.com/a/7517673/1077943
             localVarUrlObj.search = null;
@@ -8772,11 +10416,16 @@ export const RolloutServiceApiFetchParamCreator = function (configuration?: Conf
         },
         /**
          * 
-         * @param {string} namespace 
+         * @param {string} namespace namespace of the rollouts. The rollouts of all the namespaces are listed when empty.
+         * @param {string} [labelSelector] labelSelector only selects the rollouts whose labels match the selector.
+         * @param {string} [phase] phase only selects the rollouts in the phase, e.g. Healthy, Progressing, Paused or Degraded.
+         * @param {string} [strategy] strategy only selects the rollouts with the strategy, i.e. Canary or BlueGreen.
+         * @param {string} [limit] limit is the maximum number of rollouts of a list. All the rollouts are listed when 0.
+         * @param {string} [_continue] continue is the token of the previous list to list the next rollouts from.
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        rolloutServiceWatchRolloutInfos(namespace: string, options: any = {}): FetchArgs {
+        rolloutServiceWatchRolloutInfos(namespace: string, labelSelector?: string, phase?: string, strategy?: string, limit?: string, _continue?: string, options: any = {}): FetchArgs {
             // verify required parameter 'namespace' is not null or undefined
             if (namespace === null || namespace === undefined) {
                 throw new RequiredError('namespace','Required parameter namespace was null or undefined when calling rolloutServiceWatchRolloutInfos.');
@@ -8788,6 +10437,78 @@ export const RolloutServiceApiFetchParamCreator = function (configuration?: Conf
             const localVarHeaderParameter = {} as any;
             const localVarQueryParameter = {} as any;
 
+            if (labelSelector !== undefined) {
+                localVarQueryParameter['labelSelector'] = labelSelector;
+            }
+
+            if (phase !== undefined) {
+                localVarQueryParameter['phase'] = phase;
+            }
+
+            if (strategy !== undefined) {
+                localVarQueryParameter['strategy'] = strategy;
+            }
+
+            if (limit !== undefined) {
+                localVarQueryParameter['limit'] = limit;
+            }
+
+            if (_continue !== undefined) {
+                localVarQueryParameter['continue'] = _continue;
+            }
+
+            localVarUrlObj.query = Object.assign({}, localVarUrlObj.query, localVarQueryParameter, options.query);
+            // fix override query string Detail: https://stackoverflow.com/a/7517673/1077943
+            localVarUrlObj.search = null;
+            localVarRequestOptions.headers = Object.assign({}, localVarHeaderParameter, options.headers);
+
+            return {
+                url: url.format(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
+        /**
+         * 
+         * @param {string} [namespace] namespace of the rollouts. The rollouts of all the namespaces are listed when empty.
+         * @param {string} [labelSelector] labelSelector only selects the rollouts whose labels match the selector.
+         * @param {string} [phase] phase only selects the rollouts in the phase, e.g. Healthy, Progressing, Paused or Degraded.
+         * @param {string} [strategy] strategy only selects the rollouts with the strategy, i.e. Canary or BlueGreen.
+         * @param {string} [limit] limit is the maximum number of rollouts of a list. All the rollouts are listed when 0.
+         * @param {string} [_continue] continue is the token of the previous list to list the next rollouts from.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        rolloutServiceWatchRolloutInfos2(namespace?: string, labelSelector?: string, phase?: string, strategy?: string, limit?: string, _continue?: string, options: any = {}): FetchArgs {
+            const localVarPath = `/api/v1/rollouts/info/watch`;
+            const localVarUrlObj = url.parse(localVarPath, true);
+            const localVarRequestOptions = Object.assign({ method: 'GET' }, options);
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            if (namespace !== undefined) {
+                localVarQueryParameter['namespace'] = namespace;
+            }
+
+            if (labelSelector !== undefined) {
+                localVarQueryParameter['labelSelector'] = labelSelector;
+            }
+
+            if (phase !== undefined) {
+                localVarQueryParameter['phase'] = phase;
+            }
+
+            if (strategy !== undefined) {
+                localVarQueryParameter['strategy'] = strategy;
+            }
+
+            if (limit !== undefined) {
+                localVarQueryParameter['limit'] = limit;
+            }
+
+            if (_continue !== undefined) {
+                localVarQueryParameter['continue'] = _continue;
+            }
+
             localVarUrlObj.query = Object.assign({}, localVarUrlObj.query, localVarQueryParameter, options.query);
             // fix override query string Detail: https://stackoverflow.com/a/7517673/1077943
             localVarUrlObj.search = null;
@@ -8866,11 +10587,58 @@ export const RolloutServiceApiFp = function(configuration?: Configuration) {
         /**
          * 
          * @param {string} namespace 
+         * @param {string} name 
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        rolloutServiceListRolloutAuditEvents(namespace: string, name: string, options?: any): (fetch?: FetchAPI, basePath?: string) => Promise<RolloutAuditEventList> {
+            const localVarFetchArgs = RolloutServiceApiFetchParamCreator(configuration).rolloutServiceListRolloutAuditEvents(namespace, name, options);
+            return (fetch: FetchAPI = isomorphicFetch, basePath: string = BASE_PATH) => {
+                return fetch(basePath + localVarFetchArgs.url, localVarFetchArgs.options).then((response) => {
+                    if (response.status >= 200 && response.status < 300) {
+                        return response.json();
+                    } else {
+                        throw response;
+                    }
+                });
+            };
+        },
+        /**
+         * 
+         * @param {string} namespace namespace of the rollouts. The rollouts of all the namespaces are listed when empty.
+         * @param {string} [labelSelector] labelSelector only selects the rollouts whose labels match the selector.
+         * @param {string} [phase] phase only selects the rollouts in the phase, e.g. Healthy, Progressing, Paused or Degraded.
+         * @param {string} [strategy] strategy only selects the rollouts with the strategy, i.e. Canary or BlueGreen.
+         * @param {string} [limit] limit is the maximum number of rollouts of a list. All the rollouts are listed when 0.
+         * @param {string} [_continue] continue is the token of the previous list to list the next rollouts from.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        rolloutServiceListRolloutInfos(namespace: string, labelSelector?: string, phase?: string, strategy?: string, limit?: string, _continue?: string, options?: any): (fetch?: FetchAPI, basePath?: string) => Promise<RolloutRolloutInfoList> {
+            const localVarFetchArgs = RolloutServiceApiFetchParamCreator(configuration).rolloutServiceListRolloutInfos(namespace, labelSelector, phase, strategy, limit, _continue, options);
+            return (fetch: FetchAPI = isomorphicFetch, basePath: string = BASE_PATH) => {
+                return fetch(basePath + localVarFetchArgs.url, localVarFetchArgs.options).then((response) => {
+                    if (response.status >= 200 && response.status < 300) {
+                        return response.json();
+                    } else {
+                        throw response;
+                    }
+                });
+            };
+        },
+        /**
+         * 
+         * @param {string} [namespace] namespace of the rollouts. The rollouts of all the namespaces are listed when empty.
+         * @param {string} [labelSelector] labelSelector only selects the rollouts whose labels match the selector.
+         * @param {string} [phase] phase only selects the rollouts in the phase, e.g. Healthy, Progressing, Paused or Degraded.
+         * @param {string} [strategy] strategy only selects the rollouts with the strategy, i.e. Canary or BlueGreen.
+         * @param {string} [limit] limit is the maximum number of rollouts of a list. All the rollouts are listed when 0.
+         * @param {string} [_continue] continue is the token of the previous list to list the next rollouts from.
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        rolloutServiceListRolloutInfos(namespace: string, options?: any): (fetch?: FetchAPI, basePath?: string) => Promise<RolloutRolloutInfoList> {
-            const localVarFetchArgs = RolloutServiceApiFetchParamCreator(configuration).rolloutServiceListRolloutInfos(namespace, options);
+        rolloutServiceListRolloutInfos2(namespace?: string, labelSelector?: string, phase?: string, strategy?: string, limit?: string, _continue?: string, options?: any): (fetch?: FetchAPI, basePath?: string) => Promise<RolloutRolloutInfoList> {
+            const localVarFetchArgs = RolloutServiceApiFetchParamCreator(configuration).rolloutServiceListRolloutInfos2(namespace, labelSelector, phase, strategy, limit, _continue, options);
             return (fetch: FetchAPI = isomorphicFetch, basePath: string = BASE_PATH) => {
                 return fetch(basePath + localVarFetchArgs.url, localVarFetchArgs.options).then((response) => {
                     if (response.status >= 200 && response.status < 300) {
@@ -9023,12 +10791,40 @@ export const RolloutServiceApiFp = function(configuration?: Configuration) {
         },
         /**
          * 
-         * @param {string} namespace 
+         * @param {string} namespace namespace of the rollouts. The rollouts of all the namespaces are listed when empty.
+         * @param {string} [labelSelector] labelSelector only selects the rollouts whose labels match the selector.
+         * @param {string} [phase] phase only selects the rollouts in the phase, e.g. Healthy, Progressing, Paused or Degraded.
+         * @param {string} [strategy] strategy only selects the rollouts with the strategy, i.e. Canary or BlueGreen.
+         * @param {string} [limit] limit is the maximum number of rollouts of a list. All the rollouts are listed when 0.
+         * @param {string} [_continue] continue is the token of the previous list to list the next rollouts from.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        rolloutServiceWatchRolloutInfos(namespace: string, labelSelector?: string, phase?: string, strategy?: string, limit?: string, _continue?: string, options?: any): (fetch?: FetchAPI, basePath?: string) => Promise<StreamResultOfRolloutRolloutWatchEvent> {
+            const localVarFetchArgs = RolloutServiceApiFetchParamCreator(configuration).rolloutServiceWatchRolloutInfos(namespace, labelSelector, phase, strategy, limit, _continue, options);
+            return (fetch: FetchAPI = isomorphicFetch, basePath: string = BASE_PATH) => {
+                return fetch(basePath + localVarFetchArgs.url, localVarFetchArgs.options).then((response) => {
+                    if (response.status >= 200 && response.status < 300) {
+                        return response.json();
+                    } else {
+                        throw response;
+                    }
+                });
+            };
+        },
+        /**
+         * 
+         * @param {string} [namespace] namespace of the rollouts. The rollouts of all the namespaces are listed when empty.
+         * @param {string} [labelSelector] labelSelector only selects the rollouts whose labels match the selector.
+         * @param {string} [phase] phase only selects the rollouts in the phase, e.g. Healthy, Progressing, Paused or Degraded.
+         * @param {string} [strategy] strategy only selects the rollouts with the strategy, i.e. Canary or BlueGreen.
+         * @param {string} [limit] limit is the maximum number of rollouts of a list. All the rollouts are listed when 0.
+         * @param {string} [_continue] continue is the token of the previous list to list the next rollouts from.
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        rolloutServiceWatchRolloutInfos(namespace: string, options?: any): (fetch?: FetchAPI, basePath?: string) => Promise<StreamResultOfRolloutRolloutWatchEvent> {
-            const localVarFetchArgs = RolloutServiceApiFetchParamCreator(configuration).rolloutServiceWatchRolloutInfos(namespace, options);
+        rolloutServiceWatchRolloutInfos2(namespace?: string, labelSelector?: string, phase?: string, strategy?: string, limit?: string, _continue?: string, options?: any): (fetch?: FetchAPI, basePath?: string) => Promise<StreamResultOfRolloutRolloutWatchEvent> {
+            const localVarFetchArgs = RolloutServiceApiFetchParamCreator(configuration).rolloutServiceWatchRolloutInfos2(namespace, labelSelector, phase, strategy, limit, _continue, options);
             return (fetch: FetchAPI = isomorphicFetch, basePath: string = BASE_PATH) => {
                 return fetch(basePath + localVarFetchArgs.url, localVarFetchArgs.options).then((response) => {
                     if (response.status >= 200 && response.status < 300) {
@@ -9080,11 +10876,40 @@ export const RolloutServiceApiFactory = function (configuration?: Configuration,
         /**
          * 
          * @param {string} namespace 
+         * @param {string} name 
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        rolloutServiceListRolloutAuditEvents(namespace: string, name: string, options?: any) {
+            return RolloutServiceApiFp(configuration).rolloutServiceListRolloutAuditEvents(namespace, name, options)(fetch, basePath);
+        },
+        /**
+         * 
+         * @param {string} namespace namespace of the rollouts. The rollouts of all the namespaces are listed when empty.
+         * @param {string} [labelSelector] labelSelector only selects the rollouts whose labels match the selector.
+         * @param {string} [phase] phase only selects the rollouts in the phase, e.g. Healthy, Progressing, Paused or Degraded.
+         * @param {string} [strategy] strategy only selects the rollouts with the strategy, i.e. Canary or BlueGreen.
+         * @param {string} [limit] limit is the maximum number of rollouts of a list. All the rollouts are listed when 0.
+         * @param {string} [_continue] continue is the token of the previous list to list the next rollouts from.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        rolloutServiceListRolloutInfos(namespace: string, labelSelector?: string, phase?: string, strategy?: string, limit?: string, _continue?: string, options?: any) {
+            return RolloutServiceApiFp(configuration).rolloutServiceListRolloutInfos(namespace, labelSelector, phase, strategy, limit, _continue, options)(fetch, basePath);
+        },
+        /**
+         * 
+         * @param {string} [namespace] namespace of the rollouts. The rollouts of all the namespaces are listed when empty.
+         * @param {string} [labelSelector] labelSelector only selects the rollouts whose labels match the selector.
+         * @param {string} [phase] phase only selects the rollouts in the phase, e.g. Healthy, Progressing, Paused or Degraded.
+         * @param {string} [strategy] strategy only selects the rollouts with the strategy, i.e. Canary or BlueGreen.
+         * @param {string} [limit] limit is the maximum number of rollouts of a list. All the rollouts are listed when 0.
+         * @param {string} [_continue] continue is the token of the previous list to list the next rollouts from.
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        rolloutServiceListRolloutInfos(namespace: string, options?: any) {
-            return RolloutServiceApiFp(configuration).rolloutServiceListRolloutInfos(namespace, options)(fetch, basePath);
+        rolloutServiceListRolloutInfos2(namespace?: string, labelSelector?: string, phase?: string, strategy?: string, limit?: string, _continue?: string, options?: any) {
+            return RolloutServiceApiFp(configuration).rolloutServiceListRolloutInfos2(namespace, labelSelector, phase, strategy, limit, _continue, options)(fetch, basePath);
         },
         /**
          * 
@@ -9165,12 +10990,31 @@ export const RolloutServiceApiFactory = function (configuration?: Configuration,
         },
         /**
          * 
-         * @param {string} namespace 
+         * @param {string} namespace namespace of the rollouts. The rollouts of all the namespaces are listed when empty.
+         * @param {string} [labelSelector] labelSelector only selects the rollouts whose labels match the selector.
+         * @param {string} [phase] phase only selects the rollouts in the phase, e.g. Healthy, Progressing, Paused or Degraded.
+         * @param {string} [strategy] strategy only selects the rollouts with the strategy, i.e. Canary or BlueGreen.
+         * @param {string} [limit] limit is the maximum number of rollouts of a list. All the rollouts are listed when 0.
+         * @param {string} [_continue] continue is the token of the previous list to list the next rollouts from.
          * @param {*} [options] Override http request option.
          * @throws {RequiredError}
          */
-        rolloutServiceWatchRolloutInfos(namespace: string, options?: any) {
-            return RolloutServiceApiFp(configuration).rolloutServiceWatchRolloutInfos(namespace, options)(fetch, basePath);
+        rolloutServiceWatchRolloutInfos(namespace: string, labelSelector?: string, phase?: string, strategy?: string, limit?: string, _continue?: string, options?: any) {
+            return RolloutServiceApiFp(configuration).rolloutServiceWatchRolloutInfos(namespace, labelSelector, phase, strategy, limit, _continue, options)(fetch, basePath);
+        },
+        /**
+         * 
+         * @param {string} [namespace] namespace of the rollouts. The rollouts of all the namespaces are listed when empty.
+         * @param {string} [labelSelector] labelSelector only selects the rollouts whose labels match the selector.
+         * @param {string} [phase] phase only selects the rollouts in the phase, e.g. Healthy, Progressing, Paused or Degraded.
+         * @param {string} [strategy] strategy only selects the rollouts with the strategy, i.e. Canary or BlueGreen.
+         * @param {string} [limit] limit is the maximum number of rollouts of a list. All the rollouts are listed when 0.
+         * @param {string} [_continue] continue is the token of the previous list to list the next rollouts from.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        rolloutServiceWatchRolloutInfos2(namespace?: string, labelSelector?: string, phase?: string, strategy?: string, limit?: string, _continue?: string, options?: any) {
+            return RolloutServiceApiFp(configuration).rolloutServiceWatchRolloutInfos2(namespace, labelSelector, phase, strategy, limit, _continue, options)(fetch, basePath);
         },
     };
 };
@@ -9220,12 +11064,45 @@ export class RolloutServiceApi extends BaseAPI {
     /**
      * 
      * @param {string} namespace 
+     * @param {string} name 
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof RolloutServiceApi
+     */
+    public rolloutServiceListRolloutAuditEvents(namespace: string, name: string, options?: any) {
+        return RolloutServiceApiFp(this.configuration).rolloutServiceListRolloutAuditEvents(namespace, name, options)(this.fetch, this.basePath);
+    }
+
+    /**
+     * 
+     * @param {string} namespace namespace of the rollouts. The rollouts of all the namespaces are listed when empty.
+     * @param {string} [labelSelector] labelSelector only selects the rollouts whose labels match the selector.
+     * @param {string} [phase] phase only selects the rollouts in the phase, e.g. Healthy, Progressing, Paused or Degraded.
+     * @param {string} [strategy] strategy only selects the rollouts with the strategy, i.e. Canary or BlueGreen.
+     * @param {string} [limit] limit is the maximum number of rollouts of a list. All the rollouts are listed when 0.
+     * @param {string} [_continue] continue is the token of the previous list to list the next rollouts from.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof RolloutServiceApi
+     */
+    public rolloutServiceListRolloutInfos(namespace: string, labelSelector?: string, phase?: string, strategy?: string, limit?: string, _continue?: string, options?: any) {
+        return RolloutServiceApiFp(this.configuration).rolloutServiceListRolloutInfos(namespace, labelSelector, phase, strategy, limit, _continue, options)(this.fetch, this.basePath);
+    }
+
+    /**
+     * 
+     * @param {string} [namespace] namespace of the rollouts. The rollouts of all the namespaces are listed when empty.
+     * @param {string} [labelSelector] labelSelector only selects the rollouts whose labels match the selector.
+     * @param {string} [phase] phase only selects the rollouts in the phase, e.g. Healthy, Progressing, Paused or Degraded.
+     * @param {string} [strategy] strategy only selects the rollouts with the strategy, i.e. Canary or BlueGreen.
+     * @param {string} [limit] limit is the maximum number of rollouts of a list. All the rollouts are listed when 0.
+     * @param {string} [_continue] continue is the token of the previous list to list the next rollouts from.
      * @param {*} [options] Override http request option.
      * @throws {RequiredError}
      * @memberof RolloutServiceApi
      */
-    public rolloutServiceListRolloutInfos(namespace: string, options?: any) {
-        return RolloutServiceApiFp(this.configuration).rolloutServiceListRolloutInfos(namespace, options)(this.fetch, this.basePath);
+    public rolloutServiceListRolloutInfos2(namespace?: string, labelSelector?: string, phase?: string, strategy?: string, limit?: string, _continue?: string, options?: any) {
+        return RolloutServiceApiFp(this.configuration).rolloutServiceListRolloutInfos2(namespace, labelSelector, phase, strategy, limit, _continue, options)(this.fetch, this.basePath);
     }
 
     /**
@@ -9321,13 +11198,34 @@ export class RolloutServiceApi extends BaseAPI {
 
     /**
      * 
-     * @param {string} namespace 
+     * @param {string} namespace namespace of the rollouts. The rollouts of all the namespaces are listed when empty.
+     * @param {string} [labelSelector] labelSelector only selects the rollouts whose labels match the selector.
+     * @param {string} [phase] phase only selects the rollouts in the phase, e.g. Healthy, Progressing, Paused or Degraded.
+     * @param {string} [strategy] strategy only selects the rollouts with the strategy, i.e. Canary or BlueGreen.
+     * @param {string} [limit] limit is the maximum number of rollouts of a list. All the rollouts are listed when 0.
+     * @param {string} [_continue] continue is the token of the previous list to list the next rollouts from.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof RolloutServiceApi
+     */
+    public rolloutServiceWatchRolloutInfos(namespace: string, labelSelector?: string, phase?: string, strategy?: string, limit?: string, _continue?: string, options?: any) {
+        return RolloutServiceApiFp(this.configuration).rolloutServiceWatchRolloutInfos(namespace, labelSelector, phase, strategy, limit, _continue, options)(this.fetch, this.basePath);
+    }
+
+    /**
+     * 
+     * @param {string} [namespace] namespace of the rollouts. The rollouts of all the namespaces are listed when empty.
+     * @param {string} [labelSelector] labelSelector only selects the rollouts whose labels match the selector.
+     * @param {string} [phase] phase only selects the rollouts in the phase, e.g. Healthy, Progressing, Paused or Degraded.
+     * @param {string} [strategy] strategy only selects the rollouts with the strategy, i.e. Canary or BlueGreen.
+     * @param {string} [limit] limit is the maximum number of rollouts of a list. All the rollouts are listed when 0.
+     * @param {string} [_continue] continue is the token of the previous list to list the next rollouts from.
      * @param {*} [options] Override http request option.
      * @throws {RequiredError}
      * @memberof RolloutServiceApi
      */
-    public rolloutServiceWatchRolloutInfos(namespace: string, options?: any) {
-        return RolloutServiceApiFp(this.configuration).rolloutServiceWatchRolloutInfos(namespace, options)(this.fetch, this.basePath);
+    public rolloutServiceWatchRolloutInfos2(namespace?: string, labelSelector?: string, phase?: string, strategy?: string, limit?: string, _continue?: string, options?: any) {
+        return RolloutServiceApiFp(this.configuration).rolloutServiceWatchRolloutInfos2(namespace, labelSelector, phase, strategy, limit, _continue, options)(this.fetch, this.basePath);
     }
 
 }
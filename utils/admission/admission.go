@@ -0,0 +1,333 @@
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/validation"
+	"github.com/argoproj/argo-rollouts/utils/annotations"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+	serviceutil "github.com/argoproj/argo-rollouts/utils/service"
+	timeutil "github.com/argoproj/argo-rollouts/utils/time"
+)
+
+const (
+	// ValidatePath is the path of the validating webhook of the rollouts
+	ValidatePath = "/validate/rollouts"
+	// MutatePath is the path of the mutating webhook of the rollouts, which applies their defaults and records the
+	// users who promote, abort or retry them
+	MutatePath = "/mutate/rollouts"
+
+	maxAdmissionReviewBytes = 3 << 20
+)
+
+// NewHandler returns a handler which serves the validating and mutating admission webhooks of the rollouts. The
+// updates of the status of the rollouts by the controller, which runs as controllerUsername, are not recorded as
+// actions of users. No actions are recorded when controllerUsername is empty.
+func NewHandler(kubeclientset kubernetes.Interface, controllerUsername string) http.Handler {
+	h := &handler{kubeclientset: kubeclientset, controllerUsername: controllerUsername}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+ValidatePath, h.serve(h.validate))
+	mux.HandleFunc("POST "+MutatePath, h.serve(h.mutate))
+	return mux
+}
+
+type handler struct {
+	kubeclientset      kubernetes.Interface
+	controllerUsername string
+}
+
+// Username returns the name of the user the client authenticates as
+func Username(ctx context.Context, kubeclientset kubernetes.Interface) (string, error) {
+	review, err := kubeclientset.AuthenticationV1().SelfSubjectReviews().Create(ctx, &authenticationv1.SelfSubjectReview{}, metav1.CreateOptions{})
+	if err != nil {
+		return "", err
+	}
+	return review.Status.UserInfo.Username, nil
+}
+
+// serve decodes the AdmissionReview of a request, and responds with the response of the review func
+func (h *handler) serve(review func(context.Context, *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var admissionReview admissionv1.AdmissionReview
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAdmissionReviewBytes)).Decode(&admissionReview); err != nil {
+			http.Error(w, fmt.Sprintf("invalid AdmissionReview: %v", err), http.StatusBadRequest)
+			return
+		}
+		if admissionReview.Request == nil {
+			http.Error(w, "AdmissionReview has no request", http.StatusBadRequest)
+			return
+		}
+		response := review(r.Context(), admissionReview.Request)
+		response.UID = admissionReview.Request.UID
+		admissionReview.Request = nil
+		admissionReview.Response = response
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(admissionReview); err != nil {
+			log.Warnf("Failed to write AdmissionReview response: %v", err)
+		}
+	}
+}
+
+// validate rejects rollouts with an invalid spec, or which reference missing Services or Services managed by another
+// rollout. Updates which leave the spec unchanged, e.g. of the metadata or status of an invalid rollout, are allowed.
+func (h *handler) validate(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update || req.SubResource != "" {
+		return allowed()
+	}
+	ro, err := decodeRollout(req.Object.Raw)
+	if err != nil {
+		return errored(http.StatusBadRequest, err)
+	}
+	if req.Operation == admissionv1.Update {
+		old, err := decodeRollout(req.OldObject.Raw)
+		if err != nil {
+			return errored(http.StatusBadRequest, err)
+		}
+		if ro.DeletionTimestamp != nil || equality.Semantic.DeepEqual(old.Spec, ro.Spec) {
+			return allowed()
+		}
+	}
+	allErrs := validateRollout(ro)
+	serviceErrs, err := h.validateServices(ctx, ro)
+	if err != nil {
+		return errored(http.StatusInternalServerError, err)
+	}
+	allErrs = append(allErrs, serviceErrs...)
+	if len(allErrs) > 0 {
+		status := k8serrors.NewInvalid(schema.GroupKind{Group: rollouts.Group, Kind: rollouts.RolloutKind}, ro.Name, allErrs).ErrStatus
+		return &admissionv1.AdmissionResponse{Result: &status}
+	}
+	return allowed()
+}
+
+// validateRollout validates the spec and the traffic routing of the rollout. The pod template of a rollout which
+// references a workload is resolved by the controller, so that only its strategy is validated.
+func validateRollout(ro *v1alpha1.Rollout) field.ErrorList {
+	var allErrs field.ErrorList
+	if ro.Spec.WorkloadRef != nil {
+		allErrs = validation.ValidateRolloutStrategy(ro, field.NewPath("spec", "strategy"))
+	} else {
+		allErrs = validation.ValidateRollout(ro)
+	}
+	trafficRoutingPath := field.NewPath("spec", "strategy", "canary", "trafficRouting")
+	for _, validate := range []func(*v1alpha1.Rollout) error{
+		validation.ValidateRolloutNginxIngressesConfig,
+		validation.ValidateRolloutAlbIngressesConfig,
+		validation.ValidateRolloutVirtualServicesConfig,
+	} {
+		if err := validate(ro); err != nil {
+			allErrs = append(allErrs, field.Invalid(trafficRoutingPath, ro.Name, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+// validateServices validates the Services referenced by the rollout. The stable and canary Services of a rollout
+// which creates its Services may be missing.
+func (h *handler) validateServices(ctx context.Context, ro *v1alpha1.Rollout) (field.ErrorList, error) {
+	allErrs := field.ErrorList{}
+	references := map[validation.ServiceType]string{}
+	if blueGreen := ro.Spec.Strategy.BlueGreen; blueGreen != nil {
+		references[validation.ActiveService] = blueGreen.ActiveService
+		references[validation.PreviewService] = blueGreen.PreviewService
+	} else if canary := ro.Spec.Strategy.Canary; canary != nil {
+		references[validation.StableService] = canary.StableService
+		references[validation.CanaryService] = canary.CanaryService
+		if canary.PingPong != nil {
+			references[validation.PingService] = canary.PingPong.PingService
+			references[validation.PongService] = canary.PingPong.PongService
+		}
+	}
+	createServices := ro.Spec.Strategy.Canary != nil && ro.Spec.Strategy.Canary.CreateServices
+	for _, serviceType := range []validation.ServiceType{
+		validation.ActiveService, validation.PreviewService, validation.StableService,
+		validation.CanaryService, validation.PingService, validation.PongService,
+	} {
+		name := references[serviceType]
+		if name == "" {
+			continue
+		}
+		fldPath := validation.GetServiceWithTypeFieldPath(serviceType)
+		svc, err := h.kubeclientset.CoreV1().Services(ro.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			if !createServices || (serviceType != validation.StableService && serviceType != validation.CanaryService) {
+				allErrs = append(allErrs, field.Invalid(fldPath, name, err.Error()))
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if ro.Spec.WorkloadRef == nil {
+			allErrs = append(allErrs, validation.ValidateService(validation.ServiceWithType{Service: svc, Type: serviceType}, ro)...)
+		} else if managedBy, ok := serviceutil.HasManagedByAnnotation(svc); ok && managedBy != ro.Name {
+			allErrs = append(allErrs, field.Invalid(fldPath, name, fmt.Sprintf(conditions.ServiceReferencingManagedService, name)))
+		}
+	}
+	return allErrs, nil
+}
+
+// mutate applies the defaults of the fields of the rollout which are unset, so that the rollout shows the values the
+// controller runs it with. The updates of the status of the rollout which promote, abort or retry it record the user
+// in the status, i.e. the logged in user for the actions the dashboard takes by impersonating them. The updates of
+// the rollout which change its sync-action annotation record the user in the sync-action-user annotation, which no
+// other update can change.
+func (h *handler) mutate(_ context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return allowed()
+	}
+	ro, err := decodeRollout(req.Object.Raw)
+	if err != nil {
+		return errored(http.StatusBadRequest, err)
+	}
+	var patch []jsonPatchOperation
+	switch {
+	case req.SubResource == "status" && req.Operation == admissionv1.Update:
+		old, err := decodeRollout(req.OldObject.Raw)
+		if err != nil {
+			return errored(http.StatusBadRequest, err)
+		}
+		patch = h.userActionPatch(req.UserInfo, &old.Status, &ro.Status)
+	case req.SubResource == "":
+		var old *v1alpha1.Rollout
+		if req.Operation == admissionv1.Update {
+			if old, err = decodeRollout(req.OldObject.Raw); err != nil {
+				return errored(http.StatusBadRequest, err)
+			}
+		}
+		patch = append(defaultsPatch(ro), syncActionUserPatch(req.UserInfo, old, ro)...)
+	}
+	if len(patch) == 0 {
+		return allowed()
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return errored(http.StatusInternalServerError, err)
+	}
+	patchType := admissionv1.PatchTypeJSONPatch
+	return &admissionv1.AdmissionResponse{Allowed: true, Patch: data, PatchType: &patchType}
+}
+
+// jsonPatchOperation is an operation of a JSON patch
+type jsonPatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value"`
+}
+
+// defaultsPatch returns the JSON patch which sets the unset fields of the rollout to their defaults
+func defaultsPatch(ro *v1alpha1.Rollout) []jsonPatchOperation {
+	var patch []jsonPatchOperation
+	if ro.Spec.Replicas == nil {
+		patch = append(patch, jsonPatchOperation{Op: "add", Path: "/spec/replicas", Value: defaults.DefaultReplicas})
+	}
+	if ro.Spec.RevisionHistoryLimit == nil {
+		patch = append(patch, jsonPatchOperation{Op: "add", Path: "/spec/revisionHistoryLimit", Value: defaults.DefaultRevisionHistoryLimit})
+	}
+	if ro.Spec.ProgressDeadlineSeconds == nil {
+		patch = append(patch, jsonPatchOperation{Op: "add", Path: "/spec/progressDeadlineSeconds", Value: defaults.DefaultProgressDeadlineSeconds})
+	}
+	return patch
+}
+
+// syncActionUserPatch returns the JSON patch which records the user who changed the sync-action annotation of the
+// rollout in its sync-action-user annotation, or which restores the sync-action-user annotation of the old rollout
+// if the update changes it but not the sync-action annotation
+func syncActionUserPatch(user authenticationv1.UserInfo, old, ro *v1alpha1.Rollout) []jsonPatchOperation {
+	var oldAction, oldUser string
+	var hasOldUser bool
+	if old != nil {
+		oldAction = old.Annotations[annotations.SyncActionAnnotation]
+		oldUser, hasOldUser = old.Annotations[annotations.SyncActionUserAnnotation]
+	}
+	action, hasAction := ro.Annotations[annotations.SyncActionAnnotation]
+	currentUser, hasUser := ro.Annotations[annotations.SyncActionUserAnnotation]
+	path := "/metadata/annotations/" + strings.ReplaceAll(annotations.SyncActionUserAnnotation, "/", "~1")
+	switch {
+	case hasAction && action != oldAction:
+		if hasUser && currentUser == user.Username {
+			return nil
+		}
+		return []jsonPatchOperation{{Op: "add", Path: path, Value: user.Username}}
+	case hasUser == hasOldUser && currentUser == oldUser:
+		return nil
+	case !hasOldUser:
+		return []jsonPatchOperation{{Op: "remove", Path: path}}
+	case ro.Annotations == nil:
+		return []jsonPatchOperation{{Op: "add", Path: "/metadata/annotations", Value: map[string]string{annotations.SyncActionUserAnnotation: oldUser}}}
+	}
+	return []jsonPatchOperation{{Op: "add", Path: path, Value: oldUser}}
+}
+
+// userActionPatch returns the JSON patch which records the action of the user who updated the status, if the update
+// promotes, aborts or retries the rollout
+func (h *handler) userActionPatch(user authenticationv1.UserInfo, old, status *v1alpha1.RolloutStatus) []jsonPatchOperation {
+	if h.controllerUsername == "" || user.Username == h.controllerUsername {
+		return nil
+	}
+	action := userAction(old, status)
+	if action == "" {
+		return nil
+	}
+	return []jsonPatchOperation{{Op: "add", Path: "/status/lastUserAction", Value: v1alpha1.RolloutUserAction{
+		Action: action,
+		User:   user.Username,
+		Groups: user.Groups,
+		Time:   timeutil.MetaNow(),
+	}}}
+}
+
+// userAction returns the action of an update of the status of a rollout, i.e. the patches of the status of the
+// promote, abort and retry commands
+func userAction(old, status *v1alpha1.RolloutStatus) v1alpha1.RolloutUserActionType {
+	switch {
+	case status.Abort && !old.Abort:
+		return v1alpha1.RolloutUserActionAbort
+	case !status.Abort && old.Abort:
+		return v1alpha1.RolloutUserActionRetry
+	case status.PromoteFull && !old.PromoteFull:
+		return v1alpha1.RolloutUserActionPromoteFull
+	case len(status.PauseConditions) == 0 && len(old.PauseConditions) > 0,
+		!status.ControllerPause && old.ControllerPause,
+		status.CurrentStepIndex != nil && old.CurrentStepIndex != nil && *status.CurrentStepIndex > *old.CurrentStepIndex:
+		return v1alpha1.RolloutUserActionPromote
+	}
+	return ""
+}
+
+func decodeRollout(raw []byte) (*v1alpha1.Rollout, error) {
+	var ro v1alpha1.Rollout
+	if err := json.Unmarshal(raw, &ro); err != nil {
+		return nil, fmt.Errorf("invalid Rollout: %w", err)
+	}
+	return &ro, nil
+}
+
+func allowed() *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{Allowed: true}
+}
+
+func errored(code int32, err error) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{Result: &metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    code,
+		Message: err.Error(),
+	}}
+}
@@ -0,0 +1,246 @@
+package admission
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+func newRollout() *v1alpha1.Rollout {
+	labels := map[string]string{"app": "foo"}
+	return &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: metav1.NamespaceDefault},
+		Spec: v1alpha1.RolloutSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "foo", Image: "foo"}}},
+			},
+			Strategy: v1alpha1.RolloutStrategy{Canary: &v1alpha1.CanaryStrategy{
+				StableService: "stable",
+				CanaryService: "canary",
+				Steps:         []v1alpha1.CanaryStep{{SetWeight: ptr.To[int32](50)}},
+			}},
+		},
+	}
+}
+
+func newService(name string, annotations map[string]string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceDefault, Annotations: annotations},
+		Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "foo"}},
+	}
+}
+
+// review sends an AdmissionReview of the rollout to the path of the handler, and returns the response
+func review(t *testing.T, handler http.Handler, path string, operation admissionv1.Operation, ro, old *v1alpha1.Rollout, opts ...func(*admissionv1.AdmissionRequest)) *admissionv1.AdmissionResponse {
+	req := &admissionv1.AdmissionRequest{UID: types.UID("review"), Operation: operation}
+	for _, opt := range opts {
+		opt(req)
+	}
+	data, err := json.Marshal(ro)
+	require.NoError(t, err)
+	req.Object = runtime.RawExtension{Raw: data}
+	if old != nil {
+		data, err = json.Marshal(old)
+		require.NoError(t, err)
+		req.OldObject = runtime.RawExtension{Raw: data}
+	}
+	body, err := json.Marshal(admissionv1.AdmissionReview{TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"}, Request: req})
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp admissionv1.AdmissionReview
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.NotNil(t, resp.Response)
+	assert.Equal(t, types.UID("review"), resp.Response.UID)
+	assert.Equal(t, "AdmissionReview", resp.Kind)
+	return resp.Response
+}
+
+func TestValidate(t *testing.T) {
+	client := k8sfake.NewSimpleClientset(newService("stable", nil), newService("canary", nil))
+	handler := NewHandler(client, "")
+
+	resp := review(t, handler, ValidatePath, admissionv1.Create, newRollout(), nil)
+	assert.True(t, resp.Allowed)
+
+	ro := newRollout()
+	ro.Spec.Strategy.Canary.Steps = append(ro.Spec.Strategy.Canary.Steps, v1alpha1.CanaryStep{SetWeight: ptr.To[int32](10), Pause: &v1alpha1.RolloutPause{}})
+	resp = review(t, handler, ValidatePath, admissionv1.Create, ro, nil)
+	assert.False(t, resp.Allowed)
+	require.NotNil(t, resp.Result)
+	assert.Equal(t, metav1.StatusReasonInvalid, resp.Result.Reason)
+	assert.Contains(t, resp.Result.Message, "spec.strategy.steps[1]")
+
+	// updates of the metadata of an invalid rollout are allowed
+	updated := ro.DeepCopy()
+	updated.Annotations = map[string]string{"foo": "bar"}
+	resp = review(t, handler, ValidatePath, admissionv1.Update, updated, ro)
+	assert.True(t, resp.Allowed)
+
+	resp = review(t, handler, ValidatePath, admissionv1.Update, ro, newRollout())
+	assert.False(t, resp.Allowed)
+}
+
+func TestValidateServices(t *testing.T) {
+	client := k8sfake.NewSimpleClientset(newService("stable", map[string]string{v1alpha1.ManagedByRolloutsKey: "bar"}))
+	handler := NewHandler(client, "")
+
+	resp := review(t, handler, ValidatePath, admissionv1.Create, newRollout(), nil)
+	assert.False(t, resp.Allowed)
+	require.NotNil(t, resp.Result)
+	assert.Len(t, resp.Result.Details.Causes, 2)
+	assert.Contains(t, resp.Result.Message, `Service "stable" is managed by another Rollout`)
+	assert.Contains(t, resp.Result.Message, `services "canary" not found`)
+
+	// the services of a rollout which creates them may be missing
+	client = k8sfake.NewSimpleClientset()
+	ro := newRollout()
+	ro.Spec.Strategy.Canary.CreateServices = true
+	ro.Spec.Template.Spec.Containers[0].Ports = []corev1.ContainerPort{{ContainerPort: 8080}}
+	resp = review(t, NewHandler(client, ""), ValidatePath, admissionv1.Create, ro, nil)
+	assert.True(t, resp.Allowed)
+}
+
+func TestMutate(t *testing.T) {
+	handler := NewHandler(k8sfake.NewSimpleClientset(), "")
+
+	resp := review(t, handler, MutatePath, admissionv1.Create, newRollout(), nil)
+	assert.True(t, resp.Allowed)
+	require.NotNil(t, resp.PatchType)
+	assert.Equal(t, admissionv1.PatchTypeJSONPatch, *resp.PatchType)
+	assert.JSONEq(t, `[
+		{"op": "add", "path": "/spec/replicas", "value": 1},
+		{"op": "add", "path": "/spec/revisionHistoryLimit", "value": 10},
+		{"op": "add", "path": "/spec/progressDeadlineSeconds", "value": 600}
+	]`, string(resp.Patch))
+
+	ro := newRollout()
+	ro.Spec.Replicas = ptr.To[int32](3)
+	ro.Spec.RevisionHistoryLimit = ptr.To[int32](2)
+	ro.Spec.ProgressDeadlineSeconds = ptr.To[int32](300)
+	resp = review(t, handler, MutatePath, admissionv1.Create, ro, nil)
+	assert.True(t, resp.Allowed)
+	assert.Nil(t, resp.Patch)
+}
+
+func TestMutateSyncActionUser(t *testing.T) {
+	handler := NewHandler(k8sfake.NewSimpleClientset(), "")
+	updateBy := func(username string) func(*admissionv1.AdmissionRequest) {
+		return func(req *admissionv1.AdmissionRequest) {
+			req.UserInfo = authenticationv1.UserInfo{Username: username}
+		}
+	}
+	withAnnotations := func(annotations map[string]string) *v1alpha1.Rollout {
+		ro := newRollout()
+		ro.Spec.Replicas = ptr.To[int32](1)
+		ro.Spec.RevisionHistoryLimit = ptr.To[int32](10)
+		ro.Spec.ProgressDeadlineSeconds = ptr.To[int32](600)
+		ro.Annotations = annotations
+		return ro
+	}
+	synced := withAnnotations(map[string]string{
+		"rollout.argoproj.io/sync-action":      "retry:1",
+		"rollout.argoproj.io/sync-action-user": "alice",
+	})
+
+	// a new sync action records the user
+	resp := review(t, handler, MutatePath, admissionv1.Update, withAnnotations(map[string]string{"rollout.argoproj.io/sync-action": "retry:2"}), synced, updateBy("bob"))
+	assert.JSONEq(t, `[{"op": "add", "path": "/metadata/annotations/rollout.argoproj.io~1sync-action-user", "value": "bob"}]`, string(resp.Patch))
+	resp = review(t, handler, MutatePath, admissionv1.Create, withAnnotations(map[string]string{"rollout.argoproj.io/sync-action": "retry:2", "rollout.argoproj.io/sync-action-user": "alice"}), nil, updateBy("bob"))
+	assert.JSONEq(t, `[{"op": "add", "path": "/metadata/annotations/rollout.argoproj.io~1sync-action-user", "value": "bob"}]`, string(resp.Patch))
+
+	// the user cannot be changed without changing the sync action
+	resp = review(t, handler, MutatePath, admissionv1.Update, withAnnotations(map[string]string{"rollout.argoproj.io/sync-action": "retry:1", "rollout.argoproj.io/sync-action-user": "carol"}), synced, updateBy("bob"))
+	assert.JSONEq(t, `[{"op": "add", "path": "/metadata/annotations/rollout.argoproj.io~1sync-action-user", "value": "alice"}]`, string(resp.Patch))
+	resp = review(t, handler, MutatePath, admissionv1.Update, withAnnotations(nil), synced, updateBy("bob"))
+	assert.JSONEq(t, `[{"op": "add", "path": "/metadata/annotations", "value": {"rollout.argoproj.io/sync-action-user": "alice"}}]`, string(resp.Patch))
+	resp = review(t, handler, MutatePath, admissionv1.Update, withAnnotations(map[string]string{"rollout.argoproj.io/sync-action-user": "carol"}), withAnnotations(nil), updateBy("bob"))
+	assert.JSONEq(t, `[{"op": "remove", "path": "/metadata/annotations/rollout.argoproj.io~1sync-action-user", "value": null}]`, string(resp.Patch))
+
+	// other updates are not patched
+	resp = review(t, handler, MutatePath, admissionv1.Update, synced, synced, updateBy("bob"))
+	assert.Nil(t, resp.Patch)
+}
+
+func TestMutateUserAction(t *testing.T) {
+	handler := NewHandler(k8sfake.NewSimpleClientset(), "system:serviceaccount:argo-rollouts:argo-rollouts")
+	statusUpdateBy := func(username string) func(*admissionv1.AdmissionRequest) {
+		return func(req *admissionv1.AdmissionRequest) {
+			req.SubResource = "status"
+			req.UserInfo = authenticationv1.UserInfo{Username: username, Groups: []string{"sre"}}
+		}
+	}
+	paused := newRollout()
+	paused.Spec.Replicas = ptr.To[int32](1)
+	paused.Status.CurrentStepIndex = ptr.To[int32](1)
+	paused.Status.ControllerPause = true
+	paused.Status.PauseConditions = []v1alpha1.PauseCondition{{Reason: v1alpha1.PauseReasonCanaryPauseStep}}
+	aborted := paused.DeepCopy()
+	aborted.Status.Abort = true
+
+	promoted := paused.DeepCopy()
+	promoted.Status.PauseConditions = nil
+	promotedFull := paused.DeepCopy()
+	promotedFull.Status.PromoteFull = true
+	for _, test := range []struct {
+		old, new *v1alpha1.Rollout
+		action   v1alpha1.RolloutUserActionType
+	}{
+		{paused, promoted, v1alpha1.RolloutUserActionPromote},
+		{paused, promotedFull, v1alpha1.RolloutUserActionPromoteFull},
+		{paused, aborted, v1alpha1.RolloutUserActionAbort},
+		{aborted, paused, v1alpha1.RolloutUserActionRetry},
+	} {
+		t.Run(string(test.action), func(t *testing.T) {
+			resp := review(t, handler, MutatePath, admissionv1.Update, test.new, test.old, statusUpdateBy("alice"))
+			assert.True(t, resp.Allowed)
+			var patch []struct {
+				Op    string                     `json:"op"`
+				Path  string                     `json:"path"`
+				Value v1alpha1.RolloutUserAction `json:"value"`
+			}
+			require.NoError(t, json.Unmarshal(resp.Patch, &patch))
+			require.Len(t, patch, 1)
+			assert.Equal(t, "/status/lastUserAction", patch[0].Path)
+			assert.Equal(t, test.action, patch[0].Value.Action)
+			assert.Equal(t, "alice", patch[0].Value.User)
+			assert.Equal(t, []string{"sre"}, patch[0].Value.Groups)
+			assert.False(t, patch[0].Value.Time.IsZero())
+		})
+	}
+
+	// the updates of the controller are not recorded
+	resp := review(t, handler, MutatePath, admissionv1.Update, promoted, paused, statusUpdateBy("system:serviceaccount:argo-rollouts:argo-rollouts"))
+	assert.True(t, resp.Allowed)
+	assert.Nil(t, resp.Patch)
+
+	// other updates of the status are not recorded
+	updated := paused.DeepCopy()
+	updated.Status.Message = "updated"
+	resp = review(t, handler, MutatePath, admissionv1.Update, updated, paused, statusUpdateBy("alice"))
+	assert.Nil(t, resp.Patch)
+}
+
+func TestInvalidAdmissionReview(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewHandler(k8sfake.NewSimpleClientset(), "").ServeHTTP(w, httptest.NewRequest(http.MethodPost, ValidatePath, bytes.NewReader([]byte("{"))))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
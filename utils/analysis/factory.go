@@ -92,6 +92,30 @@ func BackgroundLabels(podHash, instanceID string) map[string]string {
 
 }
 
+// GuardrailLabels returns a map[string]string of common labels for the guardrail analysis
+func GuardrailLabels(podHash, instanceID string) map[string]string {
+	labels := map[string]string{
+		v1alpha1.DefaultRolloutUniqueLabelKey: podHash,
+		v1alpha1.RolloutTypeLabel:             v1alpha1.RolloutTypeGuardrailLabel,
+	}
+	if instanceID != "" {
+		labels[v1alpha1.LabelKeyControllerInstanceID] = instanceID
+	}
+	return labels
+}
+
+// RestartLabels returns a map[string]string of common labels for the analysis of a restarted batch of pods
+func RestartLabels(podHash, instanceID string) map[string]string {
+	labels := map[string]string{
+		v1alpha1.DefaultRolloutUniqueLabelKey: podHash,
+		v1alpha1.RolloutTypeLabel:             v1alpha1.RolloutTypeRestartLabel,
+	}
+	if instanceID != "" {
+		labels[v1alpha1.LabelKeyControllerInstanceID] = instanceID
+	}
+	return labels
+}
+
 // StepLabels returns a map[string]string of common labels for analysisruns created from an analysis step
 func StepLabels(index int32, podHash, instanceID string) map[string]string {
 	indexStr := strconv.Itoa(int(index))
@@ -251,6 +275,9 @@ func ValidateMetric(metric v1alpha1.Metric) error {
 	if metric.Provider.SkyWalking != nil {
 		numProviders++
 	}
+	if metric.Provider.AzureMonitor != nil {
+		numProviders++
+	}
 	if metric.Provider.Plugin != nil && len(metric.Provider.Plugin) > 0 {
 		// We allow exactly one plugin to be specified per analysis run template
 		numProviders = numProviders + len(metric.Provider.Plugin)
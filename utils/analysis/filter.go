@@ -29,6 +29,10 @@ func FilterCurrentRolloutAnalysisRuns(analysisRuns []*v1alpha1.AnalysisRun, r *v
 		}
 		return s.Name
 	}
+	restartArName := ""
+	if r.Status.RestartStatus != nil {
+		restartArName = getArName(r.Status.RestartStatus.CurrentAnalysisRunStatus)
+	}
 	for i := range analysisRuns {
 		ar := analysisRuns[i]
 		if ar != nil {
@@ -37,10 +41,14 @@ func FilterCurrentRolloutAnalysisRuns(analysisRuns []*v1alpha1.AnalysisRun, r *v
 				currArs.CanaryStep = ar
 			case getArName(r.Status.Canary.CurrentBackgroundAnalysisRunStatus):
 				currArs.CanaryBackground = ar
+			case getArName(r.Status.Canary.CurrentGuardrailAnalysisRunStatus):
+				currArs.CanaryGuardrail = ar
 			case getArName(r.Status.BlueGreen.PrePromotionAnalysisRunStatus):
 				currArs.BlueGreenPrePromotion = ar
 			case getArName(r.Status.BlueGreen.PostPromotionAnalysisRunStatus):
 				currArs.BlueGreenPostPromotion = ar
+			case restartArName:
+				currArs.Restart = ar
 			default:
 				otherArs = append(otherArs, ar)
 			}
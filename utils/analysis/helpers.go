@@ -25,6 +25,8 @@ type CurrentAnalysisRuns struct {
 	BlueGreenPostPromotion *v1alpha1.AnalysisRun
 	CanaryStep             *v1alpha1.AnalysisRun
 	CanaryBackground       *v1alpha1.AnalysisRun
+	CanaryGuardrail        *v1alpha1.AnalysisRun
+	Restart                *v1alpha1.AnalysisRun
 }
 
 func (c CurrentAnalysisRuns) ToArray() []*v1alpha1.AnalysisRun {
@@ -41,6 +43,12 @@ func (c CurrentAnalysisRuns) ToArray() []*v1alpha1.AnalysisRun {
 	if c.CanaryBackground != nil {
 		currentAnalysisRuns = append(currentAnalysisRuns, c.CanaryBackground)
 	}
+	if c.CanaryGuardrail != nil {
+		currentAnalysisRuns = append(currentAnalysisRuns, c.CanaryGuardrail)
+	}
+	if c.Restart != nil {
+		currentAnalysisRuns = append(currentAnalysisRuns, c.Restart)
+	}
 	return currentAnalysisRuns
 }
 
@@ -30,6 +30,20 @@ const (
 	WorkloadGenerationAnnotation = RolloutLabel + "/workload-generation"
 	// NotificationEngineAnnotation the annotation notification engine uses to determine if it should notify
 	NotificationEngineAnnotation = "notified.notifications.argoproj.io"
+	// PromotedExperimentWinnerAnnotation is the pod template hash of the pod template a rollout was updated to by an
+	// experiment step which promoted the template that won its experiment. The update skips the experiment step.
+	PromotedExperimentWinnerAnnotation = RolloutLabel + "/promoted-experiment-winner"
+	// SyncActionAnnotation requests an action on a rollout from a GitOps tool such as Argo CD. Its value is the
+	// action, optionally followed by a colon and a token (e.g. promote-full:3f2a1c) so that the action can be
+	// requested again
+	SyncActionAnnotation = RolloutLabel + "/sync-action"
+	// SyncActionUserAnnotation is the user who last changed the sync-action annotation of a rollout, which is set by
+	// the mutating admission webhook
+	SyncActionUserAnnotation = RolloutLabel + "/sync-action-user"
+	// SyncActionRetry retries an aborted update
+	SyncActionRetry = "retry"
+	// SyncActionPromoteFull fully promotes the update, skipping the remaining steps, analysis and pauses
+	SyncActionPromoteFull = "promote-full"
 )
 
 // GetDesiredReplicasAnnotation returns the number of desired replicas
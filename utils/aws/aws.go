@@ -4,13 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	elbv2 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
 	elbv2types "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -19,6 +23,7 @@ import (
 	"k8s.io/client-go/dynamic"
 
 	"github.com/argoproj/argo-rollouts/utils/defaults"
+	tlsutil "github.com/argoproj/argo-rollouts/utils/tls"
 )
 
 // TargetType is the targetType of your ELBV2 TargetGroup.
@@ -99,8 +104,66 @@ type ServiceReference struct {
 // NewClient instantiates a new AWS Client. It is declared as a variable to allow mocking
 var NewClient = DefaultNewClientFunc
 
+// RoleSessionName is the session name of the IAM roles assumed by the controller
+const RoleSessionName = "argo-rollouts"
+
+// LoadConfig loads the AWS configuration of the controller. Its credentials come from its environment, which includes
+// the web identity token of IAM roles for service accounts and the credentials endpoint of EKS Pod Identity, so that
+// no static keys are needed. When the controller runs outside of AWS with a web identity source, e.g. on GKE or AKS
+// with Workload Identity, the token of the source is exchanged for the credentials of the IAM role of the controller
+// instead. Otherwise the controller assumes its IAM role, if any, with the credentials of its environment. The region
+// overrides the region of the environment when set, and the credentials assume the IAM role roleARN with the external
+// ID when roleARN is set.
+func LoadConfig(ctx context.Context, region, roleARN, externalID string) (aws.Config, error) {
+	var opts []func(*config.LoadOptions) error
+	if region != "" {
+		opts = append(opts, config.WithRegion(region))
+	}
+	// the clients of the SDK have a transport of their own, which applies the outbound TLS policy of the controller
+	if tlsutil.HasClientConfig() {
+		opts = append(opts, config.WithHTTPClient(awshttp.NewBuildableClient().WithTransportOptions(func(transport *http.Transport) {
+			transport.TLSClientConfig = tlsutil.ClientConfig(false)
+		})))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return aws.Config{}, err
+	}
+	controllerRoleARN := defaults.GetAWSRoleARN()
+	if source := defaults.GetAWSWebIdentitySource(); source != "" {
+		retriever, err := newIdentityTokenRetriever(source)
+		if err != nil {
+			return aws.Config{}, err
+		}
+		if controllerRoleARN == "" {
+			return aws.Config{}, fmt.Errorf("the IAM role of the controller is required with the %s web identity source", source)
+		}
+		provider := stscreds.NewWebIdentityRoleProvider(sts.NewFromConfig(cfg), controllerRoleARN, retriever, func(o *stscreds.WebIdentityRoleOptions) {
+			o.RoleSessionName = RoleSessionName
+		})
+		cfg.Credentials = aws.NewCredentialsCache(provider)
+	} else if controllerRoleARN != "" {
+		cfg.Credentials = assumeRole(cfg, controllerRoleARN, "")
+	}
+	if roleARN != "" {
+		cfg.Credentials = assumeRole(cfg, roleARN, externalID)
+	}
+	return cfg, nil
+}
+
+// assumeRole returns the credentials of the IAM role, assumed with the credentials of the config
+func assumeRole(cfg aws.Config, roleARN, externalID string) aws.CredentialsProvider {
+	provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), roleARN, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = RoleSessionName
+		if externalID != "" {
+			o.ExternalID = aws.String(externalID)
+		}
+	})
+	return aws.NewCredentialsCache(provider)
+}
+
 func DefaultNewClientFunc() (Client, error) {
-	cfg, err := config.LoadDefaultConfig(context.TODO())
+	cfg, err := LoadConfig(context.TODO(), "", "", "")
 	if err != nil {
 		return nil, err
 	}
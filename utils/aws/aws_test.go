@@ -2,13 +2,24 @@ package aws
 
 import (
 	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	elbv2 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
 	elbv2types "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -17,6 +28,7 @@ import (
 	testutil "github.com/argoproj/argo-rollouts/test/util"
 	"github.com/argoproj/argo-rollouts/utils/aws/mocks"
 	"github.com/argoproj/argo-rollouts/utils/defaults"
+	tlsutil "github.com/argoproj/argo-rollouts/utils/tls"
 	unstructuredutil "github.com/argoproj/argo-rollouts/utils/unstructured"
 )
 
@@ -36,6 +48,106 @@ func TestGetTargetGroupBindingsGVR(t *testing.T) {
 	defaults.SetTargetGroupBindingAPIVersion("elbv2.k8s.aws/v1beta1")
 }
 
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_ACCESS_KEY_ID", "hoge")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "fuga")
+
+	cfg, err := LoadConfig(context.TODO(), "", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "us-east-1", cfg.Region)
+	cred, err := cfg.Credentials.Retrieve(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, "hoge", cred.AccessKeyID)
+
+	cfg, err = LoadConfig(context.TODO(), "eu-west-1", "arn:aws:iam::123456789012:role/rollouts", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "eu-west-1", cfg.Region)
+	cache, ok := cfg.Credentials.(*aws.CredentialsCache)
+	assert.True(t, ok)
+	assert.True(t, cache.IsCredentialsProvider(&stscreds.AssumeRoleProvider{}))
+}
+
+func TestLoadConfigOutboundTLS(t *testing.T) {
+	t.Setenv("AWS_REGION", "us-east-1")
+	tlsutil.SetClientConfig(&tls.Config{MinVersion: tls.VersionTLS13})
+	defer tlsutil.SetClientConfig(nil)
+
+	cfg, err := LoadConfig(context.TODO(), "", "", "")
+	require.NoError(t, err)
+	client, ok := cfg.HTTPClient.(*awshttp.BuildableClient)
+	require.True(t, ok)
+	assert.Equal(t, uint16(tls.VersionTLS13), client.GetTransport().TLSClientConfig.MinVersion)
+}
+
+// newSTSServer returns an STS endpoint which records the form of the requests, and responds with the credentials of
+// the action
+func newSTSServer(t *testing.T) *[]url.Values {
+	var requests []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		requests = append(requests, r.PostForm)
+		action := r.PostForm.Get("Action")
+		fmt.Fprintf(w, `<%[1]sResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/"><%[1]sResult><Credentials>`+
+			`<AccessKeyId>%[1]s</AccessKeyId><SecretAccessKey>secret</SecretAccessKey><SessionToken>token</SessionToken>`+
+			`<Expiration>2100-01-01T00:00:00Z</Expiration></Credentials></%[1]sResult></%[1]sResponse>`, action)
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("AWS_ENDPOINT_URL_STS", server.URL)
+	t.Setenv("AWS_REGION", "us-east-1")
+	return &requests
+}
+
+func TestLoadConfigExternalID(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "hoge")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "fuga")
+	requests := newSTSServer(t)
+
+	cfg, err := LoadConfig(context.TODO(), "", "arn:aws:iam::123456789012:role/metrics", "team-a")
+	require.NoError(t, err)
+	cred, err := cfg.Credentials.Retrieve(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, "AssumeRole", cred.AccessKeyID)
+	require.Len(t, *requests, 1)
+	assert.Equal(t, "arn:aws:iam::123456789012:role/metrics", (*requests)[0].Get("RoleArn"))
+	assert.Equal(t, "team-a", (*requests)[0].Get("ExternalId"))
+}
+
+func TestLoadConfigAzureWebIdentity(t *testing.T) {
+	requests := newSTSServer(t)
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("federated-token"), 0o600))
+	t.Setenv("AZURE_FEDERATED_TOKEN_FILE", tokenFile)
+	defaults.SetAWSWebIdentitySource(WebIdentitySourceAzure)
+	defer defaults.SetAWSWebIdentitySource("")
+
+	// the IAM role of the controller is required
+	_, err := LoadConfig(context.TODO(), "", "", "")
+	assert.EqualError(t, err, "the IAM role of the controller is required with the azure web identity source")
+
+	defaults.SetAWSRoleARN("arn:aws:iam::123456789012:role/rollouts")
+	defer defaults.SetAWSRoleARN("")
+	cfg, err := LoadConfig(context.TODO(), "", "", "")
+	require.NoError(t, err)
+	cred, err := cfg.Credentials.Retrieve(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, "AssumeRoleWithWebIdentity", cred.AccessKeyID)
+	require.Len(t, *requests, 1)
+	assert.Equal(t, "arn:aws:iam::123456789012:role/rollouts", (*requests)[0].Get("RoleArn"))
+	assert.Equal(t, "federated-token", (*requests)[0].Get("WebIdentityToken"))
+}
+
+func TestNewIdentityTokenRetriever(t *testing.T) {
+	_, err := newIdentityTokenRetriever("aws")
+	assert.EqualError(t, err, `unknown web identity source "aws", expected gcp or azure`)
+	t.Setenv("AZURE_FEDERATED_TOKEN_FILE", "")
+	_, err = newIdentityTokenRetriever(WebIdentitySourceAzure)
+	assert.EqualError(t, err, "AZURE_FEDERATED_TOKEN_FILE is not set, the controller does not run with Azure Workload Identity")
+	retriever, err := newIdentityTokenRetriever(WebIdentitySourceGCP)
+	assert.NoError(t, err)
+	assert.Equal(t, gcpIdentityToken{}, retriever)
+}
+
 func TestFindLoadBalancerByDNSName(t *testing.T) {
 	// LoadBalancer not found
 	{
@@ -0,0 +1,56 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"cloud.google.com/go/compute/metadata"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+)
+
+const (
+	// WebIdentitySourceGCP exchanges the ID token of the Google service account of the controller, e.g. on GKE with
+	// Workload Identity, for the credentials of its IAM role
+	WebIdentitySourceGCP = "gcp"
+	// WebIdentitySourceAzure exchanges the federated token of the Kubernetes service account of the controller on AKS
+	// with Azure Workload Identity for the credentials of its IAM role
+	WebIdentitySourceAzure = "azure"
+
+	// WebIdentityAudience is the audience of the Google ID tokens of the controller
+	WebIdentityAudience = "sts.amazonaws.com"
+	// azureFederatedTokenFileEnv is the path of the federated token projected by Azure Workload Identity
+	azureFederatedTokenFileEnv = "AZURE_FEDERATED_TOKEN_FILE"
+	// gcpIdentityTokenTimeout limits the time the request of a Google ID token takes
+	gcpIdentityTokenTimeout = 10 * time.Second
+)
+
+// newIdentityTokenRetriever returns the retriever of the web identity tokens of the source
+func newIdentityTokenRetriever(source string) (stscreds.IdentityTokenRetriever, error) {
+	switch source {
+	case WebIdentitySourceGCP:
+		return gcpIdentityToken{}, nil
+	case WebIdentitySourceAzure:
+		path := os.Getenv(azureFederatedTokenFileEnv)
+		if path == "" {
+			return nil, fmt.Errorf("%s is not set, the controller does not run with Azure Workload Identity", azureFederatedTokenFileEnv)
+		}
+		return stscreds.IdentityTokenFile(path), nil
+	}
+	return nil, fmt.Errorf("unknown web identity source %q, expected %s or %s", source, WebIdentitySourceGCP, WebIdentitySourceAzure)
+}
+
+// gcpIdentityToken retrieves the ID token of the Google service account of the controller from the metadata server
+type gcpIdentityToken struct{}
+
+func (gcpIdentityToken) GetIdentityToken() ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), gcpIdentityTokenTimeout)
+	defer cancel()
+	token, err := metadata.GetWithContext(ctx, "instance/service-accounts/default/identity?format=full&audience="+url.QueryEscape(WebIdentityAudience))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the ID token of the Google service account: %w", err)
+	}
+	return []byte(token), nil
+}
@@ -0,0 +1,140 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+)
+
+// KeyPair is a PEM encoded certificate and its private key
+type KeyPair struct {
+	Cert []byte
+	Key  []byte
+}
+
+// clockSkew backdates the certificates, so that they are valid on hosts whose clocks are slightly behind
+const clockSkew = 5 * time.Minute
+
+// GenerateCA generates a self-signed CA which is valid for the validity from now on
+func GenerateCA(commonName string, now time.Time, validity time.Duration) (*KeyPair, error) {
+	template, err := newTemplate(commonName, now, validity)
+	if err != nil {
+		return nil, err
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CA key: %w", err)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CA certificate: %w", err)
+	}
+	return encode(der, key)
+}
+
+// GenerateServingCert generates a serving certificate for the DNS names, signed by the CA, which is valid for the
+// validity from now on
+func GenerateServingCert(ca *KeyPair, dnsNames []string, now time.Time, validity time.Duration) (*KeyPair, error) {
+	if len(dnsNames) == 0 {
+		return nil, errors.New("serving certificate requires at least one DNS name")
+	}
+	caCert, caKey, err := parseKeyPair(ca)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA: %w", err)
+	}
+	template, err := newTemplate(dnsNames[0], now, validity)
+	if err != nil {
+		return nil, err
+	}
+	template.DNSNames = dnsNames
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serving key: %w", err)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create serving certificate: %w", err)
+	}
+	return encode(der, key)
+}
+
+func newTemplate(commonName string, now time.Time, validity time.Duration) (*x509.Certificate, error) {
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	return &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      pkix.Name{CommonName: commonName, Organization: []string{"Argo Rollouts"}},
+		NotBefore:    now.Add(-clockSkew),
+		NotAfter:     now.Add(validity),
+	}, nil
+}
+
+func encode(der []byte, key *ecdsa.PrivateKey) (*KeyPair, error) {
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal key: %w", err)
+	}
+	return &KeyPair{
+		Cert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		Key:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}, nil
+}
+
+// parseCertificate parses the first PEM encoded certificate
+func parseCertificate(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("no PEM encoded certificate")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func parseKeyPair(kp *KeyPair) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	cert, err := parseCertificate(kp.Cert)
+	if err != nil {
+		return nil, nil, err
+	}
+	block, _ := pem.Decode(kp.Key)
+	if block == nil || block.Type != "EC PRIVATE KEY" {
+		return nil, nil, errors.New("no PEM encoded EC private key")
+	}
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !key.PublicKey.Equal(cert.PublicKey) {
+		return nil, nil, errors.New("private key does not match the certificate")
+	}
+	return cert, key, nil
+}
+
+// needsRenewal returns whether less than a third of the lifetime of the certificate remains, so that certificates
+// are renewed well before they expire
+func needsRenewal(cert *x509.Certificate, now time.Time) bool {
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	return now.After(cert.NotAfter.Add(-lifetime / 3))
+}
+
+// coversDNSNames returns whether the certificate is valid for exactly the DNS names
+func coversDNSNames(cert *x509.Certificate, dnsNames []string) bool {
+	certNames := slices.Clone(cert.DNSNames)
+	names := slices.Clone(dnsNames)
+	slices.Sort(certNames)
+	slices.Sort(names)
+	return slices.Equal(certNames, names)
+}
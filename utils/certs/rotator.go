@@ -0,0 +1,406 @@
+package certs
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// DefaultSecretName is the name of the Secret the CA and the serving certificate are persisted to
+	DefaultSecretName = "argo-rollouts-serving-certs"
+	// DefaultCABundleConfigMapName is the name of the ConfigMap the CA bundle is published to
+	DefaultCABundleConfigMapName = "argo-rollouts-ca-bundle"
+	// DefaultCertValidity is the validity of the serving certificates
+	DefaultCertValidity = 90 * 24 * time.Hour
+	// DefaultCAValidity is the validity of the CAs
+	DefaultCAValidity = 5 * 365 * 24 * time.Hour
+	// DefaultCheckInterval is the interval in which the certificates are checked for renewal
+	DefaultCheckInterval = time.Hour
+	// DefaultCAOverlap is the period in which a renewed CA is published in the CA bundle before it signs the serving
+	// certificate
+	DefaultCAOverlap = 24 * time.Hour
+
+	// CABundleKey is the key of the CA bundle in the ConfigMap
+	CABundleKey = "ca.crt"
+	// InjectCABundleLabel labels the webhook configurations the CA bundle is injected into, with the value "true"
+	InjectCABundleLabel = "argo-rollouts.argoproj.io/inject-ca-bundle"
+
+	caCertKey         = "ca.crt"
+	caKeyKey          = "ca.key"
+	previousCACertKey = "ca-previous.crt"
+	nextCACertKey     = "ca-next.crt"
+	nextCAKeyKey      = "ca-next.key"
+	caCommonName      = "argo-rollouts-ca"
+)
+
+// Config configures the generation and rotation of the serving certificates
+type Config struct {
+	// Namespace of the Secret and the ConfigMap
+	Namespace string
+	// SecretName is the name of the Secret the CA and the serving certificate are persisted to, so that all replicas
+	// of the controller serve the same certificate
+	SecretName string
+	// CABundleConfigMapName is the name of the ConfigMap the CA bundle is published to
+	CABundleConfigMapName string
+	// DNSNames are the DNS names the serving certificate is valid for
+	DNSNames []string
+	// CertValidity and CAValidity are the validities of the serving certificates and the CAs. They are renewed once
+	// less than a third of their validity remains.
+	CertValidity time.Duration
+	CAValidity   time.Duration
+	// CheckInterval is the interval in which the certificates are checked for renewal
+	CheckInterval time.Duration
+	// CAOverlap is the period in which a renewed CA is published in the CA bundle before it signs the serving
+	// certificate, so that the clients trust the renewed CA by the time the serving certificate is signed by it
+	CAOverlap time.Duration
+	// InjectWebhooks injects the CA bundle into the webhook configurations labeled with InjectCABundleLabel
+	InjectWebhooks bool
+}
+
+// DefaultDNSNames returns the DNS names of a Service in a namespace
+func DefaultDNSNames(service, namespace string) []string {
+	return []string{
+		service,
+		fmt.Sprintf("%s.%s", service, namespace),
+		fmt.Sprintf("%s.%s.svc", service, namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", service, namespace),
+	}
+}
+
+// Rotator generates a CA and a serving certificate signed by it, and renews them before they expire. The CA bundle
+// is published to a ConfigMap. A renewed CA is added to the CA bundle for the overlap period before it signs the
+// serving certificate, and the previous CA stays in the CA bundle until it expires, so that the clients keep trusting
+// the serving certificate while the CA is rotated.
+type Rotator struct {
+	kubeclientset kubernetes.Interface
+	config        Config
+	now           func() time.Time
+	cert          atomic.Pointer[tls.Certificate]
+	caPool        atomic.Pointer[x509.CertPool]
+}
+
+// NewRotator returns a Rotator, which serves no certificate until the first Rotate
+func NewRotator(kubeclientset kubernetes.Interface, config Config) *Rotator {
+	if config.SecretName == "" {
+		config.SecretName = DefaultSecretName
+	}
+	if config.CABundleConfigMapName == "" {
+		config.CABundleConfigMapName = DefaultCABundleConfigMapName
+	}
+	if config.CertValidity <= 0 {
+		config.CertValidity = DefaultCertValidity
+	}
+	if config.CAValidity <= 0 {
+		config.CAValidity = DefaultCAValidity
+	}
+	if config.CheckInterval <= 0 {
+		config.CheckInterval = DefaultCheckInterval
+	}
+	if config.CAOverlap <= 0 {
+		config.CAOverlap = DefaultCAOverlap
+	}
+	return &Rotator{
+		kubeclientset: kubeclientset,
+		config:        config,
+		now:           time.Now,
+	}
+}
+
+// Run renews the certificates in the check interval until the context is done
+func (r *Rotator) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.CheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Rotate(ctx); err != nil {
+				log.Warnf("Failed to rotate the serving certificates: %v", err)
+			}
+		}
+	}
+}
+
+// Rotate renews the persisted CA and serving certificate if needed, serves the persisted serving certificate and
+// publishes the CA bundle. When another replica renewed the certificates concurrently, its certificates are used.
+func (r *Rotator) Rotate(ctx context.Context) error {
+	secrets := r.kubeclientset.CoreV1().Secrets(r.config.Namespace)
+	secret, err := secrets.Get(ctx, r.config.SecretName, metav1.GetOptions{})
+	create := k8serrors.IsNotFound(err)
+	if err != nil && !create {
+		return fmt.Errorf("failed to get serving certificates secret: %w", err)
+	}
+	if create {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: r.config.SecretName, Namespace: r.config.Namespace},
+			Type:       corev1.SecretTypeTLS,
+		}
+	}
+	renewed, err := r.renew(secret)
+	if err != nil {
+		return err
+	}
+	if renewed {
+		if create {
+			_, err = secrets.Create(ctx, secret, metav1.CreateOptions{})
+		} else {
+			_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+		}
+		if k8serrors.IsAlreadyExists(err) || k8serrors.IsConflict(err) {
+			secret, err = secrets.Get(ctx, r.config.SecretName, metav1.GetOptions{})
+		}
+		if err != nil {
+			return fmt.Errorf("failed to persist serving certificates: %w", err)
+		}
+		log.Infof("Renewed the serving certificates in secret %s/%s", r.config.Namespace, r.config.SecretName)
+	}
+
+	cert, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return fmt.Errorf("failed to load serving certificate: %w", err)
+	}
+	r.cert.Store(&cert)
+
+	bundle := caBundle(secret)
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(bundle) {
+		return errors.New("failed to load CA bundle")
+	}
+	r.caPool.Store(caPool)
+	if err := r.publishCABundle(ctx, bundle); err != nil {
+		return err
+	}
+	if r.config.InjectWebhooks {
+		return r.injectWebhooks(ctx, bundle)
+	}
+	return nil
+}
+
+// renew renews the CA and the serving certificate of the secret if they are invalid or about to expire, and returns
+// whether the secret was changed. A CA about to expire is renewed in two steps: the next CA is generated and published
+// in the CA bundle, and it replaces the CA and signs the serving certificate once the overlap period passed.
+func (r *Rotator) renew(secret *corev1.Secret) (bool, error) {
+	now := r.now()
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	renewed := false
+	caChanged := false
+	ca := &KeyPair{Cert: secret.Data[caCertKey], Key: secret.Data[caKeyKey]}
+	caCert, _, err := parseKeyPair(ca)
+	switch {
+	case err != nil || !now.Before(caCert.NotAfter):
+		// no client can trust a missing or expired CA, so that it is replaced right away
+		delete(secret.Data, previousCACertKey)
+		delete(secret.Data, nextCACertKey)
+		delete(secret.Data, nextCAKeyKey)
+		if ca, caCert, err = r.generateCA(now); err != nil {
+			return false, err
+		}
+		secret.Data[caCertKey] = ca.Cert
+		secret.Data[caKeyKey] = ca.Key
+		renewed, caChanged = true, true
+	case needsRenewal(caCert, now):
+		next := &KeyPair{Cert: secret.Data[nextCACertKey], Key: secret.Data[nextCAKeyKey]}
+		nextCert, _, err := parseKeyPair(next)
+		if err != nil || needsRenewal(nextCert, now) {
+			// the next CA is published in the CA bundle, and the serving certificate stays signed by the current CA
+			if next, _, err = r.generateCA(now); err != nil {
+				return false, err
+			}
+			secret.Data[nextCACertKey] = next.Cert
+			secret.Data[nextCAKeyKey] = next.Key
+			renewed = true
+		} else if !now.Before(nextCert.NotBefore.Add(clockSkew + r.config.CAOverlap)) {
+			// the previous CA is published until it expires, so that the clients trust the serving certificates of both
+			secret.Data[previousCACertKey] = ca.Cert
+			secret.Data[caCertKey] = next.Cert
+			secret.Data[caKeyKey] = next.Key
+			delete(secret.Data, nextCACertKey)
+			delete(secret.Data, nextCAKeyKey)
+			ca, caCert = next, nextCert
+			renewed, caChanged = true, true
+		}
+	default:
+		if previous, ok := secret.Data[previousCACertKey]; ok {
+			if previousCert, err := parseCertificate(previous); err != nil || !now.Before(previousCert.NotAfter) {
+				delete(secret.Data, previousCACertKey)
+				renewed = true
+			}
+		}
+	}
+
+	servingCert, err := parseCertificate(secret.Data[corev1.TLSCertKey])
+	if caChanged || err != nil || needsRenewal(servingCert, now) || servingCert.CheckSignatureFrom(caCert) != nil || !coversDNSNames(servingCert, r.config.DNSNames) {
+		serving, err := GenerateServingCert(ca, r.config.DNSNames, now, r.config.CertValidity)
+		if err != nil {
+			return false, err
+		}
+		secret.Data[corev1.TLSCertKey] = serving.Cert
+		secret.Data[corev1.TLSPrivateKeyKey] = serving.Key
+		renewed = true
+	}
+	return renewed, nil
+}
+
+// generateCA generates a CA and returns it along with its parsed certificate
+func (r *Rotator) generateCA(now time.Time) (*KeyPair, *x509.Certificate, error) {
+	ca, err := GenerateCA(caCommonName, now, r.config.CAValidity)
+	if err != nil {
+		return nil, nil, err
+	}
+	caCert, err := parseCertificate(ca.Cert)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ca, caCert, nil
+}
+
+// caBundle returns the PEM encoded CA, next CA and previous CA of the secret
+func caBundle(secret *corev1.Secret) []byte {
+	bundle := bytes.Clone(secret.Data[caCertKey])
+	bundle = append(bundle, secret.Data[nextCACertKey]...)
+	return append(bundle, secret.Data[previousCACertKey]...)
+}
+
+// publishCABundle creates or updates the ConfigMap of the CA bundle, if it changed
+func (r *Rotator) publishCABundle(ctx context.Context, bundle []byte) error {
+	configMaps := r.kubeclientset.CoreV1().ConfigMaps(r.config.Namespace)
+	cm, err := configMaps.Get(ctx, r.config.CABundleConfigMapName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: r.config.CABundleConfigMapName, Namespace: r.config.Namespace},
+			Data:       map[string]string{CABundleKey: string(bundle)},
+		}
+		_, err = configMaps.Create(ctx, cm, metav1.CreateOptions{})
+	} else if err == nil && cm.Data[CABundleKey] != string(bundle) {
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[CABundleKey] = string(bundle)
+		_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to publish CA bundle: %w", err)
+	}
+	return nil
+}
+
+// injectWebhooks sets the CA bundle of the webhooks of the labeled mutating and validating webhook configurations
+func (r *Rotator) injectWebhooks(ctx context.Context, bundle []byte) error {
+	opts := metav1.ListOptions{LabelSelector: InjectCABundleLabel + "=true"}
+	var errs []error
+	mutating := r.kubeclientset.AdmissionregistrationV1().MutatingWebhookConfigurations()
+	mutatingList, err := mutating.List(ctx, opts)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to list mutating webhook configurations: %w", err))
+	} else {
+		for i := range mutatingList.Items {
+			config := &mutatingList.Items[i]
+			changed := false
+			for j := range config.Webhooks {
+				changed = setCABundle(&config.Webhooks[j].ClientConfig.CABundle, bundle) || changed
+			}
+			if !changed {
+				continue
+			}
+			if _, err := mutating.Update(ctx, config, metav1.UpdateOptions{}); err != nil {
+				errs = append(errs, fmt.Errorf("failed to inject CA bundle into mutating webhook configuration %s: %w", config.Name, err))
+			}
+		}
+	}
+	validating := r.kubeclientset.AdmissionregistrationV1().ValidatingWebhookConfigurations()
+	validatingList, err := validating.List(ctx, opts)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to list validating webhook configurations: %w", err))
+	} else {
+		for i := range validatingList.Items {
+			config := &validatingList.Items[i]
+			changed := false
+			for j := range config.Webhooks {
+				changed = setCABundle(&config.Webhooks[j].ClientConfig.CABundle, bundle) || changed
+			}
+			if !changed {
+				continue
+			}
+			if _, err := validating.Update(ctx, config, metav1.UpdateOptions{}); err != nil {
+				errs = append(errs, fmt.Errorf("failed to inject CA bundle into validating webhook configuration %s: %w", config.Name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func setCABundle(caBundle *[]byte, bundle []byte) bool {
+	if bytes.Equal(*caBundle, bundle) {
+		return false
+	}
+	*caBundle = bundle
+	return true
+}
+
+// GetCertificate returns the current serving certificate, for tls.Config.GetCertificate
+func (r *Rotator) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cert := r.cert.Load()
+	if cert == nil {
+		return nil, errors.New("no serving certificate")
+	}
+	return cert, nil
+}
+
+// ClientTLSConfig returns a TLS configuration of the clients of the servers serving the certificates of the rotator,
+// which verifies the serving certificate against the current CA bundle and the first of the DNS names
+func (r *Rotator) ClientTLSConfig() *tls.Config {
+	var serverName string
+	if len(r.config.DNSNames) > 0 {
+		serverName = r.config.DNSNames[0]
+	}
+	return &tls.Config{
+		ServerName: serverName,
+		// the serving certificate is verified by VerifyConnection, since the CA bundle changes when the CA is rotated
+		InsecureSkipVerify: true,
+		VerifyConnection:   r.verifyConnection,
+		MinVersion:         tls.VersionTLS12,
+	}
+}
+
+func (r *Rotator) verifyConnection(state tls.ConnectionState) error {
+	caPool := r.caPool.Load()
+	if caPool == nil {
+		return errors.New("no CA bundle")
+	}
+	if len(state.PeerCertificates) == 0 {
+		return errors.New("no serving certificate")
+	}
+	opts := x509.VerifyOptions{Roots: caPool, Intermediates: x509.NewCertPool()}
+	if len(r.config.DNSNames) > 0 {
+		opts.DNSName = r.config.DNSNames[0]
+	}
+	for _, cert := range state.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err := state.PeerCertificates[0].Verify(opts)
+	return err
+}
+
+// TLSConfig returns a TLS configuration serving the current serving certificate, so that renewed certificates are
+// served without restarting the servers
+func (r *Rotator) TLSConfig() *tls.Config {
+	return &tls.Config{
+		GetCertificate: r.GetCertificate,
+		MinVersion:     tls.VersionTLS12,
+	}
+}
@@ -0,0 +1,218 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const namespace = "argo-rollouts"
+
+func newRotator(client *fake.Clientset, now time.Time) *Rotator {
+	r := NewRotator(client, Config{
+		Namespace:      namespace,
+		DNSNames:       DefaultDNSNames("argo-rollouts-metrics", namespace),
+		CertValidity:   90 * 24 * time.Hour,
+		CAValidity:     365 * 24 * time.Hour,
+		InjectWebhooks: true,
+	})
+	r.now = func() time.Time { return now }
+	return r
+}
+
+func getSecret(t *testing.T, client *fake.Clientset) *corev1.Secret {
+	secret, err := client.CoreV1().Secrets(namespace).Get(context.TODO(), DefaultSecretName, metav1.GetOptions{})
+	require.NoError(t, err)
+	return secret
+}
+
+func getCABundle(t *testing.T, client *fake.Clientset) string {
+	cm, err := client.CoreV1().ConfigMaps(namespace).Get(context.TODO(), DefaultCABundleConfigMapName, metav1.GetOptions{})
+	require.NoError(t, err)
+	return cm.Data[CABundleKey]
+}
+
+func TestGenerateServingCert(t *testing.T) {
+	now := time.Now()
+	ca, err := GenerateCA("test-ca", now, time.Hour)
+	require.NoError(t, err)
+	serving, err := GenerateServingCert(ca, []string{"example.com"}, now, time.Hour)
+	require.NoError(t, err)
+
+	caCert, err := parseCertificate(ca.Cert)
+	require.NoError(t, err)
+	servingCert, err := parseCertificate(serving.Cert)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+	_, err = servingCert.Verify(x509.VerifyOptions{DNSName: "example.com", Roots: pool})
+	assert.NoError(t, err)
+	assert.False(t, needsRenewal(servingCert, now))
+	assert.True(t, needsRenewal(servingCert, now.Add(45*time.Minute)))
+
+	_, err = GenerateServingCert(ca, nil, now, time.Hour)
+	assert.Error(t, err)
+}
+
+func TestRotate(t *testing.T) {
+	now := time.Now()
+	webhook := &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "argo-rollouts", Labels: map[string]string{InjectCABundleLabel: "true"}},
+		Webhooks:   []admissionregistrationv1.ValidatingWebhook{{Name: "rollouts.argoproj.io"}},
+	}
+	otherWebhook := &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "other"},
+		Webhooks:   []admissionregistrationv1.ValidatingWebhook{{Name: "other.example.com"}},
+	}
+	client := fake.NewSimpleClientset(webhook, otherWebhook)
+	r := newRotator(client, now)
+	_, err := r.GetCertificate(nil)
+	assert.Error(t, err)
+
+	require.NoError(t, r.Rotate(context.TODO()))
+	secret := getSecret(t, client)
+	assert.Equal(t, corev1.SecretTypeTLS, secret.Type)
+	bundle := getCABundle(t, client)
+	assert.Equal(t, string(secret.Data[caCertKey]), bundle)
+	cert, err := r.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"argo-rollouts-metrics", "argo-rollouts-metrics.argo-rollouts", "argo-rollouts-metrics.argo-rollouts.svc", "argo-rollouts-metrics.argo-rollouts.svc.cluster.local"}, cert.Leaf.DNSNames)
+
+	injected, err := client.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(context.TODO(), "argo-rollouts", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, bundle, string(injected.Webhooks[0].ClientConfig.CABundle))
+	other, err := client.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(context.TODO(), "other", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, other.Webhooks[0].ClientConfig.CABundle)
+
+	// the persisted certificates are reused by other replicas and after restarts
+	replica := newRotator(client, now.Add(time.Hour))
+	require.NoError(t, replica.Rotate(context.TODO()))
+	assert.Equal(t, secret.Data, getSecret(t, client).Data)
+	replicaCert, err := replica.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, cert.Certificate, replicaCert.Certificate)
+}
+
+func TestRotateRenewsServingCert(t *testing.T) {
+	now := time.Now()
+	client := fake.NewSimpleClientset()
+	require.NoError(t, newRotator(client, now).Rotate(context.TODO()))
+	secret := getSecret(t, client)
+
+	// the serving certificate is renewed once a third of its validity remains, signed by the same CA
+	r := newRotator(client, now.Add(61*24*time.Hour))
+	require.NoError(t, r.Rotate(context.TODO()))
+	renewed := getSecret(t, client)
+	assert.Equal(t, secret.Data[caCertKey], renewed.Data[caCertKey])
+	assert.NotEqual(t, secret.Data[corev1.TLSCertKey], renewed.Data[corev1.TLSCertKey])
+
+	// the serving certificate is renewed when the DNS names change
+	r.config.DNSNames = []string{"argo-rollouts.example.com"}
+	require.NoError(t, r.Rotate(context.TODO()))
+	cert, err := r.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"argo-rollouts.example.com"}, cert.Leaf.DNSNames)
+}
+
+func TestRotateRenewsCA(t *testing.T) {
+	now := time.Now()
+	client := fake.NewSimpleClientset()
+	require.NoError(t, newRotator(client, now).Rotate(context.TODO()))
+	secret := getSecret(t, client)
+
+	// the next CA is published in the CA bundle first, and the serving certificate stays signed by the current CA
+	require.NoError(t, newRotator(client, now.Add(250*24*time.Hour)).Rotate(context.TODO()))
+	published := getSecret(t, client)
+	assert.Equal(t, secret.Data[caCertKey], published.Data[caCertKey])
+	require.Contains(t, published.Data, nextCACertKey)
+	servingCert, err := parseCertificate(published.Data[corev1.TLSCertKey])
+	require.NoError(t, err)
+	caCert, err := parseCertificate(published.Data[caCertKey])
+	require.NoError(t, err)
+	assert.NoError(t, servingCert.CheckSignatureFrom(caCert))
+	assert.Equal(t, string(secret.Data[caCertKey])+string(published.Data[nextCACertKey]), getCABundle(t, client))
+
+	// the next CA is not used before the end of the overlap period
+	require.NoError(t, newRotator(client, now.Add(250*24*time.Hour+time.Hour)).Rotate(context.TODO()))
+	assert.Equal(t, published.Data, getSecret(t, client).Data)
+
+	// then it replaces the CA and signs the serving certificate, and the previous CA is published until it expires
+	require.NoError(t, newRotator(client, now.Add(251*24*time.Hour+time.Hour)).Rotate(context.TODO()))
+	renewed := getSecret(t, client)
+	assert.Equal(t, published.Data[nextCACertKey], renewed.Data[caCertKey])
+	assert.NotContains(t, renewed.Data, nextCACertKey)
+	assert.Equal(t, secret.Data[caCertKey], renewed.Data[previousCACertKey])
+	assert.Equal(t, string(renewed.Data[caCertKey])+string(secret.Data[caCertKey]), getCABundle(t, client))
+
+	servingCert, err = parseCertificate(renewed.Data[corev1.TLSCertKey])
+	require.NoError(t, err)
+	caCert, err = parseCertificate(renewed.Data[caCertKey])
+	require.NoError(t, err)
+	assert.NoError(t, servingCert.CheckSignatureFrom(caCert))
+
+	require.NoError(t, newRotator(client, now.Add(400*24*time.Hour)).Rotate(context.TODO()))
+	assert.NotContains(t, getSecret(t, client).Data, previousCACertKey)
+	assert.Equal(t, string(renewed.Data[caCertKey]), getCABundle(t, client))
+}
+
+func TestRotateReplacesExpiredCA(t *testing.T) {
+	now := time.Now()
+	client := fake.NewSimpleClientset()
+	require.NoError(t, newRotator(client, now).Rotate(context.TODO()))
+	secret := getSecret(t, client)
+
+	// no client trusts an expired CA, so that it is replaced without an overlap period
+	require.NoError(t, newRotator(client, now.Add(400*24*time.Hour)).Rotate(context.TODO()))
+	renewed := getSecret(t, client)
+	assert.NotEqual(t, secret.Data[caCertKey], renewed.Data[caCertKey])
+	assert.NotContains(t, renewed.Data, previousCACertKey)
+	assert.NotContains(t, renewed.Data, nextCACertKey)
+	assert.Equal(t, string(renewed.Data[caCertKey]), getCABundle(t, client))
+}
+
+func TestTLSConfig(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	r := newRotator(client, time.Now())
+	r.config.DNSNames = []string{"localhost"}
+	require.NoError(t, r.Rotate(context.TODO()))
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = r.TLSConfig()
+	server.StartTLS()
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM([]byte(getCABundle(t, client))))
+	httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool, ServerName: "localhost"}}}
+	resp, err := httpClient.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// the client configuration of the rotator verifies the serving certificate against the CA bundle
+	httpClient = &http.Client{Transport: &http.Transport{TLSClientConfig: r.ClientTLSConfig()}}
+	resp, err = httpClient.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	other := newRotator(fake.NewSimpleClientset(), time.Now())
+	other.config.DNSNames = []string{"localhost"}
+	require.NoError(t, other.Rotate(context.TODO()))
+	httpClient = &http.Client{Transport: &http.Transport{TLSClientConfig: other.ClientTLSConfig()}}
+	_, err = httpClient.Get(server.URL)
+	assert.Error(t, err)
+}
@@ -9,6 +9,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/rand"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
@@ -80,16 +81,42 @@ const (
 	// RolloutNotHealthyMessage is added when the rollout is completed and is healthy or not.
 	RolloutNotHealthyMessage = "Rollout is not healthy"
 
+	// NotificationDeliveredReason is added in a rollout when the last notification about it was delivered
+	NotificationDeliveredReason = "NotificationDelivered"
+	// NotificationDeliveredMessage is added when the last notification about a rollout was delivered
+	NotificationDeliveredMessage = "Notification to %s:%s delivered"
+	// NotificationDeliveryFailedReason is added in a rollout when the last notification about it failed to be delivered
+	NotificationDeliveryFailedReason = "NotificationDeliveryFailed"
+	// NotificationDeliveryFailedMessage is added when the last notification about a rollout failed to be delivered
+	NotificationDeliveryFailedMessage = "Notification to %s:%s failed to be delivered: %v"
+
+	// RolloutUserActionReason indicates that a user promoted, aborted or retried the rollout
+	RolloutUserActionReason = "RolloutUserAction"
+	// RolloutUserActionMessage indicates the action and the user
+	RolloutUserActionMessage = "%s performed by %s"
+
 	// RolloutAbortedReason indicates that the rollout was aborted
 	RolloutAbortedReason = "RolloutAborted"
 	// RolloutAbortedMessage indicates that the rollout was aborted
 	RolloutAbortedMessage = "Rollout aborted update to revision %d"
 
+	// RolloutDegradedReason indicates that the rollout made no progress before its progress deadline
+	RolloutDegradedReason = "RolloutDegraded"
+	// RolloutDegradedMessage indicates that the rollout made no progress before its progress deadline
+	RolloutDegradedMessage = "Rollout degraded: %s"
+
 	// RolloutRetryReason indicates that the rollout is retrying after being aborted
 	RolloutRetryReason = "RolloutRetry"
 	// RolloutRetryMessage indicates that the rollout is retrying after being aborted
 	RolloutRetryMessage = "Retrying Rollout after abort"
 
+	// RolloutProgressionPendingReason is added in a rollout when its update is held back, because the controller
+	// already progresses the maximum number of rollouts at once
+	RolloutProgressionPendingReason = "ProgressionPending"
+	// RolloutProgressionPendingMessage is added in a rollout when its update is held back, because the controller
+	// already progresses the maximum number of rollouts at once
+	RolloutProgressionPendingMessage = "Update is pending until fewer rollouts are progressing"
+
 	// RolloutPausedReason is added in a rollout when it is paused. Lack of progress shouldn't be
 	// estimated once a rollout is paused.
 	RolloutPausedReason = "RolloutPaused"
@@ -141,6 +168,9 @@ const (
 	// TimedOutReason is added in a rollout when its newest replica set fails to show any progress
 	// within the given deadline (progressDeadlineSeconds).
 	TimedOutReason = "ProgressDeadlineExceeded"
+	// StepProgressDeadlineExceededReason is added in a rollout when it is paused because a canary step
+	// exceeded its progress deadline
+	StepProgressDeadlineExceededReason = "StepProgressDeadlineExceeded"
 	// RolloutTimeOutMessage is added in a rollout when the rollout fails to show any progress
 	// within the given deadline (progressDeadlineSeconds).
 	RolloutTimeOutMessage = "Rollout %q has timed out progressing."
@@ -151,6 +181,79 @@ const (
 	ScalingReplicaSetReason  = "ScalingReplicaSet"
 	ScalingReplicaSetMessage = "Scaled %s ReplicaSet %s (revision %d) from %d to %d"
 
+	// ScaledObjectPausedReason is emitted when the autoscaling of a KEDA ScaledObject is paused for an update
+	ScaledObjectPausedReason  = "ScaledObjectPaused"
+	ScaledObjectPausedMessage = "Paused the autoscaling of ScaledObject %s at %d replicas"
+	// ScaledObjectResumedReason is emitted when the autoscaling of a KEDA ScaledObject is resumed after an update
+	ScaledObjectResumedReason  = "ScaledObjectResumed"
+	ScaledObjectResumedMessage = "Resumed the autoscaling of ScaledObject %s"
+
+	// LoadTestStartedReason is emitted when the Job of a load test step is started
+	LoadTestStartedReason  = "LoadTestStarted"
+	LoadTestStartedMessage = "Started load test Job %s of step %d"
+	// LoadTestFailedReason is emitted when the Job of a load test step which waits on the load test failed
+	LoadTestFailedReason  = "LoadTestFailed"
+	LoadTestFailedMessage = "Load test Job %s of step %d failed: %s"
+
+	// PreProvisionStartedReason is emitted when the placeholder ReplicaSet of a preProvision step is created
+	PreProvisionStartedReason  = "PreProvisionStarted"
+	PreProvisionStartedMessage = "Created placeholder ReplicaSet %s with %d pods to pre-provision capacity for step %d"
+	// PreProvisionTimedOutReason is emitted when the placeholder pods of a preProvision step were not scheduled in time
+	PreProvisionTimedOutReason  = "PreProvisionTimedOut"
+	PreProvisionTimedOutMessage = "Only %d of %d placeholder pods of step %d were scheduled within %s"
+
+	// ImagesVerifiedReason is emitted when the signatures of the images of a ReplicaSet were verified
+	ImagesVerifiedReason  = "ImagesVerified"
+	ImagesVerifiedMessage = "Verified the signatures of the images of ReplicaSet %s"
+	// ImageVerificationFailedReason is emitted when an image of a ReplicaSet is not signed by a trusted key or identity
+	ImageVerificationFailedReason  = "ImageVerificationFailed"
+	ImageVerificationFailedMessage = "Signature verification of ReplicaSet %s failed: %s"
+	// ProgressionDeniedReason is added in a rollout when a progression policy denies it to progress to its next step
+	ProgressionDeniedReason  = "ProgressionDenied"
+	ProgressionDeniedMessage = "Progression after step %d/%d denied: %s"
+	// PromotionDeniedMessage is the message of the ProgressionDenied condition when a progression policy denies a
+	// blue-green promotion or a full promotion
+	PromotionDeniedMessage = "Full promotion denied: %s"
+	// ProgressionAllowedReason is emitted when the progression policies allow a denied rollout to progress again
+	ProgressionAllowedReason  = "ProgressionAllowed"
+	ProgressionAllowedMessage = "Progression policies allow the rollout to progress"
+	// FeatureFlagSetReason is emitted when the percentage of a feature flag of a setFeatureFlag step is set
+	FeatureFlagSetReason  = "FeatureFlagSet"
+	FeatureFlagSetMessage = "Set feature flag %s to %d%%"
+	// FeatureFlagErrorReason is emitted when the percentage of a feature flag could not be set
+	FeatureFlagErrorReason  = "FeatureFlagError"
+	FeatureFlagErrorMessage = "Failed to set feature flag %s to %d%%: %s"
+	// MigrationStartedReason is emitted when the migrations of a migration step are started
+	MigrationStartedReason  = "MigrationStarted"
+	MigrationStartedMessage = "Started the migrations of step %d"
+	// MigrationCompletedReason is emitted when the migrations of a migration step are completed
+	MigrationCompletedReason  = "MigrationCompleted"
+	MigrationCompletedMessage = "Migrations of step %d completed"
+	// MigrationFailedReason is emitted when the migrations of a migration step failed or timed out
+	MigrationFailedReason  = "MigrationFailed"
+	MigrationFailedMessage = "Migrations of step %d failed: %s"
+	// MigrationRollbackStartedReason is emitted when the rollback Job of a migration step is started
+	MigrationRollbackStartedReason  = "MigrationRollbackStarted"
+	MigrationRollbackStartedMessage = "Started rollback Job %s of the migrations of step %d"
+	// MigrationRolledBackReason is emitted when the rollback Job of a migration step completed
+	MigrationRolledBackReason  = "MigrationRolledBack"
+	MigrationRolledBackMessage = "Rolled back the migrations of step %d"
+	// MigrationRollbackFailedReason is emitted when the rollback Job of a migration step failed
+	MigrationRollbackFailedReason  = "MigrationRollbackFailed"
+	MigrationRollbackFailedMessage = "Rollback Job %s of the migrations of step %d failed: %s"
+	// GateProceededReason is emitted when the webhook of a gate step decides to proceed
+	GateProceededReason  = "GateProceeded"
+	GateProceededMessage = "Gate of step %d proceeded: %s"
+	// GatePausedReason is emitted when the webhook of a gate step decides to pause the rollout
+	GatePausedReason  = "GatePaused"
+	GatePausedMessage = "Gate of step %d paused the rollout: %s"
+	// GateAbortedReason is emitted when the webhook of a gate step decides to abort the rollout
+	GateAbortedReason  = "GateAborted"
+	GateAbortedMessage = "Gate of step %d aborted the rollout: %s"
+	// GateErrorReason is emitted when a request to the webhook of a gate step fails
+	GateErrorReason  = "GateError"
+	GateErrorMessage = "Request to the gate of step %d failed: %v"
+
 	// ReplicaSetTimeOutMessage is added in a rollout when its newest replica set fails to show any progress
 	// within the given deadline (progressDeadlineSeconds).
 	ReplicaSetTimeOutMessage = "ReplicaSet %q has timed out progressing."
@@ -182,6 +285,12 @@ const (
 	// WeightVerifyErrorReason is emitted when there is an error verifying the set weight
 	WeightVerifyErrorReason  = "WeightVerifyError"
 	WeightVerifyErrorMessage = "Failed to verify weight: %s"
+	// WeightVerifiedReason is emitted when the traffic router verified that the desired weight took effect
+	WeightVerifiedReason  = "WeightVerified"
+	WeightVerifiedMessage = "Weight %d verified by %s"
+	// TrafficRouterUnhealthyReason is emitted when a traffic router which reports its health is unhealthy
+	TrafficRouterUnhealthyReason  = "TrafficRouterUnhealthy"
+	TrafficRouterUnhealthyMessage = "Traffic router %s is unhealthy: %s"
 	// LoadBalancerNotFoundReason is emitted when load balancer can not be found
 	LoadBalancerNotFoundReason  = "LoadBalancerNotFound"
 	LoadBalancerNotFoundMessage = "Failed to find load balancer: %s"
@@ -338,6 +447,16 @@ func ComputeStepHash(rollout *v1alpha1.Rollout) string {
 // RolloutTimedOut considers a rollout to have timed out once its condition that reports progress
 // is older than progressDeadlineSeconds or a Progressing condition with a TimedOutReason reason already
 // exists.
+// ProgressDeadlineStart returns the time the progress deadline of a rollout is measured from. A canary
+// step with its own progress deadline is measured from the start of the step, which is not reset by the
+// updates of the Progressing condition. Otherwise, it is the last update of the Progressing condition.
+func ProgressDeadlineStart(status *v1alpha1.RolloutStatus, progressing v1alpha1.RolloutCondition) metav1.Time {
+	if status.Canary.CurrentStepStartedAt != nil {
+		return *status.Canary.CurrentStepStartedAt
+	}
+	return progressing.LastUpdateTime
+}
+
 func RolloutTimedOut(rollout *v1alpha1.Rollout, newStatus *v1alpha1.RolloutStatus) bool {
 	// Look for the Progressing condition. If it doesn't exist, we have no base to estimate progress.
 	// If it's already set with a TimedOutReason reason, we have already timed out, no need to check
@@ -355,12 +474,12 @@ func RolloutTimedOut(rollout *v1alpha1.Rollout, newStatus *v1alpha1.RolloutStatu
 	}
 
 	// Look at the difference in seconds between now and the last time we reported any
-	// progress or tried to create a replica set, or resumed a paused rollout and
-	// compare against progressDeadlineSeconds.
-	from := condition.LastUpdateTime
+	// progress or tried to create a replica set, or resumed a paused rollout (or the start of
+	// a step with its own progress deadline) and compare against progressDeadlineSeconds.
+	from := ProgressDeadlineStart(newStatus, *condition)
 	now := timeutil.Now()
 
-	progressDeadlineSeconds := defaults.GetProgressDeadlineSecondsOrDefault(rollout)
+	progressDeadlineSeconds := defaults.GetCurrentStepProgressDeadlineSecondsOrDefault(rollout)
 	delta := time.Duration(progressDeadlineSeconds) * time.Second
 	timedOut := from.Add(delta).Before(now)
 	logCtx := logutil.WithRollout(rollout)
@@ -23,6 +23,50 @@ const (
 	ExperimentCompletedMessage = "Experiment %q has successfully ran and completed."
 	// ExperimentCompleteReason is added when the experiment is completed
 	ExperimentCompleteReason = "ExperimentCompleted"
+	// ExperimentPausedReason is emitted when an experiment is paused
+	ExperimentPausedReason = "ExperimentPaused"
+	// ExperimentResumedReason is emitted when a paused experiment is resumed
+	ExperimentResumedReason = "ExperimentResumed"
+	// ExperimentWinnerSelectedReason is emitted when the template which won an experiment is selected
+	ExperimentWinnerSelectedReason = "ExperimentWinnerSelected"
+	// ExperimentWinnerSelectedMessage is emitted when the template which won an experiment is selected
+	ExperimentWinnerSelectedMessage = "Template '%s' won the experiment"
+	// ExperimentNoWinnerReason is emitted when no template won an experiment
+	ExperimentNoWinnerReason = "ExperimentNoWinner"
+	// ExperimentNoWinnerMessage is the message of an experiment which is inconclusive since no template won it
+	ExperimentNoWinnerMessage = "No template won the experiment: %v"
+	// ExperimentWinnerCandidateTemplateMessage indicates a winner candidate references a template which does not exist
+	ExperimentWinnerCandidateTemplateMessage = "Experiment %s winner candidate references the template '%s' which does not exist"
+	// ExperimentWinnerCandidateAnalysisMessage indicates a winner candidate references an analysis which does not exist
+	ExperimentWinnerCandidateAnalysisMessage = "Experiment %s winner candidate references the analysis '%s' which does not exist"
+	// ExperimentWinnerSelectionGoalMessage indicates the goal of the winner selection is invalid
+	ExperimentWinnerSelectionGoalMessage = "Experiment %s winner selection has the invalid goal '%s', must be Maximize or Minimize"
+	// HorizontalPodAutoscalerCreatedReason is emitted when the HorizontalPodAutoscaler of an experiment template is created
+	HorizontalPodAutoscalerCreatedReason = "HorizontalPodAutoscalerCreated"
+	// HorizontalPodAutoscalerCreatedMessage is the message of a created HorizontalPodAutoscaler
+	HorizontalPodAutoscalerCreatedMessage = "Created HorizontalPodAutoscaler %s for template '%s'"
+	// HorizontalPodAutoscalerDeletedReason is emitted when the HorizontalPodAutoscaler of an experiment template is deleted
+	HorizontalPodAutoscalerDeletedReason = "HorizontalPodAutoscalerDeleted"
+	// HorizontalPodAutoscalerDeletedMessage is the message of a deleted HorizontalPodAutoscaler
+	HorizontalPodAutoscalerDeletedMessage = "Deleted HorizontalPodAutoscaler %s of template '%s'"
+	// ExperimentInvalidAutoscalingMessage indicates the replica limits of the autoscaling of a template are invalid
+	ExperimentInvalidAutoscalingMessage = "Experiment %s has invalid autoscaling for template '%s': maxReplicas must be at least minReplicas, which must be at least 1"
+	// ExperimentResultExportedReason is emitted when the result of a completed experiment is exported
+	ExperimentResultExportedReason = "ExperimentResultExported"
+	// ExperimentResultExportedMessage is the message of an exported experiment result
+	ExperimentResultExportedMessage = "Exported experiment result with the %s exporter"
+	// ExperimentResultExportFailedReason is emitted when the result of a completed experiment fails to be exported
+	ExperimentResultExportFailedReason = "ExperimentResultExportFailed"
+	// ExperimentResultExportFailedMessage is the message of a failed export of an experiment result
+	ExperimentResultExportFailedMessage = "Failed to export experiment result with the %s exporter: %v"
+	// ExperimentScheduledReason is emitted when an experiment waits for the start scheduled by its startAt and window
+	ExperimentScheduledReason = "ExperimentScheduled"
+	// ExperimentScheduledMessage is the message of an experiment which waits for its scheduled start
+	ExperimentScheduledMessage = "Experiment is scheduled to start at %s"
+	// ExperimentInvalidDurationMessage indicates the duration or the extension of the experiment is not a duration
+	ExperimentInvalidDurationMessage = "Experiment %s has the invalid %s '%s', must be a duration string (e.g. 30s, 5m, 1h)"
+	// ExperimentInvalidWindowMessage indicates the window of the experiment is invalid
+	ExperimentInvalidWindowMessage = "Experiment %s has an invalid window: %v"
 	// ExperimentTemplateNameRepeatedMessage message when name in spec.template is repeated
 	ExperimentTemplateNameRepeatedMessage = "Experiment %s has repeated template name '%s' in templates"
 	// ExperimentTemplateNameEmpty message when name in template is empty
@@ -31,6 +75,10 @@ const (
 	DurationLongerThanDeadlineMessage = "Duration cannot be longer than ProgressDeadlineSeconds"
 	// ExperimentSelectAllMessage the message to indicate that the rollout has an empty selector
 	ExperimentSelectAllMessage = "This experiment is selecting all pods at index %d. A non-empty selector is required."
+	// ExperimentTemplateWithWorkloadRefMessage indicates a template sets both a pod template and a workload reference
+	ExperimentTemplateWithWorkloadRefMessage = "Experiment %s template '%s' must not set the template when it references a workload"
+	// ExperimentUnsupportedWorkloadRefMessage indicates a template references a workload of an unsupported kind
+	ExperimentUnsupportedWorkloadRefMessage = "Experiment %s template '%s' references the unsupported workload kind '%s', must be Deployment or Rollout"
 	// ExperimentMinReadyLongerThanDeadlineMessage indicates the MinReadySeconds is longer than ProgressDeadlineSeconds
 	ExperimentMinReadyLongerThanDeadlineMessage = "MinReadySeconds cannot be longer than ProgressDeadlineSeconds. Check template index %d"
 )
@@ -139,6 +187,16 @@ func VerifyExperimentSpec(experiment *v1alpha1.Experiment, prevCond *v1alpha1.Ex
 	templateNameSet := make(map[string]bool)
 	for i := range experiment.Spec.Templates {
 		template := experiment.Spec.Templates[i]
+		if template.WorkloadRef != nil {
+			if !reflect.DeepEqual(template.Template, corev1.PodTemplateSpec{}) {
+				message := fmt.Sprintf(ExperimentTemplateWithWorkloadRefMessage, experiment.Name, template.Name)
+				return newInvalidSpecExperimentCondition(prevCond, InvalidSpecReason, message)
+			}
+			if template.WorkloadRef.Kind != "Deployment" && template.WorkloadRef.Kind != "Rollout" {
+				message := fmt.Sprintf(ExperimentUnsupportedWorkloadRefMessage, experiment.Name, template.Name, template.WorkloadRef.Kind)
+				return newInvalidSpecExperimentCondition(prevCond, InvalidSpecReason, message)
+			}
+		}
 		if template.Selector == nil {
 			missingFieldPath := fmt.Sprintf(".Spec.Templates[%d].Selector", i)
 			message := fmt.Sprintf(MissingFieldMessage, missingFieldPath)
@@ -165,6 +223,88 @@ func VerifyExperimentSpec(experiment *v1alpha1.Experiment, prevCond *v1alpha1.Ex
 			return newInvalidSpecExperimentCondition(prevCond, InvalidSpecReason, message)
 		}
 		templateNameSet[template.Name] = true
+
+		if autoscaling := template.Autoscaling; autoscaling != nil {
+			minReplicas := defaults.GetReplicasOrDefault(template.Replicas)
+			if autoscaling.MinReplicas != nil {
+				minReplicas = *autoscaling.MinReplicas
+			}
+			if minReplicas < 1 || autoscaling.MaxReplicas < minReplicas {
+				message := fmt.Sprintf(ExperimentInvalidAutoscalingMessage, experiment.Name, template.Name)
+				return newInvalidSpecExperimentCondition(prevCond, InvalidSpecReason, message)
+			}
+		}
+	}
+	// an invalid duration or extension would keep the experiment from ever passing its duration
+	for _, field := range []struct {
+		name     string
+		duration v1alpha1.DurationString
+	}{{"duration", experiment.Spec.Duration}, {"extension", experiment.Spec.Extension}} {
+		if field.duration == "" {
+			continue
+		}
+		if d, err := field.duration.Duration(); err != nil || d < 0 {
+			message := fmt.Sprintf(ExperimentInvalidDurationMessage, experiment.Name, field.name, field.duration)
+			return newInvalidSpecExperimentCondition(prevCond, InvalidSpecReason, message)
+		}
+	}
+	if window := experiment.Spec.Window; window != nil {
+		if err := verifyExperimentWindow(experiment, *window); err != nil {
+			message := fmt.Sprintf(ExperimentInvalidWindowMessage, experiment.Name, err)
+			return newInvalidSpecExperimentCondition(prevCond, InvalidSpecReason, message)
+		}
+	}
+	if selection := experiment.Spec.WinnerSelection; selection != nil {
+		return verifyExperimentWinnerSelection(experiment, *selection, templateNameSet, prevCond)
+	}
+	return nil
+}
+
+func verifyExperimentWindow(experiment *v1alpha1.Experiment, window v1alpha1.ExperimentWindow) error {
+	if _, err := experimentutil.ParseWindowSchedule(window); err != nil {
+		return err
+	}
+	duration, err := window.Duration.Duration()
+	if err != nil {
+		return err
+	}
+	if duration <= 0 {
+		return fmt.Errorf("duration must be positive")
+	}
+	// the experiment only starts in a window if it can run for its duration before the window ends
+	if experiment.Spec.Duration != "" {
+		experimentDuration, err := experiment.Spec.Duration.Duration()
+		if err == nil && experimentDuration >= duration {
+			return fmt.Errorf("duration %s must be longer than the duration of the experiment (%s)", window.Duration, experiment.Spec.Duration)
+		}
+	}
+	return nil
+}
+
+func verifyExperimentWinnerSelection(experiment *v1alpha1.Experiment, selection v1alpha1.ExperimentWinnerSelection, templateNameSet map[string]bool, prevCond *v1alpha1.ExperimentCondition) *v1alpha1.ExperimentCondition {
+	if len(selection.Candidates) == 0 {
+		message := fmt.Sprintf(MissingFieldMessage, ".Spec.WinnerSelection.Candidates")
+		return newInvalidSpecExperimentCondition(prevCond, InvalidSpecReason, message)
+	}
+	switch selection.Goal {
+	case "", v1alpha1.WinnerSelectionGoalMaximize, v1alpha1.WinnerSelectionGoalMinimize:
+	default:
+		message := fmt.Sprintf(ExperimentWinnerSelectionGoalMessage, experiment.Name, selection.Goal)
+		return newInvalidSpecExperimentCondition(prevCond, InvalidSpecReason, message)
+	}
+	analysisNameSet := make(map[string]bool)
+	for _, analysis := range experiment.Spec.Analyses {
+		analysisNameSet[analysis.Name] = true
+	}
+	for _, candidate := range selection.Candidates {
+		if !templateNameSet[candidate.TemplateName] {
+			message := fmt.Sprintf(ExperimentWinnerCandidateTemplateMessage, experiment.Name, candidate.TemplateName)
+			return newInvalidSpecExperimentCondition(prevCond, InvalidSpecReason, message)
+		}
+		if !analysisNameSet[candidate.AnalysisName] {
+			message := fmt.Sprintf(ExperimentWinnerCandidateAnalysisMessage, experiment.Name, candidate.AnalysisName)
+			return newInvalidSpecExperimentCondition(prevCond, InvalidSpecReason, message)
+		}
 	}
 	return nil
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 )
@@ -360,6 +361,32 @@ func TestVerifyExperimentSpecBaseCases(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf(MissingFieldMessage, missingField), noSelectorCond.Message)
 	assert.Equal(t, InvalidSpecReason, noSelectorCond.Reason)
 
+	workloadRef := ex.DeepCopy()
+	workloadRef.Spec.Templates[0].Template = v1.PodTemplateSpec{}
+	workloadRef.Spec.Templates[0].WorkloadRef = &v1alpha1.ExperimentWorkloadRef{APIVersion: "apps/v1", Kind: "Deployment", Name: "guestbook"}
+	assert.Nil(t, VerifyExperimentSpec(workloadRef, nil))
+
+	// the selector of a template which references a workload is required as well
+	workloadRefWithoutSelector := workloadRef.DeepCopy()
+	workloadRefWithoutSelector.Spec.Templates[0].Selector = nil
+	workloadRefWithoutSelectorCond := VerifyExperimentSpec(workloadRefWithoutSelector, nil)
+	assert.NotNil(t, workloadRefWithoutSelectorCond)
+	assert.Equal(t, fmt.Sprintf(MissingFieldMessage, missingField), workloadRefWithoutSelectorCond.Message)
+
+	workloadRefWithTemplate := workloadRef.DeepCopy()
+	workloadRefWithTemplate.Spec.Templates[0].Template.Spec.Containers = []v1.Container{{Image: "guestbook"}}
+	workloadRefWithTemplateCond := VerifyExperimentSpec(workloadRefWithTemplate, nil)
+	assert.NotNil(t, workloadRefWithTemplateCond)
+	assert.Equal(t, fmt.Sprintf(ExperimentTemplateWithWorkloadRefMessage, "foo", "test"), workloadRefWithTemplateCond.Message)
+	assert.Equal(t, InvalidSpecReason, workloadRefWithTemplateCond.Reason)
+
+	unsupportedWorkloadRef := workloadRef.DeepCopy()
+	unsupportedWorkloadRef.Spec.Templates[0].WorkloadRef.Kind = "StatefulSet"
+	unsupportedWorkloadRefCond := VerifyExperimentSpec(unsupportedWorkloadRef, nil)
+	assert.NotNil(t, unsupportedWorkloadRefCond)
+	assert.Equal(t, fmt.Sprintf(ExperimentUnsupportedWorkloadRefMessage, "foo", "test", "StatefulSet"), unsupportedWorkloadRefCond.Message)
+	assert.Equal(t, InvalidSpecReason, unsupportedWorkloadRefCond.Reason)
+
 	minReadyLongerThanProgressDeadline := ex.DeepCopy()
 	minReadyLongerThanProgressDeadline.Spec.Templates[0].MinReadySeconds = 1000
 	minReadyLongerThanProgressDeadlineCond := VerifyExperimentSpec(minReadyLongerThanProgressDeadline, nil)
@@ -376,3 +403,171 @@ func TestVerifyExperimentSpecBaseCases(t *testing.T) {
 	assert.Equal(t, InvalidSpecReason, sameInvalidSpec.Reason)
 	assert.NotEqual(t, prevLastUpdateTime, sameInvalidSpec.LastUpdateTime)
 }
+
+func TestVerifyExperimentSpecWinnerSelection(t *testing.T) {
+	ex := &v1alpha1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "foo",
+		},
+		Spec: v1alpha1.ExperimentSpec{
+			Templates: []v1alpha1.TemplateSpec{{
+				Name: "test",
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"key": "value"},
+				},
+			}},
+			Analyses: []v1alpha1.ExperimentAnalysisTemplateRef{{
+				Name:         "test-analysis",
+				TemplateName: "success-rate",
+			}},
+			WinnerSelection: &v1alpha1.ExperimentWinnerSelection{
+				Candidates: []v1alpha1.ExperimentWinnerCandidate{{TemplateName: "test", AnalysisName: "test-analysis"}},
+			},
+		},
+	}
+	assert.Nil(t, VerifyExperimentSpec(ex, nil))
+
+	noCandidates := ex.DeepCopy()
+	noCandidates.Spec.WinnerSelection.Candidates = nil
+	noCandidatesCond := VerifyExperimentSpec(noCandidates, nil)
+	assert.NotNil(t, noCandidatesCond)
+	assert.Equal(t, fmt.Sprintf(MissingFieldMessage, ".Spec.WinnerSelection.Candidates"), noCandidatesCond.Message)
+
+	invalidGoal := ex.DeepCopy()
+	invalidGoal.Spec.WinnerSelection.Goal = "Optimize"
+	invalidGoalCond := VerifyExperimentSpec(invalidGoal, nil)
+	assert.NotNil(t, invalidGoalCond)
+	assert.Equal(t, fmt.Sprintf(ExperimentWinnerSelectionGoalMessage, "foo", "Optimize"), invalidGoalCond.Message)
+
+	missingTemplate := ex.DeepCopy()
+	missingTemplate.Spec.WinnerSelection.Candidates[0].TemplateName = "missing"
+	missingTemplateCond := VerifyExperimentSpec(missingTemplate, nil)
+	assert.NotNil(t, missingTemplateCond)
+	assert.Equal(t, fmt.Sprintf(ExperimentWinnerCandidateTemplateMessage, "foo", "missing"), missingTemplateCond.Message)
+
+	missingAnalysis := ex.DeepCopy()
+	missingAnalysis.Spec.WinnerSelection.Candidates[0].AnalysisName = "missing"
+	missingAnalysisCond := VerifyExperimentSpec(missingAnalysis, nil)
+	assert.NotNil(t, missingAnalysisCond)
+	assert.Equal(t, fmt.Sprintf(ExperimentWinnerCandidateAnalysisMessage, "foo", "missing"), missingAnalysisCond.Message)
+	assert.Equal(t, InvalidSpecReason, missingAnalysisCond.Reason)
+}
+
+func TestVerifyExperimentSpecWindow(t *testing.T) {
+	ex := &v1alpha1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "foo",
+		},
+		Spec: v1alpha1.ExperimentSpec{
+			Templates: []v1alpha1.TemplateSpec{{
+				Name: "test",
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"key": "value"},
+				},
+			}},
+			Window: &v1alpha1.ExperimentWindow{
+				Schedule: "0 9 * * 1-5",
+				Duration: "8h",
+				TimeZone: "Europe/Berlin",
+			},
+		},
+	}
+	assert.Nil(t, VerifyExperimentSpec(ex, nil))
+
+	invalidSchedule := ex.DeepCopy()
+	invalidSchedule.Spec.Window.Schedule = "every morning"
+	invalidScheduleCond := VerifyExperimentSpec(invalidSchedule, nil)
+	assert.NotNil(t, invalidScheduleCond)
+	assert.Contains(t, invalidScheduleCond.Message, "Experiment foo has an invalid window")
+
+	invalidTimeZone := ex.DeepCopy()
+	invalidTimeZone.Spec.Window.TimeZone = "Mars/Olympus"
+	assert.NotNil(t, VerifyExperimentSpec(invalidTimeZone, nil))
+
+	invalidDuration := ex.DeepCopy()
+	invalidDuration.Spec.Window.Duration = "0s"
+	invalidDurationCond := VerifyExperimentSpec(invalidDuration, nil)
+	assert.NotNil(t, invalidDurationCond)
+	assert.Equal(t, "Experiment foo has an invalid window: duration must be positive", invalidDurationCond.Message)
+
+	shortExperiment := ex.DeepCopy()
+	shortExperiment.Spec.Duration = "2h"
+	assert.Nil(t, VerifyExperimentSpec(shortExperiment, nil))
+
+	// the experiment could never run for its duration within a window
+	longExperiment := ex.DeepCopy()
+	longExperiment.Spec.Duration = "8h"
+	longExperimentCond := VerifyExperimentSpec(longExperiment, nil)
+	assert.NotNil(t, longExperimentCond)
+	assert.Equal(t, "Experiment foo has an invalid window: duration 8h must be longer than the duration of the experiment (8h)", longExperimentCond.Message)
+
+	// the extension of a started experiment may exceed the window
+	extendedExperiment := shortExperiment.DeepCopy()
+	extendedExperiment.Spec.Extension = "10h"
+	assert.Nil(t, VerifyExperimentSpec(extendedExperiment, nil))
+}
+
+func TestVerifyExperimentSpecExtension(t *testing.T) {
+	ex := &v1alpha1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "foo",
+		},
+		Spec: v1alpha1.ExperimentSpec{
+			Templates: []v1alpha1.TemplateSpec{{
+				Name: "test",
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"key": "value"},
+				},
+			}},
+			Duration:  "1h",
+			Extension: "30m",
+		},
+	}
+	assert.Nil(t, VerifyExperimentSpec(ex, nil))
+
+	invalidExtension := ex.DeepCopy()
+	invalidExtension.Spec.Extension = "1m-typo"
+	invalidExtensionCond := VerifyExperimentSpec(invalidExtension, nil)
+	assert.NotNil(t, invalidExtensionCond)
+	assert.Equal(t, "Experiment foo has the invalid extension '1m-typo', must be a duration string (e.g. 30s, 5m, 1h)", invalidExtensionCond.Message)
+
+	negativeExtension := ex.DeepCopy()
+	negativeExtension.Spec.Extension = "-5m"
+	assert.NotNil(t, VerifyExperimentSpec(negativeExtension, nil))
+
+	invalidDuration := ex.DeepCopy()
+	invalidDuration.Spec.Duration = "forever"
+	invalidDurationCond := VerifyExperimentSpec(invalidDuration, nil)
+	assert.NotNil(t, invalidDurationCond)
+	assert.Equal(t, "Experiment foo has the invalid duration 'forever', must be a duration string (e.g. 30s, 5m, 1h)", invalidDurationCond.Message)
+}
+
+func TestVerifyExperimentSpecAutoscaling(t *testing.T) {
+	ex := &v1alpha1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "foo",
+		},
+		Spec: v1alpha1.ExperimentSpec{
+			Templates: []v1alpha1.TemplateSpec{{
+				Name: "test",
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"key": "value"},
+				},
+				Autoscaling: &v1alpha1.TemplateAutoscaling{MaxReplicas: 3},
+			}},
+		},
+	}
+	assert.Nil(t, VerifyExperimentSpec(ex, nil))
+
+	maxBelowMin := ex.DeepCopy()
+	maxBelowMin.Spec.Templates[0].Autoscaling.MinReplicas = ptr.To[int32](5)
+	maxBelowMinCond := VerifyExperimentSpec(maxBelowMin, nil)
+	assert.NotNil(t, maxBelowMinCond)
+	assert.Equal(t, fmt.Sprintf(ExperimentInvalidAutoscalingMessage, "foo", "test"), maxBelowMinCond.Message)
+
+	zeroReplicas := ex.DeepCopy()
+	zeroReplicas.Spec.Templates[0].Replicas = ptr.To[int32](0)
+	assert.NotNil(t, VerifyExperimentSpec(zeroReplicas, nil))
+	zeroReplicas.Spec.Templates[0].Autoscaling.MinReplicas = ptr.To[int32](1)
+	assert.Nil(t, VerifyExperimentSpec(zeroReplicas, nil))
+}
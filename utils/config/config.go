@@ -3,8 +3,10 @@ package config
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"regexp"
 	"slices"
+	"strings"
 	"sync"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -12,8 +14,10 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	k8errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"sigs.k8s.io/yaml"
 
+	"github.com/argoproj/argo-rollouts/utils/cosign"
 	"github.com/argoproj/argo-rollouts/utils/defaults"
 	"github.com/argoproj/argo-rollouts/utils/plugin/types"
 )
@@ -25,6 +29,59 @@ type Config struct {
 	lock      *sync.RWMutex
 }
 
+// ExperimentResultExport configures the export of the results of completed experiments, e.g. to an experimentation
+// platform. It is defined under the experimentResultExport key of the configmap.
+type ExperimentResultExport struct {
+	// Webhook posts the results to an HTTP endpoint
+	Webhook *WebhookResultExport `json:"webhook,omitempty"`
+	// S3 uploads the results to an S3 bucket, or to a bucket of an S3 compatible object store
+	S3 *S3ResultExport `json:"s3,omitempty"`
+	// GCS uploads the results to a Google Cloud Storage bucket
+	GCS *GCSResultExport `json:"gcs,omitempty"`
+}
+
+// WebhookResultExport posts the results of experiments as JSON documents to an HTTP endpoint
+type WebhookResultExport struct {
+	// URL of the endpoint
+	URL string `json:"url"`
+	// Headers are added to the requests
+	Headers []WebhookResultExportHeader `json:"headers,omitempty"`
+	// TimeoutSeconds is the timeout of the requests. Defaults to 10 seconds.
+	TimeoutSeconds int64 `json:"timeoutSeconds,omitempty"`
+}
+
+// WebhookResultExportHeader is a header of the requests of a webhook. Its value is either set or read from a secret in
+// the namespace of the controller.
+type WebhookResultExportHeader struct {
+	Key          string                `json:"key"`
+	Value        string                `json:"value,omitempty"`
+	SecretKeyRef *v1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+}
+
+// S3ResultExport uploads the results of experiments as JSON documents to an S3 bucket. The credentials are resolved
+// from the environment of the controller.
+type S3ResultExport struct {
+	// Bucket is the name of the bucket
+	Bucket string `json:"bucket"`
+	// Prefix is prepended to the keys of the documents
+	Prefix string `json:"prefix,omitempty"`
+	// Region of the bucket
+	Region string `json:"region,omitempty"`
+	// RoleARN is the role assumed to upload the documents
+	RoleARN string `json:"roleArn,omitempty"`
+	// Endpoint is the URL of an S3 compatible object store
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// GCSResultExport uploads the results of experiments as JSON documents to a Google Cloud Storage bucket. The
+// credentials are resolved from the environment of the controller (application default credentials).
+type GCSResultExport struct {
+	// Bucket is the name of the bucket
+	Bucket string `json:"bucket"`
+	// Prefix is prepended to the names of the documents
+	Prefix string `json:"prefix,omitempty"`
+}
+
 var configMemoryCache *Config
 var mutex = &sync.RWMutex{}
 
@@ -46,44 +103,113 @@ func InitializeConfig(k8sClientset kubernetes.Interface, configMapName string) (
 		return nil, fmt.Errorf("failed to get configmap %s/%s: %w", defaults.Namespace(), configMapName, err)
 	}
 
+	plugins, err := parsePlugins(configMapCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	mutex.Lock()
+	configMemoryCache = &Config{
+		configMap: configMapCluster,
+		plugins:   plugins,
+		lock:      &sync.RWMutex{},
+	}
+	mutex.Unlock()
+
+	err = configMemoryCache.ValidateConfig()
+	if err != nil {
+		return nil, fmt.Errorf("validation of config due to (%w)", err)
+	}
+
+	return configMemoryCache, nil
+}
+
+// ParseConfig returns the config of the configmap, or an empty config if the configmap is nil, without replacing the
+// in memory config. It also returns the plugins which differ from the in memory config, i.e. which were added, changed
+// or removed, with their previous definition if they were removed.
+func ParseConfig(configMap *v1.ConfigMap) (*Config, []types.PluginItem, error) {
+	config := &Config{
+		configMap: configMap,
+		lock:      &sync.RWMutex{},
+	}
+	if configMap != nil {
+		plugins, err := parsePlugins(configMap)
+		if err != nil {
+			return nil, nil, err
+		}
+		config.plugins = plugins
+	}
+	if err := config.ValidateConfig(); err != nil {
+		return nil, nil, fmt.Errorf("validation of config due to (%w)", err)
+	}
+
+	mutex.RLock()
+	previous := configMemoryCache
+	mutex.RUnlock()
+
+	var previousPlugins []types.PluginItem
+	if previous != nil {
+		previousPlugins = previous.GetAllPlugins()
+	}
+	return config, changedPlugins(previousPlugins, config.GetAllPlugins()), nil
+}
+
+// SetConfig replaces the in memory config with a config returned by ParseConfig
+func SetConfig(config *Config) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	configMemoryCache = config
+}
+
+// parsePlugins returns the plugins of every type defined in the configmap
+func parsePlugins(configMap *v1.ConfigMap) ([]types.PluginItem, error) {
 	var trafficRouterPlugins []types.PluginItem
-	if err = yaml.Unmarshal([]byte(configMapCluster.Data["trafficRouterPlugins"]), &trafficRouterPlugins); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal traffic router plugins while initializing: %w", err)
+	if err := yaml.Unmarshal([]byte(configMap.Data["trafficRouterPlugins"]), &trafficRouterPlugins); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal traffic router plugins: %w", err)
 	}
 	for i := range trafficRouterPlugins {
 		trafficRouterPlugins[i].Type = types.PluginTypeTrafficRouter
 	}
 
 	var metricProviderPlugins []types.PluginItem
-	if err = yaml.Unmarshal([]byte(configMapCluster.Data["metricProviderPlugins"]), &metricProviderPlugins); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal metric provider plugins while initializing: %w", err)
+	if err := yaml.Unmarshal([]byte(configMap.Data["metricProviderPlugins"]), &metricProviderPlugins); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal metric provider plugins: %w", err)
 	}
 	for i := range metricProviderPlugins {
 		metricProviderPlugins[i].Type = types.PluginTypeMetricProvider
 	}
 
 	var stepPlugins []types.PluginItem
-	if err = yaml.Unmarshal([]byte(configMapCluster.Data["stepPlugins"]), &stepPlugins); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal step plugins while initializing: %w", err)
+	if err := yaml.Unmarshal([]byte(configMap.Data["stepPlugins"]), &stepPlugins); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal step plugins: %w", err)
 	}
 	for i := range stepPlugins {
 		stepPlugins[i].Type = types.PluginTypeStep
 	}
 
-	mutex.Lock()
-	configMemoryCache = &Config{
-		configMap: configMapCluster,
-		plugins:   slices.Concat(trafficRouterPlugins, metricProviderPlugins, stepPlugins),
-		lock:      &sync.RWMutex{},
-	}
-	mutex.Unlock()
+	return slices.Concat(trafficRouterPlugins, metricProviderPlugins, stepPlugins), nil
+}
 
-	err = configMemoryCache.ValidateConfig()
-	if err != nil {
-		return nil, fmt.Errorf("validation of config due to (%w)", err)
+// changedPlugins returns the plugins of current which are not in previous or have another definition, followed by the
+// plugins of previous which were removed
+func changedPlugins(previous, current []types.PluginItem) []types.PluginItem {
+	var changed []types.PluginItem
+	for _, plugin := range current {
+		i := slices.IndexFunc(previous, func(p types.PluginItem) bool {
+			return p.Name == plugin.Name && p.Type == plugin.Type
+		})
+		if i < 0 || !reflect.DeepEqual(previous[i], plugin) {
+			changed = append(changed, plugin)
+		}
 	}
-
-	return configMemoryCache, nil
+	for _, plugin := range previous {
+		if !slices.ContainsFunc(current, func(p types.PluginItem) bool {
+			return p.Name == plugin.Name && p.Type == plugin.Type
+		}) {
+			changed = append(changed, plugin)
+		}
+	}
+	return changed
 }
 
 // GetConfig returns the initialized in memory config object if it exists otherwise errors if InitializeConfig has not been called.
@@ -122,12 +248,109 @@ func (c *Config) GetPlugin(name string, pluginType types.PluginType) *types.Plug
 	return nil
 }
 
+// GetExperimentResultExport returns the configuration of the export of experiment results, or nil if the results are
+// not exported
+func (c *Config) GetExperimentResultExport() (*ExperimentResultExport, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.configMap == nil || c.configMap.Data["experimentResultExport"] == "" {
+		return nil, nil
+	}
+	var export ExperimentResultExport
+	if err := yaml.Unmarshal([]byte(c.configMap.Data["experimentResultExport"]), &export); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal experiment result export: %w", err)
+	}
+	return &export, nil
+}
+
+// GetAWSAssumableRoles returns the patterns of the ARNs of the IAM roles which the metrics of analysis templates may
+// assume, defined under the awsAssumableRoles key of the configmap. No role may be assumed when there are none.
+func (c *Config) GetAWSAssumableRoles() ([]string, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.configMap == nil || c.configMap.Data["awsAssumableRoles"] == "" {
+		return nil, nil
+	}
+	var roles []string
+	if err := yaml.Unmarshal([]byte(c.configMap.Data["awsAssumableRoles"]), &roles); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal aws assumable roles: %w", err)
+	}
+	return roles, nil
+}
+
+// AWSRoleAssumable returns whether the metrics of analysis templates may assume the IAM role, i.e. whether its ARN
+// matches one of the assumable roles, where * matches any characters
+func (c *Config) AWSRoleAssumable(roleARN string) (bool, error) {
+	patterns, err := c.GetAWSAssumableRoles()
+	if err != nil {
+		return false, err
+	}
+	return slices.ContainsFunc(patterns, func(pattern string) bool {
+		parts := strings.Split(pattern, "*")
+		for i := range parts {
+			parts[i] = regexp.QuoteMeta(parts[i])
+		}
+		return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$").MatchString(roleARN)
+	}), nil
+}
+
 func (c *Config) ValidateConfig() error {
+	if err := c.validateExperimentResultExport(); err != nil {
+		return fmt.Errorf("experiment result export is invalid: %w", err)
+	}
+	if _, err := c.GetAWSAssumableRoles(); err != nil {
+		return err
+	}
 	for _, pluginItem := range c.GetAllPlugins() {
 		matches := re.FindAllStringSubmatch(pluginItem.Name, -1)
 		if len(matches) != 1 || len(matches[0]) != 3 {
 			return fmt.Errorf("plugin repository (%s) must be in the format of <namespace>/<name>", pluginItem.Name)
 		}
+		if err := validatePluginVerification(pluginItem); err != nil {
+			return fmt.Errorf("plugin (%s) is invalid: %w", pluginItem.Name, err)
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateExperimentResultExport() error {
+	export, err := c.GetExperimentResultExport()
+	if err != nil || export == nil {
+		return err
+	}
+	if export.Webhook != nil {
+		if export.Webhook.URL == "" {
+			return fmt.Errorf("webhook requires a url")
+		}
+		for _, header := range export.Webhook.Headers {
+			if header.Key == "" || (header.Value == "") == (header.SecretKeyRef == nil) {
+				return fmt.Errorf("webhook header requires a key, and either a value or a secretKeyRef")
+			}
+		}
+	}
+	if export.S3 != nil && export.S3.Bucket == "" {
+		return fmt.Errorf("s3 requires a bucket")
+	}
+	if export.GCS != nil && export.GCS.Bucket == "" {
+		return fmt.Errorf("gcs requires a bucket")
+	}
+	return nil
+}
+
+// validatePluginVerification validates the signature and the resource limits of a plugin
+func validatePluginVerification(plugin types.PluginItem) error {
+	if plugin.Signature != "" {
+		if plugin.PublicKey == "" {
+			return fmt.Errorf("signature requires a publicKey")
+		}
+		if _, err := cosign.ParsePublicKey([]byte(plugin.PublicKey)); err != nil {
+			return fmt.Errorf("invalid publicKey: %w", err)
+		}
+	}
+	if plugin.ResourceLimits != nil && plugin.ResourceLimits.MemoryLimit != "" {
+		if _, err := resource.ParseQuantity(plugin.ResourceLimits.MemoryLimit); err != nil {
+			return fmt.Errorf("invalid resourceLimits memoryLimit: %w", err)
+		}
 	}
 	return nil
 }
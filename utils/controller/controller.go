@@ -8,6 +8,8 @@ import (
 
 	"errors"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 
 	log "github.com/sirupsen/logrus"
@@ -20,12 +22,17 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/dynamic"
+	kubeinformers "k8s.io/client-go/informers"
+	appsinformers "k8s.io/client-go/informers/apps/v1"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 
 	"github.com/argoproj/argo-rollouts/controller/metrics"
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	logutil "github.com/argoproj/argo-rollouts/utils/log"
+	"github.com/argoproj/argo-rollouts/utils/queue"
 )
 
 // processNextWatchObj will process a single object from the watch by seeing if
@@ -111,6 +118,11 @@ func RunWorker(ctx context.Context, workqueue workqueue.RateLimitingInterface, o
 
 var StaleCacheError = errors.New("stale cache, requeuing item")
 
+// ErrShutdown is the cause with which the context of the controllers is cancelled when the controller shuts down.
+// The workers finish the items they are processing on shutdown, while they are cancelled for any other cause, e.g.
+// when the leadership was lost.
+var ErrShutdown = errors.New("controller is shutting down")
+
 // StaleCacheRequeueDelay is how long to wait before retrying when the informer cache
 // hasn't yet observed our previous write.
 const StaleCacheRequeueDelay = 100 * time.Millisecond
@@ -172,6 +184,7 @@ func processNextWorkItem(ctx context.Context, workqueue workqueue.RateLimitingIn
 			}
 			logCtx.Errorf("%s syncHandler error: %v", objType, err)
 			metricsServer.IncError(namespace, name, objType)
+			metricsServer.SetLastError(namespace, name, objType, err)
 			if k8serrors.IsNotFound(err) {
 				workqueue.Forget(obj)
 				return nil
@@ -186,6 +199,7 @@ func processNextWorkItem(ctx context.Context, workqueue workqueue.RateLimitingIn
 		// Finally, if no error occurs we Forget this item so it does not
 		// get queued again until another change happens.
 		workqueue.Forget(obj)
+		metricsServer.ClearLastError(namespace, name, objType)
 		return nil
 	}(obj)
 
@@ -235,6 +249,23 @@ func EnqueueRateLimited(obj any, q workqueue.RateLimitingInterface) {
 	q.AddRateLimited(key)
 }
 
+// EnqueuePriority adds the object ahead of the objects which are not prioritized when the queue is a priority queue,
+// and otherwise rate limited
+func EnqueuePriority(obj any, q workqueue.RateLimitingInterface) {
+	pq, ok := q.(queue.PriorityQueue)
+	if !ok {
+		EnqueueRateLimited(obj, q)
+		return
+	}
+	var key string
+	var err error
+	if key, err = metaNamespaceKeyFunc(obj); err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	pq.AddPriority(key)
+}
+
 // EnqueueParentObject will take any resource implementing metav1.Object and attempt
 // to find the ownerType resource that 'owns' it. It does this by looking at the
 // objects metadata.ownerReferences field for an appropriate OwnerReference.
@@ -298,3 +329,41 @@ func InstanceIDRequirement(instanceID string) labels.Requirement {
 	}
 	return *instanceIDReq
 }
+
+// RegisterLabelFilteredInformers registers ReplicaSet and Service informers, which only watch and cache the objects
+// matching the given label selectors, with the informer factory. The informers subsequently returned by the factory
+// for these types are the filtered informers. An empty selector leaves the informer of the type unfiltered.
+func RegisterLabelFilteredInformers(factory kubeinformers.SharedInformerFactory, namespace, replicaSetSelector, serviceSelector string) error {
+	indexers := cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}
+	if replicaSetSelector != "" {
+		if _, err := labels.Parse(replicaSetSelector); err != nil {
+			return fmt.Errorf("invalid ReplicaSet label selector: %w", err)
+		}
+		factory.InformerFor(&appsv1.ReplicaSet{}, func(client kubernetes.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+			return appsinformers.NewFilteredReplicaSetInformer(client, namespace, resyncPeriod, indexers, func(options *metav1.ListOptions) {
+				options.LabelSelector = replicaSetSelector
+			})
+		})
+	}
+	if serviceSelector != "" {
+		if _, err := labels.Parse(serviceSelector); err != nil {
+			return fmt.Errorf("invalid Service label selector: %w", err)
+		}
+		factory.InformerFor(&corev1.Service{}, func(client kubernetes.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+			return coreinformers.NewFilteredServiceInformer(client, namespace, resyncPeriod, indexers, func(options *metav1.ListOptions) {
+				options.LabelSelector = serviceSelector
+			})
+		})
+	}
+	return nil
+}
+
+// AddEventHandlerWithResyncPeriod adds the event handler to the informer. The handler is resynced with the given
+// period, or with the default resync period of the informer when the period is zero.
+func AddEventHandlerWithResyncPeriod(informer cache.SharedIndexInformer, handler cache.ResourceEventHandler, resyncPeriod time.Duration) {
+	if resyncPeriod > 0 {
+		informer.AddEventHandlerWithResyncPeriod(handler, resyncPeriod)
+		return
+	}
+	informer.AddEventHandler(handler)
+}
@@ -3,6 +3,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -20,6 +22,8 @@ import (
 	"k8s.io/apimachinery/pkg/watch"
 	dynamicinformers "k8s.io/client-go/dynamic/dynamicinformer"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubeinformers "k8s.io/client-go/informers"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
 	kubetesting "k8s.io/client-go/testing"
 	"k8s.io/client-go/util/workqueue"
 
@@ -45,6 +49,19 @@ func TestProcessNextWorkItemHandlePanic(t *testing.T) {
 		panic("Bad big panic :(")
 	}
 	assert.True(t, processNextWorkItem(context.Background(), q, log.RolloutKey, syncHandler, metricServer))
+	lastErrors := metricServer.LastErrors()
+	if assert.Len(t, lastErrors, 1) {
+		assert.Equal(t, "valid", lastErrors[0].Namespace)
+		assert.Equal(t, "key", lastErrors[0].Name)
+		assert.Equal(t, "Recovered from Panic", lastErrors[0].Error)
+	}
+
+	q.Add("valid/key")
+	syncHandler = func(ctx context.Context, key string) error {
+		return nil
+	}
+	assert.True(t, processNextWorkItem(context.Background(), q, log.RolloutKey, syncHandler, metricServer))
+	assert.Empty(t, metricServer.LastErrors())
 }
 
 func TestProcessNextWorkItemShutDownQueue(t *testing.T) {
@@ -183,6 +200,24 @@ func TestEnqueueRateLimitedInvalidObject(t *testing.T) {
 	assert.Equal(t, 0, q.Len())
 }
 
+func TestEnqueuePriority(t *testing.T) {
+	newRollout := func(name string) *v1alpha1.Rollout {
+		return &v1alpha1.Rollout{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "testNamespace"}}
+	}
+	pq := queue.NewNamedPriorityRateLimitingQueue(queue.DefaultRateLimiterConfig(), "")
+	defer pq.ShutDown()
+	Enqueue(newRollout("a"), pq)
+	EnqueuePriority(newRollout("b"), pq)
+	assert.Equal(t, 2, pq.Len())
+	item, _ := pq.Get()
+	assert.Equal(t, "testNamespace/b", item)
+
+	q := workqueue.NewNamedRateLimitingQueue(queue.DefaultArgoRolloutsRateLimiter(), "")
+	defer q.ShutDown()
+	EnqueuePriority(newRollout("a"), q)
+	assert.Eventually(t, func() bool { return q.Len() == 1 }, time.Second, 10*time.Millisecond)
+}
+
 func TestEnqueueParentObjectInvalidObject(t *testing.T) {
 	errorMessages := make([]error, 0)
 	utilruntime.ErrorHandlers = append(utilruntime.ErrorHandlers, utilruntime.ErrorHandler(func(ctx context.Context, err error, _ string, _ ...interface{}) {
@@ -407,6 +442,65 @@ func TestInstanceIDRequirement(t *testing.T) {
 	assert.Panics(t, func() { InstanceIDRequirement(".%&(") })
 }
 
+func TestRegisterLabelFilteredInformers(t *testing.T) {
+	newRS := func(name string, lbls map[string]string) *appsv1.ReplicaSet {
+		return &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceDefault, Labels: lbls}}
+	}
+	newSvc := func(name string, lbls map[string]string) *corev1.Service {
+		return &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceDefault, Labels: lbls}}
+	}
+	client := k8sfake.NewSimpleClientset(
+		newRS("rollout-rs", map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: "abc"}),
+		newRS("deployment-rs", nil),
+		newSvc("rollout-svc", map[string]string{"app": "rollout"}),
+		newSvc("other-svc", nil),
+	)
+
+	t.Run("Filtered informers", func(t *testing.T) {
+		factory := kubeinformers.NewSharedInformerFactory(client, 0)
+		err := RegisterLabelFilteredInformers(factory, metav1.NamespaceAll, v1alpha1.DefaultRolloutUniqueLabelKey, "app=rollout")
+		assert.NoError(t, err)
+		rsInformer := factory.Apps().V1().ReplicaSets()
+		svcInformer := factory.Core().V1().Services()
+		rsInformer.Informer()
+		svcInformer.Informer()
+		stopCh := make(chan struct{})
+		defer close(stopCh)
+		factory.Start(stopCh)
+		factory.WaitForCacheSync(stopCh)
+
+		replicaSets, err := rsInformer.Lister().List(labels.Everything())
+		assert.NoError(t, err)
+		assert.Len(t, replicaSets, 1)
+		assert.Equal(t, "rollout-rs", replicaSets[0].Name)
+		services, err := svcInformer.Lister().Services(metav1.NamespaceDefault).List(labels.Everything())
+		assert.NoError(t, err)
+		assert.Len(t, services, 1)
+		assert.Equal(t, "rollout-svc", services[0].Name)
+	})
+
+	t.Run("Empty selectors", func(t *testing.T) {
+		factory := kubeinformers.NewSharedInformerFactory(client, 0)
+		assert.NoError(t, RegisterLabelFilteredInformers(factory, metav1.NamespaceAll, "", ""))
+		rsInformer := factory.Apps().V1().ReplicaSets()
+		rsInformer.Informer()
+		stopCh := make(chan struct{})
+		defer close(stopCh)
+		factory.Start(stopCh)
+		factory.WaitForCacheSync(stopCh)
+
+		replicaSets, err := rsInformer.Lister().List(labels.Everything())
+		assert.NoError(t, err)
+		assert.Len(t, replicaSets, 2)
+	})
+
+	t.Run("Invalid selectors", func(t *testing.T) {
+		factory := kubeinformers.NewSharedInformerFactory(client, 0)
+		assert.ErrorContains(t, RegisterLabelFilteredInformers(factory, metav1.NamespaceAll, "app in (", ""), "invalid ReplicaSet label selector")
+		assert.ErrorContains(t, RegisterLabelFilteredInformers(factory, metav1.NamespaceAll, "", "app in ("), "invalid Service label selector")
+	})
+}
+
 func newObj(name, kind, apiVersion string) *unstructured.Unstructured {
 	obj := make(map[string]any)
 	obj["apiVersion"] = apiVersion
@@ -485,3 +579,39 @@ func TestProcessNextWatchObj(t *testing.T) {
 		assert.Equal(t, 0, wq.Len())
 	}
 }
+
+func TestAddEventHandlerWithResyncPeriod(t *testing.T) {
+	client := k8sfake.NewSimpleClientset(
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "rs", Namespace: metav1.NamespaceDefault}},
+	)
+	factory := kubeinformers.NewSharedInformerFactory(client, 10*time.Minute)
+	informer := factory.Apps().V1().ReplicaSets().Informer()
+
+	var lock sync.Mutex
+	resyncs := map[string]int{}
+	newHandler := func(name string) cache.ResourceEventHandler {
+		return cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(old, new any) {
+				lock.Lock()
+				defer lock.Unlock()
+				resyncs[name]++
+			},
+		}
+	}
+	AddEventHandlerWithResyncPeriod(informer, newHandler("explicit"), time.Second)
+	AddEventHandlerWithResyncPeriod(informer, newHandler("default"), 0)
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	factory.Start(stopCh)
+	factory.WaitForCacheSync(stopCh)
+
+	assert.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return resyncs["explicit"] > 0
+	}, 5*time.Second, 100*time.Millisecond)
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Equal(t, 0, resyncs["default"])
+}
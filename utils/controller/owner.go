@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// OwnerIndex is the name of the informer index of objects by the UID of their controller. Objects without a
+	// controller are indexed by their namespace, so that they can still be adopted.
+	OwnerIndex = "byControllerUID"
+	// orphanIndexKeyPrefix prefixes the namespace of objects without a controller in the OwnerIndex
+	orphanIndexKeyPrefix = "orphan/"
+)
+
+// OwnerIndexFunc indexes an object by the UID of its controller, or by its namespace if it has no controller
+func OwnerIndexFunc(obj any) ([]string, error) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	acc, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	controllerRef := metav1.GetControllerOfNoCopy(acc)
+	if controllerRef == nil {
+		return []string{orphanIndexKeyPrefix + acc.GetNamespace()}, nil
+	}
+	return []string{string(controllerRef.UID)}, nil
+}
+
+// AddOwnerIndexer adds the OwnerIndex to the informer, unless it was already added, e.g. by another controller
+// sharing the informer
+func AddOwnerIndexer(informer cache.SharedIndexInformer) error {
+	if _, ok := informer.GetIndexer().GetIndexers()[OwnerIndex]; ok {
+		return nil
+	}
+	return informer.AddIndexers(cache.Indexers{OwnerIndex: OwnerIndexFunc})
+}
+
+// ByOwner returns the objects of the indexer controlled by the owner with the given UID. When withOrphans is true,
+// the objects of the namespace without a controller are returned as well, so that the owner can adopt them.
+func ByOwner(indexer cache.Indexer, namespace string, uid types.UID, withOrphans bool) ([]any, error) {
+	objs, err := indexer.ByIndex(OwnerIndex, string(uid))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get objects by owner: %w", err)
+	}
+	if !withOrphans {
+		return objs, nil
+	}
+	orphans, err := indexer.ByIndex(OwnerIndex, orphanIndexKeyPrefix+namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get orphaned objects: %w", err)
+	}
+	return append(objs, orphans...), nil
+}
@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+)
+
+func newOwnedReplicaSet(name, namespace, ownerUID string) *appsv1.ReplicaSet {
+	rs := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+	if ownerUID != "" {
+		rs.OwnerReferences = []metav1.OwnerReference{{
+			Kind:       "Rollout",
+			Name:       "foo",
+			UID:        types.UID("uid-" + ownerUID),
+			Controller: ptr.To(true),
+		}}
+	}
+	return rs
+}
+
+func TestByOwner(t *testing.T) {
+	informer := informers.NewSharedInformerFactory(k8sfake.NewSimpleClientset(), 0).Apps().V1().ReplicaSets().Informer()
+	assert.NoError(t, AddOwnerIndexer(informer))
+	// adding the index again, e.g. by another controller, is a no-op
+	assert.NoError(t, AddOwnerIndexer(informer))
+
+	indexer := informer.GetIndexer()
+	for _, rs := range []*appsv1.ReplicaSet{
+		newOwnedReplicaSet("foo-1", "default", "foo"),
+		newOwnedReplicaSet("foo-2", "default", "foo"),
+		newOwnedReplicaSet("bar-1", "default", "bar"),
+		newOwnedReplicaSet("orphan", "default", ""),
+		newOwnedReplicaSet("other-orphan", "other", ""),
+	} {
+		assert.NoError(t, indexer.Add(rs))
+	}
+
+	names := func(objs []any) []string {
+		var names []string
+		for _, obj := range objs {
+			names = append(names, obj.(*appsv1.ReplicaSet).Name)
+		}
+		return names
+	}
+	objs, err := ByOwner(indexer, "default", "uid-foo", false)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"foo-1", "foo-2"}, names(objs))
+
+	objs, err = ByOwner(indexer, "default", "uid-foo", true)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"foo-1", "foo-2", "orphan"}, names(objs))
+
+	objs, err = ByOwner(indexer, "default", "uid-unknown", false)
+	assert.NoError(t, err)
+	assert.Empty(t, objs)
+
+	_, err = ByOwner(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{}), "default", "uid-foo", false)
+	assert.Error(t, err)
+}
@@ -0,0 +1,206 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/argoproj/argo-rollouts/controller/metrics"
+)
+
+const (
+	// DefaultWorkerTargetLatency is the default duration in which the items of a workqueue should be processed
+	DefaultWorkerTargetLatency = 5 * time.Second
+	// DefaultWorkerScaleInterval is the default interval in which the number of workers is adjusted
+	DefaultWorkerScaleInterval = 10 * time.Second
+	// DefaultWorkerDrainTimeout is the default duration the workers are given to finish the items they are
+	// processing when the controller stops
+	DefaultWorkerDrainTimeout = 30 * time.Second
+)
+
+// WorkerScaling configures the scaling of the workers of a controller between its threadiness and MaxWorkers
+type WorkerScaling struct {
+	// MaxWorkers is the maximum number of workers. The number of workers is fixed to the threadiness of the
+	// controller when it is not greater than the threadiness.
+	MaxWorkers int
+	// TargetLatency is the duration in which the items of the workqueue should be processed. Workers are added when
+	// the workqueue is estimated to take longer to drain, and removed when it drains much faster.
+	TargetLatency time.Duration
+	// Interval is the interval in which the number of workers is adjusted
+	Interval time.Duration
+}
+
+// WorkerPool runs the workers processing the items of a workqueue
+type WorkerPool struct {
+	workqueue     workqueue.RateLimitingInterface
+	objType       string
+	syncHandler   func(context.Context, string) error
+	metricsServer *metrics.MetricsServer
+	minWorkers    int
+	scaling       WorkerScaling
+	syncCtx       context.Context
+
+	lock sync.Mutex
+	// cancels stop the running workers, one per worker
+	cancels []context.CancelFunc
+	// avgSyncDuration is the moving average of the duration in which the workers process an item
+	avgSyncDuration time.Duration
+	wg              sync.WaitGroup
+}
+
+// StartWorkers starts threadiness workers processing the items of the workqueue. When scaling is enabled, the
+// number of workers is adjusted within the threadiness and the maximum number of workers based on the depth of the
+// workqueue and the time it takes to process an item. The workers stop when the context is done.
+func StartWorkers(ctx context.Context, workqueue workqueue.RateLimitingInterface, objType string, syncHandler func(context.Context, string) error, metricsServer *metrics.MetricsServer, threadiness int, scaling WorkerScaling) *WorkerPool {
+	if scaling.TargetLatency <= 0 {
+		scaling.TargetLatency = DefaultWorkerTargetLatency
+	}
+	if scaling.Interval <= 0 {
+		scaling.Interval = DefaultWorkerScaleInterval
+	}
+	p := &WorkerPool{
+		workqueue:     workqueue,
+		objType:       objType,
+		syncHandler:   syncHandler,
+		metricsServer: metricsServer,
+		minWorkers:    threadiness,
+		scaling:       scaling,
+	}
+	// the items are synced with a context which is only cancelled when the pool is not stopped by a shutdown
+	syncCtx, cancelSync := context.WithCancel(context.WithoutCancel(ctx))
+	context.AfterFunc(ctx, func() {
+		if !errors.Is(context.Cause(ctx), ErrShutdown) {
+			cancelSync()
+		}
+	})
+	p.syncCtx = syncCtx
+	p.scaleTo(ctx, threadiness)
+	if scaling.MaxWorkers > threadiness {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			wait.Until(func() {
+				p.scaleTo(ctx, desiredWorkers(p.Workers(), p.minWorkers, p.scaling.MaxWorkers, p.workqueue.Len(), p.averageSyncDuration(), p.scaling.TargetLatency))
+			}, p.scaling.Interval, ctx.Done())
+		}()
+	}
+	return p
+}
+
+// Workers returns the number of running workers
+func (p *WorkerPool) Workers() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return len(p.cancels)
+}
+
+// Wait blocks until all workers have stopped, or until the timeout expires. It returns whether all workers have
+// stopped.
+func (p *WorkerPool) Wait(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		log.Warnf("%s workers did not stop within %s", p.objType, timeout)
+		return false
+	}
+}
+
+func (p *WorkerPool) averageSyncDuration() time.Duration {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.avgSyncDuration
+}
+
+func (p *WorkerPool) observeSyncDuration(duration time.Duration) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.avgSyncDuration == 0 {
+		p.avgSyncDuration = duration
+		return
+	}
+	// exponentially weighted moving average, so that the pool adapts to changes of the sync duration
+	p.avgSyncDuration = (4*p.avgSyncDuration + duration) / 5
+}
+
+// scaleTo starts or stops workers until the given number of workers are running. A stopped worker finishes the
+// item it is processing, and a worker waiting for an item stops once it receives the next one.
+func (p *WorkerPool) scaleTo(ctx context.Context, workers int) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	current := len(p.cancels)
+	if workers == current || ctx.Err() != nil {
+		return
+	}
+	for len(p.cancels) < workers {
+		workerCtx, cancel := context.WithCancel(ctx)
+		p.cancels = append(p.cancels, cancel)
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			wait.Until(func() {
+				p.runWorker(workerCtx)
+			}, time.Second, workerCtx.Done())
+			log.Debugf("%s worker has stopped", p.objType)
+		}()
+	}
+	for len(p.cancels) > workers {
+		last := len(p.cancels) - 1
+		p.cancels[last]()
+		p.cancels = p.cancels[:last]
+	}
+	if current > 0 {
+		log.Infof("Scaled %s workers from %d to %d", p.objType, current, workers)
+	}
+	p.metricsServer.SetWorkers(p.objType, workers)
+}
+
+// runWorker processes items of the workqueue until the worker is stopped or the workqueue is shut down. Items are
+// synced with the sync context of the pool rather than the context of the worker, so that scaling down the pool or
+// shutting down the controller does not cancel the item being processed, e.g. halfway through patching a status.
+// The sync context is cancelled when the pool is stopped for any other cause, e.g. when the controller lost its
+// leadership, so that a replica which is no longer the leader stops writing.
+func (p *WorkerPool) runWorker(workerCtx context.Context) {
+	for workerCtx.Err() == nil {
+		start := time.Now()
+		if !processNextWorkItem(p.syncCtx, p.workqueue, p.objType, p.syncHandler, p.metricsServer) {
+			return
+		}
+		p.observeSyncDuration(time.Since(start))
+	}
+}
+
+// desiredWorkers returns the number of workers required to process the items of the workqueue within the target
+// latency, bounded by the minimum and maximum number of workers. Workers are removed one at a time, so that the
+// pool does not shrink abruptly when the workqueue is temporarily empty.
+func desiredWorkers(current, minWorkers, maxWorkers, depth int, avgSyncDuration, targetLatency time.Duration) int {
+	desired := current
+	switch {
+	case depth == 0:
+		desired = current - 1
+	case avgSyncDuration <= 0:
+		// the duration of a sync is unknown until the first item was processed
+		if depth > current {
+			desired = current + 1
+		}
+	default:
+		drainTime := time.Duration(float64(depth) * float64(avgSyncDuration) / float64(max(current, 1)))
+		if drainTime > targetLatency {
+			desired = int(math.Ceil(float64(depth) * float64(avgSyncDuration) / float64(targetLatency)))
+		} else if drainTime < targetLatency/2 {
+			desired = current - 1
+		}
+	}
+	return min(max(desired, minWorkers), maxWorkers)
+}
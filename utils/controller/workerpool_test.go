@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/argoproj/argo-rollouts/controller/metrics"
+	"github.com/argoproj/argo-rollouts/utils/log"
+	"github.com/argoproj/argo-rollouts/utils/queue"
+)
+
+func TestDesiredWorkers(t *testing.T) {
+	tests := []struct {
+		name            string
+		current         int
+		depth           int
+		avgSyncDuration time.Duration
+		expected        int
+	}{
+		{name: "empty queue removes a worker", current: 5, depth: 0, avgSyncDuration: time.Second, expected: 4},
+		{name: "never less than the minimum", current: 2, depth: 0, avgSyncDuration: time.Second, expected: 2},
+		{name: "unknown sync duration adds a worker", current: 2, depth: 10, expected: 3},
+		{name: "unknown sync duration with few items", current: 2, depth: 1, expected: 2},
+		{name: "slow drain scales to the target latency", current: 2, depth: 40, avgSyncDuration: time.Second, expected: 8},
+		{name: "never more than the maximum", current: 2, depth: 1000, avgSyncDuration: time.Second, expected: 10},
+		{name: "drain within the target latency", current: 4, depth: 15, avgSyncDuration: time.Second, expected: 4},
+		{name: "fast drain removes a worker", current: 4, depth: 2, avgSyncDuration: time.Second, expected: 3},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, desiredWorkers(test.current, 2, 10, test.depth, test.avgSyncDuration, 5*time.Second))
+		})
+	}
+}
+
+func TestStartWorkers(t *testing.T) {
+	q := workqueue.NewNamedRateLimitingQueue(queue.DefaultArgoRolloutsRateLimiter(), "Rollouts")
+	metricServer := metrics.NewMetricsServer(metrics.ServerConfig{
+		Addr:               "localhost:8080",
+		K8SRequestProvider: &metrics.K8sRequestsCountProvider{},
+	})
+	var synced atomic.Int32
+	syncHandler := func(ctx context.Context, key string) error {
+		time.Sleep(10 * time.Millisecond)
+		synced.Add(1)
+		return nil
+	}
+	for i := 0; i < 100; i++ {
+		q.Add(fmt.Sprintf("default/rollout-%d", i))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	workers := StartWorkers(ctx, q, log.RolloutKey, syncHandler, metricServer, 1, WorkerScaling{
+		MaxWorkers:    4,
+		TargetLatency: 20 * time.Millisecond,
+		Interval:      10 * time.Millisecond,
+	})
+	assert.Eventually(t, func() bool { return workers.Workers() == 4 }, 5*time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool { return synced.Load() == 100 }, 5*time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool { return workers.Workers() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	q.ShutDown()
+	cancel()
+	assert.True(t, workers.Wait(5*time.Second))
+}
+
+func TestStartWorkersWithoutScaling(t *testing.T) {
+	q := workqueue.NewNamedRateLimitingQueue(queue.DefaultArgoRolloutsRateLimiter(), "Rollouts")
+	metricServer := metrics.NewMetricsServer(metrics.ServerConfig{
+		Addr:               "localhost:8080",
+		K8SRequestProvider: &metrics.K8sRequestsCountProvider{},
+	})
+	syncHandler := func(ctx context.Context, key string) error {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	workers := StartWorkers(ctx, q, log.RolloutKey, syncHandler, metricServer, 3, WorkerScaling{MaxWorkers: 2})
+	assert.Equal(t, 3, workers.Workers())
+
+	q.ShutDown()
+	cancel()
+	assert.True(t, workers.Wait(5*time.Second))
+}
+
+func TestWorkersStopWithLeadership(t *testing.T) {
+	q := workqueue.NewNamedRateLimitingQueue(queue.DefaultArgoRolloutsRateLimiter(), "Rollouts")
+	defer q.ShutDown()
+	metricServer := metrics.NewMetricsServer(metrics.ServerConfig{
+		Addr:               "localhost:8080",
+		K8SRequestProvider: &metrics.K8sRequestsCountProvider{},
+	})
+	started := make(chan string, 1)
+	release := make(chan struct{})
+	syncHandler := func(ctx context.Context, key string) error {
+		started <- key
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-release:
+			return nil
+		}
+	}
+
+	// losing the leadership cancels the item being processed
+	ctx, cancel := context.WithCancelCause(context.Background())
+	workers := StartWorkers(ctx, q, log.RolloutKey, syncHandler, metricServer, 1, WorkerScaling{})
+	q.Add("default/foo")
+	<-started
+	cancel(errors.New("leadership lost"))
+	assert.True(t, workers.Wait(5*time.Second))
+	assert.Len(t, metricServer.LastErrors(), 1)
+
+	// on shutdown, the item being processed is finished, but delays the shutdown no longer than the timeout
+	ctx, cancel = context.WithCancelCause(context.Background())
+	workers = StartWorkers(ctx, q, log.RolloutKey, syncHandler, metricServer, 1, WorkerScaling{})
+	q.Add("default/bar")
+	<-started
+	cancel(ErrShutdown)
+	assert.False(t, workers.Wait(100*time.Millisecond))
+	close(release)
+	assert.True(t, workers.Wait(5*time.Second))
+	assert.Len(t, metricServer.LastErrors(), 1)
+}
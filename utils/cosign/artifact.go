@@ -0,0 +1,105 @@
+package cosign
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+)
+
+// maxArtifactSize limits the size of the artifacts pulled from a registry
+const maxArtifactSize = 256 << 20
+
+// ArtifactPuller pulls the file of OCI artifacts, e.g. pushed with oras push, from registries. Artifacts with a
+// manifest per platform are pulled for the platform of the controller.
+type ArtifactPuller struct {
+	HTTPClient *http.Client
+	// Insecure registries are accessed with plain HTTP
+	Insecure bool
+}
+
+// index is an OCI image index, which references a manifest per platform
+type index struct {
+	Manifests []struct {
+		descriptor
+		Platform *struct {
+			OS           string `json:"os"`
+			Architecture string `json:"architecture"`
+		} `json:"platform,omitempty"`
+	} `json:"manifests"`
+}
+
+// Pull returns the content of the single layer of the artifact, and the digest of its manifest. The manifest of an
+// artifact referenced by digest must match the digest.
+func (p *ArtifactPuller) Pull(ctx context.Context, artifact string, credentials map[string]Credentials) ([]byte, string, error) {
+	ref, err := parseReference(artifact)
+	if err != nil {
+		return nil, "", err
+	}
+	httpClient := p.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	client := newRegistryClient(httpClient, credentials, p.Insecure)
+	digest, err := client.resolveDigest(ctx, ref)
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := p.getManifest(ctx, client, ref, digest)
+	if err != nil {
+		return nil, "", err
+	}
+	var idx index
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, "", fmt.Errorf("invalid manifest of artifact %s: %w", artifact, err)
+	}
+	if len(idx.Manifests) > 0 {
+		platformDigest := ""
+		for _, m := range idx.Manifests {
+			if m.Platform != nil && m.Platform.OS == runtime.GOOS && m.Platform.Architecture == runtime.GOARCH {
+				platformDigest = m.Digest
+				break
+			}
+		}
+		if platformDigest == "" {
+			return nil, "", fmt.Errorf("artifact %s has no manifest for platform %s/%s", artifact, runtime.GOOS, runtime.GOARCH)
+		}
+		if data, err = p.getManifest(ctx, client, ref, platformDigest); err != nil {
+			return nil, "", err
+		}
+	}
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, "", fmt.Errorf("invalid manifest of artifact %s: %w", artifact, err)
+	}
+	if len(m.Layers) != 1 {
+		return nil, "", fmt.Errorf("artifact %s must have a single layer, but has %d", artifact, len(m.Layers))
+	}
+	if m.Layers[0].Size > maxArtifactSize {
+		return nil, "", fmt.Errorf("artifact %s exceeds the maximum size of %d bytes", artifact, maxArtifactSize)
+	}
+	content, err := client.getBlob(ctx, ref, m.Layers[0].Digest, maxArtifactSize)
+	if err != nil {
+		return nil, "", err
+	}
+	return content, digest, nil
+}
+
+// getManifest returns the manifest of the digest, after verifying that its content matches the digest
+func (p *ArtifactPuller) getManifest(ctx context.Context, client *registryClient, ref reference, digest string) ([]byte, error) {
+	data, found, err := client.getManifest(ctx, ref, digest)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("manifest %s@%s not found", ref, digest)
+	}
+	sum := sha256.Sum256(data)
+	if "sha256:"+hex.EncodeToString(sum[:]) != digest {
+		return nil, fmt.Errorf("content of manifest %s@%s does not match its digest", ref, digest)
+	}
+	return data, nil
+}
@@ -0,0 +1,73 @@
+package cosign
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pushArtifact pushes a manifest with the layers under the tag and its digest, and returns the digest
+func (r *fakeRegistry) pushArtifact(tag string, layers ...descriptor) string {
+	data, _ := json.Marshal(manifest{Layers: layers})
+	digest := digestOf(data)
+	r.manifests[tag] = data
+	r.manifests[digest] = data
+	return digest
+}
+
+func TestPullArtifact(t *testing.T) {
+	registry := newFakeRegistry(t)
+	plugin := []byte("#!/bin/sh")
+	digest := registry.pushArtifact("v1", registry.pushBlob(plugin))
+	puller := &ArtifactPuller{Insecure: true}
+
+	content, pulledDigest, err := puller.Pull(context.TODO(), registry.image("v1"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, plugin, content)
+	assert.Equal(t, digest, pulledDigest)
+
+	content, pulledDigest, err = puller.Pull(context.TODO(), strings.TrimSuffix(registry.image(""), ":")+"@"+digest, nil)
+	require.NoError(t, err)
+	assert.Equal(t, plugin, content)
+	assert.Equal(t, digest, pulledDigest)
+
+	t.Run("digest mismatch", func(t *testing.T) {
+		tampered := "sha256:" + strings.Repeat("0", 64)
+		registry.manifests[tampered] = registry.manifests["v1"]
+		_, _, err := puller.Pull(context.TODO(), strings.TrimSuffix(registry.image(""), ":")+"@"+tampered, nil)
+		assert.ErrorContains(t, err, "does not match its digest")
+	})
+
+	t.Run("layers", func(t *testing.T) {
+		registry.pushArtifact("empty")
+		_, _, err := puller.Pull(context.TODO(), registry.image("empty"), nil)
+		assert.EqualError(t, err, fmt.Sprintf("artifact %s must have a single layer, but has 0", registry.image("empty")))
+	})
+
+	t.Run("index", func(t *testing.T) {
+		platform := registry.pushArtifact("platform", registry.pushBlob([]byte("platform")))
+		data := []byte(fmt.Sprintf(`{"schemaVersion":2,"manifests":[{"digest":"sha256:0","platform":{"os":"plan9","architecture":"386"}},{"digest":"%s","platform":{"os":"%s","architecture":"%s"}}]}`, platform, runtime.GOOS, runtime.GOARCH))
+		registry.manifests["index"] = data
+		registry.manifests[digestOf(data)] = data
+		content, pulledDigest, err := puller.Pull(context.TODO(), registry.image("index"), nil)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("platform"), content)
+		assert.Equal(t, digestOf(data), pulledDigest)
+
+		registry.manifests["other-platform"] = []byte(`{"schemaVersion":2,"manifests":[{"digest":"sha256:0","platform":{"os":"plan9","architecture":"386"}}]}`)
+		registry.manifests[digestOf(registry.manifests["other-platform"])] = registry.manifests["other-platform"]
+		_, _, err = puller.Pull(context.TODO(), registry.image("other-platform"), nil)
+		assert.ErrorContains(t, err, "has no manifest for platform")
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, _, err := puller.Pull(context.TODO(), registry.image("missing"), nil)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,360 @@
+package cosign
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+const (
+	defaultRegistry = "docker.io"
+	// dockerHubRegistry is the host of the registry API of Docker Hub
+	dockerHubRegistry = "registry-1.docker.io"
+	// maxManifestSize and maxBlobSize limit the size of the manifests and signature payloads read from a registry
+	maxManifestSize = 4 << 20
+	maxBlobSize     = 1 << 20
+)
+
+var manifestMediaTypes = strings.Join([]string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}, ",")
+
+// Credentials are the credentials of a registry
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// reference is a parsed image reference
+type reference struct {
+	registry   string
+	repository string
+	tag        string
+	digest     string
+}
+
+// parseReference parses an image reference the same as the container runtimes, i.e. images without registry are
+// pulled from Docker Hub and images without tag or digest are the latest tag
+func parseReference(image string) (reference, error) {
+	var ref reference
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, ref.digest = name[:i], name[i+1:]
+		if !strings.HasPrefix(ref.digest, "sha256:") || len(ref.digest) != len("sha256:")+64 {
+			return ref, fmt.Errorf("invalid digest in image %s", image)
+		}
+	}
+	if i := strings.LastIndex(name, ":"); i >= 0 && !strings.Contains(name[i:], "/") {
+		name, ref.tag = name[:i], name[i+1:]
+	}
+	if name == "" {
+		return ref, fmt.Errorf("invalid image %s", image)
+	}
+	ref.registry = defaultRegistry
+	ref.repository = name
+	if i := strings.Index(name, "/"); i >= 0 {
+		host := name[:i]
+		if strings.ContainsAny(host, ".:") || host == "localhost" {
+			ref.registry, ref.repository = host, name[i+1:]
+		}
+	}
+	if ref.registry == defaultRegistry && !strings.Contains(ref.repository, "/") {
+		ref.repository = "library/" + ref.repository
+	}
+	if ref.tag == "" && ref.digest == "" {
+		ref.tag = "latest"
+	}
+	return ref, nil
+}
+
+// String returns the repository of the reference including its registry
+func (r reference) String() string {
+	return r.registry + "/" + r.repository
+}
+
+// manifest is an OCI image manifest
+type manifest struct {
+	Layers []descriptor `json:"layers"`
+}
+
+// descriptor describes a blob of an image manifest
+type descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// registryClient reads manifests and blobs from registries with the Docker Registry HTTP API V2, authenticating with
+// the credentials of the registries
+type registryClient struct {
+	httpClient  *http.Client
+	credentials map[string]Credentials
+	// insecure registries are accessed with plain HTTP
+	insecure bool
+
+	lock sync.Mutex
+	// authorization holds the Authorization header of a repository once the registry asked for it
+	authorization map[string]string
+}
+
+func newRegistryClient(httpClient *http.Client, credentials map[string]Credentials, insecure bool) *registryClient {
+	return &registryClient{
+		httpClient:    httpClient,
+		credentials:   credentials,
+		insecure:      insecure,
+		authorization: map[string]string{},
+	}
+}
+
+// resolveDigest returns the digest of the manifest the reference points to
+func (c *registryClient) resolveDigest(ctx context.Context, ref reference) (string, error) {
+	if ref.digest != "" {
+		return ref.digest, nil
+	}
+	resp, err := c.get(ctx, ref, http.MethodHead, "manifests/"+ref.tag, manifestMediaTypes)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to resolve %s:%s: registry returned status %d", ref, ref.tag, resp.StatusCode)
+	}
+	if digest := resp.Header.Get("Docker-Content-Digest"); digest != "" {
+		return digest, nil
+	}
+	// not all registries return the digest of a manifest, in which case it is computed from the manifest
+	data, found, err := c.getManifest(ctx, ref, ref.tag)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", fmt.Errorf("manifest %s:%s not found", ref, ref.tag)
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// getManifest returns the manifest of the tag or digest, and whether it exists
+func (c *registryClient) getManifest(ctx context.Context, ref reference, tagOrDigest string) ([]byte, bool, error) {
+	resp, err := c.get(ctx, ref, http.MethodGet, "manifests/"+tagOrDigest, manifestMediaTypes)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("failed to get manifest %s:%s: registry returned status %d", ref, tagOrDigest, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read manifest %s:%s: %w", ref, tagOrDigest, err)
+	}
+	return data, true, nil
+}
+
+// getBlob returns the blob of the digest, after verifying that its content matches the digest
+func (c *registryClient) getBlob(ctx context.Context, ref reference, digest string, maxSize int64) ([]byte, error) {
+	resp, err := c.get(ctx, ref, http.MethodGet, "blobs/"+digest, "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get blob %s@%s: registry returned status %d", ref, digest, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blob %s@%s: %w", ref, digest, err)
+	}
+	sum := sha256.Sum256(data)
+	if "sha256:"+hex.EncodeToString(sum[:]) != digest {
+		return nil, fmt.Errorf("content of blob %s@%s does not match its digest", ref, digest)
+	}
+	return data, nil
+}
+
+// get sends a request to the registry API of the repository. A request which is not authorized is retried once with
+// the authorization the registry asks for in its challenge.
+func (c *registryClient) get(ctx context.Context, ref reference, method, path, accept string) (*http.Response, error) {
+	scheme := "https"
+	if c.insecure {
+		scheme = "http"
+	}
+	host := ref.registry
+	if host == defaultRegistry {
+		host = dockerHubRegistry
+	}
+	requestURL := fmt.Sprintf("%s://%s/v2/%s/%s", scheme, host, ref.repository, path)
+	key := ref.String()
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, requestURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		c.lock.Lock()
+		authorization := c.authorization[key]
+		c.lock.Unlock()
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to access registry %s: %w", ref.registry, err)
+		}
+		if resp.StatusCode != http.StatusUnauthorized || attempt > 0 {
+			return resp, nil
+		}
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		authorization, err = c.authorize(ctx, ref, challenge)
+		if err != nil {
+			return nil, err
+		}
+		c.lock.Lock()
+		c.authorization[key] = authorization
+		c.lock.Unlock()
+	}
+}
+
+// authorize returns the Authorization header which answers the challenge of a registry, either with the credentials of
+// the registry or with a token for pulling the repository
+func (c *registryClient) authorize(ctx context.Context, ref reference, challenge string) (string, error) {
+	creds, hasCreds := c.credentials[ref.registry]
+	scheme, params := parseChallenge(challenge)
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if !hasCreds {
+			return "", fmt.Errorf("registry %s requires credentials", ref.registry)
+		}
+		return "Basic " + basicAuth(creds), nil
+	case "bearer":
+	default:
+		return "", fmt.Errorf("registry %s requested unsupported authentication %q", ref.registry, scheme)
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Host == "" {
+		return "", fmt.Errorf("registry %s requested authentication with invalid realm %q", ref.registry, params["realm"])
+	}
+	query := realm.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	query.Set("scope", fmt.Sprintf("repository:%s:pull", ref.repository))
+	realm.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if hasCreds {
+		req.Header.Set("Authorization", "Basic "+basicAuth(creds))
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get token of registry %s: %w", ref.registry, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get token of registry %s: status %d", ref.registry, resp.StatusCode)
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxManifestSize)).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to decode token of registry %s: %w", ref.registry, err)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	return "Bearer " + token.Token, nil
+}
+
+// parseChallenge parses the scheme and the parameters of a WWW-Authenticate header, e.g.
+// Bearer realm="https://auth.docker.io/token",service="registry.docker.io"
+func parseChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := map[string]string{}
+	for rest != "" {
+		var key, value string
+		key, rest, _ = strings.Cut(strings.TrimLeft(rest, " ,"), "=")
+		if strings.HasPrefix(rest, `"`) {
+			value, rest, _ = strings.Cut(rest[1:], `"`)
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		if key = strings.ToLower(strings.TrimSpace(key)); key != "" {
+			params[key] = value
+		}
+	}
+	return scheme, params
+}
+
+func basicAuth(creds Credentials) string {
+	return base64.StdEncoding.EncodeToString([]byte(creds.Username + ":" + creds.Password))
+}
+
+// ParseDockerConfig returns the credentials of the registries of a .dockerconfigjson or .dockercfg image pull secret
+func ParseDockerConfig(data []byte) (map[string]Credentials, error) {
+	type auth struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		Auth     string `json:"auth"`
+	}
+	var config struct {
+		Auths map[string]auth `json:"auths"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	if config.Auths == nil {
+		// the legacy .dockercfg format holds the registries at the top level
+		if err := json.Unmarshal(data, &config.Auths); err != nil {
+			return nil, err
+		}
+	}
+	credentials := map[string]Credentials{}
+	for server, a := range config.Auths {
+		creds := Credentials{Username: a.Username, Password: a.Password}
+		if a.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(a.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("invalid auth of registry %s: %w", server, err)
+			}
+			creds.Username, creds.Password, _ = strings.Cut(string(decoded), ":")
+		}
+		credentials[registryHost(server)] = creds
+	}
+	return credentials, nil
+}
+
+// registryHost returns the registry of a server of a docker config, which might be a URL such as
+// https://index.docker.io/v1/
+func registryHost(server string) string {
+	host := server
+	if u, err := url.Parse(server); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	host, _, _ = strings.Cut(host, "/")
+	switch host {
+	case "index.docker.io", dockerHubRegistry:
+		return defaultRegistry
+	}
+	return host
+}
@@ -0,0 +1,372 @@
+package cosign
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	protobundle "github.com/sigstore/protobuf-specs/gen/pb-go/bundle/v1"
+	protocommon "github.com/sigstore/protobuf-specs/gen/pb-go/common/v1"
+	protorekor "github.com/sigstore/protobuf-specs/gen/pb-go/rekor/v1"
+	sgbundle "github.com/sigstore/sigstore-go/pkg/bundle"
+	"github.com/sigstore/sigstore-go/pkg/root"
+	"github.com/sigstore/sigstore-go/pkg/verify"
+	sigsignature "github.com/sigstore/sigstore/pkg/signature"
+)
+
+const (
+	// SimpleSigningMediaType is the media type of the layers of a cosign signature manifest
+	SimpleSigningMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+	// SignatureAnnotation holds the base64 encoded signature of the payload of a layer
+	SignatureAnnotation = "dev.cosignproject.cosign/signature"
+	// CertificateAnnotation holds the PEM encoded certificate of a keyless signature
+	CertificateAnnotation = "dev.sigstore.cosign/certificate"
+	// ChainAnnotation holds the PEM encoded intermediate certificates of a keyless signature
+	ChainAnnotation = "dev.sigstore.cosign/chain"
+	// BundleAnnotation holds the transparency log entry of a signature
+	BundleAnnotation = "dev.sigstore.cosign/bundle"
+
+	signaturePayloadType = "cosign container image signature"
+	// legacyBundleMediaType is the media type of the sigstore bundles whose transparency log entries have a signed entry
+	// timestamp instead of an inclusion proof, like the transparency log entries of the cosign signature annotations
+	legacyBundleMediaType = "application/vnd.dev.sigstore.bundle+json;version=0.1"
+)
+
+// Identity is the identity of a keyless signature. The subject is matched against the email addresses and URIs of the
+// certificate of the signature.
+type Identity struct {
+	Issuer       string
+	Subject      string
+	SubjectRegex *regexp.Regexp
+}
+
+// Verifier verifies that images are signed with cosign by one of the public keys or identities. Keyless signatures of
+// the identities must have a certificate issued by the roots, and an entry in the transparency log, at whose time
+// the certificate is verified. The keyless signatures are verified with sigstore-go.
+type Verifier struct {
+	PublicKeys []crypto.PublicKey
+	Identities []Identity
+
+	Roots               []*x509.Certificate
+	Intermediates       []*x509.Certificate
+	TransparencyLogKeys []crypto.PublicKey
+
+	HTTPClient *http.Client
+	// Insecure registries are accessed with plain HTTP
+	Insecure bool
+}
+
+// VerificationError indicates that an image is not signed by one of the keys or identities of the verifier, as
+// opposed to an error accessing its registry
+type VerificationError struct {
+	Image  string
+	Reason string
+}
+
+func (e *VerificationError) Error() string {
+	return fmt.Sprintf("image %s is not signed by a trusted key or identity: %s", e.Image, e.Reason)
+}
+
+// IsVerificationError returns whether the error indicates that an image is not signed by a trusted key or identity
+func IsVerificationError(err error) bool {
+	var verificationErr *VerificationError
+	return errors.As(err, &verificationErr)
+}
+
+// signaturePayload is the simple signing payload signed by cosign
+type signaturePayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// bundle is the transparency log entry of a signature
+type bundle struct {
+	SignedEntryTimestamp []byte        `json:"SignedEntryTimestamp"`
+	Payload              bundlePayload `json:"Payload"`
+}
+
+// bundlePayload is the payload of the signed entry timestamp. Its fields are in the order of the canonical JSON which
+// is signed by the transparency log.
+type bundlePayload struct {
+	Body           string `json:"body"`
+	IntegratedTime int64  `json:"integratedTime"`
+	LogID          string `json:"logID"`
+	LogIndex       int64  `json:"logIndex"`
+}
+
+// Verify verifies that the image is signed by one of the public keys or identities, and returns the digest of its
+// manifest. A VerificationError is returned if it is not.
+func (v *Verifier) Verify(ctx context.Context, image string, credentials map[string]Credentials) (string, error) {
+	ref, err := parseReference(image)
+	if err != nil {
+		return "", &VerificationError{Image: image, Reason: err.Error()}
+	}
+	httpClient := v.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	client := newRegistryClient(httpClient, credentials, v.Insecure)
+	digest, err := client.resolveDigest(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	signatureTag := strings.Replace(digest, ":", "-", 1) + ".sig"
+	data, found, err := client.getManifest(ctx, ref, signatureTag)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", &VerificationError{Image: image, Reason: "no signatures found"}
+	}
+	var signatures manifest
+	if err := json.Unmarshal(data, &signatures); err != nil {
+		return "", &VerificationError{Image: image, Reason: fmt.Sprintf("invalid signature manifest: %v", err)}
+	}
+	var reasons []string
+	for _, layer := range signatures.Layers {
+		if layer.MediaType != SimpleSigningMediaType || layer.Size > maxBlobSize {
+			continue
+		}
+		payload, err := client.getBlob(ctx, ref, layer.Digest, maxBlobSize)
+		if err != nil {
+			return "", err
+		}
+		if err := v.verifyLayer(layer, payload, digest); err != nil {
+			reasons = append(reasons, err.Error())
+			continue
+		}
+		return digest, nil
+	}
+	if len(reasons) == 0 {
+		return "", &VerificationError{Image: image, Reason: "no signatures found"}
+	}
+	slices.Sort(reasons)
+	return "", &VerificationError{Image: image, Reason: strings.Join(slices.Compact(reasons), "; ")}
+}
+
+// verifyLayer verifies that the payload of the signature layer is signed by one of the keys or identities, and that
+// it is the payload of the digest
+func (v *Verifier) verifyLayer(layer descriptor, payload []byte, digest string) error {
+	var p signaturePayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("invalid signature payload: %w", err)
+	}
+	if p.Critical.Type != signaturePayloadType || p.Critical.Image.DockerManifestDigest != digest {
+		return errors.New("signature payload does not match the image")
+	}
+	signature, err := base64.StdEncoding.DecodeString(layer.Annotations[SignatureAnnotation])
+	if err != nil || len(signature) == 0 {
+		return errors.New("invalid signature")
+	}
+	for _, key := range v.PublicKeys {
+		if verifySignature(key, payload, signature) == nil {
+			return nil
+		}
+	}
+	certPEM := layer.Annotations[CertificateAnnotation]
+	if certPEM == "" {
+		return errors.New("signature does not match any public key")
+	}
+	if len(v.Identities) == 0 {
+		return errors.New("keyless signature, but no identities are trusted")
+	}
+	return v.verifyKeyless(payload, signature, certPEM, layer.Annotations[ChainAnnotation], layer.Annotations[BundleAnnotation])
+}
+
+// verifyKeyless verifies a keyless signature with sigstore-go. The signature and its transparency log entry are
+// verified as a bundle of the legacy cosign format, i.e. the entry must have a signed entry timestamp of a trusted
+// transparency log, at whose time the certificate must be issued by the certificate authorities to one of the
+// identities.
+func (v *Verifier) verifyKeyless(payload, signature []byte, certPEM, chainPEM, bundleJSON string) error {
+	certs, err := parseCertificates([]byte(certPEM))
+	if err != nil || len(certs) == 0 {
+		return errors.New("invalid certificate")
+	}
+	chain, err := parseCertificates([]byte(chainPEM))
+	if err != nil {
+		return errors.New("invalid certificate chain")
+	}
+	if bundleJSON == "" {
+		return errors.New("keyless signature has no transparency log entry")
+	}
+	var b bundle
+	if err := json.Unmarshal([]byte(bundleJSON), &b); err != nil {
+		return fmt.Errorf("invalid transparency log entry: %w", err)
+	}
+	logID, err := hex.DecodeString(b.Payload.LogID)
+	if err != nil {
+		return fmt.Errorf("invalid transparency log entry: %w", err)
+	}
+	body, err := base64.StdEncoding.DecodeString(b.Payload.Body)
+	if err != nil {
+		return fmt.Errorf("invalid transparency log entry: %w", err)
+	}
+	payloadHash := sha256.Sum256(payload)
+	entity, err := sgbundle.NewBundle(&protobundle.Bundle{
+		MediaType: legacyBundleMediaType,
+		VerificationMaterial: &protobundle.VerificationMaterial{
+			Content: &protobundle.VerificationMaterial_X509CertificateChain{
+				X509CertificateChain: &protocommon.X509CertificateChain{
+					Certificates: []*protocommon.X509Certificate{{RawBytes: certs[0].Raw}},
+				},
+			},
+			TlogEntries: []*protorekor.TransparencyLogEntry{{
+				LogIndex:          b.Payload.LogIndex,
+				LogId:             &protocommon.LogId{KeyId: logID},
+				KindVersion:       &protorekor.KindVersion{Kind: "hashedrekord", Version: "0.0.1"},
+				IntegratedTime:    b.Payload.IntegratedTime,
+				InclusionPromise:  &protorekor.InclusionPromise{SignedEntryTimestamp: b.SignedEntryTimestamp},
+				CanonicalizedBody: body,
+			}},
+		},
+		Content: &protobundle.Bundle_MessageSignature{
+			MessageSignature: &protocommon.MessageSignature{
+				MessageDigest: &protocommon.HashOutput{Algorithm: protocommon.HashAlgorithm_SHA2_256, Digest: payloadHash[:]},
+				Signature:     signature,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("invalid transparency log entry: %w", err)
+	}
+
+	trustedMaterial, err := v.trustedMaterial(chain)
+	if err != nil {
+		return err
+	}
+	verifier, err := verify.NewVerifier(trustedMaterial, verify.WithTransparencyLog(1), verify.WithIntegratedTimestamps(1))
+	if err != nil {
+		return err
+	}
+	var policyOptions []verify.PolicyOption
+	for _, identity := range v.Identities {
+		subjectRegex := ""
+		if identity.SubjectRegex != nil {
+			subjectRegex = identity.SubjectRegex.String()
+		}
+		certificateIdentity, err := verify.NewShortCertificateIdentity(identity.Issuer, "", identity.Subject, subjectRegex)
+		if err != nil {
+			return fmt.Errorf("invalid identity: %w", err)
+		}
+		policyOptions = append(policyOptions, verify.WithCertificateIdentity(certificateIdentity))
+	}
+	if _, err := verifier.Verify(entity, verify.NewPolicy(verify.WithArtifact(bytes.NewReader(payload)), policyOptions...)); err != nil {
+		return fmt.Errorf("keyless signature is not trusted: %w", err)
+	}
+	return nil
+}
+
+// trustedMaterial returns the certificate authorities and transparency logs trusted by the verifier. The intermediate
+// certificates of the chain of a signature are trusted the same as the intermediate certificates of the verifier,
+// since the certificates are verified up to the roots.
+func (v *Verifier) trustedMaterial(chain []*x509.Certificate) (root.TrustedMaterial, error) {
+	intermediates := slices.Concat(v.Intermediates, chain)
+	var authorities []root.CertificateAuthority
+	for _, cert := range v.Roots {
+		authorities = append(authorities, &root.FulcioCertificateAuthority{Root: cert, Intermediates: intermediates})
+	}
+	logs := map[string]*root.TransparencyLog{}
+	for _, key := range v.TransparencyLogKeys {
+		der, err := x509.MarshalPKIXPublicKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transparency log public key: %w", err)
+		}
+		// the ID of a log is the digest of its public key, which its entries reference
+		id := sha256.Sum256(der)
+		logs[hex.EncodeToString(id[:])] = &root.TransparencyLog{
+			ID:        id[:],
+			PublicKey: key,
+			HashFunc:  crypto.SHA256,
+			// the public keys are trusted regardless of the time of the entries
+			ValidityPeriodStart: time.Unix(0, 0),
+			SignatureHashFunc:   crypto.SHA256,
+		}
+	}
+	return root.NewTrustedRoot(root.TrustedRootMediaType01, authorities, nil, nil, logs)
+}
+
+// verifySignature verifies the signature of the message with the public key, the same as cosign does, i.e. ECDSA and
+// RSA signatures are of the SHA-256 digest of the message
+func verifySignature(key crypto.PublicKey, message, signature []byte) error {
+	verifier, err := sigsignature.LoadVerifier(key, crypto.SHA256)
+	if err != nil {
+		return err
+	}
+	return verifier.VerifySignature(bytes.NewReader(signature), bytes.NewReader(message))
+}
+
+// VerifyBlob verifies the base64 encoded signature of a blob with a public key, e.g. a signature created by cosign
+// sign-blob
+func VerifyBlob(key crypto.PublicKey, blob []byte, signature string) error {
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(signature))
+	if err != nil || len(sig) == 0 {
+		return errors.New("invalid signature")
+	}
+	return verifySignature(key, blob, sig)
+}
+
+// ParsePublicKey parses a PEM encoded public key
+func ParsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM encoded public key found")
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+// ParseCertificateAuthorities parses PEM encoded certificates into the self-signed root certificates and the
+// intermediate certificates
+func ParseCertificateAuthorities(data []byte) ([]*x509.Certificate, []*x509.Certificate, error) {
+	certs, err := parseCertificates(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	var roots, intermediates []*x509.Certificate
+	for _, cert := range certs {
+		if bytes.Equal(cert.RawIssuer, cert.RawSubject) && cert.CheckSignatureFrom(cert) == nil {
+			roots = append(roots, cert)
+		} else {
+			intermediates = append(intermediates, cert)
+		}
+	}
+	if len(roots) == 0 {
+		return nil, nil, errors.New("no root certificate found")
+	}
+	return roots, intermediates, nil
+}
+
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return certs, nil
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+}
@@ -0,0 +1,280 @@
+package cosign
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRegistry serves the manifests and blobs of a repository, and requires a bearer token
+type fakeRegistry struct {
+	manifests map[string][]byte
+	blobs     map[string][]byte
+	server    *httptest.Server
+}
+
+func newFakeRegistry(t *testing.T) *fakeRegistry {
+	r := &fakeRegistry{manifests: map[string][]byte{}, blobs: map[string][]byte{}}
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/token" {
+			assert.Equal(t, "repository:team/app:pull", req.URL.Query().Get("scope"))
+			w.Write([]byte(`{"token":"secret"}`))
+			return
+		}
+		if req.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry"`, r.server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		path := strings.TrimPrefix(req.URL.Path, "/v2/team/app/")
+		var data []byte
+		var ok bool
+		if name, found := strings.CutPrefix(path, "manifests/"); found {
+			data, ok = r.manifests[name]
+		} else if digest, found := strings.CutPrefix(path, "blobs/"); found {
+			data, ok = r.blobs[digest]
+		}
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	}))
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+func (r *fakeRegistry) image(tag string) string {
+	u, _ := url.Parse(r.server.URL)
+	return u.Host + "/team/app:" + tag
+}
+
+// push pushes an image manifest, and returns its digest
+func (r *fakeRegistry) push(tag string) string {
+	data := []byte(fmt.Sprintf(`{"schemaVersion":2,"annotations":{"tag":"%s"},"layers":[]}`, tag))
+	r.manifests[tag] = data
+	return digestOf(data)
+}
+
+// sign pushes the signature manifest of the digest with a layer for each of the signatures
+func (r *fakeRegistry) sign(digest string, layers ...descriptor) {
+	data, _ := json.Marshal(manifest{Layers: layers})
+	r.manifests[strings.Replace(digest, ":", "-", 1)+".sig"] = data
+}
+
+func (r *fakeRegistry) pushBlob(data []byte) descriptor {
+	digest := digestOf(data)
+	r.blobs[digest] = data
+	return descriptor{MediaType: SimpleSigningMediaType, Digest: digest, Size: int64(len(data))}
+}
+
+func digestOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func newPayload(digest string) []byte {
+	return []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"app"},"image":{"docker-manifest-digest":"%s"},"type":"cosign container image signature"},"optional":null}`, digest))
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, message []byte) []byte {
+	digest := sha256.Sum256(message)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	return signature
+}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+func TestVerifyPublicKey(t *testing.T) {
+	registry := newFakeRegistry(t)
+	key := newKey(t)
+	signed := registry.push("signed")
+	layer := registry.pushBlob(newPayload(signed))
+	layer.Annotations = map[string]string{SignatureAnnotation: base64.StdEncoding.EncodeToString(sign(t, key, newPayload(signed)))}
+	registry.sign(signed, layer)
+	registry.push("unsigned")
+	otherKey := registry.push("other-key")
+	layer = registry.pushBlob(newPayload(otherKey))
+	layer.Annotations = map[string]string{SignatureAnnotation: base64.StdEncoding.EncodeToString(sign(t, newKey(t), newPayload(otherKey)))}
+	registry.sign(otherKey, layer)
+	// the signature of an image does not verify another image
+	copied := registry.push("copied")
+	registry.sign(copied, registry.manifestLayers(signed)...)
+
+	verifier := &Verifier{PublicKeys: []crypto.PublicKey{&key.PublicKey}, Insecure: true}
+	digest, err := verifier.Verify(context.TODO(), registry.image("signed"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, signed, digest)
+
+	_, err = verifier.Verify(context.TODO(), registry.image("unsigned"), nil)
+	assert.True(t, IsVerificationError(err))
+	assert.Contains(t, err.Error(), "no signatures found")
+	_, err = verifier.Verify(context.TODO(), registry.image("other-key"), nil)
+	assert.True(t, IsVerificationError(err))
+	assert.Contains(t, err.Error(), "signature does not match any public key")
+	_, err = verifier.Verify(context.TODO(), registry.image("copied"), nil)
+	assert.True(t, IsVerificationError(err))
+	assert.Contains(t, err.Error(), "signature payload does not match the image")
+
+	// errors accessing the registry are not verification errors
+	_, err = verifier.Verify(context.TODO(), registry.image("missing"), nil)
+	assert.Error(t, err)
+	assert.False(t, IsVerificationError(err))
+}
+
+func (r *fakeRegistry) manifestLayers(digest string) []descriptor {
+	var m manifest
+	_ = json.Unmarshal(r.manifests[strings.Replace(digest, ":", "-", 1)+".sig"], &m)
+	return m.Layers
+}
+
+func TestVerifyKeyless(t *testing.T) {
+	registry := newFakeRegistry(t)
+	now := time.Now()
+
+	caKey := newKey(t)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "fulcio"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, _ := x509.ParseCertificate(caDER)
+	roots, intermediates, err := ParseCertificateAuthorities(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}))
+	require.NoError(t, err)
+
+	// the certificate of the signature expired, but was valid at the time of the transparency log entry
+	signingKey := newKey(t)
+	issuer, _ := asn1.Marshal("https://token.actions.githubusercontent.com")
+	workflow, _ := url.Parse("https://github.com/team/app/.github/workflows/release.yaml@refs/heads/main")
+	certDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber:    big.NewInt(2),
+		NotBefore:       now.Add(-40 * time.Minute),
+		NotAfter:        now.Add(-30 * time.Minute),
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		URIs:            []*url.URL{workflow},
+		ExtraExtensions: []pkix.Extension{{Id: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}, Value: issuer}},
+	}, caCert, &signingKey.PublicKey, caKey)
+	require.NoError(t, err)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+
+	digest := registry.push("keyless")
+	payload := newPayload(digest)
+	signature := sign(t, signingKey, payload)
+	payloadHash := sha256.Sum256(payload)
+	body, _ := json.Marshal(map[string]any{
+		"apiVersion": "0.0.1",
+		"kind":       "hashedrekord",
+		"spec": map[string]any{
+			"data":      map[string]any{"hash": map[string]any{"algorithm": "sha256", "value": hex.EncodeToString(payloadHash[:])}},
+			"signature": map[string]any{"content": base64.StdEncoding.EncodeToString(signature), "publicKey": map[string]any{"content": base64.StdEncoding.EncodeToString(certPEM)}},
+		},
+	})
+	rekorKey := newKey(t)
+	rekorDER, _ := x509.MarshalPKIXPublicKey(&rekorKey.PublicKey)
+	logID := sha256.Sum256(rekorDER)
+	entry := bundlePayload{Body: base64.StdEncoding.EncodeToString(body), IntegratedTime: now.Add(-35 * time.Minute).Unix(), LogID: hex.EncodeToString(logID[:]), LogIndex: 42}
+	canonical, _ := json.Marshal(entry)
+	bundleJSON, _ := json.Marshal(bundle{SignedEntryTimestamp: sign(t, rekorKey, canonical), Payload: entry})
+
+	layer := registry.pushBlob(payload)
+	layer.Annotations = map[string]string{
+		SignatureAnnotation:   base64.StdEncoding.EncodeToString(signature),
+		CertificateAnnotation: string(certPEM),
+		BundleAnnotation:      string(bundleJSON),
+	}
+	registry.sign(digest, layer)
+
+	verifier := &Verifier{
+		Identities: []Identity{{
+			Issuer:       "https://token.actions.githubusercontent.com",
+			SubjectRegex: regexp.MustCompile(`^https://github\.com/team/app/`),
+		}},
+		Roots:               roots,
+		Intermediates:       intermediates,
+		TransparencyLogKeys: []crypto.PublicKey{&rekorKey.PublicKey},
+		Insecure:            true,
+	}
+	_, err = verifier.Verify(context.TODO(), registry.image("keyless"), nil)
+	require.NoError(t, err)
+
+	verifier.Identities[0].Issuer = "https://accounts.google.com"
+	_, err = verifier.Verify(context.TODO(), registry.image("keyless"), nil)
+	assert.True(t, IsVerificationError(err))
+	assert.Contains(t, err.Error(), "keyless signature is not trusted")
+	assert.Contains(t, err.Error(), "no matching CertificateIdentity")
+
+	verifier.Identities[0].Issuer = "https://token.actions.githubusercontent.com"
+	verifier.TransparencyLogKeys = []crypto.PublicKey{&newKey(t).PublicKey}
+	_, err = verifier.Verify(context.TODO(), registry.image("keyless"), nil)
+	assert.True(t, IsVerificationError(err))
+	assert.Contains(t, err.Error(), "not enough verified log entries from transparency log")
+
+	// the certificate must be issued by the certificate authorities
+	verifier.TransparencyLogKeys = []crypto.PublicKey{&rekorKey.PublicKey}
+	otherKey := newKey(t)
+	otherDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &otherKey.PublicKey, otherKey)
+	require.NoError(t, err)
+	verifier.Roots, _, err = ParseCertificateAuthorities(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: otherDER}))
+	require.NoError(t, err)
+	_, err = verifier.Verify(context.TODO(), registry.image("keyless"), nil)
+	assert.True(t, IsVerificationError(err))
+	assert.Contains(t, err.Error(), "keyless signature is not trusted")
+}
+
+func TestParseReference(t *testing.T) {
+	for image, expected := range map[string]reference{
+		"nginx":                       {registry: "docker.io", repository: "library/nginx", tag: "latest"},
+		"argoproj/rollouts-demo:blue": {registry: "docker.io", repository: "argoproj/rollouts-demo", tag: "blue"},
+		"localhost:5000/app":          {registry: "localhost:5000", repository: "app", tag: "latest"},
+		"ghcr.io/team/app:v1@sha256:" + strings.Repeat("a", 64): {registry: "ghcr.io", repository: "team/app", tag: "v1", digest: "sha256:" + strings.Repeat("a", 64)},
+	} {
+		ref, err := parseReference(image)
+		require.NoError(t, err)
+		assert.Equal(t, expected, ref, image)
+	}
+	_, err := parseReference("app@sha256:abc")
+	assert.Error(t, err)
+}
+
+func TestParseDockerConfig(t *testing.T) {
+	credentials, err := ParseDockerConfig([]byte(`{"auths":{"https://index.docker.io/v1/":{"auth":"` + base64.StdEncoding.EncodeToString([]byte("user:pass")) + `"},"ghcr.io":{"username":"bot","password":"token"}}}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Credentials{
+		"docker.io": {Username: "user", Password: "pass"},
+		"ghcr.io":   {Username: "bot", Password: "token"},
+	}, credentials)
+}
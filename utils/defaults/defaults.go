@@ -4,6 +4,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -30,6 +31,12 @@ const (
 	DefaultScaleDownDelaySeconds = int32(30)
 	// DefaultAbortScaleDownDelaySeconds default seconds before scaling down old replicaset after switching services
 	DefaultAbortScaleDownDelaySeconds = int32(30)
+	// DefaultScaleDownVerificationTimeoutSeconds default seconds to wait for the scale down verification of an old replicaset
+	DefaultScaleDownVerificationTimeoutSeconds = int32(300)
+	// DefaultDrainProbeTimeoutSeconds default seconds after which a drain probe request times out
+	DefaultDrainProbeTimeoutSeconds = int32(1)
+	// DefaultPostPromotionSmokeTestWindowSeconds default seconds the post-promotion smoke test has to succeed
+	DefaultPostPromotionSmokeTestWindowSeconds = int32(300)
 	// DefaultAutoPromotionEnabled default value for auto promoting a blueGreen strategy
 	DefaultAutoPromotionEnabled = true
 	// DefaultConsecutiveErrorLimit is the default number times a metric can error in sequence before
@@ -48,8 +55,17 @@ const (
 	DefaultRolloutsConfigMapName = "argo-rollouts-config"
 	// DefaultRolloutPluginFolder is the default location where plugins will be downloaded and/or moved to.
 	DefaultRolloutPluginFolder = "plugin-bin"
+	// DefaultFieldManager is the field manager the controller uses to server-side apply and to update the
+	// ReplicaSets and Services it manages
+	DefaultFieldManager = "argo-rollouts-controller"
 	// DefaultDescribeTagsLimit is the default number resources (ARNs) in a single call
 	DefaultDescribeTagsLimit int = 20
+	// DefaultRolloutStatusUpdateWindow is the default window in which status updates of a rollout that only change
+	// its replica counts are coalesced. Zero disables the coalescing.
+	DefaultRolloutStatusUpdateWindow = time.Duration(0)
+	// DefaultEventThrottleWindow is the default window in which repeated events with the same reason for the same
+	// object are suppressed. Zero disables the throttling.
+	DefaultEventThrottleWindow = time.Duration(0)
 	// Kubernetes_DNS_Limit is the maximum length of a DNS name in Kubernetes. Currently used for Analysis Job names
 	Kubernetes_DNS_Limit int = 63
 )
@@ -70,6 +86,7 @@ const (
 
 var (
 	defaultVerifyTargetGroup     = false
+	defaultIstioVerifyWeight     = false
 	traefikAPIGroup              = DefaultTraefikAPIGroup
 	traefikVersion               = DefaultTraefikVersion
 	istioAPIVersion              = DefaultIstioVersion
@@ -77,9 +94,19 @@ var (
 	smiAPIVersion                = DefaultSMITrafficSplitVersion
 	targetGroupBindingAPIVersion = DefaultTargetGroupBindingAPIVersion
 	albTagKeyResourceID          = DefaultAlbTagKeyResourceID
+	awsRoleARN                   = ""
+	awsWebIdentitySource         = ""
 	appmeshCRDVersion            = DefaultAppMeshCRDVersion
 	defaultMetricCleanupDelay    = DefaultMetricCleanupDelay
 	defaultDescribeTagsLimit     = DefaultDescribeTagsLimit
+	rolloutStatusUpdateWindow    = DefaultRolloutStatusUpdateWindow
+	eventThrottleWindow          = DefaultEventThrottleWindow
+	eventThrottleReasonWindows   map[string]time.Duration
+	compactReplicaSetHistory     = false
+	syncActionsEnabled           = false
+	// namespaceDefaults are the defaults of the rollouts of the namespaces with a RolloutControllerConfig
+	namespaceDefaults     = map[string]v1alpha1.RolloutDefaults{}
+	namespaceDefaultsLock sync.RWMutex
 )
 
 const (
@@ -118,6 +145,9 @@ func GetReplicasOrDefault(replicas *int32) int32 {
 // GetRevisionHistoryLimitOrDefault returns the specified number of replicas in a rollout or the default number
 func GetRevisionHistoryLimitOrDefault(rollout *v1alpha1.Rollout) int32 {
 	if rollout.Spec.RevisionHistoryLimit == nil {
+		if limit := getNamespaceDefaults(rollout.Namespace).RevisionHistoryLimit; limit != nil {
+			return *limit
+		}
 		return DefaultRevisionHistoryLimit
 	}
 	return *rollout.Spec.RevisionHistoryLimit
@@ -169,9 +199,46 @@ func GetProgressDeadlineSecondsOrDefault(rollout *v1alpha1.Rollout) int32 {
 	if rollout.Spec.ProgressDeadlineSeconds != nil {
 		return *rollout.Spec.ProgressDeadlineSeconds
 	}
+	if deadline := getNamespaceDefaults(rollout.Namespace).ProgressDeadlineSeconds; deadline != nil {
+		return *deadline
+	}
 	return DefaultProgressDeadlineSeconds
 }
 
+// GetCurrentStepProgressDeadlineSecondsOrDefault returns the progress deadline of the current canary
+// step, falling back to the progress deadline of the rollout
+func GetCurrentStepProgressDeadlineSecondsOrDefault(rollout *v1alpha1.Rollout) int32 {
+	if rollout.Spec.Strategy.Canary != nil {
+		currentStepIndex := int32(0)
+		if rollout.Status.CurrentStepIndex != nil {
+			currentStepIndex = *rollout.Status.CurrentStepIndex
+		}
+		steps := rollout.Spec.Strategy.Canary.Steps
+		if int(currentStepIndex) < len(steps) && steps[currentStepIndex].ProgressDeadline != nil {
+			return steps[currentStepIndex].ProgressDeadline.Seconds
+		}
+	}
+	return GetProgressDeadlineSecondsOrDefault(rollout)
+}
+
+// GetScaleDownVerificationTimeoutOrDefault returns the maximum duration to wait for the scale down verification
+func GetScaleDownVerificationTimeoutOrDefault(rollout *v1alpha1.Rollout) time.Duration {
+	timeoutSeconds := DefaultScaleDownVerificationTimeoutSeconds
+	if rollout.Spec.Strategy.Canary != nil && rollout.Spec.Strategy.Canary.ScaleDownVerification != nil && rollout.Spec.Strategy.Canary.ScaleDownVerification.TimeoutSeconds != nil {
+		timeoutSeconds = *rollout.Spec.Strategy.Canary.ScaleDownVerification.TimeoutSeconds
+	}
+	return time.Duration(timeoutSeconds) * time.Second
+}
+
+// GetDrainProbeTimeoutOrDefault returns the duration after which a drain probe request times out
+func GetDrainProbeTimeoutOrDefault(probe *v1alpha1.DrainProbe) time.Duration {
+	timeoutSeconds := DefaultDrainProbeTimeoutSeconds
+	if probe.TimeoutSeconds > 0 {
+		timeoutSeconds = probe.TimeoutSeconds
+	}
+	return time.Duration(timeoutSeconds) * time.Second
+}
+
 func GetExperimentProgressDeadlineSecondsOrDefault(e *v1alpha1.Experiment) int32 {
 	if e.Spec.ProgressDeadlineSeconds != nil {
 		return *e.Spec.ProgressDeadlineSeconds
@@ -188,15 +255,19 @@ func GetExperimentScaleDownDelaySecondsOrDefault(e *v1alpha1.Experiment) int32 {
 
 func GetScaleDownDelaySecondsOrDefault(rollout *v1alpha1.Rollout) time.Duration {
 	var delaySeconds int32
+	defaultDelaySeconds := DefaultScaleDownDelaySeconds
+	if delay := getNamespaceDefaults(rollout.Namespace).ScaleDownDelaySeconds; delay != nil {
+		defaultDelaySeconds = *delay
+	}
 	if rollout.Spec.Strategy.BlueGreen != nil {
-		delaySeconds = DefaultScaleDownDelaySeconds
+		delaySeconds = defaultDelaySeconds
 		if rollout.Spec.Strategy.BlueGreen.ScaleDownDelaySeconds != nil {
 			delaySeconds = *rollout.Spec.Strategy.BlueGreen.ScaleDownDelaySeconds
 		}
 	}
 	if rollout.Spec.Strategy.Canary != nil {
 		if rollout.Spec.Strategy.Canary.TrafficRouting != nil {
-			delaySeconds = DefaultScaleDownDelaySeconds
+			delaySeconds = defaultDelaySeconds
 			if rollout.Spec.Strategy.Canary.ScaleDownDelaySeconds != nil {
 				delaySeconds = *rollout.Spec.Strategy.Canary.ScaleDownDelaySeconds
 			}
@@ -205,6 +276,34 @@ func GetScaleDownDelaySecondsOrDefault(rollout *v1alpha1.Rollout) time.Duration
 	return time.Duration(delaySeconds) * time.Second
 }
 
+// GetScaleDownDelaySecondsForRevisionOrDefault returns the scale down delay of the old ReplicaSet which is the
+// given number of revisions behind the current revision, honoring scaleDownDelayOverrides and falling back to
+// GetScaleDownDelaySecondsOrDefault
+func GetScaleDownDelaySecondsForRevisionOrDefault(rollout *v1alpha1.Rollout, revision int32) time.Duration {
+	var overrides []v1alpha1.ScaleDownDelayOverride
+	if rollout.Spec.Strategy.BlueGreen != nil {
+		overrides = rollout.Spec.Strategy.BlueGreen.ScaleDownDelayOverrides
+	}
+	if rollout.Spec.Strategy.Canary != nil && rollout.Spec.Strategy.Canary.TrafficRouting != nil {
+		overrides = rollout.Spec.Strategy.Canary.ScaleDownDelayOverrides
+	}
+	for _, override := range overrides {
+		if override.Revision == revision {
+			return time.Duration(override.DelaySeconds) * time.Second
+		}
+	}
+	return GetScaleDownDelaySecondsOrDefault(rollout)
+}
+
+// GetPostPromotionSmokeTestWindowOrDefault returns the duration the post-promotion smoke test has to succeed
+func GetPostPromotionSmokeTestWindowOrDefault(smokeTest *v1alpha1.PostPromotionSmokeTest) time.Duration {
+	windowSeconds := DefaultPostPromotionSmokeTestWindowSeconds
+	if smokeTest.WindowSeconds != nil {
+		windowSeconds = *smokeTest.WindowSeconds
+	}
+	return time.Duration(windowSeconds) * time.Second
+}
+
 // GetAbortScaleDownDelaySecondsOrDefault returns the duration to delay the scale down of
 // the canary/preview ReplicaSet in an abort situation. A nil value indicates it should not
 // scale down at all (abortScaleDownDelaySeconds: 0). A value of 0 indicates it should scale down
@@ -279,6 +378,16 @@ func VerifyTargetGroup() bool {
 	return defaultVerifyTargetGroup
 }
 
+// SetIstioVerifyWeight sets whether Istio weights are verified from the status of the VirtualServices
+func SetIstioVerifyWeight(b bool) {
+	defaultIstioVerifyWeight = b
+}
+
+// IstioVerifyWeight returns whether Istio weights are verified from the status of the VirtualServices
+func IstioVerifyWeight() bool {
+	return defaultIstioVerifyWeight
+}
+
 func SetIstioAPIVersion(apiVersion string) {
 	istioAPIVersion = apiVersion
 }
@@ -335,6 +444,29 @@ func GetalbTagKeyResourceID() string {
 	return albTagKeyResourceID
 }
 
+// SetAWSRoleARN sets the IAM role the controller assumes when calling the AWS APIs
+func SetAWSRoleARN(roleARN string) {
+	awsRoleARN = roleARN
+}
+
+// GetAWSRoleARN returns the IAM role the controller assumes when calling the AWS APIs, or an empty string to use
+// the credentials of its environment
+func GetAWSRoleARN() string {
+	return awsRoleARN
+}
+
+// SetAWSWebIdentitySource sets the source of the web identity token the controller exchanges for the credentials of
+// its IAM role when it runs outside of AWS, i.e. gcp or azure
+func SetAWSWebIdentitySource(source string) {
+	awsWebIdentitySource = source
+}
+
+// GetAWSWebIdentitySource returns the source of the web identity token of the controller, or an empty string to use
+// the credentials of its environment
+func GetAWSWebIdentitySource() string {
+	return awsWebIdentitySource
+}
+
 func SetTargetGroupBindingAPIVersion(apiVersion string) {
 	targetGroupBindingAPIVersion = apiVersion
 }
@@ -366,3 +498,73 @@ func GetDescribeTagsLimit() int {
 func SetDescribeTagsLimit(limit int) {
 	defaultDescribeTagsLimit = limit
 }
+
+// GetRolloutStatusUpdateWindow returns the window in which status updates of a rollout that only change its
+// replica counts are coalesced
+func GetRolloutStatusUpdateWindow() time.Duration {
+	return rolloutStatusUpdateWindow
+}
+
+// SetRolloutStatusUpdateWindow sets the window in which status updates of a rollout that only change its replica
+// counts are coalesced
+func SetRolloutStatusUpdateWindow(window time.Duration) {
+	rolloutStatusUpdateWindow = window
+}
+
+// GetEventThrottleWindow returns the window in which repeated events with the given reason for the same object are
+// suppressed
+func GetEventThrottleWindow(reason string) time.Duration {
+	if window, ok := eventThrottleReasonWindows[reason]; ok {
+		return window
+	}
+	return eventThrottleWindow
+}
+
+// SetEventThrottleWindows sets the window in which repeated events with the same reason for the same object are
+// suppressed, along with the windows of specific event reasons which take precedence over it
+func SetEventThrottleWindows(window time.Duration, reasonWindows map[string]time.Duration) {
+	eventThrottleWindow = window
+	eventThrottleReasonWindows = reasonWindows
+}
+
+// GetCompactReplicaSetHistory returns whether the scaled down ReplicaSets of Rollouts are compacted in the informer
+// cache
+func GetCompactReplicaSetHistory() bool {
+	return compactReplicaSetHistory
+}
+
+// SetCompactReplicaSetHistory sets whether the scaled down ReplicaSets of Rollouts are compacted in the informer cache
+func SetCompactReplicaSetHistory(compact bool) {
+	compactReplicaSetHistory = compact
+}
+
+// GetSyncActionsEnabled returns whether the controller performs the actions requested by the sync-action annotation
+// of the rollouts
+func GetSyncActionsEnabled() bool {
+	return syncActionsEnabled
+}
+
+// SetSyncActionsEnabled sets whether the controller performs the actions requested by the sync-action annotation of
+// the rollouts
+func SetSyncActionsEnabled(enabled bool) {
+	syncActionsEnabled = enabled
+}
+
+// SetNamespaceDefaults sets the defaults of the rollouts of a namespace, which take precedence over the defaults of
+// the controller. The defaults of the namespace are removed when nil
+func SetNamespaceDefaults(namespace string, rolloutDefaults *v1alpha1.RolloutDefaults) {
+	namespaceDefaultsLock.Lock()
+	defer namespaceDefaultsLock.Unlock()
+	if rolloutDefaults == nil {
+		delete(namespaceDefaults, namespace)
+		return
+	}
+	namespaceDefaults[namespace] = *rolloutDefaults
+}
+
+// getNamespaceDefaults returns the defaults of the rollouts of a namespace
+func getNamespaceDefaults(namespace string) v1alpha1.RolloutDefaults {
+	namespaceDefaultsLock.RLock()
+	defer namespaceDefaultsLock.RUnlock()
+	return namespaceDefaults[namespace]
+}
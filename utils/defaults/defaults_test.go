@@ -157,6 +157,28 @@ func TestGetProgressDeadlineSecondsOrDefault(t *testing.T) {
 	assert.Equal(t, DefaultProgressDeadlineSeconds, GetProgressDeadlineSecondsOrDefault(rolloutDefaultValue))
 }
 
+func TestGetCurrentStepProgressDeadlineSecondsOrDefault(t *testing.T) {
+	seconds := int32(2)
+	ro := &v1alpha1.Rollout{
+		Spec: v1alpha1.RolloutSpec{
+			ProgressDeadlineSeconds: &seconds,
+			Strategy: v1alpha1.RolloutStrategy{
+				Canary: &v1alpha1.CanaryStrategy{
+					Steps: []v1alpha1.CanaryStep{
+						{SetWeight: ptr.To[int32](10), ProgressDeadline: &v1alpha1.StepProgressDeadline{Seconds: 60}},
+						{SetWeight: ptr.To[int32](20)},
+					},
+				},
+			},
+		},
+	}
+	assert.Equal(t, int32(60), GetCurrentStepProgressDeadlineSecondsOrDefault(ro))
+	ro.Status.CurrentStepIndex = ptr.To[int32](1)
+	assert.Equal(t, seconds, GetCurrentStepProgressDeadlineSecondsOrDefault(ro))
+	ro.Status.CurrentStepIndex = ptr.To[int32](2)
+	assert.Equal(t, seconds, GetCurrentStepProgressDeadlineSecondsOrDefault(ro))
+}
+
 func TestGetScaleDownDelaySecondsOrDefault(t *testing.T) {
 	{
 		scaleDownDelaySeconds := int32(60)
@@ -214,6 +236,63 @@ func TestGetScaleDownDelaySecondsOrDefault(t *testing.T) {
 	}
 }
 
+func TestGetScaleDownVerificationTimeoutOrDefault(t *testing.T) {
+	ro := &v1alpha1.Rollout{}
+	ro.Spec.Strategy.Canary = &v1alpha1.CanaryStrategy{}
+	assert.Equal(t, time.Duration(DefaultScaleDownVerificationTimeoutSeconds)*time.Second, GetScaleDownVerificationTimeoutOrDefault(ro))
+	ro.Spec.Strategy.Canary.ScaleDownVerification = &v1alpha1.ScaleDownVerification{}
+	assert.Equal(t, time.Duration(DefaultScaleDownVerificationTimeoutSeconds)*time.Second, GetScaleDownVerificationTimeoutOrDefault(ro))
+	ro.Spec.Strategy.Canary.ScaleDownVerification.TimeoutSeconds = ptr.To[int32](60)
+	assert.Equal(t, time.Minute, GetScaleDownVerificationTimeoutOrDefault(ro))
+}
+
+func TestGetDrainProbeTimeoutOrDefault(t *testing.T) {
+	assert.Equal(t, time.Duration(DefaultDrainProbeTimeoutSeconds)*time.Second, GetDrainProbeTimeoutOrDefault(&v1alpha1.DrainProbe{}))
+	assert.Equal(t, 5*time.Second, GetDrainProbeTimeoutOrDefault(&v1alpha1.DrainProbe{TimeoutSeconds: 5}))
+}
+
+func TestGetScaleDownDelaySecondsForRevisionOrDefault(t *testing.T) {
+	overrides := []v1alpha1.ScaleDownDelayOverride{{Revision: 1, DelaySeconds: 86400}}
+	{
+		blueGreen := &v1alpha1.Rollout{
+			Spec: v1alpha1.RolloutSpec{
+				Strategy: v1alpha1.RolloutStrategy{
+					BlueGreen: &v1alpha1.BlueGreenStrategy{
+						ScaleDownDelayOverrides: overrides,
+					},
+				},
+			},
+		}
+		assert.Equal(t, 24*time.Hour, GetScaleDownDelaySecondsForRevisionOrDefault(blueGreen, 1))
+		assert.Equal(t, time.Duration(DefaultScaleDownDelaySeconds)*time.Second, GetScaleDownDelaySecondsForRevisionOrDefault(blueGreen, 2))
+	}
+	{
+		canaryNoTrafficRouting := &v1alpha1.Rollout{
+			Spec: v1alpha1.RolloutSpec{
+				Strategy: v1alpha1.RolloutStrategy{
+					Canary: &v1alpha1.CanaryStrategy{
+						ScaleDownDelayOverrides: overrides,
+					},
+				},
+			},
+		}
+		assert.Equal(t, time.Duration(0), GetScaleDownDelaySecondsForRevisionOrDefault(canaryNoTrafficRouting, 1))
+	}
+	{
+		canaryWithTrafficRouting := &v1alpha1.Rollout{
+			Spec: v1alpha1.RolloutSpec{
+				Strategy: v1alpha1.RolloutStrategy{
+					Canary: &v1alpha1.CanaryStrategy{
+						ScaleDownDelayOverrides: overrides,
+						TrafficRouting:          &v1alpha1.RolloutTrafficRouting{},
+					},
+				},
+			},
+		}
+		assert.Equal(t, 24*time.Hour, GetScaleDownDelaySecondsForRevisionOrDefault(canaryWithTrafficRouting, 1))
+	}
+}
+
 func TestGetAbortScaleDownDelaySecondsOrDefault(t *testing.T) {
 	{
 		abortScaleDownDelaySeconds := int32(60)
@@ -382,6 +461,11 @@ func TestSetDefaults(t *testing.T) {
 	SetVerifyTargetGroup(false)
 	assert.False(t, VerifyTargetGroup())
 
+	SetIstioVerifyWeight(true)
+	assert.True(t, IstioVerifyWeight())
+	SetIstioVerifyWeight(false)
+	assert.False(t, IstioVerifyWeight())
+
 	SetIstioAPIVersion("v1alpha9")
 	assert.Equal(t, "v1alpha9", GetIstioAPIVersion())
 	SetIstioAPIVersion(DefaultIstioVersion)
@@ -429,4 +513,54 @@ func TestSetDefaults(t *testing.T) {
 	assert.Equal(t, DefaultDescribeTagsLimit, GetDescribeTagsLimit())
 	SetDescribeTagsLimit(2)
 	assert.Equal(t, 2, GetDescribeTagsLimit())
+
+	assert.Equal(t, DefaultRolloutStatusUpdateWindow, GetRolloutStatusUpdateWindow())
+	SetRolloutStatusUpdateWindow(time.Second)
+	assert.Equal(t, time.Second, GetRolloutStatusUpdateWindow())
+	SetRolloutStatusUpdateWindow(DefaultRolloutStatusUpdateWindow)
+
+	assert.Equal(t, DefaultEventThrottleWindow, GetEventThrottleWindow("RolloutAborted"))
+	SetEventThrottleWindows(time.Minute, map[string]time.Duration{"RolloutAborted": 10 * time.Minute})
+	assert.Equal(t, 10*time.Minute, GetEventThrottleWindow("RolloutAborted"))
+	assert.Equal(t, time.Minute, GetEventThrottleWindow("RolloutUpdated"))
+	SetEventThrottleWindows(DefaultEventThrottleWindow, nil)
+
+	assert.False(t, GetCompactReplicaSetHistory())
+	SetCompactReplicaSetHistory(true)
+	assert.True(t, GetCompactReplicaSetHistory())
+	SetCompactReplicaSetHistory(false)
+}
+
+func TestSetNamespaceDefaults(t *testing.T) {
+	SetNamespaceDefaults("team-a", &v1alpha1.RolloutDefaults{
+		ProgressDeadlineSeconds: ptr.To[int32](1200),
+		RevisionHistoryLimit:    ptr.To[int32](3),
+		ScaleDownDelaySeconds:   ptr.To[int32](60),
+	})
+	defer SetNamespaceDefaults("team-a", nil)
+
+	ro := &v1alpha1.Rollout{}
+	ro.Namespace = "team-a"
+	ro.Spec.Strategy.BlueGreen = &v1alpha1.BlueGreenStrategy{}
+	assert.Equal(t, int32(1200), GetProgressDeadlineSecondsOrDefault(ro))
+	assert.Equal(t, int32(3), GetRevisionHistoryLimitOrDefault(ro))
+	assert.Equal(t, time.Minute, GetScaleDownDelaySecondsOrDefault(ro))
+
+	// the fields of the rollout take precedence over the defaults of the namespace
+	ro.Spec.ProgressDeadlineSeconds = ptr.To[int32](300)
+	ro.Spec.RevisionHistoryLimit = ptr.To[int32](5)
+	ro.Spec.Strategy.BlueGreen.ScaleDownDelaySeconds = ptr.To[int32](10)
+	assert.Equal(t, int32(300), GetProgressDeadlineSecondsOrDefault(ro))
+	assert.Equal(t, int32(5), GetRevisionHistoryLimitOrDefault(ro))
+	assert.Equal(t, 10*time.Second, GetScaleDownDelaySecondsOrDefault(ro))
+
+	// the defaults of the namespace do not apply to the rollouts of other namespaces
+	other := &v1alpha1.Rollout{}
+	other.Namespace = "team-b"
+	assert.Equal(t, DefaultProgressDeadlineSeconds, GetProgressDeadlineSecondsOrDefault(other))
+	assert.Equal(t, DefaultRevisionHistoryLimit, GetRevisionHistoryLimitOrDefault(other))
+
+	SetNamespaceDefaults("team-a", nil)
+	ro.Spec = v1alpha1.RolloutSpec{}
+	assert.Equal(t, DefaultProgressDeadlineSeconds, GetProgressDeadlineSecondsOrDefault(ro))
 }
@@ -0,0 +1,229 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/annotations"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	timeutil "github.com/argoproj/argo-rollouts/utils/time"
+)
+
+const (
+	// SpecVersion is the version of the CloudEvents specification the events conform to
+	SpecVersion = "1.0"
+	// EventTypePrefix prefixes the types of the events, e.g. io.argoproj.rollouts.rollout.aborted
+	EventTypePrefix = "io.argoproj.rollouts."
+	// DefaultQueueSize is the number of events buffered for the sinks, beyond which events are dropped
+	DefaultQueueSize = 1000
+
+	publishTimeout = 10 * time.Second
+)
+
+// eventTypes are the types of the published events by the reason of the Kubernetes event of the rollout. The events
+// with other reasons are not published.
+var eventTypes = map[string]string{
+	conditions.RolloutUpdatedReason:                            "rollout.updated",
+	conditions.RolloutStepCompletedReason:                      "rollout.step.completed",
+	conditions.RolloutPausedReason:                             "rollout.paused",
+	conditions.RolloutResumedReason:                            "rollout.resumed",
+	conditions.RolloutCompletedReason:                          "rollout.completed",
+	conditions.RolloutAbortedReason:                            "rollout.aborted",
+	conditions.RolloutDegradedReason:                           "rollout.degraded",
+	conditions.RolloutUserActionReason:                         "rollout.user.action",
+	"SwitchService":                                            "rollout.promoted",
+	"AnalysisRun" + string(v1alpha1.AnalysisPhaseSuccessful):   "analysisrun.successful",
+	"AnalysisRun" + string(v1alpha1.AnalysisPhaseFailed):       "analysisrun.failed",
+	"AnalysisRun" + string(v1alpha1.AnalysisPhaseError):        "analysisrun.error",
+	"AnalysisRun" + string(v1alpha1.AnalysisPhaseInconclusive): "analysisrun.inconclusive",
+}
+
+// CloudEvent is a rollout lifecycle event in the structured JSON format of CloudEvents
+type CloudEvent struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            EventData `json:"data"`
+}
+
+// EventData is the data of a rollout lifecycle event
+type EventData struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	UID       string `json:"uid"`
+	// Reason is the reason of the Kubernetes event of the rollout
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+	// Revision is the revision of the rollout
+	Revision        string `json:"revision,omitempty"`
+	PodTemplateHash string `json:"podTemplateHash,omitempty"`
+	StableRS        string `json:"stableRS,omitempty"`
+	StepIndex       *int32 `json:"stepIndex,omitempty"`
+	// Images are the images of the containers of the rollout by container name
+	Images map[string]string `json:"images,omitempty"`
+}
+
+// Sink receives the published events
+type Sink interface {
+	Publish(ctx context.Context, event CloudEvent) error
+	Close() error
+}
+
+// Publisher publishes events to sinks in the background, so that a slow sink does not slow down the reconciliations
+type Publisher struct {
+	sinks []Sink
+	queue chan CloudEvent
+	done  chan struct{}
+	// mu guards closed, so that no event is queued once the queue is closed
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewPublisher returns a publisher to the sinks, which buffers up to queueSize events
+func NewPublisher(sinks []Sink, queueSize int) *Publisher {
+	return &Publisher{
+		sinks: sinks,
+		queue: make(chan CloudEvent, queueSize),
+		done:  make(chan struct{}),
+	}
+}
+
+// Run publishes the queued events until Close is called
+func (p *Publisher) Run() {
+	defer close(p.done)
+	for event := range p.queue {
+		for _, sink := range p.sinks {
+			ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+			if err := sink.Publish(ctx, event); err != nil {
+				log.Warnf("Failed to publish event %s of type %s about %s: %v", event.ID, event.Type, event.Subject, err)
+			}
+			cancel()
+		}
+	}
+}
+
+// Publish queues an event, or drops it if the queue is full
+func (p *Publisher) Publish(event CloudEvent) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return
+	}
+	select {
+	case p.queue <- event:
+	default:
+		log.Warnf("Dropped event %s of type %s about %s: the event queue is full", event.ID, event.Type, event.Subject)
+	}
+}
+
+// Close publishes the queued events and closes the sinks
+func (p *Publisher) Close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	close(p.queue)
+	p.mu.Unlock()
+
+	<-p.done
+	for _, sink := range p.sinks {
+		if err := sink.Close(); err != nil {
+			log.Warnf("Failed to close event sink: %v", err)
+		}
+	}
+}
+
+var publisher atomic.Pointer[Publisher]
+
+// Init publishes the rollout lifecycle events to the sinks with the given URLs. The returned function publishes the
+// queued events and closes the sinks.
+func Init(sinkURLs []string) (func(), error) {
+	var sinks []Sink
+	for _, sinkURL := range sinkURLs {
+		sink, err := NewSink(sinkURL)
+		if err != nil {
+			for _, s := range sinks {
+				_ = s.Close()
+			}
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	p := NewPublisher(sinks, DefaultQueueSize)
+	go p.Run()
+	publisher.Store(p)
+	return func() {
+		publisher.CompareAndSwap(p, nil)
+		p.Close()
+	}, nil
+}
+
+// PublishRolloutEvent publishes a Kubernetes event of a rollout, if Init was called and its reason is a lifecycle
+// event of the rollout. The message of the event is formatted from messageFmt and args like the Kubernetes event.
+func PublishRolloutEvent(object runtime.Object, reason, messageFmt string, args []any) {
+	p := publisher.Load()
+	if p == nil {
+		return
+	}
+	ro, ok := object.(*v1alpha1.Rollout)
+	if !ok {
+		return
+	}
+	if event, ok := NewRolloutEvent(ro, reason, fmt.Sprintf(messageFmt, args...)); ok {
+		p.Publish(event)
+	}
+}
+
+// NewRolloutEvent returns the CloudEvent of a Kubernetes event of a rollout, and whether the reason of the event is a
+// lifecycle event of the rollout
+func NewRolloutEvent(ro *v1alpha1.Rollout, reason, message string) (CloudEvent, bool) {
+	eventType, ok := eventTypes[reason]
+	if !ok {
+		return CloudEvent{}, false
+	}
+	revision, _ := annotations.GetRevisionAnnotation(ro)
+	data := EventData{
+		Namespace:       ro.Namespace,
+		Name:            ro.Name,
+		UID:             string(ro.UID),
+		Reason:          reason,
+		Message:         message,
+		PodTemplateHash: ro.Status.CurrentPodHash,
+		StableRS:        ro.Status.StableRS,
+		StepIndex:       ro.Status.CurrentStepIndex,
+	}
+	if revision > 0 {
+		data.Revision = fmt.Sprint(revision)
+	}
+	for _, c := range ro.Spec.Template.Spec.Containers {
+		if data.Images == nil {
+			data.Images = map[string]string{}
+		}
+		data.Images[c.Name] = c.Image
+	}
+	return CloudEvent{
+		SpecVersion:     SpecVersion,
+		ID:              uuid.NewString(),
+		Source:          fmt.Sprintf("/apis/argoproj.io/v1alpha1/namespaces/%s/rollouts/%s", ro.Namespace, ro.Name),
+		Type:            EventTypePrefix + eventType,
+		Subject:         strings.Join([]string{ro.Namespace, ro.Name}, "/"),
+		Time:            timeutil.Now().UTC(),
+		DataContentType: "application/json",
+		Data:            data,
+	}, true
+}
@@ -0,0 +1,235 @@
+package eventbus
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+)
+
+func newRollout() *v1alpha1.Rollout {
+	return &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "guestbook",
+			Namespace:   "default",
+			UID:         "1234",
+			Annotations: map[string]string{"rollout.argoproj.io/revision": "3"},
+		},
+		Spec: v1alpha1.RolloutSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "guestbook", Image: "argoproj/rollouts-demo:blue"}}},
+			},
+		},
+		Status: v1alpha1.RolloutStatus{
+			CurrentPodHash:   "5cb4fd98cf",
+			StableRS:         "7fc44855c7",
+			CurrentStepIndex: ptr.To[int32](2),
+		},
+	}
+}
+
+type fakeSink struct {
+	mu     sync.Mutex
+	events []CloudEvent
+	closed bool
+}
+
+func (s *fakeSink) Publish(_ context.Context, event CloudEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *fakeSink) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestNewRolloutEvent(t *testing.T) {
+	event, ok := NewRolloutEvent(newRollout(), conditions.RolloutAbortedReason, "Rollout aborted update to revision 3")
+	require.True(t, ok)
+	assert.NotEmpty(t, event.ID)
+	assert.False(t, event.Time.IsZero())
+	assert.Equal(t, CloudEvent{
+		SpecVersion:     "1.0",
+		ID:              event.ID,
+		Source:          "/apis/argoproj.io/v1alpha1/namespaces/default/rollouts/guestbook",
+		Type:            "io.argoproj.rollouts.rollout.aborted",
+		Subject:         "default/guestbook",
+		Time:            event.Time,
+		DataContentType: "application/json",
+		Data: EventData{
+			Namespace:       "default",
+			Name:            "guestbook",
+			UID:             "1234",
+			Reason:          "RolloutAborted",
+			Message:         "Rollout aborted update to revision 3",
+			Revision:        "3",
+			PodTemplateHash: "5cb4fd98cf",
+			StableRS:        "7fc44855c7",
+			StepIndex:       ptr.To[int32](2),
+			Images:          map[string]string{"guestbook": "argoproj/rollouts-demo:blue"},
+		},
+	}, event)
+
+	event, ok = NewRolloutEvent(newRollout(), "AnalysisRunFailed", "")
+	require.True(t, ok)
+	assert.Equal(t, "io.argoproj.rollouts.analysisrun.failed", event.Type)
+
+	_, ok = NewRolloutEvent(newRollout(), conditions.ScalingReplicaSetReason, "")
+	assert.False(t, ok)
+}
+
+func TestPublisher(t *testing.T) {
+	sink := &fakeSink{}
+	p := NewPublisher([]Sink{sink}, 2)
+	p.Publish(CloudEvent{ID: "1"})
+	p.Publish(CloudEvent{ID: "2"})
+	// the queue is full until the publisher runs
+	p.Publish(CloudEvent{ID: "3"})
+	go p.Run()
+	p.Close()
+	p.Publish(CloudEvent{ID: "4"})
+	p.Close()
+
+	assert.Equal(t, []CloudEvent{{ID: "1"}, {ID: "2"}}, sink.events)
+	assert.True(t, sink.closed)
+}
+
+func TestPublishRolloutEvent(t *testing.T) {
+	var received []CloudEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, CloudEventsContentType, r.Header.Get("Content-Type"))
+		var event CloudEvent
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		received = append(received, event)
+	}))
+	defer server.Close()
+
+	// events are not published before Init
+	PublishRolloutEvent(newRollout(), conditions.RolloutCompletedReason, "", nil)
+
+	shutdown, err := Init([]string{server.URL})
+	require.NoError(t, err)
+	PublishRolloutEvent(newRollout(), conditions.RolloutStepCompletedReason, "Rollout step %d/%d completed", []any{3, 5})
+	PublishRolloutEvent(newRollout(), conditions.ScalingReplicaSetReason, "", nil)
+	PublishRolloutEvent(&v1alpha1.AnalysisRun{}, "AnalysisRunFailed", "", nil)
+	shutdown()
+	PublishRolloutEvent(newRollout(), conditions.RolloutCompletedReason, "", nil)
+
+	require.Len(t, received, 1)
+	assert.Equal(t, "io.argoproj.rollouts.rollout.step.completed", received[0].Type)
+	assert.Equal(t, "Rollout step 3/5 completed", received[0].Data.Message)
+}
+
+func TestKafkaRESTSink(t *testing.T) {
+	var records kafkaRecords
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/topics/rollouts", r.URL.Path)
+		assert.Equal(t, KafkaRESTContentType, r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&records))
+	}))
+	defer server.Close()
+
+	sink, err := NewSink(strings.Replace(server.URL, "http://", "kafka+http://", 1) + "/rollouts")
+	require.NoError(t, err)
+	event, _ := NewRolloutEvent(newRollout(), conditions.RolloutCompletedReason, "")
+	require.NoError(t, sink.Publish(context.Background(), event))
+	require.Len(t, records.Records, 1)
+	assert.Equal(t, "default/guestbook", records.Records[0].Key)
+	assert.Equal(t, event.ID, records.Records[0].Value.ID)
+}
+
+func TestHTTPSinkError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sink, err := NewSink(server.URL)
+	require.NoError(t, err)
+	err = sink.Publish(context.Background(), CloudEvent{})
+	assert.ErrorContains(t, err, "status code 503")
+}
+
+// newFakeNATSServer accepts a NATS client and sends the payloads it publishes to the returned channel
+func newFakeNATSServer(t *testing.T) (string, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	published := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		fmt.Fprint(conn, "INFO {\"server_id\":\"fake\",\"max_payload\":1048576}\r\n")
+		reader := bufio.NewReader(conn)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			fields := strings.Fields(line)
+			switch {
+			case len(fields) == 0:
+			case fields[0] == "PING":
+				fmt.Fprint(conn, "PONG\r\n")
+			case fields[0] == "PUB":
+				size, _ := strconv.Atoi(fields[len(fields)-1])
+				payload := make([]byte, size+2)
+				if _, err := io.ReadFull(reader, payload); err != nil {
+					return
+				}
+				published <- fields[1] + " " + string(payload[:size])
+			}
+		}
+	}()
+	return listener.Addr().String(), published
+}
+
+func TestNATSSink(t *testing.T) {
+	addr, published := newFakeNATSServer(t)
+	sink, err := NewSink("nats://" + addr + "/rollouts.events")
+	require.NoError(t, err)
+	event, _ := NewRolloutEvent(newRollout(), conditions.RolloutCompletedReason, "")
+	require.NoError(t, sink.Publish(context.Background(), event))
+	require.NoError(t, sink.Close())
+
+	msg := <-published
+	subject, payload, _ := strings.Cut(msg, " ")
+	assert.Equal(t, "rollouts.events", subject)
+	var received CloudEvent
+	require.NoError(t, json.Unmarshal([]byte(payload), &received))
+	assert.Equal(t, event.ID, received.ID)
+}
+
+func TestNewSinkValidation(t *testing.T) {
+	_, err := NewSink("ftp://events")
+	assert.EqualError(t, err, "invalid event sink URL 'ftp://events': unsupported scheme 'ftp'")
+	_, err = NewSink("nats://nats:4222")
+	assert.EqualError(t, err, "invalid event sink URL 'nats://nats:4222': the subject is missing")
+	_, err = NewSink("kafka+http://proxy:8082/")
+	assert.EqualError(t, err, "invalid event sink URL 'kafka+http://proxy:8082/': the topic is missing")
+	_, err = Init([]string{"http://collector", "ftp://events"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,137 @@
+package eventbus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	// CloudEventsContentType is the content type of the events in the structured JSON format of CloudEvents
+	CloudEventsContentType = "application/cloudevents+json"
+	// KafkaRESTContentType is the content type of the records produced with the v2 API of a Kafka REST Proxy
+	KafkaRESTContentType = "application/vnd.kafka.json.v2+json"
+)
+
+// NewSink returns the sink of a URL:
+//   - http(s)://<host>/<path> posts the events to an HTTP endpoint
+//   - nats://<host>:<port>/<subject> publishes the events to a NATS subject
+//   - kafka+http(s)://<host>:<port>/<topic> produces the events to a Kafka topic via a Kafka REST Proxy
+func NewSink(sinkURL string) (Sink, error) {
+	u, err := url.Parse(sinkURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid event sink URL '%s': %w", sinkURL, err)
+	}
+	switch u.Scheme {
+	case "http", "https":
+		return &httpSink{url: sinkURL, client: &http.Client{}}, nil
+	case "nats":
+		subject := strings.Trim(u.Path, "/")
+		if subject == "" {
+			return nil, fmt.Errorf("invalid event sink URL '%s': the subject is missing", sinkURL)
+		}
+		server := *u
+		server.Path = ""
+		conn, err := nats.Connect(server.String(), nats.Name("argo-rollouts"), nats.MaxReconnects(-1), nats.RetryOnFailedConnect(true))
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to NATS server %s: %w", server.Host, err)
+		}
+		return &natsSink{conn: conn, subject: subject}, nil
+	case "kafka+http", "kafka+https":
+		topic := strings.Trim(u.Path, "/")
+		if topic == "" {
+			return nil, fmt.Errorf("invalid event sink URL '%s': the topic is missing", sinkURL)
+		}
+		proxy := *u
+		proxy.Scheme = strings.TrimPrefix(u.Scheme, "kafka+")
+		proxy.Path = "/topics/" + topic
+		return &kafkaRESTSink{url: proxy.String(), client: &http.Client{}}, nil
+	default:
+		return nil, fmt.Errorf("invalid event sink URL '%s': unsupported scheme '%s'", sinkURL, u.Scheme)
+	}
+}
+
+type httpSink struct {
+	url    string
+	client *http.Client
+}
+
+func (s *httpSink) Publish(ctx context.Context, event CloudEvent) error {
+	return post(ctx, s.client, s.url, CloudEventsContentType, event)
+}
+
+func (s *httpSink) Close() error {
+	return nil
+}
+
+type natsSink struct {
+	conn    *nats.Conn
+	subject string
+}
+
+func (s *natsSink) Publish(_ context.Context, event CloudEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return s.conn.Publish(s.subject, data)
+}
+
+func (s *natsSink) Close() error {
+	return s.conn.Drain()
+}
+
+type kafkaRESTSink struct {
+	url    string
+	client *http.Client
+}
+
+// kafkaRecords are the records produced to a topic with the v2 API of a Kafka REST Proxy
+type kafkaRecords struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+type kafkaRecord struct {
+	Key   string     `json:"key"`
+	Value CloudEvent `json:"value"`
+}
+
+// Publish produces the event keyed by its subject, i.e. the namespace and name of the rollout, so that the events of
+// a rollout are ordered
+func (s *kafkaRESTSink) Publish(ctx context.Context, event CloudEvent) error {
+	return post(ctx, s.client, s.url, KafkaRESTContentType, kafkaRecords{Records: []kafkaRecord{{Key: event.Subject, Value: event}}})
+}
+
+func (s *kafkaRESTSink) Close() error {
+	return nil
+}
+
+func post(ctx context.Context, client *http.Client, endpoint, contentType string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("request to %s has failed with status code %d: %s", endpoint, resp.StatusCode, string(body))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -53,12 +53,17 @@ func IsTerminating(experiment *v1alpha1.Experiment) bool {
 		}
 	}
 	for _, run := range experiment.Status.AnalysisRuns {
+		if IsWinnerCandidateAnalysis(experiment, run.Name) {
+			// a candidate whose analysis did not succeed loses the experiment instead of failing it
+			continue
+		}
 		switch run.Phase {
 		case v1alpha1.AnalysisPhaseFailed, v1alpha1.AnalysisPhaseError, v1alpha1.AnalysisPhaseInconclusive:
 			return true
 		}
 	}
-	return RequiredAnalysisRunsSuccessful(experiment, &experiment.Status)
+	// a paused experiment is held open after its required analysis runs complete
+	return !experiment.Spec.Paused && RequiredAnalysisRunsSuccessful(experiment, &experiment.Status)
 }
 
 func HasRequiredAnalysisRuns(ex *v1alpha1.Experiment) bool {
@@ -70,8 +75,21 @@ func HasRequiredAnalysisRuns(ex *v1alpha1.Experiment) bool {
 	return false
 }
 
+// IsWinnerCandidateAnalysis returns true if the analysis scores a candidate of the winner selection of the experiment
+func IsWinnerCandidateAnalysis(ex *v1alpha1.Experiment, analysisName string) bool {
+	if ex.Spec.WinnerSelection == nil {
+		return false
+	}
+	for _, candidate := range ex.Spec.WinnerSelection.Candidates {
+		if candidate.AnalysisName == analysisName {
+			return true
+		}
+	}
+	return false
+}
+
 // RequiredAnalysisRunsSuccessful has at least one required for completion analysis run
-// and it completed successfully
+// and it completed successfully. The analysis run of a winner candidate only needs to complete.
 func RequiredAnalysisRunsSuccessful(ex *v1alpha1.Experiment, exStatus *v1alpha1.ExperimentStatus) bool {
 	if exStatus == nil {
 		return false
@@ -82,7 +100,14 @@ func RequiredAnalysisRunsSuccessful(ex *v1alpha1.Experiment, exStatus *v1alpha1.
 		if analysis.RequiredForCompletion {
 			hasRequiredAnalysisRun = true
 			analysisStatus := GetAnalysisRunStatus(*exStatus, analysis.Name)
-			if analysisStatus == nil || analysisStatus.Phase != v1alpha1.AnalysisPhaseSuccessful {
+			switch {
+			case analysisStatus == nil:
+				completedAllRequiredRuns = false
+			case IsWinnerCandidateAnalysis(ex, analysis.Name):
+				if !analysisStatus.Phase.Completed() {
+					completedAllRequiredRuns = false
+				}
+			case analysisStatus.Phase != v1alpha1.AnalysisPhaseSuccessful:
 				completedAllRequiredRuns = false
 			}
 		}
@@ -90,23 +115,57 @@ func RequiredAnalysisRunsSuccessful(ex *v1alpha1.Experiment, exStatus *v1alpha1.
 	return hasRequiredAnalysisRun && completedAllRequiredRuns
 }
 
-// PassedDurations indicates if the experiment has run longer than the duration
+// PassedDurations indicates if the experiment has run longer than the duration, including its extension and the time
+// it was paused. A paused experiment never passes its duration.
 func PassedDurations(experiment *v1alpha1.Experiment) (bool, time.Duration) {
 	if experiment.Spec.Duration == "" {
 		return false, 0
 	}
-	if experiment.Status.AvailableAt == nil {
+	if experiment.Status.AvailableAt == nil || experiment.Spec.Paused {
 		return false, 0
 	}
 	now := timeutil.MetaNow()
-	dur, err := experiment.Spec.Duration.Duration()
+	dur, err := GetDuration(experiment)
 	if err != nil {
 		return false, 0
 	}
-	expiredTime := experiment.Status.AvailableAt.Add(dur)
+	expiredTime := experiment.Status.AvailableAt.Add(dur + GetPausedDuration(experiment.Status, now.Time))
 	return now.After(expiredTime), expiredTime.Sub(now.Time)
 }
 
+// GetDuration returns the duration of the experiment, extended by spec.extension
+func GetDuration(experiment *v1alpha1.Experiment) (time.Duration, error) {
+	dur, err := experiment.Spec.Duration.Duration()
+	if err != nil {
+		return 0, err
+	}
+	if experiment.Spec.Extension != "" {
+		extension, err := experiment.Spec.Extension.Duration()
+		if err != nil {
+			return 0, err
+		}
+		dur += extension
+	}
+	return dur, nil
+}
+
+// GetPausedDuration returns how long the experiment was paused after it became available, including the time since it
+// was paused if it is still paused
+func GetPausedDuration(status v1alpha1.ExperimentStatus, now time.Time) time.Duration {
+	paused := time.Duration(status.PausedSeconds) * time.Second
+	if status.PausedAt == nil || status.AvailableAt == nil {
+		return paused
+	}
+	pausedSince := status.PausedAt.Time
+	if status.AvailableAt.After(pausedSince) {
+		pausedSince = status.AvailableAt.Time
+	}
+	if now.After(pausedSince) {
+		paused += now.Sub(pausedSince)
+	}
+	return paused
+}
+
 func CalculateTemplateReplicasCount(experiment *v1alpha1.Experiment, template v1alpha1.TemplateSpec) int32 {
 	if HasFinished(experiment) || IsTerminating(experiment) {
 		return int32(0)
@@ -237,6 +296,10 @@ func IsSemanticallyEqual(left, right v1alpha1.ExperimentSpec) bool {
 	// we set these to both to false so that it does not factor into equality check
 	left.Terminate = false
 	right.Terminate = false
+	left.Paused = false
+	right.Paused = false
+	left.Extension = ""
+	right.Extension = ""
 	leftBytes, err := json.Marshal(left)
 	if err != nil {
 		panic(err)
@@ -128,6 +128,59 @@ func TestPassedDurations(t *testing.T) {
 	e.Status.AvailableAt = &metav1.Time{Time: now.Add(-2 * time.Second)}
 	passedDuration, _ = PassedDurations(e)
 	assert.True(t, passedDuration)
+
+	e.Spec.Paused = true
+	passedDuration, _ = PassedDurations(e)
+	assert.False(t, passedDuration)
+
+	e.Spec.Paused = false
+	e.Spec.Extension = "1m"
+	passedDuration, remaining := PassedDurations(e)
+	assert.False(t, passedDuration)
+	assert.Greater(t, remaining, 58*time.Second)
+
+	e.Spec.Extension = ""
+	e.Status.PausedSeconds = 60
+	passedDuration, remaining = PassedDurations(e)
+	assert.False(t, passedDuration)
+	assert.Greater(t, remaining, 58*time.Second)
+}
+
+func TestGetDuration(t *testing.T) {
+	e := &v1alpha1.Experiment{
+		Spec: v1alpha1.ExperimentSpec{
+			Duration: "5m",
+		},
+	}
+	duration, err := GetDuration(e)
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, duration)
+
+	e.Spec.Extension = "90s"
+	duration, err = GetDuration(e)
+	assert.NoError(t, err)
+	assert.Equal(t, 6*time.Minute+30*time.Second, duration)
+
+	e.Spec.Extension = "1m-typo"
+	_, err = GetDuration(e)
+	assert.Error(t, err)
+}
+
+func TestGetPausedDuration(t *testing.T) {
+	now := time.Now()
+	status := v1alpha1.ExperimentStatus{
+		PausedSeconds: 30,
+	}
+	assert.Equal(t, 30*time.Second, GetPausedDuration(status, now))
+
+	// the time paused before the experiment is available is not counted
+	status.PausedAt = &metav1.Time{Time: now.Add(-time.Minute)}
+	assert.Equal(t, 30*time.Second, GetPausedDuration(status, now))
+	status.AvailableAt = &metav1.Time{Time: now.Add(-10 * time.Second)}
+	assert.Equal(t, 40*time.Second, GetPausedDuration(status, now))
+
+	status.AvailableAt = &metav1.Time{Time: now.Add(-2 * time.Minute)}
+	assert.Equal(t, 90*time.Second, GetPausedDuration(status, now))
 }
 
 func TestGetTemplateStatusMapping(t *testing.T) {
@@ -272,6 +325,9 @@ func TestIsTerminating(t *testing.T) {
 			},
 		}
 		assert.True(t, IsTerminating(e))
+		// a paused experiment is held open after its required analysis runs complete
+		e.Spec.Paused = true
+		assert.False(t, IsTerminating(e))
 	}
 	{
 		e := &v1alpha1.Experiment{}
@@ -424,6 +480,8 @@ func TestIsSemanticallyEqual(t *testing.T) {
 	}
 	right := left.DeepCopy()
 	right.Terminate = true
+	right.Paused = true
+	right.Extension = "10m"
 	assert.True(t, IsSemanticallyEqual(*left, *right))
 	right.Templates[0].Replicas = ptr.To[int32](1)
 	assert.False(t, IsSemanticallyEqual(*left, *right))
@@ -451,6 +509,35 @@ func TestRequiredAnalysisRunsSuccessful(t *testing.T) {
 	assert.True(t, RequiredAnalysisRunsSuccessful(e, &e.Status))
 }
 
+func TestRequiredAnalysisRunsSuccessfulWithWinnerCandidate(t *testing.T) {
+	e := &v1alpha1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "foo",
+		},
+		Spec: v1alpha1.ExperimentSpec{
+			Analyses: []v1alpha1.ExperimentAnalysisTemplateRef{{
+				Name:                  "foo",
+				RequiredForCompletion: true,
+			}},
+			WinnerSelection: &v1alpha1.ExperimentWinnerSelection{
+				Candidates: []v1alpha1.ExperimentWinnerCandidate{{TemplateName: "bar", AnalysisName: "foo"}},
+			},
+		},
+		Status: v1alpha1.ExperimentStatus{
+			AnalysisRuns: []v1alpha1.ExperimentAnalysisRunStatus{{
+				Name:  "foo",
+				Phase: v1alpha1.AnalysisPhaseRunning,
+			}},
+		},
+	}
+	assert.True(t, IsWinnerCandidateAnalysis(e, "foo"))
+	assert.False(t, IsWinnerCandidateAnalysis(e, "bar"))
+	assert.False(t, RequiredAnalysisRunsSuccessful(e, &e.Status))
+	// the failed analysis of a candidate only disqualifies the candidate
+	e.Status.AnalysisRuns[0].Phase = v1alpha1.AnalysisPhaseFailed
+	assert.True(t, RequiredAnalysisRunsSuccessful(e, &e.Status))
+}
+
 func TestHasRequiredAnalysisRuns(t *testing.T) {
 	e := &v1alpha1.Experiment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,109 @@
+package experiment
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+// ParseWindowSchedule parses the cron schedule of the window in its time zone
+func ParseWindowSchedule(window v1alpha1.ExperimentWindow) (cron.Schedule, error) {
+	if window.TimeZone != "" {
+		if _, err := time.LoadLocation(window.TimeZone); err != nil {
+			return nil, fmt.Errorf("invalid time zone '%s': %w", window.TimeZone, err)
+		}
+		return cron.ParseStandard(fmt.Sprintf("CRON_TZ=%s %s", window.TimeZone, window.Schedule))
+	}
+	return cron.ParseStandard(window.Schedule)
+}
+
+// HasStarted returns whether the experiment started, i.e. it created the ReplicaSets of its templates
+func HasStarted(experiment *v1alpha1.Experiment) bool {
+	return len(experiment.Status.TemplateStatuses) > 0
+}
+
+// GetScheduledStartTime returns the time the experiment is scheduled to start by its startAt and window, or nil if it
+// can start at the given time. An experiment with a duration only starts in a window if it can run for its duration
+// before the window ends.
+func GetScheduledStartTime(experiment *v1alpha1.Experiment, now time.Time) (*time.Time, error) {
+	start := now
+	if experiment.Spec.StartAt != nil && experiment.Spec.StartAt.After(start) {
+		start = experiment.Spec.StartAt.Time
+	}
+	if window := experiment.Spec.Window; window != nil {
+		schedule, err := ParseWindowSchedule(*window)
+		if err != nil {
+			return nil, err
+		}
+		latestStart, err := window.Duration.Duration()
+		if err != nil {
+			return nil, err
+		}
+		if experiment.Spec.Duration != "" {
+			// the extension is not planned for, it only extends an experiment which already started
+			duration, err := experiment.Spec.Duration.Duration()
+			if err != nil {
+				return nil, err
+			}
+			latestStart -= duration
+		}
+		if latestStart <= 0 {
+			return nil, fmt.Errorf("the duration of the experiment is not shorter than its window")
+		}
+		// the start is within a window if the window started less than its latest start before
+		if windowStart := schedule.Next(start.Add(-latestStart)); windowStart.After(start) {
+			start = schedule.Next(start)
+		}
+	}
+	if !start.After(now) {
+		return nil, nil
+	}
+	return &start, nil
+}
+
+// GetTTLExpiry returns the time when the completed experiment expires according to its TTL strategy, or nil if it
+// does not expire
+func GetTTLExpiry(experiment *v1alpha1.Experiment) *time.Time {
+	ttlStrategy := experiment.Spec.TTLStrategy
+	if ttlStrategy == nil || !experiment.Status.Phase.Completed() {
+		return nil
+	}
+	completedAt := GetCompletedAt(experiment)
+	if completedAt == nil {
+		return nil
+	}
+	var ttlSeconds *int32
+	if experiment.Status.Phase == v1alpha1.AnalysisPhaseSuccessful && ttlStrategy.SecondsAfterSuccess != nil {
+		ttlSeconds = ttlStrategy.SecondsAfterSuccess
+	} else if experiment.Status.Phase == v1alpha1.AnalysisPhaseFailed && ttlStrategy.SecondsAfterFailure != nil {
+		ttlSeconds = ttlStrategy.SecondsAfterFailure
+	} else if ttlStrategy.SecondsAfterCompletion != nil {
+		ttlSeconds = ttlStrategy.SecondsAfterCompletion
+	}
+	if ttlSeconds == nil {
+		return nil
+	}
+	expiry := completedAt.Add(time.Duration(*ttlSeconds) * time.Second)
+	return &expiry
+}
+
+// GetCompletedAt returns the time the completed experiment completed. Experiments which completed before
+// status.completedAt was recorded fall back to the last transition of their conditions, which is the completion for
+// the Progressing condition of a successful experiment.
+func GetCompletedAt(experiment *v1alpha1.Experiment) *metav1.Time {
+	if experiment.Status.CompletedAt != nil {
+		return experiment.Status.CompletedAt
+	}
+	var completedAt *metav1.Time
+	for i := range experiment.Status.Conditions {
+		transition := experiment.Status.Conditions[i].LastTransitionTime
+		if completedAt == nil || transition.After(completedAt.Time) {
+			completedAt = &transition
+		}
+	}
+	return completedAt
+}
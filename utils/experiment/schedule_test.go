@@ -0,0 +1,149 @@
+package experiment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+func TestParseWindowSchedule(t *testing.T) {
+	_, err := ParseWindowSchedule(v1alpha1.ExperimentWindow{Schedule: "0 9 * * 1-5"})
+	assert.NoError(t, err)
+	_, err = ParseWindowSchedule(v1alpha1.ExperimentWindow{Schedule: "0 9 * * 1-5", TimeZone: "America/New_York"})
+	assert.NoError(t, err)
+	_, err = ParseWindowSchedule(v1alpha1.ExperimentWindow{Schedule: "not a schedule"})
+	assert.Error(t, err)
+	_, err = ParseWindowSchedule(v1alpha1.ExperimentWindow{Schedule: "0 9 * * *", TimeZone: "Mars/Olympus"})
+	assert.Error(t, err)
+}
+
+func TestGetScheduledStartTime(t *testing.T) {
+	// Wednesday
+	now := time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC)
+	window := &v1alpha1.ExperimentWindow{Schedule: "0 9 * * *", Duration: "4h"}
+
+	t.Run("no schedule", func(t *testing.T) {
+		start, err := GetScheduledStartTime(&v1alpha1.Experiment{}, now)
+		assert.NoError(t, err)
+		assert.Nil(t, start)
+	})
+
+	t.Run("startAt in the past", func(t *testing.T) {
+		ex := &v1alpha1.Experiment{Spec: v1alpha1.ExperimentSpec{StartAt: &metav1.Time{Time: now.Add(-time.Hour)}}}
+		start, err := GetScheduledStartTime(ex, now)
+		assert.NoError(t, err)
+		assert.Nil(t, start)
+	})
+
+	t.Run("startAt in the future", func(t *testing.T) {
+		ex := &v1alpha1.Experiment{Spec: v1alpha1.ExperimentSpec{StartAt: &metav1.Time{Time: now.Add(time.Hour)}}}
+		start, err := GetScheduledStartTime(ex, now)
+		assert.NoError(t, err)
+		assert.Equal(t, now.Add(time.Hour), *start)
+	})
+
+	t.Run("within the window", func(t *testing.T) {
+		ex := &v1alpha1.Experiment{Spec: v1alpha1.ExperimentSpec{Window: window}}
+		start, err := GetScheduledStartTime(ex, now)
+		assert.NoError(t, err)
+		assert.Nil(t, start)
+	})
+
+	t.Run("after the window", func(t *testing.T) {
+		ex := &v1alpha1.Experiment{Spec: v1alpha1.ExperimentSpec{Window: window}}
+		start, err := GetScheduledStartTime(ex, now.Add(2*time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 1, 4, 9, 0, 0, 0, time.UTC), *start)
+	})
+
+	t.Run("within the window, with time for the duration", func(t *testing.T) {
+		ex := &v1alpha1.Experiment{Spec: v1alpha1.ExperimentSpec{Window: window, Duration: "30m"}}
+		start, err := GetScheduledStartTime(ex, now)
+		assert.NoError(t, err)
+		assert.Nil(t, start)
+	})
+
+	t.Run("within the window, without time for the duration", func(t *testing.T) {
+		// the window ends at 13:00, before the experiment would complete
+		ex := &v1alpha1.Experiment{Spec: v1alpha1.ExperimentSpec{Window: window, Duration: "2h"}}
+		start, err := GetScheduledStartTime(ex, now)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 1, 4, 9, 0, 0, 0, time.UTC), *start)
+
+		// the experiment starts once its window starts
+		start, err = GetScheduledStartTime(ex, start.Add(time.Second))
+		assert.NoError(t, err)
+		assert.Nil(t, start)
+	})
+
+	t.Run("duration longer than the window", func(t *testing.T) {
+		ex := &v1alpha1.Experiment{Spec: v1alpha1.ExperimentSpec{Window: window, Duration: "4h"}}
+		_, err := GetScheduledStartTime(ex, now)
+		assert.EqualError(t, err, "the duration of the experiment is not shorter than its window")
+	})
+
+	t.Run("startAt within the window", func(t *testing.T) {
+		ex := &v1alpha1.Experiment{Spec: v1alpha1.ExperimentSpec{
+			StartAt: &metav1.Time{Time: now.Add(24 * time.Hour)},
+			Window:  window,
+		}}
+		start, err := GetScheduledStartTime(ex, now)
+		assert.NoError(t, err)
+		assert.Equal(t, now.Add(24*time.Hour), *start)
+	})
+
+	t.Run("window in time zone", func(t *testing.T) {
+		ex := &v1alpha1.Experiment{Spec: v1alpha1.ExperimentSpec{Window: &v1alpha1.ExperimentWindow{
+			Schedule: "0 9 * * *",
+			Duration: "1h",
+			TimeZone: "America/New_York",
+		}}}
+		start, err := GetScheduledStartTime(ex, now)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 1, 3, 14, 0, 0, 0, time.UTC), start.UTC())
+	})
+
+	t.Run("invalid window", func(t *testing.T) {
+		ex := &v1alpha1.Experiment{Spec: v1alpha1.ExperimentSpec{Window: &v1alpha1.ExperimentWindow{Schedule: "bad", Duration: "1h"}}}
+		_, err := GetScheduledStartTime(ex, now)
+		assert.Error(t, err)
+	})
+}
+
+func TestGetTTLExpiry(t *testing.T) {
+	completedAt := metav1.NewTime(time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC))
+	newExperiment := func(phase v1alpha1.AnalysisPhase, ttlStrategy *v1alpha1.TTLStrategy) *v1alpha1.Experiment {
+		return &v1alpha1.Experiment{
+			Spec:   v1alpha1.ExperimentSpec{TTLStrategy: ttlStrategy},
+			Status: v1alpha1.ExperimentStatus{Phase: phase, CompletedAt: &completedAt},
+		}
+	}
+	ttlStrategy := &v1alpha1.TTLStrategy{
+		SecondsAfterCompletion: ptr.To[int32](60),
+		SecondsAfterFailure:    ptr.To[int32](120),
+		SecondsAfterSuccess:    ptr.To[int32](180),
+	}
+
+	assert.Nil(t, GetTTLExpiry(newExperiment(v1alpha1.AnalysisPhaseSuccessful, nil)))
+	assert.Nil(t, GetTTLExpiry(newExperiment(v1alpha1.AnalysisPhaseRunning, ttlStrategy)))
+	assert.Equal(t, completedAt.Add(180*time.Second), *GetTTLExpiry(newExperiment(v1alpha1.AnalysisPhaseSuccessful, ttlStrategy)))
+	assert.Equal(t, completedAt.Add(120*time.Second), *GetTTLExpiry(newExperiment(v1alpha1.AnalysisPhaseFailed, ttlStrategy)))
+	assert.Equal(t, completedAt.Add(60*time.Second), *GetTTLExpiry(newExperiment(v1alpha1.AnalysisPhaseError, ttlStrategy)))
+	assert.Nil(t, GetTTLExpiry(newExperiment(v1alpha1.AnalysisPhaseError, &v1alpha1.TTLStrategy{SecondsAfterSuccess: ptr.To[int32](1)})))
+
+	ex := newExperiment(v1alpha1.AnalysisPhaseSuccessful, ttlStrategy)
+	ex.Status.CompletedAt = nil
+	assert.Nil(t, GetTTLExpiry(ex))
+
+	// an experiment which completed before status.completedAt was recorded expires after its last condition transition
+	ex.Status.Conditions = []v1alpha1.ExperimentCondition{
+		{Type: v1alpha1.ExperimentProgressing, LastTransitionTime: completedAt},
+		{Type: v1alpha1.InvalidExperimentSpec, LastTransitionTime: metav1.NewTime(completedAt.Add(-time.Hour))},
+	}
+	assert.Equal(t, completedAt.Add(180*time.Second), *GetTTLExpiry(ex))
+}
@@ -0,0 +1,91 @@
+package featureflag
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
+	rolloututil "github.com/argoproj/argo-rollouts/utils/rollout"
+	"github.com/argoproj/argo-rollouts/utils/weightutil"
+)
+
+// Flags returns the setFeatureFlag steps of the rollout by the key of their flag. The last step of a flag defines
+// the provider the flag is set with when the update is aborted or promoted.
+func Flags(ro *v1alpha1.Rollout) map[string]*v1alpha1.SetFeatureFlag {
+	if ro.Spec.Strategy.Canary == nil {
+		return nil
+	}
+	flags := map[string]*v1alpha1.SetFeatureFlag{}
+	for _, step := range ro.Spec.Strategy.Canary.Steps {
+		if step.SetFeatureFlag != nil {
+			flags[step.SetFeatureFlag.Flag] = step.SetFeatureFlag
+		}
+	}
+	return flags
+}
+
+// Percentage returns the percentage of the users the flag is enabled for, and whether the rollout has a
+// setFeatureFlag step of the flag. The flag is enabled for all users once the update is fully promoted, and has the
+// percentage of its last setFeatureFlag step up to the current step otherwise. Before its first setFeatureFlag step
+// of the update and when the update is aborted, the flag has its stable percentage (see StablePercentage).
+func Percentage(ro *v1alpha1.Rollout, flag string) (int32, bool) {
+	if _, ok := Flags(ro)[flag]; !ok {
+		return 0, false
+	}
+	if ro.Status.Abort {
+		return StablePercentage(ro, flag), true
+	}
+	currentStep, currentStepIndex := replicasetutil.GetCurrentCanaryStep(ro)
+	if currentStep == nil || rolloututil.IsFullyPromoted(ro) {
+		return 100, true
+	}
+	steps := ro.Spec.Strategy.Canary.Steps
+	for i := *currentStepIndex; i >= 0; i-- {
+		step := steps[i].SetFeatureFlag
+		if step == nil || step.Flag != flag {
+			continue
+		}
+		if step.Percentage != nil {
+			return *step.Percentage, true
+		}
+		return stepWeightPercentage(ro, i), true
+	}
+	return StablePercentage(ro, flag), true
+}
+
+// StablePercentage returns the percentage of the users the flag is enabled for by the stable ReplicaSet, i.e. 100 if
+// the stable ReplicaSet was promoted with a setFeatureFlag step of the flag, and 0 for a flag which is new in the
+// update
+func StablePercentage(ro *v1alpha1.Rollout, flag string) int32 {
+	if slices.Contains(ro.Status.Canary.StableFeatureFlags, flag) {
+		return 100
+	}
+	return 0
+}
+
+// Keys returns the sorted keys of the flags of the setFeatureFlag steps of the rollout
+func Keys(ro *v1alpha1.Rollout) []string {
+	flags := Flags(ro)
+	if len(flags) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(flags))
+	for key := range flags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// stepWeightPercentage returns the canary weight of the step in percent, i.e. the weight of the last setWeight step up
+// to the step
+func stepWeightPercentage(ro *v1alpha1.Rollout, index int32) int32 {
+	steps := ro.Spec.Strategy.Canary.Steps
+	for i := index; i >= 0; i-- {
+		if steps[i].SetWeight != nil {
+			return *steps[i].SetWeight * 100 / weightutil.MaxTrafficWeight(ro)
+		}
+	}
+	return 0
+}
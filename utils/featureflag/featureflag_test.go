@@ -0,0 +1,81 @@
+package featureflag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+func newRollout(currentStepIndex int32, steps ...v1alpha1.CanaryStep) *v1alpha1.Rollout {
+	ro := &v1alpha1.Rollout{}
+	ro.Name = "guestbook"
+	ro.Namespace = "default"
+	ro.Spec.Strategy.Canary = &v1alpha1.CanaryStrategy{Steps: steps}
+	ro.Status.CurrentStepIndex = ptr.To(currentStepIndex)
+	ro.Status.StableRS = "stable"
+	ro.Status.CurrentPodHash = "canary"
+	return ro
+}
+
+func setFeatureFlagStep(flag string, percentage *int32) v1alpha1.CanaryStep {
+	return v1alpha1.CanaryStep{SetFeatureFlag: &v1alpha1.SetFeatureFlag{
+		Flag:        flag,
+		Percentage:  percentage,
+		OpenFeature: &v1alpha1.OpenFeatureFeatureFlag{},
+	}}
+}
+
+func setStableFlag(ro *v1alpha1.Rollout) {
+	ro.Status.Canary.StableFeatureFlags = []string{"new-checkout"}
+}
+
+func TestPercentage(t *testing.T) {
+	steps := []v1alpha1.CanaryStep{
+		{SetWeight: ptr.To[int32](20)},
+		setFeatureFlagStep("new-checkout", nil),
+		{Pause: &v1alpha1.RolloutPause{}},
+		setFeatureFlagStep("new-checkout", ptr.To[int32](50)),
+		{Pause: &v1alpha1.RolloutPause{}},
+	}
+	for _, test := range []struct {
+		name     string
+		index    int32
+		mutate   func(ro *v1alpha1.Rollout)
+		expected int32
+	}{
+		{name: "before the first step", index: 0, expected: 0},
+		{name: "weight of the step", index: 2, expected: 20},
+		{name: "percentage of the step", index: 4, expected: 50},
+		{name: "completed steps", index: 5, expected: 100},
+		{name: "fully promoted", index: 2, mutate: func(ro *v1alpha1.Rollout) { ro.Status.StableRS = "canary" }, expected: 100},
+		{name: "aborted", index: 4, mutate: func(ro *v1alpha1.Rollout) { ro.Status.Abort = true }, expected: 0},
+		// a flag the stable ReplicaSet was promoted with stays enabled until the first step, and when aborted
+		{name: "stable flag before the first step", index: 0, mutate: setStableFlag, expected: 100},
+		{name: "stable flag at the step", index: 2, mutate: setStableFlag, expected: 20},
+		{name: "stable flag aborted", index: 4, mutate: func(ro *v1alpha1.Rollout) { setStableFlag(ro); ro.Status.Abort = true }, expected: 100},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ro := newRollout(test.index, steps...)
+			if test.mutate != nil {
+				test.mutate(ro)
+			}
+			percentage, ok := Percentage(ro, "new-checkout")
+			assert.True(t, ok)
+			assert.Equal(t, test.expected, percentage)
+		})
+	}
+
+	_, ok := Percentage(newRollout(2, steps...), "new-search")
+	assert.False(t, ok)
+}
+
+func TestPercentageMaxTrafficWeight(t *testing.T) {
+	ro := newRollout(2, v1alpha1.CanaryStep{SetWeight: ptr.To[int32](250)}, setFeatureFlagStep("new-checkout", nil))
+	ro.Spec.Strategy.Canary.TrafficRouting = &v1alpha1.RolloutTrafficRouting{MaxTrafficWeight: ptr.To[int32](1000)}
+	ro.Status.CurrentStepIndex = ptr.To[int32](1)
+	percentage, _ := Percentage(ro, "new-checkout")
+	assert.Equal(t, int32(25), percentage)
+}
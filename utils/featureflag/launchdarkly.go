@@ -0,0 +1,114 @@
+package featureflag
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// DefaultLaunchDarklyAPIURL is the URL of the LaunchDarkly API
+	DefaultLaunchDarklyAPIURL = "https://app.launchdarkly.com"
+	// semanticPatchContentType is the content type of the semantic patches of the LaunchDarkly API
+	semanticPatchContentType = "application/json; domain-model=launchdarkly.semanticpatch"
+)
+
+// LaunchDarklyClient sets the percentage rollouts of boolean flags with the LaunchDarkly API
+type LaunchDarklyClient struct {
+	// APIURL is the URL of the LaunchDarkly API. Defaults to DefaultLaunchDarklyAPIURL
+	APIURL string
+	// APIToken is an API access token which is allowed to update the flags
+	APIToken   string
+	HTTPClient *http.Client
+}
+
+type launchDarklyFlag struct {
+	Variations []struct {
+		ID    string `json:"_id"`
+		Value any    `json:"value"`
+	} `json:"variations"`
+}
+
+// SetPercentage sets the fallthrough rule of the boolean flag in the environment to a percentage rollout, which serves
+// the true variation to the percentage of the users and the false variation to the others. The targeting of the flag
+// must be on, so that the fallthrough rule is served.
+func (c *LaunchDarklyClient) SetPercentage(ctx context.Context, projectKey, environmentKey, flag string, percentage int32) error {
+	path := fmt.Sprintf("/api/v2/flags/%s/%s", url.PathEscape(projectKey), url.PathEscape(flag))
+	var ldFlag launchDarklyFlag
+	if err := c.do(ctx, http.MethodGet, path, "", nil, &ldFlag); err != nil {
+		return err
+	}
+	weights := map[string]int32{}
+	for _, variation := range ldFlag.Variations {
+		enabled, ok := variation.Value.(bool)
+		if !ok {
+			return fmt.Errorf("flag %s is not a boolean flag", flag)
+		}
+		// the weights of a percentage rollout are in thousandths of a percent
+		if enabled {
+			weights[variation.ID] = percentage * 1000
+		} else {
+			weights[variation.ID] = (100 - percentage) * 1000
+		}
+	}
+	if len(weights) != 2 {
+		return fmt.Errorf("flag %s is not a boolean flag", flag)
+	}
+	patch := map[string]any{
+		"environmentKey": environmentKey,
+		"comment":        fmt.Sprintf("Argo Rollouts set the rollout to %d%%", percentage),
+		"instructions": []map[string]any{{
+			"kind":           "updateFallthroughVariationOrRollout",
+			"rolloutWeights": weights,
+		}},
+	}
+	return c.do(ctx, http.MethodPatch, path, semanticPatchContentType, patch, nil)
+}
+
+func (c *LaunchDarklyClient) do(ctx context.Context, method, path, contentType string, body, result any) error {
+	apiURL := c.APIURL
+	if apiURL == "" {
+		apiURL = DefaultLaunchDarklyAPIURL
+	}
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(apiURL, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", c.APIToken)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("LaunchDarkly API returned %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(data, result)
+}
@@ -0,0 +1,56 @@
+package featureflag
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLaunchDarklySetPercentage(t *testing.T) {
+	var patch map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "api-token", r.Header.Get("Authorization"))
+		assert.Equal(t, "/api/v2/flags/default/new-checkout", r.URL.Path)
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`{"variations": [{"_id": "on-id", "value": true}, {"_id": "off-id", "value": false}]}`))
+		case http.MethodPatch:
+			assert.Equal(t, semanticPatchContentType, r.Header.Get("Content-Type"))
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&patch))
+		}
+	}))
+	defer server.Close()
+
+	client := LaunchDarklyClient{APIURL: server.URL, APIToken: "api-token"}
+	require.NoError(t, client.SetPercentage(context.Background(), "default", "production", "new-checkout", 20))
+	assert.Equal(t, "production", patch["environmentKey"])
+	assert.Equal(t, []any{map[string]any{
+		"kind":           "updateFallthroughVariationOrRollout",
+		"rolloutWeights": map[string]any{"on-id": float64(20000), "off-id": float64(80000)},
+	}}, patch["instructions"])
+}
+
+func TestLaunchDarklySetPercentageErrors(t *testing.T) {
+	variations := `{"variations": [{"_id": "a", "value": "blue"}, {"_id": "b", "value": "green"}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			http.Error(w, `{"message": "forbidden"}`, http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(variations))
+	}))
+	defer server.Close()
+
+	client := LaunchDarklyClient{APIURL: server.URL}
+	err := client.SetPercentage(context.Background(), "default", "production", "new-checkout", 20)
+	assert.EqualError(t, err, "flag new-checkout is not a boolean flag")
+
+	variations = `{"variations": [{"_id": "on-id", "value": true}, {"_id": "off-id", "value": false}]}`
+	err = client.SetPercentage(context.Background(), "default", "production", "new-checkout", 20)
+	assert.EqualError(t, err, `LaunchDarkly API returned 403 Forbidden: {"message": "forbidden"}`)
+}
@@ -0,0 +1,201 @@
+package featureflag
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	listers "github.com/argoproj/argo-rollouts/pkg/client/listers/rollouts/v1alpha1"
+)
+
+// OFREP reasons and error codes of the OpenFeature Remote Evaluation Protocol
+const (
+	ReasonStatic              = "STATIC"
+	ReasonSplit               = "SPLIT"
+	ErrorFlagNotFound         = "FLAG_NOT_FOUND"
+	ErrorParse                = "PARSE_ERROR"
+	ErrorTargetingKeyMissing  = "TARGETING_KEY_MISSING"
+	ErrorGeneral              = "GENERAL"
+	variantOn                 = "on"
+	variantOff                = "off"
+	targetingKeyAttribute     = "targetingKey"
+	maxEvaluationRequestBytes = 1 << 20
+)
+
+// evaluationRequest is the body of an OFREP evaluation request
+type evaluationRequest struct {
+	Context map[string]any `json:"context"`
+}
+
+// Evaluation is the result of the evaluation of a flag, or the error evaluating it
+type Evaluation struct {
+	Key          string         `json:"key"`
+	Reason       string         `json:"reason,omitempty"`
+	Variant      string         `json:"variant,omitempty"`
+	Value        any            `json:"value,omitempty"`
+	Metadata     map[string]any `json:"metadata,omitempty"`
+	ErrorCode    string         `json:"errorCode,omitempty"`
+	ErrorDetails string         `json:"errorDetails,omitempty"`
+}
+
+// NewOFREPHandler returns a handler which serves the OpenFeature flags of the setFeatureFlag steps of the rollouts
+// with the OpenFeature Remote Evaluation Protocol. The flags of a namespace are evaluated at
+// /namespaces/<namespace>/ofrep/v1/evaluate/flags, i.e. the base URL of the OFREP provider is
+// http://<controller>/namespaces/<namespace>. The requests must carry a Kubernetes bearer token whose user is allowed
+// to get the rollouts of the namespace.
+func NewOFREPHandler(rolloutLister listers.RolloutLister, kubeClient kubernetes.Interface) http.Handler {
+	h := &ofrepHandler{rolloutLister: rolloutLister, authorizer: newOFREPAuthorizer(kubeClient)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /namespaces/{namespace}/ofrep/v1/evaluate/flags/{key}", h.authorized(h.evaluateFlag))
+	mux.HandleFunc("POST /namespaces/{namespace}/ofrep/v1/evaluate/flags", h.authorized(h.evaluateFlags))
+	return mux
+}
+
+type ofrepHandler struct {
+	rolloutLister listers.RolloutLister
+	authorizer    *ofrepAuthorizer
+}
+
+// authorized rejects the requests which are not allowed to evaluate the flags of the namespace
+func (h *ofrepHandler) authorized(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if status, reason := h.authorizer.authorize(r, r.PathValue("namespace")); status != http.StatusOK {
+			writeJSON(w, status, map[string]string{"errorCode": ErrorGeneral, "errorDetails": reason})
+			return
+		}
+		handler(w, r)
+	}
+}
+
+func (h *ofrepHandler) evaluateFlag(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	evalCtx, err := readContext(w, r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, Evaluation{Key: key, ErrorCode: ErrorParse, ErrorDetails: err.Error()})
+		return
+	}
+	flags, err := h.namespaceFlags(r.PathValue("namespace"))
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, Evaluation{Key: key, ErrorCode: ErrorGeneral, ErrorDetails: err.Error()})
+		return
+	}
+	ro, ok := flags[key]
+	if !ok {
+		writeJSON(w, http.StatusNotFound, Evaluation{Key: key, ErrorCode: ErrorFlagNotFound, ErrorDetails: fmt.Sprintf("flag %s not found", key)})
+		return
+	}
+	evaluation := evaluate(ro, key, evalCtx)
+	status := http.StatusOK
+	if evaluation.ErrorCode != "" {
+		status = http.StatusBadRequest
+	}
+	writeJSON(w, status, evaluation)
+}
+
+func (h *ofrepHandler) evaluateFlags(w http.ResponseWriter, r *http.Request) {
+	evalCtx, err := readContext(w, r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"errorCode": ErrorParse, "errorDetails": err.Error()})
+		return
+	}
+	flags, err := h.namespaceFlags(r.PathValue("namespace"))
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"errorCode": ErrorGeneral, "errorDetails": err.Error()})
+		return
+	}
+	keys := make([]string, 0, len(flags))
+	for key := range flags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	evaluations := make([]Evaluation, 0, len(keys))
+	for _, key := range keys {
+		evaluations = append(evaluations, evaluate(flags[key], key, evalCtx))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"flags": evaluations})
+}
+
+// namespaceFlags returns the rollouts of the OpenFeature flags of the namespace by the key of the flag. A flag of
+// several rollouts is served from the first rollout by name.
+func (h *ofrepHandler) namespaceFlags(namespace string) (map[string]*v1alpha1.Rollout, error) {
+	rollouts, err := h.rolloutLister.Rollouts(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(rollouts, func(i, j int) bool { return rollouts[i].Name < rollouts[j].Name })
+	flags := map[string]*v1alpha1.Rollout{}
+	for _, ro := range rollouts {
+		for key, step := range Flags(ro) {
+			if _, ok := flags[key]; !ok && step.OpenFeature != nil {
+				flags[key] = ro
+			}
+		}
+	}
+	return flags, nil
+}
+
+// evaluate evaluates the flag of the rollout for the evaluation context. The flag is enabled for a targeting key when
+// the bucket of the key is below the percentage of the flag, so that a targeting key stays enabled as the percentage
+// increases.
+func evaluate(ro *v1alpha1.Rollout, key string, evalCtx map[string]any) Evaluation {
+	percentage, _ := Percentage(ro, key)
+	variants := Flags(ro)[key].OpenFeature.Variants
+	evaluation := Evaluation{
+		Key:      key,
+		Reason:   ReasonStatic,
+		Metadata: map[string]any{"rollout": ro.Name, "percentage": percentage},
+	}
+	enabled := percentage >= 100
+	if percentage > 0 && percentage < 100 {
+		targetingKey, _ := evalCtx[targetingKeyAttribute].(string)
+		if targetingKey == "" {
+			return Evaluation{Key: key, ErrorCode: ErrorTargetingKeyMissing, ErrorDetails: "flag is enabled for a percentage of the targeting keys, but no targeting key was given"}
+		}
+		evaluation.Reason = ReasonSplit
+		enabled = bucket(key, targetingKey) < percentage
+	}
+	evaluation.Variant, evaluation.Value = variantOff, false
+	if enabled {
+		evaluation.Variant, evaluation.Value = variantOn, true
+	}
+	if variants != nil {
+		evaluation.Value = variants.Off
+		if enabled {
+			evaluation.Value = variants.On
+		}
+	}
+	return evaluation
+}
+
+// bucket assigns the targeting key to one of 100 buckets, which is stable for the flag
+func bucket(key, targetingKey string) int32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key + "/" + targetingKey))
+	return int32(h.Sum32() % 100)
+}
+
+func readContext(w http.ResponseWriter, r *http.Request) (map[string]any, error) {
+	var req evaluationRequest
+	if r.ContentLength == 0 {
+		return map[string]any{}, nil
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxEvaluationRequestBytes)).Decode(&req); err != nil {
+		return nil, err
+	}
+	return req.Context, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Warnf("Failed to write OFREP response: %v", err)
+	}
+}
@@ -0,0 +1,176 @@
+package featureflag
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	listers "github.com/argoproj/argo-rollouts/pkg/client/listers/rollouts/v1alpha1"
+)
+
+// newKubeClient returns a client whose API server authenticates the token "valid" as the service account of an
+// application, which may get the rollouts of every namespace but "restricted"
+func newKubeClient() *k8sfake.Clientset {
+	client := k8sfake.NewSimpleClientset()
+	client.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		if review.Spec.Token == "valid" {
+			review.Status.Authenticated = true
+			review.Status.User.Username = "system:serviceaccount:default:app"
+		}
+		return true, review, nil
+	})
+	client.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		review.Status.Allowed = review.Spec.User == "system:serviceaccount:default:app" &&
+			review.Spec.ResourceAttributes.Namespace != "restricted" &&
+			review.Spec.ResourceAttributes.Resource == "rollouts" && review.Spec.ResourceAttributes.Verb == "get"
+		return true, review, nil
+	})
+	return client
+}
+
+func newOFREPHandler(t *testing.T, rollouts ...*v1alpha1.Rollout) http.Handler {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, ro := range rollouts {
+		require.NoError(t, indexer.Add(ro))
+	}
+	return NewOFREPHandler(listers.NewRolloutLister(indexer), newKubeClient())
+}
+
+func postEvaluation(handler http.Handler, path, body string) (int, map[string]any) {
+	return postEvaluationWithToken(handler, path, body, "valid")
+}
+
+func postEvaluationWithToken(handler http.Handler, path, body, token string) (int, map[string]any) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	handler.ServeHTTP(rec, req)
+	var result map[string]any
+	_ = json.Unmarshal(rec.Body.Bytes(), &result)
+	return rec.Code, result
+}
+
+func TestOFREPEvaluateFlag(t *testing.T) {
+	ro := newRollout(1, setFeatureFlagStep("new-checkout", ptr.To[int32](30)), v1alpha1.CanaryStep{Pause: &v1alpha1.RolloutPause{}})
+	handler := newOFREPHandler(t, ro)
+	path := "/namespaces/default/ofrep/v1/evaluate/flags/new-checkout"
+
+	enabled := 0
+	for i := range 1000 {
+		code, result := postEvaluation(handler, path, fmt.Sprintf(`{"context": {"targetingKey": "user-%d"}}`, i))
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, ReasonSplit, result["reason"])
+		assert.Equal(t, map[string]any{"rollout": "guestbook", "percentage": float64(30)}, result["metadata"])
+		if result["value"] == true {
+			assert.Equal(t, variantOn, result["variant"])
+			enabled++
+		}
+	}
+	assert.InDelta(t, 300, enabled, 60)
+
+	// a targeting key keeps its variant
+	_, first := postEvaluation(handler, path, `{"context": {"targetingKey": "user-1"}}`)
+	_, second := postEvaluation(handler, path, `{"context": {"targetingKey": "user-1"}}`)
+	assert.Equal(t, first["value"], second["value"])
+
+	code, result := postEvaluation(handler, path, `{"context": {}}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, ErrorTargetingKeyMissing, result["errorCode"])
+
+	code, result = postEvaluation(handler, path, `{`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, ErrorParse, result["errorCode"])
+
+	code, result = postEvaluation(handler, "/namespaces/other/ofrep/v1/evaluate/flags/new-checkout", `{}`)
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, ErrorFlagNotFound, result["errorCode"])
+}
+
+func TestOFREPEvaluateStaticFlag(t *testing.T) {
+	step := setFeatureFlagStep("theme", nil)
+	step.SetFeatureFlag.OpenFeature.Variants = &v1alpha1.FeatureFlagVariants{On: "dark", Off: "light"}
+	ro := newRollout(1, step)
+	handler := newOFREPHandler(t, ro)
+
+	code, result := postEvaluation(handler, "/namespaces/default/ofrep/v1/evaluate/flags/theme", "")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, ReasonStatic, result["reason"])
+	assert.Equal(t, variantOn, result["variant"])
+	assert.Equal(t, "dark", result["value"])
+
+	ro.Status.Abort = true
+	_, result = postEvaluation(handler, "/namespaces/default/ofrep/v1/evaluate/flags/theme", "")
+	assert.Equal(t, variantOff, result["variant"])
+	assert.Equal(t, "light", result["value"])
+}
+
+func TestOFREPEvaluateFlags(t *testing.T) {
+	launchDarkly := v1alpha1.CanaryStep{SetFeatureFlag: &v1alpha1.SetFeatureFlag{
+		Flag:         "launchdarkly-flag",
+		LaunchDarkly: &v1alpha1.LaunchDarklyFeatureFlag{},
+	}}
+	ro := newRollout(2, setFeatureFlagStep("new-checkout", ptr.To[int32](100)), setFeatureFlagStep("new-search", ptr.To[int32](0)), launchDarkly)
+	other := newRollout(2, setFeatureFlagStep("new-checkout", ptr.To[int32](0)))
+	other.Name = "zz-other"
+	handler := newOFREPHandler(t, ro, other)
+
+	code, result := postEvaluation(handler, "/namespaces/default/ofrep/v1/evaluate/flags", `{"context": {"targetingKey": "user-1"}}`)
+	require.Equal(t, http.StatusOK, code)
+	flags := result["flags"].([]any)
+	require.Len(t, flags, 2)
+	assert.Equal(t, "new-checkout", flags[0].(map[string]any)["key"])
+	assert.Equal(t, true, flags[0].(map[string]any)["value"])
+	assert.Equal(t, "guestbook", flags[0].(map[string]any)["metadata"].(map[string]any)["rollout"])
+	assert.Equal(t, "new-search", flags[1].(map[string]any)["key"])
+	assert.Equal(t, false, flags[1].(map[string]any)["value"])
+}
+
+func TestOFREPAuthorization(t *testing.T) {
+	ro := newRollout(1, setFeatureFlagStep("new-checkout", ptr.To[int32](100)))
+	handler := newOFREPHandler(t, ro)
+	path := "/namespaces/default/ofrep/v1/evaluate/flags/new-checkout"
+
+	code, _ := postEvaluationWithToken(handler, path, "", "")
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, _ = postEvaluationWithToken(handler, path, "", "invalid")
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, result := postEvaluationWithToken(handler, "/namespaces/restricted/ofrep/v1/evaluate/flags", "", "valid")
+	assert.Equal(t, http.StatusForbidden, code)
+	assert.Equal(t, "user 'system:serviceaccount:default:app' is not allowed to get the rollouts of namespace 'restricted'", result["errorDetails"])
+	code, _ = postEvaluationWithToken(handler, path, "", "valid")
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestOFREPAuthorizationCache(t *testing.T) {
+	client := newKubeClient()
+	authorizer := newOFREPAuthorizer(client)
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Authorization", "Bearer valid")
+
+	for range 3 {
+		status, _ := authorizer.authorize(req, "default")
+		assert.Equal(t, http.StatusOK, status)
+	}
+	// the token is reviewed once for each namespace
+	status, _ := authorizer.authorize(req, "restricted")
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Len(t, client.Actions(), 4)
+}
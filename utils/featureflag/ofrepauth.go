@@ -0,0 +1,116 @@
+package featureflag
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilcache "k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+const (
+	// ofrepAuthCacheTTL is the time the decisions of the authorizer are cached, so that the evaluations of an
+	// application do not review its token on every request
+	ofrepAuthCacheTTL = time.Minute
+	// ofrepAuthCacheSize is the maximum number of cached decisions
+	ofrepAuthCacheSize = 4096
+	// ofrepAuthTimeout limits the time the review of a token takes
+	ofrepAuthTimeout = 10 * time.Second
+)
+
+// ofrepAuthorizer authorizes the OFREP requests of a namespace. A request must carry a Kubernetes token, e.g. the
+// token of the service account of the application, as a bearer token, and its user must be allowed to get the rollouts
+// of the namespace.
+type ofrepAuthorizer struct {
+	kubeClient kubernetes.Interface
+	decisions  *utilcache.LRUExpireCache
+}
+
+// ofrepDecision is the cached decision of the authorizer for a token and a namespace
+type ofrepDecision struct {
+	status int
+	reason string
+}
+
+func newOFREPAuthorizer(kubeClient kubernetes.Interface) *ofrepAuthorizer {
+	return &ofrepAuthorizer{
+		kubeClient: kubeClient,
+		decisions:  utilcache.NewLRUExpireCache(ofrepAuthCacheSize),
+	}
+}
+
+// authorize returns http.StatusOK if the request is allowed to evaluate the flags of the namespace, and the status
+// and reason of the rejection otherwise
+func (a *ofrepAuthorizer) authorize(r *http.Request, namespace string) (int, string) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return http.StatusUnauthorized, "a bearer token is required"
+	}
+	sum := sha256.Sum256([]byte(token))
+	key := hex.EncodeToString(sum[:]) + "/" + namespace
+	if decision, ok := a.decisions.Get(key); ok {
+		d := decision.(ofrepDecision)
+		return d.status, d.reason
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), ofrepAuthTimeout)
+	defer cancel()
+	status, reason, err := a.review(ctx, token, namespace)
+	if err != nil {
+		// failed reviews are not cached, so that they are retried with the next request
+		log.Warnf("Failed to review the token of an OFREP request: %v", err)
+		return http.StatusInternalServerError, "failed to review the token"
+	}
+	a.decisions.Add(key, ofrepDecision{status: status, reason: reason}, ofrepAuthCacheTTL)
+	return status, reason
+}
+
+// review authenticates the token with a TokenReview, and checks that its user may get the rollouts of the namespace
+// with a SubjectAccessReview
+func (a *ofrepAuthorizer) review(ctx context.Context, token, namespace string) (int, string, error) {
+	tokenReview, err := a.kubeClient.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return 0, "", err
+	}
+	if !tokenReview.Status.Authenticated {
+		return http.StatusUnauthorized, "invalid token", nil
+	}
+	user := tokenReview.Status.User
+	extra := map[string]authorizationv1.ExtraValue{}
+	for key, values := range user.Extra {
+		extra[key] = authorizationv1.ExtraValue(values)
+	}
+	review, err := a.kubeClient.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user.Username,
+			UID:    user.UID,
+			Groups: user.Groups,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "get",
+				Group:     v1alpha1.SchemeGroupVersion.Group,
+				Resource:  v1alpha1.RolloutGVR.Resource,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return 0, "", err
+	}
+	if !review.Status.Allowed {
+		return http.StatusForbidden, fmt.Sprintf("user '%s' is not allowed to get the rollouts of namespace '%s'", user.Username, namespace), nil
+	}
+	return http.StatusOK, "", nil
+}
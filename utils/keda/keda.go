@@ -0,0 +1,63 @@
+package keda
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamiclister"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+const (
+	// KEDACRDGroup is the API group of the KEDA resources
+	KEDACRDGroup = "keda.sh"
+	// PausedReplicasAnnotation pauses the autoscaling of a ScaledObject, and scales its target to the given replicas
+	PausedReplicasAnnotation = "autoscaling.keda.sh/paused-replicas"
+	// PausedByAnnotation is the name of the rollout which paused a ScaledObject, so that the controller only resumes
+	// the ScaledObjects it paused
+	PausedByAnnotation = "rollout.argoproj.io/keda-paused-by"
+)
+
+// DoesKEDAExist returns whether the ScaledObjects of the namespace can be listed, i.e. whether KEDA is installed on
+// the cluster
+func DoesKEDAExist(dynamicClient dynamic.Interface, namespace string) bool {
+	_, err := dynamicClient.Resource(GetScaledObjectGVR()).Namespace(namespace).List(context.TODO(), metav1.ListOptions{Limit: 1})
+	return err == nil
+}
+
+func GetScaledObjectGVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    KEDACRDGroup,
+		Version:  "v1alpha1",
+		Resource: "scaledobjects",
+	}
+}
+
+// GetScaledObjectsForRollout returns the ScaledObjects of the namespace of the rollout which scale the rollout. The
+// returned objects come from the informer cache and must not be modified.
+func GetScaledObjectsForRollout(lister dynamiclister.Lister, ro *v1alpha1.Rollout) ([]*unstructured.Unstructured, error) {
+	list, err := lister.Namespace(ro.Namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var scaledObjects []*unstructured.Unstructured
+	for _, so := range list {
+		targetRef, _, _ := unstructured.NestedStringMap(so.Object, "spec", "scaleTargetRef")
+		if targetRef["kind"] != "Rollout" || targetRef["name"] != ro.Name {
+			continue
+		}
+		if apiVersion, ok := targetRef["apiVersion"]; ok {
+			gv, err := schema.ParseGroupVersion(apiVersion)
+			if err != nil || gv.Group != v1alpha1.SchemeGroupVersion.Group {
+				continue
+			}
+		}
+		scaledObjects = append(scaledObjects, so)
+	}
+	return scaledObjects, nil
+}
@@ -0,0 +1,108 @@
+package log
+
+import (
+	"io"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// LogLevelAnnotation overrides the log level of the controller for a single object, e.g. to debug one rollout
+	LogLevelAnnotation = "rollout.argoproj.io/log-level"
+	// LogLevelConfigMapKey is the key of the controller ConfigMap which overrides the log level of the controller
+	LogLevelConfigMapKey = "logLevel"
+)
+
+// levelLoggers holds one logger per overridden log level, which write to the output of the standard logger
+var levelLoggers = struct {
+	sync.Mutex
+	out     *lockedWriter
+	loggers map[log.Level]*log.Logger
+}{}
+
+// withObjectLevel returns a logging context honoring the log level annotation of an object. Messages logged for an
+// object with a log level override are never sampled.
+func withObjectLevel(annotations map[string]string) *log.Entry {
+	value, ok := annotations[LogLevelAnnotation]
+	if !ok {
+		return log.NewEntry(log.StandardLogger())
+	}
+	level, err := log.ParseLevel(value)
+	if err != nil {
+		return log.NewEntry(log.StandardLogger())
+	}
+	return log.NewEntry(levelLogger(level))
+}
+
+// levelLogger returns the logger of a log level override. The output of the standard logger is wrapped in a
+// lockedWriter, which the loggers share with the standard logger, so that their messages are not interleaved. The
+// loggers are created again when the output of the standard logger was replaced.
+func levelLogger(level log.Level) *log.Logger {
+	levelLoggers.Lock()
+	defer levelLoggers.Unlock()
+	std := log.StandardLogger()
+	if out, ok := std.Out.(*lockedWriter); !ok || out != levelLoggers.out {
+		levelLoggers.out = &lockedWriter{out: std.Out}
+		levelLoggers.loggers = map[log.Level]*log.Logger{}
+		std.SetOutput(levelLoggers.out)
+	}
+	if logger, ok := levelLoggers.loggers[level]; ok {
+		return logger
+	}
+	logger := &log.Logger{
+		Out:          levelLoggers.out,
+		Hooks:        std.Hooks,
+		Formatter:    unsampledFormatter{},
+		ReportCaller: std.ReportCaller,
+		Level:        level,
+		ExitFunc:     std.ExitFunc,
+		BufferPool:   std.BufferPool,
+	}
+	levelLoggers.loggers[level] = logger
+	return logger
+}
+
+// lockedWriter serializes the writes of the loggers sharing an output
+type lockedWriter struct {
+	lock sync.Mutex
+	out  io.Writer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.out.Write(p)
+}
+
+// unsampledFormatter formats messages with the formatter of the standard logger, bypassing the sampling
+type unsampledFormatter struct{}
+
+func (unsampledFormatter) Format(entry *log.Entry) ([]byte, error) {
+	formatter := log.StandardLogger().Formatter
+	if sampling, ok := formatter.(*SamplingFormatter); ok {
+		formatter = sampling.Formatter
+	}
+	return formatter.Format(entry)
+}
+
+// SetLevelFromConfigMap sets the level of the standard logger to the level of the controller ConfigMap, or to the
+// default level when the ConfigMap does not override it
+func SetLevelFromConfigMap(cm *corev1.ConfigMap, defaultLevel log.Level) {
+	level := defaultLevel
+	if cm != nil {
+		if value, ok := cm.Data[LogLevelConfigMapKey]; ok {
+			parsed, err := log.ParseLevel(value)
+			if err != nil {
+				log.Warnf("Invalid log level %q in ConfigMap %s/%s: %v", value, cm.Namespace, cm.Name, err)
+				return
+			}
+			level = parsed
+		}
+	}
+	if log.GetLevel() != level {
+		log.Infof("Setting log level to %s", level)
+		log.SetLevel(level)
+	}
+}
@@ -0,0 +1,94 @@
+package log
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+func TestWithRolloutLogLevelAnnotation(t *testing.T) {
+	buf := bytes.NewBufferString("")
+	std := log.StandardLogger()
+	prevOut, prevFormatter := std.Out, std.Formatter
+	defer func() {
+		std.SetOutput(prevOut)
+		std.SetFormatter(prevFormatter)
+	}()
+	std.SetOutput(buf)
+	std.SetFormatter(NewSamplingFormatter(&log.TextFormatter{}, 1, 0, time.Hour))
+
+	ro := &v1alpha1.Rollout{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"}}
+	WithRollout(ro).Debug("hidden")
+	assert.Empty(t, buf.String())
+
+	ro.Annotations = map[string]string{LogLevelAnnotation: "debug"}
+	WithRollout(ro).Debug("shown")
+	WithRollout(ro).Debug("shown")
+	assert.Equal(t, 2, bytes.Count(buf.Bytes(), []byte("msg=shown")), "messages with a log level override are not sampled")
+	assert.Equal(t, log.InfoLevel, log.GetLevel())
+
+	buf.Reset()
+	ro.Annotations = map[string]string{LogLevelAnnotation: "verbose"}
+	WithRollout(ro).Debug("invalid")
+	assert.Empty(t, buf.String())
+}
+
+func TestLevelLoggerIsShared(t *testing.T) {
+	buf := bytes.NewBufferString("")
+	std := log.StandardLogger()
+	prevOut := std.Out
+	defer std.SetOutput(prevOut)
+	std.SetOutput(buf)
+
+	logger := levelLogger(log.DebugLevel)
+	assert.Same(t, logger, levelLogger(log.DebugLevel))
+	assert.NotSame(t, logger, levelLogger(log.TraceLevel))
+	assert.Same(t, std.Out, logger.Out, "the loggers share the synchronized output of the standard logger")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			logger.Debug("override")
+		}()
+		go func() {
+			defer wg.Done()
+			std.Info("standard")
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 10, bytes.Count(buf.Bytes(), []byte("msg=override")))
+	assert.Equal(t, 10, bytes.Count(buf.Bytes(), []byte("msg=standard")))
+
+	// the loggers follow a replaced output of the standard logger
+	std.SetOutput(bytes.NewBufferString(""))
+	assert.NotSame(t, logger, levelLogger(log.DebugLevel))
+}
+
+func TestSetLevelFromConfigMap(t *testing.T) {
+	defer log.SetLevel(log.GetLevel())
+
+	cm := &corev1.ConfigMap{Data: map[string]string{LogLevelConfigMapKey: "debug"}}
+	SetLevelFromConfigMap(cm, log.InfoLevel)
+	assert.Equal(t, log.DebugLevel, log.GetLevel())
+
+	cm.Data[LogLevelConfigMapKey] = "verbose"
+	SetLevelFromConfigMap(cm, log.InfoLevel)
+	assert.Equal(t, log.DebugLevel, log.GetLevel())
+
+	SetLevelFromConfigMap(&corev1.ConfigMap{}, log.InfoLevel)
+	assert.Equal(t, log.InfoLevel, log.GetLevel())
+
+	SetLevelFromConfigMap(cm, log.InfoLevel)
+	SetLevelFromConfigMap(nil, log.WarnLevel)
+	assert.Equal(t, log.WarnLevel, log.GetLevel())
+}
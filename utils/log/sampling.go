@@ -0,0 +1,62 @@
+package log
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SamplingFormatter drops repeated log messages, so that the logs of a busy controller remain readable and cheap to
+// ingest. Within each tick, the first Initial messages with the same level and message are written, and every
+// Thereafter-th message after that. Warnings and errors are never dropped.
+type SamplingFormatter struct {
+	// Formatter formats the messages which are written
+	Formatter  log.Formatter
+	Initial    int
+	Thereafter int
+	Tick       time.Duration
+
+	lock sync.Mutex
+	// counts holds the number of messages seen in the current tick, keyed by level and message
+	counts    map[string]int
+	tickStart time.Time
+}
+
+// NewSamplingFormatter returns a formatter sampling the messages formatted by the given formatter
+func NewSamplingFormatter(formatter log.Formatter, initial, thereafter int, tick time.Duration) *SamplingFormatter {
+	return &SamplingFormatter{
+		Formatter:  formatter,
+		Initial:    initial,
+		Thereafter: thereafter,
+		Tick:       tick,
+		counts:     map[string]int{},
+	}
+}
+
+// Format formats the entry, or returns no data if the entry is dropped
+func (f *SamplingFormatter) Format(e *log.Entry) ([]byte, error) {
+	if !f.sample(e) {
+		return nil, nil
+	}
+	return f.Formatter.Format(e)
+}
+
+func (f *SamplingFormatter) sample(e *log.Entry) bool {
+	if e.Level <= log.WarnLevel {
+		return true
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if e.Time.Sub(f.tickStart) >= f.Tick || e.Time.Before(f.tickStart) {
+		clear(f.counts)
+		f.tickStart = e.Time
+	}
+	key := e.Level.String() + "/" + e.Message
+	f.counts[key]++
+	count := f.counts[key]
+	if count <= f.Initial {
+		return true
+	}
+	return f.Thereafter > 0 && (count-f.Initial)%f.Thereafter == 0
+}
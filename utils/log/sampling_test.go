@@ -0,0 +1,34 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSamplingFormatter(t *testing.T) {
+	buf := bytes.NewBufferString("")
+	logger := log.New()
+	logger.SetOutput(buf)
+	formatter := NewSamplingFormatter(&log.TextFormatter{DisableTimestamp: true}, 2, 3, time.Second)
+	logger.SetFormatter(formatter)
+
+	now := time.Now()
+	for i := 0; i < 10; i++ {
+		logger.WithTime(now).Info("repeated")
+		logger.WithTime(now).Warn("warning")
+	}
+	logger.WithTime(now).Info("other")
+	// the first 2 messages, then every 3rd of the remaining 8
+	assert.Equal(t, 4, bytes.Count(buf.Bytes(), []byte("msg=repeated")))
+	assert.Equal(t, 10, bytes.Count(buf.Bytes(), []byte("msg=warning")))
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("msg=other")))
+
+	// the counts are reset in the next tick
+	buf.Reset()
+	logger.WithTime(now.Add(time.Second)).Info("repeated")
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("msg=repeated")))
+}
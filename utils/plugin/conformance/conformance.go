@@ -0,0 +1,302 @@
+// Package conformance exercises a plugin against the RPC contract of its plugin type with golden scenarios, so that
+// plugin authors can validate the compatibility of their plugin with the controller before deploying it.
+//
+// A plugin executable is validated with RunExecutable, which is what `kubectl argo rollouts plugin conformance` does.
+// The implementation of a plugin is validated with Serve, which serves the implementation over RPC like its executable
+// would. The conformancetest package reports the scenarios of Serve as the subtests of the Go tests of a plugin.
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"text/tabwriter"
+	"time"
+
+	goPlugin "github.com/hashicorp/go-plugin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	metricRpc "github.com/argoproj/argo-rollouts/metricproviders/plugin/rpc"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	stepRpc "github.com/argoproj/argo-rollouts/rollout/steps/plugin/rpc"
+	trafficRouterRpc "github.com/argoproj/argo-rollouts/rollout/trafficrouting/plugin/rpc"
+	"github.com/argoproj/argo-rollouts/utils/plugin/types"
+)
+
+const (
+	// DefaultTimeout is the default time given to the operations which complete asynchronously
+	DefaultTimeout = 30 * time.Second
+
+	reportHeaderFmtString = "SCENARIO\tRESULT\tMESSAGE\n"
+	reportColumnFmtString = "%s\t%s\t%s\n"
+)
+
+var (
+	// pollInterval is the interval between the calls of an operation which has not completed yet, when the plugin
+	// does not tell when to call it again
+	pollInterval = time.Second
+	// startTimeout is the time given to a plugin served in-process to start
+	startTimeout = 5 * time.Second
+)
+
+// handshakes are the handshake configs of the plugin types, as expected by the controller
+var handshakes = map[types.PluginType]goPlugin.HandshakeConfig{
+	types.PluginTypeTrafficRouter:  {ProtocolVersion: 1, MagicCookieKey: "ARGO_ROLLOUTS_RPC_PLUGIN", MagicCookieValue: "trafficrouter"},
+	types.PluginTypeMetricProvider: {ProtocolVersion: 1, MagicCookieKey: "ARGO_ROLLOUTS_RPC_PLUGIN", MagicCookieValue: "metricprovider"},
+	types.PluginTypeStep:           {ProtocolVersion: 1, MagicCookieKey: "ARGO_ROLLOUTS_RPC_PLUGIN", MagicCookieValue: "step"},
+}
+
+// pluginKeys are the names under which the controller dispenses the plugins of each type
+var pluginKeys = map[types.PluginType]string{
+	types.PluginTypeTrafficRouter:  "RpcTrafficRouterPlugin",
+	types.PluginTypeMetricProvider: "RpcMetricProviderPlugin",
+	types.PluginTypeStep:           "RpcStepPlugin",
+}
+
+// Options configures the scenarios run against a plugin
+type Options struct {
+	// PluginName is the name of the plugin, e.g. argoproj-labs/sample-nginx, under which its config is set in the
+	// rollout or the metric
+	PluginName string
+	// Config is the config of the plugin in the rollout, the metric or the step
+	Config json.RawMessage
+	// Rollout is the rollout the traffic router and step scenarios are run with. By default, a canary rollout named
+	// conformance, with the stable and canary services conformance-stable and conformance-canary, is used
+	Rollout *v1alpha1.Rollout
+	// Timeout is the time given to the operations which complete asynchronously, DefaultTimeout if unset
+	Timeout time.Duration
+}
+
+func (o Options) timeout() time.Duration {
+	if o.Timeout <= 0 {
+		return DefaultTimeout
+	}
+	return o.Timeout
+}
+
+// newRollout returns the rollout of the traffic router and step scenarios
+func newRollout(opts Options) *v1alpha1.Rollout {
+	if opts.Rollout != nil {
+		return opts.Rollout.DeepCopy()
+	}
+	return &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "conformance",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: v1alpha1.RolloutSpec{
+			Strategy: v1alpha1.RolloutStrategy{
+				Canary: &v1alpha1.CanaryStrategy{
+					CanaryService: "conformance-canary",
+					StableService: "conformance-stable",
+					TrafficRouting: &v1alpha1.RolloutTrafficRouting{
+						Plugins: map[string]json.RawMessage{opts.PluginName: opts.Config},
+						ManagedRoutes: []v1alpha1.MangedRoutes{
+							{Name: headerRouteName},
+							{Name: mirrorRouteName},
+						},
+					},
+					Steps: []v1alpha1.CanaryStep{
+						{Plugin: &v1alpha1.PluginStep{Name: opts.PluginName, Config: opts.Config}},
+					},
+				},
+			},
+		},
+		Status: v1alpha1.RolloutStatus{
+			CurrentPodHash: canaryHash,
+			StableRS:       stableHash,
+		},
+	}
+}
+
+// Result is the result of a scenario
+type Result struct {
+	// Scenario is the name of the scenario
+	Scenario string
+	// Skipped indicates that the scenario does not apply to the plugin, e.g. because it does not implement an
+	// optional operation
+	Skipped bool
+	// Message explains why the scenario failed or was skipped
+	Message string
+	// Err is the reason the scenario failed, nil if it passed or was skipped
+	Err error
+}
+
+// Report is the results of the scenarios run against a plugin
+type Report []Result
+
+// Failed returns the number of failed scenarios
+func (r Report) Failed() int {
+	failed := 0
+	for _, result := range r {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Print prints the results of the scenarios as a table
+func (r Report) Print(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprint(tw, reportHeaderFmtString)
+	for _, result := range r {
+		status := "PASS"
+		switch {
+		case result.Err != nil:
+			status = "FAIL"
+		case result.Skipped:
+			status = "SKIP"
+		}
+		fmt.Fprintf(tw, reportColumnFmtString, result.Scenario, status, result.Message)
+	}
+	_ = tw.Flush()
+}
+
+// skipError skips a scenario which does not apply to the plugin
+type skipError struct {
+	reason string
+}
+
+func (e skipError) Error() string {
+	return e.reason
+}
+
+func skip(format string, args ...any) error {
+	return skipError{reason: fmt.Sprintf(format, args...)}
+}
+
+// scenario is a golden scenario of the contract of a plugin type
+type scenario struct {
+	name string
+	run  func() error
+}
+
+// runScenarios runs the scenarios in order. The scenarios share the state of the plugin, so a scenario runs even if
+// the previous ones failed, in order to report all the violations of the contract at once.
+func runScenarios(scenarios []scenario) Report {
+	report := make(Report, 0, len(scenarios))
+	for _, s := range scenarios {
+		result := Result{Scenario: s.name}
+		var skipped skipError
+		if err := s.run(); errors.As(err, &skipped) {
+			result.Skipped = true
+			result.Message = skipped.reason
+		} else if err != nil {
+			result.Err = err
+			result.Message = err.Error()
+		}
+		report = append(report, result)
+	}
+	return report
+}
+
+// rpcError converts the error of an operation to an error, nil if the operation succeeded
+func rpcError(operation string, err types.RpcError) error {
+	if !err.HasError() {
+		return nil
+	}
+	return fmt.Errorf("%s returned an error: %w", operation, err)
+}
+
+// RunExecutable starts the plugin executable of the type with the args, the way the controller does, and runs the
+// scenarios of the plugin type against it
+func RunExecutable(pluginType types.PluginType, pluginPath string, args []string, opts Options) (Report, error) {
+	handshake, ok := handshakes[pluginType]
+	if !ok {
+		return nil, fmt.Errorf("unknown plugin type %s", pluginType)
+	}
+	client := goPlugin.NewClient(&goPlugin.ClientConfig{
+		HandshakeConfig: handshake,
+		Plugins:         newPluginMap(pluginType, nil),
+		Cmd:             exec.Command(pluginPath, args...),
+		Managed:         true,
+	})
+	defer client.Kill()
+	return dispenseAndRun(client, pluginType, opts)
+}
+
+// Serve serves the implementation of a plugin in-process over RPC, and runs the scenarios of the plugin type against
+// it
+func Serve(pluginType types.PluginType, impl any, opts Options) (Report, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	reattachCh := make(chan *goPlugin.ReattachConfig, 1)
+	closeCh := make(chan struct{})
+	defer func() {
+		cancel()
+		<-closeCh
+	}()
+	go goPlugin.Serve(&goPlugin.ServeConfig{
+		HandshakeConfig: handshakes[pluginType],
+		Plugins:         newPluginMap(pluginType, impl),
+		Test: &goPlugin.ServeTestConfig{
+			Context:          ctx,
+			ReattachConfigCh: reattachCh,
+			CloseCh:          closeCh,
+		},
+	})
+
+	var reattach *goPlugin.ReattachConfig
+	select {
+	case reattach = <-reattachCh:
+	case <-time.After(startTimeout):
+		return nil, fmt.Errorf("plugin did not start within %s", startTimeout)
+	}
+	client := goPlugin.NewClient(&goPlugin.ClientConfig{
+		HandshakeConfig: handshakes[pluginType],
+		Plugins:         newPluginMap(pluginType, nil),
+		Reattach:        reattach,
+	})
+	defer client.Kill()
+	return dispenseAndRun(client, pluginType, opts)
+}
+
+// newPluginMap returns the plugin map of the plugin type, which serves the implementation if any
+func newPluginMap(pluginType types.PluginType, impl any) map[string]goPlugin.Plugin {
+	var p goPlugin.Plugin
+	switch pluginType {
+	case types.PluginTypeTrafficRouter:
+		rpcPlugin := &trafficRouterRpc.RpcTrafficRouterPlugin{}
+		if impl != nil {
+			rpcPlugin.Impl = impl.(trafficRouterRpc.TrafficRouterPlugin)
+		}
+		p = rpcPlugin
+	case types.PluginTypeMetricProvider:
+		rpcPlugin := &metricRpc.RpcMetricProviderPlugin{}
+		if impl != nil {
+			rpcPlugin.Impl = impl.(metricRpc.MetricProviderPlugin)
+		}
+		p = rpcPlugin
+	case types.PluginTypeStep:
+		rpcPlugin := &stepRpc.RpcStepPlugin{}
+		if impl != nil {
+			rpcPlugin.Impl = impl.(stepRpc.StepPlugin)
+		}
+		p = rpcPlugin
+	}
+	return map[string]goPlugin.Plugin{pluginKeys[pluginType]: p}
+}
+
+// dispenseAndRun dispenses the plugin of the client and runs the scenarios of the plugin type against it
+func dispenseAndRun(client *goPlugin.Client, pluginType types.PluginType, opts Options) (Report, error) {
+	rpcClient, err := client.Client()
+	if err != nil {
+		return nil, fmt.Errorf("unable to start plugin: %w", err)
+	}
+	raw, err := rpcClient.Dispense(pluginKeys[pluginType])
+	if err != nil {
+		return nil, fmt.Errorf("unable to dispense plugin: %w", err)
+	}
+	switch p := raw.(type) {
+	case trafficRouterRpc.TrafficRouterPlugin:
+		return RunTrafficRouterPlugin(p, opts), nil
+	case metricRpc.MetricProviderPlugin:
+		return RunMetricProviderPlugin(p, opts), nil
+	case stepRpc.StepPluginClient:
+		return RunStepPlugin(p, opts), nil
+	}
+	return nil, fmt.Errorf("unexpected type from plugin")
+}
@@ -0,0 +1,267 @@
+package conformance
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/tj/assert"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/plugin/types"
+)
+
+type trafficRouterPlugin struct {
+	weight        int32
+	ignoreWeights bool
+	unhealthy     bool
+}
+
+func (p *trafficRouterPlugin) InitPlugin() types.RpcError {
+	return types.RpcError{}
+}
+
+func (p *trafficRouterPlugin) UpdateHash(_ *v1alpha1.Rollout, _, _ string, _ []v1alpha1.WeightDestination) types.RpcError {
+	return types.RpcError{}
+}
+
+func (p *trafficRouterPlugin) SetWeight(_ *v1alpha1.Rollout, desiredWeight int32, _ []v1alpha1.WeightDestination) types.RpcError {
+	if !p.ignoreWeights {
+		p.weight = desiredWeight
+	}
+	return types.RpcError{}
+}
+
+func (p *trafficRouterPlugin) SetHeaderRoute(_ *v1alpha1.Rollout, headerRoute *v1alpha1.SetHeaderRoute) types.RpcError {
+	if headerRoute.Name != headerRouteName {
+		return types.RpcError{ErrorString: "unknown route"}
+	}
+	return types.RpcError{}
+}
+
+func (p *trafficRouterPlugin) SetMirrorRoute(_ *v1alpha1.Rollout, mirrorRoute *v1alpha1.SetMirrorRoute) types.RpcError {
+	if mirrorRoute.Name != mirrorRouteName {
+		return types.RpcError{ErrorString: "unknown route"}
+	}
+	return types.RpcError{}
+}
+
+func (p *trafficRouterPlugin) VerifyWeight(_ *v1alpha1.Rollout, desiredWeight int32, _ []v1alpha1.WeightDestination) (types.RpcVerified, types.RpcError) {
+	if p.weight != desiredWeight {
+		return types.NotVerified, types.RpcError{}
+	}
+	return types.Verified, types.RpcError{}
+}
+
+func (p *trafficRouterPlugin) RemoveManagedRoutes(_ *v1alpha1.Rollout) types.RpcError {
+	return types.RpcError{}
+}
+
+func (p *trafficRouterPlugin) Type() string {
+	return "Conformance"
+}
+
+func (p *trafficRouterPlugin) Healthz(_ *v1alpha1.Rollout) types.RpcError {
+	if p.unhealthy {
+		return types.RpcError{ErrorString: "route not found"}
+	}
+	return types.RpcError{}
+}
+
+type metricProviderPlugin struct {
+	async   bool
+	invalid bool
+}
+
+func (p *metricProviderPlugin) InitPlugin() types.RpcError {
+	return types.RpcError{}
+}
+
+func (p *metricProviderPlugin) Run(_ *v1alpha1.AnalysisRun, _ v1alpha1.Metric) v1alpha1.Measurement {
+	if p.invalid {
+		return v1alpha1.Measurement{}
+	}
+	if p.async {
+		return v1alpha1.Measurement{Phase: v1alpha1.AnalysisPhaseRunning}
+	}
+	return v1alpha1.Measurement{Phase: v1alpha1.AnalysisPhaseSuccessful, Value: "1"}
+}
+
+func (p *metricProviderPlugin) Resume(_ *v1alpha1.AnalysisRun, _ v1alpha1.Metric, measurement v1alpha1.Measurement) v1alpha1.Measurement {
+	measurement.Phase = v1alpha1.AnalysisPhaseSuccessful
+	return measurement
+}
+
+func (p *metricProviderPlugin) Terminate(_ *v1alpha1.AnalysisRun, _ v1alpha1.Metric, measurement v1alpha1.Measurement) v1alpha1.Measurement {
+	measurement.Phase = v1alpha1.AnalysisPhaseSuccessful
+	return measurement
+}
+
+func (p *metricProviderPlugin) GarbageCollect(_ *v1alpha1.AnalysisRun, _ v1alpha1.Metric, _ int) types.RpcError {
+	return types.RpcError{}
+}
+
+func (p *metricProviderPlugin) Type() string {
+	return "Conformance"
+}
+
+func (p *metricProviderPlugin) GetMetadata(_ v1alpha1.Metric) map[string]string {
+	return nil
+}
+
+type stepState struct {
+	Executions int
+}
+
+type stepPlugin struct {
+	// asyncTerminate runs Terminate asynchronously, which breaks the contract
+	asyncTerminate bool
+}
+
+func (p *stepPlugin) InitPlugin() types.RpcError {
+	return types.RpcError{}
+}
+
+func (p *stepPlugin) Run(_ *v1alpha1.Rollout, stepContext *types.RpcStepContext) (types.RpcStepResult, types.RpcError) {
+	var state stepState
+	if stepContext.Status != nil {
+		if err := json.Unmarshal(stepContext.Status, &state); err != nil {
+			return types.RpcStepResult{}, types.RpcError{ErrorString: err.Error()}
+		}
+	}
+	state.Executions++
+	status, _ := json.Marshal(state)
+	phase := types.PhaseRunning
+	if state.Executions > 2 {
+		phase = types.PhaseSuccessful
+	}
+	return types.RpcStepResult{Phase: phase, Status: status, RequeueAfter: time.Millisecond}, types.RpcError{}
+}
+
+func (p *stepPlugin) Terminate(_ *v1alpha1.Rollout, _ *types.RpcStepContext) (types.RpcStepResult, types.RpcError) {
+	if p.asyncTerminate {
+		return types.RpcStepResult{Phase: types.PhaseRunning}, types.RpcError{}
+	}
+	return types.RpcStepResult{Phase: types.PhaseSuccessful}, types.RpcError{}
+}
+
+func (p *stepPlugin) Abort(_ *v1alpha1.Rollout, _ *types.RpcStepContext) (types.RpcStepResult, types.RpcError) {
+	return types.RpcStepResult{Phase: types.PhaseSuccessful}, types.RpcError{}
+}
+
+func (p *stepPlugin) Type() string {
+	return "Conformance"
+}
+
+func (p *stepPlugin) Info() (types.RpcStepPluginInfo, types.RpcError) {
+	return types.RpcStepPluginInfo{
+		APIVersion:   types.StepPluginAPIVersion,
+		ConfigSchema: []byte(`{"type":"object","required":["value"]}`),
+		StateVersion: 1,
+	}, types.RpcError{}
+}
+
+func results(report Report) map[string]string {
+	results := map[string]string{}
+	for _, result := range report {
+		switch {
+		case result.Err != nil:
+			results[result.Scenario] = "FAIL: " + result.Message
+		case result.Skipped:
+			results[result.Scenario] = "SKIP"
+		default:
+			results[result.Scenario] = "PASS"
+		}
+	}
+	return results
+}
+
+func TestTrafficRouterPlugin(t *testing.T) {
+	pollInterval = time.Millisecond
+	defer func() { pollInterval = time.Second }()
+
+	report, err := Serve(types.PluginTypeTrafficRouter, &trafficRouterPlugin{}, Options{PluginName: "argoproj-labs/conformance"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, report.Failed())
+
+	report, err = Serve(types.PluginTypeTrafficRouter, &trafficRouterPlugin{ignoreWeights: true, unhealthy: true}, Options{
+		PluginName: "argoproj-labs/conformance",
+		Timeout:    10 * time.Millisecond,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"InitPlugin":                             "PASS",
+		"Type":                                   "PASS",
+		"UpdateHash":                             "PASS",
+		"SetWeight":                              "FAIL: weight 25: weight 25 was not verified within 10ms",
+		"SetWeight with additional destinations": "FAIL: weight 20 was not verified within 10ms",
+		"SetHeaderRoute":                         "PASS",
+		"SetMirrorRoute":                         "PASS",
+		"RemoveManagedRoutes":                    "PASS",
+		"Healthz":                                "FAIL: Healthz returned an error: route not found",
+	}, results(report))
+	assert.Equal(t, 3, report.Failed())
+}
+
+func TestMetricProviderPlugin(t *testing.T) {
+	pollInterval = time.Millisecond
+	defer func() { pollInterval = time.Second }()
+
+	report, err := Serve(types.PluginTypeMetricProvider, &metricProviderPlugin{async: true}, Options{PluginName: "argoproj-labs/conformance"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, report.Failed())
+
+	report, err = Serve(types.PluginTypeMetricProvider, &metricProviderPlugin{}, Options{PluginName: "argoproj-labs/conformance"})
+	assert.NoError(t, err)
+	assert.Equal(t, "SKIP", results(report)["Terminate"])
+	assert.Equal(t, 0, report.Failed())
+
+	report, err = Serve(types.PluginTypeMetricProvider, &metricProviderPlugin{invalid: true}, Options{PluginName: "argoproj-labs/conformance"})
+	assert.NoError(t, err)
+	assert.Equal(t, "FAIL: Run returned a measurement with the invalid phase ''", results(report)["Run"])
+	assert.Equal(t, 2, report.Failed())
+}
+
+func TestStepPlugin(t *testing.T) {
+	report, err := Serve(types.PluginTypeStep, &stepPlugin{}, Options{PluginName: "argoproj-labs/conformance", Config: []byte(`{"value":1}`)})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, report.Failed())
+
+	report, err = Serve(types.PluginTypeStep, &stepPlugin{asyncTerminate: true}, Options{PluginName: "argoproj-labs/conformance"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"InitPlugin": "PASS",
+		"Type":       "PASS",
+		"Info":       "FAIL: the config is not valid with the config schema of the plugin: config in body must be of type object: \"null\"",
+		"Run":        "PASS",
+		"Progress":   "SKIP",
+		"Terminate":  "FAIL: Terminate returned the Running phase, but it cannot run asynchronously",
+		"Abort":      "PASS",
+	}, results(report))
+}
+
+func TestRunExecutable(t *testing.T) {
+	_, err := RunExecutable("Unknown", "plugin", nil, Options{})
+	assert.EqualError(t, err, "unknown plugin type Unknown")
+
+	_, err = RunExecutable(types.PluginTypeStep, "/does/not/exist", nil, Options{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to start plugin")
+}
+
+func TestReportPrint(t *testing.T) {
+	report := runScenarios([]scenario{
+		{name: "Pass", run: func() error { return nil }},
+		{name: "Skip", run: func() error { return skip("not %s", "implemented") }},
+		{name: "Fail", run: func() error { return rpcError("Fail", types.RpcError{ErrorString: "failure"}) }},
+	})
+	var out bytes.Buffer
+	report.Print(&out)
+	assert.Equal(t, `SCENARIO  RESULT  MESSAGE
+Pass      PASS    
+Skip      SKIP    not implemented
+Fail      FAIL    Fail returned an error: failure
+`, out.String())
+	assert.Equal(t, 1, report.Failed())
+}
@@ -0,0 +1,52 @@
+// Package conformancetest runs the conformance scenarios against the implementation of a plugin in its Go tests, and
+// reports each scenario as a subtest. It is kept apart from the conformance package so that the testing package is
+// not linked into the binaries which run the scenarios against plugin executables.
+package conformancetest
+
+import (
+	"testing"
+
+	metricRpc "github.com/argoproj/argo-rollouts/metricproviders/plugin/rpc"
+	stepRpc "github.com/argoproj/argo-rollouts/rollout/steps/plugin/rpc"
+	trafficRouterRpc "github.com/argoproj/argo-rollouts/rollout/trafficrouting/plugin/rpc"
+	"github.com/argoproj/argo-rollouts/utils/plugin/conformance"
+	"github.com/argoproj/argo-rollouts/utils/plugin/types"
+)
+
+// check serves the implementation of a plugin over RPC and reports each scenario as a subtest of t
+func check(t *testing.T, pluginType types.PluginType, impl any, opts conformance.Options) {
+	t.Helper()
+	report, err := conformance.Serve(pluginType, impl, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, result := range report {
+		t.Run(result.Scenario, func(t *testing.T) {
+			if result.Err != nil {
+				t.Error(result.Err)
+			} else if result.Skipped {
+				t.Skip(result.Message)
+			}
+		})
+	}
+}
+
+// CheckTrafficRouterPlugin runs the traffic router scenarios against the implementation of a plugin, served over RPC,
+// as subtests of t
+func CheckTrafficRouterPlugin(t *testing.T, impl trafficRouterRpc.TrafficRouterPlugin, opts conformance.Options) {
+	t.Helper()
+	check(t, types.PluginTypeTrafficRouter, impl, opts)
+}
+
+// CheckMetricProviderPlugin runs the metric provider scenarios against the implementation of a plugin, served over
+// RPC, as subtests of t
+func CheckMetricProviderPlugin(t *testing.T, impl metricRpc.MetricProviderPlugin, opts conformance.Options) {
+	t.Helper()
+	check(t, types.PluginTypeMetricProvider, impl, opts)
+}
+
+// CheckStepPlugin runs the step scenarios against the implementation of a plugin, served over RPC, as subtests of t
+func CheckStepPlugin(t *testing.T, impl stepRpc.StepPlugin, opts conformance.Options) {
+	t.Helper()
+	check(t, types.PluginTypeStep, impl, opts)
+}
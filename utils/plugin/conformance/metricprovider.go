@@ -0,0 +1,113 @@
+package conformance
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	metricRpc "github.com/argoproj/argo-rollouts/metricproviders/plugin/rpc"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+// newAnalysisRun returns the analysis run of the metric provider scenarios, with a metric of the plugin
+func newAnalysisRun(opts Options) (*v1alpha1.AnalysisRun, v1alpha1.Metric) {
+	metric := v1alpha1.Metric{
+		Name: "conformance",
+		Provider: v1alpha1.MetricProvider{
+			Plugin: map[string]json.RawMessage{opts.PluginName: opts.Config},
+		},
+	}
+	run := &v1alpha1.AnalysisRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "conformance",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: v1alpha1.AnalysisRunSpec{
+			Metrics: []v1alpha1.Metric{metric},
+		},
+	}
+	return run, metric
+}
+
+// validateMeasurement returns an error if a measurement returned by an operation has an invalid or an Error phase
+func validateMeasurement(operation string, measurement v1alpha1.Measurement) error {
+	switch measurement.Phase {
+	case v1alpha1.AnalysisPhaseError:
+		return fmt.Errorf("%s returned an Error measurement: %s", operation, measurement.Message)
+	case v1alpha1.AnalysisPhaseRunning, v1alpha1.AnalysisPhaseSuccessful, v1alpha1.AnalysisPhaseFailed, v1alpha1.AnalysisPhaseInconclusive:
+		return nil
+	}
+	return fmt.Errorf("%s returned a measurement with the invalid phase '%s'", operation, measurement.Phase)
+}
+
+// RunMetricProviderPlugin runs the metric provider scenarios against a plugin: a measurement is taken, resumed until
+// it completes, then terminated, and the measurements are garbage collected
+func RunMetricProviderPlugin(plugin metricRpc.MetricProviderPlugin, opts Options) Report {
+	run, metric := newAnalysisRun(opts)
+
+	return runScenarios([]scenario{
+		{name: "InitPlugin", run: func() error {
+			return rpcError("InitPlugin", plugin.InitPlugin())
+		}},
+		{name: "Type", run: func() error {
+			if plugin.Type() == "" {
+				return fmt.Errorf("Type returned an empty type")
+			}
+			return nil
+		}},
+		{name: "GetMetadata", run: func() error {
+			// the metadata is optional, but the call must succeed
+			plugin.GetMetadata(metric)
+			return nil
+		}},
+		{name: "Run", run: func() error {
+			measurement := plugin.Run(run, metric)
+			if err := validateMeasurement("Run", measurement); err != nil {
+				return err
+			}
+			deadline := time.Now().Add(opts.timeout())
+			for measurement.Phase == v1alpha1.AnalysisPhaseRunning {
+				if time.Now().After(deadline) {
+					return fmt.Errorf("measurement did not complete within %s", opts.timeout())
+				}
+				wait := pollInterval
+				if measurement.ResumeAt != nil {
+					wait = min(max(time.Until(measurement.ResumeAt.Time), 0), time.Until(deadline))
+				}
+				time.Sleep(wait)
+				measurement = plugin.Resume(run, metric, measurement)
+				if err := validateMeasurement("Resume", measurement); err != nil {
+					return err
+				}
+			}
+			run.Status.MetricResults = append(run.Status.MetricResults, v1alpha1.MetricResult{
+				Name:         metric.Name,
+				Phase:        measurement.Phase,
+				Measurements: []v1alpha1.Measurement{measurement},
+			})
+			return nil
+		}},
+		{name: "Terminate", run: func() error {
+			measurement := plugin.Run(run, metric)
+			if err := validateMeasurement("Run", measurement); err != nil {
+				return err
+			}
+			if measurement.Phase != v1alpha1.AnalysisPhaseRunning {
+				return skip("the measurements of the plugin complete immediately")
+			}
+			measurement = plugin.Terminate(run, metric, measurement)
+			if err := validateMeasurement("Terminate", measurement); err != nil {
+				return err
+			}
+			if measurement.Phase == v1alpha1.AnalysisPhaseRunning {
+				return fmt.Errorf("Terminate returned a Running measurement")
+			}
+			return nil
+		}},
+		{name: "GarbageCollect", run: func() error {
+			return rpcError("GarbageCollect", plugin.GarbageCollect(run, metric, 1))
+		}},
+	})
+}
@@ -0,0 +1,192 @@
+package conformance
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+
+	stepRpc "github.com/argoproj/argo-rollouts/rollout/steps/plugin/rpc"
+	"github.com/argoproj/argo-rollouts/utils/plugin/types"
+)
+
+// validateStepResult returns an error if the result of an operation has an invalid phase or status
+func validateStepResult(operation string, result types.RpcStepResult) error {
+	if result.Phase != "" {
+		if err := result.Phase.Validate(); err != nil {
+			return fmt.Errorf("%s returned an invalid phase: %w", operation, err)
+		}
+	}
+	if result.Phase == types.PhaseError {
+		return fmt.Errorf("%s returned the Error phase: %s", operation, result.Message)
+	}
+	if len(result.Status) > 0 && !json.Valid(result.Status) {
+		return fmt.Errorf("%s returned a status which is not valid JSON", operation)
+	}
+	if result.Progress != nil && (result.Progress.Percent < 0 || result.Progress.Percent > 100) {
+		return fmt.Errorf("%s returned the progress %d%%, which is not between 0 and 100", operation, result.Progress.Percent)
+	}
+	return nil
+}
+
+// RunStepPlugin runs the step scenarios against a plugin: the step is run until it completes, passing back the status
+// it returns, and its Terminate and Abort operations are called for a new run
+func RunStepPlugin(plugin stepRpc.StepPluginClient, opts Options) Report {
+	ro := newRollout(opts)
+	var info types.RpcStepPluginInfo
+
+	// newContext returns the context of an operation resuming the operation which returned the result
+	newContext := func(result *types.RpcStepResult) *types.RpcStepContext {
+		stepContext := &types.RpcStepContext{PluginName: opts.PluginName, Config: opts.Config}
+		if result != nil {
+			stepContext.Status = result.Status
+			stepContext.StateVersion = info.StateVersion
+		}
+		return stepContext
+	}
+
+	// runStep runs the step once, like the controller does when it reaches the step
+	runStep := func() (types.RpcStepResult, error) {
+		result, err := plugin.Run(ro, newContext(nil))
+		if err := rpcError("Run", err); err != nil {
+			return result, err
+		}
+		return result, validateStepResult("Run", result)
+	}
+
+	// completeStep runs the step again, with the status of the previous execution, until it completes
+	completeStep := func(result types.RpcStepResult) error {
+		deadline := time.Now().Add(opts.timeout())
+		for result.Phase == "" || result.Phase == types.PhaseRunning {
+			if time.Now().After(deadline) {
+				return fmt.Errorf("step did not complete within %s", opts.timeout())
+			}
+			wait := pollInterval
+			if result.RequeueAfter > 0 {
+				wait = min(result.RequeueAfter, time.Until(deadline))
+			}
+			time.Sleep(wait)
+			var err types.RpcError
+			result, err = plugin.Run(ro, newContext(&result))
+			if err := rpcError("Run", err); err != nil {
+				return err
+			}
+			if err := validateStepResult("Run", result); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return runScenarios([]scenario{
+		{name: "InitPlugin", run: func() error {
+			return rpcError("InitPlugin", plugin.InitPlugin())
+		}},
+		{name: "Type", run: func() error {
+			if plugin.Type() == "" {
+				return fmt.Errorf("Type returned an empty type")
+			}
+			return nil
+		}},
+		{name: "Info", run: func() error {
+			var err types.RpcError
+			info, err = plugin.Info()
+			if err := rpcError("Info", err); err != nil {
+				return err
+			}
+			if info.APIVersion < 1 || info.APIVersion > types.StepPluginAPIVersion {
+				return fmt.Errorf("Info returned the unsupported API version %d", info.APIVersion)
+			}
+			if info.StateVersion < 0 {
+				return fmt.Errorf("Info returned the negative state version %d", info.StateVersion)
+			}
+			if len(info.ConfigSchema) == 0 {
+				return nil
+			}
+			schema := &spec.Schema{}
+			if err := json.Unmarshal(info.ConfigSchema, schema); err != nil {
+				return fmt.Errorf("Info returned an invalid config schema: %w", err)
+			}
+			var config any
+			if len(opts.Config) > 0 {
+				if err := json.Unmarshal(opts.Config, &config); err != nil {
+					return fmt.Errorf("invalid config: %w", err)
+				}
+			}
+			result := validate.NewSchemaValidator(schema, nil, "config", strfmt.Default).Validate(config)
+			if result.IsValid() {
+				return nil
+			}
+			var messages []string
+			for _, err := range result.Errors {
+				messages = append(messages, err.Error())
+			}
+			return fmt.Errorf("the config is not valid with the config schema of the plugin: %s", strings.Join(messages, ", "))
+		}},
+		{name: "Run", run: func() error {
+			result, err := runStep()
+			if err != nil {
+				return err
+			}
+			return completeStep(result)
+		}},
+		{name: "Progress", run: func() error {
+			if !info.Progress {
+				return skip("the plugin does not report its progress")
+			}
+			result, err := runStep()
+			if err != nil {
+				return err
+			}
+			progress, rpcErr := plugin.Progress(ro, newContext(&result))
+			if err := rpcError("Progress", rpcErr); err != nil {
+				return err
+			}
+			if progress.Percent < 0 || progress.Percent > 100 {
+				return fmt.Errorf("Progress returned the progress %d%%, which is not between 0 and 100", progress.Percent)
+			}
+			return completeStep(result)
+		}},
+		{name: "Terminate", run: func() error {
+			result, err := runStep()
+			if err != nil {
+				return err
+			}
+			if result.Phase != "" && result.Phase != types.PhaseRunning {
+				return skip("the step completes immediately")
+			}
+			result, rpcErr := plugin.Terminate(ro, newContext(&result))
+			if err := rpcError("Terminate", rpcErr); err != nil {
+				return err
+			}
+			if err := validateStepResult("Terminate", result); err != nil {
+				return err
+			}
+			if result.Phase == types.PhaseRunning {
+				return fmt.Errorf("Terminate returned the Running phase, but it cannot run asynchronously")
+			}
+			return nil
+		}},
+		{name: "Abort", run: func() error {
+			result, err := runStep()
+			if err != nil {
+				return err
+			}
+			result, rpcErr := plugin.Abort(ro, newContext(&result))
+			if err := rpcError("Abort", rpcErr); err != nil {
+				return err
+			}
+			if err := validateStepResult("Abort", result); err != nil {
+				return err
+			}
+			if result.Phase == types.PhaseRunning {
+				return fmt.Errorf("Abort returned the Running phase, but it cannot run asynchronously")
+			}
+			return nil
+		}},
+	})
+}
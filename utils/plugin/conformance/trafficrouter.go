@@ -0,0 +1,135 @@
+package conformance
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	trafficRouterRpc "github.com/argoproj/argo-rollouts/rollout/trafficrouting/plugin/rpc"
+	"github.com/argoproj/argo-rollouts/utils/plugin/types"
+)
+
+const (
+	headerRouteName = "conformance-header-route"
+	mirrorRouteName = "conformance-mirror-route"
+	canaryHash      = "conformance-canary-hash"
+	stableHash      = "conformance-stable-hash"
+)
+
+// RunTrafficRouterPlugin runs the traffic router scenarios against a plugin: a canary is shifted from 0 to 100% of
+// the traffic, with additional destinations, header and mirror routes, and the managed routes are removed
+func RunTrafficRouterPlugin(plugin trafficRouterRpc.TrafficRouterPlugin, opts Options) Report {
+	ro := newRollout(opts)
+	additionalDestinations := []v1alpha1.WeightDestination{
+		{ServiceName: "conformance-experiment", PodTemplateHash: "conformance-experiment-hash", Weight: 10},
+	}
+
+	// setWeight sets the weight of the canary and waits until the plugin verifies it
+	setWeight := func(desiredWeight int32, additionalDestinations []v1alpha1.WeightDestination) error {
+		if err := rpcError("SetWeight", plugin.SetWeight(ro, desiredWeight, additionalDestinations)); err != nil {
+			return err
+		}
+		deadline := time.Now().Add(opts.timeout())
+		for {
+			verified, err := plugin.VerifyWeight(ro, desiredWeight, additionalDestinations)
+			if err := rpcError("VerifyWeight", err); err != nil {
+				return err
+			}
+			switch verified {
+			case types.Verified, types.NotImplemented:
+				return nil
+			case types.NotVerified:
+			default:
+				return fmt.Errorf("VerifyWeight returned the unknown verification %d", verified)
+			}
+			if time.Now().After(deadline) {
+				return fmt.Errorf("weight %d was not verified within %s", desiredWeight, opts.timeout())
+			}
+			time.Sleep(pollInterval)
+		}
+	}
+
+	return runScenarios([]scenario{
+		{name: "InitPlugin", run: func() error {
+			return rpcError("InitPlugin", plugin.InitPlugin())
+		}},
+		{name: "Type", run: func() error {
+			if plugin.Type() == "" {
+				return fmt.Errorf("Type returned an empty type")
+			}
+			return nil
+		}},
+		{name: "UpdateHash", run: func() error {
+			return rpcError("UpdateHash", plugin.UpdateHash(ro, canaryHash, stableHash, nil))
+		}},
+		{name: "SetWeight", run: func() error {
+			for _, desiredWeight := range []int32{0, 25, 50, 100, 0} {
+				if err := setWeight(desiredWeight, nil); err != nil {
+					return fmt.Errorf("weight %d: %w", desiredWeight, err)
+				}
+			}
+			return nil
+		}},
+		{name: "SetWeight with additional destinations", run: func() error {
+			if err := rpcError("UpdateHash", plugin.UpdateHash(ro, canaryHash, stableHash, additionalDestinations)); err != nil {
+				return err
+			}
+			if err := setWeight(20, additionalDestinations); err != nil {
+				return err
+			}
+			if err := rpcError("UpdateHash", plugin.UpdateHash(ro, canaryHash, stableHash, nil)); err != nil {
+				return err
+			}
+			return setWeight(0, nil)
+		}},
+		{name: "SetHeaderRoute", run: func() error {
+			headerRoute := &v1alpha1.SetHeaderRoute{
+				Name: headerRouteName,
+				Match: []v1alpha1.HeaderRoutingMatch{
+					{HeaderName: "X-Conformance", HeaderValue: &v1alpha1.StringMatch{Exact: "canary"}},
+				},
+			}
+			if err := rpcError("SetHeaderRoute", plugin.SetHeaderRoute(ro, headerRoute)); err != nil {
+				return err
+			}
+			// a header route without match is removed
+			return rpcError("SetHeaderRoute", plugin.SetHeaderRoute(ro, &v1alpha1.SetHeaderRoute{Name: headerRouteName}))
+		}},
+		{name: "SetMirrorRoute", run: func() error {
+			mirrorRoute := &v1alpha1.SetMirrorRoute{
+				Name: mirrorRouteName,
+				Match: []v1alpha1.RouteMatch{
+					{Path: &v1alpha1.StringMatch{Prefix: "/conformance"}},
+				},
+				Percentage: ptr.To[int32](50),
+			}
+			if err := rpcError("SetMirrorRoute", plugin.SetMirrorRoute(ro, mirrorRoute)); err != nil {
+				return err
+			}
+			// a mirror route without match is removed
+			return rpcError("SetMirrorRoute", plugin.SetMirrorRoute(ro, &v1alpha1.SetMirrorRoute{Name: mirrorRouteName}))
+		}},
+		{name: "RemoveManagedRoutes", run: func() error {
+			if err := rpcError("SetHeaderRoute", plugin.SetHeaderRoute(ro, &v1alpha1.SetHeaderRoute{
+				Name:  headerRouteName,
+				Match: []v1alpha1.HeaderRoutingMatch{{HeaderName: "X-Conformance", HeaderValue: &v1alpha1.StringMatch{Prefix: "canary"}}},
+			})); err != nil {
+				return err
+			}
+			if err := rpcError("RemoveManagedRoutes", plugin.RemoveManagedRoutes(ro)); err != nil {
+				return err
+			}
+			// the routes may already be removed
+			return rpcError("RemoveManagedRoutes", plugin.RemoveManagedRoutes(ro))
+		}},
+		{name: "Healthz", run: func() error {
+			checker, ok := plugin.(types.RpcTrafficRouterHealthChecker)
+			if !ok {
+				return skip("the plugin does not implement Healthz")
+			}
+			return rpcError("Healthz", checker.Healthz(ro))
+		}},
+	})
+}
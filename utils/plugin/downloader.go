@@ -9,12 +9,16 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 
 	argoConfig "github.com/argoproj/argo-rollouts/utils/config"
+	"github.com/argoproj/argo-rollouts/utils/cosign"
+	"github.com/argoproj/argo-rollouts/utils/plugin/types"
 
 	"github.com/argoproj/argo-rollouts/utils/defaults"
 
@@ -39,6 +43,11 @@ func (fd FileDownloaderImpl) Get(url string, header http.Header) (resp *http.Res
 	return http.DefaultClient.Do(request)
 }
 
+// pullArtifact pulls the plugin executable of an OCI artifact, and returns it with the digest of the artifact
+var pullArtifact = func(ctx context.Context, artifact string, credentials map[string]cosign.Credentials) ([]byte, string, error) {
+	return (&cosign.ArtifactPuller{}).Pull(ctx, artifact, credentials)
+}
+
 // checkPluginExists this function checks if the plugin exists in the configured path on the filesystem
 func checkPluginExists(pluginLocation string) error {
 	if pluginLocation != "" {
@@ -118,84 +127,204 @@ func DownloadPlugins(fd FileDownloader, kubeClient kubernetes.Interface) error {
 	}
 
 	for _, plugin := range config.GetAllPlugins() {
-		urlObj, err := url.ParseRequestURI(plugin.Location)
-		if err != nil {
-			return fmt.Errorf("failed to parse plugin location: %w", err)
+		if err := downloadPlugin(fd, kubeClient, absoluteFilepath, plugin); err != nil {
+			return err
 		}
+	}
+
+	return nil
+}
+
+// fetchedPlugin is the executable of a plugin which was downloaded and verified into a temporary file
+type fetchedPlugin struct {
+	tmpPath string
+	path    string
+}
+
+// install moves the executable to the path of the plugin. The executable is replaced rather than overwritten, so that
+// a running process of the plugin keeps its executable.
+func (f fetchedPlugin) install() error {
+	if err := os.Rename(f.tmpPath, f.path); err != nil {
+		return fmt.Errorf("failed to install plugin executable %s: %w", f.path, err)
+	}
+	return nil
+}
+
+// downloadPlugin downloads the executable of a plugin, verifies it and moves it to the path of the plugin
+func downloadPlugin(fd FileDownloader, kubeClient kubernetes.Interface, absoluteFilepath string, plugin types.PluginItem) error {
+	f, err := fetchPlugin(fd, kubeClient, absoluteFilepath, plugin)
+	if err != nil {
+		return err
+	}
+	return f.install()
+}
+
+// fetchPlugin downloads the executable of a plugin into a temporary file next to the path of the plugin, and verifies
+// it. The temporary file is removed if the executable cannot be downloaded or verified, so that the path of the plugin
+// only ever holds a verified executable.
+func fetchPlugin(fd FileDownloader, kubeClient kubernetes.Interface, absoluteFilepath string, plugin types.PluginItem) (f fetchedPlugin, err error) {
+	urlObj, err := url.ParseRequestURI(plugin.Location)
+	if err != nil {
+		return f, fmt.Errorf("failed to parse plugin location: %w", err)
+	}
+
+	dir, pluginFile, err := argoConfig.GetPluginDirectoryAndFilename(plugin.Name)
+	if err != nil {
+		return f, fmt.Errorf("failed to convert plugin name (%s) to directory and filename: (%w)", plugin.Name, err)
+	}
+
+	finalFolderLocation := filepath.Join(absoluteFilepath, dir)
+	err = os.MkdirAll(finalFolderLocation, 0700)
+	if err != nil {
+		return f, fmt.Errorf("failed to create plugin folder for plugin (%s): (%w)", plugin.Name, err)
+	}
 
-		dir, pluginFile, err := argoConfig.GetPluginDirectoryAndFilename(plugin.Name)
+	tmpFile, err := os.CreateTemp(finalFolderLocation, "."+pluginFile+"-*")
+	if err != nil {
+		return f, fmt.Errorf("failed to create temporary file for plugin (%s): %w", plugin.Name, err)
+	}
+	tmpFile.Close()
+	f = fetchedPlugin{tmpPath: tmpFile.Name(), path: filepath.Join(finalFolderLocation, pluginFile)}
+	defer func() {
 		if err != nil {
-			return fmt.Errorf("failed to convert plugin name (%s) to directory and filename: (%w)", plugin.Name, err)
+			os.Remove(f.tmpPath)
+		}
+	}()
+	downloadLocation := f.tmpPath
+
+	switch urlObj.Scheme {
+	case "http", "https":
+		log.Infof("Downloading plugin %s from: %s", plugin.Name, plugin.Location)
+		startTime := time.Now()
+		requestHeader := http.Header{}
+		for _, header := range plugin.HeadersFrom {
+			secret, err := kubeClient.CoreV1().Secrets(defaults.Namespace()).Get(context.Background(), header.SecretRef.Name, metav1.GetOptions{})
+			if err != nil {
+				return f, fmt.Errorf("failed to get secret in secretRef: %w", err)
+			}
+			for k, v := range secret.Data {
+				requestHeader.Add(k, string(v))
+			}
 		}
 
-		finalFolderLocation := filepath.Join(absoluteFilepath, dir)
-		err = os.MkdirAll(finalFolderLocation, 0700)
+		err = downloadFile(downloadLocation, urlObj.String(), fd, requestHeader)
 		if err != nil {
-			return fmt.Errorf("failed to create plugin folder for plugin (%s): (%w)", plugin.Name, err)
-		}
-
-		finalFileLocation := filepath.Join(finalFolderLocation, pluginFile)
-
-		switch urlObj.Scheme {
-		case "http", "https":
-			log.Infof("Downloading plugin %s from: %s", plugin.Name, plugin.Location)
-			startTime := time.Now()
-			requestHeader := http.Header{}
-			for _, header := range plugin.HeadersFrom {
-				secret, err := kubeClient.CoreV1().Secrets(defaults.Namespace()).Get(context.Background(), header.SecretRef.Name, metav1.GetOptions{})
-				if err != nil {
-					return fmt.Errorf("failed to get secret in secretRef: %w", err)
-				}
-				for k, v := range secret.Data {
-					requestHeader.Add(k, string(v))
-				}
-			}
+			return f, fmt.Errorf("failed to download plugin from %s: %w", plugin.Location, err)
+		}
+		timeTakenToDownload := time.Now().Sub(startTime)
+		log.Infof("Download complete, it took %s", timeTakenToDownload)
 
-			err = downloadFile(finalFileLocation, urlObj.String(), fd, requestHeader)
+		if plugin.Sha256 != "" {
+			sha256Matched, err := checkShaOfPlugin(downloadLocation, plugin.Sha256)
 			if err != nil {
-				return fmt.Errorf("failed to download plugin from %s: %w", plugin.Location, err)
+				return f, fmt.Errorf("failed to check sha256 of downloaded plugin: %w", err)
 			}
-			timeTakenToDownload := time.Now().Sub(startTime)
-			log.Infof("Download complete, it took %s", timeTakenToDownload)
-
-			if plugin.Sha256 != "" {
-				sha256Matched, err := checkShaOfPlugin(finalFileLocation, plugin.Sha256)
-				if err != nil {
-					return fmt.Errorf("failed to check sha256 of downloaded plugin: %w", err)
-				}
-				if !sha256Matched {
-					return fmt.Errorf("sha256 hash of downloaded plugin (%s) does not match expected hash", plugin.Location)
-				}
-			}
-			if checkPluginExists(finalFileLocation) != nil {
-				return fmt.Errorf("failed to find downloaded plugin at location: %s", plugin.Location)
+			if !sha256Matched {
+				return f, fmt.Errorf("sha256 hash of downloaded plugin (%s) does not match expected hash", plugin.Location)
 			}
+		}
+		if checkPluginExists(downloadLocation) != nil {
+			return f, fmt.Errorf("failed to find downloaded plugin at location: %s", plugin.Location)
+		}
+
+	case "oci":
+		artifact := strings.TrimPrefix(plugin.Location, "oci://")
+		log.Infof("Pulling plugin %s from: %s", plugin.Name, artifact)
+		credentials, err := getPullCredentials(kubeClient, plugin.PullSecrets)
+		if err != nil {
+			return f, fmt.Errorf("failed to get pull secrets of plugin (%s): %w", plugin.Name, err)
+		}
+		content, digest, err := pullArtifact(context.Background(), artifact, credentials)
+		if err != nil {
+			return f, fmt.Errorf("failed to pull plugin from %s: %w", plugin.Location, err)
+		}
+		if err := os.WriteFile(downloadLocation, content, 0700); err != nil {
+			return f, fmt.Errorf("failed to write plugin to %s: %w", downloadLocation, err)
+		}
+		// Set the file permissions, to allow execution
+		if err := os.Chmod(downloadLocation, 0700); err != nil {
+			return f, fmt.Errorf("failed to set file permissions of plugin (%s): %w", downloadLocation, err)
+		}
+		log.Infof("Pulled plugin %s with digest %s", plugin.Name, digest)
 
-		case "file":
-			pluginPath, err := filepath.Abs(urlObj.Host + urlObj.Path)
+		if plugin.Sha256 != "" {
+			sha256Matched, err := checkShaOfPlugin(downloadLocation, plugin.Sha256)
 			if err != nil {
-				return fmt.Errorf("failed to get absolute path of plugin: %w", err)
+				return f, fmt.Errorf("failed to check sha256 of pulled plugin: %w", err)
 			}
-
-			if err := copyFile(pluginPath, finalFileLocation); err != nil {
-				return fmt.Errorf("failed to copy plugin from %s to %s: %w", pluginPath, finalFileLocation, err)
+			if !sha256Matched {
+				return f, fmt.Errorf("sha256 hash of pulled plugin (%s) does not match expected hash", plugin.Location)
 			}
+		}
 
-			log.Infof("Copied plugin from %s to %s", pluginPath, finalFileLocation)
-			if checkPluginExists(finalFileLocation) != nil {
-				return fmt.Errorf("failed to find filebased plugin at location: %s", plugin.Location)
-			}
-			// Set the file permissions, to allow execution
-			err = os.Chmod(finalFileLocation, 0700)
-			if err != nil {
-				return fmt.Errorf("failed to set file permissions of plugin (%s): %w", finalFileLocation, err)
-			}
-		default:
-			return fmt.Errorf("plugin location must be of http(s) or file scheme")
+	case "file":
+		pluginPath, err := filepath.Abs(urlObj.Host + urlObj.Path)
+		if err != nil {
+			return f, fmt.Errorf("failed to get absolute path of plugin: %w", err)
 		}
+
+		if err := copyFile(pluginPath, downloadLocation); err != nil {
+			return f, fmt.Errorf("failed to copy plugin from %s to %s: %w", pluginPath, downloadLocation, err)
+		}
+
+		log.Infof("Copied plugin from %s to %s", pluginPath, downloadLocation)
+		if checkPluginExists(downloadLocation) != nil {
+			return f, fmt.Errorf("failed to find filebased plugin at location: %s", plugin.Location)
+		}
+		// Set the file permissions, to allow execution
+		err = os.Chmod(downloadLocation, 0700)
+		if err != nil {
+			return f, fmt.Errorf("failed to set file permissions of plugin (%s): %w", downloadLocation, err)
+		}
+	default:
+		return f, fmt.Errorf("plugin location must be of http(s), file or oci scheme")
 	}
 
-	return nil
+	if plugin.Signature != "" {
+		if err := verifySignatureOfPlugin(downloadLocation, plugin); err != nil {
+			return f, fmt.Errorf("failed to verify signature of plugin (%s): %w", plugin.Name, err)
+		}
+	}
+	return f, nil
+}
+
+// getPullCredentials returns the registry credentials of the docker config secrets in the namespace of the controller
+func getPullCredentials(kubeClient kubernetes.Interface, pullSecrets []types.SecretRef) (map[string]cosign.Credentials, error) {
+	credentials := map[string]cosign.Credentials{}
+	for _, ref := range pullSecrets {
+		secret, err := kubeClient.CoreV1().Secrets(defaults.Namespace()).Get(context.Background(), ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get secret %s: %w", ref.Name, err)
+		}
+		data, ok := secret.Data[corev1.DockerConfigJsonKey]
+		if !ok {
+			data, ok = secret.Data[corev1.DockerConfigKey]
+		}
+		if !ok {
+			return nil, fmt.Errorf("secret %s has no %s or %s key", ref.Name, corev1.DockerConfigJsonKey, corev1.DockerConfigKey)
+		}
+		secretCredentials, err := cosign.ParseDockerConfig(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid docker config in secret %s: %w", ref.Name, err)
+		}
+		for registry, creds := range secretCredentials {
+			credentials[registry] = creds
+		}
+	}
+	return credentials, nil
+}
+
+// verifySignatureOfPlugin verifies the signature of the plugin executable with the public key of the plugin
+func verifySignatureOfPlugin(pluginLocation string, plugin types.PluginItem) error {
+	key, err := cosign.ParsePublicKey([]byte(plugin.PublicKey))
+	if err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+	fileBytes, err := os.ReadFile(pluginLocation)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s: %w", pluginLocation, err)
+	}
+	return cosign.VerifyBlob(key, fileBytes, plugin.Signature)
 }
 
 // CopyFile copies a file from src to dst.
@@ -2,6 +2,8 @@ package plugin
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"os"
@@ -10,12 +12,15 @@ import (
 
 	"github.com/argoproj/argo-rollouts/utils/config"
 
+	"github.com/stretchr/testify/require"
 	"github.com/tj/assert"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 
+	"github.com/argoproj/argo-rollouts/utils/cosign"
 	"github.com/argoproj/argo-rollouts/utils/defaults"
+	"github.com/argoproj/argo-rollouts/utils/plugin/types"
 )
 
 type MockFileDownloader struct {
@@ -159,10 +164,12 @@ func TestPlugin(t *testing.T) {
 		err = DownloadPlugins(MockFileDownloader{}, client)
 		assert.Error(t, err)
 
-		dir, filename, err := config.GetPluginDirectoryAndFilename("argoproj-labs/http-badsha")
+		// the executable which does not match the sha256 is discarded
+		dir, _, err := config.GetPluginDirectoryAndFilename("argoproj-labs/http-badsha")
 		assert.NoError(t, err)
-		err = os.Remove(filepath.Join(defaults.DefaultRolloutPluginFolder, dir, filename))
+		entries, err := os.ReadDir(filepath.Join(defaults.DefaultRolloutPluginFolder, dir))
 		assert.NoError(t, err)
+		assert.Empty(t, entries)
 		err = os.RemoveAll(defaults.DefaultRolloutPluginFolder)
 		assert.NoError(t, err)
 	})
@@ -280,6 +287,57 @@ func TestPlugin(t *testing.T) {
 	})
 }
 
+func TestDownloadOCIPlugin(t *testing.T) {
+	defer config.UnInitializeConfig()
+	defer os.RemoveAll(defaults.DefaultRolloutPluginFolder)
+	pullArtifactOrig := pullArtifact
+	defer func() { pullArtifact = pullArtifactOrig }()
+	var pulled string
+	var pulledCredentials map[string]cosign.Credentials
+	pullArtifact = func(ctx context.Context, artifact string, credentials map[string]cosign.Credentials) ([]byte, string, error) {
+		pulled, pulledCredentials = artifact, credentials
+		return []byte("test"), "sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", nil
+	}
+
+	client, err := initializePluginConfig(t, types.PluginItem{
+		Name:        "argoproj-labs/step",
+		Location:    "oci://ghcr.io/argoproj-labs/step:v1",
+		Sha256:      "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+		PullSecrets: []types.SecretRef{{Name: "ghcr"}},
+	})
+	require.NoError(t, err)
+	err = DownloadPlugins(MockFileDownloader{}, client)
+	assert.EqualError(t, err, "failed to get pull secrets of plugin (argoproj-labs/step): failed to get secret ghcr: secrets \"ghcr\" not found")
+
+	_, err = client.CoreV1().Secrets("argo-rollouts").Create(context.TODO(), &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ghcr", Namespace: "argo-rollouts"},
+		Type:       v1.SecretTypeDockerConfigJson,
+		Data:       map[string][]byte{v1.DockerConfigJsonKey: []byte(`{"auths":{"ghcr.io":{"username":"user","password":"pass"}}}`)},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, DownloadPlugins(MockFileDownloader{}, client))
+	assert.Equal(t, "ghcr.io/argoproj-labs/step:v1", pulled)
+	assert.Equal(t, map[string]cosign.Credentials{"ghcr.io": {Username: "user", Password: "pass"}}, pulledCredentials)
+	content, err := os.ReadFile(filepath.Join(defaults.DefaultRolloutPluginFolder, "argoproj-labs", "step"))
+	require.NoError(t, err)
+	assert.Equal(t, "test", string(content))
+
+	client, err = initializePluginConfig(t, types.PluginItem{
+		Name:     "argoproj-labs/step",
+		Location: "oci://ghcr.io/argoproj-labs/step:v1",
+		Sha256:   "0000000000000000000000000000000000000000000000000000000000000000",
+	})
+	require.NoError(t, err)
+	err = DownloadPlugins(MockFileDownloader{}, client)
+	assert.EqualError(t, err, "sha256 hash of pulled plugin (oci://ghcr.io/argoproj-labs/step:v1) does not match expected hash")
+
+	pullArtifact = func(ctx context.Context, artifact string, credentials map[string]cosign.Credentials) ([]byte, string, error) {
+		return nil, "", fmt.Errorf("manifest not found")
+	}
+	err = DownloadPlugins(MockFileDownloader{}, client)
+	assert.EqualError(t, err, "failed to pull plugin from oci://ghcr.io/argoproj-labs/step:v1: manifest not found")
+}
+
 func TestCheckPluginExits(t *testing.T) {
 	t.Run("test that non existing files on the fs return error", func(t *testing.T) {
 		err := checkPluginExists("nonexistentplugin")
@@ -0,0 +1,92 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	goPlugin "github.com/hashicorp/go-plugin"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/argoproj/argo-rollouts/utils/config"
+	"github.com/argoproj/argo-rollouts/utils/plugin/types"
+)
+
+// GetPlugin returns the configuration of the plugin, or nil if it is not configured
+func GetPlugin(pluginName string, pluginType types.PluginType) *types.PluginItem {
+	configMap, err := config.GetConfig()
+	if err != nil {
+		return nil
+	}
+	return configMap.GetPlugin(pluginName, pluginType)
+}
+
+// NewClient returns a client which runs the plugin executable with the environment of the plugin. The resource limits
+// of the plugin are applied by StartClient.
+func NewClient(pluginPath string, args []string, plugin *types.PluginItem, handshakeConfig goPlugin.HandshakeConfig, plugins map[string]goPlugin.Plugin) *goPlugin.Client {
+	return goPlugin.NewClient(&goPlugin.ClientConfig{
+		HandshakeConfig: handshakeConfig,
+		Plugins:         plugins,
+		Cmd:             newCommand(pluginPath, args, plugin),
+		Managed:         true,
+		SkipHostEnv:     plugin != nil && plugin.Env != nil,
+		// the RPC connection to the plugin is authenticated and encrypted with mTLS, with certificates generated for
+		// every plugin process
+		AutoMTLS: true,
+	})
+}
+
+// newCommand returns the command of the plugin executable. A plugin with an env list only gets the listed environment
+// variables of the controller, rather than all of them.
+func newCommand(pluginPath string, args []string, plugin *types.PluginItem) *exec.Cmd {
+	cmd := exec.Command(pluginPath, args...)
+	if plugin != nil && plugin.Env != nil {
+		cmd.Env = []string{}
+		for _, name := range plugin.Env {
+			if value, ok := os.LookupEnv(name); ok {
+				cmd.Env = append(cmd.Env, name+"="+value)
+			}
+		}
+	}
+	return cmd
+}
+
+// StartClient starts the plugin process of the client and applies the resource limits of the plugin. The limits are
+// applied once the process has started and completed the handshake, and the process is killed if they cannot be
+// applied.
+func StartClient(client *goPlugin.Client, plugin *types.PluginItem) (goPlugin.ClientProtocol, error) {
+	rpcClient, err := client.Client()
+	if err != nil {
+		return nil, err
+	}
+	if plugin == nil || plugin.ResourceLimits == nil {
+		return rpcClient, nil
+	}
+	reattach := client.ReattachConfig()
+	if reattach == nil || reattach.Pid == 0 {
+		client.Kill()
+		return nil, fmt.Errorf("unable to find the process of the plugin")
+	}
+	if err := limitResources(reattach.Pid, plugin.ResourceLimits); err != nil {
+		client.Kill()
+		return nil, fmt.Errorf("unable to apply the resource limits of the plugin: %w", err)
+	}
+	return rpcClient, nil
+}
+
+// resourceLimits returns the memory limit in bytes and the maximum of open files of the plugin, which are zero if
+// unset
+func resourceLimits(limits *types.PluginResourceLimits) (uint64, uint64, error) {
+	var memory uint64
+	if limits.MemoryLimit != "" {
+		quantity, err := resource.ParseQuantity(limits.MemoryLimit)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid memoryLimit: %w", err)
+		}
+		if quantity.Value() <= 0 {
+			return 0, 0, fmt.Errorf("memoryLimit must be positive")
+		}
+		memory = uint64(quantity.Value())
+	}
+	return memory, limits.MaxOpenFiles, nil
+}
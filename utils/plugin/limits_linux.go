@@ -0,0 +1,26 @@
+package plugin
+
+import (
+	"golang.org/x/sys/unix"
+
+	"github.com/argoproj/argo-rollouts/utils/plugin/types"
+)
+
+// limitResources sets the resource limits on the plugin process
+func limitResources(pid int, limits *types.PluginResourceLimits) error {
+	memory, openFiles, err := resourceLimits(limits)
+	if err != nil {
+		return err
+	}
+	if memory > 0 {
+		if err := unix.Prlimit(pid, unix.RLIMIT_AS, &unix.Rlimit{Cur: memory, Max: memory}, nil); err != nil {
+			return err
+		}
+	}
+	if openFiles > 0 {
+		if err := unix.Prlimit(pid, unix.RLIMIT_NOFILE, &unix.Rlimit{Cur: openFiles, Max: openFiles}, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
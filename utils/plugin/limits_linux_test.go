@@ -0,0 +1,27 @@
+package plugin
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/argoproj/argo-rollouts/utils/plugin/types"
+)
+
+func TestLimitResources(t *testing.T) {
+	cmd := exec.Command("sleep", "30")
+	require.NoError(t, cmd.Start())
+	defer cmd.Process.Kill()
+
+	err := limitResources(cmd.Process.Pid, &types.PluginResourceLimits{MemoryLimit: "1Gi", MaxOpenFiles: 64})
+	require.NoError(t, err)
+
+	limits, err := os.ReadFile("/proc/" + strconv.Itoa(cmd.Process.Pid) + "/limits")
+	require.NoError(t, err)
+	assert.Regexp(t, `Max address space\s+1073741824\s+1073741824`, string(limits))
+	assert.Regexp(t, `Max open files\s+64\s+64`, string(limits))
+}
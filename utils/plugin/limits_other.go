@@ -0,0 +1,21 @@
+//go:build !linux
+
+package plugin
+
+import (
+	"fmt"
+
+	"github.com/argoproj/argo-rollouts/utils/plugin/types"
+)
+
+// limitResources fails if there are resource limits, since they are only supported on Linux
+func limitResources(pid int, limits *types.PluginResourceLimits) error {
+	memory, openFiles, err := resourceLimits(limits)
+	if err != nil {
+		return err
+	}
+	if memory > 0 || openFiles > 0 {
+		return fmt.Errorf("resource limits of plugins are only supported on Linux")
+	}
+	return nil
+}
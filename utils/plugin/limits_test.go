@@ -0,0 +1,169 @@
+package plugin
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/argoproj/argo-rollouts/utils/config"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+	"github.com/argoproj/argo-rollouts/utils/plugin/types"
+)
+
+func initializePluginConfig(t *testing.T, plugins ...types.PluginItem) (*fake.Clientset, error) {
+	data, err := yaml.Marshal(plugins)
+	require.NoError(t, err)
+	client := fake.NewSimpleClientset(&v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "argo-rollouts-config",
+			Namespace: "argo-rollouts",
+		},
+		Data: map[string]string{"stepPlugins": string(data)},
+	})
+	config.UnInitializeConfig()
+	_, err = config.InitializeConfig(client, "argo-rollouts-config")
+	return client, err
+}
+
+// signPlugin signs the content of the MockFileDownloader, and returns the PEM encoded public key and the signature
+func signPlugin(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte("test"))
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	publicKey := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	return string(publicKey), base64.StdEncoding.EncodeToString(signature)
+}
+
+func TestPluginVerificationConfig(t *testing.T) {
+	defer config.UnInitializeConfig()
+	publicKey, signature := signPlugin(t)
+
+	tests := []struct {
+		name   string
+		plugin types.PluginItem
+		err    string
+	}{{
+		name:   "signature without public key",
+		plugin: types.PluginItem{Name: "argoproj-labs/step", Location: "https://test/plugin", Signature: signature},
+		err:    "signature requires a publicKey",
+	}, {
+		name:   "invalid public key",
+		plugin: types.PluginItem{Name: "argoproj-labs/step", Location: "https://test/plugin", Signature: signature, PublicKey: "foo"},
+		err:    "invalid publicKey",
+	}, {
+		name:   "invalid memory limit",
+		plugin: types.PluginItem{Name: "argoproj-labs/step", Location: "file://./plugin", ResourceLimits: &types.PluginResourceLimits{MemoryLimit: "foo"}},
+		err:    "invalid resourceLimits memoryLimit",
+	}, {
+		name:   "plugin with signature and resource limits",
+		plugin: types.PluginItem{Name: "argoproj-labs/step", Location: "https://test/plugin", Signature: signature, PublicKey: publicKey, ResourceLimits: &types.PluginResourceLimits{MemoryLimit: "1Gi"}},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := initializePluginConfig(t, test.plugin)
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.err)
+			}
+		})
+	}
+}
+
+func TestDownloadSignedPlugin(t *testing.T) {
+	defer config.UnInitializeConfig()
+	defer os.RemoveAll(defaults.DefaultRolloutPluginFolder)
+	publicKey, signature := signPlugin(t)
+
+	client, err := initializePluginConfig(t, types.PluginItem{
+		Name:      "argoproj-labs/step",
+		Location:  "https://test/plugin",
+		Signature: signature,
+		PublicKey: publicKey,
+	})
+	require.NoError(t, err)
+	// the executable is replaced on each download
+	for range 2 {
+		require.NoError(t, DownloadPlugins(MockFileDownloader{}, client))
+	}
+	_, err = os.Stat(filepath.Join(defaults.DefaultRolloutPluginFolder, "argoproj-labs", "step"))
+	require.NoError(t, err)
+
+	otherPublicKey, _ := signPlugin(t)
+	client, err = initializePluginConfig(t, types.PluginItem{
+		Name:      "argoproj-labs/step",
+		Location:  "https://test/plugin",
+		Signature: signature,
+		PublicKey: otherPublicKey,
+	})
+	require.NoError(t, err)
+	err = DownloadPlugins(MockFileDownloader{}, client)
+	assert.ErrorContains(t, err, "failed to verify signature of plugin (argoproj-labs/step)")
+}
+
+func TestGetPlugin(t *testing.T) {
+	defer config.UnInitializeConfig()
+	config.UnInitializeConfig()
+	assert.Nil(t, GetPlugin("argoproj-labs/step", types.PluginTypeStep))
+
+	limits := &types.PluginResourceLimits{MaxOpenFiles: 64}
+	_, err := initializePluginConfig(t,
+		types.PluginItem{Name: "argoproj-labs/step", Location: "file://./plugin", ResourceLimits: limits},
+		types.PluginItem{Name: "argoproj-labs/other", Location: "file://./plugin"},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, limits, GetPlugin("argoproj-labs/step", types.PluginTypeStep).ResourceLimits)
+	assert.Nil(t, GetPlugin("argoproj-labs/other", types.PluginTypeStep).ResourceLimits)
+	assert.Nil(t, GetPlugin("argoproj-labs/step", types.PluginTypeTrafficRouter))
+}
+
+func TestNewCommand(t *testing.T) {
+	t.Setenv("PLUGIN_TOKEN", "foo")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "bar")
+
+	cmd := newCommand("/plugin", []string{"-l", "2"}, nil)
+	assert.Equal(t, []string{"/plugin", "-l", "2"}, cmd.Args)
+	assert.Nil(t, cmd.Env)
+
+	cmd = newCommand("/plugin", nil, &types.PluginItem{})
+	assert.Nil(t, cmd.Env)
+
+	cmd = newCommand("/plugin", nil, &types.PluginItem{Env: []string{"PLUGIN_TOKEN", "MISSING"}})
+	assert.Equal(t, []string{"PLUGIN_TOKEN=foo"}, cmd.Env)
+
+	cmd = newCommand("/plugin", nil, &types.PluginItem{Env: []string{}})
+	assert.Equal(t, []string{}, cmd.Env)
+}
+
+func TestResourceLimits(t *testing.T) {
+	memory, openFiles, err := resourceLimits(&types.PluginResourceLimits{MemoryLimit: "1Gi", MaxOpenFiles: 64})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1<<30), memory)
+	assert.Equal(t, uint64(64), openFiles)
+
+	memory, openFiles, err = resourceLimits(&types.PluginResourceLimits{})
+	assert.NoError(t, err)
+	assert.Zero(t, memory)
+	assert.Zero(t, openFiles)
+
+	_, _, err = resourceLimits(&types.PluginResourceLimits{MemoryLimit: "0"})
+	assert.EqualError(t, err, "memoryLimit must be positive")
+}
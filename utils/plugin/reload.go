@@ -0,0 +1,106 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	goPlugin "github.com/hashicorp/go-plugin"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	argoConfig "github.com/argoproj/argo-rollouts/utils/config"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+	"github.com/argoproj/argo-rollouts/utils/plugin/types"
+)
+
+// DrainTimeout is the maximum duration to wait for the in-flight calls of a plugin to complete before its process is
+// stopped
+var DrainTimeout = 30 * time.Second
+
+var reloadersLock sync.Mutex
+var reloaders = map[types.PluginType]func(pluginName string){}
+
+// RegisterReloader registers the function which stops the running process of a plugin of the type when its
+// definition changes, so that the plugin is started again with its new definition the next time it is used
+func RegisterReloader(pluginType types.PluginType, reload func(pluginName string)) {
+	reloadersLock.Lock()
+	defer reloadersLock.Unlock()
+	reloaders[pluginType] = reload
+}
+
+// ReloadPlugins reloads the config of the controller from the configmap, downloads the plugins which were added or
+// changed, and stops the running processes of the plugins which were changed or removed. The executables of the
+// plugins and the config are only replaced once every added or changed plugin was downloaded and verified. Nothing is
+// reloaded otherwise, so the plugins keep their previous definition and executable.
+func ReloadPlugins(fd FileDownloader, kubeClient kubernetes.Interface, configMap *corev1.ConfigMap) error {
+	config, changed, err := argoConfig.ParseConfig(configMap)
+	if err != nil {
+		return fmt.Errorf("failed to reload config: %w", err)
+	}
+
+	absoluteFilepath, err := filepath.Abs(defaults.DefaultRolloutPluginFolder)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path of plugin folder: %w", err)
+	}
+
+	var fetched []fetchedPlugin
+	defer func() {
+		// the executables which were not installed are discarded
+		for _, f := range fetched {
+			os.Remove(f.tmpPath)
+		}
+	}()
+	var errs []error
+	for _, plugin := range changed {
+		if config.GetPlugin(plugin.Name, plugin.Type) == nil {
+			continue
+		}
+		f, err := fetchPlugin(fd, kubeClient, absoluteFilepath, plugin)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		fetched = append(fetched, f)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to reload config, the plugins keep their previous definition: %w", errors.Join(errs...))
+	}
+	// the running processes keep the previous executables until they are stopped
+	for _, f := range fetched {
+		if err := f.install(); err != nil {
+			return err
+		}
+	}
+	argoConfig.SetConfig(config)
+
+	for _, plugin := range changed {
+		if config.GetPlugin(plugin.Name, plugin.Type) != nil {
+			log.Infof("Reloading plugin %s", plugin.Name)
+		} else {
+			log.Infof("Removing plugin %s", plugin.Name)
+		}
+		reloadersLock.Lock()
+		reload := reloaders[plugin.Type]
+		reloadersLock.Unlock()
+		if reload != nil {
+			reload(plugin.Name)
+		}
+	}
+	return nil
+}
+
+// StopClient stops the process of a plugin once the in-flight calls of its rpc client have completed, or DrainTimeout
+// has expired
+func StopClient(pluginName string, client *goPlugin.Client, rpcClient any) {
+	if drainer, ok := rpcClient.(interface{ Drain(time.Duration) bool }); ok {
+		if !drainer.Drain(DrainTimeout) {
+			log.Warnf("Stopping plugin %s with in-flight calls after %s", pluginName, DrainTimeout)
+		}
+	}
+	client.Kill()
+}
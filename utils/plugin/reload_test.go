@@ -0,0 +1,110 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tj/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/argoproj/argo-rollouts/utils/config"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+	"github.com/argoproj/argo-rollouts/utils/plugin/types"
+)
+
+func TestReloadPlugins(t *testing.T) {
+	newConfigMap := func(data map[string]string) *v1.ConfigMap {
+		return &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      defaults.DefaultRolloutsConfigMapName,
+				Namespace: defaults.Namespace(),
+			},
+			Data: data,
+		}
+	}
+	cm := newConfigMap(map[string]string{
+		"stepPlugins":           "\n  - name: argoproj-labs/step\n    location: https://test/plugin",
+		"metricProviderPlugins": "\n  - name: argoproj-labs/metric\n    location: https://test/plugin",
+	})
+	client := fake.NewSimpleClientset(cm)
+
+	config.UnInitializeConfig()
+	_, err := config.InitializeConfig(client, defaults.DefaultRolloutsConfigMapName)
+	assert.NoError(t, err)
+	assert.NoError(t, DownloadPlugins(MockFileDownloader{}, client))
+	defer os.RemoveAll(defaults.DefaultRolloutPluginFolder)
+
+	var reloaded []string
+	for _, pluginType := range []types.PluginType{types.PluginTypeStep, types.PluginTypeMetricProvider} {
+		RegisterReloader(pluginType, func(pluginName string) {
+			reloaded = append(reloaded, string(pluginType)+":"+pluginName)
+		})
+	}
+	defer func() {
+		reloaders = map[types.PluginType]func(pluginName string){}
+	}()
+
+	t.Run("unchanged plugins are not reloaded", func(t *testing.T) {
+		reloaded = nil
+		assert.NoError(t, ReloadPlugins(MockFileDownloader{}, client, cm))
+		assert.Empty(t, reloaded)
+	})
+
+	t.Run("changed, added and removed plugins are reloaded", func(t *testing.T) {
+		reloaded = nil
+		cm = newConfigMap(map[string]string{
+			"stepPlugins": "\n  - name: argoproj-labs/step\n    location: https://test/plugin\n    args: [\"--v2\"]\n  - name: argoproj-labs/other-step\n    location: https://test/plugin",
+		})
+		assert.NoError(t, ReloadPlugins(MockFileDownloader{}, client, cm))
+		assert.Equal(t, []string{"Step:argoproj-labs/step", "Step:argoproj-labs/other-step", "MetricProvider:argoproj-labs/metric"}, reloaded)
+
+		_, args, err := GetPluginInfo("argoproj-labs/step", types.PluginTypeStep)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"--v2"}, args)
+		_, _, err = GetPluginInfo("argoproj-labs/metric", types.PluginTypeMetricProvider)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid configmap is ignored", func(t *testing.T) {
+		reloaded = nil
+		err := ReloadPlugins(MockFileDownloader{}, client, newConfigMap(map[string]string{"stepPlugins": "\n  - name: invalid\n    location: https://test/plugin"}))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to reload config")
+		assert.Empty(t, reloaded)
+
+		_, _, err = GetPluginInfo("argoproj-labs/other-step", types.PluginTypeStep)
+		assert.NoError(t, err)
+	})
+
+	t.Run("plugin which cannot be downloaded is not reloaded", func(t *testing.T) {
+		reloaded = nil
+		cm = newConfigMap(map[string]string{
+			"stepPlugins": "\n  - name: argoproj-labs/step\n    location: https://test/plugin/fail\n  - name: argoproj-labs/other-step\n    location: https://test/plugin",
+		})
+		err := ReloadPlugins(MockFileDownloader{}, client, cm)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to download plugin")
+		assert.Empty(t, reloaded)
+
+		// the config is not replaced, although the other plugin was downloaded
+		_, args, err := GetPluginInfo("argoproj-labs/step", types.PluginTypeStep)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"--v2"}, args)
+		entries, err := os.ReadDir(filepath.Join(defaults.DefaultRolloutPluginFolder, "argoproj-labs"))
+		assert.NoError(t, err)
+		var files []string
+		for _, entry := range entries {
+			files = append(files, entry.Name())
+		}
+		assert.ElementsMatch(t, []string{"step", "other-step", "metric"}, files)
+	})
+
+	t.Run("plugins are removed with the configmap", func(t *testing.T) {
+		reloaded = nil
+		assert.NoError(t, ReloadPlugins(MockFileDownloader{}, client, nil))
+		assert.Equal(t, []string{"Step:argoproj-labs/step", "Step:argoproj-labs/other-step"}, reloaded)
+	})
+}
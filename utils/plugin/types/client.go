@@ -0,0 +1,65 @@
+package types
+
+import (
+	"errors"
+	"net/rpc"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IsMethodNotFound returns true if the error of a call is caused by a plugin which does not implement the method, e.g.
+// a plugin built against a previous version of the plugin API
+func IsMethodNotFound(err error) bool {
+	var serverErr rpc.ServerError
+	return errors.As(err, &serverErr) && strings.HasPrefix(string(serverErr), "rpc: can't find method")
+}
+
+// RpcClient is a net/rpc client which tracks its in-flight calls, so that the process of a plugin can be stopped once
+// they have completed
+type RpcClient struct {
+	*rpc.Client
+
+	lock     sync.Mutex
+	inFlight sync.WaitGroup
+	draining bool
+}
+
+// NewRpcClient returns a RpcClient for the net/rpc client of a plugin
+func NewRpcClient(client *rpc.Client) *RpcClient {
+	return &RpcClient{Client: client}
+}
+
+// Call invokes the method of the plugin and waits for it to complete. The calls made while the client is draining are
+// not waited for.
+func (c *RpcClient) Call(serviceMethod string, args any, reply any) error {
+	c.lock.Lock()
+	tracked := !c.draining
+	if tracked {
+		c.inFlight.Add(1)
+	}
+	c.lock.Unlock()
+	if tracked {
+		defer c.inFlight.Done()
+	}
+	return c.Client.Call(serviceMethod, args, reply)
+}
+
+// Drain waits for the in-flight calls to complete, and returns false if they did not complete within the timeout
+func (c *RpcClient) Drain(timeout time.Duration) bool {
+	c.lock.Lock()
+	c.draining = true
+	c.lock.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		c.inFlight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
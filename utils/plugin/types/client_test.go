@@ -0,0 +1,57 @@
+package types
+
+import (
+	"net"
+	"net/rpc"
+	"testing"
+	"time"
+
+	"github.com/tj/assert"
+)
+
+type blockingServer struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingServer) Block(_ int, resp *int) error {
+	s.started <- struct{}{}
+	<-s.release
+	*resp = 1
+	return nil
+}
+
+func TestRpcClientDrain(t *testing.T) {
+	impl := &blockingServer{started: make(chan struct{}), release: make(chan struct{})}
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName("Plugin", impl))
+	serverConn, clientConn := net.Pipe()
+	go server.ServeConn(serverConn)
+	client := NewRpcClient(rpc.NewClient(clientConn))
+	defer client.Close()
+
+	t.Run("no in-flight calls", func(t *testing.T) {
+		assert.True(t, client.Drain(time.Second))
+		client.draining = false
+	})
+
+	t.Run("in-flight call", func(t *testing.T) {
+		done := make(chan int)
+		go func() {
+			var resp int
+			assert.NoError(t, client.Call("Plugin.Block", 0, &resp))
+			done <- resp
+		}()
+		<-impl.started
+
+		assert.False(t, client.Drain(10*time.Millisecond))
+
+		drained := make(chan bool)
+		go func() {
+			drained <- client.Drain(time.Minute)
+		}()
+		close(impl.release)
+		assert.Equal(t, 1, <-done)
+		assert.True(t, <-drained)
+	})
+}
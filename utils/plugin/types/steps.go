@@ -20,6 +20,9 @@ const (
 	PhaseError StepPhase = "Error"
 )
 
+// StepPluginAPIVersion is the latest version of the step plugin API. The plugins of the v2 API implement RpcStepInfo
+const StepPluginAPIVersion = 2
+
 // RpcStepContext is the context of the step plugin operation
 type RpcStepContext struct {
 	// PluginName is the name of the plugin as defined by the user
@@ -28,6 +31,8 @@ type RpcStepContext struct {
 	Config json.RawMessage
 	// Status holds a previous execution status related to the operation
 	Status json.RawMessage
+	// StateVersion is the StateVersion of the plugin which returned the Status, or 0 if the plugin did not set one
+	StateVersion int32
 }
 
 type RpcStepResult struct {
@@ -39,6 +44,31 @@ type RpcStepResult struct {
 	RequeueAfter time.Duration
 	// Status hold the execution status of this plugin step. It can be used to persist a state between executions
 	Status json.RawMessage
+	// Progress is the progress of the operation, displayed with the rollout
+	Progress *RpcStepProgress
+}
+
+// RpcStepPluginInfo describes a plugin of the v2 step plugin API
+type RpcStepPluginInfo struct {
+	// APIVersion is the version of the step plugin API implemented by the plugin
+	APIVersion int32
+	// ConfigSchema is the OpenAPI v3 schema of the Config of the plugin. The config of the steps is validated with the
+	// schema before the plugin is called, and the steps with an invalid config fail
+	ConfigSchema json.RawMessage
+	// StateVersion is the version of the Status returned by the plugin, which is passed back with the Status. A plugin
+	// increments it when it changes the format of its Status, so that it can migrate the Status of the operations
+	// started by a previous version of the plugin
+	StateVersion int32
+	// Progress indicates that the plugin implements RpcStepProgressReporter
+	Progress bool
+}
+
+// RpcStepProgress is the progress of a running operation of a step plugin
+type RpcStepProgress struct {
+	// Percent is the completion of the operation, from 0 to 100
+	Percent int32
+	// Stage describes the current stage of the operation, e.g. migrating table orders (3/10)
+	Stage string
 }
 
 // Validate the phase of a step plugin
@@ -85,6 +85,13 @@ type RpcTrafficRoutingReconciler interface {
 	Type() string
 }
 
+// RpcTrafficRouterHealthChecker is implemented by the traffic router plugins which report their health. The plugins
+// which do not implement it are considered healthy
+type RpcTrafficRouterHealthChecker interface {
+	// Healthz returns an error if the traffic router is not able to route the traffic of the rollout
+	Healthz(rollout *v1alpha1.Rollout) RpcError
+}
+
 type RpcStep interface {
 	// Run executes a step plugin for the RpcStepContext and returns the result to the controller or an RpcError for unexpeted failures
 	Run(*v1alpha1.Rollout, *RpcStepContext) (RpcStepResult, RpcError)
@@ -96,6 +103,19 @@ type RpcStep interface {
 	Type() string
 }
 
+// RpcStepInfo is implemented by the step plugins of the v2 API. The plugins which do not implement it are of the v1 API
+type RpcStepInfo interface {
+	// Info describes the plugin
+	Info() (RpcStepPluginInfo, RpcError)
+}
+
+// RpcStepProgressReporter is implemented by the step plugins of the v2 API which report the progress of a running Run
+// operation between its executions
+type RpcStepProgressReporter interface {
+	// Progress returns the progress of the Run operation for the RpcStepContext
+	Progress(*v1alpha1.Rollout, *RpcStepContext) (RpcStepProgress, RpcError)
+}
+
 type TrafficRouterPlugins struct {
 	// TrafficRouters is the list of plugin that implements a RpcTrafficRoutingReconciler
 	TrafficRouters []PluginItem `json:"trafficRouterPlugins" yaml:"trafficRouterPlugins"`
@@ -126,7 +146,8 @@ const (
 type PluginItem struct {
 	// Name of the plugin to use in the Rollout custom resources
 	Name string `json:"name" yaml:"name"`
-	// Location of the plugin. Supports http(s):// urls and file:// prefix
+	// Location of the plugin. Supports http(s):// urls, file:// prefix and oci:// references of OCI artifacts, e.g.
+	// oci://ghcr.io/argoproj-labs/sample-plugin@sha256:<digest>
 	Location string `json:"location" yaml:"location"`
 	// Sha256 is the checksum of the file specified at the provided Location
 	Sha256 string `json:"sha256" yaml:"sha256"`
@@ -138,6 +159,27 @@ type PluginItem struct {
 	Args []string `json:"args" yaml:"args"`
 	// HeadersFrom holds the names of secrets where the headers should be pulled from
 	HeadersFrom []HeadersFrom `json:"headersFrom" yaml:"headersFrom"`
+	// PullSecrets holds the names of the docker config secrets with the credentials of the registry of an oci:// location
+	PullSecrets []SecretRef `json:"pullSecrets" yaml:"pullSecrets"`
+	// Signature is the base64 encoded signature of the plugin executable, e.g. the output of cosign sign-blob, which
+	// is verified with the PublicKey
+	Signature string `json:"signature" yaml:"signature"`
+	// PublicKey is the PEM encoded public key which verifies the Signature
+	PublicKey string `json:"publicKey" yaml:"publicKey"`
+	// ResourceLimits caps the resources of the plugin process
+	ResourceLimits *PluginResourceLimits `json:"resourceLimits,omitempty" yaml:"resourceLimits,omitempty"`
+	// Env are the names of the environment variables of the controller which are passed to the plugin. The plugin
+	// inherits the whole environment of the controller when it is unset, and no variables when it is empty.
+	Env []string `json:"env,omitempty" yaml:"env,omitempty"`
+}
+
+// PluginResourceLimits caps the resources of a plugin process. The limits do not isolate the plugin from the
+// controller, which it runs as.
+type PluginResourceLimits struct {
+	// MemoryLimit caps the virtual memory of the plugin process, e.g. 1Gi
+	MemoryLimit string `json:"memoryLimit" yaml:"memoryLimit"`
+	// MaxOpenFiles caps the file descriptors of the plugin process
+	MaxOpenFiles uint64 `json:"maxOpenFiles" yaml:"maxOpenFiles"`
 }
 
 type HeadersFrom struct {
@@ -0,0 +1,204 @@
+package policy
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+const (
+	// ConfigMapName is the name of the ConfigMap in the namespace of the controller which holds the progression
+	// policies
+	ConfigMapName = "argo-rollouts-policies"
+	// ConfigMapKey is the key of the ConfigMap which holds the list of policies
+	ConfigMapKey = "policies"
+	// costLimit limits the cost of evaluating a policy, so that an expensive policy, e.g. one which iterates over the
+	// rollouts of a large namespace in nested loops, cannot block the workers of the controller
+	costLimit = 1000000
+)
+
+// Policy is a CEL expression which must evaluate to true for a rollout to progress to its next step
+type Policy struct {
+	// Name identifies the policy in the messages of denied progressions
+	Name string `json:"name"`
+	// Expression is the CEL expression of the policy
+	Expression string `json:"expression"`
+	// Message is the message of a denied progression. Defaults to a message naming the policy.
+	Message string `json:"message,omitempty"`
+}
+
+// Input is the context a policy is evaluated with
+type Input struct {
+	// Rollout is the rollout which progresses
+	Rollout *v1alpha1.Rollout
+	// Rollouts are the rollouts of the namespace of the rollout, including the rollout itself
+	Rollouts []*v1alpha1.Rollout
+	// FromStep is the index of the completed step
+	FromStep int32
+	// ToStep is the index of the step the rollout progresses to. It equals the number of steps when the rollout
+	// progresses to its full promotion.
+	ToStep int32
+	// UpdateStartedAt is the time the update of the rollout started, i.e. the time its new ReplicaSet was created
+	UpdateStartedAt time.Time
+	// Now is the time of the evaluation
+	Now time.Time
+}
+
+type compiledPolicy struct {
+	Policy
+	program cel.Program
+	// err is the error compiling the policy. An invalid policy denies all progressions.
+	err error
+}
+
+var (
+	lock     sync.RWMutex
+	policies []compiledPolicy
+)
+
+// newEnv returns the CEL environment of the policies
+func newEnv() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("rollout", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("rollouts", cel.ListType(cel.MapType(cel.StringType, cel.DynType))),
+		cel.Variable("step", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("updateStartedAt", cel.TimestampType),
+		cel.Variable("now", cel.TimestampType),
+		ext.Strings(),
+	)
+}
+
+// compile compiles the expression of a policy, which must evaluate to a bool
+func compile(env *cel.Env, policy Policy) (cel.Program, error) {
+	ast, issues := env.Compile(policy.Expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("expression must evaluate to a bool, not %s", ast.OutputType())
+	}
+	return env.Program(ast, cel.CostLimit(costLimit))
+}
+
+// SetPolicies replaces the policies which are evaluated before rollouts progress
+func SetPolicies(newPolicies []Policy) {
+	env, err := newEnv()
+	compiled := make([]compiledPolicy, 0, len(newPolicies))
+	for _, policy := range newPolicies {
+		c := compiledPolicy{Policy: policy, err: err}
+		if c.err == nil {
+			c.program, c.err = compile(env, policy)
+		}
+		if c.err != nil {
+			log.Warnf("Invalid progression policy %s: %v", policy.Name, c.err)
+		}
+		compiled = append(compiled, c)
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	policies = compiled
+}
+
+// SetPoliciesFromConfigMap replaces the policies with the ones of the policy ConfigMap. All policies are removed when
+// the ConfigMap is nil. The policies are kept when the ConfigMap cannot be parsed.
+func SetPoliciesFromConfigMap(cm *corev1.ConfigMap) {
+	var newPolicies []Policy
+	if cm != nil {
+		if err := yaml.Unmarshal([]byte(cm.Data[ConfigMapKey]), &newPolicies); err != nil {
+			log.Warnf("Invalid progression policies in ConfigMap %s/%s: %v", cm.Namespace, cm.Name, err)
+			return
+		}
+	}
+	log.Infof("Loaded %d progression policies", len(newPolicies))
+	SetPolicies(newPolicies)
+}
+
+// Enabled returns whether any policies are configured
+func Enabled() bool {
+	lock.RLock()
+	defer lock.RUnlock()
+	return len(policies) > 0
+}
+
+// Evaluate evaluates the policies with the input, and returns the message of the first policy which denies the
+// progression, or an empty string if all policies allow it. A policy which is invalid or fails to evaluate denies
+// the progression.
+func Evaluate(input Input) string {
+	lock.RLock()
+	current := policies
+	lock.RUnlock()
+	if len(current) == 0 {
+		return ""
+	}
+	vars, err := newVars(input)
+	if err != nil {
+		return fmt.Sprintf("failed to evaluate progression policies: %v", err)
+	}
+	for _, policy := range current {
+		if policy.err != nil {
+			return fmt.Sprintf("progression policy %s is invalid: %v", policy.Name, policy.err)
+		}
+		out, _, err := policy.program.Eval(vars)
+		if err != nil {
+			return fmt.Sprintf("progression policy %s failed to evaluate: %v", policy.Name, err)
+		}
+		allowed, ok := out.Value().(bool)
+		if !ok {
+			return fmt.Sprintf("progression policy %s did not evaluate to a bool", policy.Name)
+		}
+		if !allowed {
+			if policy.Message != "" {
+				return policy.Message
+			}
+			return fmt.Sprintf("denied by progression policy %s", policy.Name)
+		}
+	}
+	return ""
+}
+
+// newVars returns the variables of the policies for the input
+func newVars(input Input) (map[string]any, error) {
+	ro, err := runtime.DefaultUnstructuredConverter.ToUnstructured(input.Rollout)
+	if err != nil {
+		return nil, err
+	}
+	rollouts := make([]any, 0, len(input.Rollouts))
+	for _, other := range input.Rollouts {
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(other)
+		if err != nil {
+			return nil, err
+		}
+		rollouts = append(rollouts, obj)
+	}
+	step := map[string]any{
+		"from":  int64(input.FromStep),
+		"to":    int64(input.ToStep),
+		"count": int64(0),
+	}
+	if canary := input.Rollout.Spec.Strategy.Canary; canary != nil {
+		step["count"] = int64(len(canary.Steps))
+		if int(input.FromStep) < len(canary.Steps) {
+			completed, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&canary.Steps[input.FromStep])
+			if err != nil {
+				return nil, err
+			}
+			step["completed"] = completed
+		}
+	}
+	return map[string]any{
+		"rollout":         ro,
+		"rollouts":        rollouts,
+		"step":            step,
+		"updateStartedAt": input.UpdateStartedAt,
+		"now":             input.Now,
+	}, nil
+}
@@ -0,0 +1,112 @@
+package policy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+func newInput(now time.Time) Input {
+	ro := &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: "prod", Labels: map[string]string{"tier": "frontend"}},
+		Spec: v1alpha1.RolloutSpec{
+			Strategy: v1alpha1.RolloutStrategy{
+				Canary: &v1alpha1.CanaryStrategy{
+					Steps: []v1alpha1.CanaryStep{{SetWeight: ptr.To[int32](20)}, {Pause: &v1alpha1.RolloutPause{}}, {SetWeight: ptr.To[int32](50)}},
+				},
+			},
+		},
+	}
+	return Input{
+		Rollout:         ro,
+		Rollouts:        []*v1alpha1.Rollout{ro},
+		FromStep:        1,
+		ToStep:          2,
+		UpdateStartedAt: now.Add(-10 * time.Minute),
+		Now:             now,
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	t.Cleanup(func() { SetPolicies(nil) })
+	friday := time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "", Evaluate(newInput(friday)))
+
+	SetPolicies([]Policy{
+		{Name: "no-friday-promotions", Expression: `now.getDayOfWeek("Europe/Berlin") != 5`, Message: "No promotions on Fridays"},
+		{Name: "canary-soak", Expression: `step.to < step.count || now - updateStartedAt >= duration("30m")`},
+	})
+	assert.True(t, Enabled())
+	assert.Equal(t, "No promotions on Fridays", Evaluate(newInput(friday)))
+
+	monday := friday.Add(72 * time.Hour)
+	assert.Equal(t, "", Evaluate(newInput(monday)))
+	input := newInput(monday)
+	input.FromStep, input.ToStep = 2, 3
+	assert.Equal(t, "denied by progression policy canary-soak", Evaluate(input))
+	input.UpdateStartedAt = monday.Add(-time.Hour)
+	assert.Equal(t, "", Evaluate(input))
+}
+
+func TestEvaluateRolloutContext(t *testing.T) {
+	t.Cleanup(func() { SetPolicies(nil) })
+	now := time.Now()
+
+	SetPolicies([]Policy{{
+		Name:       "frontend-pause",
+		Expression: `rollout.metadata.labels.tier != "frontend" || has(step.completed.pause) || rollouts.size() > 1`,
+	}})
+	input := newInput(now)
+	assert.Equal(t, "", Evaluate(input))
+	input.FromStep, input.ToStep = 0, 1
+	assert.Equal(t, "denied by progression policy frontend-pause", Evaluate(input))
+	input.Rollouts = append(input.Rollouts, &v1alpha1.Rollout{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "prod"}})
+	assert.Equal(t, "", Evaluate(input))
+}
+
+func TestEvaluateInvalidPolicy(t *testing.T) {
+	t.Cleanup(func() { SetPolicies(nil) })
+	now := time.Now()
+
+	// an invalid policy denies all progressions
+	SetPolicies([]Policy{{Name: "typo", Expression: `now.getDayOfWeek( != 5`}})
+	assert.Contains(t, Evaluate(newInput(now)), "progression policy typo is invalid")
+	SetPolicies([]Policy{{Name: "not-bool", Expression: `"yes"`}})
+	assert.Contains(t, Evaluate(newInput(now)), "progression policy not-bool is invalid: expression must evaluate to a bool")
+	SetPolicies([]Policy{{Name: "missing", Expression: `rollout.metadata.labels.team == "web"`}})
+	assert.Contains(t, Evaluate(newInput(now)), "progression policy missing failed to evaluate")
+
+	// a policy which exceeds the cost limit fails to evaluate
+	SetPolicies([]Policy{{Name: "expensive", Expression: `rollouts.all(a, rollouts.all(b, rollouts.all(c, a != c || b == c)))`}})
+	input := newInput(now)
+	for i := 0; i < 200; i++ {
+		input.Rollouts = append(input.Rollouts, input.Rollout)
+	}
+	assert.Contains(t, Evaluate(input), "progression policy expensive failed to evaluate: operation cancelled: actual cost limit exceeded")
+}
+
+func TestSetPoliciesFromConfigMap(t *testing.T) {
+	t.Cleanup(func() { SetPolicies(nil) })
+
+	cm := &corev1.ConfigMap{Data: map[string]string{ConfigMapKey: `
+- name: never
+  expression: "false"
+  message: Frozen
+`}}
+	SetPoliciesFromConfigMap(cm)
+	assert.Equal(t, "Frozen", Evaluate(newInput(time.Now())))
+
+	// the policies are kept when the ConfigMap cannot be parsed
+	SetPoliciesFromConfigMap(&corev1.ConfigMap{Data: map[string]string{ConfigMapKey: "name: never"}})
+	assert.Equal(t, "Frozen", Evaluate(newInput(time.Now())))
+
+	SetPoliciesFromConfigMap(nil)
+	assert.False(t, Enabled())
+}
@@ -0,0 +1,108 @@
+package queue
+
+import (
+	"sync"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// PriorityQueue is a rate limited queue which processes prioritized items ahead of the other items, so that
+// user initiated actions are not stuck behind periodic resyncs on a busy controller
+type PriorityQueue interface {
+	workqueue.RateLimitingInterface
+	// AddPriority adds the item to the queue ahead of the items which are not prioritized
+	AddPriority(item any)
+}
+
+// NewNamedPriorityRateLimitingQueue returns a rate limited priority queue configured by the rate limiter configuration
+func NewNamedPriorityRateLimitingQueue(cfg RateLimiterConfig, name string) PriorityQueue {
+	storage := newPriorityStorage()
+	q := workqueue.NewRateLimitingQueueWithConfig(NewRateLimiter(cfg), workqueue.RateLimitingQueueConfig{
+		Name: name,
+		DelayingQueue: workqueue.NewDelayingQueueWithConfig(workqueue.DelayingQueueConfig{
+			Name:  name,
+			Queue: workqueue.NewWithConfig(workqueue.QueueConfig{Name: name, Queue: storage}),
+		}),
+	})
+	return &priorityQueue{RateLimitingInterface: withBackoff(q, cfg), storage: storage}
+}
+
+type priorityQueue struct {
+	workqueue.RateLimitingInterface
+	storage *priorityStorage
+}
+
+// AddPriority marks the item as prioritized and adds it to the queue. An item which is already queued is
+// moved ahead of the items which are not prioritized.
+func (q *priorityQueue) AddPriority(item any) {
+	q.storage.prioritize(item)
+	q.Add(item)
+}
+
+// priorityStorage is the underlying storage of the queue, which pops the prioritized items before the other items
+// in FIFO order
+type priorityStorage struct {
+	lock        sync.Mutex
+	prioritized map[any]bool
+	high        []any
+	low         []any
+}
+
+func newPriorityStorage() *priorityStorage {
+	return &priorityStorage{prioritized: map[any]bool{}}
+}
+
+func (s *priorityStorage) prioritize(item any) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.prioritized[item] = true
+}
+
+// Touch moves an item which is already queued to the prioritized items when it was prioritized since
+func (s *priorityStorage) Touch(item any) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if !s.prioritized[item] {
+		return
+	}
+	for i, queued := range s.low {
+		if queued == item {
+			s.low = append(s.low[:i], s.low[i+1:]...)
+			s.high = append(s.high, item)
+			return
+		}
+	}
+}
+
+func (s *priorityStorage) Push(item any) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.prioritized[item] {
+		s.high = append(s.high, item)
+	} else {
+		s.low = append(s.low, item)
+	}
+}
+
+func (s *priorityStorage) Len() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.high) + len(s.low)
+}
+
+func (s *priorityStorage) Pop() any {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var item any
+	if len(s.high) > 0 {
+		item = s.high[0]
+		s.high[0] = nil
+		s.high = s.high[1:]
+	} else {
+		item = s.low[0]
+		s.low[0] = nil
+		s.low = s.low[1:]
+	}
+	delete(s.prioritized, item)
+	return item
+}
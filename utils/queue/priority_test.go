@@ -0,0 +1,54 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	q := NewNamedPriorityRateLimitingQueue(RateLimiterConfig{BaseDelay: time.Millisecond, MaxDelay: time.Second}, "")
+	defer q.ShutDown()
+
+	q.Add("a")
+	q.Add("b")
+	q.Add("c")
+	// prioritizing a queued item moves it ahead of the other items
+	q.AddPriority("c")
+	q.AddPriority("d")
+	assert.Equal(t, 4, q.Len())
+
+	var order []any
+	for q.Len() > 0 {
+		item, _ := q.Get()
+		order = append(order, item)
+		q.Done(item)
+	}
+	assert.Equal(t, []any{"c", "d", "a", "b"}, order)
+
+	// the priority is reset once the item was processed
+	q.Add("a")
+	q.Add("c")
+	item, _ := q.Get()
+	assert.Equal(t, "a", item)
+	q.Done(item)
+	item, _ = q.Get()
+	q.Done(item)
+}
+
+func TestPriorityQueueProcessingItem(t *testing.T) {
+	q := NewNamedPriorityRateLimitingQueue(RateLimiterConfig{BaseDelay: time.Millisecond, MaxDelay: time.Second}, "")
+	defer q.ShutDown()
+
+	q.Add("a")
+	item, _ := q.Get()
+	q.Add("b")
+	// an item which is prioritized while it is processed is requeued with priority
+	q.AddPriority("a")
+	q.Done(item)
+
+	item, _ = q.Get()
+	assert.Equal(t, "a", item)
+	q.Done(item)
+}
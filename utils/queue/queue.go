@@ -1,13 +1,106 @@
 package queue
 
 import (
+	"math"
 	"time"
 
+	"golang.org/x/time/rate"
 	"k8s.io/client-go/util/workqueue"
 )
 
+const (
+	// DefaultBaseDelay is the default delay of the first retry of a failing item
+	DefaultBaseDelay = time.Millisecond
+	// DefaultMaxDelay is the default maximum delay between the retries of a failing item
+	DefaultMaxDelay = 10 * time.Second
+	// DefaultBackoffThreshold is the default number of consecutive failures of an item after which all
+	// additions of the item are delayed by its backoff
+	DefaultBackoffThreshold = 5
+)
+
+// RateLimiterConfig configures the rate limiting of the workqueues of the controller
+type RateLimiterConfig struct {
+	// BaseDelay is the delay of the first retry of a failing item, which doubles with every further failure
+	BaseDelay time.Duration
+	// MaxDelay is the maximum delay between the retries of a failing item
+	MaxDelay time.Duration
+	// BucketQPS is the overall rate at which items are added to the queue. The overall rate is not limited when zero.
+	BucketQPS float64
+	// BucketSize is the burst of the overall rate limit
+	BucketSize int
+	// BackoffThreshold is the number of consecutive failures of an item after which the additions of the item due to
+	// events are delayed by its backoff as well, so that a persistently failing item can't starve the queue.
+	// Disabled when zero.
+	BackoffThreshold int
+}
+
+// DefaultRateLimiterConfig returns the default rate limiter configuration
+func DefaultRateLimiterConfig() RateLimiterConfig {
+	return RateLimiterConfig{
+		BaseDelay:        DefaultBaseDelay,
+		MaxDelay:         DefaultMaxDelay,
+		BackoffThreshold: DefaultBackoffThreshold,
+	}
+}
+
 // DefaultArgoRolloutsRateLimiter is the default queue rate limiter.
 // Similar to workqueue.DefaultControllerRateLimiter() but the max limit is 10 seconds instead of 16 minutes
 func DefaultArgoRolloutsRateLimiter() workqueue.RateLimiter {
-	return workqueue.NewItemExponentialFailureRateLimiter(time.Millisecond, 10*time.Second)
+	return workqueue.NewItemExponentialFailureRateLimiter(DefaultBaseDelay, DefaultMaxDelay)
+}
+
+// NewRateLimiter returns a rate limiter which retries failing items with an exponential backoff, and limits the
+// overall rate of the queue when a bucket is configured
+func NewRateLimiter(cfg RateLimiterConfig) workqueue.RateLimiter {
+	itemRateLimiter := workqueue.NewItemExponentialFailureRateLimiter(cfg.BaseDelay, cfg.MaxDelay)
+	if cfg.BucketQPS <= 0 {
+		return itemRateLimiter
+	}
+	bucketSize := cfg.BucketSize
+	if bucketSize <= 0 {
+		bucketSize = int(math.Ceil(cfg.BucketQPS))
+	}
+	return workqueue.NewMaxOfRateLimiter(
+		itemRateLimiter,
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(cfg.BucketQPS), bucketSize)},
+	)
+}
+
+// NewNamedRateLimitingQueue returns a rate limited queue configured by the rate limiter configuration
+func NewNamedRateLimitingQueue(cfg RateLimiterConfig, name string) workqueue.RateLimitingInterface {
+	return withBackoff(workqueue.NewNamedRateLimitingQueue(NewRateLimiter(cfg), name), cfg)
+}
+
+// withBackoff wraps the queue to back off persistently failing items, if configured
+func withBackoff(q workqueue.RateLimitingInterface, cfg RateLimiterConfig) workqueue.RateLimitingInterface {
+	if cfg.BackoffThreshold <= 0 {
+		return q
+	}
+	return &backoffQueue{RateLimitingInterface: q, cfg: cfg}
+}
+
+// backoffQueue delays the additions of items which failed persistently by their current backoff. Otherwise,
+// the events of a persistently failing item would bypass its backoff, and the item could starve the queue.
+type backoffQueue struct {
+	workqueue.RateLimitingInterface
+	cfg RateLimiterConfig
+}
+
+// Add adds the item to the queue, after its backoff when it failed persistently
+func (q *backoffQueue) Add(item any) {
+	failures := q.NumRequeues(item)
+	if failures < q.cfg.BackoffThreshold {
+		q.RateLimitingInterface.Add(item)
+		return
+	}
+	q.AddAfter(item, q.backoff(failures))
+}
+
+// backoff returns the delay of the last retry of an item with the number of failures
+func (q *backoffQueue) backoff(failures int) time.Duration {
+	backoff := float64(q.cfg.BaseDelay.Nanoseconds()) * math.Pow(2, float64(failures-1))
+	if backoff > math.MaxInt64 || time.Duration(backoff) > q.cfg.MaxDelay {
+		return q.cfg.MaxDelay
+	}
+	return time.Duration(backoff)
 }
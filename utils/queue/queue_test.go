@@ -0,0 +1,74 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRateLimiter(t *testing.T) {
+	rateLimiter := NewRateLimiter(RateLimiterConfig{BaseDelay: time.Second, MaxDelay: 3 * time.Second})
+	assert.Equal(t, time.Second, rateLimiter.When("foo"))
+	assert.Equal(t, 2*time.Second, rateLimiter.When("foo"))
+	assert.Equal(t, 3*time.Second, rateLimiter.When("foo"))
+	assert.Equal(t, time.Second, rateLimiter.When("bar"))
+	assert.Equal(t, 3, rateLimiter.NumRequeues("foo"))
+	rateLimiter.Forget("foo")
+	assert.Equal(t, 0, rateLimiter.NumRequeues("foo"))
+
+	rateLimiter = NewRateLimiter(RateLimiterConfig{BaseDelay: time.Millisecond, MaxDelay: time.Second, BucketQPS: 1, BucketSize: 1})
+	assert.Equal(t, time.Millisecond, rateLimiter.When("foo"))
+	assert.Greater(t, rateLimiter.When("bar"), 500*time.Millisecond)
+}
+
+func TestNewNamedRateLimitingQueue(t *testing.T) {
+	q := NewNamedRateLimitingQueue(RateLimiterConfig{BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}, "test")
+	defer q.ShutDown()
+	_, ok := q.(*backoffQueue)
+	assert.False(t, ok)
+
+	q = NewNamedRateLimitingQueue(DefaultRateLimiterConfig(), "test")
+	defer q.ShutDown()
+	_, ok = q.(*backoffQueue)
+	assert.True(t, ok)
+}
+
+func TestBackoffQueue(t *testing.T) {
+	cfg := RateLimiterConfig{BaseDelay: time.Hour, MaxDelay: time.Hour, BackoffThreshold: 2}
+	q := NewNamedRateLimitingQueue(cfg, "test")
+	defer q.ShutDown()
+
+	q.Add("foo")
+	assert.Equal(t, 1, q.Len())
+	item, _ := q.Get()
+	q.Done(item)
+
+	// a single failure does not delay the additions of the item
+	q.AddRateLimited("foo")
+	q.Add("foo")
+	assert.Equal(t, 1, q.Len())
+	item, _ = q.Get()
+	q.Done(item)
+
+	// persistent failures delay the additions of the item by its backoff
+	q.AddRateLimited("foo")
+	q.Add("foo")
+	assert.Equal(t, 0, q.Len())
+
+	// other items are not delayed
+	q.Add("bar")
+	assert.Equal(t, 1, q.Len())
+
+	q.Forget("foo")
+	q.Add("foo")
+	assert.Equal(t, 2, q.Len())
+}
+
+func TestBackoff(t *testing.T) {
+	q := &backoffQueue{cfg: RateLimiterConfig{BaseDelay: time.Second, MaxDelay: time.Minute}}
+	assert.Equal(t, time.Second, q.backoff(1))
+	assert.Equal(t, 8*time.Second, q.backoff(4))
+	assert.Equal(t, time.Minute, q.backoff(10))
+	assert.Equal(t, time.Minute, q.backoff(1000))
+}
@@ -0,0 +1,124 @@
+package record
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/argoproj/notifications-engine/pkg/services"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultNotificationQueueSize is the number of events whose notifications wait to be sent before the
+	// notifications of new events are dropped
+	defaultNotificationQueueSize = 1000
+	// defaultNotificationWorkers is the number of events whose notifications are sent concurrently
+	defaultNotificationWorkers = 4
+)
+
+// NotificationDelivery is the result of the delivery of a notification
+type NotificationDelivery struct {
+	// Time is when the delivery was attempted
+	Time time.Time
+	// Destination is the destination of the notification
+	Destination services.Destination
+	// Error is the error of a failed delivery
+	Error error
+}
+
+// notificationDeliveries holds the last notification delivery to each destination of each object
+type notificationDeliveries struct {
+	lock sync.Mutex
+	// last holds the last delivery to each destination, keyed by the kind, namespace and name of the object
+	last map[string]map[services.Destination]NotificationDelivery
+}
+
+func newNotificationDeliveries() *notificationDeliveries {
+	return &notificationDeliveries{
+		last: map[string]map[services.Destination]NotificationDelivery{},
+	}
+}
+
+func notificationDeliveryKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+// Record records the last delivery of a notification about an object to its destination. A nil
+// notificationDeliveries records nothing.
+func (d *notificationDeliveries) Record(key string, delivery NotificationDelivery) {
+	if d == nil {
+		return
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.last[key] == nil {
+		d.last[key] = map[services.Destination]NotificationDelivery{}
+	}
+	d.last[key][delivery.Destination] = delivery
+}
+
+// Last returns the last delivery of a notification about an object to each destination, ordered by destination
+func (d *notificationDeliveries) Last(key string) []NotificationDelivery {
+	if d == nil {
+		return nil
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	var deliveries []NotificationDelivery
+	for _, delivery := range d.last[key] {
+		deliveries = append(deliveries, delivery)
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		if deliveries[i].Destination.Service != deliveries[j].Destination.Service {
+			return deliveries[i].Destination.Service < deliveries[j].Destination.Service
+		}
+		return deliveries[i].Destination.Recipient < deliveries[j].Destination.Recipient
+	})
+	return deliveries
+}
+
+// Forget removes the deliveries of a deleted object
+func (d *notificationDeliveries) Forget(key string) {
+	if d == nil {
+		return
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	delete(d.last, key)
+}
+
+// notificationQueue sends the notifications of the events in the background, so that slow deliveries, and the
+// retries of the signed webhooks, do not block the workers of the controllers
+type notificationQueue struct {
+	sends chan func()
+}
+
+// newNotificationQueue returns a queue of the given size, whose notifications are sent by the given number of workers
+func newNotificationQueue(size, workers int) *notificationQueue {
+	q := &notificationQueue{sends: make(chan func(), size)}
+	for range workers {
+		go func() {
+			for send := range q.sends {
+				send()
+			}
+		}()
+	}
+	return q
+}
+
+// Enqueue queues the sending of notifications, or sends them right away when the queue is nil. It returns false when
+// the queue is full, in which case the notifications are dropped.
+func (q *notificationQueue) Enqueue(send func()) bool {
+	if q == nil {
+		send()
+		return true
+	}
+	select {
+	case q.sends <- send:
+		return true
+	default:
+		log.Errorf("Notification queue is full, dropping the notifications of an event")
+		return false
+	}
+}
@@ -0,0 +1,301 @@
+package record
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/argoproj/notifications-engine/pkg/api"
+	"github.com/argoproj/notifications-engine/pkg/services"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// PagerDutyEventsServiceType is the type of the notification services which open and resolve PagerDuty incidents
+	// with the Events API v2, e.g. service.pagerdutyevents in the notification ConfigMap
+	PagerDutyEventsServiceType = "pagerdutyevents"
+	// OpsgenieAlertsServiceType is the type of the notification services which open and close Opsgenie alerts, e.g.
+	// service.opsgeniealerts in the notification ConfigMap
+	OpsgenieAlertsServiceType = "opsgeniealerts"
+
+	defaultPagerDutyEventsURL = "https://events.pagerduty.com"
+	defaultOpsgenieURL        = "https://api.opsgenie.com"
+	// opsgenieMessageMaxLength is the maximum length of the message of an Opsgenie alert
+	opsgenieMessageMaxLength = 130
+)
+
+// IncidentAction is the action of an incident notification
+type IncidentAction string
+
+const (
+	// IncidentActionOpen opens an incident, or updates the open incident with the same key
+	IncidentActionOpen IncidentAction = "open"
+	// IncidentActionResolve resolves the open incident with the same key
+	IncidentActionResolve IncidentAction = "resolve"
+)
+
+// Incident is the payload of an incident notification, which is the YAML body of the webhook template named after the
+// incident service
+type Incident struct {
+	// Action opens or resolves the incident. Defaults to open
+	Action IncidentAction `json:"action,omitempty"`
+	// Key identifies the incident, so that it is resolved by the notification with the same key, e.g. the namespace
+	// and name of the rollout
+	Key string `json:"key"`
+	// Summary is the title of the incident
+	Summary string `json:"summary,omitempty"`
+	// Severity is one of critical, error, warning or info. Defaults to error
+	Severity string `json:"severity,omitempty"`
+	// Source is the component the incident is about
+	Source string `json:"source,omitempty"`
+	// URL links the incident to a page about the rollout, such as its dashboard
+	URL string `json:"url,omitempty"`
+	// Details are added to the incident, such as the images of the rollout
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// PagerDutyEventsOptions are the options of a PagerDuty Events API v2 notification service
+type PagerDutyEventsOptions struct {
+	// RoutingKeys are the integration keys of the PagerDuty services by recipient
+	RoutingKeys map[string]string `json:"routingKeys"`
+	// APIURL is the URL of the Events API. Defaults to https://events.pagerduty.com
+	APIURL string `json:"apiUrl,omitempty"`
+}
+
+// OpsgenieAlertsOptions are the options of an Opsgenie alerts notification service
+type OpsgenieAlertsOptions struct {
+	// APIKey is the key of an Opsgenie API integration
+	APIKey string `json:"apiKey"`
+	// APIURL is the URL of the Opsgenie API. Defaults to https://api.opsgenie.com
+	APIURL string `json:"apiUrl,omitempty"`
+}
+
+type pagerDutyEventsService struct {
+	opts   PagerDutyEventsOptions
+	client *http.Client
+}
+
+type opsgenieAlertsService struct {
+	opts   OpsgenieAlertsOptions
+	client *http.Client
+}
+
+// NewPagerDutyEventsService returns a notification service which opens and resolves PagerDuty incidents with the Events
+// API v2. The recipient of a notification is the name of a routing key.
+func NewPagerDutyEventsService(opts PagerDutyEventsOptions) (services.NotificationService, error) {
+	if len(opts.RoutingKeys) == 0 {
+		return nil, fmt.Errorf("routingKeys are required")
+	}
+	if opts.APIURL == "" {
+		opts.APIURL = defaultPagerDutyEventsURL
+	}
+	return &pagerDutyEventsService{opts: opts, client: &http.Client{Timeout: webhookTimeout}}, nil
+}
+
+// NewOpsgenieAlertsService returns a notification service which opens and closes Opsgenie alerts. The recipient of a
+// notification, if any, is the name of the team the alert is routed to.
+func NewOpsgenieAlertsService(opts OpsgenieAlertsOptions) (services.NotificationService, error) {
+	if opts.APIKey == "" {
+		return nil, fmt.Errorf("apiKey is required")
+	}
+	if opts.APIURL == "" {
+		opts.APIURL = defaultOpsgenieURL
+	}
+	return &opsgenieAlertsService{opts: opts, client: &http.Client{Timeout: webhookTimeout}}, nil
+}
+
+// incidentOf returns the incident of a notification to an incident service
+func incidentOf(notification services.Notification, dest services.Destination) (*Incident, error) {
+	webhook, ok := notification.Webhook[dest.Service]
+	if !ok {
+		return nil, fmt.Errorf("notification has no webhook template for service %s", dest.Service)
+	}
+	var incident Incident
+	if err := yaml.Unmarshal([]byte(webhook.Body), &incident); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal incident: %w", err)
+	}
+	if incident.Key == "" {
+		return nil, fmt.Errorf("incident key is required")
+	}
+	switch incident.Action {
+	case "":
+		incident.Action = IncidentActionOpen
+	case IncidentActionOpen, IncidentActionResolve:
+	default:
+		return nil, fmt.Errorf("invalid incident action '%s'", incident.Action)
+	}
+	if incident.Summary == "" {
+		incident.Summary = notification.Message
+	}
+	if incident.Severity == "" {
+		incident.Severity = "error"
+	}
+	return &incident, nil
+}
+
+func (s *pagerDutyEventsService) Send(notification services.Notification, dest services.Destination) error {
+	routingKey, ok := s.opts.RoutingKeys[dest.Recipient]
+	if !ok {
+		return fmt.Errorf("no routing key for recipient %s", dest.Recipient)
+	}
+	incident, err := incidentOf(notification, dest)
+	if err != nil {
+		return err
+	}
+	event := map[string]any{
+		"routing_key": routingKey,
+		"dedup_key":   incident.Key,
+	}
+	if incident.Action == IncidentActionResolve {
+		event["event_action"] = "resolve"
+	} else {
+		event["event_action"] = "trigger"
+		payload := map[string]any{
+			"summary":  incident.Summary,
+			"severity": incident.Severity,
+			"source":   incident.Source,
+		}
+		if len(incident.Details) > 0 {
+			payload["custom_details"] = incident.Details
+		}
+		event["payload"] = payload
+		if incident.URL != "" {
+			event["links"] = []map[string]string{{"href": incident.URL, "text": "Rollout"}}
+		}
+	}
+	return postJSON(s.client, strings.TrimRight(s.opts.APIURL, "/")+"/v2/enqueue", nil, event)
+}
+
+func (s *opsgenieAlertsService) Send(notification services.Notification, dest services.Destination) error {
+	incident, err := incidentOf(notification, dest)
+	if err != nil {
+		return err
+	}
+	headers := map[string]string{"Authorization": "GenieKey " + s.opts.APIKey}
+	alertsURL := strings.TrimRight(s.opts.APIURL, "/") + "/v2/alerts"
+	if incident.Action == IncidentActionResolve {
+		closeURL := fmt.Sprintf("%s/%s/close?identifierType=alias", alertsURL, url.PathEscape(incident.Key))
+		return postJSON(s.client, closeURL, headers, map[string]any{"source": incident.Source, "note": incident.Summary})
+	}
+	message := incident.Summary
+	if len(message) > opsgenieMessageMaxLength {
+		message = message[:opsgenieMessageMaxLength]
+	}
+	alert := map[string]any{
+		"message":     message,
+		"alias":       incident.Key,
+		"description": incident.Summary,
+		"priority":    opsgeniePriority(incident.Severity),
+		"source":      incident.Source,
+	}
+	if dest.Recipient != "" {
+		alert["responders"] = []map[string]string{{"name": dest.Recipient, "type": "team"}}
+	}
+	details := map[string]string{}
+	for k, v := range incident.Details {
+		details[k] = v
+	}
+	if incident.URL != "" {
+		details["url"] = incident.URL
+	}
+	if len(details) > 0 {
+		alert["details"] = details
+	}
+	return postJSON(s.client, alertsURL, headers, alert)
+}
+
+// opsgeniePriority maps the severity of an incident to the priority of an Opsgenie alert
+func opsgeniePriority(severity string) string {
+	switch severity {
+	case "critical":
+		return "P1"
+	case "error":
+		return "P2"
+	case "warning":
+		return "P3"
+	default:
+		return "P5"
+	}
+}
+
+// postJSON posts a JSON payload, and returns a webhookStatusError if the response has an unsuccessful status code
+func postJSON(client *http.Client, endpoint string, headers map[string]string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &webhookStatusError{url: endpoint, statusCode: resp.StatusCode, body: string(body)}
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// addIncidentServices adds the PagerDuty and Opsgenie incident services of a notification ConfigMap to its
+// configuration, which the notifications engine does not know how to create
+func addIncidentServices(cfg *api.Config, configMap *corev1.ConfigMap, secret *corev1.Secret) error {
+	if cfg == nil || configMap == nil {
+		return nil
+	}
+	if cfg.Services == nil {
+		cfg.Services = map[string]api.ServiceFactory{}
+	}
+	pagerDutyConfigs, err := customServiceConfigs(configMap, PagerDutyEventsServiceType)
+	if err != nil {
+		return err
+	}
+	for name, v := range pagerDutyConfigs {
+		var opts PagerDutyEventsOptions
+		if err := yaml.Unmarshal([]byte(v), &opts); err != nil {
+			return fmt.Errorf("failed to unmarshal service %s: %w", name, err)
+		}
+		for recipient, routingKey := range opts.RoutingKeys {
+			opts.RoutingKeys[recipient] = replaceSecret(routingKey, secret)
+		}
+		svc, err := NewPagerDutyEventsService(opts)
+		if err != nil {
+			return fmt.Errorf("invalid service %s: %w", name, err)
+		}
+		cfg.Services[name] = func() (services.NotificationService, error) {
+			return svc, nil
+		}
+	}
+	opsgenieConfigs, err := customServiceConfigs(configMap, OpsgenieAlertsServiceType)
+	if err != nil {
+		return err
+	}
+	for name, v := range opsgenieConfigs {
+		var opts OpsgenieAlertsOptions
+		if err := yaml.Unmarshal([]byte(v), &opts); err != nil {
+			return fmt.Errorf("failed to unmarshal service %s: %w", name, err)
+		}
+		opts.APIKey = replaceSecret(opts.APIKey, secret)
+		svc, err := NewOpsgenieAlertsService(opts)
+		if err != nil {
+			return fmt.Errorf("invalid service %s: %w", name, err)
+		}
+		cfg.Services[name] = func() (services.NotificationService, error) {
+			return svc, nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package record
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/argoproj/notifications-engine/pkg/api"
+	"github.com/argoproj/notifications-engine/pkg/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	openIncidentBody = `
+action: open
+key: default/guestbook
+summary: Rollout default/guestbook has been aborted.
+severity: critical
+source: default/guestbook
+details:
+  guestbook: argoproj/rollouts-demo:blue
+`
+	resolveIncidentBody = `
+action: resolve
+key: default/guestbook
+summary: Rollout default/guestbook has been completed.
+source: default/guestbook
+`
+)
+
+type capturedRequest struct {
+	path   string
+	query  string
+	header http.Header
+	body   map[string]any
+}
+
+func newIncidentServer(t *testing.T, requests *[]capturedRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		*requests = append(*requests, capturedRequest{path: r.URL.Path, query: r.URL.RawQuery, header: r.Header, body: body})
+		w.WriteHeader(http.StatusAccepted)
+	}))
+}
+
+func TestPagerDutyEventsService(t *testing.T) {
+	var requests []capturedRequest
+	server := newIncidentServer(t, &requests)
+	defer server.Close()
+
+	svc, err := NewPagerDutyEventsService(PagerDutyEventsOptions{RoutingKeys: map[string]string{"payments": "my-routing-key"}, APIURL: server.URL})
+	require.NoError(t, err)
+	dest := services.Destination{Service: "pagerdutyevents", Recipient: "payments"}
+
+	err = svc.Send(services.Notification{Webhook: services.WebhookNotifications{"pagerdutyevents": {Body: openIncidentBody}}}, dest)
+	require.NoError(t, err)
+	err = svc.Send(services.Notification{Webhook: services.WebhookNotifications{"pagerdutyevents": {Body: resolveIncidentBody}}}, dest)
+	require.NoError(t, err)
+
+	require.Len(t, requests, 2)
+	assert.Equal(t, "/v2/enqueue", requests[0].path)
+	assert.Equal(t, map[string]any{
+		"routing_key":  "my-routing-key",
+		"dedup_key":    "default/guestbook",
+		"event_action": "trigger",
+		"payload": map[string]any{
+			"summary":        "Rollout default/guestbook has been aborted.",
+			"severity":       "critical",
+			"source":         "default/guestbook",
+			"custom_details": map[string]any{"guestbook": "argoproj/rollouts-demo:blue"},
+		},
+	}, requests[0].body)
+	assert.Equal(t, map[string]any{
+		"routing_key":  "my-routing-key",
+		"dedup_key":    "default/guestbook",
+		"event_action": "resolve",
+	}, requests[1].body)
+
+	err = svc.Send(services.Notification{Webhook: services.WebhookNotifications{"pagerdutyevents": {Body: openIncidentBody}}}, services.Destination{Service: "pagerdutyevents", Recipient: "checkout"})
+	assert.EqualError(t, err, "no routing key for recipient checkout")
+}
+
+func TestOpsgenieAlertsService(t *testing.T) {
+	var requests []capturedRequest
+	server := newIncidentServer(t, &requests)
+	defer server.Close()
+
+	svc, err := NewOpsgenieAlertsService(OpsgenieAlertsOptions{APIKey: "my-api-key", APIURL: server.URL})
+	require.NoError(t, err)
+	dest := services.Destination{Service: "opsgeniealerts", Recipient: "payments"}
+
+	err = svc.Send(services.Notification{Webhook: services.WebhookNotifications{"opsgeniealerts": {Body: openIncidentBody}}}, dest)
+	require.NoError(t, err)
+	err = svc.Send(services.Notification{Webhook: services.WebhookNotifications{"opsgeniealerts": {Body: resolveIncidentBody}}}, dest)
+	require.NoError(t, err)
+
+	require.Len(t, requests, 2)
+	assert.Equal(t, "/v2/alerts", requests[0].path)
+	assert.Equal(t, "GenieKey my-api-key", requests[0].header.Get("Authorization"))
+	assert.Equal(t, map[string]any{
+		"message":     "Rollout default/guestbook has been aborted.",
+		"alias":       "default/guestbook",
+		"description": "Rollout default/guestbook has been aborted.",
+		"priority":    "P1",
+		"source":      "default/guestbook",
+		"responders":  []any{map[string]any{"name": "payments", "type": "team"}},
+		"details":     map[string]any{"guestbook": "argoproj/rollouts-demo:blue"},
+	}, requests[0].body)
+	assert.Equal(t, "/v2/alerts/default/guestbook/close", requests[1].path)
+	assert.Equal(t, "identifierType=alias", requests[1].query)
+	assert.Equal(t, map[string]any{"source": "default/guestbook", "note": "Rollout default/guestbook has been completed."}, requests[1].body)
+}
+
+func TestIncidentOf(t *testing.T) {
+	dest := services.Destination{Service: "pagerdutyevents"}
+	_, err := incidentOf(services.Notification{}, dest)
+	assert.EqualError(t, err, "notification has no webhook template for service pagerdutyevents")
+	_, err = incidentOf(services.Notification{Webhook: services.WebhookNotifications{"pagerdutyevents": {Body: "summary: aborted"}}}, dest)
+	assert.EqualError(t, err, "incident key is required")
+	_, err = incidentOf(services.Notification{Webhook: services.WebhookNotifications{"pagerdutyevents": {Body: "key: a\naction: snooze"}}}, dest)
+	assert.EqualError(t, err, "invalid incident action 'snooze'")
+
+	incident, err := incidentOf(services.Notification{Message: "Rollout guestbook has been aborted.", Webhook: services.WebhookNotifications{"pagerdutyevents": {Body: "key: default/guestbook"}}}, dest)
+	require.NoError(t, err)
+	assert.Equal(t, &Incident{Action: IncidentActionOpen, Key: "default/guestbook", Summary: "Rollout guestbook has been aborted.", Severity: "error"}, incident)
+}
+
+func TestAddIncidentServices(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		Data: map[string]string{
+			"service.pagerdutyevents": "routingKeys:\n  payments: $pd-payments\n",
+			"service.opsgeniealerts":  "apiKey: $opsgenie-key\n",
+		},
+	}
+	secret := &corev1.Secret{Data: map[string][]byte{"pd-payments": []byte("my-routing-key"), "opsgenie-key": []byte("my-api-key")}}
+	cfg := &api.Config{}
+	require.NoError(t, addIncidentServices(cfg, cm, secret))
+	pagerDuty, err := cfg.Services["pagerdutyevents"]()
+	require.NoError(t, err)
+	assert.Equal(t, PagerDutyEventsOptions{RoutingKeys: map[string]string{"payments": "my-routing-key"}, APIURL: defaultPagerDutyEventsURL}, pagerDuty.(*pagerDutyEventsService).opts)
+	opsgenie, err := cfg.Services["opsgeniealerts"]()
+	require.NoError(t, err)
+	assert.Equal(t, OpsgenieAlertsOptions{APIKey: "my-api-key", APIURL: defaultOpsgenieURL}, opsgenie.(*opsgenieAlertsService).opts)
+
+	cm.Data["service.opsgeniealerts"] = "apiUrl: https://api.eu.opsgenie.com\n"
+	assert.EqualError(t, addIncidentServices(cfg, cm, secret), "invalid service opsgeniealerts: apiKey is required")
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -32,12 +33,19 @@ import (
 	"k8s.io/kubectl/pkg/scheme"
 
 	argoinformers "github.com/argoproj/argo-rollouts/pkg/client/informers/externalversions/rollouts/v1alpha1"
+	rolloutlisters "github.com/argoproj/argo-rollouts/pkg/client/listers/rollouts/v1alpha1"
 	timeutil "github.com/argoproj/argo-rollouts/utils/time"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	rolloutscheme "github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned/scheme"
 	"github.com/argoproj/argo-rollouts/utils/annotations"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+	"github.com/argoproj/argo-rollouts/utils/eventbus"
+	hashutil "github.com/argoproj/argo-rollouts/utils/hash"
 	logutil "github.com/argoproj/argo-rollouts/utils/log"
+	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
+	tlsutil "github.com/argoproj/argo-rollouts/utils/tls"
+	"github.com/argoproj/argo-rollouts/utils/writeback"
 )
 
 func init() {
@@ -66,6 +74,11 @@ type EventRecorder interface {
 	Eventf(object runtime.Object, opts EventOptions, messageFmt string, args ...any)
 	Warnf(object runtime.Object, opts EventOptions, messageFmt string, args ...any)
 	K8sRecorder() record.EventRecorder
+	// LastNotificationDeliveries returns the result of the last delivery of a notification about an object to each
+	// destination, ordered by destination
+	LastNotificationDeliveries(object runtime.Object) []NotificationDelivery
+	// ForgetNotificationDeliveries removes the deliveries of the notifications about a deleted object
+	ForgetNotificationDeliveries(object runtime.Object)
 }
 
 // EventRecorderAdapter implements the EventRecorder interface
@@ -81,11 +94,20 @@ type EventRecorderAdapter struct {
 	NotificationSendPerformance *prometheus.HistogramVec
 
 	eventf func(object runtime.Object, warn bool, opts EventOptions, messageFmt string, args ...any)
+	// throttle suppresses repeated events within the windows configured with defaults.SetEventThrottleWindows
+	throttle *eventThrottle
+	// deliveries holds the last notification delivery to each destination of each object
+	deliveries *notificationDeliveries
+	// notifications sends the notifications of the events in the background. The notifications are sent right away
+	// when nil.
+	notifications *notificationQueue
 	// apiFactory is a notifications engine API factory
 	apiFactory api.Factory
+	// notificationPolicyLister lists the RolloutNotificationPolicies which subscribe rollouts to notifications
+	notificationPolicyLister rolloutlisters.RolloutNotificationPolicyLister
 }
 
-func NewEventRecorder(kubeclientset kubernetes.Interface, rolloutEventCounter *prometheus.CounterVec, notificationFailedCounter *prometheus.CounterVec, notificationSuccessCounter *prometheus.CounterVec, notificationSendPerformance *prometheus.HistogramVec, apiFactory api.Factory) EventRecorder {
+func NewEventRecorder(kubeclientset kubernetes.Interface, rolloutEventCounter *prometheus.CounterVec, notificationFailedCounter *prometheus.CounterVec, notificationSuccessCounter *prometheus.CounterVec, notificationSendPerformance *prometheus.HistogramVec, apiFactory api.Factory, notificationPolicyLister rolloutlisters.RolloutNotificationPolicyLister) EventRecorder {
 	// Create event broadcaster
 	// Add argo-rollouts custom resources to the default Kubernetes Scheme so Events can be
 	// logged for argo-rollouts types.
@@ -100,6 +122,10 @@ func NewEventRecorder(kubeclientset kubernetes.Interface, rolloutEventCounter *p
 		NotificationSuccessCounter:  notificationSuccessCounter,
 		NotificationSendPerformance: notificationSendPerformance,
 		apiFactory:                  apiFactory,
+		notificationPolicyLister:    notificationPolicyLister,
+		throttle:                    newEventThrottle(),
+		deliveries:                  newNotificationDeliveries(),
+		notifications:               newNotificationQueue(defaultNotificationQueueSize, defaultNotificationWorkers),
 	}
 	recorder.eventf = recorder.defaultEventf
 	return recorder
@@ -197,8 +223,11 @@ func NewFakeEventRecorder() *FakeEventRecorder {
 			[]string{"namespace", "name"},
 		),
 		NewFakeApiFactory(),
+		nil,
 	).(*EventRecorderAdapter)
 	recorder.Recorder = record.NewFakeRecorder(1000)
+	// the notifications of the fake recorder are sent right away
+	recorder.notifications = nil
 	fakeRecorder := &FakeEventRecorder{}
 	recorder.eventf = func(object runtime.Object, warn bool, opts EventOptions, messageFmt string, args ...any) {
 		recorder.defaultEventf(object, warn, opts, messageFmt, args...)
@@ -227,12 +256,19 @@ func (e *EventRecorderAdapter) defaultEventf(object runtime.Object, warn bool, o
 
 	if opts.EventReason != "" {
 		logCtx = logCtx.WithField("event_reason", opts.EventReason)
+		kind, namespace, name := logutil.KindNamespaceName(logCtx)
+		key := eventThrottleKey(kind, namespace, name, opts.EventReason)
+		if !e.throttle.Allow(key, defaults.GetEventThrottleWindow(opts.EventReason), timeutil.Now()) {
+			logCtx.Debugf("Suppressed repeated event: "+messageFmt, args...)
+			return
+		}
 		e.Recorder.Eventf(object, opts.EventType, opts.EventReason, messageFmt, args...)
 
 		// Increment rollout_events_total counter
-		kind, namespace, name := logutil.KindNamespaceName(logCtx)
 		if kind == "Rollout" {
 			e.RolloutEventCounter.WithLabelValues(namespace, name, opts.EventType, opts.EventReason).Inc()
+			eventbus.PublishRolloutEvent(object, opts.EventReason, messageFmt, args)
+			writeback.RecordRolloutEvent(object, opts.EventReason, messageFmt, args)
 		}
 
 		if e.apiFactory != nil {
@@ -242,10 +278,19 @@ func (e *EventRecorderAdapter) defaultEventf(object runtime.Object, warn bool, o
 				e.NotificationFailedCounter.WithLabelValues(namespace, name, opts.EventType, opts.EventReason).Inc()
 			}
 
-			for _, api := range apis {
-				err := e.sendNotifications(api, object, opts)
-				if err != nil {
-					logCtx.Errorf("Notifications failed to send for eventReason %s with error: %s", opts.EventReason, err)
+			if len(apis) > 0 {
+				// the object is sent in the background, while the worker which recorded the event keeps changing it
+				object := object.DeepCopyObject()
+				queued := e.notifications.Enqueue(func() {
+					for _, api := range apis {
+						err := e.sendNotifications(api, object, opts)
+						if err != nil {
+							logCtx.Errorf("Notifications failed to send for eventReason %s with error: %s", opts.EventReason, err)
+						}
+					}
+				})
+				if !queued {
+					e.NotificationFailedCounter.WithLabelValues(namespace, name, opts.EventType, opts.EventReason).Inc()
 				}
 			}
 		}
@@ -262,7 +307,18 @@ func (e *EventRecorderAdapter) K8sRecorder() record.EventRecorder {
 	return e.Recorder
 }
 
-func getAnalysisRunsFilterWithLabels(ro v1alpha1.Rollout, arInformer argoinformers.AnalysisRunInformer) (any, error) {
+func (e *EventRecorderAdapter) LastNotificationDeliveries(object runtime.Object) []NotificationDelivery {
+	kind, namespace, name := logutil.KindNamespaceName(logutil.WithObject(object))
+	return e.deliveries.Last(notificationDeliveryKey(kind, namespace, name))
+}
+
+func (e *EventRecorderAdapter) ForgetNotificationDeliveries(object runtime.Object) {
+	kind, namespace, name := logutil.KindNamespaceName(logutil.WithObject(object))
+	e.deliveries.Forget(notificationDeliveryKey(kind, namespace, name))
+}
+
+// getAnalysisRunsFilterWithLabels returns the analysis runs of the current revision of a rollout, the most recent first
+func getAnalysisRunsFilterWithLabels(ro v1alpha1.Rollout, arInformer argoinformers.AnalysisRunInformer) ([]*v1alpha1.AnalysisRun, error) {
 
 	set := labels.Set(map[string]string{
 		v1alpha1.DefaultRolloutUniqueLabelKey: ro.Status.CurrentPodHash,
@@ -290,27 +346,123 @@ func getAnalysisRunsFilterWithLabels(ro v1alpha1.Rollout, arInformer argoinforme
 		ts2 := filteredArs[j].ObjectMeta.CreationTimestamp.Time
 		return ts1.After(ts2)
 	})
+	return filteredArs, nil
+}
 
+// toAnalysisRunsObject converts analysis runs to the object the notification templates refer to as analysisRuns
+func toAnalysisRunsObject(ars []*v1alpha1.AnalysisRun) (any, error) {
+	if ars == nil {
+		return nil, nil
+	}
 	var arsObj any
-	arBytes, err := json.Marshal(filteredArs)
-
+	arBytes, err := json.Marshal(ars)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to marshal analysisRuns for rollout revision: %s, err: %w", string(revision), err)
+		return nil, fmt.Errorf("Failed to marshal analysisRuns: %w", err)
 	}
-
 	err = json.Unmarshal(arBytes, &arsObj)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to unmarshal analysisRuns for rollout revision: %s, err: %w", string(revision), err)
+		return nil, fmt.Errorf("Failed to unmarshal analysisRuns: %w", err)
 	}
-
 	return arsObj, nil
 }
 
+// analysisResults returns the results of the metrics of analysis runs, which the notification templates refer to as
+// analysisResults, so that they do not have to walk the status of the analysis runs
+func analysisResults(ars []*v1alpha1.AnalysisRun) []map[string]any {
+	var results []map[string]any
+	for _, ar := range ars {
+		for _, mr := range ar.Status.MetricResults {
+			results = append(results, map[string]any{
+				"analysisRun":  ar.Name,
+				"metric":       mr.Name,
+				"phase":        string(mr.Phase),
+				"message":      mr.Message,
+				"value":        lastMeasurementValue(mr),
+				"successful":   mr.Successful,
+				"failed":       mr.Failed,
+				"inconclusive": mr.Inconclusive,
+				"error":        mr.Error,
+			})
+		}
+	}
+	return results
+}
+
+// failedMetrics returns the names of the failed or errored metrics of analysis runs, which the notification templates
+// refer to as failedMetrics, so that a notification can tell what failed
+func failedMetrics(ars []*v1alpha1.AnalysisRun) []string {
+	var names []string
+	for _, ar := range ars {
+		for _, mr := range ar.Status.MetricResults {
+			if (mr.Phase == v1alpha1.AnalysisPhaseFailed || mr.Phase == v1alpha1.AnalysisPhaseError) && !slices.Contains(names, mr.Name) {
+				names = append(names, mr.Name)
+			}
+		}
+	}
+	return names
+}
+
+// metricValues returns the values of the last measurements of the metrics of analysis runs by metric name, which the
+// notification templates refer to as metricValues. Analysis runs are the most recent first, so a metric measured by
+// several runs has the value of its most recent measurement.
+func metricValues(ars []*v1alpha1.AnalysisRun) map[string]string {
+	values := map[string]string{}
+	for _, ar := range ars {
+		for _, mr := range ar.Status.MetricResults {
+			if _, ok := values[mr.Name]; !ok && len(mr.Measurements) > 0 {
+				values[mr.Name] = lastMeasurementValue(mr)
+			}
+		}
+	}
+	return values
+}
+
+func lastMeasurementValue(mr v1alpha1.MetricResult) string {
+	if len(mr.Measurements) == 0 {
+		return ""
+	}
+	return mr.Measurements[len(mr.Measurements)-1].Value
+}
+
+// canaryStep returns the index of the current step of a canary rollout and its number of steps, which the notification
+// templates refer to as step
+func canaryStep(ro *v1alpha1.Rollout) map[string]any {
+	step := map[string]any{
+		"count": len(ro.Spec.Strategy.Canary.Steps),
+	}
+	if ro.Status.CurrentStepIndex != nil {
+		step["index"] = *ro.Status.CurrentStepIndex
+	}
+	return step
+}
+
+// canaryWeights returns the desired weight of the canary of a rollout and, if its traffic is routed, the current
+// weights of the canary and the stable, which the notification templates refer to as weights
+func canaryWeights(ro *v1alpha1.Rollout) map[string]any {
+	weights := map[string]any{
+		"desired": replicasetutil.GetCurrentSetWeight(ro),
+	}
+	if w := ro.Status.Canary.Weights; w != nil {
+		weights["current"] = w.Canary.Weight
+		weights["stable"] = w.Stable.Weight
+	}
+	return weights
+}
+
 func NewAPIFactorySettings(arInformer argoinformers.AnalysisRunInformer) api.Settings {
 	return api.Settings{
 		SecretName:    NotificationSecret,
 		ConfigMapName: NotificationConfigMap,
 		InitGetVars: func(cfg *api.Config, configMap *corev1.ConfigMap, secret *corev1.Secret) (api.GetVars, error) {
+			if err := addSignedWebhookServices(cfg, configMap, secret); err != nil {
+				return nil, err
+			}
+			if err := addIncidentServices(cfg, configMap, secret); err != nil {
+				return nil, err
+			}
+			if err := disableServicesWithoutOutboundTLS(cfg, configMap); err != nil {
+				return nil, err
+			}
 			return func(obj map[string]any, dest services.Destination) map[string]any {
 
 				var vars = map[string]any{
@@ -319,11 +471,6 @@ func NewAPIFactorySettings(arInformer argoinformers.AnalysisRunInformer) api.Set
 					"secrets": secret.Data,
 				}
 
-				if arInformer == nil {
-					log.Infof("Notification is not set for analysisRun Informer: %s", dest)
-					return vars
-				}
-
 				var ro v1alpha1.Rollout
 				err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, &ro)
 
@@ -331,8 +478,25 @@ func NewAPIFactorySettings(arInformer argoinformers.AnalysisRunInformer) api.Set
 					log.Errorf("unable to send notification: bad rollout object: %v", err)
 					return vars
 				}
+				// the pod template hash identifies the revision of the rollout, so that the notifications of a revision
+				// can be grouped, e.g. in a Slack thread. The template of a rollout referencing a workload is not part
+				// of its spec, so the hash is only computed from the template before the first status update.
+				podTemplateHash := ro.Status.CurrentPodHash
+				if podTemplateHash == "" && ro.Spec.WorkloadRef == nil {
+					podTemplateHash = hashutil.ComputePodTemplateHash(&ro.Spec.Template, ro.Status.CollisionCount)
+				}
+				vars["podTemplateHash"] = podTemplateHash
+				if ro.Spec.Strategy.Canary != nil {
+					vars["step"] = canaryStep(&ro)
+					vars["weights"] = canaryWeights(&ro)
+				}
+
+				if arInformer == nil {
+					log.Infof("Notification is not set for analysisRun Informer: %s", dest)
+					return vars
+				}
 
-				arsObj, err := getAnalysisRunsFilterWithLabels(ro, arInformer)
+				ars, err := getAnalysisRunsFilterWithLabels(ro, arInformer)
 
 				if err != nil {
 					log.Errorf("Error calling getAnalysisRunsFilterWithLabels for namespace: %s",
@@ -340,23 +504,69 @@ func NewAPIFactorySettings(arInformer argoinformers.AnalysisRunInformer) api.Set
 					return vars
 
 				}
-
-				vars = map[string]any{
-					"rollout":      obj,
-					"analysisRuns": arsObj,
-					"time":         timeExprs,
-					"secrets":      secret.Data,
+				arsObj, err := toAnalysisRunsObject(ars)
+				if err != nil {
+					log.Errorf("unable to send notification: bad analysis runs of rollout %s/%s: %v", ro.Namespace, ro.Name, err)
+					return vars
 				}
+
+				vars["analysisRuns"] = arsObj
+				vars["analysisResults"] = analysisResults(ars)
+				vars["failedMetrics"] = failedMetrics(ars)
+				vars["metricValues"] = metricValues(ars)
 				return vars
 			}, nil
 		},
 	}
 }
 
+// outboundTLSServiceTypes are the types of the notification services of the controller, which connect with its
+// outbound TLS policy
+var outboundTLSServiceTypes = []string{SignedWebhookServiceType, PagerDutyEventsServiceType, OpsgenieAlertsServiceType}
+
+// disableServicesWithoutOutboundTLS replaces the services of the notifications engine, e.g. slack or webhook, with
+// services which fail to send when the controller has an outbound TLS policy. The notifications engine creates their
+// connections without the policy, so their notifications are not sent rather than sent without it.
+func disableServicesWithoutOutboundTLS(cfg *api.Config, configMap *corev1.ConfigMap) error {
+	if cfg == nil || configMap == nil || !tlsutil.HasClientConfig() {
+		return nil
+	}
+	supported := map[string]bool{}
+	for _, serviceType := range outboundTLSServiceTypes {
+		serviceConfigs, err := customServiceConfigs(configMap, serviceType)
+		if err != nil {
+			return err
+		}
+		for name := range serviceConfigs {
+			supported[name] = true
+		}
+	}
+	for name := range cfg.Services {
+		if supported[name] {
+			continue
+		}
+		log.Warnf("Notification service %s does not support the outbound TLS policy of the controller, its notifications are not sent", name)
+		err := fmt.Errorf("notification service %s does not support the outbound TLS policy of the controller, use a service of type %s instead", name, strings.Join(outboundTLSServiceTypes, ", "))
+		cfg.Services[name] = func() (services.NotificationService, error) {
+			return &unsupportedService{err: err}, nil
+		}
+	}
+	return nil
+}
+
+// unsupportedService is a notification service whose notifications fail to be sent
+type unsupportedService struct {
+	err error
+}
+
+func (s *unsupportedService) Send(notification services.Notification, dest services.Destination) error {
+	return s.err
+}
+
 // Send notifications for triggered event if user is subscribed
 func (e *EventRecorderAdapter) sendNotifications(notificationsAPI api.API, object runtime.Object, opts EventOptions) []error {
 	logCtx := logutil.WithObject(object)
-	_, namespace, name := logutil.KindNamespaceName(logCtx)
+	kind, namespace, name := logutil.KindNamespaceName(logCtx)
 	startTime := timeutil.Now()
 	defer func() {
 		duration := time.Since(startTime)
@@ -371,8 +581,9 @@ func (e *EventRecorderAdapter) sendNotifications(notificationsAPI api.API, objec
 	cfg := notificationsAPI.GetConfig()
 	destByTrigger := cfg.GetGlobalDestinations(object.(metav1.Object).GetLabels())
 	destByTrigger.Merge(subscriptions.NewAnnotations(object.(metav1.Object).GetAnnotations()).GetDestinations(cfg.DefaultTriggers, cfg.ServiceDefaultTriggers))
+	destByTrigger.Merge(e.getPolicyDestinations(object.(metav1.Object), cfg))
 	trigger := translateReasonToTrigger(opts.EventReason)
-	destinations := destByTrigger[trigger]
+	destinations := destByTrigger.Dedup()[trigger]
 	if len(destinations) == 0 {
 		logCtx.Debugf("No configured destinations for trigger: %s", trigger)
 		return nil
@@ -402,6 +613,7 @@ func (e *EventRecorderAdapter) sendNotifications(notificationsAPI api.API, objec
 			s := strings.Split(c.Key, ".")[1]
 			if s != emptyCondition && c.Triggered == true {
 				err = notificationsAPI.Send(objMap, c.Templates, destination)
+				e.deliveries.Record(notificationDeliveryKey(kind, namespace, name), NotificationDelivery{Time: timeutil.Now(), Destination: destination, Error: err})
 				if err != nil {
 					log.Errorf("Failed to execute the sending of notification on not empty condition, trigger: %s, destination: %s, namespace config: %s : %v",
 						trigger, destination, notificationsAPI.GetConfig().Namespace, err)
@@ -412,6 +624,7 @@ func (e *EventRecorderAdapter) sendNotifications(notificationsAPI api.API, objec
 				e.NotificationSuccessCounter.WithLabelValues(namespace, name, opts.EventType, opts.EventReason).Inc()
 			} else if s == emptyCondition {
 				err = notificationsAPI.Send(objMap, c.Templates, destination)
+				e.deliveries.Record(notificationDeliveryKey(kind, namespace, name), NotificationDelivery{Time: timeutil.Now(), Destination: destination, Error: err})
 				if err != nil {
 					log.Errorf("Failed to execute the sending of notification on empty condition, trigger: %s, destination: %s, namespace config: %s : %v",
 						trigger, destination, notificationsAPI.GetConfig().Namespace, err)
@@ -429,6 +642,47 @@ func (e *EventRecorderAdapter) sendNotifications(notificationsAPI api.API, objec
 	return errors
 }
 
+// getPolicyDestinations returns the destinations of the RolloutNotificationPolicies of the namespace of an object
+// which select the object
+func (e *EventRecorderAdapter) getPolicyDestinations(obj metav1.Object, cfg api.Config) services.Destinations {
+	dests := services.Destinations{}
+	if e.notificationPolicyLister == nil {
+		return dests
+	}
+	policies, err := e.notificationPolicyLister.RolloutNotificationPolicies(obj.GetNamespace()).List(labels.Everything())
+	if err != nil {
+		log.Errorf("Failed to list notification policies of namespace %s: %v", obj.GetNamespace(), err)
+		return dests
+	}
+	for _, policy := range policies {
+		if policy.Spec.Selector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(policy.Spec.Selector)
+			if err != nil {
+				log.Warnf("Invalid selector of notification policy %s/%s: %v", policy.Namespace, policy.Name, err)
+				continue
+			}
+			if !selector.Matches(labels.Set(obj.GetLabels())) {
+				continue
+			}
+		}
+		for _, sub := range policy.Spec.Subscriptions {
+			triggers := sub.Triggers
+			if len(triggers) == 0 {
+				triggers = cfg.DefaultTriggers
+				if t, ok := cfg.ServiceDefaultTriggers[sub.Service]; ok {
+					triggers = t
+				}
+			}
+			for _, trigger := range triggers {
+				for _, recipient := range sub.Recipients {
+					dests[trigger] = append(dests[trigger], services.Destination{Service: sub.Service, Recipient: recipient})
+				}
+			}
+		}
+	}
+	return dests
+}
+
 // This function is copied over from notification engine to make sure we honour emptyCondition
 // emptyConditions today are not handled well in notification engine.
 // TODO: update notification engine to handle emptyConditions and remove this function and its usage
@@ -27,12 +27,14 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	argofake "github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned/fake"
 	argoinformersfactory "github.com/argoproj/argo-rollouts/pkg/client/informers/externalversions"
 	argoinformers "github.com/argoproj/argo-rollouts/pkg/client/informers/externalversions/rollouts/v1alpha1"
 	"github.com/argoproj/argo-rollouts/utils/defaults"
+	hashutil "github.com/argoproj/argo-rollouts/utils/hash"
 	timeutil "github.com/argoproj/argo-rollouts/utils/time"
 )
 
@@ -101,6 +103,51 @@ func TestIncCounter(t *testing.T) {
 	assert.Equal(t, []string{"FooReason", "FooReason", "FooReason"}, rec.Events())
 }
 
+func TestEventThrottle(t *testing.T) {
+	defaults.SetEventThrottleWindows(0, map[string]time.Duration{"RolloutAborted": 10 * time.Minute})
+	defer defaults.SetEventThrottleWindows(defaults.DefaultEventThrottleWindow, nil)
+	now := time.Now()
+	timeutil.SetNowTimeFunc(func() time.Time { return now })
+	defer timeutil.SetNowTimeFunc(time.Now)
+
+	r := v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "guestbook",
+			Namespace: "default",
+		},
+	}
+	rec := NewFakeEventRecorder()
+	for i := 0; i < 3; i++ {
+		rec.Warnf(&r, EventOptions{EventReason: "RolloutAborted"}, "Rollout aborted")
+		rec.Eventf(&r, EventOptions{EventReason: "RolloutUpdated"}, "Rollout updated")
+	}
+	assert.Equal(t, float64(1), testutil.ToFloat64(rec.RolloutEventCounter.WithLabelValues("default", "guestbook", corev1.EventTypeWarning, "RolloutAborted")))
+	assert.Equal(t, float64(3), testutil.ToFloat64(rec.RolloutEventCounter.WithLabelValues("default", "guestbook", corev1.EventTypeNormal, "RolloutUpdated")))
+
+	// events of other rollouts are not suppressed
+	other := r.DeepCopy()
+	other.Name = "other"
+	rec.Warnf(other, EventOptions{EventReason: "RolloutAborted"}, "Rollout aborted")
+	assert.Equal(t, float64(1), testutil.ToFloat64(rec.RolloutEventCounter.WithLabelValues("default", "other", corev1.EventTypeWarning, "RolloutAborted")))
+
+	now = now.Add(10 * time.Minute)
+	rec.Warnf(&r, EventOptions{EventReason: "RolloutAborted"}, "Rollout aborted")
+	assert.Equal(t, float64(2), testutil.ToFloat64(rec.RolloutEventCounter.WithLabelValues("default", "guestbook", corev1.EventTypeWarning, "RolloutAborted")))
+}
+
+func TestEventThrottlePrunesExpiredEntries(t *testing.T) {
+	throttle := newEventThrottle()
+	now := time.Now()
+	assert.True(t, throttle.Allow("a", time.Minute, now))
+	assert.False(t, throttle.Allow("a", time.Minute, now.Add(30*time.Second)))
+	assert.True(t, throttle.Allow("b", time.Minute, now.Add(2*time.Minute)))
+	assert.Len(t, throttle.suppressedUntil, 1)
+	assert.True(t, throttle.Allow("a", 0, now))
+
+	var nilThrottle *eventThrottle
+	assert.True(t, nilThrottle.Allow("a", time.Minute, now))
+}
+
 func TestSendNotifications(t *testing.T) {
 	r := v1alpha1.Rollout{
 		ObjectMeta: metav1.ObjectMeta{
@@ -124,8 +171,118 @@ func TestSendNotifications(t *testing.T) {
 	rec := NewFakeEventRecorder()
 	rec.EventRecorderAdapter.apiFactory = apiFactory
 	//ch := make(chan prometheus.HistogramVec, 1)
+	assert.Empty(t, rec.LastNotificationDeliveries(&r))
+	err := rec.sendNotifications(mockAPI, &r, EventOptions{EventReason: "FooReason"})
+	assert.Nil(t, err)
+	deliveries := rec.LastNotificationDeliveries(&r)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, services.Destination{Service: "console", Recipient: "console"}, deliveries[0].Destination)
+	assert.NoError(t, deliveries[0].Error)
+
+	rec.ForgetNotificationDeliveries(&r)
+	assert.Empty(t, rec.LastNotificationDeliveries(&r))
+}
+
+func TestSendNotificationsWithPolicies(t *testing.T) {
+	r := v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "guestbook",
+			Namespace:   "default",
+			Labels:      map[string]string{"team": "guestbook"},
+			Annotations: map[string]string{"notifications.argoproj.io/subscribe.on-foo-reason.console": "console"},
+		},
+	}
+	policies := []*v1alpha1.RolloutNotificationPolicy{{
+		ObjectMeta: metav1.ObjectMeta{Name: "all", Namespace: "default"},
+		Spec: v1alpha1.RolloutNotificationPolicySpec{
+			Subscriptions: []v1alpha1.NotificationSubscription{
+				{Triggers: []string{"on-foo-reason"}, Service: "console", Recipients: []string{"console"}},
+				{Service: "slack", Recipients: []string{"deploys"}},
+			},
+		},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Name: "other-team", Namespace: "default"},
+		Spec: v1alpha1.RolloutNotificationPolicySpec{
+			Selector:      &metav1.LabelSelector{MatchLabels: map[string]string{"team": "other"}},
+			Subscriptions: []v1alpha1.NotificationSubscription{{Service: "slack", Recipients: []string{"other"}}},
+		},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Name: "other-namespace", Namespace: "other"},
+		Spec: v1alpha1.RolloutNotificationPolicySpec{
+			Subscriptions: []v1alpha1.NotificationSubscription{{Service: "slack", Recipients: []string{"other"}}},
+		},
+	}}
+	i := argoinformersfactory.NewSharedInformerFactory(argofake.NewSimpleClientset(), noResyncPeriodFunc())
+	policyInformer := i.Argoproj().V1alpha1().RolloutNotificationPolicies()
+	for _, policy := range policies {
+		require.NoError(t, policyInformer.Informer().GetIndexer().Add(policy))
+	}
+
+	mockCtrl := gomock.NewController(t)
+	mockAPI := mocks.NewMockAPI(mockCtrl)
+	cr := []triggers.ConditionResult{{
+		Key:       "1." + hash(""),
+		Triggered: true,
+		Templates: []string{"my-template"},
+	}}
+	mockAPI.EXPECT().RunTrigger(gomock.Any(), gomock.Any()).Return(cr, nil).AnyTimes()
+	mockAPI.EXPECT().Send(gomock.Any(), gomock.Any(), services.Destination{Service: "console", Recipient: "console"}).Return(nil).Times(1)
+	mockAPI.EXPECT().Send(gomock.Any(), gomock.Any(), services.Destination{Service: "slack", Recipient: "deploys"}).Return(nil).Times(1)
+	mockAPI.EXPECT().GetConfig().Return(api.Config{
+		Triggers:        map[string][]triggers.Condition{"on-foo-reason": {triggers.Condition{Send: []string{"my-template"}}}},
+		DefaultTriggers: []string{"on-foo-reason"},
+	}).AnyTimes()
+	rec := NewFakeEventRecorder()
+	rec.EventRecorderAdapter.notificationPolicyLister = policyInformer.Lister()
+
 	err := rec.sendNotifications(mockAPI, &r, EventOptions{EventReason: "FooReason"})
 	assert.Nil(t, err)
+	// the deliveries are recorded per destination
+	deliveries := rec.LastNotificationDeliveries(&r)
+	require.Len(t, deliveries, 2)
+	assert.Equal(t, services.Destination{Service: "console", Recipient: "console"}, deliveries[0].Destination)
+	assert.Equal(t, services.Destination{Service: "slack", Recipient: "deploys"}, deliveries[1].Destination)
+}
+
+func TestSendNotificationsInBackground(t *testing.T) {
+	r := v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "guestbook",
+			Namespace:   "default",
+			Annotations: map[string]string{"notifications.argoproj.io/subscribe.on-foo-reason.console": "console"},
+		},
+	}
+	mockCtrl := gomock.NewController(t)
+	mockAPI := mocks.NewMockAPI(mockCtrl)
+	cr := []triggers.ConditionResult{{
+		Key:       "1." + hash(""),
+		Triggered: true,
+		Templates: []string{"my-template"},
+	}}
+	sent := make(chan struct{})
+	mockAPI.EXPECT().RunTrigger(gomock.Any(), gomock.Any()).Return(cr, nil).AnyTimes()
+	mockAPI.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(any, any, any) error {
+		<-sent
+		return nil
+	}).Times(1)
+	mockAPI.EXPECT().GetConfig().Return(api.Config{
+		Triggers: map[string][]triggers.Condition{"on-foo-reason": {triggers.Condition{Send: []string{"my-template"}}}}}).AnyTimes()
+	rec := NewFakeEventRecorder()
+	rec.EventRecorderAdapter.apiFactory = &mocks.FakeFactory{Api: mockAPI}
+	// a single notification waits to be sent while another one is being sent
+	rec.notifications = newNotificationQueue(1, 1)
+	rec.eventf = rec.defaultEventf
+
+	rec.Eventf(&r, EventOptions{EventReason: "FooReason"}, "first")
+	assert.Empty(t, rec.LastNotificationDeliveries(&r))
+	// the notifications of the events which do not fit in the queue are dropped
+	assert.Eventually(t, func() bool { return len(rec.notifications.sends) == 0 }, 5*time.Second, 10*time.Millisecond)
+	rec.Eventf(&r, EventOptions{EventReason: "BarReason"}, "queued")
+	rec.Eventf(&r, EventOptions{EventReason: "BazReason"}, "dropped")
+	assert.Equal(t, 1.0, testutil.ToFloat64(rec.NotificationFailedCounter.WithLabelValues("default", "guestbook", "Normal", "BazReason")))
+
+	close(sent)
+	assert.Eventually(t, func() bool { return len(rec.LastNotificationDeliveries(&r)) == 1 }, 5*time.Second, 10*time.Millisecond)
 }
 
 func TestSendNotificationsWhenCondition(t *testing.T) {
@@ -367,6 +524,9 @@ func TestSendNotificationsFails(t *testing.T) {
 
 		err := rec.sendNotifications(mockAPI, &r, EventOptions{EventReason: "FooReason"})
 		assert.Len(t, err, 1)
+		deliveries := rec.LastNotificationDeliveries(&r)
+		require.Len(t, deliveries, 1)
+		assert.EqualError(t, deliveries[0].Error, "failed to send")
 	})
 
 	t.Run("GetAPIError", func(t *testing.T) {
@@ -469,6 +629,16 @@ func TestNewAPIFactorySettings(t *testing.T) {
 				Labels:            map[string]string{"rollouts-pod-template-hash": "85659df978"},
 				Annotations:       map[string]string{"rollout.argoproj.io/revision": "1"},
 			},
+			Status: v1alpha1.AnalysisRunStatus{
+				MetricResults: []v1alpha1.MetricResult{{
+					Name:         "success-rate",
+					Phase:        v1alpha1.AnalysisPhaseFailed,
+					Message:      "metric failed",
+					Measurements: []v1alpha1.Measurement{{Value: "0.95"}, {Value: "0.42"}},
+					Successful:   1,
+					Failed:       1,
+				}},
+			},
 		},
 		{
 			ObjectMeta: metav1.ObjectMeta{
@@ -491,6 +661,21 @@ func TestNewAPIFactorySettings(t *testing.T) {
 		},
 	}
 
+	podTemplateHash := ro.Status.CurrentPodHash
+
+	newRo := ro.DeepCopy()
+	newRo.Status = v1alpha1.RolloutStatus{}
+
+	canaryRo := ro.DeepCopy()
+	canaryRo.Spec.Strategy.Canary = &v1alpha1.CanaryStrategy{
+		Steps: []v1alpha1.CanaryStep{{SetWeight: ptr.To[int32](10)}, {SetWeight: ptr.To[int32](20)}, {Pause: &v1alpha1.RolloutPause{}}},
+	}
+	canaryRo.Status.CurrentStepIndex = ptr.To[int32](1)
+	canaryRo.Status.Canary.Weights = &v1alpha1.TrafficWeights{
+		Canary: v1alpha1.WeightDestination{Weight: 10},
+		Stable: v1alpha1.WeightDestination{Weight: 90},
+	}
+
 	expectedSecrets := map[string][]byte{
 		"notification-secret": []byte("secret-value"),
 	}
@@ -524,8 +709,22 @@ func TestNewAPIFactorySettings(t *testing.T) {
 				return map[string]interface{}{
 					"rollout":      obj,
 					"analysisRuns": ar,
-					"time":         timeExprs,
-					"secrets":      expectedSecrets,
+					"analysisResults": []map[string]any{{
+						"analysisRun":  "analysis-run-1",
+						"metric":       "success-rate",
+						"phase":        "Failed",
+						"message":      "metric failed",
+						"value":        "0.42",
+						"successful":   int32(1),
+						"failed":       int32(1),
+						"inconclusive": int32(0),
+						"error":        int32(0),
+					}},
+					"failedMetrics":   []string{"success-rate"},
+					"metricValues":    map[string]string{"success-rate": "0.42"},
+					"time":            timeExprs,
+					"secrets":         expectedSecrets,
+					"podTemplateHash": podTemplateHash,
 				}
 			},
 		},
@@ -548,10 +747,14 @@ func TestNewAPIFactorySettings(t *testing.T) {
 			},
 			expected: func(obj map[string]interface{}, ar any) map[string]interface{} {
 				return map[string]interface{}{
-					"rollout":      obj,
-					"analysisRuns": nil,
-					"time":         timeExprs,
-					"secrets":      expectedSecrets,
+					"rollout":         obj,
+					"analysisRuns":    nil,
+					"analysisResults": []map[string]any(nil),
+					"failedMetrics":   []string(nil),
+					"metricValues":    map[string]string{},
+					"time":            timeExprs,
+					"secrets":         expectedSecrets,
+					"podTemplateHash": podTemplateHash,
 				}
 			},
 		},
@@ -564,9 +767,44 @@ func TestNewAPIFactorySettings(t *testing.T) {
 			ars:     nil,
 			expected: func(obj map[string]interface{}, ar any) map[string]interface{} {
 				return map[string]interface{}{
-					"rollout": obj,
-					"time":    timeExprs,
-					"secrets": expectedSecrets,
+					"rollout":         obj,
+					"time":            timeExprs,
+					"secrets":         expectedSecrets,
+					"podTemplateHash": podTemplateHash,
+				}
+			},
+		},
+		{
+			name: "pod template hash before the first status update",
+			arInformer: func(ars []*v1alpha1.AnalysisRun) argoinformers.AnalysisRunInformer {
+				return nil
+			},
+			rollout: *newRo,
+			ars:     nil,
+			expected: func(obj map[string]interface{}, ar any) map[string]interface{} {
+				return map[string]interface{}{
+					"rollout":         obj,
+					"time":            timeExprs,
+					"secrets":         expectedSecrets,
+					"podTemplateHash": hashutil.ComputePodTemplateHash(&newRo.Spec.Template, nil),
+				}
+			},
+		},
+		{
+			name: "canary step and weights",
+			arInformer: func(ars []*v1alpha1.AnalysisRun) argoinformers.AnalysisRunInformer {
+				return nil
+			},
+			rollout: *canaryRo,
+			ars:     nil,
+			expected: func(obj map[string]interface{}, ar any) map[string]interface{} {
+				return map[string]interface{}{
+					"rollout":         obj,
+					"time":            timeExprs,
+					"secrets":         expectedSecrets,
+					"podTemplateHash": podTemplateHash,
+					"step":            map[string]any{"index": int32(1), "count": 3},
+					"weights":         map[string]any{"desired": int32(20), "current": int32(10), "stable": int32(90)},
 				}
 			},
 		},
@@ -589,10 +827,14 @@ func TestNewAPIFactorySettings(t *testing.T) {
 			},
 			expected: func(obj map[string]interface{}, ar any) map[string]interface{} {
 				return map[string]interface{}{
-					"rollout":      obj,
-					"analysisRuns": nil,
-					"time":         timeExprs,
-					"secrets":      expectedSecrets,
+					"rollout":         obj,
+					"analysisRuns":    nil,
+					"analysisResults": []map[string]any(nil),
+					"failedMetrics":   []string(nil),
+					"metricValues":    map[string]string{},
+					"time":            timeExprs,
+					"secrets":         expectedSecrets,
+					"podTemplateHash": podTemplateHash,
 				}
 			},
 		},
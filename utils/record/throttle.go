@@ -0,0 +1,55 @@
+package record
+
+import (
+	"sync"
+	"time"
+)
+
+// eventThrottle remembers until when the events emitted for an object suppress repeated events with the same
+// reason. This keeps flapping rollouts from flooding the Kubernetes Events and the notification services.
+type eventThrottle struct {
+	lock sync.Mutex
+	// suppressedUntil holds the time until which events are suppressed, keyed by the object and the event reason
+	suppressedUntil map[string]time.Time
+	// lastPruned is the time the expired entries of suppressedUntil were last removed
+	lastPruned time.Time
+}
+
+func newEventThrottle() *eventThrottle {
+	return &eventThrottle{
+		suppressedUntil: map[string]time.Time{},
+	}
+}
+
+func eventThrottleKey(kind, namespace, name, reason string) string {
+	return kind + "/" + namespace + "/" + name + "/" + reason
+}
+
+// Allow returns whether an event with the given key may be emitted. An event is suppressed when an event with the
+// same key was emitted less than the window ago. A nil eventThrottle or a non-positive window allows all events.
+func (t *eventThrottle) Allow(key string, window time.Duration, now time.Time) bool {
+	if t == nil || window <= 0 {
+		return true
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if until, ok := t.suppressedUntil[key]; ok && now.Before(until) {
+		return false
+	}
+	t.suppressedUntil[key] = now.Add(window)
+	t.prune(window, now)
+	return true
+}
+
+// prune removes the expired entries, at most once per window, so that deleted objects are forgotten
+func (t *eventThrottle) prune(window time.Duration, now time.Time) {
+	if now.Sub(t.lastPruned) < window {
+		return
+	}
+	for key, until := range t.suppressedUntil {
+		if !now.Before(until) {
+			delete(t.suppressedUntil, key)
+		}
+	}
+	t.lastPruned = now
+}
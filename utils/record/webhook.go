@@ -0,0 +1,252 @@
+package record
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/argoproj/notifications-engine/pkg/api"
+	"github.com/argoproj/notifications-engine/pkg/services"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/yaml"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	tlsutil "github.com/argoproj/argo-rollouts/utils/tls"
+)
+
+const (
+	// SignedWebhookServiceType is the type of the notification services which post notifications to a webhook with
+	// an HMAC-SHA256 signature of the payload, e.g. service.signedwebhook.<name> in the notification ConfigMap
+	SignedWebhookServiceType = "signedwebhook"
+	// DefaultSignatureHeader is the header which holds the signature of the payload of a signed webhook
+	DefaultSignatureHeader = "X-Rollouts-Signature-256"
+
+	defaultWebhookRetryMax     = 3
+	defaultWebhookRetryWaitMin = time.Second
+	defaultWebhookRetryWaitMax = 30 * time.Second
+	webhookTimeout             = 10 * time.Second
+)
+
+// SignedWebhookOptions are the options of a signed webhook notification service
+type SignedWebhookOptions struct {
+	// URL is the URL of the webhook
+	URL string `json:"url"`
+	// Headers are sent with each request
+	Headers []services.Header `json:"headers,omitempty"`
+	// Secret is the key of the HMAC-SHA256 signature of the payload
+	Secret string `json:"secret"`
+	// SignatureHeader is the header which holds the signature, in the sha256=<hex digest> format. Defaults to
+	// X-Rollouts-Signature-256
+	SignatureHeader string `json:"signatureHeader,omitempty"`
+	// RetryMax is the number of retries of a failed delivery. Defaults to 3
+	RetryMax *int `json:"retryMax,omitempty"`
+	// RetryWaitMin is the wait before the first retry, which doubles with each retry. Defaults to 1s
+	RetryWaitMin v1alpha1.DurationString `json:"retryWaitMin,omitempty"`
+	// RetryWaitMax caps the wait between retries. Defaults to 30s
+	RetryWaitMax v1alpha1.DurationString `json:"retryWaitMax,omitempty"`
+	// InsecureSkipVerify skips the verification of the certificate of the webhook
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+type signedWebhookService struct {
+	opts         SignedWebhookOptions
+	retryMax     int
+	retryWaitMin time.Duration
+	retryWaitMax time.Duration
+	client       *http.Client
+}
+
+// webhookStatusError is returned when a webhook responds with an unsuccessful status code
+type webhookStatusError struct {
+	url        string
+	statusCode int
+	body       string
+}
+
+func (e *webhookStatusError) Error() string {
+	return fmt.Sprintf("request to %s has failed with status code %d: %s", e.url, e.statusCode, e.body)
+}
+
+// NewSignedWebhookService returns a notification service which posts notifications to a webhook with an HMAC-SHA256
+// signature of the payload, and retries failed deliveries with an exponential backoff
+func NewSignedWebhookService(opts SignedWebhookOptions) (services.NotificationService, error) {
+	if opts.URL == "" {
+		return nil, fmt.Errorf("url is required")
+	}
+	if opts.Secret == "" {
+		return nil, fmt.Errorf("secret is required")
+	}
+	s := &signedWebhookService{
+		opts:         opts,
+		retryMax:     defaultWebhookRetryMax,
+		retryWaitMin: defaultWebhookRetryWaitMin,
+		retryWaitMax: defaultWebhookRetryWaitMax,
+		client: &http.Client{
+			Timeout: webhookTimeout,
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: tlsutil.ClientConfig(opts.InsecureSkipVerify),
+			},
+		},
+	}
+	if s.opts.SignatureHeader == "" {
+		s.opts.SignatureHeader = DefaultSignatureHeader
+	}
+	if opts.RetryMax != nil {
+		if *opts.RetryMax < 0 {
+			return nil, fmt.Errorf("retryMax must not be negative")
+		}
+		s.retryMax = *opts.RetryMax
+	}
+	var err error
+	if opts.RetryWaitMin != "" {
+		if s.retryWaitMin, err = opts.RetryWaitMin.Duration(); err != nil {
+			return nil, fmt.Errorf("invalid retryWaitMin: %w", err)
+		}
+	}
+	if opts.RetryWaitMax != "" {
+		if s.retryWaitMax, err = opts.RetryWaitMax.Duration(); err != nil {
+			return nil, fmt.Errorf("invalid retryWaitMax: %w", err)
+		}
+	}
+	return s, nil
+}
+
+// Send posts the webhook payload of the notification to the webhook. The payload is the body of the webhook template
+// named after the service, or the message of the notification if the template has none.
+func (s *signedWebhookService) Send(notification services.Notification, dest services.Destination) error {
+	method, url, body := http.MethodPost, s.opts.URL, notification.Message
+	if webhook, ok := notification.Webhook[dest.Service]; ok {
+		body = webhook.Body
+		if webhook.Method != "" {
+			method = webhook.Method
+		}
+		if webhook.Path != "" {
+			url = strings.TrimRight(url, "/") + "/" + strings.TrimLeft(webhook.Path, "/")
+		}
+	}
+	signature := Sign([]byte(s.opts.Secret), []byte(body))
+
+	var lastErr error
+	backoff := wait.Backoff{Duration: s.retryWaitMin, Factor: 2, Steps: s.retryMax + 1, Cap: s.retryWaitMax}
+	err := wait.ExponentialBackoff(backoff, func() (bool, error) {
+		lastErr = s.post(method, url, body, signature)
+		if lastErr == nil {
+			return true, nil
+		}
+		if statusErr, ok := lastErr.(*webhookStatusError); ok && !retryable(statusErr.statusCode) {
+			return false, lastErr
+		}
+		return false, nil
+	})
+	if wait.Interrupted(err) {
+		return fmt.Errorf("giving up after %d attempts: %w", s.retryMax+1, lastErr)
+	}
+	return err
+}
+
+func (s *signedWebhookService) post(method, url, body, signature string) error {
+	req, err := http.NewRequest(method, url, bytes.NewBufferString(body))
+	if err != nil {
+		return err
+	}
+	for _, header := range s.opts.Headers {
+		req.Header.Set(header.Name, header.Value)
+	}
+	req.Header.Set(s.opts.SignatureHeader, signature)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &webhookStatusError{url: url, statusCode: resp.StatusCode, body: string(data)}
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// retryable returns whether a delivery which failed with a status code might succeed if retried
+func retryable(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
+
+// Sign returns the HMAC-SHA256 signature of a webhook payload, in the sha256=<hex digest> format
+func Sign(secret, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// addSignedWebhookServices adds the signed webhook services of a notification ConfigMap to its configuration, which
+// the notifications engine does not know how to create
+func addSignedWebhookServices(cfg *api.Config, configMap *corev1.ConfigMap, secret *corev1.Secret) error {
+	if cfg == nil || configMap == nil {
+		return nil
+	}
+	if cfg.Services == nil {
+		cfg.Services = map[string]api.ServiceFactory{}
+	}
+	serviceConfigs, err := customServiceConfigs(configMap, SignedWebhookServiceType)
+	if err != nil {
+		return err
+	}
+	for name, v := range serviceConfigs {
+		var opts SignedWebhookOptions
+		if err := yaml.Unmarshal([]byte(v), &opts); err != nil {
+			return fmt.Errorf("failed to unmarshal service %s: %w", name, err)
+		}
+		opts.URL = replaceSecret(opts.URL, secret)
+		opts.Secret = replaceSecret(opts.Secret, secret)
+		for i := range opts.Headers {
+			opts.Headers[i].Value = replaceSecret(opts.Headers[i].Value, secret)
+		}
+		svc, err := NewSignedWebhookService(opts)
+		if err != nil {
+			return fmt.Errorf("invalid service %s: %w", name, err)
+		}
+		cfg.Services[name] = func() (services.NotificationService, error) {
+			return svc, nil
+		}
+	}
+	return nil
+}
+
+// customServiceConfigs returns the configurations of the services of a type of a notification ConfigMap by name, i.e.
+// of the service.<type>(.<name>) keys
+func customServiceConfigs(configMap *corev1.ConfigMap, serviceType string) (map[string]string, error) {
+	configs := map[string]string{}
+	for k, v := range configMap.Data {
+		parts := strings.Split(k, ".")
+		if len(parts) < 2 || parts[0] != "service" || parts[1] != serviceType {
+			continue
+		}
+		name := parts[1]
+		if len(parts) == 3 {
+			name = parts[2]
+		} else if len(parts) > 3 {
+			return nil, fmt.Errorf("invalid service key; expected 'service.<type>(.<name>)' but got '%s'", k)
+		}
+		configs[name] = v
+	}
+	return configs, nil
+}
+
+// replaceSecret replaces a $<key> reference to a key of the notification secret with its value
+func replaceSecret(val string, secret *corev1.Secret) string {
+	if !strings.HasPrefix(val, "$") || secret == nil {
+		return val
+	}
+	if data, ok := secret.Data[val[1:]]; ok {
+		return string(data)
+	}
+	return val
+}
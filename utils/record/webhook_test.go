@@ -0,0 +1,151 @@
+package record
+
+import (
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/argoproj/notifications-engine/pkg/api"
+	"github.com/argoproj/notifications-engine/pkg/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	tlsutil "github.com/argoproj/argo-rollouts/utils/tls"
+)
+
+func TestSignedWebhookService(t *testing.T) {
+	var requests atomic.Int32
+	statusCodes := []int{http.StatusServiceUnavailable, http.StatusOK}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := requests.Add(1) - 1
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, `{"rollout":"guestbook"}`, string(body))
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/events", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, Sign([]byte("my-secret"), body), r.Header.Get(DefaultSignatureHeader))
+		w.WriteHeader(statusCodes[i])
+	}))
+	defer server.Close()
+
+	svc, err := NewSignedWebhookService(SignedWebhookOptions{
+		URL:          server.URL,
+		Headers:      []services.Header{{Name: "Content-Type", Value: "application/json"}},
+		Secret:       "my-secret",
+		RetryWaitMin: "1ms",
+	})
+	require.NoError(t, err)
+
+	notification := services.Notification{
+		Message: "Rollout guestbook has been completed.",
+		Webhook: services.WebhookNotifications{"bus": {Path: "/events", Body: `{"rollout":"guestbook"}`}},
+	}
+	err = svc.Send(notification, services.Destination{Service: "bus"})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestSignedWebhookServiceGivesUp(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	svc, err := NewSignedWebhookService(SignedWebhookOptions{URL: server.URL, Secret: "my-secret", RetryMax: ptr.To(2), RetryWaitMin: "1ms"})
+	require.NoError(t, err)
+	err = svc.Send(services.Notification{Message: "hello"}, services.Destination{Service: "bus"})
+	assert.ErrorContains(t, err, "giving up after 3 attempts")
+	assert.ErrorContains(t, err, "status code 502")
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestSignedWebhookServiceDoesNotRetryClientErrors(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	svc, err := NewSignedWebhookService(SignedWebhookOptions{URL: server.URL, Secret: "my-secret", RetryWaitMin: "1ms"})
+	require.NoError(t, err)
+	err = svc.Send(services.Notification{Message: "hello"}, services.Destination{Service: "bus"})
+	assert.ErrorContains(t, err, "status code 401")
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestNewSignedWebhookServiceValidation(t *testing.T) {
+	_, err := NewSignedWebhookService(SignedWebhookOptions{Secret: "my-secret"})
+	assert.EqualError(t, err, "url is required")
+	_, err = NewSignedWebhookService(SignedWebhookOptions{URL: "http://bus"})
+	assert.EqualError(t, err, "secret is required")
+	_, err = NewSignedWebhookService(SignedWebhookOptions{URL: "http://bus", Secret: "my-secret", RetryMax: ptr.To(-1)})
+	assert.EqualError(t, err, "retryMax must not be negative")
+	_, err = NewSignedWebhookService(SignedWebhookOptions{URL: "http://bus", Secret: "my-secret", RetryWaitMax: "soon"})
+	assert.ErrorContains(t, err, "invalid retryWaitMax")
+}
+
+func TestAddSignedWebhookServices(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		Data: map[string]string{
+			"service.signedwebhook.bus": "url: http://bus\nsecret: $bus-secret\n",
+			"service.slack":             "token: abc",
+		},
+	}
+	secret := &corev1.Secret{Data: map[string][]byte{"bus-secret": []byte("my-secret")}}
+	cfg := &api.Config{Services: map[string]api.ServiceFactory{}}
+	require.NoError(t, addSignedWebhookServices(cfg, cm, secret))
+	require.Contains(t, cfg.Services, "bus")
+	assert.NotContains(t, cfg.Services, "slack")
+	svc, err := cfg.Services["bus"]()
+	require.NoError(t, err)
+	assert.Equal(t, "my-secret", svc.(*signedWebhookService).opts.Secret)
+
+	cm.Data["service.signedwebhook.bus"] = "url: http://bus\n"
+	assert.EqualError(t, addSignedWebhookServices(cfg, cm, secret), "invalid service bus: secret is required")
+}
+
+func TestDisableServicesWithoutOutboundTLS(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		Data: map[string]string{
+			"service.signedwebhook.bus": "url: https://bus\nsecret: my-secret\n",
+			"service.slack":             "token: abc",
+		},
+	}
+	newConfig := func() *api.Config {
+		cfg := &api.Config{Services: map[string]api.ServiceFactory{
+			"slack": func() (services.NotificationService, error) {
+				return services.NewSlackService(services.SlackOptions{Token: "abc"}), nil
+			},
+		}}
+		require.NoError(t, addSignedWebhookServices(cfg, cm, &corev1.Secret{}))
+		return cfg
+	}
+
+	// the services of the notifications engine are kept without an outbound TLS policy
+	cfg := newConfig()
+	require.NoError(t, disableServicesWithoutOutboundTLS(cfg, cm))
+	svc, err := cfg.Services["slack"]()
+	require.NoError(t, err)
+	_, unsupported := svc.(*unsupportedService)
+	assert.False(t, unsupported)
+
+	tlsutil.SetClientConfig(&tls.Config{MinVersion: tls.VersionTLS12})
+	defer tlsutil.SetClientConfig(nil)
+	cfg = newConfig()
+	require.NoError(t, disableServicesWithoutOutboundTLS(cfg, cm))
+	svc, err = cfg.Services["slack"]()
+	require.NoError(t, err)
+	err = svc.Send(services.Notification{Message: "hello"}, services.Destination{Service: "slack", Recipient: "general"})
+	assert.EqualError(t, err, "notification service slack does not support the outbound TLS policy of the controller, use a service of type signedwebhook, pagerdutyevents, opsgeniealerts instead")
+	svc, err = cfg.Services["bus"]()
+	require.NoError(t, err)
+	assert.IsType(t, &signedWebhookService{}, svc)
+}
@@ -2,13 +2,20 @@ package replicaset
 
 import (
 	"encoding/json"
+	"fmt"
+	"hash/fnv"
 	"math"
 
 	"github.com/argoproj/argo-rollouts/utils/annotations"
 
 	log "github.com/sirupsen/logrus"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/yaml"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	"github.com/argoproj/argo-rollouts/utils/defaults"
@@ -18,6 +25,8 @@ import (
 const (
 	// EphemeralMetadataAnnotation denotes pod metadata which is ephemerally injected to canary/stable pods
 	EphemeralMetadataAnnotation = annotations.RolloutLabel + "/ephemeral-metadata"
+	// PodTemplateOverlayAnnotation holds the hash of the overlay applied to the pod template of the canary
+	PodTemplateOverlayAnnotation = annotations.RolloutLabel + "/pod-template-overlay"
 )
 
 func allDesiredAreAvailable(rs *appsv1.ReplicaSet, desired int32) bool {
@@ -155,6 +164,25 @@ func CalculateReplicaCountsForBasicCanary(rollout *v1alpha1.Rollout, newRS *apps
 	totalCurrentReplicaCount := GetReplicaCountForReplicaSets(allRSs)
 	scaleUpCount := maxReplicaCountAllowed - totalCurrentReplicaCount
 
+	stableRSMissingCount := int32(0)
+	if scaleStableRS {
+		stableRSMissingCount = max(desiredStableRSReplicaCount-*stableRS.Spec.Replicas, 0)
+	}
+	newRSMissingCount := int32(0)
+	if newRS != nil {
+		newRSMissingCount = max(desiredNewRSReplicaCount-*newRS.Spec.Replicas, 0)
+	}
+	if stableRSMissingCount > 0 && newRSMissingCount > 0 && scaleUpCount > 0 && scaleUpCount < stableRSMissingCount+newRSMissingCount &&
+		replicasIncreased(stableRS, rolloutSpecReplica) {
+		// spec.replicas was increased mid-rollout, e.g. by an HPA, and both ReplicaSets have to be scaled up. The
+		// replicas which can be created are shared in proportion to the missing replicas of each ReplicaSet, so that
+		// the canary keeps its share of the pods instead of waiting for the stable to be scaled up first.
+		newRSScaleUpCount := scaleUpCount * newRSMissingCount / (stableRSMissingCount + newRSMissingCount)
+		newRSReplicaCount = *newRS.Spec.Replicas + newRSScaleUpCount
+		stableRSReplicaCount = *stableRS.Spec.Replicas + scaleUpCount - newRSScaleUpCount
+		scaleUpCount = 0
+	}
+
 	if scaleStableRS && *stableRS.Spec.Replicas < desiredStableRSReplicaCount && scaleUpCount > 0 {
 		// if the controller doesn't have to use every replica to achieve the desired count, it only scales up to the
 		// desired count.
@@ -210,6 +238,12 @@ func CalculateReplicaCountsForBasicCanary(rollout *v1alpha1.Rollout, newRS *apps
 	return newRSReplicaCount, stableRSReplicaCount
 }
 
+// replicasIncreased returns whether spec.replicas of the rollout was increased since the ReplicaSet was last scaled
+func replicasIncreased(rs *appsv1.ReplicaSet, specReplicas int32) bool {
+	desiredReplicas, ok := annotations.GetDesiredReplicasAnnotation(rs)
+	return ok && desiredReplicas < specReplicas
+}
+
 // approximateWeightedCanaryStableReplicaCounts approximates the desired canary weight and returns
 // the closest replica count values for the canary and stable to reach the desired weight. The
 // canary/stable replica counts might sum to either spec.replicas or spec.replicas + 1 but will not
@@ -600,6 +634,67 @@ func UseSetCanaryScale(rollout *v1alpha1.Rollout) *v1alpha1.SetCanaryScale {
 	return nil
 }
 
+// GetCurrentPodTemplateOverlay returns the overlay of the latest setCanaryPodTemplateOverlay step which
+// was reached. Returns nil if the canary pod template should not be overlaid, which is the case when the
+// rollout is not updating, is being promoted, or has completed all of its steps.
+func GetCurrentPodTemplateOverlay(rollout *v1alpha1.Rollout) *v1alpha1.PodTemplateOverlay {
+	if rollout.Status.PromoteFull || rollout.Status.StableRS == "" || rollout.Status.CurrentPodHash == rollout.Status.StableRS {
+		return nil
+	}
+	currentStep, currentStepIndex := GetCurrentCanaryStep(rollout)
+	if currentStep == nil {
+		return nil
+	}
+	for i := *currentStepIndex; i >= 0; i-- {
+		step := rollout.Spec.Strategy.Canary.Steps[i]
+		if step.SetCanaryPodTemplateOverlay == nil {
+			continue
+		}
+		if step.SetCanaryPodTemplateOverlay.Patch == "" {
+			return nil
+		}
+		return step.SetCanaryPodTemplateOverlay
+	}
+	return nil
+}
+
+// ComputePodTemplateOverlayHash returns a hash of the overlay patch, or an empty string if there is no overlay
+func ComputePodTemplateOverlayHash(overlay *v1alpha1.PodTemplateOverlay) string {
+	if overlay == nil || overlay.Patch == "" {
+		return ""
+	}
+	hasher := fnv.New32a()
+	_, _ = hasher.Write([]byte(overlay.Patch))
+	return rand.SafeEncodeString(fmt.Sprint(hasher.Sum32()))
+}
+
+// ApplyPodTemplateOverlay returns a copy of the pod template with the spec patched by the overlay
+func ApplyPodTemplateOverlay(template *corev1.PodTemplateSpec, overlay *v1alpha1.PodTemplateOverlay) (*corev1.PodTemplateSpec, error) {
+	result := template.DeepCopy()
+	if overlay == nil || overlay.Patch == "" {
+		return result, nil
+	}
+	patch, err := yaml.YAMLToJSON([]byte(overlay.Patch))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse pod template overlay: %w", err)
+	}
+	original, err := json.Marshal(template)
+	if err != nil {
+		return nil, err
+	}
+	patched, err := strategicpatch.StrategicMergePatch(original, patch, corev1.PodTemplateSpec{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply pod template overlay: %w", err)
+	}
+	var patchedTemplate corev1.PodTemplateSpec
+	if err := json.Unmarshal(patched, &patchedTemplate); err != nil {
+		return nil, fmt.Errorf("failed to apply pod template overlay: %w", err)
+	}
+	// only the spec is overlaid so that the metadata managed by the controller is left untouched
+	result.Spec = patchedTemplate.Spec
+	return result, nil
+}
+
 // GetOtherRSs the function goes through a list of ReplicaSets and returns a list of RS that are not the new or stable RS
 func GetOtherRSs(rollout *v1alpha1.Rollout, newRS, stableRS *appsv1.ReplicaSet, allRSs []*appsv1.ReplicaSet) []*appsv1.ReplicaSet {
 	otherRSs := []*appsv1.ReplicaSet{}
@@ -734,18 +829,107 @@ func SyncEphemeralPodMetadata(metadata *metav1.ObjectMeta, existingPodMetadata,
 	return metadata, modified
 }
 
+// EphemeralMetadataEnvVar returns the container environment variable which exposes the ephemeral label
+// or annotation using the downward API
+func EphemeralMetadataEnvVar(envVar v1alpha1.EphemeralMetadataEnvVar) corev1.EnvVar {
+	fieldPath := fmt.Sprintf("metadata.labels['%s']", envVar.Label)
+	if envVar.Annotation != "" {
+		fieldPath = fmt.Sprintf("metadata.annotations['%s']", envVar.Annotation)
+	}
+	return corev1.EnvVar{
+		Name: envVar.Name,
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{
+				APIVersion: "v1",
+				FieldPath:  fieldPath,
+			},
+		},
+	}
+}
+
+// SyncEphemeralPodEnv injects the environment variables of the desired pod metadata to the containers of
+// the pod spec, and removes previously injected variables which are no longer desired. Only variables which
+// still reference the injected label or annotation are removed, and variables defined in the pod template
+// with the same name as a desired variable are left untouched rather than overridden.
+func SyncEphemeralPodEnv(spec *corev1.PodSpec, existingPodMetadata, desiredPodMetadata *v1alpha1.PodTemplateMetadata) (*corev1.PodSpec, bool) {
+	modified := false
+	spec = spec.DeepCopy()
+
+	desired := map[string]corev1.EnvVar{}
+	if desiredPodMetadata != nil {
+		for _, envVar := range desiredPodMetadata.Env {
+			desired[envVar.Name] = EphemeralMetadataEnvVar(envVar)
+		}
+	}
+	existing := map[string]corev1.EnvVar{}
+	stale := map[string]corev1.EnvVar{}
+	if existingPodMetadata != nil {
+		for _, envVar := range existingPodMetadata.Env {
+			existing[envVar.Name] = EphemeralMetadataEnvVar(envVar)
+			if _, ok := desired[envVar.Name]; !ok {
+				stale[envVar.Name] = EphemeralMetadataEnvVar(envVar)
+			}
+		}
+	}
+	if len(desired) == 0 && len(stale) == 0 {
+		return spec, false
+	}
+
+	for i := range spec.Containers {
+		container := &spec.Containers[i]
+		env := make([]corev1.EnvVar, 0, len(container.Env)+len(desired))
+		injected := map[string]bool{}
+		for _, envVar := range container.Env {
+			if staleVar, ok := stale[envVar.Name]; ok && apiequality.Semantic.DeepEqual(envVar, staleVar) {
+				modified = true
+				continue
+			}
+			if desiredVar, ok := desired[envVar.Name]; ok {
+				existingVar, wasInjected := existing[envVar.Name]
+				if !apiequality.Semantic.DeepEqual(envVar, desiredVar) && (!wasInjected || !apiequality.Semantic.DeepEqual(envVar, existingVar)) {
+					log.Warnf("Skipping ephemeral env var %s of container %s: already defined by the pod template", envVar.Name, container.Name)
+					injected[envVar.Name] = true
+					env = append(env, envVar)
+					continue
+				}
+				if !apiequality.Semantic.DeepEqual(envVar, desiredVar) {
+					envVar = desiredVar
+					modified = true
+				}
+				injected[envVar.Name] = true
+			}
+			env = append(env, envVar)
+		}
+		if desiredPodMetadata != nil {
+			for _, envVar := range desiredPodMetadata.Env {
+				if !injected[envVar.Name] {
+					env = append(env, desired[envVar.Name])
+					modified = true
+				}
+			}
+		}
+		if len(env) == 0 {
+			env = nil
+		}
+		container.Env = env
+	}
+	return spec, modified
+}
+
 // SyncReplicaSetEphemeralPodMetadata injects the desired pod metadata to the ReplicaSet, and
 // removes previously injected metadata (based on the rollout.argoproj.io/ephemeral-metadata
 // annotation) if it is no longer desired. A podMetadata value of nil indicates all ephemeral
 // metadata should be removed completely.
 func SyncReplicaSetEphemeralPodMetadata(rs *appsv1.ReplicaSet, podMetadata *v1alpha1.PodTemplateMetadata) (*appsv1.ReplicaSet, bool) {
 	existingPodMetadata := ParseExistingPodMetadata(rs)
-	newObjectMeta, modified := SyncEphemeralPodMetadata(&rs.Spec.Template.ObjectMeta, existingPodMetadata, podMetadata)
+	newObjectMeta, metadataModified := SyncEphemeralPodMetadata(&rs.Spec.Template.ObjectMeta, existingPodMetadata, podMetadata)
+	newPodSpec, envModified := SyncEphemeralPodEnv(&rs.Spec.Template.Spec, existingPodMetadata, podMetadata)
 	rs = rs.DeepCopy()
-	if !modified {
+	if !metadataModified && !envModified {
 		return rs, false
 	}
 	rs.Spec.Template.ObjectMeta = *newObjectMeta
+	rs.Spec.Template.Spec = *newPodSpec
 	if podMetadata != nil {
 		// remember what we injected by annotating it
 		metadataBytes, _ := json.Marshal(podMetadata)
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -13,6 +14,7 @@ import (
 	"k8s.io/utils/ptr"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/annotations"
 )
 
 func newRollout(
@@ -879,6 +881,25 @@ func TestCalculateReplicaCountsForNewDeployment(t *testing.T) {
 	assert.Equal(t, int32(0), stableRSReplicaCount)
 }
 
+func TestCalculateReplicaCountsForBasicCanaryAfterScaleUp(t *testing.T) {
+	// spec.replicas was scaled from 10 to 20 while an older ReplicaSet is still scaled down
+	rollout := newRollout(20, 30, intstr.FromInt(0), intstr.FromInt(0), "canary", "stable", nil, nil)
+	stableRS := newRS("stable", 7, 7)
+	canaryRS := newRS("canary", 3, 3)
+	olderRS := newRS("older", 4, 4)
+
+	// the ReplicaSets were not scaled for 10 replicas, so the stable is scaled up first
+	newRSReplicaCount, stableRSReplicaCount := CalculateReplicaCountsForBasicCanary(rollout, canaryRS, stableRS, []*appsv1.ReplicaSet{olderRS})
+	assert.Equal(t, int32(3), newRSReplicaCount)
+	assert.Equal(t, int32(13), stableRSReplicaCount)
+
+	// the replicas which can be created are shared between the stable and the canary
+	stableRS.Annotations = map[string]string{annotations.DesiredReplicasAnnotation: "10"}
+	newRSReplicaCount, stableRSReplicaCount = CalculateReplicaCountsForBasicCanary(rollout, canaryRS, stableRS, []*appsv1.ReplicaSet{olderRS})
+	assert.Equal(t, int32(4), newRSReplicaCount)
+	assert.Equal(t, int32(12), stableRSReplicaCount)
+}
+
 func TestCalculateReplicaCountsForCanaryTrafficRouting(t *testing.T) {
 	rollout := newRollout(10, 10, intstr.FromInt(0), intstr.FromInt(1), "canary", "stable", nil, nil)
 	rollout.Spec.Strategy.Canary.TrafficRouting = &v1alpha1.RolloutTrafficRouting{}
@@ -1899,3 +1920,168 @@ func TestAllReplicaProgressThresholdMet(t *testing.T) {
 		})
 	}
 }
+
+func TestGetCurrentPodTemplateOverlay(t *testing.T) {
+	rollout := newRollout(10, 10, intstr.FromInt(0), intstr.FromInt(1), "current", "stable", nil, nil)
+	overlay := &v1alpha1.PodTemplateOverlay{Patch: "spec:\n  terminationGracePeriodSeconds: 5"}
+	rollout.Spec.Strategy.Canary.Steps = []v1alpha1.CanaryStep{
+		{SetWeight: ptr.To[int32](10)},
+		{SetCanaryPodTemplateOverlay: overlay},
+		{SetWeight: ptr.To[int32](20)},
+		{SetCanaryPodTemplateOverlay: &v1alpha1.PodTemplateOverlay{}},
+		{SetWeight: ptr.To[int32](30)},
+	}
+	expected := map[int32]*v1alpha1.PodTemplateOverlay{0: nil, 1: overlay, 2: overlay, 3: nil, 4: nil, 5: nil}
+	for index, expectedOverlay := range expected {
+		rollout.Status.CurrentStepIndex = ptr.To(index)
+		assert.Equal(t, expectedOverlay, GetCurrentPodTemplateOverlay(rollout), "step %d", index)
+	}
+
+	rollout.Status.CurrentStepIndex = ptr.To[int32](2)
+	rollout.Status.PromoteFull = true
+	assert.Nil(t, GetCurrentPodTemplateOverlay(rollout))
+	rollout.Status.PromoteFull = false
+	rollout.Status.StableRS = "current"
+	assert.Nil(t, GetCurrentPodTemplateOverlay(rollout))
+}
+
+func TestApplyPodTemplateOverlay(t *testing.T) {
+	template := &corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "foo"}},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:  "app",
+				Image: "foo:v2",
+				Env:   []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "info"}},
+			}},
+		},
+	}
+
+	overlaid, err := ApplyPodTemplateOverlay(template, nil)
+	require.NoError(t, err)
+	assert.Equal(t, template, overlaid)
+
+	overlay := &v1alpha1.PodTemplateOverlay{Patch: `
+spec:
+  containers:
+  - name: app
+    env:
+    - name: LOG_LEVEL
+      value: debug
+  - name: debug
+    image: busybox
+`}
+	overlaid, err = ApplyPodTemplateOverlay(template, overlay)
+	require.NoError(t, err)
+	require.Len(t, overlaid.Spec.Containers, 2)
+	assert.Equal(t, "foo:v2", overlaid.Spec.Containers[0].Image)
+	assert.Equal(t, []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}}, overlaid.Spec.Containers[0].Env)
+	assert.Equal(t, "busybox", overlaid.Spec.Containers[1].Image)
+	assert.Equal(t, template.Labels, overlaid.Labels)
+	// the original template is left untouched
+	assert.Len(t, template.Spec.Containers, 1)
+
+	assert.NotEmpty(t, ComputePodTemplateOverlayHash(overlay))
+	assert.Empty(t, ComputePodTemplateOverlayHash(&v1alpha1.PodTemplateOverlay{}))
+
+	_, err = ApplyPodTemplateOverlay(template, &v1alpha1.PodTemplateOverlay{Patch: "spec: ["})
+	assert.Error(t, err)
+}
+
+func TestSyncEphemeralPodEnv(t *testing.T) {
+	userVar := corev1.EnvVar{Name: "USER_VAR", Value: "foo"}
+	roleVar := v1alpha1.EphemeralMetadataEnvVar{Name: "ROLE", Label: "role"}
+	flagVar := v1alpha1.EphemeralMetadataEnvVar{Name: "FEATURE_FLAG", Annotation: "example.com/feature"}
+	spec := corev1.PodSpec{
+		Containers: []corev1.Container{
+			{Name: "app", Env: []corev1.EnvVar{userVar}},
+			{Name: "sidecar"},
+		},
+	}
+
+	// verify nothing is done without env
+	newSpec, modified := SyncEphemeralPodEnv(&spec, nil, &v1alpha1.PodTemplateMetadata{Labels: map[string]string{"role": "canary"}})
+	assert.False(t, modified)
+	assert.Equal(t, spec, *newSpec)
+
+	// verify env is injected to all containers
+	desired := &v1alpha1.PodTemplateMetadata{Env: []v1alpha1.EphemeralMetadataEnvVar{roleVar, flagVar}}
+	newSpec, modified = SyncEphemeralPodEnv(&spec, nil, desired)
+	assert.True(t, modified)
+	assert.Equal(t, []corev1.EnvVar{userVar, EphemeralMetadataEnvVar(roleVar), EphemeralMetadataEnvVar(flagVar)}, newSpec.Containers[0].Env)
+	assert.Equal(t, []corev1.EnvVar{EphemeralMetadataEnvVar(roleVar), EphemeralMetadataEnvVar(flagVar)}, newSpec.Containers[1].Env)
+	assert.Equal(t, "metadata.labels['role']", newSpec.Containers[0].Env[1].ValueFrom.FieldRef.FieldPath)
+	assert.Equal(t, "metadata.annotations['example.com/feature']", newSpec.Containers[0].Env[2].ValueFrom.FieldRef.FieldPath)
+
+	// verify applying the same env is a noop
+	injectedSpec, modified := SyncEphemeralPodEnv(newSpec, desired, desired)
+	assert.False(t, modified)
+	assert.Equal(t, newSpec, injectedSpec)
+
+	// verify an env var whose source changed is updated in place
+	updatedFlagVar := v1alpha1.EphemeralMetadataEnvVar{Name: "FEATURE_FLAG", Label: "feature"}
+	updated := &v1alpha1.PodTemplateMetadata{Env: []v1alpha1.EphemeralMetadataEnvVar{roleVar, updatedFlagVar}}
+	updatedSpec, modified := SyncEphemeralPodEnv(newSpec, desired, updated)
+	assert.True(t, modified)
+	assert.Equal(t, []corev1.EnvVar{userVar, EphemeralMetadataEnvVar(roleVar), EphemeralMetadataEnvVar(updatedFlagVar)}, updatedSpec.Containers[0].Env)
+
+	// verify env vars which are no longer desired are removed, leaving user defined vars untouched
+	removedSpec, modified := SyncEphemeralPodEnv(newSpec, desired, nil)
+	assert.True(t, modified)
+	assert.Equal(t, []corev1.EnvVar{userVar}, removedSpec.Containers[0].Env)
+	assert.Nil(t, removedSpec.Containers[1].Env)
+
+	// verify a user defined var sharing the name of a previously injected var is not removed
+	overridden := spec.DeepCopy()
+	overridden.Containers[0].Env = append(overridden.Containers[0].Env, corev1.EnvVar{Name: "ROLE", Value: "static"})
+	overriddenSpec, modified := SyncEphemeralPodEnv(overridden, desired, nil)
+	assert.False(t, modified)
+	assert.Equal(t, overridden.Containers[0].Env, overriddenSpec.Containers[0].Env)
+
+	// verify a user defined var sharing the name of a desired var is not overridden
+	conflictSpec, modified := SyncEphemeralPodEnv(overridden, nil, desired)
+	assert.True(t, modified)
+	assert.Equal(t, []corev1.EnvVar{userVar, {Name: "ROLE", Value: "static"}, EphemeralMetadataEnvVar(flagVar)}, conflictSpec.Containers[0].Env)
+	assert.Equal(t, []corev1.EnvVar{EphemeralMetadataEnvVar(roleVar), EphemeralMetadataEnvVar(flagVar)}, conflictSpec.Containers[1].Env)
+	conflictSpec, modified = SyncEphemeralPodEnv(conflictSpec, desired, updated)
+	assert.True(t, modified)
+	assert.Equal(t, []corev1.EnvVar{userVar, {Name: "ROLE", Value: "static"}, EphemeralMetadataEnvVar(updatedFlagVar)}, conflictSpec.Containers[0].Env)
+}
+
+func TestSyncReplicaSetEphemeralPodMetadataEnv(t *testing.T) {
+	rs := appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "guestbook",
+			Namespace: corev1.NamespaceDefault,
+		},
+		Spec: appsv1.ReplicaSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "app"}},
+				},
+			},
+		},
+	}
+	podMetadata := v1alpha1.PodTemplateMetadata{
+		Labels: map[string]string{"role": "canary"},
+		Env:    []v1alpha1.EphemeralMetadataEnvVar{{Name: "ROLE", Label: "role"}},
+	}
+
+	newRS, modified := SyncReplicaSetEphemeralPodMetadata(&rs, &podMetadata)
+	assert.True(t, modified)
+	assert.Equal(t, "canary", newRS.Spec.Template.Labels["role"])
+	require.Len(t, newRS.Spec.Template.Spec.Containers[0].Env, 1)
+	assert.Equal(t, "ROLE", newRS.Spec.Template.Spec.Containers[0].Env[0].Name)
+	assert.Equal(t, `{"labels":{"role":"canary"},"env":[{"name":"ROLE","label":"role"}]}`, newRS.Annotations[EphemeralMetadataAnnotation])
+
+	// verify applying the same pod-metadata is a noop
+	_, modified = SyncReplicaSetEphemeralPodMetadata(newRS, &podMetadata)
+	assert.False(t, modified)
+
+	// verify removing all ephemeral metadata also removes the injected env
+	clearedRS, modified := SyncReplicaSetEphemeralPodMetadata(newRS, nil)
+	assert.True(t, modified)
+	assert.Empty(t, clearedRS.Spec.Template.Labels)
+	assert.Nil(t, clearedRS.Spec.Template.Spec.Containers[0].Env)
+	assert.NotContains(t, clearedRS.Annotations, EphemeralMetadataAnnotation)
+}
@@ -0,0 +1,47 @@
+package replicaset
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	register "github.com/argoproj/argo-rollouts/pkg/apis/rollouts"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+)
+
+// CompactReplicaSet is an informer transform which drops the pod template spec and the managed fields of the
+// scaled down ReplicaSets of Rollouts before they are stored in the informer cache. This reduces the memory usage of
+// the controller on clusters with deep revision histories, since the old ReplicaSets of a Rollout are only told
+// apart by their pod template hash. ReplicaSets carrying ephemeral metadata are kept intact, since the injected
+// metadata has to be removed from their pod template spec. A compacted ReplicaSet must be hydrated from the API
+// server before its pod template spec is read or the ReplicaSet is updated.
+func CompactReplicaSet(obj any) (any, error) {
+	rs, ok := obj.(*appsv1.ReplicaSet)
+	if !ok || !isCompactable(rs) {
+		return obj, nil
+	}
+	rs.ManagedFields = nil
+	rs.Spec.Template.Spec = corev1.PodSpec{}
+	return rs, nil
+}
+
+func isCompactable(rs *appsv1.ReplicaSet) bool {
+	if rs.Spec.Replicas == nil || *rs.Spec.Replicas != 0 || rs.Status.Replicas != 0 {
+		return false
+	}
+	if _, ok := rs.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]; !ok {
+		return false
+	}
+	if _, ok := rs.Annotations[EphemeralMetadataAnnotation]; ok {
+		return false
+	}
+	ownerRef := metav1.GetControllerOf(rs)
+	return ownerRef != nil && ownerRef.Kind == register.RolloutKind
+}
+
+// IsCompacted returns whether the pod template spec of the ReplicaSet was dropped by CompactReplicaSet. A ReplicaSet
+// accepted by the API server always has at least one container.
+func IsCompacted(rs *appsv1.ReplicaSet) bool {
+	return defaults.GetCompactReplicaSetHistory() && rs != nil && len(rs.Spec.Template.Spec.Containers) == 0
+}
@@ -0,0 +1,92 @@
+package replicaset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+)
+
+func newCompactableReplicaSet() *appsv1.ReplicaSet {
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo-abc123",
+			Namespace: metav1.NamespaceDefault,
+			Labels:    map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: "abc123"},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "argoproj.io/v1alpha1",
+				Kind:       "Rollout",
+				Name:       "foo",
+				Controller: ptr.To(true),
+			}},
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "rollouts-controller"}},
+		},
+		Spec: appsv1.ReplicaSetSpec{
+			Replicas: ptr.To[int32](0),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: "abc123"},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "foo", Image: "foo:v1"}},
+				},
+			},
+		},
+	}
+}
+
+func TestCompactReplicaSet(t *testing.T) {
+	defaults.SetCompactReplicaSetHistory(true)
+	defer defaults.SetCompactReplicaSetHistory(false)
+
+	rs := newCompactableReplicaSet()
+	assert.False(t, IsCompacted(rs))
+	obj, err := CompactReplicaSet(rs)
+	assert.NoError(t, err)
+	compactedRS := obj.(*appsv1.ReplicaSet)
+	assert.True(t, IsCompacted(compactedRS))
+	assert.Nil(t, compactedRS.ManagedFields)
+	assert.Equal(t, "abc123", compactedRS.Spec.Template.Labels[v1alpha1.DefaultRolloutUniqueLabelKey])
+	assert.Equal(t, "abc123", GetPodTemplateHash(compactedRS))
+}
+
+func TestCompactReplicaSetSkipsReplicaSets(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(rs *appsv1.ReplicaSet)
+	}{
+		{name: "scaled up", modify: func(rs *appsv1.ReplicaSet) { rs.Spec.Replicas = ptr.To[int32](1) }},
+		{name: "pods still running", modify: func(rs *appsv1.ReplicaSet) { rs.Status.Replicas = 1 }},
+		{name: "no pod template hash", modify: func(rs *appsv1.ReplicaSet) { delete(rs.Labels, v1alpha1.DefaultRolloutUniqueLabelKey) }},
+		{name: "ephemeral metadata", modify: func(rs *appsv1.ReplicaSet) {
+			rs.Annotations = map[string]string{EphemeralMetadataAnnotation: "{}"}
+		}},
+		{name: "not owned by a rollout", modify: func(rs *appsv1.ReplicaSet) { rs.OwnerReferences[0].Kind = "Deployment" }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rs := newCompactableReplicaSet()
+			test.modify(rs)
+			obj, err := CompactReplicaSet(rs)
+			assert.NoError(t, err)
+			assert.Len(t, obj.(*appsv1.ReplicaSet).Spec.Template.Spec.Containers, 1)
+		})
+	}
+
+	obj, err := CompactReplicaSet(&corev1.Pod{})
+	assert.NoError(t, err)
+	assert.Equal(t, &corev1.Pod{}, obj)
+}
+
+func TestIsCompactedWithoutCompaction(t *testing.T) {
+	rs := newCompactableReplicaSet()
+	rs.Spec.Template.Spec = corev1.PodSpec{}
+	assert.False(t, IsCompacted(rs))
+	assert.False(t, IsCompacted(nil))
+}
@@ -3,8 +3,10 @@ package replicaset
 import (
 	"context"
 	"fmt"
+	"math"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -60,9 +62,10 @@ func FindNewReplicaSet(rollout *v1alpha1.Rollout, rsList []*appsv1.ReplicaSet) *
 	for _, rs := range rsList {
 		// Remove injected canary/stable metadata from spec.template.metadata before comparing
 		rsCopy, _ := SyncReplicaSetEphemeralPodMetadata(rs, nil)
-		// Remove anti-affinity from template.Spec.Affinity before comparing
+		// Remove anti-affinity and injected topology spread constraints before comparing
 		live := &rsCopy.Spec.Template
 		live.Spec.Affinity = RemoveInjectedAntiAffinityRule(live.Spec.Affinity, *rollout)
+		live.Spec.TopologySpreadConstraints = RemoveInjectedTopologySpreadConstraints(live.Spec.TopologySpreadConstraints, *rollout)
 
 		desired := rollout.Spec.Template.DeepCopy()
 		if PodTemplateEqualIgnoreHash(live, desired) {
@@ -220,6 +223,109 @@ func IfInjectedAntiAffinityRuleNeedsUpdate(affinity *corev1.Affinity, rollout v1
 	return false
 }
 
+// GenerateReplicaSetTopologySpreadConstraints returns the topologySpreadConstraints of the rollout pod
+// template along with the constraints which spread the new pods with the pods of the stable ReplicaSet
+func GenerateReplicaSetTopologySpreadConstraints(rollout v1alpha1.Rollout) []corev1.TopologySpreadConstraint {
+	antiAffinityStrategy := GetRolloutAffinity(rollout)
+	currentPodHash := hash.ComputePodTemplateHash(&rollout.Spec.Template, rollout.Status.CollisionCount)
+	constraints := rollout.Spec.Template.DeepCopy().Spec.TopologySpreadConstraints
+	if antiAffinityStrategy == nil || antiAffinityStrategy.TopologySpread == nil || rollout.Status.StableRS == "" || rollout.Status.StableRS == currentPodHash {
+		return constraints
+	}
+	return append(constraints, CreateInjectedTopologySpreadConstraints(rollout)...)
+}
+
+// CreateInjectedTopologySpreadConstraints creates a constraint for every topology key which selects the
+// pods of both the stable and the new ReplicaSet, so that the scheduler places new pods away from the
+// domains which are already crowded by stable pods
+func CreateInjectedTopologySpreadConstraints(rollout v1alpha1.Rollout) []corev1.TopologySpreadConstraint {
+	topologySpread := GetRolloutAffinity(rollout).TopologySpread
+	topologyKeys := topologySpread.TopologyKeys
+	if len(topologyKeys) == 0 {
+		topologyKeys = []string{"kubernetes.io/hostname"}
+	}
+	maxSkew := int32(1)
+	if topologySpread.MaxSkew != nil {
+		maxSkew = *topologySpread.MaxSkew
+	}
+	whenUnsatisfiable := topologySpread.WhenUnsatisfiable
+	if whenUnsatisfiable == "" {
+		whenUnsatisfiable = corev1.ScheduleAnyway
+	}
+	currentPodHash := hash.ComputePodTemplateHash(&rollout.Spec.Template, rollout.Status.CollisionCount)
+
+	constraints := make([]corev1.TopologySpreadConstraint, 0, len(topologyKeys))
+	for _, topologyKey := range topologyKeys {
+		selector := &metav1.LabelSelector{}
+		if rollout.Spec.Selector != nil {
+			selector = rollout.Spec.Selector.DeepCopy()
+		}
+		selector.MatchExpressions = append(selector.MatchExpressions, metav1.LabelSelectorRequirement{
+			Key:      v1alpha1.DefaultRolloutUniqueLabelKey,
+			Operator: metav1.LabelSelectorOpIn,
+			// Most recent stable ReplicaSet and the new ReplicaSet
+			Values: []string{rollout.Status.StableRS, currentPodHash},
+		})
+		constraints = append(constraints, corev1.TopologySpreadConstraint{
+			MaxSkew:           maxSkew,
+			TopologyKey:       topologyKey,
+			WhenUnsatisfiable: whenUnsatisfiable,
+			LabelSelector:     selector,
+		})
+	}
+	return constraints
+}
+
+// isInjectedTopologySpreadConstraint returns true if the constraint has the shape of the constraints created by
+// CreateInjectedTopologySpreadConstraints and is not one of the constraints of the rollout pod template, so that
+// user-defined constraints selecting on the pod template hash are left untouched
+func isInjectedTopologySpreadConstraint(constraint corev1.TopologySpreadConstraint, rollout v1alpha1.Rollout) bool {
+	if constraint.LabelSelector == nil || len(constraint.LabelSelector.MatchExpressions) == 0 {
+		return false
+	}
+	requirement := constraint.LabelSelector.MatchExpressions[len(constraint.LabelSelector.MatchExpressions)-1]
+	if requirement.Key != v1alpha1.DefaultRolloutUniqueLabelKey || requirement.Operator != metav1.LabelSelectorOpIn || len(requirement.Values) != 2 {
+		return false
+	}
+	for _, templateConstraint := range rollout.Spec.Template.Spec.TopologySpreadConstraints {
+		if apiequality.Semantic.DeepEqual(constraint, templateConstraint) {
+			return false
+		}
+	}
+	return true
+}
+
+// RemoveInjectedTopologySpreadConstraints returns the topologySpreadConstraints without the constraints
+// injected by the controller
+func RemoveInjectedTopologySpreadConstraints(constraints []corev1.TopologySpreadConstraint, rollout v1alpha1.Rollout) []corev1.TopologySpreadConstraint {
+	var remaining []corev1.TopologySpreadConstraint
+	for _, constraint := range constraints {
+		if !isInjectedTopologySpreadConstraint(constraint, rollout) {
+			remaining = append(remaining, *constraint.DeepCopy())
+		}
+	}
+	return remaining
+}
+
+// IfInjectedTopologySpreadConstraintsNeedUpdate returns true if the injected topologySpreadConstraints
+// no longer select the current stable ReplicaSet
+func IfInjectedTopologySpreadConstraintsNeedUpdate(constraints []corev1.TopologySpreadConstraint, rollout v1alpha1.Rollout) bool {
+	currentPodHash := hash.ComputePodTemplateHash(&rollout.Spec.Template, rollout.Status.CollisionCount)
+	if rollout.Status.StableRS == currentPodHash {
+		return false
+	}
+	for _, constraint := range constraints {
+		if !isInjectedTopologySpreadConstraint(constraint, rollout) {
+			continue
+		}
+		requirement := constraint.LabelSelector.MatchExpressions[len(constraint.LabelSelector.MatchExpressions)-1]
+		if requirement.Values[0] != rollout.Status.StableRS {
+			return true
+		}
+	}
+	return false
+}
+
 func NeedsRestart(rollout *v1alpha1.Rollout) bool {
 	now := timeutil.MetaNow().UTC()
 	if rollout.Spec.RestartAt == nil {
@@ -244,6 +350,41 @@ func FindOldReplicaSets(rollout *v1alpha1.Rollout, rsList []*appsv1.ReplicaSet,
 	return allRSs
 }
 
+// GetPreviewReplicaCount returns the number of replicas the preview stack of a blue-green rollout
+// should run before the switchover. It returns nil when neither previewReplicaCount nor
+// previewReplicaProfile is used, in which case the preview stack is fully scaled.
+// The previewReplicaProfile is a percentage of spec.replicas, which a HorizontalPodAutoscaler adjusts, so
+// that the preview stack follows the autoscaled size without depending on the replicas running at the time.
+// The preview stack of a profile always has at least one replica.
+func GetPreviewReplicaCount(rollout *v1alpha1.Rollout) *int32 {
+	blueGreen := rollout.Spec.Strategy.BlueGreen
+	if blueGreen == nil {
+		return nil
+	}
+	if blueGreen.PreviewReplicaCount != nil {
+		return blueGreen.PreviewReplicaCount
+	}
+	profile := blueGreen.PreviewReplicaProfile
+	if profile == nil {
+		return nil
+	}
+	desiredReplicas := defaults.GetReplicasOrDefault(rollout.Spec.Replicas)
+	previewReplicas := int32(math.Ceil(float64(desiredReplicas) * float64(profile.Percent) / 100.0))
+	if previewReplicas < 1 {
+		previewReplicas = 1
+	}
+	if profile.MinReplicas != nil && previewReplicas < *profile.MinReplicas {
+		previewReplicas = *profile.MinReplicas
+	}
+	if profile.MaxReplicas != nil && previewReplicas > *profile.MaxReplicas {
+		previewReplicas = *profile.MaxReplicas
+	}
+	if previewReplicas > desiredReplicas {
+		previewReplicas = desiredReplicas
+	}
+	return &previewReplicas
+}
+
 // NewRSNewReplicas calculates the number of replicas a Rollout's new RS should have.
 // When one of the followings is true, we're rolling out the deployment; otherwise, we're scaling it.
 // 1) The new RS is saturated: newRS's replicas == deployment's replicas
@@ -251,7 +392,7 @@ func FindOldReplicaSets(rollout *v1alpha1.Rollout, rsList []*appsv1.ReplicaSet,
 func NewRSNewReplicas(rollout *v1alpha1.Rollout, allRSs []*appsv1.ReplicaSet, newRS *appsv1.ReplicaSet, weights *v1alpha1.TrafficWeights) (int32, error) {
 	if rollout.Spec.Strategy.BlueGreen != nil {
 		desiredReplicas := defaults.GetReplicasOrDefault(rollout.Spec.Replicas)
-		if rollout.Spec.Strategy.BlueGreen.PreviewReplicaCount != nil {
+		if previewReplicaCount := GetPreviewReplicaCount(rollout); previewReplicaCount != nil {
 			activeRS, _ := GetReplicaSetByTemplateHash(allRSs, rollout.Status.BlueGreen.ActiveSelector)
 			if activeRS == nil || activeRS.Name == newRS.Name {
 				// the active RS is our desired RS. we are already past the blue-green promote step
@@ -264,7 +405,7 @@ func NewRSNewReplicas(rollout *v1alpha1.Rollout, allRSs []*appsv1.ReplicaSet, ne
 			if newRS.Labels[v1alpha1.DefaultRolloutUniqueLabelKey] != rollout.Status.CurrentPodHash {
 				// the desired RS is not equal to our previously recorded current RS.
 				// This must be a new update, so return previewReplicaCount
-				return *rollout.Spec.Strategy.BlueGreen.PreviewReplicaCount, nil
+				return *previewReplicaCount, nil
 			}
 			isNotPaused := !rollout.Spec.Paused && len(rollout.Status.PauseConditions) == 0
 			if isNotPaused && rollout.Status.BlueGreen.ScaleUpPreviewCheckPoint {
@@ -273,7 +414,7 @@ func NewRSNewReplicas(rollout *v1alpha1.Rollout, allRSs []*appsv1.ReplicaSet, ne
 				// active service switch to the desired RS.
 				return desiredReplicas, nil
 			}
-			return *rollout.Spec.Strategy.BlueGreen.PreviewReplicaCount, nil
+			return *previewReplicaCount, nil
 		}
 		return desiredReplicas, nil
 	}
@@ -447,6 +588,22 @@ func MaxUnavailable(rollout *v1alpha1.Rollout) int32 {
 	return maxUnavailable
 }
 
+// RestartBatchSize returns the maximum number of pods which are restarted at a time. Without a
+// restart strategy or batch size, this is maxUnavailable or 1, whichever is greater.
+func RestartBatchSize(rollout *v1alpha1.Rollout) int32 {
+	batchSize := MaxUnavailable(rollout)
+	if rollout.Spec.RestartStrategy != nil && rollout.Spec.RestartStrategy.BatchSize != nil {
+		rolloutReplicas := defaults.GetReplicasOrDefault(rollout.Spec.Replicas)
+		// Error caught by validation
+		value, _ := intstrutil.GetScaledValueFromIntOrPercent(rollout.Spec.RestartStrategy.BatchSize, int(rolloutReplicas), true)
+		batchSize = int32(value)
+	}
+	if batchSize < 1 {
+		return 1
+	}
+	return batchSize
+}
+
 // MaxSurge returns the maximum surge pods a rolling deployment can take.
 func MaxSurge(rollout *v1alpha1.Rollout) int32 {
 	rolloutReplicas := defaults.GetReplicasOrDefault(rollout.Spec.Replicas)
@@ -543,9 +700,35 @@ func PodTemplateEqualIgnoreHash(live, desired *corev1.PodTemplateSpec) bool {
 	desired.Spec.DeprecatedServiceAccount = ""
 	live.Spec.DeprecatedServiceAccount = ""
 
+	// Do not allow the digests pinned by the image verification to factor into the equality check
+	unpinImageDigests(live.Spec.InitContainers, desired.Spec.InitContainers)
+	unpinImageDigests(live.Spec.Containers, desired.Spec.Containers)
+
 	return apiequality.Semantic.DeepEqual(live, desired)
 }
 
+// PinImageDigest returns the image referenced by the digest, e.g. ghcr.io/org/app:v1@sha256:..., unless it is already
+// referenced by a digest. The tag is kept, which the container runtimes ignore in favor of the digest.
+func PinImageDigest(image, digest string) string {
+	if digest == "" || strings.Contains(image, "@") {
+		return image
+	}
+	return image + "@" + digest
+}
+
+// unpinImageDigests restores the images of the live containers which were pinned to a digest of the image of the
+// desired container
+func unpinImageDigests(live, desired []corev1.Container) {
+	for i := range live {
+		if i >= len(desired) || strings.Contains(desired[i].Image, "@") {
+			continue
+		}
+		if image, _, found := strings.Cut(live[i].Image, "@"); found && image == desired[i].Image {
+			live[i].Image = desired[i].Image
+		}
+	}
+}
+
 // GetPodTemplateHash returns the rollouts-pod-template-hash value from a ReplicaSet's labels
 func GetPodTemplateHash(rs *appsv1.ReplicaSet) string {
 	if rs == nil || rs.Labels == nil {
@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -16,6 +18,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
+	corev1defaults "k8s.io/kubernetes/pkg/apis/core/v1"
 	"k8s.io/kubernetes/pkg/controller"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/yaml"
@@ -327,6 +330,92 @@ func TestNewRSNewReplicasWitPreviewReplicaCount(t *testing.T) {
 
 }
 
+func TestGetPreviewReplicaCount(t *testing.T) {
+	tests := []struct {
+		name        string
+		replicas    int32
+		hpaReplicas int32
+		blueGreen   *v1alpha1.BlueGreenStrategy
+		expected    *int32
+	}{
+		{
+			name:      "Neither previewReplicaCount nor previewReplicaProfile is set",
+			replicas:  10,
+			blueGreen: &v1alpha1.BlueGreenStrategy{},
+		},
+		{
+			name:      "previewReplicaCount is used as is",
+			replicas:  10,
+			blueGreen: &v1alpha1.BlueGreenStrategy{PreviewReplicaCount: ptr.To[int32](3)},
+			expected:  ptr.To[int32](3),
+		},
+		{
+			name:      "Percent rounds up",
+			replicas:  5,
+			blueGreen: &v1alpha1.BlueGreenStrategy{PreviewReplicaProfile: &v1alpha1.PreviewReplicaProfile{Percent: 50}},
+			expected:  ptr.To[int32](3),
+		},
+		{
+			name:     "Percent is bounded by minReplicas",
+			replicas: 10,
+			blueGreen: &v1alpha1.BlueGreenStrategy{PreviewReplicaProfile: &v1alpha1.PreviewReplicaProfile{
+				Percent:     10,
+				MinReplicas: ptr.To[int32](2),
+			}},
+			expected: ptr.To[int32](2),
+		},
+		{
+			name:     "Percent is bounded by maxReplicas",
+			replicas: 100,
+			blueGreen: &v1alpha1.BlueGreenStrategy{PreviewReplicaProfile: &v1alpha1.PreviewReplicaProfile{
+				Percent:     50,
+				MaxReplicas: ptr.To[int32](20),
+			}},
+			expected: ptr.To[int32](20),
+		},
+		{
+			name:     "Never exceeds the desired replicas",
+			replicas: 2,
+			blueGreen: &v1alpha1.BlueGreenStrategy{PreviewReplicaProfile: &v1alpha1.PreviewReplicaProfile{
+				Percent:     50,
+				MinReplicas: ptr.To[int32](5),
+			}},
+			expected: ptr.To[int32](2),
+		},
+		{
+			name:      "Percent of zero runs a single replica",
+			replicas:  10,
+			blueGreen: &v1alpha1.BlueGreenStrategy{PreviewReplicaProfile: &v1alpha1.PreviewReplicaProfile{Percent: 0}},
+			expected:  ptr.To[int32](1),
+		},
+		{
+			name:        "Percent ignores the replicas observed while the pods churn",
+			replicas:    20,
+			hpaReplicas: 10,
+			blueGreen:   &v1alpha1.BlueGreenStrategy{PreviewReplicaProfile: &v1alpha1.PreviewReplicaProfile{Percent: 50}},
+			expected:    ptr.To[int32](10),
+		},
+		{
+			name:      "No preview replicas when the rollout is scaled to zero",
+			replicas:  0,
+			blueGreen: &v1alpha1.BlueGreenStrategy{PreviewReplicaProfile: &v1alpha1.PreviewReplicaProfile{Percent: 50}},
+			expected:  ptr.To[int32](0),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ro := &v1alpha1.Rollout{
+				Spec: v1alpha1.RolloutSpec{
+					Replicas: ptr.To(test.replicas),
+					Strategy: v1alpha1.RolloutStrategy{BlueGreen: test.blueGreen},
+				},
+				Status: v1alpha1.RolloutStatus{HPAReplicas: test.hpaReplicas},
+			}
+			assert.Equal(t, test.expected, GetPreviewReplicaCount(ro))
+		})
+	}
+}
+
 func TestRevision(t *testing.T) {
 	rs := &appsv1.ReplicaSet{
 		ObjectMeta: metav1.ObjectMeta{
@@ -592,6 +681,36 @@ func TestMaxSurge(t *testing.T) {
 	}
 }
 
+func TestRestartBatchSize(t *testing.T) {
+	maxUnavailable := intstr.FromInt(2)
+	ro := &v1alpha1.Rollout{
+		Spec: v1alpha1.RolloutSpec{
+			Replicas: ptr.To[int32](10),
+			Strategy: v1alpha1.RolloutStrategy{
+				Canary: &v1alpha1.CanaryStrategy{MaxUnavailable: &maxUnavailable},
+			},
+		},
+	}
+	// defaults to maxUnavailable
+	assert.Equal(t, int32(2), RestartBatchSize(ro))
+
+	ro.Spec.RestartStrategy = &v1alpha1.RolloutRestartStrategy{}
+	assert.Equal(t, int32(2), RestartBatchSize(ro))
+
+	batchSize := intstr.FromString("25%")
+	ro.Spec.RestartStrategy.BatchSize = &batchSize
+	assert.Equal(t, int32(3), RestartBatchSize(ro))
+
+	batchSize = intstr.FromInt(4)
+	assert.Equal(t, int32(4), RestartBatchSize(ro))
+
+	// restarts at least one pod at a time
+	zero := intstr.FromInt(0)
+	ro.Spec.RestartStrategy = nil
+	ro.Spec.Strategy.Canary.MaxUnavailable = &zero
+	assert.Equal(t, int32(1), RestartBatchSize(ro))
+}
+
 func TestMaxUnavailable(t *testing.T) {
 	rollout := func(replicas int32, maxUnavailable intstr.IntOrString) *v1alpha1.Rollout {
 		return &v1alpha1.Rollout{
@@ -911,6 +1030,87 @@ func TestGenerateReplicaSetAffinity(t *testing.T) {
 	assert.Len(t, affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution, 2)
 }
 
+func TestGenerateReplicaSetTopologySpreadConstraints(t *testing.T) {
+	ro := generateRollout("nginx")
+	userConstraint := corev1.TopologySpreadConstraint{
+		MaxSkew:           2,
+		TopologyKey:       "topology.kubernetes.io/region",
+		WhenUnsatisfiable: corev1.DoNotSchedule,
+	}
+	ro.Spec.Template.Spec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{userConstraint}
+	ro.Spec.Strategy.Canary = &v1alpha1.CanaryStrategy{
+		AntiAffinity: &v1alpha1.AntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &v1alpha1.RequiredDuringSchedulingIgnoredDuringExecution{},
+		},
+	}
+	currentPodHash := hash.ComputePodTemplateHash(&ro.Spec.Template, nil)
+	ro.Status.StableRS = "test"
+
+	// topologySpread not enabled
+	assert.Equal(t, []corev1.TopologySpreadConstraint{userConstraint}, GenerateReplicaSetTopologySpreadConstraints(ro))
+
+	// StableRS is equal to CurrentPodHash
+	ro.Spec.Strategy.Canary.AntiAffinity.TopologySpread = &v1alpha1.AntiAffinityTopologySpread{}
+	ro.Status.StableRS = currentPodHash
+	assert.Equal(t, []corev1.TopologySpreadConstraint{userConstraint}, GenerateReplicaSetTopologySpreadConstraints(ro))
+
+	// Injects a constraint with the defaults next to the existing constraints
+	ro.Status.StableRS = "test"
+	constraints := GenerateReplicaSetTopologySpreadConstraints(ro)
+	require.Len(t, constraints, 2)
+	assert.Equal(t, userConstraint, constraints[0])
+	assert.Equal(t, int32(1), constraints[1].MaxSkew)
+	assert.Equal(t, "kubernetes.io/hostname", constraints[1].TopologyKey)
+	assert.Equal(t, corev1.ScheduleAnyway, constraints[1].WhenUnsatisfiable)
+	assert.Equal(t, ro.Spec.Selector.MatchLabels, constraints[1].LabelSelector.MatchLabels)
+	assert.Equal(t, []metav1.LabelSelectorRequirement{{
+		Key:      v1alpha1.DefaultRolloutUniqueLabelKey,
+		Operator: metav1.LabelSelectorOpIn,
+		Values:   []string{"test", currentPodHash},
+	}}, constraints[1].LabelSelector.MatchExpressions)
+	assert.Len(t, ro.Spec.Template.Spec.TopologySpreadConstraints, 1)
+
+	// Injects a constraint per topology key
+	ro.Spec.Strategy.Canary.AntiAffinity.TopologySpread = &v1alpha1.AntiAffinityTopologySpread{
+		TopologyKeys:      []string{"kubernetes.io/hostname", "topology.kubernetes.io/zone"},
+		MaxSkew:           ptr.To[int32](3),
+		WhenUnsatisfiable: corev1.DoNotSchedule,
+	}
+	constraints = GenerateReplicaSetTopologySpreadConstraints(ro)
+	require.Len(t, constraints, 3)
+	assert.Equal(t, "topology.kubernetes.io/zone", constraints[2].TopologyKey)
+	assert.Equal(t, int32(3), constraints[2].MaxSkew)
+	assert.Equal(t, corev1.DoNotSchedule, constraints[2].WhenUnsatisfiable)
+
+	assert.Equal(t, []corev1.TopologySpreadConstraint{userConstraint}, RemoveInjectedTopologySpreadConstraints(constraints, ro))
+
+	assert.False(t, IfInjectedTopologySpreadConstraintsNeedUpdate(constraints, ro))
+	ro.Status.StableRS = "new-stable"
+	assert.True(t, IfInjectedTopologySpreadConstraintsNeedUpdate(constraints, ro))
+	ro.Status.StableRS = currentPodHash
+	assert.False(t, IfInjectedTopologySpreadConstraintsNeedUpdate(constraints, ro))
+
+	// User-defined constraints selecting on the pod template hash are not removed
+	hashConstraint := corev1.TopologySpreadConstraint{
+		MaxSkew:           1,
+		TopologyKey:       "kubernetes.io/hostname",
+		WhenUnsatisfiable: corev1.ScheduleAnyway,
+		LabelSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{
+				Key:      v1alpha1.DefaultRolloutUniqueLabelKey,
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   []string{"a", "b"},
+			}},
+		},
+	}
+	ro.Spec.Template.Spec.TopologySpreadConstraints = append(ro.Spec.Template.Spec.TopologySpreadConstraints, hashConstraint)
+	ro.Status.StableRS = "new-stable"
+	constraints = append(ro.Spec.Template.Spec.TopologySpreadConstraints, CreateInjectedTopologySpreadConstraints(ro)...)
+	require.Len(t, constraints, 4)
+	assert.Equal(t, []corev1.TopologySpreadConstraint{userConstraint, hashConstraint}, RemoveInjectedTopologySpreadConstraints(constraints, ro))
+	assert.False(t, IfInjectedTopologySpreadConstraintsNeedUpdate(constraints, ro))
+}
+
 func TestCreateInjectedAntiAffinityRule(t *testing.T) {
 	ro := generateRollout("nginx")
 	ro.Status.StableRS = "test"
@@ -1240,6 +1440,24 @@ spec:
 	assert.True(t, PodTemplateEqualIgnoreHash(&live, &desired))
 }
 
+func TestPodTemplateEqualIgnoreHashWithPinnedImages(t *testing.T) {
+	desired := corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "nginx:1.19-alpine"}}}}
+	digest := "sha256:" + strings.Repeat("a", 64)
+	// the live template is defaulted by the API server
+	podTemplate := corev1.PodTemplate{Template: *desired.DeepCopy()}
+	corev1defaults.SetObjectDefaults_PodTemplate(&podTemplate)
+	live := &podTemplate.Template
+	live.Spec.Containers[0].Image = PinImageDigest("nginx:1.19-alpine", digest)
+	assert.Equal(t, "nginx:1.19-alpine@"+digest, live.Spec.Containers[0].Image)
+	assert.True(t, PodTemplateEqualIgnoreHash(live, &desired))
+
+	// images which are already referenced by digest are not pinned
+	assert.Equal(t, "nginx@"+digest, PinImageDigest("nginx@"+digest, "sha256:"+strings.Repeat("b", 64)))
+
+	live.Spec.Containers[0].Image = "nginx:1.20-alpine@" + digest
+	assert.False(t, PodTemplateEqualIgnoreHash(live, &desired))
+}
+
 func TestIsReplicaSetAvailable(t *testing.T) {
 	{
 		assert.False(t, IsReplicaSetAvailable(nil))
@@ -131,37 +131,82 @@ func isWorkloadGenerationObserved(ro *v1alpha1.Rollout) bool {
 // by clients). Clients should instead call GetRolloutPhase, which takes into consideration
 // status.observedGeneration
 func CalculateRolloutPhase(spec v1alpha1.RolloutSpec, status v1alpha1.RolloutStatus) (v1alpha1.RolloutPhase, string) {
+	phase, _, message := calculateRolloutPhase(spec, status)
+	return phase, message
+}
+
+// CalculateRolloutHealth summarizes the health and the progress of a rollout based on its spec and status, in a
+// stable format which GitOps tools such as Argo CD can rely on
+func CalculateRolloutHealth(spec v1alpha1.RolloutSpec, status v1alpha1.RolloutStatus) *v1alpha1.RolloutHealth {
+	phase, reason, _ := calculateRolloutPhase(spec, status)
+	health := &v1alpha1.RolloutHealth{Reason: reason}
+	switch phase {
+	case v1alpha1.RolloutPhaseHealthy:
+		health.Status = v1alpha1.RolloutHealthStatusHealthy
+	case v1alpha1.RolloutPhasePaused:
+		health.Status = v1alpha1.RolloutHealthStatusSuspended
+	case v1alpha1.RolloutPhaseDegraded:
+		health.Status = v1alpha1.RolloutHealthStatusDegraded
+	default:
+		health.Status = v1alpha1.RolloutHealthStatusProgressing
+	}
+	if phase == v1alpha1.RolloutPhaseHealthy {
+		health.PercentComplete = 100
+		return health
+	}
+
+	replicas := defaults.GetReplicasOrDefault(spec.Replicas)
+	if spec.Strategy.Canary != nil && len(spec.Strategy.Canary.Steps) > 0 && status.CurrentStepIndex != nil {
+		steps := spec.Strategy.Canary.Steps
+		index := *status.CurrentStepIndex
+		if int(index) < len(steps) {
+			health.CurrentStep = CanaryStepString(steps[index])
+		}
+		health.PercentComplete = index * 100 / int32(len(steps))
+	} else if replicas > 0 {
+		health.PercentComplete = min(status.UpdatedReplicas, replicas) * 100 / replicas
+	}
+	// the update is only complete once the rollout is healthy
+	health.PercentComplete = min(health.PercentComplete, 99)
+	return health
+}
+
+// calculateRolloutPhase calculates the phase of a rollout, along with a CamelCase reason and a message
+func calculateRolloutPhase(spec v1alpha1.RolloutSpec, status v1alpha1.RolloutStatus) (v1alpha1.RolloutPhase, string, string) {
 	ro := v1alpha1.Rollout{
 		Spec:   spec,
 		Status: status,
 	}
 	for _, cond := range ro.Status.Conditions {
 		if cond.Type == v1alpha1.InvalidSpec {
-			return v1alpha1.RolloutPhaseDegraded, fmt.Sprintf("%s: %s", v1alpha1.InvalidSpec, cond.Message)
+			return v1alpha1.RolloutPhaseDegraded, string(v1alpha1.InvalidSpec), fmt.Sprintf("%s: %s", v1alpha1.InvalidSpec, cond.Message)
 		}
 		switch cond.Reason {
 		case conditions.RolloutAbortedReason, conditions.TimedOutReason:
-			return v1alpha1.RolloutPhaseDegraded, fmt.Sprintf("%s: %s", cond.Reason, cond.Message)
+			return v1alpha1.RolloutPhaseDegraded, cond.Reason, fmt.Sprintf("%s: %s", cond.Reason, cond.Message)
 		}
 	}
 	if ro.Spec.Paused {
-		return v1alpha1.RolloutPhasePaused, "manually paused"
+		return v1alpha1.RolloutPhasePaused, "ManuallyPaused", "manually paused"
+	}
+	if cond := conditions.GetRolloutCondition(ro.Status, v1alpha1.RolloutProgressing); cond != nil && cond.Reason == conditions.RolloutProgressionPendingReason {
+		return v1alpha1.RolloutPhaseProgressing, cond.Reason, fmt.Sprintf("%s: %s", cond.Reason, cond.Message)
 	}
 	for _, pauseCond := range ro.Status.PauseConditions {
-		return v1alpha1.RolloutPhasePaused, string(pauseCond.Reason)
+		return v1alpha1.RolloutPhasePaused, string(pauseCond.Reason), string(pauseCond.Reason)
 	}
 	if ro.Spec.RestartAt != nil && (ro.Status.RestartedAt == nil || !ro.Spec.RestartAt.Time.Equal(ro.Status.RestartedAt.Time)) {
-		return v1alpha1.RolloutPhaseProgressing, "rollout is restarting"
+		return v1alpha1.RolloutPhaseProgressing, "Restarting", "rollout is restarting"
 	}
 	if ro.Status.UpdatedReplicas < defaults.GetReplicasOrDefault(ro.Spec.Replicas) {
-		return v1alpha1.RolloutPhaseProgressing, "more replicas need to be updated"
+		return v1alpha1.RolloutPhaseProgressing, "UpdatingReplicas", "more replicas need to be updated"
 	}
 	if ro.Status.AvailableReplicas < ro.Status.UpdatedReplicas {
-		return v1alpha1.RolloutPhaseProgressing, "updated replicas are still becoming available"
+		return v1alpha1.RolloutPhaseProgressing, "WaitingForAvailability", "updated replicas are still becoming available"
 	}
 	if ro.Spec.Strategy.BlueGreen != nil {
 		if ro.Status.BlueGreen.ActiveSelector == "" || ro.Status.BlueGreen.ActiveSelector != ro.Status.CurrentPodHash {
-			return v1alpha1.RolloutPhaseProgressing, "active service cutover pending"
+			return v1alpha1.RolloutPhaseProgressing, "ActiveServiceCutoverPending", "active service cutover pending"
 		}
 		if ro.Status.StableRS == "" || !IsFullyPromoted(&ro) {
 			// we switched the active selector to the desired ReplicaSet, but we have yet to mark it
@@ -169,9 +214,9 @@ func CalculateRolloutPhase(spec v1alpha1.RolloutSpec, status v1alpha1.RolloutSta
 			// 1. post-promotion analysis has yet to complete successfully
 			// 2. post-promotion verification (i.e. target group verification)
 			if waitingForBlueGreenPostPromotionAnalysis(&ro) {
-				return v1alpha1.RolloutPhaseProgressing, "waiting for analysis to complete"
+				return v1alpha1.RolloutPhaseProgressing, "WaitingForAnalysis", "waiting for analysis to complete"
 			}
-			return v1alpha1.RolloutPhaseProgressing, "waiting for post-promotion verification to complete"
+			return v1alpha1.RolloutPhaseProgressing, "WaitingForVerification", "waiting for post-promotion verification to complete"
 		}
 	} else if ro.Spec.Strategy.Canary != nil {
 		if ro.Spec.Strategy.Canary.TrafficRouting == nil {
@@ -179,19 +224,26 @@ func CalculateRolloutPhase(spec v1alpha1.RolloutSpec, status v1alpha1.RolloutSta
 				// This check should only be done for basic canary and not blue-green or canary with traffic routing
 				// since the latter two have the scaleDownDelay feature which leaves the old stack of replicas
 				// running for a long time
-				return v1alpha1.RolloutPhaseProgressing, "old replicas are pending termination"
+				return v1alpha1.RolloutPhaseProgressing, "TerminatingOldReplicas", "old replicas are pending termination"
 			}
 		}
 		if ro.Status.StableRS == "" || !IsFullyPromoted(&ro) {
-			return v1alpha1.RolloutPhaseProgressing, "waiting for all steps to complete"
+			return v1alpha1.RolloutPhaseProgressing, "WaitingForSteps", "waiting for all steps to complete"
 		}
 	}
-	return v1alpha1.RolloutPhaseHealthy, ""
+	return v1alpha1.RolloutPhaseHealthy, "", ""
+}
+
+// HasPostPromotionAnalysis returns whether a blue-green rollout runs a post-promotion AnalysisRun, either
+// for postPromotionAnalysis or postPromotionSmokeTest
+func HasPostPromotionAnalysis(ro *v1alpha1.Rollout) bool {
+	blueGreen := ro.Spec.Strategy.BlueGreen
+	return blueGreen != nil && (blueGreen.PostPromotionAnalysis != nil || blueGreen.PostPromotionSmokeTest != nil)
 }
 
 // waitingForBlueGreenPostPromotionAnalysis returns we are waiting for blue-green post promotion to complete
 func waitingForBlueGreenPostPromotionAnalysis(ro *v1alpha1.Rollout) bool {
-	if ro.Spec.Strategy.BlueGreen.PostPromotionAnalysis != nil {
+	if HasPostPromotionAnalysis(ro) {
 		if ro.Status.BlueGreen.PostPromotionAnalysisRunStatus == nil || !ro.Status.BlueGreen.PostPromotionAnalysisRunStatus.Status.Completed() {
 			return true
 		}
@@ -235,6 +287,24 @@ func CanaryStepString(c v1alpha1.CanaryStep) string {
 	if c.SetMirrorRoute != nil {
 		return fmt.Sprintf("setMirrorRoute: %s", c.SetMirrorRoute.Name)
 	}
+	if c.SetCanaryPodTemplateOverlay != nil {
+		return "setCanaryPodTemplateOverlay"
+	}
+	if c.LoadTest != nil {
+		return "loadTest"
+	}
+	if c.PreProvision != nil {
+		return "preProvision"
+	}
+	if c.SetFeatureFlag != nil {
+		return fmt.Sprintf("setFeatureFlag: %s", c.SetFeatureFlag.Flag)
+	}
+	if c.Migration != nil {
+		return "migration"
+	}
+	if c.Gate != nil {
+		return "gate"
+	}
 	return "invalid"
 }
 
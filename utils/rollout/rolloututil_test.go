@@ -12,6 +12,7 @@ import (
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	"github.com/argoproj/argo-rollouts/utils/annotations"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
 )
 
 func newCanaryRollout() *v1alpha1.Rollout {
@@ -114,6 +115,17 @@ func TestRolloutStatusPaused(t *testing.T) {
 }
 
 func TestRolloutStatusProgressing(t *testing.T) {
+	{
+		ro := newCanaryRollout()
+		ro.Status.Conditions = append(ro.Status.Conditions, v1alpha1.RolloutCondition{
+			Type:    v1alpha1.RolloutProgressing,
+			Reason:  conditions.RolloutProgressionPendingReason,
+			Message: conditions.RolloutProgressionPendingMessage,
+		})
+		status, message := GetRolloutPhase(ro)
+		assert.Equal(t, v1alpha1.RolloutPhaseProgressing, status)
+		assert.Equal(t, "ProgressionPending: "+conditions.RolloutProgressionPendingMessage, message)
+	}
 	{
 		ro := newCanaryRollout()
 		ro.Spec.Replicas = ptr.To[int32](5)
@@ -347,6 +359,57 @@ func TestRolloutStatusHealthy(t *testing.T) {
 	}
 }
 
+func TestCalculateRolloutHealth(t *testing.T) {
+	{
+		ro := newCanaryRollout()
+		ro.Status.PauseConditions = []v1alpha1.PauseCondition{{Reason: v1alpha1.PauseReasonCanaryPauseStep}}
+		assert.Equal(t, &v1alpha1.RolloutHealth{
+			Status:          v1alpha1.RolloutHealthStatusSuspended,
+			Reason:          string(v1alpha1.PauseReasonCanaryPauseStep),
+			CurrentStep:     "pause: 60",
+			PercentComplete: 33,
+		}, CalculateRolloutHealth(ro.Spec, ro.Status))
+	}
+	{
+		ro := newCanaryRollout()
+		ro.Status.Conditions = []v1alpha1.RolloutCondition{{Type: v1alpha1.RolloutProgressing, Reason: conditions.RolloutAbortedReason}}
+		health := CalculateRolloutHealth(ro.Spec, ro.Status)
+		assert.Equal(t, v1alpha1.RolloutHealthStatusDegraded, health.Status)
+		assert.Equal(t, conditions.RolloutAbortedReason, health.Reason)
+	}
+	{
+		// all steps are completed, but the update is not
+		ro := newCanaryRollout()
+		ro.Status.CurrentStepIndex = ptr.To[int32](3)
+		assert.Equal(t, &v1alpha1.RolloutHealth{
+			Status:          v1alpha1.RolloutHealthStatusProgressing,
+			Reason:          "UpdatingReplicas",
+			PercentComplete: 99,
+		}, CalculateRolloutHealth(ro.Spec, ro.Status))
+	}
+	{
+		ro := newBlueGreenRollout()
+		assert.Equal(t, &v1alpha1.RolloutHealth{
+			Status:          v1alpha1.RolloutHealthStatusProgressing,
+			Reason:          "UpdatingReplicas",
+			PercentComplete: 60,
+		}, CalculateRolloutHealth(ro.Spec, ro.Status))
+	}
+	{
+		ro := newBlueGreenRollout()
+		ro.Status.Replicas = 5
+		ro.Status.UpdatedReplicas = 5
+		ro.Status.AvailableReplicas = 5
+		ro.Status.BlueGreen.ActiveSelector = "abc1234"
+		ro.Status.CurrentPodHash = "abc1234"
+		ro.Status.StableRS = "abc1234"
+		assert.Equal(t, &v1alpha1.RolloutHealth{
+			Status:          v1alpha1.RolloutHealthStatusHealthy,
+			PercentComplete: 100,
+		}, CalculateRolloutHealth(ro.Spec, ro.Status))
+	}
+}
+
 func TestCanaryStepString(t *testing.T) {
 	ten := intstr.FromInt(10)
 	tenS := intstr.FromString("10s")
@@ -402,6 +465,10 @@ func TestCanaryStepString(t *testing.T) {
 			step:           v1alpha1.CanaryStep{SetMirrorRoute: &v1alpha1.SetMirrorRoute{Name: "foo"}},
 			expectedString: "setMirrorRoute: foo",
 		},
+		{
+			step:           v1alpha1.CanaryStep{SetCanaryPodTemplateOverlay: &v1alpha1.PodTemplateOverlay{}},
+			expectedString: "setCanaryPodTemplateOverlay",
+		},
 	}
 	for _, test := range tests {
 		assert.Equal(t, test.expectedString, CanaryStepString(test.step))
@@ -0,0 +1,137 @@
+package sharding
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	register "github.com/argoproj/argo-rollouts/pkg/apis/rollouts"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+// Sharder decides which objects are reconciled by a controller replica when the controller runs in sharding mode.
+// Every object is assigned to exactly one of the shards, so that multiple replicas of the controller can be
+// active at the same time. A nil Sharder owns all objects.
+type Sharder struct {
+	shards int
+	index  int
+}
+
+// NewSharder returns a Sharder for the shard with the given index. Sharding is disabled when there are less
+// than two shards, in which case nil is returned.
+func NewSharder(shards, index int) (*Sharder, error) {
+	if shards < 2 {
+		return nil, nil
+	}
+	if index < 0 || index >= shards {
+		return nil, fmt.Errorf("shard index %d is out of range, must be between 0 and %d", index, shards-1)
+	}
+	return &Sharder{shards: shards, index: index}, nil
+}
+
+// Index returns the index of the shard
+func (s *Sharder) Index() int {
+	return s.index
+}
+
+// Shards returns the total number of shards
+func (s *Sharder) Shards() int {
+	return s.shards
+}
+
+// Owns returns whether the object is assigned to the shard
+func (s *Sharder) Owns(obj metav1.Object) bool {
+	if s == nil {
+		return true
+	}
+	return GetShard(obj, s.shards) == s.index
+}
+
+// OwnsRollout returns whether a rollout with the given namespace and name, and without a shard label, is
+// assigned to the shard. It is used for objects which only reference a rollout by name.
+func (s *Sharder) OwnsRollout(namespace, name string) bool {
+	if s == nil {
+		return true
+	}
+	return hashShard(namespace+"/"+name, s.shards) == s.index
+}
+
+// GetShard returns the shard the object is assigned to. A valid shard label on the object takes precedence,
+// otherwise the shard is derived from a hash of the shard key of the object (see ShardKey).
+func GetShard(obj metav1.Object, shards int) int {
+	if value, ok := obj.GetLabels()[v1alpha1.LabelKeyControllerShard]; ok {
+		if shard, err := strconv.Atoi(value); err == nil && shard >= 0 && shard < shards {
+			return shard
+		}
+	}
+	return hashShard(ShardKey(obj), shards)
+}
+
+// ShardKey returns the key which is hashed to assign the object to a shard. Objects controlled by a Rollout,
+// or Services managed by a Rollout, use the namespace and name of their Rollout so that they are reconciled
+// by the same shard. Objects created by the controller for another owner carry the key of that owner in the
+// shard key annotation. All other objects use their own namespace and name.
+func ShardKey(obj metav1.Object) string {
+	return shardKey(obj, obj.GetNamespace())
+}
+
+func shardKey(obj metav1.Object, namespace string) string {
+	if key, ok := obj.GetAnnotations()[v1alpha1.AnnotationKeyControllerShardKey]; ok && key != "" {
+		return key
+	}
+	name := obj.GetName()
+	if ownerRef := metav1.GetControllerOf(obj); ownerRef != nil && ownerRef.Kind == register.RolloutKind {
+		name = ownerRef.Name
+	} else if rolloutName, ok := obj.GetAnnotations()[v1alpha1.ManagedByRolloutsKey]; ok && rolloutName != "" {
+		name = rolloutName
+	}
+	return namespace + "/" + name
+}
+
+// PropagateShard assigns an object created by the controller to the shard of its owner. The shard label of
+// the owner is copied, and the shard key annotation is set when the object would otherwise hash differently.
+// It must be called after the owner references of the object are set.
+func PropagateShard(owner, obj metav1.Object) {
+	if value, ok := owner.GetLabels()[v1alpha1.LabelKeyControllerShard]; ok {
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[v1alpha1.LabelKeyControllerShard] = value
+		obj.SetLabels(labels)
+	}
+	// the object is created in the namespace of its owner, which may not be set on the object yet
+	key := ShardKey(owner)
+	if shardKey(obj, owner.GetNamespace()) == key {
+		return
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[v1alpha1.AnnotationKeyControllerShardKey] = key
+	obj.SetAnnotations(annotations)
+}
+
+func hashShard(key string, shards int) int {
+	hasher := fnv.New32a()
+	hasher.Write([]byte(key))
+	return int(hasher.Sum32() % uint32(shards))
+}
+
+// IndexFromHostname returns the shard index from the ordinal suffix of a hostname, such as the hostname of a
+// StatefulSet pod (e.g. argo-rollouts-2)
+func IndexFromHostname(hostname string) (int, error) {
+	i := strings.LastIndex(hostname, "-")
+	if i < 0 {
+		return 0, fmt.Errorf("hostname %s does not end with an ordinal", hostname)
+	}
+	index, err := strconv.Atoi(hostname[i+1:])
+	if err != nil {
+		return 0, fmt.Errorf("hostname %s does not end with an ordinal", hostname)
+	}
+	return index, nil
+}
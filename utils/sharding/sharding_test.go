@@ -0,0 +1,161 @@
+package sharding
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+func TestNewSharder(t *testing.T) {
+	sharder, err := NewSharder(1, 0)
+	assert.NoError(t, err)
+	assert.Nil(t, sharder)
+	assert.True(t, sharder.Owns(&v1alpha1.Rollout{}))
+
+	sharder, err = NewSharder(3, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 3, sharder.Shards())
+	assert.Equal(t, 2, sharder.Index())
+
+	_, err = NewSharder(3, 3)
+	assert.EqualError(t, err, "shard index 3 is out of range, must be between 0 and 2")
+	_, err = NewSharder(3, -1)
+	assert.Error(t, err)
+}
+
+func TestGetShard(t *testing.T) {
+	newRollout := func(name string) *v1alpha1.Rollout {
+		return &v1alpha1.Rollout{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: "uid"}}
+	}
+
+	t.Run("Objects are distributed over all shards", func(t *testing.T) {
+		counts := make([]int, 3)
+		for i := 0; i < 300; i++ {
+			counts[GetShard(newRollout(fmt.Sprintf("rollout-%d", i)), 3)]++
+		}
+		for _, count := range counts {
+			assert.Greater(t, count, 50)
+		}
+	})
+
+	t.Run("Shard label takes precedence", func(t *testing.T) {
+		ro := newRollout("foo")
+		shard := GetShard(ro, 3)
+		ro.Labels = map[string]string{v1alpha1.LabelKeyControllerShard: fmt.Sprint((shard + 1) % 3)}
+		assert.Equal(t, (shard+1)%3, GetShard(ro, 3))
+	})
+
+	t.Run("Invalid shard label is ignored", func(t *testing.T) {
+		ro := newRollout("foo")
+		shard := GetShard(ro, 3)
+		ro.Labels = map[string]string{v1alpha1.LabelKeyControllerShard: "3"}
+		assert.Equal(t, shard, GetShard(ro, 3))
+		ro.Labels[v1alpha1.LabelKeyControllerShard] = "invalid"
+		assert.Equal(t, shard, GetShard(ro, 3))
+	})
+
+	t.Run("Objects controlled by a rollout follow the rollout", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			ro := newRollout(fmt.Sprintf("rollout-%d", i))
+			run := &v1alpha1.AnalysisRun{ObjectMeta: metav1.ObjectMeta{
+				Name:            ro.Name + "-analysis",
+				Namespace:       ro.Namespace,
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(ro, v1alpha1.SchemeGroupVersion.WithKind("Rollout"))},
+			}}
+			assert.Equal(t, GetShard(ro, 5), GetShard(run, 5))
+		}
+	})
+
+	t.Run("Services managed by a rollout follow the rollout", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			ro := newRollout(fmt.Sprintf("rollout-%d", i))
+			svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{
+				Name:        "stable",
+				Namespace:   ro.Namespace,
+				Annotations: map[string]string{v1alpha1.ManagedByRolloutsKey: ro.Name},
+			}}
+			assert.Equal(t, GetShard(ro, 5), GetShard(svc, 5))
+		}
+	})
+}
+
+func TestPropagateShard(t *testing.T) {
+	newRollout := func(name string) *v1alpha1.Rollout {
+		return &v1alpha1.Rollout{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: "uid"}}
+	}
+	newExperiment := func(ro *v1alpha1.Rollout) *v1alpha1.Experiment {
+		ex := &v1alpha1.Experiment{ObjectMeta: metav1.ObjectMeta{
+			Name:            ro.Name + "-experiment",
+			Namespace:       ro.Namespace,
+			UID:             "ex-uid",
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(ro, v1alpha1.SchemeGroupVersion.WithKind("Rollout"))},
+		}}
+		PropagateShard(ro, ex)
+		return ex
+	}
+	newRun := func(ex *v1alpha1.Experiment) *v1alpha1.AnalysisRun {
+		run := &v1alpha1.AnalysisRun{ObjectMeta: metav1.ObjectMeta{
+			Name:            ex.Name + "-analysis",
+			Namespace:       ex.Namespace,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(ex, v1alpha1.SchemeGroupVersion.WithKind("Experiment"))},
+		}}
+		PropagateShard(ex, run)
+		return run
+	}
+
+	t.Run("Analysis runs of a rollout experiment follow the rollout", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			ro := newRollout(fmt.Sprintf("rollout-%d", i))
+			ex := newExperiment(ro)
+			assert.NotContains(t, ex.Annotations, v1alpha1.AnnotationKeyControllerShardKey)
+			run := newRun(ex)
+			assert.Equal(t, "default/"+ro.Name, run.Annotations[v1alpha1.AnnotationKeyControllerShardKey])
+			assert.Equal(t, GetShard(ro, 5), GetShard(run, 5))
+		}
+	})
+
+	t.Run("Shard label is copied", func(t *testing.T) {
+		ro := newRollout("foo")
+		shard := (GetShard(ro, 3) + 1) % 3
+		ro.Labels = map[string]string{v1alpha1.LabelKeyControllerShard: fmt.Sprint(shard)}
+		run := newRun(newExperiment(ro))
+		assert.Equal(t, fmt.Sprint(shard), run.Labels[v1alpha1.LabelKeyControllerShard])
+		assert.Equal(t, shard, GetShard(run, 3))
+	})
+
+	t.Run("Analysis runs of a standalone experiment follow the experiment", func(t *testing.T) {
+		ex := &v1alpha1.Experiment{ObjectMeta: metav1.ObjectMeta{Name: "standalone", Namespace: "default", UID: "uid"}}
+		run := newRun(ex)
+		assert.Equal(t, GetShard(ex, 5), GetShard(run, 5))
+	})
+}
+
+func TestOwns(t *testing.T) {
+	ro := &v1alpha1.Rollout{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"}}
+	owners := 0
+	for i := 0; i < 3; i++ {
+		sharder, err := NewSharder(3, i)
+		require.NoError(t, err)
+		if sharder.Owns(ro) {
+			owners++
+		}
+	}
+	assert.Equal(t, 1, owners)
+}
+
+func TestIndexFromHostname(t *testing.T) {
+	index, err := IndexFromHostname("argo-rollouts-2")
+	require.NoError(t, err)
+	assert.Equal(t, 2, index)
+
+	_, err = IndexFromHostname("argo-rollouts-7d9c5b6f4-x2k8p")
+	assert.EqualError(t, err, "hostname argo-rollouts-7d9c5b6f4-x2k8p does not end with an ordinal")
+	_, err = IndexFromHostname("localhost")
+	assert.Error(t, err)
+}
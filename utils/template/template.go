@@ -11,6 +11,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	experimentutil "github.com/argoproj/argo-rollouts/utils/experiment"
 )
 
 const (
@@ -32,7 +33,7 @@ func ResolveExperimentArgsValue(argTemplate string, ex *v1alpha1.Experiment, tem
 	if ex.Status.AvailableAt != nil {
 		argsMap[experimentAvailableAt] = ex.Status.AvailableAt.Format(time.RFC3339)
 		if ex.Spec.Duration != "" {
-			duration, err := ex.Spec.Duration.Duration()
+			duration, err := experimentutil.GetDuration(ex)
 			if err != nil {
 				return "", err
 			}
@@ -0,0 +1,155 @@
+package tls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// versions are the TLS versions of the --outbound-tls-min-version flag
+var versions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+var (
+	clientConfig     *tls.Config
+	clientConfigLock sync.RWMutex
+)
+
+// Config is the TLS policy of the outbound connections of the controller, to metric providers, notification
+// services, webhooks and plugin downloads
+type Config struct {
+	// MinVersion is the minimum TLS version, e.g. 1.2
+	MinVersion string
+	// CipherSuites are the names of the cipher suites of TLS 1.2 and below, e.g. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256.
+	// The cipher suites of TLS 1.3 are not configurable
+	CipherSuites []string
+	// CAFile is a PEM bundle of CAs which are trusted in addition to the CAs of the system
+	CAFile string
+	// CertFile and KeyFile are the PEM client certificate and key presented to the servers which request one
+	CertFile string
+	KeyFile  string
+}
+
+// IsZero returns whether the policy is empty, in which case the defaults of Go apply
+func (c Config) IsZero() bool {
+	return c.MinVersion == "" && len(c.CipherSuites) == 0 && c.CAFile == "" && c.CertFile == "" && c.KeyFile == ""
+}
+
+// TLSConfig builds the client TLS configuration of the policy. The client certificate is read again at each
+// handshake, so that rotated certificates are picked up without a restart
+func (c Config) TLSConfig() (*tls.Config, error) {
+	cfg := &tls.Config{}
+	if c.MinVersion != "" {
+		version, ok := versions[c.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("invalid TLS version %q, must be one of %s", c.MinVersion, strings.Join(versionNames(), ", "))
+		}
+		cfg.MinVersion = version
+	}
+	for _, name := range c.CipherSuites {
+		id, err := cipherSuite(name)
+		if err != nil {
+			return nil, err
+		}
+		cfg.CipherSuites = append(cfg.CipherSuites, id)
+	}
+	if c.CAFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the system CAs: %w", err)
+		}
+		bundle, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the CA bundle: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("CA bundle %s has no PEM certificates", c.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return nil, fmt.Errorf("client certificate and key must be set together")
+	}
+	if c.CertFile != "" {
+		if _, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile); err != nil {
+			return nil, fmt.Errorf("failed to load the client certificate: %w", err)
+		}
+		certFile, keyFile := c.CertFile, c.KeyFile
+		cfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load the client certificate: %w", err)
+			}
+			return &cert, nil
+		}
+	}
+	return cfg, nil
+}
+
+// cipherSuite returns the ID of a secure cipher suite. The insecure cipher suites of Go are rejected
+func cipherSuite(name string) (uint16, error) {
+	for _, suite := range tls.CipherSuites() {
+		if suite.Name == name {
+			return suite.ID, nil
+		}
+	}
+	for _, suite := range tls.InsecureCipherSuites() {
+		if suite.Name == name {
+			return 0, fmt.Errorf("cipher suite %s is insecure", name)
+		}
+	}
+	return 0, fmt.Errorf("unknown cipher suite %s", name)
+}
+
+func versionNames() []string {
+	names := make([]string, 0, len(versions))
+	for name := range versions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetClientConfig sets the client TLS configuration of the outbound connections of the controller. It also applies
+// to the clients which use the default transport of net/http, so it must be called before any connection is made.
+// The clients with a transport of their own, e.g. the AWS clients, apply ClientConfig to it
+func SetClientConfig(cfg *tls.Config) {
+	clientConfigLock.Lock()
+	defer clientConfigLock.Unlock()
+	clientConfig = cfg
+	if transport, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport.TLSClientConfig = nil
+		if cfg != nil {
+			transport.TLSClientConfig = cfg.Clone()
+		}
+	}
+}
+
+// HasClientConfig returns whether the controller has an outbound TLS policy
+func HasClientConfig() bool {
+	clientConfigLock.RLock()
+	defer clientConfigLock.RUnlock()
+	return clientConfig != nil
+}
+
+// ClientConfig returns a copy of the client TLS configuration of the controller, which skips the verification of
+// the server certificates if insecureSkipVerify is set
+func ClientConfig(insecureSkipVerify bool) *tls.Config {
+	clientConfigLock.RLock()
+	defer clientConfigLock.RUnlock()
+	cfg := &tls.Config{}
+	if clientConfig != nil {
+		cfg = clientConfig.Clone()
+	}
+	cfg.InsecureSkipVerify = insecureSkipVerify
+	return cfg
+}
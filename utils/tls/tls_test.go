@@ -0,0 +1,129 @@
+package tls
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/argoproj/argo-rollouts/utils/certs"
+)
+
+func writeFile(t *testing.T, name string, data []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func TestTLSConfig(t *testing.T) {
+	ca, err := certs.GenerateCA("test-ca", time.Now(), time.Hour)
+	require.NoError(t, err)
+	cert, err := certs.GenerateServingCert(ca, []string{"localhost"}, time.Now(), time.Hour)
+	require.NoError(t, err)
+
+	t.Run("empty", func(t *testing.T) {
+		assert.True(t, Config{}.IsZero())
+		cfg, err := Config{}.TLSConfig()
+		require.NoError(t, err)
+		assert.Equal(t, &tls.Config{}, cfg)
+	})
+
+	t.Run("min version and cipher suites", func(t *testing.T) {
+		cfg, err := Config{
+			MinVersion:   "1.2",
+			CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384"},
+		}.TLSConfig()
+		require.NoError(t, err)
+		assert.Equal(t, uint16(tls.VersionTLS12), cfg.MinVersion)
+		assert.Equal(t, []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384}, cfg.CipherSuites)
+	})
+
+	t.Run("invalid min version", func(t *testing.T) {
+		_, err := Config{MinVersion: "TLS12"}.TLSConfig()
+		assert.EqualError(t, err, `invalid TLS version "TLS12", must be one of 1.0, 1.1, 1.2, 1.3`)
+	})
+
+	t.Run("insecure cipher suite", func(t *testing.T) {
+		_, err := Config{CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}}.TLSConfig()
+		assert.EqualError(t, err, "cipher suite TLS_RSA_WITH_RC4_128_SHA is insecure")
+		_, err = Config{CipherSuites: []string{"TLS_FOO"}}.TLSConfig()
+		assert.EqualError(t, err, "unknown cipher suite TLS_FOO")
+	})
+
+	t.Run("CA bundle", func(t *testing.T) {
+		cfg, err := Config{CAFile: writeFile(t, "ca.crt", ca.Cert)}.TLSConfig()
+		require.NoError(t, err)
+		assert.NotNil(t, cfg.RootCAs)
+
+		_, err = Config{CAFile: writeFile(t, "ca.crt", []byte("foo"))}.TLSConfig()
+		assert.ErrorContains(t, err, "has no PEM certificates")
+	})
+
+	t.Run("client certificate", func(t *testing.T) {
+		_, err := Config{CertFile: writeFile(t, "tls.crt", cert.Cert)}.TLSConfig()
+		assert.EqualError(t, err, "client certificate and key must be set together")
+
+		_, err = Config{CertFile: writeFile(t, "tls.crt", cert.Cert), KeyFile: writeFile(t, "tls.key", []byte("foo"))}.TLSConfig()
+		assert.ErrorContains(t, err, "failed to load the client certificate")
+
+		cfg, err := Config{CertFile: writeFile(t, "tls.crt", cert.Cert), KeyFile: writeFile(t, "tls.key", cert.Key)}.TLSConfig()
+		require.NoError(t, err)
+		clientCert, err := cfg.GetClientCertificate(nil)
+		require.NoError(t, err)
+		assert.NotEmpty(t, clientCert.Certificate)
+	})
+}
+
+func TestSetClientConfig(t *testing.T) {
+	ca, err := certs.GenerateCA("test-ca", time.Now(), time.Hour)
+	require.NoError(t, err)
+	serving, err := certs.GenerateServingCert(ca, []string{"localhost"}, time.Now(), time.Hour)
+	require.NoError(t, err)
+	servingCert, err := tls.X509KeyPair(serving.Cert, serving.Key)
+	require.NoError(t, err)
+
+	var clientCerts int
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientCerts = len(r.TLS.PeerCertificates)
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{servingCert},
+		ClientAuth:   tls.RequireAnyClientCert,
+	}
+	server.StartTLS()
+	defer server.Close()
+	url := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+
+	cfg, err := Config{
+		MinVersion: "1.2",
+		CAFile:     writeFile(t, "ca.crt", ca.Cert),
+		CertFile:   writeFile(t, "tls.crt", serving.Cert),
+		KeyFile:    writeFile(t, "tls.key", serving.Key),
+	}.TLSConfig()
+	require.NoError(t, err)
+	assert.False(t, HasClientConfig())
+	SetClientConfig(cfg)
+	defer SetClientConfig(nil)
+
+	assert.True(t, HasClientConfig())
+	assert.Equal(t, uint16(tls.VersionTLS12), ClientConfig(false).MinVersion)
+	assert.True(t, ClientConfig(true).InsecureSkipVerify)
+
+	// the default transport of net/http trusts the CA and presents the client certificate
+	resp, err := (&http.Client{}).Get(url)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 1, clientCerts)
+
+	SetClientConfig(nil)
+	assert.False(t, HasClientConfig())
+	assert.Nil(t, http.DefaultTransport.(*http.Transport).TLSClientConfig)
+	assert.Equal(t, &tls.Config{}, ClientConfig(false))
+}
@@ -0,0 +1,86 @@
+package tolerantinformer
+
+import (
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	rolloutinformers "github.com/argoproj/argo-rollouts/pkg/client/informers/externalversions/rollouts/v1alpha1"
+	rolloutlisters "github.com/argoproj/argo-rollouts/pkg/client/listers/rollouts/v1alpha1"
+)
+
+func NewTolerantRolloutControllerConfigInformer(factory dynamicinformer.DynamicSharedInformerFactory) rolloutinformers.RolloutControllerConfigInformer {
+	return &tolerantRolloutControllerConfigInformer{
+		delegate: factory.ForResource(v1alpha1.RolloutControllerConfigGVR),
+	}
+}
+
+type tolerantRolloutControllerConfigInformer struct {
+	delegate informers.GenericInformer
+}
+
+func (i *tolerantRolloutControllerConfigInformer) Informer() cache.SharedIndexInformer {
+	return i.delegate.Informer()
+}
+
+func (i *tolerantRolloutControllerConfigInformer) Lister() rolloutlisters.RolloutControllerConfigLister {
+	return &tolerantRolloutControllerConfigLister{
+		delegate: i.delegate.Lister(),
+	}
+}
+
+type tolerantRolloutControllerConfigLister struct {
+	delegate cache.GenericLister
+}
+
+func (t *tolerantRolloutControllerConfigLister) List(selector labels.Selector) ([]*v1alpha1.RolloutControllerConfig, error) {
+	objects, err := t.delegate.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	return convertObjectsToRolloutControllerConfigs(objects)
+}
+
+func (t *tolerantRolloutControllerConfigLister) RolloutControllerConfigs(namespace string) rolloutlisters.RolloutControllerConfigNamespaceLister {
+	return &tolerantRolloutControllerConfigNamespaceLister{
+		delegate: t.delegate.ByNamespace(namespace),
+	}
+}
+
+type tolerantRolloutControllerConfigNamespaceLister struct {
+	delegate cache.GenericNamespaceLister
+}
+
+func (t *tolerantRolloutControllerConfigNamespaceLister) Get(name string) (*v1alpha1.RolloutControllerConfig, error) {
+	object, err := t.delegate.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	v := &v1alpha1.RolloutControllerConfig{}
+	err = convertObject(object, v)
+	return v, err
+}
+
+func (t *tolerantRolloutControllerConfigNamespaceLister) List(selector labels.Selector) ([]*v1alpha1.RolloutControllerConfig, error) {
+	objects, err := t.delegate.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	return convertObjectsToRolloutControllerConfigs(objects)
+}
+
+func convertObjectsToRolloutControllerConfigs(objects []runtime.Object) ([]*v1alpha1.RolloutControllerConfig, error) {
+	var firstErr error
+	vs := make([]*v1alpha1.RolloutControllerConfig, len(objects))
+	for i, obj := range objects {
+		vs[i] = &v1alpha1.RolloutControllerConfig{}
+		err := convertObject(obj, vs[i])
+		if err != nil && firstErr != nil {
+			firstErr = err
+		}
+	}
+	return vs, firstErr
+}
@@ -0,0 +1,86 @@
+package tolerantinformer
+
+import (
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	rolloutinformers "github.com/argoproj/argo-rollouts/pkg/client/informers/externalversions/rollouts/v1alpha1"
+	rolloutlisters "github.com/argoproj/argo-rollouts/pkg/client/listers/rollouts/v1alpha1"
+)
+
+func NewTolerantRolloutNotificationPolicyInformer(factory dynamicinformer.DynamicSharedInformerFactory) rolloutinformers.RolloutNotificationPolicyInformer {
+	return &tolerantRolloutNotificationPolicyInformer{
+		delegate: factory.ForResource(v1alpha1.RolloutNotificationPolicyGVR),
+	}
+}
+
+type tolerantRolloutNotificationPolicyInformer struct {
+	delegate informers.GenericInformer
+}
+
+func (i *tolerantRolloutNotificationPolicyInformer) Informer() cache.SharedIndexInformer {
+	return i.delegate.Informer()
+}
+
+func (i *tolerantRolloutNotificationPolicyInformer) Lister() rolloutlisters.RolloutNotificationPolicyLister {
+	return &tolerantRolloutNotificationPolicyLister{
+		delegate: i.delegate.Lister(),
+	}
+}
+
+type tolerantRolloutNotificationPolicyLister struct {
+	delegate cache.GenericLister
+}
+
+func (t *tolerantRolloutNotificationPolicyLister) List(selector labels.Selector) ([]*v1alpha1.RolloutNotificationPolicy, error) {
+	objects, err := t.delegate.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	return convertObjectsToRolloutNotificationPolicies(objects)
+}
+
+func (t *tolerantRolloutNotificationPolicyLister) RolloutNotificationPolicies(namespace string) rolloutlisters.RolloutNotificationPolicyNamespaceLister {
+	return &tolerantRolloutNotificationPolicyNamespaceLister{
+		delegate: t.delegate.ByNamespace(namespace),
+	}
+}
+
+type tolerantRolloutNotificationPolicyNamespaceLister struct {
+	delegate cache.GenericNamespaceLister
+}
+
+func (t *tolerantRolloutNotificationPolicyNamespaceLister) Get(name string) (*v1alpha1.RolloutNotificationPolicy, error) {
+	object, err := t.delegate.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	v := &v1alpha1.RolloutNotificationPolicy{}
+	err = convertObject(object, v)
+	return v, err
+}
+
+func (t *tolerantRolloutNotificationPolicyNamespaceLister) List(selector labels.Selector) ([]*v1alpha1.RolloutNotificationPolicy, error) {
+	objects, err := t.delegate.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	return convertObjectsToRolloutNotificationPolicies(objects)
+}
+
+func convertObjectsToRolloutNotificationPolicies(objects []runtime.Object) ([]*v1alpha1.RolloutNotificationPolicy, error) {
+	var firstErr error
+	vs := make([]*v1alpha1.RolloutNotificationPolicy, len(objects))
+	for i, obj := range objects {
+		vs[i] = &v1alpha1.RolloutNotificationPolicy{}
+		err := convertObject(obj, vs[i])
+		if err != nil && firstErr != nil {
+			firstErr = err
+		}
+	}
+	return vs, firstErr
+}
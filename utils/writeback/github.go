@@ -0,0 +1,175 @@
+package writeback
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v69/github"
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultBranch is the branch the decisions are written back to when none is configured
+const DefaultBranch = "main"
+
+// GitHubWriter writes the decisions back to a repository of GitHub or GitHub Enterprise via its REST API. Each rollout
+// has a file in the repository, which holds its last decision, so that the history of the file is the history of what
+// went live.
+type GitHubWriter struct {
+	client      *github.Client
+	owner       string
+	repo        string
+	branch      string
+	path        string
+	pullRequest bool
+}
+
+// NewGitHubWriter returns a writer to the repository of the config. The API of GitHub Enterprise is used for the
+// repositories which are not hosted on github.com.
+func NewGitHubWriter(config Config) (*GitHubWriter, error) {
+	u, err := url.Parse(strings.TrimSuffix(config.RepoURL, ".git"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository URL %s: %w", config.RepoURL, err)
+	}
+	owner, repo, ok := strings.Cut(strings.Trim(u.Path, "/"), "/")
+	if !ok || u.Host == "" || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return nil, fmt.Errorf("invalid repository URL %s: expected <scheme>://<host>/<owner>/<repository>", config.RepoURL)
+	}
+	client := github.NewClient(http.DefaultClient)
+	if config.Token != "" {
+		client = client.WithAuthToken(config.Token)
+	}
+	if u.Host != "github.com" {
+		baseURL := fmt.Sprintf("%s://%s", u.Scheme, u.Host)
+		client, err = client.WithEnterpriseURLs(baseURL, baseURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid repository URL %s: %w", config.RepoURL, err)
+		}
+	}
+	branch := config.Branch
+	if branch == "" {
+		branch = DefaultBranch
+	}
+	return &GitHubWriter{
+		client:      client,
+		owner:       owner,
+		repo:        repo,
+		branch:      branch,
+		path:        strings.Trim(config.Path, "/"),
+		pullRequest: config.PullRequest,
+	}, nil
+}
+
+// Write commits the decision to the file of the rollout, either to the branch or to a new branch from which a pull
+// request is opened
+func (w *GitHubWriter) Write(ctx context.Context, decision Decision) error {
+	content, err := yaml.Marshal(decision)
+	if err != nil {
+		return err
+	}
+	filePath := path.Join(w.path, decision.Namespace, decision.Name+".yaml")
+	message := commitMessage(decision)
+
+	branch := w.branch
+	if w.pullRequest {
+		branch = fmt.Sprintf("argo-rollouts/%s-%s-%s-%s", decision.Namespace, decision.Name, decision.Revision, strings.ToLower(decision.Decision))
+		if err := w.createBranch(ctx, branch); err != nil {
+			return err
+		}
+	}
+	committed, err := w.commitFile(ctx, branch, filePath, content, message)
+	if err != nil || !w.pullRequest || !committed {
+		return err
+	}
+	_, _, err = w.client.PullRequests.Create(ctx, w.owner, w.repo, &github.NewPullRequest{
+		Title: github.Ptr(message),
+		Head:  github.Ptr(branch),
+		Base:  github.Ptr(w.branch),
+		Body:  github.Ptr(decision.Message),
+	})
+	if isStatus(err, http.StatusUnprocessableEntity) {
+		// a pull request from the branch already exists
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open pull request from %s: %w", branch, err)
+	}
+	return nil
+}
+
+// createBranch creates a branch from the head of the base branch, unless it already exists
+func (w *GitHubWriter) createBranch(ctx context.Context, branch string) error {
+	base, _, err := w.client.Git.GetRef(ctx, w.owner, w.repo, "refs/heads/"+w.branch)
+	if err != nil {
+		return fmt.Errorf("failed to get branch %s: %w", w.branch, err)
+	}
+	_, _, err = w.client.Git.CreateRef(ctx, w.owner, w.repo, &github.Reference{
+		Ref:    github.Ptr("refs/heads/" + branch),
+		Object: &github.GitObject{SHA: base.GetObject().SHA},
+	})
+	if err != nil && !isStatus(err, http.StatusUnprocessableEntity) {
+		return fmt.Errorf("failed to create branch %s: %w", branch, err)
+	}
+	return nil
+}
+
+// commitFile creates or updates the file on the branch, and returns whether a commit was made, i.e. whether the
+// content of the file changed
+func (w *GitHubWriter) commitFile(ctx context.Context, branch, filePath string, content []byte, message string) (bool, error) {
+	opts := &github.RepositoryContentFileOptions{
+		Message: github.Ptr(message),
+		Content: content,
+		Branch:  github.Ptr(branch),
+	}
+	file, _, _, err := w.client.Repositories.GetContents(ctx, w.owner, w.repo, filePath, &github.RepositoryContentGetOptions{Ref: branch})
+	switch {
+	case isStatus(err, http.StatusNotFound):
+		_, _, err = w.client.Repositories.CreateFile(ctx, w.owner, w.repo, filePath, opts)
+	case err != nil:
+		return false, fmt.Errorf("failed to get %s on branch %s: %w", filePath, branch, err)
+	default:
+		if existing, err := file.GetContent(); err == nil && bytes.Equal([]byte(existing), content) {
+			return false, nil
+		}
+		opts.SHA = file.SHA
+		_, _, err = w.client.Repositories.UpdateFile(ctx, w.owner, w.repo, filePath, opts)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to commit %s to branch %s: %w", filePath, branch, err)
+	}
+	return true, nil
+}
+
+// commitMessage returns the message of the commit of a decision, e.g.
+// "Promoted default/guestbook to revision 3 (guestbook=argoproj/rollouts-demo:blue)"
+func commitMessage(decision Decision) string {
+	format := "Aborted %s/%s revision %s"
+	if decision.Decision == DecisionPromoted {
+		format = "Promoted %s/%s to revision %s"
+	}
+	message := fmt.Sprintf(format, decision.Namespace, decision.Name, decision.Revision)
+	if len(decision.Images) == 0 {
+		return message
+	}
+	images := make([]string, 0, len(decision.Images))
+	for name, image := range decision.Images {
+		images = append(images, name+"="+image)
+	}
+	sort.Strings(images)
+	return fmt.Sprintf("%s (%s)", message, strings.Join(images, ", "))
+}
+
+// isStatus returns whether err is an error response of the GitHub API with the given status code
+func isStatus(err error, status int) bool {
+	var errResp *github.ErrorResponse
+	if !errors.As(err, &errResp) {
+		return false
+	}
+	return errResp.Response != nil && errResp.Response.StatusCode == status
+}
@@ -0,0 +1,191 @@
+package writeback
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/annotations"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	timeutil "github.com/argoproj/argo-rollouts/utils/time"
+)
+
+const (
+	// DecisionPromoted is the decision of a rollout whose update was fully promoted
+	DecisionPromoted = "Promoted"
+	// DecisionAborted is the decision of a rollout whose update was aborted
+	DecisionAborted = "Aborted"
+	// DefaultQueueSize is the number of decisions buffered for the writer, beyond which decisions are dropped
+	DefaultQueueSize = 100
+
+	writeTimeout = 30 * time.Second
+)
+
+// decisions are the decisions written back by the reason of the Kubernetes event of the rollout. The events with other
+// reasons are not written back.
+var decisions = map[string]string{
+	conditions.RolloutCompletedReason: DecisionPromoted,
+	conditions.RolloutAbortedReason:   DecisionAborted,
+}
+
+// Config configures the Git repository the decisions are written back to
+type Config struct {
+	// RepoURL is the URL of the repository, e.g. https://github.com/example/deployments
+	RepoURL string
+	// Branch is the branch the decisions are committed to, or the base branch of the pull requests
+	Branch string
+	// Path is the directory of the repository under which a file is written per rollout
+	Path string
+	// PullRequest opens a pull request per decision instead of committing to the branch
+	PullRequest bool
+	// Token is the credential of the Git hosting API
+	Token string
+}
+
+// Decision is a promotion or abort of a rollout, as written back to the repository
+type Decision struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Decision is either Promoted or Aborted
+	Decision string    `json:"decision"`
+	Message  string    `json:"message,omitempty"`
+	Time     time.Time `json:"time"`
+	// Revision is the revision of the rollout which was promoted or aborted
+	Revision        string `json:"revision,omitempty"`
+	PodTemplateHash string `json:"podTemplateHash,omitempty"`
+	// StableRS is the pod template hash of the revision which is live after the decision
+	StableRS string `json:"stableRS,omitempty"`
+	// Images are the images of the containers of the promoted or aborted revision by container name
+	Images map[string]string `json:"images,omitempty"`
+}
+
+// Writer writes the decisions back to a Git repository
+type Writer interface {
+	Write(ctx context.Context, decision Decision) error
+}
+
+// WriteBack writes decisions back in the background, one at a time, so that the reconciliations do not wait on the
+// Git hosting API and the commits to the branch do not conflict
+type WriteBack struct {
+	writer Writer
+	queue  chan Decision
+	done   chan struct{}
+	// mu guards closed, so that no decision is queued once the queue is closed
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewWriteBack returns a write-back to the writer, which buffers up to queueSize decisions
+func NewWriteBack(writer Writer, queueSize int) *WriteBack {
+	return &WriteBack{
+		writer: writer,
+		queue:  make(chan Decision, queueSize),
+		done:   make(chan struct{}),
+	}
+}
+
+// Run writes the queued decisions back until Close is called
+func (w *WriteBack) Run() {
+	defer close(w.done)
+	for decision := range w.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+		if err := w.writer.Write(ctx, decision); err != nil {
+			log.Warnf("Failed to write back decision %s of rollout %s/%s: %v", decision.Decision, decision.Namespace, decision.Name, err)
+		}
+		cancel()
+	}
+}
+
+// Add queues a decision, or drops it if the queue is full
+func (w *WriteBack) Add(decision Decision) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return
+	}
+	select {
+	case w.queue <- decision:
+	default:
+		log.Warnf("Dropped decision %s of rollout %s/%s: the write-back queue is full", decision.Decision, decision.Namespace, decision.Name)
+	}
+}
+
+// Close writes the queued decisions back
+func (w *WriteBack) Close() {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return
+	}
+	w.closed = true
+	close(w.queue)
+	w.mu.Unlock()
+	<-w.done
+}
+
+var writeBack atomic.Pointer[WriteBack]
+
+// Init writes the promotions and aborts of the rollouts back to the configured repository. The returned function
+// writes the queued decisions back.
+func Init(config Config) (func(), error) {
+	writer, err := NewGitHubWriter(config)
+	if err != nil {
+		return nil, err
+	}
+	w := NewWriteBack(writer, DefaultQueueSize)
+	go w.Run()
+	writeBack.Store(w)
+	return func() {
+		writeBack.CompareAndSwap(w, nil)
+		w.Close()
+	}, nil
+}
+
+// RecordRolloutEvent writes back a Kubernetes event of a rollout, if Init was called and the event is a promotion or
+// an abort. The message of the event is formatted from messageFmt and args like the Kubernetes event.
+func RecordRolloutEvent(object runtime.Object, reason, messageFmt string, args []any) {
+	w := writeBack.Load()
+	if w == nil {
+		return
+	}
+	ro, ok := object.(*v1alpha1.Rollout)
+	if !ok {
+		return
+	}
+	if decision, ok := NewDecision(ro, reason, fmt.Sprintf(messageFmt, args...)); ok {
+		w.Add(decision)
+	}
+}
+
+// NewDecision returns the decision of a Kubernetes event of a rollout, and whether the event is a promotion or an abort
+func NewDecision(ro *v1alpha1.Rollout, reason, message string) (Decision, bool) {
+	d, ok := decisions[reason]
+	if !ok {
+		return Decision{}, false
+	}
+	decision := Decision{
+		Namespace:       ro.Namespace,
+		Name:            ro.Name,
+		Decision:        d,
+		Message:         message,
+		Time:            timeutil.Now().UTC().Truncate(time.Second),
+		PodTemplateHash: ro.Status.CurrentPodHash,
+		StableRS:        ro.Status.StableRS,
+	}
+	if revision, ok := annotations.GetRevisionAnnotation(ro); ok && revision > 0 {
+		decision.Revision = fmt.Sprint(revision)
+	}
+	for _, c := range ro.Spec.Template.Spec.Containers {
+		if decision.Images == nil {
+			decision.Images = map[string]string{}
+		}
+		decision.Images[c.Name] = c.Image
+	}
+	return decision, true
+}
@@ -0,0 +1,218 @@
+package writeback
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+)
+
+func newRollout() *v1alpha1.Rollout {
+	return &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "guestbook",
+			Namespace:   "default",
+			Annotations: map[string]string{"rollout.argoproj.io/revision": "3"},
+		},
+		Spec: v1alpha1.RolloutSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "guestbook", Image: "argoproj/rollouts-demo:blue"}}},
+			},
+		},
+		Status: v1alpha1.RolloutStatus{CurrentPodHash: "abc123", StableRS: "abc123"},
+	}
+}
+
+func TestNewDecision(t *testing.T) {
+	ro := newRollout()
+	decision, ok := NewDecision(ro, conditions.RolloutCompletedReason, "Rollout completed update to revision 3")
+	require.True(t, ok)
+	assert.Equal(t, "default", decision.Namespace)
+	assert.Equal(t, "guestbook", decision.Name)
+	assert.Equal(t, DecisionPromoted, decision.Decision)
+	assert.Equal(t, "3", decision.Revision)
+	assert.Equal(t, "abc123", decision.PodTemplateHash)
+	assert.Equal(t, map[string]string{"guestbook": "argoproj/rollouts-demo:blue"}, decision.Images)
+
+	decision, ok = NewDecision(ro, conditions.RolloutAbortedReason, "")
+	require.True(t, ok)
+	assert.Equal(t, DecisionAborted, decision.Decision)
+
+	_, ok = NewDecision(ro, conditions.RolloutPausedReason, "")
+	assert.False(t, ok)
+}
+
+func TestCommitMessage(t *testing.T) {
+	decision := Decision{Namespace: "default", Name: "guestbook", Decision: DecisionPromoted, Revision: "3", Images: map[string]string{"b": "b:2", "a": "a:1"}}
+	assert.Equal(t, "Promoted default/guestbook to revision 3 (a=a:1, b=b:2)", commitMessage(decision))
+	decision.Decision = DecisionAborted
+	decision.Images = nil
+	assert.Equal(t, "Aborted default/guestbook revision 3", commitMessage(decision))
+}
+
+func TestNewGitHubWriter(t *testing.T) {
+	w, err := NewGitHubWriter(Config{RepoURL: "https://github.com/example/deployments.git", Path: "/rollouts/"})
+	require.NoError(t, err)
+	assert.Equal(t, "example", w.owner)
+	assert.Equal(t, "deployments", w.repo)
+	assert.Equal(t, DefaultBranch, w.branch)
+	assert.Equal(t, "rollouts", w.path)
+	assert.Equal(t, "https://api.github.com/", w.client.BaseURL.String())
+
+	w, err = NewGitHubWriter(Config{RepoURL: "https://git.example.com/example/deployments"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://git.example.com/api/v3/", w.client.BaseURL.String())
+
+	for _, repoURL := range []string{"", "https://github.com/example", "https://github.com/example/deployments/tree/main"} {
+		_, err = NewGitHubWriter(Config{RepoURL: repoURL})
+		assert.Error(t, err, repoURL)
+	}
+}
+
+// fakeGitHub is a fake of the GitHub API, which records the requests
+type fakeGitHub struct {
+	mu       sync.Mutex
+	requests []string
+	files    map[string]string
+}
+
+func (f *fakeGitHub) handler(t *testing.T) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/example/deployments/contents/", func(w http.ResponseWriter, r *http.Request) {
+		f.record(r)
+		filePath := r.URL.Path[len("/api/v3/repos/example/deployments/contents/"):]
+		switch r.Method {
+		case http.MethodGet:
+			content, ok := f.files[filePath]
+			if !ok {
+				http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"type":     "file",
+				"encoding": "base64",
+				"sha":      "1234",
+				"content":  base64.StdEncoding.EncodeToString([]byte(content)),
+			})
+		case http.MethodPut:
+			var opts struct {
+				Content []byte  `json:"content"`
+				SHA     *string `json:"sha"`
+				Branch  string  `json:"branch"`
+			}
+			body, _ := io.ReadAll(r.Body)
+			require.NoError(t, json.Unmarshal(body, &opts))
+			f.mu.Lock()
+			f.files[filePath] = string(opts.Content)
+			f.mu.Unlock()
+			_, _ = w.Write([]byte(`{}`))
+		}
+	})
+	mux.HandleFunc("/api/v3/repos/example/deployments/git/ref/heads/main", func(w http.ResponseWriter, r *http.Request) {
+		f.record(r)
+		_, _ = w.Write([]byte(`{"ref":"refs/heads/main","object":{"sha":"5678"}}`))
+	})
+	mux.HandleFunc("/api/v3/repos/example/deployments/git/refs", func(w http.ResponseWriter, r *http.Request) {
+		f.record(r)
+		_, _ = w.Write([]byte(`{}`))
+	})
+	mux.HandleFunc("/api/v3/repos/example/deployments/pulls", func(w http.ResponseWriter, r *http.Request) {
+		f.record(r)
+		_, _ = w.Write([]byte(`{}`))
+	})
+	return mux
+}
+
+func (f *fakeGitHub) record(r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+}
+
+func newDecision() Decision {
+	decision, _ := NewDecision(newRollout(), conditions.RolloutCompletedReason, "Rollout completed update to revision 3")
+	return decision
+}
+
+func TestGitHubWriterCommit(t *testing.T) {
+	gh := &fakeGitHub{files: map[string]string{}}
+	server := httptest.NewServer(gh.handler(t))
+	defer server.Close()
+
+	w, err := NewGitHubWriter(Config{RepoURL: server.URL + "/example/deployments", Path: "rollouts", Token: "token"})
+	require.NoError(t, err)
+	decision := newDecision()
+	require.NoError(t, w.Write(context.Background(), decision))
+	assert.Equal(t, []string{
+		"GET /api/v3/repos/example/deployments/contents/rollouts/default/guestbook.yaml",
+		"PUT /api/v3/repos/example/deployments/contents/rollouts/default/guestbook.yaml",
+	}, gh.requests)
+	assert.Contains(t, gh.files["rollouts/default/guestbook.yaml"], "decision: Promoted\n")
+	assert.Contains(t, gh.files["rollouts/default/guestbook.yaml"], "guestbook: argoproj/rollouts-demo:blue\n")
+
+	// the same decision is not committed again
+	gh.requests = nil
+	require.NoError(t, w.Write(context.Background(), decision))
+	assert.Equal(t, []string{"GET /api/v3/repos/example/deployments/contents/rollouts/default/guestbook.yaml"}, gh.requests)
+}
+
+func TestGitHubWriterPullRequest(t *testing.T) {
+	gh := &fakeGitHub{files: map[string]string{}}
+	server := httptest.NewServer(gh.handler(t))
+	defer server.Close()
+
+	w, err := NewGitHubWriter(Config{RepoURL: server.URL + "/example/deployments", PullRequest: true})
+	require.NoError(t, err)
+	require.NoError(t, w.Write(context.Background(), newDecision()))
+	assert.Equal(t, []string{
+		"GET /api/v3/repos/example/deployments/git/ref/heads/main",
+		"POST /api/v3/repos/example/deployments/git/refs",
+		"GET /api/v3/repos/example/deployments/contents/default/guestbook.yaml",
+		"PUT /api/v3/repos/example/deployments/contents/default/guestbook.yaml",
+		"POST /api/v3/repos/example/deployments/pulls",
+	}, gh.requests)
+}
+
+type fakeWriter struct {
+	mu        sync.Mutex
+	decisions []Decision
+}
+
+func (f *fakeWriter) Write(ctx context.Context, decision Decision) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.decisions = append(f.decisions, decision)
+	return nil
+}
+
+func TestRecordRolloutEvent(t *testing.T) {
+	writer := &fakeWriter{}
+	w := NewWriteBack(writer, DefaultQueueSize)
+	go w.Run()
+	writeBack.Store(w)
+	defer writeBack.Store(nil)
+
+	ro := newRollout()
+	RecordRolloutEvent(ro, conditions.RolloutAbortedReason, "Rollout aborted update to revision %d", []any{3})
+	RecordRolloutEvent(ro, conditions.RolloutStepCompletedReason, "Rollout step %d completed", []any{1})
+	w.Close()
+	w.Add(newDecision())
+
+	require.Len(t, writer.decisions, 1)
+	assert.Equal(t, DecisionAborted, writer.decisions[0].Decision)
+	assert.Equal(t, "Rollout aborted update to revision 3", writer.decisions[0].Message)
+	assert.WithinDuration(t, time.Now(), writer.decisions[0].Time, time.Minute)
+}